@@ -6,6 +6,7 @@ package agent
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -16,8 +17,10 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 
 	time "github.com/echlebek/timeproxy"
@@ -140,8 +143,11 @@ type Agent struct {
 	api                *http.Server
 	assetGetter        asset.Getter
 	backendSelector    BackendSelector
+	cloudMetadata      *system.CloudMetadata
+	cloudMetadataMu    sync.RWMutex
 	config             *Config
 	connected          bool
+	connectedURL       string
 	connectedMu        sync.RWMutex
 	contentType        string
 	entityConfig       *corev3.EntityConfig
@@ -152,6 +158,9 @@ type Agent struct {
 	header             http.Header
 	inProgress         map[string]*corev2.CheckConfig
 	inProgressMu       *sync.Mutex
+	inspectMu          sync.Mutex
+	loadedAssets       map[string]loadedAsset
+	checkResults       map[string]checkResult
 	localEntityConfig  *corev3.EntityConfig
 	statsdServer       StatsdServer
 	sendq              chan *transport.Message
@@ -163,8 +172,18 @@ type Agent struct {
 	unmarshal          UnmarshalFunc
 	sequencesMu        sync.Mutex
 	sequences          map[string]int64
+	signatureKeys      []ed25519.PublicKey
+	assetSignatures    *asset.SignatureVerifier
 	maxSessionLength   time.Duration
 	keepalivePipelines []*corev2.ResourceReference
+	spool              *eventSpool
+	// retryAfter is the delay, in nanoseconds, the backend asked to wait before
+	// reconnecting, when it closed the connection to shed its load.
+	retryAfter int64
+	// nextBackendURL is the URL of the backend to reconnect to first, once a
+	// preferred backend is healthy again.
+	nextBackendURL   string
+	nextBackendURLMu sync.Mutex
 
 	// ProcessGetter gets information about local agent processes.
 	ProcessGetter process.Getter
@@ -186,7 +205,7 @@ func NewAgentContext(ctx context.Context, config *Config) (*Agent, error) {
 		return nil, errors.New("keepalive warning timeout must be greater than keepalive interval")
 	}
 	agent := &Agent{
-		backendSelector:  &RandomBackendSelector{Backends: config.BackendURLs},
+		backendSelector:  newBackendSelector(config),
 		connected:        false,
 		config:           config,
 		executor:         command.NewExecutor(),
@@ -220,12 +239,35 @@ func NewAgentContext(ctx context.Context, config *Config) (*Agent, error) {
 		return nil, fmt.Errorf("error creating agent: %s", err)
 	}
 
+	if config.EventSpoolMaxSize > 0 && config.CacheDir != os.DevNull {
+		agent.spool, err = openEventSpool(filepath.Join(config.CacheDir, "event-spool"), config.EventSpoolMaxSize, config.EventSpoolMaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("error creating agent: %s", err)
+		}
+	}
+
 	allowList, err := readAllowList(config.AllowList, ioutil.ReadFile)
 	if err != nil {
 		return nil, err
 	}
 	agent.allowList = allowList
 
+	agent.signatureKeys, err = readSignaturePublicKeys(config.SignaturePublicKeys, ioutil.ReadFile)
+	if err != nil {
+		return nil, err
+	}
+
+	agent.assetSignatures, err = asset.NewSignatureVerifier(asset.SignatureConfig{
+		PublicKeysFile: config.AssetSignaturePublicKeys,
+		TrustRootsFile: config.AssetSignatureTrustRoots,
+		Subject:        config.AssetSignatureSubject,
+		Issuer:         config.AssetSignatureIssuer,
+		Strict:         config.AssetSignatureStrict,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	if config.PrometheusBinding != "" {
 		go func() {
 			logger.WithError(http.ListenAndServe(config.PrometheusBinding, promhttp.Handler())).Error("couldn't serve prometheus metrics")
@@ -241,9 +283,65 @@ func (a *Agent) sendMessage(msg *transport.Message) {
 		"content_type": a.contentType,
 		"payload_size": len(msg.Payload),
 	}).Info("sending message")
+	if !a.Connected() && a.spoolMessage(msg) {
+		return
+	}
 	a.sendq <- msg
 }
 
+// spoolMessage writes an event to the spool, and returns false if the message
+// cannot be spooled. The messages of the API queue are already persisted.
+func (a *Agent) spoolMessage(msg *transport.Message) bool {
+	if a.spool == nil || msg.Type != transport.MessageTypeEvent || msg.SendCallback != nil {
+		return false
+	}
+	err := a.spool.Write(spoolRecord{
+		ContentType: a.contentType,
+		Timestamp:   time.Now(),
+		Payload:     msg.Payload,
+	})
+	if err != nil {
+		logger.WithError(err).Error("error spooling event")
+		return false
+	}
+	logger.Debug("backend unavailable, event spooled")
+	return true
+}
+
+// replaySpool sends the spooled events to the backend, in order. The events are
+// converted to the content type of the connection if needed.
+func (a *Agent) replaySpool(conn transport.Transport) error {
+	if a.spool == nil || a.spool.Size() == 0 {
+		return nil
+	}
+	logger.WithField("size", a.spool.Size()).Info("replaying spooled events")
+	return a.spool.Replay(func(record spoolRecord) error {
+		payload := record.Payload
+		if record.ContentType != a.contentType {
+			unmarshal := UnmarshalJSON
+			if record.ContentType == ProtobufSerializationHeader {
+				unmarshal = proto.Unmarshal
+			}
+			var event corev2.Event
+			if err := unmarshal(payload, &event); err != nil {
+				logger.WithError(err).Error("discarding invalid spooled event")
+				return nil
+			}
+			var err error
+			if payload, err = a.marshal(&event); err != nil {
+				logger.WithError(err).Error("discarding invalid spooled event")
+				return nil
+			}
+		}
+		if err := conn.Send(transport.NewMessage(transport.MessageTypeEvent, payload)); err != nil {
+			logger.WithError(err).Error("error sending spooled event over websocket")
+			return err
+		}
+		messagesSent.WithLabelValues().Inc()
+		return nil
+	})
+}
+
 // RefreshSystemInfo refreshes system, platform, and process information.
 func (a *Agent) RefreshSystemInfo(ctx context.Context) error {
 	var info corev2.System
@@ -326,6 +424,11 @@ func (a *Agent) Run(ctx context.Context) error {
 		if err := a.apiQueue.Close(); err != nil {
 			logger.WithError(err).Error("error closing API queue")
 		}
+		if a.spool != nil {
+			if err := a.spool.Close(); err != nil {
+				logger.WithError(err).Error("error closing event spool")
+			}
+		}
 	}()
 	defer cancel()
 	a.header = a.buildTransportHeaderMap()
@@ -361,6 +464,13 @@ func (a *Agent) Run(ctx context.Context) error {
 		}
 	}
 
+	logger.Debug("validating prometheus scrape targets: ", a.config.PrometheusScrape.Targets)
+	for _, target := range a.config.PrometheusScrape.Targets {
+		if err := validatePrometheusScrapeTarget(target); err != nil {
+			return err
+		}
+	}
+
 	logger.Debug("validating keepalive pipelines: ", a.config.KeepalivePipelines)
 	for _, p := range a.config.KeepalivePipelines {
 		if ref, err := corev2.FromStringRef(p); err != nil {
@@ -378,6 +488,11 @@ func (a *Agent) Run(ctx context.Context) error {
 			trustedCAFile = a.config.TLS.TrustedCAFile
 		}
 		assetManager := asset.NewManager(a.config.CacheDir, trustedCAFile, a.getAgentEntity(), &a.wg)
+		assetManager.SetSignatureVerifier(a.assetSignatures)
+		assetManager.SetCacheLimits(a.config.AssetsCacheMaxSize, a.config.AssetsCacheMaxAge)
+		if a.config.AssetsProxy {
+			assetManager.SetProxy(a.assetProxy())
+		}
 		limit := a.config.AssetsRateLimit
 		if limit == 0 {
 			limit = rate.Limit(asset.DefaultAssetsRateLimit)
@@ -394,10 +509,27 @@ func (a *Agent) Run(ctx context.Context) error {
 		a.StartStatsd(ctx)
 	}
 
+	if len(a.config.PrometheusScrape.Targets) > 0 {
+		a.StartPrometheusScraper(ctx)
+	}
+
+	if len(a.config.EventLog.Channels) > 0 {
+		a.StartEventLog(ctx)
+	}
+
 	if !a.config.DisableAPI {
 		a.StartAPI(ctx)
 	}
 
+	if a.config.CloudMetadata {
+		// Query the cloud instance metadata before connecting, so that the
+		// entity is registered with it
+		if err := a.RefreshCloudMetadata(ctx); err != nil {
+			logger.WithError(err).Error("failed to get cloud instance metadata")
+		}
+		go a.refreshCloudMetadataPeriodically(ctx)
+	}
+
 	// Increment the waitgroup counter here too in case none of the components
 	// above were started, and rely on the system info collector to decrement it
 	// once it exits
@@ -454,7 +586,19 @@ func (a *Agent) connectionManager(ctx context.Context, cancel context.CancelFunc
 
 		a.clearAgentEntity()
 
-		conn, err := a.connectWithBackoff(ctx)
+		// Honor the delay requested by the backend when it drained this agent,
+		// so that the drained agents do not all reconnect at once
+		if retryAfter := time.Duration(atomic.SwapInt64(&a.retryAfter, 0)); retryAfter > 0 {
+			logger.Infof("backend requested to reconnect in %v", retryAfter)
+			select {
+			case <-time.After(retryAfter):
+			case <-ctx.Done():
+				logger.Warning("not retrying to connect")
+				return
+			}
+		}
+
+		conn, backendURL, err := a.connectWithBackoff(ctx)
 		if err != nil {
 			if err == ctx.Err() {
 				return
@@ -471,11 +615,13 @@ func (a *Agent) connectionManager(ctx context.Context, cancel context.CancelFunc
 
 		a.connectedMu.Lock()
 		a.connected = true
+		a.connectedURL = backendURL
 		a.connectedMu.Unlock()
 
 		newConnections.WithLabelValues().Inc()
 
 		go a.enforceMaxSessionLength(connCancel)
+		go a.probePreferredBackends(connCtx, connCancel, backendURL)
 		go a.receiveLoop(connCtx, connCancel, conn)
 
 		// Block until we receive an entity config, or the grace period expires,
@@ -541,6 +687,9 @@ func (a *Agent) receiveLoop(ctx context.Context, cancel context.CancelFunc, conn
 		}
 		m, err := conn.Receive()
 		if err != nil {
+			if closed, ok := err.(transport.ClosedError); ok && closed.RetryAfter > 0 {
+				atomic.StoreInt64(&a.retryAfter, int64(closed.RetryAfter))
+			}
 			logger.WithError(err).Error("transport receive error")
 			return
 		}
@@ -582,6 +731,9 @@ func (a *Agent) sendLoop(ctx context.Context, cancel context.CancelFunc, conn tr
 		logger.WithError(err).Error("error sending message over websocket")
 		return err
 	}
+	if err := a.replaySpool(conn); err != nil {
+		return err
+	}
 	for {
 		select {
 		case <-ctx.Done():
@@ -592,7 +744,9 @@ func (a *Agent) sendLoop(ctx context.Context, cancel context.CancelFunc, conn tr
 			return nil
 		case msg := <-a.sendq:
 			if err := conn.Send(msg); err != nil {
-				messagesDropped.WithLabelValues().Inc()
+				if !a.spoolMessage(msg) {
+					messagesDropped.WithLabelValues().Inc()
+				}
 				logger.WithError(err).Error("error sending message over websocket")
 				return err
 			}
@@ -651,6 +805,10 @@ func (a *Agent) newKeepalive() *transport.Message {
 	keepalive.Entity = entity
 	keepalive.Timestamp = time.Now().Unix()
 
+	if a.config.KeepaliveSystemMetrics {
+		keepalive.Metrics = a.systemMetrics(keepalive.Timestamp)
+	}
+
 	logEvent(keepalive)
 
 	msgBytes, err := a.marshal(keepalive)
@@ -670,6 +828,34 @@ func (a *Agent) Connected() bool {
 	return a.connected
 }
 
+// connectedBackendURL returns the URL of the backend the agent is connected
+// to, or an empty string if it is not connected.
+func (a *Agent) connectedBackendURL() string {
+	a.connectedMu.RLock()
+	defer a.connectedMu.RUnlock()
+	if !a.connected {
+		return ""
+	}
+	return a.connectedURL
+}
+
+// assetProxy returns the asset proxy of the backend the agent is connected
+// to, whose requests are authenticated like the connection of the agent, by
+// its headers or its TLS certificate.
+func (a *Agent) assetProxy() *asset.Proxy {
+	headers := map[string]string{}
+	for _, key := range []string{transport.HeaderKeyNamespace, transport.HeaderKeyAgentName, transport.HeaderKeyUser, "Authorization"} {
+		if value := a.header.Get(key); value != "" {
+			headers[key] = value
+		}
+	}
+	return &asset.Proxy{
+		BackendURL: a.connectedBackendURL,
+		Headers:    headers,
+		TLS:        a.config.TLS,
+	}
+}
+
 // StartAPI starts the Agent HTTP API. After attempting to start the API, if the
 // HTTP server encounters a fatal error, it will shutdown the rest of the agent.
 func (a *Agent) StartAPI(ctx context.Context) {
@@ -722,23 +908,29 @@ func (a *Agent) StartStatsd(ctx context.Context) {
 	}()
 }
 
-func (a *Agent) connectWithBackoff(ctx context.Context) (transport.Transport, error) {
+func (a *Agent) connectWithBackoff(ctx context.Context) (transport.Transport, string, error) {
 	var conn transport.Transport
+	var connectedURL string
+	selector, prioritized := a.backendSelector.(PrioritizedBackendSelector)
 
 	backoff := retry.ExponentialBackoff{
 		InitialDelayInterval: a.config.RetryMin,
 		MaxDelayInterval:     a.config.RetryMax,
 		Multiplier:           a.config.RetryMultiplier,
+		Jitter:               a.config.RetryJitter,
 		Ctx:                  ctx,
 	}
 
 	err := backoff.Retry(func(retry int) (bool, error) {
 		backendURL := a.backendSelector.Select()
+		if next := a.takeNextBackendURL(); next != "" {
+			backendURL = next
+		}
 
 		logger.Infof("connecting to backend URL %q", backendURL)
 		a.header.Set("Accept", ProtobufSerializationHeader)
 		logger.WithField("header", fmt.Sprintf("Accept: %s", ProtobufSerializationHeader)).Debug("setting header")
-		c, respHeader, err := transport.Connect(backendURL, a.config.TLS, a.header, a.config.BackendHandshakeTimeout)
+		c, respHeader, err := transport.Connect(backendURL, a.config.TLS, a.header, a.config.BackendHandshakeTimeout, a.config.BackendCompressionLevel)
 		if err != nil {
 			if err == transport.ErrTooManyRequests {
 				// Give the backend extra breathing room
@@ -751,6 +943,9 @@ func (a *Agent) connectWithBackoff(ctx context.Context) (transport.Transport, er
 				backoff.MaxDelayInterval = a.config.RetryMax
 				backoff.Multiplier = a.config.RetryMultiplier
 			}
+			if prioritized {
+				selector.Failed(backendURL)
+			}
 			websocketErrors.WithLabelValues().Inc()
 			logger.WithError(err).Error("reconnection attempt failed")
 			return false, nil
@@ -758,7 +953,11 @@ func (a *Agent) connectWithBackoff(ctx context.Context) (transport.Transport, er
 
 		logger.Info("successfully connected")
 
+		if prioritized {
+			selector.Healthy(backendURL)
+		}
 		conn = c
+		connectedURL = backendURL
 
 		logger.WithField("header", fmt.Sprintf("Accept: %s", respHeader["Accept"])).Debug("received header")
 		if utilstrings.InArray(ProtobufSerializationHeader, respHeader["Accept"]) {
@@ -778,7 +977,7 @@ func (a *Agent) connectWithBackoff(ctx context.Context) (transport.Transport, er
 		return true, nil
 	})
 
-	return conn, err
+	return conn, connectedURL, err
 }
 
 // GracefulShutdown listens for the SIGINT & SIGTERM signals and cancel the
@@ -4,16 +4,26 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 
+	corev2 "github.com/sensu/core/v2"
+	utilstrings "github.com/sensu/sensu-go/util/strings"
 	"gopkg.in/yaml.v2"
 )
 
+// allowListPatternChars are the characters which make the exec of an allow
+// list entry a glob pattern, rather than a literal executable.
+const allowListPatternChars = "*?["
+
 type allowList struct {
 	Exec      string   `yaml:"exec" json:"exec"`
 	Args      []string `yaml:"args" json:"args"`
 	Sha512    string   `yaml:"sha512" json:"sha512"`
 	EnableEnv bool     `yaml:"enable_env" json:"enable_env"`
+	// Assets pins the sha512 checksums of the assets the command may use, by
+	// asset name. Any asset is allowed when empty.
+	Assets map[string][]string `yaml:"assets" json:"assets"`
 }
 
 func readAllowList(path string, readBytes func(string) ([]byte, error)) ([]allowList, error) {
@@ -59,14 +69,59 @@ func (al *allowList) validate() error {
 		return errors.New("args cannot be empty")
 	}
 
+	if _, err := filepath.Match(al.Exec, ""); err != nil {
+		return fmt.Errorf("exec is not a valid pattern: %s", err)
+	}
+
+	return nil
+}
+
+// matchExec returns the rest of the command if it runs the executable of the
+// entry. The exec of the entry is matched against the executable of the
+// command if it is a glob pattern, like /opt/sensu/checks/*.
+func (al *allowList) matchExec(command string) (string, bool) {
+	if !strings.ContainsAny(al.Exec, allowListPatternChars) {
+		if !strings.Contains(command, al.Exec) {
+			return "", false
+		}
+		return strings.Replace(command, al.Exec, "", -1), true
+	}
+	command = strings.TrimSpace(command)
+	executable := strings.SplitN(command, " ", 2)[0]
+	if match, _ := filepath.Match(al.Exec, executable); !match {
+		return "", false
+	}
+	return strings.TrimPrefix(command, executable), true
+}
+
+// verifyAssets returns an error if the entry pins the checksums of the assets
+// and any of the assets, or any of their builds, has a checksum that is not
+// pinned.
+func (al *allowList) verifyAssets(assets []corev2.Asset) error {
+	if len(al.Assets) == 0 {
+		return nil
+	}
+	for _, asset := range assets {
+		pinned, ok := al.Assets[asset.Name]
+		if !ok {
+			return fmt.Errorf("asset %s is not pinned by the agent allow list", asset.Name)
+		}
+		checksums := []string{asset.Sha512}
+		for _, build := range asset.Builds {
+			checksums = append(checksums, build.Sha512)
+		}
+		for _, checksum := range checksums {
+			if checksum != "" && !utilstrings.InArray(checksum, pinned) {
+				return fmt.Errorf("asset %s has a sha512 not pinned by the agent allow list", asset.Name)
+			}
+		}
+	}
 	return nil
 }
 
 func (a *Agent) matchAllowList(command string) (allowList, bool) {
 	for _, al := range a.allowList {
-		remaining := command
-		if strings.Contains(command, al.Exec) {
-			remaining = strings.Replace(remaining, al.Exec, "", -1)
+		if remaining, ok := al.matchExec(command); ok {
 			for _, a := range al.Args {
 				if strings.Contains(command, a) {
 					remaining = strings.Replace(remaining, a, "", -1)
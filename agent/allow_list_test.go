@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	corev2 "github.com/sensu/core/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -251,6 +252,33 @@ func TestMatchAllowList(t *testing.T) {
 			match:   false,
 			matched: allowList{},
 		},
+		{
+			description: "Match: exec pattern",
+			command:     "/opt/sensu/checks/check-disk -w 80",
+			allowList: []allowList{
+				allowList{
+					Exec: "/opt/sensu/checks/*",
+					Args: []string{"-w 80"},
+				},
+			},
+			match: true,
+			matched: allowList{
+				Exec: "/opt/sensu/checks/*",
+				Args: []string{"-w 80"},
+			},
+		},
+		{
+			description: "No match: executable outside of exec pattern",
+			command:     "/tmp/check-disk /opt/sensu/checks/check-disk",
+			allowList: []allowList{
+				allowList{
+					Exec: "/opt/sensu/checks/*",
+					Args: []string{""},
+				},
+			},
+			match:   false,
+			matched: allowList{},
+		},
 		{
 			description: "No match: No allow list",
 			command:     "foo",
@@ -283,3 +311,36 @@ func TestMatchAllowList(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAllowListPattern(t *testing.T) {
+	al := allowList{
+		Exec: "/opt/sensu/checks/[",
+		Args: []string{""},
+	}
+	assert.Error(t, al.validate())
+}
+
+func TestAllowListVerifyAssets(t *testing.T) {
+	assets := []corev2.Asset{
+		{
+			ObjectMeta: corev2.ObjectMeta{Name: "check-disk"},
+			Builds: []*corev2.AssetBuild{
+				{Sha512: "linux"},
+				{Sha512: "windows"},
+			},
+		},
+	}
+
+	// Any asset is allowed when no asset is pinned
+	al := allowList{}
+	assert.NoError(t, al.verifyAssets(assets))
+
+	al.Assets = map[string][]string{"check-disk": {"linux", "windows"}}
+	assert.NoError(t, al.verifyAssets(assets))
+
+	al.Assets = map[string][]string{"check-disk": {"linux"}}
+	assert.Error(t, al.verifyAssets(assets))
+
+	al.Assets = map[string][]string{"check-cpu": {"linux", "windows"}}
+	assert.Error(t, al.verifyAssets(assets))
+}
@@ -48,6 +48,9 @@ func registerRoutes(a *Agent, r *mux.Router) {
 	r.HandleFunc("/events", addEvent(a)).Methods(http.MethodPost)
 	r.HandleFunc("/healthz", healthz(a.Connected)).Methods(http.MethodGet)
 	r.HandleFunc("/version", versionShow()).Methods(http.MethodGet)
+	r.HandleFunc("/assets", listAssets(a)).Methods(http.MethodGet)
+	r.HandleFunc("/checks", listChecks(a)).Methods(http.MethodGet)
+	r.HandleFunc("/subscriptions", listSubscriptions(a)).Methods(http.MethodGet)
 	r.Handle("/metrics", promhttp.Handler())
 }
 
@@ -0,0 +1,118 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	time "github.com/echlebek/timeproxy"
+)
+
+// defaultBackendProbeTimeout is the timeout of the backend health probes when
+// the agent has no backend handshake timeout.
+const defaultBackendProbeTimeout = 15 * time.Second
+
+// probePreferredBackends periodically probes the health of the backends
+// preferred over the one the agent is connected to, until one of them is
+// healthy, in which case the connection is closed so that the agent reconnects
+// to it.
+func (a *Agent) probePreferredBackends(ctx context.Context, connCancel context.CancelFunc, backendURL string) {
+	selector, ok := a.backendSelector.(PrioritizedBackendSelector)
+	if !ok || a.config.BackendProbeInterval <= 0 {
+		return
+	}
+	if len(selector.Preferred(backendURL)) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(a.config.BackendProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, preferred := range selector.Preferred(backendURL) {
+			if err := a.probeBackend(ctx, preferred); err != nil {
+				logger.WithError(err).Debugf("preferred backend %q is unhealthy", preferred)
+				continue
+			}
+			logger.Infof("preferred backend %q is healthy, reconnecting to it", preferred)
+			selector.Healthy(preferred)
+			a.nextBackendURLMu.Lock()
+			a.nextBackendURL = preferred
+			a.nextBackendURLMu.Unlock()
+			connCancel()
+			return
+		}
+	}
+}
+
+// takeNextBackendURL returns the URL of the backend to reconnect to first, if
+// any, and forgets it.
+func (a *Agent) takeNextBackendURL() string {
+	a.nextBackendURLMu.Lock()
+	defer a.nextBackendURLMu.Unlock()
+	next := a.nextBackendURL
+	a.nextBackendURL = ""
+	return next
+}
+
+// probeBackend returns an error unless the health endpoint of a backend
+// reports that it is healthy.
+func (a *Agent) probeBackend(ctx context.Context, backendURL string) error {
+	u, err := backendHealthURL(backendURL)
+	if err != nil {
+		return err
+	}
+
+	timeout := defaultBackendProbeTimeout
+	if a.config.BackendHandshakeTimeout > 0 {
+		timeout = time.Duration(a.config.BackendHandshakeTimeout) * time.Second
+	}
+	client := &http.Client{Timeout: timeout}
+	if a.config.TLS != nil {
+		tlsConfig, err := a.config.TLS.ToClientTLSConfig()
+		if err != nil {
+			return err
+		}
+		client.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// backendHealthURL returns the URL of the health endpoint of a backend, served
+// on the same port as its websocket API.
+func backendHealthURL(backendURL string) (string, error) {
+	u, err := url.Parse(backendURL)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	}
+	u.Path = "/health"
+	u.RawQuery = ""
+	return u.String(), nil
+}
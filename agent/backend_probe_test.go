@@ -0,0 +1,71 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	time "github.com/echlebek/timeproxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackendHealthURL(t *testing.T) {
+	u, err := backendHealthURL("ws://127.0.0.1:8081")
+	require.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:8081/health", u)
+
+	u, err = backendHealthURL("wss://sensu.example.com:8081/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://sensu.example.com:8081/health", u)
+}
+
+func TestProbePreferredBackends(t *testing.T) {
+	healthy := make(chan bool, 1)
+	healthy <- false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health", r.URL.Path)
+		select {
+		case ok := <-healthy:
+			if !ok {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		default:
+		}
+	}))
+	defer server.Close()
+	preferred := "ws://" + strings.TrimPrefix(server.URL, "http://")
+
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	config.BackendURLs = []string{preferred, "ws://remote:8081"}
+	config.BackendSelection = BackendSelectionOrdered
+	config.BackendProbeInterval = time.Second
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+	selector := agent.backendSelector.(*OrderedBackendSelector)
+	selector.Failed(preferred)
+
+	mockTime.Start()
+	defer mockTime.Stop()
+
+	// The connection is closed once the preferred backend is healthy
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		agent.probePreferredBackends(ctx, cancel, "ws://remote:8081")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Minute):
+		t.Fatal("the preferred backend was never probed healthy")
+	}
+	assert.Error(t, ctx.Err())
+	assert.Equal(t, preferred, agent.takeNextBackendURL())
+	assert.Equal(t, "", agent.takeNextBackendURL())
+	assert.Equal(t, preferred, selector.Select())
+}
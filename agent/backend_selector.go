@@ -2,6 +2,8 @@ package agent
 
 import (
 	"math/rand"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -44,3 +46,189 @@ func (b *RandomBackendSelector) Select() string {
 
 	return b.Backends[next]
 }
+
+const (
+	// BackendSelectionRandom selects the backends in a random order.
+	BackendSelectionRandom = "random"
+
+	// BackendSelectionOrdered selects the backends in the order they are
+	// configured.
+	BackendSelectionOrdered = "ordered"
+
+	// BackendSelectionWeighted randomly selects the backends in proportion to
+	// their weight.
+	BackendSelectionWeighted = "weighted"
+)
+
+// newBackendSelector returns the backend selector of the backend selection
+// strategy of the agent config.
+func newBackendSelector(config *Config) BackendSelector {
+	switch config.BackendSelection {
+	case BackendSelectionOrdered:
+		return &OrderedBackendSelector{Backends: config.BackendURLs}
+	case BackendSelectionWeighted:
+		return &WeightedBackendSelector{Backends: config.BackendURLs, Weights: config.BackendWeights}
+	default:
+		return &RandomBackendSelector{Backends: config.BackendURLs}
+	}
+}
+
+// A PrioritizedBackendSelector is a BackendSelector which prefers some backends
+// over others, and avoids the backends it could not connect to.
+type PrioritizedBackendSelector interface {
+	BackendSelector
+
+	// Failed marks a backend as failed, so that it is only selected again once
+	// all the other backends failed as well.
+	Failed(backend string)
+
+	// Healthy marks a backend as healthy, so that it can be selected again.
+	Healthy(backend string)
+
+	// Preferred returns the backends preferred over the given backend, by
+	// order of preference.
+	Preferred(backend string) []string
+}
+
+// backendFailures keeps track of the backends which could not be connected to.
+type backendFailures struct {
+	mu     sync.Mutex
+	failed map[string]bool
+}
+
+// Failed marks a backend as failed.
+func (b *backendFailures) Failed(backend string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failed == nil {
+		b.failed = make(map[string]bool)
+	}
+	b.failed[backend] = true
+}
+
+// Healthy marks a backend as healthy.
+func (b *backendFailures) Healthy(backend string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.failed, backend)
+}
+
+// available returns the backends which did not fail, or all of them once they
+// all failed, in which case they are all marked as healthy again. It must be
+// called with the lock held.
+func (b *backendFailures) available(backends []string) []int {
+	indexes := make([]int, 0, len(backends))
+	for i, backend := range backends {
+		if !b.failed[backend] {
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) == 0 {
+		b.failed = nil
+		for i := range backends {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// An OrderedBackendSelector returns the first of its backends which did not
+// fail, and starts over from the first one once they all failed, so that the
+// agents prefer the first backends and fail over predictably.
+type OrderedBackendSelector struct {
+	// Backends is the list of backend URLs, by order of preference.
+	Backends []string
+
+	backendFailures
+}
+
+// Select returns the first backend which did not fail.
+func (b *OrderedBackendSelector) Select() string {
+	if len(b.Backends) == 0 {
+		return ""
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.Backends[b.available(b.Backends)[0]]
+}
+
+// Preferred returns the backends which come before the given backend.
+func (b *OrderedBackendSelector) Preferred(backend string) []string {
+	for i, v := range b.Backends {
+		if v == backend {
+			return append([]string(nil), b.Backends[:i]...)
+		}
+	}
+	return nil
+}
+
+// A WeightedBackendSelector randomly returns one of its backends which did not
+// fail, in proportion to their weight, and starts over once they all failed.
+type WeightedBackendSelector struct {
+	// Backends is the list of backend URLs to select from.
+	Backends []string
+
+	// Weights are the weights of the backends, in the same order. The backends
+	// without a weight have a weight of 1.
+	Weights []int
+
+	backendFailures
+}
+
+func (b *WeightedBackendSelector) weight(i int) int {
+	if i < len(b.Weights) && b.Weights[i] > 0 {
+		return b.Weights[i]
+	}
+	return 1
+}
+
+// Select returns a random backend which did not fail.
+func (b *WeightedBackendSelector) Select() string {
+	if len(b.Backends) == 0 {
+		return ""
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	indexes := b.available(b.Backends)
+	total := 0
+	for _, i := range indexes {
+		total += b.weight(i)
+	}
+	n := rand.Intn(total)
+	for _, i := range indexes {
+		n -= b.weight(i)
+		if n < 0 {
+			return b.Backends[i]
+		}
+	}
+	return b.Backends[indexes[len(indexes)-1]]
+}
+
+// Preferred returns the backends with a greater weight than the given backend,
+// by decreasing weight.
+func (b *WeightedBackendSelector) Preferred(backend string) []string {
+	current := -1
+	for i, v := range b.Backends {
+		if v == backend {
+			current = b.weight(i)
+			break
+		}
+	}
+	if current < 0 {
+		return nil
+	}
+	var indexes []int
+	for i := range b.Backends {
+		if b.weight(i) > current {
+			indexes = append(indexes, i)
+		}
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return b.weight(indexes[i]) > b.weight(indexes[j])
+	})
+	preferred := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		preferred = append(preferred, b.Backends[i])
+	}
+	return preferred
+}
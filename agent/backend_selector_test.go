@@ -27,3 +27,72 @@ func TestEmptyBackendSelector(t *testing.T) {
 	assert.Equal(t, "", selector.Select())
 	assert.Equal(t, "", selector.Select())
 }
+
+func TestOrderedBackendSelector(t *testing.T) {
+	selector := &OrderedBackendSelector{
+		Backends: []string{"a", "b", "c"},
+	}
+	assert.Equal(t, "a", selector.Select())
+	assert.Equal(t, "a", selector.Select())
+
+	// The failed backends are skipped until they all failed
+	selector.Failed("a")
+	assert.Equal(t, "b", selector.Select())
+	selector.Failed("b")
+	assert.Equal(t, "c", selector.Select())
+	selector.Failed("c")
+	assert.Equal(t, "a", selector.Select())
+	assert.Equal(t, "a", selector.Select())
+
+	selector.Failed("a")
+	selector.Healthy("a")
+	assert.Equal(t, "a", selector.Select())
+
+	assert.Equal(t, []string{"a", "b"}, selector.Preferred("c"))
+	assert.Empty(t, selector.Preferred("a"))
+	assert.Empty(t, selector.Preferred("d"))
+
+	assert.Equal(t, "", (&OrderedBackendSelector{}).Select())
+}
+
+func TestWeightedBackendSelector(t *testing.T) {
+	selector := &WeightedBackendSelector{
+		Backends: []string{"a", "b", "c"},
+		Weights:  []int{1, 3, 3},
+	}
+	received := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		received[selector.Select()]++
+	}
+	assert.Len(t, received, 3)
+	assert.Greater(t, received["b"], received["a"])
+	assert.Greater(t, received["c"], received["a"])
+
+	// The failed backends are skipped until they all failed
+	selector.Failed("b")
+	selector.Failed("c")
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "a", selector.Select())
+	}
+	selector.Failed("a")
+	received = make(map[string]int)
+	for i := 0; i < 100; i++ {
+		received[selector.Select()]++
+	}
+	assert.Len(t, received, 3)
+
+	assert.Equal(t, []string{"b", "c"}, selector.Preferred("a"))
+	assert.Empty(t, selector.Preferred("b"))
+	assert.Empty(t, selector.Preferred("d"))
+
+	assert.Equal(t, "", (&WeightedBackendSelector{}).Select())
+}
+
+func TestNewBackendSelector(t *testing.T) {
+	config := &Config{BackendURLs: []string{"a"}}
+	assert.IsType(t, &RandomBackendSelector{}, newBackendSelector(config))
+	config.BackendSelection = BackendSelectionOrdered
+	assert.IsType(t, &OrderedBackendSelector{}, newBackendSelector(config))
+	config.BackendSelection = BackendSelectionWeighted
+	assert.IsType(t, &WeightedBackendSelector{}, newBackendSelector(config))
+}
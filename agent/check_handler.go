@@ -15,6 +15,7 @@ import (
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/asset"
 	"github.com/sensu/sensu-go/command"
+	"github.com/sensu/sensu-go/thresholds"
 	"github.com/sensu/sensu-go/token"
 	"github.com/sensu/sensu-go/transport"
 	"github.com/sensu/sensu-go/util/environment"
@@ -135,6 +136,13 @@ func (a *Agent) executeCheck(ctx context.Context, request *corev2.CheckRequest,
 		return event
 	}
 
+	// Verify the signature of the check before its tokens are substituted
+	if err := a.verifySignature(checkConfig.ObjectMeta, checkConfig.Command, checkConfig.RuntimeAssets); err != nil {
+		logger.WithField("check", checkConfig.Name).WithError(err).Error("check signature verification failed")
+		a.sendFailure(createEvent(), errors.New(signatureOnDenyOutput))
+		return
+	}
+
 	if origCommand != undocumentedTestCheckCommand {
 		// Perform token substitution on the check configuration, but only if
 		// we aren't doing load testing with the undocumented test check
@@ -169,6 +177,11 @@ func (a *Agent) executeCheck(ctx context.Context, request *corev2.CheckRequest,
 			return
 		}
 		logger.WithFields(fields).Debug("check matches agent allow list")
+		if err := matchedEntry.verifyAssets(checkAssets); err != nil {
+			logger.WithFields(fields).WithError(err).Error("check assets do not match agent allow list")
+			a.sendFailure(event, fmt.Errorf(allowListOnDenyOutput))
+			return
+		}
 	}
 
 	// Fetch and install all assets required for check execution.
@@ -183,6 +196,7 @@ func (a *Agent) executeCheck(ctx context.Context, request *corev2.CheckRequest,
 			a.sendFailure(event, fmt.Errorf("error getting assets for check: %s", err))
 			return
 		}
+		a.recordAssets(assets)
 	}
 
 	// Prepare environment variables
@@ -265,7 +279,7 @@ func (a *Agent) executeCheck(ctx context.Context, request *corev2.CheckRequest,
 		event.Metrics.Points = extractMetrics(event)
 
 		if event.Check.Status == 0 && len(event.Metrics.Points) > 0 && len(check.OutputMetricThresholds) > 0 {
-			event.Check.Status = evaluateOutputMetricThresholds(event)
+			event.Check.Status = thresholds.Evaluate(event)
 		}
 	}
 
@@ -296,6 +310,7 @@ func (a *Agent) executeCheck(ctx context.Context, request *corev2.CheckRequest,
 	}
 
 	logEvent(event)
+	a.recordCheckResult(event.Check)
 
 	a.sendMessage(tm)
 }
@@ -318,6 +333,7 @@ func (a *Agent) sendFailure(event *corev2.Event, err error) {
 			event.Check.Status = uint32(allowListValue)
 		}
 	}
+	a.recordCheckResult(event.Check)
 
 	if msg, err := a.marshal(event); err != nil {
 		logger.WithError(err).Error("error marshaling check failure")
@@ -348,6 +364,10 @@ func extractMetrics(event *corev2.Event) []*corev2.MetricPoint {
 		transformer = transformers.ParseOpenTSDB(event)
 	case corev2.PrometheusOutputMetricFormat:
 		transformer = transformers.ParseProm(event)
+	case transformers.OpenMetricsOutputMetricFormat:
+		transformer = transformers.ParseOpenMetrics(event)
+	case transformers.OTLPOutputMetricFormat:
+		transformer = transformers.ParseOTLP(event)
 	}
 
 	if transformer == nil {
@@ -357,203 +377,3 @@ func extractMetrics(event *corev2.Event) []*corev2.MetricPoint {
 
 	return transformer.Transform()
 }
-
-func evaluateOutputMetricThresholds(event *corev2.Event) uint32 {
-	if event.Check.Status > 0 {
-		return event.Check.Status
-	}
-
-	points := event.Metrics.Points
-	thresholds := event.Check.OutputMetricThresholds
-
-	var overallStatus uint32 = 0
-	annotationValue := ""
-	for _, thresholdRule := range thresholds {
-		ruleMatched := false
-		for _, metricPoint := range points {
-			if thresholdRule.MatchesMetricPoint(metricPoint) {
-				ruleMatched = true
-				var status uint32 = 0
-				isExceeded := false
-				for _, rule := range thresholdRule.Thresholds {
-					if rule.Min != "" {
-						min, err := strconv.ParseFloat(rule.Min, 64)
-						if err != nil {
-							continue
-						}
-						if metricPoint.Value < min {
-							isExceeded = true
-							if status < rule.Status {
-								status = rule.Status
-							}
-							if overallStatus < rule.Status {
-								overallStatus = rule.Status
-								annotationValue = getAnnotationValue(thresholdRule, metricPoint.Value, isExceeded)
-							}
-							continue
-						} else {
-							annotationValue = getAnnotationValue(thresholdRule, metricPoint.Value, isExceeded)
-						}
-					}
-					if rule.Max != "" {
-						max, err := strconv.ParseFloat(rule.Max, 64)
-						if err != nil {
-							continue
-						}
-						if metricPoint.Value > max {
-							isExceeded = true
-							if status < rule.Status {
-								status = rule.Status
-							}
-							if overallStatus < rule.Status {
-								overallStatus = rule.Status
-								annotationValue = getAnnotationValue(thresholdRule, metricPoint.Value, isExceeded)
-							}
-						} else {
-							annotationValue = getAnnotationValue(thresholdRule, metricPoint.Value, isExceeded)
-						}
-					}
-				}
-				addThresholdAnnotation(event, thresholdRule, status, metricPoint.Value, isExceeded)
-			}
-		}
-		if !ruleMatched {
-			if thresholdRule.NullStatus > 0 {
-				addNullStatusThresholdAnnotation(event, thresholdRule, thresholdRule.NullStatus)
-				if overallStatus < thresholdRule.NullStatus {
-					overallStatus = thresholdRule.NullStatus
-					annotationValue = getNullStatusAnnotationValue(thresholdRule)
-				}
-			}
-		}
-	}
-
-	if annotationValue != "" {
-		event.AddAnnotation("sensu.io/notifications/"+corev2.CheckStatusToCaption(overallStatus), annotationValue)
-	}
-
-	return overallStatus
-}
-
-func addThresholdAnnotation(event *corev2.Event, metricThreshold *corev2.MetricThreshold, status uint32, value float64, isExceeded bool) {
-	event.AddAnnotation(getAnnotationKey(metricThreshold, status), getAnnotationValue(metricThreshold, value, isExceeded))
-}
-
-func addNullStatusThresholdAnnotation(event *corev2.Event, metricThreshold *corev2.MetricThreshold, status uint32) {
-	event.AddAnnotation(getAnnotationKey(metricThreshold, status), getNullStatusAnnotationValue(metricThreshold))
-}
-
-func getAnnotationKey(metricThreshold *corev2.MetricThreshold, status uint32) string {
-	var key strings.Builder
-
-	key.WriteString("sensu.io/output_metric_thresholds/")
-	key.WriteString(metricThreshold.Name)
-	for _, tag := range metricThreshold.Tags {
-		key.WriteString(".")
-		key.WriteString(tag.Value)
-	}
-	key.WriteString("/")
-	key.WriteString(corev2.CheckStatusToCaption(status))
-
-	return key.String()
-}
-
-func getAnnotationValue(metricThreshold *corev2.MetricThreshold, value float64, isExceeded bool) string {
-	var val strings.Builder
-	var tagsKeyVal strings.Builder
-
-	for tagIdx, tag := range metricThreshold.Tags {
-		if tagIdx > 0 {
-			tagsKeyVal.WriteString(",")
-		}
-		tagsKeyVal.WriteString(tag.Name)
-		tagsKeyVal.WriteString("=")
-		tagsKeyVal.WriteString(tag.Value)
-	}
-
-	val.WriteString("The value of ")
-	val.WriteString(metricThreshold.Name)
-	if tagsKeyVal.Len() > 0 {
-		val.WriteString(" (")
-		val.WriteString(tagsKeyVal.String())
-		val.WriteString(")")
-	}
-	if isExceeded {
-		val.WriteString(" exceeded the configured threshold")
-	} else {
-		val.WriteString(" is within the configured threshold")
-	}
-
-	for _, t := range metricThreshold.Thresholds {
-		hasMin := len(t.Min) > 0
-		hasMax := len(t.Max) > 0
-		val.WriteString("; expected ")
-		if hasMin {
-			val.WriteString("min: ")
-			val.WriteString(t.Min)
-		}
-		if hasMin && hasMax {
-			val.WriteString(" - ")
-		}
-		if hasMax {
-			val.WriteString("max: ")
-			val.WriteString(t.Max)
-		}
-		val.WriteString(" (status: ")
-		val.WriteString(corev2.CheckStatusToCaption(t.Status))
-		val.WriteString(")")
-	}
-
-	val.WriteString("; actual: ")
-	val.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
-
-	return val.String()
-}
-
-func getNullStatusAnnotationValue(metricThreshold *corev2.MetricThreshold) string {
-	var val strings.Builder
-	var tagsKeyVal strings.Builder
-
-	for tagIdx, tag := range metricThreshold.Tags {
-		if tagIdx > 0 {
-			tagsKeyVal.WriteString(", ")
-		}
-		tagsKeyVal.WriteString(tag.Name)
-		tagsKeyVal.WriteString("=\"")
-		tagsKeyVal.WriteString(tag.Value)
-		tagsKeyVal.WriteString("\"")
-	}
-
-	val.WriteString(strings.ToUpper(corev2.CheckStatusToCaption(metricThreshold.NullStatus)))
-	val.WriteString(" : no metric matching \"")
-	val.WriteString(metricThreshold.Name)
-	val.WriteString("\"")
-	if tagsKeyVal.Len() > 0 {
-		val.WriteString(" (")
-		val.WriteString(tagsKeyVal.String())
-		val.WriteString(")")
-	}
-	val.WriteString(" was found")
-
-	for _, t := range metricThreshold.Thresholds {
-		hasMin := len(t.Min) > 0
-		hasMax := len(t.Max) > 0
-		val.WriteString("; expected ")
-		if hasMin {
-			val.WriteString("min: ")
-			val.WriteString(t.Min)
-		}
-		if hasMin && hasMax {
-			val.WriteString(" - ")
-		}
-		if hasMax {
-			val.WriteString("max: ")
-			val.WriteString(t.Max)
-		}
-		val.WriteString(" (status: ")
-		val.WriteString(corev2.CheckStatusToCaption(t.Status))
-		val.WriteString(")")
-	}
-
-	return val.String()
-}
@@ -2,8 +2,8 @@ package agent
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
-	"fmt"
 	"testing"
 	"time"
 
@@ -523,6 +523,42 @@ func TestFailOnAssetCheckWithDisabledAssets(t *testing.T) {
 	}
 }
 
+func TestFailOnUnsignedCheck(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+
+	agent, err := NewAgent(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agent.signatureKeys = []ed25519.PublicKey{public}
+	agent.sendq = make(chan *transport.Message, 5)
+	checkConfig := corev2.FixtureCheckConfig("check")
+	request := &corev2.CheckRequest{Config: checkConfig, Issued: time.Now().Unix()}
+	payload, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal("error marshaling check request")
+	}
+	if err := agent.handleCheck(context.Background(), payload); err != nil {
+		t.Fatal(err)
+	}
+	msg := <-agent.sendq
+	var event corev2.Event
+	if err := json.Unmarshal(msg.Payload, &event); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := event.Check.Output, signatureOnDenyOutput; got != want {
+		t.Errorf("bad output: got %q, want %q", got, want)
+	}
+	if got, want := event.Check.Status, uint32(3); got != want {
+		t.Errorf("bad status: got %d, want %d", got, want)
+	}
+}
+
 func TestCheckHandlerProcessedBy(t *testing.T) {
 	checkConfig := corev2.FixtureCheckConfig("check")
 	request := &corev2.CheckRequest{Config: checkConfig, Issued: time.Now().Unix()}
@@ -555,118 +591,3 @@ func TestCheckHandlerProcessedBy(t *testing.T) {
 		t.Errorf("bad processed_by: got %q, want %q", got, want)
 	}
 }
-
-func TestEvaluateOutputMetricThresholds(t *testing.T) {
-	now := time.Now().UnixMilli()
-
-	metric1 := &corev2.MetricPoint{Name: "disk_rate", Value: 99999.0, Timestamp: now, Tags: nil}
-	metric2 := &corev2.MetricPoint{Name: "network_rate", Value: 100001.0, Timestamp: now, Tags: []*corev2.MetricTag{{Name: "device", Value: "eth0"}}}
-
-	statusOKAnnotation := "sensu.io/notifications/ok"
-	statusWarningAnnotation := "sensu.io/notifications/warning"
-	statusUnknownAnnotation := "sensu.io/notifications/unknown"
-	statusCriticalAnnotation := "sensu.io/notifications/critical"
-	diskOKAnnotation := "sensu.io/output_metric_thresholds/disk_rate/ok"
-	diskCriticalAnnotation := "sensu.io/output_metric_thresholds/disk_rate/critical"
-	diskWarningAnnotation := "sensu.io/output_metric_thresholds/disk_rate/warning"
-	netUnknownAnnotation := "sensu.io/output_metric_thresholds/network_rate/unknown"
-	notDiskWarningNullAnnotation := "sensu.io/output_metric_thresholds/not_a_disk_rate/warning"
-
-	testCases := []struct {
-		name                string
-		event               *corev2.Event
-		metrics             []*corev2.MetricPoint
-		thresholds          []*corev2.MetricThreshold
-		expectedStatus      uint32
-		expectedAnnotations []string
-	}{
-		{
-			name:                "minimum rule match",
-			event:               &corev2.Event{Check: &corev2.Check{Status: 0}},
-			metrics:             []*corev2.MetricPoint{metric1},
-			thresholds:          []*corev2.MetricThreshold{{Name: "disk_rate", Thresholds: []*corev2.MetricThresholdRule{{Min: "200000.0", Status: 2}}}},
-			expectedStatus:      2,
-			expectedAnnotations: []string{statusCriticalAnnotation, diskCriticalAnnotation},
-		}, {
-			name:                "maximum rule match",
-			event:               &corev2.Event{Check: &corev2.Check{Status: 0}},
-			metrics:             []*corev2.MetricPoint{metric1},
-			thresholds:          []*corev2.MetricThreshold{{Name: "disk_rate", Thresholds: []*corev2.MetricThresholdRule{{Max: "50000.0", Status: 2}}}},
-			expectedStatus:      2,
-			expectedAnnotations: []string{statusCriticalAnnotation, diskCriticalAnnotation},
-		}, {
-			name:                "no min rule match",
-			event:               &corev2.Event{Check: &corev2.Check{Status: 0}},
-			metrics:             []*corev2.MetricPoint{metric1},
-			thresholds:          []*corev2.MetricThreshold{{Name: "disk_rate", Thresholds: []*corev2.MetricThresholdRule{{Min: "50000.0", Status: 2}}}},
-			expectedStatus:      0,
-			expectedAnnotations: []string{statusOKAnnotation, diskOKAnnotation},
-		}, {
-			name:                "no max rule match",
-			event:               &corev2.Event{Check: &corev2.Check{Status: 0}},
-			metrics:             []*corev2.MetricPoint{metric1},
-			thresholds:          []*corev2.MetricThreshold{{Name: "disk_rate", Thresholds: []*corev2.MetricThresholdRule{{Max: "200000.0", Status: 2}}}},
-			expectedStatus:      0,
-			expectedAnnotations: []string{statusOKAnnotation, diskOKAnnotation},
-		}, {
-			name:                "min and max rule match",
-			event:               &corev2.Event{Check: &corev2.Check{Status: 0}},
-			metrics:             []*corev2.MetricPoint{metric1},
-			thresholds:          []*corev2.MetricThreshold{{Name: "disk_rate", Thresholds: []*corev2.MetricThresholdRule{{Min: "200000.0", Status: 1}, {Max: "75000.0", Status: 2}}}},
-			expectedStatus:      2,
-			expectedAnnotations: []string{statusCriticalAnnotation, diskCriticalAnnotation},
-		}, {
-			name:                "only one rule match",
-			event:               &corev2.Event{Check: &corev2.Check{Status: 0}},
-			metrics:             []*corev2.MetricPoint{metric1},
-			thresholds:          []*corev2.MetricThreshold{{Name: "disk_rate", Thresholds: []*corev2.MetricThresholdRule{{Min: "200000.0", Status: 1}, {Max: "200000.0", Status: 2}}}},
-			expectedStatus:      1,
-			expectedAnnotations: []string{statusWarningAnnotation, diskWarningAnnotation},
-		}, {
-			name:                "no filter match - null status",
-			event:               &corev2.Event{Check: &corev2.Check{Status: 0}},
-			metrics:             []*corev2.MetricPoint{metric1},
-			thresholds:          []*corev2.MetricThreshold{{Name: "not_a_disk_rate", NullStatus: 1, Thresholds: []*corev2.MetricThresholdRule{{Max: "200000.0", Status: 2}}}},
-			expectedStatus:      1,
-			expectedAnnotations: []string{statusWarningAnnotation, notDiskWarningNullAnnotation},
-		}, {
-			name:                "multi metric and filter match, no rule match",
-			event:               &corev2.Event{Check: &corev2.Check{Status: 0}},
-			metrics:             []*corev2.MetricPoint{metric1, metric2},
-			thresholds:          []*corev2.MetricThreshold{{Name: "disk_rate", NullStatus: 1, Thresholds: []*corev2.MetricThresholdRule{{Max: "200000.0", Status: 2}}}},
-			expectedStatus:      0,
-			expectedAnnotations: []string{statusOKAnnotation, diskOKAnnotation},
-		}, {
-			name:                "multi metric and filter and rule match",
-			event:               &corev2.Event{Check: &corev2.Check{Status: 0}},
-			metrics:             []*corev2.MetricPoint{metric1, metric2},
-			thresholds:          []*corev2.MetricThreshold{{Name: "disk_rate", NullStatus: 1, Thresholds: []*corev2.MetricThresholdRule{{Max: "50000.0", Status: 2}}}},
-			expectedStatus:      2,
-			expectedAnnotations: []string{statusCriticalAnnotation, diskCriticalAnnotation},
-		}, {
-			name:    "multi metric and multi rule match",
-			event:   &corev2.Event{Check: &corev2.Check{Status: 0}},
-			metrics: []*corev2.MetricPoint{metric1, metric2},
-			thresholds: []*corev2.MetricThreshold{{Name: "disk_rate", NullStatus: 1, Thresholds: []*corev2.MetricThresholdRule{{Max: "50000.0", Status: 2}}},
-				{Name: "network_rate", Thresholds: []*corev2.MetricThresholdRule{{Max: "40000", Status: 3}}}},
-			expectedStatus:      3,
-			expectedAnnotations: []string{statusUnknownAnnotation, diskCriticalAnnotation, netUnknownAnnotation},
-		},
-	}
-
-	for _, test := range testCases {
-		t.Run(test.name, func(t *testing.T) {
-			event := test.event
-			test.event.Metrics = &corev2.Metrics{Points: test.metrics}
-			test.event.Check.OutputMetricThresholds = test.thresholds
-			status := evaluateOutputMetricThresholds(event)
-			assert.Equal(t, test.expectedStatus, status)
-
-			assert.Equal(t, len(test.expectedAnnotations), len(event.Annotations), "wrong annotation count")
-			for _, expectedKey := range test.expectedAnnotations {
-				_, ok := event.Annotations[expectedKey]
-				assert.True(t, ok, fmt.Sprintf("missing annotation %s", expectedKey))
-			}
-		})
-	}
-}
@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev2 "github.com/sensu/core/v2"
+)
+
+const (
+	// SignatureAnnotation is the annotation of the checks and hooks holding the
+	// base64 encoded ed25519 signature of their definition.
+	SignatureAnnotation = "sensu.io/signature"
+
+	signatureOnDenyOutput = "check command denied by the agent signature verification"
+
+	// signatureVersion prefixes the signed messages, so that their format can
+	// evolve.
+	signatureVersion = "sensu-signature-v1"
+)
+
+// SignatureMessage returns the message signed by the signature of a check or
+// hook, which covers its namespace, its name, its command and its runtime
+// assets.
+func SignatureMessage(meta corev2.ObjectMeta, command string, runtimeAssets []string) []byte {
+	assets := append([]string{}, runtimeAssets...)
+	sort.Strings(assets)
+	return []byte(strings.Join([]string{
+		signatureVersion,
+		meta.Namespace,
+		meta.Name,
+		command,
+		strings.Join(assets, ","),
+	}, "\n"))
+}
+
+// readSignaturePublicKeys reads the PEM encoded ed25519 public keys trusted to
+// sign the checks and hooks.
+func readSignaturePublicKeys(path string, readBytes func(string) ([]byte, error)) ([]ed25519.PublicKey, error) {
+	if path == "" {
+		return nil, nil
+	}
+	b, err := readBytes(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []ed25519.PublicKey
+	for {
+		var block *pem.Block
+		block, b = pem.Decode(b)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature public key: %s", err)
+		}
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, errors.New("signature public keys must be ed25519 keys")
+		}
+		keys = append(keys, edKey)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signature public key found in %s", path)
+	}
+	return keys, nil
+}
+
+// verifySignature returns an error if the agent verifies the signatures of
+// the checks and hooks, and the signature annotation is missing or was not
+// made by any of the trusted keys.
+func (a *Agent) verifySignature(meta corev2.ObjectMeta, command string, runtimeAssets []string) error {
+	if len(a.signatureKeys) == 0 {
+		return nil
+	}
+	annotation, ok := meta.Annotations[SignatureAnnotation]
+	if !ok {
+		return errors.New("signature annotation is missing")
+	}
+	signature, err := base64.StdEncoding.DecodeString(annotation)
+	if err != nil {
+		return fmt.Errorf("signature annotation is invalid: %s", err)
+	}
+	message := SignatureMessage(meta, command, runtimeAssets)
+	for _, key := range a.signatureKeys {
+		if ed25519.Verify(key, message, signature) {
+			return nil
+		}
+	}
+	return errors.New("signature does not match any of the trusted keys")
+}
@@ -0,0 +1,69 @@
+package agent
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSignaturePublicKeys(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+	b := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	keys, err := readSignaturePublicKeys("keys.pem", func(string) ([]byte, error) {
+		return b, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []ed25519.PublicKey{public}, keys)
+
+	keys, err = readSignaturePublicKeys("", func(string) ([]byte, error) {
+		return nil, errors.New("unexpected read")
+	})
+	require.NoError(t, err)
+	assert.Nil(t, keys)
+
+	_, err = readSignaturePublicKeys("keys.pem", func(string) ([]byte, error) {
+		return []byte("not a key"), nil
+	})
+	assert.Error(t, err)
+}
+
+func TestVerifySignature(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	check := corev2.FixtureCheckConfig("check-disk")
+	check.Command = "check-disk -w 80"
+	check.RuntimeAssets = []string{"sensu-disk-checks", "sensu-ruby-runtime"}
+	signature := ed25519.Sign(private, SignatureMessage(check.ObjectMeta, check.Command, check.RuntimeAssets))
+
+	// The signature is not required without trusted keys
+	agent := &Agent{}
+	assert.NoError(t, agent.verifySignature(check.ObjectMeta, check.Command, check.RuntimeAssets))
+
+	agent.signatureKeys = []ed25519.PublicKey{public}
+	assert.Error(t, agent.verifySignature(check.ObjectMeta, check.Command, check.RuntimeAssets))
+
+	check.Annotations = map[string]string{SignatureAnnotation: base64.StdEncoding.EncodeToString(signature)}
+	assert.NoError(t, agent.verifySignature(check.ObjectMeta, check.Command, check.RuntimeAssets))
+
+	// The order of the runtime assets does not matter
+	assert.NoError(t, agent.verifySignature(check.ObjectMeta, check.Command, []string{"sensu-ruby-runtime", "sensu-disk-checks"}))
+
+	assert.Error(t, agent.verifySignature(check.ObjectMeta, "rm -rf /", check.RuntimeAssets))
+	assert.Error(t, agent.verifySignature(check.ObjectMeta, check.Command, []string{"sensu-disk-checks"}))
+
+	check.Annotations[SignatureAnnotation] = "invalid"
+	assert.Error(t, agent.verifySignature(check.ObjectMeta, check.Command, check.RuntimeAssets))
+}
@@ -0,0 +1,127 @@
+package agent
+
+import (
+	"context"
+
+	time "github.com/echlebek/timeproxy"
+	"github.com/sensu/sensu-go/system"
+)
+
+const (
+	// cloudMetadataTimeout is the maximum time to wait for the instance
+	// metadata service of the cloud provider.
+	cloudMetadataTimeout = 10 * time.Second
+
+	// The labels and annotations of the entity populated from the cloud
+	// instance metadata.
+	cloudProviderLabel     = "cloud_provider"
+	cloudRegionLabel       = "cloud_region"
+	cloudZoneLabel         = "cloud_zone"
+	cloudInstanceTypeLabel = "cloud_instance_type"
+	cloudInstanceIDAnnot   = "cloud_instance_id"
+	cloudImageAnnot        = "cloud_image"
+)
+
+// RefreshCloudMetadata queries the instance metadata service of the cloud
+// provider, and updates the labels and annotations of the agent's entity with
+// the metadata of its instance.
+func (a *Agent) RefreshCloudMetadata(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, cloudMetadataTimeout)
+	defer cancel()
+
+	// Reuse the cloud provider detected along with the system info, if any
+	provider := a.getSystemInfo().CloudProvider
+	metadata, err := system.GetCloudMetadata(ctx, provider)
+	if err != nil {
+		return err
+	}
+
+	a.cloudMetadataMu.Lock()
+	changed := a.cloudMetadata == nil || *a.cloudMetadata != metadata
+	a.cloudMetadata = &metadata
+	a.cloudMetadataMu.Unlock()
+
+	if changed {
+		logger.WithField("provider", metadata.Provider).Info("cloud instance metadata updated")
+		// The local entity config is cached, so it must be rebuilt with the new
+		// labels and annotations
+		a.entityMu.Lock()
+		if a.entityConfig == a.localEntityConfig {
+			a.entityConfig = nil
+		}
+		a.localEntityConfig = nil
+		a.entityMu.Unlock()
+	}
+	return nil
+}
+
+func (a *Agent) refreshCloudMetadataPeriodically(ctx context.Context) {
+	defer logger.Info("shutting down cloud metadata collector")
+	interval := a.config.CloudMetadataRefreshInterval
+	if interval <= 0 {
+		interval = DefaultCloudMetadataRefreshInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := a.RefreshCloudMetadata(ctx); err != nil {
+				logger.WithError(err).Error("failed to refresh cloud instance metadata")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// entityLabels returns the labels of the agent's entity, which are the labels
+// configured on the agent along with the cloud instance metadata. The labels
+// configured on the agent take precedence.
+func (a *Agent) entityLabels() map[string]string {
+	a.cloudMetadataMu.RLock()
+	defer a.cloudMetadataMu.RUnlock()
+	if a.cloudMetadata == nil {
+		return a.config.Labels
+	}
+	return mergeCloudMetadata(a.config.Labels, map[string]string{
+		cloudProviderLabel:     a.cloudMetadata.Provider,
+		cloudRegionLabel:       a.cloudMetadata.Region,
+		cloudZoneLabel:         a.cloudMetadata.Zone,
+		cloudInstanceTypeLabel: a.cloudMetadata.InstanceType,
+	})
+}
+
+// entityAnnotations returns the annotations of the agent's entity, which are
+// the annotations configured on the agent along with the cloud instance
+// metadata. The annotations configured on the agent take precedence.
+func (a *Agent) entityAnnotations() map[string]string {
+	a.cloudMetadataMu.RLock()
+	defer a.cloudMetadataMu.RUnlock()
+	if a.cloudMetadata == nil {
+		return a.config.Annotations
+	}
+	return mergeCloudMetadata(a.config.Annotations, map[string]string{
+		cloudInstanceIDAnnot: a.cloudMetadata.InstanceID,
+		cloudImageAnnot:      a.cloudMetadata.Image,
+	})
+}
+
+// mergeCloudMetadata returns the configured values along with the non-empty
+// values of the metadata that are not configured.
+func mergeCloudMetadata(configured, metadata map[string]string) map[string]string {
+	merged := make(map[string]string, len(configured)+len(metadata))
+	for k, v := range metadata {
+		if v != "" {
+			merged[k] = v
+		}
+	}
+	for k, v := range configured {
+		merged[k] = v
+	}
+	if len(merged) == 0 && configured == nil {
+		return nil
+	}
+	return merged
+}
@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/sensu/sensu-go/system"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudMetadataEntity(t *testing.T) {
+	agent := &Agent{
+		config: &Config{
+			AgentName:   "foo",
+			Namespace:   "default",
+			Labels:      map[string]string{"cloud_region": "configured", "team": "ops"},
+			Annotations: map[string]string{"owner": "ops"},
+		},
+	}
+
+	entity := agent.getAgentEntity()
+	assert.Equal(t, map[string]string{"cloud_region": "configured", "team": "ops"}, entity.Labels)
+
+	agent.cloudMetadata = &system.CloudMetadata{
+		Provider:     system.CloudProviderEC2,
+		Region:       "us-west-2",
+		Zone:         "us-west-2b",
+		InstanceID:   "i-1234567890abcdef0",
+		InstanceType: "t2.micro",
+	}
+	// The local entity config is cached until the metadata changes
+	agent.clearAgentEntity()
+	agent.localEntityConfig = nil
+
+	entity = agent.getAgentEntity()
+	assert.Equal(t, map[string]string{
+		"cloud_provider":      "EC2",
+		"cloud_region":        "configured",
+		"cloud_zone":          "us-west-2b",
+		"cloud_instance_type": "t2.micro",
+		"team":                "ops",
+	}, entity.Labels)
+	assert.Equal(t, map[string]string{
+		"cloud_instance_id": "i-1234567890abcdef0",
+		"owner":             "ops",
+	}, entity.Annotations)
+}
+
+func TestMergeCloudMetadata(t *testing.T) {
+	assert.Nil(t, mergeCloudMetadata(nil, map[string]string{"cloud_image": ""}))
+	assert.Equal(t, map[string]string{"cloud_image": "ami-5fb8c835"}, mergeCloudMetadata(nil, map[string]string{"cloud_image": "ami-5fb8c835"}))
+	assert.Equal(t, map[string]string{"cloud_image": "custom"}, mergeCloudMetadata(map[string]string{"cloud_image": "custom"}, map[string]string{"cloud_image": "ami-5fb8c835"}))
+}
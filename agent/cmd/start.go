@@ -12,6 +12,7 @@ import (
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/agent"
 	"github.com/sensu/sensu-go/asset"
+	"github.com/sensu/sensu-go/transport"
 	"github.com/sensu/sensu-go/util/path"
 	"github.com/sensu/sensu-go/util/url"
 	"github.com/sirupsen/logrus"
@@ -41,14 +42,24 @@ const (
 	flagAPIPort                   = "api-port"
 	flagAssetsRateLimit           = "assets-rate-limit"
 	flagAssetsBurstLimit          = "assets-burst-limit"
+	flagAssetsCacheMaxSize        = "assets-cache-max-size"
+	flagAssetsCacheMaxAge         = "assets-cache-max-age"
+	flagAssetsProxy               = "assets-proxy"
 	flagBackendURL                = "backend-url"
+	flagBackendSelection          = "backend-selection"
+	flagBackendWeights            = "backend-weights"
+	flagBackendProbeInterval      = "backend-probe-interval"
 	flagCacheDir                  = "cache-dir"
+	flagCloudMetadata             = "cloud-metadata"
+	flagCloudMetadataInterval     = "cloud-metadata-refresh-interval"
 	flagConfigFile                = "config-file"
 	flagDeregister                = "deregister"
 	flagDeregistrationHandler     = "deregistration-handler"
 	flagDetectCloudProvider       = "detect-cloud-provider"
 	flagEventsRateLimit           = "events-rate-limit"
 	flagEventsBurstLimit          = "events-burst-limit"
+	flagEventSpoolMaxSize         = "event-spool-max-size"
+	flagEventSpoolMaxAge          = "event-spool-max-age"
 	flagKeepaliveHandlers         = "keepalive-handlers"
 	flagKeepaliveInterval         = "keepalive-interval"
 	flagKeepaliveWarningTimeout   = "keepalive-warning-timeout"
@@ -56,14 +67,24 @@ const (
 	flagKeepaliveCheckLabels      = "keepalive-check-labels"
 	flagKeepaliveCheckAnnotations = "keepalive-check-annotations"
 	flagKeepalivePipelines        = "keepalive-pipelines"
+	flagKeepaliveSystemMetrics    = "keepalive-system-metrics"
 	flagNamespace                 = "namespace"
 	flagPassword                  = "password"
+	flagEventLogChannels          = "event-log-channels"
+	flagEventLogQuery             = "event-log-query"
+	flagEventLogHandlers          = "event-log-handlers"
+	flagEventLogSeverities        = "event-log-severities"
+	flagPrometheusScrapeTargets   = "prometheus-scrape-targets"
+	flagPrometheusScrapeInterval  = "prometheus-scrape-interval"
+	flagPrometheusScrapeTimeout   = "prometheus-scrape-timeout"
+	flagPrometheusScrapeHandlers  = "prometheus-scrape-handlers"
 	flagRedact                    = "redact"
 	flagStatsdDisable             = "statsd-disable"
 	flagStatsdEventHandlers       = "statsd-event-handlers"
 	flagStatsdFlushInterval       = "statsd-flush-interval"
 	flagStatsdMetricsHost         = "statsd-metrics-host"
 	flagStatsdMetricsPort         = "statsd-metrics-port"
+	flagStatsdPercentiles         = "statsd-percentiles"
 	flagSubscriptions             = "subscriptions"
 	flagUser                      = "user"
 	flagDisableAPI                = "disable-api"
@@ -72,15 +93,25 @@ const (
 	flagLabels                    = "labels"
 	flagAnnotations               = "annotations"
 	flagAllowList                 = "allow-list"
+	flagSignaturePublicKeys       = "signature-public-keys"
+	flagAssetSignaturePublicKeys  = "asset-signature-public-keys"
+	flagAssetSignatureTrustRoots  = "asset-signature-trust-roots"
+	flagAssetSignatureSubject     = "asset-signature-subject"
+	flagAssetSignatureIssuer      = "asset-signature-issuer"
+	flagAssetSignatureStrict      = "asset-signature-strict"
 	flagBackendHandshakeTimeout   = "backend-handshake-timeout"
+	flagBackendCompressionLevel   = "backend-compression-level"
 	flagBackendHeartbeatInterval  = "backend-heartbeat-interval"
 	flagBackendHeartbeatTimeout   = "backend-heartbeat-timeout"
 	flagAgentManagedEntity        = "agent-managed-entity"
 	flagRetryMin                  = "retry-min"
 	flagRetryMax                  = "retry-max"
 	flagRetryMultiplier           = "retry-multiplier"
+	flagRetryJitter               = "retry-jitter"
 	flagMaxSessionLength          = "max-session-length"
 	flagStripNetworks             = "strip-networks"
+	flagHookTimeout               = "hook-timeout"
+	flagHookMaxOutputSize         = "hook-max-output-size"
 
 	// TLS flags
 	flagTrustedCAFile         = "trusted-ca-file"
@@ -114,13 +145,20 @@ func NewAgentConfig(cmd *cobra.Command) (*agent.Config, error) {
 	cfg.API.Port = viper.GetInt(flagAPIPort)
 	cfg.AssetsRateLimit = rate.Limit(viper.GetFloat64(flagAssetsRateLimit))
 	cfg.AssetsBurstLimit = viper.GetInt(flagAssetsBurstLimit)
+	cfg.AssetsCacheMaxSize = viper.GetInt64(flagAssetsCacheMaxSize)
+	cfg.AssetsCacheMaxAge = viper.GetDuration(flagAssetsCacheMaxAge)
+	cfg.AssetsProxy = viper.GetBool(flagAssetsProxy)
 	cfg.CacheDir = viper.GetString(flagCacheDir)
+	cfg.CloudMetadata = viper.GetBool(flagCloudMetadata)
+	cfg.CloudMetadataRefreshInterval = viper.GetDuration(flagCloudMetadataInterval)
 	cfg.Deregister = viper.GetBool(flagDeregister)
 	cfg.DeregistrationHandler = viper.GetString(flagDeregistrationHandler)
 	cfg.DetectCloudProvider = viper.GetBool(flagDetectCloudProvider)
 	cfg.DisableAssets = viper.GetBool(flagDisableAssets)
 	cfg.EventsAPIRateLimit = rate.Limit(viper.GetFloat64(flagEventsRateLimit))
 	cfg.EventsAPIBurstLimit = viper.GetInt(flagEventsBurstLimit)
+	cfg.EventSpoolMaxSize = viper.GetInt64(flagEventSpoolMaxSize)
+	cfg.EventSpoolMaxAge = viper.GetDuration(flagEventSpoolMaxAge)
 	cfg.KeepaliveHandlers = viper.GetStringSlice(flagKeepaliveHandlers)
 	cfg.KeepaliveInterval = uint32(viper.GetInt(flagKeepaliveInterval))
 	cfg.KeepaliveWarningTimeout = uint32(viper.GetInt(flagKeepaliveWarningTimeout))
@@ -128,8 +166,16 @@ func NewAgentConfig(cmd *cobra.Command) (*agent.Config, error) {
 	cfg.KeepaliveCheckLabels = viper.GetStringMapString(flagKeepaliveCheckLabels)
 	cfg.KeepaliveCheckAnnotations = viper.GetStringMapString(flagKeepaliveCheckAnnotations)
 	cfg.KeepalivePipelines = viper.GetStringSlice(flagKeepalivePipelines)
+	cfg.KeepaliveSystemMetrics = viper.GetBool(flagKeepaliveSystemMetrics)
 	cfg.Namespace = viper.GetString(flagNamespace)
 	cfg.Password = viper.GetString(flagPassword)
+	cfg.EventLog.Channels = viper.GetStringSlice(flagEventLogChannels)
+	cfg.EventLog.Query = viper.GetString(flagEventLogQuery)
+	cfg.EventLog.Handlers = viper.GetStringSlice(flagEventLogHandlers)
+	cfg.PrometheusScrape.Targets = viper.GetStringSlice(flagPrometheusScrapeTargets)
+	cfg.PrometheusScrape.Interval = viper.GetInt(flagPrometheusScrapeInterval)
+	cfg.PrometheusScrape.Timeout = viper.GetInt(flagPrometheusScrapeTimeout)
+	cfg.PrometheusScrape.Handlers = viper.GetStringSlice(flagPrometheusScrapeHandlers)
 	cfg.StatsdServer.Disable = viper.GetBool(flagStatsdDisable)
 	cfg.StatsdServer.FlushInterval = viper.GetInt(flagStatsdFlushInterval)
 	cfg.StatsdServer.Host = viper.GetString(flagStatsdMetricsHost)
@@ -137,14 +183,24 @@ func NewAgentConfig(cmd *cobra.Command) (*agent.Config, error) {
 	cfg.StatsdServer.Handlers = viper.GetStringSlice(flagStatsdEventHandlers)
 	cfg.User = viper.GetString(flagUser)
 	cfg.AllowList = viper.GetString(flagAllowList)
+	cfg.SignaturePublicKeys = viper.GetString(flagSignaturePublicKeys)
+	cfg.AssetSignaturePublicKeys = viper.GetString(flagAssetSignaturePublicKeys)
+	cfg.AssetSignatureTrustRoots = viper.GetString(flagAssetSignatureTrustRoots)
+	cfg.AssetSignatureSubject = viper.GetString(flagAssetSignatureSubject)
+	cfg.AssetSignatureIssuer = viper.GetString(flagAssetSignatureIssuer)
+	cfg.AssetSignatureStrict = viper.GetBool(flagAssetSignatureStrict)
 	cfg.BackendHandshakeTimeout = viper.GetInt(flagBackendHandshakeTimeout)
+	cfg.BackendCompressionLevel = viper.GetInt(flagBackendCompressionLevel)
 	cfg.BackendHeartbeatInterval = viper.GetInt(flagBackendHeartbeatInterval)
 	cfg.BackendHeartbeatTimeout = viper.GetInt(flagBackendHeartbeatTimeout)
 	cfg.RetryMin = viper.GetDuration(flagRetryMin)
 	cfg.RetryMax = viper.GetDuration(flagRetryMax)
 	cfg.RetryMultiplier = viper.GetFloat64(flagRetryMultiplier)
+	cfg.RetryJitter = viper.GetFloat64(flagRetryJitter)
 	cfg.MaxSessionLength = viper.GetDuration(flagMaxSessionLength)
 	cfg.StripNetworks = viper.GetBool(flagStripNetworks)
+	cfg.HookTimeout = uint32(viper.GetInt(flagHookTimeout))
+	cfg.HookMaxOutputSize = viper.GetInt(flagHookMaxOutputSize)
 
 	// Set the labels & annotations using values defined configuration files
 	// and/or environment variables for now
@@ -158,11 +214,31 @@ func NewAgentConfig(cmd *cobra.Command) (*agent.Config, error) {
 	cfg.TLS.CertFile = viper.GetString(flagCertFile)
 	cfg.TLS.KeyFile = viper.GetString(flagKeyFile)
 
+	if err := transport.ValidateCompressionLevel(cfg.BackendCompressionLevel); err != nil {
+		return nil, fmt.Errorf("--%s: %s", flagBackendCompressionLevel, err)
+	}
+
+	severities, err := agent.ParseEventLogSeverities(viper.GetStringSlice(flagEventLogSeverities))
+	if err != nil {
+		return nil, fmt.Errorf("--%s: %s", flagEventLogSeverities, err)
+	}
+	cfg.EventLog.Severities = severities
+
+	percentiles, err := agent.ParseStatsdPercentiles(viper.GetStringSlice(flagStatsdPercentiles))
+	if err != nil {
+		return nil, fmt.Errorf("--%s: %s", flagStatsdPercentiles, err)
+	}
+	cfg.StatsdServer.Percentiles = percentiles
+
 	if cfg.KeepaliveCriticalTimeout != 0 && cfg.KeepaliveCriticalTimeout < cfg.KeepaliveWarningTimeout {
 		return nil, fmt.Errorf("if set, --%s must be greater than --%s",
 			flagKeepaliveCriticalTimeout, flagKeepaliveWarningTimeout)
 	}
 
+	if cfg.HookMaxOutputSize < 0 {
+		return nil, fmt.Errorf("--%s must not be negative", flagHookMaxOutputSize)
+	}
+
 	agentName := viper.GetString(flagAgentName)
 	if agentName != "" {
 		cfg.AgentName = agentName
@@ -176,6 +252,25 @@ func NewAgentConfig(cmd *cobra.Command) (*agent.Config, error) {
 		cfg.BackendURLs = append(cfg.BackendURLs, newURL)
 	}
 
+	cfg.BackendSelection = viper.GetString(flagBackendSelection)
+	switch cfg.BackendSelection {
+	case agent.BackendSelectionRandom, agent.BackendSelectionOrdered:
+	case agent.BackendSelectionWeighted:
+		cfg.BackendWeights = viper.GetIntSlice(flagBackendWeights)
+		if len(cfg.BackendWeights) != len(cfg.BackendURLs) {
+			return nil, fmt.Errorf("--%s must have a weight for each --%s", flagBackendWeights, flagBackendURL)
+		}
+		for _, weight := range cfg.BackendWeights {
+			if weight < 1 {
+				return nil, fmt.Errorf("--%s must be positive integers", flagBackendWeights)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("--%s must be one of %s, %s or %s", flagBackendSelection,
+			agent.BackendSelectionRandom, agent.BackendSelectionOrdered, agent.BackendSelectionWeighted)
+	}
+	cfg.BackendProbeInterval = viper.GetDuration(flagBackendProbeInterval)
+
 	cfg.Redact = viper.GetStringSlice(flagRedact)
 	cfg.Subscriptions = viper.GetStringSlice(flagSubscriptions)
 
@@ -291,40 +386,63 @@ func handleConfig(cmd *cobra.Command, arguments []string) error {
 	viper.SetDefault(flagAPIHost, agent.DefaultAPIHost)
 	viper.SetDefault(flagAPIPort, agent.DefaultAPIPort)
 	viper.SetDefault(flagBackendURL, []string{agent.DefaultBackendURL})
+	viper.SetDefault(flagBackendSelection, agent.BackendSelectionRandom)
+	viper.SetDefault(flagBackendProbeInterval, agent.DefaultBackendProbeInterval)
 	viper.SetDefault(flagCacheDir, path.SystemCacheDir("sensu-agent"))
 	viper.SetDefault(flagDeregister, false)
 	viper.SetDefault(flagDeregistrationHandler, "")
 	viper.SetDefault(flagDetectCloudProvider, false)
+	viper.SetDefault(flagCloudMetadata, false)
+	viper.SetDefault(flagCloudMetadataInterval, agent.DefaultCloudMetadataRefreshInterval)
 	viper.SetDefault(flagDisableAPI, false)
 	viper.SetDefault(flagDisableAssets, false)
 	viper.SetDefault(flagAssetsRateLimit, asset.DefaultAssetsRateLimit)
 	viper.SetDefault(flagAssetsBurstLimit, asset.DefaultAssetsBurstLimit)
+	viper.SetDefault(flagAssetsCacheMaxSize, 0)
+	viper.SetDefault(flagAssetsCacheMaxAge, 0)
+	viper.SetDefault(flagAssetsProxy, false)
 	viper.SetDefault(flagEventsRateLimit, agent.DefaultEventsAPIRateLimit)
 	viper.SetDefault(flagEventsBurstLimit, agent.DefaultEventsAPIBurstLimit)
+	viper.SetDefault(flagEventSpoolMaxSize, 0)
+	viper.SetDefault(flagEventSpoolMaxAge, agent.DefaultEventSpoolMaxAge)
 	viper.SetDefault(flagKeepaliveInterval, agent.DefaultKeepaliveInterval)
 	viper.SetDefault(flagKeepaliveWarningTimeout, corev2.DefaultKeepaliveTimeout)
 	viper.SetDefault(flagKeepaliveCriticalTimeout, 0)
+	viper.SetDefault(flagKeepaliveSystemMetrics, false)
 	viper.SetDefault(flagNamespace, agent.DefaultNamespace)
 	viper.SetDefault(flagPassword, agent.DefaultPassword)
+	viper.SetDefault(flagEventLogChannels, []string{})
+	viper.SetDefault(flagEventLogQuery, agent.DefaultEventLogQuery)
+	viper.SetDefault(flagEventLogHandlers, []string{})
+	viper.SetDefault(flagEventLogSeverities, []string{})
+	viper.SetDefault(flagPrometheusScrapeTargets, []string{})
+	viper.SetDefault(flagPrometheusScrapeInterval, agent.DefaultPrometheusScrapeInterval)
+	viper.SetDefault(flagPrometheusScrapeTimeout, agent.DefaultPrometheusScrapeTimeout)
+	viper.SetDefault(flagPrometheusScrapeHandlers, []string{})
 	viper.SetDefault(flagRedact, corev2.DefaultRedactFields)
 	viper.SetDefault(flagStatsdDisable, agent.DefaultStatsdDisable)
 	viper.SetDefault(flagStatsdFlushInterval, agent.DefaultStatsdFlushInterval)
 	viper.SetDefault(flagStatsdMetricsHost, agent.DefaultStatsdMetricsHost)
 	viper.SetDefault(flagStatsdMetricsPort, agent.DefaultStatsdMetricsPort)
 	viper.SetDefault(flagStatsdEventHandlers, []string{})
+	viper.SetDefault(flagStatsdPercentiles, []string{})
 	viper.SetDefault(flagSubscriptions, []string{})
 	viper.SetDefault(flagUser, agent.DefaultUser)
 	viper.SetDefault(flagTrustedCAFile, "")
 	viper.SetDefault(flagInsecureSkipTLSVerify, false)
 	viper.SetDefault(flagLogLevel, "info")
 	viper.SetDefault(flagBackendHandshakeTimeout, 15)
+	viper.SetDefault(flagBackendCompressionLevel, 0)
 	viper.SetDefault(flagBackendHeartbeatInterval, 30)
 	viper.SetDefault(flagBackendHeartbeatTimeout, 45)
 	viper.SetDefault(flagRetryMin, time.Second)
 	viper.SetDefault(flagRetryMax, 120*time.Second)
 	viper.SetDefault(flagRetryMultiplier, 2.0)
+	viper.SetDefault(flagRetryJitter, 1.0)
 	viper.SetDefault(flagMaxSessionLength, 0*time.Second)
 	viper.SetDefault(flagStripNetworks, false)
+	viper.SetDefault(flagHookTimeout, 0)
+	viper.SetDefault(flagHookMaxOutputSize, 0)
 
 	// Merge in flag set so that it appears in command usage
 	flags := flagSet()
@@ -407,21 +525,40 @@ func flagSet() *pflag.FlagSet {
 	flagSet.String(flagCacheDir, viper.GetString(flagCacheDir), "path to store cached data")
 	flagSet.String(flagDeregistrationHandler, viper.GetString(flagDeregistrationHandler), "deregistration handler that should process the entity deregistration event")
 	flagSet.Bool(flagDetectCloudProvider, viper.GetBool(flagDetectCloudProvider), "enable cloud provider detection")
+	flagSet.Bool(flagCloudMetadata, viper.GetBool(flagCloudMetadata), "add the region, zone, instance type and image of the cloud instance to the entity labels and annotations")
+	flagSet.Duration(flagCloudMetadataInterval, viper.GetDuration(flagCloudMetadataInterval), "interval at which the cloud instance metadata is refreshed")
 	flagSet.Float64(flagAssetsRateLimit, viper.GetFloat64(flagAssetsRateLimit), "maximum number of assets fetched per second")
 	flagSet.Int(flagAssetsBurstLimit, viper.GetInt(flagAssetsBurstLimit), "asset fetch burst limit")
+	flagSet.Int64(flagAssetsCacheMaxSize, viper.GetInt64(flagAssetsCacheMaxSize), "maximum size in bytes of the assets in the cache, above which the least recently used assets are evicted (0 disables the limit)")
+	flagSet.Duration(flagAssetsCacheMaxAge, viper.GetDuration(flagAssetsCacheMaxAge), "maximum duration an asset stays in the cache without being used (0 disables the limit)")
+	flagSet.Bool(flagAssetsProxy, viper.GetBool(flagAssetsProxy), "fetch the assets through the asset proxy of the backend, before falling back to their URL")
 	flagSet.Float64(flagEventsRateLimit, viper.GetFloat64(flagEventsRateLimit), "maximum number of events transmitted to the backend through the /events api")
 	flagSet.Int(flagEventsBurstLimit, viper.GetInt(flagEventsBurstLimit), "/events api burst limit")
+	flagSet.Int64(flagEventSpoolMaxSize, viper.GetInt64(flagEventSpoolMaxSize), "maximum size in bytes of the on-disk spool of the events produced while disconnected from the backend (0 disables the spool)")
+	flagSet.Duration(flagEventSpoolMaxAge, viper.GetDuration(flagEventSpoolMaxAge), "maximum age of the spooled events replayed to the backend (0 replays all the events)")
 	flagSet.String(flagNamespace, viper.GetString(flagNamespace), "agent namespace")
 	flagSet.String(flagPassword, viper.GetString(flagPassword), "agent password")
+	flagSet.StringSlice(flagEventLogChannels, viper.GetStringSlice(flagEventLogChannels), "comma-delimited list of Windows Event Log channels to send the events of (Windows only). This flag can also be invoked multiple times")
+	flagSet.String(flagEventLogQuery, viper.GetString(flagEventLogQuery), "XPath query selecting the events of the Windows Event Log channels")
+	flagSet.StringSlice(flagEventLogHandlers, viper.GetStringSlice(flagEventLogHandlers), "comma-delimited list of event handlers for the Windows Event Log events. This flag can also be invoked multiple times")
+	flagSet.StringSlice(flagEventLogSeverities, viper.GetStringSlice(flagEventLogSeverities), "comma-delimited list of level=status pairs overriding the check status of the Windows Event Log levels (critical, error, warning, information, verbose)")
+	flagSet.StringSlice(flagPrometheusScrapeTargets, viper.GetStringSlice(flagPrometheusScrapeTargets), "comma-delimited list of URLs of prometheus exporters to scrape. This flag can also be invoked multiple times")
+	flagSet.Int(flagPrometheusScrapeInterval, viper.GetInt(flagPrometheusScrapeInterval), "number of seconds between prometheus scrapes")
+	flagSet.Int(flagPrometheusScrapeTimeout, viper.GetInt(flagPrometheusScrapeTimeout), "number of seconds to wait for a prometheus exporter to respond")
+	flagSet.StringSlice(flagPrometheusScrapeHandlers, viper.GetStringSlice(flagPrometheusScrapeHandlers), "comma-delimited list of event handlers for the scraped prometheus metrics. This flag can also be invoked multiple times")
 	flagSet.StringSlice(flagRedact, viper.GetStringSlice(flagRedact), "comma-delimited list of fields to redact, overwrites the default fields. This flag can also be invoked multiple times")
 	flagSet.Bool(flagStatsdDisable, viper.GetBool(flagStatsdDisable), "disables the statsd listener and metrics server")
 	flagSet.StringSlice(flagStatsdEventHandlers, viper.GetStringSlice(flagStatsdEventHandlers), "comma-delimited list of event handlers for statsd metrics. This flag can also be invoked multiple times")
 	flagSet.Int(flagStatsdFlushInterval, viper.GetInt(flagStatsdFlushInterval), "number of seconds between statsd flush")
 	flagSet.String(flagStatsdMetricsHost, viper.GetString(flagStatsdMetricsHost), "address used for the statsd metrics server")
 	flagSet.Int(flagStatsdMetricsPort, viper.GetInt(flagStatsdMetricsPort), "port used for the statsd metrics server")
+	flagSet.StringSlice(flagStatsdPercentiles, viper.GetStringSlice(flagStatsdPercentiles), "comma-delimited list of percentiles computed for the statsd timers, histograms and distributions (default 90)")
 	flagSet.StringSlice(flagSubscriptions, viper.GetStringSlice(flagSubscriptions), "comma-delimited list of agent subscriptions. This flag can also be invoked multiple times")
 	flagSet.String(flagUser, viper.GetString(flagUser), "agent user")
 	flagSet.StringSlice(flagBackendURL, viper.GetStringSlice(flagBackendURL), "comma-delimited list of ws/wss URLs of Sensu backend servers. This flag can also be invoked multiple times")
+	flagSet.String(flagBackendSelection, viper.GetString(flagBackendSelection), "strategy used to select the backend URL to connect to: random, ordered (by order of the backend URLs) or weighted (by --backend-weights)")
+	flagSet.IntSlice(flagBackendWeights, viper.GetIntSlice(flagBackendWeights), "comma-delimited list of weights of the backend URLs, in the same order, when they are selected by weight")
+	flagSet.Duration(flagBackendProbeInterval, viper.GetDuration(flagBackendProbeInterval), "interval at which the health of the preferred backends is probed while connected to another backend, to reconnect to them once healthy (0 to disable)")
 	flagSet.StringSlice(flagKeepaliveHandlers, viper.GetStringSlice(flagKeepaliveHandlers), "comma-delimited list of keepalive handlers for this entity. This flag can also be invoked multiple times")
 	flagSet.Int(flagKeepaliveInterval, viper.GetInt(flagKeepaliveInterval), "number of seconds to send between keepalive events")
 	flagSet.Uint32(flagKeepaliveWarningTimeout, uint32(viper.GetInt(flagKeepaliveWarningTimeout)), "number of seconds until agent is considered dead by backend to create a warning event")
@@ -429,6 +566,7 @@ func flagSet() *pflag.FlagSet {
 	flagSet.StringToStringVar(&keepaliveCheckLabels, flagKeepaliveCheckLabels, nil, "keepalive labels map to add to keepalive events")
 	flagSet.StringToStringVar(&keepaliveCheckAnnotations, flagKeepaliveCheckAnnotations, nil, "keepalive annotations map to add to keepalive events")
 	flagSet.StringSlice(flagKeepalivePipelines, viper.GetStringSlice(flagKeepalivePipelines), "comma-delimited list of pipeline references for keepalive event")
+	flagSet.Bool(flagKeepaliveSystemMetrics, viper.GetBool(flagKeepaliveSystemMetrics), "send the load, memory and disk usage of the system with keepalives")
 	flagSet.Bool(flagDisableAPI, viper.GetBool(flagDisableAPI), "disable the Agent HTTP API")
 	flagSet.Bool(flagDisableAssets, viper.GetBool(flagDisableAssets), "disable check assets on this agent")
 	flagSet.String(flagTrustedCAFile, viper.GetString(flagTrustedCAFile), "TLS CA certificate bundle in PEM format")
@@ -439,15 +577,25 @@ func flagSet() *pflag.FlagSet {
 	flagSet.StringToStringVar(&labels, flagLabels, nil, "entity labels map")
 	flagSet.StringToStringVar(&annotations, flagAnnotations, nil, "entity annotations map")
 	flagSet.String(flagAllowList, viper.GetString(flagAllowList), "path to agent execution allow list configuration file")
+	flagSet.String(flagSignaturePublicKeys, viper.GetString(flagSignaturePublicKeys), "path to the PEM encoded ed25519 public keys trusted to sign the checks and hooks, whose signature is then required")
+	flagSet.String(flagAssetSignaturePublicKeys, viper.GetString(flagAssetSignaturePublicKeys), "path to the PEM encoded ECDSA or RSA public keys trusted to sign the assets")
+	flagSet.String(flagAssetSignatureTrustRoots, viper.GetString(flagAssetSignatureTrustRoots), "path to the PEM encoded root certificates trusted to issue the certificates of the keyless signatures of the assets")
+	flagSet.String(flagAssetSignatureSubject, viper.GetString(flagAssetSignatureSubject), "email address or URI of the signer required in the certificates of the keyless signatures of the assets")
+	flagSet.String(flagAssetSignatureIssuer, viper.GetString(flagAssetSignatureIssuer), "OIDC issuer of the identity of the signer required in the certificates of the keyless signatures of the assets")
+	flagSet.Bool(flagAssetSignatureStrict, viper.GetBool(flagAssetSignatureStrict), "reject the assets without a signature")
 	flagSet.Int(flagBackendHandshakeTimeout, viper.GetInt(flagBackendHandshakeTimeout), "number of seconds the agent should wait when negotiating a new WebSocket connection")
+	flagSet.Int(flagBackendCompressionLevel, viper.GetInt(flagBackendCompressionLevel), "level of the compression of the backend connection, if the backend enables it, between 1 and 9 (0 disables the compression)")
 	flagSet.Int(flagBackendHeartbeatInterval, viper.GetInt(flagBackendHeartbeatInterval), "interval at which the agent should send heartbeats to the backend")
 	flagSet.Int(flagBackendHeartbeatTimeout, viper.GetInt(flagBackendHeartbeatTimeout), "number of seconds the agent should wait for a response to a hearbeat")
 	flagSet.Bool(flagAgentManagedEntity, viper.GetBool(flagAgentManagedEntity), "manage this entity via the agent")
 	flagSet.Duration(flagRetryMin, viper.GetDuration(flagRetryMin), "minimum amount of time to wait before retrying an agent connection to the backend")
 	flagSet.Duration(flagRetryMax, viper.GetDuration(flagRetryMax), "maximum amount of time to wait before retrying an agent connection to the backend")
 	flagSet.Float64(flagRetryMultiplier, viper.GetFloat64(flagRetryMultiplier), "value multiplied with the current retry delay to produce a longer retry delay (bounded by --retry-max)")
+	flagSet.Float64(flagRetryJitter, viper.GetFloat64(flagRetryJitter), "maximum fraction of the current retry delay randomly added to it (negative to disable)")
 	flagSet.Duration(flagMaxSessionLength, viper.GetDuration(flagMaxSessionLength), "maximum amount of time after which the agent will reconnect to one of the configured backends (no maximum by default)")
 	flagSet.Bool(flagStripNetworks, viper.GetBool(flagStripNetworks), "do not include Network info in agent entity state")
+	flagSet.Uint32(flagHookTimeout, uint32(viper.GetInt(flagHookTimeout)), "number of seconds after which the hooks without a timeout are stopped (no timeout by default)")
+	flagSet.Int(flagHookMaxOutputSize, viper.GetInt(flagHookMaxOutputSize), "maximum number of bytes of hook output attached to the events (no maximum by default)")
 
 	flagSet.SetOutput(ioutil.Discard)
 
@@ -127,6 +127,44 @@ func TestNewAgentConfig_AgentManagedEntityFlag(t *testing.T) {
 	}
 }
 
+func TestNewAgentConfigBackendSelectionFlags(t *testing.T) {
+	newConfig := func(selection, weights string) (*agent.Config, error) {
+		cmd := &cobra.Command{
+			Use: "test",
+		}
+		if err := handleConfig(cmd, []string{}); err != nil {
+			t.Fatal("unexpected error while calling handleConfig: ", err)
+		}
+		// Restore the default selection for the other tests
+		defer func() { _ = cmd.Flags().Set(flagBackendSelection, agent.BackendSelectionRandom) }()
+		_ = cmd.Flags().Set(flagBackendURL, "ws://local:8081,ws://remote:8081")
+		_ = cmd.Flags().Set(flagBackendSelection, selection)
+		_ = cmd.Flags().Set(flagBackendWeights, weights)
+		return NewAgentConfig(cmd)
+	}
+
+	if _, err := newConfig("closest", ""); err == nil {
+		t.Fatal("expected an error with an unknown backend selection")
+	}
+	if _, err := newConfig(agent.BackendSelectionWeighted, "3"); err == nil {
+		t.Fatal("expected an error with a missing backend weight")
+	}
+	if _, err := newConfig(agent.BackendSelectionWeighted, "3,0"); err == nil {
+		t.Fatal("expected an error with a zero backend weight")
+	}
+
+	cfg, err := newConfig(agent.BackendSelectionWeighted, "3,1")
+	if err != nil {
+		t.Fatal("unexpected error while calling NewAgentConfig: ", err)
+	}
+	if !reflect.DeepEqual(cfg.BackendWeights, []int{3, 1}) {
+		t.Fatalf("TestNewAgentConfigBackendSelectionFlags() weights = %v, want %v", cfg.BackendWeights, []int{3, 1})
+	}
+	if cfg.BackendProbeInterval != agent.DefaultBackendProbeInterval {
+		t.Fatalf("TestNewAgentConfigBackendSelectionFlags() probe interval = %v, want %v", cfg.BackendProbeInterval, agent.DefaultBackendProbeInterval)
+	}
+}
+
 func tempConfig(t *testing.T, content string) *os.File {
 	t.Helper()
 
@@ -24,6 +24,14 @@ const (
 	// DefaultBackendURL specifies the default backend URL
 	DefaultBackendURL = "ws://127.0.0.1:8081"
 
+	// DefaultBackendProbeInterval is the default interval at which the health
+	// of the preferred backends is probed while connected to another backend.
+	DefaultBackendProbeInterval = 30 * time.Second
+
+	// DefaultCloudMetadataRefreshInterval is the default interval at which the
+	// cloud instance metadata of the agent's entity is refreshed.
+	DefaultCloudMetadataRefreshInterval = time.Hour
+
 	// DefaultEventsAPIRateLimit defines the rate limit, in events per second,
 	// for outgoing events.
 	DefaultEventsAPIRateLimit rate.Limit = 10.0
@@ -33,6 +41,10 @@ const (
 	// effect.
 	DefaultEventsAPIBurstLimit int = 10
 
+	// DefaultEventSpoolMaxAge is the default maximum age of the events replayed
+	// from the event spool.
+	DefaultEventSpoolMaxAge = 24 * time.Hour
+
 	// DefaultKeepaliveInterval specifies the default keepalive interval
 	DefaultKeepaliveInterval = 20
 
@@ -42,6 +54,14 @@ const (
 	// DefaultPassword specifies the default password
 	DefaultPassword = "P@ssw0rd!"
 
+	// DefaultPrometheusScrapeInterval specifies the default interval (in
+	// seconds) at which the prometheus exporters are scraped
+	DefaultPrometheusScrapeInterval = 60
+
+	// DefaultPrometheusScrapeTimeout specifies the default timeout (in seconds)
+	// of the scrapes of the prometheus exporters
+	DefaultPrometheusScrapeTimeout = 10
+
 	// DefaultStatsdDisable specifies if the statsd listener is disabled
 	DefaultStatsdDisable = false
 
@@ -79,13 +99,49 @@ type Config struct {
 	// AssetsBurstLimit is the maximum amount of burst allowed in a rate interval.
 	AssetsBurstLimit int
 
+	// AssetsCacheMaxSize is the maximum size, in bytes, of the assets in the
+	// cache, above which the least recently used assets are evicted. 0
+	// disables the limit.
+	AssetsCacheMaxSize int64
+
+	// AssetsCacheMaxAge is the maximum duration an asset stays in the cache
+	// without being used. 0 disables the limit.
+	AssetsCacheMaxAge time.Duration
+
+	// AssetsProxy fetches the assets through the asset proxy of the backend
+	// the agent is connected to, before falling back to their URL.
+	AssetsProxy bool
+
 	// BackendURLs is a list of URLs for the Sensu Backend. Default:
 	// ws://127.0.0.1:8081
 	BackendURLs []string
 
+	// BackendSelection is the strategy used to select the backend to connect
+	// to; one of random (the default), ordered or weighted.
+	BackendSelection string
+
+	// BackendWeights are the weights of the backend URLs, in the same order,
+	// when they are selected by weight.
+	BackendWeights []int
+
+	// BackendProbeInterval is the interval at which the health of the
+	// backends preferred over the one the agent is connected to is probed.
+	// The agent reconnects to the first healthy one. It is only used by the
+	// ordered and weighted backend selections, and disabled when 0.
+	BackendProbeInterval time.Duration
+
 	// CacheDir path where cached data is stored
 	CacheDir string
 
+	// CloudMetadata enables the enrichment of the entity with the metadata of
+	// its cloud instance, such as its region and instance type, which is
+	// queried from the instance metadata service of the cloud provider.
+	CloudMetadata bool
+
+	// CloudMetadataRefreshInterval is the interval at which the cloud instance
+	// metadata is refreshed.
+	CloudMetadataRefreshInterval time.Duration
+
 	// Deregister indicates whether the entity is ephemeral
 	Deregister bool
 
@@ -105,6 +161,10 @@ type Config struct {
 	// in check execution.
 	DisableAssets bool
 
+	// EventLog contains the configuration of the ingestion of the Windows
+	// Event Log events
+	EventLog *EventLogConfig
+
 	// EventsAPIRateLimit is the maximum number of events per second that will
 	// be transmitted to the backend from the events API
 	EventsAPIRateLimit rate.Limit
@@ -113,6 +173,16 @@ type Config struct {
 	// interval.
 	EventsAPIBurstLimit int
 
+	// EventSpoolMaxSize is the maximum size, in bytes, of the on-disk spool of
+	// the events produced while the agent is disconnected from the backend,
+	// which are replayed in order once reconnected. The spool is disabled when
+	// 0, or when CacheDir is os.DevNull.
+	EventSpoolMaxSize int64
+
+	// EventSpoolMaxAge is the maximum age of the spooled events. Older events
+	// are discarded instead of being replayed, unless 0.
+	EventSpoolMaxAge time.Duration
+
 	// KeepaliveHandlers contains the handlers to use for the agent's keepalive
 	// events
 	KeepaliveHandlers []string
@@ -138,6 +208,10 @@ type Config struct {
 	// KeepalivePipelines contain pipelines for agent's keepalive events
 	KeepalivePipelines []string
 
+	// KeepaliveSystemMetrics enables sending a snapshot of the load, memory and
+	// disk usage of the system with each keepalive.
+	KeepaliveSystemMetrics bool
+
 	// Labels are key-value pairs that users can provide to agent entities
 	Labels map[string]string
 
@@ -150,6 +224,10 @@ type Config struct {
 	// Password sets Agent's password
 	Password string
 
+	// PrometheusScrape contains the configuration of the scraping of the
+	// prometheus exporters
+	PrometheusScrape *PrometheusScrapeConfig
+
 	// Redact contains the fields to redact when marshalling the agent's entity
 	Redact []string
 
@@ -171,6 +249,12 @@ type Config struct {
 	// backoff
 	BackendHandshakeTimeout int
 
+	// BackendCompressionLevel specifies the level of the permessage-deflate
+	// compression of the messages exchanged with the backend, between 1 and 9.
+	// The messages are only compressed if the backend enables the compression
+	// as well, and never when 0.
+	BackendCompressionLevel int
+
 	// BackendHeartbeatInterval specifies the interval at which the agent must
 	// send a heartbeat to the backend
 	BackendHeartbeatInterval int
@@ -204,13 +288,52 @@ type Config struct {
 	// a longer retry delay. It is bounded by RetryMax.
 	RetryMultiplier float64
 
+	// RetryJitter is the maximum fraction of the retry delay randomly added to
+	// it, so that the agents do not all reconnect at once. It defaults to 1
+	// when 0, and a negative value disables the jitter.
+	RetryJitter float64
+
 	// MaxSessionLength is the maximum duration after which the agent will
 	// reconnect to one of the backends.
 	MaxSessionLength time.Duration
 
+	// SignaturePublicKeys is the path to the PEM encoded ed25519 public keys
+	// trusted to sign the checks and hooks. When set, the checks and hooks
+	// without a valid signature annotation are not executed.
+	SignaturePublicKeys string
+
+	// AssetSignaturePublicKeys is the path to the PEM encoded ECDSA or RSA
+	// public keys trusted to sign the asset builds.
+	AssetSignaturePublicKeys string
+
+	// AssetSignatureTrustRoots is the path to the PEM encoded root certificates
+	// trusted to issue the certificates of the keyless signatures of the asset
+	// builds.
+	AssetSignatureTrustRoots string
+
+	// AssetSignatureSubject is the email address or URI of the signer required
+	// in the certificates of the keyless signatures of the asset builds, and
+	// AssetSignatureIssuer is the OIDC issuer of that identity. Both are
+	// required with AssetSignatureTrustRoots.
+	AssetSignatureSubject string
+	AssetSignatureIssuer  string
+
+	// AssetSignatureStrict rejects the asset builds without a signature, when
+	// AssetSignaturePublicKeys or AssetSignatureTrustRoots is set.
+	AssetSignatureStrict bool
+
 	// StripNetworks is a boolean to specify if we need to strip network
 	// information from the agent entity state
 	StripNetworks bool
+
+	// HookTimeout is the timeout (in seconds) of the hooks which do not
+	// configure one. The hooks without a timeout run without one when 0.
+	HookTimeout uint32
+
+	// HookMaxOutputSize is the maximum number of bytes of hook output attached
+	// to the events, unless the hook sets the max output size annotation. The
+	// output is not truncated when 0.
+	HookMaxOutputSize int
 }
 
 // StatsdServerConfig contains the statsd server configuration
@@ -220,6 +343,29 @@ type StatsdServerConfig struct {
 	FlushInterval int
 	Handlers      []string
 	Disable       bool
+
+	// Percentiles are the percentiles computed for the timers, histograms and
+	// distributions. Only the 90th percentile is computed when empty.
+	Percentiles []float64
+}
+
+// PrometheusScrapeConfig contains the configuration of the scraping of the
+// prometheus exporters, whose samples are sent as metric events
+type PrometheusScrapeConfig struct {
+	Targets  []string
+	Interval int
+	Timeout  int
+	Handlers []string
+}
+
+// EventLogConfig contains the configuration of the ingestion of the Windows
+// Event Log events, which are sent as events whose check status is the
+// severity of their level
+type EventLogConfig struct {
+	Channels   []string
+	Query      string
+	Handlers   []string
+	Severities map[string]uint32
 }
 
 // FixtureConfig provides a new Config object initialized with defaults for use
@@ -242,10 +388,16 @@ func FixtureConfig() (*Config, func()) {
 		CacheDir:                cacheDir,
 		EventsAPIRateLimit:      DefaultEventsAPIRateLimit,
 		EventsAPIBurstLimit:     DefaultEventsAPIBurstLimit,
+		EventLog:                &EventLogConfig{},
+		EventSpoolMaxAge:        DefaultEventSpoolMaxAge,
 		KeepaliveInterval:       DefaultKeepaliveInterval,
 		KeepaliveWarningTimeout: corev2.DefaultKeepaliveTimeout,
 		Namespace:               DefaultNamespace,
 		Password:                DefaultPassword,
+		PrometheusScrape: &PrometheusScrapeConfig{
+			Interval: DefaultPrometheusScrapeInterval,
+			Timeout:  DefaultPrometheusScrapeTimeout,
+		},
 		StatsdServer: &StatsdServerConfig{
 			Host:          DefaultStatsdMetricsHost,
 			Port:          DefaultStatsdMetricsPort,
@@ -265,8 +417,10 @@ func FixtureConfig() (*Config, func()) {
 // NewConfig provides a new empty Config object
 func NewConfig() *Config {
 	c := &Config{
-		API:          &APIConfig{},
-		StatsdServer: &StatsdServerConfig{},
+		API:              &APIConfig{},
+		EventLog:         &EventLogConfig{},
+		PrometheusScrape: &PrometheusScrapeConfig{},
+		StatsdServer:     &StatsdServerConfig{},
 	}
 	return c
 }
@@ -30,8 +30,8 @@ func (a *Agent) getLocalEntityConfig() *corev3.EntityConfig {
 	}
 
 	meta := corev2.NewObjectMeta(a.config.AgentName, a.config.Namespace)
-	meta.Labels = a.config.Labels
-	meta.Annotations = a.config.Annotations
+	meta.Labels = a.entityLabels()
+	meta.Annotations = a.entityAnnotations()
 	e := &corev3.EntityConfig{
 		EntityClass:       corev2.EntityAgentClass,
 		Deregister:        a.config.Deregister,
@@ -57,8 +57,8 @@ func (a *Agent) getLocalEntityConfig() *corev3.EntityConfig {
 
 func (a *Agent) getEntityState() *corev3.EntityState {
 	meta := corev2.NewObjectMeta(a.config.AgentName, a.config.Namespace)
-	meta.Labels = a.config.Labels
-	meta.Annotations = a.config.Annotations
+	meta.Labels = a.entityLabels()
+	meta.Annotations = a.entityAnnotations()
 	return &corev3.EntityState{
 		Metadata:          &meta,
 		SensuAgentVersion: version.Semver(),
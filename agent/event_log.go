@@ -0,0 +1,236 @@
+package agent
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	time "github.com/echlebek/timeproxy"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/transport"
+)
+
+const (
+	// DefaultEventLogQuery is the default XPath query of the subscriptions to
+	// the Windows Event Log channels, which selects all the events.
+	DefaultEventLogQuery = "*"
+
+	// The labels of the checks of the Windows Event Log events.
+	eventLogChannelLabel  = "event_log_channel"
+	eventLogProviderLabel = "event_log_provider"
+	eventLogEventIDLabel  = "event_log_event_id"
+	eventLogLevelLabel    = "event_log_level"
+
+	// eventLogRecordIDAnnotation is the annotation of the checks of the Windows
+	// Event Log events holding the ID of their record.
+	eventLogRecordIDAnnotation = "event_log_record_id"
+)
+
+// ErrEventLogUnsupported is returned when the Windows Event Log is subscribed
+// to on another platform.
+var ErrEventLogUnsupported = errors.New("the event log is only supported on Windows")
+
+// eventLogLevels are the names of the standard levels of the Windows Event Log
+// events, the level 0 being logged regardless of the level.
+var eventLogLevels = map[uint8]string{
+	0: "information",
+	1: "critical",
+	2: "error",
+	3: "warning",
+	4: "information",
+	5: "verbose",
+}
+
+// DefaultEventLogSeverities returns the default check status of each level of
+// the Windows Event Log events.
+func DefaultEventLogSeverities() map[string]uint32 {
+	return map[string]uint32{
+		"critical":    2,
+		"error":       2,
+		"warning":     1,
+		"information": 0,
+		"verbose":     0,
+	}
+}
+
+// ParseEventLogSeverities parses a list of level=status pairs, which override
+// the default check status of the levels of the Windows Event Log events.
+func ParseEventLogSeverities(pairs []string) (map[string]uint32, error) {
+	severities := DefaultEventLogSeverities()
+	for _, pair := range pairs {
+		level, status, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("event log severity must be level=status: %s", pair)
+		}
+		level = strings.ToLower(strings.TrimSpace(level))
+		if _, ok := severities[level]; !ok {
+			return nil, fmt.Errorf("unknown event log level: %s", level)
+		}
+		s, err := strconv.ParseUint(strings.TrimSpace(status), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event log severity status: %s", status)
+		}
+		severities[level] = uint32(s)
+	}
+	return severities, nil
+}
+
+// eventLogRecord is an event of the Windows Event Log.
+type eventLogRecord struct {
+	Channel  string
+	Provider string
+	EventID  uint32
+	Level    uint8
+	RecordID uint64
+	Computer string
+	Time     time.Time
+	Message  string
+}
+
+// eventLogXML is the XML rendering of a Windows Event Log event.
+type eventLogXML struct {
+	System struct {
+		Provider struct {
+			Name string `xml:"Name,attr"`
+		} `xml:"Provider"`
+		EventID     uint32 `xml:"EventID"`
+		Level       uint8  `xml:"Level"`
+		TimeCreated struct {
+			SystemTime string `xml:"SystemTime,attr"`
+		} `xml:"TimeCreated"`
+		EventRecordID uint64 `xml:"EventRecordID"`
+		Channel       string `xml:"Channel"`
+		Computer      string `xml:"Computer"`
+	} `xml:"System"`
+	EventData struct {
+		Data []struct {
+			Name  string `xml:"Name,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"Data"`
+	} `xml:"EventData"`
+}
+
+// parseEventLogXML parses the XML rendering of a Windows Event Log event. Its
+// message is made of its data, since the XML rendering has no message.
+func parseEventLogXML(s string) (eventLogRecord, error) {
+	var doc eventLogXML
+	if err := xml.Unmarshal([]byte(s), &doc); err != nil {
+		return eventLogRecord{}, fmt.Errorf("invalid event log event: %s", err)
+	}
+	record := eventLogRecord{
+		Channel:  doc.System.Channel,
+		Provider: doc.System.Provider.Name,
+		EventID:  doc.System.EventID,
+		Level:    doc.System.Level,
+		RecordID: doc.System.EventRecordID,
+		Computer: doc.System.Computer,
+		Time:     time.Now(),
+	}
+	if t, err := time.Parse(time.RFC3339Nano, doc.System.TimeCreated.SystemTime); err == nil {
+		record.Time = t
+	}
+
+	data := make([]string, 0, len(doc.EventData.Data))
+	for _, d := range doc.EventData.Data {
+		if d.Name != "" {
+			data = append(data, d.Name+"="+d.Value)
+		} else {
+			data = append(data, d.Value)
+		}
+	}
+	record.Message = strings.Join(data, " ")
+	return record, nil
+}
+
+// eventLogCheckName returns the name of the check of the events of a provider
+// in a channel.
+func eventLogCheckName(channel, provider string) string {
+	return "event_log_" + strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, channel+"_"+provider)
+}
+
+// StartEventLog subscribes to each of the Windows Event Log channels configured
+// on the agent, and sends an event for each of their events until the context
+// is done.
+func (a *Agent) StartEventLog(ctx context.Context) {
+	config := a.config.EventLog
+	query := config.Query
+	if query == "" {
+		query = DefaultEventLogQuery
+	}
+	for _, channel := range config.Channels {
+		logger.Info("subscribing to event log channel: ", channel)
+		a.wg.Add(1)
+		go func(channel string) {
+			defer a.wg.Done()
+			err := subscribeEventLog(ctx, channel, query, func(record eventLogRecord) {
+				a.sendEventLogEvent(a.eventLogEvent(record))
+			})
+			if err != nil {
+				logger.WithError(err).Errorf("could not subscribe to event log channel %q", channel)
+			}
+		}(channel)
+	}
+}
+
+// eventLogEvent returns the event of a Windows Event Log event, whose check
+// status is the severity of its level.
+func (a *Agent) eventLogEvent(record eventLogRecord) *corev2.Event {
+	config := a.config.EventLog
+	severities := config.Severities
+	if severities == nil {
+		severities = DefaultEventLogSeverities()
+	}
+	level, ok := eventLogLevels[record.Level]
+	if !ok {
+		level = strconv.Itoa(int(record.Level))
+	}
+
+	check := corev2.NewCheck(&corev2.CheckConfig{
+		ObjectMeta: corev2.ObjectMeta{
+			Name:      eventLogCheckName(record.Channel, record.Provider),
+			Namespace: a.config.Namespace,
+			Labels: map[string]string{
+				eventLogChannelLabel:  record.Channel,
+				eventLogProviderLabel: record.Provider,
+				eventLogEventIDLabel:  strconv.FormatUint(uint64(record.EventID), 10),
+				eventLogLevelLabel:    level,
+			},
+			Annotations: map[string]string{
+				eventLogRecordIDAnnotation: strconv.FormatUint(record.RecordID, 10),
+			},
+		},
+		Handlers: config.Handlers,
+	})
+	check.Issued = record.Time.Unix()
+	check.Executed = record.Time.Unix()
+	check.Output = record.Message
+	// The levels without a severity, like the custom ones, are unknown
+	check.Status = 3
+	if status, ok := severities[level]; ok {
+		check.Status = status
+	}
+
+	return &corev2.Event{
+		Entity:    a.getAgentEntity(),
+		Check:     check,
+		Timestamp: time.Now().Unix(),
+	}
+}
+
+func (a *Agent) sendEventLogEvent(event *corev2.Event) {
+	msg, err := a.marshal(event)
+	if err != nil {
+		logger.WithError(err).Error("error marshaling event log event")
+		return
+	}
+	logger.WithField("check", event.Check.Name).Debug("sending event log event")
+	a.sendMessage(transport.NewMessage(transport.MessageTypeEvent, msg))
+}
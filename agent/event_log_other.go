@@ -0,0 +1,14 @@
+//go:build !windows
+// +build !windows
+
+package agent
+
+import (
+	"context"
+)
+
+// subscribeEventLog always returns ErrEventLogUnsupported, the Windows Event
+// Log being only available on Windows.
+func subscribeEventLog(ctx context.Context, channel, query string, handle func(eventLogRecord)) error {
+	return ErrEventLogUnsupported
+}
@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const eventLogXMLFixture = `<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event">
+  <System>
+    <Provider Name="Service Control Manager" Guid="{555908d1-a6d7-4695-8e1e-26931d2012f4}"/>
+    <EventID Qualifiers="49152">7031</EventID>
+    <Level>2</Level>
+    <TimeCreated SystemTime="2022-11-08T10:15:30.1234567Z"/>
+    <EventRecordID>48213</EventRecordID>
+    <Channel>System</Channel>
+    <Computer>web-01</Computer>
+  </System>
+  <EventData>
+    <Data Name="param1">Sensu Agent</Data>
+    <Data Name="param2">1</Data>
+  </EventData>
+</Event>`
+
+func TestParseEventLogXML(t *testing.T) {
+	record, err := parseEventLogXML(eventLogXMLFixture)
+	require.NoError(t, err)
+	assert.Equal(t, "System", record.Channel)
+	assert.Equal(t, "Service Control Manager", record.Provider)
+	assert.Equal(t, uint32(7031), record.EventID)
+	assert.Equal(t, uint8(2), record.Level)
+	assert.Equal(t, uint64(48213), record.RecordID)
+	assert.Equal(t, "web-01", record.Computer)
+	assert.Equal(t, int64(1667902530), record.Time.Unix())
+	assert.Equal(t, "param1=Sensu Agent param2=1", record.Message)
+
+	_, err = parseEventLogXML("<Event>")
+	assert.Error(t, err)
+}
+
+func TestParseEventLogSeverities(t *testing.T) {
+	severities, err := ParseEventLogSeverities([]string{"Warning=2", "verbose = 1"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint32{
+		"critical":    2,
+		"error":       2,
+		"warning":     2,
+		"information": 0,
+		"verbose":     1,
+	}, severities)
+
+	_, err = ParseEventLogSeverities([]string{"warning"})
+	assert.Error(t, err)
+	_, err = ParseEventLogSeverities([]string{"debug=1"})
+	assert.Error(t, err)
+	_, err = ParseEventLogSeverities([]string{"warning=-1"})
+	assert.Error(t, err)
+}
+
+func TestEventLogEvent(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	config.EventLog.Handlers = []string{"slack"}
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+
+	record, err := parseEventLogXML(eventLogXMLFixture)
+	require.NoError(t, err)
+	event := agent.eventLogEvent(record)
+	require.NoError(t, event.Check.Validate())
+	assert.Equal(t, "event_log_System_Service_Control_Manager", event.Check.Name)
+	assert.Equal(t, uint32(2), event.Check.Status)
+	assert.Equal(t, "param1=Sensu Agent param2=1", event.Check.Output)
+	assert.Equal(t, int64(1667902530), event.Check.Executed)
+	assert.Equal(t, []string{"slack"}, event.Check.Handlers)
+	assert.Equal(t, "7031", event.Check.Labels[eventLogEventIDLabel])
+	assert.Equal(t, "error", event.Check.Labels[eventLogLevelLabel])
+	assert.Equal(t, "48213", event.Check.Annotations[eventLogRecordIDAnnotation])
+
+	// The configured severities override the default ones
+	agent.config.EventLog.Severities = map[string]uint32{"error": 1}
+	assert.Equal(t, uint32(1), agent.eventLogEvent(record).Check.Status)
+
+	// The levels without a severity are unknown
+	record.Level = 16
+	event = agent.eventLogEvent(record)
+	assert.Equal(t, uint32(3), event.Check.Status)
+	assert.Equal(t, "16", event.Check.Labels[eventLogLevelLabel])
+}
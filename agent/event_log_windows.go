@@ -0,0 +1,172 @@
+//go:build windows
+// +build windows
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	evtSubscribeToFutureEvents = 1
+	evtRenderEventXML          = 1
+	evtFormatMessageEvent      = 1
+
+	// eventLogBatchSize is the maximum number of events read at once from a
+	// subscription.
+	eventLogBatchSize = 16
+
+	// eventLogWaitTimeout is the maximum time, in milliseconds, to wait for new
+	// events before checking whether the context is done.
+	eventLogWaitTimeout = 1000
+)
+
+var (
+	modwevtapi = windows.NewLazySystemDLL("wevtapi.dll")
+
+	procEvtSubscribe             = modwevtapi.NewProc("EvtSubscribe")
+	procEvtNext                  = modwevtapi.NewProc("EvtNext")
+	procEvtRender                = modwevtapi.NewProc("EvtRender")
+	procEvtOpenPublisherMetadata = modwevtapi.NewProc("EvtOpenPublisherMetadata")
+	procEvtFormatMessage         = modwevtapi.NewProc("EvtFormatMessage")
+	procEvtClose                 = modwevtapi.NewProc("EvtClose")
+)
+
+// subscribeEventLog subscribes to the future events of a Windows Event Log
+// channel matching the XPath query, and handles them until the context is
+// done.
+func subscribeEventLog(ctx context.Context, channel, query string, handle func(eventLogRecord)) error {
+	signal, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(signal)
+
+	channelPtr, err := windows.UTF16PtrFromString(channel)
+	if err != nil {
+		return err
+	}
+	queryPtr, err := windows.UTF16PtrFromString(query)
+	if err != nil {
+		return err
+	}
+	subscription, _, err := procEvtSubscribe.Call(
+		0,
+		uintptr(signal),
+		uintptr(unsafe.Pointer(channelPtr)),
+		uintptr(unsafe.Pointer(queryPtr)),
+		0,
+		0,
+		0,
+		evtSubscribeToFutureEvents,
+	)
+	if subscription == 0 {
+		return err
+	}
+	defer evtClose(subscription)
+
+	events := make([]uintptr, eventLogBatchSize)
+	for {
+		var returned uint32
+		r, _, err := procEvtNext.Call(
+			subscription,
+			eventLogBatchSize,
+			uintptr(unsafe.Pointer(&events[0])),
+			0,
+			0,
+			uintptr(unsafe.Pointer(&returned)),
+		)
+		if r == 0 {
+			if !errors.Is(err, windows.ERROR_NO_MORE_ITEMS) {
+				return err
+			}
+			// Wait for the next events to be signaled
+			_ = windows.ResetEvent(signal)
+			for {
+				if ctx.Err() != nil {
+					return nil
+				}
+				event, err := windows.WaitForSingleObject(signal, eventLogWaitTimeout)
+				if err != nil {
+					return err
+				}
+				if event == windows.WAIT_OBJECT_0 {
+					break
+				}
+			}
+			continue
+		}
+
+		for _, event := range events[:returned] {
+			record, err := renderEventLogEvent(event)
+			evtClose(event)
+			if err != nil {
+				logger.WithError(err).Errorf("could not render event of event log channel %q", channel)
+				continue
+			}
+			if record.Channel == "" {
+				record.Channel = channel
+			}
+			handle(record)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// renderEventLogEvent renders an event to XML, and formats its message with the
+// metadata of its provider, if available.
+func renderEventLogEvent(event uintptr) (eventLogRecord, error) {
+	var used, count uint32
+	r, _, err := procEvtRender.Call(0, event, evtRenderEventXML, 0, 0, uintptr(unsafe.Pointer(&used)), uintptr(unsafe.Pointer(&count)))
+	if r == 0 && !errors.Is(err, windows.ERROR_INSUFFICIENT_BUFFER) {
+		return eventLogRecord{}, err
+	}
+	buf := make([]uint16, used/2+1)
+	r, _, err = procEvtRender.Call(0, event, evtRenderEventXML, uintptr(len(buf)*2), uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&used)), uintptr(unsafe.Pointer(&count)))
+	if r == 0 {
+		return eventLogRecord{}, err
+	}
+
+	record, err := parseEventLogXML(windows.UTF16ToString(buf))
+	if err != nil {
+		return record, err
+	}
+	if message, err := formatEventLogMessage(record.Provider, event); err == nil && message != "" {
+		record.Message = message
+	}
+	return record, nil
+}
+
+func formatEventLogMessage(provider string, event uintptr) (string, error) {
+	providerPtr, err := windows.UTF16PtrFromString(provider)
+	if err != nil {
+		return "", err
+	}
+	metadata, _, err := procEvtOpenPublisherMetadata.Call(0, uintptr(unsafe.Pointer(providerPtr)), 0, 0, 0)
+	if metadata == 0 {
+		return "", err
+	}
+	defer evtClose(metadata)
+
+	var used uint32
+	r, _, err := procEvtFormatMessage.Call(metadata, event, 0, 0, 0, evtFormatMessageEvent, 0, 0, uintptr(unsafe.Pointer(&used)))
+	if r == 0 && !errors.Is(err, windows.ERROR_INSUFFICIENT_BUFFER) {
+		return "", err
+	}
+	buf := make([]uint16, used+1)
+	r, _, err = procEvtFormatMessage.Call(metadata, event, 0, 0, 0, evtFormatMessageEvent, uintptr(len(buf)), uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&used)))
+	if r == 0 {
+		return "", err
+	}
+	return windows.UTF16ToString(buf), nil
+}
+
+func evtClose(handle uintptr) {
+	_, _, _ = procEvtClose.Call(handle)
+}
@@ -18,6 +18,12 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// HookMaxOutputSizeAnnotation is the hook annotation that holds the maximum
+// number of bytes of hook output attached to the event, or 0 for no limit. It
+// overrides the hook-max-output-size agent option. Output beyond the limit is
+// discarded, and the attached output ends with command.TruncatedOutputMarker.
+const HookMaxOutputSizeAnnotation = "sensu.io/max-output-size"
+
 // ExecuteHooks executes all hooks contained in a check request based on
 // the check status code of the check request
 func (a *Agent) ExecuteHooks(ctx context.Context, request *corev2.CheckRequest, event *corev2.Event, assets map[string]*corev2.AssetList) []*corev2.Hook {
@@ -69,6 +75,7 @@ func (a *Agent) executeHook(ctx context.Context, hookConfig *corev2.HookConfig,
 	hook := &corev2.Hook{
 		HookConfig: *hookConfig,
 		Executed:   time.Now().Unix(),
+		Issued:     event.Check.Issued,
 	}
 
 	// Prepare log entry
@@ -78,6 +85,12 @@ func (a *Agent) executeHook(ctx context.Context, hookConfig *corev2.HookConfig,
 		"assets":    hook.RuntimeAssets,
 	}
 
+	// Verify the signature of the hook
+	if err := a.verifySignature(hookConfig.ObjectMeta, hookConfig.Command, hookConfig.RuntimeAssets); err != nil {
+		logger.WithFields(fields).WithError(err).Error("hook signature verification failed")
+		return failedHook(hook)
+	}
+
 	// Match check against allow list
 	var matchedEntry allowList
 	var match bool
@@ -99,11 +112,16 @@ func (a *Agent) executeHook(ctx context.Context, hookConfig *corev2.HookConfig,
 			assetList = value.Assets
 		}
 	}
+	if err := matchedEntry.verifyAssets(assetList); err != nil {
+		logger.WithFields(fields).WithError(err).Error("hook assets do not match agent allow list")
+		return failedHook(hook)
+	}
 	assets, err := asset.GetAll(ctx, a.assetGetter, assetList)
 	if err != nil {
 		logger.WithError(err).WithFields(fields).Error("error getting assets for hook")
 		return failedHook(hook)
 	}
+	a.recordAssets(assets)
 
 	// Prepare environment
 	env := environment.MergeEnvironments(os.Environ(), assets.Env())
@@ -128,22 +146,34 @@ func (a *Agent) executeHook(ctx context.Context, hookConfig *corev2.HookConfig,
 		}
 	}
 
+	// The hooks without a timeout use the one of the agent, if any
+	timeout := hookConfig.Timeout
+	if timeout == 0 {
+		timeout = a.config.HookTimeout
+	}
+
+	// Validated by prepareHook
+	maxOutputSize, _ := a.hookMaxOutputSize(hookConfig)
+
 	// Instantiate the execution command
 	ex := command.ExecutionRequest{
-		Command:      hookConfig.Command,
-		Timeout:      int(hookConfig.Timeout),
-		InProgress:   a.inProgress,
-		InProgressMu: a.inProgressMu,
-		Name:         event.Check.ObjectMeta.Name,
-		Env:          env,
+		Command:       hookConfig.Command,
+		Timeout:       int(timeout),
+		InProgress:    a.inProgress,
+		InProgressMu:  a.inProgressMu,
+		Name:          event.Check.ObjectMeta.Name,
+		Env:           env,
+		MaxOutputSize: maxOutputSize,
 	}
 
 	// If stdin is true, add JSON event data to command execution.
 	if hookConfig.Stdin {
 		input, err := json.Marshal(event)
 		if err != nil {
-			a.sendFailure(event, fmt.Errorf("error marshaling json from event: %s", err))
-			return nil
+			logger.WithFields(fields).WithError(err).Error("error marshaling json from event")
+			hook.Output = fmt.Sprintf("error marshaling json from event: %s", err)
+			hook.Status = 3
+			return hook
 		}
 		ex.Input = string(input)
 	}
@@ -151,16 +181,34 @@ func (a *Agent) executeHook(ctx context.Context, hookConfig *corev2.HookConfig,
 	hookExec, err := a.executor.Execute(context.Background(), ex)
 	if err != nil {
 		hook.Output = err.Error()
-	} else {
-		hook.Output = hookExec.Output
+		hook.Status = 3
+		if hookExec != nil {
+			hook.Duration = hookExec.Duration
+		}
+		return hook
 	}
 
+	hook.Output = hookExec.Output
 	hook.Duration = hookExec.Duration
 	hook.Status = int32(hookExec.Status)
 
 	return hook
 }
 
+// hookMaxOutputSize returns the maximum number of bytes of output attached to
+// the event for the hook.
+func (a *Agent) hookMaxOutputSize(hookConfig *corev2.HookConfig) (int, error) {
+	value, ok := hookConfig.Annotations[HookMaxOutputSizeAnnotation]
+	if !ok {
+		return a.config.HookMaxOutputSize, nil
+	}
+	size, err := strconv.Atoi(value)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("invalid %s annotation: %q", HookMaxOutputSizeAnnotation, value)
+	}
+	return size, nil
+}
+
 func (a *Agent) prepareHook(hookConfig *corev2.HookConfig) error {
 	if hookConfig == nil {
 		return errors.New("nil hook config")
@@ -175,6 +223,10 @@ func (a *Agent) prepareHook(hookConfig *corev2.HookConfig) error {
 		return fmt.Errorf("hook %q: error doing token substitution: %s", hookConfig.Name, err)
 	}
 
+	if _, err := a.hookMaxOutputSize(hookConfig); err != nil {
+		return fmt.Errorf("hook %q: %s", hookConfig.Name, err)
+	}
+
 	return nil
 }
 
@@ -56,6 +56,60 @@ func TestExecuteHook(t *testing.T) {
 	assert.Equal("hello", hook.Output)
 }
 
+func TestExecuteHookTimeoutAndMaxOutputSize(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	config.HookTimeout = 30
+	config.HookMaxOutputSize = 1024
+	agent, err := NewAgent(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ex := &mockexecutor.MockExecutor{}
+	agent.executor = ex
+	ex.Return(command.FixtureExecutionResponse(0, "hello"), nil)
+	var request command.ExecutionRequest
+	ex.SetRequestFunc(func(ctx context.Context, r command.ExecutionRequest) {
+		request = r
+	})
+
+	evt := corev2.FixtureEvent("entity", "check")
+	evt.Check.Issued = 42
+
+	// The hook without a timeout nor annotation uses the ones of the agent
+	hookConfig := corev2.FixtureHookConfig("hook")
+	hookConfig.Timeout = 0
+	hook := agent.executeHook(context.Background(), hookConfig, evt, nil)
+	assert.Equal(t, 30, request.Timeout)
+	assert.Equal(t, 1024, request.MaxOutputSize)
+	assert.Equal(t, int64(42), hook.Issued)
+	assert.Equal(t, "hello", hook.Output)
+
+	// The hook configuration takes precedence
+	hookConfig.Timeout = 5
+	hookConfig.Annotations = map[string]string{HookMaxOutputSizeAnnotation: "0"}
+	agent.executeHook(context.Background(), hookConfig, evt, nil)
+	assert.Equal(t, 5, request.Timeout)
+	assert.Equal(t, 0, request.MaxOutputSize)
+}
+
+func TestExecuteHookError(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	agent, err := NewAgent(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ex := &mockexecutor.MockExecutor{}
+	agent.executor = ex
+	ex.Return(nil, errors.New("command failed"))
+
+	evt := corev2.FixtureEvent("entity", "check")
+	hook := agent.executeHook(context.Background(), corev2.FixtureHookConfig("hook"), evt, nil)
+	assert.Equal(t, int32(3), hook.Status)
+	assert.Equal(t, "command failed", hook.Output)
+}
+
 func TestExecuteHooks_GH3779(t *testing.T) {
 	cfg, cleanup := FixtureConfig()
 	defer cleanup()
@@ -110,6 +164,12 @@ func TestPrepareHook(t *testing.T) {
 	if err := agent.prepareHook(hook); err != nil {
 		t.Error(err)
 	}
+
+	// Invalid max output size
+	hook.Annotations = map[string]string{HookMaxOutputSizeAnnotation: "-1"}
+	if err := agent.prepareHook(hook); err == nil {
+		t.Error("expected non-nil error")
+	}
 }
 
 func TestHookInList(t *testing.T) {
@@ -0,0 +1,131 @@
+package agent
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/asset"
+)
+
+// loadedAsset describes an asset installed by the agent, in the responses of
+// the local API.
+type loadedAsset struct {
+	Name   string `json:"name"`
+	SHA512 string `json:"sha512"`
+	Path   string `json:"path"`
+}
+
+// checkResult is the last execution result of a check, in the responses of
+// the local API.
+type checkResult struct {
+	Name            string  `json:"name"`
+	ProxyEntityName string  `json:"proxy_entity_name,omitempty"`
+	Issued          int64   `json:"issued"`
+	Executed        int64   `json:"executed"`
+	Duration        float64 `json:"duration"`
+	Status          uint32  `json:"status"`
+	Output          string  `json:"output"`
+}
+
+func (a *Agent) recordAssets(assets asset.RuntimeAssetSet) {
+	a.inspectMu.Lock()
+	defer a.inspectMu.Unlock()
+	if a.loadedAssets == nil {
+		a.loadedAssets = map[string]loadedAsset{}
+	}
+	for _, asset := range assets {
+		a.loadedAssets[asset.SHA512] = loadedAsset{
+			Name:   asset.Name,
+			SHA512: asset.SHA512,
+			Path:   asset.Path,
+		}
+	}
+}
+
+func (a *Agent) recordCheckResult(check *corev2.Check) {
+	result := checkResult{
+		Name:            check.Name,
+		ProxyEntityName: check.ProxyEntityName,
+		Issued:          check.Issued,
+		Executed:        check.Executed,
+		Duration:        check.Duration,
+		Status:          check.Status,
+		Output:          check.Output,
+	}
+	key := check.Name
+	if check.ProxyEntityName != "" {
+		key += "/" + check.ProxyEntityName
+	}
+
+	a.inspectMu.Lock()
+	defer a.inspectMu.Unlock()
+	if a.checkResults == nil {
+		a.checkResults = map[string]checkResult{}
+	}
+	a.checkResults[key] = result
+}
+
+// listAssets returns the assets loaded by the agent since it started.
+func listAssets(a *Agent) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		a.inspectMu.Lock()
+		assets := make([]loadedAsset, 0, len(a.loadedAssets))
+		for _, asset := range a.loadedAssets {
+			assets = append(assets, asset)
+		}
+		a.inspectMu.Unlock()
+
+		sort.Slice(assets, func(i, j int) bool {
+			if assets[i].Name == assets[j].Name {
+				return assets[i].SHA512 < assets[j].SHA512
+			}
+			return assets[i].Name < assets[j].Name
+		})
+		writeJSON(w, assets)
+	}
+}
+
+// listChecks returns the last execution result of each check executed by the
+// agent since it started.
+func listChecks(a *Agent) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		a.inspectMu.Lock()
+		results := make([]checkResult, 0, len(a.checkResults))
+		for _, result := range a.checkResults {
+			results = append(results, result)
+		}
+		a.inspectMu.Unlock()
+
+		sort.Slice(results, func(i, j int) bool {
+			if results[i].Name == results[j].Name {
+				return results[i].ProxyEntityName < results[j].ProxyEntityName
+			}
+			return results[i].Name < results[j].Name
+		})
+		writeJSON(w, results)
+	}
+}
+
+// listSubscriptions returns the subscriptions of the agent's entity, which
+// are the ones of the backend entity unless the agent manages its entity.
+func listSubscriptions(a *Agent) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		subscriptions := a.getAgentEntity().Subscriptions
+		if subscriptions == nil {
+			subscriptions = []string{}
+		}
+		writeJSON(w, subscriptions)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
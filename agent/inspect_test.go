@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/asset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveInspect(t *testing.T, agent *Agent, path string) string {
+	t.Helper()
+	r, err := http.NewRequest(http.MethodGet, path, nil)
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	registerRoutes(agent, router)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	return w.Body.String()
+}
+
+func TestListAssets(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `[]`, serveInspect(t, agent, "/assets"))
+
+	agent.recordAssets(asset.RuntimeAssetSet{
+		{Name: "sensu-ruby-runtime", SHA512: "def", Path: "/cache/def"},
+		{Name: "check-disk", SHA512: "abc", Path: "/cache/abc"},
+	})
+	agent.recordAssets(asset.RuntimeAssetSet{
+		{Name: "check-disk", SHA512: "abc", Path: "/cache/abc"},
+	})
+	assert.JSONEq(t, `[
+		{"name":"check-disk","sha512":"abc","path":"/cache/abc"},
+		{"name":"sensu-ruby-runtime","sha512":"def","path":"/cache/def"}
+	]`, serveInspect(t, agent, "/assets"))
+}
+
+func TestListChecks(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `[]`, serveInspect(t, agent, "/checks"))
+
+	newCheck := func(proxyEntityName string, executed int64, status uint32, output string) *corev2.Check {
+		check := corev2.FixtureCheck("check-disk")
+		check.ProxyEntityName = proxyEntityName
+		check.Issued = 40
+		check.Executed = executed
+		check.Duration = 1.5
+		check.Status = status
+		check.Output = output
+		return check
+	}
+	agent.recordCheckResult(newCheck("", 41, 1, "warning"))
+	agent.recordCheckResult(newCheck("", 42, 0, "ok"))
+	agent.recordCheckResult(newCheck("router", 43, 2, "critical"))
+
+	assert.JSONEq(t, `[
+		{"name":"check-disk","issued":40,"executed":42,"duration":1.5,"status":0,"output":"ok"},
+		{"name":"check-disk","proxy_entity_name":"router","issued":40,"executed":43,"duration":1.5,"status":2,"output":"critical"}
+	]`, serveInspect(t, agent, "/checks"))
+}
+
+func TestListSubscriptions(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	config.Subscriptions = []string{"linux", "web"}
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `["linux","web"]`, serveInspect(t, agent, "/subscriptions"))
+}
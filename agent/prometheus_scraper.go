@@ -0,0 +1,171 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	time "github.com/echlebek/timeproxy"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// prometheusScrapeAccept is the exposition format requested to the
+	// exporters, the text format being the only one the agent can parse.
+	prometheusScrapeAccept = "text/plain;version=0.0.4;q=1,*/*;q=0.1"
+
+	// prometheusScrapeMaxSize is the maximum size of the scraped metrics.
+	prometheusScrapeMaxSize = 10 << 20
+
+	// prometheusScrapeInstanceTag is the tag added to the scraped samples,
+	// which identifies the exporter like the instance label of Prometheus.
+	prometheusScrapeInstanceTag = "instance"
+)
+
+// validatePrometheusScrapeTarget returns an error if target is not the URL of
+// a Prometheus exporter.
+func validatePrometheusScrapeTarget(target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("bad prometheus scrape target (%s): %s", target, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("prometheus scrape target (%s) must have http:// or https:// scheme", target)
+	}
+	return nil
+}
+
+// prometheusScrapeCheckName returns the name of the check of the events of a
+// scrape target, which is unique per exporter.
+func prometheusScrapeCheckName(target *url.URL) string {
+	host := strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, target.Host)
+	return "prometheus_scrape_" + host
+}
+
+// StartPrometheusScraper scrapes each of the Prometheus exporters configured on
+// the agent on an interval, until the context is done.
+func (a *Agent) StartPrometheusScraper(ctx context.Context) {
+	config := a.config.PrometheusScrape
+	interval := time.Duration(config.Interval) * time.Second
+	if interval <= 0 {
+		interval = time.Duration(DefaultPrometheusScrapeInterval) * time.Second
+	}
+	client := &http.Client{Timeout: time.Duration(config.Timeout) * time.Second}
+
+	for _, target := range config.Targets {
+		u, err := url.Parse(target)
+		if err != nil {
+			logger.WithError(err).Errorf("invalid prometheus scrape target %q", target)
+			continue
+		}
+		logger.Info("scraping prometheus exporter: ", target)
+		a.wg.Add(1)
+		go func(u *url.URL) {
+			defer a.wg.Done()
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				a.sendPrometheusScrape(a.scrapePrometheus(ctx, client, u, interval))
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(u)
+	}
+}
+
+// scrapePrometheus scrapes a Prometheus exporter, and returns the event of the
+// scrape. Its metrics are the samples of the exporter, and its check is
+// critical when the exporter could not be scraped.
+func (a *Agent) scrapePrometheus(ctx context.Context, client *http.Client, target *url.URL, interval time.Duration) *corev2.Event {
+	config := a.config.PrometheusScrape
+	check := corev2.NewCheck(&corev2.CheckConfig{
+		ObjectMeta: corev2.ObjectMeta{
+			Name:      prometheusScrapeCheckName(target),
+			Namespace: a.config.Namespace,
+		},
+		Interval:             uint32(interval / time.Second),
+		OutputMetricFormat:   corev2.PrometheusOutputMetricFormat,
+		OutputMetricHandlers: config.Handlers,
+		OutputMetricTags: []*corev2.MetricTag{
+			{Name: prometheusScrapeInstanceTag, Value: target.Host},
+		},
+	})
+	start := time.Now()
+	check.Issued = start.Unix()
+	check.Executed = start.Unix()
+
+	output, err := a.fetchPrometheusMetrics(ctx, client, target)
+	check.Duration = time.Since(start).Seconds()
+	event := &corev2.Event{
+		Entity:    a.getAgentEntity(),
+		Check:     check,
+		Timestamp: time.Now().Unix(),
+	}
+	if err != nil {
+		check.Status = 2
+		check.Output = err.Error()
+		return event
+	}
+
+	check.Output = output
+	event.Metrics = &corev2.Metrics{
+		Points:   extractMetrics(event),
+		Handlers: config.Handlers,
+	}
+	// The samples are already in the metrics of the event, there is no need
+	// to store the exposition of the exporter along with its check
+	check.Output = ""
+	return event
+}
+
+func (a *Agent) fetchPrometheusMetrics(ctx context.Context, client *http.Client, target *url.URL) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", prometheusScrapeAccept)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("could not scrape prometheus exporter: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("could not scrape prometheus exporter: %s", resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, prometheusScrapeMaxSize))
+	if err != nil {
+		return "", fmt.Errorf("could not read prometheus metrics: %s", err)
+	}
+	return string(b), nil
+}
+
+func (a *Agent) sendPrometheusScrape(event *corev2.Event) {
+	msg, err := a.marshal(event)
+	if err != nil {
+		logger.WithError(err).Error("error marshaling prometheus scrape event")
+		return
+	}
+
+	fields := logrus.Fields{
+		"check":  event.Check.Name,
+		"entity": event.Entity.Name,
+	}
+	if event.HasMetrics() {
+		fields["metrics"] = len(event.Metrics.Points)
+	}
+	logger.WithFields(fields).Debug("sending prometheus scrape")
+	a.sendMessage(transport.NewMessage(transport.MessageTypeEvent, msg))
+}
@@ -0,0 +1,76 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	time "github.com/echlebek/timeproxy"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const prometheusExposition = `# HELP node_load1 1m load average.
+# TYPE node_load1 gauge
+node_load1 0.42
+# HELP node_cpu_seconds_total Seconds the CPUs spent in each mode.
+# TYPE node_cpu_seconds_total counter
+node_cpu_seconds_total{cpu="0",mode="idle"} 1234.5
+`
+
+func TestScrapePrometheus(t *testing.T) {
+	cfg, cleanup := FixtureConfig()
+	defer cleanup()
+	cfg.PrometheusScrape.Handlers = []string{"influxdb"}
+	a, err := NewAgent(cfg)
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Contains(t, r.Header.Get("Accept"), "text/plain")
+		_, _ = w.Write([]byte(prometheusExposition))
+	}))
+	defer ts.Close()
+	target, err := url.Parse(ts.URL + "/metrics")
+	require.NoError(t, err)
+
+	event := a.scrapePrometheus(context.Background(), ts.Client(), target, time.Minute)
+	require.NoError(t, event.Validate())
+	assert.Equal(t, uint32(0), event.Check.Status)
+	assert.Equal(t, uint32(60), event.Check.Interval)
+	assert.Equal(t, corev2.PrometheusOutputMetricFormat, event.Check.OutputMetricFormat)
+	assert.Empty(t, event.Check.Output)
+	require.True(t, event.HasMetrics())
+	assert.Equal(t, []string{"influxdb"}, event.Metrics.Handlers)
+	require.Len(t, event.Metrics.Points, 2)
+	for _, point := range event.Metrics.Points {
+		assert.Contains(t, point.Tags, &corev2.MetricTag{Name: prometheusScrapeInstanceTag, Value: target.Host})
+	}
+
+	target.Path = "/missing"
+	event = a.scrapePrometheus(context.Background(), ts.Client(), target, time.Minute)
+	assert.Equal(t, uint32(2), event.Check.Status)
+	assert.Contains(t, event.Check.Output, "404")
+	assert.False(t, event.HasMetrics())
+}
+
+func TestPrometheusScrapeCheckName(t *testing.T) {
+	target, err := url.Parse("http://[::1]:9100/metrics")
+	require.NoError(t, err)
+	name := prometheusScrapeCheckName(target)
+	assert.Equal(t, "prometheus_scrape____1__9100", name)
+	assert.NoError(t, corev2.ValidateName(name))
+}
+
+func TestValidatePrometheusScrapeTarget(t *testing.T) {
+	assert.NoError(t, validatePrometheusScrapeTarget("http://localhost:9100/metrics"))
+	assert.NoError(t, validatePrometheusScrapeTarget("https://localhost:9100/metrics"))
+	assert.Error(t, validatePrometheusScrapeTarget("ws://localhost:9100/metrics"))
+	assert.Error(t, validatePrometheusScrapeTarget("://"))
+}
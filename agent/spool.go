@@ -0,0 +1,391 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	time "github.com/echlebek/timeproxy"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	EventsSpooled        = "sensu_go_agent_events_spooled"
+	EventsSpoolDiscarded = "sensu_go_agent_events_spool_discarded"
+)
+
+var (
+	eventsSpooled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: EventsSpooled,
+			Help: "The total number of events written to the spool while disconnected from sensu-backend",
+		},
+		[]string{},
+	)
+
+	eventsSpoolDiscarded = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: EventsSpoolDiscarded,
+			Help: "The total number of spooled events discarded because of the size or age limits of the spool, or because they were corrupted",
+		},
+		[]string{},
+	)
+)
+
+func init() {
+	_ = prometheus.Register(eventsSpooled)
+	_ = prometheus.Register(eventsSpoolDiscarded)
+}
+
+const (
+	// spoolSegmentExt is the extension of the segment files of the spool.
+	spoolSegmentExt = ".spool"
+
+	// spoolMinSegmentSize is the minimum size of the segments of the spool,
+	// which is otherwise divided into spoolSegments segments.
+	spoolMinSegmentSize = 4096
+	spoolSegments       = 8
+
+	// spoolHeaderSize is the size of the header of the spooled records:
+	// magic (2 bytes), content type (1 byte), reserved (1 byte), payload
+	// length (4 bytes), timestamp in nanoseconds (8 bytes) and CRC-32 of the
+	// previous fields and the payload (4 bytes).
+	spoolHeaderSize = 20
+
+	spoolMagic = 0x5350
+
+	spoolContentTypeJSON     = 0
+	spoolContentTypeProtobuf = 1
+)
+
+// errSpoolRecordTooLarge is returned when a record cannot fit in the spool.
+var errSpoolRecordTooLarge = errors.New("event is larger than the spool")
+
+// spoolRecord is an event spooled while the agent was disconnected from the
+// backend, serialized with the content type of the connection at the time.
+type spoolRecord struct {
+	ContentType string
+	Timestamp   time.Time
+	Payload     []byte
+}
+
+// eventSpool is an on-disk FIFO queue of events. The records are appended to
+// segment files, which are removed once their records were replayed or when
+// the spool exceeds its maximum size, oldest first. Each record is framed with
+// its length and checksum, so that a truncated or corrupted record is skipped
+// without losing the following ones.
+type eventSpool struct {
+	dir         string
+	maxSize     int64
+	maxAge      time.Duration
+	segmentSize int64
+
+	mu       sync.Mutex
+	segments []*spoolSegment
+	size     int64
+	active   *os.File
+}
+
+type spoolSegment struct {
+	id   uint64
+	size int64
+	// replayed is the offset of the first record that was not replayed
+	replayed int64
+}
+
+func (s *spoolSegment) path(dir string) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", s.id, spoolSegmentExt))
+}
+
+// openEventSpool opens the spool stored in dir, creating it if needed. The
+// spool keeps at most maxSize bytes of events, and discards the events older
+// than maxAge when replayed, unless maxAge is 0.
+func openEventSpool(dir string, maxSize int64, maxAge time.Duration) (*eventSpool, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("could not create directory for event spool (%s): %s", dir, err)
+	}
+	segmentSize := maxSize / spoolSegments
+	if segmentSize < spoolMinSegmentSize {
+		segmentSize = spoolMinSegmentSize
+	}
+	s := &eventSpool{
+		dir:         dir,
+		maxSize:     maxSize,
+		maxAge:      maxAge,
+		segmentSize: segmentSize,
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read event spool (%s): %s", dir, err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, spoolSegmentExt) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, spoolSegmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("could not read event spool (%s): %s", dir, err)
+		}
+		s.segments = append(s.segments, &spoolSegment{id: id, size: info.Size()})
+		s.size += info.Size()
+	}
+	sort.Slice(s.segments, func(i, j int) bool {
+		return s.segments[i].id < s.segments[j].id
+	})
+	s.enforceMaxSize(0)
+	return s, nil
+}
+
+// Size returns the size of the records of the spool, in bytes.
+func (s *eventSpool) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// Write appends a record to the spool. The oldest segments are discarded when
+// the spool would exceed its maximum size.
+func (s *eventSpool) Write(record spoolRecord) error {
+	frame := encodeSpoolRecord(record)
+	if int64(len(frame)) > s.maxSize {
+		return errSpoolRecordTooLarge
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last := s.lastSegment()
+	if s.active == nil || last == nil || last.size+int64(len(frame)) > s.segmentSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+		last = s.lastSegment()
+	}
+	s.enforceMaxSize(int64(len(frame)))
+
+	n, err := s.active.Write(frame)
+	last.size += int64(n)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("could not write to event spool: %s", err)
+	}
+	eventsSpooled.WithLabelValues().Inc()
+	return nil
+}
+
+// Replay calls fn with the spooled records, oldest first, and removes them
+// from the spool once fn succeeded. It stops at the first error of fn, which
+// is returned, and the following records are kept for the next replay. The
+// records older than the maximum age of the spool are discarded.
+func (s *eventSpool) Replay(fn func(spoolRecord) error) error {
+	for {
+		s.mu.Lock()
+		// Close the active segment, so that the segments replayed are not
+		// written concurrently. The records written in the meantime are
+		// replayed by the next iteration.
+		if s.active != nil {
+			if err := s.active.Close(); err != nil {
+				logger.WithError(err).Warning("error closing event spool segment")
+			}
+			s.active = nil
+		}
+		segments := append([]*spoolSegment(nil), s.segments...)
+		s.mu.Unlock()
+
+		if len(segments) == 0 {
+			return nil
+		}
+		for _, segment := range segments {
+			if err := s.replaySegment(segment, fn); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *eventSpool) replaySegment(segment *spoolSegment, fn func(spoolRecord) error) error {
+	s.mu.Lock()
+	offset := segment.replayed
+	s.mu.Unlock()
+
+	b, err := os.ReadFile(segment.path(s.dir))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not read event spool segment: %s", err)
+	}
+	now := time.Now()
+	for offset < int64(len(b)) {
+		record, next, err := decodeSpoolRecord(b, offset)
+		if err != nil {
+			logger.WithError(err).Warning("skipping corrupted event spool record")
+			eventsSpoolDiscarded.WithLabelValues().Inc()
+		} else if s.maxAge > 0 && now.Sub(record.Timestamp) > s.maxAge {
+			eventsSpoolDiscarded.WithLabelValues().Inc()
+		} else if err := fn(record); err != nil {
+			s.mu.Lock()
+			segment.replayed = offset
+			s.mu.Unlock()
+			return err
+		}
+		offset = next
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeSegment(segment)
+	return nil
+}
+
+// Close closes the spool. The spooled records are kept.
+func (s *eventSpool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active == nil {
+		return nil
+	}
+	err := s.active.Close()
+	s.active = nil
+	return err
+}
+
+func (s *eventSpool) lastSegment() *spoolSegment {
+	if len(s.segments) == 0 {
+		return nil
+	}
+	return s.segments[len(s.segments)-1]
+}
+
+// rotate starts a new segment. It must be called with the lock held.
+func (s *eventSpool) rotate() error {
+	if s.active != nil {
+		if err := s.active.Close(); err != nil {
+			logger.WithError(err).Warning("error closing event spool segment")
+		}
+		s.active = nil
+	}
+	segment := &spoolSegment{}
+	if last := s.lastSegment(); last != nil {
+		segment.id = last.id + 1
+	}
+	f, err := os.OpenFile(segment.path(s.dir), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("could not create event spool segment: %s", err)
+	}
+	s.active = f
+	s.segments = append(s.segments, segment)
+	return nil
+}
+
+// enforceMaxSize removes the oldest segments until n more bytes fit in the
+// spool, keeping the active segment. It must be called with the lock held.
+func (s *eventSpool) enforceMaxSize(n int64) {
+	for s.size+n > s.maxSize && len(s.segments) > 1 {
+		oldest := s.segments[0]
+		logger.WithField("size", oldest.size).Warning("event spool is full, discarding its oldest events")
+		// The number of events discarded is unknown without reading them, so
+		// count the segment once
+		eventsSpoolDiscarded.WithLabelValues().Inc()
+		s.removeSegment(oldest)
+	}
+}
+
+// removeSegment removes a segment from the spool. It must be called with the
+// lock held.
+func (s *eventSpool) removeSegment(segment *spoolSegment) {
+	for i, seg := range s.segments {
+		if seg != segment {
+			continue
+		}
+		s.segments = append(s.segments[:i], s.segments[i+1:]...)
+		s.size -= segment.size
+		if err := os.Remove(segment.path(s.dir)); err != nil && !os.IsNotExist(err) {
+			logger.WithError(err).Warning("error removing event spool segment")
+		}
+		return
+	}
+}
+
+func encodeSpoolRecord(record spoolRecord) []byte {
+	frame := make([]byte, spoolHeaderSize+len(record.Payload))
+	binary.BigEndian.PutUint16(frame[0:2], spoolMagic)
+	if record.ContentType == ProtobufSerializationHeader {
+		frame[2] = spoolContentTypeProtobuf
+	} else {
+		frame[2] = spoolContentTypeJSON
+	}
+	binary.BigEndian.PutUint32(frame[4:8], uint32(len(record.Payload)))
+	binary.BigEndian.PutUint64(frame[8:16], uint64(record.Timestamp.UnixNano()))
+	copy(frame[spoolHeaderSize:], record.Payload)
+	crc := crc32.NewIEEE()
+	_, _ = crc.Write(frame[0:16])
+	_, _ = crc.Write(record.Payload)
+	binary.BigEndian.PutUint32(frame[16:20], crc.Sum32())
+	return frame
+}
+
+// decodeSpoolRecord decodes the record at the given offset of a segment, and
+// returns the offset of the next record. If the record is corrupted, the
+// offset returned is the one of the next valid record, if any, or the end of
+// the segment.
+func decodeSpoolRecord(b []byte, offset int64) (spoolRecord, int64, error) {
+	record, size, err := decodeSpoolFrame(b[offset:])
+	if err == nil {
+		return record, offset + size, nil
+	}
+	// Resynchronize on the next valid frame
+	magic := []byte{spoolMagic >> 8, spoolMagic & 0xff}
+	for next := offset + 1; next < int64(len(b)); next++ {
+		i := bytes.Index(b[next:], magic)
+		if i < 0 {
+			break
+		}
+		next += int64(i)
+		if _, _, ferr := decodeSpoolFrame(b[next:]); ferr == nil {
+			return spoolRecord{}, next, err
+		}
+	}
+	return spoolRecord{}, int64(len(b)), err
+}
+
+func decodeSpoolFrame(b []byte) (spoolRecord, int64, error) {
+	if len(b) < spoolHeaderSize {
+		return spoolRecord{}, 0, io.ErrUnexpectedEOF
+	}
+	if binary.BigEndian.Uint16(b[0:2]) != spoolMagic {
+		return spoolRecord{}, 0, errors.New("invalid record header")
+	}
+	length := int64(binary.BigEndian.Uint32(b[4:8]))
+	if int64(len(b)) < spoolHeaderSize+length {
+		return spoolRecord{}, 0, io.ErrUnexpectedEOF
+	}
+	payload := b[spoolHeaderSize : spoolHeaderSize+length]
+	crc := crc32.NewIEEE()
+	_, _ = crc.Write(b[0:16])
+	_, _ = crc.Write(payload)
+	if crc.Sum32() != binary.BigEndian.Uint32(b[16:20]) {
+		return spoolRecord{}, 0, errors.New("invalid record checksum")
+	}
+	record := spoolRecord{
+		ContentType: JSONSerializationHeader,
+		Timestamp:   time.Unix(0, int64(binary.BigEndian.Uint64(b[8:16]))),
+		Payload:     payload,
+	}
+	if b[2] == spoolContentTypeProtobuf {
+		record.ContentType = ProtobufSerializationHeader
+	}
+	return record, spoolHeaderSize + length, nil
+}
@@ -0,0 +1,165 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	time "github.com/echlebek/timeproxy"
+	"github.com/gogo/protobuf/proto"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/testing/mocktransport"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSpoolRecords(t *testing.T, spool *eventSpool, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		require.NoError(t, spool.Write(spoolRecord{
+			ContentType: ProtobufSerializationHeader,
+			Timestamp:   time.Now(),
+			Payload:     []byte(fmt.Sprintf("event-%03d", i)),
+		}))
+	}
+}
+
+func replaySpoolPayloads(t *testing.T, spool *eventSpool) []string {
+	t.Helper()
+	var payloads []string
+	require.NoError(t, spool.Replay(func(record spoolRecord) error {
+		assert.Equal(t, ProtobufSerializationHeader, record.ContentType)
+		payloads = append(payloads, string(record.Payload))
+		return nil
+	}))
+	return payloads
+}
+
+func TestEventSpoolReplayInOrder(t *testing.T) {
+	dir := t.TempDir()
+	spool, err := openEventSpool(dir, 1<<20, 0)
+	require.NoError(t, err)
+	writeSpoolRecords(t, spool, 10)
+	require.NoError(t, spool.Close())
+
+	// The records persist across restarts
+	spool, err = openEventSpool(dir, 1<<20, 0)
+	require.NoError(t, err)
+	writeSpoolRecords(t, spool, 2)
+
+	payloads := replaySpoolPayloads(t, spool)
+	require.Len(t, payloads, 12)
+	assert.Equal(t, "event-000", payloads[0])
+	assert.Equal(t, "event-009", payloads[9])
+	assert.Equal(t, "event-001", payloads[11])
+	assert.Equal(t, int64(0), spool.Size())
+	assert.Empty(t, replaySpoolPayloads(t, spool))
+}
+
+func TestEventSpoolReplayResumes(t *testing.T) {
+	spool, err := openEventSpool(t.TempDir(), 1<<20, 0)
+	require.NoError(t, err)
+	writeSpoolRecords(t, spool, 5)
+
+	var sent []string
+	errSend := errors.New("connection lost")
+	err = spool.Replay(func(record spoolRecord) error {
+		if len(sent) == 3 {
+			return errSend
+		}
+		sent = append(sent, string(record.Payload))
+		return nil
+	})
+	assert.Equal(t, errSend, err)
+
+	assert.Equal(t, []string{"event-003", "event-004"}, replaySpoolPayloads(t, spool))
+}
+
+func TestEventSpoolMaxSize(t *testing.T) {
+	// Each record takes 29 bytes, and the segments are 4096 bytes
+	spool, err := openEventSpool(t.TempDir(), 3*spoolMinSegmentSize, 0)
+	require.NoError(t, err)
+	writeSpoolRecords(t, spool, 1000)
+	assert.LessOrEqual(t, spool.Size(), int64(3*spoolMinSegmentSize))
+
+	payloads := replaySpoolPayloads(t, spool)
+	require.NotEmpty(t, payloads)
+	assert.Less(t, len(payloads), 1000)
+	// The oldest events are discarded first
+	assert.Equal(t, "event-999", payloads[len(payloads)-1])
+
+	err = spool.Write(spoolRecord{Payload: make([]byte, 3*spoolMinSegmentSize)})
+	assert.Equal(t, errSpoolRecordTooLarge, err)
+}
+
+func TestEventSpoolMaxAge(t *testing.T) {
+	spool, err := openEventSpool(t.TempDir(), 1<<20, time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, spool.Write(spoolRecord{Timestamp: time.Now().Add(-time.Hour), Payload: []byte("old")}))
+	require.NoError(t, spool.Write(spoolRecord{Timestamp: time.Now(), Payload: []byte("new")}))
+
+	var payloads []string
+	require.NoError(t, spool.Replay(func(record spoolRecord) error {
+		payloads = append(payloads, string(record.Payload))
+		return nil
+	}))
+	assert.Equal(t, []string{"new"}, payloads)
+}
+
+func TestEventSpoolCorruption(t *testing.T) {
+	dir := t.TempDir()
+	spool, err := openEventSpool(dir, 1<<20, 0)
+	require.NoError(t, err)
+	writeSpoolRecords(t, spool, 3)
+	require.NoError(t, spool.Close())
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+spoolSegmentExt))
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	b, err := os.ReadFile(matches[0])
+	require.NoError(t, err)
+	recordSize := len(b) / 3
+
+	// Corrupt the payload of the second record, and truncate a fourth record
+	// as if the agent crashed while writing it
+	b[recordSize+spoolHeaderSize] ^= 0xff
+	b = append(b, encodeSpoolRecord(spoolRecord{Payload: []byte("truncated")})[:spoolHeaderSize+2]...)
+	require.NoError(t, os.WriteFile(matches[0], b, 0600))
+
+	spool, err = openEventSpool(dir, 1<<20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"event-000", "event-002"}, replaySpoolPayloads(t, spool))
+}
+
+func TestAgentSpoolsEventsWhileDisconnected(t *testing.T) {
+	cfg, cleanup := FixtureConfig()
+	defer cleanup()
+	cfg.EventSpoolMaxSize = 1 << 20
+	a, err := NewAgent(cfg)
+	require.NoError(t, err)
+
+	event := corev2.FixtureEvent("entity", "check")
+	payload, err := a.marshal(event)
+	require.NoError(t, err)
+	a.sendMessage(transport.NewMessage(transport.MessageTypeEvent, payload))
+	assert.Empty(t, a.sendq)
+	assert.NotZero(t, a.spool.Size())
+
+	// The event is converted to the content type of the connection
+	a.contentType = ProtobufSerializationHeader
+	a.marshal = proto.Marshal
+	conn := &mocktransport.MockTransport{}
+	conn.On("Send", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		msg := args.Get(0).(*transport.Message)
+		var got corev2.Event
+		require.NoError(t, proto.Unmarshal(msg.Payload, &got))
+		assert.Equal(t, event.Check.Name, got.Check.Name)
+	}).Once()
+	require.NoError(t, a.replaySpool(conn))
+	conn.AssertExpectations(t)
+	assert.Zero(t, a.spool.Size())
+}
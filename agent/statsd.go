@@ -3,6 +3,9 @@ package agent
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 )
 
 // ErrStatsdUnsupported is returned when statsd can't be supported on the platform.
@@ -15,3 +18,17 @@ var StatsdUnsupported = ErrStatsdUnsupported
 type StatsdServer interface {
 	Run(context.Context) error
 }
+
+// ParseStatsdPercentiles parses the percentiles computed for the statsd timers,
+// histograms and distributions, which must be greater than 0 and at most 100.
+func ParseStatsdPercentiles(values []string) ([]float64, error) {
+	percentiles := make([]float64, 0, len(values))
+	for _, value := range values {
+		percentile, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || percentile <= 0 || percentile > 100 {
+			return nil, fmt.Errorf("invalid percentile: %s", value)
+		}
+		percentiles = append(percentiles, percentile)
+	}
+	return percentiles, nil
+}
@@ -0,0 +1,71 @@
+//go:build !solaris
+// +build !solaris
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"net"
+
+	"github.com/atlassian/gostatsd/pkg/statsd"
+)
+
+// statsdServer is the statsd server of the agent. On top of the metric types
+// supported by gostatsd, it accepts the DogStatsD distributions, which are
+// aggregated like the histograms.
+type statsdServer struct {
+	*statsd.Server
+}
+
+// Run listens for metrics on the metrics address of the server until the
+// context is done.
+func (s statsdServer) Run(ctx context.Context) error {
+	conn, err := net.ListenPacket("udp", s.MetricsAddr)
+	if err != nil {
+		return err
+	}
+	return s.RunWithCustomSocket(ctx, func() (net.PacketConn, error) {
+		return distributionConn{PacketConn: conn}, nil
+	})
+}
+
+// distributionConn is a packet connection which rewrites the type of the
+// DogStatsD distributions it reads to the histogram type.
+type distributionConn struct {
+	net.PacketConn
+}
+
+// ReadFrom reads a datagram, and rewrites the type of its distributions.
+func (c distributionConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	n, addr, err := c.PacketConn.ReadFrom(b)
+	if n > 0 {
+		rewriteDistributions(b[:n])
+	}
+	return n, addr, err
+}
+
+// rewriteDistributions rewrites in place the type of the distributions of a
+// datagram, made of one metric per line, to the histogram type.
+func rewriteDistributions(datagram []byte) {
+	for len(datagram) > 0 {
+		line := datagram
+		if i := bytes.IndexByte(datagram, '\n'); i >= 0 {
+			line, datagram = datagram[:i], datagram[i+1:]
+		} else {
+			datagram = nil
+		}
+		// Skip the events and service checks
+		if len(line) == 0 || line[0] == '_' {
+			continue
+		}
+		// The type follows the value: name:value|type[|@rate][|#tags]
+		i := bytes.IndexByte(line, '|')
+		if i < 0 || i+1 >= len(line) {
+			continue
+		}
+		if line[i+1] == 'd' && (i+2 == len(line) || line[i+2] == '|') {
+			line[i+1] = 'h'
+		}
+	}
+}
@@ -24,15 +24,17 @@ import (
 
 // GetMetricsAddr gets the metrics address of the statsd server.
 func GetMetricsAddr(s StatsdServer) string {
-	server, ok := s.(*statsd.Server)
-	if !ok {
-		return ""
+	switch server := s.(type) {
+	case statsdServer:
+		return server.MetricsAddr
+	case *statsd.Server:
+		return server.MetricsAddr
 	}
-	return server.MetricsAddr
+	return ""
 }
 
 // NewStatsdServer provides a new statsd server for the sensu-agent.
-func NewStatsdServer(a *Agent) *statsd.Server {
+func NewStatsdServer(a *Agent) statsdServer {
 	c := a.config.StatsdServer
 	s := NewServer()
 	backend, err := NewClientFromViper(s.Viper, a)
@@ -47,7 +49,10 @@ func NewStatsdServer(a *Agent) *statsd.Server {
 	s.FlushInterval = time.Duration(c.FlushInterval) * time.Second
 	s.MetricsAddr = fmt.Sprintf("%s:%d", c.Host, c.Port)
 	s.StatserType = statsd.StatserNull
-	return s
+	if len(c.Percentiles) > 0 {
+		s.PercentThreshold = c.Percentiles
+	}
+	return statsdServer{Server: s}
 }
 
 // NewServer will create a new statsd Server with the default configuration.
@@ -181,16 +186,13 @@ func (c Client) sendMetrics(points []*v2.MetricPoint) (retErr error) {
 	return nil
 }
 
+// composeMetricTags maps the DogStatsD tags of a metric to metric tags. The
+// tags without a value are mapped to metric tags with an empty value.
 func composeMetricTags(tagsKey string) []*v2.MetricTag {
 	tagsKeys := strings.Split(tagsKey, ",")
 	var tags []*v2.MetricTag
-	var name, value string
 	for _, tag := range tagsKeys {
-		tagsValues := strings.Split(tag, ":")
-		if len(tagsValues) > 1 {
-			name = tagsValues[0]
-			value = tagsValues[1]
-		}
+		name, value, _ := strings.Cut(tag, ":")
 		if tag != "" {
 			t := &v2.MetricTag{
 				Name:	name,
@@ -32,6 +32,11 @@ func TestNewStatsdServer(t *testing.T) {
 	assert.Equal(t, BackendName, s.Backends[0].Name())
 	assert.Equal(t, 20*time.Second, s.FlushInterval)
 	assert.Equal(t, "foo:8126", GetMetricsAddr(s))
+	assert.Equal(t, []float64{90}, s.PercentThreshold)
+
+	c.StatsdServer.Percentiles = []float64{50, 99.9}
+	s = NewStatsdServer(a)
+	assert.Equal(t, []float64{50, 99.9}, s.PercentThreshold)
 }
 
 func TestComposeMetricTags(t *testing.T) {
@@ -56,6 +61,14 @@ func TestComposeMetricTags(t *testing.T) {
 				{Name: "aggregator_id", Value: "5"},
 			},
 		},
+		{
+			name:    "DogStatsD tags",
+			tagsKey: "env,url:http://localhost:8080",
+			metricTag: []*v2.MetricTag{
+				{Name: "env", Value: ""},
+				{Name: "url", Value: "http://localhost:8080"},
+			},
+		},
 		{
 			name:		"Empty tagsKey",
 			tagsKey:	"",
@@ -190,6 +203,68 @@ func TestReceiveMetrics(t *testing.T) {
 	}
 }
 
+func TestRewriteDistributions(t *testing.T) {
+	datagram := []byte("latency:12|d|#env:prod\nsize:3|d\ndone:1|c|#d\n_e{1,1}:d|d\nd:1|h")
+	rewriteDistributions(datagram)
+	assert.Equal(t, "latency:12|h|#env:prod\nsize:3|h\ndone:1|c|#d\n_e{1,1}:d|d\nd:1|h", string(datagram))
+}
+
+func TestParseStatsdPercentiles(t *testing.T) {
+	percentiles, err := ParseStatsdPercentiles([]string{"50", " 99.9"})
+	require.NoError(t, err)
+	assert.Equal(t, []float64{50, 99.9}, percentiles)
+
+	for _, value := range []string{"0", "101", "p99"} {
+		_, err := ParseStatsdPercentiles([]string{value})
+		assert.Error(t, err, value)
+	}
+}
+
+func TestReceiveDistribution(t *testing.T) {
+	cfg, cleanup := FixtureConfig()
+	defer cleanup()
+	cfg.StatsdServer.FlushInterval = 1
+	cfg.StatsdServer.Port = 0
+	cfg.StatsdServer.Percentiles = []float64{50}
+	ta, err := NewAgent(cfg)
+	require.NoError(t, err)
+
+	// Listen on a random port
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := ta.statsdServer.(statsdServer)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = server.RunWithCustomSocket(ctx, func() (net.PacketConn, error) {
+			return distributionConn{PacketConn: conn}, nil
+		})
+	}()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer client.Close()
+	_, err = client.Write([]byte("latency:10|d|#env:prod\nlatency:20|d|#env:prod"))
+	require.NoError(t, err)
+
+	select {
+	case msg := <-ta.sendq:
+		var event v2.Event
+		require.NoError(t, ta.unmarshal(msg.Payload, &event))
+		require.True(t, event.HasMetrics())
+		points := make(map[string]*v2.MetricPoint)
+		for _, point := range event.Metrics.Points {
+			points[point.Name] = point
+		}
+		require.Contains(t, points, "latency.count")
+		assert.Equal(t, 2.0, points["latency.count"].Value)
+		assert.Contains(t, points, "latency.percentile_upper_50")
+		assert.Contains(t, points["latency.count"].Tags, &v2.MetricTag{Name: "env", Value: "prod"})
+	case <-time.After(10 * time.Second):
+		t.Fatal("no statsd metrics received")
+	}
+}
+
 func FixtureCounter(now int64) gostatsd.Counter {
 	return gostatsd.Counter{
 		PerSecond:	2,
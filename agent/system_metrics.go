@@ -0,0 +1,29 @@
+package agent
+
+import (
+	"context"
+
+	time "github.com/echlebek/timeproxy"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/system"
+)
+
+// systemMetricsTimeout is the maximum time spent gathering the system metrics
+// sent with a keepalive.
+const systemMetricsTimeout = 5 * time.Second
+
+// systemMetrics returns the metrics of the snapshot of the system sent with a
+// keepalive, or nil if the snapshot could not be gathered, in which case the
+// keepalive is sent without metrics.
+func (a *Agent) systemMetrics(timestamp int64) *corev2.Metrics {
+	ctx, cancel := context.WithTimeout(context.Background(), systemMetricsTimeout)
+	defer cancel()
+	metrics, err := system.GetMetrics(ctx, timestamp)
+	if err != nil {
+		logger.WithError(err).Error("could not gather system metrics")
+		return nil
+	}
+	return &corev2.Metrics{
+		Points: metrics.Points(),
+	}
+}
@@ -0,0 +1,29 @@
+package agent
+
+import (
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeepaliveSystemMetrics(t *testing.T) {
+	config, cleanup := FixtureConfig()
+	defer cleanup()
+	agent, err := NewAgent(config)
+	require.NoError(t, err)
+
+	var keepalive corev2.Event
+	require.NoError(t, agent.unmarshal(agent.newKeepalive().Payload, &keepalive))
+	assert.False(t, keepalive.HasMetrics())
+
+	agent.config.KeepaliveSystemMetrics = true
+	require.NoError(t, agent.unmarshal(agent.newKeepalive().Payload, &keepalive))
+	require.True(t, keepalive.HasMetrics())
+	metrics, ok := system.MetricsFromPoints(keepalive.Metrics.Points)
+	require.True(t, ok)
+	assert.Equal(t, keepalive.Timestamp, metrics.Timestamp)
+	assert.Greater(t, metrics.MemoryUsedPercent, 0.0)
+}
@@ -0,0 +1,299 @@
+package transformers
+
+import (
+	"bufio"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	time "github.com/echlebek/timeproxy"
+	v2 "github.com/sensu/core/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// OpenMetricsOutputMetricFormat is the output metric format of the checks
+	// whose output is in the OpenMetrics text format.
+	OpenMetricsOutputMetricFormat = "openmetrics_text"
+
+	// PromUnitTagName is the tag of the unit of the OpenMetrics samples.
+	PromUnitTagName = "prom_unit"
+
+	// ExemplarTagPrefix prefixes the tags of the exemplar of an OpenMetrics
+	// sample: its labels, its value and its timestamp.
+	ExemplarTagPrefix = "exemplar_"
+)
+
+// openMetricsSuffixes are the suffixes of the samples of the OpenMetrics
+// families, which are stripped to find the family of a sample.
+var openMetricsSuffixes = []string{"_total", "_created", "_count", "_sum", "_bucket", "_gcount", "_gsum", "_info"}
+
+func init() {
+	// The format is not known by the check validation of sensu/core
+	v2.OutputMetricFormats = append(v2.OutputMetricFormats, OpenMetricsOutputMetricFormat)
+}
+
+// OpenMetricsList contains the samples of the OpenMetrics text format
+type OpenMetricsList []OpenMetricsSample
+
+// OpenMetricsSample is a sample of the OpenMetrics text format, whose tags
+// include the labels of its exemplar, if any.
+type OpenMetricsSample struct {
+	Name      string
+	Value     float64
+	Timestamp int64
+	Tags      []*v2.MetricTag
+}
+
+// openMetricsFamily is the metadata of an OpenMetrics family.
+type openMetricsFamily struct {
+	Type string
+	Help string
+	Unit string
+}
+
+// Transform transforms metrics in the OpenMetrics text format to the Sensu
+// Metric Format.
+func (o OpenMetricsList) Transform() []*v2.MetricPoint {
+	var points []*v2.MetricPoint
+	for _, sample := range o {
+		if math.IsNaN(sample.Value) {
+			continue
+		}
+		mp := &v2.MetricPoint{
+			Name:      sample.Name,
+			Value:     sample.Value,
+			Timestamp: sample.Timestamp,
+			Tags:      sample.Tags,
+		}
+		if mp.Tags == nil {
+			mp.Tags = []*v2.MetricTag{}
+		}
+		points = append(points, mp)
+	}
+	return points
+}
+
+// ParseOpenMetrics parses an OpenMetrics text formatted string into a list of
+// samples. The parsing stops at the # EOF marker.
+func ParseOpenMetrics(event *v2.Event) OpenMetricsList {
+	var list OpenMetricsList
+	fields := logrus.Fields{
+		"namespace": event.Check.Namespace,
+		"check":     event.Check.Name,
+	}
+
+	families := map[string]*openMetricsFamily{}
+	now := time.Now().Unix()
+	s := bufio.NewScanner(strings.NewReader(event.Check.Output))
+	l := 0
+	for s.Scan() {
+		line := s.Text()
+		fields["line"] = l
+		l++
+		if line == "# EOF" {
+			break
+		}
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			parseOpenMetricsDescriptor(line, families)
+			continue
+		}
+
+		sample, err := parseOpenMetricsSample(line, now)
+		if err != nil {
+			logger.WithFields(fields).WithError(ErrMetricExtraction).Error(err)
+			continue
+		}
+		if family := openMetricsFamilyOf(sample.Name, families); family != nil {
+			sample.Tags = append(sample.Tags, &v2.MetricTag{Name: PromTypeTagName, Value: family.Type})
+			if family.Help != "" {
+				sample.Tags = append(sample.Tags, &v2.MetricTag{Name: PromHelpTagName, Value: family.Help})
+			}
+			if family.Unit != "" {
+				sample.Tags = append(sample.Tags, &v2.MetricTag{Name: PromUnitTagName, Value: family.Unit})
+			}
+		} else {
+			sample.Tags = append(sample.Tags, &v2.MetricTag{Name: PromTypeTagName, Value: "unknown"})
+		}
+		sample.Tags = append(sample.Tags, event.Check.OutputMetricTags...)
+		list = append(list, sample)
+	}
+	if err := s.Err(); err != nil {
+		logger.WithFields(fields).WithError(ErrMetricExtraction).Error(err)
+	}
+
+	return list
+}
+
+// parseOpenMetricsDescriptor parses a TYPE, HELP or UNIT line into the
+// metadata of its family. The other comments are ignored.
+func parseOpenMetricsDescriptor(line string, families map[string]*openMetricsFamily) {
+	parts := strings.SplitN(line, " ", 4)
+	if len(parts) < 4 || parts[0] != "#" {
+		return
+	}
+	family, ok := families[parts[2]]
+	if !ok {
+		family = &openMetricsFamily{Type: "unknown"}
+		families[parts[2]] = family
+	}
+	switch parts[1] {
+	case "TYPE":
+		family.Type = parts[3]
+	case "HELP":
+		family.Help = unescapeOpenMetrics(parts[3])
+	case "UNIT":
+		family.Unit = parts[3]
+	}
+}
+
+// openMetricsFamilyOf returns the family of a sample, or nil if the sample
+// belongs to no declared family.
+func openMetricsFamilyOf(name string, families map[string]*openMetricsFamily) *openMetricsFamily {
+	if family, ok := families[name]; ok {
+		return family
+	}
+	for _, suffix := range openMetricsSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			if family, ok := families[strings.TrimSuffix(name, suffix)]; ok {
+				return family
+			}
+		}
+	}
+	return nil
+}
+
+// parseOpenMetricsSample parses a sample line, with its optional timestamp
+// and exemplar. The sample is timestamped with now if it has no timestamp.
+func parseOpenMetricsSample(line string, now int64) (OpenMetricsSample, error) {
+	sample := OpenMetricsSample{Timestamp: now}
+
+	end := strings.IndexAny(line, "{ ")
+	if end <= 0 {
+		return sample, errors.New("openmetrics sample requires a name and a value")
+	}
+	sample.Name = line[:end]
+	rest := line[end:]
+	if rest[0] == '{' {
+		labels, r, err := parseOpenMetricsLabels(rest)
+		if err != nil {
+			return sample, err
+		}
+		sample.Tags = labels
+		rest = r
+	}
+
+	rest, exemplar, hasExemplar := strings.Cut(rest, " # ")
+	values := strings.Fields(rest)
+	if len(values) == 0 || len(values) > 2 {
+		return sample, errors.New("openmetrics sample requires a value and an optional timestamp")
+	}
+	value, err := strconv.ParseFloat(values[0], 64)
+	if err != nil {
+		return sample, errors.New("openmetrics sample value is invalid: " + values[0])
+	}
+	sample.Value = value
+	if len(values) == 2 {
+		ts, err := strconv.ParseFloat(values[1], 64)
+		if err != nil {
+			return sample, errors.New("openmetrics sample timestamp is invalid: " + values[1])
+		}
+		sample.Timestamp = int64(ts)
+	}
+
+	if hasExemplar {
+		tags, err := parseOpenMetricsExemplar(exemplar)
+		if err != nil {
+			return sample, err
+		}
+		sample.Tags = append(sample.Tags, tags...)
+	}
+
+	return sample, nil
+}
+
+// parseOpenMetricsExemplar parses the exemplar of a sample into tags.
+func parseOpenMetricsExemplar(exemplar string) ([]*v2.MetricTag, error) {
+	if !strings.HasPrefix(exemplar, "{") {
+		return nil, errors.New("openmetrics exemplar requires labels")
+	}
+	labels, rest, err := parseOpenMetricsLabels(exemplar)
+	if err != nil {
+		return nil, err
+	}
+	values := strings.Fields(rest)
+	if len(values) == 0 || len(values) > 2 {
+		return nil, errors.New("openmetrics exemplar requires a value and an optional timestamp")
+	}
+
+	tags := make([]*v2.MetricTag, 0, len(labels)+2)
+	for _, label := range labels {
+		tags = append(tags, &v2.MetricTag{Name: ExemplarTagPrefix + label.Name, Value: label.Value})
+	}
+	if _, err := strconv.ParseFloat(values[0], 64); err != nil {
+		return nil, errors.New("openmetrics exemplar value is invalid: " + values[0])
+	}
+	tags = append(tags, &v2.MetricTag{Name: ExemplarTagPrefix + "value", Value: values[0]})
+	if len(values) == 2 {
+		if _, err := strconv.ParseFloat(values[1], 64); err != nil {
+			return nil, errors.New("openmetrics exemplar timestamp is invalid: " + values[1])
+		}
+		tags = append(tags, &v2.MetricTag{Name: ExemplarTagPrefix + "timestamp", Value: values[1]})
+	}
+	return tags, nil
+}
+
+// parseOpenMetricsLabels parses a set of labels, which s must start with, and
+// returns the rest of s.
+func parseOpenMetricsLabels(s string) ([]*v2.MetricTag, string, error) {
+	var labels []*v2.MetricTag
+	i := 1
+	for {
+		if i >= len(s) {
+			return nil, "", errors.New("openmetrics labels are not terminated")
+		}
+		if s[i] == '}' {
+			return labels, s[i+1:], nil
+		}
+
+		eq := strings.IndexByte(s[i:], '=')
+		if eq <= 0 || i+eq+1 >= len(s) || s[i+eq+1] != '"' {
+			return nil, "", errors.New("openmetrics label requires a name and a quoted value")
+		}
+		name := s[i : i+eq]
+		i += eq + 2
+
+		var value strings.Builder
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				switch s[i] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(s[i])
+				}
+				continue
+			}
+			value.WriteByte(s[i])
+		}
+		if i >= len(s) {
+			return nil, "", errors.New("openmetrics label value is not terminated")
+		}
+		labels = append(labels, &v2.MetricTag{Name: name, Value: value.String()})
+
+		i++
+		if i < len(s) && s[i] == ',' {
+			i++
+		}
+	}
+}
+
+// unescapeOpenMetrics unescapes the text of a HELP line.
+func unescapeOpenMetrics(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\"`, `"`).Replace(s)
+}
@@ -0,0 +1,109 @@
+package transformers
+
+import (
+	"testing"
+
+	time "github.com/echlebek/timeproxy"
+	v2 "github.com/sensu/core/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOpenMetrics(t *testing.T) {
+	ts := time.Now().Unix()
+
+	testCases := []struct {
+		name     string
+		metric   string
+		tags     []*v2.MetricTag
+		expected OpenMetricsList
+	}{
+		{
+			name:   "counter with exemplar",
+			metric: "# TYPE http_requests counter\n# HELP http_requests Requests served.\nhttp_requests_total{code=\"200\",path=\"/a\\\"b\"} 1027 1395066363.5 # {trace_id=\"KOO5S4vxi0o\"} 0.67 1395066363\n# EOF\n",
+			expected: OpenMetricsList{
+				{
+					Name:      "http_requests_total",
+					Value:     1027,
+					Timestamp: 1395066363,
+					Tags: []*v2.MetricTag{
+						{Name: "code", Value: "200"},
+						{Name: "path", Value: "/a\"b"},
+						{Name: "exemplar_trace_id", Value: "KOO5S4vxi0o"},
+						{Name: "exemplar_value", Value: "0.67"},
+						{Name: "exemplar_timestamp", Value: "1395066363"},
+						{Name: PromTypeTagName, Value: "counter"},
+						{Name: PromHelpTagName, Value: "Requests served."},
+					},
+				},
+			},
+		},
+		{
+			name:   "gauge with unit and check tags",
+			metric: "# TYPE temperature gauge\n# UNIT temperature celsius\ntemperature 21.5\n",
+			tags:   []*v2.MetricTag{{Name: "site", Value: "lab"}},
+			expected: OpenMetricsList{
+				{
+					Name:      "temperature",
+					Value:     21.5,
+					Timestamp: ts,
+					Tags: []*v2.MetricTag{
+						{Name: PromTypeTagName, Value: "gauge"},
+						{Name: PromUnitTagName, Value: "celsius"},
+						{Name: "site", Value: "lab"},
+					},
+				},
+			},
+		},
+		{
+			name:   "invalid lines are skipped and parsing stops at EOF",
+			metric: "up{job=\"a\" 1\nup 1\nup one\n# EOF\nup 0\n",
+			expected: OpenMetricsList{
+				{
+					Name:      "up",
+					Value:     1,
+					Timestamp: ts,
+					Tags: []*v2.MetricTag{
+						{Name: PromTypeTagName, Value: "unknown"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			event := v2.FixtureEvent("test", "test")
+			event.Check.Output = tc.metric
+			event.Check.OutputMetricTags = tc.tags
+			list := ParseOpenMetrics(event)
+			// The samples without timestamp are within a second of ts
+			for i := range list {
+				if list[i].Timestamp-ts == 1 {
+					list[i].Timestamp = ts
+				}
+			}
+			assert.Equal(t, tc.expected, list)
+		})
+	}
+}
+
+func TestOpenMetricsTransform(t *testing.T) {
+	event := v2.FixtureEvent("test", "test")
+	event.Check.Output = "# TYPE latency histogram\nlatency_bucket{le=\"+Inf\"} 3 1395066363\nlatency_sum NaN 1395066363\n# EOF\n"
+	points := ParseOpenMetrics(event).Transform()
+	assert.Equal(t, []*v2.MetricPoint{
+		{
+			Name:      "latency_bucket",
+			Value:     3,
+			Timestamp: 1395066363,
+			Tags: []*v2.MetricTag{
+				{Name: "le", Value: "+Inf"},
+				{Name: PromTypeTagName, Value: "histogram"},
+			},
+		},
+	}, points)
+}
+
+func TestOpenMetricsOutputMetricFormat(t *testing.T) {
+	assert.NoError(t, v2.ValidateOutputMetricFormat(OpenMetricsOutputMetricFormat))
+}
@@ -0,0 +1,201 @@
+package transformers
+
+import (
+	"math"
+	"strconv"
+
+	time "github.com/echlebek/timeproxy"
+	v2 "github.com/sensu/core/v2"
+	"github.com/sirupsen/logrus"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	metricsv1 "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// OTLPOutputMetricFormat is the output metric format of the checks whose
+	// output is an OTLP/JSON payload of metrics.
+	OTLPOutputMetricFormat = "otlp_json"
+
+	// OTLPTypeTagName is the tag of the type of the OTLP metrics.
+	OTLPTypeTagName = "otlp_type"
+
+	// OTLPUnitTagName is the tag of the unit of the OTLP metrics.
+	OTLPUnitTagName = "otlp_unit"
+)
+
+func init() {
+	// The format is not known by the check validation of sensu/core
+	v2.OutputMetricFormats = append(v2.OutputMetricFormats, OTLPOutputMetricFormat)
+}
+
+// OTLPList contains the points of an OTLP/JSON payload of metrics
+type OTLPList []OTLPPoint
+
+// OTLPPoint is a point of an OTLP metric. The histograms and summaries are
+// flattened into several points, like their Prometheus counterparts.
+type OTLPPoint struct {
+	Name      string
+	Value     float64
+	Timestamp int64
+	Tags      []*v2.MetricTag
+}
+
+// Transform transforms an OTLP/JSON payload of metrics to the Sensu Metric
+// Format.
+func (o OTLPList) Transform() []*v2.MetricPoint {
+	var points []*v2.MetricPoint
+	for _, point := range o {
+		if math.IsNaN(point.Value) {
+			continue
+		}
+		mp := &v2.MetricPoint{
+			Name:      point.Name,
+			Value:     point.Value,
+			Timestamp: point.Timestamp,
+			Tags:      point.Tags,
+		}
+		if mp.Tags == nil {
+			mp.Tags = []*v2.MetricTag{}
+		}
+		points = append(points, mp)
+	}
+	return points
+}
+
+// ParseOTLP parses an OTLP/JSON payload of metrics, as sent to the
+// /v1/metrics endpoint of an OTLP/HTTP collector, into a list of points.
+func ParseOTLP(event *v2.Event) OTLPList {
+	fields := logrus.Fields{
+		"namespace": event.Check.Namespace,
+		"check":     event.Check.Name,
+	}
+
+	var data metricsv1.MetricsData
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal([]byte(event.Check.Output), &data); err != nil {
+		logger.WithFields(fields).WithError(ErrMetricExtraction).Error(err)
+		return nil
+	}
+
+	p := otlpParser{now: time.Now().Unix(), checkTags: event.Check.OutputMetricTags}
+	for _, resourceMetrics := range data.GetResourceMetrics() {
+		resourceTags := otlpTags(resourceMetrics.GetResource().GetAttributes())
+		for _, scopeMetrics := range resourceMetrics.GetScopeMetrics() {
+			for _, metric := range scopeMetrics.GetMetrics() {
+				p.parseMetric(metric, resourceTags)
+			}
+		}
+	}
+	return p.list
+}
+
+type otlpParser struct {
+	list      OTLPList
+	now       int64
+	checkTags []*v2.MetricTag
+}
+
+func (p *otlpParser) parseMetric(metric *metricsv1.Metric, resourceTags []*v2.MetricTag) {
+	name := metric.GetName()
+	metricTags := func(typ string, attributes []*commonv1.KeyValue) []*v2.MetricTag {
+		tags := append([]*v2.MetricTag{}, resourceTags...)
+		tags = append(tags, otlpTags(attributes)...)
+		tags = append(tags, &v2.MetricTag{Name: OTLPTypeTagName, Value: typ})
+		if unit := metric.GetUnit(); unit != "" {
+			tags = append(tags, &v2.MetricTag{Name: OTLPUnitTagName, Value: unit})
+		}
+		return append(tags, p.checkTags...)
+	}
+
+	switch {
+	case metric.GetGauge() != nil:
+		for _, dp := range metric.GetGauge().GetDataPoints() {
+			p.add(name, otlpNumber(dp), dp.GetTimeUnixNano(), metricTags("gauge", dp.GetAttributes()))
+		}
+	case metric.GetSum() != nil:
+		for _, dp := range metric.GetSum().GetDataPoints() {
+			p.add(name, otlpNumber(dp), dp.GetTimeUnixNano(), metricTags("sum", dp.GetAttributes()))
+		}
+	case metric.GetHistogram() != nil:
+		for _, dp := range metric.GetHistogram().GetDataPoints() {
+			tags := metricTags("histogram", dp.GetAttributes())
+			ts := dp.GetTimeUnixNano()
+			p.add(name+"_count", float64(dp.GetCount()), ts, tags)
+			p.add(name+"_sum", dp.GetSum(), ts, tags)
+			// The OTLP buckets are not cumulative, unlike the Prometheus ones
+			var cumulative uint64
+			bounds := dp.GetExplicitBounds()
+			for i, count := range dp.GetBucketCounts() {
+				cumulative += count
+				le := "+Inf"
+				if i < len(bounds) {
+					le = strconv.FormatFloat(bounds[i], 'g', -1, 64)
+				}
+				bucketTags := append([]*v2.MetricTag{{Name: "le", Value: le}}, tags...)
+				p.add(name+"_bucket", float64(cumulative), ts, bucketTags)
+			}
+		}
+	case metric.GetExponentialHistogram() != nil:
+		for _, dp := range metric.GetExponentialHistogram().GetDataPoints() {
+			tags := metricTags("exponential_histogram", dp.GetAttributes())
+			p.add(name+"_count", float64(dp.GetCount()), dp.GetTimeUnixNano(), tags)
+			p.add(name+"_sum", dp.GetSum(), dp.GetTimeUnixNano(), tags)
+		}
+	case metric.GetSummary() != nil:
+		for _, dp := range metric.GetSummary().GetDataPoints() {
+			tags := metricTags("summary", dp.GetAttributes())
+			ts := dp.GetTimeUnixNano()
+			p.add(name+"_count", float64(dp.GetCount()), ts, tags)
+			p.add(name+"_sum", dp.GetSum(), ts, tags)
+			for _, q := range dp.GetQuantileValues() {
+				quantile := strconv.FormatFloat(q.GetQuantile(), 'g', -1, 64)
+				quantileTags := append([]*v2.MetricTag{{Name: "quantile", Value: quantile}}, tags...)
+				p.add(name, q.GetValue(), ts, quantileTags)
+			}
+		}
+	}
+}
+
+// add adds a point, timestamped with the time of the parsing if it has no
+// timestamp.
+func (p *otlpParser) add(name string, value float64, timeUnixNano uint64, tags []*v2.MetricTag) {
+	ts := p.now
+	if timeUnixNano > 0 {
+		ts = int64(timeUnixNano / uint64(time.Second))
+	}
+	p.list = append(p.list, OTLPPoint{
+		Name:      name,
+		Value:     value,
+		Timestamp: ts,
+		Tags:      tags,
+	})
+}
+
+func otlpNumber(dp *metricsv1.NumberDataPoint) float64 {
+	if v, ok := dp.GetValue().(*metricsv1.NumberDataPoint_AsInt); ok {
+		return float64(v.AsInt)
+	}
+	return dp.GetAsDouble()
+}
+
+// otlpTags converts the OTLP attributes with a scalar value to tags.
+func otlpTags(attributes []*commonv1.KeyValue) []*v2.MetricTag {
+	var tags []*v2.MetricTag
+	for _, attribute := range attributes {
+		var value string
+		switch v := attribute.GetValue().GetValue().(type) {
+		case *commonv1.AnyValue_StringValue:
+			value = v.StringValue
+		case *commonv1.AnyValue_BoolValue:
+			value = strconv.FormatBool(v.BoolValue)
+		case *commonv1.AnyValue_IntValue:
+			value = strconv.FormatInt(v.IntValue, 10)
+		case *commonv1.AnyValue_DoubleValue:
+			value = strconv.FormatFloat(v.DoubleValue, 'g', -1, 64)
+		default:
+			continue
+		}
+		tags = append(tags, &v2.MetricTag{Name: attribute.GetKey(), Value: value})
+	}
+	return tags
+}
@@ -0,0 +1,88 @@
+package transformers
+
+import (
+	"testing"
+
+	v2 "github.com/sensu/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const otlpPayload = `{
+  "resourceMetrics": [{
+    "resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "api"}}]},
+    "scopeMetrics": [{
+      "scope": {"name": "meter"},
+      "metrics": [
+        {
+          "name": "requests",
+          "unit": "1",
+          "sum": {
+            "aggregationTemporality": 2,
+            "isMonotonic": true,
+            "dataPoints": [{"asInt": "42", "timeUnixNano": "1395066363000000000", "attributes": [{"key": "code", "value": {"intValue": "200"}}]}]
+          }
+        },
+        {
+          "name": "temperature",
+          "gauge": {"dataPoints": [{"asDouble": 21.5, "timeUnixNano": "1395066363000000000"}]}
+        },
+        {
+          "name": "latency",
+          "histogram": {
+            "aggregationTemporality": 2,
+            "dataPoints": [{"count": "3", "sum": 0.6, "bucketCounts": ["1", "2"], "explicitBounds": [0.1], "timeUnixNano": "1395066363000000000"}]
+          }
+        }
+      ]
+    }]
+  }]
+}`
+
+func TestParseOTLP(t *testing.T) {
+	event := v2.FixtureEvent("test", "test")
+	event.Check.Output = otlpPayload
+	event.Check.OutputMetricTags = []*v2.MetricTag{{Name: "site", Value: "lab"}}
+
+	points := ParseOTLP(event).Transform()
+	require.Len(t, points, 6)
+
+	service := &v2.MetricTag{Name: "service.name", Value: "api"}
+	site := &v2.MetricTag{Name: "site", Value: "lab"}
+	assert.Equal(t, &v2.MetricPoint{
+		Name:      "requests",
+		Value:     42,
+		Timestamp: 1395066363,
+		Tags: []*v2.MetricTag{
+			service,
+			{Name: "code", Value: "200"},
+			{Name: OTLPTypeTagName, Value: "sum"},
+			{Name: OTLPUnitTagName, Value: "1"},
+			site,
+		},
+	}, points[0])
+	assert.Equal(t, "temperature", points[1].Name)
+	assert.Equal(t, 21.5, points[1].Value)
+
+	assert.Equal(t, "latency_count", points[2].Name)
+	assert.Equal(t, float64(3), points[2].Value)
+	assert.Equal(t, "latency_sum", points[3].Name)
+	assert.Equal(t, 0.6, points[3].Value)
+
+	// The buckets are cumulative
+	assert.Equal(t, "latency_bucket", points[4].Name)
+	assert.Equal(t, float64(1), points[4].Value)
+	assert.Equal(t, &v2.MetricTag{Name: "le", Value: "0.1"}, points[4].Tags[0])
+	assert.Equal(t, float64(3), points[5].Value)
+	assert.Equal(t, &v2.MetricTag{Name: "le", Value: "+Inf"}, points[5].Tags[0])
+}
+
+func TestParseOTLPInvalid(t *testing.T) {
+	event := v2.FixtureEvent("test", "test")
+	event.Check.Output = "not json"
+	assert.Empty(t, ParseOTLP(event))
+}
+
+func TestOTLPOutputMetricFormat(t *testing.T) {
+	assert.NoError(t, v2.ValidateOutputMetricFormat(OTLPOutputMetricFormat))
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/prometheus/client_golang/prometheus"
@@ -101,6 +102,20 @@ type boltDBAssetManager struct {
 	fetcher      Fetcher
 	expander     Expander
 	verifier     Verifier
+
+	// signatureVerifier verifies the signatures of the assets, if not nil.
+	signatureVerifier *SignatureVerifier
+
+	// usage records when the assets are used, for the cache GC, if not nil.
+	usage *assetUsage
+
+	// proxy is the asset proxy of the backend the assets are fetched through
+	// first, if not nil.
+	proxy *Proxy
+
+	// proxyFetcher fetches the assets from the proxy, with its TLS options.
+	// The fetcher is used if nil.
+	proxyFetcher Fetcher
 }
 
 // Get opens a transaction to BoltDB, causing subsequent calls to
@@ -116,6 +131,9 @@ type boltDBAssetManager struct {
 func (b *boltDBAssetManager) Get(ctx context.Context, asset *corev2.Asset) (*RuntimeAsset, error) {
 	key := []byte(asset.GetSha512())
 	var localAsset *RuntimeAsset
+	if b.usage != nil {
+		b.usage.touch(asset.GetSha512(), time.Now())
+	}
 
 	// Concurrent calls to View are allowed, but a concurrent call that has
 	// has proceeded to Update below will block here.
@@ -185,6 +203,11 @@ func (b *boltDBAssetManager) Get(ctx context.Context, asset *corev2.Asset) (*Run
 				asset.Name, humanize.Bytes(size), err,
 			)
 		}
+		if b.signatureVerifier != nil {
+			if err := b.signatureVerifier.Verify(tmpFile, asset); err != nil {
+				return fmt.Errorf("could not verify the signature of downloaded asset %q: %s", asset.Name, err)
+			}
+		}
 
 		// expand
 		assetPath, err := b.expandWithDuration(tmpFile, asset)
@@ -226,6 +249,24 @@ func (b *boltDBAssetManager) fetchWithDuration(ctx context.Context, asset *corev
 	}))
 	defer timer.ObserveDuration()
 
+	if b.proxy != nil {
+		fetcher := b.proxyFetcher
+		if fetcher == nil {
+			fetcher = b.fetcher
+		}
+		proxyURL, err := b.proxy.urlFor(asset)
+		if err == nil {
+			var file *os.File
+			if file, err = fetcher.Fetch(ctx, proxyURL, b.proxy.Headers); err == nil {
+				return file, nil
+			}
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		logger.WithField("asset", asset.Name).WithError(err).Warn("could not get asset from the asset proxy of the backend, fetching it from its URL")
+	}
+
 	return b.fetcher.Fetch(ctx, asset.URL, asset.Headers)
 }
 
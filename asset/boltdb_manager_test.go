@@ -287,3 +287,48 @@ func TestSuccessfulGetAsset(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestGetUnsignedAssetStrict(t *testing.T) {
+	t.Parallel()
+
+	tmpFile, err := ioutil.TempFile(os.TempDir(), "asset_test_get_unsigned_asset.db")
+	if err != nil {
+		t.Fatalf("unable to create test boltdb file: %v", err)
+	}
+	defer tmpFile.Close()
+	defer os.Remove(tmpFile.Name())
+
+	db, err := bolt.Open(tmpFile.Name(), 0666, &bolt.Options{})
+	if err != nil {
+		t.Fatalf("unable to open boltdb in test: %v", err)
+	}
+	defer db.Close()
+
+	manager := &boltDBAssetManager{
+		db:                db,
+		fetcher:           &mockFetcher{true},
+		verifier:          &mockVerifier{true},
+		expander:          &mockExpander{true},
+		signatureVerifier: &SignatureVerifier{strict: true},
+	}
+
+	a := &v2.Asset{
+		ObjectMeta: v2.ObjectMeta{
+			Name:      "asset",
+			Namespace: "default",
+		},
+		Sha512: "sha",
+		URL:    "path",
+	}
+
+	runtimeAsset, err := manager.Get(context.TODO(), a)
+	if runtimeAsset != nil {
+		t.Logf("expected nil runtime asset, got %v", runtimeAsset)
+		t.Fail()
+	}
+
+	if err == nil {
+		t.Log("expected error, got nil")
+		t.Fail()
+	}
+}
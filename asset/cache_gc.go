@@ -0,0 +1,218 @@
+package asset
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metricspkg "github.com/sensu/sensu-go/metrics"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// CacheSize is the name of the prometheus gauge of the size of the assets
+	// in the cache.
+	CacheSize = "sensu_go_asset_cache_size_bytes"
+
+	// CacheEvictions is the name of the prometheus counter vec of the assets
+	// evicted from the cache.
+	CacheEvictions = "sensu_go_asset_cache_evictions_total"
+
+	// CacheGCInterval is the interval at which the assets are evicted from
+	// the cache.
+	CacheGCInterval = 10 * time.Minute
+)
+
+var (
+	cacheSize = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: CacheSize,
+			Help: "size of the assets in the cache",
+		},
+	)
+
+	cacheEvictions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: CacheEvictions,
+			Help: "number of assets evicted from the cache",
+		},
+		[]string{"reason"},
+	)
+)
+
+func init() {
+	if err := prometheus.Register(cacheSize); err != nil {
+		panic(metricspkg.FormatRegistrationErr(CacheSize, err))
+	}
+	if err := prometheus.Register(cacheEvictions); err != nil {
+		panic(metricspkg.FormatRegistrationErr(CacheEvictions, err))
+	}
+}
+
+// assetUsage records when the assets were last used, by their SHA-512 sum.
+type assetUsage struct {
+	mu       sync.Mutex
+	lastUsed map[string]time.Time
+}
+
+func newAssetUsage() *assetUsage {
+	return &assetUsage{lastUsed: map[string]time.Time{}}
+}
+
+func (u *assetUsage) touch(sha string, t time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.lastUsed[sha] = t
+}
+
+// get returns when an asset was last used, or since if it was not used since.
+func (u *assetUsage) get(sha string, since time.Time) time.Time {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if t, ok := u.lastUsed[sha]; ok {
+		return t
+	}
+	return since
+}
+
+func (u *assetUsage) forget(sha string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.lastUsed, sha)
+}
+
+// cacheGC evicts the assets of the cache unused for longer than maxAge, and
+// the least recently used assets while the cache is larger than maxSize. The
+// assets used since the previous collection are never evicted for the size,
+// since they are likely in use, and the assets installed before the agent
+// started are considered used when it started.
+type cacheGC struct {
+	db           *bolt.DB
+	localStorage string
+	usage        *assetUsage
+	maxSize      int64
+	maxAge       time.Duration
+	interval     time.Duration
+	started      time.Time
+	now          func() time.Time
+}
+
+// cachedAsset is an asset of the cache, by its SHA-512 sum.
+type cachedAsset struct {
+	sha      string
+	path     string
+	size     int64
+	lastUsed time.Time
+}
+
+// run collects the assets at every interval until ctx is done.
+func (g *cacheGC) run(ctx context.Context) {
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+	for {
+		if err := g.collect(); err != nil {
+			logger.WithError(err).Error("error evicting assets from the cache")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// collect evicts the assets exceeding the limits of the cache, and updates
+// the size of the cache.
+func (g *cacheGC) collect() error {
+	now := g.now()
+	return g.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(assetBucketName)
+		if bucket == nil {
+			cacheSize.Set(0)
+			return nil
+		}
+
+		var assets []cachedAsset
+		var total int64
+		if err := bucket.ForEach(func(k, v []byte) error {
+			var runtimeAsset RuntimeAsset
+			if err := json.Unmarshal(v, &runtimeAsset); err != nil {
+				return nil
+			}
+			asset := cachedAsset{
+				sha:      string(k),
+				path:     runtimeAsset.Path,
+				size:     dirSize(runtimeAsset.Path),
+				lastUsed: g.usage.get(string(k), g.started),
+			}
+			total += asset.size
+			assets = append(assets, asset)
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		sort.Slice(assets, func(i, j int) bool { return assets[i].lastUsed.Before(assets[j].lastUsed) })
+		for _, asset := range assets {
+			reason := g.evictionReason(asset, total, now)
+			if reason == "" {
+				continue
+			}
+			if err := bucket.Delete([]byte(asset.sha)); err != nil {
+				return err
+			}
+			// The assets are expanded in the cache directory, check it for
+			// safety before removing them
+			if filepath.Dir(asset.path) == filepath.Clean(g.localStorage) {
+				if err := os.RemoveAll(asset.path); err != nil {
+					logger.WithError(err).WithField("path", asset.path).Error("error removing asset from the cache")
+				}
+			}
+			g.usage.forget(asset.sha)
+			total -= asset.size
+			cacheEvictions.WithLabelValues(reason).Inc()
+			logger.WithFields(map[string]interface{}{
+				"sha512": asset.sha,
+				"reason": reason,
+			}).Info("evicted asset from the cache")
+		}
+		cacheSize.Set(float64(total))
+		return nil
+	})
+}
+
+// evictionReason returns why an asset must be evicted from a cache of the
+// given total size, "age" or "size", or an empty string if it is kept.
+func (g *cacheGC) evictionReason(asset cachedAsset, total int64, now time.Time) string {
+	unused := now.Sub(asset.lastUsed)
+	if g.maxAge > 0 && unused > g.maxAge {
+		return "age"
+	}
+	if g.maxSize > 0 && total > g.maxSize && unused >= g.interval {
+		return "size"
+	}
+	return ""
+}
+
+// dirSize returns the size of the files of a directory.
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.Type().IsRegular() {
+			if info, err := entry.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,114 @@
+package asset
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+// installAsset adds an asset with a file of the given size to the cache.
+func installAsset(t *testing.T, db *bolt.DB, dir, sha string, size int) string {
+	t.Helper()
+	path := filepath.Join(dir, sha)
+	require.NoError(t, os.MkdirAll(filepath.Join(path, "bin"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(path, "bin", "check"), make([]byte, size), 0755))
+	value, err := json.Marshal(&RuntimeAsset{Path: path})
+	require.NoError(t, err)
+	require.NoError(t, db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(assetBucketName)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(sha), value)
+	}))
+	return path
+}
+
+func cachedAssets(t *testing.T, db *bolt.DB) []string {
+	t.Helper()
+	var shas []string
+	require.NoError(t, db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(assetBucketName).ForEach(func(k, v []byte) error {
+			shas = append(shas, string(k))
+			return nil
+		})
+	}))
+	return shas
+}
+
+func TestCacheGC(t *testing.T) {
+	dir := t.TempDir()
+	db, err := bolt.Open(filepath.Join(dir, dbName), 0600, &bolt.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	started := time.Now().Add(-3 * time.Hour)
+	now := started.Add(3 * time.Hour)
+	usage := newAssetUsage()
+
+	// Unused since the agent started
+	stale := installAsset(t, db, dir, "stale", 100)
+	// Least recently used
+	old := installAsset(t, db, dir, "old", 100)
+	usage.touch("old", now.Add(-time.Hour))
+	recent := installAsset(t, db, dir, "recent", 100)
+	usage.touch("recent", now.Add(-30*time.Minute))
+	// Used since the previous collection
+	current := installAsset(t, db, dir, "current", 100)
+	usage.touch("current", now.Add(-time.Minute))
+
+	gc := &cacheGC{
+		db:           db,
+		localStorage: dir,
+		usage:        usage,
+		maxSize:      150,
+		maxAge:       2 * time.Hour,
+		interval:     CacheGCInterval,
+		started:      started,
+		now:          func() time.Time { return now },
+	}
+	ageEvictions := testutil.ToFloat64(cacheEvictions.WithLabelValues("age"))
+	sizeEvictions := testutil.ToFloat64(cacheEvictions.WithLabelValues("size"))
+	require.NoError(t, gc.collect())
+
+	assert.ElementsMatch(t, []string{"current"}, cachedAssets(t, db))
+	for _, path := range []string{stale, old, recent} {
+		assert.NoDirExists(t, path)
+	}
+	assert.DirExists(t, current)
+	assert.Equal(t, float64(100), testutil.ToFloat64(cacheSize))
+	assert.Equal(t, ageEvictions+1, testutil.ToFloat64(cacheEvictions.WithLabelValues("age")))
+	assert.Equal(t, sizeEvictions+2, testutil.ToFloat64(cacheEvictions.WithLabelValues("size")))
+}
+
+func TestCacheGCWithinLimits(t *testing.T) {
+	dir := t.TempDir()
+	db, err := bolt.Open(filepath.Join(dir, dbName), 0600, &bolt.Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	installAsset(t, db, dir, "a", 100)
+	installAsset(t, db, dir, "b", 100)
+
+	gc := &cacheGC{
+		db:           db,
+		localStorage: dir,
+		usage:        newAssetUsage(),
+		maxSize:      1000,
+		maxAge:       time.Hour,
+		interval:     CacheGCInterval,
+		started:      now.Add(-time.Minute),
+		now:          func() time.Time { return now },
+	}
+	require.NoError(t, gc.collect())
+	assert.ElementsMatch(t, []string{"a", "b"}, cachedAssets(t, db))
+	assert.Equal(t, float64(200), testutil.ToFloat64(cacheSize))
+}
@@ -34,8 +34,9 @@ type Fetcher interface {
 // URLGetter gets all content at the specified URL.
 type urlGetter func(context.Context, string, string, map[string]string) (io.ReadCloser, error)
 
-// Get the target URL and return an io.ReadCloser
-func httpGet(ctx context.Context, path, trustedCAFile string, headers map[string]string) (io.ReadCloser, error) {
+// newHTTPClient returns the HTTP client fetching the assets, trusting the
+// certificates of trustedCAFile in addition to the system ones.
+func newHTTPClient(trustedCAFile string) *http.Client {
 	client := &http.Client{}
 
 	if trustedCAFile != "" {
@@ -67,7 +68,17 @@ func httpGet(ctx context.Context, path, trustedCAFile string, headers map[string
 			},
 		}
 	}
+	return client
+}
+
+// Get the target URL and return an io.ReadCloser
+func httpGet(ctx context.Context, path, trustedCAFile string, headers map[string]string) (io.ReadCloser, error) {
+	return getWithClient(ctx, newHTTPClient(trustedCAFile), path, headers)
+}
 
+// getWithClient gets the target URL with the given client and returns an
+// io.ReadCloser
+func getWithClient(ctx context.Context, client *http.Client, path string, headers map[string]string) (io.ReadCloser, error) {
 	req, err := http.NewRequest(http.MethodGet, path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching asset: %s", err)
@@ -112,7 +123,11 @@ func (h *httpFetcher) Fetch(ctx context.Context, url string, headers map[string]
 		}
 	}
 
-	resp, err := h.URLGetter(ctx, url, h.trustedCAFile, headers)
+	getter := h.URLGetter
+	if strings.HasPrefix(url, OCIScheme) {
+		getter = ociGet
+	}
+	resp, err := getter(ctx, url, h.trustedCAFile, headers)
 	if err != nil {
 		return nil, err
 	}
@@ -120,7 +120,47 @@ func (f *filteredManager) Get(ctx context.Context, asset *corev2.Asset) (*Runtim
 		return nil, fmt.Errorf("error while substituting asset %q tokens: %s", asset.Name, err)
 	}
 
-	return f.getter.Get(ctx, filteredAsset)
+	return f.getFromMirrors(ctx, filteredAsset)
+}
+
+// getFromMirrors gets an asset from the mirrors whose filters match the
+// entity, in order, and falls back to the URL of the asset. The assets are
+// verified with their checksum, so a mirror serving another file is skipped.
+func (f *filteredManager) getFromMirrors(ctx context.Context, asset *corev2.Asset) (*RuntimeAsset, error) {
+	fields := logrus.Fields{
+		"entity": f.entity.Name,
+		"asset":  asset.Name,
+	}
+	mirrors, err := Mirrors(asset)
+	if err != nil {
+		logger.WithFields(fields).WithError(err).Error("ignoring the mirrors of the asset")
+	}
+	for _, mirror := range mirrors {
+		filtered, err := f.isFiltered(&corev2.Asset{Filters: mirror.Filters})
+		if err != nil {
+			logger.WithFields(fields).WithError(err).Error("error filtering entities from asset mirror")
+			continue
+		}
+		if !filtered {
+			continue
+		}
+		mirrorURL, err := mirror.URLFor(asset.URL)
+		if err != nil {
+			logger.WithFields(fields).WithError(err).Error("error building the URL of the asset on a mirror")
+			continue
+		}
+		mirrored := *asset
+		mirrored.URL = mirrorURL
+		runtimeAsset, err := f.getter.Get(ctx, &mirrored)
+		if err == nil {
+			return runtimeAsset, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		logger.WithFields(fields).WithField("url", mirrorURL).WithError(err).Warn("could not get asset from mirror, trying the next one")
+	}
+	return f.getter.Get(ctx, asset)
 }
 
 // isFiltered evaluates the given asset's filters and returns true if all of
@@ -22,6 +22,11 @@ type Manager struct {
 	entity		*v2.Entity
 	wg		*sync.WaitGroup
 	trustedCAFile	string
+
+	signatureVerifier	*SignatureVerifier
+	cacheMaxSize		int64
+	cacheMaxAge		time.Duration
+	proxy			*Proxy
 }
 
 // NewManager ...
@@ -34,6 +39,29 @@ func NewManager(cacheDir, trustedCAFile string, entity *v2.Entity, wg *sync.Wait
 	}
 }
 
+// SetSignatureVerifier sets the verifier of the signatures of the assets, which
+// are verified before they are expanded. It must be called before the asset
+// manager is started.
+func (m *Manager) SetSignatureVerifier(verifier *SignatureVerifier) {
+	m.signatureVerifier = verifier
+}
+
+// SetCacheLimits sets the limits of the cache: the assets unused for longer
+// than maxAge, and the least recently used assets while the cache is larger
+// than maxSize bytes, are evicted from the cache every CacheGCInterval. A zero
+// limit disables it. It must be called before the asset manager is started.
+func (m *Manager) SetCacheLimits(maxSize int64, maxAge time.Duration) {
+	m.cacheMaxSize = maxSize
+	m.cacheMaxAge = maxAge
+}
+
+// SetProxy sets the asset proxy of the backend the assets are fetched
+// through, before falling back to their URL. It must be called before the
+// asset manager is started.
+func (m *Manager) SetProxy(proxy *Proxy) {
+	m.proxy = proxy
+}
+
 // StartAssetManager starts the asset manager for a backend or agent.
 func (m *Manager) StartAssetManager(ctx context.Context, limiter *rate.Limiter) (Getter, error) {
 	// create agent cache directory if it doesn't already exist
@@ -57,7 +85,31 @@ func (m *Manager) StartAssetManager(ctx context.Context, limiter *rate.Limiter)
 		}
 	}()
 	boltDBGetter := NewBoltDBGetter(
-		db, m.cacheDir, m.trustedCAFile, nil, nil, nil, limiter)
+		db, m.cacheDir, m.trustedCAFile, nil, nil, nil, limiter).(*boltDBAssetManager)
+	boltDBGetter.signatureVerifier = m.signatureVerifier
+	boltDBGetter.proxy = m.proxy
+	if m.proxy != nil {
+		boltDBGetter.proxyFetcher = &httpFetcher{Limiter: limiter, URLGetter: m.proxy.get}
+	}
+
+	if m.cacheMaxSize > 0 || m.cacheMaxAge > 0 {
+		boltDBGetter.usage = newAssetUsage()
+		gc := &cacheGC{
+			db:		db,
+			localStorage:	m.cacheDir,
+			usage:		boltDBGetter.usage,
+			maxSize:	m.cacheMaxSize,
+			maxAge:		m.cacheMaxAge,
+			interval:	CacheGCInterval,
+			started:	time.Now(),
+			now:		time.Now,
+		}
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			gc.run(ctx)
+		}()
+	}
 
 	return NewFilteredManager(boltDBGetter, m.entity), nil
 }
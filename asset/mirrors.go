@@ -0,0 +1,56 @@
+package asset
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	corev2 "github.com/sensu/core/v2"
+)
+
+// MirrorsAnnotation is the annotation of the assets listing their mirrors, as
+// a JSON list of mirrors. The agents fetch the builds from the mirrors whose
+// filters match their entity, in order, then from the URL of the build:
+//
+//	sensu.io/asset-mirrors: '[{"url": "https://mirror.eu.example.com/assets", "filters": ["entity.labels.region == ''eu''"]}]'
+const MirrorsAnnotation = "sensu.io/asset-mirrors"
+
+// Mirror is a mirror of the builds of an asset. The URL of a build on a mirror
+// is the URL of the mirror followed by the file name of the build.
+type Mirror struct {
+	URL     string   `json:"url"`
+	Filters []string `json:"filters,omitempty"`
+}
+
+// Mirrors returns the mirrors of an asset, given by its MirrorsAnnotation.
+func Mirrors(asset *corev2.Asset) ([]Mirror, error) {
+	value, ok := asset.Annotations[MirrorsAnnotation]
+	if !ok || strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	var mirrors []Mirror
+	if err := json.Unmarshal([]byte(value), &mirrors); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %s", MirrorsAnnotation, err)
+	}
+	for _, mirror := range mirrors {
+		if mirror.URL == "" {
+			return nil, fmt.Errorf("invalid %s annotation: a mirror has no URL", MirrorsAnnotation)
+		}
+	}
+	return mirrors, nil
+}
+
+// URLFor returns the URL of a build on the mirror, given the URL of the build.
+func (m Mirror) URLFor(buildURL string) (string, error) {
+	u, err := url.Parse(buildURL)
+	if err != nil {
+		return "", err
+	}
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		return "", fmt.Errorf("the URL %q has no file name", buildURL)
+	}
+	return strings.TrimSuffix(m.URL, "/") + "/" + name, nil
+}
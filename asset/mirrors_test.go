@@ -0,0 +1,121 @@
+package asset
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mirrorGetter fails to get the assets from the URLs it has no asset for, and
+// records the URLs it was asked for.
+type mirrorGetter struct {
+	assets map[string]*RuntimeAsset
+	urls   []string
+}
+
+func (g *mirrorGetter) Get(ctx context.Context, asset *corev2.Asset) (*RuntimeAsset, error) {
+	g.urls = append(g.urls, asset.URL)
+	if runtimeAsset, ok := g.assets[asset.URL]; ok {
+		return runtimeAsset, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestMirrors(t *testing.T) {
+	asset := corev2.FixtureAsset("asset")
+	mirrors, err := Mirrors(asset)
+	require.NoError(t, err)
+	assert.Empty(t, mirrors)
+
+	asset.Annotations = map[string]string{MirrorsAnnotation: `[{"url": "https://mirror.example.com/assets/", "filters": ["entity.labels.region == 'eu'"]}]`}
+	mirrors, err = Mirrors(asset)
+	require.NoError(t, err)
+	require.Len(t, mirrors, 1)
+	mirrorURL, err := mirrors[0].URLFor("https://example.com/releases/asset_1.0.0_linux_amd64.tar.gz?token=1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://mirror.example.com/assets/asset_1.0.0_linux_amd64.tar.gz", mirrorURL)
+
+	_, err = mirrors[0].URLFor("https://example.com")
+	assert.Error(t, err)
+
+	asset.Annotations[MirrorsAnnotation] = `[{"filters": []}]`
+	_, err = Mirrors(asset)
+	assert.Error(t, err)
+
+	asset.Annotations[MirrorsAnnotation] = `{`
+	_, err = Mirrors(asset)
+	assert.Error(t, err)
+}
+
+func TestFilteredManagerMirrors(t *testing.T) {
+	entity := corev2.FixtureEntity("test-entity")
+	entity.Labels = map[string]string{"region": "eu"}
+
+	asset := corev2.FixtureAsset("asset")
+	asset.URL = "https://example.com/releases/asset.tar.gz"
+	asset.Annotations = map[string]string{MirrorsAnnotation: `[
+		{"url": "https://mirror.us.example.com", "filters": ["entity.labels.region == 'us'"]},
+		{"url": "https://mirror-{{ .labels.region }}-1.example.com"},
+		{"url": "https://mirror.eu.example.com", "filters": ["entity.labels.region == 'eu'"]}
+	]`}
+
+	tests := []struct {
+		name     string
+		assets   []string
+		wantURLs []string
+		wantErr  bool
+	}{
+		{
+			name:     "first matching mirror",
+			assets:   []string{"https://mirror-eu-1.example.com/asset.tar.gz"},
+			wantURLs: []string{"https://mirror-eu-1.example.com/asset.tar.gz"},
+		},
+		{
+			name:     "next matching mirror",
+			assets:   []string{"https://mirror.eu.example.com/asset.tar.gz"},
+			wantURLs: []string{"https://mirror-eu-1.example.com/asset.tar.gz", "https://mirror.eu.example.com/asset.tar.gz"},
+		},
+		{
+			name:     "fallback to the asset URL",
+			assets:   []string{"https://example.com/releases/asset.tar.gz"},
+			wantURLs: []string{"https://mirror-eu-1.example.com/asset.tar.gz", "https://mirror.eu.example.com/asset.tar.gz", "https://example.com/releases/asset.tar.gz"},
+		},
+		{
+			name:     "not found",
+			wantURLs: []string{"https://mirror-eu-1.example.com/asset.tar.gz", "https://mirror.eu.example.com/asset.tar.gz", "https://example.com/releases/asset.tar.gz"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := &mirrorGetter{assets: map[string]*RuntimeAsset{}}
+			for _, u := range tt.assets {
+				getter.assets[u] = &RuntimeAsset{Path: "/foo/bar"}
+			}
+			manager := NewFilteredManager(getter, entity)
+
+			// The tokens are substituted in the asset given to the manager
+			runtimeAsset, err := manager.Get(context.Background(), deepCopyAsset(asset))
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "/foo/bar", runtimeAsset.Path)
+			}
+			assert.Equal(t, tt.wantURLs, getter.urls)
+		})
+	}
+}
+
+func deepCopyAsset(asset *corev2.Asset) *corev2.Asset {
+	copied := *asset
+	copied.Annotations = map[string]string{}
+	for k, v := range asset.Annotations {
+		copied.Annotations[k] = v
+	}
+	return &copied
+}
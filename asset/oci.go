@@ -0,0 +1,442 @@
+package asset
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// OCIScheme is the scheme of the URLs of the asset builds pulled from OCI
+// registries, oci://REGISTRY/REPOSITORY[:TAG][@DIGEST], like
+// oci://ghcr.io/sensu/check-disk:1.0.0. The artifact is the single layer of
+// the manifest, or its first tarball layer, and the digest pins the manifest.
+const OCIScheme = "oci://"
+
+const (
+	ociManifestType       = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestType    = "application/vnd.docker.distribution.manifest.v2+json"
+	ociIndexType          = "application/vnd.oci.image.index.v1+json"
+	dockerManifestList    = "application/vnd.docker.distribution.manifest.list.v2+json"
+	maxOCIManifestSize    = 4 << 20
+	defaultOCIRegistryTag = "latest"
+)
+
+// OCITokenRealms are the hosts of the token services trusted by the OCI
+// registries whose token service has another host, by registry host. The token
+// services of the other registries must have the host of the registry.
+var OCITokenRealms = map[string][]string{
+	"docker.io":            {"auth.docker.io"},
+	"registry-1.docker.io": {"auth.docker.io"},
+}
+
+// ociReference is a reference to a manifest of an OCI registry.
+type ociReference struct {
+	registry   string
+	repository string
+	tag        string
+	digest     string
+}
+
+// parseOCIReference parses an oci:// URL.
+func parseOCIReference(ref string) (ociReference, error) {
+	var r ociReference
+	rest := strings.TrimPrefix(ref, OCIScheme)
+	slash := strings.Index(rest, "/")
+	if slash <= 0 {
+		return r, fmt.Errorf("invalid OCI reference %q: no repository", ref)
+	}
+	r.registry, rest = rest[:slash], rest[slash+1:]
+	if at := strings.Index(rest, "@"); at >= 0 {
+		rest, r.digest = rest[:at], rest[at+1:]
+		if !strings.HasPrefix(r.digest, "sha256:") {
+			return r, fmt.Errorf("invalid OCI reference %q: unsupported digest %q", ref, r.digest)
+		}
+	}
+	// The tag follows the last colon after the last slash, the colons before
+	// being the port of the registry
+	if colon := strings.LastIndex(rest, ":"); colon > strings.LastIndex(rest, "/") {
+		rest, r.tag = rest[:colon], rest[colon+1:]
+	}
+	r.repository = rest
+	if r.repository == "" {
+		return r, fmt.Errorf("invalid OCI reference %q: no repository", ref)
+	}
+	if r.tag == "" && r.digest == "" {
+		r.tag = defaultOCIRegistryTag
+	}
+	return r, nil
+}
+
+// baseURL returns the URL of the registry API. The registries of the loopback
+// addresses are reached over plain HTTP, like with docker.
+func (r ociReference) baseURL() string {
+	if isLoopbackHost(r.host()) {
+		return "http://" + r.registry
+	}
+	return "https://" + r.registry
+}
+
+// host returns the host of the registry, without its port.
+func (r ociReference) host() string {
+	if h, _, err := net.SplitHostPort(r.registry); err == nil {
+		return h
+	}
+	return r.registry
+}
+
+// isLoopbackHost returns true if the host is a loopback address.
+func isLoopbackHost(host string) bool {
+	ip := net.ParseIP(host)
+	return host == "localhost" || (ip != nil && ip.IsLoopback())
+}
+
+// manifestRef returns the reference of the manifest, its digest if pinned.
+func (r ociReference) manifestRef() string {
+	if r.digest != "" {
+		return r.digest
+	}
+	return r.tag
+}
+
+// ociManifest is the part of the OCI image manifests used to find the
+// artifact.
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// artifact returns the layer of the manifest holding the asset: its single
+// layer, or its first tarball layer.
+func (m ociManifest) artifact() (ociDescriptor, error) {
+	switch m.MediaType {
+	case ociIndexType, dockerManifestList:
+		return ociDescriptor{}, errors.New("OCI image indexes are not supported, the reference must be a manifest")
+	}
+	if len(m.Layers) == 1 {
+		return m.Layers[0], nil
+	}
+	for _, layer := range m.Layers {
+		if strings.Contains(layer.MediaType, "tar") || strings.HasSuffix(layer.MediaType, "gzip") {
+			return layer, nil
+		}
+	}
+	return ociDescriptor{}, fmt.Errorf("the OCI manifest has %d layers and none is a tarball", len(m.Layers))
+}
+
+// ociGet pulls the artifact of an asset build from an OCI registry. The
+// registry requests have the headers of the asset. When the registry asks
+// for credentials, they are the basic credentials of the Authorization header
+// of the asset, or the ones of the registry in the docker configuration,
+// which are exchanged for a token if the registry requires one.
+func ociGet(ctx context.Context, ref, trustedCAFile string, headers map[string]string) (io.ReadCloser, error) {
+	r, err := parseOCIReference(ref)
+	if err != nil {
+		return nil, err
+	}
+	registry := &ociRegistry{
+		client:  newHTTPClient(trustedCAFile),
+		ref:     r,
+		headers: headers,
+	}
+
+	manifestURL := fmt.Sprintf("%s/v2/%s/manifests/%s", r.baseURL(), r.repository, r.manifestRef())
+	resp, err := registry.get(ctx, manifestURL, strings.Join([]string{ociManifestType, dockerManifestType, ociIndexType, dockerManifestList}, ", "))
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxOCIManifestSize))
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching OCI manifest: %s", err)
+	}
+	if r.digest != "" {
+		if sum := sha256.Sum256(body); "sha256:"+hex.EncodeToString(sum[:]) != r.digest {
+			return nil, fmt.Errorf("digest of OCI manifest does not match %s", r.digest)
+		}
+	}
+	var manifest ociManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid OCI manifest: %s", err)
+	}
+	if manifest.MediaType == "" {
+		manifest.MediaType = resp.Header.Get("Content-Type")
+	}
+	layer, err := manifest.artifact()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(layer.Digest, "sha256:") {
+		return nil, fmt.Errorf("unsupported digest of OCI layer %q", layer.Digest)
+	}
+
+	blobURL := fmt.Sprintf("%s/v2/%s/blobs/%s", r.baseURL(), r.repository, layer.Digest)
+	resp, err = registry.get(ctx, blobURL, "")
+	if err != nil {
+		return nil, err
+	}
+	return &digestReader{
+		ReadCloser: resp.Body,
+		hash:       sha256.New(),
+		digest:     layer.Digest,
+	}, nil
+}
+
+// ociRegistry sends the requests to an OCI registry, authenticating them
+// when the registry asks to.
+type ociRegistry struct {
+	client        *http.Client
+	ref           ociReference
+	headers       map[string]string
+	authorization string
+}
+
+func (o *ociRegistry) get(ctx context.Context, u, accept string) (*http.Response, error) {
+	resp, err := o.do(ctx, u, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && o.authorization == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		_ = resp.Body.Close()
+		if o.authorization, err = o.authenticate(ctx, challenge); err != nil {
+			return nil, err
+		}
+		if resp, err = o.do(ctx, u, accept); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("error fetching asset from OCI registry: Response Code %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func (o *ociRegistry) do(ctx context.Context, u, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching asset: %s", err)
+	}
+	for k, v := range o.headers {
+		req.Header.Set(k, v)
+	}
+	if o.authorization != "" {
+		req.Header.Set("Authorization", o.authorization)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching asset: %s", err)
+	}
+	return resp, nil
+}
+
+// authenticate returns the Authorization header answering the challenge of
+// the registry: the basic credentials, or the bearer token they get from the
+// token service of the registry.
+func (o *ociRegistry) authenticate(ctx context.Context, challenge string) (string, error) {
+	basic := o.credentials()
+	scheme, params := parseChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if basic == "" {
+			return "", fmt.Errorf("no credentials for OCI registry %s", o.ref.registry)
+		}
+		return basic, nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("unsupported authentication of OCI registry %s: %q", o.ref.registry, challenge)
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("invalid token realm of OCI registry %s", o.ref.registry)
+	}
+	// The credentials of the registry are sent to the token service
+	if err := o.checkRealm(tokenURL); err != nil {
+		return "", err
+	}
+	query := tokenURL.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", o.ref.repository)
+	}
+	query.Set("scope", scope)
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if basic != "" {
+		req.Header.Set("Authorization", basic)
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error authenticating to OCI registry %s: %s", o.ref.registry, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error authenticating to OCI registry %s: Response Code %d", o.ref.registry, resp.StatusCode)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxOCIManifestSize)).Decode(&token); err != nil {
+		return "", fmt.Errorf("invalid token of OCI registry %s: %s", o.ref.registry, err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return "", fmt.Errorf("no token returned by OCI registry %s", o.ref.registry)
+	}
+	return "Bearer " + token.Token, nil
+}
+
+// checkRealm returns an error if the token service of the registry is not
+// trusted: it must be reached over HTTPS, or over HTTP for the registries of
+// the loopback addresses, and have the host of the registry or one of its
+// OCITokenRealms.
+func (o *ociRegistry) checkRealm(realm *url.URL) error {
+	registry := o.ref.host()
+	switch realm.Scheme {
+	case "https":
+	case "http":
+		if !isLoopbackHost(registry) || !isLoopbackHost(realm.Hostname()) {
+			return fmt.Errorf("token realm %q of OCI registry %s does not use HTTPS", realm, o.ref.registry)
+		}
+	default:
+		return fmt.Errorf("invalid token realm %q of OCI registry %s", realm, o.ref.registry)
+	}
+	host := strings.ToLower(realm.Hostname())
+	if host == strings.ToLower(registry) {
+		return nil
+	}
+	for _, trusted := range OCITokenRealms[strings.ToLower(registry)] {
+		if host == trusted {
+			return nil
+		}
+	}
+	return fmt.Errorf("token realm %q of OCI registry %s is not trusted", realm, o.ref.registry)
+}
+
+// credentials returns the basic credentials of the registry: the
+// Authorization header of the asset, or the credentials of the registry in
+// the docker configuration.
+func (o *ociRegistry) credentials() string {
+	for k, v := range o.headers {
+		if strings.EqualFold(k, "Authorization") && strings.HasPrefix(strings.ToLower(v), "basic ") {
+			return v
+		}
+	}
+	if auth := dockerCredentials(o.ref.registry); auth != "" {
+		return "Basic " + auth
+	}
+	return ""
+}
+
+// dockerCredentials returns the base64 encoded credentials of a registry in
+// the docker configuration, $DOCKER_CONFIG/config.json or
+// ~/.docker/config.json. The credential helpers are not supported.
+func dockerCredentials(registry string) string {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return ""
+	}
+	var config struct {
+		Auths map[string]struct {
+			Auth     string `json:"auth"`
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(b, &config); err != nil {
+		logger.WithError(err).Warn("invalid docker configuration")
+		return ""
+	}
+	for _, key := range []string{registry, "https://" + registry, "http://" + registry} {
+		auth, ok := config.Auths[key]
+		if !ok {
+			continue
+		}
+		if auth.Auth != "" {
+			return auth.Auth
+		}
+		if auth.Username != "" {
+			return base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password))
+		}
+	}
+	return ""
+}
+
+// parseChallenge parses a WWW-Authenticate header, like
+// Bearer realm="https://auth.example.com/token",service="registry".
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key = strings.TrimSpace(key); key != "" {
+			params[strings.ToLower(key)] = value
+		}
+	}
+	return scheme, params
+}
+
+// digestReader verifies the digest of the content it reads, once it read all
+// of it.
+type digestReader struct {
+	io.ReadCloser
+	hash   hash.Hash
+	digest string
+}
+
+func (d *digestReader) Read(p []byte) (int, error) {
+	n, err := d.ReadCloser.Read(p)
+	d.hash.Write(p[:n])
+	if err == io.EOF {
+		if digest := "sha256:" + hex.EncodeToString(d.hash.Sum(nil)); digest != d.digest {
+			return n, fmt.Errorf("digest of OCI layer (%s) does not match %s", digest, d.digest)
+		}
+	}
+	return n, err
+}
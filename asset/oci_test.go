@@ -0,0 +1,222 @@
+package asset
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha256Digest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// ociRegistryServer serves an artifact as the single layer of the manifest
+// sensu/check-disk:1.0.0, requiring a token obtained with the credentials
+// user:pass.
+func ociRegistryServer(t *testing.T, artifact, served []byte) (*httptest.Server, string) {
+	t.Helper()
+	manifest, err := json.Marshal(ociManifest{
+		MediaType: ociManifestType,
+		Layers: []ociDescriptor{{
+			MediaType: "application/vnd.oci.image.layer.v1.tar+gzip",
+			Digest:    sha256Digest(artifact),
+			Size:      int64(len(artifact)),
+		}},
+	})
+	require.NoError(t, err)
+	manifestDigest := sha256Digest(manifest)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != "user" || pass != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Equal(t, "repository:sensu/check-disk:pull", r.URL.Query().Get("scope"))
+			_, _ = w.Write([]byte(`{"token": "secret"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/sensu/check-disk/manifests/1.0.0", "/v2/sensu/check-disk/manifests/" + manifestDigest:
+			w.Header().Set("Content-Type", ociManifestType)
+			_, _ = w.Write(manifest)
+		case "/v2/sensu/check-disk/blobs/" + sha256Digest(artifact):
+			_, _ = w.Write(served)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server, manifestDigest
+}
+
+func TestOCIGet(t *testing.T) {
+	artifact := []byte("asset archive")
+	server, manifestDigest := ociRegistryServer(t, artifact, artifact)
+	defer server.Close()
+	registry := strings.TrimPrefix(server.URL, "http://")
+	headers := map[string]string{"Authorization": "Basic dXNlcjpwYXNz"}
+
+	for _, ref := range []string{
+		OCIScheme + registry + "/sensu/check-disk:1.0.0",
+		OCIScheme + registry + "/sensu/check-disk@" + manifestDigest,
+	} {
+		t.Run(ref, func(t *testing.T) {
+			rc, err := ociGet(context.Background(), ref, "", headers)
+			require.NoError(t, err)
+			defer rc.Close()
+			content, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			assert.Equal(t, artifact, content)
+		})
+	}
+}
+
+func TestOCIGetDockerCredentials(t *testing.T) {
+	artifact := []byte("asset archive")
+	server, _ := ociRegistryServer(t, artifact, artifact)
+	defer server.Close()
+	registry := strings.TrimPrefix(server.URL, "http://")
+
+	dir := t.TempDir()
+	config := fmt.Sprintf(`{"auths": {%q: {"auth": "dXNlcjpwYXNz"}}}`, registry)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0600))
+	t.Setenv("DOCKER_CONFIG", dir)
+
+	rc, err := ociGet(context.Background(), OCIScheme+registry+"/sensu/check-disk:1.0.0", "", nil)
+	require.NoError(t, err)
+	defer rc.Close()
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, artifact, content)
+}
+
+func TestOCIGetErrors(t *testing.T) {
+	artifact := []byte("asset archive")
+	server, _ := ociRegistryServer(t, artifact, []byte("tampered archive"))
+	defer server.Close()
+	registry := strings.TrimPrefix(server.URL, "http://")
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	headers := map[string]string{"Authorization": "Basic dXNlcjpwYXNz"}
+
+	_, err := ociGet(context.Background(), OCIScheme+registry+"/sensu/check-disk:1.0.0", "", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "error authenticating")
+
+	_, err = ociGet(context.Background(), OCIScheme+registry+"/sensu/check-disk@"+sha256Digest([]byte("other")), "", headers)
+	require.Error(t, err)
+
+	rc, err := ociGet(context.Background(), OCIScheme+registry+"/sensu/check-disk:1.0.0", "", headers)
+	require.NoError(t, err)
+	defer rc.Close()
+	_, err = io.ReadAll(rc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match")
+}
+
+func TestOCIRegistryCheckRealm(t *testing.T) {
+	tests := []struct {
+		registry string
+		realm    string
+		wantErr  string
+	}{
+		{registry: "ghcr.io", realm: "https://ghcr.io/token"},
+		{registry: "registry.example.com:5000", realm: "https://registry.example.com/token"},
+		{registry: "registry-1.docker.io", realm: "https://auth.docker.io/token"},
+		{registry: "127.0.0.1:5000", realm: "http://127.0.0.1:5000/token"},
+		{registry: "ghcr.io", realm: "http://ghcr.io/token", wantErr: "does not use HTTPS"},
+		{registry: "127.0.0.1:5000", realm: "http://auth.example.com/token", wantErr: "does not use HTTPS"},
+		{registry: "ghcr.io", realm: "ftp://ghcr.io/token", wantErr: "invalid token realm"},
+		{registry: "ghcr.io", realm: "https://auth.example.com/token", wantErr: "is not trusted"},
+		{registry: "quay.io", realm: "https://auth.docker.io/token", wantErr: "is not trusted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.registry+" "+tt.realm, func(t *testing.T) {
+			realm, err := url.Parse(tt.realm)
+			require.NoError(t, err)
+			registry := &ociRegistry{ref: ociReference{registry: tt.registry}}
+			err = registry.checkRealm(realm)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestParseOCIReference(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    ociReference
+		wantURL string
+		wantErr bool
+	}{
+		{
+			ref:     "oci://ghcr.io/sensu/check-disk:1.0.0",
+			want:    ociReference{registry: "ghcr.io", repository: "sensu/check-disk", tag: "1.0.0"},
+			wantURL: "https://ghcr.io",
+		},
+		{
+			ref:     "oci://localhost:5000/check-disk",
+			want:    ociReference{registry: "localhost:5000", repository: "check-disk", tag: "latest"},
+			wantURL: "http://localhost:5000",
+		},
+		{
+			ref:     "oci://registry.example.com:443/check-disk:1.0@sha256:abc",
+			want:    ociReference{registry: "registry.example.com:443", repository: "check-disk", tag: "1.0", digest: "sha256:abc"},
+			wantURL: "https://registry.example.com:443",
+		},
+		{
+			ref:     "oci://ghcr.io",
+			wantErr: true,
+		},
+		{
+			ref:     "oci://ghcr.io/check-disk@md5:abc",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := parseOCIReference(tt.ref)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantURL, got.baseURL())
+		})
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.example.com/token",service="registry",scope="repository:sensu/check-disk:pull"`)
+	assert.Equal(t, "Bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry",
+		"scope":   "repository:sensu/check-disk:pull",
+	}, params)
+}
@@ -0,0 +1,288 @@
+package asset
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"golang.org/x/time/rate"
+)
+
+// ProxyPathPrefix is the path of the asset proxy of the backends, on the
+// agent port. The archive of an asset build is served at
+// ProxyPathPrefix/SHA512/NAME, from the namespace of the agent. The names of
+// the assets can have slashes.
+const ProxyPathPrefix = "/assets"
+
+// ProxyPath returns the path of the archive of an asset build on the asset
+// proxy of the backends.
+func ProxyPath(name, sha512 string) string {
+	return path.Join(ProxyPathPrefix, sha512, name)
+}
+
+// ValidSha512 returns whether sha512 is a hex encoded SHA-512 sum.
+func ValidSha512(sha512 string) bool {
+	b, err := hex.DecodeString(sha512)
+	return err == nil && len(b) == 64
+}
+
+// Proxy is the asset proxy of the backend an agent is connected to, which the
+// agent fetches the asset builds through.
+type Proxy struct {
+	// BackendURL returns the URL of the backend, ws:// or wss://, or an empty
+	// string if the agent is not connected.
+	BackendURL func() string
+
+	// Headers are the headers of the requests, which authenticate the agent.
+	Headers map[string]string
+
+	// TLS are the TLS options of the connection of the agent to the backend,
+	// including the certificate of the agents authenticated by mTLS, if any.
+	TLS *corev2.TLSOptions
+}
+
+// get gets an archive from the asset proxy, with the TLS options of the
+// connection of the agent.
+func (p *Proxy) get(ctx context.Context, path, _ string, headers map[string]string) (io.ReadCloser, error) {
+	client := &http.Client{}
+	if p.TLS != nil {
+		tlsConfig, err := p.TLS.ToClientTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		client.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		}
+	}
+	return getWithClient(ctx, client, path, headers)
+}
+
+// urlFor returns the URL of an asset build on the asset proxy.
+func (p *Proxy) urlFor(asset *corev2.Asset) (string, error) {
+	backendURL := p.BackendURL()
+	if backendURL == "" {
+		return "", fmt.Errorf("not connected to a backend")
+	}
+	u, err := url.Parse(backendURL)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	}
+	u.Path = ProxyPath(asset.Name, asset.Sha512)
+	return u.String(), nil
+}
+
+// ArchiveCache is the cache of the asset proxy of a backend, which fetches the
+// archives of the asset builds once, verifies their checksum and keeps them
+// for the agents. The archives are evicted like the assets of the cache of
+// the agents, with SetLimits and Run.
+type ArchiveCache struct {
+	dir      string
+	fetcher  Fetcher
+	verifier Verifier
+	gc       *cacheGC
+
+	mu    sync.Mutex
+	locks map[string]*archiveLock
+}
+
+// archiveLock is the lock of the archive of an asset build, deleted once it
+// is not used.
+type archiveLock struct {
+	sync.Mutex
+	refs int
+}
+
+// NewArchiveCache returns an ArchiveCache keeping the archives in dir.
+func NewArchiveCache(dir, trustedCAFile string, limiter *rate.Limiter) (*ArchiveCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &ArchiveCache{
+		dir: dir,
+		fetcher: &httpFetcher{
+			Limiter:       limiter,
+			trustedCAFile: trustedCAFile,
+		},
+		verifier: defaultVerifier,
+		gc: &cacheGC{
+			usage:    newAssetUsage(),
+			interval: CacheGCInterval,
+			now:      time.Now,
+		},
+		locks: map[string]*archiveLock{},
+	}, nil
+}
+
+// SetLimits sets the maximum size, in bytes, of the archives of the cache, and
+// the maximum duration an archive stays in the cache without being served. 0
+// disables a limit. It must be called before Run.
+func (c *ArchiveCache) SetLimits(maxSize int64, maxAge time.Duration) {
+	c.gc.maxSize = maxSize
+	c.gc.maxAge = maxAge
+}
+
+// Run evicts the archives exceeding the limits of the cache at every
+// CacheGCInterval, until ctx is done. It returns immediately if the cache has
+// no limit.
+func (c *ArchiveCache) Run(ctx context.Context) {
+	if c.gc.maxSize <= 0 && c.gc.maxAge <= 0 {
+		return
+	}
+	ticker := time.NewTicker(c.gc.interval)
+	defer ticker.Stop()
+	for {
+		if err := c.collect(); err != nil {
+			logger.WithError(err).Error("error evicting archives from the asset proxy cache")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// collect evicts the archives exceeding the limits of the cache. The archives
+// in the cache before the backend started are considered served when they were
+// fetched.
+func (c *ArchiveCache) collect() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	var archives []cachedAsset
+	var total int64
+	for _, entry := range entries {
+		// Skip the partial archives being fetched
+		if !ValidSha512(entry.Name()) || !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		archive := cachedAsset{
+			sha:      entry.Name(),
+			path:     filepath.Join(c.dir, entry.Name()),
+			size:     info.Size(),
+			lastUsed: c.gc.usage.get(entry.Name(), info.ModTime()),
+		}
+		total += archive.size
+		archives = append(archives, archive)
+	}
+
+	now := c.gc.now()
+	sort.Slice(archives, func(i, j int) bool { return archives[i].lastUsed.Before(archives[j].lastUsed) })
+	for _, archive := range archives {
+		reason := c.gc.evictionReason(archive, total, now)
+		if reason == "" {
+			continue
+		}
+		// The archives being served stay readable once removed
+		lock := c.lock(archive.sha)
+		err := os.Remove(archive.path)
+		c.unlock(archive.sha, lock)
+		if err != nil && !os.IsNotExist(err) {
+			logger.WithError(err).WithField("path", archive.path).Error("error removing archive from the asset proxy cache")
+			continue
+		}
+		c.gc.usage.forget(archive.sha)
+		total -= archive.size
+		logger.WithFields(map[string]interface{}{
+			"sha512": archive.sha,
+			"reason": reason,
+		}).Info("evicted archive from the asset proxy cache")
+	}
+	return nil
+}
+
+// lock locks the archive of an asset build, so that concurrent requests fetch
+// it once.
+func (c *ArchiveCache) lock(sha512 string) *archiveLock {
+	c.mu.Lock()
+	lock, ok := c.locks[sha512]
+	if !ok {
+		lock = &archiveLock{}
+		c.locks[sha512] = lock
+	}
+	lock.refs++
+	c.mu.Unlock()
+	lock.Lock()
+	return lock
+}
+
+// unlock unlocks the archive of an asset build, and deletes its lock if no
+// other request waits for it.
+func (c *ArchiveCache) unlock(sha512 string, lock *archiveLock) {
+	lock.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	lock.refs--
+	if lock.refs == 0 {
+		delete(c.locks, sha512)
+	}
+}
+
+// Open returns the archive of an asset build, fetched from its URL if it is
+// not in the cache.
+func (c *ArchiveCache) Open(ctx context.Context, asset *corev2.Asset) (*os.File, error) {
+	sha512 := strings.ToLower(asset.Sha512)
+	if !ValidSha512(sha512) {
+		return nil, fmt.Errorf("invalid sha512 %q", asset.Sha512)
+	}
+	lock := c.lock(sha512)
+	defer c.unlock(sha512, lock)
+	c.gc.usage.touch(sha512, time.Now())
+
+	archive := filepath.Join(c.dir, sha512)
+	if f, err := os.Open(archive); err == nil {
+		return f, nil
+	}
+
+	tmpFile, err := c.fetcher.Fetch(ctx, asset.URL, asset.Headers)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+	if err := c.verifier.Verify(tmpFile, sha512); err != nil {
+		return nil, fmt.Errorf("could not validate downloaded asset %q: %s", asset.Name, err)
+	}
+
+	// The temporary file can be on another filesystem, copy it to the cache
+	// before renaming it, so that the archives of the cache are complete
+	partial, err := os.CreateTemp(c.dir, sha512+".*.partial")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(partial.Name())
+	if _, err := io.Copy(partial, tmpFile); err != nil {
+		_ = partial.Close()
+		return nil, err
+	}
+	if err := partial.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(partial.Name(), archive); err != nil {
+		return nil, err
+	}
+	return os.Open(archive)
+}
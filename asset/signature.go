@@ -0,0 +1,312 @@
+package asset
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	corev2 "github.com/sensu/core/v2"
+)
+
+// SignaturesAnnotation is the annotation of the assets holding the signatures
+// of their builds, as a JSON object keyed by the SHA-512 sums of the builds.
+// The signatures are those of "cosign sign-blob" with an ECDSA or RSA key: the
+// base64 encoded signature of the archive, and for the keyless signatures, the
+// PEM encoded certificate of the signing key:
+//
+//	sensu.io/asset-signatures: '{"<sha512>": {"signature": "MEUCIQ...", "certificate": "-----BEGIN CERTIFICATE-----..."}}'
+const SignaturesAnnotation = "sensu.io/asset-signatures"
+
+var (
+	// oidcIssuerV1 and oidcIssuerV2 are the extensions of the Fulcio
+	// certificates holding the OIDC issuer of the identity of the signer, as
+	// a raw string and as a DER encoded UTF8String respectively.
+	oidcIssuerV1 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidcIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// Signature is the signature of the archive of an asset build.
+type Signature struct {
+	Signature string `json:"signature"`
+
+	// Certificate is the certificate of the keyless signatures, followed by
+	// its intermediate certificates.
+	Certificate string `json:"certificate,omitempty"`
+}
+
+// SignatureConfig configures the verification of the signatures of the
+// assets.
+type SignatureConfig struct {
+	// PublicKeysFile is the path to the PEM encoded ECDSA or RSA public keys
+	// trusted to sign the assets.
+	PublicKeysFile string
+
+	// TrustRootsFile is the path to the PEM encoded root certificates trusted
+	// to issue the certificates of the keyless signatures, e.g. the Fulcio
+	// root of the Sigstore public good instance.
+	TrustRootsFile string
+
+	// Subject is the identity of the signer required in the certificates of
+	// the keyless signatures, an email address or a URI of their subject
+	// alternative names, and Issuer is the OIDC issuer of that identity.
+	Subject string
+	Issuer  string
+
+	// Strict rejects the unsigned assets.
+	Strict bool
+}
+
+// SignatureVerifier verifies the signatures of the archives of the assets
+// before they are expanded, with trusted public keys or, for the keyless
+// signatures, with certificates issued by trusted roots to a trusted
+// identity. The transparency log of the keyless signatures is not checked.
+type SignatureVerifier struct {
+	keys    []crypto.PublicKey
+	roots   *x509.CertPool
+	subject string
+	issuer  string
+	strict  bool
+}
+
+// NewSignatureVerifier returns a SignatureVerifier for the given config. It
+// returns nil if neither public keys nor trust roots are given, in which case
+// the signatures are not verified.
+func NewSignatureVerifier(config SignatureConfig) (*SignatureVerifier, error) {
+	if config.PublicKeysFile == "" && config.TrustRootsFile == "" {
+		if config.Strict {
+			return nil, errors.New("strict asset signature verification requires public keys or trust roots")
+		}
+		return nil, nil
+	}
+	v := &SignatureVerifier{
+		subject: config.Subject,
+		issuer:  config.Issuer,
+		strict:  config.Strict,
+	}
+	if config.PublicKeysFile != "" {
+		b, err := os.ReadFile(config.PublicKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		if v.keys, err = parsePublicKeys(b); err != nil {
+			return nil, fmt.Errorf("invalid asset signature public keys in %s: %s", config.PublicKeysFile, err)
+		}
+	}
+	if config.TrustRootsFile != "" {
+		// Any identity can get a certificate from a public trust root, so
+		// the keyless signatures are only trusted for the given identity
+		if config.Subject == "" || config.Issuer == "" {
+			return nil, errors.New("keyless asset signature verification requires the subject and the issuer of the signer")
+		}
+		b, err := os.ReadFile(config.TrustRootsFile)
+		if err != nil {
+			return nil, err
+		}
+		v.roots = x509.NewCertPool()
+		if !v.roots.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("no asset signature trust root found in %s", config.TrustRootsFile)
+		}
+	}
+	return v, nil
+}
+
+func parsePublicKeys(b []byte) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, b = pem.Decode(b)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		switch key.(type) {
+		case *ecdsa.PublicKey, *rsa.PublicKey:
+		default:
+			// The ed25519 signatures are made over the whole archive,
+			// which would have to be read in memory
+			return nil, fmt.Errorf("unsupported public key type %T, the assets must be signed with ECDSA or RSA keys", key)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no public key found")
+	}
+	return keys, nil
+}
+
+// Verify verifies the signature of the archive of an asset build, given by the
+// SignaturesAnnotation of the asset.
+func (v *SignatureVerifier) Verify(rs io.ReadSeeker, asset *corev2.Asset) error {
+	signature, err := assetSignature(asset)
+	if err != nil {
+		return err
+	}
+	if signature == nil {
+		if v.strict {
+			return fmt.Errorf("asset %q is not signed", asset.Name)
+		}
+		return nil
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature.Signature))
+	if err != nil {
+		return fmt.Errorf("invalid signature of asset %q: %s", asset.Name, err)
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, rs); err != nil {
+		return err
+	}
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	digest := h.Sum(nil)
+
+	if signature.Certificate != "" {
+		key, err := v.verifyCertificate(signature.Certificate)
+		if err != nil {
+			return fmt.Errorf("invalid certificate of asset %q: %s", asset.Name, err)
+		}
+		if !verifySignature(key, digest, sig) {
+			return fmt.Errorf("signature of asset %q does not match its certificate", asset.Name)
+		}
+		return nil
+	}
+	for _, key := range v.keys {
+		if verifySignature(key, digest, sig) {
+			return nil
+		}
+	}
+	return fmt.Errorf("signature of asset %q does not match any of the trusted keys", asset.Name)
+}
+
+// assetSignature returns the signature of an asset build, or nil if it is not
+// signed.
+func assetSignature(asset *corev2.Asset) (*Signature, error) {
+	value, ok := asset.Annotations[SignaturesAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var signatures map[string]*Signature
+	if err := json.Unmarshal([]byte(value), &signatures); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %s", SignaturesAnnotation, err)
+	}
+	for sha, signature := range signatures {
+		if strings.EqualFold(sha, asset.Sha512) && signature != nil {
+			return signature, nil
+		}
+	}
+	return nil, nil
+}
+
+// verifyCertificate verifies that a certificate was issued by one of the
+// trust roots to the trusted identity for code signing, and returns its public
+// key. The intermediate certificates follow the certificate. The short-lived
+// keyless certificates are verified at the time they were issued.
+func (v *SignatureVerifier) verifyCertificate(certificate string) (crypto.PublicKey, error) {
+	if v.roots == nil {
+		return nil, errors.New("no trust root configured")
+	}
+	var certs []*x509.Certificate
+	b := []byte(certificate)
+	for {
+		var block *pem.Block
+		block, b = pem.Decode(b)
+		if block == nil {
+			break
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         v.roots,
+		Intermediates: intermediates,
+		CurrentTime:   certs[0].NotBefore,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return nil, err
+	}
+	if !certificateHasSubject(certs[0], v.subject) {
+		return nil, fmt.Errorf("certificate was not issued to %q", v.subject)
+	}
+	issuer, err := certificateIssuer(certs[0])
+	if err != nil {
+		return nil, err
+	}
+	if issuer != v.issuer {
+		return nil, fmt.Errorf("certificate identity was issued by %q, not %q", issuer, v.issuer)
+	}
+	return certs[0].PublicKey, nil
+}
+
+// certificateHasSubject returns true if subject is one of the email addresses
+// or URIs of the subject alternative names of the certificate.
+func certificateHasSubject(cert *x509.Certificate, subject string) bool {
+	for _, email := range cert.EmailAddresses {
+		if email == subject {
+			return true
+		}
+	}
+	for _, uri := range cert.URIs {
+		if uri.String() == subject {
+			return true
+		}
+	}
+	return false
+}
+
+// certificateIssuer returns the OIDC issuer of the identity of a Fulcio
+// certificate.
+func certificateIssuer(cert *x509.Certificate) (string, error) {
+	for _, ext := range cert.Extensions {
+		switch {
+		case ext.Id.Equal(oidcIssuerV2):
+			var issuer string
+			if _, err := asn1.UnmarshalWithParams(ext.Value, &issuer, "utf8"); err != nil {
+				return "", fmt.Errorf("invalid certificate issuer extension: %s", err)
+			}
+			return issuer, nil
+		case ext.Id.Equal(oidcIssuerV1):
+			return string(ext.Value), nil
+		}
+	}
+	return "", errors.New("certificate has no issuer extension")
+}
+
+// verifySignature verifies a signature like cosign, made over the SHA-256
+// digest of the archive.
+func verifySignature(key crypto.PublicKey, digest, sig []byte) bool {
+	switch key := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, digest, sig)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, sig) == nil
+	default:
+		return false
+	}
+}
@@ -0,0 +1,274 @@
+package asset
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var signedContent = []byte("asset archive")
+
+func writePEM(t *testing.T, blockType string, blocks ...[]byte) string {
+	t.Helper()
+	var b bytes.Buffer
+	for _, block := range blocks {
+		require.NoError(t, pem.Encode(&b, &pem.Block{Type: blockType, Bytes: block}))
+	}
+	path := filepath.Join(t.TempDir(), "keys.pem")
+	require.NoError(t, os.WriteFile(path, b.Bytes(), 0600))
+	return path
+}
+
+func publicKeyDER(t *testing.T, key crypto.PublicKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	return der
+}
+
+func signedAsset(t *testing.T, signature Signature) *corev2.Asset {
+	t.Helper()
+	asset := corev2.FixtureAsset("check-disk")
+	b, err := json.Marshal(map[string]Signature{asset.Sha512: signature})
+	require.NoError(t, err)
+	asset.Annotations = map[string]string{SignaturesAnnotation: string(b)}
+	return asset
+}
+
+func ecdsaSignature(t *testing.T, key *ecdsa.PrivateKey, content []byte) string {
+	t.Helper()
+	digest := sha256.Sum256(content)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func TestSignatureVerifierPublicKeys(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	keys := writePEM(t, "PUBLIC KEY", publicKeyDER(t, &ecKey.PublicKey), publicKeyDER(t, &rsaKey.PublicKey))
+	digest := sha256.Sum256(signedContent)
+	rsaSig, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		asset   *corev2.Asset
+		strict  bool
+		content []byte
+		wantErr string
+	}{
+		{
+			name:    "ecdsa",
+			asset:   signedAsset(t, Signature{Signature: ecdsaSignature(t, ecKey, signedContent)}),
+			content: signedContent,
+		},
+		{
+			name:    "rsa",
+			asset:   signedAsset(t, Signature{Signature: base64.StdEncoding.EncodeToString(rsaSig)}),
+			content: signedContent,
+		},
+		{
+			name:    "untrusted key",
+			asset:   signedAsset(t, Signature{Signature: ecdsaSignature(t, otherKey, signedContent)}),
+			content: signedContent,
+			wantErr: "does not match any of the trusted keys",
+		},
+		{
+			name:    "tampered archive",
+			asset:   signedAsset(t, Signature{Signature: ecdsaSignature(t, ecKey, signedContent)}),
+			content: []byte("tampered archive"),
+			wantErr: "does not match any of the trusted keys",
+		},
+		{
+			name:    "keyless without trust roots",
+			asset:   signedAsset(t, Signature{Signature: ecdsaSignature(t, ecKey, signedContent), Certificate: "-----BEGIN CERTIFICATE-----"}),
+			content: signedContent,
+			wantErr: "no trust root configured",
+		},
+		{
+			name:    "unsigned",
+			asset:   corev2.FixtureAsset("check-disk"),
+			content: signedContent,
+		},
+		{
+			name:    "unsigned strict",
+			asset:   corev2.FixtureAsset("check-disk"),
+			strict:  true,
+			content: signedContent,
+			wantErr: "is not signed",
+		},
+		{
+			name:    "invalid annotation",
+			asset:   &corev2.Asset{ObjectMeta: corev2.ObjectMeta{Annotations: map[string]string{SignaturesAnnotation: "{"}}},
+			content: signedContent,
+			wantErr: "invalid sensu.io/asset-signatures annotation",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier, err := NewSignatureVerifier(SignatureConfig{PublicKeysFile: keys, Strict: tt.strict})
+			require.NoError(t, err)
+			rs := bytes.NewReader(tt.content)
+			err = verifier.Verify(rs, tt.asset)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			content, err := io.ReadAll(rs)
+			require.NoError(t, err)
+			assert.Equal(t, tt.content, content)
+		})
+	}
+}
+
+func TestNewSignatureVerifier(t *testing.T) {
+	verifier, err := NewSignatureVerifier(SignatureConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, verifier)
+
+	_, err = NewSignatureVerifier(SignatureConfig{Strict: true})
+	assert.Error(t, err)
+
+	_, err = NewSignatureVerifier(SignatureConfig{PublicKeysFile: writePEM(t, "CERTIFICATE", []byte("not a key"))})
+	assert.Error(t, err)
+
+	// The keyless signatures are only trusted for a given identity
+	_, err = NewSignatureVerifier(SignatureConfig{TrustRootsFile: writePEM(t, "CERTIFICATE", []byte("not a certificate"))})
+	assert.Error(t, err)
+
+	// The ed25519 signatures can't be verified without reading the whole
+	// archive in memory
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, err = NewSignatureVerifier(SignatureConfig{PublicKeysFile: writePEM(t, "PUBLIC KEY", publicKeyDER(t, edPub))})
+	assert.Error(t, err)
+}
+
+// keylessCertificate issues a short-lived code signing certificate for key,
+// like Fulcio, to the given identity.
+func keylessCertificate(t *testing.T, root *x509.Certificate, rootKey *ecdsa.PrivateKey, key *ecdsa.PrivateKey, subject, issuer string) string {
+	t.Helper()
+	issuerExt, err := asn1.MarshalWithParams(issuer, "utf8")
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       time.Now().Add(-time.Hour),
+		NotAfter:        time.Now().Add(-time.Hour + 10*time.Minute),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		EmailAddresses:  []string{subject},
+		ExtraExtensions: []pkix.Extension{{Id: oidcIssuerV2, Value: issuerExt}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, root, &key.PublicKey, rootKey)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+// keylessRoot creates a self-signed root certificate, like the Fulcio root.
+func keylessRoot(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "sigstore"},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	root, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return root, key
+}
+
+func TestSignatureVerifierKeyless(t *testing.T) {
+	root, rootKey := keylessRoot(t)
+	otherRoot, otherRootKey := keylessRoot(t)
+	roots := writePEM(t, "CERTIFICATE", root.Raw)
+
+	const (
+		subject = "releases@example.com"
+		issuer  = "https://accounts.example.com"
+	)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	sig := ecdsaSignature(t, key, signedContent)
+
+	tests := []struct {
+		name      string
+		signature Signature
+		wantErr   string
+	}{
+		{
+			name:      "trusted identity",
+			signature: Signature{Signature: sig, Certificate: keylessCertificate(t, root, rootKey, key, subject, issuer)},
+		},
+		{
+			name:      "other subject",
+			signature: Signature{Signature: sig, Certificate: keylessCertificate(t, root, rootKey, key, "mallory@example.com", issuer)},
+			wantErr:   "was not issued to",
+		},
+		{
+			name:      "other issuer",
+			signature: Signature{Signature: sig, Certificate: keylessCertificate(t, root, rootKey, key, subject, "https://evil.example.com")},
+			wantErr:   "certificate identity was issued by",
+		},
+		{
+			name:      "untrusted root",
+			signature: Signature{Signature: sig, Certificate: keylessCertificate(t, otherRoot, otherRootKey, key, subject, issuer)},
+			wantErr:   "invalid certificate",
+		},
+		{
+			name:      "other signing key",
+			signature: Signature{Signature: ecdsaSignature(t, otherKey, signedContent), Certificate: keylessCertificate(t, root, rootKey, key, subject, issuer)},
+			wantErr:   "does not match its certificate",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier, err := NewSignatureVerifier(SignatureConfig{TrustRootsFile: roots, Subject: subject, Issuer: issuer})
+			require.NoError(t, err)
+			err = verifier.Verify(bytes.NewReader(signedContent), signedAsset(t, tt.signature))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
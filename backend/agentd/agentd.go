@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -18,6 +20,7 @@ import (
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/sensu-go/agent"
+	"github.com/sensu/sensu-go/asset"
 	"github.com/sensu/sensu-go/backend/apid/middlewares"
 	"github.com/sensu/sensu-go/backend/apid/routers"
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
@@ -25,6 +28,7 @@ import (
 	"github.com/sensu/sensu-go/backend/authorization/rbac"
 	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/metrics"
+	"github.com/sensu/sensu-go/backend/quota"
 	"github.com/sensu/sensu-go/backend/ringv2"
 	"github.com/sensu/sensu-go/backend/store"
 	cachev2 "github.com/sensu/sensu-go/backend/store/cache/v2"
@@ -34,10 +38,6 @@ import (
 )
 
 var (
-	// upgrader is safe for concurrent use, and we don't need any particularly
-	// specialized configurations for different uses.
-	upgrader = &websocket.Upgrader{}
-
 	// used for registering prometheus session counter
 	sessionCounterOnce sync.Once
 )
@@ -98,6 +98,12 @@ type Agentd struct {
 	watcher        <-chan []storev2.WatchEvent
 	healthRouter   routers.Router
 	authenticator  Authenticator
+	quotas         *quota.Enforcer
+	// upgrader is safe for concurrent use by the websocket handler.
+	upgrader         *websocket.Upgrader
+	compressionLevel int
+	drainer          *Drainer
+	assetCache       *asset.ArchiveCache
 }
 
 // Config configures an Agentd.
@@ -112,6 +118,20 @@ type Config struct {
 	Watcher       <-chan []storev2.WatchEvent
 	HealthRouter  routers.Router
 	Authenticator Authenticator
+	Quotas        *quota.Enforcer
+
+	// CompressionLevel is the level of the permessage-deflate compression of
+	// the messages, when the agents enable it as well. The messages are not
+	// compressed when 0.
+	CompressionLevel int
+
+	// Drainer drains the agents connected to agentd. A new one is created
+	// when nil.
+	Drainer *Drainer
+
+	// AssetCache is the cache of the asset proxy serving the asset builds to
+	// the agents. The asset proxy is disabled when nil.
+	AssetCache *asset.ArchiveCache
 }
 
 // Option is a functional option.
@@ -136,6 +156,20 @@ func New(c Config, opts ...Option) (*Agentd, error) {
 		store:         c.Store,
 		watcher:       c.Watcher,
 		authenticator: c.Authenticator,
+		quotas:        c.Quotas,
+		upgrader: &websocket.Upgrader{
+			EnableCompression: c.CompressionLevel > 0,
+		},
+		compressionLevel: c.CompressionLevel,
+		drainer:          c.Drainer,
+		assetCache:       c.AssetCache,
+	}
+	if a.drainer == nil {
+		a.drainer = NewDrainer()
+	}
+
+	if err := transport.ValidateCompressionLevel(c.CompressionLevel); err != nil {
+		return nil, err
 	}
 
 	// prepare server TLS config
@@ -160,6 +194,12 @@ func New(c Config, opts ...Option) (*Agentd, error) {
 	a.healthRouter = c.HealthRouter
 	a.healthRouter.Mount(router)
 
+	if a.assetCache != nil {
+		assets := router.PathPrefix(asset.ProxyPathPrefix).Subrouter()
+		assets.HandleFunc("/{sha512}/{name:.+}", a.assetHandler).Methods(http.MethodGet)
+		assets.Use(authenticate, authorize)
+	}
+
 	route := router.NewRoute().Subrouter()
 	route.HandleFunc("/", a.webSocketHandler)
 	route.Use(agentLimit, authenticate, authorize)
@@ -309,6 +349,14 @@ func (a *Agentd) webSocketHandler(w http.ResponseWriter, r *http.Request) {
 		"namespace": r.Header.Get(transport.HeaderKeyNamespace),
 	})
 
+	// Send the agents to the other backends while this one is drained
+	if refuse, retryAfter := a.drainer.refusing(); refuse {
+		lager.Debug("refusing agent while draining")
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		http.Error(w, "the backend is draining its agents", http.StatusServiceUnavailable)
+		return
+	}
+
 	responseHeader := make(http.Header)
 	responseHeader.Add("Accept", agent.ProtobufSerializationHeader)
 	lager.WithField("header", fmt.Sprintf("Accept: %s", agent.ProtobufSerializationHeader)).Debug("setting header")
@@ -345,12 +393,31 @@ func (a *Agentd) webSocketHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	conn, err := upgrader.Upgrade(w, r, responseHeader)
+	// Refuse the agents whose entity would exceed the quota of the namespace
+	if a.quotas != nil {
+		err := a.quotas.CheckCreate(r.Context(), namespace, quota.ResourceEntities, r.Header.Get(transport.HeaderKeyAgentName))
+		if _, ok := err.(*quota.ExceededError); ok {
+			lager.WithError(err).Warning("refusing agent")
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		} else if err != nil {
+			lager.WithError(err).Error("could not check the entities quota")
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	conn, err := a.upgrader.Upgrade(w, r, responseHeader)
 	if err != nil {
 		lager.WithError(err).Error("transport error on websocket upgrade")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if err := transport.SetCompressionLevel(conn, a.compressionLevel); err != nil {
+		lager.WithError(err).Error("could not set the compression level")
+		_ = conn.Close()
+		return
+	}
 
 	cfg := SessionConfig{
 		AgentAddr:     r.RemoteAddr,
@@ -366,6 +433,8 @@ func (a *Agentd) webSocketHandler(w http.ResponseWriter, r *http.Request) {
 		Storev2:       a.store,
 		Marshal:       marshal,
 		Unmarshal:     unmarshal,
+		Quotas:        a.quotas,
+		Drainer:       a.drainer,
 	}
 
 	cfg.Subscriptions = corev2.AddEntitySubscription(cfg.AgentName, cfg.Subscriptions)
@@ -0,0 +1,99 @@
+package agentd
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/asset"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/transport"
+)
+
+// assetHandler serves the archives of the asset builds of the namespace of
+// the agents, from the asset cache of the backend. The builds are fetched
+// from their URL and headers as defined, so the builds with tokens, which
+// depend on the entities, are not served, and the agents fetch them.
+func (a *Agentd) assetHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	namespace := r.Header.Get(transport.HeaderKeyNamespace)
+	sha512 := strings.ToLower(vars["sha512"])
+	if !asset.ValidSha512(sha512) {
+		http.Error(w, "invalid sha512", http.StatusBadRequest)
+		return
+	}
+
+	assets := storev2.Of[*corev2.Asset](a.store)
+	resource, err := assets.Get(r.Context(), storev2.ID{Namespace: namespace, Name: vars["name"]})
+	if err != nil {
+		if _, ok := err.(*store.ErrNotFound); ok {
+			http.Error(w, "asset not found", http.StatusNotFound)
+			return
+		}
+		logger.WithError(err).Error("error getting asset for the asset proxy")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	build := findAssetBuild(resource, sha512)
+	if build == nil {
+		http.Error(w, "asset build not found", http.StatusNotFound)
+		return
+	}
+	if hasTokens(build) {
+		http.Error(w, "the asset build has tokens, fetch it from its URL", http.StatusUnprocessableEntity)
+		return
+	}
+
+	f, err := a.assetCache.Open(r.Context(), build)
+	if err != nil {
+		logger.WithField("asset", build.Name).WithError(err).Error("error fetching asset for the asset proxy")
+		http.Error(w, "error fetching asset", http.StatusBadGateway)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(w, r, "", info.ModTime(), f)
+}
+
+// findAssetBuild returns the build of an asset with the given sha512, as an
+// asset, or nil if there is none.
+func findAssetBuild(a *corev2.Asset, sha512 string) *corev2.Asset {
+	if len(a.Builds) == 0 {
+		if strings.EqualFold(a.Sha512, sha512) {
+			return a
+		}
+		return nil
+	}
+	for _, build := range a.Builds {
+		if strings.EqualFold(build.Sha512, sha512) {
+			return &corev2.Asset{
+				ObjectMeta: a.ObjectMeta,
+				URL:        build.URL,
+				Sha512:     build.Sha512,
+				Headers:    build.Headers,
+			}
+		}
+	}
+	return nil
+}
+
+// hasTokens returns whether the URL or the headers of an asset build have
+// tokens.
+func hasTokens(a *corev2.Asset) bool {
+	if strings.Contains(a.URL, "{{") {
+		return true
+	}
+	for _, v := range a.Headers {
+		if strings.Contains(v, "{{") {
+			return true
+		}
+	}
+	return false
+}
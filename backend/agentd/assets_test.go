@@ -0,0 +1,88 @@
+package agentd
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/asset"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/sensu/sensu-go/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetHandler(t *testing.T) {
+	archive := []byte("asset archive")
+	sum := sha512.Sum512(archive)
+	sha := hex.EncodeToString(sum[:])
+	otherSha := strings.Repeat("0", 128)
+
+	fetches := 0
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		_, _ = w.Write(archive)
+	}))
+	defer origin.Close()
+
+	checkDisk := corev2.FixtureAsset("check-disk")
+	checkDisk.Builds = []*corev2.AssetBuild{
+		{URL: origin.URL + "/check-disk.tar.gz", Sha512: sha},
+		{URL: origin.URL + "/{{ .labels.region }}/check-disk.tar.gz", Sha512: otherSha},
+	}
+
+	stor := &mockstore.V2MockStore{}
+	cstore := new(mockstore.ConfigStore)
+	stor.On("GetConfigStore").Return(cstore)
+	cstore.On("Get", mock.Anything, mock.MatchedBy(func(req storev2.ResourceRequest) bool {
+		return req.Name == "check-disk" && req.Namespace == "default"
+	})).Return(wrapResource(t, checkDisk), nil)
+	cstore.On("Get", mock.Anything, mock.Anything).Return(nil, &store.ErrNotFound{})
+
+	cache, err := asset.NewArchiveCache(t.TempDir(), "", nil)
+	require.NoError(t, err)
+	agentd := &Agentd{store: stor, assetCache: cache}
+	router := mux.NewRouter()
+	router.HandleFunc(asset.ProxyPathPrefix+"/{sha512}/{name:.+}", agentd.assetHandler)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+	}{
+		{name: "build", path: asset.ProxyPath("check-disk", sha), wantCode: http.StatusOK},
+		{name: "cached build", path: asset.ProxyPath("check-disk", sha), wantCode: http.StatusOK},
+		{name: "unknown build", path: asset.ProxyPath("check-disk", strings.Repeat("1", 128)), wantCode: http.StatusNotFound},
+		{name: "unknown asset", path: asset.ProxyPath("check-cpu", sha), wantCode: http.StatusNotFound},
+		{name: "build with tokens", path: asset.ProxyPath("check-disk", otherSha), wantCode: http.StatusUnprocessableEntity},
+		{name: "invalid sha512", path: asset.ProxyPath("check-disk", "abc"), wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, server.URL+tt.path, nil)
+			require.NoError(t, err)
+			req.Header.Set(transport.HeaderKeyNamespace, "default")
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.wantCode, resp.StatusCode)
+			if tt.wantCode == http.StatusOK {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, archive, body)
+			}
+		})
+	}
+	assert.Equal(t, 1, fetches)
+}
@@ -0,0 +1,181 @@
+package agentd
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/apid/routers"
+)
+
+// ErrDrainInProgress is returned when the agents are drained while a drain is
+// already in progress.
+var ErrDrainInProgress = errors.New("the agents are already being drained")
+
+// Drainer progressively disconnects the agents connected to agentd, so that a
+// backend can be drained for maintenance, or the agents rebalanced across the
+// backends. The agents are asked to wait for a jittered delay before they
+// reconnect, to avoid a thundering herd on the other backends.
+type Drainer struct {
+	mu       sync.Mutex
+	sessions map[*Session]struct{}
+
+	// id identifies the last drain, so that a cancelled drain does not update
+	// the status of the next one.
+	id           int
+	cancel       context.CancelFunc
+	draining     bool
+	refuse       bool
+	retryAfter   time.Duration
+	targeted     int
+	disconnected int
+}
+
+// NewDrainer returns a Drainer of the agents connected to agentd.
+func NewDrainer() *Drainer {
+	return &Drainer{
+		sessions: map[*Session]struct{}{},
+	}
+}
+
+func (d *Drainer) add(s *Session) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sessions[s] = struct{}{}
+}
+
+func (d *Drainer) remove(s *Session) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.sessions, s)
+}
+
+// Drain starts disconnecting a random selection of the connected agents, spread
+// evenly over the period of the drain. New agent connections are refused
+// while the drain is in progress.
+func (d *Drainer) Drain(drain routers.AgentDrain) (routers.AgentDrainStatus, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return d.status(), ErrDrainInProgress
+	}
+
+	sessions := make([]*Session, 0, len(d.sessions))
+	for s := range d.sessions {
+		sessions = append(sessions, s)
+	}
+	rand.Shuffle(len(sessions), func(i, j int) {
+		sessions[i], sessions[j] = sessions[j], sessions[i]
+	})
+	targeted := int(math.Ceil(float64(len(sessions)) * drain.Percentage / 100))
+	if targeted > len(sessions) {
+		targeted = len(sessions)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.id++
+	d.cancel = cancel
+	d.draining = true
+	d.refuse = drain.RefuseConnections
+	d.retryAfter = time.Duration(drain.RetryAfter) * time.Second
+	d.targeted = targeted
+	d.disconnected = 0
+
+	period := time.Duration(drain.Period) * time.Second
+	go d.run(ctx, d.id, sessions[:targeted], period, d.retryAfter)
+
+	return d.status(), nil
+}
+
+func (d *Drainer) run(ctx context.Context, id int, sessions []*Session, period, retryAfter time.Duration) {
+	defer func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.id == id {
+			d.draining = false
+			d.cancel()
+		}
+	}()
+
+	var interval time.Duration
+	if len(sessions) > 0 {
+		interval = period / time.Duration(len(sessions))
+	}
+	for i, s := range sessions {
+		if i > 0 && interval > 0 {
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return
+			}
+		} else if ctx.Err() != nil {
+			return
+		}
+
+		d.mu.Lock()
+		_, connected := d.sessions[s]
+		if connected && d.id == id {
+			d.disconnected++
+		}
+		d.mu.Unlock()
+		if connected {
+			s.drain(jitter(retryAfter))
+		}
+	}
+	if len(sessions) > 0 {
+		logger.WithField("disconnected", len(sessions)).Warn("finished draining agents")
+	}
+}
+
+// Cancel stops the drain in progress, if any, and accepts the new agent
+// connections again.
+func (d *Drainer) Cancel() routers.AgentDrainStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.id++
+	d.draining = false
+	d.refuse = false
+	return d.status()
+}
+
+// Status returns the progress of the last drain.
+func (d *Drainer) Status() routers.AgentDrainStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.status()
+}
+
+func (d *Drainer) status() routers.AgentDrainStatus {
+	return routers.AgentDrainStatus{
+		Draining:            d.draining,
+		RefusingConnections: d.draining || d.refuse,
+		Connected:           len(d.sessions),
+		Targeted:            d.targeted,
+		Disconnected:        d.disconnected,
+	}
+}
+
+// refusing returns true if the new agent connections must be refused, along
+// with the delay the agents should wait before trying again.
+func (d *Drainer) refusing() (bool, time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.draining && !d.refuse {
+		return false, 0
+	}
+	return true, jitter(d.retryAfter)
+}
+
+// jitter returns a random delay between retryAfter and twice retryAfter.
+func jitter(retryAfter time.Duration) time.Duration {
+	if retryAfter <= 0 {
+		return 0
+	}
+	return retryAfter + time.Duration(rand.Int63n(int64(retryAfter)))
+}
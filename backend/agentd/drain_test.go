@@ -0,0 +1,84 @@
+package agentd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/apid/routers"
+	"github.com/sensu/sensu-go/testing/mocktransport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newDrainerSessions(t *testing.T, d *Drainer, n int) []*mocktransport.MockTransport {
+	t.Helper()
+	conns := make([]*mocktransport.MockTransport, n)
+	for i := range conns {
+		conn := &mocktransport.MockTransport{}
+		conn.On("CloseWithRetry", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			retryAfter := args.Get(0).(time.Duration)
+			assert.GreaterOrEqual(t, retryAfter, 10*time.Second)
+			assert.Less(t, retryAfter, 20*time.Second)
+		})
+		session, err := NewSession(context.Background(), SessionConfig{Conn: conn, Drainer: d})
+		require.NoError(t, err)
+		d.add(session)
+		conns[i] = conn
+	}
+	return conns
+}
+
+func TestDrainer(t *testing.T) {
+	d := NewDrainer()
+	conns := newDrainerSessions(t, d, 4)
+
+	status, err := d.Drain(routers.AgentDrain{Percentage: 50, RetryAfter: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 4, status.Connected)
+	assert.Equal(t, 2, status.Targeted)
+
+	assert.Eventually(t, func() bool {
+		return !d.Status().Draining
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 2, d.Status().Disconnected)
+	drained := 0
+	for _, conn := range conns {
+		for _, call := range conn.Calls {
+			if call.Method == "CloseWithRetry" {
+				drained++
+			}
+		}
+	}
+	assert.Equal(t, 2, drained)
+
+	// The connections are accepted again once the drain is done
+	refuse, _ := d.refusing()
+	assert.False(t, refuse)
+}
+
+func TestDrainerRefusesConnections(t *testing.T) {
+	d := NewDrainer()
+	newDrainerSessions(t, d, 2)
+
+	_, err := d.Drain(routers.AgentDrain{Percentage: 100, Period: 3600, RetryAfter: 10, RefuseConnections: true})
+	require.NoError(t, err)
+	refuse, retryAfter := d.refusing()
+	assert.True(t, refuse)
+	assert.GreaterOrEqual(t, retryAfter, 10*time.Second)
+
+	_, err = d.Drain(routers.AgentDrain{Percentage: 100})
+	assert.Equal(t, ErrDrainInProgress, err)
+
+	// The first agent is disconnected right away, the second one in an hour
+	assert.Eventually(t, func() bool {
+		return d.Status().Disconnected == 1
+	}, time.Second, 10*time.Millisecond)
+	status := d.Cancel()
+	assert.False(t, status.Draining)
+	assert.False(t, status.RefusingConnections)
+	assert.Equal(t, 1, status.Disconnected)
+	refuse, _ = d.refusing()
+	assert.False(t, refuse)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/sensu/sensu-go/agent"
 	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/metrics"
+	"github.com/sensu/sensu-go/backend/quota"
 	"github.com/sensu/sensu-go/backend/ringv2"
 	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
@@ -98,6 +99,22 @@ type Session struct {
 	entityConfig     *entityConfig
 	mu               sync.Mutex
 	subscriptionsMap map[string]subscription
+
+	// labels are the labels of the entity config, once it is received from
+	// the entity watcher
+	labels map[string]string
+
+	// rings is the membership of the agent in the round-robin rings
+	rings ringMembership
+}
+
+// ringMembership records the rings the agent was last added to, and when its
+// membership must be renewed
+type ringMembership struct {
+	mu            sync.Mutex
+	subscriptions []string
+	weight        int
+	renewAt       time.Time
 }
 
 // subscription is used to abstract a message.Subscription and therefore allow
@@ -144,6 +161,12 @@ type SessionConfig struct {
 
 	Marshal   agent.MarshalFunc
 	Unmarshal agent.UnmarshalFunc
+
+	// Quotas limits the rate of the events of the namespace, if not nil.
+	Quotas *quota.Enforcer
+
+	// Drainer can disconnect the session to drain the agents, if not nil.
+	Drainer *Drainer
 }
 
 // NewSession creates a new Session object given the triple of a transport
@@ -316,6 +339,7 @@ func (s *Session) sender() {
 			newSubscriptions := sortSubscriptions(entity.Subscriptions)
 			added, removed := diff(oldSubscriptions, newSubscriptions)
 			s.cfg.Subscriptions = newSubscriptions
+			s.labels = entity.Metadata.Labels
 			s.mu.Unlock()
 			if len(added) > 0 {
 				lager.Debugf("found %d new subscription(s): %v", len(added), added)
@@ -376,6 +400,9 @@ func (s *Session) sender() {
 func (s *Session) Start() (err error) {
 	defer close(s.entityConfig.subscriptions)
 	sessionCounter.WithLabelValues(s.cfg.Namespace).Inc()
+	if s.cfg.Drainer != nil {
+		s.cfg.Drainer.add(s)
+	}
 	s.wg = &sync.WaitGroup{}
 	s.wg.Add(2)
 	s.stopWG.Add(1)
@@ -512,6 +539,21 @@ func (s *Session) Stop() {
 	s.stopWG.Wait()
 }
 
+// drain disconnects the agent right away, asking it to wait for retryAfter
+// before it reconnects, and stops the session.
+func (s *Session) drain(retryAfter time.Duration) {
+	logger.WithFields(logrus.Fields{
+		"agent":       s.cfg.AgentName,
+		"namespace":   s.cfg.Namespace,
+		"retry_after": retryAfter,
+	}).Info("draining agent session")
+	if err := s.conn.CloseWithRetry(retryAfter); err != nil {
+		websocketErrorCounter.WithLabelValues("close", "DrainSession").Inc()
+		logger.WithError(err).Error("error closing session")
+	}
+	s.cancel()
+}
+
 func (s *Session) stop() {
 	defer s.stopWG.Done()
 	defer func() {
@@ -524,6 +566,9 @@ func (s *Session) stop() {
 	defer close(s.checkChannel)
 
 	sessionCounter.WithLabelValues(s.cfg.Namespace).Dec()
+	if s.cfg.Drainer != nil {
+		s.cfg.Drainer.remove(s)
+	}
 
 	topic := messaging.TopicAgentConnectionState
 	err := s.bus.Publish(topic, messaging.AgentNotification{
@@ -578,7 +623,86 @@ func (s *Session) handleKeepalive(_ context.Context, payload []byte) error {
 
 	keepalive.Entity.Subscriptions = corev2.AddEntitySubscription(keepalive.Entity.Name, keepalive.Entity.Subscriptions)
 
-	return s.bus.Publish(messaging.TopicKeepalive, keepalive)
+	if err := s.bus.Publish(messaging.TopicKeepalive, keepalive); err != nil {
+		return err
+	}
+	s.addToRings(keepalive)
+	return nil
+}
+
+// addToRings adds the agent to the rings of its subscriptions that are in use
+// by round-robin checks, and renews its membership before its keepalive times
+// out. The agent is weighted in the rings by the weight label of its entity.
+// The rings are only written to when the agent connects, when its
+// subscriptions or its weight change, or when its membership is about to
+// expire, not on every keepalive.
+func (s *Session) addToRings(keepalive *corev2.Event) {
+	if s.ringPool == nil {
+		return
+	}
+	s.mu.Lock()
+	subscriptions := s.cfg.Subscriptions
+	labels := s.labels
+	s.mu.Unlock()
+	if labels == nil {
+		labels = keepalive.Entity.Labels
+	}
+
+	used := make([]string, 0, len(subscriptions))
+	for _, sub := range subscriptions {
+		if sub == "" || strings.HasPrefix(sub, "entity:") {
+			// Entity subscriptions don't get rings
+			continue
+		}
+		if s.ringPool.Subscribed(ringv2.Path(s.cfg.Namespace, sub)) {
+			used = append(used, sub)
+		}
+	}
+	sort.Strings(used)
+	weight := ringv2.EntityWeight(labels)
+
+	timeout := keepalive.Check.Ttl
+	if timeout <= 0 {
+		timeout = int64(keepalive.Check.Timeout)
+	}
+	if timeout <= 0 {
+		timeout = corev2.DefaultKeepaliveTimeout
+	}
+
+	s.rings.mu.Lock()
+	defer s.rings.mu.Unlock()
+	now := time.Now()
+	if now.Before(s.rings.renewAt) && s.rings.weight == weight {
+		if added, removed := diff(s.rings.subscriptions, used); len(added) == 0 && len(removed) == 0 {
+			return
+		}
+	}
+
+	ctx := ringv2.WeightContext(s.ctx, weight)
+	added := make([]string, 0, len(used))
+	for _, sub := range used {
+		ring := s.ringPool.Get(ringv2.Path(s.cfg.Namespace, sub))
+		if err := ring.Add(ctx, s.cfg.AgentName, timeout); err != nil {
+			sessionErrorCounter.WithLabelValues("ring.Add").Inc()
+			logger.WithFields(logrus.Fields{
+				"agent":        s.cfg.AgentName,
+				"namespace":    s.cfg.Namespace,
+				"subscription": sub,
+			}).WithError(err).Error("unable to add agent to ring")
+			continue
+		}
+		added = append(added, sub)
+	}
+
+	// Renew the membership at least two keepalives before it expires, so a
+	// late keepalive doesn't drop the agent from the rings
+	renewal := timeout - 2*int64(keepalive.Check.Interval)
+	if renewal < timeout/2 {
+		renewal = timeout / 2
+	}
+	s.rings.subscriptions = added
+	s.rings.weight = weight
+	s.rings.renewAt = now.Add(time.Duration(renewal) * time.Second)
 }
 
 // handleEvent is the event message handler.
@@ -610,6 +734,14 @@ func (s *Session) handleEvent(_ context.Context, payload []byte) error {
 		eventBytesSummary.WithLabelValues(metrics.EventTypeLabelMetrics).Observe(float64(len(payload)))
 	}
 
+	if s.cfg.Quotas != nil && !s.cfg.Quotas.AllowEvent(s.cfg.Namespace) {
+		logger.WithFields(logrus.Fields{
+			"agent":     s.cfg.AgentName,
+			"namespace": s.cfg.Namespace,
+		}).Debug("dropping event, the events quota of the namespace is exceeded")
+		return nil
+	}
+
 	return s.bus.Publish(messaging.TopicEventRaw, event)
 }
 
@@ -693,6 +825,12 @@ func (s *Session) unsubscribe(subscriptions []string) {
 		return
 	}
 
+	// Add the agent to the rings again at its next keepalive, should it
+	// subscribe to these subscriptions again
+	s.rings.mu.Lock()
+	s.rings.renewAt = time.Time{}
+	s.rings.mu.Unlock()
+
 	// Remove the ring for every subscription
 	var ringWG sync.WaitGroup
 	for _, sub := range subscriptions {
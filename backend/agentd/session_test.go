@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/sensu-go/agent"
 	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/ringv2"
 	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 	"github.com/sensu/sensu-go/handler"
@@ -593,6 +595,65 @@ func TestSession_unsubscribe(t *testing.T) {
 	}
 }
 
+// countingRing counts the agents added to a ring
+type countingRing struct {
+	ringv2.Interface
+	adds int32
+}
+
+func (r *countingRing) Add(ctx context.Context, value string, keepalive int64) error {
+	atomic.AddInt32(&r.adds, 1)
+	return r.Interface.Add(ctx, value, keepalive)
+}
+
+func TestSession_addToRings(t *testing.T) {
+	rings := map[string]*countingRing{}
+	ringPool := ringv2.NewRingPool(func(path string) ringv2.Interface {
+		ring := &countingRing{Interface: ringv2.NewMemoryRing()}
+		rings[path] = ring
+		return ring
+	})
+	s := &Session{
+		cfg: SessionConfig{
+			AgentName:     "foo",
+			Namespace:     "default",
+			Subscriptions: []string{"linux", "unused", "entity:foo"},
+		},
+		ctx:      context.Background(),
+		ringPool: ringPool,
+	}
+
+	// Only the linux subscription is in use by a round-robin check
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	linux := ringv2.Path("default", "linux")
+	events := ringPool.Subscribe(ctx, linux, ringv2.Subscription{Name: "check", Items: 3, IntervalSchedule: 60})
+	require.NoError(t, ringPool.Get(linux).Add(ctx, "bar", 60))
+
+	keepalive := corev2.FixtureEvent("foo", corev2.KeepaliveCheckName)
+	keepalive.Entity.Labels = map[string]string{ringv2.WeightLabel: "2"}
+	s.addToRings(keepalive)
+
+	// The agent is visited twice per iteration of the ring
+	event := <-events
+	assert.Equal(t, []string{"bar", "foo", "foo"}, event.Values)
+
+	for _, sub := range []string{"unused", "entity:foo"} {
+		empty, err := ringPool.Get(ringv2.Path("default", sub)).IsEmpty(ctx)
+		require.NoError(t, err)
+		assert.True(t, empty, sub)
+	}
+
+	// The membership is not renewed on every keepalive
+	s.addToRings(keepalive)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&rings[linux].adds))
+
+	// But it is when the weight of the agent changes
+	keepalive.Entity.Labels = nil
+	s.addToRings(keepalive)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&rings[linux].adds))
+}
+
 func Test_diff(t *testing.T) {
 	tests := []struct {
 		name        string
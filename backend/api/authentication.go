@@ -4,34 +4,54 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/backend/authentication"
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/backend/authentication/mfa"
 	"github.com/sensu/sensu-go/backend/authentication/providers/basic"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 )
 
+// ErrRevokedToken is returned when a refresh token was revoked, or when its
+// session expired.
+var ErrRevokedToken = errors.New("the refresh token was revoked")
+
 // AuthenticationClient is an API client for authentication.
 type AuthenticationClient struct {
-	auth *authentication.Authenticator
+	auth     *authentication.Authenticator
+	sessions storev2.SessionStore
+	mfa      *mfa.Manager
 }
 
-// NewAuthenticationClient creates a new AuthenticationClient, given a a store
-// and an authenticator.
-func NewAuthenticationClient(auth *authentication.Authenticator) *AuthenticationClient {
+// NewAuthenticationClient creates a new AuthenticationClient, given an
+// authenticator, the store of the sessions and the manager of the
+// multi-factor authentication, which can be nil.
+func NewAuthenticationClient(auth *authentication.Authenticator, sessions storev2.SessionStore, manager *mfa.Manager) *AuthenticationClient {
 	return &AuthenticationClient{
-		auth: auth,
+		auth:     auth,
+		sessions: sessions,
+		mfa:      manager,
 	}
 }
 
 // CreateAccessToken creates a new access token, given a valid username and
-// password.
+// password. The local users enrolled in the multi-factor authentication must
+// also give a one-time password, carried by the context.
 func (a *AuthenticationClient) CreateAccessToken(ctx context.Context, username, password string) (*corev2.Tokens, error) {
 	claims, err := a.auth.Authenticate(ctx, username, password)
 	if err != nil {
 		return nil, corev2.ErrUnauthorized
 	}
 
+	if a.mfa != nil && claims.Provider.ProviderID == basic.Type {
+		if err := a.mfa.Verify(ctx, claims.Subject, mfa.CodeFromContext(ctx)); err != nil {
+			return nil, err
+		}
+	}
+
 	// Add the 'system:users' group to this user
 	claims.Groups = append(claims.Groups, "system:users")
 
@@ -53,6 +73,20 @@ func (a *AuthenticationClient) CreateAccessToken(ctx context.Context, username,
 		return nil, fmt.Errorf("error creating access token: %s", err)
 	}
 
+	// Record the session of the refresh token, so that it can be revoked
+	now := time.Now().Unix()
+	session := &storev2.Session{
+		ID:          refreshClaims.Id,
+		Username:    claims.Subject,
+		Provider:    claims.Provider.ProviderID,
+		IssuedAt:    now,
+		RefreshedAt: now,
+		ExpiresAt:   refreshClaims.ExpiresAt,
+	}
+	if err := a.sessions.AddSession(ctx, session); err != nil {
+		return nil, fmt.Errorf("error recording the session: %s", err)
+	}
+
 	result := &corev2.Tokens{
 		Access:    tokenString,
 		ExpiresAt: claims.ExpiresAt,
@@ -84,11 +118,19 @@ func (a *AuthenticationClient) TestCreds(ctx context.Context, username, password
 //
 // corev2.AccessTokenClaims -> *corev2.Claims
 // corev2.RefreshTokenClaims -> *corev2.Claims
+//
+// The session of the refresh token is revoked.
 func (a *AuthenticationClient) Logout(ctx context.Context) error {
-	return nil
+	refreshClaims, ok := ctx.Value(corev2.RefreshTokenClaims).(*corev2.Claims)
+	if !ok || refreshClaims.Id == "" {
+		return nil
+	}
+	_, err := a.sessions.RevokeSessions(ctx, refreshClaims.Subject, refreshClaims.Id)
+	return err
 }
 
-// RefreshAccessToken refreshes an access token. The context must carry the
+// RefreshAccessToken refreshes an access token, and renews the refresh token
+// of its session. The context must carry the
 // user's access and refresh claims, as well as the previous token value,
 // with the following context key-values:
 //
@@ -106,18 +148,29 @@ func (a *AuthenticationClient) RefreshAccessToken(ctx context.Context) (*corev2.
 	}
 
 	// Get the refresh token claims
-	if value := ctx.Value(corev2.RefreshTokenClaims); value == nil {
+	var refreshClaims *corev2.Claims
+	if value := ctx.Value(corev2.RefreshTokenClaims); value != nil {
+		refreshClaims = value.(*corev2.Claims)
+	} else {
 		return nil, corev2.ErrInvalidToken
 	}
 
 	// Get the refresh token string
-	var refreshTokenString string
-	if value := ctx.Value(corev2.RefreshTokenString); value != nil {
-		refreshTokenString = value.(string)
-	} else {
+	if value := ctx.Value(corev2.RefreshTokenString); value == nil {
 		return nil, corev2.ErrInvalidToken
 	}
 
+	// The refresh tokens issued before the sessions were recorded never
+	// expire and can't be revoked, so they are rejected
+	if refreshClaims.Id == "" || refreshClaims.ExpiresAt == 0 {
+		return nil, ErrRevokedToken
+	}
+	if revoked, err := a.sessions.IsTokenRevoked(ctx, refreshClaims.Id); err != nil {
+		return nil, err
+	} else if revoked {
+		return nil, ErrRevokedToken
+	}
+
 	// Ensure backward compatibility by filling the provider claims if missing
 	if accessClaims.Provider.ProviderID == "" || accessClaims.Provider.UserID == "" {
 		accessClaims.Provider.ProviderID = basic.Type
@@ -144,6 +197,21 @@ func (a *AuthenticationClient) RefreshAccessToken(ctx context.Context) (*corev2.
 		claims.Issuer = issuer.(string)
 	}
 
+	// Renew the refresh token of the session, unless it was revoked
+	renewedClaims := &corev2.Claims{StandardClaims: corev2.StandardClaims(refreshClaims.Subject)}
+	renewedClaims.Id = refreshClaims.Id
+	_, refreshTokenString, err := jwt.RefreshToken(renewedClaims)
+	if err != nil {
+		return nil, err
+	}
+	err = a.sessions.RefreshSession(ctx, renewedClaims.Id, time.Now().Unix(), renewedClaims.ExpiresAt)
+	if err != nil {
+		if _, ok := err.(*store.ErrNotFound); ok {
+			return nil, ErrRevokedToken
+		}
+		return nil, err
+	}
+
 	// Issue a new access token
 	_, accessTokenString, err := jwt.AccessToken(claims)
 	if err != nil {
@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/backend/authentication"
 	"github.com/sensu/sensu-go/backend/authentication/bcrypt"
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/backend/authentication/mfa"
 	"github.com/sensu/sensu-go/backend/authentication/providers/basic"
+	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 	"github.com/sensu/sensu-go/testing/mockstore"
 	"github.com/stretchr/testify/mock"
@@ -91,7 +94,9 @@ func TestCreateAccessToken(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
 			store := test.Store()
-			authn := NewAuthenticationClient(test.Authenticator(store))
+			sessions := new(mockstore.SessionStore)
+			sessions.On("AddSession", mock.Anything, mock.Anything).Return(nil)
+			authn := NewAuthenticationClient(test.Authenticator(store), sessions, nil)
 			tokens, err := authn.CreateAccessToken(test.Context(), test.Username, test.Password)
 			if test.WantError && err == nil {
 				if test.Error != nil && test.Error != err {
@@ -106,11 +111,56 @@ func TestCreateAccessToken(t *testing.T) {
 				if err := tokens.Validate(); err != nil {
 					t.Fatal(err)
 				}
+				sessions.AssertCalled(t, "AddSession", mock.Anything, mock.MatchedBy(func(session *storev2.Session) bool {
+					return session.Username == test.Username && session.ID != "" && session.ExpiresAt > session.IssuedAt
+				}))
 			}
 		})
 	}
 }
 
+func TestCreateAccessTokenMFA(t *testing.T) {
+	store := &mockstore.V2MockStore{}
+	user := corev2.FixtureUser("foo")
+	user.PasswordHash, _ = bcrypt.HashPassword("P@ssw0rd!")
+	cs := new(mockstore.ConfigStore)
+	store.On("GetConfigStore").Return(cs)
+	cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*corev2.User]{Value: user}, nil)
+	sessions := new(mockstore.SessionStore)
+	sessions.On("AddSession", mock.Anything, mock.Anything).Return(nil)
+
+	secret, err := mfa.NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mfaStore := new(mockstore.MFAStore)
+	mfaStore.On("GetMFAEnrollment", mock.Anything, "foo").
+		Return(&storev2.MFAEnrollment{Username: "foo", Secret: secret, Confirmed: true}, nil)
+	mfaStore.On("UseMFACounter", mock.Anything, "foo", mock.Anything).Return(true, nil)
+	authn := NewAuthenticationClient(defaultAuth(store), sessions, mfa.NewManager(mfaStore, false))
+
+	// The one-time password is required
+	if _, err := authn.CreateAccessToken(context.Background(), "foo", "P@ssw0rd!"); err != mfa.ErrRequired {
+		t.Fatalf("bad error: got %v, want %v", err, mfa.ErrRequired)
+	}
+	ctx := mfa.ContextWithCode(context.Background(), "12345")
+	if _, err := authn.CreateAccessToken(ctx, "foo", "P@ssw0rd!"); err != mfa.ErrInvalidCode {
+		t.Fatalf("bad error: got %v, want %v", err, mfa.ErrInvalidCode)
+	}
+
+	code, err := mfa.Code(secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := authn.CreateAccessToken(mfa.ContextWithCode(context.Background(), code), "foo", "P@ssw0rd!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tokens.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestTestCreds(t *testing.T) {
 	mockError := errors.New("error")
 
@@ -168,7 +218,7 @@ func TestTestCreds(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
 			store := test.Store()
-			authn := NewAuthenticationClient(test.Authenticator(store))
+			authn := NewAuthenticationClient(test.Authenticator(store), new(mockstore.SessionStore), nil)
 			err := authn.TestCreds(test.Context(), test.Username, test.Password)
 
 			if test.WantError && test.Error != err {
@@ -183,31 +233,74 @@ func TestTestCreds(t *testing.T) {
 }
 
 func TestRefreshAccessToken(t *testing.T) {
+	userStore := func() storev2.Interface {
+		st := &mockstore.V2MockStore{}
+		user := &corev2.User{Username: "foo"}
+		cs := new(mockstore.ConfigStore)
+		st.On("GetConfigStore").Return(cs)
+		cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*corev2.User]{Value: user}, nil)
+		return st
+	}
+	refreshContext := func(claims *corev2.Claims) context.Context {
+		ctx := contextWithClaims(claims)
+		_, refreshTokenString, _ := jwt.RefreshToken(ctx.Value(corev2.RefreshTokenClaims).(*corev2.Claims))
+		ctx = context.WithValue(ctx, corev2.RefreshTokenString, refreshTokenString)
+		return ctx
+	}
+
 	tests := []struct {
 		Name          string
 		Store         func() storev2.Interface
+		Sessions      func(*mockstore.SessionStore)
 		Authenticator func(storev2.Interface) *authentication.Authenticator
 		Context       func(*corev2.Claims) context.Context
 		WantError     bool
 		Error         error
 	}{
 		{
-			Name: "success",
-			Store: func() storev2.Interface {
-				st := &mockstore.V2MockStore{}
-				user := &corev2.User{Username: "foo"}
-				cs := new(mockstore.ConfigStore)
-				st.On("GetConfigStore").Return(cs)
-				cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*corev2.User]{Value: user}, nil)
-				return st
+			Name:  "success",
+			Store: userStore,
+			Sessions: func(s *mockstore.SessionStore) {
+				s.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil)
+				s.On("RefreshSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			},
+			Authenticator: defaultAuth,
+			Context:       refreshContext,
+		},
+		{
+			Name:  "revoked token",
+			Store: userStore,
+			Sessions: func(s *mockstore.SessionStore) {
+				s.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(true, nil)
 			},
 			Authenticator: defaultAuth,
+			Context:       refreshContext,
+			WantError:     true,
+			Error:         ErrRevokedToken,
+		},
+		{
+			Name:  "expired session",
+			Store: userStore,
+			Sessions: func(s *mockstore.SessionStore) {
+				s.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil)
+				s.On("RefreshSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&store.ErrNotFound{})
+			},
+			Authenticator: defaultAuth,
+			Context:       refreshContext,
+			WantError:     true,
+			Error:         ErrRevokedToken,
+		},
+		{
+			Name:          "refresh token without expiration",
+			Store:         userStore,
+			Sessions:      func(s *mockstore.SessionStore) {},
+			Authenticator: defaultAuth,
 			Context: func(claims *corev2.Claims) context.Context {
 				ctx := contextWithClaims(claims)
-				_, refreshTokenString, _ := jwt.RefreshToken(ctx.Value(corev2.RefreshTokenClaims).(*corev2.Claims))
-				ctx = context.WithValue(ctx, corev2.RefreshTokenString, refreshTokenString)
-				return ctx
+				return context.WithValue(ctx, corev2.RefreshTokenString, "legacy")
 			},
+			WantError: true,
+			Error:     ErrRevokedToken,
 		},
 	}
 
@@ -216,15 +309,46 @@ func TestRefreshAccessToken(t *testing.T) {
 			claims := corev2.FixtureClaims("foo", nil)
 			ctx := test.Context(claims)
 			store := test.Store()
+			sessions := new(mockstore.SessionStore)
+			test.Sessions(sessions)
 			authenticator := test.Authenticator(store)
-			auth := NewAuthenticationClient(authenticator)
-			_, err := auth.RefreshAccessToken(ctx)
+			auth := NewAuthenticationClient(authenticator, sessions, nil)
+			tokens, err := auth.RefreshAccessToken(ctx)
 			if err == nil && test.WantError {
 				t.Fatal("got non-nil error")
 			}
 			if err != nil && !test.WantError {
 				t.Fatal(err)
 			}
+			if test.Error != nil && err != test.Error {
+				t.Fatalf("bad error: got %v, want %v", err, test.Error)
+			}
+			if tokens != nil {
+				// The renewed refresh token identifies the same session
+				token, err := jwt.ValidateToken(tokens.Refresh)
+				if err != nil {
+					t.Fatal(err)
+				}
+				refreshClaims := ctx.Value(corev2.RefreshTokenClaims).(*corev2.Claims)
+				if got, want := token.Claims.(*corev2.Claims).Id, refreshClaims.Id; got != want {
+					t.Errorf("bad session: got %s, want %s", got, want)
+				}
+			}
 		})
 	}
 }
+
+func TestLogout(t *testing.T) {
+	claims := corev2.FixtureClaims("foo", nil)
+	ctx := contextWithClaims(claims)
+	refreshClaims := ctx.Value(corev2.RefreshTokenClaims).(*corev2.Claims)
+	refreshClaims.Id = "session"
+
+	sessions := new(mockstore.SessionStore)
+	sessions.On("RevokeSessions", mock.Anything, "foo", []string{"session"}).Return(1, nil)
+	auth := NewAuthenticationClient(defaultAuth(defaultStore()), sessions, nil)
+	if err := auth.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+	sessions.AssertExpectations(t)
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/sensu/sensu-go/backend/messaging"
+)
+
+// eventSubscriptionBufferSize is the number of events buffered for a
+// subscriber. Subscribers that fall behind are unsubscribed.
+const eventSubscriptionBufferSize = 100
+
+// eventSubscriptionID distinguishes the bus consumers of concurrent
+// subscriptions.
+var eventSubscriptionID int64
+
+// Subscriber is an interface that represents the subscription side of the
+// message bus concept.
+type Subscriber interface {
+	Subscribe(topic string, consumer string, sub messaging.Subscriber) (messaging.Subscription, error)
+}
+
+// EventSubscriptionClient is an API client for the live events published by
+// eventd.
+type EventSubscriptionClient struct {
+	bus  Subscriber
+	auth authorization.Authorizer
+}
+
+// NewEventSubscriptionClient creates a new EventSubscriptionClient, given a
+// bus and an authorizer.
+func NewEventSubscriptionClient(bus Subscriber, auth authorization.Authorizer) *EventSubscriptionClient {
+	return &EventSubscriptionClient{
+		bus:  bus,
+		auth: auth,
+	}
+}
+
+// SubscribeEvents subscribes to the events of the namespace of the context,
+// as they are processed by eventd, if authorized. Previous is set for the
+// events that replaced a stored event. The returned channel is closed when
+// the context is done, or when the subscriber falls behind.
+func (c *EventSubscriptionClient) SubscribeEvents(ctx context.Context) (<-chan *messaging.EventWithPrevious, error) {
+	attrs := eventListAttributes(ctx)
+	if err := authorize(ctx, c.auth, attrs); err != nil {
+		return nil, err
+	}
+
+	sub := make(messaging.ChanSubscriber, eventSubscriptionBufferSize)
+	consumer := fmt.Sprintf("api-event-subscription-%d", atomic.AddInt64(&eventSubscriptionID, 1))
+	subscription, err := c.bus.Subscribe(messaging.TopicEvent, consumer, sub)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan *messaging.EventWithPrevious, eventSubscriptionBufferSize)
+	go func() {
+		defer close(events)
+		defer func() {
+			if err := subscription.Cancel(); err != nil {
+				logger.WithError(err).Error("failed to cancel event subscription")
+			}
+			// The bus recovers from sends on the closed channel
+			close(sub)
+		}()
+		for {
+			select {
+			case msg := <-sub:
+				event := eventWithPrevious(msg)
+				if event == nil || event.Entity == nil || event.Entity.Namespace != attrs.Namespace {
+					continue
+				}
+				select {
+				case events <- event:
+				default:
+					logger.WithField("consumer", consumer).Warn("event subscriber fell behind, unsubscribing")
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func eventWithPrevious(msg interface{}) *messaging.EventWithPrevious {
+	switch msg := msg.(type) {
+	case *corev2.Event:
+		return &messaging.EventWithPrevious{Event: msg}
+	case *messaging.EventWithPrevious:
+		return msg
+	}
+	return nil
+}
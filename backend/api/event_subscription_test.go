@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/sensu/sensu-go/backend/messaging"
+)
+
+func TestSubscribeEvents(t *testing.T) {
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer bus.Stop()
+
+	auth := &mockAuth{
+		attrs: map[authorization.AttributesKey]bool{
+			authorization.AttributesKey{
+				APIGroup:   "core",
+				APIVersion: "v2",
+				Namespace:  "default",
+				Resource:   "events",
+				UserName:   "legit",
+				Verb:       "list",
+			}: true,
+		},
+	}
+	client := NewEventSubscriptionClient(bus, auth)
+
+	if _, err := client.SubscribeEvents(contextWithUser(defaultContext(), "haxor", nil)); err == nil {
+		t.Fatal("expected an authorization error")
+	}
+
+	ctx, cancel := context.WithCancel(contextWithUser(defaultContext(), "legit", nil))
+	defer cancel()
+	events, err := client.SubscribeEvents(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other := corev2.FixtureEvent("other", "check")
+	other.Entity.Namespace = "other"
+	previous := corev2.FixtureEvent("entity", "check")
+	event := corev2.FixtureEvent("entity", "check")
+	event.Check.Status = 2
+	for _, msg := range []interface{}{
+		other,
+		&messaging.EventWithPrevious{Event: event, Previous: previous},
+		previous,
+	} {
+		if err := bus.Publish(messaging.TopicEvent, msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case got := <-events:
+		if got.Event != event || got.Previous != previous {
+			t.Fatalf("unexpected event: %v", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the event")
+	}
+	select {
+	case got := <-events:
+		if got.Event != previous || got.Previous != nil {
+			t.Fatalf("unexpected event: %v", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the event")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		for ok {
+			_, ok = <-events
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the subscription to end")
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/sensu/sensu-go/backend/authorization"
 	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/backend/store/v2/wrap"
 )
 
 type RBACVerb string
@@ -76,7 +77,7 @@ func (g *GenericClient) Create(ctx context.Context, value corev3.Resource) error
 	if err := g.validateConfig(); err != nil {
 		return err
 	}
-	if err := value.Validate(); err != nil {
+	if err := wrap.Validate(value); err != nil {
 		return err
 	}
 	if err := g.Authorize(ctx, "create", value.GetMetadata().Name); err != nil {
@@ -133,7 +134,7 @@ func (g *GenericClient) Update(ctx context.Context, value corev3.Resource) error
 	if err := g.validateConfig(); err != nil {
 		return err
 	}
-	if err := value.Validate(); err != nil {
+	if err := wrap.Validate(value); err != nil {
 		return err
 	}
 	if err := g.Authorize(ctx, "update", value.GetMetadata().Name); err != nil {
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication"
+	"github.com/sensu/sensu-go/backend/authentication/mfa"
+	"github.com/sensu/sensu-go/backend/authentication/providers/basic"
+)
+
+// MFAClient is an API client for the enrollment of the local users in the
+// multi-factor authentication. The users are authenticated with their
+// password, so that they can enroll before being able to log in.
+type MFAClient struct {
+	auth    *authentication.Authenticator
+	manager *mfa.Manager
+}
+
+// NewMFAClient creates a new MFAClient, given an authenticator and the
+// manager of the enrollments.
+func NewMFAClient(auth *authentication.Authenticator, manager *mfa.Manager) *MFAClient {
+	return &MFAClient{
+		auth:    auth,
+		manager: manager,
+	}
+}
+
+// Enroll enrolls a local user in the multi-factor authentication, given its
+// password.
+func (m *MFAClient) Enroll(ctx context.Context, username, password string) (*mfa.Enrollment, error) {
+	if err := m.authenticate(ctx, username, password); err != nil {
+		return nil, err
+	}
+	return m.manager.Enroll(ctx, username)
+}
+
+// Confirm confirms the enrollment of a local user, given its password and a
+// one-time password.
+func (m *MFAClient) Confirm(ctx context.Context, username, password, code string) error {
+	if err := m.authenticate(ctx, username, password); err != nil {
+		return err
+	}
+	return m.manager.Confirm(ctx, username, code)
+}
+
+// Disable removes the enrollment of a local user, given its password and a
+// one-time password or a recovery code.
+func (m *MFAClient) Disable(ctx context.Context, username, password, code string) error {
+	if err := m.authenticate(ctx, username, password); err != nil {
+		return err
+	}
+	return m.manager.Disable(ctx, username, code)
+}
+
+// authenticate authenticates a user against the basic provider, the only one
+// whose users can enroll.
+func (m *MFAClient) authenticate(ctx context.Context, username, password string) error {
+	provider, ok := m.auth.Providers()[basic.Type]
+	if !ok {
+		return corev2.ErrUnauthorized
+	}
+	if _, err := provider.Authenticate(ctx, username, password); err != nil {
+		return corev2.ErrUnauthorized
+	}
+	return nil
+}
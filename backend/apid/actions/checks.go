@@ -9,6 +9,7 @@ import (
 	"github.com/sensu/sensu-go/backend/queue"
 	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/backend/store/v2/wrap"
 	utilstrings "github.com/sensu/sensu-go/util/strings"
 )
 
@@ -115,7 +116,7 @@ func (a CheckController) findAndUpdateCheckConfig(
 	}
 
 	// Validate
-	if err := check.Validate(); err != nil {
+	if err := wrap.Validate(check); err != nil {
 		return NewError(InvalidArgument, err)
 	}
 
@@ -3,9 +3,11 @@ package actions
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/apid/request"
 	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 )
@@ -39,9 +41,18 @@ func (c EntityController) Find(ctx context.Context, id string) (*corev2.Entity,
 
 // List returns resources available to the viewer.
 func (c EntityController) List(ctx context.Context, pred *store.SelectionPredicate) ([]corev3.Resource, error) {
+	// propagate selector from request context, so that the store can filter
+	// the entities by their labels
+	if sel := request.SelectorFromContext(ctx); sel != nil {
+		ctx = storev2.EntityConfigContextWithSelector(ctx, sel)
+	}
+
 	// Fetch from store
 	results, err := c.store.GetEntityStore().GetEntities(ctx, pred)
 	if err != nil {
+		if _, ok := err.(*store.ErrNotValid); ok {
+			return nil, NewError(InvalidArgument, err)
+		}
 		return nil, NewError(InternalErr, err)
 	}
 
@@ -127,3 +138,98 @@ func (c EntityController) CreateOrReplace(ctx context.Context, entity corev2.Ent
 
 	return nil
 }
+
+// Undelete restores an entity deleted recently, along with its state. It
+// returns an error if the store cannot restore the deleted entities, or if
+// there is no such deleted entity.
+func (c EntityController) Undelete(ctx context.Context, id string) error {
+	undeleter, ok := c.store.GetEntityStore().(store.EntityUndeleter)
+	if !ok {
+		return NewErrorf(PreconditionFailed, "the entity store cannot restore deleted entities")
+	}
+	if err := undeleter.UndeleteEntity(ctx, id); err != nil {
+		switch err.(type) {
+		case *store.ErrNotFound:
+			return NewErrorf(NotFound)
+		case *store.ErrNotValid:
+			return NewError(InvalidArgument, err)
+		default:
+			return NewError(InternalErr, err)
+		}
+	}
+	return nil
+}
+
+// History returns the snapshots of the system of the entity indicated by the
+// supplied id, most recent first.
+func (c EntityController) History(ctx context.Context, id string) ([]*storev2.EntityStateSnapshot, error) {
+	snapshots, err := c.store.GetEntityStateHistoryStore().GetEntityStateHistory(ctx, corev2.ContextNamespace(ctx), id)
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+	return snapshots, nil
+}
+
+// BulkEntityResult is the outcome of storing one of the entities of a bulk
+// request.
+type BulkEntityResult struct {
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+// BulkCreateOrReplace creates or replaces many proxy entities at once. The
+// entities are written with the store's batch upsert path when it has one.
+// It returns one result per entity, in the same order as the entities; an
+// invalid entity does not prevent the others from being stored.
+func (c EntityController) BulkCreateOrReplace(ctx context.Context, entities []*corev2.Entity) []BulkEntityResult {
+	results := make([]BulkEntityResult, len(entities))
+	valid := make([]*corev2.Entity, 0, len(entities))
+	indexes := make([]int, 0, len(entities))
+	namespace := corev2.ContextNamespace(ctx)
+
+	for i, entity := range entities {
+		results[i].Name = entity.Name
+		if entity.Namespace == "" {
+			entity.Namespace = namespace
+		}
+		if err := validateBulkEntity(entity, namespace); err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		valid = append(valid, entity)
+		indexes = append(indexes, i)
+	}
+
+	var errs []error
+	if updater, ok := c.store.GetEntityStore().(store.EntityBatchUpdater); ok {
+		errs = updater.UpdateEntities(ctx, valid)
+	} else {
+		errs = make([]error, len(valid))
+		for i, entity := range valid {
+			errs[i] = c.store.GetEntityStore().UpdateEntity(ctx, entity)
+		}
+	}
+	for i, err := range errs {
+		if err != nil {
+			results[indexes[i]].Error = err.Error()
+		}
+	}
+
+	return results
+}
+
+func validateBulkEntity(entity *corev2.Entity, namespace string) error {
+	if err := entity.Validate(); err != nil {
+		return err
+	}
+	if entity.EntityClass != corev2.EntityProxyClass {
+		return errors.New("only proxy entities can be imported in bulk")
+	}
+	if entity.Labels[corev2.ManagedByLabel] == "sensu-agent" {
+		return errors.New("entity is managed by its agent")
+	}
+	if entity.Namespace != namespace {
+		return fmt.Errorf("entity namespace %q does not match the request namespace %q", entity.Namespace, namespace)
+	}
+	return nil
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 	"github.com/sensu/sensu-go/testing/mockstore"
 	"github.com/sensu/sensu-go/testing/testutil"
 )
@@ -359,3 +361,142 @@ func TestEntityCreateOrReplace(t *testing.T) {
 		})
 	}
 }
+
+func TestEntityBulkCreateOrReplace(t *testing.T) {
+	ctx := testutil.NewContext(
+		testutil.ContextWithNamespace("default"),
+	)
+
+	proxyEntity := func(name string) *corev2.Entity {
+		entity := corev2.FixtureEntity(name)
+		entity.EntityClass = corev2.EntityProxyClass
+		return entity
+	}
+
+	agentEntity := corev2.FixtureEntity("agent")
+	agentEntity.EntityClass = corev2.EntityAgentClass
+
+	managedEntity := proxyEntity("managed")
+	managedEntity.Labels = map[string]string{corev2.ManagedByLabel: "sensu-agent"}
+
+	otherNamespace := proxyEntity("other")
+	otherNamespace.Namespace = "acme"
+
+	badEntity := proxyEntity("bad")
+	badEntity.Name = ""
+
+	unnamespaced := proxyEntity("unnamespaced")
+	unnamespaced.Namespace = ""
+
+	entities := []*corev2.Entity{
+		proxyEntity("foo"),
+		agentEntity,
+		managedEntity,
+		otherNamespace,
+		badEntity,
+		proxyEntity("storefail"),
+		unnamespaced,
+	}
+
+	store := &mockstore.MockStore{}
+	storev2 := new(mockstore.V2MockStore)
+	storev2.On("GetEntityStore").Return(store)
+	store.On("UpdateEntity", mock.Anything, mock.MatchedBy(func(e *corev2.Entity) bool {
+		return e.Name == "storefail"
+	})).Return(errors.New("dunno"))
+	store.On("UpdateEntity", mock.Anything, mock.Anything).Return(nil)
+
+	results := NewEntityController(storev2).BulkCreateOrReplace(ctx, entities)
+
+	assert := assert.New(t)
+	if !assert.Len(results, len(entities)) {
+		return
+	}
+	assert.Equal("foo", results[0].Name)
+	assert.Empty(results[0].Error)
+	assert.NotEmpty(results[1].Error)
+	assert.NotEmpty(results[2].Error)
+	assert.NotEmpty(results[3].Error)
+	assert.NotEmpty(results[4].Error)
+	assert.Equal("dunno", results[5].Error)
+	assert.Empty(results[6].Error)
+	assert.Equal("default", unnamespaced.Namespace)
+	store.AssertNumberOfCalls(t, "UpdateEntity", 3)
+}
+
+type undeleterStore struct {
+	*mockstore.MockStore
+}
+
+func (s undeleterStore) UndeleteEntity(ctx context.Context, name string) error {
+	args := s.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (s undeleterStore) PurgeDeletedEntities(ctx context.Context, before time.Time) (int64, error) {
+	args := s.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestEntityUndelete(t *testing.T) {
+	ctx := testutil.NewContext(
+		testutil.ContextWithNamespace("default"),
+	)
+
+	entityStore := undeleterStore{MockStore: &mockstore.MockStore{}}
+	storev2 := new(mockstore.V2MockStore)
+	storev2.On("GetEntityStore").Return(entityStore)
+	entityStore.On("UndeleteEntity", mock.Anything, "foo").Return(nil)
+	entityStore.On("UndeleteEntity", mock.Anything, "bar").Return(&store.ErrNotFound{Key: "bar"})
+	entityStore.On("UndeleteEntity", mock.Anything, "baz").Return(errors.New("dunno"))
+
+	controller := NewEntityController(storev2)
+	assert := assert.New(t)
+	assert.NoError(controller.Undelete(ctx, "foo"))
+
+	err := controller.Undelete(ctx, "bar")
+	inferErr, ok := err.(Error)
+	if assert.True(ok) {
+		assert.Equal(NotFound, inferErr.Code)
+	}
+
+	err = controller.Undelete(ctx, "baz")
+	inferErr, ok = err.(Error)
+	if assert.True(ok) {
+		assert.Equal(InternalErr, inferErr.Code)
+	}
+
+	// The stores which cannot restore deleted entities are not supported
+	unsupported := new(mockstore.V2MockStore)
+	unsupported.On("GetEntityStore").Return(&mockstore.MockStore{})
+	err = NewEntityController(unsupported).Undelete(ctx, "foo")
+	inferErr, ok = err.(Error)
+	if assert.True(ok) {
+		assert.Equal(PreconditionFailed, inferErr.Code)
+	}
+}
+
+func TestEntityHistory(t *testing.T) {
+	ctx := testutil.NewContext(
+		testutil.ContextWithNamespace("default"),
+	)
+
+	history := new(mockstore.EntityStateHistoryStore)
+	s := new(mockstore.V2MockStore)
+	s.On("GetEntityStateHistoryStore").Return(history)
+	snapshots := []*storev2.EntityStateSnapshot{{Namespace: "default", Entity: "foo", Timestamp: 1}}
+	history.On("GetEntityStateHistory", mock.Anything, "default", "foo").Return(snapshots, nil)
+	history.On("GetEntityStateHistory", mock.Anything, "default", "bar").Return([]*storev2.EntityStateSnapshot(nil), errors.New("dunno"))
+
+	controller := NewEntityController(s)
+	assert := assert.New(t)
+	got, err := controller.History(ctx, "foo")
+	assert.NoError(err)
+	assert.Equal(snapshots, got)
+
+	_, err = controller.History(ctx, "bar")
+	inferErr, ok := err.(Error)
+	if assert.True(ok) {
+		assert.Equal(InternalErr, inferErr.Code)
+	}
+}
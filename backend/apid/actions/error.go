@@ -1,6 +1,15 @@
 package actions
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
 
 //
 // Following defines error type w/ error codes. Helpful for
@@ -55,6 +64,14 @@ const (
 
 	// Gone indicates that an API that was once supported but no longer is.
 	Gone
+
+	// ResourceExhausted indicates that the viewer has exhausted its quota, eg.
+	// the number of API requests it is allowed to make per second.
+	ResourceExhausted
+
+	// Unavailable indicates that the service cannot perform the action for
+	// now, eg. because the API is in read-only maintenance mode.
+	Unavailable
 )
 
 // Default error messages if not message is provided.
@@ -69,6 +86,95 @@ var standardErrorMessages = map[ErrCode]string{
 	PreconditionFailed: "precondition failed",
 	DeadlineExceeded:   "deadline exceeded",
 	Gone:               "this action is no longer supported",
+	ResourceExhausted:  "too many requests",
+	Unavailable:        "service unavailable",
+}
+
+// Stable, machine-readable names of the error codes. Clients should rely on
+// these rather than on error messages, which may change between releases.
+var errorReasons = map[ErrCode]string{
+	InternalErr:        "internal",
+	InvalidArgument:    "invalid_argument",
+	NotFound:           "not_found",
+	AlreadyExistsErr:   "already_exists",
+	PermissionDenied:   "permission_denied",
+	Unauthenticated:    "unauthenticated",
+	PaymentRequired:    "payment_required",
+	PreconditionFailed: "precondition_failed",
+	DeadlineExceeded:   "deadline_exceeded",
+	Gone:               "gone",
+	ResourceExhausted:  "resource_exhausted",
+	Unavailable:        "unavailable",
+}
+
+// String returns the machine-readable name of the code, eg. "not_found".
+func (c ErrCode) String() string {
+	if reason, ok := errorReasons[c]; ok {
+		return reason
+	}
+	return fmt.Sprintf("unknown(%d)", uint32(c))
+}
+
+// Retryable returns true if an operation that failed with the code could
+// succeed if tried again without modification. Internal errors are not,
+// unless they were caused by a transient failure; see Error.Retryable.
+func (c ErrCode) Retryable() bool {
+	return c == DeadlineExceeded || c == ResourceExhausted || c == Unavailable
+}
+
+// isTransient returns true if the error was caused by a failure that is
+// expected to go away on its own, eg. a lost database connection or a
+// serialization failure between concurrent transactions.
+func isTransient(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "40001", pgErr.Code == "40P01":
+			// serialization_failure, deadlock_detected
+			return true
+		case strings.HasPrefix(pgErr.Code, "08"):
+			// connection_exception
+			return true
+		}
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
+// FieldViolation describes why a single field of a request was rejected.
+type FieldViolation struct {
+	// Field is the path of the offending field, eg. "metadata.namespace".
+	Field string `json:"field"`
+	// Description explains what is wrong with the field.
+	Description string `json:"description"`
+}
+
+// FieldError is an error caused by the value of a single field. When given to
+// NewError, it is reported as a field violation of the resulting Error.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+// NewFieldError returns a new FieldError for the given field.
+func NewFieldError(field string, err error) *FieldError {
+	return &FieldError{Field: field, Err: err}
+}
+
+// Error method implements error interface
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
 }
 
 // Error describes an issue that ocurred while performing the action.
@@ -79,6 +185,11 @@ type Error struct {
 	// Message is a developer / operator friendly message briefly describing what
 	// occurred.
 	Message string
+	// Details optionally lists the fields of the request that were rejected.
+	Details []FieldViolation
+
+	// transient is set when the error was caused by a transient failure.
+	transient bool
 }
 
 // Error method implements error interface
@@ -86,9 +197,57 @@ func (err Error) Error() string {
 	return fmt.Sprintf("error: code = %d desc = %s", err.Code, err.Message)
 }
 
-// NewError returns a new Error given existing error and code.
+// ErrorBody is the representation of an Error in API responses.
+type ErrorBody struct {
+	// Message is a human readable description of the error.
+	Message string `json:"message"`
+	// Code is the numeric value of the error code, kept for compatibility.
+	Code uint32 `json:"code"`
+	// Reason is the stable, machine-readable name of the error code.
+	Reason string `json:"reason"`
+	// Retryable indicates whether the request could succeed if sent again.
+	Retryable bool `json:"retryable"`
+	// Details lists the fields of the request that were rejected, if any.
+	Details []FieldViolation `json:"details,omitempty"`
+	// RequestID correlates the error with the backend logs.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Retryable returns true if the action could succeed if tried again without
+// modification, either because of its code or because it was caused by a
+// transient failure.
+func (err Error) Retryable() bool {
+	return err.Code.Retryable() || err.transient
+}
+
+// Body returns the API representation of the error.
+func (err Error) Body(requestID string) ErrorBody {
+	return ErrorBody{
+		Message:   err.Message,
+		Code:      uint32(err.Code),
+		Reason:    err.Code.String(),
+		Retryable: err.Retryable(),
+		Details:   err.Details,
+		RequestID: requestID,
+	}
+}
+
+// NewError returns a new Error given existing error and code. If err is, or
+// wraps, a FieldError, it is reported in the details of the Error.
 func NewError(code ErrCode, err error) Error {
-	return Error{Code: code, Message: err.Error()}
+	if actionErr, ok := err.(Error); ok {
+		actionErr.Code = code
+		return actionErr
+	}
+	result := Error{Code: code, Message: err.Error(), transient: isTransient(err)}
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		result.Details = []FieldViolation{{
+			Field:       fieldErr.Field,
+			Description: fieldErr.Err.Error(),
+		}}
+	}
+	return result
 }
 
 // NewErrorf returns a new Error given message and code.
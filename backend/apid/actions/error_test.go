@@ -0,0 +1,77 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewErrorFieldDetails(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", NewFieldError("metadata.name", errors.New("bad name")))
+	actionErr := NewError(InvalidArgument, err)
+	assert.Equal(t, "wrapped: bad name", actionErr.Message)
+	assert.Equal(t, []FieldViolation{{Field: "metadata.name", Description: "bad name"}}, actionErr.Details)
+
+	// Re-coding an existing Error keeps its message and details
+	recoded := NewError(PreconditionFailed, actionErr)
+	assert.Equal(t, PreconditionFailed, recoded.Code)
+	assert.Equal(t, actionErr.Message, recoded.Message)
+	assert.Equal(t, actionErr.Details, recoded.Details)
+
+	assert.Empty(t, NewError(InternalErr, errors.New("boom")).Details)
+}
+
+func TestErrorBody(t *testing.T) {
+	body := NewErrorf(DeadlineExceeded).Body("abc")
+	assert.Equal(t, ErrorBody{
+		Message:   "deadline exceeded",
+		Code:      uint32(DeadlineExceeded),
+		Reason:    "deadline_exceeded",
+		Retryable: true,
+		RequestID: "abc",
+	}, body)
+
+	assert.False(t, NewErrorf(NotFound).Body("").Retryable)
+}
+
+func TestErrorRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"plain error", errors.New("boom"), false},
+		{"serialization failure", &pgconn.PgError{Code: "40001"}, true},
+		{"deadlock", &pgconn.PgError{Code: "40P01"}, true},
+		{"connection failure", fmt.Errorf("query: %w", &pgconn.PgError{Code: "08006"}), true},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"connection refused", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, true},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"unexpected eof", io.ErrUnexpectedEOF, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewError(InternalErr, tt.err)
+			assert.Equal(t, tt.want, err.Retryable())
+			assert.Equal(t, tt.want, err.Body("").Retryable)
+
+			// Re-coding the error keeps whether it was transient
+			assert.Equal(t, tt.want, NewError(InternalErr, err).Retryable())
+		})
+	}
+	assert.False(t, NewErrorf(InternalErr).Retryable())
+	assert.True(t, NewErrorf(Unavailable).Retryable())
+}
+
+func TestErrCodeString(t *testing.T) {
+	for code := range standardErrorMessages {
+		assert.NotEmpty(t, errorReasons[code], "missing reason for code %d", code)
+	}
+	assert.Equal(t, "unknown(42)", ErrCode(42).String())
+}
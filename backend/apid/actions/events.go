@@ -18,15 +18,17 @@ const deletedEventSentinel = -1
 
 // EventController expose actions in which a viewer can perform.
 type EventController struct {
-	store store.EventStore
-	bus   messaging.MessageBus
+	store   store.EventStore
+	results storev2.HandlerResultStoreGetter
+	bus     messaging.MessageBus
 }
 
 // NewEventController returns new EventController
 func NewEventController(store storev2.Interface, bus messaging.MessageBus) EventController {
 	return EventController{
-		store: store.GetEventStore(),
-		bus:   bus,
+		store:   store.GetEventStore(),
+		results: store,
+		bus:     bus,
 	}
 }
 
@@ -48,6 +50,9 @@ func (a EventController) List(ctx context.Context, pred *store.SelectionPredicat
 	}
 
 	if err != nil {
+		if _, ok := err.(*store.ErrNotValid); ok {
+			return nil, NewError(InvalidArgument, err)
+		}
 		return nil, NewError(InternalErr, err)
 	}
 
@@ -78,6 +83,21 @@ func (a EventController) Get(ctx context.Context, entity, check string) (*corev2
 	return result, nil
 }
 
+// HandlerResults returns the last results of the handlers executed for the
+// event indicated by the supplied entity and check, most recent first.
+func (a EventController) HandlerResults(ctx context.Context, entity, check string) ([]*storev2.HandlerResult, error) {
+	if entity == "" || check == "" {
+		return nil, NewErrorf(InvalidArgument, "HandlerResults() requires both an entity and a check")
+	}
+
+	results, err := a.results.GetHandlerResultStore().GetHandlerResults(ctx, corev2.ContextNamespace(ctx), entity, check)
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+
+	return results, nil
+}
+
 // Delete destroys the event indicated by the supplied entity and check.
 func (a EventController) Delete(ctx context.Context, entity, check string) error {
 	// Destroy (for events) requires both an entity and check
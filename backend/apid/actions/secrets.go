@@ -0,0 +1,113 @@
+package actions
+
+import (
+	"context"
+	"sort"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/secrets"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// SecretReference is a resource referencing a secret.
+type SecretReference struct {
+	// Type is the type of the resource, such as CheckConfig.
+	Type string `json:"type"`
+
+	// Name is the name of the resource.
+	Name string `json:"name"`
+
+	// Variable is the environment variable the secret is exposed as.
+	Variable string `json:"variable"`
+}
+
+// SecretUsage reports the usage of a secret of a namespace.
+type SecretUsage struct {
+	// Name is the name of the secret.
+	Name string `json:"name"`
+
+	// References are the checks, handlers and mutators referencing the
+	// secret.
+	References []SecretReference `json:"references"`
+
+	// LastResolved is when the secret was last resolved by the backend, as a
+	// Unix timestamp. It is zero if the secret was not resolved since the
+	// backend started.
+	LastResolved int64 `json:"last_resolved"`
+}
+
+// SecretController exposes the usage of the secrets.
+type SecretController struct {
+	store    storev2.Interface
+	resolver *secrets.ProviderManager
+}
+
+// NewSecretController returns a new SecretController. The resolutions of the
+// secrets are reported by the given provider manager, if not nil.
+func NewSecretController(store storev2.Interface, resolver *secrets.ProviderManager) SecretController {
+	return SecretController{
+		store:    store,
+		resolver: resolver,
+	}
+}
+
+// Usage returns the usage of the secrets of the namespace of the context,
+// sorted by name. The secrets that were resolved but are no longer referenced
+// are included.
+func (a SecretController) Usage(ctx context.Context) ([]SecretUsage, error) {
+	namespace := corev2.ContextNamespace(ctx)
+	usage := make(map[string]*SecretUsage)
+	add := func(typ, name string, refs []*corev2.Secret) {
+		for _, ref := range refs {
+			u, ok := usage[ref.Secret]
+			if !ok {
+				u = &SecretUsage{Name: ref.Secret, References: []SecretReference{}}
+				usage[ref.Secret] = u
+			}
+			u.References = append(u.References, SecretReference{Type: typ, Name: name, Variable: ref.Name})
+		}
+	}
+
+	id := storev2.ID{Namespace: namespace}
+	checks, err := storev2.Of[*corev2.CheckConfig](a.store).List(ctx, id, nil)
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+	for _, check := range checks {
+		add("CheckConfig", check.Name, check.Secrets)
+	}
+	handlers, err := storev2.Of[*corev2.Handler](a.store).List(ctx, id, nil)
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+	for _, handler := range handlers {
+		add("Handler", handler.Name, handler.Secrets)
+	}
+	mutators, err := storev2.Of[*corev2.Mutator](a.store).List(ctx, id, nil)
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+	for _, mutator := range mutators {
+		add("Mutator", mutator.Name, mutator.Secrets)
+	}
+
+	if a.resolver != nil {
+		for name, resolved := range a.resolver.LastResolved(namespace) {
+			u, ok := usage[name]
+			if !ok {
+				u = &SecretUsage{Name: name, References: []SecretReference{}}
+				usage[name] = u
+			}
+			u.LastResolved = resolved
+		}
+	}
+
+	result := make([]SecretUsage, 0, len(usage))
+	for _, u := range usage {
+		result = append(result, *u)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
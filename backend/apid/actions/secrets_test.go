@@ -0,0 +1,51 @@
+package actions
+
+import (
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/sensu/sensu-go/testing/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretControllerUsage(t *testing.T) {
+	ctx := testutil.NewContext(testutil.ContextWithNamespace("default"))
+
+	check := corev2.FixtureCheckConfig("check-db")
+	check.Secrets = []*corev2.Secret{{Name: "DB_PASSWORD", Secret: "db-password"}}
+	handler := corev2.FixtureHandler("slack")
+	handler.Secrets = []*corev2.Secret{{Name: "SLACK_TOKEN", Secret: "slack-token"}, {Name: "DB", Secret: "db-password"}}
+	mutator := corev2.FixtureMutator("enrich")
+
+	store := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	store.On("GetConfigStore").Return(cs)
+	listOf := func(typ string) interface{} {
+		return mock.MatchedBy(func(req storev2.ResourceRequest) bool {
+			return req.Type == typ && req.Namespace == "default"
+		})
+	}
+	cs.On("List", mock.Anything, listOf("CheckConfig"), mock.Anything).Return(mockstore.WrapList[*corev2.CheckConfig]{check}, nil)
+	cs.On("List", mock.Anything, listOf("Handler"), mock.Anything).Return(mockstore.WrapList[*corev2.Handler]{handler}, nil)
+	cs.On("List", mock.Anything, listOf("Mutator"), mock.Anything).Return(mockstore.WrapList[*corev2.Mutator]{mutator}, nil)
+
+	usage, err := NewSecretController(store, nil).Usage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []SecretUsage{
+		{
+			Name: "db-password",
+			References: []SecretReference{
+				{Type: "CheckConfig", Name: "check-db", Variable: "DB_PASSWORD"},
+				{Type: "Handler", Name: "slack", Variable: "DB"},
+			},
+		},
+		{
+			Name:       "slack-token",
+			References: []SecretReference{{Type: "Handler", Name: "slack", Variable: "SLACK_TOKEN"}},
+		},
+	}, usage)
+}
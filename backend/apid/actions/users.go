@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
@@ -228,6 +229,34 @@ func (a UserController) RemoveAllGroups(ctx context.Context, username string) er
 	})
 }
 
+// Sessions returns the active sessions of a given user.
+func (a UserController) Sessions(ctx context.Context, username string) ([]*storev2.Session, error) {
+	sessions, err := a.store.GetSessionStore().GetSessions(ctx, username, time.Now().Unix())
+	if err != nil {
+		return nil, NewError(InternalErr, err)
+	}
+	return sessions, nil
+}
+
+// RevokeSessions revokes the given sessions of a user, or all of its sessions
+// if none are given, and returns the number of revoked sessions.
+func (a UserController) RevokeSessions(ctx context.Context, username string, ids ...string) (int, error) {
+	n, err := a.store.GetSessionStore().RevokeSessions(ctx, username, ids...)
+	if err != nil {
+		return 0, NewError(InternalErr, err)
+	}
+	return n, nil
+}
+
+// ResetMFA removes the enrollment of a user in the multi-factor
+// authentication, such as a user who lost its authenticator.
+func (a UserController) ResetMFA(ctx context.Context, username string) error {
+	if err := a.store.GetMFAStore().DeleteMFAEnrollment(ctx, username); err != nil {
+		return NewError(InternalErr, err)
+	}
+	return nil
+}
+
 func (a UserController) findUser(ctx context.Context, name string) (*corev2.User, error) {
 	ustore := storev2.Of[*corev2.User](a.store)
 	user, err := ustore.Get(ctx, storev2.ID{Namespace: corev2.ContextNamespace(ctx), Name: name})
@@ -3,7 +3,6 @@ package apid
 import (
 	"context"
 	"crypto/tls"
-	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
@@ -22,10 +21,15 @@ import (
 	"github.com/sensu/sensu-go/backend/apid/handlers"
 	"github.com/sensu/sensu-go/backend/apid/middlewares"
 	"github.com/sensu/sensu-go/backend/apid/routers"
+	"github.com/sensu/sensu-go/backend/audit"
 	"github.com/sensu/sensu-go/backend/authentication"
+	"github.com/sensu/sensu-go/backend/authentication/mfa"
+	"github.com/sensu/sensu-go/backend/authorization"
 	"github.com/sensu/sensu-go/backend/authorization/rbac"
 	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/queue"
+	"github.com/sensu/sensu-go/backend/quota"
+	"github.com/sensu/sensu-go/backend/secrets"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 )
 
@@ -64,6 +68,29 @@ type Config struct {
 	ClusterVersion string
 	GraphQLService *graphql.Service
 	Queue          queue.Client
+	Auditor        *audit.Auditor
+	RateLimiter    *middlewares.RateLimiter
+	Quotas         *quota.Enforcer
+	ReadOnly       *middlewares.ReadOnlyMode
+	AgentDrainer   routers.AgentDrainer
+
+	// Authorizer authorizes the requests. An RBAC authorizer is used if nil.
+	Authorizer authorization.Authorizer
+
+	// MFARequired requires the local users to enroll in the multi-factor
+	// authentication before they can log in.
+	MFARequired bool
+
+	// SecretsProviderManager reports when the secrets were last resolved.
+	SecretsProviderManager *secrets.ProviderManager
+}
+
+// authorizer returns the authorizer of the requests.
+func (c Config) authorizer() authorization.Authorizer {
+	if c.Authorizer != nil {
+		return c.Authorizer
+	}
+	return &rbac.Authorizer{Store: c.Store}
 }
 
 // New creates a new APId.
@@ -94,13 +121,18 @@ func New(c Config, opts ...Option) (*APId, error) {
 	_ = PublicSubrouter(router, c)
 	a.GraphQLSubrouter = GraphQLSubrouter(router, c)
 	_ = AuthenticationSubrouter(router, c)
+	_ = OIDCSubrouter(router, c)
 	a.CoreSubrouter = CoreSubrouter(router, c)
 	a.CoreV3Subrouter = CoreV3Subrouter(router, c)
 	a.EntityLimitedCoreSubrouter = EntityLimitedCoreSubrouter(router, c)
+	_ = AuditSubrouter(router, c)
+	_ = QuotaSubrouter(router, c)
+	_ = RetentionSubrouter(router, c)
+	_ = AuthProvidersSubrouter(router, c)
 
 	a.HTTPServer = &http.Server{
 		Addr:         c.ListenAddress,
-		Handler:      router,
+		Handler:      middlewares.Apply(router, middlewares.RequestID{}, middlewares.Compression{MinSize: middlewares.MinCompressionSize}),
 		WriteTimeout: c.WriteTimeout,
 		ReadTimeout:  15 * time.Second,
 		TLSConfig:    tlsServerConfig,
@@ -136,8 +168,26 @@ func AuthenticationSubrouter(router *mux.Router, cfg Config) *mux.Router {
 		middlewares.LimitRequest{Limit: cfg.RequestLimit},
 	)
 
+	mfaManager := mfa.NewManager(cfg.Store.GetMFAStore(), cfg.MFARequired)
 	mountRouters(subrouter,
-		routers.NewAuthenticationRouter(api.NewAuthenticationClient(cfg.Authenticator)),
+		routers.NewAuthenticationRouter(api.NewAuthenticationClient(cfg.Authenticator, cfg.Store.GetSessionStore(), mfaManager)),
+		routers.NewMFARouter(api.NewMFAClient(cfg.Authenticator, mfaManager)),
+	)
+
+	return subrouter
+}
+
+// OIDCSubrouter initializes a subrouter that handles the login flow of the
+// OIDC authentication providers
+func OIDCSubrouter(router *mux.Router, cfg Config) *mux.Router {
+	subrouter := NewSubrouter(
+		router.NewRoute(),
+		middlewares.SimpleLogger{},
+		middlewares.LimitRequest{Limit: cfg.RequestLimit},
+	)
+
+	mountRouters(subrouter,
+		routers.NewOIDCRouter(cfg.Store),
 	)
 
 	return subrouter
@@ -152,7 +202,13 @@ func CoreSubrouter(router *mux.Router, cfg Config) *mux.Router {
 		middlewares.Authentication{Store: cfg.Store},
 		middlewares.SimpleLogger{},
 		middlewares.AuthorizationAttributes{},
-		middlewares.Authorization{Authorizer: &rbac.Authorizer{Store: cfg.Store}},
+		middlewares.Audit{Auditor: cfg.Auditor},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter},
+		middlewares.Authorization{Authorizer: cfg.authorizer()},
+		middlewares.DryRun{},
+		middlewares.ReadOnly{Mode: cfg.ReadOnly},
+		middlewares.ReplicaReads{},
+		middlewares.Quota{Enforcer: cfg.Quotas},
 		middlewares.LimitRequest{Limit: cfg.RequestLimit},
 		middlewares.Pagination{},
 		middlewares.Selectors{},
@@ -174,6 +230,9 @@ func CoreSubrouter(router *mux.Router, cfg Config) *mux.Router {
 		routers.NewSilencedRouter(cfg.Store),
 		routers.NewTessenRouter(actions.NewTessenController(cfg.Store, cfg.Bus)),
 		routers.NewUsersRouter(cfg.Store),
+		routers.NewMaintenanceRouter(cfg.ReadOnly),
+		routers.NewAgentDrainRouter(cfg.AgentDrainer),
+		routers.NewSecretsRouter(actions.NewSecretController(cfg.Store, cfg.SecretsProviderManager)),
 	)
 
 	return subrouter
@@ -186,14 +245,123 @@ func CoreV3Subrouter(router *mux.Router, cfg Config) *mux.Router {
 		middlewares.Authentication{Store: cfg.Store},
 		middlewares.SimpleLogger{},
 		middlewares.AuthorizationAttributes{},
-		middlewares.Authorization{Authorizer: &rbac.Authorizer{Store: cfg.Store}},
+		middlewares.Audit{Auditor: cfg.Auditor},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter},
+		middlewares.Authorization{Authorizer: cfg.authorizer()},
+		middlewares.DryRun{},
+		middlewares.ReadOnly{Mode: cfg.ReadOnly},
+		middlewares.ReplicaReads{},
 		middlewares.LimitRequest{Limit: cfg.RequestLimit},
 		middlewares.Pagination{},
 		middlewares.Selectors{},
 	)
 	mountRouters(
 		subrouter,
-		routers.NewNamespacesRouter(api.NewNamespaceClient(cfg.Store, &rbac.Authorizer{Store: cfg.Store}), handlers.NewHandlers[*corev3.Namespace](cfg.Store)),
+		routers.NewNamespacesRouter(api.NewNamespaceClient(cfg.Store, cfg.authorizer()), handlers.NewHandlers[*corev3.Namespace](cfg.Store)),
+	)
+	return subrouter
+}
+
+// AuditSubrouter initializes a subrouter that handles all requests coming to
+// /api/audit/v1
+func AuditSubrouter(router *mux.Router, cfg Config) *mux.Router {
+	subrouter := NewSubrouter(
+		router.PathPrefix("/api/{group:audit}/{version:v1}/"),
+		middlewares.Namespace{},
+		middlewares.Authentication{Store: cfg.Store},
+		middlewares.SimpleLogger{},
+		middlewares.AuthorizationAttributes{},
+		middlewares.Audit{Auditor: cfg.Auditor},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter},
+		middlewares.Authorization{Authorizer: cfg.authorizer()},
+		middlewares.DryRun{},
+		middlewares.ReadOnly{Mode: cfg.ReadOnly},
+		middlewares.ReplicaReads{},
+		middlewares.LimitRequest{Limit: cfg.RequestLimit},
+		middlewares.Pagination{},
+		middlewares.Selectors{},
+	)
+	mountRouters(
+		subrouter,
+		routers.NewAuditPoliciesRouter(cfg.Store),
+	)
+	return subrouter
+}
+
+// QuotaSubrouter initializes a subrouter that handles all requests coming to
+// /api/quota/v1
+func QuotaSubrouter(router *mux.Router, cfg Config) *mux.Router {
+	subrouter := NewSubrouter(
+		router.PathPrefix("/api/{group:quota}/{version:v1}/"),
+		middlewares.Namespace{},
+		middlewares.Authentication{Store: cfg.Store},
+		middlewares.SimpleLogger{},
+		middlewares.AuthorizationAttributes{},
+		middlewares.Audit{Auditor: cfg.Auditor},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter},
+		middlewares.Authorization{Authorizer: cfg.authorizer()},
+		middlewares.DryRun{},
+		middlewares.ReadOnly{Mode: cfg.ReadOnly},
+		middlewares.ReplicaReads{},
+		middlewares.LimitRequest{Limit: cfg.RequestLimit},
+		middlewares.Pagination{},
+		middlewares.Selectors{},
+	)
+	mountRouters(
+		subrouter,
+		routers.NewNamespaceQuotasRouter(cfg.Store, cfg.Quotas),
+	)
+	return subrouter
+}
+
+// RetentionSubrouter initializes a subrouter that handles all requests coming
+// to /api/retention/v1
+func RetentionSubrouter(router *mux.Router, cfg Config) *mux.Router {
+	subrouter := NewSubrouter(
+		router.PathPrefix("/api/{group:retention}/{version:v1}/"),
+		middlewares.Namespace{},
+		middlewares.Authentication{Store: cfg.Store},
+		middlewares.SimpleLogger{},
+		middlewares.AuthorizationAttributes{},
+		middlewares.Audit{Auditor: cfg.Auditor},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter},
+		middlewares.Authorization{Authorizer: cfg.authorizer()},
+		middlewares.DryRun{},
+		middlewares.ReadOnly{Mode: cfg.ReadOnly},
+		middlewares.ReplicaReads{},
+		middlewares.LimitRequest{Limit: cfg.RequestLimit},
+		middlewares.Pagination{},
+		middlewares.Selectors{},
+	)
+	mountRouters(
+		subrouter,
+		routers.NewEventRetentionPoliciesRouter(cfg.Store),
+	)
+	return subrouter
+}
+
+// AuthProvidersSubrouter initializes a subrouter that handles all requests
+// coming to /api/authentication/v2
+func AuthProvidersSubrouter(router *mux.Router, cfg Config) *mux.Router {
+	subrouter := NewSubrouter(
+		router.PathPrefix("/api/{group:authentication}/{version:v2}/"),
+		middlewares.Namespace{},
+		middlewares.Authentication{Store: cfg.Store},
+		middlewares.SimpleLogger{},
+		middlewares.AuthorizationAttributes{},
+		middlewares.Audit{Auditor: cfg.Auditor},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter},
+		middlewares.Authorization{Authorizer: cfg.authorizer()},
+		middlewares.DryRun{},
+		middlewares.ReadOnly{Mode: cfg.ReadOnly},
+		middlewares.ReplicaReads{},
+		middlewares.LimitRequest{Limit: cfg.RequestLimit},
+		middlewares.Pagination{},
+		middlewares.Selectors{},
+	)
+	mountRouters(
+		subrouter,
+		routers.NewAuthProvidersRouter(cfg.Store),
 	)
 	return subrouter
 }
@@ -207,13 +375,27 @@ func EntityLimitedCoreSubrouter(router *mux.Router, cfg Config) *mux.Router {
 		middlewares.Authentication{Store: cfg.Store},
 		middlewares.SimpleLogger{},
 		middlewares.AuthorizationAttributes{},
-		middlewares.Authorization{Authorizer: &rbac.Authorizer{Store: cfg.Store}},
+		middlewares.Audit{Auditor: cfg.Auditor},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter},
+		middlewares.Authorization{Authorizer: cfg.authorizer()},
+		middlewares.DryRun{},
+		middlewares.ReadOnly{Mode: cfg.ReadOnly},
+		middlewares.ReplicaReads{},
+		middlewares.Quota{Enforcer: cfg.Quotas},
 		middlewares.LimitRequest{Limit: cfg.RequestLimit},
 		middlewares.Pagination{},
 		middlewares.Selectors{},
 	)
+	// Event streams end before the write timeout hangs them up, clients then
+	// reconnect and resume them.
+	streamTimeout := cfg.WriteTimeout - time.Second
+	if streamTimeout < 0 {
+		streamTimeout = 0
+	}
+
 	mountRouters(
 		subrouter,
+		routers.NewEventStreamRouter(cfg.Store, cfg.Bus, streamTimeout),
 		routers.NewEntitiesRouter(cfg.Store),
 		routers.NewEventsRouter(cfg.Store, cfg.Bus),
 	)
@@ -235,6 +417,7 @@ func GraphQLSubrouter(router *mux.Router, cfg Config) *mux.Router {
 		// https://github.com/graphql/graphiql
 		// https://graphql.org/learn/introspection/
 		middlewares.Authentication{IgnoreUnauthorized: true, Store: cfg.Store},
+		middlewares.RateLimit{Limiter: cfg.RateLimiter},
 		middlewares.SimpleLogger{},
 	)
 
@@ -249,8 +432,9 @@ func GraphQLSubrouter(router *mux.Router, cfg Config) *mux.Router {
 	mountRouters(
 		subrouter,
 		&routers.GraphQLRouter{
-			Service: cfg.GraphQLService,
-			Timeout: timeout,
+			Service:  cfg.GraphQLService,
+			Timeout:  timeout,
+			ReadOnly: cfg.ReadOnly,
 		},
 	)
 
@@ -269,6 +453,7 @@ func PublicSubrouter(router *mux.Router, cfg Config) *mux.Router {
 	mountRouters(subrouter,
 		routers.NewVersionRouter(actions.NewVersionController(cfg.ClusterVersion)),
 		routers.NewTessenMetricRouter(actions.NewTessenMetricController(cfg.Bus)),
+		routers.NewOpenAPIRouter(router, cfg.ClusterVersion),
 	)
 
 	subrouter.Handle("/metrics", promhttp.Handler())
@@ -277,11 +462,7 @@ func PublicSubrouter(router *mux.Router, cfg Config) *mux.Router {
 }
 
 func notFoundHandler(w http.ResponseWriter, _ *http.Request) {
-	w.WriteHeader(http.StatusNotFound)
-	resp := map[string]interface{}{
-		"message": "not found", "code": actions.NotFound,
-	}
-	_ = json.NewEncoder(w).Encode(resp)
+	routers.WriteError(w, actions.NewErrorf(actions.NotFound))
 }
 
 // Start APId.
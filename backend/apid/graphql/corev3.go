@@ -2,6 +2,7 @@ package graphql
 
 import (
 	"context"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
@@ -10,6 +11,7 @@ import (
 	util_api "github.com/sensu/sensu-go/backend/apid/graphql/util/api"
 	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/graphql"
+	"github.com/sensu/sensu-go/system"
 )
 
 //
@@ -30,8 +32,7 @@ var (
 
 type corev3EntityConfigExtImpl struct {
 	schema.CoreV3EntityConfigAliases
-	client       GenericClient
-	entityClient EntityClient
+	client GenericClient
 }
 
 // ID implements response to request for 'id' field.
@@ -53,9 +54,8 @@ func (i *corev3EntityConfigExtImpl) State(p graphql.ResolveParams) (interface{},
 
 // ToCoreV2Entity implements response to request for 'toCoreV2Entity' field.
 func (i *corev3EntityConfigExtImpl) ToCoreV2Entity(p graphql.ResolveParams) (interface{}, error) {
-	obj := p.Source.(interface{ GetMetadata() *corev2.ObjectMeta })
-	ctx := contextWithNamespace(p.Context, obj.GetMetadata().Namespace)
-	return i.entityClient.FetchEntity(ctx, obj.GetMetadata().Name)
+	meta := p.Source.(interface{ GetMetadata() *corev2.ObjectMeta }).GetMetadata()
+	return loadEntity(p.Context, meta.Namespace, meta.Name), nil
 }
 
 type corev3EntityConfigImpl struct {
@@ -74,8 +74,7 @@ func (*corev3EntityConfigImpl) IsTypeOf(s interface{}, p graphql.IsTypeOfParams)
 
 type corev3EntityStateExtImpl struct {
 	schema.CoreV3EntityStateAliases
-	client       GenericClient
-	entityClient EntityClient
+	client GenericClient
 }
 
 // ID implements response to request for 'id' field.
@@ -97,9 +96,35 @@ func (i *corev3EntityStateExtImpl) Config(p graphql.ResolveParams) (interface{},
 
 // ToCoreV2Entity implements response to request for 'toCoreV2Entity' field.
 func (i *corev3EntityStateExtImpl) ToCoreV2Entity(p graphql.ResolveParams) (interface{}, error) {
-	obj := p.Source.(interface{ GetMetadata() *corev2.ObjectMeta })
-	ctx := contextWithNamespace(p.Context, obj.GetMetadata().Namespace)
-	return i.entityClient.FetchEntity(ctx, obj.GetMetadata().Name)
+	meta := p.Source.(interface{ GetMetadata() *corev2.ObjectMeta }).GetMetadata()
+	return loadEntity(p.Context, meta.Namespace, meta.Name), nil
+}
+
+// SystemMetrics implements response to request for 'systemMetrics' field.
+func (*corev3EntityStateExtImpl) SystemMetrics(p graphql.ResolveParams) (interface{}, error) {
+	obj := p.Source.(*corev3.EntityState)
+	if obj.Metadata == nil {
+		return nil, nil
+	}
+	metrics, err := system.ParseMetricsAnnotation(obj.Metadata.Annotations)
+	if metrics == nil || err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+//
+// SystemMetrics
+//
+
+type systemMetricsImpl struct {
+	schema.SystemMetricsAliases
+}
+
+// Timestamp implements response to request for 'timestamp' field.
+func (*systemMetricsImpl) Timestamp(p graphql.ResolveParams) (time.Time, error) {
+	metrics := p.Source.(*system.Metrics)
+	return time.Unix(metrics.Timestamp, 0), nil
 }
 
 func getEntityComponent(ctx context.Context, client GenericClient, meta *corev2.ObjectMeta, val corev3.Resource) (interface{}, error) {
@@ -12,6 +12,7 @@ import (
 	util_api "github.com/sensu/sensu-go/backend/apid/graphql/util/api"
 	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/graphql"
+	"github.com/sensu/sensu-go/system"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -311,7 +312,7 @@ func Test_corev3EntityStateExtImpl_ToCoreV2Entity(t *testing.T) {
 					Once()
 			},
 			source:  corev3.FixtureEntityState("name"),
-			want:    corev2.FixtureEntity("name"),
+			want:    nil,
 			wantErr: true,
 		},
 	}
@@ -320,11 +321,11 @@ func Test_corev3EntityStateExtImpl_ToCoreV2Entity(t *testing.T) {
 			client := new(MockEntityClient)
 			tt.setup(client)
 
-			impl := &corev3EntityStateExtImpl{entityClient: client}
-			got, err := impl.ToCoreV2Entity(graphql.ResolveParams{
-				Context: context.Background(),
+			impl := &corev3EntityStateExtImpl{}
+			got, err := resolveThunk(impl.ToCoreV2Entity(graphql.ResolveParams{
+				Context: contextWithLoadersNoCache(context.Background(), ServiceConfig{EntityClient: client}),
 				Source:  tt.source,
-			})
+			}))
 			if (err != nil) != tt.wantErr {
 				t.Errorf("corev3EntityStateExtImpl.ToCoreV2Entity() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -336,6 +337,57 @@ func Test_corev3EntityStateExtImpl_ToCoreV2Entity(t *testing.T) {
 	}
 }
 
+func Test_corev3EntityStateExtImpl_SystemMetrics(t *testing.T) {
+	withMetrics := corev3.FixtureEntityState("name")
+	withMetrics.Metadata.Annotations = map[string]string{
+		system.MetricsAnnotation: `{"timestamp":1667902530,"load1":0.5,"memory_used_percent":42}`,
+	}
+	invalid := corev3.FixtureEntityState("name")
+	invalid.Metadata.Annotations = map[string]string{system.MetricsAnnotation: "{"}
+
+	tests := []struct {
+		name    string
+		source  interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name:    "no metrics",
+			source:  corev3.FixtureEntityState("name"),
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name:    "metrics",
+			source:  withMetrics,
+			want:    &system.Metrics{Timestamp: 1667902530, Load1: 0.5, MemoryUsedPercent: 42},
+			wantErr: false,
+		},
+		{
+			name:    "invalid metrics",
+			source:  invalid,
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			impl := &corev3EntityStateExtImpl{}
+			got, err := impl.SystemMetrics(graphql.ResolveParams{
+				Context: context.Background(),
+				Source:  tt.source,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("corev3EntityStateExtImpl.SystemMetrics() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("corev3EntityStateExtImpl.SystemMetrics() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_corev3EntityConfigExtImpl_ToCoreV2Entity(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -363,7 +415,7 @@ func Test_corev3EntityConfigExtImpl_ToCoreV2Entity(t *testing.T) {
 					Once()
 			},
 			source:  corev3.FixtureEntityConfig("name"),
-			want:    corev2.FixtureEntity("name"),
+			want:    nil,
 			wantErr: true,
 		},
 	}
@@ -372,11 +424,11 @@ func Test_corev3EntityConfigExtImpl_ToCoreV2Entity(t *testing.T) {
 			client := new(MockEntityClient)
 			tt.setup(client)
 
-			impl := &corev3EntityConfigExtImpl{entityClient: client}
-			got, err := impl.ToCoreV2Entity(graphql.ResolveParams{
-				Context: context.Background(),
+			impl := &corev3EntityConfigExtImpl{}
+			got, err := resolveThunk(impl.ToCoreV2Entity(graphql.ResolveParams{
+				Context: contextWithLoadersNoCache(context.Background(), ServiceConfig{EntityClient: client}),
 				Source:  tt.source,
-			})
+			}))
 			if (err != nil) != tt.wantErr {
 				t.Errorf("corev3EntityConfigExtImpl.ToCoreV2Entity() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/graph-gophers/dataloader"
 	corev2 "github.com/sensu/core/v2"
@@ -26,6 +27,9 @@ const (
 	mutatorsLoaderKey
 	namespacesLoaderKey
 	silencedsLoaderKey
+	entityLoaderKey
+	checkConfigLoaderKey
+	mutatorLoaderKey
 
 	// chunk size used by dataloader when retrieving resources from the store
 	loaderPageSize = 250
@@ -35,6 +39,10 @@ const (
 	maxLengthEntityDataloader  = 1_000
 	maxLengthEventDataloader   = 1_000
 	maxLengthGenericDataloader = 2_500
+
+	// the time the loaders of single resources wait for more lookups before
+	// resolving a batch.
+	loaderBatchWait = time.Millisecond
 )
 
 var (
@@ -345,6 +353,123 @@ func loadNamespaces(ctx context.Context) ([]*corev3.Namespace, error) {
 	return records, err
 }
 
+// single resources
+
+type resourceCacheKey struct {
+	namespace string
+	name      string
+}
+
+func (k *resourceCacheKey) String() string {
+	return strings.Join([]string{k.namespace, k.name}, "\n")
+}
+
+func (k *resourceCacheKey) Raw() interface{} {
+	return k
+}
+
+// batchFetchFn returns the batch function of a loader of single resources.
+// When a batch holds several lookups in a namespace, the resources of the
+// namespace are listed once instead of being fetched one by one; those that
+// are missing from the list are still fetched.
+func batchFetchFn[R interface{ GetObjectMeta() corev2.ObjectMeta }](
+	fetch func(context.Context, string) (R, error),
+	list func(context.Context) ([]R, error),
+) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		lookups := map[string]int{}
+		for _, key := range keys {
+			lookups[key.Raw().(*resourceCacheKey).namespace]++
+		}
+
+		listed := map[string]map[string]R{}
+		results := make([]*dataloader.Result, 0, len(keys))
+		for _, key := range keys {
+			key := key.Raw().(*resourceCacheKey)
+			ctx := store.NamespaceContext(ctx, key.namespace)
+			if lookups[key.namespace] > 1 {
+				records, ok := listed[key.namespace]
+				if !ok {
+					records = map[string]R{}
+					list, err := list(ctx)
+					if err != nil {
+						logger.WithError(err).Warn("couldn't list resources, fetching them instead")
+					}
+					for _, record := range list {
+						records[record.GetObjectMeta().Name] = record
+					}
+					listed[key.namespace] = records
+				}
+				if record, ok := records[key.name]; ok {
+					results = append(results, &dataloader.Result{Data: record})
+					continue
+				}
+			}
+			record, err := fetch(ctx, key.name)
+			results = append(results, &dataloader.Result{Data: record, Error: err})
+		}
+		return results
+	}
+}
+
+// loadResource returns a thunk resolving the resource of the given loader.
+// The resolvers return the thunk itself, so that the lookups of the fields of
+// a list are batched before any of them is resolved.
+func loadResource(ctx context.Context, loaderKey key, ns, name string) func() (interface{}, error) {
+	loader, err := getLoader(ctx, loaderKey)
+	if err != nil {
+		return func() (interface{}, error) {
+			return nil, err
+		}
+	}
+
+	thunk := loader.Load(ctx, &resourceCacheKey{namespace: ns, name: name})
+	return func() (interface{}, error) {
+		return handleFetchResult(thunk())
+	}
+}
+
+func loadEntityBatchFn(c EntityClient) dataloader.BatchFunc {
+	fetch := func(ctx context.Context, name string) (*corev2.Entity, error) {
+		return c.FetchEntity(ctx, name)
+	}
+	return batchFetchFn(fetch, func(ctx context.Context) ([]*corev2.Entity, error) {
+		return listEntities(ctx, c, maxLengthEntityDataloader)
+	})
+}
+
+func loadEntity(ctx context.Context, ns, name string) func() (interface{}, error) {
+	return loadResource(ctx, entityLoaderKey, ns, name)
+}
+
+func loadCheckConfigBatchFn(c CheckClient) dataloader.BatchFunc {
+	fetch := func(ctx context.Context, name string) (*corev2.CheckConfig, error) {
+		return c.FetchCheck(ctx, name)
+	}
+	return batchFetchFn(fetch, func(ctx context.Context) ([]*corev2.CheckConfig, error) {
+		ctx = context.WithValue(ctx, corev2.PageSizeKey, maxLengthGenericDataloader)
+		return c.ListChecks(ctx)
+	})
+}
+
+func loadCheckConfig(ctx context.Context, ns, name string) func() (interface{}, error) {
+	return loadResource(ctx, checkConfigLoaderKey, ns, name)
+}
+
+func loadMutatorBatchFn(c MutatorClient) dataloader.BatchFunc {
+	fetch := func(ctx context.Context, name string) (*corev2.Mutator, error) {
+		return c.FetchMutator(ctx, name)
+	}
+	return batchFetchFn(fetch, func(ctx context.Context) ([]*corev2.Mutator, error) {
+		ctx = context.WithValue(ctx, corev2.PageSizeKey, maxLengthGenericDataloader)
+		return c.ListMutators(ctx)
+	})
+}
+
+func loadMutator(ctx context.Context, ns, name string) func() (interface{}, error) {
+	return loadResource(ctx, mutatorLoaderKey, ns, name)
+}
+
 // silences
 
 func loadSilencedsBatchFn(c SilencedClient) dataloader.BatchFunc {
@@ -377,20 +502,37 @@ func loadSilenceds(ctx context.Context, ns string) ([]*corev2.Silenced, error) {
 }
 
 func contextWithLoaders(ctx context.Context, cfg ServiceConfig, opts ...dataloader.Option) context.Context {
-	// Currently all fields are resolved serially, as such we disable batching and
-	// rely only on dataloader's cache.
-	opts = append([]dataloader.Option{dataloader.WithBatchCapacity(1)}, opts...)
+	// The lists are loaded serially, as such we disable their batching and rely
+	// only on dataloader's cache.
+	listOpts := func(name string) []dataloader.Option {
+		return append([]dataloader.Option{
+			dataloader.WithBatchCapacity(1),
+			dataloader.WithTracer(loaderTracer(name)),
+		}, opts...)
+	}
+	// The single resources are loaded lazily, so that their lookups are
+	// batched.
+	fetchOpts := func(name string) []dataloader.Option {
+		return append([]dataloader.Option{
+			dataloader.WithBatchCapacity(loaderPageSize),
+			dataloader.WithWait(loaderBatchWait),
+			dataloader.WithTracer(loaderTracer(name)),
+		}, opts...)
+	}
 
 	loaders := map[key]*dataloader.Loader{}
-	loaders[assetsLoaderKey] = dataloader.NewBatchedLoader(loadAssetsBatchFn(cfg.AssetClient), opts...)
-	loaders[checkConfigsLoaderKey] = dataloader.NewBatchedLoader(loadCheckConfigsBatchFn(cfg.CheckClient), opts...)
-	loaders[entitiesLoaderKey] = dataloader.NewBatchedLoader(loadEntitiesBatchFn(cfg.EntityClient), opts...)
-	loaders[eventsLoaderKey] = dataloader.NewBatchedLoader(loadEventsBatchFn(cfg.EventClient), opts...)
-	loaders[eventFiltersLoaderKey] = dataloader.NewBatchedLoader(loadEventFiltersBatchFn(cfg.EventFilterClient), opts...)
-	loaders[handlersLoaderKey] = dataloader.NewBatchedLoader(loadHandlersBatchFn(cfg.HandlerClient), opts...)
-	loaders[mutatorsLoaderKey] = dataloader.NewBatchedLoader(loadMutatorsBatchFn(cfg.MutatorClient), opts...)
-	loaders[namespacesLoaderKey] = dataloader.NewBatchedLoader(loadNamespacesBatchFn(cfg.NamespaceClient), opts...)
-	loaders[silencedsLoaderKey] = dataloader.NewBatchedLoader(loadSilencedsBatchFn(cfg.SilencedClient), opts...)
+	loaders[assetsLoaderKey] = dataloader.NewBatchedLoader(loadAssetsBatchFn(cfg.AssetClient), listOpts("assets")...)
+	loaders[checkConfigsLoaderKey] = dataloader.NewBatchedLoader(loadCheckConfigsBatchFn(cfg.CheckClient), listOpts("check_configs")...)
+	loaders[entitiesLoaderKey] = dataloader.NewBatchedLoader(loadEntitiesBatchFn(cfg.EntityClient), listOpts("entities")...)
+	loaders[eventsLoaderKey] = dataloader.NewBatchedLoader(loadEventsBatchFn(cfg.EventClient), listOpts("events")...)
+	loaders[eventFiltersLoaderKey] = dataloader.NewBatchedLoader(loadEventFiltersBatchFn(cfg.EventFilterClient), listOpts("event_filters")...)
+	loaders[handlersLoaderKey] = dataloader.NewBatchedLoader(loadHandlersBatchFn(cfg.HandlerClient), listOpts("handlers")...)
+	loaders[mutatorsLoaderKey] = dataloader.NewBatchedLoader(loadMutatorsBatchFn(cfg.MutatorClient), listOpts("mutators")...)
+	loaders[namespacesLoaderKey] = dataloader.NewBatchedLoader(loadNamespacesBatchFn(cfg.NamespaceClient), listOpts("namespaces")...)
+	loaders[silencedsLoaderKey] = dataloader.NewBatchedLoader(loadSilencedsBatchFn(cfg.SilencedClient), listOpts("silenceds")...)
+	loaders[entityLoaderKey] = dataloader.NewBatchedLoader(loadEntityBatchFn(cfg.EntityClient), fetchOpts("entity")...)
+	loaders[checkConfigLoaderKey] = dataloader.NewBatchedLoader(loadCheckConfigBatchFn(cfg.CheckClient), fetchOpts("check_config")...)
+	loaders[mutatorLoaderKey] = dataloader.NewBatchedLoader(loadMutatorBatchFn(cfg.MutatorClient), fetchOpts("mutator")...)
 	return context.WithValue(ctx, loadersKey, loaders)
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/graph-gophers/dataloader"
 	corev2 "github.com/sensu/core/v2"
@@ -16,6 +17,14 @@ func contextWithLoadersNoCache(ctx context.Context, cfg ServiceConfig, opts ...d
 	return contextWithLoaders(ctx, cfg, opts...)
 }
 
+// resolveThunk resolves the value of a field that is loaded lazily.
+func resolveThunk(res interface{}, err error) (interface{}, error) {
+	if thunk, ok := res.(func() (interface{}, error)); ok && err == nil {
+		return thunk()
+	}
+	return res, err
+}
+
 func Test_listEvents(t *testing.T) {
 	mkEvents := func(num int) []*corev2.Event {
 		result := make([]*corev2.Event, num)
@@ -165,3 +174,34 @@ func Test_listEntities(t *testing.T) {
 		})
 	}
 }
+
+func Test_loadEntity(t *testing.T) {
+	client := new(MockEntityClient)
+	client.On("ListEntities", mock.Anything, mock.Anything).Return([]*corev2.Entity{
+		corev2.FixtureEntity("one"),
+		corev2.FixtureEntity("two"),
+	}, nil).Once()
+	client.On("FetchEntity", mock.Anything, "three").Return(corev2.FixtureEntity("three"), nil).Once()
+	client.On("FetchEntity", mock.Anything, "other").Return(corev2.FixtureEntity("other"), nil).Once()
+	// Wait long enough for all the lookups to be in the same batch
+	ctx := contextWithLoaders(context.Background(), ServiceConfig{EntityClient: client}, dataloader.WithWait(100*time.Millisecond))
+
+	// The lookups of a namespace are batched into a single list
+	thunks := []func() (interface{}, error){
+		loadEntity(ctx, "default", "one"),
+		loadEntity(ctx, "default", "two"),
+		loadEntity(ctx, "default", "three"),
+		loadEntity(ctx, "other", "other"),
+		loadEntity(ctx, "default", "one"),
+	}
+	for i, name := range []string{"one", "two", "three", "other", "one"} {
+		got, err := thunks[i]()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entity, ok := got.(*corev2.Entity); !ok || entity.Name != name {
+			t.Errorf("loadEntity() = %v, want %s", got, name)
+		}
+	}
+	client.AssertExpectations(t)
+}
@@ -19,7 +19,6 @@ var _ schema.HandlerSocketFieldResolvers = (*handlerSocketImpl)(nil)
 
 type handlerImpl struct {
 	schema.HandlerAliases
-	client MutatorClient
 }
 
 // ID implements response to request for 'id' field.
@@ -34,10 +33,7 @@ func (r *handlerImpl) Mutator(p graphql.ResolveParams) (interface{}, error) {
 		return nil, nil
 	}
 
-	ctx := corev2.SetContextFromResource(p.Context, src)
-	res, err := r.client.FetchMutator(ctx, src.Mutator)
-
-	return handleFetchResult(res, err)
+	return loadMutator(p.Context, src.Namespace, src.Mutator), nil
 }
 
 // Handlers implements response to request for 'handlers' field.
@@ -43,17 +43,18 @@ func TestHandlerTypeMutatorField(t *testing.T) {
 	handler.Mutator = mutator.Name
 
 	client := new(MockMutatorClient)
-	impl := &handlerImpl{client: client}
+	impl := &handlerImpl{}
+	ctx := contextWithLoadersNoCache(context.Background(), ServiceConfig{MutatorClient: client})
 
 	// Success
 	client.On("FetchMutator", mock.Anything, mutator.Name).Return(mutator, nil).Once()
-	res, err := impl.Mutator(graphql.ResolveParams{Source: handler, Context: context.Background()})
+	res, err := resolveThunk(impl.Mutator(graphql.ResolveParams{Source: handler, Context: ctx}))
 	require.NoError(t, err)
 	assert.NotEmpty(t, res)
 
 	// No mutator
 	handler.Mutator = ""
-	res, err = impl.Mutator(graphql.ResolveParams{Source: handler, Context: context.Background()})
+	res, err = impl.Mutator(graphql.ResolveParams{Source: handler, Context: ctx})
 	require.NoError(t, err)
 	assert.Nil(t, res)
 }
@@ -7,6 +7,7 @@ import (
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/sensu-go/backend/api"
+	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/store"
 )
 
@@ -44,6 +45,10 @@ type EventClient interface {
 	EventStoreSupportsFiltering(context.Context) bool
 }
 
+type EventSubscriptionClient interface {
+	SubscribeEvents(ctx context.Context) (<-chan *messaging.EventWithPrevious, error)
+}
+
 type EventFilterClient interface {
 	ListEventFilters(ctx context.Context) ([]*corev2.EventFilter, error)
 	FetchEventFilter(ctx context.Context, name string) (*corev2.EventFilter, error)
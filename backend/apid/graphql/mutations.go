@@ -1,6 +1,7 @@
 package graphql
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"strings"
@@ -223,7 +224,46 @@ func (r *mutationsImpl) ResolveEvent(p schema.MutationResolveEventFieldResolverP
 		return nil, err
 	}
 
-	ctx := setContextFromComponents(p.Context, components)
+	event, err := r.resolveEvent(p.Context, components, p.Args.Input.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"clientMutationId": p.Args.Input.ClientMutationID,
+		"event":            event,
+	}, nil
+}
+
+// ResolveEvents implements response to request for the 'resolveEvents' field.
+func (r *mutationsImpl) ResolveEvents(p schema.MutationResolveEventsFieldResolverParams) (interface{}, error) {
+	events := make([]*corev2.Event, 0, len(p.Args.Input.Ids))
+	errs := []stdErr{}
+	for _, id := range p.Args.Input.Ids {
+		components, err := decodeEventGID(id)
+		if err != nil {
+			errs = append(errs, newStdErr(id, err))
+			continue
+		}
+		event, err := r.resolveEvent(p.Context, components, p.Args.Input.Source)
+		if err != nil {
+			errs = append(errs, newStdErr(id, err))
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return map[string]interface{}{
+		"clientMutationId": p.Args.Input.ClientMutationID,
+		"events":           events,
+		"errors":           errs,
+	}, nil
+}
+
+// resolveEvent resolves the event with the given components, if it is not
+// resolved yet, and returns it.
+func (r *mutationsImpl) resolveEvent(ctx context.Context, components globalid.EventComponents, source string) (*corev2.Event, error) {
+	ctx = setContextFromComponents(ctx, components)
 	client := r.svc.EventClient
 
 	event, err := client.FetchEvent(ctx, components.EntityName(), components.CheckName())
@@ -233,7 +273,7 @@ func (r *mutationsImpl) ResolveEvent(p schema.MutationResolveEventFieldResolverP
 
 	if event.HasCheck() && event.Check.Status > 0 {
 		event.Check.Status = 0
-		event.Check.Output = "Resolved manually with " + p.Args.Input.Source
+		event.Check.Output = "Resolved manually with " + source
 		event.Check.Executed = int64(time.Now().Unix())
 		event.Timestamp = event.Check.Executed
 
@@ -242,11 +282,7 @@ func (r *mutationsImpl) ResolveEvent(p schema.MutationResolveEventFieldResolverP
 			return nil, err
 		}
 	}
-
-	return map[string]interface{}{
-		"clientMutationId": p.Args.Input.ClientMutationID,
-		"event":            event,
-	}, nil
+	return event, nil
 }
 
 // DeleteEvent implements response to request for the 'deleteEvent' field.
@@ -381,6 +417,42 @@ func (r *mutationsImpl) CreateSilence(p schema.MutationCreateSilenceFieldResolve
 	}, nil
 }
 
+// SilenceEvents implements response to request for the 'silenceEvents' field.
+func (r *mutationsImpl) SilenceEvents(p schema.MutationSilenceEventsFieldResolverParams) (interface{}, error) {
+	inputs := p.Args.Input
+	client := r.svc.SilencedClient
+
+	silences := make([]*corev2.Silenced, 0, len(inputs.Ids))
+	errs := []stdErr{}
+	for _, id := range inputs.Ids {
+		components, err := decodeEventGID(id)
+		if err != nil {
+			errs = append(errs, newStdErr(id, err))
+			continue
+		}
+
+		// Silence the check of the event on its entity
+		var silence corev2.Silenced
+		silence.Check = components.CheckName()
+		silence.Subscription = corev2.GetEntitySubscription(components.EntityName())
+		silence.Namespace = components.Namespace()
+		copySilenceInputs(&silence, inputs.Props)
+
+		ctx := setContextFromComponents(p.Context, components)
+		if err := client.UpdateSilenced(ctx, &silence); err != nil {
+			errs = append(errs, newStdErr(id, err))
+			continue
+		}
+		silences = append(silences, &silence)
+	}
+
+	return map[string]interface{}{
+		"clientMutationId": inputs.ClientMutationID,
+		"silences":         silences,
+		"errors":           errs,
+	}, nil
+}
+
 // DeleteSilence implements response to request for the 'deleteSilence' field.
 func (r *mutationsImpl) DeleteSilence(p schema.MutationDeleteSilenceFieldResolverParams) (interface{}, error) {
 	components, _ := globalid.Parse(p.Args.Input.ID)
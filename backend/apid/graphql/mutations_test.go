@@ -8,9 +8,11 @@ import (
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/backend/apid/graphql/globalid"
 	"github.com/sensu/sensu-go/backend/apid/graphql/schema"
+	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/graphql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMutationTypePutWrappedUpsertTrue(t *testing.T) {
@@ -331,3 +333,79 @@ func TestMutationTypeDeleteSilenceField(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, body)
 }
+
+func TestMutationTypeResolveEventsField(t *testing.T) {
+	failing := corev2.FixtureEvent("a", "b")
+	failing.Check.Status = 2
+	missing := corev2.FixtureEvent("a", "c")
+	inputs := schema.ResolveEventsInput{
+		Ids: []string{
+			globalid.EventTranslator.EncodeToString(context.Background(), failing),
+			globalid.EventTranslator.EncodeToString(context.Background(), missing),
+			"tests",
+		},
+		Source: "tests",
+	}
+	params := schema.MutationResolveEventsFieldResolverParams{ResolveParams: graphql.ResolveParams{Context: context.Background()}}
+	params.Args.Input = &inputs
+
+	client := new(MockEventClient)
+	cfg := ServiceConfig{EventClient: client}
+	impl := mutationsImpl{svc: cfg}
+
+	client.On("FetchEvent", mock.Anything, "a", "b").Return(failing, nil).Once()
+	client.On("UpdateEvent", mock.Anything, failing).Return(nil).Once()
+	client.On("FetchEvent", mock.Anything, "a", "c").Return((*corev2.Event)(nil), &store.ErrNotFound{Key: "c"}).Once()
+	body, err := impl.ResolveEvents(params)
+	require.NoError(t, err)
+
+	payload := body.(map[string]interface{})
+	events := payload["events"].([]*corev2.Event)
+	require.Len(t, events, 1)
+	assert.Equal(t, uint32(0), events[0].Check.Status)
+	assert.Equal(t, "Resolved manually with tests", events[0].Check.Output)
+
+	errs := payload["errors"].([]stdErr)
+	require.Len(t, errs, 2)
+	assert.Equal(t, inputs.Ids[1], errs[0].input)
+	assert.Equal(t, schema.ErrCodes.ERR_NOT_FOUND, errs[0].code)
+	assert.Equal(t, "tests", errs[1].input)
+	client.AssertExpectations(t)
+}
+
+func TestMutationTypeSilenceEventsField(t *testing.T) {
+	evt := corev2.FixtureEvent("a", "b")
+	inputs := schema.SilenceEventsInput{
+		Ids: []string{
+			globalid.EventTranslator.EncodeToString(context.Background(), evt),
+			globalid.EventTranslator.EncodeToString(context.Background(), corev2.FixtureEvent("a", "c")),
+		},
+		Props: &schema.SilenceInputs{Reason: "maintenance"},
+	}
+	params := schema.MutationSilenceEventsFieldResolverParams{ResolveParams: graphql.ResolveParams{Context: context.Background()}}
+	params.Args.Input = &inputs
+
+	client := new(MockSilencedClient)
+	cfg := ServiceConfig{SilencedClient: client}
+	impl := mutationsImpl{svc: cfg}
+
+	client.On("UpdateSilenced", mock.Anything, mock.MatchedBy(func(s *corev2.Silenced) bool {
+		return s.Check == "b"
+	})).Return(nil).Once()
+	client.On("UpdateSilenced", mock.Anything, mock.Anything).Return(errors.New("test")).Once()
+	body, err := impl.SilenceEvents(params)
+	require.NoError(t, err)
+
+	payload := body.(map[string]interface{})
+	silences := payload["silences"].([]*corev2.Silenced)
+	require.Len(t, silences, 1)
+	assert.Equal(t, "entity:a", silences[0].Subscription)
+	assert.Equal(t, "b", silences[0].Check)
+	assert.Equal(t, "default", silences[0].Namespace)
+	assert.Equal(t, "maintenance", silences[0].Reason)
+
+	errs := payload["errors"].([]stdErr)
+	require.Len(t, errs, 1)
+	assert.Equal(t, inputs.Ids[1], errs[0].input)
+	client.AssertExpectations(t)
+}
@@ -3,8 +3,10 @@
 package schema
 
 import (
+	errors "errors"
 	graphql1 "github.com/graphql-go/graphql"
 	graphql "github.com/sensu/sensu-go/graphql"
+	time "time"
 )
 
 // CoreV3EntityConfigExtensionOverridesFieldResolvers represents a collection of methods whose products represent the
@@ -129,6 +131,9 @@ type CoreV3EntityStateExtensionOverridesFieldResolvers interface {
 
 	// ToCoreV2Entity implements response to request for 'toCoreV2Entity' field.
 	ToCoreV2Entity(p graphql.ResolveParams) (interface{}, error)
+
+	// SystemMetrics implements response to request for 'systemMetrics' field.
+	SystemMetrics(p graphql.ResolveParams) (interface{}, error)
 }
 
 // RegisterCoreV3EntityStateExtensionOverrides registers CoreV3EntityStateExtensionOverrides object type with given service.
@@ -172,6 +177,15 @@ func _ObjTypeCoreV3EntityStateExtensionOverridesToCoreV2EntityHandler(impl inter
 	}
 }
 
+func _ObjTypeCoreV3EntityStateExtensionOverridesSystemMetricsHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		SystemMetrics(p graphql.ResolveParams) (interface{}, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.SystemMetrics(frp)
+	}
+}
+
 func _ObjectExtensionTypeCoreV3EntityStateExtensionOverridesConfigFn() graphql1.ObjectConfig {
 	return graphql1.ObjectConfig{
 		Description: "",
@@ -190,6 +204,13 @@ func _ObjectExtensionTypeCoreV3EntityStateExtensionOverridesConfigFn() graphql1.
 				Name:              "id",
 				Type:              graphql1.NewNonNull(graphql1.ID),
 			},
+			"systemMetrics": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "Snapshot of the system metrics sent with the last keepalive of the agent, if\nthe agent is configured to send them.",
+				Name:              "systemMetrics",
+				Type:              graphql.OutputType("SystemMetrics"),
+			},
 			"toCoreV2Entity": &graphql1.Field{
 				Args:              graphql1.FieldConfigArgument{},
 				DeprecationReason: "",
@@ -218,7 +239,251 @@ var _ObjectExtensionTypeCoreV3EntityStateExtensionOverridesDesc = graphql.Object
 	FieldHandlers: map[string]graphql.FieldHandler{
 		"config":         _ObjTypeCoreV3EntityStateExtensionOverridesConfigHandler,
 		"id":             _ObjTypeCoreV3EntityStateExtensionOverridesIDHandler,
+		"systemMetrics":  _ObjTypeCoreV3EntityStateExtensionOverridesSystemMetricsHandler,
 		"toCoreV2Entity": _ObjTypeCoreV3EntityStateExtensionOverridesToCoreV2EntityHandler,
 		"toJSON":         _ObjTypeCoreV3EntityStateExtensionOverridesToJSONHandler,
 	},
 }
+
+// SystemMetricsFieldResolvers represents a collection of methods whose products represent the
+// response values of the 'SystemMetrics' type.
+type SystemMetricsFieldResolvers interface {
+	// Timestamp implements response to request for 'timestamp' field.
+	Timestamp(p graphql.ResolveParams) (time.Time, error)
+
+	// Load1 implements response to request for 'load1' field.
+	Load1(p graphql.ResolveParams) (float64, error)
+
+	// Load5 implements response to request for 'load5' field.
+	Load5(p graphql.ResolveParams) (float64, error)
+
+	// Load15 implements response to request for 'load15' field.
+	Load15(p graphql.ResolveParams) (float64, error)
+
+	// MemoryUsedPercent implements response to request for 'memoryUsedPercent' field.
+	MemoryUsedPercent(p graphql.ResolveParams) (float64, error)
+
+	// DiskUsedPercent implements response to request for 'diskUsedPercent' field.
+	DiskUsedPercent(p graphql.ResolveParams) (float64, error)
+}
+
+// SystemMetricsAliases implements all methods on SystemMetricsFieldResolvers interface by using reflection to
+// match name of field to a field on the given value. Intent is reduce friction
+// of writing new resolvers by removing all the instances where you would simply
+// have the resolvers method return a field.
+type SystemMetricsAliases struct{}
+
+// Timestamp implements response to request for 'timestamp' field.
+func (_ SystemMetricsAliases) Timestamp(p graphql.ResolveParams) (time.Time, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	ret, ok := val.(time.Time)
+	if err != nil {
+		return ret, err
+	}
+	if !ok {
+		return ret, errors.New("unable to coerce value for field 'timestamp'")
+	}
+	return ret, err
+}
+
+// Load1 implements response to request for 'load1' field.
+func (_ SystemMetricsAliases) Load1(p graphql.ResolveParams) (float64, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	ret, ok := graphql1.Float.ParseValue(val).(float64)
+	if err != nil {
+		return ret, err
+	}
+	if !ok {
+		return ret, errors.New("unable to coerce value for field 'load1'")
+	}
+	return ret, err
+}
+
+// Load5 implements response to request for 'load5' field.
+func (_ SystemMetricsAliases) Load5(p graphql.ResolveParams) (float64, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	ret, ok := graphql1.Float.ParseValue(val).(float64)
+	if err != nil {
+		return ret, err
+	}
+	if !ok {
+		return ret, errors.New("unable to coerce value for field 'load5'")
+	}
+	return ret, err
+}
+
+// Load15 implements response to request for 'load15' field.
+func (_ SystemMetricsAliases) Load15(p graphql.ResolveParams) (float64, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	ret, ok := graphql1.Float.ParseValue(val).(float64)
+	if err != nil {
+		return ret, err
+	}
+	if !ok {
+		return ret, errors.New("unable to coerce value for field 'load15'")
+	}
+	return ret, err
+}
+
+// MemoryUsedPercent implements response to request for 'memoryUsedPercent' field.
+func (_ SystemMetricsAliases) MemoryUsedPercent(p graphql.ResolveParams) (float64, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	ret, ok := graphql1.Float.ParseValue(val).(float64)
+	if err != nil {
+		return ret, err
+	}
+	if !ok {
+		return ret, errors.New("unable to coerce value for field 'memoryUsedPercent'")
+	}
+	return ret, err
+}
+
+// DiskUsedPercent implements response to request for 'diskUsedPercent' field.
+func (_ SystemMetricsAliases) DiskUsedPercent(p graphql.ResolveParams) (float64, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	ret, ok := graphql1.Float.ParseValue(val).(float64)
+	if err != nil {
+		return ret, err
+	}
+	if !ok {
+		return ret, errors.New("unable to coerce value for field 'diskUsedPercent'")
+	}
+	return ret, err
+}
+
+/*
+SystemMetricsType SystemMetrics is a snapshot of the load, memory and disk usage of the system
+that the Agent process is running on.
+*/
+var SystemMetricsType = graphql.NewType("SystemMetrics", graphql.ObjectKind)
+
+// RegisterSystemMetrics registers SystemMetrics object type with given service.
+func RegisterSystemMetrics(svc *graphql.Service, impl SystemMetricsFieldResolvers) {
+	svc.RegisterObject(_ObjectTypeSystemMetricsDesc, impl)
+}
+func _ObjTypeSystemMetricsTimestampHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		Timestamp(p graphql.ResolveParams) (time.Time, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.Timestamp(frp)
+	}
+}
+
+func _ObjTypeSystemMetricsLoad1Handler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		Load1(p graphql.ResolveParams) (float64, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.Load1(frp)
+	}
+}
+
+func _ObjTypeSystemMetricsLoad5Handler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		Load5(p graphql.ResolveParams) (float64, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.Load5(frp)
+	}
+}
+
+func _ObjTypeSystemMetricsLoad15Handler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		Load15(p graphql.ResolveParams) (float64, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.Load15(frp)
+	}
+}
+
+func _ObjTypeSystemMetricsMemoryUsedPercentHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		MemoryUsedPercent(p graphql.ResolveParams) (float64, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.MemoryUsedPercent(frp)
+	}
+}
+
+func _ObjTypeSystemMetricsDiskUsedPercentHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		DiskUsedPercent(p graphql.ResolveParams) (float64, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.DiskUsedPercent(frp)
+	}
+}
+
+func _ObjectTypeSystemMetricsConfigFn() graphql1.ObjectConfig {
+	return graphql1.ObjectConfig{
+		Description: "SystemMetrics is a snapshot of the load, memory and disk usage of the system\nthat the Agent process is running on.",
+		Fields: graphql1.Fields{
+			"diskUsedPercent": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "Percentage of the root filesystem in use.",
+				Name:              "diskUsedPercent",
+				Type:              graphql1.NewNonNull(graphql1.Float),
+			},
+			"load1": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "Load averages over the last 1, 5 and 15 minutes; always 0 on Windows.",
+				Name:              "load1",
+				Type:              graphql1.NewNonNull(graphql1.Float),
+			},
+			"load15": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "self descriptive",
+				Name:              "load15",
+				Type:              graphql1.NewNonNull(graphql1.Float),
+			},
+			"load5": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "self descriptive",
+				Name:              "load5",
+				Type:              graphql1.NewNonNull(graphql1.Float),
+			},
+			"memoryUsedPercent": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "Percentage of the memory in use.",
+				Name:              "memoryUsedPercent",
+				Type:              graphql1.NewNonNull(graphql1.Float),
+			},
+			"timestamp": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "Time at which the snapshot was taken.",
+				Name:              "timestamp",
+				Type:              graphql1.NewNonNull(graphql1.DateTime),
+			},
+		},
+		Interfaces: []*graphql1.Interface{},
+		IsTypeOf: func(_ graphql1.IsTypeOfParams) bool {
+			// NOTE:
+			// Panic by default. Intent is that when Service is invoked, values of
+			// these fields are updated with instantiated resolvers. If these
+			// defaults are called it is most certainly programmer err.
+			// If you're see this comment then: 'Whoops! Sorry, my bad.'
+			panic("Unimplemented; see SystemMetricsFieldResolvers.")
+		},
+		Name: "SystemMetrics",
+	}
+}
+
+// describe SystemMetrics's configuration; kept private to avoid unintentional tampering of configuration at runtime.
+var _ObjectTypeSystemMetricsDesc = graphql.ObjectDesc{
+	Config: _ObjectTypeSystemMetricsConfigFn,
+	FieldHandlers: map[string]graphql.FieldHandler{
+		"diskUsedPercent":   _ObjTypeSystemMetricsDiskUsedPercentHandler,
+		"load1":             _ObjTypeSystemMetricsLoad1Handler,
+		"load15":            _ObjTypeSystemMetricsLoad15Handler,
+		"load5":             _ObjTypeSystemMetricsLoad5Handler,
+		"memoryUsedPercent": _ObjTypeSystemMetricsMemoryUsedPercentHandler,
+		"timestamp":         _ObjTypeSystemMetricsTimestampHandler,
+	},
+}
@@ -92,6 +92,28 @@ type MutationResolveEventFieldResolverParams struct {
 	Args MutationResolveEventFieldResolverArgs
 }
 
+// MutationResolveEventsFieldResolverArgs contains arguments provided to resolveEvents when selected
+type MutationResolveEventsFieldResolverArgs struct {
+	Input *ResolveEventsInput // Input - self descriptive
+}
+
+// MutationResolveEventsFieldResolverParams contains contextual info to resolve resolveEvents field
+type MutationResolveEventsFieldResolverParams struct {
+	graphql.ResolveParams
+	Args MutationResolveEventsFieldResolverArgs
+}
+
+// MutationSilenceEventsFieldResolverArgs contains arguments provided to silenceEvents when selected
+type MutationSilenceEventsFieldResolverArgs struct {
+	Input *SilenceEventsInput // Input - self descriptive
+}
+
+// MutationSilenceEventsFieldResolverParams contains contextual info to resolve silenceEvents field
+type MutationSilenceEventsFieldResolverParams struct {
+	graphql.ResolveParams
+	Args MutationSilenceEventsFieldResolverArgs
+}
+
 // MutationDeleteEventFieldResolverArgs contains arguments provided to deleteEvent when selected
 type MutationDeleteEventFieldResolverArgs struct {
 	Input *DeleteRecordInput // Input - self descriptive
@@ -182,6 +204,12 @@ type MutationFieldResolvers interface {
 	// ResolveEvent implements response to request for 'resolveEvent' field.
 	ResolveEvent(p MutationResolveEventFieldResolverParams) (interface{}, error)
 
+	// ResolveEvents implements response to request for 'resolveEvents' field.
+	ResolveEvents(p MutationResolveEventsFieldResolverParams) (interface{}, error)
+
+	// SilenceEvents implements response to request for 'silenceEvents' field.
+	SilenceEvents(p MutationSilenceEventsFieldResolverParams) (interface{}, error)
+
 	// DeleteEvent implements response to request for 'deleteEvent' field.
 	DeleteEvent(p MutationDeleteEventFieldResolverParams) (interface{}, error)
 
@@ -249,6 +277,18 @@ func (_ MutationAliases) ResolveEvent(p MutationResolveEventFieldResolverParams)
 	return val, err
 }
 
+// ResolveEvents implements response to request for 'resolveEvents' field.
+func (_ MutationAliases) ResolveEvents(p MutationResolveEventsFieldResolverParams) (interface{}, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	return val, err
+}
+
+// SilenceEvents implements response to request for 'silenceEvents' field.
+func (_ MutationAliases) SilenceEvents(p MutationSilenceEventsFieldResolverParams) (interface{}, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	return val, err
+}
+
 // DeleteEvent implements response to request for 'deleteEvent' field.
 func (_ MutationAliases) DeleteEvent(p MutationDeleteEventFieldResolverParams) (interface{}, error) {
 	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
@@ -397,6 +437,36 @@ func _ObjTypeMutationResolveEventHandler(impl interface{}) graphql1.FieldResolve
 	}
 }
 
+func _ObjTypeMutationResolveEventsHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		ResolveEvents(p MutationResolveEventsFieldResolverParams) (interface{}, error)
+	})
+	return func(p graphql1.ResolveParams) (interface{}, error) {
+		frp := MutationResolveEventsFieldResolverParams{ResolveParams: p}
+		err := mapstructure.Decode(p.Args, &frp.Args)
+		if err != nil {
+			return nil, err
+		}
+
+		return resolver.ResolveEvents(frp)
+	}
+}
+
+func _ObjTypeMutationSilenceEventsHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		SilenceEvents(p MutationSilenceEventsFieldResolverParams) (interface{}, error)
+	})
+	return func(p graphql1.ResolveParams) (interface{}, error) {
+		frp := MutationSilenceEventsFieldResolverParams{ResolveParams: p}
+		err := mapstructure.Decode(p.Args, &frp.Args)
+		if err != nil {
+			return nil, err
+		}
+
+		return resolver.SilenceEvents(frp)
+	}
+}
+
 func _ObjTypeMutationDeleteEventHandler(impl interface{}) graphql1.FieldResolveFn {
 	resolver := impl.(interface {
 		DeleteEvent(p MutationDeleteEventFieldResolverParams) (interface{}, error)
@@ -618,6 +688,26 @@ func _ObjectTypeMutationConfigFn() graphql1.ObjectConfig {
 				Name:              "resolveEvent",
 				Type:              graphql.OutputType("ResolveEventPayload"),
 			},
+			"resolveEvents": &graphql1.Field{
+				Args: graphql1.FieldConfigArgument{"input": &graphql1.ArgumentConfig{
+					Description: "self descriptive",
+					Type:        graphql1.NewNonNull(graphql.InputType("ResolveEventsInput")),
+				}},
+				DeprecationReason: "",
+				Description:       "Resolves the given events; the events that fail to resolve are reported.",
+				Name:              "resolveEvents",
+				Type:              graphql.OutputType("ResolveEventsPayload"),
+			},
+			"silenceEvents": &graphql1.Field{
+				Args: graphql1.FieldConfigArgument{"input": &graphql1.ArgumentConfig{
+					Description: "self descriptive",
+					Type:        graphql1.NewNonNull(graphql.InputType("SilenceEventsInput")),
+				}},
+				DeprecationReason: "",
+				Description:       "Silences the given events; the events that fail to silence are reported.",
+				Name:              "silenceEvents",
+				Type:              graphql.OutputType("SilenceEventsPayload"),
+			},
 			"updateCheck": &graphql1.Field{
 				Args: graphql1.FieldConfigArgument{"input": &graphql1.ArgumentConfig{
 					Description: "self descriptive",
@@ -658,6 +748,8 @@ var _ObjectTypeMutationDesc = graphql.ObjectDesc{
 		"executeCheck":      _ObjTypeMutationExecuteCheckHandler,
 		"putWrapped":        _ObjTypeMutationPutWrappedHandler,
 		"resolveEvent":      _ObjTypeMutationResolveEventHandler,
+		"resolveEvents":     _ObjTypeMutationResolveEventsHandler,
+		"silenceEvents":     _ObjTypeMutationSilenceEventsHandler,
 		"updateCheck":       _ObjTypeMutationUpdateCheckHandler,
 	},
 }
@@ -1560,6 +1652,346 @@ var _ObjectTypeResolveEventPayloadDesc = graphql.ObjectDesc{
 	},
 }
 
+// ResolveEventsInput self descriptive
+type ResolveEventsInput struct {
+	// ClientMutationID - A unique identifier for the client performing the mutation.
+	ClientMutationID string
+	// Ids - Global IDs of the events to resolve.
+	Ids []string
+	// Source - The source of the resolve request
+	Source string
+}
+
+// ResolveEventsInputType self descriptive
+var ResolveEventsInputType = graphql.NewType("ResolveEventsInput", graphql.InputKind)
+
+// RegisterResolveEventsInput registers ResolveEventsInput object type with given service.
+func RegisterResolveEventsInput(svc *graphql.Service) {
+	svc.RegisterInput(_InputTypeResolveEventsInputDesc)
+}
+func _InputTypeResolveEventsInputConfigFn() graphql1.InputObjectConfig {
+	return graphql1.InputObjectConfig{
+		Description: "self descriptive",
+		Fields: graphql1.InputObjectConfigFieldMap{
+			"clientMutationId": &graphql1.InputObjectFieldConfig{
+				Description: "A unique identifier for the client performing the mutation.",
+				Type:        graphql1.String,
+			},
+			"ids": &graphql1.InputObjectFieldConfig{
+				Description: "Global IDs of the events to resolve.",
+				Type:        graphql1.NewNonNull(graphql1.NewList(graphql1.NewNonNull(graphql1.ID))),
+			},
+			"source": &graphql1.InputObjectFieldConfig{
+				DefaultValue: "GraphQL",
+				Description:  "The source of the resolve request",
+				Type:         graphql1.String,
+			},
+		},
+		Name: "ResolveEventsInput",
+	}
+}
+
+// describe ResolveEventsInput's configuration; kept private to avoid unintentional tampering of configuration at runtime.
+var _InputTypeResolveEventsInputDesc = graphql.InputDesc{Config: _InputTypeResolveEventsInputConfigFn}
+
+// ResolveEventsPayloadFieldResolvers represents a collection of methods whose products represent the
+// response values of the 'ResolveEventsPayload' type.
+type ResolveEventsPayloadFieldResolvers interface {
+	// ClientMutationID implements response to request for 'clientMutationId' field.
+	ClientMutationID(p graphql.ResolveParams) (string, error)
+
+	// Events implements response to request for 'events' field.
+	Events(p graphql.ResolveParams) (interface{}, error)
+
+	// Errors implements response to request for 'errors' field.
+	Errors(p graphql.ResolveParams) (interface{}, error)
+}
+
+// ResolveEventsPayloadAliases implements all methods on ResolveEventsPayloadFieldResolvers interface by using reflection to
+// match name of field to a field on the given value. Intent is reduce friction
+// of writing new resolvers by removing all the instances where you would simply
+// have the resolvers method return a field.
+type ResolveEventsPayloadAliases struct{}
+
+// ClientMutationID implements response to request for 'clientMutationId' field.
+func (_ ResolveEventsPayloadAliases) ClientMutationID(p graphql.ResolveParams) (string, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	ret, ok := val.(string)
+	if err != nil {
+		return ret, err
+	}
+	if !ok {
+		return ret, errors.New("unable to coerce value for field 'clientMutationId'")
+	}
+	return ret, err
+}
+
+// Events implements response to request for 'events' field.
+func (_ ResolveEventsPayloadAliases) Events(p graphql.ResolveParams) (interface{}, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	return val, err
+}
+
+// Errors implements response to request for 'errors' field.
+func (_ ResolveEventsPayloadAliases) Errors(p graphql.ResolveParams) (interface{}, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	return val, err
+}
+
+// ResolveEventsPayloadType self descriptive
+var ResolveEventsPayloadType = graphql.NewType("ResolveEventsPayload", graphql.ObjectKind)
+
+// RegisterResolveEventsPayload registers ResolveEventsPayload object type with given service.
+func RegisterResolveEventsPayload(svc *graphql.Service, impl ResolveEventsPayloadFieldResolvers) {
+	svc.RegisterObject(_ObjectTypeResolveEventsPayloadDesc, impl)
+}
+func _ObjTypeResolveEventsPayloadClientMutationIDHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		ClientMutationID(p graphql.ResolveParams) (string, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.ClientMutationID(frp)
+	}
+}
+
+func _ObjTypeResolveEventsPayloadEventsHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		Events(p graphql.ResolveParams) (interface{}, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.Events(frp)
+	}
+}
+
+func _ObjTypeResolveEventsPayloadErrorsHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		Errors(p graphql.ResolveParams) (interface{}, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.Errors(frp)
+	}
+}
+
+func _ObjectTypeResolveEventsPayloadConfigFn() graphql1.ObjectConfig {
+	return graphql1.ObjectConfig{
+		Description: "self descriptive",
+		Fields: graphql1.Fields{
+			"clientMutationId": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "A unique identifier for the client performing the mutation.",
+				Name:              "clientMutationId",
+				Type:              graphql1.String,
+			},
+			"errors": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "Includes the errors of the events that could not be resolved; the input of\neach error is the global ID of its event.",
+				Name:              "errors",
+				Type:              graphql1.NewNonNull(graphql1.NewList(graphql1.NewNonNull(graphql.OutputType("Error")))),
+			},
+			"events": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "The events that were resolved.",
+				Name:              "events",
+				Type:              graphql1.NewNonNull(graphql1.NewList(graphql1.NewNonNull(graphql.OutputType("Event")))),
+			},
+		},
+		Interfaces: []*graphql1.Interface{},
+		IsTypeOf: func(_ graphql1.IsTypeOfParams) bool {
+			// NOTE:
+			// Panic by default. Intent is that when Service is invoked, values of
+			// these fields are updated with instantiated resolvers. If these
+			// defaults are called it is most certainly programmer err.
+			// If you're see this comment then: 'Whoops! Sorry, my bad.'
+			panic("Unimplemented; see ResolveEventsPayloadFieldResolvers.")
+		},
+		Name: "ResolveEventsPayload",
+	}
+}
+
+// describe ResolveEventsPayload's configuration; kept private to avoid unintentional tampering of configuration at runtime.
+var _ObjectTypeResolveEventsPayloadDesc = graphql.ObjectDesc{
+	Config: _ObjectTypeResolveEventsPayloadConfigFn,
+	FieldHandlers: map[string]graphql.FieldHandler{
+		"clientMutationId": _ObjTypeResolveEventsPayloadClientMutationIDHandler,
+		"errors":           _ObjTypeResolveEventsPayloadErrorsHandler,
+		"events":           _ObjTypeResolveEventsPayloadEventsHandler,
+	},
+}
+
+// SilenceEventsInput self descriptive
+type SilenceEventsInput struct {
+	// ClientMutationID - A unique identifier for the client performing the mutation.
+	ClientMutationID string
+	/*
+	   Ids - Global IDs of the events to silence. The check of each event is silenced on
+	   its entity.
+	*/
+	Ids []string
+	// Props - properties of the silences
+	Props *SilenceInputs
+}
+
+// SilenceEventsInputType self descriptive
+var SilenceEventsInputType = graphql.NewType("SilenceEventsInput", graphql.InputKind)
+
+// RegisterSilenceEventsInput registers SilenceEventsInput object type with given service.
+func RegisterSilenceEventsInput(svc *graphql.Service) {
+	svc.RegisterInput(_InputTypeSilenceEventsInputDesc)
+}
+func _InputTypeSilenceEventsInputConfigFn() graphql1.InputObjectConfig {
+	return graphql1.InputObjectConfig{
+		Description: "self descriptive",
+		Fields: graphql1.InputObjectConfigFieldMap{
+			"clientMutationId": &graphql1.InputObjectFieldConfig{
+				Description: "A unique identifier for the client performing the mutation.",
+				Type:        graphql1.String,
+			},
+			"ids": &graphql1.InputObjectFieldConfig{
+				Description: "Global IDs of the events to silence. The check of each event is silenced on\nits entity.",
+				Type:        graphql1.NewNonNull(graphql1.NewList(graphql1.NewNonNull(graphql1.ID))),
+			},
+			"props": &graphql1.InputObjectFieldConfig{
+				Description: "properties of the silences",
+				Type:        graphql1.NewNonNull(graphql.InputType("SilenceInputs")),
+			},
+		},
+		Name: "SilenceEventsInput",
+	}
+}
+
+// describe SilenceEventsInput's configuration; kept private to avoid unintentional tampering of configuration at runtime.
+var _InputTypeSilenceEventsInputDesc = graphql.InputDesc{Config: _InputTypeSilenceEventsInputConfigFn}
+
+// SilenceEventsPayloadFieldResolvers represents a collection of methods whose products represent the
+// response values of the 'SilenceEventsPayload' type.
+type SilenceEventsPayloadFieldResolvers interface {
+	// ClientMutationID implements response to request for 'clientMutationId' field.
+	ClientMutationID(p graphql.ResolveParams) (string, error)
+
+	// Silences implements response to request for 'silences' field.
+	Silences(p graphql.ResolveParams) (interface{}, error)
+
+	// Errors implements response to request for 'errors' field.
+	Errors(p graphql.ResolveParams) (interface{}, error)
+}
+
+// SilenceEventsPayloadAliases implements all methods on SilenceEventsPayloadFieldResolvers interface by using reflection to
+// match name of field to a field on the given value. Intent is reduce friction
+// of writing new resolvers by removing all the instances where you would simply
+// have the resolvers method return a field.
+type SilenceEventsPayloadAliases struct{}
+
+// ClientMutationID implements response to request for 'clientMutationId' field.
+func (_ SilenceEventsPayloadAliases) ClientMutationID(p graphql.ResolveParams) (string, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	ret, ok := val.(string)
+	if err != nil {
+		return ret, err
+	}
+	if !ok {
+		return ret, errors.New("unable to coerce value for field 'clientMutationId'")
+	}
+	return ret, err
+}
+
+// Silences implements response to request for 'silences' field.
+func (_ SilenceEventsPayloadAliases) Silences(p graphql.ResolveParams) (interface{}, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	return val, err
+}
+
+// Errors implements response to request for 'errors' field.
+func (_ SilenceEventsPayloadAliases) Errors(p graphql.ResolveParams) (interface{}, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	return val, err
+}
+
+// SilenceEventsPayloadType self descriptive
+var SilenceEventsPayloadType = graphql.NewType("SilenceEventsPayload", graphql.ObjectKind)
+
+// RegisterSilenceEventsPayload registers SilenceEventsPayload object type with given service.
+func RegisterSilenceEventsPayload(svc *graphql.Service, impl SilenceEventsPayloadFieldResolvers) {
+	svc.RegisterObject(_ObjectTypeSilenceEventsPayloadDesc, impl)
+}
+func _ObjTypeSilenceEventsPayloadClientMutationIDHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		ClientMutationID(p graphql.ResolveParams) (string, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.ClientMutationID(frp)
+	}
+}
+
+func _ObjTypeSilenceEventsPayloadSilencesHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		Silences(p graphql.ResolveParams) (interface{}, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.Silences(frp)
+	}
+}
+
+func _ObjTypeSilenceEventsPayloadErrorsHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		Errors(p graphql.ResolveParams) (interface{}, error)
+	})
+	return func(frp graphql1.ResolveParams) (interface{}, error) {
+		return resolver.Errors(frp)
+	}
+}
+
+func _ObjectTypeSilenceEventsPayloadConfigFn() graphql1.ObjectConfig {
+	return graphql1.ObjectConfig{
+		Description: "self descriptive",
+		Fields: graphql1.Fields{
+			"clientMutationId": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "A unique identifier for the client performing the mutation.",
+				Name:              "clientMutationId",
+				Type:              graphql1.String,
+			},
+			"errors": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "Includes the errors of the events that could not be silenced; the input of\neach error is the global ID of its event.",
+				Name:              "errors",
+				Type:              graphql1.NewNonNull(graphql1.NewList(graphql1.NewNonNull(graphql.OutputType("Error")))),
+			},
+			"silences": &graphql1.Field{
+				Args:              graphql1.FieldConfigArgument{},
+				DeprecationReason: "",
+				Description:       "The newly created silences.",
+				Name:              "silences",
+				Type:              graphql1.NewNonNull(graphql1.NewList(graphql1.NewNonNull(graphql.OutputType("Silenced")))),
+			},
+		},
+		Interfaces: []*graphql1.Interface{},
+		IsTypeOf: func(_ graphql1.IsTypeOfParams) bool {
+			// NOTE:
+			// Panic by default. Intent is that when Service is invoked, values of
+			// these fields are updated with instantiated resolvers. If these
+			// defaults are called it is most certainly programmer err.
+			// If you're see this comment then: 'Whoops! Sorry, my bad.'
+			panic("Unimplemented; see SilenceEventsPayloadFieldResolvers.")
+		},
+		Name: "SilenceEventsPayload",
+	}
+}
+
+// describe SilenceEventsPayload's configuration; kept private to avoid unintentional tampering of configuration at runtime.
+var _ObjectTypeSilenceEventsPayloadDesc = graphql.ObjectDesc{
+	Config: _ObjectTypeSilenceEventsPayloadConfigFn,
+	FieldHandlers: map[string]graphql.FieldHandler{
+		"clientMutationId": _ObjTypeSilenceEventsPayloadClientMutationIDHandler,
+		"errors":           _ObjTypeSilenceEventsPayloadErrorsHandler,
+		"silences":         _ObjTypeSilenceEventsPayloadSilencesHandler,
+	},
+}
+
 // CreateSilenceInput self descriptive
 type CreateSilenceInput struct {
 	// ClientMutationID - A unique identifier for the client performing the mutation.
@@ -17,8 +17,9 @@ func RegisterSchema(svc *graphql.Service) {
 }
 func _SchemaConfigFn() graphql1.SchemaConfig {
 	return graphql1.SchemaConfig{
-		Mutation: graphql.Object("Mutation"),
-		Query:    graphql.Object("Query"),
+		Mutation:     graphql.Object("Mutation"),
+		Query:        graphql.Object("Query"),
+		Subscription: graphql.Object("Subscription"),
 	}
 }
 
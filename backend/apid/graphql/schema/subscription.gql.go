@@ -0,0 +1,143 @@
+// Code generated by scripts/gengraphql.go. DO NOT EDIT.
+
+package schema
+
+import (
+	graphql1 "github.com/graphql-go/graphql"
+	mapstructure "github.com/mitchellh/mapstructure"
+	graphql "github.com/sensu/sensu-go/graphql"
+)
+
+// SubscriptionEventsFieldResolverArgs contains arguments provided to events when selected
+type SubscriptionEventsFieldResolverArgs struct {
+	Namespace string // Namespace - self descriptive
+}
+
+// SubscriptionEventsFieldResolverParams contains contextual info to resolve events field
+type SubscriptionEventsFieldResolverParams struct {
+	graphql.ResolveParams
+	Args SubscriptionEventsFieldResolverArgs
+}
+
+// SubscriptionEntityStatusFieldResolverArgs contains arguments provided to entityStatus when selected
+type SubscriptionEntityStatusFieldResolverArgs struct {
+	Namespace string // Namespace - self descriptive
+}
+
+// SubscriptionEntityStatusFieldResolverParams contains contextual info to resolve entityStatus field
+type SubscriptionEntityStatusFieldResolverParams struct {
+	graphql.ResolveParams
+	Args SubscriptionEntityStatusFieldResolverArgs
+}
+
+// SubscriptionFieldResolvers represents a collection of methods whose products represent the
+// response values of the 'Subscription' type.
+type SubscriptionFieldResolvers interface {
+	// Events implements response to request for 'events' field.
+	Events(p SubscriptionEventsFieldResolverParams) (interface{}, error)
+
+	// EntityStatus implements response to request for 'entityStatus' field.
+	EntityStatus(p SubscriptionEntityStatusFieldResolverParams) (interface{}, error)
+}
+
+// SubscriptionAliases implements all methods on SubscriptionFieldResolvers interface by using reflection to
+// match name of field to a field on the given value. Intent is reduce friction
+// of writing new resolvers by removing all the instances where you would simply
+// have the resolvers method return a field.
+type SubscriptionAliases struct{}
+
+// Events implements response to request for 'events' field.
+func (_ SubscriptionAliases) Events(p SubscriptionEventsFieldResolverParams) (interface{}, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	return val, err
+}
+
+// EntityStatus implements response to request for 'entityStatus' field.
+func (_ SubscriptionAliases) EntityStatus(p SubscriptionEntityStatusFieldResolverParams) (interface{}, error) {
+	val, err := graphql.DefaultResolver(p.Source, p.Info.FieldName)
+	return val, err
+}
+
+// SubscriptionType The root type of the subscriptions, which send live updates over websockets.
+var SubscriptionType = graphql.NewType("Subscription", graphql.ObjectKind)
+
+// RegisterSubscription registers Subscription object type with given service.
+func RegisterSubscription(svc *graphql.Service, impl SubscriptionFieldResolvers) {
+	svc.RegisterObject(_ObjectTypeSubscriptionDesc, impl)
+}
+func _ObjTypeSubscriptionEventsHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		Events(p SubscriptionEventsFieldResolverParams) (interface{}, error)
+	})
+	return func(p graphql1.ResolveParams) (interface{}, error) {
+		frp := SubscriptionEventsFieldResolverParams{ResolveParams: p}
+		err := mapstructure.Decode(p.Args, &frp.Args)
+		if err != nil {
+			return nil, err
+		}
+
+		return resolver.Events(frp)
+	}
+}
+
+func _ObjTypeSubscriptionEntityStatusHandler(impl interface{}) graphql1.FieldResolveFn {
+	resolver := impl.(interface {
+		EntityStatus(p SubscriptionEntityStatusFieldResolverParams) (interface{}, error)
+	})
+	return func(p graphql1.ResolveParams) (interface{}, error) {
+		frp := SubscriptionEntityStatusFieldResolverParams{ResolveParams: p}
+		err := mapstructure.Decode(p.Args, &frp.Args)
+		if err != nil {
+			return nil, err
+		}
+
+		return resolver.EntityStatus(frp)
+	}
+}
+
+func _ObjectTypeSubscriptionConfigFn() graphql1.ObjectConfig {
+	return graphql1.ObjectConfig{
+		Description: "The root type of the subscriptions, which send live updates over websockets.",
+		Fields: graphql1.Fields{
+			"entityStatus": &graphql1.Field{
+				Args: graphql1.FieldConfigArgument{"namespace": &graphql1.ArgumentConfig{
+					Description: "self descriptive",
+					Type:        graphql1.NewNonNull(graphql1.String),
+				}},
+				DeprecationReason: "",
+				Description:       "Sends an entity of the namespace each time one of its events changes check\nstatus.",
+				Name:              "entityStatus",
+				Type:              graphql1.NewNonNull(graphql.OutputType("Entity")),
+			},
+			"events": &graphql1.Field{
+				Args: graphql1.FieldConfigArgument{"namespace": &graphql1.ArgumentConfig{
+					Description: "self descriptive",
+					Type:        graphql1.NewNonNull(graphql1.String),
+				}},
+				DeprecationReason: "",
+				Description:       "Sends the events of the namespace as they are processed by the backend.",
+				Name:              "events",
+				Type:              graphql1.NewNonNull(graphql.OutputType("Event")),
+			},
+		},
+		Interfaces: []*graphql1.Interface{},
+		IsTypeOf: func(_ graphql1.IsTypeOfParams) bool {
+			// NOTE:
+			// Panic by default. Intent is that when Service is invoked, values of
+			// these fields are updated with instantiated resolvers. If these
+			// defaults are called it is most certainly programmer err.
+			// If you're see this comment then: 'Whoops! Sorry, my bad.'
+			panic("Unimplemented; see SubscriptionFieldResolvers.")
+		},
+		Name: "Subscription",
+	}
+}
+
+// describe Subscription's configuration; kept private to avoid unintentional tampering of configuration at runtime.
+var _ObjectTypeSubscriptionDesc = graphql.ObjectDesc{
+	Config: _ObjectTypeSubscriptionConfigFn,
+	FieldHandlers: map[string]graphql.FieldHandler{
+		"entityStatus": _ObjTypeSubscriptionEntityStatusHandler,
+		"events":       _ObjTypeSubscriptionEventsHandler,
+	},
+}
@@ -2,9 +2,12 @@ package graphql
 
 import (
 	"context"
+	"errors"
 
+	"github.com/graph-gophers/dataloader"
 	"github.com/sensu/sensu-go/backend/apid/graphql/relay"
 	"github.com/sensu/sensu-go/backend/apid/graphql/schema"
+	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/cli/client"
 	"github.com/sensu/sensu-go/graphql"
 	"github.com/sensu/sensu-go/graphql/tracing"
@@ -23,23 +26,32 @@ type ClientFactory interface {
 
 // ServiceConfig describes values required to instantiate service.
 type ServiceConfig struct {
-	AssetClient        AssetClient
-	CheckClient        CheckClient
-	EntityClient       EntityClient
-	EventClient        EventClient
-	EventFilterClient  EventFilterClient
-	HandlerClient      HandlerClient
-	HealthController   EtcdHealthController
-	MutatorClient      MutatorClient
-	SilencedClient     SilencedClient
-	NamespaceClient    NamespaceClient
-	HookClient         HookClient
-	UserClient         UserClient
-	RBACClient         RBACClient
-	VersionController  VersionController
-	GenericClient      GenericClient
-	MetricGatherer     MetricGatherer
-	ClusterMetricStore ClusterMetricStore
+	AssetClient             AssetClient
+	CheckClient             CheckClient
+	EntityClient            EntityClient
+	EventClient             EventClient
+	EventSubscriptionClient EventSubscriptionClient
+	EventFilterClient       EventFilterClient
+	HandlerClient           HandlerClient
+	HealthController        EtcdHealthController
+	MutatorClient           MutatorClient
+	SilencedClient          SilencedClient
+	NamespaceClient         NamespaceClient
+	HookClient              HookClient
+	UserClient              UserClient
+	RBACClient              RBACClient
+	VersionController       VersionController
+	GenericClient           GenericClient
+	MetricGatherer          MetricGatherer
+	ClusterMetricStore      ClusterMetricStore
+
+	// PersistedQueriesFile is the path to a JSON object of the queries that
+	// are persisted at startup, keyed by their SHA-256 hash.
+	PersistedQueriesFile string
+
+	// RequirePersistedQueries rejects the queries that are not in
+	// PersistedQueriesFile.
+	RequirePersistedQueries bool
 }
 
 // Service describes the Sensu GraphQL service capable of handling queries.
@@ -52,6 +64,16 @@ type Service struct {
 // NewService instantiates new GraphQL service
 func NewService(cfg ServiceConfig) (*Service, error) {
 	svc := graphql.NewService()
+	if cfg.RequirePersistedQueries && cfg.PersistedQueriesFile == "" {
+		return nil, errors.New("requiring persisted queries requires a persisted queries file")
+	}
+	persistedQueries, err := graphql.LoadPersistedQueries(cfg.PersistedQueriesFile, cfg.RequirePersistedQueries)
+	if err != nil {
+		return nil, err
+	}
+	svc.PersistedQueries = persistedQueries
+	// The queries of the dashboards can be served by a read replica
+	svc.QueryContext = store.ReplicaReadsContext
 
 	nodeRegister := relay.NodeRegister{}
 	nodeResolver := relay.Resolver{Register: &nodeRegister}
@@ -78,9 +100,9 @@ func NewService(cfg ServiceConfig) (*Service, error) {
 	schema.RegisterCoreV2Secret(svc, &schema.CoreV2SecretAliases{})
 	schema.RegisterCoreV2System(svc, &schema.CoreV2SystemAliases{})
 	schema.RegisterCoreV3EntityConfig(svc, &corev3EntityConfigImpl{})
-	schema.RegisterCoreV3EntityConfigExtensionOverrides(svc, &corev3EntityConfigExtImpl{client: cfg.GenericClient, entityClient: cfg.EntityClient})
+	schema.RegisterCoreV3EntityConfigExtensionOverrides(svc, &corev3EntityConfigExtImpl{client: cfg.GenericClient})
 	schema.RegisterCoreV3EntityState(svc, &corev3EntityStateImpl{})
-	schema.RegisterCoreV3EntityStateExtensionOverrides(svc, &corev3EntityStateExtImpl{client: cfg.GenericClient, entityClient: cfg.EntityClient})
+	schema.RegisterCoreV3EntityStateExtensionOverrides(svc, &corev3EntityStateExtImpl{client: cfg.GenericClient})
 	schema.RegisterNamespace(svc, &namespaceImpl{client: cfg.NamespaceClient, entityClient: cfg.EntityClient, eventClient: cfg.EventClient, serviceConfig: &cfg})
 	schema.RegisterErrCode(svc)
 	schema.RegisterEvent(svc, &eventImpl{})
@@ -98,13 +120,16 @@ func NewService(cfg ServiceConfig) (*Service, error) {
 	schema.RegisterProxyRequests(svc, &schema.ProxyRequestsAliases{})
 	schema.RegisterResource(svc, nil)
 	schema.RegisterResolveEventPayload(svc, &schema.ResolveEventPayloadAliases{})
+	schema.RegisterResolveEventsPayload(svc, &schema.ResolveEventsPayloadAliases{})
 	schema.RegisterSchema(svc)
 	schema.RegisterSilenceable(svc, nil)
-	schema.RegisterSilenced(svc, &silencedImpl{client: cfg.CheckClient})
+	schema.RegisterSubscription(svc, &subscriptionImpl{client: cfg.EventSubscriptionClient})
+	schema.RegisterSilenced(svc, &silencedImpl{})
 	schema.RegisterSilencedConnection(svc, &schema.SilencedConnectionAliases{})
 	schema.RegisterSilencesListOrder(svc)
 	schema.RegisterSuggestionOrder(svc)
 	schema.RegisterSuggestionResultSet(svc, &schema.SuggestionResultSetAliases{})
+	schema.RegisterSystemMetrics(svc, &systemMetricsImpl{})
 	schema.RegisterUint(svc, unsignedIntegerImpl{})
 	schema.RegisterViewer(svc, &viewerImpl{userClient: cfg.UserClient})
 
@@ -141,7 +166,7 @@ func NewService(cfg ServiceConfig) (*Service, error) {
 	schema.RegisterHookList(svc, &hookListImpl{})
 
 	// Register handler types
-	schema.RegisterHandler(svc, &handlerImpl{client: cfg.MutatorClient})
+	schema.RegisterHandler(svc, &handlerImpl{})
 	schema.RegisterHandlerListOrder(svc)
 	schema.RegisterHandlerConnection(svc, &schema.HandlerConnectionAliases{})
 	schema.RegisterHandlerSocket(svc, &handlerSocketImpl{})
@@ -203,6 +228,9 @@ func NewService(cfg ServiceConfig) (*Service, error) {
 	schema.RegisterExecuteCheckInput(svc)
 	schema.RegisterExecuteCheckPayload(svc, &schema.ExecuteCheckPayloadAliases{})
 	schema.RegisterResolveEventInput(svc)
+	schema.RegisterResolveEventsInput(svc)
+	schema.RegisterSilenceEventsInput(svc)
+	schema.RegisterSilenceEventsPayload(svc, &schema.SilenceEventsPayloadAliases{})
 	schema.RegisterSilenceInputs(svc)
 	schema.RegisterUpdateCheckInput(svc)
 	schema.RegisterUpdateCheckPayload(svc, &checkMutationPayload{})
@@ -227,7 +255,7 @@ func NewService(cfg ServiceConfig) (*Service, error) {
 	}
 	svc.RegisterMiddleware(tracer)
 
-	err := svc.Regenerate()
+	err = svc.Regenerate()
 	return &wrapper, err
 }
 
@@ -239,3 +267,13 @@ func (svc *Service) Do(ctx context.Context, p graphql.QueryParams) *graphql.Resu
 	// Execute query inside context
 	return svc.Target.Do(qryCtx, p)
 }
+
+// Subscribe executes given subscription and sends its results on the returned
+// channel, until the context is done.
+func (svc *Service) Subscribe(ctx context.Context, p graphql.QueryParams) chan *graphql.Result {
+	// Subscriptions are long-lived: their loaders must not cache the
+	// resources, which would otherwise never be refreshed.
+	qryCtx := contextWithLoaders(ctx, *svc.Config, dataloader.WithCache(&dataloader.NoCache{}))
+
+	return svc.Target.Subscribe(qryCtx, p)
+}
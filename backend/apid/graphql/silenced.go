@@ -19,7 +19,6 @@ var _ schema.SilencedFieldResolvers = (*silencedImpl)(nil)
 
 type silencedImpl struct {
 	schema.SilencedAliases
-	client CheckClient
 }
 
 // Begin implements response to request for 'begin' field.
@@ -31,10 +30,7 @@ func (r *silencedImpl) Begin(p graphql.ResolveParams) (*time.Time, error) {
 // Check implements response to request for 'check' field.
 func (r *silencedImpl) Check(p graphql.ResolveParams) (interface{}, error) {
 	src := p.Source.(*corev2.Silenced)
-	ctx := contextWithNamespace(p.Context, src.Namespace)
-
-	res, err := r.client.FetchCheck(ctx, src.Check)
-	return handleFetchResult(res, err)
+	return loadCheckConfig(p.Context, src.Namespace, src.Check), nil
 }
 
 // Expires implements response to request for 'expires' field.
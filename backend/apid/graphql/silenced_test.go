@@ -16,11 +16,12 @@ func TestSilencedTypeCheckField(t *testing.T) {
 	silenced := corev2.FixtureSilenced("unix:http-check")
 
 	client := new(MockCheckClient)
-	impl := &silencedImpl{client: client}
+	impl := &silencedImpl{}
+	ctx := contextWithLoadersNoCache(context.Background(), ServiceConfig{CheckClient: client})
 
 	// Success
 	client.On("FetchCheck", mock.Anything, check.Name).Return(check, nil).Once()
-	res, err := impl.Check(graphql.ResolveParams{Source: silenced, Context: context.Background()})
+	res, err := resolveThunk(impl.Check(graphql.ResolveParams{Source: silenced, Context: ctx}))
 	require.NoError(t, err)
 	assert.NotEmpty(t, res)
 }
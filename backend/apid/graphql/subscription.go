@@ -0,0 +1,66 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/sensu/sensu-go/backend/apid/graphql/schema"
+	"github.com/sensu/sensu-go/backend/messaging"
+)
+
+var _ schema.SubscriptionFieldResolvers = (*subscriptionImpl)(nil)
+
+//
+// Implement SubscriptionFieldResolvers
+//
+
+type subscriptionImpl struct {
+	client EventSubscriptionClient
+}
+
+// Events implements the subscription to the 'events' field.
+func (r *subscriptionImpl) Events(p schema.SubscriptionEventsFieldResolverParams) (interface{}, error) {
+	ctx := contextWithNamespace(p.Context, p.Args.Namespace)
+	return r.subscribe(ctx, func(event *messaging.EventWithPrevious) interface{} {
+		return event.Event
+	})
+}
+
+// EntityStatus implements the subscription to the 'entityStatus' field.
+func (r *subscriptionImpl) EntityStatus(p schema.SubscriptionEntityStatusFieldResolverParams) (interface{}, error) {
+	ctx := contextWithNamespace(p.Context, p.Args.Namespace)
+	return r.subscribe(ctx, func(event *messaging.EventWithPrevious) interface{} {
+		if !event.HasCheck() || event.Entity == nil {
+			return nil
+		}
+		if event.Previous != nil && event.Previous.HasCheck() && event.Previous.Check.Status == event.Check.Status {
+			return nil
+		}
+		return event.Entity
+	})
+}
+
+// subscribe subscribes to the events of the namespace of the context, and
+// returns the channel of the values mapped from them. The events mapped to nil
+// are skipped.
+func (r *subscriptionImpl) subscribe(ctx context.Context, mapFn func(*messaging.EventWithPrevious) interface{}) (chan interface{}, error) {
+	events, err := r.client.SubscribeEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values := make(chan interface{})
+	go func() {
+		defer close(values)
+		for event := range events {
+			value := mapFn(event)
+			if value == nil {
+				continue
+			}
+			select {
+			case values <- value:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return values, nil
+}
@@ -0,0 +1,75 @@
+package graphql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEventSubscriptionClient struct {
+	namespace string
+	events    chan *messaging.EventWithPrevious
+}
+
+func (c *fakeEventSubscriptionClient) SubscribeEvents(ctx context.Context) (<-chan *messaging.EventWithPrevious, error) {
+	c.namespace = corev2.ContextNamespace(ctx)
+	return c.events, nil
+}
+
+func TestSubscriptionEntityStatus(t *testing.T) {
+	client := &fakeEventSubscriptionClient{events: make(chan *messaging.EventWithPrevious, 3)}
+	svc, err := NewService(ServiceConfig{EventSubscriptionClient: client})
+	require.NoError(t, err)
+
+	ok := corev2.FixtureEvent("ok", "check")
+	failing := corev2.FixtureEvent("failing", "check")
+	failing.Check.Status = 2
+	client.events <- &messaging.EventWithPrevious{Event: ok, Previous: ok}
+	client.events <- &messaging.EventWithPrevious{Event: failing, Previous: ok}
+	client.events <- &messaging.EventWithPrevious{Event: ok}
+	close(client.events)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := svc.Subscribe(ctx, graphql.QueryParams{
+		Query: `subscription { entityStatus(namespace: "default") { metadata { name } } }`,
+	})
+
+	var names []interface{}
+	timeout := time.After(5 * time.Second)
+	for done := false; !done; {
+		select {
+		case result, more := <-results:
+			if !more {
+				done = true
+				break
+			}
+			require.Empty(t, result.Errors)
+			data := result.Data.(map[string]interface{})["entityStatus"].(map[string]interface{})
+			names = append(names, data["metadata"].(map[string]interface{})["name"])
+		case <-timeout:
+			t.Fatal("timed out waiting for the subscription to end")
+		}
+	}
+	assert.Equal(t, "default", client.namespace)
+	assert.Equal(t, []interface{}{"failing", "ok"}, names)
+}
+
+func TestSubscribeQuery(t *testing.T) {
+	svc, err := NewService(ServiceConfig{})
+	require.NoError(t, err)
+
+	results := svc.Subscribe(context.Background(), graphql.QueryParams{
+		Query: `subscription { events(namespace: "default") { id } entityStatus(namespace: "default") { id } }`,
+	})
+	result := <-results
+	assert.NotEmpty(t, result.Errors)
+	_, more := <-results
+	assert.False(t, more)
+}
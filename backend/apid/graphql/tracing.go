@@ -1,12 +1,67 @@
 package graphql
 
 import (
+	"context"
+	"time"
+
+	"github.com/graph-gophers/dataloader"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sensu/sensu-go/graphql/tracing"
 )
 
+var (
+	loaderBatchDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "graphql_dataloader_batch_duration_seconds",
+			Help:    "Time spent by the GraphQL dataloaders loading a batch of resources, in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"loader"},
+	)
+
+	loaderBatchSize = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "graphql_dataloader_batch_size",
+			Help:    "Number of lookups of the batches loaded by the GraphQL dataloaders",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 9),
+		},
+		[]string{"loader"},
+	)
+)
+
 func init() {
 	if err := prometheus.Register(tracing.Collector); err != nil {
 		logger.WithError(err).Error("unable to register tracer")
 	}
+	if err := prometheus.Register(loaderBatchDuration); err != nil {
+		logger.WithError(err).Error("unable to register dataloader metrics")
+	}
+	if err := prometheus.Register(loaderBatchSize); err != nil {
+		logger.WithError(err).Error("unable to register dataloader metrics")
+	}
+}
+
+// loaderTracer is a dataloader tracer that collects the duration and the size
+// of the batches of the loader it names. Since the lookups of single resources
+// are resolved lazily, the time spent resolving them is observed here rather
+// than by the field tracer.
+type loaderTracer string
+
+// TraceLoad is called on each load
+func (t loaderTracer) TraceLoad(ctx context.Context, key dataloader.Key) (context.Context, dataloader.TraceLoadFinishFunc) {
+	return ctx, func(dataloader.Thunk) {}
+}
+
+// TraceLoadMany is called on each load of many keys
+func (t loaderTracer) TraceLoadMany(ctx context.Context, keys dataloader.Keys) (context.Context, dataloader.TraceLoadManyFinishFunc) {
+	return ctx, func(dataloader.ThunkMany) {}
+}
+
+// TraceBatch is called on each batch
+func (t loaderTracer) TraceBatch(ctx context.Context, keys dataloader.Keys) (context.Context, dataloader.TraceBatchFinishFunc) {
+	start := time.Now()
+	loaderBatchSize.WithLabelValues(string(t)).Observe(float64(len(keys)))
+	return ctx, func([]*dataloader.Result) {
+		loaderBatchDuration.WithLabelValues(string(t)).Observe(time.Since(start).Seconds())
+	}
 }
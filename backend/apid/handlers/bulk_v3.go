@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/apid/request"
+	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// BulkResult is the outcome of the operation on one of the resources of a
+// bulk request. Err is nil when the operation succeeded.
+type BulkResult struct {
+	Name string
+	Err  error
+}
+
+// BulkCreateResources creates the resources given in the request body, a list
+// of wrapped resources, but only those that do not already exist.
+func (h Handlers[R, T]) BulkCreateResources(r *http.Request) ([]BulkResult, error) {
+	return h.bulk(r, h.create)
+}
+
+// BulkCreateOrUpdateResources creates or updates the resources given in the
+// request body, a list of wrapped resources. The valid resources are written
+// in a single batch, unless the request has conditions, which are checked for
+// each resource. If the batch fails, the resources are written one at a time
+// so that the error of each of them is reported.
+func (h Handlers[R, T]) BulkCreateOrUpdateResources(r *http.Request) ([]BulkResult, error) {
+	ctx := r.Context()
+	if storev2.IfMatchFromContext(ctx) != nil || storev2.IfNoneMatchFromContext(ctx) != nil {
+		return h.bulk(r, h.createOrUpdate)
+	}
+
+	payload, results, err := h.bulkPayload(r)
+	if err != nil {
+		return nil, err
+	}
+	valid := make([]R, 0, len(payload))
+	for i, resource := range payload {
+		if results[i].Err == nil {
+			valid = append(valid, resource)
+		}
+	}
+	if len(valid) == 0 {
+		return results, nil
+	}
+	if err := storev2.Of[R](h.Store).BatchCreateOrUpdate(ctx, valid); err != nil {
+		for i, resource := range payload {
+			if results[i].Err == nil {
+				results[i].Err = h.createOrUpdate(ctx, resource)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// BulkDeleteResources deletes the resources given in the request body, a list
+// of wrapped resources.
+func (h Handlers[R, T]) BulkDeleteResources(r *http.Request) ([]BulkResult, error) {
+	return h.bulk(r, func(ctx context.Context, resource R) error {
+		meta := resource.GetMetadata()
+		return h.delete(ctx, storev2.ID{Namespace: meta.Namespace, Name: meta.Name})
+	})
+}
+
+// bulk applies the operation to every resource of the request body, and
+// returns the result of each operation, in the same order as the resources.
+// A failed operation does not prevent the others from being applied.
+func (h Handlers[R, T]) bulk(r *http.Request, operation func(context.Context, R) error) ([]BulkResult, error) {
+	payload, results, err := h.bulkPayload(r)
+	if err != nil {
+		return nil, err
+	}
+	ctx := r.Context()
+	for i, resource := range payload {
+		if results[i].Err == nil {
+			results[i].Err = operation(ctx, resource)
+		}
+	}
+	return results, nil
+}
+
+// bulkPayload returns the resources of the request body, along with their
+// results. The result of an invalid resource has an error, and the resource
+// must not be operated on.
+func (h Handlers[R, T]) bulkPayload(r *http.Request) ([]R, []BulkResult, error) {
+	payload, err := request.Resources[R](r)
+	if err != nil {
+		return nil, nil, actions.NewError(actions.InvalidArgument, err)
+	}
+
+	vars := mux.Vars(r)
+	namespace, err := url.PathUnescape(vars["namespace"])
+	if err != nil {
+		return nil, nil, actions.NewError(actions.InvalidArgument, err)
+	}
+
+	claims := jwt.GetClaimsFromContext(r.Context())
+
+	results := make([]BulkResult, len(payload))
+	for i, resource := range payload {
+		meta := resource.GetMetadata()
+		if meta == nil {
+			results[i].Err = actions.NewError(actions.InvalidArgument, errors.New("nil metadata"))
+			continue
+		}
+		results[i].Name = meta.Name
+		if meta.Namespace == "" {
+			meta.Namespace = namespace
+		}
+		if err := checkMeta(*meta, vars, "id"); err != nil {
+			results[i].Err = actions.NewError(actions.InvalidArgument, err)
+			continue
+		}
+		if claims != nil {
+			meta.CreatedBy = claims.StandardClaims.Subject
+		}
+	}
+
+	return payload, results, nil
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/testing/fixture"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/mock"
+)
+
+func bulkBody(t *testing.T, resources ...*fixture.V3Resource) []byte {
+	t.Helper()
+	body := [][]byte{}
+	for _, resource := range resources {
+		body = append(body, marshal(t, resource))
+	}
+	return append(append([]byte("["), bytes.Join(body, []byte(","))...), ']')
+}
+
+func TestHandlers_BulkCreateOrUpdateResources(t *testing.T) {
+	cs := new(mockstore.ConfigStore)
+	cs.On("BatchCreateOrUpdate", mock.Anything, mock.Anything, mock.Anything).Return(&store.ErrInternal{})
+	cs.On("CreateOrUpdate", mock.Anything, mock.MatchedBy(func(req storev2.ResourceRequest) bool {
+		return req.Name == "broken"
+	}), mock.Anything).Return(&store.ErrInternal{})
+	cs.On("CreateOrUpdate", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	s := &mockstore.V2MockStore{}
+	s.On("GetConfigStore").Return(cs)
+	h := NewHandlers[*fixture.V3Resource](s)
+
+	body := bulkBody(t,
+		&fixture.V3Resource{Metadata: corev2.NewObjectMetaP("foo", "")},
+		&fixture.V3Resource{Metadata: corev2.NewObjectMetaP("bar", "other")},
+		&fixture.V3Resource{Metadata: corev2.NewObjectMetaP("broken", "acme")},
+	)
+	r, _ := http.NewRequest(http.MethodPut, "/", bytes.NewReader(body))
+	r = mux.SetURLVars(r, map[string]string{"namespace": "acme"})
+
+	results, err := h.BulkCreateOrUpdateResources(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].Name != "foo" || results[0].Err != nil {
+		t.Errorf("expected foo to be stored, got %v", results[0])
+	}
+	if err, ok := results[1].Err.(actions.Error); !ok || err.Code != actions.InvalidArgument {
+		t.Errorf("expected the namespace of bar to be rejected, got %v", results[1].Err)
+	}
+	if err, ok := results[2].Err.(actions.Error); !ok || err.Code != actions.InternalErr {
+		t.Errorf("expected the store error of broken, got %v", results[2].Err)
+	}
+	cs.AssertNumberOfCalls(t, "CreateOrUpdate", 2)
+}
+
+func TestHandlers_BulkCreateOrUpdateResourcesBatch(t *testing.T) {
+	cs := new(mockstore.ConfigStore)
+	cs.On("BatchCreateOrUpdate", mock.Anything, mock.MatchedBy(func(reqs []storev2.ResourceRequest) bool {
+		return len(reqs) == 2 && reqs[0].Name == "foo" && reqs[1].Name == "baz"
+	}), mock.Anything).Return(nil)
+	s := &mockstore.V2MockStore{}
+	s.On("GetConfigStore").Return(cs)
+	h := NewHandlers[*fixture.V3Resource](s)
+
+	body := bulkBody(t,
+		&fixture.V3Resource{Metadata: corev2.NewObjectMetaP("foo", "")},
+		&fixture.V3Resource{Metadata: corev2.NewObjectMetaP("bar", "other")},
+		&fixture.V3Resource{Metadata: corev2.NewObjectMetaP("baz", "acme")},
+	)
+	r, _ := http.NewRequest(http.MethodPut, "/", bytes.NewReader(body))
+	r = mux.SetURLVars(r, map[string]string{"namespace": "acme"})
+
+	results, err := h.BulkCreateOrUpdateResources(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Err != nil || results[2].Err != nil {
+		t.Errorf("expected foo and baz to be stored, got %v", results)
+	}
+	if results[1].Err == nil {
+		t.Error("expected the namespace of bar to be rejected")
+	}
+	cs.AssertNumberOfCalls(t, "BatchCreateOrUpdate", 1)
+	cs.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandlers_BulkDeleteResources(t *testing.T) {
+	cs := new(mockstore.ConfigStore)
+	cs.On("Delete", mock.Anything, mock.MatchedBy(func(req storev2.ResourceRequest) bool {
+		return req.Name == "foo" && req.Namespace == "acme"
+	})).Return(nil)
+	cs.On("Delete", mock.Anything, mock.Anything).Return(&store.ErrNotFound{})
+	s := &mockstore.V2MockStore{}
+	s.On("GetConfigStore").Return(cs)
+	h := NewHandlers[*fixture.V3Resource](s)
+
+	body := bulkBody(t,
+		&fixture.V3Resource{Metadata: corev2.NewObjectMetaP("foo", "acme")},
+		&fixture.V3Resource{Metadata: corev2.NewObjectMetaP("missing", "acme")},
+	)
+	r, _ := http.NewRequest(http.MethodDelete, "/", bytes.NewReader(body))
+	r = mux.SetURLVars(r, map[string]string{"namespace": "acme"})
+
+	results, err := h.BulkDeleteResources(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Err != nil {
+		t.Errorf("expected foo to be deleted, got %v", results[0].Err)
+	}
+	if err, ok := results[1].Err.(actions.Error); !ok || err.Code != actions.NotFound {
+		t.Errorf("expected missing not to be found, got %v", results[1].Err)
+	}
+}
+
+func TestHandlers_BulkCreateResourcesInvalidBody(t *testing.T) {
+	h := NewHandlers[*fixture.V3Resource](&mockstore.V2MockStore{})
+	r, _ := http.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("foobar")))
+	if _, err := h.BulkCreateResources(r); err == nil {
+		t.Error("expected error")
+	}
+}
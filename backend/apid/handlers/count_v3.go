@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// CountResources returns the number of resources in the namespace of the
+// request, or in all the namespaces if it has none, without listing them.
+func (h Handlers[R, T]) CountResources(r *http.Request) (int, error) {
+	ctx := r.Context()
+	namespace := store.NewNamespaceFromContext(ctx)
+
+	gstore := storev2.Of[R](h.Store)
+
+	count, err := gstore.Count(ctx, storev2.ID{Namespace: namespace})
+	if err != nil {
+		switch err := err.(type) {
+		case *store.ErrNotValid:
+			return 0, actions.NewError(actions.InvalidArgument, err)
+		default:
+			return 0, actions.NewError(actions.InternalErr, err)
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/testing/fixture"
+	"github.com/sensu/sensu-go/testing/mockstore"
+)
+
+func TestHandlers_CountResources(t *testing.T) {
+	tests := []struct {
+		name      string
+		storeFunc func(*mockstore.ConfigStore)
+		want      int
+		wantErr   bool
+	}{
+		{
+			name: "store ErrInternal",
+			storeFunc: func(s *mockstore.ConfigStore) {
+				s.On("Count", mock.Anything, mock.Anything).
+					Return(0, &store.ErrInternal{})
+			},
+			wantErr: true,
+		},
+		{
+			name: "successful count",
+			storeFunc: func(s *mockstore.ConfigStore) {
+				s.On("Count", mock.Anything, mock.MatchedBy(func(req storev2.ResourceRequest) bool {
+					return req.Namespace == "default" && req.Name == ""
+				})).Return(42, nil)
+			},
+			want: 42,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sto := &mockstore.V2MockStore{}
+			cs := new(mockstore.ConfigStore)
+			sto.On("GetConfigStore").Return(cs)
+			tt.storeFunc(cs)
+
+			h := NewHandlers[*fixture.V3Resource](sto)
+
+			ctx := context.WithValue(context.Background(), corev2.NamespaceKey, "default")
+			r, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+
+			got, err := h.CountResources(r)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Handlers.CountResources() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Handlers.CountResources() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
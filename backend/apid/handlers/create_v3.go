@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -23,7 +24,7 @@ func (h Handlers[R, T]) CreateResource(r *http.Request) (HandlerResponse, error)
 
 	ctx, err := matchHeaderContext(r)
 	if err != nil {
-		return response, actions.NewErrorf(actions.InvalidArgument, err)
+		return response, actions.NewError(actions.InvalidArgument, err)
 	}
 	ctx = storev2.ContextWithTxInfo(ctx, &response.TxInfo)
 
@@ -39,20 +40,32 @@ func (h Handlers[R, T]) CreateResource(r *http.Request) (HandlerResponse, error)
 		meta.CreatedBy = claims.StandardClaims.Subject
 	}
 
+	// The dry runs respond with the resource as it would be created
+	if request.DryRunFromContext(ctx) {
+		response.Resource = payload
+		return response, h.dryRun(ctx, payload, true)
+	}
+
+	err = h.create(ctx, payload)
+	return response, err
+}
+
+// create creates the resource if it does not already exist.
+func (h Handlers[R, T]) create(ctx context.Context, payload R) error {
 	gstore := storev2.Of[R](h.Store)
 
 	if err := gstore.CreateIfNotExists(ctx, payload); err != nil {
 		switch err := err.(type) {
 		case *store.ErrPreconditionFailed:
-			return response, actions.NewError(actions.PreconditionFailed, err)
+			return actions.NewError(actions.PreconditionFailed, err)
 		case *store.ErrAlreadyExists:
-			return response, actions.NewErrorf(actions.AlreadyExistsErr)
+			return actions.NewErrorf(actions.AlreadyExistsErr)
 		case *store.ErrNotValid:
-			return response, actions.NewError(actions.InvalidArgument, err)
+			return actions.NewError(actions.InvalidArgument, err)
 		default:
-			return response, actions.NewError(actions.InternalErr, err)
+			return actions.NewError(actions.InternalErr, err)
 		}
 	}
 
-	return response, nil
+	return nil
 }
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 
@@ -22,26 +23,32 @@ func (h Handlers[R, T]) DeleteResource(r *http.Request) (HandlerResponse, error)
 
 	ctx, err := matchHeaderContext(r)
 	if err != nil {
-		return response, actions.NewErrorf(actions.InvalidArgument, err)
+		return response, actions.NewError(actions.InvalidArgument, err)
 	}
 
 	ctx = storev2.ContextWithTxInfo(ctx, &response.TxInfo)
 
 	namespace := store.NewNamespaceFromContext(ctx)
 
+	err = h.delete(ctx, storev2.ID{Namespace: namespace, Name: name})
+	return response, err
+}
+
+// delete deletes the resource identified by id.
+func (h Handlers[R, T]) delete(ctx context.Context, id storev2.ID) error {
 	gstore := storev2.Of[R](h.Store)
 
-	if err := gstore.Delete(ctx, storev2.ID{Namespace: namespace, Name: name}); err != nil {
+	if err := gstore.Delete(ctx, id); err != nil {
 		switch err := err.(type) {
 		case *store.ErrPreconditionFailed:
-			return response, actions.NewError(actions.PreconditionFailed, err)
+			return actions.NewError(actions.PreconditionFailed, err)
 		case *store.ErrNotFound:
-			return response, actions.NewErrorf(actions.NotFound)
+			return actions.NewErrorf(actions.NotFound)
 		case *store.ErrNotValid:
-			return response, actions.NewError(actions.InvalidArgument, err)
+			return actions.NewError(actions.InvalidArgument, err)
 		default:
-			return response, actions.NewError(actions.InternalErr, err)
+			return actions.NewError(actions.InternalErr, err)
 		}
 	}
-	return response, nil
+	return nil
 }
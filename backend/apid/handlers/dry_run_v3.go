@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// dryRun runs the checks of the write of a resource, without persisting it:
+// the resource is validated, and the preconditions of the write are verified
+// against the stored resource. When create is set, the resource must not
+// exist yet.
+func (h Handlers[R, T]) dryRun(ctx context.Context, payload R, create bool) error {
+	if _, err := storev2.WrapResource(payload); err != nil {
+		return actions.NewError(actions.InvalidArgument, err)
+	}
+
+	meta := payload.GetMetadata()
+	id := storev2.ID{Namespace: meta.Namespace, Name: meta.Name}
+	stored, err := storev2.Of[R](h.Store).Get(ctx, id)
+	if err != nil {
+		if _, ok := err.(*store.ErrNotFound); !ok {
+			return actions.NewError(actions.InternalErr, err)
+		}
+		// If-Match requires the resource to exist
+		if storev2.IfMatchFromContext(ctx) != nil {
+			return actions.NewError(actions.PreconditionFailed, err)
+		}
+		return nil
+	}
+	if create {
+		return actions.NewErrorf(actions.AlreadyExistsErr)
+	}
+
+	etag, err := storev2.DecodeETag(stored.GetMetadata().Annotations[store.SensuETagKey])
+	if err != nil {
+		return actions.NewError(actions.InternalErr, err)
+	}
+	if ifMatch := storev2.IfMatchFromContext(ctx); ifMatch != nil && !ifMatch.Matches(etag) {
+		return actions.NewErrorf(actions.PreconditionFailed, "the resource was modified")
+	}
+	if ifNoneMatch := storev2.IfNoneMatchFromContext(ctx); ifNoneMatch != nil && !ifNoneMatch.Matches(etag) {
+		return actions.NewErrorf(actions.PreconditionFailed, "the resource was modified")
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/apid/request"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/backend/store/v2/wrap"
+	"github.com/sensu/sensu-go/testing/fixture"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlers_DryRun(t *testing.T) {
+	stored, err := wrap.Resource(&fixture.V3Resource{Metadata: corev2.NewObjectMetaP("foo", "default")})
+	require.NoError(t, err)
+	stored.ETag = "aGVsbG8"
+
+	tests := []struct {
+		name     string
+		method   string
+		ifMatch  string
+		exists   bool
+		wantCode actions.ErrCode
+	}{
+		{
+			name:   "create",
+			method: http.MethodPost,
+		},
+		{
+			name:     "create existing resource",
+			method:   http.MethodPost,
+			exists:   true,
+			wantCode: actions.AlreadyExistsErr,
+		},
+		{
+			name:   "update",
+			method: http.MethodPut,
+			exists: true,
+		},
+		{
+			name:   "update missing resource",
+			method: http.MethodPut,
+		},
+		{
+			name:    "update with if-match",
+			method:  http.MethodPut,
+			ifMatch: `"aGVsbG8"`,
+			exists:  true,
+		},
+		{
+			name:     "if-match mismatch",
+			method:   http.MethodPut,
+			ifMatch:  `"Ynll"`,
+			exists:   true,
+			wantCode: actions.PreconditionFailed,
+		},
+		{
+			name:     "if-match on a missing resource",
+			method:   http.MethodPut,
+			ifMatch:  `"aGVsbG8"`,
+			wantCode: actions.PreconditionFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &mockstore.V2MockStore{}
+			cs := new(mockstore.ConfigStore)
+			s.On("GetConfigStore").Return(cs)
+			if tt.exists {
+				cs.On("Get", mock.Anything, mock.Anything).Return(stored, nil)
+			} else {
+				cs.On("Get", mock.Anything, mock.Anything).Return((storev2.Wrapper)(nil), &store.ErrNotFound{})
+			}
+
+			h := NewHandlers[*fixture.V3Resource](s)
+			body := marshal(t, &fixture.V3Resource{Metadata: corev2.NewObjectMetaP("foo", "default")})
+			r, _ := http.NewRequest(tt.method, "/", bytes.NewReader(body))
+			r = r.WithContext(request.ContextWithDryRun(r.Context()))
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			create := h.CreateOrUpdateResource
+			if tt.method == http.MethodPost {
+				create = h.CreateResource
+			}
+			response, err := create(r)
+			if tt.wantCode != 0 {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantCode, err.(actions.Error).Code)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "foo", response.Resource.GetMetadata().Name)
+			}
+
+			// Nothing is written
+			cs.AssertNotCalled(t, "CreateIfNotExists", mock.Anything, mock.Anything, mock.Anything)
+			cs.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything, mock.Anything)
+			cs.AssertNotCalled(t, "UpdateIfExists", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
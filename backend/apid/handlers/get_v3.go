@@ -22,7 +22,7 @@ func (h Handlers[R, T]) GetResource(r *http.Request) (HandlerResponse, error) {
 
 	ctx, err := matchHeaderContext(r)
 	if err != nil {
-		return response, actions.NewErrorf(actions.InvalidArgument, err)
+		return response, actions.NewError(actions.InvalidArgument, err)
 	}
 	ctx = storev2.ContextWithTxInfo(ctx, &response.TxInfo)
 
@@ -6,12 +6,18 @@ import (
 	"net/url"
 
 	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/store/patch"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 )
 
 // Handlers represents the HTTP handlers for CRUD operations on resources
 type Handlers[R storev2.Resource[T], T any] struct {
 	Store storev2.Interface
+
+	// Patcher, if set, wraps the patchers of the patch requests, such as to
+	// protect the fields of the resources managed by the backend.
+	Patcher func(patch.Patcher) patch.Patcher
 }
 
 func NewHandlers[R storev2.Resource[T], T any](store storev2.Interface) Handlers[R, T] {
@@ -27,11 +33,11 @@ func checkMeta(meta corev2.ObjectMeta, vars map[string]string, idVar string) err
 	}
 
 	if meta.Namespace != namespace && namespace != "" {
-		return fmt.Errorf(
+		return actions.NewFieldError("metadata.namespace", fmt.Errorf(
 			"the namespace of the resource (%s) does not match the namespace of the URI (%s)",
 			meta.Namespace,
 			namespace,
-		)
+		))
 	}
 
 	// The URL path name that holds the resource ID might differ, but fallback
@@ -46,11 +52,11 @@ func checkMeta(meta corev2.ObjectMeta, vars map[string]string, idVar string) err
 	}
 
 	if meta.Name != id && id != "" {
-		return fmt.Errorf(
+		return actions.NewFieldError("metadata.name", fmt.Errorf(
 			"the name of the resource (%s) does not match the name of the URI (%s)",
 			meta.Name,
 			id,
-		)
+		))
 	}
 
 	return nil
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gorilla/mux"
+
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// resourceHistory returns the versions of the resource of the request, most
+// recent first.
+func (h Handlers[R, T]) resourceHistory(r *http.Request) ([]*storev2.ResourceVersion, error) {
+	name, err := url.PathUnescape(mux.Vars(r)["id"])
+	if err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, err)
+	}
+	ctx := r.Context()
+	namespace := store.NewNamespaceFromContext(ctx)
+
+	versions, err := storev2.Of[R](h.Store).History(ctx, storev2.ID{Namespace: namespace, Name: name})
+	if err != nil {
+		return nil, actions.NewError(actions.InternalErr, err)
+	}
+	return versions, nil
+}
+
+// findVersion returns the version with the given number.
+func findVersion(versions []*storev2.ResourceVersion, value string) (*storev2.ResourceVersion, error) {
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, actions.NewError(actions.InvalidArgument, fmt.Errorf("invalid version: %q", value))
+	}
+	for _, version := range versions {
+		if version.Version == number {
+			return version, nil
+		}
+	}
+	return nil, actions.NewErrorf(actions.NotFound)
+}
+
+// ListResourceVersions returns the versions of the resource, most recent
+// first.
+func (h Handlers[R, T]) ListResourceVersions(r *http.Request) (interface{}, error) {
+	return h.resourceHistory(r)
+}
+
+// GetResourceVersion returns the version of the resource given by the
+// "resource_version" path variable.
+func (h Handlers[R, T]) GetResourceVersion(r *http.Request) (interface{}, error) {
+	versions, err := h.resourceHistory(r)
+	if err != nil {
+		return nil, err
+	}
+	return findVersion(versions, mux.Vars(r)["resource_version"])
+}
+
+// DiffResourceVersion returns the JSON merge patch which turns the version of
+// the resource given by the "resource_version" path variable into the version
+// given by the "to" query parameter, or into the latest version if there is
+// none.
+func (h Handlers[R, T]) DiffResourceVersion(r *http.Request) (interface{}, error) {
+	versions, err := h.resourceHistory(r)
+	if err != nil {
+		return nil, err
+	}
+	from, err := findVersion(versions, mux.Vars(r)["resource_version"])
+	if err != nil {
+		return nil, err
+	}
+	to := versions[0]
+	if value := r.URL.Query().Get("to"); value != "" {
+		if to, err = findVersion(versions, value); err != nil {
+			return nil, err
+		}
+	}
+	if from.Deleted || to.Deleted {
+		return nil, actions.NewError(actions.InvalidArgument, fmt.Errorf("deleted versions can't be compared"))
+	}
+	diff, err := jsonpatch.CreateMergePatch(from.Resource, to.Resource)
+	if err != nil {
+		return nil, actions.NewError(actions.InternalErr, err)
+	}
+	return json.RawMessage(diff), nil
+}
+
+// RollbackResource writes back the version of the resource given by the
+// "resource_version" path variable, which is recorded as a new version.
+func (h Handlers[R, T]) RollbackResource(r *http.Request) (HandlerResponse, error) {
+	var response HandlerResponse
+
+	versions, err := h.resourceHistory(r)
+	if err != nil {
+		return response, err
+	}
+	version, err := findVersion(versions, mux.Vars(r)["resource_version"])
+	if err != nil {
+		return response, err
+	}
+	if version.Deleted {
+		return response, actions.NewError(actions.InvalidArgument, fmt.Errorf("version %d is a deletion", version.Version))
+	}
+
+	resource := R(new(T))
+	if err := json.Unmarshal(version.Resource, resource); err != nil {
+		return response, actions.NewError(actions.InternalErr, err)
+	}
+
+	ctx := storev2.ContextWithTxInfo(r.Context(), &response.TxInfo)
+	if err := storev2.Of[R](h.Store).CreateOrUpdate(ctx, resource); err != nil {
+		switch err := err.(type) {
+		case *store.ErrNotValid:
+			return response, actions.NewError(actions.InvalidArgument, err)
+		default:
+			return response, actions.NewError(actions.InternalErr, err)
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/testing/fixture"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func fixtureResourceHistory() []*storev2.ResourceVersion {
+	return []*storev2.ResourceVersion{
+		{Version: 3, Author: "carol", Deleted: true},
+		{Version: 2, Author: "bob", Resource: []byte(`{"Metadata":{"name":"foo","namespace":"acme","labels":{"region":"us"}}}`)},
+		{Version: 1, Author: "alice", Resource: []byte(`{"Metadata":{"name":"foo","namespace":"acme"}}`)},
+	}
+}
+
+func historyRequest(t *testing.T, method string, urlVars map[string]string, query string) *http.Request {
+	t.Helper()
+	ctx := context.WithValue(context.Background(), corev2.NamespaceKey, "acme")
+	r, err := http.NewRequestWithContext(ctx, method, "/?"+query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mux.SetURLVars(r, urlVars)
+}
+
+func newHistoryStore(historyErr error) (*mockstore.V2MockStore, *mockstore.ConfigStore) {
+	sto := &mockstore.V2MockStore{}
+	hs := new(mockstore.ResourceHistoryStore)
+	hs.On("GetResourceHistory", mock.Anything, mock.MatchedBy(func(req storev2.ResourceRequest) bool {
+		return req.Namespace == "acme" && req.Name == "foo" && req.Type == "V3Resource"
+	})).Return(fixtureResourceHistory(), historyErr)
+	sto.On("GetResourceHistoryStore").Return(hs)
+	cs := new(mockstore.ConfigStore)
+	sto.On("GetConfigStore").Return(cs)
+	return sto, cs
+}
+
+func TestHandlers_ListResourceVersions(t *testing.T) {
+	sto, _ := newHistoryStore(nil)
+	h := NewHandlers[*fixture.V3Resource](sto)
+	got, err := h.ListResourceVersions(historyRequest(t, http.MethodGet, map[string]string{"id": "foo"}, ""))
+	assert.NoError(t, err)
+	assert.Len(t, got, 3)
+
+	sto, _ = newHistoryStore(&store.ErrInternal{})
+	h = NewHandlers[*fixture.V3Resource](sto)
+	_, err = h.ListResourceVersions(historyRequest(t, http.MethodGet, map[string]string{"id": "foo"}, ""))
+	assert.Error(t, err)
+}
+
+func TestHandlers_GetResourceVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    string
+		wantErr bool
+	}{
+		{name: "existing version", version: "2", want: "bob"},
+		{name: "missing version", version: "4", wantErr: true},
+		{name: "invalid version", version: "two", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sto, _ := newHistoryStore(nil)
+			h := NewHandlers[*fixture.V3Resource](sto)
+			got, err := h.GetResourceVersion(historyRequest(t, http.MethodGet, map[string]string{"id": "foo", "resource_version": tt.version}, ""))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Handlers.GetResourceVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				assert.Equal(t, tt.want, got.(*storev2.ResourceVersion).Author)
+			}
+		})
+	}
+}
+
+func TestHandlers_DiffResourceVersion(t *testing.T) {
+	sto, _ := newHistoryStore(nil)
+	h := NewHandlers[*fixture.V3Resource](sto)
+	got, err := h.DiffResourceVersion(historyRequest(t, http.MethodGet, map[string]string{"id": "foo", "resource_version": "1"}, "to=2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, `{"Metadata":{"labels":{"region":"us"}}}`, string(got.(json.RawMessage)))
+
+	// The latest version is a deletion
+	_, err = h.DiffResourceVersion(historyRequest(t, http.MethodGet, map[string]string{"id": "foo", "resource_version": "1"}, ""))
+	assert.Error(t, err)
+}
+
+func TestHandlers_RollbackResource(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   string
+		storeFunc func(*mockstore.ConfigStore)
+		wantErr   bool
+	}{
+		{
+			name:    "deleted version",
+			version: "3",
+			wantErr: true,
+		},
+		{
+			name:    "store err",
+			version: "2",
+			storeFunc: func(s *mockstore.ConfigStore) {
+				s.On("CreateOrUpdate", mock.Anything, mock.Anything, mock.Anything).
+					Return(&store.ErrNotValid{Err: errors.New("error")})
+			},
+			wantErr: true,
+		},
+		{
+			name:    "successful rollback",
+			version: "2",
+			storeFunc: func(s *mockstore.ConfigStore) {
+				s.On("CreateOrUpdate", mock.Anything, mock.MatchedBy(func(req storev2.ResourceRequest) bool {
+					return req.Namespace == "acme" && req.Name == "foo"
+				}), mock.Anything).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sto, cs := newHistoryStore(nil)
+			if tt.storeFunc != nil {
+				tt.storeFunc(cs)
+			}
+			h := NewHandlers[*fixture.V3Resource](sto)
+			_, err := h.RollbackResource(historyRequest(t, http.MethodPost, map[string]string{"id": "foo", "resource_version": tt.version}, ""))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Handlers.RollbackResource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/apid/request"
 	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
@@ -33,6 +34,9 @@ func (h Handlers[R, T]) ListResources(ctx context.Context, pred *store.Selection
 
 	list, err := gstore.List(ctx, storev2.ID{Namespace: namespace}, pred)
 	if err != nil {
+		if _, ok := err.(*store.ErrNotValid); ok {
+			return nil, actions.NewError(actions.InvalidArgument, err)
+		}
 		return nil, err
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/sensu/sensu-go/backend/apid/actions"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 )
 
@@ -17,14 +18,14 @@ func matchHeaderContext(req *http.Request) (context.Context, error) {
 	if value := req.Header.Get(ifMatchHeader); value != "" {
 		ifMatch, err := storev2.ReadIfMatch(value)
 		if err != nil {
-			return nil, err
+			return nil, actions.NewFieldError(ifMatchHeader, err)
 		}
 		ctx = storev2.ContextWithIfMatch(ctx, ifMatch)
 	}
 	if value := req.Header.Get(ifNoneMatchHeader); value != "" {
 		ifNoneMatch, err := storev2.ReadIfNoneMatch(value)
 		if err != nil {
-			return nil, err
+			return nil, actions.NewFieldError(ifNoneMatchHeader, err)
 		}
 		ctx = storev2.ContextWithIfNoneMatch(ctx, ifNoneMatch)
 	}
@@ -61,6 +61,10 @@ func (h Handlers[R, T]) PatchResource(r *http.Request) (HandlerResponse, error)
 		)
 	}
 
+	if h.Patcher != nil {
+		patcher = h.Patcher(patcher)
+	}
+
 	ctx := r.Context()
 
 	// Determine if we have a conditional request
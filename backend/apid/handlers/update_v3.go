@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -32,7 +33,7 @@ func (h Handlers[R, T]) CreateOrUpdateResource(r *http.Request) (HandlerResponse
 
 	ctx, err := matchHeaderContext(r)
 	if err != nil {
-		return response, actions.NewErrorf(actions.InvalidArgument, err)
+		return response, actions.NewError(actions.InvalidArgument, err)
 	}
 	ctx = storev2.ContextWithTxInfo(ctx, &response.TxInfo)
 
@@ -40,18 +41,45 @@ func (h Handlers[R, T]) CreateOrUpdateResource(r *http.Request) (HandlerResponse
 		meta.CreatedBy = claims.StandardClaims.Subject
 	}
 
+	// The dry runs respond with the resource as it would be written
+	if request.DryRunFromContext(ctx) {
+		response.Resource = payload
+		return response, h.dryRun(ctx, payload, false)
+	}
+
+	// The store records the transaction in the response
+	err = h.createOrUpdate(ctx, payload)
+	return response, err
+}
+
+// createOrUpdate creates or updates the resource. When the context holds an
+// If-Match condition, the resource must exist.
+func (h Handlers[R, T]) createOrUpdate(ctx context.Context, payload R) error {
 	gstore := storev2.Of[R](h.Store)
 
-	if err := gstore.CreateOrUpdate(ctx, payload); err != nil {
+	// If-Match requires the resource to exist, with a matching etag, so that
+	// concurrent updates can't clobber each other.
+	ifMatch := storev2.IfMatchFromContext(ctx) != nil
+	write := gstore.CreateOrUpdate
+	if ifMatch {
+		write = gstore.UpdateIfExists
+	}
+
+	if err := write(ctx, payload); err != nil {
 		switch err := err.(type) {
+		case *store.ErrNotFound:
+			if ifMatch {
+				return actions.NewError(actions.PreconditionFailed, err)
+			}
+			return actions.NewError(actions.InternalErr, err)
 		case *store.ErrPreconditionFailed:
-			return response, actions.NewError(actions.PreconditionFailed, err)
+			return actions.NewError(actions.PreconditionFailed, err)
 		case *store.ErrNotValid:
-			return response, actions.NewError(actions.InvalidArgument, err)
+			return actions.NewError(actions.InvalidArgument, err)
 		default:
-			return response, actions.NewError(actions.InternalErr, err)
+			return actions.NewError(actions.InternalErr, err)
 		}
 	}
 
-	return response, nil
+	return nil
 }
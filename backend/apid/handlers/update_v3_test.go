@@ -23,6 +23,7 @@ func TestHandlers_UpdateResourceV3(t *testing.T) {
 		name      string
 		body      []byte
 		urlVars   map[string]string
+		ifMatch   string
 		storeFunc storeFunc
 		wantErr   bool
 	}{
@@ -63,6 +64,35 @@ func TestHandlers_UpdateResourceV3(t *testing.T) {
 					Return(nil)
 			},
 		},
+		{
+			name:    "successful update with if-match",
+			body:    marshal(t, &fixture.V3Resource{Metadata: corev2.NewObjectMetaP("", "")}),
+			ifMatch: `"aGVsbG8"`,
+			storeFunc: func(s *mockstore.ConfigStore) {
+				s.On("UpdateIfExists", mock.Anything, mock.Anything, mock.Anything).
+					Return(nil)
+			},
+		},
+		{
+			name:    "if-match mismatch",
+			body:    marshal(t, &fixture.V3Resource{Metadata: corev2.NewObjectMetaP("", "")}),
+			ifMatch: `"aGVsbG8"`,
+			storeFunc: func(s *mockstore.ConfigStore) {
+				s.On("UpdateIfExists", mock.Anything, mock.Anything, mock.Anything).
+					Return(&store.ErrPreconditionFailed{})
+			},
+			wantErr: true,
+		},
+		{
+			name:    "if-match on a missing resource",
+			body:    marshal(t, &fixture.V3Resource{Metadata: corev2.NewObjectMetaP("", "")}),
+			ifMatch: `"aGVsbG8"`,
+			storeFunc: func(s *mockstore.ConfigStore) {
+				s.On("UpdateIfExists", mock.Anything, mock.Anything, mock.Anything).
+					Return(&store.ErrNotFound{})
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -77,6 +107,9 @@ func TestHandlers_UpdateResourceV3(t *testing.T) {
 
 			r, _ := http.NewRequest(http.MethodPut, "/", bytes.NewReader(tt.body))
 			r = mux.SetURLVars(r, tt.urlVars)
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
 
 			_, err := h.CreateOrUpdateResource(r)
 			if (err != nil) != tt.wantErr {
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// WatchEvent is a change of one of the watched resources.
+type WatchEvent struct {
+	Type     storev2.WatchActionType
+	Resource corev3.Resource
+	Err      error
+}
+
+// WatchResources watches the resources of the namespace of the context, or of
+// all the namespaces if the context has none. The channel is closed once the
+// context is done.
+func (h Handlers[R, T]) WatchResources(ctx context.Context) <-chan []WatchEvent {
+	namespace := corev2.ContextNamespace(ctx)
+	watch := storev2.Of[R](h.Store).Watch(ctx, storev2.ID{Namespace: namespace})
+
+	out := make(chan []WatchEvent, 1)
+	go func() {
+		defer close(out)
+		for {
+			var events []storev2.GenericEvent[R]
+			var ok bool
+			select {
+			case <-ctx.Done():
+				return
+			case events, ok = <-watch:
+				if !ok {
+					return
+				}
+			}
+
+			result := make([]WatchEvent, 0, len(events))
+			for _, event := range events {
+				if event.Err != nil {
+					result = append(result, WatchEvent{Type: storev2.WatchError, Err: event.Err})
+					continue
+				}
+				var resource R = event.Value
+				if resource == nil {
+					resource = new(T)
+				}
+				if meta := resource.GetMetadata(); meta == nil || meta.Name == "" {
+					// The deleted resources are only identified by their key
+					meta := event.Key
+					resource.SetMetadata(&meta)
+				}
+				result = append(result, WatchEvent{Type: event.Type, Resource: resource})
+			}
+
+			select {
+			case out <- result:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/testing/fixture"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandlers_WatchResources(t *testing.T) {
+	meta := corev2.NewObjectMeta("foo", "default")
+	wrapper, _ := storev2.WrapResource(&fixture.V3Resource{Metadata: &meta})
+	events := make(chan []storev2.WatchEvent, 1)
+	events <- []storev2.WatchEvent{
+		{Type: storev2.WatchCreate, Value: wrapper},
+		{Type: storev2.WatchDelete, Key: storev2.ResourceRequest{Namespace: "default", Name: "bar"}},
+	}
+
+	cs := new(mockstore.ConfigStore)
+	cs.On("Watch", mock.Anything, mock.MatchedBy(func(req storev2.ResourceRequest) bool {
+		return req.Namespace == "default" && req.Name == ""
+	})).Return((<-chan []storev2.WatchEvent)(events))
+	s := &mockstore.V2MockStore{}
+	s.On("GetConfigStore").Return(cs)
+	h := NewHandlers[*fixture.V3Resource](s)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), corev2.NamespaceKey, "default"))
+	watch := h.WatchResources(ctx)
+
+	select {
+	case got := <-watch:
+		if len(got) != 2 {
+			t.Fatalf("expected 2 events, got %d", len(got))
+		}
+		if got[0].Type != storev2.WatchCreate || got[0].Resource.GetMetadata().Name != "foo" {
+			t.Errorf("bad create event: %v", got[0])
+		}
+		if got[1].Type != storev2.WatchDelete || got[1].Resource.GetMetadata().Name != "bar" {
+			t.Errorf("bad delete event: %v", got[1])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-watch:
+		if ok {
+			t.Error("expected the watch to be closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the watch was not closed")
+	}
+}
@@ -0,0 +1,71 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/audit"
+	"github.com/sensu/sensu-go/backend/authorization"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+type auditDenialKey struct{}
+
+// Audit is an HTTP middleware that records the requests in the audit log. It
+// must follow the AuthorizationAttributes middleware, which identifies the
+// user and the resources of the request, and precede the Authorization
+// middleware, so that the denied requests are recorded as well.
+type Audit struct {
+	// Auditor records the requests. The middleware does nothing when nil.
+	Auditor *audit.Auditor
+}
+
+// Then middleware
+func (a Audit) Then(next http.Handler) http.Handler {
+	if a.Auditor == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		denied := new(bool)
+		ctx := context.WithValue(r.Context(), auditDenialKey{}, denied)
+		writerWithCapture := makeResponseWriterWithCapture(w)
+		next.ServeHTTP(writerWithCapture, r.WithContext(ctx))
+
+		attrs := authorization.GetAttributes(ctx)
+		if attrs == nil {
+			return
+		}
+		entry := &storev2.AuditEntry{
+			Timestamp:    start,
+			RequestID:    RequestIDFromContext(ctx),
+			User:         attrs.User.Username,
+			Verb:         attrs.Verb,
+			APIGroup:     attrs.APIGroup,
+			APIVersion:   attrs.APIVersion,
+			Namespace:    attrs.Namespace,
+			Resource:     attrs.Resource,
+			ResourceName: attrs.ResourceName,
+			Status:       writerWithCapture.Status(),
+			Outcome:      storev2.AuditOutcomeSuccess,
+			Latency:      time.Since(start).Seconds(),
+		}
+		if *denied {
+			entry.Outcome = storev2.AuditOutcomeDenied
+		} else if entry.Status >= http.StatusBadRequest {
+			entry.Outcome = storev2.AuditOutcomeFailure
+		}
+		a.Auditor.Record(entry)
+	})
+}
+
+// auditDenial marks the request of the context as denied in the audit log.
+// Denials cannot be told apart from the requests for missing resources by
+// their status, which is the same.
+func auditDenial(ctx context.Context) {
+	if denied, ok := ctx.Value(auditDenialKey{}).(*bool); ok {
+		*denied = true
+	}
+}
@@ -0,0 +1,102 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/audit"
+	"github.com/sensu/sensu-go/backend/authorization"
+	cachev2 "github.com/sensu/sensu-go/backend/store/cache/v2"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type auditSink chan *storev2.AuditEntry
+
+func (s auditSink) Name() string {
+	return "test"
+}
+
+func (s auditSink) Write(_ context.Context, entry *storev2.AuditEntry) error {
+	s <- entry
+	return nil
+}
+
+type staticAuthorizer bool
+
+func (a staticAuthorizer) Authorize(context.Context, *authorization.Attributes) (bool, error) {
+	return bool(a), nil
+}
+
+func TestAudit(t *testing.T) {
+	tests := []struct {
+		name        string
+		authorized  bool
+		status      int
+		wantOutcome string
+	}{
+		{
+			name:        "successful request",
+			authorized:  true,
+			status:      http.StatusCreated,
+			wantOutcome: storev2.AuditOutcomeSuccess,
+		},
+		{
+			name:        "failed request",
+			authorized:  true,
+			status:      http.StatusBadRequest,
+			wantOutcome: storev2.AuditOutcomeFailure,
+		},
+		{
+			name:        "denied request",
+			authorized:  false,
+			wantOutcome: storev2.AuditOutcomeDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			policy := &audit.AuditPolicy{
+				Metadata: corev2.NewObjectMetaP("policy", ""),
+				Rules: []corev2.Rule{{
+					Verbs:     []string{corev2.VerbAll},
+					Resources: []string{corev2.ResourceAll},
+				}},
+			}
+			sink := make(auditSink, 1)
+			auditor := audit.New(ctx, cachev2.NewFromResources([]*audit.AuditPolicy{policy}, false), sink)
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			})
+			stack := Apply(handler, Audit{Auditor: auditor}, Authorization{Authorizer: staticAuthorizer(tt.authorized)})
+
+			attrs := &authorization.Attributes{
+				APIGroup:   "core",
+				APIVersion: "v2",
+				Namespace:  "default",
+				Resource:   "checks",
+				User:       corev2.User{Username: "alice"},
+				Verb:       "create",
+			}
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req = req.WithContext(authorization.SetAttributes(req.Context(), attrs))
+			stack.ServeHTTP(httptest.NewRecorder(), req)
+
+			select {
+			case entry := <-sink:
+				assert.Equal(t, "alice", entry.User)
+				assert.Equal(t, "create", entry.Verb)
+				assert.Equal(t, "checks", entry.Resource)
+				assert.Equal(t, tt.wantOutcome, entry.Outcome)
+			case <-time.After(5 * time.Second):
+				t.Fatal("the request was not audited")
+			}
+		})
+	}
+}
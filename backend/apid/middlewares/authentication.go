@@ -5,11 +5,14 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/backend/apid/actions"
-	"github.com/sensu/sensu-go/backend/authentication/bcrypt"
+	"github.com/sensu/sensu-go/backend/authentication/apikey"
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/backend/store/history"
+	"github.com/sensu/sensu-go/backend/store/patch"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 )
 
@@ -83,20 +86,25 @@ func extractAPIKeyClaims(ctx context.Context, key string, store storev2.Interfac
 		return nil, err
 	}
 
+	now := time.Now()
 	for _, apiKey := range apiKeys {
-		if bcrypt.CheckPassword(string(apiKey.Hash), key) {
+		if apikey.Matches(apiKey, key, now) {
 			userStore := storev2.Of[*corev2.User](store)
 			user, err := userStore.Get(ctx, storev2.ID{Name: apiKey.Username})
 			if err != nil {
 				return nil, err
 			}
 
-			// inject the username and groups into standard jwt claims
+			// inject the username and groups into standard jwt claims, and
+			// identify the key with the claims ID
 			claims = &corev2.Claims{
 				StandardClaims: corev2.StandardClaims(user.Username),
 				Groups:         user.Groups,
 				APIKey:         true,
 			}
+			claims.Id = apiKey.Name
+
+			recordAPIKeyUse(ctx, keyStore, apiKey, now)
 
 			return claims, nil
 		}
@@ -105,6 +113,23 @@ func extractAPIKeyClaims(ctx context.Context, key string, store storev2.Interfac
 	return nil, errors.New("API key rejected")
 }
 
+// recordAPIKeyUse records the last time an API key was used, at most once per
+// apikey.LastUsedGranularity. The annotation is merged into the stored key in
+// the transaction of the patch, decrypted if the store is encrypted, so that
+// a concurrent rotation of the key is not overwritten. The use of the keys is
+// not a version of the keys, and is not recorded in their history.
+func recordAPIKeyUse(ctx context.Context, keyStore storev2.Generic[*corev2.APIKey, corev2.APIKey], key *corev2.APIKey, now time.Time) {
+	lastUsedAt, err := apikey.LastUsedAt(key)
+	if err == nil && now.Sub(lastUsedAt) < apikey.LastUsedGranularity {
+		return
+	}
+	id := storev2.ID{Name: key.Name}
+	ctx = history.ContextWithoutRecord(ctx)
+	if err := keyStore.Patch(ctx, id, &patch.Merge{MergePatch: apikey.LastUsedPatch(now)}); err != nil {
+		logger.WithError(err).WithField("apikey", key.Name).Warn("could not record the use of the api key")
+	}
+}
+
 type errorWriter struct {
 	err actions.Error
 }
@@ -6,8 +6,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication/apikey"
 	"github.com/sensu/sensu-go/backend/authentication/bcrypt"
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
 	"github.com/sensu/sensu-go/backend/store"
@@ -106,6 +108,7 @@ func TestMiddlewareValidAPIKey(t *testing.T) {
 	cs.On("Get", mock.Anything, keyReq).Return(mockstore.Wrapper[*corev2.APIKey]{Value: key}, nil)
 	cs.On("Get", mock.Anything, userReq).Return(mockstore.Wrapper[*corev2.User]{Value: user}, nil)
 	cs.On("List", mock.Anything, mock.Anything, mock.Anything).Return(mockstore.WrapList[*corev2.APIKey]{key}, nil)
+	cs.On("Patch", mock.Anything, keyReq, mock.Anything).Return(nil)
 
 	client := &http.Client{}
 	req, _ := http.NewRequest("GET", server.URL, nil)
@@ -113,6 +116,83 @@ func TestMiddlewareValidAPIKey(t *testing.T) {
 	res, err := client.Do(req)
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	// The use of the key is recorded
+	cs.AssertCalled(t, "Patch", mock.Anything, keyReq, mock.Anything)
+}
+
+func TestMiddlewareAPIKeyLastUsed(t *testing.T) {
+	store := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	store.On("GetConfigStore").Return(cs)
+	mware := Authentication{
+		Store: store,
+	}
+	server := httptest.NewServer(mware.Then(testHandler()))
+	defer server.Close()
+
+	secret := "174373d0-4aff-41d8-aa5f-084dfcad7dc7"
+	hash, err := bcrypt.HashPassword(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := &corev2.APIKey{
+		ObjectMeta: corev2.ObjectMeta{
+			Name: "foobar",
+			Annotations: map[string]string{
+				apikey.LastUsedAtAnnotation: time.Now().UTC().Format(time.RFC3339),
+			},
+		},
+		Username: "admin",
+		Hash:     []byte(hash),
+	}
+	user := &corev2.User{Username: "admin"}
+	userReq := storev2.NewResourceRequestFromResource(user)
+	cs.On("Get", mock.Anything, userReq).Return(mockstore.Wrapper[*corev2.User]{Value: user}, nil)
+	cs.On("List", mock.Anything, mock.Anything, mock.Anything).Return(mockstore.WrapList[*corev2.APIKey]{key}, nil)
+
+	client := &http.Client{}
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	req.Header.Add("Authorization", fmt.Sprintf("Key %s", secret))
+	res, err := client.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	// The key was used less than a minute ago, so its use is not recorded
+	cs.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMiddlewareExpiredAPIKey(t *testing.T) {
+	store := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	store.On("GetConfigStore").Return(cs)
+	mware := Authentication{
+		Store: store,
+	}
+	server := httptest.NewServer(mware.Then(testHandler()))
+	defer server.Close()
+
+	secret := "174373d0-4aff-41d8-aa5f-084dfcad7dc7"
+	hash, err := bcrypt.HashPassword(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := &corev2.APIKey{
+		ObjectMeta: corev2.ObjectMeta{
+			Name: "foobar",
+		},
+		Username: "admin",
+		Hash:     []byte(hash),
+	}
+	apikey.SetExpiresAt(key, time.Now().Add(-time.Minute))
+	cs.On("List", mock.Anything, mock.Anything, mock.Anything).Return(mockstore.WrapList[*corev2.APIKey]{key}, nil)
+
+	client := &http.Client{}
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	req.Header.Add("Authorization", fmt.Sprintf("Key %s", secret))
+	res, err := client.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
 }
 
 func TestMiddlewareInvalidAPIKey(t *testing.T) {
@@ -45,6 +45,7 @@ func (a Authorization) Then(next http.Handler) http.Handler {
 		authorized, err := a.Authorizer.Authorize(ctx, attrs)
 		if err != nil {
 			if _, ok := err.(rbac.ErrRoleNotFound); ok {
+				auditDenial(ctx)
 				writeErr(w, actions.NewErrorf(
 					actions.PermissionDenied,
 					err.Error(),
@@ -59,6 +60,7 @@ func (a Authorization) Then(next http.Handler) http.Handler {
 			return
 		}
 		if !authorized {
+			auditDenial(ctx)
 			writeErr(w, actions.NewErrorf(actions.PermissionDenied))
 			return
 		}
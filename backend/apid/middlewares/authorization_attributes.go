@@ -8,6 +8,7 @@ import (
 
 	"github.com/gorilla/mux"
 	v2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
 	"github.com/sensu/sensu-go/backend/authorization"
 )
@@ -80,9 +81,16 @@ func (a AuthorizationAttributes) Then(next http.Handler) http.Handler {
 			attrs.Verb = "list"
 		}
 
+		// Rolling a resource back to one of its versions replaces the
+		// resource, so it requires the update verb, not the create verb of
+		// the POST requests
+		if attrs.Verb == "create" && vars["resource_version"] != "" && path.Base(r.URL.Path) == "rollback" {
+			attrs.Verb = "update"
+		}
+
 		// Add the user to the attributes
 		if err := GetUser(ctx, attrs); err != nil {
-			http.Error(w, err.Error(), http.StatusUnauthorized)
+			writeErr(w, actions.NewError(actions.Unauthenticated, err))
 			return
 		}
 
@@ -98,6 +106,18 @@ func (a AuthorizationAttributes) Then(next http.Handler) http.Handler {
 			if attrs.Verb == "update" && vars["subresource"] == "password" {
 				attrs.Resource = v2.LocalSelfUserResource
 			}
+
+			// Change the resource to LocalSelfUserResource if a user lists its
+			// own sessions
+			if attrs.Verb == "get" && vars["subresource"] == "sessions" {
+				attrs.Resource = v2.LocalSelfUserResource
+			}
+
+			// Change the resource to LocalSelfUserResource if a user revokes
+			// one of its own sessions, e.g. a session it logged out of
+			if attrs.Verb == "delete" && vars["subresource"] == "sessions" && vars["session"] != "" {
+				attrs.Resource = v2.LocalSelfUserResource
+			}
 		}
 	})
 }
@@ -496,6 +496,19 @@ func TestLegacyAuthorizationAttributes(t *testing.T) {
 				Verb:		"update",
 			},
 		},
+		{
+			description:	"Roll back a resource",
+			method:		"POST",
+			path:		"/api/core/v2/namespaces/default/checks/foo/history/3/rollback",
+			expected: authorization.Attributes{
+				APIGroup:	"core",
+				APIVersion:	"v2",
+				Namespace:	"default",
+				Resource:	"checks",
+				ResourceName:	"foo",
+				Verb:		"update",
+			},
+		},
 		{
 			description:	"Update its own password",
 			method:		"PUT",
@@ -509,6 +522,58 @@ func TestLegacyAuthorizationAttributes(t *testing.T) {
 				Verb:		"update",
 			},
 		},
+		{
+			description:	"List its own sessions",
+			method:		"GET",
+			path:		"/api/core/v2/users/admin/sessions",
+			expected: authorization.Attributes{
+				APIGroup:	"core",
+				APIVersion:	"v2",
+				Namespace:	"",
+				Resource:	v2.LocalSelfUserResource,
+				ResourceName:	"admin",
+				Verb:		"get",
+			},
+		},
+		{
+			description:	"Revoke its own sessions",
+			method:		"DELETE",
+			path:		"/api/core/v2/users/admin/sessions",
+			expected: authorization.Attributes{
+				APIGroup:	"core",
+				APIVersion:	"v2",
+				Namespace:	"",
+				Resource:	"users",
+				ResourceName:	"admin",
+				Verb:		"delete",
+			},
+		},
+		{
+			description:	"Revoke one of its own sessions",
+			method:		"DELETE",
+			path:		"/api/core/v2/users/admin/sessions/abc",
+			expected: authorization.Attributes{
+				APIGroup:	"core",
+				APIVersion:	"v2",
+				Namespace:	"",
+				Resource:	v2.LocalSelfUserResource,
+				ResourceName:	"admin",
+				Verb:		"delete",
+			},
+		},
+		{
+			description:	"Revoke one of the sessions of another user",
+			method:		"DELETE",
+			path:		"/api/core/v2/users/foo/sessions/abc",
+			expected: authorization.Attributes{
+				APIGroup:	"core",
+				APIVersion:	"v2",
+				Namespace:	"",
+				Resource:	"users",
+				ResourceName:	"foo",
+				Verb:		"delete",
+			},
+		},
 	}
 
 	for _, tt := range cases {
@@ -539,12 +604,14 @@ func TestLegacyAuthorizationAttributes(t *testing.T) {
 			router := mux.NewRouter()
 			router.PathPrefix("/api/{group}/{version}/namespaces/{namespace}/{resource:cluster}/members/{id}").Handler(testHandler)
 			router.PathPrefix("/api/{group}/{version}/namespaces/{namespace}/{resource:cluster}/members").Handler(testHandler)
+			router.PathPrefix("/api/{group}/{version}/namespaces/{namespace}/{resource}/{id}/history/{resource_version}/rollback").Handler(testHandler)
 			router.PathPrefix("/api/{group}/{version}/namespaces/{namespace}/{resource:events}/{entity}/{check}").Handler(testHandler)
 			router.PathPrefix("/api/{group}/{version}/namespaces/{namespace}/{resource:events}/{entity}").Handler(testHandler)
 			router.PathPrefix("/api/{group}/{version}/namespaces/{namespace}/{resource:silenced}/checks/{check}").Handler(testHandler)
 			router.PathPrefix("/api/{group}/{version}/namespaces/{namespace}/{resource:silenced}/subscriptions/{subscription}").Handler(testHandler)
 			router.PathPrefix("/api/{group}/{version}/namespaces/{namespace}/{resource}/{id}").Handler(testHandler)
 			router.PathPrefix("/api/{group}/{version}/namespaces/{namespace}/{resource}").Handler(testHandler)
+			router.PathPrefix("/api/{group}/{version}/{resource:users}/{id}/{subresource:sessions}/{session}").Handler(testHandler)
 			router.PathPrefix("/api/{group}/{version}/{resource}/{id}/{subresource}").Handler(testHandler)
 			router.PathPrefix("/api/{group}/{version}/{resource}/{id}").Handler(testHandler)
 			router.PathPrefix("/api/{group}/{version}/{resource}").Handler(testHandler)
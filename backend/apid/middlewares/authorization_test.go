@@ -706,3 +706,87 @@ func TestRoleNotFound_GH4268(t *testing.T) {
 		t.Error(w.Body.String())
 	}
 }
+
+func TestRollbackAuthorization(t *testing.T) {
+	tests := []struct {
+		description  string
+		verbs        []string
+		method       string
+		url          string
+		expectedCode int
+	}{
+		{
+			description:  "the create verb grants permission to create",
+			verbs:        []string{"create"},
+			method:       "POST",
+			url:          "/api/core/v2/namespaces/default/checks",
+			expectedCode: http.StatusOK,
+		},
+		{
+			description:  "the create verb does not grant permission to roll back",
+			verbs:        []string{"create"},
+			method:       "POST",
+			url:          "/api/core/v2/namespaces/default/checks/foo/history/3/rollback",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			description:  "the update verb grants permission to roll back",
+			verbs:        []string{"update"},
+			method:       "POST",
+			url:          "/api/core/v2/namespaces/default/checks/foo/history/3/rollback",
+			expectedCode: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			role := &corev2.Role{
+				ObjectMeta: corev2.NewObjectMeta("checks", "default"),
+				Rules: []corev2.Rule{
+					{Verbs: tt.verbs, Resources: []string{"checks"}},
+				},
+			}
+			binding := &corev2.RoleBinding{
+				ObjectMeta: corev2.NewObjectMeta("checks", "default"),
+				RoleRef:    corev2.RoleRef{Type: "Role", Name: "checks"},
+				Subjects:   []corev2.Subject{{Type: "Group", Name: "checks"}},
+			}
+			st := new(mockstore.V2MockStore)
+			cs := new(mockstore.ConfigStore)
+			st.On("GetConfigStore").Return(cs)
+			rb := &corev2.RoleBinding{}
+			rb.Namespace = "default"
+			rbListReq := storev2.NewResourceRequestFromResource(rb)
+			cs.On("List", mock.Anything, rbListReq, mock.Anything).Return(mockstore.WrapList[*corev2.RoleBinding]{binding}, nil)
+			crbListReq := storev2.NewResourceRequestFromResource(new(corev2.ClusterRoleBinding))
+			cs.On("List", mock.Anything, crbListReq, mock.Anything).Return(mockstore.WrapList[*corev2.ClusterRoleBinding](nil), nil)
+			cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*corev2.Role]{Value: role}, nil)
+
+			testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			w := httptest.NewRecorder()
+			r, err := http.NewRequest(tt.method, tt.url, nil)
+			if err != nil {
+				t.Fatal("Couldn't create request: ", err)
+			}
+			claims := corev2.Claims{
+				StandardClaims: jwt.StandardClaims{Subject: "foo"},
+				Groups:         []string{"checks"},
+			}
+			ctx := sensuJWT.SetClaimsIntoContext(r, &claims)
+
+			namespaceMiddleware := middlewares.Namespace{}
+			attributesMiddleware := middlewares.AuthorizationAttributes{}
+			authorizationMiddleware := middlewares.Authorization{Authorizer: &rbac.Authorizer{Store: st}}
+
+			router := mux.NewRouter()
+			router.PathPrefix("/api/{group}/{version}/namespaces/{namespace}/{resource}/{id}/history/{resource_version}/rollback").Handler(testHandler)
+			router.PathPrefix("/api/{group}/{version}/namespaces/{namespace}/{resource}/{id}").Handler(testHandler)
+			router.PathPrefix("/api/{group}/{version}/namespaces/{namespace}/{resource}").Handler(testHandler)
+			router.Use(namespaceMiddleware.Then, attributesMiddleware.Then, authorizationMiddleware.Then)
+
+			router.ServeHTTP(w, r.WithContext(ctx))
+			if got, want := w.Code, tt.expectedCode; got != want {
+				t.Errorf("bad status: got %d, want %d: %s", got, want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package middlewares
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// MinCompressionSize is the default size, in bytes, under which the responses
+// are not compressed, since their compression would hardly save any bandwidth.
+const MinCompressionSize = 1024
+
+// The content encodings supported by the Compression middleware, by order of
+// preference.
+const (
+	encodingZstd = "zstd"
+	encodingGzip = "gzip"
+)
+
+// Compression is an HTTP middleware that compresses the responses with zstd
+// or gzip, as negotiated with the Accept-Encoding header of the request. The
+// event streams are never compressed, so that their events are delivered as
+// soon as they are flushed, and the upgrade requests are passed through, so
+// that their connection can be hijacked.
+type Compression struct {
+	// MinSize is the size, in bytes, under which the responses are not
+	// compressed.
+	MinSize int
+}
+
+// Then middleware
+func (c Compression) Then(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{w: w, encoding: encoding, minSize: c.MinSize, status: http.StatusOK}
+		defer func() {
+			if err := cw.Close(); err != nil {
+				logger.WithError(err).Error("failed to compress response")
+			}
+		}()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding returns the preferred encoding accepted by the client, or
+// an empty string if none of the supported encodings is accepted.
+func negotiateEncoding(header string) string {
+	accepted := map[string]bool{}
+	for _, value := range strings.Split(header, ",") {
+		parts := strings.Split(value, ";")
+		coding := strings.ToLower(strings.TrimSpace(parts[0]))
+		weight := 1.0
+		for _, param := range parts[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					weight = q
+				}
+			}
+		}
+		accepted[coding] = weight > 0
+	}
+	for _, encoding := range []string{encodingZstd, encodingGzip} {
+		if accepted[encoding] {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// compressWriter buffers the beginning of a response until it reaches the
+// minimum size, and then compresses the response. The responses that end
+// before reaching it are written as is.
+type compressWriter struct {
+	w        http.ResponseWriter
+	encoding string
+	minSize  int
+	status   int
+
+	buf     []byte
+	started bool
+	encoder io.WriteCloser
+}
+
+func (c *compressWriter) Header() http.Header {
+	return c.w.Header()
+}
+
+func (c *compressWriter) WriteHeader(status int) {
+	if !c.started {
+		c.status = status
+	}
+}
+
+func (c *compressWriter) Write(b []byte) (int, error) {
+	if !c.started {
+		c.buf = append(c.buf, b...)
+		if len(c.buf) < c.minSize {
+			return len(b), nil
+		}
+		if err := c.start(true); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if c.encoder != nil {
+		return c.encoder.Write(b)
+	}
+	return c.w.Write(b)
+}
+
+// Flush writes the buffered response, which is no longer compressed if it
+// did not reach the minimum size yet, and flushes the compressed data.
+func (c *compressWriter) Flush() {
+	if !c.started {
+		if err := c.start(false); err != nil {
+			logger.WithError(err).Error("failed to write response")
+			return
+		}
+	}
+	if f, ok := c.encoder.(interface{ Flush() error }); ok {
+		if err := f.Flush(); err != nil {
+			logger.WithError(err).Error("failed to compress response")
+		}
+	}
+	if f, ok := c.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close writes the rest of the response.
+func (c *compressWriter) Close() error {
+	if !c.started {
+		return c.start(false)
+	}
+	if c.encoder != nil {
+		return c.encoder.Close()
+	}
+	return nil
+}
+
+// start writes the headers and the buffered response, compressing it if
+// requested and if the response can be compressed.
+func (c *compressWriter) start(compress bool) error {
+	c.started = true
+	header := c.w.Header()
+	if compress && c.compressible() {
+		header.Del("Content-Length")
+		header.Set("Content-Encoding", c.encoding)
+		switch c.encoding {
+		case encodingZstd:
+			encoder, err := zstd.NewWriter(c.w, zstd.WithEncoderConcurrency(1))
+			if err != nil {
+				return err
+			}
+			c.encoder = encoder
+		case encodingGzip:
+			c.encoder = gzip.NewWriter(c.w)
+		}
+	}
+	c.w.WriteHeader(c.status)
+
+	buf := c.buf
+	c.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if c.encoder != nil {
+		_, err = c.encoder.Write(buf)
+	} else {
+		_, err = c.w.Write(buf)
+	}
+	return err
+}
+
+func (c *compressWriter) compressible() bool {
+	if c.status < http.StatusOK || c.status == http.StatusNoContent || c.status == http.StatusNotModified {
+		return false
+	}
+	header := c.w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	return !strings.HasPrefix(header.Get("Content-Type"), "text/event-stream")
+}
@@ -0,0 +1,90 @@
+package middlewares
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"gzip, deflate, br, zstd", "zstd"},
+		{"zstd;q=0, gzip;q=0.5", "gzip"},
+		{"GZIP", "gzip"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, negotiateEncoding(tt.header), tt.header)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	body := bytes.Repeat([]byte(`{"metadata":{"name":"entity"}}`), 100)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.URL.Query().Get("type"))
+		if r.URL.Query().Get("small") != "" {
+			_, _ = w.Write(body[:10])
+			return
+		}
+		_, _ = w.Write(body[:500])
+		_, _ = w.Write(body[500:])
+	})
+	stack := Compression{MinSize: MinCompressionSize}.Then(handler)
+
+	request := func(target, encoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("Accept-Encoding", encoding)
+		w := httptest.NewRecorder()
+		stack.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("/?type=application/json", "gzip")
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	decoded, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, body, decoded)
+
+	w = request("/?type=application/json", "zstd")
+	assert.Equal(t, "zstd", w.Header().Get("Content-Encoding"))
+	decoder, err := zstd.NewReader(w.Body)
+	require.NoError(t, err)
+	decoded, err = ioutil.ReadAll(decoder)
+	require.NoError(t, err)
+	assert.Equal(t, body, decoded)
+
+	w = request("/?type=application/json&small=true", "gzip")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, body[:10], w.Body.Bytes())
+
+	w = request("/?type=text/event-stream", "gzip")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, body, w.Body.Bytes())
+
+	w = request("/?type=application/json", "")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, body, w.Body.Bytes())
+
+	req := httptest.NewRequest(http.MethodGet, "/?type=application/json", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Upgrade", "websocket")
+	w = httptest.NewRecorder()
+	stack.ServeHTTP(w, req)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, body, w.Body.Bytes())
+}
@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/apid/request"
+)
+
+// DryRunHandler is the handler of a route supporting the dry runs of its
+// writes, which must not persist anything when the context of the request is
+// marked with request.ContextWithDryRun.
+type DryRunHandler struct {
+	http.Handler
+}
+
+// DryRun is an HTTP middleware marking the context of the requests with a
+// dryRun=server query parameter. The dry runs are rejected unless the handler
+// of the route is a DryRunHandler, so that they are never persisted by the
+// handlers that ignore them.
+type DryRun struct{}
+
+// Then middleware
+func (d DryRun) Then(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		values, ok := r.URL.Query()["dryRun"]
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(values) != 1 || values[0] != request.DryRunServer {
+			writeErr(w, actions.NewErrorf(actions.InvalidArgument, "unsupported dry run mode, expected dryRun=%s", request.DryRunServer))
+			return
+		}
+		route := mux.CurrentRoute(r)
+		if route == nil {
+			writeErr(w, actions.NewErrorf(actions.InvalidArgument, "dry runs are not supported by this endpoint"))
+			return
+		}
+		if _, ok := route.GetHandler().(DryRunHandler); !ok {
+			writeErr(w, actions.NewErrorf(actions.InvalidArgument, "dry runs are not supported by this endpoint"))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(request.ContextWithDryRun(r.Context())))
+	})
+}
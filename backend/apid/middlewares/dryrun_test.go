@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sensu/sensu-go/backend/apid/request"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if request.DryRunFromContext(r.Context()) {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	router := mux.NewRouter()
+	router.Use(DryRun{}.Then)
+	router.Handle("/checks", DryRunHandler{Handler: handler})
+	router.Handle("/entities", handler)
+
+	request := func(path string) int {
+		req := httptest.NewRequest(http.MethodPut, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusCreated, request("/checks"))
+	assert.Equal(t, http.StatusOK, request("/checks?dryRun=server"))
+	assert.Equal(t, http.StatusBadRequest, request("/checks?dryRun=client"))
+	assert.Equal(t, http.StatusCreated, request("/entities"))
+	assert.Equal(t, http.StatusBadRequest, request("/entities?dryRun=server"))
+}
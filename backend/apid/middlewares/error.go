@@ -12,10 +12,7 @@ func writeErr(w http.ResponseWriter, err error) {
 	if erro, ok := err.(actions.Error); ok {
 		errRes = erro
 	} else {
-		errRes = actions.Error{
-			Code:    actions.InternalErr,
-			Message: err.Error(),
-		}
+		errRes = actions.NewError(actions.InternalErr, err)
 	}
 
 	var st int
@@ -32,13 +29,18 @@ func writeErr(w http.ResponseWriter, err error) {
 		st = http.StatusForbidden
 	case actions.Unauthenticated:
 		st = http.StatusUnauthorized
+	case actions.ResourceExhausted:
+		st = http.StatusTooManyRequests
+	case actions.Unavailable:
+		st = http.StatusServiceUnavailable
 	}
 
-	errJSON, err := json.Marshal(errRes)
+	errJSON, err := json.Marshal(errRes.Body(w.Header().Get(RequestIDHeader)))
 	if err != nil {
 		logger.WithError(err).Error("unable to marshal error")
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(st)
 	_, _ = w.Write(errJSON)
 }
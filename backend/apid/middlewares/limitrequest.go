@@ -3,6 +3,8 @@ package middlewares
 import (
 	"io"
 	"net/http"
+
+	"github.com/sensu/sensu-go/backend/apid/actions"
 )
 
 // MaxBytesLimit is the default max http request size, in bytes (see https://docs.sensu.io/sensu-go/latest/api/#request-size-limit)
@@ -22,7 +24,7 @@ func (l LimitRequest) Then(next http.Handler) http.Handler {
 		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 		err := r.ParseForm()
 		if err != nil && err != io.EOF {
-			http.Error(w, "Request exceeded max length", http.StatusInternalServerError)
+			writeErr(w, actions.NewErrorf(actions.InternalErr, "request exceeded max length"))
 			return
 		}
 		next.ServeHTTP(w, r)
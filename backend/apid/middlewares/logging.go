@@ -40,6 +40,9 @@ func (m SimpleLogger) Then(next http.Handler) http.Handler {
 			"method":   r.Method,
 			"user":     user,
 		})
+		if id := RequestIDFromContext(r.Context()); id != "" {
+			logEntry = logEntry.WithField("request_id", id)
+		}
 		logEntry.Info("request completed")
 	})
 }
@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/sensu/sensu-go/backend/quota"
+)
+
+// Quota is an HTTP middleware that enforces the quotas of the namespaces on
+// the creation of resources and events. It must follow the Authorization
+// middleware, so that the quotas are only revealed to authorized users.
+type Quota struct {
+	// Enforcer enforces the quotas. The middleware does nothing when nil.
+	Enforcer *quota.Enforcer
+}
+
+// Then middleware
+func (q Quota) Then(next http.Handler) http.Handler {
+	if q.Enforcer == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attrs := authorization.GetAttributes(r.Context())
+		if attrs == nil || attrs.Namespace == "" || (r.Method != http.MethodPost && r.Method != http.MethodPut) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		switch attrs.Resource {
+		case quota.ResourceChecks, quota.ResourceEntities, quota.ResourceSilenced:
+			// PUT creates the resource if it does not exist yet, POST always
+			// creates it.
+			var name string
+			if r.Method == http.MethodPut {
+				name = attrs.ResourceName
+			}
+			err := q.Enforcer.CheckCreate(r.Context(), attrs.Namespace, attrs.Resource, name)
+			if _, ok := err.(*quota.ExceededError); ok {
+				writeErr(w, actions.NewError(actions.ResourceExhausted, err))
+				return
+			} else if err != nil {
+				writeErr(w, actions.NewError(actions.InternalErr, err))
+				return
+			}
+		case "events":
+			if !q.Enforcer.AllowEvent(attrs.Namespace) {
+				writeErr(w, actions.NewErrorf(actions.ResourceExhausted, "the events quota of the namespace is exceeded"))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,50 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/sensu/sensu-go/backend/quota"
+	cachev2 "github.com/sensu/sensu-go/backend/store/cache/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestQuota(t *testing.T) {
+	cs := new(mockstore.ConfigStore)
+	cs.On("Exists", mock.Anything, mock.Anything).Return(false, nil)
+	cs.On("Count", mock.Anything, mock.Anything).Return(1, nil)
+	s := &mockstore.V2MockStore{}
+	s.On("GetConfigStore").Return(cs)
+
+	quotas := cachev2.NewFromResources([]*quota.NamespaceQuota{{
+		Metadata:           corev2.NewObjectMetaP("quota", "default"),
+		MaxChecks:          1,
+		MaxEventsPerSecond: 1,
+	}}, false)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	stack := Apply(handler, Quota{Enforcer: quota.NewEnforcer(quotas, s)})
+
+	request := func(method, resource, name string) int {
+		req := httptest.NewRequest(method, "/", nil)
+		attrs := &authorization.Attributes{Namespace: "default", Resource: resource, ResourceName: name}
+		req = req.WithContext(authorization.SetAttributes(req.Context(), attrs))
+		w := httptest.NewRecorder()
+		stack.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests, request(http.MethodPost, "checks", ""))
+	assert.Equal(t, http.StatusTooManyRequests, request(http.MethodPut, "checks", "new"))
+	assert.Equal(t, http.StatusOK, request(http.MethodGet, "checks", ""))
+	assert.Equal(t, http.StatusOK, request(http.MethodPost, "handlers", ""))
+
+	assert.Equal(t, http.StatusOK, request(http.MethodPost, "events", ""))
+	assert.Equal(t, http.StatusTooManyRequests, request(http.MethodPost, "events", ""))
+}
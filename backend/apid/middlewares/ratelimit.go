@@ -0,0 +1,141 @@
+package middlewares
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"golang.org/x/time/rate"
+)
+
+// RateLimiterIdleTimeout is the duration after which the token bucket of a
+// client that stopped making requests is discarded.
+const RateLimiterIdleTimeout = 10 * time.Minute
+
+type rateLimitKey struct {
+	namespace string
+	client    string
+}
+
+type rateLimitBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimiter holds a token bucket for each client of the API, either a user
+// or an API key. The requests made to the namespaces that have their own limit
+// are counted separately from the other requests.
+type RateLimiter struct {
+	limit      rate.Limit
+	burst      int
+	namespaces map[string]rate.Limit
+
+	mu        sync.Mutex
+	buckets   map[rateLimitKey]*rateLimitBucket
+	lastSweep time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing each client limit requests per
+// second, and the given limits per second in the namespaces of the map, with
+// bursts of the given size. A limit of 0 disables the rate limiting.
+func NewRateLimiter(limit rate.Limit, burst int, namespaces map[string]rate.Limit) *RateLimiter {
+	return &RateLimiter{
+		limit:      limit,
+		burst:      burst,
+		namespaces: namespaces,
+		buckets:    make(map[rateLimitKey]*rateLimitBucket),
+		lastSweep:  time.Now(),
+	}
+}
+
+// Reserve takes a token from the bucket of the client for a request in the
+// given namespace. It returns 0 if the request is allowed, and otherwise how
+// long the client must wait before its next request is allowed.
+func (l *RateLimiter) Reserve(namespace, client string) time.Duration {
+	limit, ok := l.namespaces[namespace]
+	if !ok {
+		// The requests to the other namespaces, and the requests for cluster-wide
+		// resources, share the bucket of the global limit.
+		limit = l.limit
+		namespace = ""
+	}
+	if limit <= 0 {
+		return 0
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > RateLimiterIdleTimeout {
+		for key, bucket := range l.buckets {
+			if now.Sub(bucket.lastSeen) > RateLimiterIdleTimeout {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	key := rateLimitKey{namespace: namespace, client: client}
+	bucket, ok := l.buckets[key]
+	if !ok {
+		burst := l.burst
+		if burst < 1 {
+			burst = int(math.Ceil(float64(limit)))
+		}
+		bucket = &rateLimitBucket{limiter: rate.NewLimiter(limit, burst)}
+		l.buckets[key] = bucket
+	}
+	bucket.lastSeen = now
+
+	reservation := bucket.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return delay
+	}
+	return 0
+}
+
+// RateLimit is an HTTP middleware that limits the rate of the requests made by
+// each authenticated user or API key, and responds with 429 Too Many Requests
+// to the requests exceeding it. It must follow the Authentication middleware.
+type RateLimit struct {
+	// Limiter tracks the requests of the clients. The middleware does nothing
+	// when nil.
+	Limiter *RateLimiter
+}
+
+// Then middleware
+func (l RateLimit) Then(next http.Handler) http.Handler {
+	if l.Limiter == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims := jwt.GetClaimsFromContext(r.Context())
+		if claims == nil {
+			// Anonymous requests are left to the other limits
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		client := "user:" + claims.Subject
+		if claims.APIKey {
+			client = "key:" + claims.Id
+		}
+		namespace := corev2.ContextNamespace(r.Context())
+
+		if delay := l.Limiter.Reserve(namespace, client); delay > 0 {
+			seconds := int(math.Ceil(delay.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			writeErr(w, actions.NewErrorf(actions.ResourceExhausted, "rate limit exceeded"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
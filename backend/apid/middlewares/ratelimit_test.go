@@ -0,0 +1,66 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	limiter := NewRateLimiter(1, 2, map[string]rate.Limit{"unlimited": 0, "slow": 0.001})
+
+	assert.Zero(t, limiter.Reserve("default", "user:alice"))
+	assert.Zero(t, limiter.Reserve("", "user:alice"))
+	assert.NotZero(t, limiter.Reserve("default", "user:alice"), "the global bucket should be exhausted")
+
+	// Every client has its own bucket
+	assert.Zero(t, limiter.Reserve("default", "user:bob"))
+
+	// The namespaces with their own limit have their own buckets
+	for i := 0; i < 5; i++ {
+		assert.Zero(t, limiter.Reserve("unlimited", "user:alice"))
+	}
+	assert.Zero(t, limiter.Reserve("slow", "user:alice"))
+	assert.Zero(t, limiter.Reserve("slow", "user:alice"))
+	assert.NotZero(t, limiter.Reserve("slow", "user:alice"))
+}
+
+func TestRateLimit(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	stack := Apply(handler, RateLimit{Limiter: NewRateLimiter(0.5, 1, nil)})
+
+	request := func(claims *corev2.Claims) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		ctx := context.WithValue(req.Context(), corev2.NamespaceKey, "default")
+		if claims != nil {
+			ctx = jwt.SetClaimsIntoContext(req.WithContext(ctx), claims)
+		}
+		w := httptest.NewRecorder()
+		stack.ServeHTTP(w, req.WithContext(ctx))
+		return w
+	}
+
+	user := &corev2.Claims{StandardClaims: corev2.StandardClaims("alice")}
+	assert.Equal(t, http.StatusOK, request(user).Code)
+	w := request(user)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+
+	// The API keys of the user are limited separately
+	key := &corev2.Claims{StandardClaims: corev2.StandardClaims("alice"), APIKey: true}
+	key.Id = "key1"
+	assert.Equal(t, http.StatusOK, request(key).Code)
+	assert.Equal(t, http.StatusTooManyRequests, request(key).Code)
+
+	// Anonymous requests are not limited
+	assert.Equal(t, http.StatusOK, request(nil).Code)
+	assert.Equal(t, http.StatusOK, request(nil).Code)
+}
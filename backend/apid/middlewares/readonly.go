@@ -0,0 +1,79 @@
+package middlewares
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/apid/request"
+	"github.com/sensu/sensu-go/backend/authorization"
+)
+
+// MaintenanceResource is the RBAC name of the maintenance mode of the API,
+// which can still be updated when the API is read-only.
+const MaintenanceResource = "maintenance"
+
+// ReadOnlyMode is the read-only maintenance mode of the API, which can be
+// toggled at runtime. The mode is kept in memory, by each backend.
+type ReadOnlyMode struct {
+	enabled int32
+}
+
+// NewReadOnlyMode returns a ReadOnlyMode, initially enabled or not.
+func NewReadOnlyMode(enabled bool) *ReadOnlyMode {
+	m := &ReadOnlyMode{}
+	m.Set(enabled)
+	return m
+}
+
+// Enabled returns whether the API is read-only.
+func (m *ReadOnlyMode) Enabled() bool {
+	return m != nil && atomic.LoadInt32(&m.enabled) == 1
+}
+
+// Set enables or disables the read-only mode.
+func (m *ReadOnlyMode) Set(enabled bool) {
+	var value int32
+	if enabled {
+		value = 1
+	}
+	atomic.StoreInt32(&m.enabled, value)
+}
+
+// ReadOnly is an HTTP middleware that rejects the requests with mutating verbs
+// with 503 Service Unavailable while the API is read-only, except those to the
+// maintenance mode itself and the dry runs. It must follow the
+// AuthorizationAttributes and DryRun middlewares.
+type ReadOnly struct {
+	// Mode is the maintenance mode. The middleware does nothing when nil.
+	Mode *ReadOnlyMode
+}
+
+// Then middleware
+func (m ReadOnly) Then(next http.Handler) http.Handler {
+	if m.Mode == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.Mode.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+		if attrs := authorization.GetAttributes(r.Context()); attrs != nil && attrs.Resource == MaintenanceResource {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// The dry runs don't persist anything
+		if request.DryRunFromContext(r.Context()) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		writeErr(w, actions.NewErrorf(actions.Unavailable, "the API is read-only for maintenance"))
+	})
+}
@@ -0,0 +1,49 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sensu/sensu-go/backend/apid/request"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnly(t *testing.T) {
+	mode := NewReadOnlyMode(true)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	stack := Apply(handler, ReadOnly{Mode: mode})
+
+	request := func(method, resource string) int {
+		req := httptest.NewRequest(method, "/", nil)
+		attrs := &authorization.Attributes{Resource: resource}
+		req = req.WithContext(authorization.SetAttributes(req.Context(), attrs))
+		w := httptest.NewRecorder()
+		stack.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request(http.MethodGet, "checks"))
+	assert.Equal(t, http.StatusServiceUnavailable, request(http.MethodPost, "checks"))
+	assert.Equal(t, http.StatusServiceUnavailable, request(http.MethodDelete, "checks"))
+	assert.Equal(t, http.StatusOK, request(http.MethodPut, MaintenanceResource))
+
+	mode.Set(false)
+	assert.Equal(t, http.StatusOK, request(http.MethodPost, "checks"))
+}
+
+func TestReadOnlyDryRun(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	stack := Apply(handler, ReadOnly{Mode: NewReadOnlyMode(true)})
+
+	req := httptest.NewRequest(http.MethodPut, "/", nil)
+	req = req.WithContext(request.ContextWithDryRun(req.Context()))
+	w := httptest.NewRecorder()
+	stack.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	v2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
 )
 
@@ -40,7 +41,7 @@ func (m RefreshToken) Then(next http.Handler) http.Handler {
 		accessToken, err := jwt.ValidateExpiredToken(accessTokenString)
 		if err != nil {
 			logger.WithError(err).Error("access token is invalid")
-			http.Error(w, "Request unauthorized", http.StatusUnauthorized)
+			writeErr(w, actions.NewErrorf(actions.Unauthenticated, "request unauthorized"))
 			return
 		}
 
@@ -48,12 +49,12 @@ func (m RefreshToken) Then(next http.Handler) http.Handler {
 		accessClaims, err := jwt.GetClaims(accessToken)
 		if err != nil {
 			logger.WithError(err).Error("could not parse the access token claims")
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeErr(w, actions.NewError(actions.InvalidArgument, err))
 			return
 		}
 
 		if accessClaims.Provider.ProviderType == "oidc" {
-			http.Redirect(w, r, "/api/enterprise/authentication/v2/oidc/token", http.StatusTemporaryRedirect)
+			http.Redirect(w, r, "/api/authentication/v2/oidc/token", http.StatusTemporaryRedirect)
 			return
 		}
 
@@ -62,7 +63,7 @@ func (m RefreshToken) Then(next http.Handler) http.Handler {
 		err = decoder.Decode(payload)
 		if err != nil {
 			logger.WithError(err).Error("could not decode the refresh token")
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeErr(w, actions.NewError(actions.InvalidArgument, err))
 			return
 		}
 
@@ -70,7 +71,7 @@ func (m RefreshToken) Then(next http.Handler) http.Handler {
 		refreshToken, err := jwt.ValidateToken(payload.Refresh)
 		if err != nil {
 			logger.WithError(err).Error("refresh token is invalid")
-			http.Error(w, "Request unauthorized", http.StatusUnauthorized)
+			writeErr(w, actions.NewErrorf(actions.Unauthenticated, "request unauthorized"))
 			return
 		}
 
@@ -78,7 +79,7 @@ func (m RefreshToken) Then(next http.Handler) http.Handler {
 		refreshClaims, err := jwt.GetClaims(refreshToken)
 		if err != nil {
 			logger.WithError(err).Error("could not parse the refresh token claims")
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeErr(w, actions.NewError(actions.InvalidArgument, err))
 			return
 		}
 
@@ -89,7 +90,7 @@ func (m RefreshToken) Then(next http.Handler) http.Handler {
 				"access_token":		accessClaims.Subject,
 				"refresh_token":	refreshClaims.Subject,
 			}).Error("the access and refresh tokens subject do not match")
-			http.Error(w, "Request unauthorized", http.StatusUnauthorized)
+			writeErr(w, actions.NewErrorf(actions.Unauthenticated, "request unauthorized"))
 			return
 		}
 
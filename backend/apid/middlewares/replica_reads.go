@@ -0,0 +1,23 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// ReplicaReads is an HTTP middleware that lets the store serve the reads of
+// the requests with read-only verbs from a read replica, if it has one. The
+// responses can then be slightly out of date.
+type ReplicaReads struct{}
+
+// Then middleware
+func (m ReplicaReads) Then(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			r = r.WithContext(store.ReplicaReadsContext(r.Context()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
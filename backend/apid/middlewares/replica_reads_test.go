@@ -0,0 +1,30 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplicaReads(t *testing.T) {
+	var replicaReads bool
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		replicaReads = store.IsReplicaReadsContext(r.Context())
+	})
+	stack := Apply(handler, ReplicaReads{})
+
+	for method, want := range map[string]bool{
+		http.MethodGet:    true,
+		http.MethodHead:   true,
+		http.MethodPost:   false,
+		http.MethodPut:    false,
+		http.MethodPatch:  false,
+		http.MethodDelete: false,
+	} {
+		stack.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/", nil))
+		assert.Equal(t, want, replicaReads, method)
+	}
+}
@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is the header used to correlate a request with its response
+// and with the log entries it produces.
+const RequestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength is the maximum length of a request ID provided by a client
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestID assigns a correlation ID to every request. A valid ID provided by
+// the client in the X-Request-Id header is reused, otherwise one is generated.
+// The ID is stored in the request context and echoed in the response headers.
+type RequestID struct{}
+
+// Then middleware
+func (RequestID) Then(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.New().String()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestIDFromContext returns the request ID stored in the context, if any.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
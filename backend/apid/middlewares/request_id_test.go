@@ -0,0 +1,60 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expectID string
+	}{
+		{
+			name:     "client provided ID is reused",
+			header:   "abc-123",
+			expectID: "abc-123",
+		},
+		{
+			name:   "missing ID is generated",
+			header: "",
+		},
+		{
+			name:   "invalid ID is replaced",
+			header: "has spaces",
+		},
+		{
+			name:   "oversized ID is replaced",
+			header: strings.Repeat("a", maxRequestIDLength+1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fromContext string
+			handler := RequestID{}.Then(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fromContext = RequestIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			got := w.Header().Get(RequestIDHeader)
+			assert.NotEmpty(t, got)
+			assert.Equal(t, got, fromContext)
+			if tt.expectID != "" {
+				assert.Equal(t, tt.expectID, got)
+			} else {
+				assert.NotEqual(t, tt.header, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,382 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+)
+
+// apiPrefix is the prefix of the routes described by the document.
+const apiPrefix = "/api/core/"
+
+// resources maps the resource segments of the core routes, qualified by their
+// API version, to the type of the resources. Routes that do not refer to one
+// of these are documented with free-form bodies.
+var resources = map[string]interface{}{
+	"core/v2/apikeys":             &corev2.APIKey{},
+	"core/v2/assets":              &corev2.Asset{},
+	"core/v2/checks":              &corev2.CheckConfig{},
+	"core/v2/clusterrolebindings": &corev2.ClusterRoleBinding{},
+	"core/v2/clusterroles":        &corev2.ClusterRole{},
+	"core/v2/entities":            &corev2.Entity{},
+	"core/v2/events":              &corev2.Event{},
+	"core/v2/filters":             &corev2.EventFilter{},
+	"core/v2/handlers":            &corev2.Handler{},
+	"core/v2/hooks":               &corev2.HookConfig{},
+	"core/v2/mutators":            &corev2.Mutator{},
+	"core/v2/pipelines":           &corev2.Pipeline{},
+	"core/v2/rolebindings":        &corev2.RoleBinding{},
+	"core/v2/roles":               &corev2.Role{},
+	"core/v2/silenced":            &corev2.Silenced{},
+	"core/v2/tessen":              &corev2.TessenConfig{},
+	"core/v2/users":               &corev2.User{},
+	"core/v3/namespaces":          &corev3.Namespace{},
+}
+
+// listSuffixes are the path suffixes, following the resource segment, of the
+// GET routes that list resources rather than reading a single one.
+var listSuffixes = map[string]bool{
+	"":                             true,
+	"{subcollection}":              true,
+	"checks/{check}":               true,
+	"subscriptions/{subscription}": true,
+}
+
+// varPattern matches the variables of a route template, with their optional
+// pattern, e.g. {resource:checks}.
+var varPattern = regexp.MustCompile(`^\{([^:}]+)(?::(.+))?\}$`)
+
+const (
+	bearerAuth = "bearerAuth"
+	apiKeyAuth = "apiKeyAuth"
+
+	errorSchema      = "Error"
+	bulkResultSchema = "BulkResult"
+)
+
+// Generate walks the routes of the router and describes those of the core API
+// in an OpenAPI document. The request and response schemas are derived from
+// the resource types.
+func Generate(router *mux.Router, version string) (*Document, error) {
+	g := &generator{
+		schemas: schemas{},
+		paths:   map[string]map[string]*Operation{},
+	}
+	g.schemas[errorSchema] = g.schemas.structSchema(reflect.TypeOf(actions.ErrorBody{}))
+	g.schemas[bulkResultSchema] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"name":   {Type: "string"},
+			"status": {Type: "integer", Format: "int32"},
+			"error":  ref(errorSchema),
+		},
+	}
+
+	watchable := map[string]bool{}
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			// Subrouters and catch-all routes do not describe an operation
+			return nil
+		}
+		path, params := normalize(template)
+		if !strings.HasPrefix(path, apiPrefix) {
+			return nil
+		}
+		if queries, _ := route.GetQueriesTemplates(); len(queries) > 0 && queries[0] == "watch=true" {
+			// Watches are described as a parameter of the list operation
+			watchable[path] = true
+			return nil
+		}
+		for _, method := range methods {
+			g.addOperation(method, path, params)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't walk the routes: %s", err)
+	}
+	for path := range watchable {
+		if op, ok := g.paths[path]["get"]; ok {
+			describeWatch(op)
+		}
+	}
+
+	return &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       "Sensu API",
+			Description: "The core API of the Sensu backend.",
+			Version:     version,
+		},
+		Paths: g.paths,
+		Components: Components{
+			Schemas: g.schemas,
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerAuth: {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "JWT",
+					Description:  "Access token obtained from the /auth endpoint.",
+				},
+				apiKeyAuth: {
+					Type:        "apiKey",
+					In:          "header",
+					Name:        "Authorization",
+					Description: `API key, given as "Key <api key>".`,
+				},
+			},
+		},
+		Security: []SecurityRequirement{
+			{bearerAuth: []string{}},
+			{apiKeyAuth: []string{}},
+		},
+	}, nil
+}
+
+type generator struct {
+	schemas schemas
+	paths   map[string]map[string]*Operation
+}
+
+// normalize turns a route template into an OpenAPI path. The variables with a
+// literal pattern, e.g. {resource:checks}, are replaced by their value, and
+// the pattern of the others is dropped. The names of the path parameters are
+// returned in order.
+func normalize(template string) (string, []string) {
+	segments := strings.Split(template, "/")
+	var params []string
+	for i, segment := range segments {
+		match := varPattern.FindStringSubmatch(segment)
+		if match == nil {
+			continue
+		}
+		if pattern := match[2]; pattern != "" && regexp.QuoteMeta(pattern) == pattern {
+			segments[i] = pattern
+			continue
+		}
+		segments[i] = "{" + match[1] + "}"
+		params = append(params, match[1])
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// addOperation describes the operation of the given method on the path.
+func (g *generator) addOperation(method, path string, params []string) {
+	item, ok := g.paths[path]
+	if !ok {
+		item = map[string]*Operation{}
+		g.paths[path] = item
+	}
+	key := strings.ToLower(method)
+	if _, ok := item[key]; ok {
+		return
+	}
+
+	op := &Operation{
+		OperationID: operationID(method, path),
+		Responses: map[string]Response{
+			"default": {
+				Description: "Error",
+				Content:     jsonContent(ref(errorSchema)),
+			},
+		},
+	}
+	for _, param := range params {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     param,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	item[key] = op
+
+	apiVersion, resource, suffix, bulk := splitPath(path)
+	prototype, ok := resources[apiVersion+"/"+resource]
+	if !ok {
+		describeFreeForm(op, method)
+		return
+	}
+	op.Tags = []string{resource}
+	wrapper := g.wrapper(prototype)
+
+	switch {
+	case bulk:
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(&Schema{Type: "array", Items: wrapper}),
+		}
+		op.Responses["200"] = Response{
+			Description: "Outcome of the operation on each resource",
+			Content:     jsonContent(&Schema{Type: "array", Items: ref(bulkResultSchema)}),
+		}
+	case method == http.MethodPost && suffix == "bulk":
+		// Proxy entities are created in bulk under the entities route
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(&Schema{Type: "array", Items: wrapper}),
+		}
+		op.Responses["200"] = Response{
+			Description: "Outcome of the operation on each resource",
+			Content:     jsonContent(&Schema{Type: "array", Items: g.schemas.schemaOf(reflect.TypeOf(actions.BulkEntityResult{}))}),
+		}
+	case method == http.MethodGet && suffix == "stream":
+		op.Responses["200"] = Response{
+			Description: "Stream of server-sent events",
+			Content:     map[string]MediaType{"text/event-stream": {Schema: &Schema{Type: "string"}}},
+		}
+	case method == http.MethodGet && listSuffixes[suffix]:
+		op.Parameters = append(op.Parameters, listParameters()...)
+		op.Responses["200"] = Response{
+			Description: "List of resources",
+			Headers: map[string]Header{
+				corev2.PaginationContinueHeader: {
+					Description: "Token to pass as the continue parameter to retrieve the next page",
+					Schema:      &Schema{Type: "string"},
+				},
+			},
+			Content: jsonContent(&Schema{Type: "array", Items: wrapper}),
+		}
+	case method == http.MethodGet && isIdentifier(suffix):
+		op.Responses["200"] = Response{Description: "The resource", Content: jsonContent(wrapper)}
+	case (method == http.MethodPost || method == http.MethodPut) && (suffix == "" || isIdentifier(suffix)):
+		op.RequestBody = &RequestBody{Required: true, Content: jsonContent(wrapper)}
+		op.Responses["201"] = Response{Description: "The resource was created"}
+		op.Responses["204"] = Response{Description: "The resource was updated"}
+	case method == http.MethodPatch && isIdentifier(suffix):
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				"application/merge-patch+json": {Schema: &Schema{Type: "object"}},
+				"application/json-patch+json":  {Schema: &Schema{Type: "array", Items: &Schema{Type: "object"}}},
+			},
+		}
+		op.Responses["204"] = Response{Description: "The resource was patched"}
+	case method == http.MethodDelete && isIdentifier(suffix):
+		op.Responses["204"] = Response{Description: "The resource was deleted"}
+	default:
+		describeFreeForm(op, method)
+	}
+}
+
+// wrapper returns the schema of the resource wrapped with its type and API
+// version, as sent and received by the API.
+func (g *generator) wrapper(prototype interface{}) *Schema {
+	wrapped := types.WrapResource(prototype)
+	name := schemaName(reflect.TypeOf(prototype).Elem()) + "Wrapper"
+	if _, ok := g.schemas[name]; !ok {
+		g.schemas[name] = &Schema{
+			Type:     "object",
+			Required: []string{"type", "api_version", "spec"},
+			Properties: map[string]*Schema{
+				"type":        {Type: "string", Enum: []string{wrapped.Type}},
+				"api_version": {Type: "string", Enum: []string{wrapped.APIVersion}},
+				"spec":        g.schemas.schemaOf(reflect.TypeOf(prototype)),
+			},
+		}
+	}
+	return ref(name)
+}
+
+// splitPath splits a normalized path into the API version, the resource and
+// the part of the path following the resource. The namespace segments and
+// the bulk segment, reported separately, are skipped.
+func splitPath(path string) (apiVersion, resource, suffix string, bulk bool) {
+	segments := strings.Split(strings.TrimPrefix(path, "/api/"), "/")
+	if len(segments) < 3 {
+		return "", "", "", false
+	}
+	apiVersion = segments[0] + "/" + segments[1]
+	segments = segments[2:]
+	if len(segments) > 2 && segments[0] == "namespaces" && segments[1] == "{namespace}" {
+		segments = segments[2:]
+	}
+	if len(segments) > 1 && segments[0] == "bulk" {
+		bulk = true
+		segments = segments[1:]
+	}
+	return apiVersion, segments[0], strings.Join(segments[1:], "/"), bulk
+}
+
+// isIdentifier returns whether the suffix of a path identifies a single
+// resource, e.g. {id} or {entity}/{check}.
+func isIdentifier(suffix string) bool {
+	if suffix == "" {
+		return false
+	}
+	for _, segment := range strings.Split(suffix, "/") {
+		if !strings.HasPrefix(segment, "{") || segment == "{subcollection}" {
+			return false
+		}
+	}
+	return true
+}
+
+// describeFreeForm describes an operation whose request and response bodies
+// are not resources.
+func describeFreeForm(op *Operation, method string) {
+	if method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch {
+		op.RequestBody = &RequestBody{Content: jsonContent(&Schema{})}
+	}
+	op.Responses["200"] = Response{Description: "Success", Content: jsonContent(&Schema{})}
+	op.Responses["204"] = Response{Description: "Success, without content"}
+}
+
+// listParameters returns the query parameters supported by the list routes.
+func listParameters() []Parameter {
+	return []Parameter{
+		{Name: "limit", In: "query", Description: "Maximum number of resources to return", Schema: &Schema{Type: "integer", Format: "int32"}},
+		{Name: "continue", In: "query", Description: "Token of the next page, given by the previous page", Schema: &Schema{Type: "string"}},
+		{Name: "labelSelector", In: "query", Description: "Selector on the labels of the resources", Schema: &Schema{Type: "string"}},
+		{Name: "fieldSelector", In: "query", Description: "Selector on the fields of the resources", Schema: &Schema{Type: "string"}},
+		{Name: "sort_by", In: "query", Description: "Ordering of the resources", Schema: &Schema{Type: "string", Enum: []string{"name", "last_seen", "status", "timestamp"}}},
+		{Name: "order", In: "query", Description: "Direction of the ordering", Schema: &Schema{Type: "string", Enum: []string{"asc", "desc"}}},
+	}
+}
+
+// describeWatch adds the watch parameter to a list operation, whose resources
+// can then be streamed as server-sent events.
+func describeWatch(op *Operation) {
+	op.Parameters = append(op.Parameters, Parameter{
+		Name:        "watch",
+		In:          "query",
+		Description: "Stream the changes of the resources as server-sent events",
+		Schema:      &Schema{Type: "boolean"},
+	})
+	response := op.Responses["200"]
+	response.Content["text/event-stream"] = MediaType{Schema: &Schema{Type: "string"}}
+	op.Responses["200"] = response
+}
+
+// operationID derives a unique identifier of the operation from its method
+// and path, e.g. getCoreV2NamespacesNamespaceChecksId.
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "/api/"), "/") {
+		for _, word := range strings.FieldsFunc(segment, isSeparator) {
+			id += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return id
+}
+
+func isSeparator(r rune) bool {
+	return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
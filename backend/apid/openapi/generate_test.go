@@ -0,0 +1,86 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func testRouter() *mux.Router {
+	handler := func(http.ResponseWriter, *http.Request) {}
+	router := mux.NewRouter()
+	router.HandleFunc("/version", handler).Methods(http.MethodGet)
+	core := router.PathPrefix("/api/{group:core}/{version:v2}/").Subrouter()
+	core.HandleFunc("/namespaces/{namespace}/{resource:checks}", handler).Methods(http.MethodGet)
+	core.HandleFunc("/namespaces/{namespace}/{resource:checks}/{id}", handler).Methods(http.MethodPut)
+	core.HandleFunc("/namespaces/{namespace}/bulk/{resource:checks}", handler).Methods(http.MethodDelete)
+	core.HandleFunc("/namespaces/{namespace}/{resource:checks}/{id}/execute", handler).Methods(http.MethodPost)
+	return router
+}
+
+func TestNormalize(t *testing.T) {
+	path, params := normalize("/api/{group:core}/{version:v2}/namespaces/{namespace}/{resource:checks}/{id:.+}")
+	if got, want := path, "/api/core/v2/namespaces/{namespace}/checks/{id}"; got != want {
+		t.Errorf("bad path: got %q, want %q", got, want)
+	}
+	if len(params) != 2 || params[0] != "namespace" || params[1] != "id" {
+		t.Errorf("bad params: %v", params)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	doc, err := Generate(testRouter(), "1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Info.Version != "1.2.3" {
+		t.Errorf("bad version: %q", doc.Info.Version)
+	}
+	if _, ok := doc.Paths["/version"]; ok {
+		t.Error("expected routes outside of the core API not to be described")
+	}
+
+	list := doc.Paths["/api/core/v2/namespaces/{namespace}/checks"]["get"]
+	if list == nil {
+		t.Fatal("expected the list operation to be described")
+	}
+	if got, want := list.OperationID, "getCoreV2NamespacesNamespaceChecks"; got != want {
+		t.Errorf("bad operation id: got %q, want %q", got, want)
+	}
+	if got := list.Responses["200"].Content["application/json"].Schema; got.Type != "array" || got.Items.Ref != "#/components/schemas/core.v2.CheckConfigWrapper" {
+		t.Errorf("bad list response: %+v", got)
+	}
+	if len(list.Parameters) != 7 {
+		t.Errorf("expected the namespace and the list parameters, got %v", list.Parameters)
+	}
+
+	put := doc.Paths["/api/core/v2/namespaces/{namespace}/checks/{id}"]["put"]
+	if put == nil || put.RequestBody == nil {
+		t.Fatal("expected the put operation to have a request body")
+	}
+	if got := put.RequestBody.Content["application/json"].Schema.Ref; got != "#/components/schemas/core.v2.CheckConfigWrapper" {
+		t.Errorf("bad request body: %q", got)
+	}
+
+	bulk := doc.Paths["/api/core/v2/namespaces/{namespace}/bulk/checks"]["delete"]
+	if bulk == nil || bulk.Responses["200"].Content["application/json"].Schema.Items.Ref != "#/components/schemas/BulkResult" {
+		t.Errorf("bad bulk operation: %+v", bulk)
+	}
+
+	if execute := doc.Paths["/api/core/v2/namespaces/{namespace}/checks/{id}/execute"]["post"]; execute == nil {
+		t.Error("expected custom routes to be described")
+	}
+
+	wrapper := doc.Components.Schemas["core.v2.CheckConfigWrapper"]
+	if wrapper == nil {
+		t.Fatal("expected the wrapper schema")
+	}
+	if got := wrapper.Properties["type"].Enum; len(got) != 1 || got[0] != "CheckConfig" {
+		t.Errorf("bad wrapped type: %v", got)
+	}
+	check := doc.Components.Schemas["core.v2.CheckConfig"]
+	if check == nil || check.Properties["metadata"] == nil || check.Properties["interval"].Type != "integer" {
+		t.Errorf("bad check schema: %+v", check)
+	}
+}
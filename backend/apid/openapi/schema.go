@@ -0,0 +1,129 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemas derives the component schemas from Go types, following the rules of
+// encoding/json, which is used to serialize the resources.
+type schemas map[string]*Schema
+
+// schemaName returns the component name of a named type, qualified by the last
+// two elements of its package path, e.g. core.v2.CheckConfig.
+func schemaName(t reflect.Type) string {
+	pkg := strings.Split(t.PkgPath(), "/")
+	if len(pkg) > 2 {
+		pkg = pkg[len(pkg)-2:]
+	}
+	return strings.Join(append(pkg, t.Name()), ".")
+}
+
+// schemaOf returns the schema of the given type. Named struct types are added
+// to the components and referenced, so that recursive types are supported.
+func (s schemas) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+		name := schemaName(t)
+		if _, ok := s[name]; !ok {
+			// Reserve the name before walking the fields, so that a field
+			// referring to the type being described does not recurse.
+			s[name] = &Schema{}
+			*s[name] = *s.structSchema(t)
+		}
+		return ref(name)
+	}
+
+	// Interfaces, and any other kind, can hold any value
+	return &Schema{}
+}
+
+// structSchema describes the JSON object that a struct is serialized into.
+func (s schemas) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	s.addFields(schema, t)
+	return schema
+}
+
+// addFields adds the serialized fields of the struct to the properties of the
+// schema. The fields of embedded structs without a JSON name are promoted, as
+// done by encoding/json, unless shadowed by a field of the outer struct.
+func (s schemas) addFields(schema *Schema, t reflect.Type) {
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = s.schemaOf(field.Type)
+	}
+
+	for _, t := range embedded {
+		promoted := s.structSchema(t)
+		for name, property := range promoted.Properties {
+			if _, ok := schema.Properties[name]; !ok {
+				schema.Properties[name] = property
+			}
+		}
+	}
+}
@@ -27,3 +27,21 @@ func SelectorFromContext(ctx context.Context) *selector.Selector {
 	}
 	return val.(*selector.Selector)
 }
+
+// DryRunServer is the value of the dryRun query parameter of the writes that
+// are validated by the backend, without being persisted.
+const DryRunServer = "server"
+
+type dryRunContextKey struct{}
+
+// ContextWithDryRun returns a new context, marking the write of the request as
+// a dry run.
+func ContextWithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunContextKey{}, true)
+}
+
+// DryRunFromContext returns whether the write of the request is a dry run.
+func DryRunFromContext(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunContextKey{}).(bool)
+	return dryRun
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 
 	corev3 "github.com/sensu/core/v3"
@@ -35,6 +36,57 @@ func Resource[R corev3.Resource](r *http.Request) (R, error) {
 	return wrapper.Value.(R), nil
 }
 
+// NDJSONContentType is the content type of request bodies made of
+// newline-delimited JSON documents.
+const NDJSONContentType = "application/x-ndjson"
+
+// Resources decodes the request body, a JSON array of wrapped resources, into
+// a slice of the specified corev3.Resource type. When the content type of the
+// request is NDJSONContentType, the body is a stream of wrapped resources
+// instead.
+func Resources[R corev3.Resource](r *http.Request) ([]R, error) {
+	var raw []json.RawMessage
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == NDJSONContentType {
+		decoder := json.NewDecoder(r.Body)
+		for {
+			var b json.RawMessage
+			if err := decoder.Decode(&b); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("resource %d: %v", len(raw), err)
+			}
+			raw = append(raw, b)
+		}
+	} else {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(body, &raw); err != nil {
+			return nil, fmt.Errorf("request body must be a list of resources: %v", err)
+		}
+	}
+
+	payload := make([]R, 0, len(raw))
+	for i, b := range raw {
+		if err := validate[R](b); err != nil {
+			return nil, fmt.Errorf("resource %d: %v", i, err)
+		}
+		var wrapper types.Wrapper
+		if err := json.Unmarshal(b, &wrapper); err != nil {
+			return nil, fmt.Errorf("resource %d: %v", i, err)
+		}
+		resource, ok := wrapper.Value.(R)
+		if !ok {
+			var expected R
+			return nil, fmt.Errorf("resource %d: unexpected type described in the request body: expected %T, got %T", i, expected, wrapper.Value)
+		}
+		payload = append(payload, resource)
+	}
+
+	return payload, nil
+}
+
 func validate[R corev3.Resource](b []byte) error {
 	var w rawWrapper
 	if err := json.Unmarshal(b, &w); err != nil {
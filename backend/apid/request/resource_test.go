@@ -68,6 +68,51 @@ func TestResource(t *testing.T) {
 	}
 }
 
+func TestResources(t *testing.T) {
+	marshal := func(v interface{}) []byte {
+		b, _ := json.Marshal(v)
+		return b
+	}
+
+	_, err := Resources[*corev2.Entity](newRequest(marshal(types.WrapResource(corev2.FixtureEntity("test")))))
+	assertError(t, err)
+
+	_, err = Resources[*corev2.Entity](newRequest(marshal([]types.Wrapper{
+		types.WrapResource(corev2.FixtureEntity("test")),
+		types.WrapResource(corev2.FixtureHandler("test")),
+	})))
+	assertError(t, err)
+
+	actual, err := Resources[*corev2.Entity](newRequest(marshal([]types.Wrapper{
+		types.WrapResource(corev2.FixtureEntity("foo")),
+		types.WrapResource(corev2.FixtureEntity("bar")),
+	})))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := len(actual), 2; got != want {
+		t.Fatalf("bad number of resources: got %d, want %d", got, want)
+	}
+	if got, want := actual[1].Name, "bar"; got != want {
+		t.Errorf("bad resource name: got %q, want %q", got, want)
+	}
+
+	ndjson := append(marshal(types.WrapResource(corev2.FixtureEntity("foo"))), '\n')
+	ndjson = append(ndjson, marshal(types.WrapResource(corev2.FixtureEntity("bar")))...)
+	req := newRequest(ndjson)
+	req.Header.Set("Content-Type", NDJSONContentType)
+	actual, err = Resources[*corev2.Entity](req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := len(actual), 2; got != want {
+		t.Fatalf("bad number of resources: got %d, want %d", got, want)
+	}
+	if got, want := actual[1].Name, "bar"; got != want {
+		t.Errorf("bad resource name: got %q, want %q", got, want)
+	}
+}
+
 func newRequest(b []byte) *http.Request {
 	r, _ := http.NewRequest(http.MethodHead, "", bytes.NewBuffer(b))
 	return r
@@ -0,0 +1,159 @@
+package routers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/authentication/jwt"
+)
+
+// AgentDrainResource is the RBAC name of the draining of the agents connected
+// to the backend.
+const AgentDrainResource = "agent-drain"
+
+// AgentDrain asks agentd to progressively disconnect a percentage of its
+// agents, to drain the backend for maintenance or to rebalance the agents
+// across the backends.
+type AgentDrain struct {
+	// Percentage is the percentage of the connected agents to disconnect.
+	Percentage float64 `json:"percentage"`
+
+	// Period is the number of seconds over which the agents are disconnected.
+	// They are all disconnected at once when 0.
+	Period int `json:"period"`
+
+	// RetryAfter is the number of seconds the agents are asked to wait before
+	// reconnecting. The delay of each agent is jittered up to twice that.
+	RetryAfter int `json:"retry_after"`
+
+	// RefuseConnections keeps refusing the new agent connections after the
+	// agents are disconnected, until the drain is cancelled. The connections
+	// are always refused while the agents are being disconnected.
+	RefuseConnections bool `json:"refuse_connections"`
+}
+
+// Validate returns an error if the drain is invalid.
+func (d AgentDrain) Validate() error {
+	if d.Percentage <= 0 || d.Percentage > 100 {
+		return errors.New("percentage must be greater than 0 and at most 100")
+	}
+	if d.Period < 0 {
+		return errors.New("period must not be negative")
+	}
+	if d.RetryAfter < 0 {
+		return errors.New("retry_after must not be negative")
+	}
+	return nil
+}
+
+// AgentDrainStatus is the progress of the draining of the agents.
+type AgentDrainStatus struct {
+	// Draining is true while the agents are being disconnected.
+	Draining bool `json:"draining"`
+
+	// RefusingConnections is true while the new agent connections are refused.
+	RefusingConnections bool `json:"refusing_connections"`
+
+	// Connected is the number of agents connected to the backend.
+	Connected int `json:"connected"`
+
+	// Targeted is the number of agents to disconnect by the last drain.
+	Targeted int `json:"targeted"`
+
+	// Disconnected is the number of agents disconnected by the last drain.
+	Disconnected int `json:"disconnected"`
+}
+
+// AgentDrainer drains the agents connected to the backend.
+type AgentDrainer interface {
+	// Drain starts disconnecting the agents. It returns an error if a drain
+	// is already in progress.
+	Drain(AgentDrain) (AgentDrainStatus, error)
+
+	// Cancel stops disconnecting the agents, and accepts the new agent
+	// connections again.
+	Cancel() AgentDrainStatus
+
+	// Status returns the progress of the drain.
+	Status() AgentDrainStatus
+}
+
+// AgentDrainRouter handles requests for /agent-drain.
+type AgentDrainRouter struct {
+	drainer AgentDrainer
+}
+
+// NewAgentDrainRouter instantiates a new router draining the agents with the
+// given drainer.
+func NewAgentDrainRouter(drainer AgentDrainer) *AgentDrainRouter {
+	return &AgentDrainRouter{
+		drainer: drainer,
+	}
+}
+
+// Mount the AgentDrainRouter on the given parent Router, unless it has no
+// drainer.
+func (r *AgentDrainRouter) Mount(parent *mux.Router) {
+	if r.drainer == nil {
+		return
+	}
+	path := "/{resource:" + AgentDrainResource + "}"
+	parent.HandleFunc(path, r.get).Methods(http.MethodGet)
+	parent.HandleFunc(path, r.drain).Methods(http.MethodPut)
+	parent.HandleFunc(path, r.cancel).Methods(http.MethodDelete)
+}
+
+func (r *AgentDrainRouter) get(w http.ResponseWriter, req *http.Request) {
+	r.respond(w, r.drainer.Status())
+}
+
+func (r *AgentDrainRouter) drain(w http.ResponseWriter, req *http.Request) {
+	var drain AgentDrain
+	if err := json.NewDecoder(req.Body).Decode(&drain); err != nil {
+		WriteError(w, actions.NewError(actions.InvalidArgument, err))
+		return
+	}
+	if err := drain.Validate(); err != nil {
+		WriteError(w, actions.NewError(actions.InvalidArgument, err))
+		return
+	}
+
+	status, err := r.drainer.Drain(drain)
+	if err != nil {
+		WriteError(w, actions.NewError(actions.AlreadyExistsErr, err))
+		return
+	}
+	entry := logger.WithField("percentage", drain.Percentage).WithField("targeted", status.Targeted)
+	if claims := jwt.GetClaimsFromContext(req.Context()); claims != nil {
+		entry = entry.WithField("user", claims.Subject)
+	}
+	entry.Warn("draining agents")
+
+	r.respond(w, status)
+}
+
+func (r *AgentDrainRouter) cancel(w http.ResponseWriter, req *http.Request) {
+	status := r.drainer.Cancel()
+	entry := logger.WithField("disconnected", status.Disconnected)
+	if claims := jwt.GetClaimsFromContext(req.Context()); claims != nil {
+		entry = entry.WithField("user", claims.Subject)
+	}
+	entry.Warn("agent drain cancelled")
+
+	r.respond(w, status)
+}
+
+func (r *AgentDrainRouter) respond(w http.ResponseWriter, status AgentDrainStatus) {
+	b, err := json.Marshal(status)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(b); err != nil {
+		logger.WithError(err).Error("failed to write response")
+	}
+}
@@ -0,0 +1,68 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAgentDrainer struct {
+	drain    AgentDrain
+	draining bool
+}
+
+func (m *mockAgentDrainer) Drain(drain AgentDrain) (AgentDrainStatus, error) {
+	if m.draining {
+		return m.Status(), errors.New("already draining")
+	}
+	m.drain = drain
+	m.draining = true
+	return m.Status(), nil
+}
+
+func (m *mockAgentDrainer) Cancel() AgentDrainStatus {
+	m.draining = false
+	return m.Status()
+}
+
+func (m *mockAgentDrainer) Status() AgentDrainStatus {
+	return AgentDrainStatus{Draining: m.draining, RefusingConnections: m.draining, Connected: 10, Targeted: 5}
+}
+
+func TestAgentDrainRouter(t *testing.T) {
+	drainer := &mockAgentDrainer{}
+	parentRouter := mux.NewRouter().PathPrefix("/api/core/v2").Subrouter()
+	NewAgentDrainRouter(drainer).Mount(parentRouter)
+
+	request := func(method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/core/v2/agent-drain", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		parentRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"draining":false,"refusing_connections":false,"connected":10,"targeted":5,"disconnected":0}`, w.Body.String())
+
+	w = request(http.MethodPut, `{"percentage":150}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.False(t, drainer.draining)
+
+	w = request(http.MethodPut, `{"percentage":50,"period":60,"retry_after":30}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"draining":true`)
+	assert.Equal(t, AgentDrain{Percentage: 50, Period: 60, RetryAfter: 30}, drainer.drain)
+
+	w = request(http.MethodPut, `{"percentage":50}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = request(http.MethodDelete, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, drainer.draining)
+}
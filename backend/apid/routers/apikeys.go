@@ -7,19 +7,21 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"path"
 	"strings"
 	"time"
 
 	"github.com/echlebek/pet"
-	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/apid/handlers"
 	"github.com/sensu/sensu-go/backend/apid/request"
-	"github.com/sensu/sensu-go/backend/authentication/bcrypt"
+	"github.com/sensu/sensu-go/backend/authentication/apikey"
 	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/store/patch"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 	"github.com/sensu/core/v3/types"
 )
@@ -44,23 +46,30 @@ func (r *APIKeysRouter) Mount(parent *mux.Router) {
 	}
 
 	handlers := handlers.NewHandlers[*corev2.APIKey](r.store)
+	// The patches can't change the last use and the rotations of the keys
+	handlers.Patcher = func(patcher patch.Patcher) patch.Patcher {
+		return &apikey.ClientPatch{Patcher: patcher}
+	}
 
 	routes.Del(handlers.DeleteResource)
 	routes.Get(handlers.GetResource)
 	routes.List(handlers.ListResources, corev3.APIKeyFields)
 	parent.HandleFunc(routes.PathPrefix, r.create).Methods(http.MethodPost, http.MethodPut)
 	routes.Patch(handlers.PatchResource)
+
+	// Custom
+	parent.HandleFunc(path.Join(routes.PathPrefix, "{id}/{subresource:rotate}"), r.rotate).Methods(http.MethodPut)
 }
 
 func (r *APIKeysRouter) create(w http.ResponseWriter, req *http.Request) {
-	apikey, err := request.Resource[*corev2.APIKey](req)
+	key, err := request.Resource[*corev2.APIKey](req)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	// validate that the user exists
-	user := &corev2.User{Username: apikey.Username}
+	user := &corev2.User{Username: key.Username}
 	storeReq := storev2.NewResourceRequestFromResource(user)
 	if _, err := r.store.GetConfigStore().Get(req.Context(), storeReq); err != nil {
 		// validate that the user the API key pertains to exists
@@ -73,32 +82,48 @@ func (r *APIKeysRouter) create(w http.ResponseWriter, req *http.Request) {
 		}
 	}
 
-	response := corev2.APIKeyResponse{}
-	if len(bytes.TrimSpace(apikey.Hash)) == 0 {
-		// If Hash is not specified by the client, we generate a new one for them,
-		// and return the secret key in the response body. Otherwise, {} is returned.
-		secretKey, err := uuid.NewRandom()
-		if err != nil {
+	// The last use and the rotations of the keys are only recorded by the
+	// backend, and kept when the keys are replaced
+	stored := &corev2.APIKey{}
+	if req.Method == http.MethodPut && key.Name != "" {
+		existing, err := storev2.Of[*corev2.APIKey](r.store).Get(req.Context(), storev2.ID{Name: key.Name})
+		if err == nil {
+			stored = existing
+		} else if _, ok := err.(*store.ErrNotFound); !ok {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		hash, err := bcrypt.HashPassword(secretKey.String())
+	}
+	apikey.KeepServerManagedAnnotations(key, stored)
+	if expiresAt, err := apikey.ExpiresAt(key); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	} else if !expiresAt.IsZero() && !expiresAt.After(time.Now()) {
+		http.Error(w, "the api key expiration must be in the future", http.StatusBadRequest)
+		return
+	}
+
+	response := corev2.APIKeyResponse{}
+	if len(bytes.TrimSpace(key.Hash)) == 0 {
+		// If Hash is not specified by the client, we generate a new one for them,
+		// and return the secret key in the response body. Otherwise, {} is returned.
+		secret, hash, err := apikey.NewSecret()
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		apikey.Hash = []byte(hash)
-		response.Key = secretKey.String()
+		key.Hash = hash
+		response.Key = secret
 	}
-	apikey.CreatedAt = time.Now().Unix()
-	if strings.TrimSpace(apikey.Name) == "" {
+	key.CreatedAt = time.Now().Unix()
+	if strings.TrimSpace(key.Name) == "" {
 		// If the API key is not named, generate a pet name for the API key.
-		apikey.Name = pet.Generate(3, "")
+		key.Name = pet.Generate(3, "")
 	}
 
 MARSHAL:
 
-	newBytes, err := json.Marshal(types.WrapResource(apikey))
+	newBytes, err := json.Marshal(types.WrapResource(key))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -117,7 +142,7 @@ MARSHAL:
 		if actionErr, ok := err.(actions.Error); ok {
 			// small chance of pet name collision, this should take care of it
 			if actionErr.Code == actions.AlreadyExistsErr {
-				apikey.Name = pet.Generate(3, "")
+				key.Name = pet.Generate(3, "")
 				goto MARSHAL
 			}
 		}
@@ -125,12 +150,53 @@ MARSHAL:
 		return
 	}
 
-	response.Name = apikey.Name
+	response.Name = key.Name
 
 	// set the relative location header
-	w.Header().Set("Location", fmt.Sprintf("%s/%s", req.URL.String(), apikey.Name))
+	w.Header().Set("Location", fmt.Sprintf("%s/%s", req.URL.String(), key.Name))
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		logger.Error(err)
 	}
 }
+
+// rotate replaces the secret of an API key, and responds with the new secret.
+// The previous secret is still accepted for the grace period given by the
+// grace_period parameter, which is one hour by default.
+func (r *APIKeysRouter) rotate(w http.ResponseWriter, req *http.Request) {
+	name, err := url.PathUnescape(mux.Vars(req)["id"])
+	if err != nil {
+		WriteError(w, actions.NewError(actions.InvalidArgument, err))
+		return
+	}
+	gracePeriod := apikey.DefaultRotationGracePeriod
+	if value := req.URL.Query().Get("grace_period"); value != "" {
+		gracePeriod, err = time.ParseDuration(value)
+		if err != nil || gracePeriod < 0 {
+			WriteError(w, actions.NewErrorf(actions.InvalidArgument, "invalid grace period: %q", value))
+			return
+		}
+	}
+
+	secret, hash, err := apikey.NewSecret()
+	if err != nil {
+		WriteError(w, actions.NewError(actions.InternalErr, err))
+		return
+	}
+	rotation := &apikey.Rotation{Hash: hash, GracePeriod: gracePeriod, Now: time.Now()}
+	if err := storev2.Of[*corev2.APIKey](r.store).Patch(req.Context(), storev2.ID{Name: name}, rotation); err != nil {
+		switch err := err.(type) {
+		case *store.ErrNotFound:
+			WriteError(w, actions.NewError(actions.NotFound, err))
+		default:
+			WriteError(w, actions.NewError(actions.InternalErr, err))
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(corev2.APIKeyResponse{Name: name, Key: secret}); err != nil {
+		logger.Error(err)
+	}
+}
@@ -6,15 +6,20 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication/apikey"
+	"github.com/sensu/sensu-go/backend/authentication/bcrypt"
 	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/store/patch"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 	"github.com/sensu/sensu-go/testing/mockstore"
 	"github.com/sensu/core/v3/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAPIKeysRouter(t *testing.T) {
@@ -103,3 +108,126 @@ func TestPostAPIKeyInvalidUser(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 }
+
+func TestPostAPIKeyExpired(t *testing.T) {
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*corev2.User]{Value: corev2.FixtureUser("admin")}, nil)
+	router := NewAPIKeysRouter(s)
+
+	fixture := corev2.FixtureAPIKey("226f9e06-9d54-45c6-a9f6-4206bfa7ccf6", "admin")
+	apikey.SetExpiresAt(fixture, time.Now().Add(-time.Hour))
+	payload, err := json.Marshal(types.WrapResource(fixture))
+	require.NoError(t, err)
+	req, _ := http.NewRequest(http.MethodPost, "/apikeys", bytes.NewReader(payload))
+	res := processRequest(router, req)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	cs.AssertNotCalled(t, "CreateIfNotExists", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRotateAPIKey(t *testing.T) {
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	hash, err := bcrypt.HashPassword("old")
+	require.NoError(t, err)
+	key := corev2.FixtureAPIKey("mykey", "admin")
+	key.Hash = []byte(hash)
+	var rotated *corev2.APIKey
+	cs.On("Patch", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		document, err := json.Marshal(key)
+		require.NoError(t, err)
+		document, err = args.Get(2).(*apikey.Rotation).Patch(document)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(document, &rotated))
+	}).Return(nil)
+	router := NewAPIKeysRouter(s)
+
+	req, _ := http.NewRequest(http.MethodPut, "/apikeys/mykey/rotate?grace_period=10m", nil)
+	res := processRequest(router, req)
+	require.Equal(t, http.StatusOK, res.Code)
+
+	var response corev2.APIKeyResponse
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&response))
+	assert.Equal(t, "mykey", response.Name)
+	now := time.Now()
+	assert.True(t, apikey.Matches(rotated, response.Key, now))
+	assert.True(t, apikey.Matches(rotated, "old", now))
+	assert.False(t, apikey.Matches(rotated, "old", now.Add(11*time.Minute)))
+}
+
+func TestRotateAPIKeyErrors(t *testing.T) {
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	cs.On("Patch", mock.Anything, mock.Anything, mock.Anything).Return(&store.ErrNotFound{})
+	router := NewAPIKeysRouter(s)
+
+	req, _ := http.NewRequest(http.MethodPut, "/apikeys/mykey/rotate?grace_period=-1h", nil)
+	res := processRequest(router, req)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+
+	req, _ = http.NewRequest(http.MethodPut, "/apikeys/mykey/rotate", nil)
+	res = processRequest(router, req)
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
+
+func TestPutAPIKeyKeepsServerManagedAnnotations(t *testing.T) {
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	stored := corev2.FixtureAPIKey("mykey", "admin")
+	apikey.Rotate(stored, []byte("hash"), time.Hour, time.Now())
+	isKey := func(req storev2.ResourceRequest) bool { return req.Type == "APIKey" }
+	cs.On("Get", mock.Anything, mock.MatchedBy(isKey)).Return(mockstore.Wrapper[*corev2.APIKey]{Value: stored}, nil)
+	cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*corev2.User]{Value: corev2.FixtureUser("admin")}, nil)
+	var replaced *corev2.APIKey
+	cs.On("CreateOrUpdate", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		resource, err := args.Get(2).(storev2.Wrapper).Unwrap()
+		require.NoError(t, err)
+		replaced = resource.(*corev2.APIKey)
+	}).Return(nil)
+	router := NewAPIKeysRouter(s)
+
+	key := corev2.FixtureAPIKey("mykey", "admin")
+	key.Annotations = map[string]string{
+		"foo":                       "bar",
+		apikey.LastUsedAtAnnotation: "2000-01-01T00:00:00Z",
+	}
+	payload, err := json.Marshal(types.WrapResource(key))
+	require.NoError(t, err)
+	req, _ := http.NewRequest(http.MethodPut, "/apikeys", bytes.NewReader(payload))
+	res := processRequest(router, req)
+	require.Equal(t, http.StatusCreated, res.Code, res.Body.String())
+
+	require.NotNil(t, replaced)
+	assert.Equal(t, "bar", replaced.Annotations["foo"])
+	assert.NotContains(t, replaced.Annotations, apikey.LastUsedAtAnnotation)
+	assert.Equal(t, stored.Annotations[apikey.PreviousHashAnnotation], replaced.Annotations[apikey.PreviousHashAnnotation])
+}
+
+func TestPatchAPIKeyKeepsServerManagedAnnotations(t *testing.T) {
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	stored := corev2.FixtureAPIKey("mykey", "admin")
+	apikey.Rotate(stored, []byte("hash"), time.Hour, time.Now())
+	var patched corev2.APIKey
+	cs.On("Patch", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		document, err := json.Marshal(stored)
+		require.NoError(t, err)
+		document, err = args.Get(2).(patch.Patcher).Patch(document)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(document, &patched))
+	}).Return(nil)
+	router := NewAPIKeysRouter(s)
+
+	body := []byte(`{"metadata":{"annotations":{"sensu.io/previous_hash":null,"foo":"bar"}}}`)
+	req, _ := http.NewRequest(http.MethodPatch, "/apikeys/mykey", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	res := processRequest(router, req)
+	require.Equal(t, http.StatusNoContent, res.Code, res.Body.String())
+	assert.Equal(t, "bar", patched.Annotations["foo"])
+	assert.Equal(t, stored.Annotations[apikey.PreviousHashAnnotation], patched.Annotations[apikey.PreviousHashAnnotation])
+}
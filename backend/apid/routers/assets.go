@@ -30,10 +30,15 @@ func (r *AssetsRouter) Mount(parent *mux.Router) {
 	handlers := handlers.NewHandlers[*corev2.Asset](r.store)
 
 	routes.Get(handlers.GetResource)
+	routes.Watch(handlers.WatchResources)
 	routes.List(handlers.ListResources, corev3.AssetFields)
+	routes.WatchAllNamespaces(handlers.WatchResources, "/{resource:assets}")
 	routes.ListAllNamespaces(handlers.ListResources, "/{resource:assets}", corev3.AssetFields)
 	routes.Patch(handlers.PatchResource)
-	routes.Post(handlers.CreateResource)
-	routes.Put(handlers.CreateOrUpdateResource)
+	dryRun(routes.Post(handlers.CreateResource))
+	dryRun(routes.Put(handlers.CreateOrUpdateResource))
+	routes.Bulk(handlers.BulkCreateResources, handlers.BulkCreateOrUpdateResources, handlers.BulkDeleteResources)
+	routes.Count(handlers.CountResources)
+	routes.History(handlers.ListResourceVersions, handlers.GetResourceVersion, handlers.DiffResourceVersion, handlers.RollbackResource)
 	routes.Del(handlers.DeleteResource)
 }
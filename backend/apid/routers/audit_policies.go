@@ -0,0 +1,38 @@
+package routers
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/backend/audit"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// AuditPoliciesRouter handles requests for AuditPolicies.
+type AuditPoliciesRouter struct {
+	store storev2.Interface
+}
+
+// NewAuditPoliciesRouter instantiates a new router for AuditPolicies.
+func NewAuditPoliciesRouter(store storev2.Interface) *AuditPoliciesRouter {
+	return &AuditPoliciesRouter{
+		store: store,
+	}
+}
+
+// Mount the AuditPoliciesRouter on the given parent Router
+func (r *AuditPoliciesRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/{resource:auditpolicies}",
+	}
+
+	handlers := handlers.NewHandlers[*audit.AuditPolicy](r.store)
+
+	routes.Del(handlers.DeleteResource)
+	routes.Get(handlers.GetResource)
+	routes.Watch(handlers.WatchResources)
+	routes.List(handlers.ListResources, audit.AuditPolicyFields)
+	routes.Patch(handlers.PatchResource)
+	dryRun(routes.Post(handlers.CreateResource))
+	dryRun(routes.Put(handlers.CreateOrUpdateResource))
+}
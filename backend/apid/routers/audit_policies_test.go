@@ -0,0 +1,39 @@
+package routers
+
+import (
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/audit"
+	"github.com/sensu/sensu-go/testing/mockstore"
+)
+
+func TestAuditPoliciesRouter(t *testing.T) {
+	// Setup the router
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	router := NewAuditPoliciesRouter(s)
+	parentRouter := mux.NewRouter().PathPrefix("/api/audit/v1").Subrouter()
+	router.Mount(parentRouter)
+
+	empty := &audit.AuditPolicy{Metadata: &corev2.ObjectMeta{}}
+	fixture := &audit.AuditPolicy{
+		Metadata: corev2.NewObjectMetaP("foo", ""),
+		Rules: []corev2.Rule{{
+			Verbs:     []string{"create", "update", "delete"},
+			Resources: []string{corev2.ResourceAll},
+		}},
+	}
+
+	tests := []routerTestCase{}
+	tests = append(tests, getTestCases[*audit.AuditPolicy](fixture)...)
+	tests = append(tests, listTestCases[*audit.AuditPolicy](empty)...)
+	tests = append(tests, createTestCases(fixture)...)
+	tests = append(tests, updateTestCases(fixture)...)
+	tests = append(tests, deleteTestCases(fixture)...)
+	for _, tt := range tests {
+		run(t, tt, parentRouter, s)
+	}
+}
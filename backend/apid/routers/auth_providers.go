@@ -0,0 +1,39 @@
+package routers
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/backend/authentication/providers/oidc"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// AuthProvidersRouter handles requests for the OIDC authentication providers.
+type AuthProvidersRouter struct {
+	store storev2.Interface
+}
+
+// NewAuthProvidersRouter instantiates a new router for the OIDC
+// authentication providers.
+func NewAuthProvidersRouter(store storev2.Interface) *AuthProvidersRouter {
+	return &AuthProvidersRouter{
+		store: store,
+	}
+}
+
+// Mount the AuthProvidersRouter on the given parent Router
+func (r *AuthProvidersRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/{resource:authproviders}",
+	}
+
+	handlers := handlers.NewHandlers[*oidc.Provider](r.store)
+
+	routes.Del(handlers.DeleteResource)
+	routes.Get(handlers.GetResource)
+	routes.Watch(handlers.WatchResources)
+	routes.List(handlers.ListResources, oidc.AuthProviderFields)
+	routes.Patch(handlers.PatchResource)
+	dryRun(routes.Post(handlers.CreateResource))
+	dryRun(routes.Put(handlers.CreateOrUpdateResource))
+}
@@ -0,0 +1,39 @@
+package routers
+
+import (
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication/providers/oidc"
+	"github.com/sensu/sensu-go/testing/mockstore"
+)
+
+func TestAuthProvidersRouter(t *testing.T) {
+	// Setup the router
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	router := NewAuthProvidersRouter(s)
+	parentRouter := mux.NewRouter().PathPrefix("/api/authentication/v2").Subrouter()
+	router.Mount(parentRouter)
+
+	empty := &oidc.Provider{Metadata: &corev2.ObjectMeta{}}
+	fixture := &oidc.Provider{
+		Metadata:     corev2.NewObjectMetaP("foo", ""),
+		Server:       "https://example.okta.com",
+		ClientID:     "sensu",
+		ClientSecret: "secret",
+		RedirectURI:  "https://sensu.example.com/api/authentication/v2/oidc/foo/callback",
+	}
+
+	tests := []routerTestCase{}
+	tests = append(tests, getTestCases[*oidc.Provider](fixture)...)
+	tests = append(tests, listTestCases[*oidc.Provider](empty)...)
+	tests = append(tests, createTestCases(fixture)...)
+	tests = append(tests, updateTestCases(fixture)...)
+	tests = append(tests, deleteTestCases(fixture)...)
+	for _, tt := range tests {
+		run(t, tt, parentRouter, s)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/backend/authentication/mfa"
 
 	"github.com/gorilla/mux"
 
@@ -50,9 +51,18 @@ func (a *AuthenticationRouter) login(w http.ResponseWriter, r *http.Request) {
 	// issuer URL
 	ctx := context.WithValue(r.Context(), jwt.IssuerURLKey, issuerURL(r))
 
+	// Pass the one-time password of the users enrolled in the multi-factor
+	// authentication
+	ctx = mfa.ContextWithCode(ctx, r.Header.Get(OTPHeader))
+
 	client := a.authenticator
 	tokens, err := client.CreateAccessToken(ctx, username, password)
 	if err != nil {
+		switch err {
+		case mfa.ErrRequired, mfa.ErrInvalidCode, mfa.ErrEnrollmentRequired, mfa.ErrLockedOut:
+			writeMFAError(w, username, err)
+			return
+		}
 		if err == corev2.ErrUnauthorized {
 			logger.WithError(err).WithField("user", username).
 				Error("invalid username and/or password")
@@ -11,6 +11,7 @@ import (
 
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication/mfa"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -86,6 +87,26 @@ func TestLoginSuccessful(t *testing.T) {
 	assert.NotEmpty(t, response.Refresh)
 }
 
+func TestLoginMFA(t *testing.T) {
+	auth := new(mockAuthenticator)
+	auth.On("CreateAccessToken", mock.Anything, "foo", "P@ssw0rd!").Return((*corev2.Tokens)(nil), mfa.ErrRequired).Once()
+	auth.On("CreateAccessToken", mock.MatchedBy(func(ctx context.Context) bool {
+		return mfa.CodeFromContext(ctx) == "123456"
+	}), "foo", "P@ssw0rd!").Return(&corev2.Tokens{Access: "abcd"}, nil).Once()
+	router := NewAuthenticationRouter(auth)
+
+	req, _ := http.NewRequest(http.MethodGet, "/auth", nil)
+	req.SetBasicAuth("foo", "P@ssw0rd!")
+
+	res := processRequest(router, req)
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+	assert.Equal(t, "required", res.Header().Get(MFAHeader))
+
+	req.Header.Set(OTPHeader, "123456")
+	res = processRequest(router, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+}
+
 func TestTestNoCredentials(t *testing.T) {
 	auth := new(mockAuthenticator)
 	auth.On("TestCreds", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("not authenticated"))
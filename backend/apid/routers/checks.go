@@ -50,11 +50,16 @@ func (r *ChecksRouter) Mount(parent *mux.Router) {
 
 	routes.Del(handlers.DeleteResource)
 	routes.Get(handlers.GetResource)
+	routes.Watch(handlers.WatchResources)
 	routes.List(handlers.ListResources, corev3.CheckConfigFields)
+	routes.WatchAllNamespaces(handlers.WatchResources, "/{resource:checks}")
 	routes.ListAllNamespaces(handlers.ListResources, "/{resource:checks}", corev3.CheckConfigFields)
 	routes.Patch(handlers.PatchResource)
-	routes.Post(handlers.CreateResource)
-	routes.Put(handlers.CreateOrUpdateResource)
+	dryRun(routes.Post(handlers.CreateResource))
+	dryRun(routes.Put(handlers.CreateOrUpdateResource))
+	routes.Bulk(handlers.BulkCreateResources, handlers.BulkCreateOrUpdateResources, handlers.BulkDeleteResources)
+	routes.Count(handlers.CountResources)
+	routes.History(handlers.ListResourceVersions, handlers.GetResourceVersion, handlers.DiffResourceVersion, handlers.RollbackResource)
 
 	// Custom
 	routes.Path("{id}/hooks/{type}", r.addCheckHook).Methods(http.MethodPut)
@@ -31,8 +31,12 @@ func (r *ClusterRolesRouter) Mount(parent *mux.Router) {
 
 	routes.Del(handlers.DeleteResource)
 	routes.Get(handlers.GetResource)
+	routes.Watch(handlers.WatchResources)
 	routes.List(handlers.ListResources, corev3.ClusterRoleFields)
 	routes.Patch(handlers.PatchResource)
-	routes.Post(handlers.CreateResource)
-	routes.Put(handlers.CreateOrUpdateResource)
+	dryRun(routes.Post(handlers.CreateResource))
+	dryRun(routes.Put(handlers.CreateOrUpdateResource))
+	routes.Bulk(handlers.BulkCreateResources, handlers.BulkCreateOrUpdateResources, handlers.BulkDeleteResources)
+	routes.Count(handlers.CountResources)
+	routes.History(handlers.ListResourceVersions, handlers.GetResourceVersion, handlers.DiffResourceVersion, handlers.RollbackResource)
 }
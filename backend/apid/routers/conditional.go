@@ -0,0 +1,98 @@
+package routers
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// ModifiedFunc returns the time of the last modification of a resource, or the
+// zero time if it is unknown.
+type ModifiedFunc func(resource corev3.Resource) time.Time
+
+// ResourceModified returns the time of the last modification of a resource, as
+// recorded by the store in its labels.
+func ResourceModified(resource corev3.Resource) time.Time {
+	var modified time.Time
+	if meta := resource.GetMetadata(); meta != nil {
+		_ = modified.UnmarshalText([]byte(meta.Labels[store.SensuUpdatedAtKey]))
+	}
+	return modified
+}
+
+// EntityModified returns the time of the last modification of an entity,
+// which is modified by its keepalives as well.
+func EntityModified(resource corev3.Resource) time.Time {
+	modified := ResourceModified(resource)
+	if entity, ok := resource.(*corev2.Entity); ok {
+		if lastSeen := time.Unix(entity.LastSeen, 0); lastSeen.After(modified) {
+			return lastSeen
+		}
+	}
+	return modified
+}
+
+// EventModified returns the time of the last modification of an event, which
+// is the time of its last check result.
+func EventModified(resource corev3.Resource) time.Time {
+	modified := ResourceModified(resource)
+	if event, ok := resource.(*corev2.Event); ok {
+		if timestamp := time.Unix(event.Timestamp, 0); timestamp.After(modified) {
+			return timestamp
+		}
+	}
+	return modified
+}
+
+// listValidators returns the Last-Modified time and the weak ETag of a list of
+// resources. Unlike the Last-Modified time, the ETag also changes when a
+// resource is removed from the list.
+func listValidators(resources []corev3.Resource, modifiedFunc ModifiedFunc) (time.Time, string) {
+	var lastModified time.Time
+	hash := fnv.New64a()
+	for _, resource := range resources {
+		modified := modifiedFunc(resource)
+		if modified.After(lastModified) {
+			lastModified = modified
+		}
+		var namespace, name string
+		if meta := resource.GetMetadata(); meta != nil {
+			namespace, name = meta.Namespace, meta.Name
+		}
+		fmt.Fprintf(hash, "%s/%s/%d\n", namespace, name, modified.UnixNano())
+	}
+	return lastModified.Truncate(time.Second), fmt.Sprintf(`W/"%x"`, hash.Sum64())
+}
+
+// notModified sets the validators of the list in the response headers, and
+// returns whether the request is conditional and the list was not modified
+// since. As specified by RFC 7232, If-Modified-Since is ignored when the
+// request has an If-None-Match header.
+func notModified(w http.ResponseWriter, r *http.Request, lastModified time.Time, etag string) bool {
+	w.Header().Set("Etag", etag)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, value := range strings.Split(ifNoneMatch, ",") {
+			value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+			if value == "*" || value == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	return err == nil && !lastModified.After(since)
+}
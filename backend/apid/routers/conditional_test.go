@@ -0,0 +1,65 @@
+package routers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapConditionalList(t *testing.T) {
+	modified := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	events := []corev3.Resource{
+		&corev2.Event{ObjectMeta: corev2.NewObjectMeta("", "default"), Timestamp: modified.Add(-time.Hour).Unix()},
+		&corev2.Event{ObjectMeta: corev2.NewObjectMeta("", "default"), Timestamp: modified.Unix()},
+	}
+	list := func(ctx context.Context, pred *store.SelectionPredicate) ([]corev3.Resource, error) {
+		return events, nil
+	}
+	handler := WrapConditionalList(list, corev3.EventFields, EventModified)
+
+	request := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/events", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, modified.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+	etag := w.Header().Get("Etag")
+	assert.NotEmpty(t, etag)
+
+	assert.Equal(t, http.StatusNotModified, request("If-Modified-Since", modified.Format(http.TimeFormat)).Code)
+	assert.Equal(t, http.StatusOK, request("If-Modified-Since", modified.Add(-time.Second).Format(http.TimeFormat)).Code)
+	assert.Equal(t, http.StatusNotModified, request("If-None-Match", etag).Code)
+
+	// Removing an event from the list changes its ETag, but not its
+	// Last-Modified time
+	events = events[1:]
+	w = request("If-None-Match", etag)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, modified.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+	assert.NotEqual(t, etag, w.Header().Get("Etag"))
+}
+
+func TestEntityModified(t *testing.T) {
+	updated := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	entity := corev2.FixtureEntity("entity")
+	entity.Labels = map[string]string{store.SensuUpdatedAtKey: updated.Format(time.RFC3339)}
+	entity.LastSeen = updated.Add(-time.Minute).Unix()
+	assert.True(t, updated.Equal(EntityModified(entity)))
+
+	entity.LastSeen = updated.Add(time.Minute).Unix()
+	assert.True(t, updated.Add(time.Minute).Equal(EntityModified(entity)))
+}
@@ -2,9 +2,11 @@ package routers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/url"
+	"path"
 
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/core/v2"
@@ -32,6 +34,9 @@ type EntityController interface {
 	List(ctx context.Context, pred *store.SelectionPredicate) ([]corev3.Resource, error)
 	Create(ctx context.Context, entity corev2.Entity) error
 	CreateOrReplace(ctx context.Context, entity corev2.Entity) error
+	BulkCreateOrReplace(ctx context.Context, entities []*corev2.Entity) []actions.BulkEntityResult
+	Undelete(ctx context.Context, id string) error
+	History(ctx context.Context, id string) ([]*storev2.EntityStateSnapshot, error)
 }
 
 // NewEntitiesRouter instantiates new router for controlling entities resources
@@ -61,11 +66,19 @@ func (r *EntitiesRouter) Mount(parent *mux.Router) {
 
 	routes.Del(deleter.Delete)
 	routes.Get(r.find)
-	routes.List(r.controller.List, corev3.EntityFields)
-	routes.ListAllNamespaces(r.controller.List, "/{resource:entities}", corev3.EntityFields)
+	// The changes of the entities are those of their configuration
+	routes.Watch(ecHandlers.WatchResources)
+	routes.ListConditional(r.controller.List, corev3.EntityFields, EntityModified)
+	routes.WatchAllNamespaces(ecHandlers.WatchResources, "/{resource:entities}")
+	routes.ListAllNamespacesConditional(r.controller.List, "/{resource:entities}", corev3.EntityFields, EntityModified)
 	routes.Patch(ecHandlers.PatchResource)
 	routes.Post(r.create)
 	routes.Put(r.createOrReplace)
+	routes.Count(ecHandlers.CountResources)
+
+	parent.HandleFunc(path.Join(routes.PathPrefix, "bulk"), r.bulkCreateOrReplace).Methods(http.MethodPost)
+	parent.HandleFunc(path.Join(routes.PathPrefix, "{id}", "undelete"), r.undelete).Methods(http.MethodPost)
+	parent.HandleFunc(path.Join(routes.PathPrefix, "{id}", "history"), r.history).Methods(http.MethodGet)
 }
 
 func responseWrap(args ...interface{}) (handlers.HandlerResponse, error) {
@@ -110,3 +123,64 @@ func (r *EntitiesRouter) createOrReplace(req *http.Request) (handlers.HandlerRes
 
 	return responseWrap(entity, r.controller.CreateOrReplace(req.Context(), *entity))
 }
+
+// bulkCreateOrReplace creates or replaces every entity of the request body, a
+// list of wrapped entities, and responds with the result of each operation.
+func (r *EntitiesRouter) bulkCreateOrReplace(w http.ResponseWriter, req *http.Request) {
+	entities, err := request.Resources[*corev2.Entity](req)
+	if err != nil {
+		WriteError(w, actions.NewError(actions.InvalidArgument, err))
+		return
+	}
+
+	results := r.controller.BulkCreateOrReplace(req.Context(), entities)
+
+	b, err := json.Marshal(results)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(b); err != nil {
+		logger.WithError(err).Error("failed to write response")
+	}
+}
+
+// history responds with the snapshots of the system of an entity, most recent
+// first.
+func (r *EntitiesRouter) history(w http.ResponseWriter, req *http.Request) {
+	id, err := url.PathUnescape(mux.Vars(req)["id"])
+	if err != nil {
+		WriteError(w, actions.NewError(actions.InvalidArgument, err))
+		return
+	}
+	snapshots, err := r.controller.History(req.Context(), id)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	b, err := json.Marshal(snapshots)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(b); err != nil {
+		logger.WithError(err).Error("failed to write response")
+	}
+}
+
+// undelete restores an entity deleted recently, along with its state.
+func (r *EntitiesRouter) undelete(w http.ResponseWriter, req *http.Request) {
+	id, err := url.PathUnescape(mux.Vars(req)["id"])
+	if err != nil {
+		WriteError(w, actions.NewError(actions.InvalidArgument, err))
+		return
+	}
+	if err := r.controller.Undelete(req.Context(), id); err != nil {
+		WriteError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
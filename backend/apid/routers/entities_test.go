@@ -1,13 +1,22 @@
 package routers
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/apid/middlewares"
 	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 	"github.com/sensu/sensu-go/testing/mockstore"
 	"github.com/stretchr/testify/mock"
 )
@@ -36,6 +45,170 @@ func (m *mockEntitiesController) CreateOrReplace(ctx context.Context, entity cor
 	return args.Error(0)
 }
 
+func (m *mockEntitiesController) BulkCreateOrReplace(ctx context.Context, entities []*corev2.Entity) []actions.BulkEntityResult {
+	args := m.Called(ctx, entities)
+	return args.Get(0).([]actions.BulkEntityResult)
+}
+
+func (m *mockEntitiesController) Undelete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockEntitiesController) History(ctx context.Context, id string) ([]*storev2.EntityStateSnapshot, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]*storev2.EntityStateSnapshot), args.Error(1)
+}
+
+func TestEntitiesRouterUndelete(t *testing.T) {
+	controller := new(mockEntitiesController)
+	controller.On("Undelete", mock.Anything, "foo").Return(nil)
+	controller.On("Undelete", mock.Anything, "bar").Return(actions.NewErrorf(actions.NotFound))
+	s := new(mockstore.V2MockStore)
+	s.On("GetEntityStore").Return(new(mockstore.MockStore))
+	s.On("GetEventStore").Return(new(mockstore.MockStore))
+	router := NewEntitiesRouter(s)
+	router.controller = controller
+	parentRouter := mux.NewRouter().PathPrefix(corev2.URLPrefix).Subrouter()
+	router.Mount(parentRouter)
+
+	server := httptest.NewServer(parentRouter)
+	defer server.Close()
+
+	url := server.URL + corev2.URLPrefix + "/namespaces/default/entities/foo/undelete"
+	resp, err := http.Post(url, "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got, want := resp.StatusCode, http.StatusNoContent; got != want {
+		t.Fatalf("bad status: got %d, want %d", got, want)
+	}
+
+	url = server.URL + corev2.URLPrefix + "/namespaces/default/entities/bar/undelete"
+	resp, err = http.Post(url, "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got, want := resp.StatusCode, http.StatusNotFound; got != want {
+		t.Fatalf("bad status: got %d, want %d", got, want)
+	}
+}
+
+func TestEntitiesRouterHistory(t *testing.T) {
+	controller := new(mockEntitiesController)
+	snapshots := []*storev2.EntityStateSnapshot{
+		{Namespace: "default", Entity: "foo", Timestamp: 2, System: corev2.System{Hostname: "bar"}},
+		{Namespace: "default", Entity: "foo", Timestamp: 1, System: corev2.System{Hostname: "foo"}},
+	}
+	controller.On("History", mock.Anything, "foo").Return(snapshots, nil)
+	s := new(mockstore.V2MockStore)
+	s.On("GetEntityStore").Return(new(mockstore.MockStore))
+	s.On("GetEventStore").Return(new(mockstore.MockStore))
+	router := NewEntitiesRouter(s)
+	router.controller = controller
+	parentRouter := mux.NewRouter().PathPrefix(corev2.URLPrefix).Subrouter()
+	router.Mount(parentRouter)
+
+	server := httptest.NewServer(parentRouter)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + corev2.URLPrefix + "/namespaces/default/entities/foo/history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got, want := resp.StatusCode, http.StatusOK; got != want {
+		t.Fatalf("bad status: got %d, want %d", got, want)
+	}
+	var got []*storev2.EntityStateSnapshot
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, snapshots) {
+		t.Errorf("bad history: got %v, want %v", got, snapshots)
+	}
+}
+
+func TestEntitiesRouterBulk(t *testing.T) {
+	controller := new(mockEntitiesController)
+	results := []actions.BulkEntityResult{{Name: "foo"}, {Name: "bar", Error: "entity is managed by its agent"}}
+	controller.On("BulkCreateOrReplace", mock.Anything, mock.Anything).Return(results)
+	s := new(mockstore.V2MockStore)
+	s.On("GetEntityStore").Return(new(mockstore.MockStore))
+	s.On("GetEventStore").Return(new(mockstore.MockStore))
+	router := NewEntitiesRouter(s)
+	router.controller = controller
+	parentRouter := mux.NewRouter().PathPrefix(corev2.URLPrefix).Subrouter()
+	router.Mount(parentRouter)
+
+	server := httptest.NewServer(parentRouter)
+	defer server.Close()
+
+	body, _ := json.Marshal([]types.Wrapper{
+		types.WrapResource(corev2.FixtureEntity("foo")),
+		types.WrapResource(corev2.FixtureEntity("bar")),
+	})
+	url := server.URL + corev2.URLPrefix + "/namespaces/default/entities/bulk"
+	resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got, want := resp.StatusCode, http.StatusOK; got != want {
+		t.Fatalf("bad status: got %d, want %d", got, want)
+	}
+	var got []actions.BulkEntityResult
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, results) {
+		t.Errorf("bad results: got %v, want %v", got, results)
+	}
+
+	resp, err = http.Post(url, "application/json", bytes.NewReader([]byte("{}")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got, want := resp.StatusCode, http.StatusBadRequest; got != want {
+		t.Fatalf("bad status: got %d, want %d", got, want)
+	}
+}
+
+func TestEntitiesRouterCount(t *testing.T) {
+	ecstore := new(mockstore.EntityConfigStore)
+	ecstore.On("Count", mock.Anything, "default", "").Return(3, nil)
+	s := new(mockstore.V2MockStore)
+	s.On("GetEntityConfigStore").Return(ecstore)
+	s.On("GetEntityStore").Return(new(mockstore.MockStore))
+	s.On("GetEventStore").Return(new(mockstore.MockStore))
+	router := NewEntitiesRouter(s)
+	parentRouter := mux.NewRouter().PathPrefix(corev2.URLPrefix).Subrouter()
+	parentRouter.Use(middlewares.Namespace{}.Then)
+	router.Mount(parentRouter)
+
+	server := httptest.NewServer(parentRouter)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + corev2.URLPrefix + "/namespaces/default/count/entities")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got, want := resp.StatusCode, http.StatusOK; got != want {
+		t.Fatalf("bad status: got %d, want %d", got, want)
+	}
+	var got countResult
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Count != 3 {
+		t.Errorf("bad count: got %d, want 3", got.Count)
+	}
+}
+
 func TestEntitiesRouter(t *testing.T) {
 	// Setup the router
 	controller := new(mockEntitiesController)
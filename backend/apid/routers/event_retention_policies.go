@@ -0,0 +1,41 @@
+package routers
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/backend/retention"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// EventRetentionPoliciesRouter handles requests for EventRetentionPolicies.
+type EventRetentionPoliciesRouter struct {
+	store storev2.Interface
+}
+
+// NewEventRetentionPoliciesRouter instantiates a new router for
+// EventRetentionPolicies.
+func NewEventRetentionPoliciesRouter(store storev2.Interface) *EventRetentionPoliciesRouter {
+	return &EventRetentionPoliciesRouter{
+		store: store,
+	}
+}
+
+// Mount the EventRetentionPoliciesRouter on the given parent Router
+func (r *EventRetentionPoliciesRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/namespaces/{namespace}/{resource:eventretentionpolicies}",
+	}
+
+	handlers := handlers.NewHandlers[*retention.EventRetentionPolicy](r.store)
+
+	routes.Del(handlers.DeleteResource)
+	routes.Get(handlers.GetResource)
+	routes.Watch(handlers.WatchResources)
+	routes.List(handlers.ListResources, retention.EventRetentionPolicyFields)
+	routes.WatchAllNamespaces(handlers.WatchResources, "/{resource:eventretentionpolicies}")
+	routes.ListAllNamespaces(handlers.ListResources, "/{resource:eventretentionpolicies}", retention.EventRetentionPolicyFields)
+	routes.Patch(handlers.PatchResource)
+	dryRun(routes.Post(handlers.CreateResource))
+	dryRun(routes.Put(handlers.CreateOrUpdateResource))
+}
@@ -0,0 +1,36 @@
+package routers
+
+import (
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/retention"
+	"github.com/sensu/sensu-go/testing/mockstore"
+)
+
+func TestEventRetentionPoliciesRouter(t *testing.T) {
+	// Setup the router
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	router := NewEventRetentionPoliciesRouter(s)
+	parentRouter := mux.NewRouter().PathPrefix("/api/retention/v1").Subrouter()
+	router.Mount(parentRouter)
+
+	empty := &retention.EventRetentionPolicy{Metadata: &corev2.ObjectMeta{}}
+	fixture := &retention.EventRetentionPolicy{
+		Metadata: corev2.NewObjectMetaP("foo", "default"),
+		MaxAge:   3600,
+	}
+
+	tests := []routerTestCase{}
+	tests = append(tests, getTestCases[*retention.EventRetentionPolicy](fixture)...)
+	tests = append(tests, listTestCases[*retention.EventRetentionPolicy](empty)...)
+	tests = append(tests, createTestCases(fixture)...)
+	tests = append(tests, updateTestCases(fixture)...)
+	tests = append(tests, deleteTestCases(fixture)...)
+	for _, tt := range tests {
+		run(t, tt, parentRouter, s)
+	}
+}
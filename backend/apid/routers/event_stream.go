@@ -0,0 +1,241 @@
+package routers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/apid/request"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/selector"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+const (
+	// eventStreamBufferSize is the number of events buffered for a client.
+	// Slow clients that fall behind are disconnected, and can resume the
+	// stream with the Last-Event-ID header.
+	eventStreamBufferSize = 100
+
+	// eventStreamKeepalive is the interval between two keepalive comments
+	// sent on idle streams, so that proxies do not close them.
+	eventStreamKeepalive = 30 * time.Second
+)
+
+// eventStreamID distinguishes the bus consumers of concurrent streams.
+var eventStreamID int64
+
+// EventStreamRouter handles requests for /events/stream, pushing the events
+// published by eventd to clients as server-sent events.
+type EventStreamRouter struct {
+	controller eventLister
+	bus        messaging.MessageBus
+	timeout    time.Duration
+}
+
+// eventLister represents the controller needs of the EventStreamRouter.
+type eventLister interface {
+	List(ctx context.Context, pred *store.SelectionPredicate) ([]corev3.Resource, error)
+}
+
+// NewEventStreamRouter instantiates a new event stream router. Streams are
+// closed after timeout, if not zero, so that they end before the write
+// timeout of the server. Clients are then expected to reconnect.
+func NewEventStreamRouter(store storev2.Interface, bus messaging.MessageBus, timeout time.Duration) *EventStreamRouter {
+	return &EventStreamRouter{
+		controller: actions.NewEventController(store, bus),
+		bus:        bus,
+		timeout:    timeout,
+	}
+}
+
+// Mount the EventStreamRouter to a parent Router. It must be mounted before
+// the EventsRouter, whose routes would otherwise match the stream path.
+func (r *EventStreamRouter) Mount(parent *mux.Router) {
+	parent.HandleFunc("/namespaces/{namespace}/{resource:events}/stream", r.stream).Methods(http.MethodGet)
+}
+
+// eventStreamSubscriber receives the events published on the bus. The bus
+// blocks until its subscribers receive a message, so the messages are
+// forwarded to the buffer of the client without ever blocking.
+type eventStreamSubscriber struct {
+	ch     chan interface{}
+	events chan *corev2.Event
+	// overflow is closed when the client falls behind.
+	overflow chan struct{}
+}
+
+func (s *eventStreamSubscriber) Receiver() chan<- interface{} {
+	return s.ch
+}
+
+// forward forwards the events of a namespace matching the selector, if any.
+func (s *eventStreamSubscriber) forward(namespace string, sel *selector.Selector) {
+	for msg := range s.ch {
+		var event *corev2.Event
+		switch msg := msg.(type) {
+		case *corev2.Event:
+			event = msg
+		case *messaging.EventWithPrevious:
+			event = msg.Event
+		}
+		if event == nil || event.Entity == nil || event.Entity.Namespace != namespace {
+			continue
+		}
+		if sel != nil && len(sel.Operations) > 0 && !sel.Matches(eventSelectorSet(event)) {
+			continue
+		}
+		select {
+		case s.events <- event:
+		default:
+			select {
+			case <-s.overflow:
+			default:
+				close(s.overflow)
+			}
+		}
+	}
+}
+
+// eventSelectorSet returns the fields and the labels of an event that the
+// selectors match against.
+func eventSelectorSet(event *corev2.Event) map[string]string {
+	set := corev2.EventFields(event)
+	for k, v := range event.Labels {
+		set["event.labels."+k] = v
+	}
+	if event.HasCheck() {
+		for k, v := range event.Check.Labels {
+			set["event.check.labels."+k] = v
+		}
+	}
+	for k, v := range event.Entity.Labels {
+		set["event.entity.labels."+k] = v
+	}
+	return set
+}
+
+// stream streams the events of a namespace, filtered by the selector of the
+// request. The id of the events is their
+// timestamp: when the Last-Event-ID header is set, the stored events with a
+// timestamp greater than or equal to it are sent first.
+func (r *EventStreamRouter) stream(w http.ResponseWriter, req *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		WriteError(w, fmt.Errorf("streaming is not supported"))
+		return
+	}
+	ctx := req.Context()
+	namespace := mux.Vars(req)["namespace"]
+
+	var lastEventID int64
+	if value := req.Header.Get("Last-Event-ID"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			WriteError(w, actions.NewErrorf(actions.InvalidArgument, "invalid Last-Event-ID: %q", value))
+			return
+		}
+		lastEventID = id
+	}
+
+	// Subscribe before listing the stored events, so that no event is missed
+	sub := &eventStreamSubscriber{
+		ch:       make(chan interface{}, eventStreamBufferSize),
+		events:   make(chan *corev2.Event, eventStreamBufferSize),
+		overflow: make(chan struct{}),
+	}
+	consumer := fmt.Sprintf("apid-event-stream-%d", atomic.AddInt64(&eventStreamID, 1))
+	subscription, err := r.bus.Subscribe(messaging.TopicEvent, consumer, sub)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	go sub.forward(namespace, request.SelectorFromContext(ctx))
+	defer func() {
+		if err := subscription.Cancel(); err != nil {
+			logger.WithError(err).Error("failed to cancel event stream subscription")
+		}
+		// The bus recovers from sends on the closed channel
+		close(sub.ch)
+	}()
+
+	var replay []*corev2.Event
+	if lastEventID > 0 {
+		resources, err := r.controller.List(ctx, &store.SelectionPredicate{})
+		if err != nil {
+			WriteError(w, err)
+			return
+		}
+		for _, resource := range resources {
+			if event, ok := resource.(*corev2.Event); ok && event.Timestamp >= lastEventID {
+				replay = append(replay, event)
+			}
+		}
+		sort.SliceStable(replay, func(i, j int) bool {
+			return replay[i].Timestamp < replay[j].Timestamp
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for _, event := range replay {
+		if err := writeStreamEvent(w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	var timeout <-chan time.Time
+	if r.timeout > 0 {
+		timer := time.NewTimer(r.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	keepalive := time.NewTicker(eventStreamKeepalive)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case event := <-sub.events:
+			if err := writeStreamEvent(w, event); err != nil {
+				return
+			}
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		case <-sub.overflow:
+			return
+		case <-timeout:
+			return
+		case <-ctx.Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// writeStreamEvent writes the event as a server-sent event.
+func writeStreamEvent(w http.ResponseWriter, event *corev2.Event) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		logger.WithError(err).Error("failed to marshal streamed event")
+		return nil
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: event\ndata: %s\n\n", event.Timestamp, b)
+	return err
+}
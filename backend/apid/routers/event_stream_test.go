@@ -0,0 +1,141 @@
+package routers
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/apid/request"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/selector"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEventStreamRouter(t *testing.T) {
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer bus.Stop()
+
+	old := corev2.FixtureEvent("entity1", "old")
+	old.Timestamp = 100
+	recent := corev2.FixtureEvent("entity1", "recent")
+	recent.Timestamp = 200
+	controller := &mockEventController{}
+	controller.On("List", mock.Anything, mock.Anything).Return([]corev3.Resource{recent, old}, nil)
+
+	router := &EventStreamRouter{controller: controller, bus: bus, timeout: 5 * time.Second}
+	parentRouter := mux.NewRouter().PathPrefix(corev2.URLPrefix).Subrouter()
+	router.Mount(parentRouter)
+	server := httptest.NewServer(parentRouter)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/core/v2/namespaces/default/events/stream", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Last-Event-ID", "150")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got, want := resp.Header.Get("Content-Type"), "text/event-stream"; got != want {
+		t.Fatalf("bad content type: got %q, want %q", got, want)
+	}
+
+	other := corev2.FixtureEvent("entity1", "other")
+	other.Entity.Namespace = "other"
+	live := corev2.FixtureEvent("entity1", "live")
+	live.Timestamp = 300
+	if err := bus.Publish(messaging.TopicEvent, other); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Publish(messaging.TopicEvent, &messaging.EventWithPrevious{Event: live}); err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() && len(ids) < 2 {
+		if id := strings.TrimPrefix(scanner.Text(), "id: "); id != scanner.Text() {
+			ids = append(ids, id)
+		}
+	}
+	if strings.Join(ids, ",") != "200,300" {
+		t.Errorf("bad streamed events: %v", ids)
+	}
+}
+
+func TestEventStreamRouterSelector(t *testing.T) {
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer bus.Stop()
+
+	router := &EventStreamRouter{controller: &mockEventController{}, bus: bus, timeout: 5 * time.Second}
+	parentRouter := mux.NewRouter().PathPrefix(corev2.URLPrefix).Subrouter()
+	router.Mount(parentRouter)
+	sel, err := selector.ParseFieldSelector("event.check.name == wanted")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parentRouter.ServeHTTP(w, r.WithContext(request.ContextWithSelector(r.Context(), sel)))
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/core/v2/namespaces/default/events/stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	unwanted := corev2.FixtureEvent("entity1", "unwanted")
+	unwanted.Timestamp = 100
+	wanted := corev2.FixtureEvent("entity1", "wanted")
+	wanted.Timestamp = 200
+	for _, event := range []*corev2.Event{unwanted, wanted} {
+		if err := bus.Publish(messaging.TopicEvent, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if id := strings.TrimPrefix(scanner.Text(), "id: "); id != scanner.Text() {
+			if id != "200" {
+				t.Errorf("bad streamed event: %s", id)
+			}
+			break
+		}
+	}
+}
+
+func TestEventStreamRouterInvalidLastEventID(t *testing.T) {
+	router := &EventStreamRouter{controller: &mockEventController{}}
+	parentRouter := mux.NewRouter().PathPrefix(corev2.URLPrefix).Subrouter()
+	router.Mount(parentRouter)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/core/v2/namespaces/default/events/stream", nil)
+	req.Header.Set("Last-Event-ID", "yesterday")
+	rec := httptest.NewRecorder()
+	parentRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("bad status: got %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
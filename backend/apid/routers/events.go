@@ -2,17 +2,23 @@ package routers
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/apid/filters/fields"
+	"github.com/sensu/sensu-go/backend/apid/filters/labels"
 	"github.com/sensu/sensu-go/backend/apid/handlers"
 	"github.com/sensu/sensu-go/backend/apid/request"
 	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/selector"
 	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 )
@@ -28,6 +34,7 @@ type eventController interface {
 	Delete(ctx context.Context, entity, check string) error
 	Get(ctx context.Context, entity, check string) (*corev2.Event, error)
 	List(ctx context.Context, pred *store.SelectionPredicate) ([]corev3.Resource, error)
+	HandlerResults(ctx context.Context, entity, check string) ([]*storev2.HandlerResult, error)
 }
 
 // NewEventsRouter instantiates new events controller
@@ -45,16 +52,24 @@ func (r *EventsRouter) Mount(parent *mux.Router) {
 	}
 
 	routes.Post(r.create)
-	routes.List(r.controller.List, corev3.EventFields)
-	routes.ListAllNamespaces(r.controller.List, "/{resource:events}", corev3.EventFields)
+	routes.ListConditional(r.controller.List, corev3.EventFields, EventModified)
+	routes.ListAllNamespacesConditional(r.controller.List, "/{resource:events}", corev3.EventFields, EventModified)
 	routes.Path("{entity}/{check}", r.get).Methods(http.MethodGet)
 	routes.Path("{entity}/{check}", r.delete).Methods(http.MethodDelete)
 	routes.Path("{entity}/{check}", r.createOrReplace).Methods(http.MethodPost, http.MethodPut)
 
+	parent.HandleFunc(path.Join(routes.PathPrefix, "{entity}/{check}/handler-results"), r.handlerResults).Methods(http.MethodGet)
+
+	// The bulk operations act on the events matching the label and field
+	// selectors of the request, rather than on the events of its body
+	bulkPath := path.Join(path.Dir(routes.PathPrefix), "bulk", path.Base(routes.PathPrefix))
+	parent.HandleFunc(path.Join(bulkPath, "resolve"), bulkHandler(r.bulkResolve)).Methods(http.MethodPost)
+	parent.HandleFunc(bulkPath, bulkHandler(r.bulkDelete)).Methods(http.MethodDelete)
+
 	// Additionaly allow a subcollection to be specified when listing events,
 	// which correspond to the entity name here
 	parent.HandleFunc(path.Join(routes.PathPrefix, "{subcollection}"),
-		WrapList(r.controller.List, corev3.EventFields)).Methods(http.MethodGet)
+		WrapConditionalList(r.controller.List, corev3.EventFields, EventModified)).Methods(http.MethodGet)
 }
 
 func (r *EventsRouter) get(req *http.Request) (handlers.HandlerResponse, error) {
@@ -66,6 +81,28 @@ func (r *EventsRouter) get(req *http.Request) (handlers.HandlerResponse, error)
 	return response, err
 }
 
+// handlerResults responds with the last handler results of an event.
+func (r *EventsRouter) handlerResults(w http.ResponseWriter, req *http.Request) {
+	params := actions.QueryParams(mux.Vars(req))
+	entity := url.PathEscape(params["entity"])
+	check := url.PathEscape(params["check"])
+	results, err := r.controller.HandlerResults(req.Context(), entity, check)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	b, err := json.Marshal(results)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(b); err != nil {
+		logger.WithError(err).Error("failed to write response")
+	}
+}
+
 func (r *EventsRouter) delete(req *http.Request) (handlers.HandlerResponse, error) {
 	params := actions.QueryParams(mux.Vars(req))
 	entity := url.PathEscape(params["entity"])
@@ -73,6 +110,103 @@ func (r *EventsRouter) delete(req *http.Request) (handlers.HandlerResponse, erro
 	return handlers.HandlerResponse{}, r.controller.Delete(req.Context(), entity, check)
 }
 
+// bulkResolve resolves the failing events matching the selectors of the
+// request. The events that are already resolved are left untouched, and are
+// not part of the results.
+func (r *EventsRouter) bulkResolve(req *http.Request) ([]handlers.BulkResult, error) {
+	events, err := r.selectEvents(req)
+	if err != nil {
+		return nil, err
+	}
+	source := req.URL.Query().Get("source")
+	if source == "" {
+		source = "the API"
+	}
+	results := []handlers.BulkResult{}
+	for _, event := range events {
+		if !event.HasCheck() || event.Check.Status == 0 {
+			continue
+		}
+		event.Check.Status = 0
+		event.Check.Output = "Resolved manually with " + source
+		event.Check.Executed = time.Now().Unix()
+		event.Timestamp = event.Check.Executed
+		results = append(results, handlers.BulkResult{
+			Name: eventName(event),
+			Err:  r.controller.CreateOrReplace(req.Context(), event),
+		})
+	}
+	return results, nil
+}
+
+// bulkDelete deletes the events matching the selectors of the request.
+func (r *EventsRouter) bulkDelete(req *http.Request) ([]handlers.BulkResult, error) {
+	events, err := r.selectEvents(req)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]handlers.BulkResult, 0, len(events))
+	for _, event := range events {
+		if !event.HasCheck() {
+			continue
+		}
+		results = append(results, handlers.BulkResult{
+			Name: eventName(event),
+			Err:  r.controller.Delete(req.Context(), event.Entity.Name, event.Check.Name),
+		})
+	}
+	return results, nil
+}
+
+// selectEvents lists the events of the namespace matching the label and field
+// selectors of the request. A selector is required, so that the bulk
+// operations never act on all the events of a namespace by mistake.
+func (r *EventsRouter) selectEvents(req *http.Request) ([]*corev2.Event, error) {
+	query := req.URL.Query()
+
+	var labelSelector, fieldSelector *selector.Selector
+	var err error
+	if requirements := strings.Join(query["labelSelector"], " && "); requirements != "" {
+		if labelSelector, err = selector.ParseLabelSelector(requirements); err != nil {
+			return nil, actions.NewError(actions.InvalidArgument, err)
+		}
+	}
+	if requirements := strings.Join(query["fieldSelector"], " && "); requirements != "" {
+		if fieldSelector, err = selector.ParseFieldSelector(requirements); err != nil {
+			return nil, actions.NewError(actions.InvalidArgument, err)
+		}
+	}
+	if labelSelector == nil && fieldSelector == nil {
+		return nil, actions.NewErrorf(actions.InvalidArgument, "a label or field selector is required")
+	}
+
+	ctx := request.ContextWithSelector(req.Context(), selector.Merge(labelSelector, fieldSelector))
+	resources, err := r.controller.List(ctx, &store.SelectionPredicate{})
+	if err != nil {
+		return nil, err
+	}
+	if labelSelector != nil {
+		resources = labels.Filter(resources, labelSelector.Matches).([]corev3.Resource)
+	}
+	if fieldSelector != nil {
+		resources = fields.Filter(resources, fieldSelector.Matches, fields.FieldsFunc(corev3.EventFields)).([]corev3.Resource)
+	}
+
+	events := make([]*corev2.Event, 0, len(resources))
+	for _, resource := range resources {
+		if event, ok := resource.(*corev2.Event); ok {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// eventName returns the name of an event in the results of the bulk
+// operations, ENTITY/CHECK.
+func eventName(event *corev2.Event) string {
+	return event.Entity.Name + "/" + event.Check.Name
+}
+
 func (r *EventsRouter) create(req *http.Request) (handlers.HandlerResponse, error) {
 	var response handlers.HandlerResponse
 	event, err := request.Resource[*corev2.Event](req)
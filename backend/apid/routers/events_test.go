@@ -3,9 +3,11 @@ package routers
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/gorilla/mux"
@@ -13,7 +15,10 @@ import (
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type mockEventController struct {
@@ -38,6 +43,11 @@ func (m *mockEventController) List(ctx context.Context, pred *store.SelectionPre
 	return args.Get(0).([]corev3.Resource), args.Error(1)
 }
 
+func (m *mockEventController) HandlerResults(ctx context.Context, entity, check string) ([]*storev2.HandlerResult, error) {
+	args := m.Called(ctx, entity, check)
+	return args.Get(0).([]*storev2.HandlerResult), args.Error(1)
+}
+
 func TestEventsRouter(t *testing.T) {
 	type controllerFunc func(*mockEventController)
 
@@ -89,6 +99,28 @@ func TestEventsRouter(t *testing.T) {
 			},
 			wantStatusCode: http.StatusOK,
 		},
+		{
+			name:   "it returns 200 and the handler results of an event",
+			method: http.MethodGet,
+			path:   fixture.URIPath() + "/handler-results",
+			controllerFunc: func(c *mockEventController) {
+				c.On("HandlerResults", mock.Anything, "foo", "check-cpu").
+					Return([]*storev2.HandlerResult{{Handler: "slack"}}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "it returns 500 if the store encounters an error while getting handler results",
+			method: http.MethodGet,
+			path:   fixture.URIPath() + "/handler-results",
+			controllerFunc: func(c *mockEventController) {
+				c.On("HandlerResults", mock.Anything, "foo", "check-cpu").
+					Return([]*storev2.HandlerResult(nil), actions.NewErrorf(actions.InternalErr)).
+					Once()
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
 		{
 			name:   "it returns 500 if the store encounters an error while listing events",
 			method: http.MethodGet,
@@ -413,3 +445,99 @@ func TestEventsRouter(t *testing.T) {
 		})
 	}
 }
+
+func TestEventsRouterBulk(t *testing.T) {
+	failing := corev2.FixtureEvent("foo", "check-cpu")
+	failing.Check.Status = 2
+	passing := corev2.FixtureEvent("bar", "check-cpu")
+	other := corev2.FixtureEvent("foo", "check-mem")
+	other.Check.Status = 2
+	selected := "?fieldSelector=" + url.QueryEscape(`event.check.name == "check-cpu"`)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		controllerFunc func(*mockEventController)
+		wantStatusCode int
+		wantResults    []bulkResult
+	}{
+		{
+			name:           "it requires a selector",
+			method:         http.MethodDelete,
+			path:           "/api/core/v2/namespaces/default/bulk/events",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "it returns 400 if the selector is not valid",
+			method:         http.MethodDelete,
+			path:           "/api/core/v2/namespaces/default/bulk/events?labelSelector=" + url.QueryEscape("region =="),
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "it deletes the matching events",
+			method: http.MethodDelete,
+			path:   "/api/core/v2/namespaces/default/bulk/events" + selected,
+			controllerFunc: func(c *mockEventController) {
+				c.On("List", mock.Anything, mock.Anything).Return([]corev3.Resource{failing, passing, other}, nil)
+				c.On("Delete", mock.Anything, "foo", "check-cpu").Return(nil)
+				c.On("Delete", mock.Anything, "bar", "check-cpu").Return(actions.NewErrorf(actions.NotFound))
+			},
+			wantStatusCode: http.StatusOK,
+			wantResults: []bulkResult{
+				{Name: "foo/check-cpu", Status: http.StatusOK},
+				{Name: "bar/check-cpu", Status: http.StatusNotFound},
+			},
+		},
+		{
+			name:   "it resolves the matching failing events",
+			method: http.MethodPost,
+			path:   "/api/core/v2/namespaces/default/bulk/events/resolve" + selected,
+			controllerFunc: func(c *mockEventController) {
+				c.On("List", mock.Anything, mock.Anything).Return([]corev3.Resource{failing, passing, other}, nil)
+				c.On("CreateOrReplace", mock.Anything, mock.MatchedBy(func(event *corev2.Event) bool {
+					return event.Entity.Name == "foo" && event.Check.Status == 0
+				})).Return(nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantResults: []bulkResult{
+				{Name: "foo/check-cpu", Status: http.StatusOK},
+			},
+		},
+		{
+			name:   "it returns 500 if the events can't be listed",
+			method: http.MethodPost,
+			path:   "/api/core/v2/namespaces/default/bulk/events/resolve" + selected,
+			controllerFunc: func(c *mockEventController) {
+				c.On("List", mock.Anything, mock.Anything).Return([]corev3.Resource(nil), actions.NewErrorf(actions.InternalErr))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := &mockEventController{}
+			if tt.controllerFunc != nil {
+				tt.controllerFunc(controller)
+			}
+			router := EventsRouter{controller: controller}
+			parentRouter := mux.NewRouter().PathPrefix(corev2.URLPrefix).Subrouter()
+			router.Mount(parentRouter)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			res := httptest.NewRecorder()
+			parentRouter.ServeHTTP(res, req)
+
+			require.Equal(t, tt.wantStatusCode, res.Code, res.Body.String())
+			if tt.wantResults == nil {
+				return
+			}
+			var results []bulkResult
+			require.NoError(t, json.Unmarshal(res.Body.Bytes(), &results))
+			for i := range results {
+				results[i].Error = nil
+			}
+			assert.Equal(t, tt.wantResults, results)
+		})
+	}
+}
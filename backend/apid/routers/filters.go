@@ -31,9 +31,14 @@ func (r *EventFiltersRouter) Mount(parent *mux.Router) {
 
 	routes.Del(handlers.DeleteResource)
 	routes.Get(handlers.GetResource)
+	routes.Watch(handlers.WatchResources)
 	routes.List(handlers.ListResources, corev3.EventFilterFields)
+	routes.WatchAllNamespaces(handlers.WatchResources, "/{resource:filters}")
 	routes.ListAllNamespaces(handlers.ListResources, "/{resource:filters}", corev3.EventFilterFields)
 	routes.Patch(handlers.PatchResource)
-	routes.Post(handlers.CreateResource)
-	routes.Put(handlers.CreateOrUpdateResource)
+	dryRun(routes.Post(handlers.CreateResource))
+	dryRun(routes.Put(handlers.CreateOrUpdateResource))
+	routes.Bulk(handlers.BulkCreateResources, handlers.BulkCreateOrUpdateResources, handlers.BulkDeleteResources)
+	routes.Count(handlers.CountResources)
+	routes.History(handlers.ListResourceVersions, handlers.GetResourceVersion, handlers.DiffResourceVersion, handlers.RollbackResource)
 }
@@ -11,6 +11,7 @@ import (
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/backend/apid/middlewares"
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
 	"github.com/sensu/sensu-go/graphql"
 )
@@ -21,17 +22,23 @@ var (
 
 type GraphQLService interface {
 	Do(context.Context, graphql.QueryParams) *graphql.Result
+	Subscribe(context.Context, graphql.QueryParams) chan *graphql.Result
 }
 
 // GraphQLRouter handles requests for /events
 type GraphQLRouter struct {
 	Service GraphQLService
 	Timeout time.Duration
+	// ReadOnly rejects the mutations while enabled.
+	ReadOnly *middlewares.ReadOnlyMode
 }
 
 // Mount the GraphQLRouter to a parent Router
 func (r *GraphQLRouter) Mount(parent *mux.Router) {
 	parent.HandleFunc("/graphql", r.query).Methods(http.MethodPost)
+	parent.HandleFunc("/graphql", r.subscribe).
+		Methods(http.MethodGet).
+		HeadersRegexp("Upgrade", "(?i)^websocket$")
 }
 
 func (r *GraphQLRouter) query(w http.ResponseWriter, req *http.Request) {
@@ -80,12 +87,16 @@ func (r *GraphQLRouter) query(w http.ResponseWriter, req *http.Request) {
 		queryVars, _ := op["variables"].(map[string]interface{})
 		skipValidate, _ := op["skip_validation"].(bool)
 
+		extensions, _ := op["extensions"].(map[string]interface{})
+
 		// Execute given query
 		result := r.Service.Do(ctx, graphql.QueryParams{
-			Query:          query,
-			Variables:      queryVars,
-			SkipValidation: skipValidate,
-			IsAuthed:       claims != nil,
+			Query:              query,
+			PersistedQueryHash: persistedQueryHash(extensions),
+			Variables:          queryVars,
+			SkipValidation:     skipValidate,
+			IsAuthed:           claims != nil,
+			ReadOnly:           r.ReadOnly.Enabled(),
 		})
 		results = append(results, map[string]interface{}{
 			"data":   result.Data,
@@ -121,3 +132,12 @@ func (r *GraphQLRouter) query(w http.ResponseWriter, req *http.Request) {
 
 	RespondWith(w, req, response)
 }
+
+// persistedQueryHash returns the hash of the persisted query given in the
+// extensions of an operation, as sent by the clients of automatic persisted
+// queries: {"persistedQuery": {"version": 1, "sha256Hash": "..."}}
+func persistedQueryHash(extensions map[string]interface{}) string {
+	persistedQuery, _ := extensions["persistedQuery"].(map[string]interface{})
+	hash, _ := persistedQuery["sha256Hash"].(string)
+	return hash
+}
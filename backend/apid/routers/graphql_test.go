@@ -9,6 +9,7 @@ import (
 
 	"github.com/graphql-go/graphql/testutil"
 	"github.com/sensu/sensu-go/backend/apid/graphql"
+	sensugraphql "github.com/sensu/sensu-go/graphql"
 )
 
 func setupRequest(method string, path string, payload interface{}) (*http.Request, error) {
@@ -79,3 +80,50 @@ func TestHttpGraphQLBatchRequest(t *testing.T) {
 		t.Error("response failed")
 	}
 }
+
+func TestHttpGraphQLPersistedQuery(t *testing.T) {
+	service, err := graphql.NewService(graphql.ServiceConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := &GraphQLRouter{Service: service}
+
+	query := "{ __typename }"
+	persistedQuery := map[string]interface{}{
+		"persistedQuery": map[string]interface{}{
+			"version":    1,
+			"sha256Hash": sensugraphql.PersistedQueryHash(query),
+		},
+	}
+	do := func(body map[string]interface{}) map[string]interface{} {
+		req, err := setupRequest(http.MethodPost, "/graphql", body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writer := httptest.NewRecorder()
+		router.query(writer, req)
+		var results []map[string]interface{}
+		if err := json.NewDecoder(writer.Body).Decode(&results); err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("expected a single result, got %v", results)
+		}
+		return results[0]
+	}
+
+	result := do(map[string]interface{}{"extensions": persistedQuery})
+	if errs, _ := result["errors"].([]interface{}); len(errs) != 1 || errs[0].(map[string]interface{})["message"] != "PersistedQueryNotFound" {
+		t.Fatalf("expected the persisted query not to be found, got %v", result)
+	}
+
+	result = do(map[string]interface{}{"query": query, "extensions": persistedQuery})
+	if result["errors"] != nil {
+		t.Fatalf("unexpected errors: %v", result["errors"])
+	}
+
+	result = do(map[string]interface{}{"extensions": persistedQuery})
+	if data, _ := result["data"].(map[string]interface{}); data["__typename"] != "Query" {
+		t.Fatalf("unexpected result: %v", result)
+	}
+}
@@ -0,0 +1,330 @@
+package routers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/graphql"
+)
+
+// graphqlWSProtocol is the websocket subprotocol of the GraphQL subscriptions,
+// as specified by https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+const graphqlWSProtocol = "graphql-transport-ws"
+
+// The message types of the graphql-transport-ws protocol.
+const (
+	graphqlWSConnectionInit = "connection_init"
+	graphqlWSConnectionAck  = "connection_ack"
+	graphqlWSPing           = "ping"
+	graphqlWSPong           = "pong"
+	graphqlWSSubscribe      = "subscribe"
+	graphqlWSNext           = "next"
+	graphqlWSError          = "error"
+	graphqlWSComplete       = "complete"
+)
+
+// The close codes of the graphql-transport-ws protocol.
+const (
+	graphqlWSInvalidMessage      = 4400
+	graphqlWSUnauthorized        = 4401
+	graphqlWSForbidden           = 4403
+	graphqlWSSubprotocol         = 4406
+	graphqlWSInitTimeout         = 4408
+	graphqlWSDuplicateSubscriber = 4409
+	graphqlWSTooManyInitRequests = 4429
+)
+
+const (
+	// graphqlWSConnectionInitWait is the time given to the clients to send
+	// their connection_init message.
+	graphqlWSConnectionInitWait = 10 * time.Second
+
+	// graphqlWSWriteWait is the time allowed to write a message.
+	graphqlWSWriteWait = 10 * time.Second
+
+	// graphqlWSPongWait is the time allowed to read the next pong message.
+	// The connections are pinged more often than that.
+	graphqlWSPongWait     = 60 * time.Second
+	graphqlWSPingInterval = graphqlWSPongWait * 9 / 10
+
+	// graphqlWSMaxSubscriptions is the maximum number of concurrent
+	// subscriptions of a connection.
+	graphqlWSMaxSubscriptions = 100
+)
+
+var graphqlWSUpgrader = websocket.Upgrader{
+	Subprotocols: []string{graphqlWSProtocol},
+}
+
+type graphqlWSMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type graphqlWSSubscribePayload struct {
+	OperationName string                 `json:"operationName"`
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	Extensions    map[string]interface{} `json:"extensions"`
+}
+
+type graphqlWSInitPayload struct {
+	Authorization string `json:"Authorization"`
+}
+
+// graphqlWSConn is a websocket connection of a GraphQL subscriptions client.
+// The messages are written by the handler and by the subscriptions, under
+// the write lock; the control messages can be written concurrently.
+type graphqlWSConn struct {
+	router *GraphQLRouter
+	conn   *websocket.Conn
+	ctx    context.Context
+	claims *corev2.Claims
+
+	writeMu sync.Mutex
+
+	mu            sync.Mutex
+	acked         bool
+	subscriptions map[string]context.CancelFunc
+}
+
+// subscribe handles the websocket requests for /graphql, which execute
+// GraphQL subscriptions with the graphql-transport-ws protocol. The clients
+// that cannot set the Authorization header of the request can send their
+// access token in the payload of the connection_init message.
+func (r *GraphQLRouter) subscribe(w http.ResponseWriter, req *http.Request) {
+	conn, err := graphqlWSUpgrader.Upgrade(w, req, nil)
+	if err != nil {
+		// The upgrader already responded to the client
+		logger.WithError(err).Warn("failed to upgrade GraphQL websocket connection")
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.WithValue(req.Context(), corev2.NamespaceKey, ""))
+	defer cancel()
+
+	c := &graphqlWSConn{
+		router:        r,
+		conn:          conn,
+		ctx:           ctx,
+		claims:        jwt.GetClaimsFromContext(req.Context()),
+		subscriptions: map[string]context.CancelFunc{},
+	}
+	if conn.Subprotocol() != graphqlWSProtocol {
+		c.close(graphqlWSSubprotocol, "Subprotocol not acceptable")
+		return
+	}
+	c.serve()
+}
+
+// serve reads the messages of the client until the connection is closed.
+func (c *graphqlWSConn) serve() {
+	// The deadlines set by the server on the hijacked connection do not apply
+	// to the websocket connection, which is kept alive with pings.
+	_ = c.conn.SetReadDeadline(time.Now().Add(graphqlWSPongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(graphqlWSPongWait))
+	})
+	go c.keepalive()
+
+	initTimer := time.AfterFunc(graphqlWSConnectionInitWait, func() {
+		c.mu.Lock()
+		acked := c.acked
+		c.mu.Unlock()
+		if !acked {
+			c.close(graphqlWSInitTimeout, "Connection initialisation timeout")
+		}
+	})
+	defer initTimer.Stop()
+
+	for {
+		var msg graphqlWSMessage
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			if _, ok := err.(*json.SyntaxError); ok {
+				c.close(graphqlWSInvalidMessage, "Invalid message")
+			}
+			return
+		}
+		if !c.handle(msg) {
+			return
+		}
+	}
+}
+
+// handle handles a message of the client, and returns false if the connection
+// was closed.
+func (c *graphqlWSConn) handle(msg graphqlWSMessage) bool {
+	switch msg.Type {
+	case graphqlWSConnectionInit:
+		return c.init(msg)
+	case graphqlWSPing:
+		return c.write(graphqlWSMessage{Type: graphqlWSPong}) == nil
+	case graphqlWSPong:
+		return true
+	case graphqlWSSubscribe:
+		return c.startSubscription(msg)
+	case graphqlWSComplete:
+		c.mu.Lock()
+		if cancel, ok := c.subscriptions[msg.ID]; ok {
+			cancel()
+			delete(c.subscriptions, msg.ID)
+		}
+		c.mu.Unlock()
+		return true
+	}
+	c.close(graphqlWSInvalidMessage, "Invalid message type")
+	return false
+}
+
+func (c *graphqlWSConn) init(msg graphqlWSMessage) bool {
+	c.mu.Lock()
+	acked := c.acked
+	c.acked = true
+	c.mu.Unlock()
+	if acked {
+		c.close(graphqlWSTooManyInitRequests, "Too many initialisation requests")
+		return false
+	}
+
+	if len(msg.Payload) > 0 {
+		var payload graphqlWSInitPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.close(graphqlWSInvalidMessage, "Invalid connection_init payload")
+			return false
+		}
+		if payload.Authorization != "" {
+			token, err := jwt.ValidateToken(strings.TrimPrefix(payload.Authorization, "Bearer "))
+			if err != nil {
+				logger.WithError(err).Warn("invalid token")
+				c.close(graphqlWSForbidden, "Forbidden")
+				return false
+			}
+			c.claims = token.Claims.(*corev2.Claims)
+		}
+	}
+	return c.write(graphqlWSMessage{Type: graphqlWSConnectionAck}) == nil
+}
+
+func (c *graphqlWSConn) startSubscription(msg graphqlWSMessage) bool {
+	var payload graphqlWSSubscribePayload
+	if msg.ID == "" || json.Unmarshal(msg.Payload, &payload) != nil {
+		c.close(graphqlWSInvalidMessage, "Invalid subscribe message")
+		return false
+	}
+
+	c.mu.Lock()
+	if !c.acked {
+		c.mu.Unlock()
+		c.close(graphqlWSUnauthorized, "Unauthorized")
+		return false
+	}
+	if _, ok := c.subscriptions[msg.ID]; ok {
+		c.mu.Unlock()
+		c.close(graphqlWSDuplicateSubscriber, "Subscriber for "+msg.ID+" already exists")
+		return false
+	}
+	if len(c.subscriptions) >= graphqlWSMaxSubscriptions {
+		c.mu.Unlock()
+		return c.writeError(msg.ID, "too many subscriptions") == nil
+	}
+	ctx := c.ctx
+	if c.claims != nil {
+		ctx = context.WithValue(ctx, corev2.ClaimsKey, c.claims)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	c.subscriptions[msg.ID] = cancel
+	c.mu.Unlock()
+
+	results := c.router.Service.Subscribe(ctx, graphql.QueryParams{
+		OperationName:      payload.OperationName,
+		Query:              payload.Query,
+		PersistedQueryHash: persistedQueryHash(payload.Extensions),
+		Variables:          payload.Variables,
+		IsAuthed:           c.claims != nil,
+		ReadOnly:           c.router.ReadOnly.Enabled(),
+	})
+	go c.forward(ctx, msg.ID, results)
+	return true
+}
+
+// forward sends the results of a subscription to the client. The results
+// are drained until the channel is closed, so that the executor of the
+// subscription never blocks.
+func (c *graphqlWSConn) forward(ctx context.Context, id string, results chan *graphql.Result) {
+	failed := false
+	for result := range results {
+		if ctx.Err() != nil || failed {
+			continue
+		}
+		var err error
+		if result.Data == nil && result.HasErrors() {
+			failed = true
+			b, _ := json.Marshal(result.Errors)
+			err = c.write(graphqlWSMessage{ID: id, Type: graphqlWSError, Payload: b})
+		} else {
+			b, _ := json.Marshal(result)
+			err = c.write(graphqlWSMessage{ID: id, Type: graphqlWSNext, Payload: b})
+		}
+		if err != nil {
+			failed = true
+		}
+	}
+
+	c.mu.Lock()
+	cancel, ok := c.subscriptions[id]
+	delete(c.subscriptions, id)
+	c.mu.Unlock()
+	if !ok {
+		// The client completed the subscription
+		return
+	}
+	cancel()
+	if !failed && c.ctx.Err() == nil {
+		_ = c.write(graphqlWSMessage{ID: id, Type: graphqlWSComplete})
+	}
+}
+
+// keepalive pings the client until the connection is closed.
+func (c *graphqlWSConn) keepalive() {
+	ticker := time.NewTicker(graphqlWSPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(graphqlWSWriteWait)); err != nil {
+				return
+			}
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *graphqlWSConn) writeError(id string, message string) error {
+	b, _ := json.Marshal([]map[string]string{{"message": message}})
+	return c.write(graphqlWSMessage{ID: id, Type: graphqlWSError, Payload: b})
+}
+
+func (c *graphqlWSConn) write(msg graphqlWSMessage) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(graphqlWSWriteWait))
+	return c.conn.WriteJSON(msg)
+}
+
+// close closes the connection with the given code and reason, which also ends
+// the read loop of the handler.
+func (c *graphqlWSConn) close(code int, reason string) {
+	message := websocket.FormatCloseMessage(code, reason)
+	_ = c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(graphqlWSWriteWait))
+	_ = c.conn.Close()
+}
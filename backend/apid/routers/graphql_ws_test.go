@@ -0,0 +1,95 @@
+package routers
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/sensu/sensu-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeGraphQLService struct {
+	results chan *graphql.Result
+	params  graphql.QueryParams
+}
+
+func (s *fakeGraphQLService) Do(ctx context.Context, p graphql.QueryParams) *graphql.Result {
+	return &graphql.Result{}
+}
+
+func (s *fakeGraphQLService) Subscribe(ctx context.Context, p graphql.QueryParams) chan *graphql.Result {
+	s.params = p
+	return s.results
+}
+
+func TestGraphQLSubscription(t *testing.T) {
+	service := &fakeGraphQLService{results: make(chan *graphql.Result, 2)}
+	service.results <- &graphql.Result{Data: map[string]interface{}{"events": "one"}}
+	service.results <- &graphql.Result{Data: map[string]interface{}{"events": "two"}}
+	close(service.results)
+
+	parent := mux.NewRouter()
+	router := &GraphQLRouter{Service: service}
+	router.Mount(parent)
+	server := httptest.NewServer(parent)
+	defer server.Close()
+
+	dialer := websocket.Dialer{Subprotocols: []string{graphqlWSProtocol}}
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/graphql"
+	conn, _, err := dialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	read := func() graphqlWSMessage {
+		var msg graphqlWSMessage
+		require.NoError(t, conn.ReadJSON(&msg))
+		return msg
+	}
+
+	require.NoError(t, conn.WriteJSON(graphqlWSMessage{Type: graphqlWSConnectionInit}))
+	assert.Equal(t, graphqlWSConnectionAck, read().Type)
+
+	require.NoError(t, conn.WriteJSON(graphqlWSMessage{Type: graphqlWSPing}))
+	assert.Equal(t, graphqlWSPong, read().Type)
+
+	require.NoError(t, conn.WriteJSON(graphqlWSMessage{
+		ID:      "1",
+		Type:    graphqlWSSubscribe,
+		Payload: []byte(`{"query":"subscription { events(namespace: \"default\") { id } }"}`),
+	}))
+	msg := read()
+	assert.Equal(t, graphqlWSMessage{ID: "1", Type: graphqlWSNext, Payload: []byte(`{"data":{"events":"one"}}`)}, msg)
+	msg = read()
+	assert.Equal(t, graphqlWSMessage{ID: "1", Type: graphqlWSNext, Payload: []byte(`{"data":{"events":"two"}}`)}, msg)
+	msg = read()
+	assert.Equal(t, graphqlWSMessage{ID: "1", Type: graphqlWSComplete}, msg)
+	assert.Equal(t, `subscription { events(namespace: "default") { id } }`, service.params.Query)
+	assert.False(t, service.params.IsAuthed)
+}
+
+func TestGraphQLSubscriptionUnauthorized(t *testing.T) {
+	parent := mux.NewRouter()
+	router := &GraphQLRouter{Service: &fakeGraphQLService{}}
+	router.Mount(parent)
+	server := httptest.NewServer(parent)
+	defer server.Close()
+
+	dialer := websocket.Dialer{Subprotocols: []string{graphqlWSProtocol}}
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/graphql"
+	conn, _, err := dialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	// Subscribing before initializing the connection closes it
+	require.NoError(t, conn.WriteJSON(graphqlWSMessage{ID: "1", Type: graphqlWSSubscribe, Payload: []byte(`{}`)}))
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, graphqlWSUnauthorized), err)
+}
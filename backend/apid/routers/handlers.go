@@ -29,9 +29,14 @@ func (r *HandlersRouter) Mount(parent *mux.Router) {
 	handlers := handlers.NewHandlers[*corev2.Handler](r.store)
 	routes.Del(handlers.DeleteResource)
 	routes.Get(handlers.GetResource)
+	routes.Watch(handlers.WatchResources)
 	routes.List(handlers.ListResources, corev3.HandlerFields)
+	routes.WatchAllNamespaces(handlers.WatchResources, "/{resource:handlers}")
 	routes.ListAllNamespaces(handlers.ListResources, "/{resource:handlers}", corev3.HandlerFields)
 	routes.Patch(handlers.PatchResource)
-	routes.Post(handlers.CreateResource)
-	routes.Put(handlers.CreateOrUpdateResource)
+	dryRun(routes.Post(handlers.CreateResource))
+	dryRun(routes.Put(handlers.CreateOrUpdateResource))
+	routes.Bulk(handlers.BulkCreateResources, handlers.BulkCreateOrUpdateResources, handlers.BulkDeleteResources)
+	routes.Count(handlers.CountResources)
+	routes.History(handlers.ListResourceVersions, handlers.GetResourceVersion, handlers.DiffResourceVersion, handlers.RollbackResource)
 }
@@ -3,6 +3,7 @@ package routers
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
@@ -25,8 +26,50 @@ type ListControllerFunc func(ctx context.Context, pred *store.SelectionPredicate
 // FieldsFunc represents the function to retrieve fields about a given resource
 type FieldsFunc func(resource corev3.Resource) map[string]string
 
+// listOrderings maps the values of the sort_by query parameter to the
+// orderings of the store. The store rejects the orderings that it does not
+// support for the listed resource.
+var listOrderings = map[string]string{
+	"name":      corev2.EntitySortName,
+	"last_seen": corev2.EntitySortLastSeen,
+	"status":    corev2.EventSortSeverity,
+	"timestamp": corev2.EventSortTimestamp,
+}
+
+// listOrdering returns the ordering and direction of a list, given by the
+// sort_by and order query parameters.
+func listOrdering(query url.Values) (string, bool, error) {
+	var ordering string
+	if sortBy := query.Get("sort_by"); sortBy != "" {
+		var ok bool
+		if ordering, ok = listOrderings[sortBy]; !ok {
+			return "", false, fmt.Errorf("invalid sort_by %q: must be one of name, last_seen, status or timestamp", sortBy)
+		}
+	}
+	switch order := query.Get("order"); order {
+	case "", "asc":
+		return ordering, false, nil
+	case "desc":
+		return ordering, true, nil
+	default:
+		return "", false, fmt.Errorf("invalid order %q: must be asc or desc", order)
+	}
+}
+
 // WrapList handles pagination and selector filtering for listing resources.
 func WrapList(list ListControllerFunc, fieldsFunc FieldsFunc) http.HandlerFunc {
+	return wrapList(list, fieldsFunc, nil)
+}
+
+// WrapConditionalList wraps a ListControllerFunc like WrapList, and responds
+// with the Last-Modified time and the ETag of the list, given by the time of
+// the last modification of its resources. The conditional requests of the
+// lists that were not modified since are answered with 304 Not Modified.
+func WrapConditionalList(list ListControllerFunc, fieldsFunc FieldsFunc, modifiedFunc ModifiedFunc) http.HandlerFunc {
+	return wrapList(list, fieldsFunc, modifiedFunc)
+}
+
+func wrapList(list ListControllerFunc, fieldsFunc FieldsFunc, modifiedFunc ModifiedFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var err error
 
@@ -42,6 +85,12 @@ func WrapList(list ListControllerFunc, fieldsFunc FieldsFunc) http.HandlerFunc {
 
 		query := r.URL.Query()
 
+		pred.Ordering, pred.Descending, err = listOrdering(query)
+		if err != nil {
+			WriteError(w, actions.NewError(actions.InvalidArgument, err))
+			return
+		}
+
 		// Determine if we have a label selector
 		var labelSelector *selector.Selector
 		requirements := strings.Join(query["labelSelector"], " && ")
@@ -129,6 +178,14 @@ func WrapList(list ListControllerFunc, fieldsFunc FieldsFunc) http.HandlerFunc {
 			w.Header().Set(corev2.PaginationContinueHeader, encodedContinue)
 		}
 
+		if modifiedFunc != nil {
+			lastModified, etag := listValidators(resources, modifiedFunc)
+			if notModified(w, r, lastModified, etag) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+
 		response := handlers.HandlerResponse{
 			ResourceList: resources,
 		}
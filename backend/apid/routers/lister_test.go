@@ -71,6 +71,24 @@ func TestList(t *testing.T) {
 			expectedStatus:         http.StatusOK,
 			expectedContinueHeader: "YmFy",
 		},
+		{
+			name:           "sorted list",
+			path:           "/foo?sort_by=status&order=desc",
+			results:        []corev3.Resource{corev2.FixtureCheck("check-cpu")},
+			expectedLen:    1,
+			expectedPred:   &store.SelectionPredicate{Ordering: corev2.EventSortSeverity, Descending: true},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid sort_by",
+			path:           "/foo?sort_by=color",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid order",
+			path:           "/foo?sort_by=name&order=up",
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
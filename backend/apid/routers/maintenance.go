@@ -0,0 +1,74 @@
+package routers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/apid/middlewares"
+	"github.com/sensu/sensu-go/backend/authentication/jwt"
+)
+
+// Maintenance is the maintenance mode of the API.
+type Maintenance struct {
+	// ReadOnly is true when the requests with mutating verbs are rejected.
+	ReadOnly bool `json:"read_only"`
+}
+
+// MaintenanceRouter handles requests for /maintenance.
+type MaintenanceRouter struct {
+	mode *middlewares.ReadOnlyMode
+}
+
+// NewMaintenanceRouter instantiates a new router toggling the given read-only
+// mode.
+func NewMaintenanceRouter(mode *middlewares.ReadOnlyMode) *MaintenanceRouter {
+	return &MaintenanceRouter{
+		mode: mode,
+	}
+}
+
+// Mount the MaintenanceRouter on the given parent Router, unless it has no
+// read-only mode to toggle.
+func (r *MaintenanceRouter) Mount(parent *mux.Router) {
+	if r.mode == nil {
+		return
+	}
+	path := "/{resource:" + middlewares.MaintenanceResource + "}"
+	parent.HandleFunc(path, r.get).Methods(http.MethodGet)
+	parent.HandleFunc(path, r.update).Methods(http.MethodPut)
+}
+
+func (r *MaintenanceRouter) get(w http.ResponseWriter, req *http.Request) {
+	r.respond(w)
+}
+
+func (r *MaintenanceRouter) update(w http.ResponseWriter, req *http.Request) {
+	var maintenance Maintenance
+	if err := json.NewDecoder(req.Body).Decode(&maintenance); err != nil {
+		WriteError(w, actions.NewError(actions.InvalidArgument, err))
+		return
+	}
+
+	r.mode.Set(maintenance.ReadOnly)
+	entry := logger.WithField("read_only", maintenance.ReadOnly)
+	if claims := jwt.GetClaimsFromContext(req.Context()); claims != nil {
+		entry = entry.WithField("user", claims.Subject)
+	}
+	entry.Warn("api maintenance mode updated")
+
+	r.respond(w)
+}
+
+func (r *MaintenanceRouter) respond(w http.ResponseWriter) {
+	b, err := json.Marshal(Maintenance{ReadOnly: r.mode.Enabled()})
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(b); err != nil {
+		logger.WithError(err).Error("failed to write response")
+	}
+}
@@ -0,0 +1,38 @@
+package routers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sensu/sensu-go/backend/apid/middlewares"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceRouter(t *testing.T) {
+	mode := middlewares.NewReadOnlyMode(false)
+	parentRouter := mux.NewRouter().PathPrefix("/api/core/v2").Subrouter()
+	NewMaintenanceRouter(mode).Mount(parentRouter)
+
+	request := func(method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/core/v2/maintenance", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		parentRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"read_only":false}`, w.Body.String())
+
+	w = request(http.MethodPut, `{"read_only":true}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"read_only":true}`, w.Body.String())
+	assert.True(t, mode.Enabled())
+
+	w = request(http.MethodPut, `{`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.True(t, mode.Enabled())
+}
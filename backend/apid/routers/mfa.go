@@ -0,0 +1,125 @@
+package routers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication/mfa"
+)
+
+const (
+	// OTPHeader is the header carrying the one-time password, or a recovery
+	// code, of a user logging in.
+	OTPHeader = "Sensu-OTP"
+
+	// MFAHeader is the header telling the clients why a login was rejected
+	// by the multi-factor authentication: "required" when a valid one-time
+	// password is required, or "enrollment-required" when the user must
+	// enroll first.
+	MFAHeader = "Sensu-MFA"
+)
+
+// MFAClient represents the enrollment of the local users in the multi-factor
+// authentication.
+type MFAClient interface {
+	Enroll(ctx context.Context, username, password string) (*mfa.Enrollment, error)
+	Confirm(ctx context.Context, username, password, code string) error
+	Disable(ctx context.Context, username, password, code string) error
+}
+
+// MFARouter handles the enrollment of the local users in the multi-factor
+// authentication. The users are authenticated with basic credentials.
+type MFARouter struct {
+	client MFAClient
+}
+
+// NewMFARouter instantiates a new router.
+func NewMFARouter(client MFAClient) *MFARouter {
+	return &MFARouter{client: client}
+}
+
+// Mount the MFA routes on given mux.Router.
+func (m *MFARouter) Mount(r *mux.Router) {
+	r.HandleFunc("/auth/mfa", m.enroll).Methods(http.MethodPost)
+	r.HandleFunc("/auth/mfa", m.disable).Methods(http.MethodDelete)
+	r.HandleFunc("/auth/mfa/confirm", m.confirm).Methods(http.MethodPost)
+}
+
+// enroll responds with the secret and the recovery codes of a new enrollment,
+// which must be confirmed.
+func (m *MFARouter) enroll(w http.ResponseWriter, r *http.Request) {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	enrollment, err := m.client.Enroll(r.Context(), username, password)
+	if err != nil {
+		writeMFAError(w, username, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(enrollment); err != nil {
+		logger.WithError(err).Error("couldn't write response")
+	}
+}
+
+// confirm confirms an enrollment with a one-time password.
+func (m *MFARouter) confirm(w http.ResponseWriter, r *http.Request) {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	if err := m.client.Confirm(r.Context(), username, password, r.Header.Get(OTPHeader)); err != nil {
+		writeMFAError(w, username, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// disable removes an enrollment, given a one-time password or a recovery code
+// if it is confirmed.
+func (m *MFARouter) disable(w http.ResponseWriter, r *http.Request) {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	if err := m.client.Disable(r.Context(), username, password, r.Header.Get(OTPHeader)); err != nil {
+		writeMFAError(w, username, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeMFAError writes an error of the multi-factor authentication, or of the
+// authentication of the user.
+func writeMFAError(w http.ResponseWriter, username string, err error) {
+	switch err {
+	case mfa.ErrRequired, mfa.ErrInvalidCode:
+		w.Header().Set(MFAHeader, "required")
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case mfa.ErrEnrollmentRequired:
+		w.Header().Set(MFAHeader, "enrollment-required")
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case mfa.ErrLockedOut:
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	case mfa.ErrAlreadyEnrolled:
+		http.Error(w, err.Error(), http.StatusConflict)
+	case mfa.ErrNotEnrolled:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case corev2.ErrUnauthorized:
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	default:
+		logger.WithError(err).WithField("user", username).Error("multi-factor authentication failed")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	logger.WithError(err).WithField("user", username).Info("multi-factor authentication rejected")
+}
@@ -0,0 +1,135 @@
+package routers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication/mfa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockMFAClient struct {
+	mock.Mock
+}
+
+func (m *mockMFAClient) Enroll(ctx context.Context, username, password string) (*mfa.Enrollment, error) {
+	args := m.Called(ctx, username, password)
+	return args.Get(0).(*mfa.Enrollment), args.Error(1)
+}
+
+func (m *mockMFAClient) Confirm(ctx context.Context, username, password, code string) error {
+	return m.Called(ctx, username, password, code).Error(0)
+}
+
+func (m *mockMFAClient) Disable(ctx context.Context, username, password, code string) error {
+	return m.Called(ctx, username, password, code).Error(0)
+}
+
+func TestMFARouter(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		code           string
+		clientFunc     func(*mockMFAClient)
+		wantStatusCode int
+		wantMFAHeader  string
+	}{
+		{
+			name:   "enroll",
+			method: http.MethodPost,
+			path:   "/auth/mfa",
+			clientFunc: func(c *mockMFAClient) {
+				c.On("Enroll", mock.Anything, "foo", "P@ssw0rd!").Return(&mfa.Enrollment{Secret: "JBSWY3DPEHPK3PXP"}, nil)
+			},
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name:   "enroll with invalid credentials",
+			method: http.MethodPost,
+			path:   "/auth/mfa",
+			clientFunc: func(c *mockMFAClient) {
+				c.On("Enroll", mock.Anything, "foo", "P@ssw0rd!").Return((*mfa.Enrollment)(nil), corev2.ErrUnauthorized)
+			},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "enroll while already enrolled",
+			method: http.MethodPost,
+			path:   "/auth/mfa",
+			clientFunc: func(c *mockMFAClient) {
+				c.On("Enroll", mock.Anything, "foo", "P@ssw0rd!").Return((*mfa.Enrollment)(nil), mfa.ErrAlreadyEnrolled)
+			},
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name:   "confirm",
+			method: http.MethodPost,
+			path:   "/auth/mfa/confirm",
+			code:   "123456",
+			clientFunc: func(c *mockMFAClient) {
+				c.On("Confirm", mock.Anything, "foo", "P@ssw0rd!", "123456").Return(nil)
+			},
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:   "confirm with an invalid code",
+			method: http.MethodPost,
+			path:   "/auth/mfa/confirm",
+			code:   "000000",
+			clientFunc: func(c *mockMFAClient) {
+				c.On("Confirm", mock.Anything, "foo", "P@ssw0rd!", "000000").Return(mfa.ErrInvalidCode)
+			},
+			wantStatusCode: http.StatusUnauthorized,
+			wantMFAHeader:  "required",
+		},
+		{
+			name:   "disable",
+			method: http.MethodDelete,
+			path:   "/auth/mfa",
+			code:   "123456",
+			clientFunc: func(c *mockMFAClient) {
+				c.On("Disable", mock.Anything, "foo", "P@ssw0rd!", "123456").Return(nil)
+			},
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:   "disable without enrollment",
+			method: http.MethodDelete,
+			path:   "/auth/mfa",
+			clientFunc: func(c *mockMFAClient) {
+				c.On("Disable", mock.Anything, "foo", "P@ssw0rd!", "").Return(mfa.ErrNotEnrolled)
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := new(mockMFAClient)
+			tt.clientFunc(client)
+			router := NewMFARouter(client)
+
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			req.SetBasicAuth("foo", "P@ssw0rd!")
+			if tt.code != "" {
+				req.Header.Set(OTPHeader, tt.code)
+			}
+
+			res := processRequest(router, req)
+			assert.Equal(t, tt.wantStatusCode, res.Code)
+			assert.Equal(t, tt.wantMFAHeader, res.Header().Get(MFAHeader))
+		})
+	}
+}
+
+func TestMFARouterNoCredentials(t *testing.T) {
+	router := NewMFARouter(new(mockMFAClient))
+
+	req, _ := http.NewRequest(http.MethodPost, "/auth/mfa", nil)
+
+	res := processRequest(router, req)
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+}
@@ -0,0 +1,78 @@
+package routers
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+
+	"github.com/gorilla/mux"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/backend/quota"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// NamespaceQuotasRouter handles requests for NamespaceQuotas.
+type NamespaceQuotasRouter struct {
+	store    storev2.Interface
+	enforcer *quota.Enforcer
+}
+
+// NewNamespaceQuotasRouter instantiates a new router for NamespaceQuotas. The
+// usage of the quotas is reported by the given enforcer.
+func NewNamespaceQuotasRouter(store storev2.Interface, enforcer *quota.Enforcer) *NamespaceQuotasRouter {
+	return &NamespaceQuotasRouter{
+		store:    store,
+		enforcer: enforcer,
+	}
+}
+
+// Mount the NamespaceQuotasRouter on the given parent Router
+func (r *NamespaceQuotasRouter) Mount(parent *mux.Router) {
+	routes := ResourceRoute{
+		Router:     parent,
+		PathPrefix: "/namespaces/{namespace}/{resource:namespacequotas}",
+	}
+
+	handlers := handlers.NewHandlers[*quota.NamespaceQuota](r.store)
+
+	routes.Del(handlers.DeleteResource)
+	routes.Get(handlers.GetResource)
+	routes.Watch(handlers.WatchResources)
+	routes.List(handlers.ListResources, quota.NamespaceQuotaFields)
+	routes.WatchAllNamespaces(handlers.WatchResources, "/{resource:namespacequotas}")
+	routes.ListAllNamespaces(handlers.ListResources, "/{resource:namespacequotas}", quota.NamespaceQuotaFields)
+	routes.Patch(handlers.PatchResource)
+	dryRun(routes.Post(handlers.CreateResource))
+	dryRun(routes.Put(handlers.CreateOrUpdateResource))
+
+	if r.enforcer != nil {
+		parent.HandleFunc(path.Join(routes.PathPrefix, "{id}/usage"), r.usage(handlers)).Methods(http.MethodGet)
+	}
+}
+
+// usage responds with the usage of the namespace of the quota, against the
+// limits of the quota.
+func (r *NamespaceQuotasRouter) usage(h handlers.Handlers[*quota.NamespaceQuota, quota.NamespaceQuota]) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		response, err := h.GetResource(req)
+		if err != nil {
+			WriteError(w, err)
+			return
+		}
+		usage, err := r.enforcer.Usage(req.Context(), response.Resource.(*quota.NamespaceQuota))
+		if err != nil {
+			WriteError(w, err)
+			return
+		}
+
+		b, err := json.Marshal(usage)
+		if err != nil {
+			WriteError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(b); err != nil {
+			logger.WithError(err).Error("failed to write response")
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package routers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/quota"
+	cachev2 "github.com/sensu/sensu-go/backend/store/cache/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNamespaceQuotasRouter(t *testing.T) {
+	// Setup the router
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	ecs := new(mockstore.EntityConfigStore)
+	ss := new(mockstore.SilencesStore)
+	s.On("GetConfigStore").Return(cs)
+	s.On("GetEntityConfigStore").Return(ecs)
+	s.On("GetSilencesStore").Return(ss)
+	enforcer := quota.NewEnforcer(cachev2.NewFromResources([]*quota.NamespaceQuota{}, false), s)
+	router := NewNamespaceQuotasRouter(s, enforcer)
+	parentRouter := mux.NewRouter().PathPrefix("/api/quota/v1").Subrouter()
+	router.Mount(parentRouter)
+
+	empty := &quota.NamespaceQuota{Metadata: &corev2.ObjectMeta{}}
+	fixture := &quota.NamespaceQuota{
+		Metadata:  corev2.NewObjectMetaP("foo", "default"),
+		MaxChecks: 10,
+	}
+
+	tests := []routerTestCase{}
+	tests = append(tests, getTestCases[*quota.NamespaceQuota](fixture)...)
+	tests = append(tests, listTestCases[*quota.NamespaceQuota](empty)...)
+	tests = append(tests, createTestCases(fixture)...)
+	tests = append(tests, updateTestCases(fixture)...)
+	tests = append(tests, deleteTestCases(fixture)...)
+	tests = append(tests, routerTestCase{
+		name:   "it returns the usage of a quota",
+		method: http.MethodGet,
+		path:   fixture.URIPath() + "/usage",
+		storeFunc: func(s *mockstore.V2MockStore) {
+			cs.On("Get", mock.Anything, mock.Anything).
+				Return(mockstore.Wrapper[*quota.NamespaceQuota]{Value: fixture}, nil).
+				Once()
+			cs.On("Count", mock.Anything, mock.Anything).Return(2, nil).Once()
+			ecs.On("Count", mock.Anything, mock.Anything, "").Return(1, nil).Once()
+			ss.On("GetSilences", mock.Anything, mock.Anything).Return([]*corev2.Silenced{}, nil).Once()
+		},
+		wantStatusCode: http.StatusOK,
+	})
+	for _, tt := range tests {
+		run(t, tt, parentRouter, s)
+	}
+}
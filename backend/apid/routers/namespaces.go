@@ -3,11 +3,14 @@ package routers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/url"
 
 	"github.com/gorilla/mux"
 	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/core/v3/types"
 	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/apid/handlers"
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
@@ -91,9 +94,9 @@ func (r *NamespacesRouter) list(ctx context.Context, pred *store.SelectionPredic
 
 func (r *NamespacesRouter) create(req *http.Request) (handlers.HandlerResponse, error) {
 	ctx := req.Context()
-	var ns corev3.Namespace
 	var response handlers.HandlerResponse
-	if err := json.NewDecoder(req.Body).Decode(&ns); err != nil {
+	ns, err := decodeNamespace(req)
+	if err != nil {
 		return response, actions.NewError(actions.InvalidArgument, err)
 	}
 	meta := ns.GetMetadata()
@@ -101,13 +104,13 @@ func (r *NamespacesRouter) create(req *http.Request) (handlers.HandlerResponse,
 		meta.CreatedBy = claims.StandardClaims.Subject
 		ns.Metadata = meta
 	}
-	if err := handlers.CheckMeta(&ns, mux.Vars(req), "id"); err != nil {
+	if err := handlers.CheckMeta(ns, mux.Vars(req), "id"); err != nil {
 		return response, actions.NewError(actions.InvalidArgument, err)
 	}
 	if err := ns.Validate(); err != nil {
 		return response, actions.NewError(actions.InvalidArgument, err)
 	}
-	if err := r.client.CreateNamespace(ctx, &ns); err != nil {
+	if err := r.client.CreateNamespace(ctx, ns); err != nil {
 		switch err := err.(type) {
 		case *store.ErrAlreadyExists:
 			return response, actions.NewErrorf(actions.AlreadyExistsErr)
@@ -122,9 +125,9 @@ func (r *NamespacesRouter) create(req *http.Request) (handlers.HandlerResponse,
 
 func (r *NamespacesRouter) update(req *http.Request) (handlers.HandlerResponse, error) {
 	ctx := req.Context()
-	var ns corev3.Namespace
 	var response handlers.HandlerResponse
-	if err := json.NewDecoder(req.Body).Decode(&ns); err != nil {
+	ns, err := decodeNamespace(req)
+	if err != nil {
 		return response, actions.NewError(actions.InvalidArgument, err)
 	}
 	meta := ns.GetMetadata()
@@ -132,13 +135,13 @@ func (r *NamespacesRouter) update(req *http.Request) (handlers.HandlerResponse,
 		meta.CreatedBy = claims.StandardClaims.Subject
 		ns.Metadata = meta
 	}
-	if err := handlers.CheckMeta(&ns, mux.Vars(req), "id"); err != nil {
+	if err := handlers.CheckMeta(ns, mux.Vars(req), "id"); err != nil {
 		return response, actions.NewError(actions.InvalidArgument, err)
 	}
 	if err := ns.Validate(); err != nil {
 		return response, actions.NewError(actions.InvalidArgument, err)
 	}
-	if err := r.client.UpdateNamespace(ctx, &ns); err != nil {
+	if err := r.client.UpdateNamespace(ctx, ns); err != nil {
 		switch err := err.(type) {
 		case *store.ErrNotValid:
 			return response, actions.NewError(actions.InvalidArgument, err)
@@ -168,3 +171,27 @@ func (r *NamespacesRouter) delete(req *http.Request) (handlers.HandlerResponse,
 
 	return response, nil
 }
+
+// decodeNamespace decodes the namespace of the request body, which can be
+// wrapped, as sent by sensuctl, or not.
+func decodeNamespace(req *http.Request) (*corev3.Namespace, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	var ns *corev3.Namespace
+	var wrapper types.Wrapper
+	if err := json.Unmarshal(body, &wrapper); err == nil {
+		ns, _ = wrapper.Value.(*corev3.Namespace)
+	}
+	if ns == nil {
+		ns = new(corev3.Namespace)
+		if err := json.Unmarshal(body, ns); err != nil {
+			return nil, err
+		}
+	}
+	if ns.Metadata == nil {
+		return nil, errors.New("nil metadata")
+	}
+	return ns, nil
+}
@@ -35,6 +35,7 @@ func TestNamespacesRouter(t *testing.T) {
 	//nsClient.EXPECT().ListNamespaces(gomock.Any(), gomock.Any()).Return([]*corev3.Namespace{corev3.FixtureNamespace("default")}, nil)
 	//nsClient.EXPECT().CreateNamespace(gomock.Any(), gomock.Any()).Return(nil)
 	//nsClient.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).Return(nil)
+	nsClient.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).Return(nil).Times(2)
 	//nsClient.EXPECT().DeleteNamespace(gomock.Any(), gomock.Any()).Return(nil)
 	nsClient.EXPECT().FetchNamespace(gomock.Any(), "default").Return(corev3.FixtureNamespace("default"), nil)
 	nsClient.EXPECT().FetchNamespace(gomock.Any(), "other").Return(nil, &store.ErrNotFound{})
@@ -66,6 +67,24 @@ func TestNamespacesRouter(t *testing.T) {
 			Path:      "/namespaces/broken",
 			ExpStatus: http.StatusInternalServerError,
 		},
+		{
+			Method:    "PUT",
+			Path:      "/namespaces/default",
+			Body:      []byte(`{"metadata": {"name": "default"}}`),
+			ExpStatus: http.StatusCreated,
+		},
+		{
+			Method:    "PUT",
+			Path:      "/namespaces/default",
+			Body:      []byte(`{"type": "Namespace", "api_version": "core/v3", "spec": {"metadata": {"name": "default"}}}`),
+			ExpStatus: http.StatusCreated,
+		},
+		{
+			Method:    "PUT",
+			Path:      "/namespaces/default",
+			Body:      []byte(`{}`),
+			ExpStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, test := range tests {
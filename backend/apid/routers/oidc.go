@@ -0,0 +1,341 @@
+package routers
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/backend/authentication/providers/oidc"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	utilstrings "github.com/sensu/sensu-go/util/strings"
+)
+
+const (
+	// OIDCPathPrefix is the path of the OIDC login flow in the API.
+	OIDCPathPrefix = "/api/authentication/v2/oidc"
+
+	// oidcSessionCookie is the cookie holding the state of the authorization
+	// code flow, between the authorization request and the callback.
+	oidcSessionCookie = "sensu_oidc_session"
+
+	// oidcSessionMaxAge is the number of seconds the users have to log in to
+	// the OIDC server.
+	oidcSessionMaxAge = 600
+
+	// oidcRefreshWindow is the time after the expiration of the access token
+	// of an OIDC user during which it can be refreshed. The user has to log
+	// in again afterwards.
+	oidcRefreshWindow = 24 * time.Hour
+)
+
+// OIDCRouter handles the login flow of the OIDC authentication providers. The
+// users are redirected to the OIDC server by the authorize route, which
+// redirects them back to the callback route, where they are issued their
+// access token. Their refresh token is the refresh token of the OIDC server,
+// which the token route exchanges for a new access token. The refresh tokens
+// are bound to the access tokens by the sessions of the users, identified by
+// the hash of their refresh token.
+type OIDCRouter struct {
+	store storev2.Interface
+}
+
+// NewOIDCRouter instantiates a new router for the OIDC login flow.
+func NewOIDCRouter(store storev2.Interface) *OIDCRouter {
+	return &OIDCRouter{store: store}
+}
+
+// Mount the OIDC routes on the given parent Router.
+func (r *OIDCRouter) Mount(parent *mux.Router) {
+	parent.HandleFunc(OIDCPathPrefix+"/token", r.token).Methods(http.MethodPost)
+	parent.HandleFunc(OIDCPathPrefix+"/{provider}/authorize", r.authorize).Methods(http.MethodGet)
+	parent.HandleFunc(OIDCPathPrefix+"/{provider}/callback", r.callback).Methods(http.MethodGet)
+}
+
+func (r *OIDCRouter) provider(ctx context.Context, name string) (*oidc.Provider, error) {
+	name, err := url.PathUnescape(name)
+	if err != nil {
+		return nil, err
+	}
+	return storev2.Of[*oidc.Provider](r.store).Get(ctx, storev2.ID{Name: name})
+}
+
+// authorize redirects the user agent to the OIDC server. The optional
+// redirect parameter is the path the user agent is redirected to once logged
+// in, with the tokens in the URL fragment.
+func (r *OIDCRouter) authorize(w http.ResponseWriter, req *http.Request) {
+	provider, err := r.provider(req.Context(), mux.Vars(req)["provider"])
+	if err != nil {
+		logger.WithError(err).Error("could not get the oidc provider")
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	redirect := req.URL.Query().Get("redirect")
+	if redirect != "" && !isLocalPath(redirect) {
+		http.Error(w, "the redirect must be a path", http.StatusBadRequest)
+		return
+	}
+
+	session, err := oidc.NewSession()
+	if err != nil {
+		logger.WithError(err).Error("could not start the oidc login")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	session.Redirect = redirect
+	authURL, err := provider.AuthCodeURL(req.Context(), session)
+	if err != nil {
+		logger.WithError(err).Error("could not start the oidc login")
+		http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+		return
+	}
+	value, err := json.Marshal(session)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	setOIDCSessionCookie(w, req, base64.RawURLEncoding.EncodeToString(value), oidcSessionMaxAge)
+	http.Redirect(w, req, authURL, http.StatusFound)
+}
+
+// callback issues the access token of the user redirected by the OIDC server.
+func (r *OIDCRouter) callback(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	if oidcErr := query.Get("error"); oidcErr != "" {
+		logger.WithField("error", oidcErr).WithField("description", query.Get("error_description")).
+			Error("the oidc server refused the login")
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	// The session is only used once
+	session, err := oidcSession(req)
+	setOIDCSessionCookie(w, req, "", -1)
+	if err != nil || !session.ValidState(query.Get("state")) {
+		logger.WithError(err).Error("invalid oidc login state")
+		http.Error(w, "invalid login state", http.StatusBadRequest)
+		return
+	}
+
+	provider, err := r.provider(req.Context(), mux.Vars(req)["provider"])
+	if err != nil {
+		logger.WithError(err).Error("could not get the oidc provider")
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	tokens, err := provider.Exchange(req.Context(), query.Get("code"), session)
+	if err != nil {
+		logger.WithError(err).Error("oidc login failed")
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	result, err := accessTokens(req, tokens)
+	if err != nil {
+		logger.WithError(err).Error("could not issue an access token")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if err := r.addSession(req.Context(), tokens, provider.Name(), time.Now().Unix()); err != nil {
+		logger.WithError(err).Error("could not record the session")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	logger.WithField("subject", tokens.Claims.Subject).WithField("groups", tokens.Claims.Groups).
+		WithField("provider_id", provider.Name()).Info("login successful")
+
+	if session.Redirect == "" {
+		writeTokens(w, result)
+		return
+	}
+	fragment := url.Values{
+		"access_token":  {result.Access},
+		"expires_at":    {strconv.FormatInt(result.ExpiresAt, 10)},
+		"refresh_token": {result.Refresh},
+	}
+	http.Redirect(w, req, session.Redirect+"#"+fragment.Encode(), http.StatusFound)
+}
+
+// token issues a new access token to an OIDC user, given its access token,
+// expired for less than oidcRefreshWindow, and the refresh token of the OIDC
+// server of its session.
+func (r *OIDCRouter) token(w http.ResponseWriter, req *http.Request) {
+	accessToken, err := jwt.ValidateExpiredToken(jwt.ExtractBearerToken(req))
+	if err != nil {
+		http.Error(w, "invalid access token", http.StatusUnauthorized)
+		return
+	}
+	claims, err := jwt.GetClaims(accessToken)
+	if err != nil || claims.Provider.ProviderType != oidc.Type {
+		http.Error(w, "invalid access token", http.StatusBadRequest)
+		return
+	}
+	now := time.Now()
+	if time.Unix(claims.ExpiresAt, 0).Add(oidcRefreshWindow).Before(now) {
+		http.Error(w, "the access token expired too long ago", http.StatusUnauthorized)
+		return
+	}
+	var payload corev2.Tokens
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		http.Error(w, "invalid refresh token", http.StatusBadRequest)
+		return
+	}
+
+	// The refresh token must be the one of the session of the access token,
+	// and the session must not be revoked
+	if claims.SessionID == "" || subtle.ConstantTimeCompare([]byte(oidcSessionID(payload.Refresh)), []byte(claims.SessionID)) != 1 {
+		http.Error(w, "invalid refresh token", http.StatusUnauthorized)
+		return
+	}
+	sessions := r.store.GetSessionStore()
+	expiresAt := now.Add(oidcRefreshWindow).Unix()
+	if err := sessions.RefreshSession(req.Context(), claims.SessionID, now.Unix(), expiresAt); err != nil {
+		if _, ok := err.(*store.ErrNotFound); ok {
+			http.Error(w, "the refresh token was revoked", http.StatusUnauthorized)
+			return
+		}
+		logger.WithError(err).Error("could not refresh the session")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	provider, err := r.provider(req.Context(), claims.Provider.ProviderID)
+	if err != nil {
+		logger.WithError(err).Error("could not get the oidc provider")
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	tokens, err := provider.RefreshTokens(req.Context(), claims, payload.Refresh)
+	if err != nil {
+		logger.WithError(err).WithField("user", claims.Subject).Info("could not refresh the oidc tokens")
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	result, err := accessTokens(req, tokens)
+	if err != nil {
+		logger.WithError(err).Error("could not issue an access token")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	// The session follows the refresh token when the OIDC server rotates it
+	if tokens.Claims.SessionID != claims.SessionID {
+		if _, err := sessions.RevokeSessions(req.Context(), claims.Subject, claims.SessionID); err != nil {
+			logger.WithError(err).Error("could not revoke the session of the previous refresh token")
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if err := r.addSession(req.Context(), tokens, claims.Provider.ProviderID, now.Unix()); err != nil {
+			logger.WithError(err).Error("could not record the session")
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+	}
+	writeTokens(w, result)
+}
+
+// addSession records the session of the refresh token of an OIDC user, so
+// that it can be listed and revoked. There is no session without refresh
+// token.
+func (r *OIDCRouter) addSession(ctx context.Context, tokens *oidc.Tokens, provider string, issuedAt int64) error {
+	if tokens.RefreshToken == "" {
+		return nil
+	}
+	return r.store.GetSessionStore().AddSession(ctx, &storev2.Session{
+		ID:          tokens.Claims.SessionID,
+		Username:    tokens.Claims.Subject,
+		Provider:    provider,
+		IssuedAt:    issuedAt,
+		RefreshedAt: issuedAt,
+		ExpiresAt:   tokens.Claims.ExpiresAt + int64(oidcRefreshWindow/time.Second),
+	})
+}
+
+// oidcSessionID returns the ID of the session of a refresh token of an OIDC
+// server.
+func oidcSessionID(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])
+}
+
+// accessTokens issues the access token of an OIDC user, bound to the session
+// of its refresh token.
+func accessTokens(req *http.Request, tokens *oidc.Tokens) (*corev2.Tokens, error) {
+	claims := tokens.Claims
+	if !utilstrings.InArray("system:users", claims.Groups) {
+		claims.Groups = append(claims.Groups, "system:users")
+	}
+	if tokens.RefreshToken != "" {
+		claims.SessionID = oidcSessionID(tokens.RefreshToken)
+	}
+	claims.Issuer = issuerURL(req)
+	_, accessToken, err := jwt.AccessToken(claims)
+	if err != nil {
+		return nil, err
+	}
+	return &corev2.Tokens{
+		Access:    accessToken,
+		ExpiresAt: claims.ExpiresAt,
+		Refresh:   tokens.RefreshToken,
+	}, nil
+}
+
+func writeTokens(w http.ResponseWriter, tokens *corev2.Tokens) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(tokens); err != nil {
+		logger.WithError(err).Error("couldn't write response body")
+	}
+}
+
+func oidcSession(req *http.Request) (oidc.Session, error) {
+	var session oidc.Session
+	cookie, err := req.Cookie(oidcSessionCookie)
+	if err != nil {
+		return session, err
+	}
+	value, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+	if err != nil {
+		return session, err
+	}
+	if err := json.Unmarshal(value, &session); err != nil {
+		return session, err
+	}
+	if session.Redirect != "" && !isLocalPath(session.Redirect) {
+		return session, errors.New("the redirect must be a path")
+	}
+	return session, nil
+}
+
+func setOIDCSessionCookie(w http.ResponseWriter, req *http.Request, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcSessionCookie,
+		Value:    value,
+		Path:     OIDCPathPrefix,
+		MaxAge:   maxAge,
+		Secure:   req.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// isLocalPath returns whether the redirect is a path of the host serving the
+// request, so that the tokens are never sent to another host.
+func isLocalPath(redirect string) bool {
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.ContainsAny(redirect, "\\#") {
+		return false
+	}
+	u, err := url.Parse(redirect)
+	return err == nil && u.Scheme == "" && u.Host == ""
+}
@@ -0,0 +1,199 @@
+package routers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v4"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	"github.com/sensu/sensu-go/backend/authentication/providers/oidc"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newOIDCRouter(t *testing.T) (*OIDCRouter, *mockstore.SessionStore) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issuer := "http://" + r.Host
+		if r.URL.Path == "/token" {
+			// The refresh tokens are rotated, without ID token
+			if r.PostFormValue("refresh_token") != "refresh1" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"access","refresh_token":"refresh2"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer,
+			"authorization_endpoint": issuer + "/authorize",
+			"token_endpoint":         issuer + "/token",
+			"jwks_uri":               issuer + "/jwks",
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	provider := &oidc.Provider{
+		Metadata:     corev2.NewObjectMetaP("okta", ""),
+		Server:       server.URL,
+		ClientID:     "sensu",
+		ClientSecret: "secret",
+		RedirectURI:  "https://sensu.example.com/api/authentication/v2/oidc/okta/callback",
+	}
+	s := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	s.On("GetConfigStore").Return(cs)
+	cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*oidc.Provider]{Value: provider}, nil)
+	sessions := new(mockstore.SessionStore)
+	s.On("GetSessionStore").Return(sessions)
+	return NewOIDCRouter(s), sessions
+}
+
+func TestOIDCAuthorize(t *testing.T) {
+	router, _ := newOIDCRouter(t)
+
+	req, _ := http.NewRequest(http.MethodGet, OIDCPathPrefix+"/okta/authorize?redirect=/events", nil)
+	res := processRequest(router, req)
+	require.Equal(t, http.StatusFound, res.Code)
+
+	location, err := url.Parse(res.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "/authorize", location.Path)
+	assert.NotEmpty(t, location.Query().Get("code_challenge"))
+
+	// The state of the flow is kept in a cookie, without the verifier being
+	// sent to the OIDC server
+	cookies := res.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.True(t, cookies[0].HttpOnly)
+	req.AddCookie(cookies[0])
+	session, err := oidcSession(req)
+	require.NoError(t, err)
+	assert.Equal(t, location.Query().Get("state"), session.State)
+	assert.Equal(t, location.Query().Get("code_challenge"), session.Challenge())
+	assert.Equal(t, "/events", session.Redirect)
+}
+
+func TestOIDCAuthorizeRedirectToOtherHost(t *testing.T) {
+	router, _ := newOIDCRouter(t)
+
+	req, _ := http.NewRequest(http.MethodGet, OIDCPathPrefix+"/okta/authorize?redirect=//evil.example.com", nil)
+	res := processRequest(router, req)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestOIDCCallbackInvalidState(t *testing.T) {
+	router, _ := newOIDCRouter(t)
+
+	req, _ := http.NewRequest(http.MethodGet, OIDCPathPrefix+"/okta/authorize", nil)
+	res := processRequest(router, req)
+	require.Equal(t, http.StatusFound, res.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, OIDCPathPrefix+"/okta/callback?code=code&state=forged", nil)
+	req.AddCookie(res.Result().Cookies()[0])
+	res = processRequest(router, req)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestOIDCTokenInvalidAccessToken(t *testing.T) {
+	router, _ := newOIDCRouter(t)
+
+	req, _ := http.NewRequest(http.MethodPost, OIDCPathPrefix+"/token", nil)
+	req.Header.Set("Authorization", "Bearer invalid")
+	res := processRequest(router, req)
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+}
+
+// oidcAccessToken returns an access token of an OIDC user, expired at the
+// given time, bound to the session of the given refresh token.
+func oidcAccessToken(t *testing.T, expiresAt time.Time, refreshToken string) string {
+	t.Helper()
+	claims := &corev2.Claims{
+		StandardClaims: corev2.StandardClaims("okta:jane@example.com"),
+		Groups:         []string{"okta:ops", "system:users"},
+		Provider:       corev2.AuthProviderClaims{ProviderID: "okta", ProviderType: oidc.Type, UserID: "1234"},
+		SessionID:      oidcSessionID(refreshToken),
+	}
+	claims.ExpiresAt = expiresAt.Unix()
+	token, err := jwtgo.NewWithClaims(jwtgo.SigningMethodHS256, claims).SignedString([]byte("oidc-test-secret"))
+	require.NoError(t, err)
+	return token
+}
+
+func TestOIDCToken(t *testing.T) {
+	jwt.SetSecret([]byte("oidc-test-secret"))
+	refresh := func(router *OIDCRouter, accessToken, refreshToken string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(corev2.Tokens{Refresh: refreshToken})
+		req, _ := http.NewRequest(http.MethodPost, OIDCPathPrefix+"/token", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		return processRequest(router, req)
+	}
+
+	t.Run("refresh", func(t *testing.T) {
+		router, sessions := newOIDCRouter(t)
+		sessions.On("RefreshSession", mock.Anything, oidcSessionID("refresh1"), mock.Anything, mock.Anything).Return(nil)
+		sessions.On("RevokeSessions", mock.Anything, "okta:jane@example.com", []string{oidcSessionID("refresh1")}).Return(1, nil)
+		sessions.On("AddSession", mock.Anything, mock.MatchedBy(func(s *storev2.Session) bool {
+			return s.ID == oidcSessionID("refresh2") && s.Username == "okta:jane@example.com"
+		})).Return(nil)
+
+		res := refresh(router, oidcAccessToken(t, time.Now().Add(-time.Hour), "refresh1"), "refresh1")
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		var tokens corev2.Tokens
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &tokens))
+		assert.Equal(t, "refresh2", tokens.Refresh)
+		token, err := jwt.ValidateToken(tokens.Access)
+		require.NoError(t, err)
+		claims, err := jwt.GetClaims(token)
+		require.NoError(t, err)
+		assert.Equal(t, "okta:jane@example.com", claims.Subject)
+		assert.Equal(t, []string{"okta:ops", "system:users"}, claims.Groups)
+		assert.Equal(t, oidcSessionID("refresh2"), claims.SessionID)
+		sessions.AssertExpectations(t)
+	})
+
+	t.Run("refresh token of another session", func(t *testing.T) {
+		router, _ := newOIDCRouter(t)
+		res := refresh(router, oidcAccessToken(t, time.Now().Add(-time.Hour), "other"), "refresh1")
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+
+	t.Run("access token expired too long ago", func(t *testing.T) {
+		router, _ := newOIDCRouter(t)
+		res := refresh(router, oidcAccessToken(t, time.Now().Add(-oidcRefreshWindow-time.Minute), "refresh1"), "refresh1")
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+
+	t.Run("revoked session", func(t *testing.T) {
+		router, sessions := newOIDCRouter(t)
+		sessions.On("RefreshSession", mock.Anything, oidcSessionID("refresh1"), mock.Anything, mock.Anything).
+			Return(&store.ErrNotFound{Key: oidcSessionID("refresh1")})
+		res := refresh(router, oidcAccessToken(t, time.Now().Add(-time.Hour), "refresh1"), "refresh1")
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+}
+
+func TestIsLocalPath(t *testing.T) {
+	tests := map[string]bool{
+		"/":                          true,
+		"/events?namespace=default":  true,
+		"events":                     false,
+		"//evil.example.com":         false,
+		"/\\evil.example.com":        false,
+		"https://evil.example.com/":  false,
+		"/events#access_token=forge": false,
+	}
+	for redirect, want := range tests {
+		assert.Equal(t, want, isLocalPath(redirect), redirect)
+	}
+}
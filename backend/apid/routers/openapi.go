@@ -0,0 +1,54 @@
+package routers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/sensu/sensu-go/backend/apid/openapi"
+)
+
+// OpenAPIRouter handles requests for /api/openapi.json
+type OpenAPIRouter struct {
+	root    *mux.Router
+	version string
+
+	once     sync.Once
+	document []byte
+	err      error
+}
+
+// NewOpenAPIRouter instantiates a new router serving the OpenAPI document of
+// the routes of the given root router.
+func NewOpenAPIRouter(root *mux.Router, version string) *OpenAPIRouter {
+	return &OpenAPIRouter{
+		root:    root,
+		version: version,
+	}
+}
+
+// Mount the OpenAPIRouter to a parent Router
+func (r *OpenAPIRouter) Mount(parent *mux.Router) {
+	parent.HandleFunc("/api/openapi.json", r.openAPI).Methods(http.MethodGet)
+}
+
+func (r *OpenAPIRouter) openAPI(w http.ResponseWriter, _ *http.Request) {
+	// The document is generated on the first request, once every route has
+	// been mounted on the root router.
+	r.once.Do(func() {
+		var document *openapi.Document
+		if document, r.err = openapi.Generate(r.root, r.version); r.err == nil {
+			r.document, r.err = json.Marshal(document)
+		}
+	})
+	if r.err != nil {
+		WriteError(w, r.err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(r.document); err != nil {
+		logger.WithError(err).Error("failed to write response")
+	}
+}
@@ -0,0 +1,37 @@
+package routers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestOpenAPIRouter(t *testing.T) {
+	router := mux.NewRouter()
+	NewOpenAPIRouter(router, "1.2.3").Mount(router)
+	// Routes mounted after the document router must be described as well
+	router.HandleFunc("/api/core/v2/{resource:users}", func(http.ResponseWriter, *http.Request) {}).Methods(http.MethodGet)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("bad status: %d", w.Code)
+	}
+
+	var document struct {
+		OpenAPI string                     `json:"openapi"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &document); err != nil {
+		t.Fatal(err)
+	}
+	if document.OpenAPI == "" {
+		t.Error("expected the openapi version")
+	}
+	if _, ok := document.Paths["/api/core/v2/users"]; !ok {
+		t.Errorf("expected the users route to be described, got %v", document.Paths)
+	}
+}
@@ -30,10 +30,15 @@ func (r *PipelinesRouter) Mount(parent *mux.Router) {
 	handlers := handlers.NewHandlers[*corev2.Pipeline](r.store)
 
 	routes.Get(handlers.GetResource)
+	routes.Watch(handlers.WatchResources)
 	routes.List(handlers.ListResources, corev3.PipelineFields)
+	routes.WatchAllNamespaces(handlers.WatchResources, "/{resource:pipelines}")
 	routes.ListAllNamespaces(handlers.ListResources, "/{resource:pipelines}", corev3.PipelineFields)
 	routes.Patch(handlers.PatchResource)
-	routes.Post(handlers.CreateResource)
-	routes.Put(handlers.CreateOrUpdateResource)
+	dryRun(routes.Post(handlers.CreateResource))
+	dryRun(routes.Put(handlers.CreateOrUpdateResource))
+	routes.Bulk(handlers.BulkCreateResources, handlers.BulkCreateOrUpdateResources, handlers.BulkDeleteResources)
+	routes.Count(handlers.CountResources)
+	routes.History(handlers.ListResourceVersions, handlers.GetResourceVersion, handlers.DiffResourceVersion, handlers.RollbackResource)
 	routes.Del(handlers.DeleteResource)
 }
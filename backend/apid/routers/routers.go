@@ -12,14 +12,10 @@ import (
 	"github.com/sensu/core/v3/types"
 	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/backend/apid/middlewares"
 	"github.com/sensu/sensu-go/backend/store"
 )
 
-type errorBody struct {
-	Message string `json:"message"`
-	Code    uint32 `json:"code"`
-}
-
 // RespondWith given writer and resource, marshal to JSON and write response.
 func RespondWith(w http.ResponseWriter, r *http.Request, response handlers.HandlerResponse) {
 	// Set content-type to JSON
@@ -28,6 +24,10 @@ func RespondWith(w http.ResponseWriter, r *http.Request, response handlers.Handl
 	var etag string
 	if response.Resource != nil {
 		etag = response.Resource.GetMetadata().Annotations[store.SensuETagKey]
+	} else if info := response.TxInfo.Records; len(info) > 0 && len(info[0].ETag) > 0 {
+		// Writes respond with the etag of the written resource, so that
+		// clients can make conditional requests without reading it again.
+		etag = info[0].ETag.String()
 	}
 
 	if etag != "" {
@@ -79,25 +79,28 @@ func RespondWith(w http.ResponseWriter, r *http.Request, response handlers.Handl
 	}
 }
 
-// WriteError writes error response in JSON format.
+// WriteError writes error response in JSON format. The request ID assigned by
+// the RequestID middleware, if any, is included so that the error can be
+// correlated with the backend logs.
 func WriteError(w http.ResponseWriter, err error) {
 	const fallback = `{"message": "failed to marshal error message"}`
 
-	errBody := errorBody{}
-	st := http.StatusInternalServerError
-
-	// Wrap message in standard errorBody
+	// Wrap message in standard error body
 	actionErr, ok := err.(actions.Error)
-	if ok {
-		errBody.Message = actionErr.Message
-		errBody.Code = uint32(actionErr.Code)
-		st = HTTPStatusFromCode(actionErr.Code)
-	} else {
-		errBody.Message = err.Error()
+	if !ok {
+		actionErr = actions.NewError(actions.InternalErr, err)
+	}
+	requestID := w.Header().Get(middlewares.RequestIDHeader)
+	errBody := actionErr.Body(requestID)
+	st := HTTPStatusFromCode(actionErr.Code)
+
+	if st >= http.StatusInternalServerError {
+		logger.WithError(err).WithField("request_id", requestID).Error("request failed")
 	}
 
 	// Prevent browser from doing mime-sniffing
 	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Type", "application/json")
 
 	// Marshall error message to JSON
 	errJSON, err := json.Marshal(errBody)
@@ -140,6 +143,10 @@ func HTTPStatusFromCode(code actions.ErrCode) int {
 		return http.StatusGatewayTimeout
 	case actions.Gone:
 		return http.StatusGone
+	case actions.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case actions.Unavailable:
+		return http.StatusServiceUnavailable
 	}
 
 	logger.WithField("code", code).Error("unknown error code")
@@ -175,6 +182,99 @@ func actionHandler(action actionHandlerFunc) http.HandlerFunc {
 	}
 }
 
+// bulkResult is the outcome of one of the operations of a bulk request, as
+// written in the response.
+type bulkResult struct {
+	Name   string             `json:"name"`
+	Status int                `json:"status"`
+	Error  *actions.ErrorBody `json:"error,omitempty"`
+}
+
+// bulkHandler takes a bulk action and responds with the HTTP status and error
+// of each of its operations.
+func bulkHandler(action bulkHandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		results, err := action(r)
+		if err != nil {
+			WriteError(w, err)
+			return
+		}
+
+		response := make([]bulkResult, len(results))
+		for i, result := range results {
+			response[i] = bulkResult{Name: result.Name, Status: http.StatusOK}
+			if result.Err == nil {
+				continue
+			}
+			actionErr, ok := result.Err.(actions.Error)
+			if !ok {
+				actionErr = actions.NewError(actions.InternalErr, result.Err)
+			}
+			body := actionErr.Body(w.Header().Get(middlewares.RequestIDHeader))
+			response[i].Status = HTTPStatusFromCode(actionErr.Code)
+			response[i].Error = &body
+		}
+
+		b, err := json.Marshal(response)
+		if err != nil {
+			WriteError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(b); err != nil {
+			logger.WithError(err).Error("failed to write response")
+		}
+	}
+}
+
+// countResult is the response of the count requests.
+type countResult struct {
+	Count int `json:"count"`
+}
+
+// countHandler takes a count action and responds with the count.
+func countHandler(action countHandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		count, err := action(r)
+		if err != nil {
+			WriteError(w, err)
+			return
+		}
+
+		b, err := json.Marshal(countResult{Count: count})
+		if err != nil {
+			WriteError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(b); err != nil {
+			logger.WithError(err).Error("failed to write response")
+		}
+	}
+}
+
+// historyHandler takes a history action and responds with its result, encoded
+// as JSON.
+func historyHandler(action historyHandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := action(r)
+		if err != nil {
+			WriteError(w, err)
+			return
+		}
+
+		b, err := json.Marshal(result)
+		if err != nil {
+			WriteError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(b); err != nil {
+			logger.WithError(err).Error("failed to write response")
+		}
+	}
+}
+
 // listHandler is still used by silenced entries.
 // TODO(palourde): Add pagination to silenced entries
 func listHandler(fn listHandlerFunc) http.HandlerFunc {
@@ -193,6 +293,12 @@ type actionHandlerFunc func(r *http.Request) (handlers.HandlerResponse, error)
 
 type listHandlerFunc func(w http.ResponseWriter, req *http.Request) ([]corev3.Resource, error)
 
+type bulkHandlerFunc func(r *http.Request) ([]handlers.BulkResult, error)
+
+type countHandlerFunc func(r *http.Request) (int, error)
+
+type historyHandlerFunc func(r *http.Request) (interface{}, error)
+
 // ResourceRoute mounts resources in a convetional RESTful manner.
 //
 //	routes := ResourceRoute{PathPrefix: "checks", Router: ...}
@@ -223,6 +329,32 @@ func (r *ResourceRoute) ListAllNamespaces(fn ListControllerFunc, path string, fi
 	return r.Router.HandleFunc(path, WrapList(fn, fields)).Methods(http.MethodGet)
 }
 
+// ListConditional lists resources, answering the conditional requests of
+// the lists that were not modified since with 304 Not Modified.
+func (r *ResourceRoute) ListConditional(fn ListControllerFunc, fields FieldsFunc, modified ModifiedFunc) *mux.Route {
+	return r.Router.HandleFunc(r.PathPrefix, WrapConditionalList(fn, fields, modified)).Methods(http.MethodGet)
+}
+
+// ListAllNamespacesConditional lists resources across all namespaces, like
+// ListConditional.
+func (r *ResourceRoute) ListAllNamespacesConditional(fn ListControllerFunc, path string, fields FieldsFunc, modified ModifiedFunc) *mux.Route {
+	return r.Router.HandleFunc(path, WrapConditionalList(fn, fields, modified)).Methods(http.MethodGet)
+}
+
+// Watch streams the changes of the resources, and must be mounted before List,
+// whose route would otherwise match the watch requests.
+//
+//	GET /checks?watch=true
+func (r *ResourceRoute) Watch(fn watchHandlerFunc) *mux.Route {
+	return r.Router.HandleFunc(r.PathPrefix, watchHandler(fn)).Queries("watch", "true").Methods(http.MethodGet)
+}
+
+// WatchAllNamespaces streams the changes of the resources across all
+// namespaces, and must be mounted before ListAllNamespaces.
+func (r *ResourceRoute) WatchAllNamespaces(fn watchHandlerFunc, path string) *mux.Route {
+	return r.Router.HandleFunc(path, watchHandler(fn)).Queries("watch", "true").Methods(http.MethodGet)
+}
+
 // Patch patches a resource
 func (r *ResourceRoute) Patch(fn actionHandlerFunc) *mux.Route {
 	return r.Path("{id}", fn).Methods(http.MethodPatch)
@@ -245,11 +377,56 @@ func (r *ResourceRoute) Put(fn actionHandlerFunc) *mux.Route {
 	return r.Path("{id}", fn).Methods(http.MethodPut)
 }
 
+// dryRun marks a route as supporting the dry runs of its writes, which the
+// DryRun middleware rejects otherwise.
+func dryRun(route *mux.Route) *mux.Route {
+	return route.Handler(middlewares.DryRunHandler{Handler: route.GetHandler()})
+}
+
 // Del deletes
 func (r *ResourceRoute) Del(fn actionHandlerFunc) *mux.Route {
 	return r.Path("{id}", fn).Methods(http.MethodDelete)
 }
 
+// Bulk mounts the bulk operations on the resources. The "bulk" path segment
+// precedes the resource type, so that the routes do not shadow the resources.
+//
+//	POST /namespaces/:namespace/bulk/checks   creates the resources
+//	PUT /namespaces/:namespace/bulk/checks    creates or updates the resources
+//	DELETE /namespaces/:namespace/bulk/checks deletes the resources
+func (r *ResourceRoute) Bulk(create, createOrUpdate, del bulkHandlerFunc) {
+	bulkPath := path.Join(path.Dir(r.PathPrefix), "bulk", path.Base(r.PathPrefix))
+	r.Router.HandleFunc(bulkPath, bulkHandler(create)).Methods(http.MethodPost)
+	r.Router.HandleFunc(bulkPath, bulkHandler(createOrUpdate)).Methods(http.MethodPut)
+	r.Router.HandleFunc(bulkPath, bulkHandler(del)).Methods(http.MethodDelete)
+}
+
+// Count mounts the count of the resources, which spares the clients from
+// listing them only to display their number. Like for the bulk operations, the
+// "count" path segment precedes the resource type.
+//
+//	GET /namespaces/:namespace/count/checks counts the resources
+func (r *ResourceRoute) Count(fn countHandlerFunc) *mux.Route {
+	countPath := path.Join(path.Dir(r.PathPrefix), "count", path.Base(r.PathPrefix))
+	return r.Router.HandleFunc(countPath, countHandler(fn)).Methods(http.MethodGet)
+}
+
+// History mounts the history of the versions of the resources, with the
+// actions listing them, getting one of them, diffing one of them with another
+// and rolling back to one of them.
+//
+//	GET  /checks/:id/history                   lists the versions
+//	GET  /checks/:id/history/:version          gets a version
+//	GET  /checks/:id/history/:version/diff     diffs a version with the latest one
+//	POST /checks/:id/history/:version/rollback rolls back to a version
+func (r *ResourceRoute) History(list, get, diff historyHandlerFunc, rollback actionHandlerFunc) {
+	historyPath := path.Join(r.PathPrefix, "{id}", "history")
+	r.Router.HandleFunc(historyPath, historyHandler(list)).Methods(http.MethodGet)
+	r.Router.HandleFunc(path.Join(historyPath, "{resource_version}"), historyHandler(get)).Methods(http.MethodGet)
+	r.Router.HandleFunc(path.Join(historyPath, "{resource_version}", "diff"), historyHandler(diff)).Methods(http.MethodGet)
+	handleAction(r.Router, path.Join(historyPath, "{resource_version}", "rollback"), rollback).Methods(http.MethodPost)
+}
+
 // Path adds custom path
 func (r *ResourceRoute) Path(p string, fn actionHandlerFunc) *mux.Route {
 	fullPath := path.Join(r.PathPrefix, p)
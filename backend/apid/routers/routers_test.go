@@ -3,6 +3,8 @@ package routers
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -14,7 +16,9 @@ import (
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/backend/apid/middlewares"
 	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 )
 
 func newRequest(t *testing.T, method, endpoint string, body io.Reader) *http.Request {
@@ -65,6 +69,19 @@ func TestRespondWith(t *testing.T) {
 			},
 			expectETagHeader: true,
 		},
+		{
+			name: "etag added from the record of a write",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("PUT", "/", nil),
+				response: handlers.HandlerResponse{
+					TxInfo: storev2.TxInfo{
+						Records: []storev2.TxRecordInfo{{Updated: true, ETag: storev2.ETag("helloworld")}},
+					},
+				},
+			},
+			expectETagHeader: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -79,19 +96,64 @@ func TestRespondWith(t *testing.T) {
 }
 
 func TestWriteError(t *testing.T) {
-	type args struct {
-		w   http.ResponseWriter
-		err error
-	}
 	tests := []struct {
-		name string
-		args args
+		name       string
+		err        error
+		requestID  string
+		wantStatus int
+		wantBody   actions.ErrorBody
 	}{
-		// TODO: Add test cases.
+		{
+			name:       "action error",
+			err:        actions.NewErrorf(actions.NotFound),
+			requestID:  "abc",
+			wantStatus: http.StatusNotFound,
+			wantBody: actions.ErrorBody{
+				Message:   "not found",
+				Code:      uint32(actions.NotFound),
+				Reason:    "not_found",
+				RequestID: "abc",
+			},
+		},
+		{
+			name:       "plain error",
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantBody: actions.ErrorBody{
+				Message: "boom",
+				Code:    uint32(actions.InternalErr),
+				Reason:  "internal",
+			},
+		},
+		{
+			name:       "field error",
+			err:        actions.NewError(actions.InvalidArgument, actions.NewFieldError("metadata.name", errors.New("bad name"))),
+			wantStatus: http.StatusBadRequest,
+			wantBody: actions.ErrorBody{
+				Message: "bad name",
+				Code:    uint32(actions.InvalidArgument),
+				Reason:  "invalid_argument",
+				Details: []actions.FieldViolation{{Field: "metadata.name", Description: "bad name"}},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			WriteError(tt.args.w, tt.args.err)
+			w := httptest.NewRecorder()
+			if tt.requestID != "" {
+				w.Header().Set(middlewares.RequestIDHeader, tt.requestID)
+			}
+			WriteError(w, tt.err)
+			if w.Code != tt.wantStatus {
+				t.Errorf("WriteError() status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			var got actions.ErrorBody
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.wantBody) {
+				t.Errorf("WriteError() body = %#v, want %#v", got, tt.wantBody)
+			}
 		})
 	}
 }
@@ -333,6 +395,104 @@ func TestResourceRoute_Del(t *testing.T) {
 	}
 }
 
+func TestResourceRoute_Bulk(t *testing.T) {
+	router := mux.NewRouter()
+	routes := ResourceRoute{Router: router, PathPrefix: "/namespaces/{namespace}/{resource:checks}"}
+	fn := func(method string) bulkHandlerFunc {
+		return func(r *http.Request) ([]handlers.BulkResult, error) {
+			return []handlers.BulkResult{
+				{Name: method},
+				{Name: "missing", Err: actions.NewErrorf(actions.NotFound)},
+			}, nil
+		}
+	}
+	routes.Bulk(fn(http.MethodPost), fn(http.MethodPut), fn(http.MethodDelete))
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodDelete} {
+		t.Run(method, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, newRequest(t, method, "/namespaces/default/bulk/checks", nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("bad status: %d", w.Code)
+			}
+			var results []bulkResult
+			if err := json.Unmarshal(w.Body.Bytes(), &results); err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 2 {
+				t.Fatalf("expected 2 results, got %d", len(results))
+			}
+			if got := results[0]; got.Name != method || got.Status != http.StatusOK || got.Error != nil {
+				t.Errorf("bad result: %v", got)
+			}
+			if got := results[1]; got.Status != http.StatusNotFound || got.Error == nil || got.Error.Message != "not found" {
+				t.Errorf("bad result: %v", got)
+			}
+		})
+	}
+}
+
+func TestResourceRoute_Count(t *testing.T) {
+	router := mux.NewRouter()
+	routes := ResourceRoute{Router: router, PathPrefix: "/namespaces/{namespace}/{resource:checks}"}
+	routes.Count(func(r *http.Request) (int, error) {
+		return 42, nil
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newRequest(t, http.MethodGet, "/namespaces/default/count/checks", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("bad status: %d", w.Code)
+	}
+	if got, want := w.Body.String(), `{"count":42}`; got != want {
+		t.Errorf("bad body: got %s, want %s", got, want)
+	}
+}
+
+func TestResourceRoute_History(t *testing.T) {
+	router := mux.NewRouter()
+	routes := ResourceRoute{Router: router, PathPrefix: "/namespaces/{namespace}/{resource:checks}"}
+	version := func(r *http.Request) (interface{}, error) {
+		return mux.Vars(r)["resource_version"], nil
+	}
+	routes.History(
+		func(r *http.Request) (interface{}, error) {
+			return []string{mux.Vars(r)["id"]}, nil
+		},
+		version,
+		func(r *http.Request) (interface{}, error) {
+			return json.RawMessage(`{"interval":60}`), nil
+		},
+		func(r *http.Request) (handlers.HandlerResponse, error) {
+			return handlers.HandlerResponse{}, nil
+		},
+	)
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{http.MethodGet, "/namespaces/default/checks/check/history", http.StatusOK, `["check"]`},
+		{http.MethodGet, "/namespaces/default/checks/check/history/2", http.StatusOK, `"2"`},
+		{http.MethodGet, "/namespaces/default/checks/check/history/2/diff", http.StatusOK, `{"interval":60}`},
+		{http.MethodPost, "/namespaces/default/checks/check/history/2/rollback", http.StatusCreated, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, newRequest(t, tt.method, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("bad status: got %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Body.String(); got != tt.body {
+				t.Errorf("bad body: got %s, want %s", got, tt.body)
+			}
+		})
+	}
+}
+
 func TestResourceRoute_Path(t *testing.T) {
 	type fields struct {
 		Router     *mux.Router
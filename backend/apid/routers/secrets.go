@@ -0,0 +1,52 @@
+package routers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+)
+
+// SecretController represents the controller needs of the SecretsRouter.
+type SecretController interface {
+	Usage(ctx context.Context) ([]actions.SecretUsage, error)
+}
+
+// SecretsRouter handles requests for the usage of the secrets.
+type SecretsRouter struct {
+	controller SecretController
+}
+
+// NewSecretsRouter instantiates a new router for the usage of the secrets.
+func NewSecretsRouter(ctrl SecretController) *SecretsRouter {
+	return &SecretsRouter{
+		controller: ctrl,
+	}
+}
+
+// Mount the SecretsRouter to a parent Router
+func (r *SecretsRouter) Mount(parent *mux.Router) {
+	parent.HandleFunc("/namespaces/{namespace}/{resource:secret_usage}", r.usage).Methods(http.MethodGet)
+}
+
+// usage responds with the checks, handlers and mutators referencing each
+// secret of the namespace, and when the secrets were last resolved.
+func (r *SecretsRouter) usage(w http.ResponseWriter, req *http.Request) {
+	usage, err := r.controller.Usage(req.Context())
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	b, err := json.Marshal(usage)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(b); err != nil {
+		logger.WithError(err).Error("failed to write response")
+	}
+}
@@ -0,0 +1,40 @@
+package routers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSecretController struct {
+	mock.Mock
+}
+
+func (m *mockSecretController) Usage(ctx context.Context) ([]actions.SecretUsage, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]actions.SecretUsage), args.Error(1)
+}
+
+func TestSecretsRouterUsage(t *testing.T) {
+	controller := new(mockSecretController)
+	controller.On("Usage", mock.Anything).Return([]actions.SecretUsage{{Name: "db-password", LastResolved: 42}}, nil).Once()
+	controller.On("Usage", mock.Anything).Return([]actions.SecretUsage(nil), actions.NewError(actions.InternalErr, errors.New("error"))).Once()
+	router := NewSecretsRouter(controller)
+
+	req, _ := http.NewRequest(http.MethodGet, "/namespaces/default/secret_usage", nil)
+	res := processRequest(router, req)
+	require.Equal(t, http.StatusOK, res.Code)
+	var usage []actions.SecretUsage
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &usage))
+	assert.Equal(t, int64(42), usage[0].LastResolved)
+
+	res = processRequest(router, req)
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+}
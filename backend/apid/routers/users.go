@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"path"
 
 	"github.com/gorilla/mux"
 	corev2 "github.com/sensu/core/v2"
@@ -29,6 +30,9 @@ type UserController interface {
 	RemoveGroup(ctx context.Context, name string, group string) error
 	RemoveAllGroups(ctx context.Context, name string) error
 	AuthenticateUser(ctx context.Context, username, password string) (*corev2.User, error)
+	Sessions(ctx context.Context, username string) ([]*storev2.Session, error)
+	RevokeSessions(ctx context.Context, username string, ids ...string) (int, error)
+	ResetMFA(ctx context.Context, username string) error
 }
 
 // UsersRouter handles requests for /users
@@ -64,6 +68,14 @@ func (r *UsersRouter) Mount(parent *mux.Router) {
 	// Password change & reset
 	routes.Path("{id}/{subresource:password}", r.updatePassword).Methods(http.MethodPut)
 	routes.Path("{id}/{subresource:reset_password}", r.resetPassword).Methods(http.MethodPut)
+
+	// Multi-factor authentication reset
+	routes.Path("{id}/{subresource:mfa}", r.resetMFA).Methods(http.MethodDelete)
+
+	// Sessions
+	parent.HandleFunc(path.Join(routes.PathPrefix, "{id}", "{subresource:sessions}"), r.sessions).Methods(http.MethodGet)
+	parent.HandleFunc(path.Join(routes.PathPrefix, "{id}", "{subresource:sessions}"), r.revokeSessions).Methods(http.MethodDelete)
+	parent.HandleFunc(path.Join(routes.PathPrefix, "{id}", "{subresource:sessions}", "{session}"), r.revokeSessions).Methods(http.MethodDelete)
 }
 
 func (r *UsersRouter) get(req *http.Request) (handlers.HandlerResponse, error) {
@@ -233,3 +245,76 @@ func (r *UsersRouter) removeAllGroups(req *http.Request) (handlers.HandlerRespon
 	err = r.controller.RemoveAllGroups(req.Context(), id)
 	return response, err
 }
+
+func (r *UsersRouter) resetMFA(req *http.Request) (handlers.HandlerResponse, error) {
+	var response handlers.HandlerResponse
+	params := mux.Vars(req)
+	id, err := url.PathUnescape(params["id"])
+	if err != nil {
+		return response, err
+	}
+
+	err = r.controller.ResetMFA(req.Context(), id)
+	return response, err
+}
+
+// sessions responds with the active sessions of a user.
+func (r *UsersRouter) sessions(w http.ResponseWriter, req *http.Request) {
+	id, err := url.PathUnescape(mux.Vars(req)["id"])
+	if err != nil {
+		WriteError(w, actions.NewError(actions.InvalidArgument, err))
+		return
+	}
+	sessions, err := r.controller.Sessions(req.Context(), id)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	if sessions == nil {
+		sessions = []*storev2.Session{}
+	}
+
+	b, err := json.Marshal(sessions)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(b); err != nil {
+		logger.WithError(err).Error("failed to write response")
+	}
+}
+
+// revokeSessions revokes a session of a user, or all of its sessions, and
+// responds with the number of revoked sessions.
+func (r *UsersRouter) revokeSessions(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	id, err := url.PathUnescape(vars["id"])
+	if err != nil {
+		WriteError(w, actions.NewError(actions.InvalidArgument, err))
+		return
+	}
+	var ids []string
+	if session, ok := vars["session"]; ok {
+		ids = append(ids, session)
+	}
+	n, err := r.controller.RevokeSessions(req.Context(), id, ids...)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	if len(ids) > 0 && n == 0 {
+		WriteError(w, actions.NewErrorf(actions.NotFound))
+		return
+	}
+
+	b, err := json.Marshal(map[string]int{"revoked": n})
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(b); err != nil {
+		logger.WithError(err).Error("failed to write response")
+	}
+}
@@ -14,6 +14,7 @@ import (
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -70,6 +71,20 @@ func (m *mockUserController) RemoveAllGroups(ctx context.Context, name string) e
 	return m.Called(ctx, name).Error(0)
 }
 
+func (m *mockUserController) Sessions(ctx context.Context, name string) ([]*storev2.Session, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).([]*storev2.Session), args.Error(1)
+}
+
+func (m *mockUserController) RevokeSessions(ctx context.Context, name string, ids ...string) (int, error) {
+	args := m.Called(ctx, name, ids)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockUserController) ResetMFA(ctx context.Context, name string) error {
+	return m.Called(ctx, name).Error(0)
+}
+
 func TestUsersRouter(t *testing.T) {
 	type controllerFunc func(*mockUserController)
 
@@ -236,6 +251,61 @@ func TestUsersRouter(t *testing.T) {
 			},
 			wantStatusCode: http.StatusCreated,
 		},
+		{
+			name:   "it returns 200 and lists the sessions of a user",
+			method: http.MethodGet,
+			path:   path.Join(fixture.URIPath(), "sessions"),
+			controllerFunc: func(c *mockUserController) {
+				c.On("Sessions", mock.Anything, "foo").
+					Return([]*storev2.Session{{ID: "abc", Username: "foo"}}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "it returns 200 when revoking all the sessions of a user",
+			method: http.MethodDelete,
+			path:   path.Join(fixture.URIPath(), "sessions"),
+			controllerFunc: func(c *mockUserController) {
+				c.On("RevokeSessions", mock.Anything, "foo", []string(nil)).
+					Return(2, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "it returns 200 when revoking a session",
+			method: http.MethodDelete,
+			path:   path.Join(fixture.URIPath(), "sessions", "abc"),
+			controllerFunc: func(c *mockUserController) {
+				c.On("RevokeSessions", mock.Anything, "foo", []string{"abc"}).
+					Return(1, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:   "it returns 404 when revoking an unknown session",
+			method: http.MethodDelete,
+			path:   path.Join(fixture.URIPath(), "sessions", "def"),
+			controllerFunc: func(c *mockUserController) {
+				c.On("RevokeSessions", mock.Anything, "foo", []string{"def"}).
+					Return(0, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:   "it returns 204 when resetting the multi-factor authentication of a user",
+			method: http.MethodDelete,
+			path:   path.Join(fixture.URIPath(), "mfa"),
+			controllerFunc: func(c *mockUserController) {
+				c.On("ResetMFA", mock.Anything, "foo").
+					Return(nil).
+					Once()
+			},
+			wantStatusCode: http.StatusNoContent,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
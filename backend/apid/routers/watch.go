@@ -0,0 +1,91 @@
+package routers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+)
+
+type watchHandlerFunc func(ctx context.Context) <-chan []handlers.WatchEvent
+
+// watchHandler streams the changes of the watched resources as server-sent
+// events, named after the type of the change (create, update, delete or
+// error), with the wrapped resource as data.
+//
+// The stream ends before the write timeout of the server hangs it up. Clients
+// are then expected to list the resources again, and resume watching them.
+func watchHandler(fn watchHandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			WriteError(w, fmt.Errorf("streaming is not supported"))
+			return
+		}
+
+		ctx := r.Context()
+		if server, ok := ctx.Value(http.ServerContextKey).(*http.Server); ok && server.WriteTimeout > 0 {
+			timeout := server.WriteTimeout - time.Second
+			if timeout < 0 {
+				timeout = 0
+			}
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		watch := fn(ctx)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		keepalive := time.NewTicker(eventStreamKeepalive)
+		defer keepalive.Stop()
+
+		for {
+			select {
+			case events, ok := <-watch:
+				if !ok {
+					return
+				}
+				for _, event := range events {
+					if err := writeWatchEvent(w, event); err != nil {
+						return
+					}
+				}
+			case <-keepalive.C:
+				if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeWatchEvent writes the change of a resource as a server-sent event.
+func writeWatchEvent(w http.ResponseWriter, event handlers.WatchEvent) error {
+	var data interface{}
+	if event.Err != nil {
+		data = map[string]string{"message": event.Err.Error()}
+	} else {
+		data = types.WrapResource(event.Resource)
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		logger.WithError(err).Error("failed to marshal watch event")
+		return nil
+	}
+	typ := strings.ToLower(event.Type.String())
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", typ, b)
+	return err
+}
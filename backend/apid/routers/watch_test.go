@@ -0,0 +1,69 @@
+package routers
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+func TestResourceRouteWatch(t *testing.T) {
+	watch := func(ctx context.Context) <-chan []handlers.WatchEvent {
+		ch := make(chan []handlers.WatchEvent, 1)
+		ch <- []handlers.WatchEvent{
+			{Type: storev2.WatchCreate, Resource: corev2.FixtureCheckConfig("check1")},
+			{Type: storev2.WatchError, Err: errors.New("boom")},
+		}
+		close(ch)
+		return ch
+	}
+	list := func(ctx context.Context, pred *store.SelectionPredicate) ([]corev3.Resource, error) {
+		return []corev3.Resource{corev2.FixtureCheckConfig("check1")}, nil
+	}
+
+	parent := mux.NewRouter().PathPrefix(corev2.URLPrefix).Subrouter()
+	routes := ResourceRoute{Router: parent, PathPrefix: "/namespaces/{namespace}/{resource:checks}"}
+	routes.Watch(watch)
+	routes.List(list, corev3.CheckConfigFields)
+	server := httptest.NewServer(parent)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/core/v2/namespaces/default/checks?watch=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got, want := resp.Header.Get("Content-Type"), "text/event-stream"; got != want {
+		t.Fatalf("bad content type: got %q, want %q", got, want)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "event: create\ndata: {\"type\":\"CheckConfig\"") {
+		t.Errorf("missing create event: %s", body)
+	}
+	if !strings.Contains(string(body), "event: error\ndata: {\"message\":\"boom\"}") {
+		t.Errorf("missing error event: %s", body)
+	}
+
+	// Without the watch parameter the resources are listed
+	resp, err = http.Get(server.URL + "/api/core/v2/namespaces/default/checks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got, want := resp.Header.Get("Content-Type"), "application/json"; got != want {
+		t.Fatalf("bad content type: got %q, want %q", got, want)
+	}
+}
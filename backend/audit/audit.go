@@ -0,0 +1,89 @@
+// Package audit records the requests made to the API, as selected by the audit
+// policies, to pluggable sinks.
+package audit
+
+import (
+	"context"
+
+	cachev2 "github.com/sensu/sensu-go/backend/store/cache/v2"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// BufferSize is the number of audit entries that can be waiting to be written
+// to the sinks. Entries are dropped when the buffer is full, so that slow
+// sinks do not slow down the API.
+const BufferSize = 1000
+
+// Sink is the destination of the audit entries.
+type Sink interface {
+	// Name identifies the sink in the logs.
+	Name() string
+
+	// Write writes an audit entry.
+	Write(ctx context.Context, entry *storev2.AuditEntry) error
+}
+
+// PolicyGetter gets the audit policies. It is implemented by the resource
+// cache of the policies.
+type PolicyGetter interface {
+	GetAll() []cachev2.Value[*AuditPolicy, AuditPolicy]
+}
+
+// Auditor writes the audit entries selected by the audit policies to its
+// sinks, in the background.
+type Auditor struct {
+	policies PolicyGetter
+	sinks    []Sink
+	entries  chan *storev2.AuditEntry
+}
+
+// New creates an Auditor writing to the given sinks until the context is
+// canceled.
+func New(ctx context.Context, policies PolicyGetter, sinks ...Sink) *Auditor {
+	a := &Auditor{
+		policies: policies,
+		sinks:    sinks,
+		entries:  make(chan *storev2.AuditEntry, BufferSize),
+	}
+	go a.run(ctx)
+	return a
+}
+
+// Selects returns whether the request recorded by the entry is selected by one
+// of the audit policies.
+func (a *Auditor) Selects(entry *storev2.AuditEntry) bool {
+	for _, value := range a.policies.GetAll() {
+		if value.Resource.Matches(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// Record queues the entry to be written to the sinks if it is selected by one
+// of the audit policies.
+func (a *Auditor) Record(entry *storev2.AuditEntry) {
+	if !a.Selects(entry) {
+		return
+	}
+	select {
+	case a.entries <- entry:
+	default:
+		logger.WithField("request_id", entry.RequestID).Warn("audit buffer is full, dropping audit entry")
+	}
+}
+
+func (a *Auditor) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case entry := <-a.entries:
+			for _, sink := range a.sinks {
+				if err := sink.Write(ctx, entry); err != nil {
+					logger.WithError(err).WithField("sink", sink.Name()).Error("couldn't write audit entry")
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	cachev2 "github.com/sensu/sensu-go/backend/store/cache/v2"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+func fixturePolicy(name string, namespaces ...string) *AuditPolicy {
+	return &AuditPolicy{
+		Metadata: corev2.NewObjectMetaP(name, ""),
+		Rules: []corev2.Rule{{
+			Verbs:     []string{"create", "update", "delete"},
+			Resources: []string{corev2.ResourceAll},
+		}},
+		Namespaces: namespaces,
+	}
+}
+
+func TestAuditPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  func(*AuditPolicy)
+		wantErr bool
+	}{
+		{
+			name:   "valid policy",
+			policy: func(*AuditPolicy) {},
+		},
+		{
+			name:    "namespaced policy",
+			policy:  func(p *AuditPolicy) { p.Metadata.Namespace = "default" },
+			wantErr: true,
+		},
+		{
+			name:    "no rules",
+			policy:  func(p *AuditPolicy) { p.Rules = nil },
+			wantErr: true,
+		},
+		{
+			name:    "invalid verb",
+			policy:  func(p *AuditPolicy) { p.Rules[0].Verbs = []string{"patch"} },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := fixturePolicy("policy")
+			tt.policy(policy)
+			if err := policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuditPolicyMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *AuditPolicy
+		entry  storev2.AuditEntry
+		want   bool
+	}{
+		{
+			name:   "matching verb",
+			policy: fixturePolicy("policy"),
+			entry:  storev2.AuditEntry{Verb: "delete", Resource: "checks", Namespace: "default"},
+			want:   true,
+		},
+		{
+			name:   "other verb",
+			policy: fixturePolicy("policy"),
+			entry:  storev2.AuditEntry{Verb: "list", Resource: "checks", Namespace: "default"},
+		},
+		{
+			name:   "matching namespace",
+			policy: fixturePolicy("policy", "prod"),
+			entry:  storev2.AuditEntry{Verb: "create", Resource: "checks", Namespace: "prod"},
+			want:   true,
+		},
+		{
+			name:   "other namespace",
+			policy: fixturePolicy("policy", "prod"),
+			entry:  storev2.AuditEntry{Verb: "create", Resource: "checks", Namespace: "default"},
+		},
+		{
+			name:   "cluster-wide resource with namespaces",
+			policy: fixturePolicy("policy", "prod"),
+			entry:  storev2.AuditEntry{Verb: "create", Resource: "clusterroles"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Matches(&tt.entry); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type testSink chan *storev2.AuditEntry
+
+func (s testSink) Name() string {
+	return "test"
+}
+
+func (s testSink) Write(_ context.Context, entry *storev2.AuditEntry) error {
+	s <- entry
+	return nil
+}
+
+func TestAuditorRecord(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	policies := cachev2.NewFromResources([]*AuditPolicy{fixturePolicy("policy")}, false)
+	sink := make(testSink, 2)
+	auditor := New(ctx, policies, sink)
+
+	auditor.Record(&storev2.AuditEntry{Verb: "list", Resource: "checks", RequestID: "ignored"})
+	auditor.Record(&storev2.AuditEntry{Verb: "create", Resource: "checks", RequestID: "recorded"})
+
+	select {
+	case entry := <-sink:
+		if entry.RequestID != "recorded" {
+			t.Errorf("expected the create request to be recorded, got %q", entry.RequestID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no entry written to the sink")
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewWriterSink(&buf)
+	for _, user := range []string{"alice", "bob"} {
+		if err := sink.Write(context.Background(), &storev2.AuditEntry{User: user}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	decoder := json.NewDecoder(&buf)
+	for _, want := range []string{"alice", "bob"} {
+		var entry storev2.AuditEntry
+		if err := decoder.Decode(&entry); err != nil {
+			t.Fatal(err)
+		}
+		if entry.User != want {
+			t.Errorf("got user %q, want %q", entry.User, want)
+		}
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	received := make(chan storev2.AuditEntry, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var entry storev2.AuditEntry
+		if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- entry
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL)
+	if err := sink.Write(context.Background(), &storev2.AuditEntry{User: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if entry := <-received; entry.User != "alice" {
+		t.Errorf("got user %q, want alice", entry.User)
+	}
+
+	failing := NewWebhookSink(server.URL + "/fail")
+	if err := failing.Write(context.Background(), &storev2.AuditEntry{}); err == nil {
+		t.Error("expected an error")
+	}
+}
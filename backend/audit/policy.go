@@ -0,0 +1,149 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	apitools "github.com/sensu/sensu-api-tools"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	utilstrings "github.com/sensu/sensu-go/util/strings"
+)
+
+const (
+	// APIGroup is the API group of the audit resources.
+	APIGroup = "audit"
+
+	// APIVersion is the API version of the audit resources.
+	APIVersion = "v1"
+
+	// AuditPoliciesResource is the RBAC name of the audit policies.
+	AuditPoliciesResource = "auditpolicies"
+)
+
+func init() {
+	apitools.RegisterType(path.Join(APIGroup, APIVersion), new(AuditPolicy))
+}
+
+var (
+	_ corev3.Resource       = new(AuditPolicy)
+	_ corev3.GlobalResource = new(AuditPolicy)
+)
+
+// allowedVerbs are the verbs of the requests that can be audited.
+var allowedVerbs = []string{corev2.VerbAll, "get", "list", "create", "update", "delete"}
+
+// AuditPolicy selects the API requests that are recorded in the audit log. A
+// request is recorded when it matches one of the rules of a policy, in one of
+// the namespaces of the policy.
+type AuditPolicy struct {
+	// Metadata contains the name, labels and annotations of the policy.
+	Metadata *corev2.ObjectMeta `json:"metadata"`
+
+	// Rules select the requests by verb, resource and resource name, like
+	// the rules of RBAC roles.
+	Rules []corev2.Rule `json:"rules"`
+
+	// Namespaces restricts the policy to the requests made in the given
+	// namespaces. The policy applies to all requests when empty.
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// GetMetadata returns the metadata of the policy.
+func (p *AuditPolicy) GetMetadata() *corev2.ObjectMeta {
+	return p.Metadata
+}
+
+// SetMetadata sets the metadata of the policy.
+func (p *AuditPolicy) SetMetadata(meta *corev2.ObjectMeta) {
+	p.Metadata = meta
+}
+
+// StoreName returns the name of the policies in the store.
+func (p *AuditPolicy) StoreName() string {
+	return "audit_policies"
+}
+
+// RBACName returns the name of the policies for RBAC purposes.
+func (p *AuditPolicy) RBACName() string {
+	return AuditPoliciesResource
+}
+
+// URIPath returns the path of the policy in the API.
+func (p *AuditPolicy) URIPath() string {
+	if p.Metadata == nil {
+		return path.Join("/api", APIGroup, APIVersion, AuditPoliciesResource)
+	}
+	return path.Join("/api", APIGroup, APIVersion, AuditPoliciesResource, url.PathEscape(p.Metadata.Name))
+}
+
+// IsGlobalResource returns true, policies are not namespaced.
+func (p *AuditPolicy) IsGlobalResource() bool {
+	return true
+}
+
+// GetTypeMeta returns the type and API version of the policies.
+func (p *AuditPolicy) GetTypeMeta() corev2.TypeMeta {
+	return corev2.TypeMeta{
+		Type:       "AuditPolicy",
+		APIVersion: path.Join(APIGroup, APIVersion),
+	}
+}
+
+// Validate the policy.
+func (p *AuditPolicy) Validate() error {
+	if p.Metadata == nil {
+		return errors.New("nil metadata")
+	}
+	if err := corev2.ValidateName(p.Metadata.Name); err != nil {
+		return fmt.Errorf("the audit policy name %s", err)
+	}
+	if p.Metadata.Namespace != "" {
+		return errors.New("an audit policy cannot have a namespace")
+	}
+	if len(p.Rules) == 0 {
+		return errors.New("an audit policy must have at least one rule")
+	}
+	for _, rule := range p.Rules {
+		if len(rule.Verbs) == 0 || len(rule.Resources) == 0 {
+			return errors.New("the rules of an audit policy must have verbs and resources")
+		}
+		for _, verb := range rule.Verbs {
+			if !utilstrings.InArray(verb, allowedVerbs) {
+				return fmt.Errorf("the verb %q is not valid", verb)
+			}
+		}
+	}
+	return nil
+}
+
+// Matches returns whether the request recorded by the entry is selected by the
+// policy.
+func (p *AuditPolicy) Matches(entry *storev2.AuditEntry) bool {
+	if len(p.Namespaces) > 0 && !utilstrings.InArray(entry.Namespace, p.Namespaces) {
+		return false
+	}
+	for _, rule := range p.Rules {
+		if rule.VerbMatches(entry.Verb) &&
+			rule.ResourceMatches(entry.Resource) &&
+			rule.ResourceNameMatches(entry.ResourceName) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditPolicyFields returns the fields of the policy available to selectors.
+func AuditPolicyFields(r corev3.Resource) map[string]string {
+	policy := r.(*AuditPolicy)
+	fields := map[string]string{
+		"auditpolicy.name": policy.Metadata.Name,
+	}
+	for key, value := range policy.Metadata.Labels {
+		fields["auditpolicy.labels."+key] = value
+	}
+	return fields
+}
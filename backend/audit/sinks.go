@@ -0,0 +1,108 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// WriterSink writes the audit entries to a writer, such as a log file, as
+// newline-delimited JSON.
+type WriterSink struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+// NewWriterSink creates a WriterSink writing to the given writer.
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{writer: w}
+}
+
+// Name implements Sink.
+func (s *WriterSink) Name() string {
+	return "file"
+}
+
+// Write implements Sink.
+func (s *WriterSink) Write(_ context.Context, entry *storev2.AuditEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.writer.Write(append(b, '\n'))
+	return err
+}
+
+// StoreSink writes the audit entries to the store.
+type StoreSink struct {
+	store storev2.AuditStore
+}
+
+// NewStoreSink creates a StoreSink writing to the given store.
+func NewStoreSink(store storev2.AuditStore) *StoreSink {
+	return &StoreSink{store: store}
+}
+
+// Name implements Sink.
+func (s *StoreSink) Name() string {
+	return "store"
+}
+
+// Write implements Sink.
+func (s *StoreSink) Write(ctx context.Context, entry *storev2.AuditEntry) error {
+	return s.store.AddAuditEntry(ctx, entry)
+}
+
+// WebhookTimeout is the time allowed to the webhook to respond.
+const WebhookTimeout = 10 * time.Second
+
+// WebhookSink posts the audit entries, as JSON, to a URL.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink creates a WebhookSink posting to the given URL.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: WebhookTimeout},
+	}
+}
+
+// Name implements Sink.
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+// Write implements Sink.
+func (s *WebhookSink) Write(ctx context.Context, entry *storev2.AuditEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("audit webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
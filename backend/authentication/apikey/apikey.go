@@ -0,0 +1,201 @@
+// Package apikey implements the expiration, the rotation and the last-used
+// tracking of the API keys. The APIKey resource has no fields for them, so
+// they are kept in the annotations of the keys.
+package apikey
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authentication/bcrypt"
+	"github.com/sensu/sensu-go/backend/store/patch"
+)
+
+const (
+	// ExpiresAtAnnotation is the annotation holding the time after which an
+	// API key is rejected, in RFC3339 format.
+	ExpiresAtAnnotation = "sensu.io/expires_at"
+
+	// LastUsedAtAnnotation is the annotation holding the last time an API key
+	// was used, in RFC3339 format.
+	LastUsedAtAnnotation = "sensu.io/last_used_at"
+
+	// PreviousHashAnnotation is the annotation holding the hash of the secret
+	// an API key had before its last rotation.
+	PreviousHashAnnotation = "sensu.io/previous_hash"
+
+	// PreviousHashExpiresAtAnnotation is the annotation holding the time
+	// after which the previous secret of an API key is rejected, in RFC3339
+	// format.
+	PreviousHashExpiresAtAnnotation = "sensu.io/previous_hash_expires_at"
+
+	// LastUsedGranularity is the granularity of the last-used timestamps, so
+	// that the keys are not written on every request.
+	LastUsedGranularity = time.Minute
+
+	// DefaultRotationGracePeriod is how long the previous secret of a rotated
+	// API key is accepted by default.
+	DefaultRotationGracePeriod = time.Hour
+)
+
+// ServerManagedAnnotations are the annotations only set by the backend.
+var ServerManagedAnnotations = []string{
+	LastUsedAtAnnotation,
+	PreviousHashAnnotation,
+	PreviousHashExpiresAtAnnotation,
+}
+
+func annotationTime(key *corev2.APIKey, annotation string) (time.Time, error) {
+	value, ok := key.Annotations[annotation]
+	if !ok || value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s annotation: %s", annotation, err)
+	}
+	return t, nil
+}
+
+func setAnnotation(key *corev2.APIKey, annotation, value string) {
+	if key.Annotations == nil {
+		key.Annotations = make(map[string]string)
+	}
+	key.Annotations[annotation] = value
+}
+
+// ExpiresAt returns the expiration of an API key, or the zero time if the
+// key does not expire.
+func ExpiresAt(key *corev2.APIKey) (time.Time, error) {
+	return annotationTime(key, ExpiresAtAnnotation)
+}
+
+// SetExpiresAt sets the expiration of an API key.
+func SetExpiresAt(key *corev2.APIKey, t time.Time) {
+	setAnnotation(key, ExpiresAtAnnotation, t.UTC().Format(time.RFC3339))
+}
+
+// LastUsedAt returns the last time an API key was used, or the zero time if
+// it was never used.
+func LastUsedAt(key *corev2.APIKey) (time.Time, error) {
+	return annotationTime(key, LastUsedAtAnnotation)
+}
+
+// Expired returns whether an API key is expired at the given time. A key
+// with an invalid expiration is expired.
+func Expired(key *corev2.APIKey, now time.Time) bool {
+	expiresAt, err := ExpiresAt(key)
+	if err != nil {
+		return true
+	}
+	return !expiresAt.IsZero() && !now.Before(expiresAt)
+}
+
+// Matches returns whether the secret is the secret of an unexpired API key,
+// or its previous secret during the grace period of its last rotation.
+func Matches(key *corev2.APIKey, secret string, now time.Time) bool {
+	if Expired(key, now) {
+		return false
+	}
+	if bcrypt.CheckPassword(string(key.Hash), secret) {
+		return true
+	}
+	previousHash := key.Annotations[PreviousHashAnnotation]
+	if previousHash == "" {
+		return false
+	}
+	expiresAt, err := annotationTime(key, PreviousHashExpiresAtAnnotation)
+	if err != nil || expiresAt.IsZero() || !now.Before(expiresAt) {
+		return false
+	}
+	return bcrypt.CheckPassword(previousHash, secret)
+}
+
+// NewSecret returns a new random secret, and its hash.
+func NewSecret() (secret string, hash []byte, err error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return "", nil, err
+	}
+	h, err := bcrypt.HashPassword(id.String())
+	if err != nil {
+		return "", nil, err
+	}
+	return id.String(), []byte(h), nil
+}
+
+// LastUsedPatch returns the merge patch recording that an API key was used at
+// the given time.
+func LastUsedPatch(now time.Time) []byte {
+	return []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`,
+		LastUsedAtAnnotation, now.UTC().Format(time.RFC3339)))
+}
+
+// Rotate replaces the hash of an API key. The previous hash is still accepted
+// for the grace period, which revokes the hash preceding it, if any.
+func Rotate(key *corev2.APIKey, hash []byte, gracePeriod time.Duration, now time.Time) {
+	delete(key.Annotations, PreviousHashAnnotation)
+	delete(key.Annotations, PreviousHashExpiresAtAnnotation)
+	if gracePeriod > 0 {
+		setAnnotation(key, PreviousHashAnnotation, string(key.Hash))
+		setAnnotation(key, PreviousHashExpiresAtAnnotation, now.Add(gracePeriod).UTC().Format(time.RFC3339))
+	}
+	key.Hash = hash
+}
+
+// Rotation is a patcher rotating the hash of the API key it is applied to, so
+// that the key is read and rotated in the same transaction.
+type Rotation struct {
+	Hash        []byte
+	GracePeriod time.Duration
+	Now         time.Time
+}
+
+// Patch rotates the hash of the API key document.
+func (r *Rotation) Patch(document []byte) ([]byte, error) {
+	var key corev2.APIKey
+	if err := json.Unmarshal(document, &key); err != nil {
+		return nil, err
+	}
+	Rotate(&key, r.Hash, r.GracePeriod, r.Now)
+	return json.Marshal(&key)
+}
+
+// KeepServerManagedAnnotations replaces the annotations of an API key only set
+// by the backend with those of the stored key.
+func KeepServerManagedAnnotations(key, stored *corev2.APIKey) {
+	for _, annotation := range ServerManagedAnnotations {
+		delete(key.Annotations, annotation)
+		if value, ok := stored.Annotations[annotation]; ok {
+			setAnnotation(key, annotation, value)
+		}
+	}
+}
+
+// ClientPatch is a patcher applying the patch of a client to an API key,
+// which can't change the annotations only set by the backend.
+type ClientPatch struct {
+	patch.Patcher
+}
+
+// Patch patches the API key document, and restores its annotations only set
+// by the backend.
+func (p *ClientPatch) Patch(document []byte) ([]byte, error) {
+	var stored corev2.APIKey
+	if err := json.Unmarshal(document, &stored); err != nil {
+		return nil, err
+	}
+	patched, err := p.Patcher.Patch(document)
+	if err != nil {
+		return nil, err
+	}
+	var key corev2.APIKey
+	if err := json.Unmarshal(patched, &key); err != nil {
+		return nil, err
+	}
+	KeepServerManagedAnnotations(&key, &stored)
+	return json.Marshal(&key)
+}
@@ -0,0 +1,132 @@
+package apikey
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store/patch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixtureKey(t *testing.T) (*corev2.APIKey, string) {
+	secret, hash, err := NewSecret()
+	require.NoError(t, err)
+	key := corev2.FixtureAPIKey("mykey", "admin")
+	key.Hash = hash
+	return key, secret
+}
+
+func TestMatches(t *testing.T) {
+	now := time.Now()
+	key, secret := fixtureKey(t)
+	assert.True(t, Matches(key, secret, now))
+	assert.False(t, Matches(key, "wrong", now))
+
+	SetExpiresAt(key, now.Add(time.Minute))
+	assert.True(t, Matches(key, secret, now))
+	assert.False(t, Matches(key, secret, now.Add(time.Minute)))
+
+	// A key with an invalid expiration is rejected
+	key.Annotations[ExpiresAtAnnotation] = "tomorrow"
+	assert.False(t, Matches(key, secret, now))
+}
+
+func TestRotate(t *testing.T) {
+	now := time.Now()
+	key, first := fixtureKey(t)
+
+	second, hash, err := NewSecret()
+	require.NoError(t, err)
+	Rotate(key, hash, time.Hour, now)
+	assert.True(t, Matches(key, first, now))
+	assert.True(t, Matches(key, second, now))
+	assert.False(t, Matches(key, first, now.Add(time.Hour)))
+	assert.True(t, Matches(key, second, now.Add(time.Hour)))
+
+	// Rotating the key again revokes its first secret
+	third, hash, err := NewSecret()
+	require.NoError(t, err)
+	Rotate(key, hash, time.Hour, now)
+	assert.False(t, Matches(key, first, now))
+	assert.True(t, Matches(key, second, now))
+	assert.True(t, Matches(key, third, now))
+
+	// Without a grace period, the previous secret is revoked immediately
+	fourth, hash, err := NewSecret()
+	require.NoError(t, err)
+	Rotate(key, hash, 0, now)
+	assert.False(t, Matches(key, third, now))
+	assert.True(t, Matches(key, fourth, now))
+	assert.NotContains(t, key.Annotations, PreviousHashAnnotation)
+}
+
+func TestRotationPatch(t *testing.T) {
+	now := time.Now()
+	key, first := fixtureKey(t)
+	key.Annotations = map[string]string{"foo": "bar"}
+	document, err := json.Marshal(key)
+	require.NoError(t, err)
+
+	second, hash, err := NewSecret()
+	require.NoError(t, err)
+	document, err = (&Rotation{Hash: hash, GracePeriod: time.Hour, Now: now}).Patch(document)
+	require.NoError(t, err)
+
+	var rotated corev2.APIKey
+	require.NoError(t, json.Unmarshal(document, &rotated))
+	assert.Equal(t, "bar", rotated.Annotations["foo"])
+	assert.True(t, Matches(&rotated, first, now))
+	assert.True(t, Matches(&rotated, second, now))
+}
+
+func TestLastUsedPatch(t *testing.T) {
+	now := time.Now()
+	key, _ := fixtureKey(t)
+	document, err := json.Marshal(key)
+	require.NoError(t, err)
+
+	document, err = (&patch.Merge{MergePatch: LastUsedPatch(now)}).Patch(document)
+	require.NoError(t, err)
+
+	var used corev2.APIKey
+	require.NoError(t, json.Unmarshal(document, &used))
+	lastUsedAt, err := LastUsedAt(&used)
+	require.NoError(t, err)
+	assert.Equal(t, now.Unix(), lastUsedAt.Unix())
+	assert.Equal(t, key.Hash, used.Hash)
+}
+
+func TestClientPatch(t *testing.T) {
+	now := time.Now()
+	key, _ := fixtureKey(t)
+	Rotate(key, []byte("hash"), time.Hour, now)
+	setAnnotation(key, LastUsedAtAnnotation, now.UTC().Format(time.RFC3339))
+	document, err := json.Marshal(key)
+	require.NoError(t, err)
+
+	// The patches can't set, change or remove the annotations of the backend
+	merge := &patch.Merge{MergePatch: []byte(`{"metadata":{"annotations":{
+		"foo": "bar",
+		"sensu.io/last_used_at": "2000-01-01T00:00:00Z",
+		"sensu.io/previous_hash": null
+	}}}`)}
+	document, err = (&ClientPatch{Patcher: merge}).Patch(document)
+	require.NoError(t, err)
+
+	var patched corev2.APIKey
+	require.NoError(t, json.Unmarshal(document, &patched))
+	assert.Equal(t, "bar", patched.Annotations["foo"])
+	assert.Equal(t, key.Annotations[LastUsedAtAnnotation], patched.Annotations[LastUsedAtAnnotation])
+	assert.Equal(t, key.Annotations[PreviousHashAnnotation], patched.Annotations[PreviousHashAnnotation])
+	assert.Equal(t, key.Annotations[PreviousHashExpiresAtAnnotation], patched.Annotations[PreviousHashExpiresAtAnnotation])
+
+	document, err = (&ClientPatch{Patcher: &patch.Merge{MergePatch: []byte(`{"metadata":{"annotations":null}}`)}}).Patch(document)
+	require.NoError(t, err)
+	patched = corev2.APIKey{}
+	require.NoError(t, json.Unmarshal(document, &patched))
+	assert.NotContains(t, patched.Annotations, "foo")
+	assert.Equal(t, key.Annotations[PreviousHashAnnotation], patched.Annotations[PreviousHashAnnotation])
+}
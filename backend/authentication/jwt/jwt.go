@@ -27,6 +27,7 @@ const Name = "jwt"
 
 var (
 	defaultExpiration	= time.Minute * 5
+	refreshExpiration	= time.Hour * 24 * 7
 	secret			[]byte
 	privateKey		*ecdsa.PrivateKey
 	publicKey		*ecdsa.PublicKey
@@ -222,14 +223,21 @@ func parseToken(tokenString string) (*jwt.Token, error) {
 	return t, err
 }
 
-// RefreshToken returns a refresh token for a specific user
+// RefreshToken returns a refresh token for a specific user. The identifier of
+// the claims is kept if set, so that a renewed refresh token identifies the
+// same session.
 func RefreshToken(claims *corev2.Claims) (*jwt.Token, string, error) {
 	// Create a unique identifier for the token
-	jti, err := GenJTI()
-	if err != nil {
-		return nil, "", err
+	if claims.Id == "" {
+		jti, err := GenJTI()
+		if err != nil {
+			return nil, "", err
+		}
+		claims.Id = jti
 	}
-	claims.Id = jti
+
+	// Add an expiration to the token, which is renewed with the token
+	claims.ExpiresAt = time.Now().Add(refreshExpiration).Unix()
 
 	token := jwt.NewWithClaims(signingMethod, claims)
 
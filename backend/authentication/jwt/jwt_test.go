@@ -86,6 +86,14 @@ func TestRefreshToken(t *testing.T) {
 	tokenClaims, _ := token.Claims.(*v2.Claims)
 	assert.Equal(t, claims.Subject, tokenClaims.Subject)
 	assert.NotEmpty(t, tokenClaims.Id)
+	assert.NotZero(t, tokenClaims.ExpiresAt)
+
+	// A renewed refresh token keeps its identifier
+	_, tokenString, err = RefreshToken(tokenClaims)
+	assert.NoError(t, err)
+	token, err = ValidateToken(tokenString)
+	assert.NoError(t, err)
+	assert.Equal(t, tokenClaims.Id, token.Claims.(*v2.Claims).Id)
 }
 
 func TestValidateTokenError(t *testing.T) {
@@ -0,0 +1,299 @@
+// Package mfa implements the multi-factor authentication of the users of the
+// basic authentication provider, with time-based one-time passwords as
+// defined by RFC 6238 and single-use recovery codes.
+package mfa
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+const (
+	// Issuer is the issuer of the one-time passwords shown by the
+	// authenticator applications.
+	Issuer = "Sensu"
+
+	// MaxFailures is the number of consecutive invalid one-time passwords, or
+	// recovery codes, after which a user is locked out.
+	MaxFailures = 5
+
+	// LockoutDuration is the time a user is first locked out for. It doubles
+	// with every invalid code given after the lockout, up to MaxLockoutDuration.
+	LockoutDuration = time.Minute
+
+	// MaxLockoutDuration is the maximum time a user is locked out for.
+	MaxLockoutDuration = time.Hour
+)
+
+var (
+	// ErrRequired is returned when a user enrolled in the multi-factor
+	// authentication logs in without a one-time password.
+	ErrRequired = errors.New("a one-time password is required")
+
+	// ErrEnrollmentRequired is returned when a user logs in without being
+	// enrolled, while the multi-factor authentication is required.
+	ErrEnrollmentRequired = errors.New("enrollment in the multi-factor authentication is required")
+
+	// ErrInvalidCode is returned when a one-time password or a recovery code
+	// is invalid, or was already used.
+	ErrInvalidCode = errors.New("invalid one-time password")
+
+	// ErrAlreadyEnrolled is returned when a user enrolls again without
+	// disabling its confirmed enrollment first.
+	ErrAlreadyEnrolled = errors.New("already enrolled in the multi-factor authentication")
+
+	// ErrNotEnrolled is returned when confirming or disabling the enrollment
+	// of a user that is not enrolled.
+	ErrNotEnrolled = errors.New("not enrolled in the multi-factor authentication")
+
+	// ErrLockedOut is returned when a user gave too many invalid one-time
+	// passwords, until its lockout expires.
+	ErrLockedOut = errors.New("too many invalid one-time passwords, try again later")
+)
+
+type codeKey struct{}
+
+// ContextWithCode returns a context carrying the one-time password, or the
+// recovery code, given by a user logging in.
+func ContextWithCode(ctx context.Context, code string) context.Context {
+	return context.WithValue(ctx, codeKey{}, code)
+}
+
+// CodeFromContext returns the one-time password carried by the context.
+func CodeFromContext(ctx context.Context) string {
+	code, _ := ctx.Value(codeKey{}).(string)
+	return code
+}
+
+// Enrollment is the response to the enrollment of a user. It is only shown
+// once.
+type Enrollment struct {
+	Secret        string   `json:"secret"`
+	URI           string   `json:"uri"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// Manager enrolls the users in the multi-factor authentication, and verifies
+// their one-time passwords. The users who give MaxFailures invalid codes in a
+// row are locked out; the failures are counted by each backend.
+type Manager struct {
+	store    storev2.MFAStore
+	required bool
+	now      func() time.Time
+
+	mu       sync.Mutex
+	failures map[string]*failures
+}
+
+// failures are the consecutive invalid codes given by a user. Its mutex
+// serializes the verifications of the codes of the user, so that concurrent
+// attempts are all counted.
+type failures struct {
+	mu          sync.Mutex
+	count       int
+	lockedUntil time.Time
+}
+
+// NewManager creates a Manager. If required, the users that are not enrolled
+// can't log in until they enroll.
+func NewManager(store storev2.MFAStore, required bool) *Manager {
+	return &Manager{
+		store:    store,
+		required: required,
+		now:      time.Now,
+		failures: make(map[string]*failures),
+	}
+}
+
+// Enroll enrolls a user, with a new secret and new recovery codes. The
+// enrollment must be confirmed with a one-time password before it is
+// enforced.
+func (m *Manager) Enroll(ctx context.Context, username string) (*Enrollment, error) {
+	secret, err := NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	codes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = hashRecoveryCode(code)
+	}
+	enrollment := &storev2.MFAEnrollment{
+		Username:  username,
+		Secret:    secret,
+		CreatedAt: m.now().Unix(),
+	}
+	if err := m.store.PutMFAEnrollment(ctx, enrollment, hashes); err != nil {
+		if _, ok := err.(*store.ErrAlreadyExists); ok {
+			return nil, ErrAlreadyEnrolled
+		}
+		return nil, err
+	}
+	return &Enrollment{
+		Secret:        secret,
+		URI:           URI(Issuer, username, secret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// Confirm confirms the enrollment of a user with a one-time password, from
+// which the one-time passwords are required.
+func (m *Manager) Confirm(ctx context.Context, username, code string) error {
+	enrollment, err := m.enrollment(ctx, username)
+	if err != nil {
+		return err
+	}
+	if enrollment == nil {
+		return ErrNotEnrolled
+	}
+	if enrollment.Confirmed {
+		return ErrAlreadyEnrolled
+	}
+	if err := m.limit(username, func() error {
+		return m.verifyCode(ctx, enrollment, code)
+	}); err != nil {
+		return err
+	}
+	return m.store.ConfirmMFAEnrollment(ctx, username)
+}
+
+// Verify verifies the one-time password, or the recovery code, of a user
+// logging in. Users without a confirmed enrollment are not verified, unless
+// the multi-factor authentication is required.
+func (m *Manager) Verify(ctx context.Context, username, code string) error {
+	enrollment, err := m.enrollment(ctx, username)
+	if err != nil {
+		return err
+	}
+	if enrollment == nil || !enrollment.Confirmed {
+		if m.required {
+			return ErrEnrollmentRequired
+		}
+		return nil
+	}
+	if code == "" {
+		return ErrRequired
+	}
+	return m.limit(username, func() error {
+		if isRecoveryCode(code) {
+			used, err := m.store.UseMFARecoveryCode(ctx, username, hashRecoveryCode(code))
+			if err != nil {
+				return err
+			}
+			if !used {
+				return ErrInvalidCode
+			}
+			logger.WithField("user", username).Info("recovery code used")
+			return nil
+		}
+		return m.verifyCode(ctx, enrollment, code)
+	})
+}
+
+// limit verifies a code of a user with verify, unless the user is locked out,
+// and counts the invalid codes.
+func (m *Manager) limit(username string, verify func() error) error {
+	m.mu.Lock()
+	f, ok := m.failures[username]
+	if !ok {
+		f = &failures{}
+		m.failures[username] = f
+	}
+	m.mu.Unlock()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := m.now()
+	if now.Before(f.lockedUntil) {
+		return ErrLockedOut
+	}
+	err := verify()
+	if err != ErrInvalidCode {
+		if err == nil {
+			f.count = 0
+		}
+		return err
+	}
+	f.count++
+	if f.count >= MaxFailures {
+		lockout := MaxLockoutDuration
+		if shift := f.count - MaxFailures; shift < 6 {
+			if d := LockoutDuration << shift; d < lockout {
+				lockout = d
+			}
+		}
+		f.lockedUntil = now.Add(lockout)
+		logger.WithField("user", username).WithField("failures", f.count).
+			Warnf("user locked out of the multi-factor authentication for %s", lockout)
+	}
+	return err
+}
+
+// Disable removes the enrollment of a user, given a one-time password or a
+// recovery code if the enrollment is confirmed.
+func (m *Manager) Disable(ctx context.Context, username, code string) error {
+	enrollment, err := m.enrollment(ctx, username)
+	if err != nil {
+		return err
+	}
+	if enrollment == nil {
+		return ErrNotEnrolled
+	}
+	if enrollment.Confirmed {
+		if code == "" {
+			return ErrRequired
+		}
+		if err := m.Verify(ctx, username, code); err != nil {
+			return err
+		}
+	}
+	return m.store.DeleteMFAEnrollment(ctx, username)
+}
+
+// Reset removes the enrollment of a user, such as a user who lost its
+// authenticator and its recovery codes, and lifts its lockout.
+func (m *Manager) Reset(ctx context.Context, username string) error {
+	if err := m.store.DeleteMFAEnrollment(ctx, username); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	delete(m.failures, username)
+	m.mu.Unlock()
+	return nil
+}
+
+// enrollment returns the enrollment of a user, or nil if it is not enrolled.
+func (m *Manager) enrollment(ctx context.Context, username string) (*storev2.MFAEnrollment, error) {
+	enrollment, err := m.store.GetMFAEnrollment(ctx, username)
+	if err != nil {
+		if _, ok := err.(*store.ErrNotFound); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return enrollment, nil
+}
+
+// verifyCode verifies a one-time password, which can only be used once.
+func (m *Manager) verifyCode(ctx context.Context, enrollment *storev2.MFAEnrollment, code string) error {
+	step, ok := match(enrollment.Secret, code, m.now())
+	if !ok || step <= enrollment.LastCounter {
+		return ErrInvalidCode
+	}
+	used, err := m.store.UseMFACounter(ctx, enrollment.Username, step)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidCode
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package mfa
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is an in-memory storev2.MFAStore.
+type memoryStore struct {
+	mu          sync.Mutex
+	enrollments map[string]storev2.MFAEnrollment
+	codes       map[string]map[string]bool
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		enrollments: make(map[string]storev2.MFAEnrollment),
+		codes:       make(map[string]map[string]bool),
+	}
+}
+
+func (s *memoryStore) PutMFAEnrollment(ctx context.Context, enrollment *storev2.MFAEnrollment, hashes []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.enrollments[enrollment.Username].Confirmed {
+		return &store.ErrAlreadyExists{Key: enrollment.Username}
+	}
+	s.enrollments[enrollment.Username] = *enrollment
+	s.codes[enrollment.Username] = make(map[string]bool)
+	for _, hash := range hashes {
+		s.codes[enrollment.Username][hash] = true
+	}
+	return nil
+}
+
+func (s *memoryStore) GetMFAEnrollment(ctx context.Context, username string) (*storev2.MFAEnrollment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	enrollment, ok := s.enrollments[username]
+	if !ok {
+		return nil, &store.ErrNotFound{Key: username}
+	}
+	return &enrollment, nil
+}
+
+func (s *memoryStore) ConfirmMFAEnrollment(ctx context.Context, username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	enrollment, ok := s.enrollments[username]
+	if !ok {
+		return &store.ErrNotFound{Key: username}
+	}
+	enrollment.Confirmed = true
+	s.enrollments[username] = enrollment
+	return nil
+}
+
+func (s *memoryStore) UseMFACounter(ctx context.Context, username string, counter int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	enrollment, ok := s.enrollments[username]
+	if !ok || enrollment.LastCounter >= counter {
+		return false, nil
+	}
+	enrollment.LastCounter = counter
+	s.enrollments[username] = enrollment
+	return true, nil
+}
+
+func (s *memoryStore) UseMFARecoveryCode(ctx context.Context, username, hash string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.codes[username][hash] {
+		return false, nil
+	}
+	delete(s.codes[username], hash)
+	return true, nil
+}
+
+func (s *memoryStore) DeleteMFAEnrollment(ctx context.Context, username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.enrollments, username)
+	delete(s.codes, username)
+	return nil
+}
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	m := NewManager(newMemoryStore(), false)
+	m.now = func() time.Time { return now }
+
+	// Users that are not enrolled don't need a one-time password
+	require.NoError(t, m.Verify(ctx, "jane", ""))
+
+	enrollment, err := m.Enroll(ctx, "jane")
+	require.NoError(t, err)
+	assert.Len(t, enrollment.RecoveryCodes, RecoveryCodes)
+
+	// The enrollment is only enforced once confirmed
+	require.NoError(t, m.Verify(ctx, "jane", ""))
+	assert.Equal(t, ErrInvalidCode, m.Confirm(ctx, "jane", "000000"))
+	code, err := Code(enrollment.Secret, now)
+	require.NoError(t, err)
+	require.NoError(t, m.Confirm(ctx, "jane", code))
+	assert.Equal(t, ErrRequired, m.Verify(ctx, "jane", ""))
+
+	// The one-time passwords can't be replayed
+	assert.Equal(t, ErrInvalidCode, m.Verify(ctx, "jane", code))
+	now = now.Add(Period)
+	code, err = Code(enrollment.Secret, now)
+	require.NoError(t, err)
+	require.NoError(t, m.Verify(ctx, "jane", code))
+	assert.Equal(t, ErrInvalidCode, m.Verify(ctx, "jane", code))
+
+	// The recovery codes can only be used once
+	require.NoError(t, m.Verify(ctx, "jane", enrollment.RecoveryCodes[0]))
+	assert.Equal(t, ErrInvalidCode, m.Verify(ctx, "jane", enrollment.RecoveryCodes[0]))
+
+	// A confirmed enrollment must be disabled before enrolling again
+	_, err = m.Enroll(ctx, "jane")
+	assert.Equal(t, ErrAlreadyEnrolled, err)
+	assert.Equal(t, ErrRequired, m.Disable(ctx, "jane", ""))
+	require.NoError(t, m.Disable(ctx, "jane", enrollment.RecoveryCodes[1]))
+	require.NoError(t, m.Verify(ctx, "jane", ""))
+	assert.Equal(t, ErrNotEnrolled, m.Disable(ctx, "jane", ""))
+}
+
+func TestManagerRequired(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(newMemoryStore(), true)
+
+	assert.Equal(t, ErrEnrollmentRequired, m.Verify(ctx, "jane", ""))
+
+	enrollment, err := m.Enroll(ctx, "jane")
+	require.NoError(t, err)
+	assert.Equal(t, ErrEnrollmentRequired, m.Verify(ctx, "jane", ""))
+
+	code, err := Code(enrollment.Secret, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, m.Confirm(ctx, "jane", code))
+	assert.Equal(t, ErrRequired, m.Verify(ctx, "jane", ""))
+
+	// An administrator can reset the enrollment of a user
+	require.NoError(t, m.Reset(ctx, "jane"))
+	assert.Equal(t, ErrEnrollmentRequired, m.Verify(ctx, "jane", ""))
+}
+
+func TestManagerLockout(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	m := NewManager(newMemoryStore(), false)
+	m.now = func() time.Time { return now }
+
+	enrollment, err := m.Enroll(ctx, "jane")
+	require.NoError(t, err)
+	code, err := Code(enrollment.Secret, now)
+	require.NoError(t, err)
+	require.NoError(t, m.Confirm(ctx, "jane", code))
+
+	// The user is locked out after too many invalid codes, even with a valid
+	// code, while the other users are not
+	for i := 0; i < MaxFailures; i++ {
+		assert.Equal(t, ErrInvalidCode, m.Verify(ctx, "jane", "000000"))
+	}
+	now = now.Add(Period)
+	code, err = Code(enrollment.Secret, now)
+	require.NoError(t, err)
+	assert.Equal(t, ErrLockedOut, m.Verify(ctx, "jane", code))
+	assert.Equal(t, ErrLockedOut, m.Verify(ctx, "jane", enrollment.RecoveryCodes[0]))
+	require.NoError(t, m.Verify(ctx, "john", ""))
+
+	// The lockout doubles with every invalid code given after it expires
+	now = now.Add(LockoutDuration)
+	assert.Equal(t, ErrInvalidCode, m.Verify(ctx, "jane", "000000"))
+	now = now.Add(LockoutDuration)
+	assert.Equal(t, ErrLockedOut, m.Verify(ctx, "jane", code))
+	now = now.Add(LockoutDuration)
+	code, err = Code(enrollment.Secret, now)
+	require.NoError(t, err)
+	require.NoError(t, m.Verify(ctx, "jane", code))
+
+	// A valid code resets the count of invalid codes
+	for i := 0; i < MaxFailures-1; i++ {
+		assert.Equal(t, ErrInvalidCode, m.Verify(ctx, "jane", "000000"))
+	}
+	now = now.Add(Period)
+	code, err = Code(enrollment.Secret, now)
+	require.NoError(t, err)
+	require.NoError(t, m.Verify(ctx, "jane", code))
+	assert.Equal(t, ErrInvalidCode, m.Verify(ctx, "jane", "000000"))
+
+	// An administrator can lift the lockout by resetting the enrollment
+	for i := 0; i < MaxFailures; i++ {
+		_ = m.Verify(ctx, "jane", "000000")
+	}
+	require.NoError(t, m.Reset(ctx, "jane"))
+	enrollment, err = m.Enroll(ctx, "jane")
+	require.NoError(t, err)
+	code, err = Code(enrollment.Secret, now)
+	require.NoError(t, err)
+	require.NoError(t, m.Confirm(ctx, "jane", code))
+}
@@ -0,0 +1,131 @@
+package mfa
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Period is the duration of the time steps of the one-time passwords.
+	Period = 30 * time.Second
+
+	// Digits is the number of digits of the one-time passwords.
+	Digits = 6
+
+	// Skew is the number of time steps before and after the current one
+	// whose one-time passwords are accepted, allowing for clock drift.
+	Skew = 1
+
+	// RecoveryCodes is the number of recovery codes of an enrollment.
+	RecoveryCodes = 10
+
+	secretSize       = 20
+	recoveryCodeSize = 10
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewSecret returns a new random secret, base32-encoded.
+func NewSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// counter returns the time step of the given time.
+func counter(t time.Time) int64 {
+	return t.Unix() / int64(Period/time.Second)
+}
+
+// code returns the one-time password of a time step, as defined by RFC 4226.
+func code(key []byte, counter int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+	mac := hmac.New(sha1.New, key)
+	_, _ = mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0xf
+	value := binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", Digits, value%1000000)
+}
+
+// Code returns the one-time password of the secret at the given time.
+func Code(secret string, t time.Time) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("invalid secret: %s", err)
+	}
+	return code(key, counter(t)), nil
+}
+
+// match returns the time step of the one-time password of the secret that
+// matches the code around the given time, if any.
+func match(secret, c string, t time.Time) (int64, bool) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(c) != Digits {
+		return 0, false
+	}
+	current := counter(t)
+	for step := current - Skew; step <= current+Skew; step++ {
+		if hmac.Equal([]byte(code(key, step)), []byte(c)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// URI returns the otpauth URI of a secret, which authenticator applications
+// import from a QR code.
+func URI(issuer, username, secret string) string {
+	values := url.Values{}
+	values.Set("secret", secret)
+	values.Set("issuer", issuer)
+	values.Set("algorithm", "SHA1")
+	values.Set("digits", fmt.Sprint(Digits))
+	values.Set("period", fmt.Sprint(int(Period/time.Second)))
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(username)
+	return "otpauth://totp/" + label + "?" + values.Encode()
+}
+
+// newRecoveryCodes returns new random recovery codes, formatted as two groups
+// of five characters.
+func newRecoveryCodes() ([]string, error) {
+	codes := make([]string, RecoveryCodes)
+	b := make([]byte, recoveryCodeSize)
+	for i := range codes {
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		s := strings.ToLower(encoding.EncodeToString(b))[:recoveryCodeSize]
+		codes[i] = s[:5] + "-" + s[5:]
+	}
+	return codes, nil
+}
+
+// isRecoveryCode returns whether a code has the format of the recovery codes
+// rather than of the one-time passwords.
+func isRecoveryCode(c string) bool {
+	return len(normalizeRecoveryCode(c)) == recoveryCodeSize
+}
+
+func normalizeRecoveryCode(c string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(c), "-", ""))
+}
+
+// hashRecoveryCode returns the hash of a recovery code, as stored. The codes
+// are random, so they are not salted.
+func hashRecoveryCode(c string) string {
+	sum := sha256.Sum256([]byte(normalizeRecoveryCode(c)))
+	return hex.EncodeToString(sum[:])
+}
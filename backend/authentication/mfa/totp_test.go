@@ -0,0 +1,66 @@
+package mfa
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCode(t *testing.T) {
+	// The SHA1 test vectors of RFC 6238, truncated to 6 digits
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	tests := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, want := range tests {
+		got, err := Code(secret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, want, got, unix)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	now := time.Now()
+
+	c, err := Code(secret, now)
+	require.NoError(t, err)
+	step, ok := match(secret, c, now)
+	assert.True(t, ok)
+	assert.Equal(t, counter(now), step)
+
+	// The codes of the adjacent time steps are accepted
+	_, ok = match(secret, c, now.Add(Period))
+	assert.True(t, ok)
+	_, ok = match(secret, c, now.Add(3*Period))
+	assert.False(t, ok)
+
+	// The secrets are case insensitive
+	_, ok = match(strings.ToLower(secret), c, now)
+	assert.True(t, ok)
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := newRecoveryCodes()
+	require.NoError(t, err)
+	assert.Len(t, codes, RecoveryCodes)
+	for _, c := range codes {
+		assert.True(t, isRecoveryCode(c))
+		assert.Equal(t, hashRecoveryCode(c), hashRecoveryCode(strings.ToUpper(strings.ReplaceAll(c, "-", ""))))
+	}
+	assert.False(t, isRecoveryCode("123456"))
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Sensu", "jane doe", "JBSWY3DPEHPK3PXP")
+	assert.Equal(t, "otpauth://totp/Sensu:jane%20doe?algorithm=SHA1&digits=6&issuer=Sensu&period=30&secret=JBSWY3DPEHPK3PXP", uri)
+}
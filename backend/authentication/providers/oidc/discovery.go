@@ -0,0 +1,242 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	// DiscoveryTTL is the time the discovery documents of the OIDC servers
+	// are cached.
+	DiscoveryTTL = time.Hour
+
+	// KeysRefreshInterval is the minimum interval between two fetches of the
+	// keys of an OIDC server, when an ID token is signed by an unknown key.
+	KeysRefreshInterval = 10 * time.Second
+
+	// RequestTimeout is the time allowed to the OIDC servers to respond.
+	RequestTimeout = 10 * time.Second
+)
+
+// httpClient is the HTTP client of the OIDC servers.
+var httpClient = &http.Client{Timeout: RequestTimeout}
+
+// signingMethods are the algorithms accepted for the signature of ID tokens.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// server is the configuration of an OIDC server, read from its discovery
+// document, and its signing keys.
+type server struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+
+	discovered time.Time
+
+	mu          sync.Mutex
+	keys        map[string]interface{}
+	keysFetched time.Time
+}
+
+var (
+	serversMu sync.Mutex
+	servers   = make(map[string]*server)
+)
+
+// discover returns the configuration of the OIDC server at the given URL,
+// which must be its issuer, including any trailing slash.
+func discover(ctx context.Context, serverURL string) (*server, error) {
+	serversMu.Lock()
+	s, ok := servers[serverURL]
+	serversMu.Unlock()
+	if ok && time.Since(s.discovered) < DiscoveryTTL {
+		return s, nil
+	}
+
+	s = &server{}
+	if err := getJSON(ctx, strings.TrimSuffix(serverURL, "/")+"/.well-known/openid-configuration", s); err != nil {
+		return nil, fmt.Errorf("could not discover the oidc server: %s", err)
+	}
+	if s.Issuer != serverURL {
+		return nil, fmt.Errorf("the oidc server issuer %q does not match its URL %q", s.Issuer, serverURL)
+	}
+	if s.AuthorizationEndpoint == "" || s.TokenEndpoint == "" || s.JWKSURI == "" {
+		return nil, errors.New("the oidc server discovery document is incomplete")
+	}
+	s.discovered = time.Now()
+
+	serversMu.Lock()
+	servers[serverURL] = s
+	serversMu.Unlock()
+	return s, nil
+}
+
+// key returns the key of the server with the given ID. The keys are fetched
+// again when the ID is unknown, as the server may have rotated its keys.
+func (s *server) key(ctx context.Context, kid string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	if time.Since(s.keysFetched) < KeysRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	keys, err := fetchKeys(ctx, s.JWKSURI)
+	s.keysFetched = time.Now()
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch the oidc server keys: %s", err)
+	}
+	s.keys = keys
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// verify verifies the signature, the issuer, the audience and the expiration
+// of an ID token, and returns its claims.
+func (s *server) verify(ctx context.Context, rawToken, clientID string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods(signingMethods))
+	_, err := parser.ParseWithClaims(rawToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return s.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %s", err)
+	}
+	if !claims.VerifyIssuer(s.Issuer, true) {
+		return nil, errors.New("invalid ID token: unexpected issuer")
+	}
+	if !claims.VerifyAudience(clientID, true) {
+		return nil, errors.New("invalid ID token: unexpected audience")
+	}
+	if _, ok := claims["exp"]; !ok {
+		return nil, errors.New("invalid ID token: no expiration")
+	}
+	return claims, nil
+}
+
+// jwk is a JSON web key.
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetchKeys fetches the RSA and EC signing keys of a JSON web key set, by ID.
+func fetchKeys(ctx context.Context, jwksURI string) (map[string]interface{}, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := getJSON(ctx, jwksURI, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			logger.WithError(err).WithField("kid", k.Kid).Warning("ignoring invalid oidc server key")
+			continue
+		}
+		if key != nil {
+			keys[k.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+// publicKey returns the public key of an RSA or EC key, or nil for the other
+// key types.
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("invalid EC point")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// getJSON gets and decodes a JSON document.
+func getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded with status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
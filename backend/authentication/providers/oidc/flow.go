@@ -0,0 +1,210 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	corev2 "github.com/sensu/core/v2"
+	utilbytes "github.com/sensu/sensu-go/util/bytes"
+)
+
+// Session is the state of an authorization code flow, kept by the user agent
+// between the authorization request and the callback.
+type Session struct {
+	// State binds the callback to the authorization request.
+	State string `json:"state"`
+
+	// Nonce binds the ID token to the authorization request.
+	Nonce string `json:"nonce"`
+
+	// Verifier is the PKCE code verifier.
+	Verifier string `json:"verifier"`
+
+	// Redirect is the path the user agent is redirected to once logged in.
+	Redirect string `json:"redirect,omitempty"`
+}
+
+// NewSession returns a new Session, with random state, nonce and verifier.
+func NewSession() (Session, error) {
+	var values [3]string
+	for i := range values {
+		b, err := utilbytes.Random(32)
+		if err != nil {
+			return Session{}, err
+		}
+		values[i] = base64.RawURLEncoding.EncodeToString(b)
+	}
+	return Session{State: values[0], Nonce: values[1], Verifier: values[2]}, nil
+}
+
+// Challenge returns the S256 PKCE code challenge of the session.
+func (s Session) Challenge() string {
+	sum := sha256.Sum256([]byte(s.Verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// ValidState returns whether the state returned to the callback is the state
+// of the session.
+func (s Session) ValidState(state string) bool {
+	return s.State != "" && subtle.ConstantTimeCompare([]byte(s.State), []byte(state)) == 1
+}
+
+// Tokens are the claims of a user authenticated by the OIDC server, and the
+// refresh token to renew them.
+type Tokens struct {
+	// Claims are the claims of the user.
+	Claims *corev2.Claims
+
+	// RefreshToken is the refresh token issued by the OIDC server. It is empty
+	// when offline access is disabled.
+	RefreshToken string
+}
+
+// AuthCodeURL returns the URL of the OIDC server the user agent is redirected
+// to, to start the authorization code flow of the session.
+func (p *Provider) AuthCodeURL(ctx context.Context, session Session) (string, error) {
+	s, err := discover(ctx, p.Server)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(s.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid oidc authorization endpoint: %s", err)
+	}
+	query := u.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.ClientID)
+	query.Set("redirect_uri", p.RedirectURI)
+	query.Set("scope", strings.Join(p.scopes(), " "))
+	query.Set("state", session.State)
+	query.Set("nonce", session.Nonce)
+	query.Set("code_challenge", session.Challenge())
+	query.Set("code_challenge_method", "S256")
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// Exchange exchanges the authorization code returned to the callback of the
+// session for the tokens of the user.
+func (p *Provider) Exchange(ctx context.Context, code string, session Session) (*Tokens, error) {
+	s, err := discover(ctx, p.Server)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.token(ctx, s, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.RedirectURI},
+		"code_verifier": {session.Verifier},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.IDToken == "" {
+		return nil, errors.New("the oidc server did not issue an ID token")
+	}
+	idClaims, err := s.verify(ctx, resp.IDToken, p.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if nonce, _ := idClaims["nonce"].(string); subtle.ConstantTimeCompare([]byte(nonce), []byte(session.Nonce)) != 1 {
+		return nil, errors.New("invalid ID token: unexpected nonce")
+	}
+	claims, err := p.Claims(idClaims)
+	if err != nil {
+		return nil, err
+	}
+	return &Tokens{Claims: claims, RefreshToken: resp.RefreshToken}, nil
+}
+
+// RefreshTokens renews the claims of a user with a refresh token issued by
+// the OIDC server. The groups of the user are updated when the server issues
+// a new ID token.
+func (p *Provider) RefreshTokens(ctx context.Context, claims *corev2.Claims, refreshToken string) (*Tokens, error) {
+	if refreshToken == "" {
+		return nil, errors.New("no oidc refresh token")
+	}
+	s, err := discover(ctx, p.Server)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.token(ctx, s, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := &Tokens{RefreshToken: resp.RefreshToken}
+	if tokens.RefreshToken == "" {
+		// The server does not rotate its refresh tokens
+		tokens.RefreshToken = refreshToken
+	}
+	if resp.IDToken == "" {
+		tokens.Claims = &corev2.Claims{
+			StandardClaims: corev2.StandardClaims(claims.Subject),
+			Groups:         claims.Groups,
+			Provider:       claims.Provider,
+		}
+		return tokens, nil
+	}
+	idClaims, err := s.verify(ctx, resp.IDToken, p.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if tokens.Claims, err = p.Claims(idClaims); err != nil {
+		return nil, err
+	}
+	if tokens.Claims.Provider.UserID != claims.Provider.UserID {
+		return nil, errors.New("invalid ID token: unexpected subject")
+	}
+	return tokens, nil
+}
+
+// tokenResponse is a response of the token endpoint of an OIDC server.
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// token posts a request to the token endpoint of the OIDC server, with the
+// client credentials.
+func (p *Provider) token(ctx context.Context, s *server, form url.Values) (*tokenResponse, error) {
+	form.Set("client_id", p.ClientID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.ClientID), url.QueryEscape(p.ClientSecret))
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not request the oidc server tokens: %s", err)
+	}
+	defer resp.Body.Close()
+
+	var body tokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("invalid oidc token response: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK || body.Error != "" {
+		if body.Error == "" {
+			body.Error = http.StatusText(resp.StatusCode)
+		}
+		return nil, fmt.Errorf("the oidc server did not issue tokens: %s %s", body.Error, body.ErrorDescription)
+	}
+	return &body, nil
+}
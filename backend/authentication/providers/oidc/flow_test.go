@@ -0,0 +1,222 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer is a fake OIDC server, which issues the ID tokens of a single
+// user.
+type fakeServer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu        sync.Mutex
+	challenge string
+	nonce     string
+	groups    []string
+	audience  string
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	s := &fakeServer{key: key, groups: []string{"ops"}, audience: "sensu"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 s.URL,
+			"authorization_endpoint": s.URL + "/authorize",
+			"token_endpoint":         s.URL + "/token",
+			"jwks_uri":               s.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "k1",
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", s.token)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// authorize records the challenge and the nonce of an authorization request.
+func (s *fakeServer) authorize(authURL string) {
+	u, _ := url.Parse(authURL)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.challenge = u.Query().Get("code_challenge")
+	s.nonce = u.Query().Get("nonce")
+}
+
+func (s *fakeServer) token(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, secret, _ := r.BasicAuth(); id != "sensu" || secret != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+		return
+	}
+	var refreshToken string
+	switch r.PostFormValue("grant_type") {
+	case "authorization_code":
+		sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+		if r.PostFormValue("code") != "code" || base64.RawURLEncoding.EncodeToString(sum[:]) != s.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		refreshToken = "refresh1"
+	case "refresh_token":
+		if r.PostFormValue("refresh_token") != "refresh1" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		refreshToken = "refresh2"
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":    s.URL,
+		"aud":    s.audience,
+		"sub":    "1234",
+		"exp":    time.Now().Add(time.Minute).Unix(),
+		"nonce":  s.nonce,
+		"email":  "jane@example.com",
+		"groups": s.groups,
+	})
+	token.Header["kid"] = "k1"
+	idToken, _ := token.SignedString(s.key)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"access_token":  "access",
+		"id_token":      idToken,
+		"refresh_token": refreshToken,
+	})
+}
+
+func fixtureProvider(server string) *Provider {
+	return &Provider{
+		Metadata:       corev2.NewObjectMetaP("okta", ""),
+		Server:         server,
+		ClientID:       "sensu",
+		ClientSecret:   "secret",
+		RedirectURI:    "https://sensu.example.com/api/authentication/v2/oidc/okta/callback",
+		UsernameClaim:  "email",
+		UsernamePrefix: "okta:",
+		GroupsClaim:    "groups",
+		GroupsPrefix:   "okta:",
+	}
+}
+
+func TestProviderLogin(t *testing.T) {
+	server := newFakeServer(t)
+	provider := fixtureProvider(server.URL)
+	ctx := context.Background()
+
+	session, err := NewSession()
+	require.NoError(t, err)
+	authURL, err := provider.AuthCodeURL(ctx, session)
+	require.NoError(t, err)
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, "openid offline_access", u.Query().Get("scope"))
+	assert.Equal(t, "S256", u.Query().Get("code_challenge_method"))
+	assert.Equal(t, session.State, u.Query().Get("state"))
+	server.authorize(authURL)
+
+	tokens, err := provider.Exchange(ctx, "code", session)
+	require.NoError(t, err)
+	assert.Equal(t, "okta:jane@example.com", tokens.Claims.Subject)
+	assert.Equal(t, []string{"okta:ops"}, tokens.Claims.Groups)
+	assert.Equal(t, corev2.AuthProviderClaims{ProviderID: "okta", ProviderType: Type, UserID: "1234"}, tokens.Claims.Provider)
+	assert.Equal(t, "refresh1", tokens.RefreshToken)
+
+	// The groups are updated when the tokens are refreshed
+	server.groups = []string{"ops", "dev"}
+	refreshed, err := provider.RefreshTokens(ctx, tokens.Claims, tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"okta:ops", "okta:dev"}, refreshed.Claims.Groups)
+	assert.Equal(t, "refresh2", refreshed.RefreshToken)
+
+	_, err = provider.RefreshTokens(ctx, tokens.Claims, "invalid")
+	assert.Error(t, err)
+}
+
+func TestProviderExchangeErrors(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		modify func(*fakeServer, *Session)
+	}{
+		{
+			name: "wrong verifier",
+			modify: func(s *fakeServer, session *Session) {
+				session.Verifier = "wrong"
+			},
+		},
+		{
+			name: "wrong nonce",
+			modify: func(s *fakeServer, session *Session) {
+				s.nonce = "wrong"
+			},
+		},
+		{
+			name: "wrong audience",
+			modify: func(s *fakeServer, session *Session) {
+				s.audience = "other"
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeServer(t)
+			provider := fixtureProvider(server.URL)
+			session, err := NewSession()
+			require.NoError(t, err)
+			authURL, err := provider.AuthCodeURL(ctx, session)
+			require.NoError(t, err)
+			server.authorize(authURL)
+			tt.modify(server, &session)
+
+			_, err = provider.Exchange(ctx, "code", session)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSession(t *testing.T) {
+	session, err := NewSession()
+	require.NoError(t, err)
+	other, err := NewSession()
+	require.NoError(t, err)
+	assert.NotEqual(t, session.State, other.State)
+	assert.NotEqual(t, session.Challenge(), other.Challenge())
+
+	assert.True(t, session.ValidState(session.State))
+	assert.False(t, session.ValidState(other.State))
+	assert.False(t, Session{}.ValidState(""))
+}
@@ -0,0 +1,253 @@
+// Package oidc provides an OpenID Connect authentication provider. The users
+// are authenticated by an OIDC server with the authorization code flow and
+// PKCE, and their groups are read from a claim of their ID token so that they
+// can be bound to RBAC roles.
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	apitools "github.com/sensu/sensu-api-tools"
+)
+
+const (
+	// Type represents the type of the OIDC authentication provider.
+	Type = "oidc"
+
+	// APIGroup is the API group of the authentication providers.
+	APIGroup = "authentication"
+
+	// APIVersion is the API version of the authentication providers.
+	APIVersion = "v2"
+
+	// AuthProvidersResource is the RBAC name of the authentication providers.
+	AuthProvidersResource = "authproviders"
+
+	// DefaultUsernameClaim is the claim of the ID token that the usernames
+	// are read from, by default.
+	DefaultUsernameClaim = "sub"
+)
+
+// ErrPasswordNotSupported is the error returned by the provider when one tries
+// to authenticate with a username and a password.
+var ErrPasswordNotSupported = errors.New("the oidc provider does not authenticate usernames and passwords")
+
+func init() {
+	apitools.RegisterType(path.Join(APIGroup, APIVersion), new(Provider), apitools.WithAlias(Type))
+}
+
+var (
+	_ corev3.Resource       = new(Provider)
+	_ corev3.GlobalResource = new(Provider)
+	_ corev3.AuthProvider   = new(Provider)
+)
+
+// Provider authenticates the users with an OpenID Connect server.
+type Provider struct {
+	// Metadata contains the name, labels and annotations of the provider.
+	Metadata *corev2.ObjectMeta `json:"metadata"`
+
+	// Server is the URL of the OIDC server, which serves its discovery
+	// document under /.well-known/openid-configuration. It must be the issuer
+	// of the server, exactly.
+	Server string `json:"server"`
+
+	// ClientID is the ID of the backend at the OIDC server.
+	ClientID string `json:"client_id"`
+
+	// ClientSecret is the secret of the backend at the OIDC server.
+	ClientSecret string `json:"client_secret"`
+
+	// RedirectURI is the callback URL of the provider in the backend API,
+	// /api/authentication/v2/oidc/{name}/callback, as registered at the OIDC
+	// server.
+	RedirectURI string `json:"redirect_uri"`
+
+	// AdditionalScopes are requested along with the openid scope.
+	AdditionalScopes []string `json:"additional_scopes,omitempty"`
+
+	// UsernameClaim is the claim of the ID token used as the username. It
+	// defaults to DefaultUsernameClaim.
+	UsernameClaim string `json:"username_claim,omitempty"`
+
+	// UsernamePrefix is prepended to the usernames, to keep them apart from
+	// the users of other providers.
+	UsernamePrefix string `json:"username_prefix,omitempty"`
+
+	// GroupsClaim is the claim of the ID token listing the groups of the
+	// user. The users have no groups when empty.
+	GroupsClaim string `json:"groups_claim,omitempty"`
+
+	// GroupsPrefix is prepended to the groups, to keep them apart from the
+	// groups of other providers.
+	GroupsPrefix string `json:"groups_prefix,omitempty"`
+
+	// DisableOfflineAccess disables the offline_access scope, so that the
+	// OIDC server issues no refresh token and the users log in again when
+	// their access token expires.
+	DisableOfflineAccess bool `json:"disable_offline_access,omitempty"`
+}
+
+// GetMetadata returns the metadata of the provider.
+func (p *Provider) GetMetadata() *corev2.ObjectMeta {
+	return p.Metadata
+}
+
+// SetMetadata sets the metadata of the provider.
+func (p *Provider) SetMetadata(meta *corev2.ObjectMeta) {
+	p.Metadata = meta
+}
+
+// StoreName returns the name of the providers in the store.
+func (p *Provider) StoreName() string {
+	return "auth_providers"
+}
+
+// RBACName returns the name of the providers for RBAC purposes.
+func (p *Provider) RBACName() string {
+	return AuthProvidersResource
+}
+
+// URIPath returns the path of the provider in the API.
+func (p *Provider) URIPath() string {
+	if p.Metadata == nil {
+		return path.Join("/api", APIGroup, APIVersion, AuthProvidersResource)
+	}
+	return path.Join("/api", APIGroup, APIVersion, AuthProvidersResource, url.PathEscape(p.Metadata.Name))
+}
+
+// IsGlobalResource returns true, providers are not namespaced.
+func (p *Provider) IsGlobalResource() bool {
+	return true
+}
+
+// GetTypeMeta returns the type and API version of the providers.
+func (p *Provider) GetTypeMeta() corev2.TypeMeta {
+	return corev2.TypeMeta{
+		Type:       Type,
+		APIVersion: path.Join(APIGroup, APIVersion),
+	}
+}
+
+// Validate the provider.
+func (p *Provider) Validate() error {
+	if p.Metadata == nil {
+		return errors.New("nil metadata")
+	}
+	if err := corev2.ValidateName(p.Metadata.Name); err != nil {
+		return fmt.Errorf("the oidc provider name %s", err)
+	}
+	if p.Metadata.Namespace != "" {
+		return errors.New("an oidc provider cannot have a namespace")
+	}
+	if p.ClientID == "" || p.ClientSecret == "" {
+		return errors.New("an oidc provider must have a client ID and a client secret")
+	}
+	for name, value := range map[string]string{"server": p.Server, "redirect URI": p.RedirectURI} {
+		u, err := url.Parse(value)
+		if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+			return fmt.Errorf("the %s of an oidc provider must be an absolute http(s) URL", name)
+		}
+	}
+	return nil
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	if p.Metadata == nil {
+		return ""
+	}
+	return p.Metadata.Name
+}
+
+// Type returns the provider type.
+func (p *Provider) Type() string {
+	return Type
+}
+
+// Authenticate is not supported, the users of the provider log in with the
+// authorization code flow.
+func (p *Provider) Authenticate(context.Context, string, string) (*corev2.Claims, error) {
+	return nil, ErrPasswordNotSupported
+}
+
+// Refresh is not supported, the claims of the users are refreshed with their
+// refresh token by RefreshTokens.
+func (p *Provider) Refresh(_ context.Context, claims *corev2.Claims) (*corev2.Claims, error) {
+	return nil, fmt.Errorf("the claims of user %q must be refreshed with the oidc refresh token", claims.Provider.UserID)
+}
+
+// scopes returns the scopes requested to the OIDC server.
+func (p *Provider) scopes() []string {
+	scopes := []string{"openid"}
+	if !p.DisableOfflineAccess {
+		scopes = append(scopes, "offline_access")
+	}
+	for _, scope := range p.AdditionalScopes {
+		if scope != "openid" && scope != "offline_access" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// Claims returns the Sensu claims of the user identified by the claims of an
+// ID token. The username and the groups are read from the configured claims,
+// and prefixed.
+func (p *Provider) Claims(idClaims map[string]interface{}) (*corev2.Claims, error) {
+	subject, _ := idClaims["sub"].(string)
+	if subject == "" {
+		return nil, errors.New("the ID token has no subject")
+	}
+	usernameClaim := p.UsernameClaim
+	if usernameClaim == "" {
+		usernameClaim = DefaultUsernameClaim
+	}
+	username, _ := idClaims[usernameClaim].(string)
+	if username == "" {
+		return nil, fmt.Errorf("the ID token has no %q claim", usernameClaim)
+	}
+
+	var groups []string
+	if p.GroupsClaim != "" {
+		switch value := idClaims[p.GroupsClaim].(type) {
+		case string:
+			groups = append(groups, p.GroupsPrefix+value)
+		case []interface{}:
+			for _, group := range value {
+				if group, ok := group.(string); ok && group != "" {
+					groups = append(groups, p.GroupsPrefix+group)
+				}
+			}
+		}
+	}
+
+	return &corev2.Claims{
+		StandardClaims: corev2.StandardClaims(p.UsernamePrefix + username),
+		Groups:         groups,
+		Provider: corev2.AuthProviderClaims{
+			ProviderID:   p.Name(),
+			ProviderType: Type,
+			UserID:       subject,
+		},
+	}, nil
+}
+
+// AuthProviderFields returns the fields of the provider available to selectors.
+func AuthProviderFields(r corev3.Resource) map[string]string {
+	provider := r.(*Provider)
+	fields := map[string]string{
+		"authprovider.name": provider.Metadata.Name,
+		"authprovider.type": Type,
+	}
+	for key, value := range provider.Metadata.Labels {
+		fields["authprovider.labels."+key] = value
+	}
+	return fields
+}
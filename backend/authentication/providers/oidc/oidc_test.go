@@ -0,0 +1,82 @@
+package oidc
+
+import (
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Provider)
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			modify: func(p *Provider) {},
+		},
+		{
+			name:    "namespaced",
+			modify:  func(p *Provider) { p.Metadata.Namespace = "default" },
+			wantErr: true,
+		},
+		{
+			name:    "no client secret",
+			modify:  func(p *Provider) { p.ClientSecret = "" },
+			wantErr: true,
+		},
+		{
+			name:    "relative redirect URI",
+			modify:  func(p *Provider) { p.RedirectURI = "/api/authentication/v2/oidc/okta/callback" },
+			wantErr: true,
+		},
+		{
+			name:    "invalid server",
+			modify:  func(p *Provider) { p.Server = "ldap://example.com" },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := fixtureProvider("https://example.okta.com")
+			tt.modify(p)
+			if err := p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProviderClaims(t *testing.T) {
+	p := &Provider{Metadata: corev2.NewObjectMetaP("okta", ""), GroupsClaim: "groups"}
+
+	claims, err := p.Claims(map[string]interface{}{
+		"sub":    "1234",
+		"groups": []interface{}{"ops", 42, "dev"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1234", claims.Subject)
+	assert.Equal(t, []string{"ops", "dev"}, claims.Groups)
+
+	// A single group can be a string
+	claims, err = p.Claims(map[string]interface{}{"sub": "1234", "groups": "ops"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ops"}, claims.Groups)
+
+	p.UsernameClaim = "email"
+	_, err = p.Claims(map[string]interface{}{"sub": "1234"})
+	assert.Error(t, err)
+	_, err = p.Claims(map[string]interface{}{"email": "jane@example.com"})
+	assert.Error(t, err)
+}
+
+func TestProviderScopes(t *testing.T) {
+	p := &Provider{AdditionalScopes: []string{"openid", "email", "groups"}}
+	assert.Equal(t, []string{"openid", "offline_access", "email", "groups"}, p.scopes())
+
+	p.DisableOfflineAccess = true
+	assert.Equal(t, []string{"openid", "email", "groups"}, p.scopes())
+}
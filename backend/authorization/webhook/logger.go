@@ -0,0 +1,7 @@
+package webhook
+
+import "github.com/sirupsen/logrus"
+
+var logger = logrus.WithFields(logrus.Fields{
+	"component": "authorization-webhook",
+})
@@ -0,0 +1,249 @@
+// Package webhook implements an authorizer delegating the authorization
+// decisions to an external HTTPS endpoint, such as an Open Policy Agent
+// server or a custom policy engine. The requests and the responses of the
+// webhook have the shape of the Open Policy Agent data API, so that a policy
+// can be queried directly.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/authorization"
+)
+
+const (
+	// DefaultTimeout is the time allowed to the webhook to respond.
+	DefaultTimeout = 5 * time.Second
+
+	// DefaultCacheTTL is how long the decisions of the webhook are cached by
+	// default.
+	DefaultCacheTTL = 30 * time.Second
+
+	// maxCacheSize is the number of decisions above which the expired
+	// decisions are evicted from the cache.
+	maxCacheSize = 10000
+
+	// maxResponseSize is the maximum size of the responses of the webhook.
+	maxResponseSize = 1 << 16
+)
+
+// Request is the body posted to the webhook.
+type Request struct {
+	Input Input `json:"input"`
+}
+
+// Input describes the request to authorize.
+type Input struct {
+	User         string   `json:"user"`
+	Groups       []string `json:"groups"`
+	Verb         string   `json:"verb"`
+	APIGroup     string   `json:"api_group"`
+	APIVersion   string   `json:"api_version"`
+	Namespace    string   `json:"namespace"`
+	Resource     string   `json:"resource"`
+	ResourceName string   `json:"resource_name"`
+}
+
+// Response is the body of the responses of the webhook.
+type Response struct {
+	Result Decision `json:"result"`
+}
+
+// Decision is the decision of the webhook. A request that is neither allowed
+// nor denied is authorized by the next authorizer.
+type Decision struct {
+	Allowed bool   `json:"allowed"`
+	Denied  bool   `json:"denied"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// cachedDecision is a decision of the webhook, cached until it expires.
+type cachedDecision struct {
+	decision  Decision
+	expiresAt time.Time
+}
+
+// cacheKey identifies the requests sharing the same decision.
+type cacheKey struct {
+	authorization.AttributesKey
+	Groups string
+}
+
+// Config configures an Authorizer.
+type Config struct {
+	// URL is the HTTPS URL of the webhook.
+	URL string
+
+	// CacheTTL is how long the decisions of the webhook are cached. The
+	// decisions are not cached when negative.
+	CacheTTL time.Duration
+
+	// Client is the HTTP client posting to the webhook. A client with
+	// DefaultTimeout is used if nil.
+	Client *http.Client
+
+	// Next authorizes the requests the webhook has no opinion on. They are
+	// denied if nil.
+	Next authorization.Authorizer
+
+	// FailOpen authorizes the requests with Next while the webhook is
+	// unavailable, as if it had no opinion on them. By default, the webhook
+	// fails closed: the requests are rejected while it is unavailable.
+	FailOpen bool
+}
+
+// Authorizer implements an authorizer interface by posting the attributes of
+// the requests to a webhook, and caching its decisions.
+type Authorizer struct {
+	url      string
+	ttl      time.Duration
+	client   *http.Client
+	next     authorization.Authorizer
+	failOpen bool
+
+	mu    sync.Mutex
+	cache map[cacheKey]cachedDecision
+	now   func() time.Time
+}
+
+// New creates an Authorizer from its configuration.
+func New(cfg Config) (*Authorizer, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid authorization webhook URL: %s", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return nil, errors.New("the authorization webhook URL must be an https URL")
+	}
+	a := &Authorizer{
+		url:      cfg.URL,
+		ttl:      cfg.CacheTTL,
+		client:   cfg.Client,
+		next:     cfg.Next,
+		failOpen: cfg.FailOpen,
+		cache:    make(map[cacheKey]cachedDecision),
+		now:      time.Now,
+	}
+	if a.ttl == 0 {
+		a.ttl = DefaultCacheTTL
+	}
+	if a.client == nil {
+		a.client = &http.Client{Timeout: DefaultTimeout}
+	}
+	return a, nil
+}
+
+// Authorize implements authorization.Authorizer. The requests are denied if
+// the webhook cannot be reached, unless the authorizer fails open.
+func (a *Authorizer) Authorize(ctx context.Context, attrs *authorization.Attributes) (bool, error) {
+	decision, err := a.decide(ctx, attrs)
+	if err != nil {
+		if !a.failOpen {
+			return false, err
+		}
+		logger.WithError(err).WithField("user", attrs.User.Username).
+			Warn("authorization webhook unavailable, authorizing the request without it")
+		decision = Decision{}
+	}
+	switch {
+	case decision.Denied:
+		logger.WithField("user", attrs.User.Username).WithField("reason", decision.Reason).
+			Debug("request denied by the authorization webhook")
+		return false, nil
+	case decision.Allowed:
+		return true, nil
+	case a.next != nil:
+		return a.next.Authorize(ctx, attrs)
+	default:
+		return false, nil
+	}
+}
+
+// decide returns the decision of the webhook for the attributes, from the
+// cache if possible.
+func (a *Authorizer) decide(ctx context.Context, attrs *authorization.Attributes) (Decision, error) {
+	groups := append([]string(nil), attrs.User.Groups...)
+	sort.Strings(groups)
+	key := cacheKey{AttributesKey: attrs.Key(), Groups: strings.Join(groups, "\n")}
+
+	now := a.now()
+	a.mu.Lock()
+	cached, ok := a.cache[key]
+	a.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.decision, nil
+	}
+
+	response, err := a.post(ctx, &Request{Input: Input{
+		User:         attrs.User.Username,
+		Groups:       attrs.User.Groups,
+		Verb:         attrs.Verb,
+		APIGroup:     attrs.APIGroup,
+		APIVersion:   attrs.APIVersion,
+		Namespace:    attrs.Namespace,
+		Resource:     attrs.Resource,
+		ResourceName: attrs.ResourceName,
+	}})
+	if err != nil {
+		return Decision{}, err
+	}
+	if a.ttl > 0 {
+		a.store(key, cachedDecision{decision: response.Result, expiresAt: now.Add(a.ttl)}, now)
+	}
+	return response.Result, nil
+}
+
+// store caches a decision, evicting the expired decisions when the cache is
+// full.
+func (a *Authorizer) store(key cacheKey, d cachedDecision, now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.cache) >= maxCacheSize {
+		for k, v := range a.cache {
+			if !now.Before(v.expiresAt) {
+				delete(a.cache, k)
+			}
+		}
+		if len(a.cache) >= maxCacheSize {
+			a.cache = make(map[cacheKey]cachedDecision)
+		}
+	}
+	a.cache[key] = d
+}
+
+func (a *Authorizer) post(ctx context.Context, request *Request) (Response, error) {
+	var response Response
+	b, err := json.Marshal(request)
+	if err != nil {
+		return response, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(b))
+	if err != nil {
+		return response, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return response, fmt.Errorf("authorization webhook: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return response, fmt.Errorf("authorization webhook responded with status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&response); err != nil {
+		return response, fmt.Errorf("invalid authorization webhook response: %s", err)
+	}
+	return response, nil
+}
@@ -0,0 +1,162 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/authorization"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticAuthorizer bool
+
+func (s staticAuthorizer) Authorize(context.Context, *authorization.Attributes) (bool, error) {
+	return bool(s), nil
+}
+
+// newWebhook starts a webhook allowing the requests of alice, denying the
+// requests of bob and having no opinion on the others.
+func newWebhook(t *testing.T, calls *int32) *httptest.Server {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var resp Response
+		switch input := req.Input; input.User {
+		case "alice":
+			resp.Result.Allowed = input.Verb == "get" && input.Resource == "checks" && input.Namespace == "default"
+		case "bob":
+			resp.Result.Denied = true
+			resp.Result.Reason = "bob is not allowed"
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func attributes(user string) *authorization.Attributes {
+	return &authorization.Attributes{
+		APIGroup:   "core",
+		APIVersion: "v2",
+		Namespace:  "default",
+		Resource:   "checks",
+		User:       corev2.User{Username: user, Groups: []string{"ops"}},
+		Verb:       "get",
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	var calls int32
+	server := newWebhook(t, &calls)
+	a, err := New(Config{URL: server.URL, Client: server.Client(), Next: staticAuthorizer(true)})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		attrs *authorization.Attributes
+		want  bool
+	}{
+		{
+			name:  "allowed",
+			attrs: attributes("alice"),
+			want:  true,
+		},
+		{
+			name:  "denied",
+			attrs: attributes("bob"),
+			want:  false,
+		},
+		{
+			name:  "no opinion",
+			attrs: attributes("carol"),
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.Authorize(ctx, tt.attrs)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// Without a next authorizer, the requests the webhook has no opinion on
+	// are denied
+	a.next = nil
+	got, err := a.Authorize(ctx, attributes("dave"))
+	require.NoError(t, err)
+	assert.False(t, got)
+}
+
+func TestAuthorizeCache(t *testing.T) {
+	var calls int32
+	server := newWebhook(t, &calls)
+	a, err := New(Config{URL: server.URL, Client: server.Client(), CacheTTL: time.Minute})
+	require.NoError(t, err)
+	now := time.Now()
+	a.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		got, err := a.Authorize(ctx, attributes("alice"))
+		require.NoError(t, err)
+		assert.True(t, got)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// The decisions depend on the groups of the users
+	attrs := attributes("alice")
+	attrs.User.Groups = []string{"dev"}
+	_, err = a.Authorize(ctx, attrs)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// The webhook is asked again once the decision expired
+	now = now.Add(time.Minute)
+	_, err = a.Authorize(ctx, attributes("alice"))
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestAuthorizeUnavailable(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	a, err := New(Config{URL: server.URL, Client: server.Client(), Next: staticAuthorizer(true)})
+	require.NoError(t, err)
+
+	got, err := a.Authorize(context.Background(), attributes("alice"))
+	assert.Error(t, err)
+	assert.False(t, got)
+
+	// Failing open, the requests are authorized by the next authorizer
+	a.failOpen = true
+	got, err = a.Authorize(context.Background(), attributes("alice"))
+	require.NoError(t, err)
+	assert.True(t, got)
+
+	a.next = staticAuthorizer(false)
+	got, err = a.Authorize(context.Background(), attributes("alice"))
+	require.NoError(t, err)
+	assert.False(t, got)
+}
+
+func TestNewRequiresHTTPS(t *testing.T) {
+	_, err := New(Config{URL: "http://opa.example.com/v1/data/sensu/authz"})
+	assert.Error(t, err)
+	_, err = New(Config{URL: "https://opa.example.com/v1/data/sensu/authz"})
+	assert.NoError(t, err)
+}
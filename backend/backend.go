@@ -3,15 +3,22 @@ package backend
 import (
 	"context"
 	"crypto/tls"
+	"database/sql"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/ghodss/yaml"
 	"github.com/lib/pq"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sensu/sensu-go/backend/queue"
+	"github.com/sensu/sensu-go/backend/quota"
 	"github.com/sensu/sensu-go/backend/resource"
+	"github.com/sensu/sensu-go/backend/retention"
 	"github.com/spf13/viper"
 	"golang.org/x/time/rate"
 
@@ -22,13 +29,18 @@ import (
 	"github.com/sensu/sensu-go/backend/apid"
 	"github.com/sensu/sensu-go/backend/apid/actions"
 	"github.com/sensu/sensu-go/backend/apid/graphql"
+	"github.com/sensu/sensu-go/backend/apid/middlewares"
 	"github.com/sensu/sensu-go/backend/apid/routers"
+	"github.com/sensu/sensu-go/backend/audit"
 	"github.com/sensu/sensu-go/backend/authentication"
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
 	"github.com/sensu/sensu-go/backend/authentication/providers/basic"
+	"github.com/sensu/sensu-go/backend/authorization"
 	"github.com/sensu/sensu-go/backend/authorization/rbac"
+	"github.com/sensu/sensu-go/backend/authorization/webhook"
 	"github.com/sensu/sensu-go/backend/daemon"
 	"github.com/sensu/sensu-go/backend/eventd"
+	"github.com/sensu/sensu-go/backend/kafka"
 	"github.com/sensu/sensu-go/backend/keepalived"
 	"github.com/sensu/sensu-go/backend/licensing"
 	"github.com/sensu/sensu-go/backend/logging"
@@ -38,12 +50,21 @@ import (
 	"github.com/sensu/sensu-go/backend/pipeline/handler"
 	"github.com/sensu/sensu-go/backend/pipeline/mutator"
 	"github.com/sensu/sensu-go/backend/pipelined"
+	"github.com/sensu/sensu-go/backend/remotewrite"
 	"github.com/sensu/sensu-go/backend/ringv2"
 	"github.com/sensu/sensu-go/backend/schedulerd"
 	"github.com/sensu/sensu-go/backend/secrets"
+	k8ssecrets "github.com/sensu/sensu-go/backend/secrets/kubernetes"
+	"github.com/sensu/sensu-go/backend/seeds"
+	"github.com/sensu/sensu-go/backend/store"
+	cachev2 "github.com/sensu/sensu-go/backend/store/cache/v2"
+	"github.com/sensu/sensu-go/backend/store/encryption"
+	"github.com/sensu/sensu-go/backend/store/history"
 	"github.com/sensu/sensu-go/backend/store/postgres"
+	"github.com/sensu/sensu-go/backend/store/sqlite"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 	"github.com/sensu/sensu-go/backend/tessend"
+	"github.com/sensu/sensu-go/backend/tracing"
 	"github.com/sensu/sensu-go/command"
 	"github.com/sensu/sensu-go/metrics"
 	"github.com/sensu/sensu-go/system"
@@ -138,6 +159,16 @@ type Backend struct {
 	Bus                    messaging.MessageBus
 
 	Cfg *Config
+
+	// sqliteDB is set when the backend stores its resources in sqlite.
+	sqliteDB *sql.DB
+}
+
+// operatorConcierge keeps track of the operators of the backend.
+type operatorConcierge interface {
+	store.OperatorConcierge
+	store.OperatorMonitor
+	store.OperatorQueryer
 }
 
 func errorReporter(event pq.ListenerEventType, err error) {
@@ -164,21 +195,66 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 	b := &Backend{Cfg: config}
 
 	// Initialize the bus
-	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{
+		OverflowPolicies: config.BusOverflowPolicies,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error initializing %s: %s", bus.Name(), err)
 	}
 	b.Bus = bus
 	b.Daemons = append(b.Daemons, bus)
 
-	b.Store = postgres.NewStore(postgres.StoreConfig{
-		DB:                pgdb,
-		WatchInterval:     time.Second,
-		WatchTxnWindow:    5 * time.Second,
-		Bus:               bus,
-		MaxTPS:            config.Store.PostgresStore.MaxTPS,
-		DisableEventCache: config.Store.PostgresStore.DisableEventCache,
-	})
+	// Initialize the postgres notification bus
+	var pgBus *postgres.Bus
+	if !config.Store.Standalone() {
+		listener := pq.NewListener(config.Store.PostgresStore.DSN, time.Second, time.Minute, errorReporter)
+		pgBus = postgres.NewBus(ctx, listener)
+	}
+
+	if path := config.Store.SQLiteStore.Path; path != "" {
+		// Resources are stored in sqlite. Unless the backend is standalone,
+		// postgres is still used for cluster coordination (operator state,
+		// queues, rings and bus).
+		b.sqliteDB, err = sqlite.Open(ctx, path)
+		if err != nil {
+			return nil, fmt.Errorf("error opening sqlite store: %s", err)
+		}
+		logger.WithField("path", path).Info("using sqlite store")
+		b.Store = sqlite.NewStore(sqlite.StoreConfig{
+			DB:                b.sqliteDB,
+			WatchInterval:     time.Second,
+			Bus:               bus,
+			MaxTPS:            config.Store.PostgresStore.MaxTPS,
+			DisableEventCache: config.Store.PostgresStore.DisableEventCache,
+		})
+	} else {
+		b.Store = postgres.NewStore(postgres.StoreConfig{
+			DB:                pgdb,
+			WatchInterval:     time.Second,
+			WatchTxnWindow:    5 * time.Second,
+			Bus:               bus,
+			MaxTPS:            config.Store.PostgresStore.MaxTPS,
+			DisableEventCache: config.Store.PostgresStore.DisableEventCache,
+			Notifications:     pgBus,
+		})
+	}
+
+	// The versions of the resources are recorded as stored, with their
+	// sensitive fields encrypted
+	b.Store = history.NewStore(b.Store)
+
+	if config.Store.Encryption.Enabled() {
+		kek, err := encryption.NewKEK(config.Store.Encryption)
+		if err != nil {
+			return nil, fmt.Errorf("error configuring store encryption: %s", err)
+		}
+		b.Store = encryption.NewStore(b.Store, encryption.NewEncrypter(kek))
+	}
+
+	// Update the resources seeded by older versions of the backend
+	if err := seeds.MigrateClusterRoles(ctx, b.Store); err != nil {
+		return nil, err
+	}
 
 	jwtClient := api.JWT{Store: b.Store}
 	jwtSecret, err := jwtClient.GetSecret(ctx)
@@ -210,6 +286,15 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 	if err != nil {
 		return nil, fmt.Errorf("error initializing asset manager: %s", err)
 	}
+	var assetCache *asset.ArchiveCache
+	if config.AgentAssetProxy {
+		assetCache, err = asset.NewArchiveCache(filepath.Join(config.CacheDir, "asset-proxy"), trustedCAFile, rate.NewLimiter(limit, b.Cfg.AssetsBurstLimit))
+		if err != nil {
+			return nil, fmt.Errorf("error initializing asset proxy: %s", err)
+		}
+		assetCache.SetLimits(config.AgentAssetProxyCacheMaxSize, config.AgentAssetProxyCacheMaxAge)
+		go assetCache.Run(ctx)
+	}
 
 	// Create sensu-system namespace and backend entity
 	br := resource.New(b.Store.GetNamespaceStore(), b.Store.GetEntityConfigStore(), b.Store.GetEntityStateStore(), bus)
@@ -219,14 +304,46 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 
 	// Initialize the secrets provider manager
 	b.SecretsProviderManager = secrets.NewProviderManager(br)
+	if config.KubernetesSecrets {
+		if len(config.KubernetesSecretsNamespaces) == 0 {
+			return nil, errors.New("error initializing kubernetes secrets provider: no kubernetes namespace is configured for the sensu namespaces")
+		}
+		k8sConfig, err := k8ssecrets.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("error initializing kubernetes secrets provider: %s", err)
+		}
+		k8sConfig.Watch = config.KubernetesSecretsWatch
+		provider, err := k8ssecrets.New(ctx, k8sConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error initializing kubernetes secrets provider: %s", err)
+		}
+		b.SecretsProviderManager.AddProvider(provider)
+		b.SecretsProviderManager.Getter = k8ssecrets.Getter{Namespaces: config.KubernetesSecretsNamespaces}
+	}
 
-	auth := &rbac.Authorizer{Store: b.Store}
+	var auth authorization.Authorizer = &rbac.Authorizer{Store: b.Store}
+	if url := config.AuthorizationWebhookURL; url != "" {
+		auth, err = webhook.New(webhook.Config{
+			URL:      url,
+			CacheTTL: config.AuthorizationWebhookCacheTTL,
+			Next:     auth,
+			FailOpen: config.AuthorizationWebhookFailOpen,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error initializing authorization webhook: %s", err)
+		}
+	}
 
 	// Initialize pipelined
+	var pipelinedWALDir string
+	if viper.GetBool(FlagPipelinedDurable) {
+		pipelinedWALDir = filepath.Join(config.CacheDir, "pipelined")
+	}
 	pipelineDaemon, err := pipelined.New(pipelined.Config{
 		Bus:         bus,
 		BufferSize:  viper.GetInt(FlagPipelinedBufferSize),
 		WorkerCount: viper.GetInt(FlagPipelinedWorkers),
+		WALDir:      pipelinedWALDir,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error initializing %s: %s", pipelineDaemon.Name(), err)
@@ -281,6 +398,7 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 		SecretsProviderManager: b.SecretsProviderManager,
 		Store:                  b.Store,
 		StoreTimeout:           storeTimeout,
+		RecordResults:          viper.GetBool(FlagPipelinedHandlerResults),
 	}
 
 	b.PipelineAdapterV1.HandlerAdapters = []pipeline.HandlerAdapter{
@@ -290,9 +408,21 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 	pipelineDaemon.AddAdapter(&b.PipelineAdapterV1)
 	b.Daemons = append(b.Daemons, pipelineDaemon)
 
-	pgOPC := postgres.NewOPC(pgdb)
+	var opc operatorConcierge
+	if config.Store.Standalone() {
+		opc = sqlite.NewOPC(b.sqliteDB)
+	} else {
+		opc = postgres.NewOPC(pgdb)
+	}
+
+	go CheckInLoop(ctx, b.Cfg.Name, opc)
 
-	go CheckInLoop(ctx, b.Cfg.Name, pgOPC)
+	// The keepalive and check TTL timeouts are jittered, so that the operators
+	// checking in together don't expire together
+	var jitteredOPC store.OperatorConcierge = opc
+	if config.OperatorTimeoutJitter > 0 {
+		jitteredOPC = store.NewJitteredOperatorConcierge(opc, config.OperatorTimeoutJitter)
+	}
 
 	// Initialize eventd
 	event, err := eventd.New(
@@ -307,9 +437,9 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 			LogBufferSize:       b.Cfg.EventLogBufferSize,
 			LogBufferWait:       b.Cfg.EventLogBufferWait,
 			LogParallelEncoders: b.Cfg.EventLogParallelEncoders,
-			OperatorConcierge:   pgOPC,
-			OperatorMonitor:     pgOPC,
-			OperatorQueryer:     pgOPC,
+			OperatorConcierge:   jitteredOPC,
+			OperatorMonitor:     opc,
+			OperatorQueryer:     opc,
 			BackendName:         b.Cfg.Name,
 		},
 	)
@@ -319,8 +449,25 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 	b.Daemons = append(b.Daemons, event)
 
 	// Initialize work queue
-	pgQueue := postgres.NewQueue(pgdb)
-	workQueue := queue.NewClusteredQueue(pgQueue, b.Cfg.Name, pgOPC)
+	var queueClient queue.Client
+	if config.Store.Standalone() {
+		queueClient = queue.NewMemoryClient()
+	} else {
+		queueClient = postgres.NewQueue(pgdb)
+	}
+	workQueue := queue.NewClusteredQueue(queueClient, b.Cfg.Name, opc)
+
+	// Initialize the round-robin rings of the subscriptions
+	ringPool := ringv2.NewRingPool(func(path string) ringv2.Interface {
+		if config.Store.Standalone() {
+			return ringv2.NewMemoryRing()
+		}
+		ring, err := postgres.NewRing(pgdb, pgBus, path)
+		if err != nil {
+			logger.WithError(err).Error("error creating ring")
+		}
+		return ring
+	})
 
 	// Initialize schedulerd
 	scheduler, err := schedulerd.New(
@@ -330,6 +477,7 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 			Bus:                    bus,
 			SecretsProviderManager: b.SecretsProviderManager,
 			Queue:                  workQueue,
+			RingPool:               ringPool,
 		})
 	if err != nil {
 		return nil, fmt.Errorf("error initializing %s: %s", scheduler.Name(), err)
@@ -353,8 +501,8 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 		BufferSize:            viper.GetInt(FlagKeepalivedBufferSize),
 		WorkerCount:           viper.GetInt(FlagKeepalivedWorkers),
 		StoreTimeout:          2 * time.Minute,
-		OperatorConcierge:     pgOPC,
-		OperatorMonitor:       pgOPC,
+		OperatorConcierge:     jitteredOPC,
+		OperatorMonitor:       opc,
 		BackendName:           b.Cfg.Name,
 	})
 	if err != nil {
@@ -362,6 +510,39 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 	}
 	b.Daemons = append(b.Daemons, keepalive)
 
+	// Initialize the event retention pruner
+	retentionPolicies, err := cachev2.New[*retention.EventRetentionPolicy](ctx, b.Store, false)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing the event retention policies: %s", err)
+	}
+	pruner, err := retention.NewPruner(retention.PrunerConfig{
+		Policies:    retentionPolicies,
+		EventStore:  b.Store.GetEventStore(),
+		EntityStore: b.Store.GetEntityStore(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error initializing %s: %s", pruner.Name(), err)
+	}
+	b.Daemons = append(b.Daemons, pruner)
+
+	// Initialize the Kafka bridge
+	if config.KafkaRESTProxyURL != "" {
+		bridge, err := newKafkaBridge(config, bus)
+		if err != nil {
+			return nil, fmt.Errorf("error initializing the Kafka bridge: %s", err)
+		}
+		b.Daemons = append(b.Daemons, bridge)
+	}
+
+	// Initialize the Prometheus remote-write exporter
+	if config.RemoteWriteURL != "" {
+		exporter, err := newRemoteWriteExporter(config, bus)
+		if err != nil {
+			return nil, fmt.Errorf("error initializing the remote-write exporter: %s", err)
+		}
+		b.Daemons = append(b.Daemons, exporter)
+	}
+
 	// Prepare the authentication providers
 	authenticator := &authentication.Authenticator{}
 	provider := &basic.Provider{
@@ -382,40 +563,68 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 
 	// Initialize GraphQL service
 	b.GraphQLService, err = graphql.NewService(graphql.ServiceConfig{
-		AssetClient:       api.NewAssetClient(b.Store, auth),
-		CheckClient:       api.NewCheckClient(b.Store, actions.NewCheckController(b.Store, workQueue), auth),
-		EntityClient:      api.NewEntityClient(b.Store, auth),
-		EventClient:       api.NewEventClient(b.Store.GetEventStore(), auth, bus),
-		EventFilterClient: api.NewEventFilterClient(b.Store, auth),
-		HandlerClient:     api.NewHandlerClient(b.Store, auth),
-		HealthController:  actions.HealthController{},
-		MutatorClient:     api.NewMutatorClient(b.Store, auth),
-		SilencedClient:    api.NewSilencedClient(b.Store.GetSilencesStore(), auth),
-		NamespaceClient:   api.NewNamespaceClient(b.Store, auth),
-		HookClient:        api.NewHookConfigClient(b.Store, auth),
-		UserClient:        api.NewUserClient(b.Store, auth),
-		RBACClient:        api.NewRBACClient(b.Store, auth),
-		VersionController: actions.NewVersionController(clusterVersion),
-		MetricGatherer:    prometheus.DefaultGatherer,
-		GenericClient:     &api.GenericClient{Store: b.Store, Auth: auth},
+		AssetClient:             api.NewAssetClient(b.Store, auth),
+		CheckClient:             api.NewCheckClient(b.Store, actions.NewCheckController(b.Store, workQueue), auth),
+		EntityClient:            api.NewEntityClient(b.Store, auth),
+		EventClient:             api.NewEventClient(b.Store.GetEventStore(), auth, bus),
+		EventSubscriptionClient: api.NewEventSubscriptionClient(bus, auth),
+		EventFilterClient:       api.NewEventFilterClient(b.Store, auth),
+		HandlerClient:           api.NewHandlerClient(b.Store, auth),
+		HealthController:        actions.HealthController{},
+		MutatorClient:           api.NewMutatorClient(b.Store, auth),
+		SilencedClient:          api.NewSilencedClient(b.Store.GetSilencesStore(), auth),
+		NamespaceClient:         api.NewNamespaceClient(b.Store, auth),
+		HookClient:              api.NewHookConfigClient(b.Store, auth),
+		UserClient:              api.NewUserClient(b.Store, auth),
+		RBACClient:              api.NewRBACClient(b.Store, auth),
+		VersionController:       actions.NewVersionController(clusterVersion),
+		MetricGatherer:          prometheus.DefaultGatherer,
+		GenericClient:           &api.GenericClient{Store: b.Store, Auth: auth},
+		PersistedQueriesFile:    config.GraphQLPersistedQueriesFile,
+		RequirePersistedQueries: config.GraphQLRequirePersistedQueries,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error initializing graphql.Service: %s", err)
 	}
 
+	// Initialize the audit log
+	auditor, err := newAuditor(ctx, config, bus, b.Store)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing the audit log: %s", err)
+	}
+
+	quotaCache, err := cachev2.New[*quota.NamespaceQuota](ctx, b.Store, false)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing the namespace quotas: %s", err)
+	}
+	quotas := quota.NewEnforcer(quotaCache, b.Store)
+
+	// The agents connected to agentd are drained through apid
+	drainer := agentd.NewDrainer()
+
 	// Initialize apid
 	b.APIDConfig = apid.Config{
-		ListenAddress:  config.APIListenAddress,
-		RequestLimit:   config.APIRequestLimit,
-		WriteTimeout:   config.APIWriteTimeout,
-		URL:            config.APIURL,
-		Bus:            bus,
-		Store:          b.Store,
-		TLS:            config.TLS,
-		Authenticator:  authenticator,
-		ClusterVersion: clusterVersion,
-		GraphQLService: b.GraphQLService,
-		Queue:          workQueue,
+		ListenAddress:          config.APIListenAddress,
+		RequestLimit:           config.APIRequestLimit,
+		WriteTimeout:           config.APIWriteTimeout,
+		URL:                    config.APIURL,
+		Bus:                    bus,
+		Store:                  b.Store,
+		TLS:                    config.TLS,
+		Authenticator:          authenticator,
+		ClusterVersion:         clusterVersion,
+		GraphQLService:         b.GraphQLService,
+		Queue:                  workQueue,
+		Auditor:                auditor,
+		Quotas:                 quotas,
+		ReadOnly:               middlewares.NewReadOnlyMode(config.APIReadOnly),
+		AgentDrainer:           drainer,
+		Authorizer:             auth,
+		MFARequired:            config.MFARequired,
+		SecretsProviderManager: b.SecretsProviderManager,
+	}
+	if config.APIRateLimit > 0 || len(config.APINamespaceRateLimits) > 0 {
+		b.APIDConfig.RateLimiter = middlewares.NewRateLimiter(config.APIRateLimit, config.APIBurstLimit, config.APINamespaceRateLimits)
 	}
 	newApi, err := apid.New(b.APIDConfig)
 	if err != nil {
@@ -424,18 +633,6 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 	b.Daemons = append(b.Daemons, newApi)
 
 	// Initialize tessend
-	pgDSN := b.Cfg.Store.PostgresStore.DSN
-	listener := pq.NewListener(pgDSN, time.Second, time.Minute, errorReporter)
-	pgBus := postgres.NewBus(ctx, listener)
-
-	ringPool := ringv2.NewRingPool(func(path string) ringv2.Interface {
-		ring, err := postgres.NewRing(pgdb, pgBus, path)
-		if err != nil {
-			logger.WithError(err).Error("error creating tessen ring")
-		}
-		return ring
-	})
-
 	var clusterID string
 	if clusterID, err = GetClusterID(ctx, b.Store); err != nil {
 		return nil, err
@@ -449,7 +646,7 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 			RingPool:   ringPool,
 			Bus:        bus,
 			ClusterID:  clusterID,
-			OPCQueryer: pgOPC,
+			OPCQueryer: opc,
 		})
 	if err != nil {
 		return nil, fmt.Errorf("error initializing %s: %s", tessen.Name(), err)
@@ -467,6 +664,12 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 		Watcher:       entityConfigWatcher,
 		HealthRouter:  b.HealthRouter,
 		Authenticator: authenticator,
+		Quotas:        quotas,
+		RingPool:      ringPool,
+
+		CompressionLevel: config.AgentCompressionLevel,
+		Drainer:          drainer,
+		AssetCache:       assetCache,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error initializing %s: %s", agent.Name(), err)
@@ -476,6 +679,87 @@ func Initialize(ctx context.Context, pgdb postgres.DBI, config *Config) (*Backen
 	return b, nil
 }
 
+// newAuditor creates the auditor writing to the audit sinks of the
+// configuration, or returns nil if none is configured.
+func newAuditor(ctx context.Context, config *Config, bus messaging.MessageBus, store storev2.Interface) (*audit.Auditor, error) {
+	var sinks []audit.Sink
+	if path := config.AuditLogFile; path != "" {
+		sighup := make(messaging.ChanSubscriber, 1)
+		if _, err := bus.Subscribe(messaging.SignalTopic(syscall.SIGHUP), "filelogger://"+path, sighup); err != nil {
+			return nil, err
+		}
+		writer, err := logging.NewRotateWriter(path, sighup)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, audit.NewWriterSink(writer))
+	}
+	if url := config.AuditWebhookURL; url != "" {
+		sinks = append(sinks, audit.NewWebhookSink(url))
+	}
+	if config.AuditStore {
+		sinks = append(sinks, audit.NewStoreSink(store.GetAuditStore()))
+	}
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+
+	policies, err := cachev2.New[*audit.AuditPolicy](ctx, store, false)
+	if err != nil {
+		return nil, err
+	}
+	return audit.New(ctx, policies, sinks...), nil
+}
+
+// newKafkaBridge creates the bridge producing the events and keepalives of the
+// bus to the Kafka topics of the configuration.
+func newKafkaBridge(config *Config, bus messaging.MessageBus) (*kafka.Bridge, error) {
+	topics := make(map[string]string)
+	if topic := config.KafkaEventTopic; topic != "" {
+		topics[messaging.TopicEvent] = topic
+	}
+	if topic := config.KafkaKeepaliveTopic; topic != "" {
+		topics[messaging.TopicKeepalive] = topic
+	}
+	var schema []byte
+	if path := config.KafkaAvroSchemaFile; path != "" {
+		var err error
+		if schema, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return kafka.New(kafka.Config{
+		Bus:           bus,
+		RESTProxyURL:  config.KafkaRESTProxyURL,
+		Topics:        topics,
+		Serialization: config.KafkaSerialization,
+		AvroSchema:    string(schema),
+		AvroSchemaID:  config.KafkaAvroSchemaID,
+	})
+}
+
+// newRemoteWriteExporter creates the exporter writing the metric points of the
+// events to the Prometheus remote-write endpoint of the configuration.
+func newRemoteWriteExporter(config *Config, bus messaging.MessageBus) (*remotewrite.Exporter, error) {
+	var relabelConfigs []remotewrite.RelabelConfig
+	if path := config.RemoteWriteRelabelConfigFile; path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(b, &relabelConfigs); err != nil {
+			return nil, fmt.Errorf("invalid relabeling rules in %s: %s", path, err)
+		}
+	}
+	return remotewrite.New(remotewrite.Config{
+		Bus:            bus,
+		URL:            config.RemoteWriteURL,
+		RelabelConfigs: relabelConfigs,
+		BatchSize:      config.RemoteWriteBatchSize,
+		FlushInterval:  config.RemoteWriteFlushInterval,
+	})
+}
+
 // Run starts all of the Backend server's daemons
 func (b *Backend) Run(ctx context.Context) error {
 	var derr error
@@ -486,6 +770,27 @@ func (b *Backend) Run(ctx context.Context) error {
 
 	defer eg.WaitStop()
 
+	if b.sqliteDB != nil {
+		defer func() { _ = b.sqliteDB.Close() }()
+	}
+
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		OTLPEndpoint: b.Cfg.TracingOTLPEndpoint,
+		OTLPInsecure: b.Cfg.TracingOTLPInsecure,
+		SampleRatio:  b.Cfg.TracingSampleRatio,
+		BackendName:  b.Cfg.Name,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		tctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(tctx); err != nil {
+			logger.WithError(err).Error("error flushing traces")
+		}
+	}()
+
 	// crash the stopgroup after a hard-coded timeout
 	sg := &stopGroup{crashOnTimeout: true, waitTime: 30 * time.Second}
 
@@ -554,6 +859,7 @@ func (b *Backend) Run(ctx context.Context) error {
 			derr = err
 		}
 	}
+	handler.CloseGRPCStreams()
 	if derr == nil && ctx.Err() != context.Canceled {
 		derr = ctx.Err()
 	}
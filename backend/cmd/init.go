@@ -10,7 +10,10 @@ import (
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/sensu/sensu-go/backend"
 	"github.com/sensu/sensu-go/backend/seeds"
+	"github.com/sensu/sensu-go/backend/store/encryption"
 	"github.com/sensu/sensu-go/backend/store/postgres"
+	"github.com/sensu/sensu-go/backend/store/sqlite"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -98,8 +101,17 @@ func InitCommand() *cobra.Command {
 			cfg := &backend.Config{
 				Store: backend.StoreConfig{
 					PostgresStore: postgres.Config{
-						DSN: viper.GetString(flagPGDSN),
+						DSN:              viper.GetString(flagPGDSN),
+						MaxConns:         viper.GetInt32(flagPGMaxConns),
+						MinConns:         viper.GetInt32(flagPGMinConns),
+						MaxConnLifetime:  viper.GetDuration(flagPGMaxConnLifetime),
+						MaxConnIdleTime:  viper.GetDuration(flagPGMaxConnIdleTime),
+						StatementTimeout: viper.GetDuration(flagPGStatementTimeout),
 					},
+					SQLiteStore: sqlite.Config{
+						Path: viper.GetString(flagSQLitePath),
+					},
+					Encryption: storeEncryptionConfig(),
 				},
 			}
 
@@ -166,14 +178,44 @@ func InitCommand() *cobra.Command {
 func initializeStore(cfg initConfig) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	pgdb, err := newPostgresPool(ctx, cfg.Store.PostgresStore.DSN)
+	store, closeStore, err := openStore(ctx, cfg.Store)
 	if err != nil {
 		return err
 	}
-	defer pgdb.Close()
-
-	store := postgres.NewStore(postgres.StoreConfig{
-		DB: pgdb,
-	})
+	defer closeStore()
 	return seeds.SeedCluster(ctx, store, cfg.SeedConfig)
 }
+
+// openStore opens the store of the configuration, encrypting the sensitive
+// fields of the resources if configured. The returned function closes it.
+func openStore(ctx context.Context, cfg backend.StoreConfig) (storev2.Interface, func(), error) {
+	var store storev2.Interface
+	var closeStore func()
+	if path := cfg.SQLiteStore.Path; path != "" {
+		// The resources are stored in sqlite
+		db, err := sqlite.Open(ctx, path)
+		if err != nil {
+			return nil, nil, err
+		}
+		store = sqlite.NewStore(sqlite.StoreConfig{DB: db})
+		closeStore = func() { _ = db.Close() }
+	} else {
+		pgdb, err := newPostgresPool(ctx, cfg.PostgresStore)
+		if err != nil {
+			return nil, nil, err
+		}
+		store = postgres.NewStore(postgres.StoreConfig{
+			DB: pgdb,
+		})
+		closeStore = pgdb.Close
+	}
+	if cfg.Encryption.Enabled() {
+		kek, err := encryption.NewKEK(cfg.Encryption)
+		if err != nil {
+			closeStore()
+			return nil, nil, fmt.Errorf("error configuring store encryption: %s", err)
+		}
+		store = encryption.NewStore(store, encryption.NewEncrypter(kek))
+	}
+	return store, closeStore, nil
+}
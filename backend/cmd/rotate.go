@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/sensu/sensu-go/backend"
+	"github.com/sensu/sensu-go/backend/store/encryption"
+	"github.com/sensu/sensu-go/backend/store/postgres"
+	"github.com/sensu/sensu-go/backend/store/sqlite"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// RotateEncryptionKeyCommand re-encrypts the sensitive fields of the resources
+// of the store with the current key encryption key.
+func RotateEncryptionKeyCommand() *cobra.Command {
+	var setupErr error
+
+	cmd := &cobra.Command{
+		Use:   "rotate-encryption-key",
+		Short: "re-encrypt the sensitive fields of the resources with the current encryption key",
+		Long: `Re-encrypt the sensitive fields of the resources with the current encryption key.
+
+The first key of --store-encryption-key-file, or the vault transit key, encrypts
+the fields. The keys previously used must remain in the key file until the
+rotation completes, after which they can be removed.`,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_ = viper.BindPFlags(cmd.Flags())
+			if setupErr != nil {
+				return setupErr
+			}
+
+			cfg := backend.StoreConfig{
+				PostgresStore: postgres.Config{
+					DSN:              viper.GetString(flagPGDSN),
+					MaxConns:         viper.GetInt32(flagPGMaxConns),
+					MinConns:         viper.GetInt32(flagPGMinConns),
+					MaxConnLifetime:  viper.GetDuration(flagPGMaxConnLifetime),
+					MaxConnIdleTime:  viper.GetDuration(flagPGMaxConnIdleTime),
+					StatementTimeout: viper.GetDuration(flagPGStatementTimeout),
+				},
+				SQLiteStore: sqlite.Config{
+					Path: viper.GetString(flagSQLitePath),
+				},
+				Encryption: storeEncryptionConfig(),
+			}
+			if !cfg.Encryption.Enabled() {
+				return errors.New("store encryption is not configured")
+			}
+
+			rotated, err := rotateEncryptionKey(cfg)
+			if err != nil {
+				logger.Error(err.Error())
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "re-encrypted %d resources\n", rotated)
+			return nil
+		},
+	}
+
+	setupErr = handleConfig(cmd, os.Args[1:], false)
+
+	return cmd
+}
+
+func rotateEncryptionKey(cfg backend.StoreConfig) (int, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store, closeStore, err := openStore(ctx, cfg)
+	if err != nil {
+		return 0, err
+	}
+	defer closeStore()
+	encrypted, ok := store.(*encryption.Store)
+	if !ok {
+		return 0, errors.New("store encryption is not configured")
+	}
+	return encryption.Rotate(ctx, encrypted)
+}
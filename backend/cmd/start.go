@@ -11,13 +11,20 @@ import (
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sensu/sensu-go/backend/apid/middlewares"
+	"github.com/sensu/sensu-go/backend/authorization/webhook"
+	"github.com/sensu/sensu-go/backend/kafka"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/remotewrite"
+	"github.com/sensu/sensu-go/backend/store/encryption"
 	"github.com/sensu/sensu-go/backend/store/postgres"
+	"github.com/sensu/sensu-go/backend/store/sqlite"
 
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/asset"
@@ -36,9 +43,12 @@ import (
 var DeprecateDashboardFlags = true
 
 var (
-	annotations               map[string]string
-	labels                    map[string]string
-	configFileDefaultLocation = filepath.Join(path.SystemConfigDir(), "backend.yml")
+	annotations                 map[string]string
+	apiNamespaceRateLimits      map[string]string
+	busOverflowPolicies         map[string]string
+	kubernetesSecretsNamespaces map[string]string
+	labels                      map[string]string
+	configFileDefaultLocation   = filepath.Join(path.SystemConfigDir(), "backend.yml")
 )
 
 const (
@@ -52,6 +62,11 @@ const (
 	flagAPIRequestLimit       = "api-request-limit"
 	flagAPIURL                = "api-url"
 	flagAPIWriteTimeout       = "api-write-timeout"
+	flagAPIRateLimit          = "api-rate-limit"
+	flagAPIBurstLimit         = "api-burst-limit"
+	flagAPINamespaceRateLimit = "api-namespace-rate-limit"
+	flagBusOverflowPolicy     = "bus-overflow-policy"
+	flagAPIReadOnly           = "api-read-only"
 	flagAssetsRateLimit       = "assets-rate-limit"
 	flagAssetsBurstLimit      = "assets-burst-limit"
 	flagDashboardHost         = "dashboard-host"
@@ -75,6 +90,22 @@ const (
 	flagPGDSN                = "pg-dsn"                  // postgresql connection string
 	flagEventCacheWriteLimit = "event-cache-write-limit" // maximum number of tps that event cache will write
 	flagDisableEventCache    = "disable-event-cache"     // don't cache events, always write through to postgresql
+	flagPGMaxConns           = "pg-max-conns"            // maximum number of connections of the pool
+	flagPGMinConns           = "pg-min-conns"            // number of connections kept open by the pool
+	flagPGMaxConnLifetime    = "pg-max-conn-lifetime"    // duration after which a connection is closed
+	flagPGMaxConnIdleTime    = "pg-max-conn-idle-time"   // duration after which an idle connection is closed
+	flagPGStatementTimeout   = "pg-statement-timeout"    // statement_timeout of the connections
+	flagPGReadDSN            = "pg-read-dsn"             // postgresql read replica connection string
+	flagPGMaxReplicaLag      = "pg-max-replica-lag"      // replication lag above which the reads go to the primary
+
+	// SQLite store
+	flagSQLitePath = "sqlite-path" // path to the sqlite database file
+
+	// Store encryption
+	flagStoreEncryptionKeyFile    = "store-encryption-key-file"    // file of the key encryption keys
+	flagStoreEncryptionKMSAddress = "store-encryption-kms-address" // address of the vault server
+	flagStoreEncryptionKMSKey     = "store-encryption-kms-key"     // name of the vault transit key
+	flagStoreEncryptionKMSToken   = "store-encryption-kms-token"   // vault token
 
 	// Metric logging flags
 	flagDisablePlatformMetrics         = "disable-platform-metrics"
@@ -93,6 +124,50 @@ const (
 	// flagEventLogParallelEncoders used to indicate parallel encoders should be used for event logging
 	flagEventLogParallelEncoders = "event-log-parallel-encoders"
 
+	// Tracing flags
+	flagTracingOTLPEndpoint = "tracing-otlp-endpoint"
+	flagTracingOTLPInsecure = "tracing-otlp-insecure"
+	flagTracingSampleRatio  = "tracing-sample-ratio"
+
+	// GraphQL flags
+	flagGraphQLPersistedQueries        = "graphql-persisted-queries"
+	flagGraphQLRequirePersistedQueries = "graphql-require-persisted-queries"
+
+	// Audit log flags
+	flagAuditLogFile    = "audit-log-file"
+	flagAuditWebhookURL = "audit-webhook-url"
+	flagAuditStore      = "audit-store"
+
+	// Authorization webhook flags
+	flagAuthorizationWebhookURL      = "authorization-webhook-url"
+	flagAuthorizationWebhookCacheTTL = "authorization-webhook-cache-ttl"
+	flagAuthorizationWebhookFailOpen = "authorization-webhook-fail-open"
+
+	// Multi-factor authentication flags
+	flagMFARequired = "mfa-required"
+
+	// Kubernetes secrets provider flags
+	flagKubernetesSecrets          = "kubernetes-secrets"
+	flagKubernetesSecretsWatch     = "kubernetes-secrets-watch"
+	flagKubernetesSecretsNamespace = "kubernetes-secrets-namespace"
+
+	// Kafka bridge flags
+	flagKafkaRESTProxyURL   = "kafka-rest-proxy-url"
+	flagKafkaEventTopic     = "kafka-event-topic"
+	flagKafkaKeepaliveTopic = "kafka-keepalive-topic"
+	flagKafkaSerialization  = "kafka-serialization"
+	flagKafkaAvroSchemaFile = "kafka-avro-schema-file"
+	flagKafkaAvroSchemaID   = "kafka-avro-schema-id"
+
+	// Prometheus remote-write flags
+	flagRemoteWriteURL               = "remote-write-url"
+	flagRemoteWriteRelabelConfigFile = "remote-write-relabel-config-file"
+	flagRemoteWriteBatchSize         = "remote-write-batch-size"
+	flagRemoteWriteFlushInterval     = "remote-write-flush-interval"
+
+	// Operator flags
+	flagOperatorTimeoutJitter = "operator-timeout-jitter"
+
 	// Default values
 
 	// Start command usage template
@@ -158,23 +233,30 @@ func StartCommand(initialize InitializeFunc) *cobra.Command {
 			logrus.SetLevel(level)
 
 			cfg := &backend.Config{
-				AgentHost:             viper.GetString(flagAgentHost),
-				AgentPort:             viper.GetInt(flagAgentPort),
-				AgentWriteTimeout:     viper.GetInt(backend.FlagAgentWriteTimeout),
-				APIListenAddress:      viper.GetString(flagAPIListenAddress),
-				APIRequestLimit:       viper.GetInt64(flagAPIRequestLimit),
-				APIURL:                viper.GetString(flagAPIURL),
-				APIWriteTimeout:       viper.GetDuration(flagAPIWriteTimeout),
-				AssetsRateLimit:       rate.Limit(viper.GetFloat64(flagAssetsRateLimit)),
-				AssetsBurstLimit:      viper.GetInt(flagAssetsBurstLimit),
-				DashboardHost:         viper.GetString(flagDashboardHost),
-				DashboardPort:         viper.GetInt(flagDashboardPort),
-				DashboardTLSCertFile:  viper.GetString(flagDashboardCertFile),
-				DashboardTLSKeyFile:   viper.GetString(flagDashboardKeyFile),
-				DashboardWriteTimeout: viper.GetDuration(flagDashboardWriteTimeout),
-				DeregistrationHandler: viper.GetString(flagDeregistrationHandler),
-				CacheDir:              viper.GetString(flagCacheDir),
-				Name:                  viper.GetString(flagName),
+				AgentHost:                   viper.GetString(flagAgentHost),
+				AgentPort:                   viper.GetInt(flagAgentPort),
+				AgentWriteTimeout:           viper.GetInt(backend.FlagAgentWriteTimeout),
+				AgentCompressionLevel:       viper.GetInt(backend.FlagAgentCompressionLevel),
+				AgentAssetProxy:             viper.GetBool(backend.FlagAgentAssetProxy),
+				AgentAssetProxyCacheMaxSize: viper.GetInt64(backend.FlagAgentAssetProxyCacheMaxSize),
+				AgentAssetProxyCacheMaxAge:  viper.GetDuration(backend.FlagAgentAssetProxyCacheMaxAge),
+				APIListenAddress:            viper.GetString(flagAPIListenAddress),
+				APIRequestLimit:             viper.GetInt64(flagAPIRequestLimit),
+				APIURL:                      viper.GetString(flagAPIURL),
+				APIWriteTimeout:             viper.GetDuration(flagAPIWriteTimeout),
+				APIRateLimit:                rate.Limit(viper.GetFloat64(flagAPIRateLimit)),
+				APIBurstLimit:               viper.GetInt(flagAPIBurstLimit),
+				APIReadOnly:                 viper.GetBool(flagAPIReadOnly),
+				AssetsRateLimit:             rate.Limit(viper.GetFloat64(flagAssetsRateLimit)),
+				AssetsBurstLimit:            viper.GetInt(flagAssetsBurstLimit),
+				DashboardHost:               viper.GetString(flagDashboardHost),
+				DashboardPort:               viper.GetInt(flagDashboardPort),
+				DashboardTLSCertFile:        viper.GetString(flagDashboardCertFile),
+				DashboardTLSKeyFile:         viper.GetString(flagDashboardKeyFile),
+				DashboardWriteTimeout:       viper.GetDuration(flagDashboardWriteTimeout),
+				DeregistrationHandler:       viper.GetString(flagDeregistrationHandler),
+				CacheDir:                    viper.GetString(flagCacheDir),
+				Name:                        viper.GetString(flagName),
 
 				Labels:                         viper.GetStringMapString(flagLabels),
 				Annotations:                    viper.GetStringMapString(flagAnnotations),
@@ -185,13 +267,50 @@ func StartCommand(initialize InitializeFunc) *cobra.Command {
 				EventLogBufferWait:             viper.GetDuration(flagEventLogBufferWait),
 				EventLogFile:                   viper.GetString(flagEventLogFile),
 				EventLogParallelEncoders:       viper.GetBool(flagEventLogParallelEncoders),
+				TracingOTLPEndpoint:            viper.GetString(flagTracingOTLPEndpoint),
+				TracingOTLPInsecure:            viper.GetBool(flagTracingOTLPInsecure),
+				TracingSampleRatio:             viper.GetFloat64(flagTracingSampleRatio),
+				GraphQLPersistedQueriesFile:    viper.GetString(flagGraphQLPersistedQueries),
+				GraphQLRequirePersistedQueries: viper.GetBool(flagGraphQLRequirePersistedQueries),
+				AuditLogFile:                   viper.GetString(flagAuditLogFile),
+				AuditWebhookURL:                viper.GetString(flagAuditWebhookURL),
+				AuditStore:                     viper.GetBool(flagAuditStore),
+				AuthorizationWebhookURL:        viper.GetString(flagAuthorizationWebhookURL),
+				AuthorizationWebhookCacheTTL:   viper.GetDuration(flagAuthorizationWebhookCacheTTL),
+				AuthorizationWebhookFailOpen:   viper.GetBool(flagAuthorizationWebhookFailOpen),
+				MFARequired:                    viper.GetBool(flagMFARequired),
+				KubernetesSecrets:              viper.GetBool(flagKubernetesSecrets),
+				KubernetesSecretsWatch:         viper.GetBool(flagKubernetesSecretsWatch),
+				KubernetesSecretsNamespaces:    kubernetesSecretsNamespaces,
+				KafkaRESTProxyURL:              viper.GetString(flagKafkaRESTProxyURL),
+				KafkaEventTopic:                viper.GetString(flagKafkaEventTopic),
+				KafkaKeepaliveTopic:            viper.GetString(flagKafkaKeepaliveTopic),
+				KafkaSerialization:             viper.GetString(flagKafkaSerialization),
+				KafkaAvroSchemaFile:            viper.GetString(flagKafkaAvroSchemaFile),
+				KafkaAvroSchemaID:              viper.GetInt(flagKafkaAvroSchemaID),
+				RemoteWriteURL:                 viper.GetString(flagRemoteWriteURL),
+				RemoteWriteRelabelConfigFile:   viper.GetString(flagRemoteWriteRelabelConfigFile),
+				RemoteWriteBatchSize:           viper.GetInt(flagRemoteWriteBatchSize),
+				RemoteWriteFlushInterval:       viper.GetDuration(flagRemoteWriteFlushInterval),
+				OperatorTimeoutJitter:          viper.GetFloat64(flagOperatorTimeoutJitter),
 
 				Store: backend.StoreConfig{
 					PostgresStore: postgres.Config{
 						DSN:               viper.GetString(flagPGDSN),
 						MaxTPS:            viper.GetInt(flagEventCacheWriteLimit),
 						DisableEventCache: viper.GetBool(flagDisableEventCache),
+						MaxConns:          viper.GetInt32(flagPGMaxConns),
+						MinConns:          viper.GetInt32(flagPGMinConns),
+						MaxConnLifetime:   viper.GetDuration(flagPGMaxConnLifetime),
+						MaxConnIdleTime:   viper.GetDuration(flagPGMaxConnIdleTime),
+						StatementTimeout:  viper.GetDuration(flagPGStatementTimeout),
+						ReadDSN:           viper.GetString(flagPGReadDSN),
+						MaxReplicaLag:     viper.GetDuration(flagPGMaxReplicaLag),
+					},
+					SQLiteStore: sqlite.Config{
+						Path: viper.GetString(flagSQLitePath),
 					},
+					Encryption: storeEncryptionConfig(),
 				},
 			}
 
@@ -206,6 +325,16 @@ func StartCommand(initialize InitializeFunc) *cobra.Command {
 				cfg.Annotations = annotations
 			}
 
+			cfg.APINamespaceRateLimits, err = parseNamespaceRateLimits(apiNamespaceRateLimits)
+			if err != nil {
+				return err
+			}
+
+			cfg.BusOverflowPolicies, err = parseBusOverflowPolicies(busOverflowPolicies)
+			if err != nil {
+				return err
+			}
+
 			// Sensu APIs TLS config
 			certFile := viper.GetString(flagCertFile)
 			keyFile := viper.GetString(flagKeyFile)
@@ -234,17 +363,29 @@ func StartCommand(initialize InitializeFunc) *cobra.Command {
 				)
 			}
 
-			var pgDB *pgxpool.Pool
-
 			ctx, cancel := context.WithCancel(context.Background())
 
-			pgDB, err = newPostgresPool(ctx, cfg.Store.PostgresStore.DSN)
-			if err != nil {
-				return err
+			// A standalone backend stores everything in sqlite
+			var db postgres.DBI
+			if !cfg.Store.Standalone() {
+				pgDB, err := newPostgresPool(ctx, cfg.Store.PostgresStore)
+				if err != nil {
+					return err
+				}
+				defer pgDB.Close()
+				db = pgDB
+
+				if cfg.Store.PostgresStore.ReadDSN != "" {
+					replicaDB, err := newPostgresReplicaPool(ctx, cfg.Store.PostgresStore)
+					if err != nil {
+						return err
+					}
+					defer replicaDB.Close()
+					db = postgres.NewReplicaDB(ctx, pgDB, replicaDB, cfg.Store.PostgresStore.MaxReplicaLag)
+				}
 			}
-			defer pgDB.Close()
 
-			sensuBackend, err := initialize(ctx, pgDB, cfg)
+			sensuBackend, err := initialize(ctx, db, cfg)
 			if err != nil {
 				return err
 			}
@@ -273,8 +414,26 @@ func StartCommand(initialize InitializeFunc) *cobra.Command {
 	return cmd
 }
 
-func newPostgresPool(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
-	pgxConfig, err := pgxpool.ParseConfig(dsn)
+// storeEncryptionConfig returns the configuration of the encryption of the
+// store.
+func storeEncryptionConfig() encryption.Config {
+	return encryption.Config{
+		KeyFile:    viper.GetString(flagStoreEncryptionKeyFile),
+		KMSAddress: viper.GetString(flagStoreEncryptionKMSAddress),
+		KMSKey:     viper.GetString(flagStoreEncryptionKMSKey),
+		KMSToken:   viper.GetString(flagStoreEncryptionKMSToken),
+	}
+}
+
+func newPostgresPool(ctx context.Context, config postgres.Config) (*pgxpool.Pool, error) {
+	if config.MaxConns < 0 || config.MinConns < 0 || config.MaxConnLifetime < 0 || config.MaxConnIdleTime < 0 || config.StatementTimeout < 0 {
+		return nil, fmt.Errorf("the --%s, --%s, --%s, --%s and --%s values cannot be negative",
+			flagPGMaxConns, flagPGMinConns, flagPGMaxConnLifetime, flagPGMaxConnIdleTime, flagPGStatementTimeout)
+	}
+	if config.MaxConns > 0 && config.MinConns > config.MaxConns {
+		return nil, fmt.Errorf("--%s cannot be greater than --%s", flagPGMinConns, flagPGMaxConns)
+	}
+	pgxConfig, err := config.PoolConfig()
 	if err != nil {
 		return nil, err
 	}
@@ -287,6 +446,51 @@ func newPostgresPool(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
 	return db, nil
 }
 
+// newPostgresReplicaPool returns the pool of the postgresql read replica. The
+// replica is not migrated, it only serves reads.
+func newPostgresReplicaPool(ctx context.Context, config postgres.Config) (*pgxpool.Pool, error) {
+	if config.MaxReplicaLag < 0 {
+		return nil, fmt.Errorf("--%s cannot be negative", flagPGMaxReplicaLag)
+	}
+	pgxConfig, err := config.ReadPoolConfig()
+	if err != nil {
+		return nil, err
+	}
+	return pgxpool.NewWithConfig(ctx, pgxConfig)
+}
+
+// parseNamespaceRateLimits parses the API rate limits of the namespaces, given
+// as a map of namespaces to their number of requests per second.
+func parseNamespaceRateLimits(limits map[string]string) (map[string]rate.Limit, error) {
+	if len(limits) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]rate.Limit, len(limits))
+	for namespace, limit := range limits {
+		value, err := strconv.ParseFloat(limit, 64)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid --%s value for namespace %q: %q", flagAPINamespaceRateLimit, namespace, limit)
+		}
+		result[namespace] = rate.Limit(value)
+	}
+	return result, nil
+}
+
+func parseBusOverflowPolicies(policies map[string]string) (map[string]messaging.OverflowPolicy, error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]messaging.OverflowPolicy, len(policies))
+	for consumer, policy := range policies {
+		value, err := messaging.ParseOverflowPolicy(policy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s value for consumer %q: %s", flagBusOverflowPolicy, consumer, err)
+		}
+		result[consumer] = value
+	}
+	return result, nil
+}
+
 func handleConfig(cmd *cobra.Command, arguments []string, server bool) error {
 	configFlags := flagSet(server)
 	_ = configFlags.Parse(arguments)
@@ -322,6 +526,11 @@ func handleConfig(cmd *cobra.Command, arguments []string, server bool) error {
 		viper.SetDefault(flagAPIRequestLimit, middlewares.MaxBytesLimit)
 		viper.SetDefault(flagAPIURL, "http://localhost:8080")
 		viper.SetDefault(flagAPIWriteTimeout, "15s")
+		viper.SetDefault(flagAPIRateLimit, 0)
+		viper.SetDefault(flagAPIBurstLimit, 100)
+		viper.SetDefault(flagAPIReadOnly, false)
+		viper.SetDefault(flagGraphQLPersistedQueries, "")
+		viper.SetDefault(flagGraphQLRequirePersistedQueries, false)
 		viper.SetDefault(flagAssetsRateLimit, asset.DefaultAssetsRateLimit)
 		viper.SetDefault(flagAssetsBurstLimit, asset.DefaultAssetsBurstLimit)
 		viper.SetDefault(flagDashboardHost, "[::]")
@@ -341,7 +550,13 @@ func handleConfig(cmd *cobra.Command, arguments []string, server bool) error {
 		viper.SetDefault(backend.FlagKeepalivedBufferSize, 1000)
 		viper.SetDefault(backend.FlagPipelinedWorkers, 100)
 		viper.SetDefault(backend.FlagPipelinedBufferSize, 1000)
+		viper.SetDefault(backend.FlagPipelinedDurable, false)
+		viper.SetDefault(backend.FlagPipelinedHandlerResults, false)
 		viper.SetDefault(backend.FlagAgentWriteTimeout, 15)
+		viper.SetDefault(backend.FlagAgentCompressionLevel, 0)
+		viper.SetDefault(backend.FlagAgentAssetProxy, false)
+		viper.SetDefault(backend.FlagAgentAssetProxyCacheMaxSize, 0)
+		viper.SetDefault(backend.FlagAgentAssetProxyCacheMaxAge, 0)
 		viper.SetDefault(flagDisablePlatformMetrics, defaultDisablePlatformMetrics)
 		viper.SetDefault(flagPlatformMetricsLoggingInterval, defaultPlatformMetricsLoggingInterval)
 		viper.SetDefault(flagPlatformMetricsLogFile, defaultPlatformMetricsLogFile)
@@ -351,6 +566,40 @@ func handleConfig(cmd *cobra.Command, arguments []string, server bool) error {
 		viper.SetDefault(flagEventLogParallelEncoders, false)
 		viper.SetDefault(flagEventCacheWriteLimit, 1000)
 		viper.SetDefault(flagDisableEventCache, false)
+		viper.SetDefault(flagPGMaxConns, 0)
+		viper.SetDefault(flagPGMinConns, 0)
+		viper.SetDefault(flagPGMaxConnLifetime, 0)
+		viper.SetDefault(flagPGMaxConnIdleTime, 0)
+		viper.SetDefault(flagPGStatementTimeout, 0)
+		viper.SetDefault(flagPGReadDSN, "")
+		viper.SetDefault(flagPGMaxReplicaLag, postgres.DefaultMaxReplicaLag)
+		viper.SetDefault(flagStoreEncryptionKeyFile, "")
+		viper.SetDefault(flagStoreEncryptionKMSAddress, "")
+		viper.SetDefault(flagStoreEncryptionKMSKey, "")
+		viper.SetDefault(flagStoreEncryptionKMSToken, "")
+		viper.SetDefault(flagTracingOTLPEndpoint, "")
+		viper.SetDefault(flagTracingOTLPInsecure, false)
+		viper.SetDefault(flagTracingSampleRatio, 1.0)
+		viper.SetDefault(flagAuditLogFile, "")
+		viper.SetDefault(flagAuditWebhookURL, "")
+		viper.SetDefault(flagAuditStore, false)
+		viper.SetDefault(flagAuthorizationWebhookURL, "")
+		viper.SetDefault(flagAuthorizationWebhookCacheTTL, webhook.DefaultCacheTTL)
+		viper.SetDefault(flagAuthorizationWebhookFailOpen, false)
+		viper.SetDefault(flagMFARequired, false)
+		viper.SetDefault(flagKubernetesSecrets, false)
+		viper.SetDefault(flagKubernetesSecretsWatch, false)
+		viper.SetDefault(flagKafkaRESTProxyURL, "")
+		viper.SetDefault(flagKafkaEventTopic, "")
+		viper.SetDefault(flagKafkaKeepaliveTopic, "")
+		viper.SetDefault(flagKafkaSerialization, kafka.SerializationJSON)
+		viper.SetDefault(flagKafkaAvroSchemaFile, "")
+		viper.SetDefault(flagKafkaAvroSchemaID, 0)
+		viper.SetDefault(flagRemoteWriteURL, "")
+		viper.SetDefault(flagRemoteWriteRelabelConfigFile, "")
+		viper.SetDefault(flagRemoteWriteBatchSize, remotewrite.DefaultBatchSize)
+		viper.SetDefault(flagRemoteWriteFlushInterval, remotewrite.DefaultFlushInterval)
+		viper.SetDefault(flagOperatorTimeoutJitter, 0.0)
 
 		backendName, err := os.Hostname()
 		if err != nil {
@@ -408,12 +657,48 @@ func flagSet(server bool) *pflag.FlagSet {
 	flagSet.String(flagPGDSN, viper.GetString(flagPGDSN), "postgresql store DSN")
 	_ = flagSet.SetAnnotation(flagPGDSN, "categories", []string{"store"})
 
+	flagSet.String(flagSQLitePath, viper.GetString(flagSQLitePath), "path to a sqlite database used to store resources instead of postgresql (postgresql is still used for cluster coordination when --pg-dsn is set, otherwise the backend is standalone)")
+	_ = flagSet.SetAnnotation(flagSQLitePath, "categories", []string{"store"})
+
 	flagSet.Int(flagEventCacheWriteLimit, viper.GetInt(flagEventCacheWriteLimit), "events per second to flush from the event cache to postgresql")
 	_ = flagSet.SetAnnotation(flagEventCacheWriteLimit, "categories", []string{"store"})
 
 	flagSet.Bool(flagDisableEventCache, viper.GetBool(flagDisableEventCache), "disable caching events, write events directly to postgresql")
 	_ = flagSet.SetAnnotation(flagDisableEventCache, "categories", []string{"store"})
 
+	flagSet.Int32(flagPGMaxConns, viper.GetInt32(flagPGMaxConns), "maximum number of connections to postgresql (0 uses the pgx default)")
+	_ = flagSet.SetAnnotation(flagPGMaxConns, "categories", []string{"store"})
+
+	flagSet.Int32(flagPGMinConns, viper.GetInt32(flagPGMinConns), "number of connections to postgresql kept open")
+	_ = flagSet.SetAnnotation(flagPGMinConns, "categories", []string{"store"})
+
+	flagSet.Duration(flagPGMaxConnLifetime, viper.GetDuration(flagPGMaxConnLifetime), "duration after which a connection to postgresql is closed (0 uses the pgx default)")
+	_ = flagSet.SetAnnotation(flagPGMaxConnLifetime, "categories", []string{"store"})
+
+	flagSet.Duration(flagPGMaxConnIdleTime, viper.GetDuration(flagPGMaxConnIdleTime), "duration after which an idle connection to postgresql is closed (0 uses the pgx default)")
+	_ = flagSet.SetAnnotation(flagPGMaxConnIdleTime, "categories", []string{"store"})
+
+	flagSet.Duration(flagPGStatementTimeout, viper.GetDuration(flagPGStatementTimeout), "duration after which postgresql aborts a query, including the migrations (0 disables the timeout)")
+	_ = flagSet.SetAnnotation(flagPGStatementTimeout, "categories", []string{"store"})
+
+	flagSet.String(flagPGReadDSN, viper.GetString(flagPGReadDSN), "postgresql read replica DSN, serving the reads of the API and dashboard")
+	_ = flagSet.SetAnnotation(flagPGReadDSN, "categories", []string{"store"})
+
+	flagSet.Duration(flagPGMaxReplicaLag, viper.GetDuration(flagPGMaxReplicaLag), "replication lag of the read replica above which the reads are served by the primary")
+	_ = flagSet.SetAnnotation(flagPGMaxReplicaLag, "categories", []string{"store"})
+
+	flagSet.String(flagStoreEncryptionKeyFile, viper.GetString(flagStoreEncryptionKeyFile), "file of the keys encrypting the sensitive fields of the resources, one id:base64-key per line, the first one encrypting")
+	_ = flagSet.SetAnnotation(flagStoreEncryptionKeyFile, "categories", []string{"store"})
+
+	flagSet.String(flagStoreEncryptionKMSAddress, viper.GetString(flagStoreEncryptionKMSAddress), "address of the vault server whose transit secrets engine encrypts the sensitive fields of the resources")
+	_ = flagSet.SetAnnotation(flagStoreEncryptionKMSAddress, "categories", []string{"store"})
+
+	flagSet.String(flagStoreEncryptionKMSKey, viper.GetString(flagStoreEncryptionKMSKey), "name of the vault transit key")
+	_ = flagSet.SetAnnotation(flagStoreEncryptionKMSKey, "categories", []string{"store"})
+
+	flagSet.String(flagStoreEncryptionKMSToken, viper.GetString(flagStoreEncryptionKMSToken), "vault token used with the transit secrets engine")
+	_ = flagSet.SetAnnotation(flagStoreEncryptionKMSToken, "categories", []string{"store"})
+
 	if server {
 		// Main Flags
 		flagSet.String(flagName, viper.GetString(flagName), "backend name")
@@ -423,6 +708,12 @@ func flagSet(server bool) *pflag.FlagSet {
 		flagSet.Int64(flagAPIRequestLimit, viper.GetInt64(flagAPIRequestLimit), "maximum API request body size, in bytes")
 		flagSet.String(flagAPIURL, viper.GetString(flagAPIURL), "url of the api to connect to")
 		flagSet.Duration(flagAPIWriteTimeout, viper.GetDuration(flagAPIWriteTimeout), "maximum duration before timing out writes of responses")
+		flagSet.Float64(flagAPIRateLimit, viper.GetFloat64(flagAPIRateLimit), "maximum number of API requests per second for each user or API key (0 disables the limit)")
+		flagSet.Int(flagAPIBurstLimit, viper.GetInt(flagAPIBurstLimit), "API requests burst limit for each user or API key")
+		flagSet.StringToStringVar(&apiNamespaceRateLimits, flagAPINamespaceRateLimit, nil, "maximum number of API requests per second for each user or API key in the given namespaces, overriding the global limit (e.g. default=10)")
+		flagSet.Bool(flagAPIReadOnly, viper.GetBool(flagAPIReadOnly), "start the API in read-only maintenance mode, rejecting the requests that modify resources until disabled at /api/core/v2/maintenance")
+		flagSet.String(flagGraphQLPersistedQueries, viper.GetString(flagGraphQLPersistedQueries), "path to a JSON object of persisted GraphQL queries, keyed by their SHA-256 hash")
+		flagSet.Bool(flagGraphQLRequirePersistedQueries, viper.GetBool(flagGraphQLRequirePersistedQueries), "reject the GraphQL queries that are not in the persisted queries file")
 		flagSet.Float64(flagAssetsRateLimit, viper.GetFloat64(flagAssetsRateLimit), "maximum number of assets fetched per second")
 		flagSet.Int(flagAssetsBurstLimit, viper.GetInt(flagAssetsBurstLimit), "asset fetch burst limit")
 		flagSet.String(flagDashboardHost, viper.GetString(flagDashboardHost), "dashboard listener host")
@@ -444,7 +735,14 @@ func flagSet(server bool) *pflag.FlagSet {
 		flagSet.Int(backend.FlagKeepalivedBufferSize, viper.GetInt(backend.FlagKeepalivedBufferSize), "number of incoming keepalives that can be buffered")
 		flagSet.Int(backend.FlagPipelinedWorkers, viper.GetInt(backend.FlagPipelinedWorkers), "number of workers spawned for handling events through the event pipeline")
 		flagSet.Int(backend.FlagPipelinedBufferSize, viper.GetInt(backend.FlagPipelinedBufferSize), "number of events to handle that can be buffered")
+		flagSet.Bool(backend.FlagPipelinedDurable, viper.GetBool(backend.FlagPipelinedDurable), "journal the events to handle in the cache directory, so that the events not handled yet are redelivered when the backend restarts")
+		flagSet.Bool(backend.FlagPipelinedHandlerResults, viper.GetBool(backend.FlagPipelinedHandlerResults), "record the results of the last handler executions of the events of the checks, exposed by the handler-results API")
+		flagSet.StringToStringVar(&busOverflowPolicies, flagBusOverflowPolicy, nil, "policy applied by the message bus when a consumer falls behind, for each consumer (block, drop-oldest or drop-newest, e.g. pipelined=drop-oldest)")
 		flagSet.Int(backend.FlagAgentWriteTimeout, viper.GetInt(backend.FlagAgentWriteTimeout), "timeout in seconds for agent writes")
+		flagSet.Int(backend.FlagAgentCompressionLevel, viper.GetInt(backend.FlagAgentCompressionLevel), "level of the compression of the agent connections that enable it, between 1 and 9 (0 disables the compression)")
+		flagSet.Bool(backend.FlagAgentAssetProxy, viper.GetBool(backend.FlagAgentAssetProxy), "serve the asset builds to the agents on the agent port, from a cache in the cache directory")
+		flagSet.Int64(backend.FlagAgentAssetProxyCacheMaxSize, viper.GetInt64(backend.FlagAgentAssetProxyCacheMaxSize), "maximum size in bytes of the archives in the cache of the asset proxy, above which the least recently served archives are evicted (0 disables the limit)")
+		flagSet.Duration(backend.FlagAgentAssetProxyCacheMaxAge, viper.GetDuration(backend.FlagAgentAssetProxyCacheMaxAge), "maximum duration an archive stays in the cache of the asset proxy without being served (0 disables the limit)")
 		flagSet.String(backend.FlagJWTPrivateKeyFile, viper.GetString(backend.FlagJWTPrivateKeyFile), "path to the PEM-encoded private key to use to sign JWTs")
 		flagSet.String(backend.FlagJWTPublicKeyFile, viper.GetString(backend.FlagJWTPublicKeyFile), "path to the PEM-encoded public key to use to verify JWT signatures")
 		flagSet.StringToStringVar(&labels, flagLabels, nil, "entity labels map")
@@ -452,6 +750,30 @@ func flagSet(server bool) *pflag.FlagSet {
 		flagSet.Bool(flagDisablePlatformMetrics, viper.GetBool(flagDisablePlatformMetrics), "disable platform metrics logging")
 		flagSet.Duration(flagPlatformMetricsLoggingInterval, viper.GetDuration(flagPlatformMetricsLoggingInterval), "platform metrics logging interval")
 		flagSet.String(flagPlatformMetricsLogFile, viper.GetString(flagPlatformMetricsLogFile), "platform metrics log file path")
+		flagSet.String(flagTracingOTLPEndpoint, viper.GetString(flagTracingOTLPEndpoint), "host:port of the OTLP/HTTP collector that pipeline traces are exported to (tracing is disabled if empty)")
+		flagSet.Bool(flagTracingOTLPInsecure, viper.GetBool(flagTracingOTLPInsecure), "disable TLS for the connection to the OTLP collector")
+		flagSet.Float64(flagTracingSampleRatio, viper.GetFloat64(flagTracingSampleRatio), "ratio of the events traced, between 0 and 1")
+		flagSet.String(flagAuditLogFile, viper.GetString(flagAuditLogFile), "path to the file that the API requests selected by the audit policies are logged to")
+		flagSet.String(flagAuditWebhookURL, viper.GetString(flagAuditWebhookURL), "URL that the API requests selected by the audit policies are posted to")
+		flagSet.Bool(flagAuditStore, viper.GetBool(flagAuditStore), "record the API requests selected by the audit policies in the store")
+		flagSet.String(flagAuthorizationWebhookURL, viper.GetString(flagAuthorizationWebhookURL), "HTTPS URL of the webhook allowing or denying the API requests, which are authorized with RBAC when the webhook has no opinion")
+		flagSet.Duration(flagAuthorizationWebhookCacheTTL, viper.GetDuration(flagAuthorizationWebhookCacheTTL), "duration the decisions of the authorization webhook are cached for")
+		flagSet.Bool(flagAuthorizationWebhookFailOpen, viper.GetBool(flagAuthorizationWebhookFailOpen), "authorize the API requests with RBAC while the authorization webhook is unavailable, instead of rejecting them")
+		flagSet.Bool(flagMFARequired, viper.GetBool(flagMFARequired), "require the local users to enroll in the multi-factor authentication before they can log in")
+		flagSet.Bool(flagKubernetesSecrets, viper.GetBool(flagKubernetesSecrets), "read the secrets of the checks and handlers, addressed as name/key, from the Kubernetes Secrets of the cluster the backend runs in")
+		flagSet.StringToStringVar(&kubernetesSecretsNamespaces, flagKubernetesSecretsNamespace, nil, "kubernetes namespace that the secrets of each sensu namespace are read from, the other sensu namespaces can't read kubernetes secrets (e.g. default=sensu)")
+		flagSet.Bool(flagKubernetesSecretsWatch, viper.GetBool(flagKubernetesSecretsWatch), "cache the Kubernetes Secrets, watching them for changes")
+		flagSet.String(flagKafkaRESTProxyURL, viper.GetString(flagKafkaRESTProxyURL), "URL of the Kafka REST proxy that events are produced to (the Kafka bridge is disabled if empty)")
+		flagSet.String(flagKafkaEventTopic, viper.GetString(flagKafkaEventTopic), "Kafka topic that the events processed by eventd are produced to")
+		flagSet.String(flagKafkaKeepaliveTopic, viper.GetString(flagKafkaKeepaliveTopic), "Kafka topic that the keepalive events are produced to")
+		flagSet.String(flagKafkaSerialization, viper.GetString(flagKafkaSerialization), "serialization of the records produced to Kafka (json, protobuf or avro)")
+		flagSet.String(flagKafkaAvroSchemaFile, viper.GetString(flagKafkaAvroSchemaFile), "path to the Avro schema of the events, registered in the schema registry by the Kafka REST proxy")
+		flagSet.Int(flagKafkaAvroSchemaID, viper.GetInt(flagKafkaAvroSchemaID), "ID of the Avro schema of the events in the schema registry")
+		flagSet.String(flagRemoteWriteURL, viper.GetString(flagRemoteWriteURL), "URL of the Prometheus remote-write endpoint that the metric points of the events are written to, with the basic authentication credentials if any (disabled if empty)")
+		flagSet.String(flagRemoteWriteRelabelConfigFile, viper.GetString(flagRemoteWriteRelabelConfigFile), "path to the YAML list of relabeling rules applied to the series before they are written, like the write_relabel_configs of Prometheus")
+		flagSet.Int(flagRemoteWriteBatchSize, viper.GetInt(flagRemoteWriteBatchSize), "maximum number of samples written to the remote-write endpoint in a single request")
+		flagSet.Duration(flagRemoteWriteFlushInterval, viper.GetDuration(flagRemoteWriteFlushInterval), "interval at which the samples are written to the remote-write endpoint")
+		flagSet.Float64(flagOperatorTimeoutJitter, viper.GetFloat64(flagOperatorTimeoutJitter), "maximum jitter added to the keepalive and check TTL timeouts, as a fraction of the timeouts (e.g. 0.1 extends a 60s timeout by up to 6s)")
 
 		_ = flagSet.String(flagEventLogFile, "", "path to the event log file")
 		_ = flagSet.Bool(flagEventLogParallelEncoders, false, "use parallel JSON encoding for the event log")
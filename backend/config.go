@@ -5,7 +5,10 @@ import (
 
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/backend/licensing"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/store/encryption"
 	"github.com/sensu/sensu-go/backend/store/postgres"
+	"github.com/sensu/sensu-go/backend/store/sqlite"
 	"golang.org/x/time/rate"
 )
 
@@ -22,11 +25,33 @@ const (
 	FlagPipelinedWorkers = "pipelined-workers"
 	// FlagPipelinedBufferSize defines the buffer size for pipelined
 	FlagPipelinedBufferSize = "pipelined-buffer-size"
+	// FlagPipelinedDurable enables the write-ahead log of the events received
+	// by pipelined, in the cache directory
+	FlagPipelinedDurable = "pipelined-durable"
+	// FlagPipelinedHandlerResults enables the recording of the results of the
+	// handler executions, exposed by the handler-results API
+	FlagPipelinedHandlerResults = "pipelined-handler-results"
 
 	// FlagAgentWriteTimeout specifies the time in seconds to wait before
 	// giving up on a write to an agent and disposing of the connection.
 	FlagAgentWriteTimeout = "agent-write-timeout"
 
+	// FlagAgentCompressionLevel specifies the level of the permessage-deflate
+	// compression of the messages exchanged with the agents that enable it.
+	FlagAgentCompressionLevel = "agent-compression-level"
+
+	// FlagAgentAssetProxy enables the asset proxy serving the asset builds to
+	// the agents, from a cache in the cache directory.
+	FlagAgentAssetProxy = "agent-asset-proxy"
+
+	// FlagAgentAssetProxyCacheMaxSize specifies the maximum size, in bytes, of
+	// the archives in the cache of the asset proxy.
+	FlagAgentAssetProxyCacheMaxSize = "agent-asset-proxy-cache-max-size"
+
+	// FlagAgentAssetProxyCacheMaxAge specifies the maximum duration an archive
+	// stays in the cache of the asset proxy without being served.
+	FlagAgentAssetProxyCacheMaxAge = "agent-asset-proxy-cache-max-age"
+
 	// FlagJWTPrivateKeyFile defines the path to the private key file for JWT
 	// signatures
 	FlagJWTPrivateKeyFile = "jwt-private-key-file"
@@ -38,6 +63,21 @@ const (
 type StoreConfig struct {
 	// PostgresStore contains postgres configuration store details.
 	PostgresStore postgres.Config
+
+	// SQLiteStore contains sqlite store details. When a path is configured,
+	// resources are stored in sqlite instead of postgres.
+	SQLiteStore sqlite.Config
+
+	// Encryption configures the encryption of the sensitive fields of the
+	// resources in the store.
+	Encryption encryption.Config
+}
+
+// Standalone returns whether the backend runs without postgres, storing its
+// resources and the state of its operators in sqlite. A standalone backend
+// cannot be part of a cluster.
+func (c StoreConfig) Standalone() bool {
+	return c.SQLiteStore.Path != "" && c.PostgresStore.DSN == ""
 }
 
 // Config specifies a Backend configuration.
@@ -53,12 +93,51 @@ type Config struct {
 	AgentTLSOptions   *corev2.TLSOptions
 	AgentWriteTimeout int
 
+	// AgentCompressionLevel is the level of the permessage-deflate compression
+	// of the messages exchanged with the agents, between 0 (disabled) and 9.
+	AgentCompressionLevel int
+
+	// AgentAssetProxy enables the asset proxy serving the asset builds to the
+	// agents on the agent port, from a cache in the cache directory.
+	AgentAssetProxy bool
+
+	// AgentAssetProxyCacheMaxSize is the maximum size, in bytes, of the
+	// archives in the cache of the asset proxy. 0 disables the limit.
+	AgentAssetProxyCacheMaxSize int64
+
+	// AgentAssetProxyCacheMaxAge is the maximum duration an archive stays in
+	// the cache of the asset proxy without being served. 0 disables the limit.
+	AgentAssetProxyCacheMaxAge time.Duration
+
 	// Apid Configuration
 	APIListenAddress string
 	APIRequestLimit  int64
 	APIURL           string
 	APIWriteTimeout  time.Duration
 
+	// APIRateLimit is the maximum number of API requests per second allowed for
+	// each user or API key. The requests are not limited when 0.
+	APIRateLimit rate.Limit
+
+	// APIBurstLimit is the maximum amount of burst allowed in a rate interval.
+	APIBurstLimit int
+
+	// APINamespaceRateLimits overrides APIRateLimit for the requests made to
+	// the given namespaces.
+	APINamespaceRateLimits map[string]rate.Limit
+
+	// APIReadOnly starts the API in read-only maintenance mode, rejecting the
+	// requests with mutating verbs until the mode is disabled.
+	APIReadOnly bool
+
+	// GraphQLPersistedQueriesFile is the path to a JSON object of the GraphQL
+	// queries that are persisted at startup, keyed by their SHA-256 hash.
+	GraphQLPersistedQueriesFile string
+
+	// GraphQLRequirePersistedQueries rejects the GraphQL queries that are not
+	// in GraphQLPersistedQueriesFile.
+	GraphQLRequirePersistedQueries bool
+
 	// AssetsRateLimit is the maximum number of assets per second that will be fetched.
 	AssetsRateLimit rate.Limit
 
@@ -75,6 +154,14 @@ type Config struct {
 	// Pipelined Configuration
 	DeregistrationHandler string
 
+	// BusOverflowPolicies are the policies applied by the message bus when
+	// its consumers fall behind, by consumer name.
+	BusOverflowPolicies map[string]messaging.OverflowPolicy
+
+	// OperatorTimeoutJitter is the maximum jitter added to the keepalive and
+	// check TTL timeouts, as a fraction of the timeouts.
+	OperatorTimeoutJitter float64
+
 	// Labels are key-value pairs that users can provide to backend entities
 	Labels map[string]string
 
@@ -96,5 +183,64 @@ type Config struct {
 	EventLogFile             string
 	EventLogParallelEncoders bool
 
+	// Tracing Configuration
+	TracingOTLPEndpoint string
+	TracingOTLPInsecure bool
+	TracingSampleRatio  float64
+
+	// Audit log configuration
+	AuditLogFile    string
+	AuditWebhookURL string
+	AuditStore      bool
+
+	// AuthorizationWebhookURL is the HTTPS URL of the webhook deciding whether
+	// the API requests are allowed or denied. The requests the webhook has no
+	// opinion on are authorized with RBAC.
+	AuthorizationWebhookURL string
+
+	// AuthorizationWebhookCacheTTL is how long the decisions of the
+	// authorization webhook are cached.
+	AuthorizationWebhookCacheTTL time.Duration
+
+	// AuthorizationWebhookFailOpen authorizes the API requests with RBAC
+	// while the authorization webhook is unavailable, instead of rejecting
+	// them.
+	AuthorizationWebhookFailOpen bool
+
+	// MFARequired requires the users of the basic authentication provider to
+	// enroll in the multi-factor authentication before they can log in.
+	MFARequired bool
+
+	// KubernetesSecrets enables the secrets provider reading the secrets of
+	// the checks and handlers from the Kubernetes Secrets of the cluster the
+	// backend is deployed in.
+	KubernetesSecrets bool
+
+	// KubernetesSecretsWatch caches the Kubernetes Secrets, invalidating them
+	// as they change.
+	KubernetesSecretsWatch bool
+
+	// KubernetesSecretsNamespaces maps the Sensu namespaces to the Kubernetes
+	// namespace their secrets are read from. The other Sensu namespaces can't
+	// read Kubernetes Secrets.
+	KubernetesSecretsNamespaces map[string]string
+
+	// Kafka bridge configuration. The events are produced to Kafka through
+	// the REST proxy at KafkaRESTProxyURL, if set.
+	KafkaRESTProxyURL   string
+	KafkaEventTopic     string
+	KafkaKeepaliveTopic string
+	KafkaSerialization  string
+	KafkaAvroSchemaFile string
+	KafkaAvroSchemaID   int
+
+	// Prometheus remote-write configuration. The metric points of the events
+	// are written to the endpoint at RemoteWriteURL, if set, relabeled with
+	// the rules of RemoteWriteRelabelConfigFile.
+	RemoteWriteURL               string
+	RemoteWriteRelabelConfigFile string
+	RemoteWriteBatchSize         int
+	RemoteWriteFlushInterval     time.Duration
+
 	Store StoreConfig
 }
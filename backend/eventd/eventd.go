@@ -15,7 +15,9 @@ import (
 	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/backend/tracing"
 	metricspkg "github.com/sensu/sensu-go/metrics"
+	"github.com/sensu/sensu-go/thresholds"
 	utillogging "github.com/sensu/sensu-go/util/logging"
 )
 
@@ -386,7 +388,9 @@ func (e *Eventd) startHandlers() {
 	}
 }
 
-func (e *Eventd) publishEventWithDuration(event *corev2.Event) (fErr error) {
+// publishEventWithDuration publishes the event for pipelined, along with its
+// previous state if there is one.
+func (e *Eventd) publishEventWithDuration(event, prevEvent *corev2.Event) (fErr error) {
 	begin := time.Now()
 	defer func() {
 		duration := time.Since(begin)
@@ -403,6 +407,9 @@ func (e *Eventd) publishEventWithDuration(event *corev2.Event) (fErr error) {
 			Observe(float64(duration) / float64(time.Millisecond))
 	}()
 
+	if prevEvent != nil {
+		return e.bus.Publish(messaging.TopicEvent, &messaging.EventWithPrevious{Event: event, Previous: prevEvent})
+	}
 	return e.bus.Publish(messaging.TopicEvent, event)
 }
 
@@ -468,15 +475,28 @@ func (e *Eventd) handleMessage(msg interface{}) (fEvent *corev2.Event, fErr erro
 		MetricPointsProcessed.Add(float64(len(event.Metrics.Points)))
 	}
 
+	// Start the trace of the event, and propagate it to pipelined through the
+	// event annotations
+	ctx, span := tracing.Start(context.Background(), "eventd.handle_event", tracing.EventAttributes(event)...)
+	defer func() { tracing.End(span, fErr) }()
+	tracing.InjectEvent(ctx, event)
+
 	// If the event does not contain a check (rather, it contains metrics)
 	// publish the event without writing to the store
 	if !event.HasCheck() {
 		e.Logger.Println(event)
 		EventsProcessed.WithLabelValues(EventsProcessedLabelSuccess, EventsProcessedTypeLabelMetrics).Inc()
-		return event, e.publishEventWithDuration(event)
+		return event, e.publishEventWithDuration(event, nil)
 	}
 
-	ctx := context.WithValue(context.Background(), corev2.NamespaceKey, event.Entity.Namespace)
+	ctx = context.WithValue(ctx, corev2.NamespaceKey, event.Entity.Namespace)
+
+	// Evaluate the output metric thresholds of the events the agents did not
+	// evaluate, like the events of the agent and backend APIs. The events the
+	// agents evaluated are either failing, or evaluated to the same result.
+	if event.Check.Status == 0 && event.HasMetrics() && len(event.Check.OutputMetricThresholds) > 0 {
+		event.Check.Status = thresholds.Evaluate(event)
+	}
 
 	// Create a proxy entity if required and update the event's entity with it,
 	// but only if the event's entity is not an agent.
@@ -537,7 +557,7 @@ NOTTL:
 
 	EventsProcessed.WithLabelValues(EventsProcessedLabelSuccess, EventsProcessedTypeLabelCheck).Inc()
 
-	return event, e.publishEventWithDuration(event)
+	return event, e.publishEventWithDuration(event, prevEvent)
 }
 
 func (e *Eventd) handleCheckTTLNotification(ctx context.Context, state store.OperatorState) error {
@@ -618,7 +638,7 @@ func (e *Eventd) handleFailure(ctx context.Context, event *corev2.Event) error {
 		return err
 	}
 	es := e.store.GetEventStore()
-	updatedEvent, _, err := es.UpdateEvent(ctx, failedCheckEvent)
+	updatedEvent, prevEvent, err := es.UpdateEvent(ctx, failedCheckEvent)
 	if err != nil {
 		if _, ok := err.(*store.ErrInternal); ok {
 			// Fatal error
@@ -631,7 +651,7 @@ func (e *Eventd) handleFailure(ctx context.Context, event *corev2.Event) error {
 	}
 
 	e.Logger.Println(updatedEvent)
-	return e.bus.Publish(messaging.TopicEvent, updatedEvent)
+	return e.publishEventWithDuration(updatedEvent, prevEvent)
 }
 
 func (e *Eventd) createFailedCheckEvent(ctx context.Context, event *corev2.Event) (*corev2.Event, error) {
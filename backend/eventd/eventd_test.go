@@ -0,0 +1,88 @@
+package eventd
+
+import (
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/sensu/sensu-go/thresholds"
+)
+
+func TestEventdOutputMetricThresholds(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         uint32
+		value          float64
+		wantStatus     uint32
+		wantAnnotation string
+	}{
+		{
+			name:           "exceeded threshold",
+			value:          95,
+			wantStatus:     2,
+			wantAnnotation: thresholds.AnnotationPrefix + "disk_used/critical",
+		},
+		{
+			name:           "within threshold",
+			value:          50,
+			wantStatus:     0,
+			wantAnnotation: thresholds.AnnotationPrefix + "disk_used/ok",
+		},
+		{
+			name:       "failing check",
+			status:     1,
+			value:      95,
+			wantStatus: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+			require.NoError(t, err)
+			require.NoError(t, bus.Start())
+			defer func() {
+				assert.NoError(t, bus.Stop())
+			}()
+
+			event := corev2.FixtureEvent("entity1", "check1")
+			event.Entity.EntityClass = corev2.EntityAgentClass
+			event.Check.Status = tt.status
+			event.Check.Output = "disk_used 95"
+			event.Metrics = &corev2.Metrics{
+				Points: []*corev2.MetricPoint{{Name: "disk_used", Value: tt.value}},
+			}
+			event.Check.OutputMetricThresholds = []*corev2.MetricThreshold{
+				{
+					Name:       "disk_used",
+					Thresholds: []*corev2.MetricThresholdRule{{Max: "90", Status: 2}},
+				},
+			}
+
+			es := &mockstore.MockStore{}
+			es.On("UpdateEvent", mock.Anything).Return(event, (*corev2.Event)(nil), nil)
+			s := &mockstore.V2MockStore{}
+			s.On("GetEventStore").Return(es)
+
+			e := &Eventd{
+				store:  s,
+				bus:    bus,
+				Logger: NoopLogger{},
+			}
+			got, err := e.handleMessage(event)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, got.Check.Status)
+			// The thresholds override the status of the event, not its output
+			assert.Equal(t, "disk_used 95", got.Check.Output)
+			if tt.wantAnnotation != "" {
+				assert.Contains(t, got.Annotations, tt.wantAnnotation)
+			} else {
+				assert.Empty(t, got.Annotations)
+			}
+		})
+	}
+}
@@ -0,0 +1,387 @@
+// Package kafka bridges the events of the message bus into Kafka topics,
+// through the produce API of a Kafka REST proxy.
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+)
+
+const (
+	// DefaultBatchSize is the maximum number of records produced in a single
+	// request.
+	DefaultBatchSize = 100
+
+	// DefaultFlushInterval is the interval at which the records are produced,
+	// when there are fewer than the batch size.
+	DefaultFlushInterval = time.Second
+
+	// DefaultBufferSize is the number of bus messages buffered by the bridge.
+	DefaultBufferSize = 1000
+
+	// RequestTimeout is the time allowed to the REST proxy to respond.
+	RequestTimeout = 10 * time.Second
+
+	// RecordsCounterName is the name of the prometheus counter of the records
+	// produced to Kafka.
+	RecordsCounterName = "sensu_go_kafka_bridge_records_total"
+)
+
+var recordsCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: RecordsCounterName,
+		Help: "The total number of records produced to Kafka by the bridge",
+	},
+	[]string{"topic", "status"},
+)
+
+func init() {
+	if err := prometheus.Register(recordsCounter); err != nil {
+		panic(fmt.Errorf("error registering %s: %s", RecordsCounterName, err))
+	}
+}
+
+// Config configures a Bridge.
+type Config struct {
+	// Bus is the message bus the events are read from.
+	Bus messaging.MessageBus
+
+	// RESTProxyURL is the URL of the Kafka REST proxy.
+	RESTProxyURL string
+
+	// Topics maps the bus topics, like messaging.TopicEvent and
+	// messaging.TopicKeepalive, to the Kafka topics they are produced to.
+	Topics map[string]string
+
+	// Serialization is the serialization of the records, one of
+	// SerializationJSON, SerializationProtobuf or SerializationAvro. It
+	// defaults to SerializationJSON.
+	Serialization string
+
+	// AvroSchema is the Avro schema of the values, registered by the REST
+	// proxy. It must describe the JSON representation of the events.
+	AvroSchema string
+
+	// AvroSchemaID is the ID of the Avro schema of the values in the schema
+	// registry. It takes precedence over AvroSchema.
+	AvroSchemaID int
+
+	// BatchSize is the maximum number of records produced in a single
+	// request. It defaults to DefaultBatchSize.
+	BatchSize int
+
+	// FlushInterval is the interval at which the records are produced. It
+	// defaults to DefaultFlushInterval.
+	FlushInterval time.Duration
+
+	// Client is the HTTP client of the REST proxy. It defaults to a client
+	// with a timeout of RequestTimeout.
+	Client *http.Client
+}
+
+// message is a bus message, with the bus topic it was received from.
+type message struct {
+	topic string
+	event *corev2.Event
+}
+
+// subscriber receives the messages of a bus topic.
+type subscriber struct {
+	topic    string
+	receiver chan interface{}
+}
+
+// Receiver implements messaging.Subscriber.
+func (s *subscriber) Receiver() chan<- interface{} {
+	return s.receiver
+}
+
+// Bridge is a daemon which produces the events of the message bus to Kafka
+// topics. The records are produced at least once per flush interval; the
+// records that can't be produced are dropped, and counted, so that a slow
+// REST proxy does not hold the bus up.
+type Bridge struct {
+	bus           messaging.MessageBus
+	endpoint      string
+	topics        map[string]string
+	serializer    serializer
+	batchSize     int
+	flushInterval time.Duration
+	client        *http.Client
+	subscriptions []messaging.Subscription
+	messages      chan message
+	ctx           context.Context
+	cancel        context.CancelFunc
+	done          chan struct{}
+	stopped       chan struct{}
+	receivers     sync.WaitGroup
+	wg            sync.WaitGroup
+	errChan       chan error
+}
+
+// New returns a new Bridge.
+func New(config Config) (*Bridge, error) {
+	if config.RESTProxyURL == "" {
+		return nil, errors.New("the Kafka REST proxy URL is required")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(config.RESTProxyURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Kafka REST proxy URL: %s", err)
+	}
+	if len(config.Topics) == 0 {
+		return nil, errors.New("no topic to bridge")
+	}
+	serializer, err := newSerializer(config)
+	if err != nil {
+		return nil, err
+	}
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	flushInterval := config.FlushInterval
+	if flushInterval <= 0 {
+		flushInterval = DefaultFlushInterval
+	}
+	client := config.Client
+	if client == nil {
+		client = &http.Client{Timeout: RequestTimeout}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Bridge{
+		bus:           config.Bus,
+		endpoint:      endpoint.String(),
+		topics:        config.Topics,
+		serializer:    serializer,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		client:        client,
+		messages:      make(chan message, DefaultBufferSize),
+		ctx:           ctx,
+		cancel:        cancel,
+		done:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+		errChan:       make(chan error, 1),
+	}, nil
+}
+
+// Start subscribes the Bridge to the bus topics, and starts producing their
+// events.
+func (b *Bridge) Start() error {
+	for topic := range b.topics {
+		sub := &subscriber{
+			topic:    topic,
+			receiver: make(chan interface{}, DefaultBufferSize),
+		}
+		subscription, err := b.bus.Subscribe(topic, "kafka-bridge", sub)
+		if err != nil {
+			_ = b.cancelSubscriptions()
+			return err
+		}
+		b.subscriptions = append(b.subscriptions, subscription)
+		b.receivers.Add(1)
+		go b.receive(sub)
+	}
+	b.wg.Add(1)
+	go b.produceLoop()
+	return nil
+}
+
+// Stop the Bridge, after producing the events received.
+func (b *Bridge) Stop() error {
+	err := b.cancelSubscriptions()
+	close(b.done)
+	b.receivers.Wait()
+	close(b.stopped)
+	b.wg.Wait()
+	b.cancel()
+	close(b.errChan)
+	return err
+}
+
+// Err returns a channel that the caller can use to listen for terminal errors
+// indicating a premature shutdown of the Daemon.
+func (b *Bridge) Err() <-chan error {
+	return b.errChan
+}
+
+// Name returns the daemon name
+func (b *Bridge) Name() string {
+	return "kafka-bridge"
+}
+
+func (b *Bridge) cancelSubscriptions() error {
+	var err error
+	for _, subscription := range b.subscriptions {
+		if cerr := subscription.Cancel(); cerr != nil {
+			err = cerr
+		}
+	}
+	b.subscriptions = nil
+	return err
+}
+
+// receive forwards the events received from a bus topic to the produce loop,
+// until the bridge is stopped.
+func (b *Bridge) receive(sub *subscriber) {
+	defer b.receivers.Done()
+	for {
+		select {
+		case <-b.done:
+			for {
+				select {
+				case msg := <-sub.receiver:
+					b.forward(sub.topic, msg)
+				default:
+					return
+				}
+			}
+		case msg := <-sub.receiver:
+			b.forward(sub.topic, msg)
+		}
+	}
+}
+
+// forward forwards an event received from a bus topic to the produce loop,
+// or drops it if the produce loop fell behind.
+func (b *Bridge) forward(topic string, msg interface{}) {
+	var event *corev2.Event
+	switch msg := msg.(type) {
+	case *corev2.Event:
+		event = msg
+	case *messaging.EventWithPrevious:
+		event = msg.Event
+	}
+	if event == nil {
+		return
+	}
+	select {
+	case b.messages <- message{topic: topic, event: event}:
+	default:
+		recordsCounter.WithLabelValues(b.topics[topic], "dropped").Inc()
+	}
+}
+
+// produceLoop batches the events by Kafka topic, and produces each batch
+// when it is full or at the flush interval.
+func (b *Bridge) produceLoop() {
+	defer b.wg.Done()
+	ticker := time.NewTicker(b.flushInterval)
+	defer ticker.Stop()
+	batches := make(map[string][]record)
+	flush := func(ctx context.Context) {
+		for topic, records := range batches {
+			b.produce(ctx, topic, records)
+			delete(batches, topic)
+		}
+	}
+	for {
+		select {
+		case <-b.stopped:
+			// Produce the records received before stopping
+			for {
+				select {
+				case msg := <-b.messages:
+					b.add(b.ctx, batches, msg)
+				default:
+					flush(b.ctx)
+					return
+				}
+			}
+		case msg := <-b.messages:
+			b.add(b.ctx, batches, msg)
+		case <-ticker.C:
+			flush(b.ctx)
+		}
+	}
+}
+
+// add adds the record of a message to the batch of its Kafka topic, and
+// produces the batch if it is full.
+func (b *Bridge) add(ctx context.Context, batches map[string][]record, msg message) {
+	topic := b.topics[msg.topic]
+	rec, err := b.serializer.record(msg.event)
+	if err != nil {
+		logger.WithError(err).WithField("topic", topic).Error("failed to serialize event")
+		recordsCounter.WithLabelValues(topic, "error").Inc()
+		return
+	}
+	batches[topic] = append(batches[topic], rec)
+	if len(batches[topic]) >= b.batchSize {
+		b.produce(ctx, topic, batches[topic])
+		delete(batches, topic)
+	}
+}
+
+// produce produces records to a Kafka topic. The errors are logged.
+func (b *Bridge) produce(ctx context.Context, topic string, records []record) {
+	if len(records) == 0 {
+		return
+	}
+	failed, err := b.post(ctx, topic, records)
+	if err != nil {
+		logger.WithError(err).WithField("topic", topic).Errorf("failed to produce %d records", failed)
+	}
+	recordsCounter.WithLabelValues(topic, "error").Add(float64(failed))
+	recordsCounter.WithLabelValues(topic, "ok").Add(float64(len(records) - failed))
+}
+
+// produceResponse is a response of the produce API of the Kafka REST proxy.
+type produceResponse struct {
+	Offsets []struct {
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+// post posts records to the produce API of the REST proxy, and returns the
+// number of records that failed to be produced.
+func (b *Bridge) post(ctx context.Context, topic string, records []record) (int, error) {
+	body, err := json.Marshal(b.serializer.request(records))
+	if err != nil {
+		return len(records), err
+	}
+	endpoint := fmt.Sprintf("%s/topics/%s", b.endpoint, url.PathEscape(topic))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return len(records), err
+	}
+	req.Header.Set("Content-Type", b.serializer.contentType())
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return len(records), err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return len(records), fmt.Errorf("kafka REST proxy responded with status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+
+	// The records are produced independently, and each can fail
+	var response produceResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return 0, nil
+	}
+	failed := 0
+	for _, offset := range response.Offsets {
+		if offset.ErrorCode != nil {
+			failed++
+			err = errors.New(offset.Error)
+		}
+	}
+	return failed, err
+}
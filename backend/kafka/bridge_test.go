@@ -0,0 +1,209 @@
+package kafka
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// restProxy is a fake Kafka REST proxy recording the produce requests.
+type restProxy struct {
+	mu       sync.Mutex
+	requests map[string][]produceRequest
+	types    map[string]string
+}
+
+func newRESTProxy(t *testing.T) (*restProxy, *httptest.Server) {
+	proxy := &restProxy{
+		requests: make(map[string][]produceRequest),
+		types:    make(map[string]string),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var req produceRequest
+		require.NoError(t, json.Unmarshal(body, &req))
+		proxy.mu.Lock()
+		defer proxy.mu.Unlock()
+		proxy.requests[r.URL.Path] = append(proxy.requests[r.URL.Path], req)
+		proxy.types[r.URL.Path] = r.Header.Get("Content-Type")
+		_, _ = w.Write([]byte(`{"offsets":[]}`))
+	}))
+	t.Cleanup(server.Close)
+	return proxy, server
+}
+
+func (p *restProxy) records(path string) []record {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var records []record
+	for _, req := range p.requests[path] {
+		records = append(records, req.Records...)
+	}
+	return records
+}
+
+func newBus(t *testing.T) messaging.MessageBus {
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+	t.Cleanup(func() { _ = bus.Stop() })
+	return bus
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{
+			name:    "no URL",
+			config:  Config{Topics: map[string]string{messaging.TopicEvent: "events"}},
+			wantErr: true,
+		},
+		{
+			name:    "no topic",
+			config:  Config{RESTProxyURL: "http://localhost:8082"},
+			wantErr: true,
+		},
+		{
+			name: "unknown serialization",
+			config: Config{
+				RESTProxyURL:  "http://localhost:8082",
+				Topics:        map[string]string{messaging.TopicEvent: "events"},
+				Serialization: "xml",
+			},
+			wantErr: true,
+		},
+		{
+			name: "avro without schema",
+			config: Config{
+				RESTProxyURL:  "http://localhost:8082",
+				Topics:        map[string]string{messaging.TopicEvent: "events"},
+				Serialization: SerializationAvro,
+			},
+			wantErr: true,
+		},
+		{
+			name: "avro with schema ID",
+			config: Config{
+				RESTProxyURL:  "http://localhost:8082",
+				Topics:        map[string]string{messaging.TopicEvent: "events"},
+				Serialization: SerializationAvro,
+				AvroSchemaID:  1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBridge(t *testing.T) {
+	proxy, server := newRESTProxy(t)
+	bus := newBus(t)
+	bridge, err := New(Config{
+		Bus:          bus,
+		RESTProxyURL: server.URL,
+		Topics: map[string]string{
+			messaging.TopicEvent:     "sensu.events",
+			messaging.TopicKeepalive: "sensu.keepalives",
+		},
+		FlushInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.NoError(t, bridge.Start())
+
+	event := corev2.FixtureEvent("entity", "check")
+	require.NoError(t, bus.Publish(messaging.TopicEvent, &messaging.EventWithPrevious{Event: event}))
+	require.NoError(t, bus.Publish(messaging.TopicKeepalive, corev2.FixtureEvent("entity", "keepalive")))
+	// The messages that are not events are ignored
+	require.NoError(t, bus.Publish(messaging.TopicEvent, "not an event"))
+
+	assert.Eventually(t, func() bool {
+		return len(proxy.records("/topics/sensu.events")) == 1 && len(proxy.records("/topics/sensu.keepalives")) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, bridge.Stop())
+
+	records := proxy.records("/topics/sensu.events")
+	assert.Equal(t, "default/entity", records[0].Key)
+	value, err := json.Marshal(records[0].Value)
+	require.NoError(t, err)
+	var produced corev2.Event
+	require.NoError(t, json.Unmarshal(value, &produced))
+	assert.Equal(t, "check", produced.Check.Name)
+	assert.Equal(t, "application/vnd.kafka.json.v2+json", proxy.types["/topics/sensu.events"])
+}
+
+func TestBridgeStopProducesBatches(t *testing.T) {
+	proxy, server := newRESTProxy(t)
+	bus := newBus(t)
+	bridge, err := New(Config{
+		Bus:           bus,
+		RESTProxyURL:  server.URL,
+		Topics:        map[string]string{messaging.TopicEvent: "sensu.events"},
+		Serialization: SerializationProtobuf,
+		FlushInterval: time.Hour,
+	})
+	require.NoError(t, err)
+	require.NoError(t, bridge.Start())
+
+	event := corev2.FixtureEvent("entity", "check")
+	require.NoError(t, bus.Publish(messaging.TopicEvent, event))
+	// The events received are produced when the bridge stops
+	require.NoError(t, bridge.Stop())
+
+	records := proxy.records("/topics/sensu.events")
+	require.Len(t, records, 1)
+	value, err := base64.StdEncoding.DecodeString(records[0].Value.(string))
+	require.NoError(t, err)
+	var produced corev2.Event
+	require.NoError(t, produced.Unmarshal(value))
+	assert.Equal(t, "check", produced.Check.Name)
+	assert.Equal(t, "application/vnd.kafka.binary.v2+json", proxy.types["/topics/sensu.events"])
+}
+
+func TestAvroSerializerRequest(t *testing.T) {
+	s := avroSerializer{schema: `{"type":"record","name":"Event","fields":[]}`}
+	req := s.request(nil)
+	assert.Equal(t, s.schema, req.ValueSchema)
+	assert.Zero(t, req.ValueSchemaID)
+
+	s.schemaID = 42
+	req = s.request(nil)
+	assert.Empty(t, req.ValueSchema)
+	assert.Equal(t, 42, req.ValueSchemaID)
+}
+
+func TestBridgeProduceErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"offsets":[{"partition":0,"offset":1},{"error_code":50002,"error":"timeout"}]}`))
+	}))
+	defer server.Close()
+	bridge, err := New(Config{
+		RESTProxyURL: server.URL,
+		Topics:       map[string]string{messaging.TopicEvent: "sensu.events"},
+	})
+	require.NoError(t, err)
+
+	records := []record{{Value: "a"}, {Value: "b"}}
+	failed, err := bridge.post(bridge.ctx, "sensu.events", records)
+	assert.Error(t, err)
+	assert.Equal(t, 1, failed)
+}
@@ -0,0 +1,7 @@
+package kafka
+
+import "github.com/sirupsen/logrus"
+
+var logger = logrus.WithFields(logrus.Fields{
+	"component": "kafka-bridge",
+})
@@ -0,0 +1,139 @@
+package kafka
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+
+	corev2 "github.com/sensu/core/v2"
+)
+
+const (
+	// SerializationJSON produces the events as JSON.
+	SerializationJSON = "json"
+
+	// SerializationProtobuf produces the events as binary protobuf.
+	SerializationProtobuf = "protobuf"
+
+	// SerializationAvro produces the events as Avro, with a schema registered
+	// in the schema registry of the REST proxy.
+	SerializationAvro = "avro"
+)
+
+// record is a record of a request to the produce API of the Kafka REST proxy.
+type record struct {
+	Key   interface{} `json:"key,omitempty"`
+	Value interface{} `json:"value"`
+}
+
+// produceRequest is a request to the produce API of the Kafka REST proxy.
+type produceRequest struct {
+	ValueSchema   string   `json:"value_schema,omitempty"`
+	ValueSchemaID int      `json:"value_schema_id,omitempty"`
+	Records       []record `json:"records"`
+}
+
+// serializer encodes the events in the records produced to Kafka.
+type serializer interface {
+	// contentType is the content type of the produce requests.
+	contentType() string
+
+	// record returns the record of an event.
+	record(event *corev2.Event) (record, error)
+
+	// request returns the produce request of the given records.
+	request(records []record) produceRequest
+}
+
+// newSerializer returns the serializer of the given serialization.
+func newSerializer(config Config) (serializer, error) {
+	switch config.Serialization {
+	case "", SerializationJSON:
+		return jsonSerializer{}, nil
+	case SerializationProtobuf:
+		return protobufSerializer{}, nil
+	case SerializationAvro:
+		if config.AvroSchema == "" && config.AvroSchemaID == 0 {
+			return nil, fmt.Errorf("the avro serialization requires a schema or a schema ID")
+		}
+		return avroSerializer{schema: config.AvroSchema, schemaID: config.AvroSchemaID}, nil
+	default:
+		return nil, fmt.Errorf("unknown serialization: %q", config.Serialization)
+	}
+}
+
+// eventKey returns the key of the records of an event, so that the events of
+// an entity are produced to the same partition.
+func eventKey(event *corev2.Event) string {
+	if event.Entity == nil {
+		return ""
+	}
+	return path.Join(event.Entity.Namespace, event.Entity.Name)
+}
+
+type jsonSerializer struct{}
+
+func (jsonSerializer) contentType() string {
+	return "application/vnd.kafka.json.v2+json"
+}
+
+func (jsonSerializer) record(event *corev2.Event) (record, error) {
+	return record{Key: eventKey(event), Value: event}, nil
+}
+
+func (jsonSerializer) request(records []record) produceRequest {
+	return produceRequest{Records: records}
+}
+
+type protobufSerializer struct{}
+
+func (protobufSerializer) contentType() string {
+	return "application/vnd.kafka.binary.v2+json"
+}
+
+func (protobufSerializer) record(event *corev2.Event) (record, error) {
+	value, err := event.Marshal()
+	if err != nil {
+		return record{}, err
+	}
+	return record{
+		Key:   base64.StdEncoding.EncodeToString([]byte(eventKey(event))),
+		Value: base64.StdEncoding.EncodeToString(value),
+	}, nil
+}
+
+func (protobufSerializer) request(records []record) produceRequest {
+	return produceRequest{Records: records}
+}
+
+// avroSerializer produces the JSON representation of the events, which the
+// REST proxy encodes with the Avro schema. The records have no key, which
+// would require a key schema.
+type avroSerializer struct {
+	schema   string
+	schemaID int
+}
+
+func (avroSerializer) contentType() string {
+	return "application/vnd.kafka.avro.v2+json"
+}
+
+func (avroSerializer) record(event *corev2.Event) (record, error) {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return record{}, err
+	}
+	return record{Value: json.RawMessage(value)}, nil
+}
+
+func (s avroSerializer) request(records []record) produceRequest {
+	req := produceRequest{Records: records}
+	// The schema registered in the schema registry takes precedence
+	if s.schemaID != 0 {
+		req.ValueSchemaID = s.schemaID
+	} else {
+		req.ValueSchema = s.schema
+	}
+	return req
+}
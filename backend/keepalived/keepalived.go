@@ -17,6 +17,7 @@ import (
 	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/system"
 	"github.com/sirupsen/logrus"
 )
 
@@ -594,6 +595,17 @@ type agentMetadata struct {
 	Interval int `json:"i"`
 }
 
+// recordEntityStateSnapshot records the system of an entity in its state
+// history, if it changed. Failing to record it is not fatal.
+func (k *Keepalived) recordEntityStateSnapshot(state *corev3.EntityState, timestamp int64) {
+	tctx, cancel := context.WithTimeout(k.ctx, k.storeTimeout)
+	defer cancel()
+	snapshot := storev2.NewEntityStateSnapshot(state, timestamp)
+	if err := k.store.GetEntityStateHistoryStore().AddEntityStateSnapshot(tctx, snapshot); err != nil {
+		logger.WithError(err).WithField("entity", state.Metadata.Name).Warn("failed to record entity state snapshot")
+	}
+}
+
 // handleUpdate sets the entity's last seen time and publishes an OK check event
 // to the message bus.
 func (k *Keepalived) handleUpdate(e *corev2.Event) error {
@@ -605,12 +617,26 @@ func (k *Keepalived) handleUpdate(e *corev2.Event) error {
 	entity.LastSeen = e.Timestamp
 	_, entityState := corev3.V2EntityToV3(entity)
 
+	// Store the system metrics sent with the keepalive, if any, on the entity
+	// state, without altering the annotations shared with the entity config
+	if e.HasMetrics() {
+		if metrics, ok := system.MetricsFromPoints(e.Metrics.Points); ok {
+			annotations := make(map[string]string, len(entityState.Metadata.Annotations)+1)
+			for k, v := range entityState.Metadata.Annotations {
+				annotations[k] = v
+			}
+			annotations[system.MetricsAnnotation] = metrics.Annotation()
+			entityState.Metadata.Annotations = annotations
+		}
+	}
+
 	entityStateStore := storev2.Of[*corev3.EntityState](k.store)
 
 	if err := entityStateStore.CreateOrUpdate(k.ctx, entityState); err != nil {
 		logger.WithError(err).Error("error updating entity state in store")
 		return err
 	}
+	k.recordEntityStateSnapshot(entityState, e.Timestamp)
 
 	event := createKeepaliveEvent(e)
 	event.Check.Status = 0
@@ -13,6 +13,8 @@ import (
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/system"
 	"github.com/sensu/sensu-go/testing/mockstore"
 )
 
@@ -85,6 +87,9 @@ func newKeepalivedTest(t *testing.T) *keepalivedTest {
 	stor.On("GetEntityStore").Return(eventStore)
 	stor.On("GetEntityConfigStore").Return(ec)
 	stor.On("GetEntityStateStore").Return(es)
+	history := new(mockstore.EntityStateHistoryStore)
+	history.On("AddEntityStateSnapshot", mock.Anything, mock.Anything).Return(nil)
+	stor.On("GetEntityStateHistoryStore").Return(history)
 	ec.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(nil)
 	es.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(nil)
 	keepaliveStore := &KeepaliveStore{}
@@ -399,6 +404,63 @@ func TestCreateKeepaliveEvent(t *testing.T) {
 	assert.Equal(t, uint32(120), keepaliveEvent.Check.Timeout)
 }
 
+func TestHandleUpdateSystemMetrics(t *testing.T) {
+	messageBus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, messageBus.Start())
+	defer func() { assert.NoError(t, messageBus.Stop()) }()
+
+	store := &mockstore.V2MockStore{}
+	es := new(mockstore.EntityStateStore)
+	store.On("GetEntityStateStore").Return(es)
+	var state *corev3.EntityState
+	es.On("CreateOrUpdate", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		state = args.Get(1).(*corev3.EntityState)
+	}).Return(nil)
+	history := new(mockstore.EntityStateHistoryStore)
+	var snapshot *storev2.EntityStateSnapshot
+	history.On("AddEntityStateSnapshot", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		snapshot = args.Get(1).(*storev2.EntityStateSnapshot)
+	}).Return(nil)
+	store.On("GetEntityStateHistoryStore").Return(history)
+
+	keepalived, err := New(Config{
+		Store:        store,
+		Bus:          messageBus,
+		WorkerCount:  1,
+		BufferSize:   1,
+		StoreTimeout: time.Minute,
+	})
+	require.NoError(t, err)
+
+	metrics := system.Metrics{
+		Timestamp:         1667902530,
+		Load1:             0.5,
+		Load5:             0.25,
+		Load15:            0.125,
+		MemoryUsedPercent: 42,
+		DiskUsedPercent:   84,
+	}
+	event := corev2.FixtureEvent("entity1", "keepalive")
+	event.Entity.Annotations = map[string]string{"foo": "bar"}
+	event.Metrics = &corev2.Metrics{Points: metrics.Points()}
+	require.NoError(t, keepalived.handleUpdate(event))
+
+	require.NotNil(t, state)
+	got, err := system.ParseMetricsAnnotation(state.Metadata.Annotations)
+	require.NoError(t, err)
+	assert.Equal(t, &metrics, got)
+	assert.Equal(t, "bar", state.Metadata.Annotations["foo"])
+
+	// The annotations of the entity are left untouched
+	assert.NotContains(t, event.Entity.Annotations, system.MetricsAnnotation)
+
+	// The system of the entity is recorded in its state history
+	require.NotNil(t, snapshot)
+	assert.Equal(t, "entity1", snapshot.Entity)
+	assert.Equal(t, event.Timestamp, snapshot.Timestamp)
+}
+
 func TestCreateRegistrationEvent(t *testing.T) {
 	event := corev2.FixtureEntity("entity1")
 	keepaliveEvent := createRegistrationEvent(event)
@@ -0,0 +1,46 @@
+package messaging
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/google/uuid"
+	corev2 "github.com/sensu/core/v2"
+)
+
+type previousEventKey struct{}
+
+// EventWithPrevious is published by eventd on TopicEvent for the check events
+// that replaced a stored event. It carries the stored event as it was before
+// the update, so that filters and mutators can act on state transitions.
+type EventWithPrevious struct {
+	*corev2.Event
+
+	// Previous is the event as it was stored before the update.
+	Previous *corev2.Event
+}
+
+// ContextWithPreviousEvent returns a context carrying the previous state of
+// the event being processed.
+func ContextWithPreviousEvent(ctx context.Context, event *corev2.Event) context.Context {
+	return context.WithValue(ctx, previousEventKey{}, event)
+}
+
+// PreviousEvent returns the previous state of the event being processed, or
+// nil if the event was not stored before.
+func PreviousEvent(ctx context.Context) *corev2.Event {
+	event, _ := ctx.Value(previousEventKey{}).(*corev2.Event)
+	return event
+}
+
+// IdempotencyKey returns the key identifying an event across its deliveries,
+// which is its ID, or an empty string if it has none.
+func IdempotencyKey(event *corev2.Event) string {
+	if event == nil || len(event.ID) == 0 {
+		return ""
+	}
+	if id, err := uuid.FromBytes(event.ID); err == nil {
+		return id.String()
+	}
+	return hex.EncodeToString(event.ID)
+}
@@ -99,6 +99,13 @@ type MessageBus interface {
 	// are delivered to the subscriber as type `interface{}`.
 	Subscribe(topic string, consumer string, subscriber Subscriber) (Subscription, error)
 
+	// SubscribePattern allows a consumer to subscribe to all the topics
+	// matching a pattern, whose segments, separated by colons, are either
+	// literal or a "*" wildcard matching any single segment. For instance,
+	// "sensu:check:*:linux" matches the linux subscription of every
+	// namespace.
+	SubscribePattern(pattern string, consumer string, subscriber Subscriber) (Subscription, error)
+
 	// Publish sends a message to a topic.
 	Publish(topic string, message interface{}) error
 }
@@ -0,0 +1,187 @@
+package messaging
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	WizardBusSubscriberQueueDepth = "sensu_go_bus_subscriber_queue_depth"
+	WizardBusMessagesDropped      = "sensu_go_bus_messages_dropped_total"
+	WizardBusConsumerLabelName    = "consumer"
+	WizardBusPolicyLabelName      = "policy"
+)
+
+var (
+	subscriberQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: WizardBusSubscriberQueueDepth,
+			Help: "Number of messages waiting to be received by a subscriber",
+		},
+		[]string{WizardBusTopicLabelName, WizardBusConsumerLabelName},
+	)
+
+	messagesDroppedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: WizardBusMessagesDropped,
+			Help: "The total number of messages dropped because a subscriber fell behind",
+		},
+		[]string{WizardBusTopicLabelName, WizardBusConsumerLabelName, WizardBusPolicyLabelName},
+	)
+)
+
+func init() {
+	_ = prometheus.Register(subscriberQueueDepth)
+	_ = prometheus.Register(messagesDroppedCounter)
+}
+
+// OverflowPolicy is what the bus does with the messages published to a
+// subscriber whose receiver is full.
+type OverflowPolicy string
+
+const (
+	// OverflowBlock blocks the publisher until the subscriber receives the
+	// message. It is the default policy.
+	OverflowBlock OverflowPolicy = "block"
+
+	// OverflowDropOldest drops the oldest message waiting to be received by
+	// the subscriber, in favor of the message published.
+	OverflowDropOldest OverflowPolicy = "drop-oldest"
+
+	// OverflowDropNewest drops the message published.
+	OverflowDropNewest OverflowPolicy = "drop-newest"
+)
+
+// ParseOverflowPolicy parses an overflow policy.
+func ParseOverflowPolicy(s string) (OverflowPolicy, error) {
+	switch policy := OverflowPolicy(s); policy {
+	case OverflowBlock, OverflowDropOldest, OverflowDropNewest:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid overflow policy %q: must be one of %s, %s or %s", s, OverflowBlock, OverflowDropOldest, OverflowDropNewest)
+	}
+}
+
+// OverflowPolicySubscriber is a Subscriber which chooses its overflow policy.
+// The policies of the bus configuration take precedence.
+type OverflowPolicySubscriber interface {
+	Subscriber
+
+	// OverflowPolicy returns the overflow policy of the subscriber.
+	OverflowPolicy() OverflowPolicy
+}
+
+// binding is a subscriber bound to a topic, with its overflow policy.
+type binding struct {
+	topic      string
+	consumer   string
+	subscriber Subscriber
+	policy     OverflowPolicy
+
+	// queue holds the messages of the drop-oldest subscribers, since the
+	// messages of their receiver can't be dropped. They are forwarded to the
+	// receiver until stop is closed.
+	queue chan interface{}
+	stop  chan struct{}
+
+	// mu guards the metrics of the subscriber, which are not updated once
+	// the binding is closed, since the topic can still send messages to it.
+	mu     sync.Mutex
+	closed bool
+}
+
+func newBinding(topic, consumer string, sub Subscriber, policy OverflowPolicy) *binding {
+	b := &binding{
+		topic:      topic,
+		consumer:   consumer,
+		subscriber: sub,
+		policy:     policy,
+		stop:       make(chan struct{}),
+	}
+	if policy == OverflowDropOldest {
+		size := cap(sub.Receiver())
+		if size < 1 {
+			size = 1
+		}
+		b.queue = make(chan interface{}, size)
+		go b.forward()
+	}
+	return b
+}
+
+// depth returns the number of messages waiting to be received.
+func (b *binding) depth() int {
+	return len(b.subscriber.Receiver()) + len(b.queue)
+}
+
+// send sends a message to the subscriber, according to its overflow policy.
+func (b *binding) send(msg interface{}, done chan struct{}) {
+	b.mu.Lock()
+	if !b.closed {
+		subscriberQueueDepth.WithLabelValues(b.topic, b.consumer).Set(float64(b.depth()))
+	}
+	b.mu.Unlock()
+	switch b.policy {
+	case OverflowDropNewest:
+		if !trySend(b.subscriber.Receiver(), msg) {
+			b.dropped()
+		}
+	case OverflowDropOldest:
+		for !trySend(b.queue, msg) {
+			select {
+			case <-b.queue:
+				b.dropped()
+			default:
+			}
+		}
+	default:
+		safeSend(b.subscriber.Receiver(), msg, done)
+	}
+}
+
+func (b *binding) dropped() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.closed {
+		messagesDroppedCounter.WithLabelValues(b.topic, b.consumer, string(b.policy)).Inc()
+	}
+}
+
+// forward forwards the queued messages to the receiver of the subscriber.
+func (b *binding) forward() {
+	for {
+		select {
+		case <-b.stop:
+			return
+		case msg := <-b.queue:
+			safeSend(b.subscriber.Receiver(), msg, b.stop)
+		}
+	}
+}
+
+// close stops forwarding the messages, and removes the metrics of the
+// subscriber, so that the series of the topics and consumers that come and go,
+// like the ones of the agents, don't pile up.
+func (b *binding) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	close(b.stop)
+	subscriberQueueDepth.DeleteLabelValues(b.topic, b.consumer)
+	messagesDroppedCounter.DeleteLabelValues(b.topic, b.consumer, string(b.policy))
+}
+
+// trySend attempts a non-blocking send, which can be to a closed channel.
+func trySend(c chan<- interface{}, msg interface{}) (sent bool) {
+	defer func() {
+		_ = recover()
+	}()
+	select {
+	case c <- msg:
+		return true
+	default:
+		return false
+	}
+}
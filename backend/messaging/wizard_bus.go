@@ -1,6 +1,7 @@
 package messaging
 
 import (
+	"errors"
 	"strings"
 	"sync"
 	"time"
@@ -47,12 +48,19 @@ func init() {
 // message types over a single topic, however, as we do not want to introduce
 // a dependency on reflection to determine the type of the received interface{}.
 type WizardBus struct {
-	topics  sync.Map
-	errchan chan error
+	topics   sync.Map
+	patterns sync.Map
+	errchan  chan error
+	policies map[string]OverflowPolicy
 }
 
 // WizardBusConfig configures a WizardBus
-type WizardBusConfig struct{}
+type WizardBusConfig struct {
+	// OverflowPolicies are the overflow policies of the subscribers, by
+	// consumer name. They take precedence over the policies chosen by the
+	// subscribers.
+	OverflowPolicies map[string]OverflowPolicy
+}
 
 // WizardOption is a functional option.
 type WizardOption func(*WizardBus) error
@@ -60,7 +68,8 @@ type WizardOption func(*WizardBus) error
 // NewWizardBus creates a new WizardBus.
 func NewWizardBus(cfg WizardBusConfig, opts ...WizardOption) (*WizardBus, error) {
 	bus := &WizardBus{
-		errchan: make(chan error, 1),
+		errchan:  make(chan error, 1),
+		policies: cfg.OverflowPolicies,
 	}
 	for _, opt := range opts {
 		if err := opt(bus); err != nil {
@@ -79,10 +88,12 @@ func (b *WizardBus) Start() error {
 
 // Stop ...
 func (b *WizardBus) Stop() error {
-	b.topics.Range(func(_, value interface{}) bool {
+	closeTopic := func(_, value interface{}) bool {
 		value.(*wizardTopic).Close()
 		return true
-	})
+	}
+	b.topics.Range(closeTopic)
+	b.patterns.Range(closeTopic)
 	return nil
 }
 
@@ -107,7 +118,8 @@ func (b *WizardBus) Name() string {
 func (b *WizardBus) createTopic(topic string) *wizardTopic {
 	wTopic := &wizardTopic{
 		id:       topic,
-		bindings: make(map[string]Subscriber),
+		bindings: make(map[string]*binding),
+		policies: b.policies,
 		done:     make(chan struct{}),
 	}
 	return wTopic
@@ -139,6 +151,43 @@ func (b *WizardBus) Subscribe(topic string, consumer string, sub Subscriber) (Su
 	return subscription, err
 }
 
+// SubscribePattern subscribes to the WizardBus topics matching a pattern. The
+// subscribers of a pattern are bound to a WizardTopic of their own, which
+// is sent the messages published to the matching topics.
+func (b *WizardBus) SubscribePattern(pattern string, consumer string, sub Subscriber) (Subscription, error) {
+	if pattern == "" {
+		return Subscription{}, errors.New("empty topic pattern")
+	}
+	var t *wizardTopic
+	value, ok := b.patterns.Load(pattern)
+	if !ok || value.(*wizardTopic).IsClosed() {
+		t = b.createTopic(pattern)
+		b.patterns.Store(pattern, t)
+	} else {
+		t = value.(*wizardTopic)
+	}
+
+	return t.Subscribe(consumer, sub)
+}
+
+// MatchTopic returns whether a topic matches a pattern of SubscribePattern.
+func MatchTopic(pattern, topic string) bool {
+	for {
+		pi := strings.IndexByte(pattern, ':')
+		ti := strings.IndexByte(topic, ':')
+		if (pi < 0) != (ti < 0) {
+			return false
+		}
+		if pi < 0 {
+			return pattern == "*" || pattern == topic
+		}
+		if segment := pattern[:pi]; segment != "*" && segment != topic[:ti] {
+			return false
+		}
+		pattern, topic = pattern[pi+1:], topic[ti+1:]
+	}
+}
+
 func findGenericTopic(topic string) string {
 	index := strings.IndexRune(topic, ':')
 	if index <= 0 {
@@ -147,8 +196,8 @@ func findGenericTopic(topic string) string {
 	return topic[:index]
 }
 
-// Publish publishes a message to a topic. If the topic does not
-// exist, this is a noop.
+// Publish publishes a message to a topic, and to the patterns matching it. If
+// the topic does not exist and matches no pattern, this is a noop.
 func (b *WizardBus) Publish(topic string, msg interface{}) error {
 	genericTopic := findGenericTopic(topic)
 	then := time.Now()
@@ -164,5 +213,12 @@ func (b *WizardBus) Publish(topic string, msg interface{}) error {
 		wTopic.Send(msg)
 	}
 
+	b.patterns.Range(func(key, value interface{}) bool {
+		if MatchTopic(key.(string), topic) {
+			value.(*wizardTopic).Send(msg)
+		}
+		return true
+	})
+
 	return nil
 }
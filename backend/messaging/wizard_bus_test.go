@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -133,3 +134,164 @@ func TestBug1407(t *testing.T) {
 	topic := value.(*wizardTopic)
 	assert.False(t, topic.IsClosed())
 }
+
+type policySubscriber struct {
+	channelSubscriber
+	policy OverflowPolicy
+}
+
+func (p policySubscriber) OverflowPolicy() OverflowPolicy {
+	return p.policy
+}
+
+func TestWizardBusOverflowPolicies(t *testing.T) {
+	bus, err := NewWizardBus(WizardBusConfig{
+		OverflowPolicies: map[string]OverflowPolicy{"configured": OverflowDropNewest},
+	})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+	defer func() {
+		_ = bus.Stop()
+	}()
+
+	newest := channelSubscriber{make(chan interface{}, 2)}
+	_, err = bus.Subscribe("topic", "configured", policySubscriber{newest, OverflowBlock})
+	require.NoError(t, err)
+	oldest := channelSubscriber{make(chan interface{}, 2)}
+	_, err = bus.Subscribe("topic", "oldest", policySubscriber{oldest, OverflowDropOldest})
+	require.NoError(t, err)
+
+	counter := messagesDroppedCounter.WithLabelValues("topic", "configured", string(OverflowDropNewest))
+	dropped := testutil.ToFloat64(counter)
+
+	// The publisher is not blocked by the subscribers that fell behind
+	for i := 0; i < 10; i++ {
+		require.NoError(t, bus.Publish("topic", i))
+	}
+
+	assert.Equal(t, 0, <-newest.Channel)
+	assert.Equal(t, 1, <-newest.Channel)
+	assert.Len(t, newest.Channel, 0)
+
+	// The oldest messages were dropped, apart from the ones received before
+	// the queue of the subscriber filled up
+	var received []int
+	for len(received) == 0 || received[len(received)-1] != 9 {
+		select {
+		case msg := <-oldest.Channel:
+			received = append(received, msg.(int))
+		case <-time.After(time.Second):
+			t.Fatalf("messages not received: %v", received)
+		}
+	}
+	assert.Less(t, len(received), 10)
+	assert.Contains(t, received, 8)
+
+	assert.Equal(t, dropped+8, testutil.ToFloat64(counter))
+}
+
+func TestWizardBusOverflowMetricsDeleted(t *testing.T) {
+	bus, err := NewWizardBus(WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+	defer func() {
+		_ = bus.Stop()
+	}()
+
+	newest := channelSubscriber{make(chan interface{}, 1)}
+	sub, err := bus.Subscribe("metrics-topic", "agent", policySubscriber{newest, OverflowDropNewest})
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, bus.Publish("metrics-topic", i))
+	}
+	assert.Equal(t, float64(2), testutil.ToFloat64(messagesDroppedCounter.WithLabelValues("metrics-topic", "agent", string(OverflowDropNewest))))
+
+	// The series of the subscriber are deleted when it unsubscribes
+	require.NoError(t, sub.Cancel())
+	assert.False(t, subscriberQueueDepth.DeleteLabelValues("metrics-topic", "agent"))
+	assert.False(t, messagesDroppedCounter.DeleteLabelValues("metrics-topic", "agent", string(OverflowDropNewest)))
+
+	// and not recreated by the messages sent to the closed binding
+	b := newBinding("closed-topic", "agent", newest, OverflowDropNewest)
+	b.close()
+	b.send(3, nil)
+	assert.False(t, subscriberQueueDepth.DeleteLabelValues("closed-topic", "agent"))
+	assert.False(t, messagesDroppedCounter.DeleteLabelValues("closed-topic", "agent", string(OverflowDropNewest)))
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	policy, err := ParseOverflowPolicy("drop-oldest")
+	require.NoError(t, err)
+	assert.Equal(t, OverflowDropOldest, policy)
+
+	_, err = ParseOverflowPolicy("drop-everything")
+	assert.Error(t, err)
+}
+
+func TestMatchTopic(t *testing.T) {
+	tests := []struct {
+		pattern string
+		topic   string
+		want    bool
+	}{
+		{"sensu:check:*:linux", "sensu:check:default:linux", true},
+		{"sensu:check:*:linux", "sensu:check:default:windows", false},
+		{"sensu:check:*:*", "sensu:check:default:linux", true},
+		{"sensu:check:*", "sensu:check:default:linux", false},
+		{"sensu:check:*:*", "sensu:check:default", false},
+		{"sensu:*", "sensu:event", true},
+		{"*", "sensu:event", false},
+		{"sensu:event", "sensu:event", true},
+		{"sensu:event", "sensu:event-raw", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.topic, func(t *testing.T) {
+			assert.Equal(t, tt.want, MatchTopic(tt.pattern, tt.topic))
+		})
+	}
+}
+
+func TestWizardBusSubscribePattern(t *testing.T) {
+	bus, err := NewWizardBus(WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+
+	_, err = bus.SubscribePattern("", "observer", channelSubscriber{make(chan interface{}, 1)})
+	assert.Error(t, err)
+
+	observer := channelSubscriber{make(chan interface{}, 10)}
+	subscription, err := bus.SubscribePattern(SubscriptionTopic("*", "linux"), "observer", observer)
+	require.NoError(t, err)
+
+	// The topics matching the pattern are delivered, whether or not they
+	// have subscribers of their own
+	sub := channelSubscriber{make(chan interface{}, 10)}
+	_, err = bus.Subscribe(SubscriptionTopic("default", "linux"), "agent", sub)
+	require.NoError(t, err)
+	require.NoError(t, bus.Publish(SubscriptionTopic("default", "linux"), "default"))
+	require.NoError(t, bus.Publish(SubscriptionTopic("acme", "linux"), "acme"))
+	require.NoError(t, bus.Publish(SubscriptionTopic("acme", "windows"), "windows"))
+
+	assert.Equal(t, "default", <-sub.Channel)
+	assert.Equal(t, "default", <-observer.Channel)
+	assert.Equal(t, "acme", <-observer.Channel)
+	assert.Len(t, observer.Channel, 0)
+
+	// The messages are no longer delivered once the subscription is
+	// cancelled
+	require.NoError(t, subscription.Cancel())
+	require.NoError(t, bus.Publish(SubscriptionTopic("acme", "linux"), "cancelled"))
+	assert.Len(t, observer.Channel, 0)
+
+	// The pattern can be subscribed to again
+	_, err = bus.SubscribePattern(SubscriptionTopic("*", "linux"), "observer", observer)
+	require.NoError(t, err)
+	require.NoError(t, bus.Publish(SubscriptionTopic("acme", "linux"), "resubscribed"))
+	assert.Equal(t, "resubscribed", <-observer.Channel)
+
+	// The patterns are closed when the bus stops
+	require.NoError(t, bus.Stop())
+	value, ok := bus.patterns.Load(SubscriptionTopic("*", "linux"))
+	require.True(t, ok)
+	assert.True(t, value.(*wizardTopic).IsClosed())
+}
@@ -8,7 +8,8 @@ import (
 // consumer channel bindings.
 type wizardTopic struct {
 	id       string
-	bindings map[string]Subscriber
+	bindings map[string]*binding
+	policies map[string]OverflowPolicy
 	sync.RWMutex
 	done chan struct{}
 }
@@ -16,15 +17,15 @@ type wizardTopic struct {
 // Send a message to all subscribers to this topic.
 func (t *wizardTopic) Send(msg interface{}) {
 	t.RLock()
-	subscribers := make([]Subscriber, 0, len(t.bindings))
-	for _, subscriber := range t.bindings {
-		subscribers = append(subscribers, subscriber)
+	bindings := make([]*binding, 0, len(t.bindings))
+	for _, binding := range t.bindings {
+		bindings = append(bindings, binding)
 	}
 	t.RUnlock()
 
-	for _, subscriber := range subscribers {
-		topicCounter.WithLabelValues(t.id).Set(float64(len(subscriber.Receiver())))
-		safeSend(subscriber.Receiver(), msg, t.done)
+	for _, binding := range bindings {
+		topicCounter.WithLabelValues(t.id).Set(float64(len(binding.subscriber.Receiver())))
+		binding.send(msg, t.done)
 	}
 }
 
@@ -44,10 +45,22 @@ func safeSend(c chan<- interface{}, message interface{}, done chan struct{}) {
 	}
 }
 
-// Subscribe a Subscriber to this topic and receive a Subscription.
+// Subscribe a Subscriber to this topic and receive a Subscription. The
+// overflow policy of the subscriber is the one configured for its consumer
+// id, if any, or the one it chooses, or OverflowBlock.
 func (t *wizardTopic) Subscribe(id string, sub Subscriber) (Subscription, error) {
+	policy := OverflowBlock
+	if p, ok := sub.(OverflowPolicySubscriber); ok && p.OverflowPolicy() != "" {
+		policy = p.OverflowPolicy()
+	}
+	if p, ok := t.policies[id]; ok {
+		policy = p
+	}
 	t.Lock()
-	t.bindings[id] = sub
+	if previous, ok := t.bindings[id]; ok {
+		previous.close()
+	}
+	t.bindings[id] = newBinding(t.id, id, sub, policy)
 	t.Unlock()
 
 	return Subscription{
@@ -59,7 +72,10 @@ func (t *wizardTopic) Subscribe(id string, sub Subscriber) (Subscription, error)
 // Unsubscribe a consumer from this topic.
 func (t *wizardTopic) unsubscribe(id string) error {
 	t.Lock()
-	delete(t.bindings, id)
+	if binding, ok := t.bindings[id]; ok {
+		binding.close()
+		delete(t.bindings, id)
+	}
 	if len(t.bindings) == 0 {
 		select {
 		case <-t.done:
@@ -81,7 +97,8 @@ func (t *wizardTopic) Close() {
 	default:
 	}
 	close(t.done)
-	for consumer := range t.bindings {
+	for consumer, binding := range t.bindings {
+		binding.close()
 		delete(t.bindings, consumer)
 	}
 	t.Unlock()
@@ -6,7 +6,9 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/tracing"
 	metricspkg "github.com/sensu/sensu-go/metrics"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -63,6 +65,12 @@ func (a *AdapterV1) processFilter(ctx context.Context, ref *corev2.ResourceRefer
 	}))
 	defer filterTimer.ObserveDuration()
 
+	ctx, span := tracing.Start(ctx, "pipeline.filter", tracing.PipelineAttributes(ctx, attribute.String("sensu.filter", ref.ResourceID()))...)
+	defer func() {
+		span.SetAttributes(attribute.Bool("sensu.filtered", filtered))
+		tracing.End(span, fErr)
+	}()
+
 	filter, err := a.getFilterAdapterForResource(ctx, ref)
 	if err != nil {
 		return false, err
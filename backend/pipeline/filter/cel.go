@@ -0,0 +1,116 @@
+package filter
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	corev2 "github.com/sensu/core/v2"
+)
+
+const (
+	// RuntimeAnnotation is the event filter annotation that selects the
+	// language of the filter expressions.
+	RuntimeAnnotation = "sensu.io/runtime"
+
+	// RuntimeJavascript selects javascript filter expressions. It is the
+	// default runtime.
+	RuntimeJavascript = "javascript"
+
+	// RuntimeCEL selects Common Expression Language (CEL) filter expressions.
+	// CEL expressions have access to the event, as the event variable, and
+	// must evaluate to a boolean. For instance:
+	//
+	//   event.check.status != 0 && event.entity.entity_class == "agent"
+	RuntimeCEL = "cel"
+
+	// maxCELPrograms is the number of compiled CEL programs kept in the
+	// cache.
+	maxCELPrograms = 4096
+)
+
+// filterRuntime returns the runtime of the filter expressions.
+func filterRuntime(filter *corev2.EventFilter) (string, error) {
+	runtime, ok := filter.Annotations[RuntimeAnnotation]
+	if !ok || runtime == "" {
+		return RuntimeJavascript, nil
+	}
+	switch runtime {
+	case RuntimeJavascript, RuntimeCEL:
+		return runtime, nil
+	default:
+		return "", fmt.Errorf("unknown filter runtime %q", runtime)
+	}
+}
+
+// celPrograms is a cache of compiled CEL programs, indexed by expression.
+// Compiling is far more expensive than evaluating, and filters are evaluated
+// for every event.
+type celPrograms struct {
+	mu       sync.RWMutex
+	env      *cel.Env
+	programs map[string]cel.Program
+}
+
+var celCache = newCELPrograms()
+
+func newCELPrograms() *celPrograms {
+	env, err := cel.NewEnv(cel.Variable("event", cel.DynType))
+	if err != nil {
+		// the environment is static, this can't happen
+		panic(err)
+	}
+	return &celPrograms{
+		env:      env,
+		programs: make(map[string]cel.Program),
+	}
+}
+
+// program returns the compiled program of the expression, compiling it if it
+// is not in the cache.
+func (c *celPrograms) program(expression string) (cel.Program, error) {
+	c.mu.RLock()
+	program, ok := c.programs[expression]
+	c.mu.RUnlock()
+	if ok {
+		return program, nil
+	}
+
+	ast, issues := c.env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("expression must evaluate to a boolean, not %s", ast.OutputType())
+	}
+	program, err := c.env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.programs) >= maxCELPrograms {
+		// expressions rarely change, so simply start over
+		c.programs = make(map[string]cel.Program)
+	}
+	c.programs[expression] = program
+	return program, nil
+}
+
+// Eval evaluates the CEL expression against the synthesized event.
+func (c *celPrograms) Eval(expression string, event interface{}) (bool, error) {
+	program, err := c.program(expression)
+	if err != nil {
+		return false, err
+	}
+	out, _, err := program.Eval(map[string]interface{}{"event": event})
+	if err != nil {
+		return false, err
+	}
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression evaluated to %T, not a boolean", out.Value())
+	}
+	return result, nil
+}
@@ -0,0 +1,150 @@
+package filter
+
+import (
+	"context"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/dynamic"
+)
+
+func TestCELProgramsEval(t *testing.T) {
+	event := corev2.FixtureEvent("entity1", "check1")
+	event.Check.Status = 2
+	tests := []struct {
+		name       string
+		expression string
+		want       bool
+		wantErr    bool
+	}{
+		{name: "string comparison", expression: `event.check.name == "check1"`, want: true},
+		{name: "numeric comparison", expression: "event.check.status > 1", want: true},
+		{name: "logical operators", expression: `event.check.status == 0 || event.entity.name != "entity1"`, want: false},
+		{name: "macros", expression: `has(event.entity.name) && !has(event.entity.region)`, want: true},
+		{name: "syntax error", expression: "event.check.status >", wantErr: true},
+		{name: "not a boolean", expression: "event.check.status + 1", wantErr: true},
+		{name: "not a boolean literal", expression: `"true"`, wantErr: true},
+		{name: "undeclared variable", expression: "entity.name == 'entity1'", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newCELPrograms().Eval(tt.expression, dynamic.Synthesize(event))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Eval() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCELProgramsCache(t *testing.T) {
+	programs := newCELPrograms()
+	for i := 0; i < 2; i++ {
+		if _, err := programs.Eval("event.check.status == 0", map[string]interface{}{"check": map[string]interface{}{"status": 0}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := len(programs.programs); got != 1 {
+		t.Errorf("expected 1 cached program, got %d", got)
+	}
+}
+
+func TestEvaluateEventFilterCEL(t *testing.T) {
+	tests := []struct {
+		name        string
+		action      string
+		runtime     string
+		expressions []string
+		want        bool
+	}{
+		{
+			name:        "allow filter matches",
+			action:      corev2.EventFilterActionAllow,
+			runtime:     RuntimeCEL,
+			expressions: []string{`event.check.name == "check1"`, `event.entity.name == "entity1"`},
+			want:        false,
+		},
+		{
+			name:        "allow filter does not match",
+			action:      corev2.EventFilterActionAllow,
+			runtime:     RuntimeCEL,
+			expressions: []string{`event.check.name == "check1"`, `event.entity.name == "entity2"`},
+			want:        true,
+		},
+		{
+			name:        "deny filter matches",
+			action:      corev2.EventFilterActionDeny,
+			runtime:     RuntimeCEL,
+			expressions: []string{`event.check.name == "check2"`, `event.entity.name == "entity1"`},
+			want:        true,
+		},
+		{
+			name:        "javascript expressions are not evaluated as cel",
+			action:      corev2.EventFilterActionDeny,
+			runtime:     RuntimeCEL,
+			expressions: []string{"event.check.name === 'check1'"},
+			want:        false,
+		},
+		{
+			name:        "unknown runtime allows the event",
+			action:      corev2.EventFilterActionAllow,
+			runtime:     "lua",
+			expressions: []string{`event.check.name == "check2"`},
+			want:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := corev2.FixtureEventFilter("filter")
+			filter.Action = tt.action
+			filter.Expressions = tt.expressions
+			filter.Annotations = map[string]string{RuntimeAnnotation: tt.runtime}
+			event := corev2.FixtureEvent("entity1", "check1")
+			if got := evaluateEventFilter(context.Background(), event, filter, nil, nil); got != tt.want {
+				t.Errorf("evaluateEventFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateEventFilterPreviousEvent(t *testing.T) {
+	tests := []struct {
+		name       string
+		runtime    string
+		expression string
+	}{
+		{
+			name:       "javascript",
+			runtime:    RuntimeJavascript,
+			expression: "event.previous && event.previous.check.status != 0 && event.check.status == 0",
+		},
+		{
+			name:       "cel",
+			runtime:    RuntimeCEL,
+			expression: "has(event.previous) && event.previous.check.status != 0 && event.check.status == 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := corev2.FixtureEventFilter("resolution")
+			filter.Action = corev2.EventFilterActionAllow
+			filter.Expressions = []string{tt.expression}
+			filter.Annotations = map[string]string{RuntimeAnnotation: tt.runtime}
+
+			event := corev2.FixtureEvent("entity1", "check1")
+			if !evaluateEventFilter(context.Background(), event, filter, nil, nil) {
+				t.Error("expected event without previous state to be filtered")
+			}
+
+			previous := corev2.FixtureEvent("entity1", "check1")
+			previous.Check.Status = 2
+			ctx := messaging.ContextWithPreviousEvent(context.Background(), previous)
+			if evaluateEventFilter(ctx, event, filter, nil, nil) {
+				t.Error("expected resolution event to be allowed")
+			}
+		})
+	}
+}
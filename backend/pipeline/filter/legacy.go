@@ -8,6 +8,7 @@ import (
 	"github.com/robertkrimen/otto"
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/asset"
+	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 	"github.com/sensu/sensu-go/js"
@@ -95,7 +96,8 @@ func (l *LegacyAdapter) Filter(ctx context.Context, ref *corev2.ResourceReferenc
 			return false, err
 		}
 	}
-	filtered := evaluateEventFilter(ctx, event, filter, assets)
+	funcs := l.filterFuncs(ctx, event, filter)
+	filtered := evaluateEventFilter(ctx, event, filter, assets, funcs)
 	if filtered {
 		logger.WithFields(fields).Debug("denying event with custom filter")
 		return true, nil
@@ -105,8 +107,19 @@ func (l *LegacyAdapter) Filter(ctx context.Context, ref *corev2.ResourceReferenc
 	return false, nil
 }
 
+// filterFuncs returns the functions available to the expressions of the
+// filter, as properties of the sensu object.
+func (l *LegacyAdapter) filterFuncs(ctx context.Context, event *corev2.Event, filter *corev2.EventFilter) map[string]interface{} {
+	funcs := make(map[string]interface{}, len(PipelineFilterFuncs)+1)
+	for name, fn := range PipelineFilterFuncs {
+		funcs[name] = fn
+	}
+	funcs[RateLimitedFuncName] = rateLimitedFunc(l.Store, rateLimitKey(ctx, event, filter))
+	return funcs
+}
+
 // Returns true if the event should be filtered/denied.
-func evaluateEventFilter(ctx context.Context, event *corev2.Event, filter *corev2.EventFilter, assets asset.RuntimeAssetSet) bool {
+func evaluateEventFilter(ctx context.Context, event *corev2.Event, filter *corev2.EventFilter, assets asset.RuntimeAssetSet, funcs map[string]interface{}) bool {
 	// Redact the entity to avoid leaking sensitive information
 	event.Entity = event.Entity.GetRedactedEntity()
 
@@ -157,11 +170,33 @@ func evaluateEventFilter(ctx context.Context, event *corev2.Event, filter *corev
 		event.Entity.ObjectMeta.Labels = make(map[string]string)
 	}
 
+	runtime, err := filterRuntime(filter)
+	if err != nil {
+		logger.WithFields(fields).WithError(err).Error("allowing event - unable to evaluate filter")
+		return false
+	}
+	fields["runtime"] = runtime
+
 	synth := dynamic.Synthesize(event)
+	if previous := messaging.PreviousEvent(ctx); previous != nil {
+		// Expose the previous state of the event as event.previous, redacted
+		// like the event itself
+		prev := *previous
+		prev.Entity = prev.Entity.GetRedactedEntity()
+		if m, ok := synth.(map[string]interface{}); ok {
+			m["previous"] = dynamic.Synthesize(&prev)
+		}
+	}
 	env := FilterExecutionEnvironment{
 		Event:  synth,
 		Assets: assets,
-		Funcs:  PipelineFilterFuncs,
+		Funcs:  funcs,
+	}
+	eval := env.Eval
+	if runtime == RuntimeCEL {
+		eval = func(ctx context.Context, expression string) (bool, error) {
+			return celCache.Eval(expression, synth)
+		}
 	}
 
 	switch filter.Action {
@@ -171,9 +206,9 @@ func evaluateEventFilter(ctx context.Context, event *corev2.Event, filter *corev
 	case corev2.EventFilterActionAllow:
 
 		for _, expression := range filter.Expressions {
-			match, err := env.Eval(ctx, expression)
+			match, err := eval(ctx, expression)
 			if err != nil {
-				logger.WithFields(fields).WithError(err).Error("error evaluating javascript event filter")
+				logger.WithFields(fields).WithError(err).Error("error evaluating event filter")
 				continue
 			}
 
@@ -194,9 +229,9 @@ func evaluateEventFilter(ctx context.Context, event *corev2.Event, filter *corev
 	case corev2.EventFilterActionDeny:
 
 		for _, expression := range filter.Expressions {
-			match, err := env.Eval(ctx, expression)
+			match, err := eval(ctx, expression)
 			if err != nil {
-				logger.WithFields(fields).WithError(err).Error("error evaluating javascript event filter")
+				logger.WithFields(fields).WithError(err).Error("error evaluating event filter")
 				continue
 			}
 
@@ -439,7 +439,7 @@ func Test_evaluateEventFilter(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := evaluateEventFilter(tt.args.ctx, tt.args.event, tt.args.filter, tt.args.assets); got != tt.want {
+			if got := evaluateEventFilter(tt.args.ctx, tt.args.event, tt.args.filter, tt.args.assets, nil); got != tt.want {
 				t.Errorf("evaluateEventFilter() = %v, want %v", got, tt.want)
 			}
 		})
@@ -0,0 +1,78 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+// RateLimitedFuncName is the name of the filter function that rate limits
+// events. It is available to filter expressions as sensu.RateLimited.
+const RateLimitedFuncName = "RateLimited"
+
+// rateLimitKey returns the key that identifies the rate limit counter of an
+// event in a filter. Counters are kept per entity, check and pipeline
+// workflow, and therefore per handler.
+func rateLimitKey(ctx context.Context, event *corev2.Event, filter *corev2.EventFilter) string {
+	var check string
+	if event.HasCheck() {
+		check = event.Check.Name
+	}
+	return path.Join(
+		event.Entity.Namespace,
+		filter.Name,
+		corev2.ContextPipeline(ctx),
+		corev2.ContextPipelineWorkflow(ctx),
+		event.Entity.Name,
+		check,
+	)
+}
+
+// rateLimitedFunc returns the filter function that records an occurrence of
+// the event and returns true if the event occurred more than count times in
+// the window, in seconds. Counters are stored in the store so that they are
+// shared by all backends.
+//
+// For instance, a deny filter with the expression
+// "sensu.RateLimited(3, 3600)" lets at most 3 events per hour through.
+func rateLimitedFunc(s storev2.RateLimitStoreGetter, key string) func(context.Context, interface{}, interface{}) (bool, error) {
+	return func(ctx context.Context, count, window interface{}) (bool, error) {
+		limit, err := toFloat(count)
+		if err != nil {
+			return false, fmt.Errorf("%s: invalid count: %s", RateLimitedFuncName, err)
+		}
+		seconds, err := toFloat(window)
+		if err != nil {
+			return false, fmt.Errorf("%s: invalid window: %s", RateLimitedFuncName, err)
+		}
+		if limit < 0 || seconds <= 0 {
+			return false, fmt.Errorf("%s: count must not be negative and window must be positive", RateLimitedFuncName)
+		}
+		occurrences, err := s.GetRateLimitStore().IncrementRateLimit(ctx, key, time.Now(), time.Duration(seconds*float64(time.Second)))
+		if err != nil {
+			return false, err
+		}
+		return float64(occurrences) > limit, nil
+	}
+}
+
+func toFloat(v interface{}) (float64, error) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("expected a number, got %T", v)
+	}
+}
@@ -0,0 +1,68 @@
+package filter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRateLimited(t *testing.T) {
+	tests := []struct {
+		name        string
+		expression  string
+		occurrences int64
+		want        bool
+	}{
+		{name: "under the limit", expression: "sensu.RateLimited(3, 3600)", occurrences: 2, want: false},
+		{name: "at the limit", expression: "sensu.RateLimited(3, 3600)", occurrences: 3, want: false},
+		{name: "over the limit", expression: "sensu.RateLimited(3, 3600)", occurrences: 4, want: true},
+		{name: "fractional window", expression: "sensu.RateLimited(1, 0.5)", occurrences: 2, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), corev2.PipelineKey, "pipeline")
+			ctx = context.WithValue(ctx, corev2.PipelineWorkflowKey, "workflow")
+			event := corev2.FixtureEvent("entity1", "check1")
+			filter := corev2.FixtureEventFilter("rate-limit")
+			filter.Action = corev2.EventFilterActionDeny
+			filter.Expressions = []string{tt.expression}
+
+			s := new(mockstore.V2MockStore)
+			rs := new(mockstore.RateLimitStore)
+			s.On("GetRateLimitStore").Return(rs)
+			key := "default/rate-limit/pipeline/workflow/entity1/check1"
+			rs.On("IncrementRateLimit", mock.Anything, key, mock.Anything, mock.AnythingOfType("time.Duration")).
+				Return(tt.occurrences, nil)
+
+			adapter := &LegacyAdapter{Store: s}
+			funcs := adapter.filterFuncs(ctx, event, filter)
+			if got := evaluateEventFilter(ctx, event, filter, nil, funcs); got != tt.want {
+				t.Errorf("evaluateEventFilter() = %v, want %v", got, tt.want)
+			}
+			rs.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRateLimitedWindow(t *testing.T) {
+	s := new(mockstore.V2MockStore)
+	rs := new(mockstore.RateLimitStore)
+	s.On("GetRateLimitStore").Return(rs)
+	rs.On("IncrementRateLimit", mock.Anything, "key", mock.Anything, 90*time.Second).Return(int64(1), nil)
+
+	fn := rateLimitedFunc(s, "key")
+	if _, err := fn(context.Background(), int64(1), float64(90)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fn(context.Background(), int64(1), nil); err == nil {
+		t.Fatal("expected non-nil error")
+	}
+	if _, err := fn(context.Background(), int64(1), int64(0)); err == nil {
+		t.Fatal("expected non-nil error")
+	}
+	rs.AssertExpectations(t)
+}
@@ -6,7 +6,9 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/tracing"
 	metricspkg "github.com/sensu/sensu-go/metrics"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -48,6 +50,9 @@ func (a *AdapterV1) processHandler(ctx context.Context, ref *corev2.ResourceRefe
 	}))
 	defer handlerTimer.ObserveDuration()
 
+	ctx, span := tracing.Start(ctx, "pipeline.handler", tracing.PipelineAttributes(ctx, attribute.String("sensu.handler", ref.ResourceID()))...)
+	defer func() { tracing.End(span, fErr) }()
+
 	handler, err := a.getHandlerAdapterForResource(ctx, ref)
 	if err != nil {
 		return err
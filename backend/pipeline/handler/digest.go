@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	utillogging "github.com/sensu/sensu-go/util/logging"
+)
+
+const (
+	// DigestIntervalAnnotation is the handler annotation that enables the
+	// digest mode of a handler. It holds the interval between two executions
+	// of the handler, as a duration (e.g. "15m"). In digest mode, the mutated
+	// data of the events is accumulated, and the handler is executed once per
+	// interval with a JSON array of the accumulated data.
+	DigestIntervalAnnotation = "sensu.io/digest/interval"
+
+	// maxDigestEvents is the number of events after which a digest is
+	// flushed, even if its interval has not elapsed.
+	maxDigestEvents = 1000
+)
+
+// digests holds the digests of the handlers in digest mode.
+var digests = newDigestPool()
+
+// digestInterval returns the digest interval of the handler, or 0 if the
+// handler is not in digest mode.
+func digestInterval(handler *corev2.Handler) (time.Duration, error) {
+	value, ok := handler.Annotations[DigestIntervalAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("invalid %s annotation: %q", DigestIntervalAnnotation, value)
+	}
+	if handler.Type == HandlerPagerDutyType {
+		return 0, fmt.Errorf("%s handlers do not support digests", handler.Type)
+	}
+	return interval, nil
+}
+
+// digest is the data accumulated for a handler since its last execution.
+type digest struct {
+	adapter *LegacyAdapter
+	handler *corev2.Handler
+	event   *corev2.Event
+	data    [][]byte
+	timer   *time.Timer
+}
+
+// payload returns the JSON array of the accumulated data. Data that is not
+// valid JSON, e.g. the output of a mutator, is included as a string.
+func (d *digest) payload() ([]byte, error) {
+	items := make([]json.RawMessage, 0, len(d.data))
+	for _, data := range d.data {
+		if json.Valid(data) {
+			items = append(items, data)
+			continue
+		}
+		item, err := json.Marshal(string(data))
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return json.Marshal(items)
+}
+
+// digestPool accumulates the events of the handlers in digest mode, and
+// executes the handlers on schedule. Digests are kept in memory, and are lost
+// when the backend stops.
+type digestPool struct {
+	mu      sync.Mutex
+	digests map[string]*digest
+}
+
+func newDigestPool() *digestPool {
+	return &digestPool{
+		digests: make(map[string]*digest),
+	}
+}
+
+// Add adds the mutated data of the event to the digest of the handler. The
+// digest is flushed after interval, or as soon as it holds maxDigestEvents
+// events.
+func (p *digestPool) Add(adapter *LegacyAdapter, handler *corev2.Handler, event *corev2.Event, mutatedData []byte, interval time.Duration) {
+	key := path.Join(handler.Namespace, handler.Name)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	d, ok := p.digests[key]
+	if !ok {
+		d = &digest{}
+		d.timer = time.AfterFunc(interval, func() { p.flush(key, d) })
+		p.digests[key] = d
+	}
+	// The handler is executed with the most recent definition of the handler,
+	// and the most recent event.
+	d.adapter = adapter
+	d.handler = handler
+	d.event = event
+	d.data = append(d.data, mutatedData)
+	if len(d.data) >= maxDigestEvents && d.timer.Stop() {
+		go p.flush(key, d)
+	}
+}
+
+// flush removes the digest from the pool and executes its handler.
+func (p *digestPool) flush(key string, d *digest) {
+	p.mu.Lock()
+	if p.digests[key] == d {
+		delete(p.digests, key)
+	}
+	p.mu.Unlock()
+
+	fields := utillogging.EventFields(d.event, false)
+	fields["handler"] = d.handler.Name
+	fields["digest_size"] = len(d.data)
+
+	payload, err := d.payload()
+	if err != nil {
+		logger.WithFields(fields).WithError(err).Error("failed to build handler digest")
+		return
+	}
+	logger.WithFields(fields).Debug("flushing handler digest")
+	_ = d.adapter.execute(context.Background(), d.handler, d.event, payload, fields)
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLegacyAdapter_HandleDigest(t *testing.T) {
+	listener, host, port, closeListener := newListener(t, "tcp")
+	defer closeListener()
+	lines := acceptLines(listener)
+
+	handler := corev2.FixtureHandler("digest")
+	handler.Type = "tcp"
+	handler.Socket = &corev2.HandlerSocket{Host: host, Port: port}
+	handler.Annotations = map[string]string{DigestIntervalAnnotation: "100ms"}
+
+	stor := &mockstore.V2MockStore{}
+	cs := new(mockstore.ConfigStore)
+	stor.On("GetConfigStore").Return(cs)
+	cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*corev2.Handler]{Value: handler}, nil)
+	hrs := new(mockstore.HandlerResultStore)
+	stor.On("GetHandlerResultStore").Return(hrs)
+	hrs.On("AddHandlerResult", mock.Anything, mock.Anything).Return(nil)
+
+	l := &LegacyAdapter{Store: stor, StoreTimeout: time.Second}
+	ref := &corev2.ResourceReference{Name: "digest"}
+	event := corev2.FixtureEvent("entity1", "check1")
+	for _, data := range []string{`{"n":1}`, `{"n":2}`, "not json"} {
+		if err := l.Handle(context.Background(), ref, event, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case line := <-lines:
+		var payload []interface{}
+		if err := json.Unmarshal([]byte(line[1].(string)), &payload); err != nil {
+			t.Fatal(err)
+		}
+		if len(payload) != 3 || payload[2] != "not json" {
+			t.Errorf("bad digest payload: %v", payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("digest was not flushed")
+	}
+}
+
+func TestDigestInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		handlerType string
+		annotation  string
+		want        time.Duration
+		wantErr     bool
+	}{
+		{name: "disabled", handlerType: "pipe"},
+		{name: "enabled", handlerType: "pipe", annotation: "15m", want: 15 * time.Minute},
+		{name: "invalid", handlerType: "pipe", annotation: "often", wantErr: true},
+		{name: "negative", handlerType: "pipe", annotation: "-1m", wantErr: true},
+		{name: "pagerduty", handlerType: HandlerPagerDutyType, annotation: "15m", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := corev2.FixtureHandler("handler")
+			handler.Type = tt.handlerType
+			if tt.annotation != "" {
+				handler.Annotations = map[string]string{DigestIntervalAnnotation: tt.annotation}
+			}
+			got, err := digestInterval(handler)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("digestInterval() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("digestInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/pipeline/handler/rpc"
+	"github.com/sensu/sensu-go/command"
+	utillogging "github.com/sensu/sensu-go/util/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	// HandlerGRPCType represents handlers that stream event data to a
+	// long-lived gRPC sidecar service, implementing the Handler service of the
+	// rpc package. The address of the service is the socket of the handler.
+	HandlerGRPCType = "grpc"
+
+	// GRPCTLSAnnotation is the grpc handler annotation that enables TLS when
+	// set to "true". TLS is also enabled by any of the other TLS annotations.
+	GRPCTLSAnnotation = "sensu.io/grpc/tls"
+
+	// GRPCTrustedCAFileAnnotation is the grpc handler annotation that holds
+	// the path to the CA file used to verify the sidecar certificate.
+	GRPCTrustedCAFileAnnotation = "sensu.io/grpc/trusted-ca-file"
+
+	// GRPCCertFileAnnotation and GRPCKeyFileAnnotation are the grpc handler
+	// annotations that hold the client certificate and key used for mutual
+	// TLS authentication.
+	GRPCCertFileAnnotation = "sensu.io/grpc/cert-file"
+	GRPCKeyFileAnnotation  = "sensu.io/grpc/key-file"
+
+	// GRPCInsecureSkipVerifyAnnotation is the grpc handler annotation that
+	// disables the verification of the sidecar certificate when set to
+	// "true".
+	GRPCInsecureSkipVerifyAnnotation = "sensu.io/grpc/insecure-skip-verify"
+)
+
+// errGRPCStreamClosed is returned for the requests that were in flight when a
+// stream was closed.
+var errGRPCStreamClosed = errors.New("grpc handler stream closed")
+
+// grpcStreams holds the streams opened by grpc handlers, shared by all the
+// handlers that use the same sidecar.
+var grpcStreams = newGRPCStreamPool()
+
+// grpcHandler sends the mutated data to the gRPC sidecar of a Sensu grpc
+// handler and waits for its response.
+func (l *LegacyAdapter) grpcHandler(ctx context.Context, handler *corev2.Handler, event *corev2.Event, mutatedData []byte) (*command.ExecutionResponse, error) {
+	// Prepare log entry
+	fields := utillogging.EventFields(event, false)
+	fields["handler_name"] = handler.Name
+	fields["handler_namespace"] = handler.Namespace
+	fields["pipeline"] = corev2.ContextPipeline(ctx)
+	fields["pipeline_workflow"] = corev2.ContextPipelineWorkflow(ctx)
+
+	if handler.Socket == nil || handler.Socket.Host == "" || handler.Socket.Port == 0 {
+		return nil, errors.New("grpc handlers need a socket host and port")
+	}
+	maxOutput, err := maxOutputSize(handler)
+	if err != nil {
+		return nil, err
+	}
+	tlsOpts, err := grpcHandlerTLSOptions(handler)
+	if err != nil {
+		return nil, err
+	}
+	address := net.JoinHostPort(handler.Socket.Host, strconv.Itoa(int(handler.Socket.Port)))
+	fields["handler_address"] = address
+
+	timeout := handler.Timeout
+	if timeout == 0 {
+		timeout = DefaultSocketTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	stream, err := grpcStreams.Get(address, tlsOpts)
+	if err != nil {
+		return nil, fmt.Errorf("could not open grpc handler stream: %s", err)
+	}
+	response, err := stream.Send(ctx, &rpc.HandleRequest{
+		Namespace: handler.Namespace,
+		Handler:   handler.Name,
+		Data:      mutatedData,
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.WithFields(fields).Debug("event grpc handler executed")
+	output, truncated := truncateOutput(response.Output, maxOutput)
+	return &command.ExecutionResponse{
+		Output:    output,
+		Status:    int(response.Status),
+		Truncated: truncated,
+	}, nil
+}
+
+// grpcHandlerTLSOptions returns the TLS options of a grpc handler, read from
+// its annotations, or nil if the handler does not use TLS.
+func grpcHandlerTLSOptions(handler *corev2.Handler) (*corev2.TLSOptions, error) {
+	annotations := handler.Annotations
+	enableTLS, err := boolAnnotation(annotations, GRPCTLSAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	tlsOpts, err := tlsOptionsFromAnnotations(annotations, GRPCTrustedCAFileAnnotation, GRPCCertFileAnnotation, GRPCKeyFileAnnotation, GRPCInsecureSkipVerifyAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	if enableTLS || tlsOpts.TrustedCAFile != "" || tlsOpts.CertFile != "" || tlsOpts.InsecureSkipVerify {
+		return tlsOpts, nil
+	}
+	return nil, nil
+}
+
+// CloseGRPCStreams closes the streams opened by the grpc handlers, and their
+// connections. The next executions of the handlers reopen them.
+func CloseGRPCStreams() {
+	grpcStreams.Close()
+}
+
+// grpcStreamPool opens and caches a stream per sidecar address and TLS
+// configuration. Broken streams are dropped, and reopened by the next
+// request.
+type grpcStreamPool struct {
+	mu      sync.Mutex
+	streams map[string]*grpcStream
+}
+
+func newGRPCStreamPool() *grpcStreamPool {
+	return &grpcStreamPool{
+		streams: make(map[string]*grpcStream),
+	}
+}
+
+// Get returns the stream to the sidecar at address, opening it with the
+// given TLS options, if any, if needed.
+func (p *grpcStreamPool) Get(address string, tlsOpts *corev2.TLSOptions) (*grpcStream, error) {
+	key := address
+	if tlsOpts != nil {
+		key = fmt.Sprintf("%s/%+v", address, *tlsOpts)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if stream, ok := p.streams[key]; ok {
+		select {
+		case <-stream.done:
+		default:
+			return stream, nil
+		}
+	}
+	creds := insecure.NewCredentials()
+	if tlsOpts != nil {
+		tlsConfig, err := tlsOpts.ToClientTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("invalid grpc handler tls configuration: %s", err)
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	stream, err := openGRPCStream(address, creds)
+	if err != nil {
+		return nil, err
+	}
+	p.streams[key] = stream
+	return stream, nil
+}
+
+// Close closes all the streams of the pool.
+func (p *grpcStreamPool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for key, stream := range p.streams {
+		stream.close(errGRPCStreamClosed)
+		delete(p.streams, key)
+	}
+}
+
+// grpcStream multiplexes the requests of concurrent handler executions over a
+// single Handle stream, matching responses with requests by id.
+type grpcStream struct {
+	conn   *grpc.ClientConn
+	stream rpc.Handler_HandleClient
+
+	// sendMu serializes sends on the stream, which may block on flow
+	// control. It is never held with mu, so that the responses are
+	// dispatched while a send is blocked.
+	sendMu sync.Mutex
+
+	// mu guards the fields below.
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan *rpc.HandleResponse
+	err     error
+
+	done chan struct{}
+}
+
+func openGRPCStream(address string, creds credentials.TransportCredentials) (*grpcStream, error) {
+	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	// The stream outlives any request, it is only canceled by closing the
+	// connection.
+	stream, err := rpc.NewHandlerClient(conn).Handle(context.Background())
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	s := &grpcStream{
+		conn:    conn,
+		stream:  stream,
+		pending: make(map[uint64]chan *rpc.HandleResponse),
+		done:    make(chan struct{}),
+	}
+	go s.receive()
+	return s, nil
+}
+
+// receive dispatches the responses received on the stream until it breaks.
+func (s *grpcStream) receive() {
+	for {
+		response, err := s.stream.Recv()
+		if err != nil {
+			s.close(err)
+			return
+		}
+		s.mu.Lock()
+		ch, ok := s.pending[response.Id]
+		delete(s.pending, response.Id)
+		s.mu.Unlock()
+		if ok {
+			ch <- response
+		}
+	}
+}
+
+// close closes the stream and its connection. Requests in flight fail with
+// err.
+func (s *grpcStream) close(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+	s.err = err
+	s.pending = make(map[uint64]chan *rpc.HandleResponse)
+	close(s.done)
+	_ = s.conn.Close()
+}
+
+// Send sends the request on the stream, and waits for its response.
+func (s *grpcStream) Send(ctx context.Context, request *rpc.HandleRequest) (*rpc.HandleResponse, error) {
+	ch := make(chan *rpc.HandleResponse, 1)
+
+	s.mu.Lock()
+	if s.err != nil {
+		s.mu.Unlock()
+		return nil, s.err
+	}
+	s.nextID++
+	request.Id = s.nextID
+	s.pending[request.Id] = ch
+	s.mu.Unlock()
+
+	s.sendMu.Lock()
+	err := s.stream.Send(request)
+	s.sendMu.Unlock()
+	if err != nil {
+		s.close(err)
+		return nil, err
+	}
+
+	select {
+	case response := <-ch:
+		if response.Error != "" {
+			return nil, errors.New(response.Error)
+		}
+		return response, nil
+	case <-s.done:
+		return nil, s.err
+	case <-ctx.Done():
+		s.mu.Lock()
+		delete(s.pending, request.Id)
+		s.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/pipeline/handler/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// testHandlerServer replies to every request with a status of 2 when the data
+// contains "critical", 0 otherwise, and an error when it contains "fail".
+type testHandlerServer struct {
+	rpc.UnimplementedHandlerServer
+}
+
+func (testHandlerServer) Handle(stream rpc.Handler_HandleServer) error {
+	for {
+		request, err := stream.Recv()
+		if err != nil {
+			return nil
+		}
+		response := &rpc.HandleResponse{
+			Id:     request.Id,
+			Output: fmt.Sprintf("%s/%s: %s", request.Namespace, request.Handler, request.Data),
+		}
+		if strings.Contains(string(request.Data), "critical") {
+			response.Status = 2
+		}
+		if strings.Contains(string(request.Data), "fail") {
+			response.Error = "failed"
+		}
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+	}
+}
+
+func serveTestHandler(t *testing.T, opts ...grpc.ServerOption) (host string, port uint32) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(opts...)
+	rpc.RegisterHandlerServer(server, testHandlerServer{})
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(func() {
+		grpcStreams.Close()
+		server.Stop()
+	})
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), uint32(addr.Port)
+}
+
+func TestLegacyAdapter_grpcHandler(t *testing.T) {
+	host, port := serveTestHandler(t)
+	handler := corev2.FixtureHandler("sidecar")
+	handler.Type = HandlerGRPCType
+	handler.Socket = &corev2.HandlerSocket{Host: host, Port: port}
+
+	adapter := &LegacyAdapter{}
+	event := corev2.FixtureEvent("entity1", "check1")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			data := fmt.Sprintf("ok %d", i)
+			wantStatus := 0
+			if i%2 == 0 {
+				data = fmt.Sprintf("critical %d", i)
+				wantStatus = 2
+			}
+			response, err := adapter.grpcHandler(context.Background(), handler, event, []byte(data))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if got, want := response.Output, "default/sidecar: "+data; got != want {
+				t.Errorf("bad output: got %q, want %q", got, want)
+			}
+			if response.Status != wantStatus {
+				t.Errorf("bad status: got %d, want %d", response.Status, wantStatus)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if _, err := adapter.grpcHandler(context.Background(), handler, event, []byte("fail")); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestLegacyAdapter_grpcHandlerMissingSocket(t *testing.T) {
+	handler := corev2.FixtureHandler("sidecar")
+	handler.Type = HandlerGRPCType
+	adapter := &LegacyAdapter{}
+	event := corev2.FixtureEvent("entity1", "check1")
+	if _, err := adapter.grpcHandler(context.Background(), handler, event, nil); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestLegacyAdapter_grpcHandlerTLS(t *testing.T) {
+	// Borrow the certificate of httptest, which is valid for 127.0.0.1
+	server := httptest.NewUnstartedServer(nil)
+	server.StartTLS()
+	cert := server.TLS.Certificates[0]
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	server.Close()
+	if err := ioutil.WriteFile(caPath, ca, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	host, port := serveTestHandler(t, grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{cert}})))
+	handler := corev2.FixtureHandler("sidecar")
+	handler.Type = HandlerGRPCType
+	handler.Socket = &corev2.HandlerSocket{Host: host, Port: port}
+	handler.Annotations = map[string]string{GRPCTrustedCAFileAnnotation: caPath}
+	event := corev2.FixtureEvent("entity1", "check1")
+
+	adapter := &LegacyAdapter{}
+	response, err := adapter.grpcHandler(context.Background(), handler, event, []byte("critical"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Status != 2 {
+		t.Errorf("bad status: got %d, want 2", response.Status)
+	}
+}
+
+func TestCloseGRPCStreams(t *testing.T) {
+	host, port := serveTestHandler(t)
+	address := net.JoinHostPort(host, fmt.Sprint(port))
+	stream, err := grpcStreams.Get(address, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	CloseGRPCStreams()
+	select {
+	case <-stream.done:
+	default:
+		t.Fatal("expected the stream to be closed")
+	}
+	if _, err := stream.Send(context.Background(), &rpc.HandleRequest{}); err != errGRPCStreamClosed {
+		t.Errorf("bad error: got %v, want %v", err, errGRPCStreamClosed)
+	}
+
+	// The streams are reopened by the next requests
+	reopened, err := grpcStreams.Get(address, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened == stream {
+		t.Error("expected a new stream")
+	}
+}
+
+func TestGRPCHandlerTLSOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantTLS     bool
+		wantErr     bool
+	}{
+		{name: "insecure"},
+		{name: "tls", annotations: map[string]string{GRPCTLSAnnotation: "true"}, wantTLS: true},
+		{name: "trusted ca", annotations: map[string]string{GRPCTrustedCAFileAnnotation: "ca.pem"}, wantTLS: true},
+		{name: "invalid tls", annotations: map[string]string{GRPCTLSAnnotation: "yes please"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := corev2.FixtureHandler("sidecar")
+			handler.Annotations = tt.annotations
+			tlsOpts, err := grpcHandlerTLSOptions(handler)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("grpcHandlerTLSOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (tlsOpts != nil) != tt.wantTLS {
+				t.Errorf("grpcHandlerTLSOptions() = %v, wantTLS %v", tlsOpts, tt.wantTLS)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/dynamic"
+	"github.com/sensu/sensu-go/token"
+	utillogging "github.com/sensu/sensu-go/util/logging"
+)
+
+const (
+	// HandlerHTTPType represents handlers that POST event data to a remote
+	// HTTP endpoint.
+	HandlerHTTPType = "http"
+
+	// HTTPURLAnnotation is the handler annotation that holds the URL that
+	// http handlers post events to.
+	HTTPURLAnnotation = "sensu.io/http/url"
+
+	// HTTPHeaderAnnotationPrefix prefixes the handler annotations that hold
+	// the headers sent by http handlers. The header name follows the prefix,
+	// and the header value may contain event tokens, such as
+	// "{{ .check.name }}".
+	HTTPHeaderAnnotationPrefix = "sensu.io/http/header/"
+
+	// HTTPRetriesAnnotation is the handler annotation that holds the number of
+	// times http handlers retry a failed request.
+	HTTPRetriesAnnotation = "sensu.io/http/retries"
+
+	// HTTPTrustedCAFileAnnotation is the handler annotation that holds the
+	// path to the CA file used to verify the server certificate.
+	HTTPTrustedCAFileAnnotation = "sensu.io/http/trusted-ca-file"
+
+	// HTTPCertFileAnnotation and HTTPKeyFileAnnotation are the handler
+	// annotations that hold the client certificate and key used for mutual
+	// TLS authentication.
+	HTTPCertFileAnnotation = "sensu.io/http/cert-file"
+	HTTPKeyFileAnnotation  = "sensu.io/http/key-file"
+
+	// HTTPInsecureSkipVerifyAnnotation is the handler annotation that
+	// disables the verification of the server certificate when set to
+	// "true".
+	HTTPInsecureSkipVerifyAnnotation = "sensu.io/http/insecure-skip-verify"
+
+	// HTTPIdempotencyKeyHeader is the header that holds the ID of the events
+	// posted by http handlers.
+	HTTPIdempotencyKeyHeader = "Idempotency-Key"
+
+	// DefaultHTTPRetries is the default number of times a failed request is
+	// retried by http handlers.
+	DefaultHTTPRetries = 3
+
+	// maxHTTPResponseBody is the number of response body bytes that are
+	// logged when a request fails.
+	maxHTTPResponseBody = 1024
+)
+
+// httpRetryInterval is the delay before the first retry of a failed request.
+// It doubles with every subsequent retry.
+var httpRetryInterval = time.Second
+
+// httpTransports holds the transports of the http handlers, shared by all the
+// handler executions, so that their connections are reused.
+var httpTransports = newHTTPTransportPool()
+
+// httpHandlerConfig is the configuration of an http handler, as read from the
+// handler annotations.
+type httpHandlerConfig struct {
+	URL     string
+	Headers map[string]string
+	Retries int
+	TLS     *corev2.TLSOptions
+}
+
+func newHTTPHandlerConfig(handler *corev2.Handler) (*httpHandlerConfig, error) {
+	config := &httpHandlerConfig{
+		Headers: make(map[string]string),
+		Retries: DefaultHTTPRetries,
+	}
+	annotations := handler.Annotations
+	config.URL = annotations[HTTPURLAnnotation]
+	if config.URL == "" {
+		return nil, fmt.Errorf("http handlers need the %s annotation", HTTPURLAnnotation)
+	}
+	if u, err := url.Parse(config.URL); err != nil {
+		return nil, fmt.Errorf("invalid http handler url: %s", err)
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid http handler url scheme: %q", u.Scheme)
+	}
+	for key, value := range annotations {
+		if name := strings.TrimPrefix(key, HTTPHeaderAnnotationPrefix); name != key && name != "" {
+			config.Headers[name] = value
+		}
+	}
+	if value, ok := annotations[HTTPRetriesAnnotation]; ok {
+		retries, err := strconv.Atoi(value)
+		if err != nil || retries < 0 {
+			return nil, fmt.Errorf("invalid %s annotation: %q", HTTPRetriesAnnotation, value)
+		}
+		config.Retries = retries
+	}
+	tlsOpts, err := tlsOptionsFromAnnotations(annotations, HTTPTrustedCAFileAnnotation, HTTPCertFileAnnotation, HTTPKeyFileAnnotation, HTTPInsecureSkipVerifyAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	if tlsOpts.TrustedCAFile != "" || tlsOpts.CertFile != "" || tlsOpts.InsecureSkipVerify {
+		config.TLS = tlsOpts
+	}
+	return config, nil
+}
+
+// tlsOptionsFromAnnotations reads TLS options from the given handler
+// annotations.
+func tlsOptionsFromAnnotations(annotations map[string]string, caFile, certFile, keyFile, insecureSkipVerify string) (*corev2.TLSOptions, error) {
+	tlsOpts := &corev2.TLSOptions{
+		TrustedCAFile: annotations[caFile],
+		CertFile:      annotations[certFile],
+		KeyFile:       annotations[keyFile],
+	}
+	if value, ok := annotations[insecureSkipVerify]; ok {
+		insecure, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %q", insecureSkipVerify, value)
+		}
+		tlsOpts.InsecureSkipVerify = insecure
+	}
+	return tlsOpts, nil
+}
+
+// httpHandler posts the mutated data to the URL of a Sensu http handler,
+// retrying with an exponential backoff when the request fails with a network
+// error, a 429 or a 5xx status.
+func (l *LegacyAdapter) httpHandler(ctx context.Context, handler *corev2.Handler, event *corev2.Event, mutatedData []byte) error {
+	// Prepare log entry
+	fields := utillogging.EventFields(event, false)
+	fields["handler_name"] = handler.Name
+	fields["handler_namespace"] = handler.Namespace
+	fields["pipeline"] = corev2.ContextPipeline(ctx)
+	fields["pipeline_workflow"] = corev2.ContextPipelineWorkflow(ctx)
+
+	config, err := newHTTPHandlerConfig(handler)
+	if err != nil {
+		return err
+	}
+	fields["url"] = config.URL
+
+	headers, err := substituteHeaders(config.Headers, event)
+	if err != nil {
+		return err
+	}
+	headers = withIdempotencyKey(headers, event)
+
+	timeout := handler.Timeout
+	if timeout == 0 {
+		timeout = DefaultSocketTimeout
+	}
+	transport, err := httpTransports.Get(config.TLS)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(timeout) * time.Second,
+	}
+
+	interval := httpRetryInterval
+	for attempt := 0; ; attempt++ {
+		retryable, err := postEvent(ctx, client, config.URL, headers, mutatedData)
+		if err == nil {
+			logger.WithFields(fields).Info("event http handler executed")
+			return nil
+		}
+		if !retryable || attempt >= config.Retries {
+			return err
+		}
+		logger.WithFields(fields).WithError(err).Warn("event http handler failed, retrying")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		interval *= 2
+	}
+}
+
+// httpTransportPool creates and caches a transport per TLS configuration.
+type httpTransportPool struct {
+	mu         sync.Mutex
+	transports map[string]*http.Transport
+}
+
+func newHTTPTransportPool() *httpTransportPool {
+	return &httpTransportPool{
+		transports: make(map[string]*http.Transport),
+	}
+}
+
+// Get returns the transport for the given TLS options, if any, creating it if
+// needed.
+func (p *httpTransportPool) Get(tlsOpts *corev2.TLSOptions) (*http.Transport, error) {
+	var key string
+	if tlsOpts != nil {
+		key = fmt.Sprintf("%+v", *tlsOpts)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if transport, ok := p.transports[key]; ok {
+		return transport, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsOpts != nil {
+		tlsConfig, err := tlsOpts.ToClientTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("invalid http handler tls configuration: %s", err)
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	p.transports[key] = transport
+	return transport, nil
+}
+
+// substituteHeaders replaces the event tokens found in the header values.
+func substituteHeaders(headers map[string]string, event *corev2.Event) (map[string]string, error) {
+	if len(headers) == 0 {
+		return headers, nil
+	}
+	b, err := token.Substitution(dynamic.Synthesize(event), headers)
+	if err != nil {
+		return nil, fmt.Errorf("could not substitute http handler headers: %s", err)
+	}
+	result := make(map[string]string, len(headers))
+	if err := json.Unmarshal(b, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// withIdempotencyKey adds the Idempotency-Key header of the event to the
+// headers, unless they already have one, so that the endpoint can recognize
+// the events delivered more than once.
+func withIdempotencyKey(headers map[string]string, event *corev2.Event) map[string]string {
+	key := messaging.IdempotencyKey(event)
+	if key == "" {
+		return headers
+	}
+	result := map[string]string{HTTPIdempotencyKeyHeader: key}
+	for name, value := range headers {
+		if http.CanonicalHeaderKey(name) == HTTPIdempotencyKeyHeader {
+			delete(result, HTTPIdempotencyKeyHeader)
+		}
+		result[name] = value
+	}
+	return result
+}
+
+// postEvent performs a single POST request. It returns whether the request
+// can be retried when it fails.
+func postEvent(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxHTTPResponseBody))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("http handler request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+)
+
+func fixtureHTTPHandler(url string) *corev2.Handler {
+	handler := corev2.FixtureHandler("webhook")
+	handler.Type = HandlerHTTPType
+	handler.Annotations = map[string]string{
+		HTTPURLAnnotation:                      url,
+		HTTPHeaderAnnotationPrefix + "X-Check": "{{ .check.name }}",
+		HTTPRetriesAnnotation:                  "2",
+	}
+	return handler
+}
+
+func TestLegacyAdapter_httpHandler(t *testing.T) {
+	httpRetryInterval = time.Millisecond
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if got, want := r.Header.Get("X-Check"), "check1"; got != want {
+			t.Errorf("bad header: got %q, want %q", got, want)
+		}
+		if got, want := r.Header.Get("Content-Type"), "application/json"; got != want {
+			t.Errorf("bad content type: got %q, want %q", got, want)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"foo":"bar"}`; got != want {
+			t.Errorf("bad body: got %q, want %q", got, want)
+		}
+	}))
+	defer server.Close()
+
+	adapter := &LegacyAdapter{}
+	event := corev2.FixtureEvent("entity1", "check1")
+	err := adapter.httpHandler(context.Background(), fixtureHTTPHandler(server.URL), event, []byte(`{"foo":"bar"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := atomic.LoadInt32(&requests), int32(2); got != want {
+		t.Errorf("bad number of requests: got %d, want %d", got, want)
+	}
+}
+
+func TestLegacyAdapter_httpHandlerErrors(t *testing.T) {
+	httpRetryInterval = time.Millisecond
+	tests := []struct {
+		Name         string
+		Status       int
+		WantRequests int32
+	}{
+		{Name: "client error is not retried", Status: http.StatusBadRequest, WantRequests: 1},
+		{Name: "server error is retried", Status: http.StatusInternalServerError, WantRequests: 3},
+		{Name: "too many requests is retried", Status: http.StatusTooManyRequests, WantRequests: 3},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.WriteHeader(test.Status)
+			}))
+			defer server.Close()
+
+			adapter := &LegacyAdapter{}
+			event := corev2.FixtureEvent("entity1", "check1")
+			if err := adapter.httpHandler(context.Background(), fixtureHTTPHandler(server.URL), event, nil); err == nil {
+				t.Fatal("expected non-nil error")
+			}
+			if got := atomic.LoadInt32(&requests); got != test.WantRequests {
+				t.Errorf("bad number of requests: got %d, want %d", got, test.WantRequests)
+			}
+		})
+	}
+}
+
+func TestNewHTTPHandlerConfig(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Annotations map[string]string
+		WantErr     bool
+	}{
+		{Name: "missing url", WantErr: true},
+		{Name: "bad scheme", Annotations: map[string]string{HTTPURLAnnotation: "ftp://example.com"}, WantErr: true},
+		{Name: "bad retries", Annotations: map[string]string{HTTPURLAnnotation: "https://example.com", HTTPRetriesAnnotation: "-1"}, WantErr: true},
+		{Name: "bad insecure", Annotations: map[string]string{HTTPURLAnnotation: "https://example.com", HTTPInsecureSkipVerifyAnnotation: "maybe"}, WantErr: true},
+		{Name: "valid", Annotations: map[string]string{HTTPURLAnnotation: "https://example.com", HTTPInsecureSkipVerifyAnnotation: "true"}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			handler := corev2.FixtureHandler("webhook")
+			handler.Annotations = test.Annotations
+			_, err := newHTTPHandlerConfig(handler)
+			if (err != nil) != test.WantErr {
+				t.Errorf("bad error: %v", err)
+			}
+		})
+	}
+}
+
+func TestWithIdempotencyKey(t *testing.T) {
+	event := corev2.FixtureEvent("entity1", "check1")
+	event.ID = []byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+
+	headers := withIdempotencyKey(map[string]string{"X-Check": "check1"}, event)
+	if got, want := headers[HTTPIdempotencyKeyHeader], "6ba7b810-9dad-11d1-80b4-00c04fd430c8"; got != want {
+		t.Errorf("bad idempotency key: got %q, want %q", got, want)
+	}
+
+	// The headers of the handler take precedence
+	headers = withIdempotencyKey(map[string]string{"idempotency-key": "custom"}, event)
+	if len(headers) != 1 || headers["idempotency-key"] != "custom" {
+		t.Errorf("bad headers: %v", headers)
+	}
+
+	// The events without ID have no idempotency key
+	event.ID = nil
+	headers = withIdempotencyKey(nil, event)
+	if len(headers) != 0 {
+		t.Errorf("bad headers: %v", headers)
+	}
+}
+
+func TestHTTPTransportPool(t *testing.T) {
+	pool := newHTTPTransportPool()
+	plain, err := pool.Get(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := pool.Get(nil); again != plain {
+		t.Error("transport without tls should be reused")
+	}
+	insecure, err := pool.Get(&corev2.TLSOptions{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if insecure == plain {
+		t.Error("transports with different tls configurations should differ")
+	}
+	if again, _ := pool.Get(&corev2.TLSOptions{InsecureSkipVerify: true}); again != insecure {
+		t.Error("transport with the same tls configuration should be reused")
+	}
+	if _, err := pool.Get(&corev2.TLSOptions{TrustedCAFile: "/nonexistent"}); err == nil {
+		t.Error("expected an error for an invalid tls configuration")
+	}
+}
@@ -37,6 +37,10 @@ type LegacyAdapter struct {
 	SecretsProviderManager secrets.ProviderManagerer
 	Store                  storev2.Interface
 	StoreTimeout           time.Duration
+
+	// RecordResults enables the recording of the results of the handler
+	// executions in the store.
+	RecordResults bool
 }
 
 // Name returns the name of the handler adapter.
@@ -53,8 +57,8 @@ func (l *LegacyAdapter) CanHandle(ref *corev2.ResourceReference) bool {
 	return false
 }
 
-// Handle handles a Sensu event. It will pass any mutated data along to pipe or
-// tcp/udp handlers.
+// Handle handles a Sensu event. It will pass any mutated data along to pipe,
+// tcp/udp, http or grpc handlers, or send it to PagerDuty.
 func (l *LegacyAdapter) Handle(ctx context.Context, ref *corev2.ResourceReference, event *corev2.Event, mutatedData []byte) error {
 	// Prepare log entry
 	fields := utillogging.EventFields(event, false)
@@ -75,18 +79,60 @@ func (l *LegacyAdapter) Handle(ctx context.Context, ref *corev2.ResourceReferenc
 		return fmt.Errorf("failed to fetch handler from store: %v", err)
 	}
 
+	interval, err := digestInterval(handler)
+	if err != nil {
+		logger.WithFields(fields).WithError(err).Error("invalid handler digest configuration")
+		return err
+	}
+	if interval > 0 {
+		digests.Add(l, handler, event, mutatedData, interval)
+		logger.WithFields(fields).Debug("event added to handler digest")
+		return nil
+	}
+
+	return l.execute(ctx, handler, event, mutatedData, fields)
+}
+
+// execute executes the handler with the mutated data, and records the result
+// of the execution if RecordResults is set.
+func (l *LegacyAdapter) execute(ctx context.Context, handler *corev2.Handler, event *corev2.Event, mutatedData []byte, fields map[string]interface{}) error {
+	result := &storev2.HandlerResult{
+		Namespace: event.Entity.Namespace,
+		Entity:    event.Entity.Name,
+		Handler:   handler.Name,
+		Executed:  time.Now().Unix(),
+	}
+	// The results are only recorded for the events of checks, which are
+	// stored, and delete their results when they are deleted
+	if l.RecordResults && event.HasCheck() {
+		result.Check = event.Check.Name
+		start := time.Now()
+		defer func() {
+			result.Duration = time.Since(start).Seconds()
+			l.recordResult(ctx, result, fields)
+		}()
+	}
+
 	switch handler.Type {
 	case "pipe":
-		result, err := l.pipeHandler(ctx, handler, event, mutatedData)
+		response, err := l.pipeHandler(ctx, handler, event, mutatedData)
 		if err != nil {
 			logger.WithFields(fields).
 				WithError(err).
 				Error("failed to execute event pipe handler")
+			result.Status = 1
+			result.Error = err.Error()
 			return err
 		}
-		fields["status"] = result.Status
-		fields["output"] = result.Output
-		if result.Status == 0 {
+		if response.Truncated {
+			truncatedOutputsCounter.WithLabelValues(handler.Type).Inc()
+			fields["output_truncated"] = true
+		}
+		fields["status"] = response.Status
+		fields["output"] = response.Output
+		result.Status = int32(response.Status)
+		result.Output = response.Output
+		if response.Status == 0 {
 			logger.WithFields(fields).Info("event pipe handler executed")
 		} else {
 			logger.WithFields(fields).Error("event pipe handler returned non ok status code")
@@ -95,15 +141,67 @@ func (l *LegacyAdapter) Handle(ctx context.Context, ref *corev2.ResourceReferenc
 		err := l.socketHandler(ctx, handler, event, mutatedData)
 		if err != nil {
 			logger.WithFields(fields).Error(err)
+			result.Status = 1
+			result.Error = err.Error()
+			return err
+		}
+	case HandlerHTTPType:
+		err := l.httpHandler(ctx, handler, event, mutatedData)
+		if err != nil {
+			logger.WithFields(fields).WithError(err).Error("failed to execute event http handler")
+			result.Status = 1
+			result.Error = err.Error()
+			return err
+		}
+	case HandlerGRPCType:
+		response, err := l.grpcHandler(ctx, handler, event, mutatedData)
+		if err != nil {
+			logger.WithFields(fields).WithError(err).Error("failed to execute event grpc handler")
+			result.Status = 1
+			result.Error = err.Error()
+			return err
+		}
+		if response.Truncated {
+			truncatedOutputsCounter.WithLabelValues(handler.Type).Inc()
+			fields["output_truncated"] = true
+		}
+		fields["status"] = response.Status
+		fields["output"] = response.Output
+		result.Status = int32(response.Status)
+		result.Output = response.Output
+		if response.Status == 0 {
+			logger.WithFields(fields).Info("event grpc handler executed")
+		} else {
+			logger.WithFields(fields).Error("event grpc handler returned non ok status code")
+		}
+	case HandlerPagerDutyType:
+		err := l.pagerDutyHandler(ctx, handler, event)
+		if err != nil {
+			logger.WithFields(fields).WithError(err).Error("failed to execute event pagerduty handler")
+			result.Status = 1
+			result.Error = err.Error()
 			return err
 		}
 	default:
-		return errors.New("unknown handler type")
+		err := errors.New("unknown handler type")
+		result.Status = 1
+		result.Error = err.Error()
+		return err
 	}
 
 	return nil
 }
 
+// recordResult stores the result of a handler execution, so that it can be
+// inspected through the API. Failing to store the result is not fatal.
+func (l *LegacyAdapter) recordResult(ctx context.Context, result *storev2.HandlerResult, fields map[string]interface{}) {
+	tctx, cancel := context.WithTimeout(ctx, l.StoreTimeout)
+	defer cancel()
+	if err := l.Store.GetHandlerResultStore().AddHandlerResult(tctx, result); err != nil {
+		logger.WithFields(fields).WithError(err).Warn("failed to store handler result")
+	}
+}
+
 // pipeHandler fork/executes a child process for a Sensu pipe handler command
 // and writes the mutated data to it via STDIN.
 func (l *LegacyAdapter) pipeHandler(ctx context.Context, handler *corev2.Handler, event *corev2.Event, mutatedData []byte) (*command.ExecutionResponse, error) {
@@ -135,7 +233,13 @@ func (l *LegacyAdapter) pipeHandler(ctx context.Context, handler *corev2.Handler
 	// Prepare environment variables
 	env := environment.MergeEnvironments(os.Environ(), handler.EnvVars, secrets)
 
+	maxOutput, err := maxOutputSize(handler)
+	if err != nil {
+		return nil, err
+	}
+
 	handlerExec := command.ExecutionRequest{}
+	handlerExec.MaxOutputSize = maxOutput
 	handlerExec.Command = handler.Command
 	handlerExec.Timeout = int(handler.Timeout)
 	handlerExec.Env = env
@@ -169,44 +273,48 @@ func (l *LegacyAdapter) pipeHandler(ctx context.Context, handler *corev2.Handler
 }
 
 // socketHandler creates either a TCP or UDP client to write mutatedData
-// to a socket. The provided handler Type determines the protocol.
+// to a socket. The provided handler Type determines the protocol. TCP
+// connections may use TLS, and connections may be pooled, according to the
+// handler annotations.
 func (l *LegacyAdapter) socketHandler(ctx context.Context, handler *corev2.Handler, event *corev2.Event, mutatedData []byte) (err error) {
-	protocol := handler.Type
-	host := handler.Socket.Host
-	port := handler.Socket.Port
-	timeout := handler.Timeout
-
 	// Prepare log entry
 	fields := utillogging.EventFields(event, false)
 	fields["handler_name"] = handler.Name
 	fields["handler_namespace"] = handler.Namespace
-	fields["handler_protocol"] = protocol
+	fields["handler_protocol"] = handler.Type
 	fields["pipeline"] = corev2.ContextPipeline(ctx)
 	fields["pipeline_workflow"] = corev2.ContextPipelineWorkflow(ctx)
 
-	// If Timeout is not specified, use the default.
-	if timeout == 0 {
-		timeout = DefaultSocketTimeout
+	config, err := newSocketHandlerConfig(handler)
+	if err != nil {
+		return err
 	}
 
-	address := net.JoinHostPort(host, fmt.Sprint(port))
-	timeoutDuration := time.Duration(timeout) * time.Second
-
 	logger.WithFields(fields).Debug("sending event to socket handler")
 
-	deadline := time.Now().Add(timeoutDuration)
-	conn, cerr := net.DialTimeout(protocol, address, timeoutDuration)
-	if cerr != nil {
-		return cerr
+	var conn net.Conn
+	key := config.poolKey(handler)
+	if config.Pool {
+		conn = socketConns.Get(key)
+	}
+	if conn == nil {
+		conn, err = config.dial()
+		if err != nil {
+			return err
+		}
 	}
 	defer func() {
+		if config.Pool && err == nil {
+			socketConns.Put(key, conn)
+			return
+		}
 		e := conn.Close()
 		if err == nil {
 			err = e
 		}
 	}()
 
-	if err := conn.SetWriteDeadline(deadline); err != nil {
+	if err := conn.SetWriteDeadline(time.Now().Add(config.WriteTimeout)); err != nil {
 		return err
 	}
 
@@ -139,6 +139,7 @@ func TestLegacyAdapter_Handle(t *testing.T) {
 		SecretsProviderManager secrets.ProviderManagerer
 		Store                  storev2.Interface
 		StoreTimeout           time.Duration
+		RecordResults          bool
 	}
 	type args struct {
 		ctx         context.Context
@@ -192,6 +193,7 @@ func TestLegacyAdapter_Handle(t *testing.T) {
 				}(),
 			},
 			fields: fields{
+				RecordResults: true,
 				SecretsProviderManager: func() secrets.ProviderManagerer {
 					var secrets []string
 					manager := &mocksecrets.ProviderManager{}
@@ -204,12 +206,73 @@ func TestLegacyAdapter_Handle(t *testing.T) {
 					cs := new(mockstore.ConfigStore)
 					stor.On("GetConfigStore").Return(cs)
 					cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*corev2.Handler]{Value: handler}, nil)
+					hrs := new(mockstore.HandlerResultStore)
+					stor.On("GetHandlerResultStore").Return(hrs)
+					hrs.On("AddHandlerResult", mock.Anything, mock.MatchedBy(func(result *storev2.HandlerResult) bool {
+						return result.Handler == "handler1" && result.Check == "check1" && result.Status == 1 && result.Error == "secrets error"
+					})).Return(nil)
 					return stor
 				}(),
 			},
 			wantErr:    true,
 			wantErrMsg: "secrets error",
 		},
+		{
+			name: "records the result of a pipe handler",
+			args: args{
+				ctx: context.Background(),
+				ref: &corev2.ResourceReference{
+					Name: "handler1",
+				},
+				event: corev2.FixtureEvent("entity1", "check1"),
+			},
+			fields: fields{
+				RecordResults: true,
+				Executor: func() command.Executor {
+					executor := &mockexecutor.MockExecutor{}
+					executor.Return(&command.ExecutionResponse{Status: 2, Output: "oops"}, nil)
+					return executor
+				}(),
+				Store: func() storev2.Interface {
+					handler := corev2.FixtureHandler("handler1")
+					stor := &mockstore.V2MockStore{}
+					cs := new(mockstore.ConfigStore)
+					stor.On("GetConfigStore").Return(cs)
+					cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*corev2.Handler]{Value: handler}, nil)
+					hrs := new(mockstore.HandlerResultStore)
+					stor.On("GetHandlerResultStore").Return(hrs)
+					hrs.On("AddHandlerResult", mock.Anything, mock.MatchedBy(func(result *storev2.HandlerResult) bool {
+						return result.Entity == "entity1" && result.Status == 2 && result.Output == "oops"
+					})).Return(errors.New("store error"))
+					return stor
+				}(),
+			},
+		},
+		{
+			name: "does not record the results unless enabled",
+			args: args{
+				ctx: context.Background(),
+				ref: &corev2.ResourceReference{
+					Name: "handler1",
+				},
+				event: corev2.FixtureEvent("entity1", "check1"),
+			},
+			fields: fields{
+				Executor: func() command.Executor {
+					executor := &mockexecutor.MockExecutor{}
+					executor.Return(&command.ExecutionResponse{Status: 0, Output: "ok"}, nil)
+					return executor
+				}(),
+				Store: func() storev2.Interface {
+					handler := corev2.FixtureHandler("handler1")
+					stor := &mockstore.V2MockStore{}
+					cs := new(mockstore.ConfigStore)
+					stor.On("GetConfigStore").Return(cs)
+					cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*corev2.Handler]{Value: handler}, nil)
+					return stor
+				}(),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -220,6 +283,7 @@ func TestLegacyAdapter_Handle(t *testing.T) {
 				SecretsProviderManager: tt.fields.SecretsProviderManager,
 				Store:                  tt.fields.Store,
 				StoreTimeout:           tt.fields.StoreTimeout,
+				RecordResults:          tt.fields.RecordResults,
 			}
 			err := l.Handle(tt.args.ctx, tt.args.ref, tt.args.event, tt.args.mutatedData)
 			if (err != nil) != tt.wantErr {
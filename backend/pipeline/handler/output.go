@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/command"
+)
+
+const (
+	// MaxOutputSizeAnnotation is the handler annotation that holds the
+	// maximum number of bytes of handler output captured by pipelined, or 0
+	// for no limit. Output beyond the limit is discarded, and the captured
+	// output ends with command.TruncatedOutputMarker.
+	MaxOutputSizeAnnotation = "sensu.io/max-output-size"
+
+	// DefaultMaxOutputSize is the maximum number of bytes of handler output
+	// captured when the handler does not set MaxOutputSizeAnnotation.
+	DefaultMaxOutputSize = 1 << 20
+
+	// TruncatedOutputsCounterName is the name of the prometheus counter of
+	// handler executions whose output was truncated.
+	TruncatedOutputsCounterName = "sensu_go_handler_truncated_outputs_total"
+)
+
+var truncatedOutputsCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: TruncatedOutputsCounterName,
+		Help: "The total number of handler executions whose output was truncated",
+	},
+	[]string{"handler_type"},
+)
+
+func init() {
+	if err := prometheus.Register(truncatedOutputsCounter); err != nil {
+		panic(fmt.Errorf("error registering %s: %s", TruncatedOutputsCounterName, err))
+	}
+}
+
+// maxOutputSize returns the maximum number of bytes of output captured for
+// the handler.
+func maxOutputSize(handler *corev2.Handler) (int, error) {
+	value, ok := handler.Annotations[MaxOutputSizeAnnotation]
+	if !ok {
+		return DefaultMaxOutputSize, nil
+	}
+	size, err := strconv.Atoi(value)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("invalid %s annotation: %q", MaxOutputSizeAnnotation, value)
+	}
+	return size, nil
+}
+
+// truncateOutput truncates the output to max bytes, followed by the
+// truncation marker. It returns whether the output was truncated.
+func truncateOutput(output string, max int) (string, bool) {
+	if max <= 0 || len(output) <= max {
+		return output, false
+	}
+	return output[:max] + command.TruncatedOutputMarker, true
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/command"
+)
+
+func TestMaxOutputSize(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        int
+		wantErr     bool
+	}{
+		{name: "default", want: DefaultMaxOutputSize},
+		{name: "custom", annotations: map[string]string{MaxOutputSizeAnnotation: "1024"}, want: 1024},
+		{name: "unlimited", annotations: map[string]string{MaxOutputSizeAnnotation: "0"}, want: 0},
+		{name: "negative", annotations: map[string]string{MaxOutputSizeAnnotation: "-1"}, wantErr: true},
+		{name: "not a number", annotations: map[string]string{MaxOutputSizeAnnotation: "1MB"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := corev2.FixtureHandler("handler")
+			handler.Annotations = tt.annotations
+			got, err := maxOutputSize(handler)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("maxOutputSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("maxOutputSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateOutput(t *testing.T) {
+	output, truncated := truncateOutput("0123456789", 4)
+	if got, want := output, "0123"+command.TruncatedOutputMarker; got != want {
+		t.Errorf("bad output: got %q, want %q", got, want)
+	}
+	if !truncated {
+		t.Error("expected output to be truncated")
+	}
+	for _, max := range []int{0, 10} {
+		output, truncated = truncateOutput("0123456789", max)
+		if output != "0123456789" || truncated {
+			t.Errorf("unexpected truncation to %d bytes: %q", max, output)
+		}
+	}
+}
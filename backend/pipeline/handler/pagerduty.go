@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	corev2 "github.com/sensu/core/v2"
+	utillogging "github.com/sensu/sensu-go/util/logging"
+)
+
+const (
+	// HandlerPagerDutyType represents handlers that send events to the
+	// PagerDuty Events API v2, without the need for an external command.
+	HandlerPagerDutyType = "pagerduty"
+
+	// PagerDutyRoutingKeySecret is the name of the handler secret that holds
+	// the routing key (integration key) of the PagerDuty service.
+	PagerDutyRoutingKeySecret = "PAGERDUTY_ROUTING_KEY"
+
+	// PagerDutyURLAnnotation is the handler annotation that overrides the URL
+	// of the PagerDuty Events API, e.g. to go through a proxy.
+	PagerDutyURLAnnotation = "sensu.io/pagerduty/url"
+
+	// DefaultPagerDutyURL is the URL of the PagerDuty Events API v2.
+	DefaultPagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+
+	// maxPagerDutySummary is the maximum length of the summary accepted by
+	// PagerDuty.
+	maxPagerDutySummary = 1024
+)
+
+// pagerDutyEvent is a PagerDuty Events API v2 event.
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Timestamp     string                 `json:"timestamp,omitempty"`
+	Component     string                 `json:"component,omitempty"`
+	Group         string                 `json:"group,omitempty"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+// pagerDutyDedupKey returns the deduplication key of the event, so that all
+// the events of an entity and check are grouped in a single incident.
+func pagerDutyDedupKey(event *corev2.Event) string {
+	return path.Join(event.Entity.Namespace, event.Entity.Name, event.Check.Name)
+}
+
+// pagerDutySeverity maps a check status to a PagerDuty severity.
+func pagerDutySeverity(status uint32) string {
+	switch status {
+	case 0:
+		return "info"
+	case 1:
+		return "warning"
+	case 2:
+		return "critical"
+	default:
+		return "error"
+	}
+}
+
+// newPagerDutyEvent creates the PagerDuty event of a Sensu event. Failing
+// checks trigger an incident, and passing checks resolve it.
+func newPagerDutyEvent(routingKey string, event *corev2.Event) *pagerDutyEvent {
+	pdEvent := &pagerDutyEvent{
+		RoutingKey:  routingKey,
+		EventAction: "trigger",
+		DedupKey:    pagerDutyDedupKey(event),
+	}
+	if event.Check.Status == 0 {
+		pdEvent.EventAction = "resolve"
+		return pdEvent
+	}
+	summary := fmt.Sprintf("%s/%s: %s", event.Entity.Name, event.Check.Name, strings.TrimSpace(event.Check.Output))
+	if len(summary) > maxPagerDutySummary {
+		// Truncate on a rune boundary, so that the summary stays valid UTF-8
+		n := maxPagerDutySummary
+		for n > 0 && !utf8.RuneStart(summary[n]) {
+			n--
+		}
+		summary = summary[:n]
+	}
+	pdEvent.Payload = &pagerDutyPayload{
+		Summary:   summary,
+		Source:    event.Entity.Name,
+		Severity:  pagerDutySeverity(event.Check.Status),
+		Timestamp: time.Unix(event.Timestamp, 0).UTC().Format(time.RFC3339),
+		Component: event.Check.Name,
+		Group:     event.Entity.Namespace,
+		CustomDetails: map[string]interface{}{
+			"status":      event.Check.Status,
+			"output":      event.Check.Output,
+			"occurrences": event.Check.Occurrences,
+			"labels":      event.Check.Labels,
+		},
+	}
+	return pdEvent
+}
+
+// pagerDutyHandler sends the event to the PagerDuty Events API v2, with the
+// routing key found in the handler secrets.
+func (l *LegacyAdapter) pagerDutyHandler(ctx context.Context, handler *corev2.Handler, event *corev2.Event) error {
+	ctx = corev2.SetContextFromResource(ctx, handler)
+
+	// Prepare log entry
+	fields := utillogging.EventFields(event, false)
+	fields["handler_name"] = handler.Name
+	fields["handler_namespace"] = handler.Namespace
+	fields["pipeline"] = corev2.ContextPipeline(ctx)
+	fields["pipeline_workflow"] = corev2.ContextPipelineWorkflow(ctx)
+
+	if !event.HasCheck() {
+		return errors.New("pagerduty handlers can only handle check events")
+	}
+
+	if l.SecretsProviderManager == nil {
+		return errors.New("pagerduty handlers need secrets support for the routing key")
+	}
+	secrets, err := l.SecretsProviderManager.SubSecrets(ctx, handler.Secrets)
+	if err != nil {
+		logger.WithFields(fields).WithError(err).Error("failed to retrieve secrets for handler")
+		return err
+	}
+	var routingKey string
+	for _, secret := range secrets {
+		if value := strings.TrimPrefix(secret, PagerDutyRoutingKeySecret+"="); value != secret {
+			routingKey = value
+		}
+	}
+	if routingKey == "" {
+		return fmt.Errorf("pagerduty handlers need the %s secret", PagerDutyRoutingKeySecret)
+	}
+
+	url := DefaultPagerDutyURL
+	if value, ok := handler.Annotations[PagerDutyURLAnnotation]; ok && value != "" {
+		url = value
+	}
+
+	pdEvent := newPagerDutyEvent(routingKey, event)
+	fields["dedup_key"] = pdEvent.DedupKey
+	fields["event_action"] = pdEvent.EventAction
+	body, err := json.Marshal(pdEvent)
+	if err != nil {
+		return err
+	}
+
+	timeout := handler.Timeout
+	if timeout == 0 {
+		timeout = DefaultSocketTimeout
+	}
+	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
+
+	interval := httpRetryInterval
+	for attempt := 0; ; attempt++ {
+		retryable, err := postEvent(ctx, client, url, nil, body)
+		if err == nil {
+			logger.WithFields(fields).Info("event pagerduty handler executed")
+			return nil
+		}
+		if !retryable || attempt >= DefaultHTTPRetries {
+			return err
+		}
+		logger.WithFields(fields).WithError(err).Warn("event pagerduty handler failed, retrying")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		interval *= 2
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/testing/mocksecrets"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewPagerDutyEvent(t *testing.T) {
+	event := corev2.FixtureEvent("entity1", "check1")
+	event.Check.Status = 2
+	event.Check.Output = "disk full\n"
+
+	pdEvent := newPagerDutyEvent("key", event)
+	if got, want := pdEvent.EventAction, "trigger"; got != want {
+		t.Errorf("bad event action: got %q, want %q", got, want)
+	}
+	if got, want := pdEvent.DedupKey, "default/entity1/check1"; got != want {
+		t.Errorf("bad dedup key: got %q, want %q", got, want)
+	}
+	if got, want := pdEvent.Payload.Summary, "entity1/check1: disk full"; got != want {
+		t.Errorf("bad summary: got %q, want %q", got, want)
+	}
+	if got, want := pdEvent.Payload.Severity, "critical"; got != want {
+		t.Errorf("bad severity: got %q, want %q", got, want)
+	}
+
+	// The summary is truncated on a rune boundary
+	event.Check.Output = "!" + strings.Repeat("é", maxPagerDutySummary)
+	pdEvent = newPagerDutyEvent("key", event)
+	if got := pdEvent.Payload.Summary; len(got) > maxPagerDutySummary || !utf8.ValidString(got) {
+		t.Errorf("bad truncated summary: %d bytes, valid UTF-8: %v", len(got), utf8.ValidString(got))
+	}
+
+	event.Check.Status = 0
+	pdEvent = newPagerDutyEvent("key", event)
+	if got, want := pdEvent.EventAction, "resolve"; got != want {
+		t.Errorf("bad event action: got %q, want %q", got, want)
+	}
+	if pdEvent.Payload != nil {
+		t.Error("resolve events must not have a payload")
+	}
+}
+
+func TestLegacyAdapter_pagerDutyHandler(t *testing.T) {
+	var received pagerDutyEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		secrets []string
+		wantErr bool
+	}{
+		{
+			name:    "routing key from secrets",
+			secrets: []string{"OTHER=foo", PagerDutyRoutingKeySecret + "=routingkey"},
+		},
+		{
+			name:    "missing routing key",
+			secrets: []string{"OTHER=foo"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = pagerDutyEvent{}
+			manager := &mocksecrets.ProviderManager{}
+			manager.On("SubSecrets", mock.Anything, mock.Anything).Return(tt.secrets, nil)
+			adapter := &LegacyAdapter{SecretsProviderManager: manager}
+
+			handler := corev2.FixtureHandler("pagerduty")
+			handler.Type = HandlerPagerDutyType
+			handler.Annotations = map[string]string{PagerDutyURLAnnotation: server.URL}
+			event := corev2.FixtureEvent("entity1", "check1")
+			event.Check.Status = 1
+
+			err := adapter.pagerDutyHandler(context.Background(), handler, event)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pagerDutyHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got, want := received.RoutingKey, "routingkey"; got != want {
+				t.Errorf("bad routing key: got %q, want %q", got, want)
+			}
+			if got, want := received.Payload.Severity, "warning"; got != want {
+				t.Errorf("bad severity: got %q, want %q", got, want)
+			}
+		})
+	}
+}
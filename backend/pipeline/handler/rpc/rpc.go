@@ -0,0 +1,5 @@
+// Package rpc contains the protobuf contract between pipelined and the gRPC
+// sidecar services used by grpc handlers.
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative handler.proto
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+)
+
+const (
+	// SocketPoolAnnotation is the tcp/udp handler annotation that, when set to
+	// "true", keeps connections open between events so that they can be
+	// reused. Receivers must then accept several events per connection.
+	SocketPoolAnnotation = "sensu.io/socket/pool"
+
+	// SocketTLSAnnotation is the tcp handler annotation that enables TLS when
+	// set to "true". TLS is also enabled by any of the other TLS annotations.
+	SocketTLSAnnotation = "sensu.io/socket/tls"
+
+	// SocketTrustedCAFileAnnotation is the tcp handler annotation that holds
+	// the path to the CA file used to verify the server certificate.
+	SocketTrustedCAFileAnnotation = "sensu.io/socket/trusted-ca-file"
+
+	// SocketCertFileAnnotation and SocketKeyFileAnnotation are the tcp handler
+	// annotations that hold the client certificate and key used for mutual
+	// TLS authentication.
+	SocketCertFileAnnotation = "sensu.io/socket/cert-file"
+	SocketKeyFileAnnotation  = "sensu.io/socket/key-file"
+
+	// SocketInsecureSkipVerifyAnnotation is the tcp handler annotation that
+	// disables the verification of the server certificate when set to
+	// "true".
+	SocketInsecureSkipVerifyAnnotation = "sensu.io/socket/insecure-skip-verify"
+
+	// SocketWriteTimeoutAnnotation is the tcp/udp handler annotation that
+	// holds the write timeout, in seconds. It defaults to the handler
+	// timeout.
+	SocketWriteTimeoutAnnotation = "sensu.io/socket/write-timeout"
+
+	// maxIdleSocketConns is the number of idle connections kept per handler.
+	maxIdleSocketConns = 4
+)
+
+// socketIdleTimeout is the time after which idle connections are closed
+// rather than reused, since receivers are likely to have closed them.
+var socketIdleTimeout = time.Minute
+
+// socketConns holds the idle connections of the tcp/udp handlers that pool
+// their connections.
+var socketConns = newSocketPool()
+
+// socketHandlerConfig is the configuration of a tcp/udp handler, as read from
+// the handler and its annotations.
+type socketHandlerConfig struct {
+	Protocol     string
+	Address      string
+	Pool         bool
+	TLS          *tls.Config
+	DialTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+func newSocketHandlerConfig(handler *corev2.Handler) (*socketHandlerConfig, error) {
+	if handler.Socket == nil {
+		return nil, fmt.Errorf("%s handlers need a socket", handler.Type)
+	}
+	// If Timeout is not specified, use the default.
+	timeout := handler.Timeout
+	if timeout == 0 {
+		timeout = DefaultSocketTimeout
+	}
+	config := &socketHandlerConfig{
+		Protocol:     handler.Type,
+		Address:      net.JoinHostPort(handler.Socket.Host, fmt.Sprint(handler.Socket.Port)),
+		DialTimeout:  time.Duration(timeout) * time.Second,
+		WriteTimeout: time.Duration(timeout) * time.Second,
+	}
+	annotations := handler.Annotations
+	var err error
+	if config.Pool, err = boolAnnotation(annotations, SocketPoolAnnotation); err != nil {
+		return nil, err
+	}
+	if value, ok := annotations[SocketWriteTimeoutAnnotation]; ok {
+		seconds, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || seconds == 0 {
+			return nil, fmt.Errorf("invalid %s annotation: %q", SocketWriteTimeoutAnnotation, value)
+		}
+		config.WriteTimeout = time.Duration(seconds) * time.Second
+	}
+	enableTLS, err := boolAnnotation(annotations, SocketTLSAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	tlsOpts, err := tlsOptionsFromAnnotations(annotations, SocketTrustedCAFileAnnotation, SocketCertFileAnnotation, SocketKeyFileAnnotation, SocketInsecureSkipVerifyAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	if enableTLS || tlsOpts.TrustedCAFile != "" || tlsOpts.CertFile != "" || tlsOpts.InsecureSkipVerify {
+		if config.Protocol != "tcp" {
+			return nil, fmt.Errorf("TLS is not supported by %s handlers", config.Protocol)
+		}
+		config.TLS, err = tlsOpts.ToClientTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("invalid socket handler tls configuration: %s", err)
+		}
+	}
+	return config, nil
+}
+
+func boolAnnotation(annotations map[string]string, key string) (bool, error) {
+	value, ok := annotations[key]
+	if !ok {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s annotation: %q", key, value)
+	}
+	return b, nil
+}
+
+// poolKey identifies the connections that can be shared by the executions of
+// a handler. It changes with the handler configuration.
+func (c *socketHandlerConfig) poolKey(handler *corev2.Handler) string {
+	return fmt.Sprintf("%s/%s/%s://%s/%v", handler.Namespace, handler.Name, c.Protocol, c.Address, handler.Annotations)
+}
+
+// dial opens a connection to the socket of the handler.
+func (c *socketHandlerConfig) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: c.DialTimeout}
+	if c.TLS != nil {
+		return tls.DialWithDialer(dialer, c.Protocol, c.Address, c.TLS)
+	}
+	return dialer.Dial(c.Protocol, c.Address)
+}
+
+type idleSocketConn struct {
+	net.Conn
+	since time.Time
+}
+
+// socketPool keeps idle handler connections for reuse. A connection is only
+// used by one handler execution at a time.
+type socketPool struct {
+	mu   sync.Mutex
+	idle map[string][]idleSocketConn
+}
+
+func newSocketPool() *socketPool {
+	return &socketPool{
+		idle: make(map[string][]idleSocketConn),
+	}
+}
+
+// Get returns an idle connection, or nil if there is none.
+func (p *socketPool) Get(key string) net.Conn {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	conns := p.idle[key]
+	for len(conns) > 0 {
+		conn := conns[len(conns)-1]
+		conns = conns[:len(conns)-1]
+		if time.Since(conn.since) < socketIdleTimeout {
+			p.idle[key] = conns
+			return conn.Conn
+		}
+		_ = conn.Close()
+	}
+	delete(p.idle, key)
+	return nil
+}
+
+// Put returns a healthy connection to the pool, or closes it if the pool is
+// full.
+func (p *socketPool) Put(key string, conn net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.idle[key]) >= maxIdleSocketConns {
+		_ = conn.Close()
+		return
+	}
+	p.idle[key] = append(p.idle[key], idleSocketConn{Conn: conn, since: time.Now()})
+}
+
+// Close closes all the idle connections of the pool.
+func (p *socketPool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for key, conns := range p.idle {
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+		delete(p.idle, key)
+	}
+}
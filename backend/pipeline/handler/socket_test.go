@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+)
+
+// acceptLines accepts connections on the listener and sends the lines read
+// on them to the returned channel, along with the index of the connection.
+func acceptLines(listener net.Listener) <-chan [2]interface{} {
+	lines := make(chan [2]interface{}, 10)
+	go func() {
+		for i := 0; ; i++ {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(i int, conn net.Conn) {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					lines <- [2]interface{}{i, scanner.Text()}
+				}
+			}(i, conn)
+		}
+	}()
+	return lines
+}
+
+func TestLegacyAdapter_socketHandlerPool(t *testing.T) {
+	t.Cleanup(socketConns.Close)
+	listener, host, port, closeListener := newListener(t, "tcp")
+	defer closeListener()
+	lines := acceptLines(listener)
+
+	handler := corev2.FixtureHandler("pooled")
+	handler.Type = "tcp"
+	handler.Socket = &corev2.HandlerSocket{Host: host, Port: port}
+	handler.Annotations = map[string]string{SocketPoolAnnotation: "true"}
+	event := corev2.FixtureEvent("entity1", "check1")
+
+	l := &LegacyAdapter{}
+	for _, data := range []string{"first\n", "second\n"} {
+		if err := l.socketHandler(context.Background(), handler, event, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first, second := <-lines, <-lines
+	if first[1] != "first" || second[1] != "second" {
+		t.Fatalf("bad lines: %v, %v", first, second)
+	}
+	if first[0] != second[0] {
+		t.Errorf("expected the connection to be reused")
+	}
+}
+
+func TestLegacyAdapter_socketHandlerTLS(t *testing.T) {
+	// Borrow the certificate of httptest, which is valid for 127.0.0.1
+	server := httptest.NewUnstartedServer(nil)
+	server.StartTLS()
+	cert := server.TLS.Certificates[0]
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	server.Close()
+	if err := ioutil.WriteFile(caPath, ca, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	lines := acceptLines(listener)
+
+	handler := corev2.FixtureHandler("tls")
+	handler.Type = "tcp"
+	handler.Socket = &corev2.HandlerSocket{Host: "127.0.0.1", Port: uint32(listener.Addr().(*net.TCPAddr).Port)}
+	handler.Annotations = map[string]string{SocketTrustedCAFileAnnotation: caPath}
+	event := corev2.FixtureEvent("entity1", "check1")
+
+	l := &LegacyAdapter{}
+	if err := l.socketHandler(context.Background(), handler, event, []byte("secret\n")); err != nil {
+		t.Fatal(err)
+	}
+	if line := <-lines; line[1] != "secret" {
+		t.Errorf("bad line: %v", line)
+	}
+}
+
+func TestNewSocketHandlerConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		handlerType string
+		annotations map[string]string
+		wantErr     bool
+	}{
+		{name: "defaults", handlerType: "tcp"},
+		{name: "write timeout", handlerType: "tcp", annotations: map[string]string{SocketWriteTimeoutAnnotation: "5"}},
+		{name: "invalid write timeout", handlerType: "tcp", annotations: map[string]string{SocketWriteTimeoutAnnotation: "0"}, wantErr: true},
+		{name: "invalid pool", handlerType: "tcp", annotations: map[string]string{SocketPoolAnnotation: "sure"}, wantErr: true},
+		{name: "tls over udp", handlerType: "udp", annotations: map[string]string{SocketTLSAnnotation: "true"}, wantErr: true},
+		{name: "tls", handlerType: "tcp", annotations: map[string]string{SocketTLSAnnotation: "true"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := corev2.FixtureHandler("handler")
+			handler.Type = tt.handlerType
+			handler.Socket = &corev2.HandlerSocket{Host: "localhost", Port: 1234}
+			handler.Annotations = tt.annotations
+			_, err := newSocketHandlerConfig(handler)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newSocketHandlerConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store/v2/wrap"
+)
+
+func init() {
+	wrap.RegisterValidator(&corev2.Handler{}, func(resource interface{}) error {
+		return ValidateHandler(resource.(*corev2.Handler))
+	})
+}
+
+// ValidateHandler validates a handler, including the handler types of this
+// package, which the handlers of core/v2 reject as unknown.
+func ValidateHandler(handler *corev2.Handler) error {
+	switch handler.Type {
+	case HandlerHTTPType, HandlerGRPCType, HandlerPagerDutyType:
+	default:
+		return handler.Validate()
+	}
+
+	// Validate the rest of the handler as a handler set, which has no
+	// type-specific attributes
+	set := *handler
+	set.Type = corev2.HandlerSetType
+	if err := set.Validate(); err != nil {
+		return err
+	}
+
+	switch handler.Type {
+	case HandlerHTTPType:
+		_, err := newHTTPHandlerConfig(handler)
+		return err
+	case HandlerGRPCType:
+		if handler.Socket == nil || handler.Socket.Host == "" || handler.Socket.Port == 0 {
+			return errors.New("grpc handlers need a socket host and port")
+		}
+		_, err := grpcHandlerTLSOptions(handler)
+		return err
+	case HandlerPagerDutyType:
+		for _, secret := range handler.Secrets {
+			if secret != nil && secret.Name == PagerDutyRoutingKeySecret {
+				return validatePagerDutyURL(handler)
+			}
+		}
+		return fmt.Errorf("pagerduty handlers need the %s secret", PagerDutyRoutingKeySecret)
+	}
+	return nil
+}
+
+// validatePagerDutyURL validates the URL annotation of a pagerduty handler, if
+// any.
+func validatePagerDutyURL(handler *corev2.Handler) error {
+	value, ok := handler.Annotations[PagerDutyURLAnnotation]
+	if !ok || value == "" {
+		return nil
+	}
+	if u, err := url.Parse(value); err != nil {
+		return fmt.Errorf("invalid pagerduty handler url: %s", err)
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid pagerduty handler url scheme: %q", u.Scheme)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/backend/apid/handlers"
+	"github.com/sensu/sensu-go/backend/store/patch"
+	"github.com/sensu/sensu-go/backend/store/sqlite"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(*corev2.Handler)
+		wantErr bool
+	}{
+		{
+			name: "pipe",
+		},
+		{
+			name:    "unknown type",
+			handler: func(h *corev2.Handler) { h.Type = "smtp" },
+			wantErr: true,
+		},
+		{
+			name: "http",
+			handler: func(h *corev2.Handler) {
+				h.Type = HandlerHTTPType
+				h.Annotations = map[string]string{HTTPURLAnnotation: "https://example.com/events"}
+			},
+		},
+		{
+			name:    "http without url",
+			handler: func(h *corev2.Handler) { h.Type = HandlerHTTPType },
+			wantErr: true,
+		},
+		{
+			name: "http without namespace",
+			handler: func(h *corev2.Handler) {
+				h.Type = HandlerHTTPType
+				h.Namespace = ""
+				h.Annotations = map[string]string{HTTPURLAnnotation: "https://example.com/events"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "grpc",
+			handler: func(h *corev2.Handler) {
+				h.Type = HandlerGRPCType
+				h.Socket = &corev2.HandlerSocket{Host: "127.0.0.1", Port: 5050}
+			},
+		},
+		{
+			name:    "grpc without socket",
+			handler: func(h *corev2.Handler) { h.Type = HandlerGRPCType },
+			wantErr: true,
+		},
+		{
+			name: "pagerduty",
+			handler: func(h *corev2.Handler) {
+				h.Type = HandlerPagerDutyType
+				h.Secrets = []*corev2.Secret{{Name: PagerDutyRoutingKeySecret, Secret: "pagerduty"}}
+			},
+		},
+		{
+			name:    "pagerduty without routing key",
+			handler: func(h *corev2.Handler) { h.Type = HandlerPagerDutyType },
+			wantErr: true,
+		},
+		{
+			name: "pagerduty with invalid url",
+			handler: func(h *corev2.Handler) {
+				h.Type = HandlerPagerDutyType
+				h.Secrets = []*corev2.Secret{{Name: PagerDutyRoutingKeySecret, Secret: "pagerduty"}}
+				h.Annotations = map[string]string{PagerDutyURLAnnotation: "ftp://example.com"}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := corev2.FixtureHandler("handler")
+			if tt.handler != nil {
+				tt.handler(handler)
+			}
+			err := ValidateHandler(handler)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateHandlerThroughAPI(t *testing.T) {
+	create := func(t *testing.T, handler *corev2.Handler) (*mockstore.ConfigStore, error) {
+		t.Helper()
+		store := &mockstore.V2MockStore{}
+		cs := new(mockstore.ConfigStore)
+		store.On("GetConfigStore").Return(cs)
+		cs.On("CreateIfNotExists", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		body, err := json.Marshal(types.WrapResource(handler))
+		require.NoError(t, err)
+		req, _ := http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"namespace": handler.Namespace})
+		_, err = handlers.NewHandlers[*corev2.Handler](store).CreateResource(req)
+		return cs, err
+	}
+
+	handler := corev2.FixtureHandler("http")
+	handler.Type = HandlerHTTPType
+	handler.Annotations = map[string]string{HTTPURLAnnotation: "https://example.com/events"}
+	cs, err := create(t, handler)
+	require.NoError(t, err)
+	cs.AssertCalled(t, "CreateIfNotExists", mock.Anything, mock.Anything, mock.Anything)
+
+	handler.Annotations = nil
+	cs, err = create(t, handler)
+	require.Error(t, err)
+	code, _ := actions.StatusFromError(err)
+	assert.Equal(t, actions.InvalidArgument, code)
+	cs.AssertNotCalled(t, "CreateIfNotExists", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchHandlerInStore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	db, err := sqlite.Open(ctx, ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	s := sqlite.NewStore(sqlite.StoreConfig{DB: db})
+
+	handler := corev2.FixtureHandler("http")
+	handler.Type = HandlerHTTPType
+	handler.Annotations = map[string]string{HTTPURLAnnotation: "https://example.com/events"}
+	require.NoError(t, storev2.Of[*corev2.Handler](s).CreateOrUpdate(ctx, handler))
+
+	id := storev2.ID{Namespace: handler.Namespace, Name: handler.Name}
+	merge := &patch.Merge{MergePatch: []byte(`{"metadata":{"annotations":{"sensu.io/http/retries":"1"}}}`)}
+	require.NoError(t, storev2.Of[*corev2.Handler](s).Patch(ctx, id, merge))
+	patched, err := storev2.Of[*corev2.Handler](s).Get(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "1", patched.Annotations[HTTPRetriesAnnotation])
+
+	merge = &patch.Merge{MergePatch: []byte(`{"metadata":{"annotations":{"sensu.io/http/url":null}}}`)}
+	assert.Error(t, storev2.Of[*corev2.Handler](s).Patch(ctx, id, merge))
+}
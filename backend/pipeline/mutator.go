@@ -6,7 +6,9 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/tracing"
 	metricspkg "github.com/sensu/sensu-go/metrics"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -48,6 +50,9 @@ func (a *AdapterV1) processMutator(ctx context.Context, ref *corev2.ResourceRefe
 	}))
 	defer mutatorTimer.ObserveDuration()
 
+	ctx, span := tracing.Start(ctx, "pipeline.mutator", tracing.PipelineAttributes(ctx, attribute.String("sensu.mutator", ref.ResourceID()))...)
+	defer func() { tracing.End(span, fErr) }()
+
 	mutator, err := a.getMutatorAdapterForResource(ctx, ref)
 	if err != nil {
 		return nil, err
@@ -12,6 +12,7 @@ import (
 	"github.com/robertkrimen/otto"
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/asset"
+	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/js"
 	"github.com/sirupsen/logrus"
 )
@@ -93,10 +94,11 @@ func (j *JavascriptAdapter) run(ctx context.Context, mutator *corev2.Mutator, ev
 	}
 
 	env := MutatorExecutionEnvironment{
-		Event:   event,
-		Env:     mutator.EnvVars,
-		Timeout: time.Duration(mutator.Timeout) * time.Second,
-		Assets:  assets,
+		Event:    event,
+		Previous: messaging.PreviousEvent(ctx),
+		Env:      mutator.EnvVars,
+		Timeout:  time.Duration(mutator.Timeout) * time.Second,
+		Assets:   assets,
 	}
 
 	logger.WithFields(fields).Debug("javascript event mutator executed")
@@ -112,6 +114,11 @@ type MutatorExecutionEnvironment struct {
 	// serialized.
 	Event *corev2.Event
 
+	// Previous is the event as it was stored before being updated by the event
+	// being mutated, if any. It is available to the expression as previous,
+	// which is null when there is no previous event.
+	Previous *corev2.Event
+
 	// Assets are a set of javascript assets to be evaluated before mutator
 	// execution.
 	Assets js.JavascriptAssets
@@ -127,6 +134,11 @@ func (m *MutatorExecutionEnvironment) Eval(ctx context.Context, expression strin
 
 	parameters := make(map[string]interface{})
 	parameters["event"] = m.Event
+	if m.Previous != nil {
+		parameters["previous"] = m.Previous
+	} else {
+		parameters["previous"] = otto.NullValue()
+	}
 	assets = m.Assets
 	var result []byte
 
@@ -13,6 +13,7 @@ import (
 
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/asset"
+	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/js"
 	"github.com/stretchr/testify/assert"
@@ -308,3 +309,36 @@ func TestJavascriptMutatorWithOSEnv(t *testing.T) {
 		t.Error("expected non-nil error")
 	}
 }
+
+func TestJavascriptMutatorPreviousEvent(t *testing.T) {
+	adapter := new(JavascriptAdapter)
+	mutator := &corev2.Mutator{
+		ObjectMeta: corev2.ObjectMeta{
+			Namespace: "default",
+			Name:      "my_mutator",
+		},
+		Eval: "return previous === null ? 'none' : previous.check.status + ' -> ' + event.check.status;",
+		Type: corev2.JavascriptMutator,
+	}
+	event := corev2.FixtureEvent("default", "default")
+	event.Check.Status = 0
+
+	got, err := adapter.run(context.Background(), mutator, event, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(got), "none"; got != want {
+		t.Errorf("bad result: got %q, want %q", got, want)
+	}
+
+	previous := corev2.FixtureEvent("default", "default")
+	previous.Check.Status = 2
+	ctx := messaging.ContextWithPreviousEvent(context.Background(), previous)
+	got, err = adapter.run(ctx, mutator, event, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(got), "2 -> 0"; got != want {
+		t.Errorf("bad result: got %q, want %q", got, want)
+	}
+}
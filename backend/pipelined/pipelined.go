@@ -13,7 +13,9 @@ import (
 	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/pipeline"
 	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/tracing"
 	metricspkg "github.com/sensu/sensu-go/metrics"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -51,6 +53,9 @@ type Pipelined struct {
 	bus          messaging.MessageBus
 	workerCount  int
 	adapters     []pipeline.Adapter
+	walDir       string
+	wal          *wal
+	intake       chan interface{}
 }
 
 // Config configures a Pipelined.
@@ -58,6 +63,18 @@ type Config struct {
 	Bus         messaging.MessageBus
 	BufferSize  int
 	WorkerCount int
+
+	// WALDir is the directory of the write-ahead log of the events received,
+	// which are redelivered when pipelined restarts unless they were handled.
+	// The events are not journaled if it is empty.
+	WALDir string
+}
+
+// journaled is an event journaled in the write-ahead log, which is
+// acknowledged once handled.
+type journaled struct {
+	key string
+	msg interface{}
 }
 
 // Option is a functional option used to configure Pipelined.
@@ -89,6 +106,8 @@ func New(c Config, options ...Option) (*Pipelined, error) {
 		errChan:     make(chan error, 1),
 		eventChan:   make(chan interface{}, c.BufferSize),
 		workerCount: c.WorkerCount,
+		walDir:      c.WALDir,
+		intake:      make(chan interface{}),
 	}
 	for _, o := range options {
 		if err := o(p); err != nil {
@@ -107,14 +126,28 @@ func New(c Config, options ...Option) (*Pipelined, error) {
 	return p, nil
 }
 
-// Receiver returns the event channel for pipelined.
+// Receiver returns the event channel for pipelined. The events are journaled
+// before they are buffered if pipelined has a write-ahead log, so that the
+// publisher only proceeds once they are.
 func (p *Pipelined) Receiver() chan<- interface{} {
+	if p.wal != nil {
+		return p.intake
+	}
 	return p.eventChan
 }
 
 // Start pipelined, subscribing to the "event" message bus topic to
-// pass Sensu events to the pipelines for handling (goroutines).
+// pass Sensu events to the pipelines for handling (goroutines). The events
+// of the write-ahead log that were not handled are redelivered first.
 func (p *Pipelined) Start() error {
+	var pending []walEntry
+	if p.walDir != "" {
+		var err error
+		if p.wal, pending, err = openWAL(p.walDir); err != nil {
+			return err
+		}
+	}
+
 	sub, err := p.bus.Subscribe(messaging.TopicEvent, "pipelined", p)
 	if err != nil {
 		return err
@@ -123,21 +156,100 @@ func (p *Pipelined) Start() error {
 
 	p.createWorkers(p.workerCount, p.eventChan)
 
+	if p.wal != nil {
+		p.wg.Add(1)
+		go p.journal(pending)
+	}
+
 	return nil
 }
 
 // Stop pipelined.
 func (p *Pipelined) Stop() error {
+	// Unsubscribe first, so that the events received while stopping are
+	// journaled
+	err := p.subscription.Cancel()
 	p.running.Store(false)
 	close(p.stopping)
 	p.wg.Wait()
 	close(p.errChan)
-	err := p.subscription.Cancel()
 	close(p.eventChan)
+	if p.wal != nil {
+		if werr := p.wal.Close(); werr != nil && err == nil {
+			err = werr
+		}
+	}
 
 	return err
 }
 
+// journal redelivers the pending events of the write-ahead log, then journals
+// the events received before passing them to the workers.
+func (p *Pipelined) journal(pending []walEntry) {
+	defer p.wg.Done()
+	for _, entry := range pending {
+		select {
+		case p.eventChan <- &journaled{key: entry.key, msg: entry.message()}:
+			eventsRedelivered.Inc()
+		case <-p.stopping:
+			return
+		}
+	}
+	if len(pending) > 0 {
+		logger.Infof("redelivered %d events from the write-ahead log", len(pending))
+	}
+	for {
+		select {
+		case <-p.stopping:
+			// Journal the events still being published, which are
+			// redelivered when pipelined restarts
+			for {
+				select {
+				case msg := <-p.intake:
+					p.journalMessage(msg)
+				default:
+					return
+				}
+			}
+		case msg := <-p.intake:
+			if msg = p.journalMessage(msg); msg == nil {
+				continue
+			}
+			select {
+			case p.eventChan <- msg:
+			case <-p.stopping:
+				return
+			}
+		}
+	}
+}
+
+// journalMessage appends the event of a message to the write-ahead log, and
+// returns the journaled message, or nil if the event was a duplicate. The
+// messages are passed as is if they can't be journaled.
+func (p *Pipelined) journalMessage(msg interface{}) interface{} {
+	var event, previous *corev2.Event
+	switch m := msg.(type) {
+	case *corev2.Event:
+		event = m
+	case *messaging.EventWithPrevious:
+		event, previous = m.Event, m.Previous
+	default:
+		return msg
+	}
+	key, ok, err := p.wal.Append(event, previous)
+	if err != nil {
+		logger.WithError(err).Error("could not journal event")
+		return msg
+	}
+	if !ok {
+		logger.WithField("idempotency_key", key).Debug("skipping event which was already handled")
+		eventsDeduplicated.Inc()
+		return nil
+	}
+	return &journaled{key: key, msg: msg}
+}
+
 // Err returns a channel to listen for terminal errors on.
 func (p *Pipelined) Err() <-chan error {
 	return p.errChan
@@ -165,8 +277,13 @@ func (p *Pipelined) createWorkers(count int, channel chan interface{}) {
 				case <-p.stopping:
 					return
 				case msg := <-channel:
+					var key string
+					if j, ok := msg.(*journaled); ok {
+						key, msg = j.key, j.msg
+					}
 					if _, err := p.handleMessage(context.Background(), msg); err != nil {
 						if _, ok := err.(*store.ErrInternal); ok {
+							// The event is redelivered when pipelined restarts
 							select {
 							case p.errChan <- err:
 							case <-p.stopping:
@@ -174,6 +291,11 @@ func (p *Pipelined) createWorkers(count int, channel chan interface{}) {
 							return
 						}
 					}
+					if key != "" {
+						if err := p.wal.Ack(key); err != nil {
+							logger.WithError(err).Error("could not acknowledge journaled event")
+						}
+					}
 				}
 			}
 		}()
@@ -197,11 +319,24 @@ func (p *Pipelined) handleMessage(ctx context.Context, msg interface{}) (hadPipe
 			Observe(float64(duration) / float64(time.Millisecond))
 	}()
 
+	// Expose the previous state of the event to filters and mutators
+	if event, ok := msg.(*messaging.EventWithPrevious); ok {
+		ctx = messaging.ContextWithPreviousEvent(ctx, event.Previous)
+		msg = event.Event
+	}
+
 	getter, ok := msg.(PipelineGetter)
 	if !ok {
 		panic("message received was not a PipelineGetter")
 	}
 
+	// Continue the trace started by eventd, if any
+	if event, ok := msg.(*corev2.Event); ok {
+		var span trace.Span
+		ctx, span = tracing.Start(tracing.ExtractEvent(ctx, event), "pipelined.handle_event", tracing.EventAttributes(event)...)
+		defer func() { tracing.End(span, fErr) }()
+	}
+
 	fields := getter.LogFields(false)
 	pipelineRefs := getter.GetPipelines()
 
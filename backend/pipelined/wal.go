@@ -0,0 +1,392 @@
+package pipelined
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+)
+
+const (
+	EventsRedelivered  = "sensu_go_pipelined_events_redelivered"
+	EventsDeduplicated = "sensu_go_pipelined_events_deduplicated"
+)
+
+var (
+	eventsRedelivered = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: EventsRedelivered,
+			Help: "The total number of events redelivered from the pipelined write-ahead log at startup",
+		},
+	)
+
+	eventsDeduplicated = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: EventsDeduplicated,
+			Help: "The total number of events skipped by pipelined because an event with the same ID was already handled",
+		},
+	)
+)
+
+func init() {
+	_ = prometheus.Register(eventsRedelivered)
+	_ = prometheus.Register(eventsDeduplicated)
+}
+
+const (
+	// walFileName is the name of the write-ahead log file, in its directory.
+	walFileName = "events.wal"
+
+	// walCompactSize is the size above which the log is compacted, if most of
+	// its records were acknowledged.
+	walCompactSize = 4 << 20
+
+	// walAckedKeys is the number of acknowledged keys remembered to skip the
+	// events delivered twice.
+	walAckedKeys = 10000
+
+	// walHeaderSize is the size of the header of the log records: magic (2
+	// bytes), record type (1 byte), reserved (1 byte), body length (4 bytes)
+	// and CRC-32 of the previous fields and the body (4 bytes).
+	walHeaderSize = 12
+
+	walMagic = 0x504c
+
+	walRecordEvent = 1
+	walRecordAck   = 2
+)
+
+// walEntry is an event journaled in the write-ahead log, until it is
+// acknowledged.
+type walEntry struct {
+	key      string
+	event    *corev2.Event
+	previous *corev2.Event
+}
+
+// message returns the bus message of the entry.
+func (e walEntry) message() interface{} {
+	if e.previous != nil {
+		return &messaging.EventWithPrevious{Event: e.event, Previous: e.previous}
+	}
+	return e.event
+}
+
+type walPending struct {
+	seq   uint64
+	frame []byte
+}
+
+// wal is the write-ahead log of the events received by pipelined. The events
+// are appended to the log when they are received, and acknowledged once they
+// were handled, so that the events that were not are redelivered when
+// pipelined restarts. The events are keyed by their idempotency key, so that
+// the events delivered twice are only handled once.
+type wal struct {
+	path string
+
+	mu      sync.Mutex
+	file    *os.File
+	size    int64
+	seq     uint64
+	pending map[string]walPending
+	// pendingSize is the size of the records of the pending entries
+	pendingSize int64
+	acked       map[string]struct{}
+	ackedOrder  []string
+}
+
+// openWAL opens the write-ahead log stored in dir, creating it if needed, and
+// returns the entries that were not acknowledged, oldest first.
+func openWAL(dir string) (*wal, []walEntry, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, nil, fmt.Errorf("could not create directory for pipelined wal (%s): %s", dir, err)
+	}
+	w := &wal{
+		path:    filepath.Join(dir, walFileName),
+		pending: make(map[string]walPending),
+		acked:   make(map[string]struct{}),
+	}
+	b, err := os.ReadFile(w.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("could not read pipelined wal: %s", err)
+	}
+	entries := make(map[string]walEntry)
+	for offset := 0; offset < len(b); {
+		recordType, body, size, err := decodeWALFrame(b[offset:])
+		if err != nil {
+			// A record can only be truncated by a crash while it was appended,
+			// so it is the last one
+			logger.WithError(err).Warning("discarding truncated pipelined wal record")
+			break
+		}
+		frame := append([]byte(nil), b[offset:offset+size]...)
+		offset += size
+		switch recordType {
+		case walRecordEvent:
+			entry, err := decodeWALEntry(body)
+			if err != nil {
+				logger.WithError(err).Warning("discarding invalid pipelined wal record")
+				continue
+			}
+			entries[entry.key] = entry
+			w.addPending(entry.key, frame)
+		case walRecordAck:
+			w.ack(string(body))
+			delete(entries, string(body))
+		}
+	}
+
+	result := make([]walEntry, 0, len(entries))
+	for _, key := range w.pendingKeys() {
+		result = append(result, entries[key])
+	}
+	if err := w.compact(); err != nil {
+		return nil, nil, err
+	}
+	return w, result, nil
+}
+
+// Append journals an event, unless it is a duplicate of an event that is
+// pending or was acknowledged recently. It returns the key of the entry, and
+// whether it was journaled.
+func (w *wal) Append(event, previous *corev2.Event) (string, bool, error) {
+	key := messaging.IdempotencyKey(event)
+	if key == "" {
+		key = uuid.New().String()
+	}
+	entry := walEntry{key: key, event: event, previous: previous}
+	body, err := encodeWALEntry(entry)
+	if err != nil {
+		return "", false, err
+	}
+	frame := encodeWALFrame(walRecordEvent, body)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.pending[key]; ok {
+		return key, false, nil
+	}
+	if _, ok := w.acked[key]; ok {
+		return key, false, nil
+	}
+	if err := w.write(frame); err != nil {
+		return "", false, err
+	}
+	w.addPending(key, frame)
+	return key, true, nil
+}
+
+// Ack acknowledges that the event of the given key was handled.
+func (w *wal) Ack(key string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.pending[key]; !ok {
+		return nil
+	}
+	if err := w.write(encodeWALFrame(walRecordAck, []byte(key))); err != nil {
+		return err
+	}
+	w.ack(key)
+	if w.size > walCompactSize && w.size > 2*w.pendingSize {
+		return w.compact()
+	}
+	return nil
+}
+
+// Close closes the log. The pending entries are kept.
+func (w *wal) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *wal) write(frame []byte) error {
+	if w.file == nil {
+		return errors.New("pipelined wal is closed")
+	}
+	n, err := w.file.Write(frame)
+	w.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("could not write to pipelined wal: %s", err)
+	}
+	return nil
+}
+
+func (w *wal) addPending(key string, frame []byte) {
+	w.seq++
+	w.pending[key] = walPending{seq: w.seq, frame: frame}
+	w.pendingSize += int64(len(frame))
+}
+
+// ack removes an entry from the pending entries, and remembers its key.
+func (w *wal) ack(key string) {
+	if pending, ok := w.pending[key]; ok {
+		delete(w.pending, key)
+		w.pendingSize -= int64(len(pending.frame))
+	}
+	if _, ok := w.acked[key]; ok {
+		return
+	}
+	w.acked[key] = struct{}{}
+	w.ackedOrder = append(w.ackedOrder, key)
+	if len(w.ackedOrder) > walAckedKeys {
+		delete(w.acked, w.ackedOrder[0])
+		w.ackedOrder = w.ackedOrder[1:]
+	}
+}
+
+// pendingKeys returns the keys of the pending entries, oldest first.
+func (w *wal) pendingKeys() []string {
+	keys := make([]string, 0, len(w.pending))
+	for key := range w.pending {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return w.pending[keys[i]].seq < w.pending[keys[j]].seq
+	})
+	return keys
+}
+
+// compact rewrites the log with the pending entries only, and reopens it.
+func (w *wal) compact() error {
+	var buf bytes.Buffer
+	for _, key := range w.pendingKeys() {
+		buf.Write(w.pending[key].frame)
+	}
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			logger.WithError(err).Warning("error closing pipelined wal")
+		}
+		w.file = nil
+	}
+	tmp := w.path + ".tmp"
+	if err := writeFileSync(tmp, buf.Bytes()); err != nil {
+		return fmt.Errorf("could not compact pipelined wal: %s", err)
+	}
+	if err := os.Rename(tmp, w.path); err != nil {
+		return fmt.Errorf("could not compact pipelined wal: %s", err)
+	}
+	f, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open pipelined wal: %s", err)
+	}
+	w.file = f
+	w.size = int64(buf.Len())
+	return nil
+}
+
+func writeFileSync(path string, b []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// encodeWALEntry encodes an entry as its key, its event and its previous
+// event, each prefixed with its length.
+func encodeWALEntry(entry walEntry) ([]byte, error) {
+	event, err := entry.event.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	var previous []byte
+	if entry.previous != nil {
+		if previous, err = entry.previous.Marshal(); err != nil {
+			return nil, err
+		}
+	}
+	var buf bytes.Buffer
+	for _, field := range [][]byte{[]byte(entry.key), event, previous} {
+		var length [4]byte
+		binary.BigEndian.PutUint32(length[:], uint32(len(field)))
+		buf.Write(length[:])
+		buf.Write(field)
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeWALEntry(body []byte) (walEntry, error) {
+	var fields [3][]byte
+	for i := range fields {
+		if len(body) < 4 {
+			return walEntry{}, io.ErrUnexpectedEOF
+		}
+		length := binary.BigEndian.Uint32(body[:4])
+		body = body[4:]
+		if uint32(len(body)) < length {
+			return walEntry{}, io.ErrUnexpectedEOF
+		}
+		fields[i], body = body[:length], body[length:]
+	}
+	entry := walEntry{key: string(fields[0]), event: &corev2.Event{}}
+	if err := entry.event.Unmarshal(fields[1]); err != nil {
+		return walEntry{}, err
+	}
+	if len(fields[2]) > 0 {
+		entry.previous = &corev2.Event{}
+		if err := entry.previous.Unmarshal(fields[2]); err != nil {
+			return walEntry{}, err
+		}
+	}
+	return entry, nil
+}
+
+func encodeWALFrame(recordType byte, body []byte) []byte {
+	frame := make([]byte, walHeaderSize+len(body))
+	binary.BigEndian.PutUint16(frame[0:2], walMagic)
+	frame[2] = recordType
+	binary.BigEndian.PutUint32(frame[4:8], uint32(len(body)))
+	copy(frame[walHeaderSize:], body)
+	crc := crc32.NewIEEE()
+	_, _ = crc.Write(frame[0:8])
+	_, _ = crc.Write(body)
+	binary.BigEndian.PutUint32(frame[8:12], crc.Sum32())
+	return frame
+}
+
+func decodeWALFrame(b []byte) (byte, []byte, int, error) {
+	if len(b) < walHeaderSize {
+		return 0, nil, 0, io.ErrUnexpectedEOF
+	}
+	if binary.BigEndian.Uint16(b[0:2]) != walMagic {
+		return 0, nil, 0, errors.New("invalid record header")
+	}
+	length := int(binary.BigEndian.Uint32(b[4:8]))
+	if len(b) < walHeaderSize+length {
+		return 0, nil, 0, io.ErrUnexpectedEOF
+	}
+	body := b[walHeaderSize : walHeaderSize+length]
+	crc := crc32.NewIEEE()
+	_, _ = crc.Write(b[0:8])
+	_, _ = crc.Write(body)
+	if crc.Sum32() != binary.BigEndian.Uint32(b[8:12]) {
+		return 0, nil, 0, errors.New("invalid record checksum")
+	}
+	return b[2], body, walHeaderSize + length, nil
+}
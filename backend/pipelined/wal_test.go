@@ -0,0 +1,174 @@
+package pipelined
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixtureWALEvent(check string) *corev2.Event {
+	event := corev2.FixtureEvent("entity1", check)
+	event.Check.Handlers = []string{"handler1"}
+	id := uuid.New()
+	event.ID = id[:]
+	return event
+}
+
+func TestWAL(t *testing.T) {
+	dir := t.TempDir()
+	w, pending, err := openWAL(dir)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	first := fixtureWALEvent("check1")
+	second := fixtureWALEvent("check2")
+	previous := fixtureWALEvent("check2")
+	previous.Check.Status = 2
+
+	key1, ok, err := w.Append(first, nil)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	key2, ok, err := w.Append(second, previous)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, messaging.IdempotencyKey(second), key2)
+
+	// The pending events are not journaled twice
+	_, ok, err = w.Append(second, previous)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, w.Ack(key1))
+	// The acknowledged events are not journaled twice
+	_, ok, err = w.Append(first, nil)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	require.NoError(t, w.Close())
+
+	w, pending, err = openWAL(dir)
+	require.NoError(t, err)
+	defer w.Close()
+	require.Len(t, pending, 1)
+	assert.Equal(t, key2, pending[0].key)
+	msg, ok := pending[0].message().(*messaging.EventWithPrevious)
+	require.True(t, ok)
+	assert.Equal(t, "check2", msg.Check.Name)
+	assert.Equal(t, uint32(2), msg.Previous.Check.Status)
+
+	// The log was compacted to the pending events
+	info, err := os.Stat(filepath.Join(dir, walFileName))
+	require.NoError(t, err)
+	assert.Equal(t, w.pendingSize, info.Size())
+}
+
+func TestWALTruncatedRecord(t *testing.T) {
+	dir := t.TempDir()
+	w, _, err := openWAL(dir)
+	require.NoError(t, err)
+	_, _, err = w.Append(fixtureWALEvent("check1"), nil)
+	require.NoError(t, err)
+	_, _, err = w.Append(fixtureWALEvent("check2"), nil)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	// Truncate the last record, as a crash while it was appended would
+	path := filepath.Join(dir, walFileName)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(path, info.Size()-10))
+
+	w, pending, err := openWAL(dir)
+	require.NoError(t, err)
+	defer w.Close()
+	require.Len(t, pending, 1)
+	assert.Equal(t, "check1", pending[0].event.Check.Name)
+}
+
+// recordingAdapter runs the pipelines of the events by recording their check,
+// or fails with an internal error for the checks in fail.
+type recordingAdapter struct {
+	mu     sync.Mutex
+	checks []string
+	fail   map[string]bool
+}
+
+func (a *recordingAdapter) Name() string {
+	return "recording"
+}
+
+func (a *recordingAdapter) CanRun(*corev2.ResourceReference) bool {
+	return true
+}
+
+func (a *recordingAdapter) Run(_ context.Context, _ *corev2.ResourceReference, resource interface{}) error {
+	event := resource.(*corev2.Event)
+	if a.fail[event.Check.Name] {
+		return &store.ErrInternal{Message: "unavailable"}
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.checks = append(a.checks, event.Check.Name)
+	return nil
+}
+
+func (a *recordingAdapter) handled() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.checks...)
+}
+
+func TestPipelinedRedelivery(t *testing.T) {
+	dir := t.TempDir()
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+
+	// The event of check1 fails to be handled, which stops the worker
+	p, err := New(Config{Bus: bus, BufferSize: 10, WorkerCount: 1, WALDir: dir})
+	require.NoError(t, err)
+	adapter := &recordingAdapter{fail: map[string]bool{"check1": true}}
+	p.AddAdapter(adapter)
+	require.NoError(t, p.Start())
+
+	failed := fixtureWALEvent("check1")
+	require.NoError(t, bus.Publish(messaging.TopicEvent, failed))
+	select {
+	case err := <-p.Err():
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("the event did not fail")
+	}
+	require.NoError(t, p.Stop())
+
+	// The event is redelivered when pipelined restarts, and the duplicates
+	// are skipped
+	p, err = New(Config{Bus: bus, BufferSize: 10, WorkerCount: 1, WALDir: dir})
+	require.NoError(t, err)
+	adapter = &recordingAdapter{}
+	p.AddAdapter(adapter)
+	require.NoError(t, p.Start())
+	defer func() {
+		assert.NoError(t, p.Stop())
+	}()
+
+	handled := fixtureWALEvent("check2")
+	require.NoError(t, bus.Publish(messaging.TopicEvent, handled))
+	require.NoError(t, bus.Publish(messaging.TopicEvent, failed))
+	require.NoError(t, bus.Publish(messaging.TopicEvent, handled))
+
+	assert.Eventually(t, func() bool {
+		return len(adapter.handled()) == 2
+	}, time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.ElementsMatch(t, []string{"check1", "check2"}, adapter.handled())
+}
@@ -47,6 +47,10 @@ type Poller struct {
 	TxnWindow time.Duration
 	// Table implements the access methods required by the poller.
 	Table Table
+	// Notify, when not nil, wakes the poller up before the end of the
+	// current interval, typically when the Table is known to have changed.
+	// The Table is still polled at every Interval.
+	Notify <-chan struct{}
 
 	start    time.Time
 	nextPoll time.Time
@@ -64,16 +68,18 @@ func (p *Poller) Initialize(ctx context.Context) error {
 	return err
 }
 
-// Next blocks until the next polling interval, then returns any changed rows.
+// Next blocks until the next polling interval, or until the poller is
+// notified, then returns any changed rows.
 func (p *Poller) Next(ctx context.Context) ([]RowChange, error) {
 	nextInterval := time.NewTimer(time.Until(p.nextPoll))
 	defer nextInterval.Stop()
 
-	p.nextPoll = p.nextPoll.Add(p.Interval)
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	case <-nextInterval.C:
+		p.nextPoll = p.nextPoll.Add(p.Interval)
+	case <-p.Notify:
 	}
 
 	updates, err := p.Table.Since(ctx, p.start)
@@ -0,0 +1,268 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	cachev2 "github.com/sensu/sensu-go/backend/store/cache/v2"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"golang.org/x/time/rate"
+)
+
+// The resources limited by the quotas, by RBAC name.
+const (
+	ResourceChecks   = "checks"
+	ResourceEntities = "entities"
+	ResourceSilenced = "silenced"
+)
+
+// QuotaGetter gets the quotas of a namespace.
+type QuotaGetter interface {
+	Get(namespace string) []cachev2.Value[*NamespaceQuota, NamespaceQuota]
+}
+
+// Limits are the limits that apply to a namespace, combined from its quotas.
+// A limit of 0 means that the resource is not limited.
+type Limits struct {
+	Checks          int
+	Entities        int
+	EventsPerSecond float64
+	Silenced        int
+}
+
+func (l Limits) max(resource string) int {
+	switch resource {
+	case ResourceChecks:
+		return l.Checks
+	case ResourceEntities:
+		return l.Entities
+	case ResourceSilenced:
+		return l.Silenced
+	}
+	return 0
+}
+
+// ExceededError is returned when the creation of a resource would exceed the
+// quota of its namespace.
+type ExceededError struct {
+	Namespace string
+	Resource  string
+	Max       int
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("the quota of %d %s of namespace %q is exceeded", e.Max, e.Resource, e.Namespace)
+}
+
+// Usage is the usage of the quotas of a namespace.
+type Usage struct {
+	Checks   ResourceUsage `json:"checks"`
+	Entities ResourceUsage `json:"entities"`
+	Silenced ResourceUsage `json:"silenced"`
+	Events   EventsUsage   `json:"events"`
+}
+
+// ResourceUsage is the number of resources of a namespace, and their limit.
+type ResourceUsage struct {
+	Used int `json:"used"`
+	Max  int `json:"max"`
+}
+
+// EventsUsage is the rate of the events received in a namespace, and its
+// limit.
+type EventsUsage struct {
+	// PerSecond is the number of events accepted during the last second.
+	PerSecond int64 `json:"per_second"`
+	// MaxPerSecond is the maximum number of events per second.
+	MaxPerSecond float64 `json:"max_per_second"`
+	// Dropped is the number of events dropped by this backend since it
+	// started.
+	Dropped int64 `json:"dropped"`
+}
+
+// eventRate limits and measures the rate of the events of a namespace.
+type eventRate struct {
+	limiter *rate.Limiter
+	// second is the unix time of the current second, in which count events
+	// were accepted. last is the count of the previous second.
+	second  int64
+	count   int64
+	last    int64
+	dropped int64
+}
+
+func (r *eventRate) tick(now time.Time) {
+	second := now.Unix()
+	switch {
+	case second == r.second:
+		return
+	case second == r.second+1:
+		r.last = r.count
+	default:
+		r.last = 0
+	}
+	r.second = second
+	r.count = 0
+}
+
+// Enforcer enforces the quotas of the namespaces. The rates of the events are
+// tracked in memory, by each backend.
+type Enforcer struct {
+	quotas QuotaGetter
+	store  storev2.Interface
+
+	mu     sync.Mutex
+	events map[string]*eventRate
+}
+
+// NewEnforcer returns an Enforcer of the given quotas, counting the resources
+// of the store.
+func NewEnforcer(quotas QuotaGetter, store storev2.Interface) *Enforcer {
+	return &Enforcer{
+		quotas: quotas,
+		store:  store,
+		events: make(map[string]*eventRate),
+	}
+}
+
+// Limits returns the limits of the namespace, which are the lowest of the
+// limits of its quotas.
+func (e *Enforcer) Limits(namespace string) Limits {
+	var limits Limits
+	lowest := func(limit *int, value int) {
+		if value > 0 && (*limit == 0 || value < *limit) {
+			*limit = value
+		}
+	}
+	for _, value := range e.quotas.Get(namespace) {
+		quota := value.Resource
+		lowest(&limits.Checks, quota.MaxChecks)
+		lowest(&limits.Entities, quota.MaxEntities)
+		lowest(&limits.Silenced, quota.MaxSilenced)
+		if max := quota.MaxEventsPerSecond; max > 0 && (limits.EventsPerSecond == 0 || max < limits.EventsPerSecond) {
+			limits.EventsPerSecond = max
+		}
+	}
+	return limits
+}
+
+// CheckCreate returns an ExceededError if the creation of a resource, given by
+// its RBAC name, would exceed the quota of the namespace. When a name is given,
+// the resource is not counted as a creation if it already exists.
+func (e *Enforcer) CheckCreate(ctx context.Context, namespace, resource, name string) error {
+	max := e.Limits(namespace).max(resource)
+	if max == 0 {
+		return nil
+	}
+	if name != "" {
+		exists, err := e.exists(ctx, namespace, resource, name)
+		if err != nil || exists {
+			return err
+		}
+	}
+	count, err := e.count(ctx, namespace, resource)
+	if err != nil {
+		return err
+	}
+	if count >= max {
+		return &ExceededError{Namespace: namespace, Resource: resource, Max: max}
+	}
+	return nil
+}
+
+// AllowEvent returns whether an event can be received in the namespace, given
+// the limit of its quotas. The dropped events are counted.
+func (e *Enforcer) AllowEvent(namespace string) bool {
+	max := e.Limits(namespace).EventsPerSecond
+	now := time.Now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	r, ok := e.events[namespace]
+	if !ok {
+		r = &eventRate{}
+		e.events[namespace] = r
+	}
+	r.tick(now)
+
+	if max > 0 {
+		if r.limiter == nil {
+			r.limiter = rate.NewLimiter(rate.Limit(max), int(math.Ceil(max)))
+		} else if r.limiter.Limit() != rate.Limit(max) {
+			r.limiter.SetLimitAt(now, rate.Limit(max))
+			r.limiter.SetBurstAt(now, int(math.Ceil(max)))
+		}
+		if !r.limiter.AllowN(now, 1) {
+			r.dropped++
+			return false
+		}
+	} else {
+		r.limiter = nil
+	}
+	r.count++
+	return true
+}
+
+// Usage returns the usage of the namespace of the quota, against the limits of
+// the quota.
+func (e *Enforcer) Usage(ctx context.Context, quota *NamespaceQuota) (*Usage, error) {
+	namespace := quota.Metadata.Namespace
+	usage := &Usage{
+		Checks:   ResourceUsage{Max: quota.MaxChecks},
+		Entities: ResourceUsage{Max: quota.MaxEntities},
+		Silenced: ResourceUsage{Max: quota.MaxSilenced},
+		Events:   EventsUsage{MaxPerSecond: quota.MaxEventsPerSecond},
+	}
+	var err error
+	if usage.Checks.Used, err = e.count(ctx, namespace, ResourceChecks); err != nil {
+		return nil, err
+	}
+	if usage.Entities.Used, err = e.count(ctx, namespace, ResourceEntities); err != nil {
+		return nil, err
+	}
+	if usage.Silenced.Used, err = e.count(ctx, namespace, ResourceSilenced); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if r, ok := e.events[namespace]; ok {
+		r.tick(time.Now())
+		usage.Events.PerSecond = r.last
+		usage.Events.Dropped = r.dropped
+	}
+	return usage, nil
+}
+
+func (e *Enforcer) count(ctx context.Context, namespace, resource string) (int, error) {
+	switch resource {
+	case ResourceChecks:
+		req := storev2.NewResourceRequestFromV2Resource(&corev2.CheckConfig{ObjectMeta: corev2.NewObjectMeta("", namespace)})
+		return e.store.GetConfigStore().Count(ctx, req)
+	case ResourceEntities:
+		return e.store.GetEntityConfigStore().Count(ctx, namespace, "")
+	case ResourceSilenced:
+		silenced, err := e.store.GetSilencesStore().GetSilences(ctx, namespace)
+		return len(silenced), err
+	}
+	return 0, fmt.Errorf("resource %q is not limited by quotas", resource)
+}
+
+func (e *Enforcer) exists(ctx context.Context, namespace, resource, name string) (bool, error) {
+	switch resource {
+	case ResourceChecks:
+		req := storev2.NewResourceRequestFromV2Resource(&corev2.CheckConfig{ObjectMeta: corev2.NewObjectMeta(name, namespace)})
+		return e.store.GetConfigStore().Exists(ctx, req)
+	case ResourceEntities:
+		return e.store.GetEntityConfigStore().Exists(ctx, namespace, name)
+	case ResourceSilenced:
+		silenced, err := e.store.GetSilencesStore().GetSilencesByName(ctx, namespace, []string{name})
+		return len(silenced) > 0, err
+	}
+	return false, fmt.Errorf("resource %q is not limited by quotas", resource)
+}
@@ -0,0 +1,121 @@
+// Package quota limits the number of resources, and the rate of the events,
+// of the namespaces. The limits are set by the NamespaceQuota resources and
+// enforced at creation time by apid, and at ingest time by agentd.
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	apitools "github.com/sensu/sensu-api-tools"
+)
+
+const (
+	// APIGroup is the API group of the quota resources.
+	APIGroup = "quota"
+
+	// APIVersion is the API version of the quota resources.
+	APIVersion = "v1"
+
+	// NamespaceQuotasResource is the RBAC name of the namespace quotas.
+	NamespaceQuotasResource = "namespacequotas"
+)
+
+func init() {
+	apitools.RegisterType(path.Join(APIGroup, APIVersion), new(NamespaceQuota))
+}
+
+var _ corev3.Resource = new(NamespaceQuota)
+
+// NamespaceQuota limits the resources of its namespace. A limit of 0 means
+// that the resource is not limited. When a namespace has several quotas, the
+// lowest of their limits applies.
+type NamespaceQuota struct {
+	// Metadata contains the name, namespace, labels and annotations of the
+	// quota.
+	Metadata *corev2.ObjectMeta `json:"metadata"`
+
+	// MaxChecks is the maximum number of checks of the namespace.
+	MaxChecks int `json:"max_checks,omitempty"`
+
+	// MaxEntities is the maximum number of entities of the namespace.
+	MaxEntities int `json:"max_entities,omitempty"`
+
+	// MaxEventsPerSecond is the maximum number of events per second received
+	// in the namespace. Keepalives are not limited.
+	MaxEventsPerSecond float64 `json:"max_events_per_second,omitempty"`
+
+	// MaxSilenced is the maximum number of silenced entries of the namespace.
+	MaxSilenced int `json:"max_silenced,omitempty"`
+}
+
+// GetMetadata returns the metadata of the quota.
+func (q *NamespaceQuota) GetMetadata() *corev2.ObjectMeta {
+	return q.Metadata
+}
+
+// SetMetadata sets the metadata of the quota.
+func (q *NamespaceQuota) SetMetadata(meta *corev2.ObjectMeta) {
+	q.Metadata = meta
+}
+
+// StoreName returns the name of the quotas in the store.
+func (q *NamespaceQuota) StoreName() string {
+	return "namespace_quotas"
+}
+
+// RBACName returns the name of the quotas for RBAC purposes.
+func (q *NamespaceQuota) RBACName() string {
+	return NamespaceQuotasResource
+}
+
+// URIPath returns the path of the quota in the API.
+func (q *NamespaceQuota) URIPath() string {
+	if q.Metadata == nil {
+		return path.Join("/api", APIGroup, APIVersion, NamespaceQuotasResource)
+	}
+	return path.Join("/api", APIGroup, APIVersion, "namespaces", url.PathEscape(q.Metadata.Namespace),
+		NamespaceQuotasResource, url.PathEscape(q.Metadata.Name))
+}
+
+// GetTypeMeta returns the type and API version of the quotas.
+func (q *NamespaceQuota) GetTypeMeta() corev2.TypeMeta {
+	return corev2.TypeMeta{
+		Type:       "NamespaceQuota",
+		APIVersion: path.Join(APIGroup, APIVersion),
+	}
+}
+
+// Validate the quota.
+func (q *NamespaceQuota) Validate() error {
+	if q.Metadata == nil {
+		return errors.New("nil metadata")
+	}
+	if err := corev2.ValidateName(q.Metadata.Name); err != nil {
+		return fmt.Errorf("the namespace quota name %s", err)
+	}
+	if q.Metadata.Namespace == "" {
+		return errors.New("a namespace quota must have a namespace")
+	}
+	if q.MaxChecks < 0 || q.MaxEntities < 0 || q.MaxEventsPerSecond < 0 || q.MaxSilenced < 0 {
+		return errors.New("the limits of a namespace quota cannot be negative")
+	}
+	return nil
+}
+
+// NamespaceQuotaFields returns the fields of the quota available to selectors.
+func NamespaceQuotaFields(r corev3.Resource) map[string]string {
+	quota := r.(*NamespaceQuota)
+	fields := map[string]string{
+		"namespacequota.name":      quota.Metadata.Name,
+		"namespacequota.namespace": quota.Metadata.Namespace,
+	}
+	for key, value := range quota.Metadata.Labels {
+		fields["namespacequota.labels."+key] = value
+	}
+	return fields
+}
@@ -0,0 +1,83 @@
+package quota
+
+import (
+	"context"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	cachev2 "github.com/sensu/sensu-go/backend/store/cache/v2"
+	"github.com/sensu/sensu-go/testing/mockstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNamespaceQuotaValidate(t *testing.T) {
+	quota := &NamespaceQuota{Metadata: corev2.NewObjectMetaP("quota", "default"), MaxChecks: 10}
+	assert.NoError(t, quota.Validate())
+
+	quota.Metadata.Namespace = ""
+	assert.Error(t, quota.Validate())
+
+	quota.Metadata.Namespace = "default"
+	quota.MaxEntities = -1
+	assert.Error(t, quota.Validate())
+}
+
+func newTestEnforcer(store *mockstore.V2MockStore, quotas ...*NamespaceQuota) *Enforcer {
+	return NewEnforcer(cachev2.NewFromResources(quotas, false), store)
+}
+
+func TestEnforcerLimits(t *testing.T) {
+	enforcer := newTestEnforcer(&mockstore.V2MockStore{},
+		&NamespaceQuota{Metadata: corev2.NewObjectMetaP("a", "default"), MaxChecks: 10, MaxEventsPerSecond: 5},
+		&NamespaceQuota{Metadata: corev2.NewObjectMetaP("b", "default"), MaxChecks: 5, MaxEntities: 20},
+		&NamespaceQuota{Metadata: corev2.NewObjectMetaP("c", "other"), MaxChecks: 1},
+	)
+	assert.Equal(t, Limits{Checks: 5, Entities: 20, EventsPerSecond: 5}, enforcer.Limits("default"))
+	assert.Equal(t, Limits{}, enforcer.Limits("none"))
+}
+
+func TestEnforcerCheckCreate(t *testing.T) {
+	cs := new(mockstore.ConfigStore)
+	cs.On("Exists", mock.Anything, mock.Anything).Return(false, nil)
+	cs.On("Count", mock.Anything, mock.Anything).Return(2, nil)
+	ecs := new(mockstore.EntityConfigStore)
+	ecs.On("Exists", mock.Anything, "default", "existing").Return(true, nil)
+	ecs.On("Exists", mock.Anything, "default", mock.Anything).Return(false, nil)
+	ecs.On("Count", mock.Anything, "default", "").Return(3, nil)
+	ss := new(mockstore.SilencesStore)
+	ss.On("GetSilences", mock.Anything, "default").Return([]*corev2.Silenced{}, nil)
+	s := &mockstore.V2MockStore{}
+	s.On("GetConfigStore").Return(cs)
+	s.On("GetEntityConfigStore").Return(ecs)
+	s.On("GetSilencesStore").Return(ss)
+
+	enforcer := newTestEnforcer(s, &NamespaceQuota{
+		Metadata:    corev2.NewObjectMetaP("quota", "default"),
+		MaxChecks:   3,
+		MaxEntities: 3,
+		MaxSilenced: 1,
+	})
+	ctx := context.Background()
+
+	assert.NoError(t, enforcer.CheckCreate(ctx, "default", ResourceChecks, ""))
+	assert.NoError(t, enforcer.CheckCreate(ctx, "default", ResourceSilenced, ""))
+	assert.NoError(t, enforcer.CheckCreate(ctx, "default", ResourceEntities, "existing"))
+	assert.NoError(t, enforcer.CheckCreate(ctx, "other", ResourceEntities, "new"))
+
+	err := enforcer.CheckCreate(ctx, "default", ResourceEntities, "new")
+	assert.IsType(t, &ExceededError{}, err)
+}
+
+func TestEnforcerAllowEvent(t *testing.T) {
+	enforcer := newTestEnforcer(&mockstore.V2MockStore{},
+		&NamespaceQuota{Metadata: corev2.NewObjectMetaP("quota", "default"), MaxEventsPerSecond: 2},
+	)
+	assert.True(t, enforcer.AllowEvent("default"))
+	assert.True(t, enforcer.AllowEvent("default"))
+	assert.False(t, enforcer.AllowEvent("default"))
+	for i := 0; i < 5; i++ {
+		assert.True(t, enforcer.AllowEvent("other"))
+	}
+	assert.Equal(t, int64(1), enforcer.events["default"].dropped)
+}
@@ -0,0 +1,7 @@
+package remotewrite
+
+import "github.com/sirupsen/logrus"
+
+var logger = logrus.WithFields(logrus.Fields{
+	"component": "remote-write",
+})
@@ -0,0 +1,171 @@
+package remotewrite
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The relabeling actions, with the semantics of the relabeling of Prometheus.
+const (
+	// ActionReplace sets the target label to the replacement, expanded with
+	// the regex, if the regex matches the source labels.
+	ActionReplace = "replace"
+
+	// ActionKeep drops the series whose source labels do not match the regex.
+	ActionKeep = "keep"
+
+	// ActionDrop drops the series whose source labels match the regex.
+	ActionDrop = "drop"
+
+	// ActionLabelMap copies the labels whose name matches the regex to the
+	// labels named by the replacement, expanded with the regex.
+	ActionLabelMap = "labelmap"
+
+	// ActionLabelDrop removes the labels whose name matches the regex.
+	ActionLabelDrop = "labeldrop"
+
+	// ActionLabelKeep removes the labels whose name does not match the regex.
+	ActionLabelKeep = "labelkeep"
+)
+
+// RelabelConfig is a relabeling rule applied to the series before they are
+// written, like the write_relabel_configs of Prometheus.
+type RelabelConfig struct {
+	// SourceLabels are the labels whose values, joined with the separator,
+	// are matched by the regex.
+	SourceLabels []string `json:"source_labels"`
+
+	// Separator joins the values of the source labels. It defaults to ";".
+	Separator string `json:"separator"`
+
+	// Regex is the regular expression matched against the source labels, or
+	// the label names. It is anchored at both ends, and defaults to "(.*)".
+	Regex string `json:"regex"`
+
+	// TargetLabel is the label set by the replace action.
+	TargetLabel string `json:"target_label"`
+
+	// Replacement is the value of the target label of the replace action, or
+	// the label names of the labelmap action, where $1, $2... refer to the
+	// groups of the regex. It defaults to "$1".
+	Replacement string `json:"replacement"`
+
+	// Action is the relabeling action. It defaults to ActionReplace.
+	Action string `json:"action"`
+}
+
+// relabeler is a compiled RelabelConfig.
+type relabeler struct {
+	RelabelConfig
+	regex *regexp.Regexp
+}
+
+// compileRelabelConfigs validates the relabeling rules, and fills in their
+// defaults.
+func compileRelabelConfigs(configs []RelabelConfig) ([]relabeler, error) {
+	relabelers := make([]relabeler, 0, len(configs))
+	for i, config := range configs {
+		if config.Separator == "" {
+			config.Separator = ";"
+		}
+		if config.Regex == "" {
+			config.Regex = "(.*)"
+		}
+		if config.Replacement == "" {
+			config.Replacement = "$1"
+		}
+		if config.Action == "" {
+			config.Action = ActionReplace
+		}
+		regex, err := regexp.Compile("^(?:" + config.Regex + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("relabeling rule %d: invalid regex: %s", i, err)
+		}
+		switch config.Action {
+		case ActionReplace:
+			if config.TargetLabel == "" {
+				return nil, fmt.Errorf("relabeling rule %d: the replace action requires a target label", i)
+			}
+		case ActionKeep, ActionDrop:
+			if len(config.SourceLabels) == 0 {
+				return nil, fmt.Errorf("relabeling rule %d: the %s action requires source labels", i, config.Action)
+			}
+		case ActionLabelMap, ActionLabelDrop, ActionLabelKeep:
+		default:
+			return nil, fmt.Errorf("relabeling rule %d: unknown action %q", i, config.Action)
+		}
+		relabelers = append(relabelers, relabeler{RelabelConfig: config, regex: regex})
+	}
+	return relabelers, nil
+}
+
+// relabel applies the relabeling rules to the labels of a series, in order,
+// and returns false if the series is dropped.
+func relabel(relabelers []relabeler, labels map[string]string) bool {
+	for _, r := range relabelers {
+		if !r.apply(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply applies the relabeling rule to the labels of a series, and returns
+// false if the series is dropped.
+func (r *relabeler) apply(labels map[string]string) bool {
+	values := make([]string, 0, len(r.SourceLabels))
+	for _, name := range r.SourceLabels {
+		values = append(values, labels[name])
+	}
+	value := strings.Join(values, r.Separator)
+
+	switch r.Action {
+	case ActionKeep:
+		return r.regex.MatchString(value)
+	case ActionDrop:
+		return !r.regex.MatchString(value)
+	case ActionReplace:
+		indexes := r.regex.FindStringSubmatchIndex(value)
+		if indexes == nil {
+			return true
+		}
+		target := string(r.regex.ExpandString(nil, r.TargetLabel, value, indexes))
+		if !validLabelName(target) {
+			return true
+		}
+		replacement := string(r.regex.ExpandString(nil, r.Replacement, value, indexes))
+		if replacement == "" {
+			delete(labels, target)
+		} else {
+			labels[target] = replacement
+		}
+	case ActionLabelMap:
+		mapped := make(map[string]string)
+		for name, value := range labels {
+			indexes := r.regex.FindStringSubmatchIndex(name)
+			if indexes == nil {
+				continue
+			}
+			if target := string(r.regex.ExpandString(nil, r.Replacement, name, indexes)); validLabelName(target) {
+				mapped[target] = value
+			}
+		}
+		for name, value := range mapped {
+			labels[name] = value
+		}
+	case ActionLabelDrop:
+		for name := range labels {
+			if r.regex.MatchString(name) {
+				delete(labels, name)
+			}
+		}
+	case ActionLabelKeep:
+		for name := range labels {
+			if !r.regex.MatchString(name) {
+				delete(labels, name)
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,164 @@
+package remotewrite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelabel(t *testing.T) {
+	labels := func() map[string]string {
+		return map[string]string{
+			"__name__":          "cpu_usage",
+			"sensu_entity_name": "web-01.example.com",
+			"sensu_namespace":   "default",
+			"core":              "0",
+		}
+	}
+	tests := []struct {
+		name       string
+		configs    []RelabelConfig
+		wantKept   bool
+		wantLabels map[string]string
+	}{
+		{
+			name: "replace",
+			configs: []RelabelConfig{{
+				SourceLabels: []string{"sensu_entity_name"},
+				Regex:        `([^.]+)\..*`,
+				TargetLabel:  "instance",
+			}},
+			wantKept: true,
+			wantLabels: map[string]string{
+				"__name__":          "cpu_usage",
+				"sensu_entity_name": "web-01.example.com",
+				"sensu_namespace":   "default",
+				"core":              "0",
+				"instance":          "web-01",
+			},
+		},
+		{
+			name: "replace without match",
+			configs: []RelabelConfig{{
+				SourceLabels: []string{"sensu_entity_name"},
+				Regex:        `db-.*`,
+				TargetLabel:  "role",
+				Replacement:  "database",
+			}},
+			wantKept:   true,
+			wantLabels: labels(),
+		},
+		{
+			name: "replace with joined source labels",
+			configs: []RelabelConfig{{
+				SourceLabels: []string{"sensu_namespace", "core"},
+				Separator:    "/",
+				TargetLabel:  "id",
+			}},
+			wantKept: true,
+			wantLabels: map[string]string{
+				"__name__":          "cpu_usage",
+				"sensu_entity_name": "web-01.example.com",
+				"sensu_namespace":   "default",
+				"core":              "0",
+				"id":                "default/0",
+			},
+		},
+		{
+			name: "keep",
+			configs: []RelabelConfig{{
+				SourceLabels: []string{"__name__"},
+				Regex:        "cpu_.*",
+				Action:       ActionKeep,
+			}},
+			wantKept:   true,
+			wantLabels: labels(),
+		},
+		{
+			name: "keep without match",
+			configs: []RelabelConfig{{
+				SourceLabels: []string{"__name__"},
+				Regex:        "cpu",
+				Action:       ActionKeep,
+			}},
+		},
+		{
+			name: "drop",
+			configs: []RelabelConfig{{
+				SourceLabels: []string{"sensu_namespace"},
+				Regex:        "default",
+				Action:       ActionDrop,
+			}},
+		},
+		{
+			name: "labelmap",
+			configs: []RelabelConfig{{
+				Regex:       "sensu_(.+)",
+				Replacement: "origin_$1",
+				Action:      ActionLabelMap,
+			}},
+			wantKept: true,
+			wantLabels: map[string]string{
+				"__name__":           "cpu_usage",
+				"sensu_entity_name":  "web-01.example.com",
+				"sensu_namespace":    "default",
+				"origin_entity_name": "web-01.example.com",
+				"origin_namespace":   "default",
+				"core":               "0",
+			},
+		},
+		{
+			name:     "labeldrop",
+			configs:  []RelabelConfig{{Regex: "sensu_.*", Action: ActionLabelDrop}},
+			wantKept: true,
+			wantLabels: map[string]string{
+				"__name__": "cpu_usage",
+				"core":     "0",
+			},
+		},
+		{
+			name:     "labelkeep",
+			configs:  []RelabelConfig{{Regex: "__name__|core", Action: ActionLabelKeep}},
+			wantKept: true,
+			wantLabels: map[string]string{
+				"__name__": "cpu_usage",
+				"core":     "0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relabelers, err := compileRelabelConfigs(tt.configs)
+			require.NoError(t, err)
+			got := labels()
+			kept := relabel(relabelers, got)
+			assert.Equal(t, tt.wantKept, kept)
+			if kept {
+				assert.Equal(t, tt.wantLabels, got)
+			}
+		})
+	}
+}
+
+func TestCompileRelabelConfigs(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  RelabelConfig
+		wantErr bool
+	}{
+		{name: "defaults", config: RelabelConfig{TargetLabel: "instance"}},
+		{name: "replace without target label", config: RelabelConfig{}, wantErr: true},
+		{name: "drop without source labels", config: RelabelConfig{Action: ActionDrop}, wantErr: true},
+		{name: "invalid regex", config: RelabelConfig{TargetLabel: "instance", Regex: "("}, wantErr: true},
+		{name: "unknown action", config: RelabelConfig{Action: "hashmod"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileRelabelConfigs([]RelabelConfig{tt.config})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("compileRelabelConfigs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
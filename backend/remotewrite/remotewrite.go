@@ -0,0 +1,347 @@
+// Package remotewrite exports the metric points of the events processed by
+// the backend to a Prometheus remote-write endpoint.
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/version"
+)
+
+const (
+	// DefaultBatchSize is the maximum number of samples written in a single
+	// request.
+	DefaultBatchSize = 500
+
+	// DefaultFlushInterval is the interval at which the samples are written,
+	// when there are fewer than the batch size.
+	DefaultFlushInterval = 5 * time.Second
+
+	// DefaultBufferSize is the number of bus messages buffered by the
+	// exporter.
+	DefaultBufferSize = 1000
+
+	// RequestTimeout is the time allowed to the endpoint to respond.
+	RequestTimeout = 30 * time.Second
+
+	// MaxRetries is the number of times the requests which failed with a
+	// recoverable error, like a 5xx status, are retried.
+	MaxRetries = 3
+
+	// RetryBackoff is the delay before the first retry, doubled at each
+	// retry.
+	RetryBackoff = 500 * time.Millisecond
+
+	// SamplesCounterName is the name of the prometheus counter of the samples
+	// written to the remote-write endpoint.
+	SamplesCounterName = "sensu_go_remote_write_samples_total"
+)
+
+var samplesCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: SamplesCounterName,
+		Help: "The total number of samples written to the Prometheus remote-write endpoint",
+	},
+	[]string{"status"},
+)
+
+func init() {
+	if err := prometheus.Register(samplesCounter); err != nil {
+		panic(fmt.Errorf("error registering %s: %s", SamplesCounterName, err))
+	}
+}
+
+// Config configures an Exporter.
+type Config struct {
+	// Bus is the message bus the events are read from.
+	Bus messaging.MessageBus
+
+	// URL is the URL of the remote-write endpoint. The credentials of the
+	// URL, if any, are sent with basic authentication.
+	URL string
+
+	// RelabelConfigs are the relabeling rules applied to the series, in
+	// order, before they are written.
+	RelabelConfigs []RelabelConfig
+
+	// BatchSize is the maximum number of samples written in a single
+	// request. It defaults to DefaultBatchSize.
+	BatchSize int
+
+	// FlushInterval is the interval at which the samples are written. It
+	// defaults to DefaultFlushInterval.
+	FlushInterval time.Duration
+
+	// Client is the HTTP client of the endpoint. It defaults to a client with
+	// a timeout of RequestTimeout.
+	Client *http.Client
+}
+
+// Exporter is a daemon which writes the metric points of the events of the
+// message bus to a Prometheus remote-write endpoint, one series per point,
+// labeled with the tags of the point and the namespace, entity and check of
+// the event. The samples are written at least once per flush interval; the
+// samples that can't be written are dropped, and counted, so that a slow
+// endpoint does not hold the bus up.
+type Exporter struct {
+	bus           messaging.MessageBus
+	endpoint      string
+	relabelers    []relabeler
+	batchSize     int
+	flushInterval time.Duration
+	client        *http.Client
+	subscription  *messaging.Subscription
+	receiver      chan interface{}
+	events        chan *corev2.Event
+	ctx           context.Context
+	cancel        context.CancelFunc
+	done          chan struct{}
+	stopped       chan struct{}
+	receivers     sync.WaitGroup
+	wg            sync.WaitGroup
+	errChan       chan error
+}
+
+// New returns a new Exporter.
+func New(config Config) (*Exporter, error) {
+	if config.URL == "" {
+		return nil, errors.New("the remote-write URL is required")
+	}
+	endpoint, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote-write URL: %s", err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid remote-write URL %q: the scheme must be http or https", config.URL)
+	}
+	relabelers, err := compileRelabelConfigs(config.RelabelConfigs)
+	if err != nil {
+		return nil, err
+	}
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	flushInterval := config.FlushInterval
+	if flushInterval <= 0 {
+		flushInterval = DefaultFlushInterval
+	}
+	client := config.Client
+	if client == nil {
+		client = &http.Client{Timeout: RequestTimeout}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Exporter{
+		bus:           config.Bus,
+		endpoint:      endpoint.String(),
+		relabelers:    relabelers,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		client:        client,
+		receiver:      make(chan interface{}, DefaultBufferSize),
+		events:        make(chan *corev2.Event, DefaultBufferSize),
+		ctx:           ctx,
+		cancel:        cancel,
+		done:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+		errChan:       make(chan error, 1),
+	}, nil
+}
+
+// Receiver implements messaging.Subscriber.
+func (e *Exporter) Receiver() chan<- interface{} {
+	return e.receiver
+}
+
+// Start subscribes the Exporter to the events of the bus, and starts writing
+// their metric points.
+func (e *Exporter) Start() error {
+	subscription, err := e.bus.Subscribe(messaging.TopicEvent, "remote-write", e)
+	if err != nil {
+		return err
+	}
+	e.subscription = &subscription
+	e.receivers.Add(1)
+	go e.receive()
+	e.wg.Add(1)
+	go e.sendLoop()
+	return nil
+}
+
+// Stop the Exporter, after writing the samples received.
+func (e *Exporter) Stop() error {
+	var err error
+	if e.subscription != nil {
+		err = e.subscription.Cancel()
+	}
+	close(e.done)
+	e.receivers.Wait()
+	close(e.stopped)
+	e.wg.Wait()
+	e.cancel()
+	close(e.errChan)
+	return err
+}
+
+// Err returns a channel that the caller can use to listen for terminal errors
+// indicating a premature shutdown of the Daemon.
+func (e *Exporter) Err() <-chan error {
+	return e.errChan
+}
+
+// Name returns the daemon name
+func (e *Exporter) Name() string {
+	return "remote-write"
+}
+
+// receive forwards the events with metrics received from the bus to the send
+// loop, until the exporter is stopped.
+func (e *Exporter) receive() {
+	defer e.receivers.Done()
+	for {
+		select {
+		case <-e.done:
+			for {
+				select {
+				case msg := <-e.receiver:
+					e.forward(msg)
+				default:
+					return
+				}
+			}
+		case msg := <-e.receiver:
+			e.forward(msg)
+		}
+	}
+}
+
+// forward forwards an event with metrics to the send loop, or drops it if the
+// send loop fell behind.
+func (e *Exporter) forward(msg interface{}) {
+	var event *corev2.Event
+	switch msg := msg.(type) {
+	case *corev2.Event:
+		event = msg
+	case *messaging.EventWithPrevious:
+		event = msg.Event
+	}
+	if event == nil || !event.HasMetrics() {
+		return
+	}
+	select {
+	case e.events <- event:
+	default:
+		samplesCounter.WithLabelValues("dropped").Add(float64(len(event.Metrics.Points)))
+	}
+}
+
+// sendLoop batches the series of the events, and writes the batch when it is
+// full or at the flush interval.
+func (e *Exporter) sendLoop() {
+	defer e.wg.Done()
+	ticker := time.NewTicker(e.flushInterval)
+	defer ticker.Stop()
+	var batch []series
+	add := func(event *corev2.Event) {
+		for _, s := range eventSeries(event, e.relabelers) {
+			batch = append(batch, s)
+			if len(batch) >= e.batchSize {
+				e.send(e.ctx, batch)
+				batch = nil
+			}
+		}
+	}
+	for {
+		select {
+		case <-e.stopped:
+			// Write the samples received before stopping
+			for {
+				select {
+				case event := <-e.events:
+					add(event)
+				default:
+					e.send(e.ctx, batch)
+					return
+				}
+			}
+		case event := <-e.events:
+			add(event)
+		case <-ticker.C:
+			e.send(e.ctx, batch)
+			batch = nil
+		}
+	}
+}
+
+// send writes a batch of series to the endpoint, retrying the recoverable
+// errors. The errors are logged.
+func (e *Exporter) send(ctx context.Context, batch []series) {
+	if len(batch) == 0 {
+		return
+	}
+	body := encodeWriteRequest(batch)
+	backoff := RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := e.post(ctx, body)
+		if err == nil {
+			samplesCounter.WithLabelValues("ok").Add(float64(len(batch)))
+			return
+		}
+		var rerr recoverableError
+		if !errors.As(err, &rerr) || attempt >= MaxRetries {
+			logger.WithError(err).Errorf("failed to write %d samples", len(batch))
+			samplesCounter.WithLabelValues("error").Add(float64(len(batch)))
+			return
+		}
+		select {
+		case <-ctx.Done():
+			logger.WithError(ctx.Err()).Errorf("failed to write %d samples", len(batch))
+			samplesCounter.WithLabelValues("error").Add(float64(len(batch)))
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// recoverableError is an error of a request which can be retried.
+type recoverableError struct {
+	error
+}
+
+// post posts an encoded write request to the endpoint.
+func (e *Exporter) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "sensu-backend/"+version.Semver())
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return recoverableError{err}
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("remote-write endpoint responded with status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return recoverableError{err}
+	}
+	return err
+}
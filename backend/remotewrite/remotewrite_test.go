@@ -0,0 +1,316 @@
+package remotewrite
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// endpoint is a fake remote-write endpoint recording the series written.
+type endpoint struct {
+	mu       sync.Mutex
+	series   []series
+	requests int
+}
+
+func newEndpoint(t *testing.T) (*endpoint, *httptest.Server) {
+	e := &endpoint{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		assert.Equal(t, "0.1.0", r.Header.Get("X-Prometheus-Remote-Write-Version"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		req, err := snappy.Decode(nil, body)
+		require.NoError(t, err)
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.series = append(e.series, decodeWriteRequest(t, req)...)
+		e.requests++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	return e, server
+}
+
+func (e *endpoint) written() []series {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]series(nil), e.series...)
+}
+
+// consumeFields calls fn with the fields of a protobuf message.
+func consumeFields(t *testing.T, b []byte, fn func(num protowire.Number, typ protowire.Type, b []byte) int) {
+	t.Helper()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.True(t, n > 0, "invalid tag")
+		b = b[n:]
+		n = fn(num, typ, b)
+		require.True(t, n > 0, "invalid field %d", num)
+		b = b[n:]
+	}
+}
+
+func decodeWriteRequest(t *testing.T, b []byte) []series {
+	var list []series
+	consumeFields(t, b, func(num protowire.Number, typ protowire.Type, b []byte) int {
+		ts, n := protowire.ConsumeBytes(b)
+		var s series
+		consumeFields(t, ts, func(num protowire.Number, typ protowire.Type, b []byte) int {
+			msg, n := protowire.ConsumeBytes(b)
+			switch num {
+			case 1:
+				var l label
+				consumeFields(t, msg, func(num protowire.Number, typ protowire.Type, b []byte) int {
+					v, n := protowire.ConsumeString(b)
+					if num == 1 {
+						l.name = v
+					} else {
+						l.value = v
+					}
+					return n
+				})
+				s.labels = append(s.labels, l)
+			case 2:
+				consumeFields(t, msg, func(num protowire.Number, typ protowire.Type, b []byte) int {
+					if num == 1 {
+						v, n := protowire.ConsumeFixed64(b)
+						s.value = math.Float64frombits(v)
+						return n
+					}
+					v, n := protowire.ConsumeVarint(b)
+					s.timestamp = int64(v)
+					return n
+				})
+			}
+			return n
+		})
+		list = append(list, s)
+		return n
+	})
+	return list
+}
+
+func newBus(t *testing.T) messaging.MessageBus {
+	bus, err := messaging.NewWizardBus(messaging.WizardBusConfig{})
+	require.NoError(t, err)
+	require.NoError(t, bus.Start())
+	t.Cleanup(func() { _ = bus.Stop() })
+	return bus
+}
+
+func metricsEvent(points ...*corev2.MetricPoint) *corev2.Event {
+	event := corev2.FixtureEvent("entity", "check")
+	event.Timestamp = 1700000000
+	event.Metrics = &corev2.Metrics{Points: points}
+	return event
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{
+			name:    "no URL",
+			config:  Config{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid scheme",
+			config:  Config{URL: "ftp://localhost:9090/api/v1/write"},
+			wantErr: true,
+		},
+		{
+			name: "invalid relabeling rule",
+			config: Config{
+				URL:            "http://localhost:9090/api/v1/write",
+				RelabelConfigs: []RelabelConfig{{Action: "hashmod"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "relabeling rules",
+			config: Config{
+				URL:            "http://localhost:9090/api/v1/write",
+				RelabelConfigs: []RelabelConfig{{SourceLabels: []string{"__name__"}, Regex: "go_.*", Action: ActionDrop}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExporter(t *testing.T) {
+	endpoint, server := newEndpoint(t)
+	bus := newBus(t)
+	exporter, err := New(Config{
+		Bus: bus,
+		URL: server.URL,
+		RelabelConfigs: []RelabelConfig{
+			{SourceLabels: []string{"__name__"}, Regex: "ignored_.*", Action: ActionDrop},
+		},
+		FlushInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.NoError(t, exporter.Start())
+
+	event := metricsEvent(
+		&corev2.MetricPoint{
+			Name:      "disk.used-percent",
+			Value:     42.5,
+			Timestamp: 1700000001,
+			Tags:      []*corev2.MetricTag{{Name: "mount", Value: "/"}},
+		},
+		&corev2.MetricPoint{Name: "ignored_metric", Value: 1},
+	)
+	require.NoError(t, bus.Publish(messaging.TopicEvent, &messaging.EventWithPrevious{Event: event}))
+	// The events without metrics, and the messages that are not events, are
+	// ignored
+	require.NoError(t, bus.Publish(messaging.TopicEvent, corev2.FixtureEvent("entity", "check")))
+	require.NoError(t, bus.Publish(messaging.TopicEvent, "not an event"))
+
+	assert.Eventually(t, func() bool {
+		return len(endpoint.written()) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, exporter.Stop())
+
+	written := endpoint.written()
+	require.Len(t, written, 1)
+	assert.Equal(t, []label{
+		{name: "__name__", value: "disk_used_percent"},
+		{name: "mount", value: "/"},
+		{name: "sensu_check_name", value: "check"},
+		{name: "sensu_entity_name", value: "entity"},
+		{name: "sensu_namespace", value: "default"},
+	}, written[0].labels)
+	assert.Equal(t, 42.5, written[0].value)
+	assert.Equal(t, int64(1700000001000), written[0].timestamp)
+}
+
+func TestExporterBatches(t *testing.T) {
+	endpoint, server := newEndpoint(t)
+	bus := newBus(t)
+	exporter, err := New(Config{
+		Bus:           bus,
+		URL:           server.URL,
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+	})
+	require.NoError(t, err)
+	require.NoError(t, exporter.Start())
+
+	event := metricsEvent(
+		&corev2.MetricPoint{Name: "a", Value: 1},
+		&corev2.MetricPoint{Name: "b", Value: 2},
+		&corev2.MetricPoint{Name: "c", Value: 3},
+	)
+	require.NoError(t, bus.Publish(messaging.TopicEvent, event))
+	// The full batches are written without waiting for the flush interval,
+	// and the rest when the exporter stops
+	assert.Eventually(t, func() bool {
+		return len(endpoint.written()) == 2
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, exporter.Stop())
+
+	assert.Len(t, endpoint.written(), 3)
+	assert.Equal(t, 2, endpoint.requests)
+}
+
+func TestExporterRetries(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch r.URL.Path {
+		case "/unavailable":
+			if attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		case "/invalid":
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	batch := eventSeries(metricsEvent(&corev2.MetricPoint{Name: "a", Value: 1}), nil)
+	tests := []struct {
+		path         string
+		wantAttempts int
+	}{
+		{path: "/unavailable", wantAttempts: 2},
+		{path: "/invalid", wantAttempts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			attempts = 0
+			exporter, err := New(Config{URL: server.URL + tt.path})
+			require.NoError(t, err)
+			exporter.send(exporter.ctx, batch)
+			assert.Equal(t, tt.wantAttempts, attempts)
+		})
+	}
+}
+
+func TestEventSeries(t *testing.T) {
+	event := metricsEvent(
+		&corev2.MetricPoint{
+			Name:  "1xx.responses",
+			Value: 3,
+			Tags: []*corev2.MetricTag{
+				{Name: "sensu_entity_name", Value: "web"},
+				{Name: "status code", Value: "101"},
+				{Name: "empty", Value: ""},
+			},
+		},
+	)
+	list := eventSeries(event, nil)
+	require.Len(t, list, 1)
+	assert.Equal(t, []label{
+		{name: "__name__", value: "_1xx_responses"},
+		{name: "exported_sensu_entity_name", value: "web"},
+		{name: "sensu_check_name", value: "check"},
+		{name: "sensu_entity_name", value: "entity"},
+		{name: "sensu_namespace", value: "default"},
+		{name: "status_code", value: "101"},
+	}, list[0].labels)
+	// The points without timestamp take the one of their event
+	assert.Equal(t, int64(1700000000000), list[0].timestamp)
+}
+
+func TestTimestampMillis(t *testing.T) {
+	tests := []struct {
+		timestamp int64
+		want      int64
+	}{
+		{timestamp: 1700000000, want: 1700000000000},
+		{timestamp: 1700000000123, want: 1700000000123},
+		{timestamp: 1700000000123456, want: 1700000000123},
+		{timestamp: 1700000000123456789, want: 1700000000123},
+		{timestamp: 0, want: 1600000000000},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, timestampMillis(tt.timestamp, 1600000000))
+	}
+}
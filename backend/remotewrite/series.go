@@ -0,0 +1,192 @@
+package remotewrite
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/snappy"
+	corev2 "github.com/sensu/core/v2"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The labels identifying the origin of the series.
+const (
+	// MetricNameLabel is the label of the name of the metric.
+	MetricNameLabel = "__name__"
+
+	// NamespaceLabel is the label of the namespace of the event.
+	NamespaceLabel = "sensu_namespace"
+
+	// EntityLabel is the label of the name of the entity of the event.
+	EntityLabel = "sensu_entity_name"
+
+	// CheckLabel is the label of the name of the check of the event.
+	CheckLabel = "sensu_check_name"
+)
+
+// label is a label of a series.
+type label struct {
+	name  string
+	value string
+}
+
+// series is a time series with a single sample, as written to the endpoint.
+type series struct {
+	labels    []label
+	value     float64
+	timestamp int64
+}
+
+// eventSeries returns the series of the metric points of an event, relabeled.
+func eventSeries(event *corev2.Event, relabelers []relabeler) []series {
+	if !event.HasMetrics() {
+		return nil
+	}
+	origin := map[string]string{
+		NamespaceLabel: event.Namespace,
+	}
+	if event.Entity != nil {
+		origin[EntityLabel] = event.Entity.Name
+		if origin[NamespaceLabel] == "" {
+			origin[NamespaceLabel] = event.Entity.Namespace
+		}
+	}
+	if event.Check != nil {
+		origin[CheckLabel] = event.Check.Name
+	}
+
+	list := make([]series, 0, len(event.Metrics.Points))
+	for _, point := range event.Metrics.Points {
+		if point == nil {
+			continue
+		}
+		labels := make(map[string]string, len(point.Tags)+len(origin)+1)
+		for _, tag := range point.Tags {
+			if tag == nil || tag.Value == "" {
+				continue
+			}
+			name := sanitize(tag.Name, false)
+			// The tags named like the labels of the origin are kept, like
+			// the conflicting labels scraped by Prometheus
+			if _, ok := origin[name]; ok || name == MetricNameLabel {
+				name = "exported_" + name
+			}
+			labels[name] = tag.Value
+		}
+		for name, value := range origin {
+			if value != "" {
+				labels[name] = value
+			}
+		}
+		labels[MetricNameLabel] = sanitize(point.Name, true)
+		if !relabel(relabelers, labels) || labels[MetricNameLabel] == "" {
+			continue
+		}
+		list = append(list, series{
+			labels:    sortedLabels(labels),
+			value:     point.Value,
+			timestamp: timestampMillis(point.Timestamp, event.Timestamp),
+		})
+	}
+	return list
+}
+
+// sortedLabels returns the labels sorted by name, as expected by the
+// remote-write protocol.
+func sortedLabels(labels map[string]string) []label {
+	sorted := make([]label, 0, len(labels))
+	for name, value := range labels {
+		sorted = append(sorted, label{name: name, value: value})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].name < sorted[j].name
+	})
+	return sorted
+}
+
+// timestampMillis returns the timestamp of a metric point in milliseconds.
+// The metric points are timestamped in seconds, though some plugins use
+// milliseconds, microseconds or nanoseconds, which are told apart by their
+// magnitude. The points without timestamp take the one of their event.
+func timestampMillis(timestamp, eventTimestamp int64) int64 {
+	if timestamp <= 0 {
+		timestamp = eventTimestamp
+	}
+	switch {
+	case timestamp <= 0:
+		return time.Now().UnixNano() / int64(time.Millisecond)
+	case timestamp < 1e11:
+		return timestamp * 1e3
+	case timestamp < 1e14:
+		return timestamp
+	case timestamp < 1e17:
+		return timestamp / 1e3
+	default:
+		return timestamp / 1e6
+	}
+}
+
+// sanitize replaces the characters which are not allowed in the metric names,
+// or the label names, with underscores. Only the metric names can have
+// colons.
+func sanitize(name string, metric bool) string {
+	if name == "" {
+		return ""
+	}
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+		case r == ':' && metric:
+		default:
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// validLabelName returns whether name is a valid label name.
+func validLabelName(name string) bool {
+	return name != "" && sanitize(name, false) == name
+}
+
+// encodeWriteRequest returns the body of a remote-write request of series: a
+// prometheus.WriteRequest protobuf message, compressed with snappy.
+//
+//	message WriteRequest { repeated TimeSeries timeseries = 1; }
+//	message TimeSeries { repeated Label labels = 1; repeated Sample samples = 2; }
+//	message Label { string name = 1; string value = 2; }
+//	message Sample { double value = 1; int64 timestamp = 2; }
+func encodeWriteRequest(list []series) []byte {
+	var req, ts, msg []byte
+	for _, s := range list {
+		ts = ts[:0]
+		for _, l := range s.labels {
+			msg = msg[:0]
+			msg = protowire.AppendTag(msg, 1, protowire.BytesType)
+			msg = protowire.AppendString(msg, l.name)
+			msg = protowire.AppendTag(msg, 2, protowire.BytesType)
+			msg = protowire.AppendString(msg, l.value)
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, msg)
+		}
+		msg = msg[:0]
+		msg = protowire.AppendTag(msg, 1, protowire.Fixed64Type)
+		msg = protowire.AppendFixed64(msg, math.Float64bits(s.value))
+		msg = protowire.AppendTag(msg, 2, protowire.VarintType)
+		msg = protowire.AppendVarint(msg, uint64(s.timestamp))
+		ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+		ts = protowire.AppendBytes(ts, msg)
+
+		req = protowire.AppendTag(req, 1, protowire.BytesType)
+		req = protowire.AppendBytes(req, ts)
+	}
+	return snappy.Encode(nil, req)
+}
@@ -38,6 +38,10 @@ func (m *mockMessageBus) Subscribe(_ string, _ string, _ messaging.Subscriber) (
 	panic("should not be called")
 }
 
+func (m *mockMessageBus) SubscribePattern(_ string, _ string, _ messaging.Subscriber) (messaging.Subscription, error) {
+	panic("should not be called")
+}
+
 func (m *mockMessageBus) Publish(topic string, message interface{}) error {
 	args := m.Called(topic, message)
 	err := args.Error(0)
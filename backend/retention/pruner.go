@@ -0,0 +1,220 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sensu/sensu-go/backend/store"
+	cachev2 "github.com/sensu/sensu-go/backend/store/cache/v2"
+)
+
+const (
+	// DefaultPruneInterval is the interval at which the events are pruned.
+	DefaultPruneInterval = time.Minute
+
+	// DefaultDeletedEntityRetention is how long the deleted entities can be
+	// restored before they are purged.
+	DefaultDeletedEntityRetention = 24 * time.Hour
+
+	// PrunedEventsCounterName is the name of the prometheus counter of the
+	// events pruned by the retention policies.
+	PrunedEventsCounterName = "sensu_go_retention_pruned_events_total"
+)
+
+var prunedEventsCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: PrunedEventsCounterName,
+		Help: "The total number of events pruned by the event retention policies",
+	},
+	[]string{"namespace"},
+)
+
+func init() {
+	if err := prometheus.Register(prunedEventsCounter); err != nil {
+		panic(fmt.Errorf("error registering %s: %s", PrunedEventsCounterName, err))
+	}
+}
+
+// PolicyGetter gets the event retention policies of all the namespaces.
+type PolicyGetter interface {
+	GetAll() []cachev2.Value[*EventRetentionPolicy, EventRetentionPolicy]
+}
+
+// Limits are the limits of the events kept in a namespace, combined from its
+// policies. A limit of 0 means that the events are not limited.
+type Limits struct {
+	MaxAge    int64
+	MaxEvents int
+}
+
+// PrunerConfig configures a Pruner.
+type PrunerConfig struct {
+	// Policies are the event retention policies enforced by the pruner.
+	Policies PolicyGetter
+
+	// EventStore is the store of the pruned events. It must implement
+	// store.EventPruner.
+	EventStore store.EventStore
+
+	// EntityStore is the store of the purged entities. The deleted entities
+	// are only purged if it implements store.EntityUndeleter.
+	EntityStore store.EntityStore
+
+	// DeletedEntityRetention is how long the deleted entities are kept before
+	// they are purged. It defaults to DefaultDeletedEntityRetention.
+	DeletedEntityRetention time.Duration
+
+	// Interval is the interval at which the events are pruned. It defaults
+	// to DefaultPruneInterval.
+	Interval time.Duration
+}
+
+// Pruner is a daemon which periodically prunes the events of the namespaces
+// with event retention policies, and purges the entities deleted for longer
+// than the deleted entity retention. Pruning is idempotent, so the backends of
+// a cluster all prune the events.
+type Pruner struct {
+	policies               PolicyGetter
+	pruner                 store.EventPruner
+	undeleter              store.EntityUndeleter
+	deletedEntityRetention time.Duration
+	interval               time.Duration
+	ctx                    context.Context
+	cancel                 context.CancelFunc
+	wg                     sync.WaitGroup
+	errChan                chan error
+}
+
+// NewPruner returns a new Pruner.
+func NewPruner(config PrunerConfig) (*Pruner, error) {
+	pruner, ok := config.EventStore.(store.EventPruner)
+	if !ok {
+		return nil, errors.New("the event store does not support pruning events")
+	}
+	interval := config.Interval
+	if interval <= 0 {
+		interval = DefaultPruneInterval
+	}
+	undeleter, _ := config.EntityStore.(store.EntityUndeleter)
+	deletedEntityRetention := config.DeletedEntityRetention
+	if deletedEntityRetention <= 0 {
+		deletedEntityRetention = DefaultDeletedEntityRetention
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Pruner{
+		policies:               config.Policies,
+		pruner:                 pruner,
+		undeleter:              undeleter,
+		deletedEntityRetention: deletedEntityRetention,
+		interval:               interval,
+		ctx:                    ctx,
+		cancel:                 cancel,
+		errChan:                make(chan error, 1),
+	}, nil
+}
+
+// Start the Pruner.
+func (p *Pruner) Start() error {
+	p.wg.Add(1)
+	go p.pruneLoop()
+	return nil
+}
+
+// Stop the Pruner.
+func (p *Pruner) Stop() error {
+	p.cancel()
+	p.wg.Wait()
+	close(p.errChan)
+	return nil
+}
+
+// Err returns a channel that the caller can use to listen for terminal errors
+// indicating a premature shutdown of the Daemon.
+func (p *Pruner) Err() <-chan error {
+	return p.errChan
+}
+
+// Name returns the daemon name
+func (p *Pruner) Name() string {
+	return "retention"
+}
+
+func (p *Pruner) pruneLoop() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.Prune(p.ctx, time.Now())
+			p.PurgeDeletedEntities(p.ctx, time.Now())
+		}
+	}
+}
+
+// Limits returns the limits of the events of each namespace with event
+// retention policies, which are the lowest of the limits of its policies.
+func (p *Pruner) Limits() map[string]Limits {
+	limits := make(map[string]Limits)
+	for _, value := range p.policies.GetAll() {
+		policy := value.Resource
+		namespace := policy.Metadata.Namespace
+		l := limits[namespace]
+		if max := policy.MaxAge; max > 0 && (l.MaxAge == 0 || max < l.MaxAge) {
+			l.MaxAge = max
+		}
+		if max := policy.MaxEvents; max > 0 && (l.MaxEvents == 0 || max < l.MaxEvents) {
+			l.MaxEvents = max
+		}
+		limits[namespace] = l
+	}
+	return limits
+}
+
+// Prune prunes the events of the namespaces with event retention policies,
+// as of the given time. The errors are logged, and do not prevent the events
+// of the other namespaces from being pruned.
+func (p *Pruner) Prune(ctx context.Context, now time.Time) {
+	for namespace, limits := range p.Limits() {
+		var before int64
+		if limits.MaxAge > 0 {
+			before = now.Unix() - limits.MaxAge
+		}
+		pruned, err := p.pruner.PruneEvents(ctx, namespace, before, limits.MaxEvents)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.WithError(err).WithField("namespace", namespace).Error("could not prune events")
+			continue
+		}
+		if pruned > 0 {
+			logger.WithField("namespace", namespace).Debugf("pruned %d events", pruned)
+			prunedEventsCounter.WithLabelValues(namespace).Add(float64(pruned))
+		}
+	}
+}
+
+// PurgeDeletedEntities purges the entities deleted for longer than the deleted
+// entity retention, as of the given time. The errors are logged.
+func (p *Pruner) PurgeDeletedEntities(ctx context.Context, now time.Time) {
+	if p.undeleter == nil {
+		return
+	}
+	purged, err := p.undeleter.PurgeDeletedEntities(ctx, now.Add(-p.deletedEntityRetention))
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.WithError(err).Error("could not purge deleted entities")
+		}
+		return
+	}
+	if purged > 0 {
+		logger.Debugf("purged %d deleted entities", purged)
+	}
+}
@@ -0,0 +1,113 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	cachev2 "github.com/sensu/sensu-go/backend/store/cache/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pruneCall struct {
+	before int64
+	max    int
+}
+
+type testEventStore struct {
+	store.EventStore
+	calls map[string]pruneCall
+	err   error
+}
+
+func (s *testEventStore) PruneEvents(ctx context.Context, namespace string, before int64, max int) (int64, error) {
+	s.calls[namespace] = pruneCall{before: before, max: max}
+	return 1, s.err
+}
+
+func newTestPruner(t *testing.T, s store.EventStore, policies ...*EventRetentionPolicy) *Pruner {
+	t.Helper()
+	pruner, err := NewPruner(PrunerConfig{
+		Policies:   cachev2.NewFromResources(policies, false),
+		EventStore: s,
+	})
+	require.NoError(t, err)
+	return pruner
+}
+
+func TestNewPrunerUnsupportedStore(t *testing.T) {
+	_, err := NewPruner(PrunerConfig{EventStore: struct{ store.EventStore }{}})
+	assert.Error(t, err)
+}
+
+func TestPrunerLimits(t *testing.T) {
+	pruner := newTestPruner(t, &testEventStore{},
+		&EventRetentionPolicy{Metadata: corev2.NewObjectMetaP("a", "default"), MaxAge: 3600, MaxEvents: 100},
+		&EventRetentionPolicy{Metadata: corev2.NewObjectMetaP("b", "default"), MaxAge: 60},
+		&EventRetentionPolicy{Metadata: corev2.NewObjectMetaP("c", "other"), MaxEvents: 10},
+	)
+	assert.Equal(t, map[string]Limits{
+		"default": {MaxAge: 60, MaxEvents: 100},
+		"other":   {MaxEvents: 10},
+	}, pruner.Limits())
+}
+
+func TestPrunerPrune(t *testing.T) {
+	s := &testEventStore{calls: make(map[string]pruneCall)}
+	pruner := newTestPruner(t, s,
+		&EventRetentionPolicy{Metadata: corev2.NewObjectMetaP("a", "default"), MaxAge: 60},
+		&EventRetentionPolicy{Metadata: corev2.NewObjectMetaP("b", "other"), MaxEvents: 10},
+	)
+	now := time.Unix(1000, 0)
+	pruner.Prune(context.Background(), now)
+	assert.Equal(t, map[string]pruneCall{
+		"default": {before: 940},
+		"other":   {max: 10},
+	}, s.calls)
+
+	// The errors of a namespace do not prevent the others from being pruned
+	s.calls = make(map[string]pruneCall)
+	s.err = errors.New("error")
+	pruner.Prune(context.Background(), now)
+	assert.Len(t, s.calls, 2)
+}
+
+type testEntityStore struct {
+	store.EntityStore
+	before time.Time
+}
+
+func (s *testEntityStore) UndeleteEntity(ctx context.Context, name string) error {
+	return nil
+}
+
+func (s *testEntityStore) PurgeDeletedEntities(ctx context.Context, before time.Time) (int64, error) {
+	s.before = before
+	return 1, nil
+}
+
+func TestPrunerPurgeDeletedEntities(t *testing.T) {
+	entities := &testEntityStore{}
+	pruner, err := NewPruner(PrunerConfig{
+		Policies:    cachev2.NewFromResources([]*EventRetentionPolicy{}, false),
+		EventStore:  &testEventStore{},
+		EntityStore: entities,
+	})
+	require.NoError(t, err)
+	now := time.Unix(100000, 0)
+	pruner.PurgeDeletedEntities(context.Background(), now)
+	assert.Equal(t, now.Add(-DefaultDeletedEntityRetention), entities.before)
+
+	// The entity stores which cannot purge the deleted entities are ignored
+	pruner, err = NewPruner(PrunerConfig{
+		Policies:    cachev2.NewFromResources([]*EventRetentionPolicy{}, false),
+		EventStore:  &testEventStore{},
+		EntityStore: struct{ store.EntityStore }{},
+	})
+	require.NoError(t, err)
+	pruner.PurgeDeletedEntities(context.Background(), now)
+}
@@ -0,0 +1,120 @@
+// Package retention prunes the stored events of the namespaces. The events
+// kept are set by the EventRetentionPolicy resources, and enforced
+// periodically by the Pruner, which also purges the deleted entities once
+// they can no longer be restored.
+package retention
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	apitools "github.com/sensu/sensu-api-tools"
+)
+
+const (
+	// APIGroup is the API group of the retention resources.
+	APIGroup = "retention"
+
+	// APIVersion is the API version of the retention resources.
+	APIVersion = "v1"
+
+	// EventRetentionPoliciesResource is the RBAC name of the event retention
+	// policies.
+	EventRetentionPoliciesResource = "eventretentionpolicies"
+)
+
+func init() {
+	apitools.RegisterType(path.Join(APIGroup, APIVersion), new(EventRetentionPolicy))
+}
+
+var _ corev3.Resource = new(EventRetentionPolicy)
+
+// EventRetentionPolicy limits the events kept in its namespace. A limit of 0
+// means that the events are not limited. When a namespace has several
+// policies, the lowest of their limits applies. Keepalive events are never
+// pruned.
+type EventRetentionPolicy struct {
+	// Metadata contains the name, namespace, labels and annotations of the
+	// policy.
+	Metadata *corev2.ObjectMeta `json:"metadata"`
+
+	// MaxAge is the number of seconds after which the events which have not
+	// been updated are pruned.
+	MaxAge int64 `json:"max_age,omitempty"`
+
+	// MaxEvents is the maximum number of events of the namespace. The events
+	// updated last are kept.
+	MaxEvents int `json:"max_events,omitempty"`
+}
+
+// GetMetadata returns the metadata of the policy.
+func (p *EventRetentionPolicy) GetMetadata() *corev2.ObjectMeta {
+	return p.Metadata
+}
+
+// SetMetadata sets the metadata of the policy.
+func (p *EventRetentionPolicy) SetMetadata(meta *corev2.ObjectMeta) {
+	p.Metadata = meta
+}
+
+// StoreName returns the name of the policies in the store.
+func (p *EventRetentionPolicy) StoreName() string {
+	return "event_retention_policies"
+}
+
+// RBACName returns the name of the policies for RBAC purposes.
+func (p *EventRetentionPolicy) RBACName() string {
+	return EventRetentionPoliciesResource
+}
+
+// URIPath returns the path of the policy in the API.
+func (p *EventRetentionPolicy) URIPath() string {
+	if p.Metadata == nil {
+		return path.Join("/api", APIGroup, APIVersion, EventRetentionPoliciesResource)
+	}
+	return path.Join("/api", APIGroup, APIVersion, "namespaces", url.PathEscape(p.Metadata.Namespace),
+		EventRetentionPoliciesResource, url.PathEscape(p.Metadata.Name))
+}
+
+// GetTypeMeta returns the type and API version of the policies.
+func (p *EventRetentionPolicy) GetTypeMeta() corev2.TypeMeta {
+	return corev2.TypeMeta{
+		Type:       "EventRetentionPolicy",
+		APIVersion: path.Join(APIGroup, APIVersion),
+	}
+}
+
+// Validate the policy.
+func (p *EventRetentionPolicy) Validate() error {
+	if p.Metadata == nil {
+		return errors.New("nil metadata")
+	}
+	if err := corev2.ValidateName(p.Metadata.Name); err != nil {
+		return fmt.Errorf("the event retention policy name %s", err)
+	}
+	if p.Metadata.Namespace == "" {
+		return errors.New("an event retention policy must have a namespace")
+	}
+	if p.MaxAge < 0 || p.MaxEvents < 0 {
+		return errors.New("the limits of an event retention policy cannot be negative")
+	}
+	return nil
+}
+
+// EventRetentionPolicyFields returns the fields of the policy available to
+// selectors.
+func EventRetentionPolicyFields(r corev3.Resource) map[string]string {
+	policy := r.(*EventRetentionPolicy)
+	fields := map[string]string{
+		"eventretentionpolicy.name":      policy.Metadata.Name,
+		"eventretentionpolicy.namespace": policy.Metadata.Namespace,
+	}
+	for key, value := range policy.Metadata.Labels {
+		fields["eventretentionpolicy.labels."+key] = value
+	}
+	return fields
+}
@@ -0,0 +1,25 @@
+package retention
+
+import (
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventRetentionPolicyValidate(t *testing.T) {
+	policy := &EventRetentionPolicy{Metadata: corev2.NewObjectMetaP("policy", "default"), MaxAge: 3600}
+	assert.NoError(t, policy.Validate())
+
+	policy.Metadata.Namespace = ""
+	assert.Error(t, policy.Validate())
+
+	policy.Metadata.Namespace = "default"
+	policy.MaxEvents = -1
+	assert.Error(t, policy.Validate())
+}
+
+func TestEventRetentionPolicyURIPath(t *testing.T) {
+	policy := &EventRetentionPolicy{Metadata: corev2.NewObjectMetaP("policy", "default")}
+	assert.Equal(t, "/api/retention/v1/namespaces/default/eventretentionpolicies/policy", policy.URIPath())
+}
@@ -3,6 +3,7 @@ package ringv2
 import (
 	"context"
 	"errors"
+	"strconv"
 	"strings"
 
 	"github.com/robfig/cron/v3"
@@ -23,6 +24,53 @@ func DeleteEntityContext(ctx context.Context) context.Context {
 	return context.WithValue(ctx, DeleteEntityContextKey, struct{}{})
 }
 
+// WeightLabel is the entity label that sets the weight of an entity in the
+// round-robin rings it belongs to. An entity with a weight of 2 receives twice
+// as many executions as an entity with the default weight of 1.
+const WeightLabel = "roundrobin-weight"
+
+// MaxWeight is the largest weight an entity can have in a ring.
+const MaxWeight = 100
+
+type weightContextKeyT struct{}
+
+// WeightContextKey can be set to tell the ring implementation the weight of
+// the item being added.
+var WeightContextKey = weightContextKeyT{}
+
+// WeightContext modifies a context with the weight of the item that is being
+// added to a ring.
+func WeightContext(ctx context.Context, weight int) context.Context {
+	return context.WithValue(ctx, WeightContextKey, weight)
+}
+
+// WeightFromContext returns the weight stored in the context by WeightContext,
+// clamped to [1, MaxWeight]. It returns 1 if the context has no weight.
+func WeightFromContext(ctx context.Context) int {
+	weight, _ := ctx.Value(WeightContextKey).(int)
+	return clampWeight(weight)
+}
+
+// EntityWeight returns the weight set by the WeightLabel of the entity labels,
+// clamped to [1, MaxWeight]. It returns 1 if the label is missing or invalid.
+func EntityWeight(labels map[string]string) int {
+	weight, err := strconv.Atoi(labels[WeightLabel])
+	if err != nil {
+		return 1
+	}
+	return clampWeight(weight)
+}
+
+func clampWeight(weight int) int {
+	if weight < 1 {
+		return 1
+	}
+	if weight > MaxWeight {
+		return MaxWeight
+	}
+	return weight
+}
+
 // Event represents an event that occurred in a ring. The event can originate
 // from any ring client.
 type Event struct {
@@ -81,7 +129,9 @@ type Interface interface {
 
 	// Add adds an item to the ring, with a keepalive in seconds. After the
 	// first Add, Add must be called before the keepalive expires, or the ring
-	// item will be removed from the ring.
+	// item will be removed from the ring. The weight of the item, set with
+	// WeightContext, determines how many times the item is visited per
+	// iteration of the ring.
 	Add(ctx context.Context, value string, keepalive int64) error
 
 	// IsEmpty returns true if the ring is empty.
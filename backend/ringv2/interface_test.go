@@ -1,6 +1,9 @@
 package ringv2
 
-import "testing"
+import (
+	"context"
+	"testing"
+)
 
 func TestSubscriptionValidate(t *testing.T) {
 	tests := []struct {
@@ -83,3 +86,35 @@ func TestSubscriptionValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestEntityWeight(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Labels map[string]string
+		Want   int
+	}{
+		{Name: "no labels", Want: 1},
+		{Name: "no weight label", Labels: map[string]string{"foo": "bar"}, Want: 1},
+		{Name: "invalid weight", Labels: map[string]string{WeightLabel: "heavy"}, Want: 1},
+		{Name: "zero weight", Labels: map[string]string{WeightLabel: "0"}, Want: 1},
+		{Name: "negative weight", Labels: map[string]string{WeightLabel: "-3"}, Want: 1},
+		{Name: "weight", Labels: map[string]string{WeightLabel: "3"}, Want: 3},
+		{Name: "weight too large", Labels: map[string]string{WeightLabel: "1000"}, Want: MaxWeight},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if got := EntityWeight(test.Labels); got != test.Want {
+				t.Errorf("bad weight: got %d, want %d", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestWeightFromContext(t *testing.T) {
+	if got, want := WeightFromContext(context.Background()), 1; got != want {
+		t.Errorf("bad weight: got %d, want %d", got, want)
+	}
+	if got, want := WeightFromContext(WeightContext(context.Background(), 5)), 5; got != want {
+		t.Errorf("bad weight: got %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,134 @@
+package ringv2
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var _ Interface = &MemoryRing{}
+
+// MemoryRing is a ring kept in memory, for the backends which are not part
+// of a cluster. The ring is ordered by item, and each subscription iterates
+// over it independently.
+type MemoryRing struct {
+	mu    sync.Mutex
+	items map[string]memoryItem
+	next  map[string]int
+}
+
+type memoryItem struct {
+	expiresAt time.Time
+	weight    int
+}
+
+// NewMemoryRing returns an empty MemoryRing.
+func NewMemoryRing() *MemoryRing {
+	return &MemoryRing{
+		items: make(map[string]memoryItem),
+		next:  make(map[string]int),
+	}
+}
+
+// Subscribe sends the items of the ring on the schedule of the subscription,
+// the first ones immediately, until the context is done.
+func (r *MemoryRing) Subscribe(ctx context.Context, sub Subscription) <-chan Event {
+	if err := sub.Validate(); err != nil {
+		panic(err)
+	}
+	ch := make(chan Event, 1)
+	go func() {
+		defer close(ch)
+		var schedule cron.Schedule
+		if sub.CronSchedule != "" {
+			schedule, _ = cron.ParseStandard(sub.CronSchedule)
+		}
+		for {
+			select {
+			case ch <- Event{Type: EventTrigger, Values: r.advance(sub)}:
+			case <-ctx.Done():
+				return
+			}
+			wait := time.Duration(sub.IntervalSchedule) * time.Second
+			if schedule != nil {
+				wait = time.Until(schedule.Next(time.Now()))
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}()
+	return ch
+}
+
+// advance returns the next items of the subscription, repeating the items
+// when the ring has fewer of them, and moves the subscription past them.
+func (r *MemoryRing) advance(sub Subscription) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	var values []string
+	for value, item := range r.items {
+		if now.After(item.expiresAt) {
+			delete(r.items, value)
+			continue
+		}
+		values = append(values, value)
+	}
+	if len(values) == 0 {
+		return []string{}
+	}
+	sort.Strings(values)
+	var ring []string
+	for _, value := range values {
+		for i := 0; i < r.items[value].weight; i++ {
+			ring = append(ring, value)
+		}
+	}
+	next := r.next[sub.Name]
+	result := make([]string, 0, sub.Items)
+	for i := 0; i < sub.Items; i++ {
+		result = append(result, ring[(next+i)%len(ring)])
+	}
+	r.next[sub.Name] = (next + sub.Items) % len(ring)
+	return result
+}
+
+// Remove removes an item from the ring.
+func (r *MemoryRing) Remove(ctx context.Context, value string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, value)
+	return nil
+}
+
+// Add adds an item to the ring, or renews its keepalive.
+func (r *MemoryRing) Add(ctx context.Context, value string, keepalive int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items[value] = memoryItem{
+		expiresAt: time.Now().Add(time.Duration(keepalive) * time.Second),
+		weight:    WeightFromContext(ctx),
+	}
+	return nil
+}
+
+// IsEmpty returns true if the ring has no item which has not expired.
+func (r *MemoryRing) IsEmpty(ctx context.Context) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, item := range r.items {
+		if !now.After(item.expiresAt) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
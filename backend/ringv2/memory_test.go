@@ -0,0 +1,55 @@
+package ringv2
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestMemoryRing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ring := NewMemoryRing()
+
+	empty, err := ring.IsEmpty(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !empty {
+		t.Fatal("ring not empty")
+	}
+
+	for _, value := range []string{"c", "a"} {
+		if err := ring.Add(ctx, value, 60); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ring.Add(WeightContext(ctx, 2), "b", 60); err != nil {
+		t.Fatal(err)
+	}
+	// Expired items are not part of the ring
+	if err := ring.Add(ctx, "d", -1); err != nil {
+		t.Fatal(err)
+	}
+
+	sub := Subscription{Name: "check", Items: 3, IntervalSchedule: 60}
+	events := ring.Subscribe(ctx, sub)
+	event := <-events
+	if got, want := event.Values, []string{"a", "b", "b"}; event.Type != EventTrigger || !reflect.DeepEqual(got, want) {
+		t.Fatalf("bad event: got %v %v, want %v", event.Type, got, want)
+	}
+	if got, want := ring.advance(sub), []string{"c", "a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("bad values: got %v, want %v", got, want)
+	}
+
+	if err := ring.Remove(ctx, "b"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ring.advance(Subscription{Name: "other", Items: 3}), []string{"a", "c", "a"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("bad values: got %v, want %v", got, want)
+	}
+
+	cancel()
+	for range events {
+	}
+}
@@ -1,6 +1,7 @@
 package ringv2
 
 import (
+	"context"
 	"sync"
 )
 
@@ -10,16 +11,18 @@ type NewFunc func(path string) Interface
 // needed. RingPool supercedes Pool, by using the Interface type instead of a
 // *Ring.
 type RingPool struct {
-	newf  NewFunc
-	rings map[string]Interface
-	mu    sync.Mutex
+	newf        NewFunc
+	rings       map[string]Interface
+	subscribers map[string]int
+	mu          sync.Mutex
 }
 
 // NewRingPool creates a new RingPool.
 func NewRingPool(fn NewFunc) *RingPool {
 	return &RingPool{
-		newf:  fn,
-		rings: make(map[string]Interface),
+		newf:        fn,
+		rings:       make(map[string]Interface),
+		subscribers: make(map[string]int),
 	}
 }
 
@@ -51,3 +54,30 @@ func (r *RingPool) SetNewFunc(fn NewFunc) {
 	r.newf = fn
 	r.rings = make(map[string]Interface, len(r.rings))
 }
+
+// Subscribe subscribes to the ring corresponding to the given path, until the
+// context is done. The ring is reported as subscribed by Subscribed meanwhile.
+func (r *RingPool) Subscribe(ctx context.Context, path string, sub Subscription) <-chan Event {
+	ring := r.Get(path)
+	r.mu.Lock()
+	r.subscribers[path]++
+	r.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.subscribers[path]--
+		if r.subscribers[path] <= 0 {
+			delete(r.subscribers, path)
+		}
+	}()
+	return ring.Subscribe(ctx, sub)
+}
+
+// Subscribed returns true if the ring corresponding to the given path is
+// subscribed to through the pool, i.e. if it is in use by this backend.
+func (r *RingPool) Subscribed(path string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.subscribers[path] > 0
+}
@@ -3,6 +3,7 @@ package ringv2
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -44,3 +45,30 @@ func TestPoolSetNewFunc(t *testing.T) {
 		t.Fatal("rings should differ")
 	}
 }
+
+func TestPoolSubscribed(t *testing.T) {
+	pool := NewRingPool(func(path string) Interface {
+		return NewMemoryRing()
+	})
+	if pool.Subscribed("foo") {
+		t.Fatal("ring should not be subscribed")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_ = pool.Subscribe(ctx, "foo", Subscription{Name: "check", Items: 1, IntervalSchedule: 60})
+	if !pool.Subscribed("foo") {
+		t.Fatal("ring should be subscribed")
+	}
+	if pool.Subscribed("bar") {
+		t.Fatal("ring should not be subscribed")
+	}
+
+	cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for pool.Subscribed("foo") {
+		if time.Now().After(deadline) {
+			t.Fatal("ring should not be subscribed after the subscription is canceled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -61,6 +61,9 @@ func (a *AdhocScheduler) schedule() {
 		}
 		logger.WithFields(logFields).Debug("attempting to schedule ad hoc check")
 
+		// The ad hoc checks are executed even during their blackout periods,
+		// see BlackoutAnnotation
+
 		if err := a.executor.processCheck(ctx, &check); err != nil {
 			logger.WithError(err).WithFields(logFields).Error("error processing adhoc check request")
 			if nackErr := res.Nack(ctx); nackErr != nil {
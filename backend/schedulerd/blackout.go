@@ -0,0 +1,106 @@
+package schedulerd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store/v2/wrap"
+)
+
+// BlackoutAnnotation is the check annotation that holds the blackout periods
+// of the check. Its value is a list of standard cron expressions, separated by
+// semicolons or newlines. While the current minute matches any of the
+// expressions, schedulerd does not schedule the check at all, with interval,
+// cron or round-robin schedules.
+//
+// The ad hoc executions of the check are not blacked out: like they execute
+// unpublished and subdued checks, they are explicit requests of the users,
+// e.g. to verify a check during its maintenance window.
+//
+// For instance, "* 2-3 * * *; * * * * 0" blacks the check out between 02:00
+// and 03:59 every day, and all day on Sundays.
+const BlackoutAnnotation = "sensu.io/blackout"
+
+func init() {
+	wrap.RegisterValidator(&corev2.CheckConfig{}, func(resource interface{}) error {
+		return ValidateCheck(resource.(*corev2.CheckConfig))
+	})
+}
+
+// ValidateCheck validates a check, including its blackout annotation, which
+// the checks of core/v2 do not know about.
+func ValidateCheck(check *corev2.CheckConfig) error {
+	if err := check.Validate(); err != nil {
+		return err
+	}
+	if value, ok := check.Annotations[BlackoutAnnotation]; ok {
+		if _, err := ParseBlackout(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseBlackout parses a blackout annotation value into its cron schedules.
+func ParseBlackout(value string) ([]cron.Schedule, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ';' || r == '\n'
+	})
+	schedules := make([]cron.Schedule, 0, len(fields))
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		schedule, err := cron.ParseStandard(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blackout period %q: %s", field, err)
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+// InBlackout returns true if the minute containing now is matched by any of
+// the schedules.
+func InBlackout(schedules []cron.Schedule, now time.Time) bool {
+	minute := now.Truncate(time.Minute)
+	for _, schedule := range schedules {
+		if schedule.Next(minute.Add(-time.Second)).Equal(minute) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkInBlackout returns true if the check must not be scheduled at now,
+// according to its blackout annotation.
+func checkInBlackout(check *corev2.CheckConfig, now time.Time) (bool, error) {
+	value, ok := check.Annotations[BlackoutAnnotation]
+	if !ok {
+		return false, nil
+	}
+	schedules, err := ParseBlackout(value)
+	if err != nil {
+		return false, err
+	}
+	return InBlackout(schedules, now), nil
+}
+
+// isBlackedOut reports whether the check is in one of its blackout periods.
+// Invalid blackout periods are rejected when the check is written, but those
+// of the checks stored before are logged and otherwise ignored, so that a typo
+// in the annotation does not stop the check from running.
+func isBlackedOut(check *corev2.CheckConfig, logger *logrus.Entry) bool {
+	blackedOut, err := checkInBlackout(check, time.Now())
+	if err != nil {
+		logger.WithError(err).Error("error evaluating check blackout periods")
+		return false
+	}
+	return blackedOut
+}
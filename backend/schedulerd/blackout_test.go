@@ -0,0 +1,120 @@
+package schedulerd
+
+import (
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store/v2/wrap"
+)
+
+func TestParseBlackout(t *testing.T) {
+	schedules, err := ParseBlackout("* 2-3 * * *; * * * * 0\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(schedules), 2; got != want {
+		t.Fatalf("bad number of schedules: got %d, want %d", got, want)
+	}
+	if _, err := ParseBlackout("* 2-3 * * *; invalid"); err == nil {
+		t.Fatal("expected non-nil error")
+	}
+}
+
+func TestCheckInBlackout(t *testing.T) {
+	// 2021-06-02 is a Wednesday
+	tests := []struct {
+		Name     string
+		Blackout *string
+		Now      time.Time
+		Want     bool
+		WantErr  bool
+	}{
+		{
+			Name: "no blackout",
+			Now:  time.Date(2021, 6, 2, 2, 30, 0, 0, time.UTC),
+		},
+		{
+			Name:     "inside window",
+			Blackout: stringPtr("* 2-3 * * *"),
+			Now:      time.Date(2021, 6, 2, 2, 30, 15, 0, time.UTC),
+			Want:     true,
+		},
+		{
+			Name:     "last minute of window",
+			Blackout: stringPtr("* 2-3 * * *"),
+			Now:      time.Date(2021, 6, 2, 3, 59, 59, 0, time.UTC),
+			Want:     true,
+		},
+		{
+			Name:     "outside window",
+			Blackout: stringPtr("* 2-3 * * *"),
+			Now:      time.Date(2021, 6, 2, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:     "second window",
+			Blackout: stringPtr("* 2-3 * * *; * * * * 3"),
+			Now:      time.Date(2021, 6, 2, 12, 0, 0, 0, time.UTC),
+			Want:     true,
+		},
+		{
+			Name:     "timezone",
+			Blackout: stringPtr("CRON_TZ=Asia/Tokyo * 2 * * *"),
+			Now:      time.Date(2021, 6, 2, 17, 15, 0, 0, time.UTC),
+			Want:     true,
+		},
+		{
+			Name:     "invalid",
+			Blackout: stringPtr("every sunday"),
+			Now:      time.Date(2021, 6, 2, 2, 30, 0, 0, time.UTC),
+			WantErr:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			check := corev2.FixtureCheckConfig("check")
+			if test.Blackout != nil {
+				check.Annotations = map[string]string{BlackoutAnnotation: *test.Blackout}
+			}
+			got, err := checkInBlackout(check, test.Now)
+			if (err != nil) != test.WantErr {
+				t.Fatalf("bad error: %v", err)
+			}
+			if got != test.Want {
+				t.Errorf("bad blackout: got %v, want %v", got, test.Want)
+			}
+		})
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestValidateCheck(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Blackout *string
+		WantErr  bool
+	}{
+		{Name: "no blackout"},
+		{Name: "valid blackout", Blackout: stringPtr("* 2-3 * * *; * * * * 0")},
+		{Name: "invalid blackout", Blackout: stringPtr("* 2-3 * * *; invalid"), WantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			check := corev2.FixtureCheckConfig("check")
+			if test.Blackout != nil {
+				check.Annotations = map[string]string{BlackoutAnnotation: *test.Blackout}
+			}
+			if err := ValidateCheck(check); (err != nil) != test.WantErr {
+				t.Errorf("ValidateCheck() error = %v, wantErr %v", err, test.WantErr)
+			}
+			// The checks are validated the same way when they are written
+			// to the store
+			if _, err := wrap.Resource(check); (err != nil) != test.WantErr {
+				t.Errorf("wrap.Resource() error = %v, wantErr %v", err, test.WantErr)
+			}
+		})
+	}
+}
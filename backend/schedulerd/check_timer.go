@@ -22,10 +22,18 @@ type CheckTimer interface {
 	Stop()
 }
 
-// A IntervalTimer handles starting a stopping timers for a given check
+// A IntervalTimer handles starting a stopping timers for a given check.
+//
+// Executions are scheduled at absolute timestamps of the form
+// anchor + n*interval, rather than by re-arming the timer with the interval
+// after each execution. This keeps executions aligned over long periods of
+// time, and across backend restarts, regardless of how long each execution
+// takes to process.
 type IntervalTimer struct {
 	interval time.Duration
 	splay    uint64
+	anchor   time.Time
+	next     time.Time
 	timer    *time.Timer
 }
 
@@ -41,6 +49,13 @@ func NewIntervalTimer(name string, interval uint) *IntervalTimer {
 	return timer
 }
 
+// SetAnchor sets the point in time that executions are aligned to, typically
+// the creation time of the check. When no anchor is set, executions are
+// aligned to the splay derived from the check name.
+func (timerPtr *IntervalTimer) SetAnchor(anchor time.Time) {
+	timerPtr.anchor = anchor
+}
+
 // C channel emits events when timer's duration has reached 0
 func (timerPtr *IntervalTimer) C() <-chan time.Time {
 	return timerPtr.timer.C
@@ -59,7 +74,14 @@ func (timerPtr *IntervalTimer) Start() {
 
 // Next reset's timer using interval
 func (timerPtr *IntervalTimer) Next() {
-	if !timerPtr.timer.Reset(timerPtr.interval) {
+	now := time.Now()
+	timerPtr.next = timerPtr.next.Add(timerPtr.interval)
+	if !timerPtr.next.After(now) {
+		// One or more executions were missed, skip ahead to the next
+		// aligned execution instead of firing in quick succession.
+		timerPtr.next = timerPtr.nextExecution(now)
+	}
+	if !timerPtr.timer.Reset(timerPtr.next.Sub(now)) {
 		select {
 		case <-timerPtr.timer.C:
 		default:
@@ -77,12 +99,33 @@ func (timerPtr *IntervalTimer) Stop() {
 	}
 }
 
-// Calculate the first execution time using splay & interval
+// Calculate the first execution time using the anchor (or splay) & interval
 func (timerPtr *IntervalTimer) calcInitialOffset() time.Duration {
-	now := uint64(time.Now().UnixNano())
-	offset := (timerPtr.splay - now) % uint64(timerPtr.interval)
-	logger.WithField("offset", time.Duration(offset)/time.Second).Debug("initial offset for interval timer (in seconds)")
-	return time.Duration(offset) / time.Nanosecond
+	now := time.Now()
+	timerPtr.next = timerPtr.nextExecution(now)
+	offset := timerPtr.next.Sub(now)
+	logger.WithField("offset", offset/time.Second).Debug("initial offset for interval timer (in seconds)")
+	return offset
+}
+
+// nextExecution returns the first aligned execution time strictly after now.
+func (timerPtr *IntervalTimer) nextExecution(now time.Time) time.Time {
+	interval := int64(timerPtr.interval)
+	if interval <= 0 {
+		return now
+	}
+	phase := int64(timerPtr.splay % uint64(interval))
+	if !timerPtr.anchor.IsZero() {
+		phase = timerPtr.anchor.UnixNano() % interval
+		if phase < 0 {
+			phase += interval
+		}
+	}
+	offset := (phase - now.UnixNano()%interval) % interval
+	if offset <= 0 {
+		offset += interval
+	}
+	return now.Add(time.Duration(offset))
 }
 
 // A CronTimer handles starting and stopping timers for a given check
@@ -4,6 +4,8 @@ import (
 	"testing"
 	"time"
 
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -64,3 +66,42 @@ func TestInitialOffset(t *testing.T) {
 		assert.Condition(t, func() bool { return executionTime.Before(now.Add(time.Duration(intervalSeconds) * time.Second)) })
 	}
 }
+
+func TestInitialOffsetAnchored(t *testing.T) {
+	anchor := time.Unix(1000, 250000000)
+	timer := NewIntervalTimer("check1", 10)
+	timer.SetAnchor(anchor)
+
+	now := time.Unix(1234, 0)
+	next := timer.nextExecution(now)
+
+	assert.True(t, next.After(now))
+	assert.True(t, next.Sub(now) <= 10*time.Second)
+	// The next execution is a whole number of intervals after the anchor.
+	assert.Equal(t, time.Duration(0), next.Sub(anchor)%(10*time.Second))
+}
+
+func TestNextExecutionDoesNotDrift(t *testing.T) {
+	timer := NewIntervalTimer("check1", 10)
+	timer.SetAnchor(time.Unix(0, 0))
+	aligned := timer.nextExecution(time.Unix(20, 0))
+	assert.Equal(t, time.Unix(30, 0), aligned)
+
+	// Now is slightly later than the previous execution, as it would be
+	// once the check has been processed; the result is still aligned.
+	aligned = timer.nextExecution(time.Unix(30, 500))
+	assert.Equal(t, time.Unix(40, 0), aligned)
+}
+
+func TestCheckCreatedAt(t *testing.T) {
+	check := corev2.FixtureCheckConfig("check1")
+	_, ok := checkCreatedAt(check)
+	assert.False(t, ok)
+
+	createdAt := time.Unix(1600000000, 123)
+	text, _ := createdAt.MarshalText()
+	check.Labels[store.SensuCreatedAtKey] = string(text)
+	got, ok := checkCreatedAt(check)
+	assert.True(t, ok)
+	assert.True(t, createdAt.Equal(got))
+}
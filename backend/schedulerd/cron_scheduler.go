@@ -49,6 +49,11 @@ func (s *CronScheduler) schedule(timer *CronTimer, executor *CheckExecutor) {
 
 	s.logger.Debug("check is not subdued")
 
+	if isBlackedOut(s.check, s.logger) {
+		s.logger.Debug("check is in a blackout period")
+		return
+	}
+
 	if err := executor.processCheck(s.ctx, s.check); err != nil {
 		logger.Error(err)
 	}
@@ -3,10 +3,12 @@ package schedulerd
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
 )
 
 // IntervalScheduler schedules checks to be executed on a timer
@@ -49,6 +51,11 @@ func (s *IntervalScheduler) schedule(timer CheckTimer, executor *CheckExecutor)
 
 	s.logger.Debug("check is not subdued")
 
+	if isBlackedOut(s.check, s.logger) {
+		s.logger.Debug("check is in a blackout period")
+		return
+	}
+
 	if err := executor.processCheck(s.ctx, s.check); err != nil {
 		logger.WithError(err).Error("error executing check")
 	}
@@ -65,6 +72,9 @@ func (s *IntervalScheduler) start() {
 	defer s.stopWg.Done()
 	s.logger.Info("starting new interval scheduler")
 	timer := NewIntervalTimer(s.check.Name, uint(s.check.Interval))
+	if createdAt, ok := checkCreatedAt(s.check); ok {
+		timer.SetAnchor(createdAt)
+	}
 
 	timer.Start()
 
@@ -131,3 +141,17 @@ func (s *IntervalScheduler) resetTimer(timer CheckTimer) {
 func (s *IntervalScheduler) Type() SchedulerType {
 	return IntervalType
 }
+
+// checkCreatedAt returns the creation time of the check, as recorded by the
+// store in the check's labels.
+func checkCreatedAt(check *corev2.CheckConfig) (time.Time, bool) {
+	value, ok := check.Labels[store.SensuCreatedAtKey]
+	if !ok {
+		return time.Time{}, false
+	}
+	var createdAt time.Time
+	if err := createdAt.UnmarshalText([]byte(value)); err != nil || createdAt.IsZero() {
+		return time.Time{}, false
+	}
+	return createdAt, true
+}
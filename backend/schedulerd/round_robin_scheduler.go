@@ -0,0 +1,256 @@
+package schedulerd
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/ringv2"
+)
+
+// RoundRobinScheduler schedules checks on one agent of each of their
+// subscriptions at a time. The agents are picked in turn from the round-robin
+// rings of the subscriptions, which visit the agents in proportion to their
+// weight.
+type RoundRobinScheduler struct {
+	check     *corev2.CheckConfig
+	executor  *CheckExecutor
+	ringPool  *ringv2.RingPool
+	typ       SchedulerType
+	logger    *logrus.Entry
+	ctx       context.Context
+	cancel    context.CancelFunc
+	interrupt chan *corev2.CheckConfig
+	stopWg    sync.WaitGroup
+}
+
+// ringEvent is an event of the ring of one of the subscriptions of a check.
+type ringEvent struct {
+	ringv2.Event
+	subscription string
+}
+
+// NewRoundRobinScheduler initializes a RoundRobinScheduler
+func NewRoundRobinScheduler(ctx context.Context, check *corev2.CheckConfig, executor *CheckExecutor, ringPool *ringv2.RingPool) *RoundRobinScheduler {
+	typ := GetSchedulerType(check)
+	sched := &RoundRobinScheduler{
+		check:     check,
+		executor:  executor,
+		ringPool:  ringPool,
+		typ:       typ,
+		interrupt: make(chan *corev2.CheckConfig),
+		logger: logger.WithFields(logrus.Fields{
+			"name":           check.Name,
+			"namespace":      check.Namespace,
+			"scheduler_type": typ.String(),
+		}),
+	}
+	sched.ctx, sched.cancel = context.WithCancel(ctx)
+	sched.ctx = corev2.SetContextFromResource(sched.ctx, check)
+	return sched
+}
+
+// schedule executes the check on the agents of a ring event. It returns the
+// number of agents to get from the rings at the next interval, which changes
+// with the number of proxy entities of the check, or 0 if it is unknown.
+func (s *RoundRobinScheduler) schedule(event ringEvent) int {
+	lager := s.logger.WithField("subscription", event.subscription)
+	switch event.Type {
+	case ringv2.EventTrigger:
+	case ringv2.EventError:
+		lager.WithError(event.Err).Error("error receiving agents from the round-robin ring")
+		return 0
+	default:
+		return 0
+	}
+
+	items := 1
+	var proxyEntities []*corev3.EntityConfig
+	if s.check.ProxyRequests != nil {
+		entities, err := s.executor.getEntities(s.ctx)
+		if err != nil {
+			lager.WithError(err).Error("error getting proxy entities")
+			return 0
+		}
+		proxyEntities = matchEntities(entities, s.check.ProxyRequests)
+		if len(proxyEntities) > 1 {
+			items = len(proxyEntities)
+		}
+	}
+
+	if len(event.Values) == 0 {
+		lager.Debug("no agent in the round-robin ring, check will not be published")
+		return items
+	}
+
+	if s.check.IsSubdued() {
+		s.logger.Debug("check is subdued")
+		return items
+	}
+
+	if isBlackedOut(s.check, s.logger) {
+		s.logger.Debug("check is in a blackout period")
+		return items
+	}
+
+	agents := event.Values
+	if s.check.ProxyRequests != nil {
+		if len(proxyEntities) == 0 {
+			lager.Warn("no matching entities, check will not be published")
+			return items
+		}
+		// The rings repeat their agents when they have fewer agents than
+		// the proxy entities, but the proxy entities can change between
+		// the subscriptions
+		agents = make([]string, len(proxyEntities))
+		for i := range agents {
+			agents[i] = event.Values[i%len(event.Values)]
+		}
+	}
+
+	if err := processRoundRobinCheck(s.ctx, s.executor, s.check, proxyEntities, agents); err != nil {
+		lager.WithError(err).Error("error executing check")
+	}
+	return items
+}
+
+// Start starts the RoundRobinScheduler.
+func (s *RoundRobinScheduler) Start() {
+	roundRobinCounter.WithLabelValues(s.check.Namespace).Inc()
+	s.stopWg.Add(1)
+	go s.start()
+}
+
+func (s *RoundRobinScheduler) start() {
+	defer s.stopWg.Done()
+	s.logger.Info("starting new round-robin scheduler")
+	items := s.items()
+	events, cancel := s.subscribe(items)
+	defer func() {
+		cancel()
+	}()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case check := <-s.interrupt:
+			// if a schedule change is detected, subscribe to the rings again
+			changed := s.scheduleChanged(check)
+			s.check = check
+			if changed {
+				s.logger.Info("round-robin schedule has changed")
+				cancel()
+				defer s.Start()
+				return
+			}
+		case event := <-events:
+			// The number of proxy entities of the check can change between
+			// the intervals, and the rings must then be subscribed to again
+			// for as many agents
+			if next := s.schedule(event); next > 0 && next != items {
+				s.logger.WithField("items", next).Debug("number of proxy entities has changed")
+				cancel()
+				items = next
+				events, cancel = s.subscribe(items)
+			}
+		}
+	}
+}
+
+// subscribe subscribes to the rings of the subscriptions of the check, for
+// the given number of agents at a time, and returns their events, until the
+// returned cancel function is called.
+func (s *RoundRobinScheduler) subscribe(items int) (<-chan ringEvent, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	events := make(chan ringEvent)
+	sub := ringv2.Subscription{
+		Name:  s.check.Name,
+		Items: items,
+	}
+	if s.check.Cron != "" {
+		sub.CronSchedule = s.check.Cron
+	} else {
+		sub.IntervalSchedule = int(s.check.Interval)
+	}
+	if err := sub.Validate(); err != nil {
+		s.logger.WithError(err).Error("invalid round-robin schedule, check will not be scheduled")
+		return events, cancel
+	}
+
+	for _, subscription := range s.check.Subscriptions {
+		if strings.HasPrefix(subscription, "entity:") {
+			// Entity subscriptions don't get rings
+			continue
+		}
+		path := ringv2.Path(s.check.Namespace, subscription)
+		go func(subscription string, ch <-chan ringv2.Event) {
+			for event := range ch {
+				select {
+				case events <- ringEvent{Event: event, subscription: subscription}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(subscription, s.ringPool.Subscribe(ctx, path, sub))
+	}
+	return events, cancel
+}
+
+// items returns the number of agents to get from the rings at a time: one, or
+// one per proxy entity of the check.
+func (s *RoundRobinScheduler) items() int {
+	if s.check.ProxyRequests == nil {
+		return 1
+	}
+	entities, err := s.executor.getEntities(s.ctx)
+	if err != nil {
+		return 1
+	}
+	if n := len(matchEntities(entities, s.check.ProxyRequests)); n > 1 {
+		return n
+	}
+	return 1
+}
+
+// scheduleChanged returns true if the rings of the check must be subscribed to
+// again, for the revised check config.
+func (s *RoundRobinScheduler) scheduleChanged(check *corev2.CheckConfig) bool {
+	if s.check.Interval != check.Interval || s.check.Cron != check.Cron {
+		return true
+	}
+	if len(s.check.Subscriptions) != len(check.Subscriptions) {
+		return true
+	}
+	for i := range check.Subscriptions {
+		if s.check.Subscriptions[i] != check.Subscriptions[i] {
+			return true
+		}
+	}
+	return (s.check.ProxyRequests == nil) != (check.ProxyRequests == nil)
+}
+
+// Interrupt refreshes the scheduler with a revised check config.
+func (s *RoundRobinScheduler) Interrupt(check *corev2.CheckConfig) {
+	s.interrupt <- check
+}
+
+// Stop stops the RoundRobinScheduler
+func (s *RoundRobinScheduler) Stop() error {
+	s.logger.Info("stopping scheduler")
+	s.cancel()
+	s.stopWg.Wait()
+
+	roundRobinCounter.WithLabelValues(s.check.Namespace).Dec()
+
+	return nil
+}
+
+// Type returns the type of the round-robin scheduler.
+func (s *RoundRobinScheduler) Type() SchedulerType {
+	return s.typ
+}
@@ -0,0 +1,112 @@
+package schedulerd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/ringv2"
+	cachev2 "github.com/sensu/sensu-go/backend/store/cache/v2"
+)
+
+func TestRoundRobinSchedulerWeights(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduler := newIntervalScheduler(ctx, t, "check")
+	defer func() {
+		assert.NoError(t, scheduler.msgBus.Stop())
+	}()
+
+	check := scheduler.check
+	check.RoundRobin = true
+	check.Subscriptions = []string{"linux"}
+
+	ringPool := ringv2.NewRingPool(func(path string) ringv2.Interface {
+		return ringv2.NewMemoryRing()
+	})
+	ring := ringPool.Get(ringv2.Path(check.Namespace, "linux"))
+	weights := map[string]map[string]string{
+		"light": nil,
+		"heavy": {ringv2.WeightLabel: "3"},
+	}
+	requests := make(chan string, 10)
+	for agent, labels := range weights {
+		require.NoError(t, ring.Add(ringv2.WeightContext(ctx, ringv2.EntityWeight(labels)), agent, 60))
+		ch := make(chan interface{}, 10)
+		topic := messaging.SubscriptionTopic(check.Namespace, fmt.Sprintf("entity:%s", agent))
+		sub, err := scheduler.msgBus.Subscribe(topic, agent, testSubscriber{ch: ch})
+		require.NoError(t, err)
+		defer func() {
+			assert.NoError(t, sub.Cancel())
+		}()
+		go func(agent string) {
+			for range ch {
+				requests <- agent
+			}
+		}(agent)
+	}
+
+	rr := NewRoundRobinScheduler(ctx, check, scheduler.exec, ringPool)
+	rr.Start()
+	defer func() {
+		assert.NoError(t, rr.Stop())
+	}()
+
+	// The heavy agent is picked three times per iteration of the ring, and the
+	// light agent once
+	counts := map[string]int{}
+	for i := 0; i < 4; i++ {
+		select {
+		case agent := <-requests:
+			counts[agent]++
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for check request %d", i+1)
+		}
+	}
+	assert.Equal(t, map[string]int{"light": 1, "heavy": 3}, counts)
+}
+
+func TestRoundRobinSchedulerProxyEntities(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduler := newIntervalScheduler(ctx, t, "check")
+	defer func() {
+		assert.NoError(t, scheduler.msgBus.Stop())
+	}()
+
+	check := scheduler.check
+	check.RoundRobin = true
+	check.Subscriptions = []string{"linux"}
+	check.ProxyRequests = corev2.FixtureProxyRequests(false)
+	check.ProxyRequests.EntityAttributes = []string{`entity.entity_class == "proxy"`}
+
+	ringPool := ringv2.NewRingPool(func(path string) ringv2.Interface {
+		return ringv2.NewMemoryRing()
+	})
+	rr := NewRoundRobinScheduler(ctx, check, scheduler.exec, ringPool)
+	trigger := ringEvent{Event: ringv2.Event{Type: ringv2.EventTrigger}, subscription: "linux"}
+
+	// The number of agents to get from the rings follows the proxy entities
+	// of the check at each interval
+	assert.Equal(t, 1, rr.schedule(trigger))
+
+	entities := []*corev3.EntityConfig{}
+	for _, name := range []string{"proxy1", "proxy2", "proxy3"} {
+		entity := corev3.FixtureEntityConfig(name)
+		entity.EntityClass = corev2.EntityProxyClass
+		entities = append(entities, entity)
+	}
+	scheduler.exec.entityCache = cachev2.NewFromResources(entities, true)
+	assert.Equal(t, 3, rr.schedule(trigger))
+
+	failure := ringEvent{Event: ringv2.Event{Type: ringv2.EventError, Err: errors.New("error")}, subscription: "linux"}
+	assert.Equal(t, 0, rr.schedule(failure))
+}
@@ -11,6 +11,7 @@ import (
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/sensu-go/backend/messaging"
 	"github.com/sensu/sensu-go/backend/queue"
+	"github.com/sensu/sensu-go/backend/ringv2"
 	"github.com/sensu/sensu-go/backend/secrets"
 	cachev2 "github.com/sensu/sensu-go/backend/store/cache/v2"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
@@ -34,6 +35,13 @@ var (
 		},
 		[]string{"namespace"})
 
+	roundRobinCounter = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "sensu_go_round_robin_schedulers",
+			Help: "Number of active round-robin check schedulers on this backend",
+		},
+		[]string{"namespace"})
+
 	schedRefreshDuration = prometheus.NewHistogram(
 		prometheus.HistogramOpts{
 			Name:    "sensu_go_schedulerd_refresh_duration",
@@ -55,6 +63,7 @@ type Schedulerd struct {
 	entityCache            EntityCache
 	secretsProviderManager *secrets.ProviderManager
 	queue                  queue.Client
+	ringPool               *ringv2.RingPool
 
 	checks         namespacedChecks
 	schedulers     map[string]Scheduler
@@ -68,6 +77,10 @@ type Config struct {
 	SecretsProviderManager *secrets.ProviderManager
 	RefreshInterval        time.Duration
 	Queue                  queue.Client
+
+	// RingPool holds the round-robin rings of the subscriptions. The
+	// round-robin checks are not scheduled without it.
+	RingPool *ringv2.RingPool
 }
 
 // New creates a new Schedulerd.
@@ -79,6 +92,7 @@ func New(ctx context.Context, c Config) (*Schedulerd, error) {
 		errChan:                make(chan error, 1),
 		secretsProviderManager: c.SecretsProviderManager,
 		queue:                  c.Queue,
+		ringPool:               c.RingPool,
 
 		checks:     make(namespacedChecks),
 		schedulers: make(map[string]Scheduler),
@@ -100,6 +114,7 @@ func New(ctx context.Context, c Config) (*Schedulerd, error) {
 func (s *Schedulerd) Start() error {
 	_ = prometheus.Register(intervalCounter)
 	_ = prometheus.Register(cronCounter)
+	_ = prometheus.Register(roundRobinCounter)
 	_ = prometheus.Register(schedRefreshDuration)
 	return s.start()
 }
@@ -218,14 +233,14 @@ func (s *Schedulerd) makeScheduler(check *corev2.CheckConfig) Scheduler {
 		scheduler = NewIntervalScheduler(s.ctx, check, s.makeExecutor())
 	case CronType:
 		scheduler = NewCronScheduler(s.ctx, check, s.makeExecutor())
-	case RoundRobinIntervalType:
-		scheduler = NewNoopScheduler(RoundRobinIntervalType)
-		logger.WithFields(logrus.Fields{"namespace": check.Namespace, "check": check.Name}).
-			Error("checks configured with round robin enabled are not supported in this version of sensu. check will not be scheduled.")
-	case RoundRobinCronType:
-		logger.WithFields(logrus.Fields{"namespace": check.Namespace, "check": check.Name}).
-			Error("checks configured with round robin enabled are not supported in this version of sensu. check will not be scheduled.")
-		scheduler = NewNoopScheduler(RoundRobinCronType)
+	case RoundRobinIntervalType, RoundRobinCronType:
+		if s.ringPool == nil {
+			logger.WithFields(logrus.Fields{"namespace": check.Namespace, "check": check.Name}).
+				Error("checks configured with round robin enabled need round-robin rings. check will not be scheduled.")
+			scheduler = NewNoopScheduler(GetSchedulerType(check))
+			break
+		}
+		scheduler = NewRoundRobinScheduler(s.ctx, check, s.makeExecutor(), s.ringPool)
 	default:
 		logger.Error("bad scheduler type, falling back to interval scheduler")
 		scheduler = NewIntervalScheduler(s.ctx, check, s.makeExecutor())
@@ -0,0 +1,34 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev2 "github.com/sensu/core/v2"
+)
+
+// Getter resolves the secrets of the checks, handlers and mutators of a Sensu
+// namespace to the Kubernetes Secrets of the Kubernetes namespace configured
+// for it. The secrets are referenced as name/key, so that a Sensu namespace
+// can't read the secrets of another Kubernetes namespace; the Sensu
+// namespaces without a Kubernetes namespace can't read any secret.
+type Getter struct {
+	// Namespaces maps the Sensu namespaces to their Kubernetes namespace.
+	Namespaces map[string]string
+}
+
+// Get returns the provider and the ID of a secret, given its name in the
+// Sensu namespace of the context.
+func (g Getter) Get(ctx context.Context, name string) (string, string, error) {
+	namespace := corev2.ContextNamespace(ctx)
+	k8sNamespace, ok := g.Namespaces[namespace]
+	if !ok || k8sNamespace == "" {
+		return Name, "", fmt.Errorf("no kubernetes namespace configured for the %q namespace", namespace)
+	}
+	parts := strings.Split(name, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Name, "", fmt.Errorf("invalid kubernetes secret %q: expected name/key", name)
+	}
+	return Name, k8sNamespace + "/" + name, nil
+}
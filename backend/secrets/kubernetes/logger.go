@@ -0,0 +1,8 @@
+package kubernetes
+
+import "github.com/sirupsen/logrus"
+
+var logger = logrus.WithFields(logrus.Fields{
+	"component": "secrets",
+	"provider":  Name,
+})
@@ -0,0 +1,383 @@
+// Package kubernetes implements a secrets provider reading the secrets of the
+// checks and handlers from Kubernetes Secrets, through the API server of the
+// cluster the backend is deployed in.
+package kubernetes
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/secrets"
+)
+
+const (
+	// Name is the name of the provider, under which it is registered in the
+	// secrets provider manager.
+	Name = "kubernetes"
+
+	// DefaultTimeout is the time allowed to the API server to respond.
+	DefaultTimeout = 10 * time.Second
+
+	// serviceAccountDir holds the credentials of the service account of the
+	// pods.
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+	// watchRetryInterval is how long to wait before watching the secrets of a
+	// namespace again once the watch ended.
+	watchRetryInterval = 5 * time.Second
+)
+
+// asserts that Provider implements secrets.Provider
+var _ secrets.Provider = new(Provider)
+
+// Config configures a Provider.
+type Config struct {
+	// Host is the URL of the API server.
+	Host string
+
+	// TokenFile is the file holding the bearer token authenticating the
+	// requests. It is read before every request, since the tokens of the
+	// service accounts are rotated.
+	TokenFile string
+
+	// Client is the HTTP client of the API server. A client with
+	// DefaultTimeout is used if nil.
+	Client *http.Client
+
+	// Watch caches the secrets, and watches the namespaces of the cached
+	// secrets to invalidate them once modified or deleted. The secrets are
+	// read from the API server every time otherwise.
+	Watch bool
+}
+
+// InClusterConfig returns the configuration of a provider running in a pod,
+// authenticated with the service account of the pod.
+func InClusterConfig() (Config, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return Config{}, errors.New("not running in a kubernetes cluster: KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT are not set")
+	}
+	ca, err := os.ReadFile(serviceAccountDir + "/ca.crt")
+	if err != nil {
+		return Config{}, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return Config{}, errors.New("invalid certificate authority of the service account")
+	}
+	return Config{
+		Host:      "https://" + net.JoinHostPort(host, port),
+		TokenFile: serviceAccountDir + "/token",
+		Client: &http.Client{
+			Timeout: DefaultTimeout,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+			},
+		},
+	}, nil
+}
+
+// Provider is a secrets provider reading Kubernetes Secrets. The IDs of the
+// secrets are of the form namespace/name/key; they are built by a Getter,
+// which confines the Sensu namespaces to their Kubernetes namespace.
+type Provider struct {
+	Metadata corev2.ObjectMeta
+
+	ctx         context.Context
+	cfg         Config
+	client      *http.Client
+	watchClient *http.Client
+
+	mu      sync.Mutex
+	watches map[string]*namespaceWatch
+}
+
+// namespaceWatch is the cache of the secrets of a namespace.
+type namespaceWatch struct {
+	// connected is set while the secrets of the namespace are watched.
+	connected bool
+
+	// generation is incremented on every change of the watched secrets, so
+	// that the secrets read during a change are not cached.
+	generation uint64
+
+	// secrets are the data of the cached secrets, by name.
+	secrets map[string]map[string]string
+}
+
+// New creates a Provider. The namespaces are watched until the context is
+// done.
+func New(ctx context.Context, cfg Config) (*Provider, error) {
+	if _, err := url.Parse(cfg.Host); err != nil || cfg.Host == "" {
+		return nil, fmt.Errorf("invalid kubernetes API server URL %q", cfg.Host)
+	}
+	p := &Provider{
+		Metadata: corev2.ObjectMeta{Name: Name},
+		ctx:      ctx,
+		cfg:      cfg,
+		client:   cfg.Client,
+		watches:  make(map[string]*namespaceWatch),
+	}
+	if p.client == nil {
+		p.client = &http.Client{Timeout: DefaultTimeout}
+	}
+	// The watches are long-lived requests
+	watchClient := *p.client
+	watchClient.Timeout = 0
+	p.watchClient = &watchClient
+	return p, nil
+}
+
+// Get gets the value of the key of a Kubernetes Secret, given its ID.
+func (p *Provider) Get(id string) (string, error) {
+	namespace, name, key, err := p.parseID(id)
+	if err != nil {
+		return "", err
+	}
+
+	var generation uint64
+	if p.cfg.Watch {
+		var data map[string]string
+		data, generation = p.cached(namespace, name)
+		if data != nil {
+			return value(id, data, key)
+		}
+	}
+
+	data, err := p.getSecret(namespace, name)
+	if err != nil {
+		if err == errNotFound {
+			return "", secrets.ErrSecretNotFound(id)
+		}
+		return "", err
+	}
+	if p.cfg.Watch {
+		p.cache(namespace, name, generation, data)
+	}
+	return value(id, data, key)
+}
+
+// GetMetadata returns the metadata of the provider.
+func (p *Provider) GetMetadata() *corev2.ObjectMeta {
+	return &p.Metadata
+}
+
+// SetMetadata sets the metadata of the provider.
+func (p *Provider) SetMetadata(meta *corev2.ObjectMeta) {
+	p.Metadata = *meta
+}
+
+// StoreName is not used, since the provider is not stored.
+func (p *Provider) StoreName() string {
+	return ""
+}
+
+// RBACName is not used, since the provider is not stored.
+func (p *Provider) RBACName() string {
+	return ""
+}
+
+// URIPath is not used, since the provider is not stored.
+func (p *Provider) URIPath() string {
+	return ""
+}
+
+// Validate validates the provider.
+func (p *Provider) Validate() error {
+	return nil
+}
+
+// parseID returns the namespace, the name and the key of a secret ID.
+func (p *Provider) parseID(id string) (namespace, name, key string, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) == 3 {
+		namespace, name, key = parts[0], parts[1], parts[2]
+	}
+	if namespace == "" || name == "" || key == "" {
+		return "", "", "", fmt.Errorf("invalid kubernetes secret %q: expected namespace/name/key", id)
+	}
+	return namespace, name, key, nil
+}
+
+// value returns the value of a key of the data of a secret.
+func value(id string, data map[string]string, key string) (string, error) {
+	v, ok := data[key]
+	if !ok {
+		return "", secrets.ErrSecretNotFound(id)
+	}
+	return v, nil
+}
+
+var errNotFound = errors.New("not found")
+
+// secret is the subset of a Kubernetes Secret read by the provider.
+type secret struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Data map[string]string `json:"data"`
+}
+
+// getSecret reads the decoded data of a secret.
+func (p *Provider) getSecret(namespace, name string) (map[string]string, error) {
+	resp, err := p.do(p.ctx, p.client, fmt.Sprintf("/api/v1/namespaces/%s/secrets/%s", url.PathEscape(namespace), url.PathEscape(name)))
+	if err != nil {
+		return nil, secrets.ErrProviderNotAvailable(err.Error())
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errNotFound
+	case resp.StatusCode >= 500:
+		return nil, secrets.ErrProviderNotAvailable(fmt.Sprintf("kubernetes API server responded with %s", resp.Status))
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("couldn't read kubernetes secret %s/%s: %s", namespace, name, resp.Status)
+	}
+
+	var s secret
+	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+		return nil, fmt.Errorf("couldn't decode kubernetes secret %s/%s: %s", namespace, name, err)
+	}
+	data := make(map[string]string, len(s.Data))
+	for k, v := range s.Data {
+		b, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't decode key %q of kubernetes secret %s/%s: %s", k, namespace, name, err)
+		}
+		data[k] = string(b)
+	}
+	return data, nil
+}
+
+// do sends a GET request to the API server.
+func (p *Provider) do(ctx context.Context, client *http.Client, path string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.cfg.Host, "/")+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.TokenFile != "" {
+		token, err := os.ReadFile(p.cfg.TokenFile)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	return client.Do(req)
+}
+
+// cached returns the cached data of a secret, or nil along with the current
+// generation of its namespace if it is not cached. The namespace starts being
+// watched if it is not.
+func (p *Provider) cached(namespace, name string) (map[string]string, uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	w, ok := p.watches[namespace]
+	if !ok {
+		w = &namespaceWatch{secrets: make(map[string]map[string]string)}
+		p.watches[namespace] = w
+		go p.watch(namespace)
+	}
+	return w.secrets[name], w.generation
+}
+
+// cache caches the data of a secret, unless its namespace is not watched or
+// changed since the secret was read.
+func (p *Provider) cache(namespace, name string, generation uint64, data map[string]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	w := p.watches[namespace]
+	if w.connected && w.generation == generation {
+		w.secrets[name] = data
+	}
+}
+
+// invalidate removes a secret from the cache, or all the secrets of the
+// namespace if name is empty, and sets whether the namespace is watched.
+func (p *Provider) invalidate(namespace, name string, connected bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	w := p.watches[namespace]
+	w.generation++
+	w.connected = connected
+	if name == "" {
+		w.secrets = make(map[string]map[string]string)
+	} else {
+		delete(w.secrets, name)
+	}
+}
+
+// watch watches the secrets of a namespace until the context is done.
+func (p *Provider) watch(namespace string) {
+	for {
+		err := p.watchOnce(namespace)
+		p.invalidate(namespace, "", false)
+		select {
+		case <-p.ctx.Done():
+			return
+		default:
+		}
+		logger.WithError(err).WithField("namespace", namespace).Warn("watch of kubernetes secrets ended, retrying")
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-time.After(watchRetryInterval):
+		}
+	}
+}
+
+// watchEvent is an event of a watch of Kubernetes Secrets.
+type watchEvent struct {
+	Type   string `json:"type"`
+	Object secret `json:"object"`
+}
+
+// watchOnce watches the secrets of a namespace, invalidating them as they
+// change, until the watch ends.
+func (p *Provider) watchOnce(namespace string) error {
+	resp, err := p.do(p.ctx, p.watchClient, fmt.Sprintf("/api/v1/namespaces/%s/secrets?watch=true", url.PathEscape(namespace)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("kubernetes API server responded with %s", resp.Status)
+	}
+
+	// The secrets read before the watch started may have changed since
+	p.invalidate(namespace, "", true)
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var event watchEvent
+		if err := decoder.Decode(&event); err != nil {
+			if err == io.EOF {
+				return errors.New("watch closed by the kubernetes API server")
+			}
+			return err
+		}
+		if event.Type == "ERROR" {
+			return errors.New("kubernetes API server ended the watch with an error")
+		}
+		p.invalidate(namespace, event.Object.Metadata.Name, true)
+	}
+}
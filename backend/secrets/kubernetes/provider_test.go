@@ -0,0 +1,183 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// apiServer is a fake Kubernetes API server serving the secrets of the
+// "sensu" namespace.
+type apiServer struct {
+	mu      sync.Mutex
+	secrets map[string]map[string]string
+	reads   int32
+	events  chan watchEvent
+}
+
+func newAPIServer(t *testing.T) (*apiServer, *httptest.Server) {
+	s := &apiServer{
+		secrets: map[string]map[string]string{
+			"db": {"password": "P@ssw0rd!"},
+		},
+		events: make(chan watchEvent),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/namespaces/sensu/secrets/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		atomic.AddInt32(&s.reads, 1)
+		s.mu.Lock()
+		data, ok := s.secrets[filepath.Base(r.URL.Path)]
+		s.mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var sec secret
+		sec.Data = make(map[string]string)
+		for k, v := range data {
+			sec.Data[k] = base64.StdEncoding.EncodeToString([]byte(v))
+		}
+		_ = json.NewEncoder(w).Encode(sec)
+	})
+	mux.HandleFunc("/api/v1/namespaces/sensu/secrets", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("watch") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case event := <-s.events:
+				_ = json.NewEncoder(w).Encode(event)
+				w.(http.Flusher).Flush()
+			}
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return s, server
+}
+
+func (s *apiServer) set(name, key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.secrets[name] = map[string]string{key: value}
+}
+
+func newProvider(t *testing.T, host string, watch bool) *Provider {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("token\n"), 0600))
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	p, err := New(ctx, Config{
+		Host:      host,
+		TokenFile: tokenFile,
+		Watch:     watch,
+	})
+	require.NoError(t, err)
+	return p
+}
+
+func TestProviderGet(t *testing.T) {
+	_, server := newAPIServer(t)
+	p := newProvider(t, server.URL, false)
+
+	value, err := p.Get("sensu/db/password")
+	require.NoError(t, err)
+	assert.Equal(t, "P@ssw0rd!", value)
+
+	_, err = p.Get("sensu/db/username")
+	assert.IsType(t, secrets.ErrSecretNotFound(""), err)
+	_, err = p.Get("sensu/api/token")
+	assert.IsType(t, secrets.ErrSecretNotFound(""), err)
+	_, err = p.Get("db/password")
+	assert.Error(t, err)
+}
+
+func TestProviderNotAvailable(t *testing.T) {
+	_, server := newAPIServer(t)
+	server.Close()
+	p := newProvider(t, server.URL, false)
+
+	_, err := p.Get("sensu/db/password")
+	assert.IsType(t, secrets.ErrProviderNotAvailable(""), err)
+}
+
+func TestProviderWatch(t *testing.T) {
+	api, server := newAPIServer(t)
+	p := newProvider(t, server.URL, true)
+
+	get := func() string {
+		value, err := p.Get("sensu/db/password")
+		require.NoError(t, err)
+		return value
+	}
+
+	// The secrets are cached once the namespace is watched
+	assert.Equal(t, "P@ssw0rd!", get())
+	require.Eventually(t, func() bool {
+		get()
+		return atomic.LoadInt32(&api.reads) >= 2 && p.isCached("sensu", "db")
+	}, 5*time.Second, 10*time.Millisecond)
+	reads := atomic.LoadInt32(&api.reads)
+	assert.Equal(t, "P@ssw0rd!", get())
+	assert.Equal(t, reads, atomic.LoadInt32(&api.reads))
+
+	// The modified secrets are read again
+	api.set("db", "password", "s3cr3t")
+	event := watchEvent{Type: "MODIFIED"}
+	event.Object.Metadata.Name = "db"
+	api.events <- event
+	require.Eventually(t, func() bool {
+		return get() == "s3cr3t"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestGetter(t *testing.T) {
+	getter := Getter{Namespaces: map[string]string{"default": "sensu"}}
+	ctx := context.WithValue(context.Background(), corev2.NamespaceKey, "default")
+
+	provider, id, err := getter.Get(ctx, "db/password")
+	require.NoError(t, err)
+	assert.Equal(t, Name, provider)
+	assert.Equal(t, "sensu/db/password", id)
+
+	// The secrets of other kubernetes namespaces can't be referenced
+	_, _, err = getter.Get(ctx, "kube-system/db/password")
+	assert.Error(t, err)
+	_, _, err = getter.Get(ctx, "password")
+	assert.Error(t, err)
+
+	// The sensu namespaces without kubernetes namespace can't read secrets
+	ctx = context.WithValue(context.Background(), corev2.NamespaceKey, "dev")
+	_, _, err = getter.Get(ctx, "db/password")
+	assert.Error(t, err)
+}
+
+// isCached returns whether a secret is cached.
+func (p *Provider) isCached(namespace, name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	w, ok := p.watches[namespace]
+	return ok && w.secrets[name] != nil
+}
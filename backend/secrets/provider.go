@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
@@ -37,6 +38,15 @@ type ProviderManager struct {
 	TLSenabled    bool
 	Getter        Getter
 	eventReceiver EventReceiver
+
+	// resolved holds when the secrets were last resolved, by resolution.
+	resolved sync.Map
+}
+
+// resolution identifies a secret of a namespace.
+type resolution struct {
+	namespace string
+	name      string
 }
 
 type EventReceiver interface {
@@ -94,6 +104,19 @@ func (m *ProviderManager) RemoveProvider(name string) error {
 	return nil
 }
 
+// LastResolved returns when the secrets of a namespace were last resolved by
+// this backend, as Unix timestamps by secret name.
+func (m *ProviderManager) LastResolved(namespace string) map[string]int64 {
+	resolved := make(map[string]int64)
+	m.resolved.Range(func(key, value interface{}) bool {
+		if r := key.(resolution); r.namespace == namespace {
+			resolved[r.name] = value.(int64)
+		}
+		return true
+	})
+	return resolved
+}
+
 // SubSecrets substitutes all secret tokens with the value of the secret.
 func (m *ProviderManager) SubSecrets(ctx context.Context, secrets []*corev2.Secret) ([]string, error) {
 	secretVars := make([]string, 0, len(secrets))
@@ -151,6 +174,7 @@ func (m *ProviderManager) SubSecrets(ctx context.Context, secrets []*corev2.Secr
 
 			return []string{}, err
 		}
+		m.resolved.Store(resolution{namespace: corev2.ContextNamespace(ctx), name: secret.Secret}, time.Now().Unix())
 		if secretValue != "" {
 			secretVars = append(secretVars, fmt.Sprintf("%s=%s", secretKey, secretValue))
 		}
@@ -211,3 +211,32 @@ func TestSubSecrets(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, []string{}, secretVars)
 }
+
+func TestLastResolved(t *testing.T) {
+	ctx := context.WithValue(context.Background(), corev2.NamespaceKey, "default")
+
+	mer := &mockEventReceiver{}
+	mer.On("GenerateBackendEvent", "secrets", uint32(0), msgSecretsProviderOk).Return(nil)
+	env := &mockProvider{}
+	env.On("GetMetadata", mock.Anything).Return(&corev2.ObjectMeta{Name: "env"})
+	env.On("Get", "sensu-foo").Return("bar", nil)
+	env.On("Get", "sensu-err").Return("", ErrSecretNotFound("sensu-err"))
+
+	mg := &mockGetter{}
+	mg.On("Get", ctx, "sensu-foo").Return("env", "sensu-foo", nil)
+	mg.On("Get", ctx, "sensu-err").Return("env", "sensu-err", nil)
+
+	pm := NewProviderManager(mer)
+	pm.Getter = mg
+	pm.AddProvider(env)
+
+	_, err := pm.SubSecrets(ctx, []*corev2.Secret{{Name: "FOO", Secret: "sensu-foo"}})
+	require.NoError(t, err)
+	_, err = pm.SubSecrets(ctx, []*corev2.Secret{{Name: "ERR", Secret: "sensu-err"}})
+	require.Error(t, err)
+
+	resolved := pm.LastResolved("default")
+	require.Len(t, resolved, 1)
+	require.NotZero(t, resolved["sensu-foo"])
+	require.Empty(t, pm.LastResolved("acme"))
+}
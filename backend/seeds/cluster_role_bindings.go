@@ -2,11 +2,9 @@ package seeds
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	corev2 "github.com/sensu/core/v2"
-	"github.com/sensu/sensu-go/backend/store"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 )
 
@@ -17,18 +15,12 @@ func setupClusterRoleBindings(ctx context.Context, s storev2.Interface, config C
 		systemUserClusterRoleBinding(),
 	}
 
-	for _, clusterRoleBinding := range clusterRoleBindings {
-		name := clusterRoleBinding.ObjectMeta.Name
-
-		if err := createResource(ctx, s, clusterRoleBinding); err != nil {
-			var alreadyExists *store.ErrAlreadyExists
-			if !errors.As(err, &alreadyExists) {
-				msg := fmt.Sprintf("could not initialize the %s cluster role binding", name)
-				logger.WithError(err).Error(msg)
-				return fmt.Errorf("%s: %w", msg, err)
-			}
-			logger.Warnf("%s cluster role binding already exists", name)
-		}
+	// The seeds are written in a single batch, which replaces those left
+	// behind by an interrupted initialization
+	if err := storev2.Of[*corev2.ClusterRoleBinding](s).BatchCreateOrUpdate(ctx, clusterRoleBindings); err != nil {
+		msg := "could not initialize the cluster role bindings"
+		logger.WithError(err).Error(msg)
+		return fmt.Errorf("%s: %w", msg, err)
 	}
 
 	return nil
@@ -2,8 +2,8 @@ package seeds
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"reflect"
 
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/backend/store"
@@ -20,20 +20,47 @@ func setupClusterRoles(ctx context.Context, s storev2.Interface, config Config)
 		systemUserClusterRole(),
 	}
 
-	for _, clusterRole := range clusterRoles {
-		name := clusterRole.ObjectMeta.Name
+	// The seeds are written in a single batch, which replaces those left
+	// behind by an interrupted initialization
+	if err := storev2.Of[*corev2.ClusterRole](s).BatchCreateOrUpdate(ctx, clusterRoles); err != nil {
+		msg := "could not initialize the cluster roles"
+		logger.WithError(err).Error(msg)
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+
+	return nil
+}
 
-		if err := createResource(ctx, s, clusterRole); err != nil {
-			var alreadyExists *store.ErrAlreadyExists
-			if !errors.As(err, &alreadyExists) {
-				msg := fmt.Sprintf("could not initialize the %s cluster role", name)
-				logger.WithError(err).Error(msg)
-				return fmt.Errorf("%s: %w", msg, err)
-			}
-			logger.Warnf("%s cluster role already exists", name)
+// MigrateClusterRoles updates the cluster roles seeded by older versions of
+// the backend, which only apply to fresh installs otherwise. The cluster roles
+// modified by the users are left as is.
+func MigrateClusterRoles(ctx context.Context, s storev2.Interface) error {
+	rstore := storev2.Of[*corev2.ClusterRole](s)
+	role, err := rstore.Get(ctx, storev2.ID{Name: "system:user"})
+	if err != nil {
+		if _, ok := err.(*store.ErrNotFound); ok {
+			// The cluster is not initialized yet
+			return nil
 		}
+		return err
 	}
 
+	// The system:user cluster role did not allow the users to revoke their
+	// own sessions
+	if len(role.Rules) != 1 {
+		return nil
+	}
+	rule := role.Rules[0]
+	if !reflect.DeepEqual(rule.Verbs, []string{"get", "update"}) ||
+		!reflect.DeepEqual(rule.Resources, []string{corev2.LocalSelfUserResource}) ||
+		len(rule.ResourceNames) > 0 {
+		return nil
+	}
+	logger.Info("adding the delete verb to the system:user cluster role")
+	role.Rules = systemUserClusterRole().Rules
+	if err := rstore.CreateOrUpdate(ctx, role); err != nil {
+		return fmt.Errorf("could not migrate the system:user cluster role: %w", err)
+	}
 	return nil
 }
 
@@ -135,13 +162,13 @@ func systemAgentClusterRole() *corev2.ClusterRole {
 func systemUserClusterRole() *corev2.ClusterRole {
 	// The systemUser ClusterRole is used by local users and should not be
 	// modified by the users. Modification to his ClusterRole can result in
-	// non-functional Sensu users. It allows users to view themselves and change
-	// their own password
+	// non-functional Sensu users. It allows users to view themselves, change
+	// their own password, and list and revoke their own sessions
 	return &corev2.ClusterRole{
 		ObjectMeta: corev2.NewObjectMeta("system:user", ""),
 		Rules: []corev2.Rule{
 			{
-				Verbs:     []string{"get", "update"},
+				Verbs:     []string{"get", "update", "delete"},
 				Resources: []string{corev2.LocalSelfUserResource},
 			},
 		},
@@ -2,6 +2,7 @@ package seeds
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	corev2 "github.com/sensu/core/v2"
@@ -31,6 +32,7 @@ func TestSeedInitialDataWithContext(t *testing.T) {
 	s.On("GetNamespaceStore").Return(nsStore)
 	s.On("GetConfigStore").Return(cs)
 	cs.On("CreateIfNotExists", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	cs.On("BatchCreateOrUpdate", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	cs.On("Initialize", mock.Anything, mock.Anything).Return(seedCluster(config)(ctx, s))
 
 	sErr := SeedInitialDataWithContext(ctx, s)
@@ -72,10 +74,7 @@ func TestSeedInitialDataWithContext(t *testing.T) {
 			},
 		},
 	}
-	cs.AssertCalled(t, "CreateIfNotExists",
-		context.Background(),
-		storev2.NewResourceRequestFromResource(clusterAdminClusterRole),
-		mock.Anything)
+	assertBatched(t, cs, storev2.NewResourceRequestFromResource(clusterAdminClusterRole))
 
 	// ensure the admin cluster role is created
 	adminClusterRole := &corev2.ClusterRole{
@@ -96,10 +95,7 @@ func TestSeedInitialDataWithContext(t *testing.T) {
 			},
 		},
 	}
-	cs.AssertCalled(t, "CreateIfNotExists",
-		context.Background(),
-		storev2.NewResourceRequestFromResource(adminClusterRole),
-		mock.Anything)
+	assertBatched(t, cs, storev2.NewResourceRequestFromResource(adminClusterRole))
 
 	// ensure the edit cluster role is created
 	editClusterRole := &corev2.ClusterRole{
@@ -117,10 +113,7 @@ func TestSeedInitialDataWithContext(t *testing.T) {
 			},
 		},
 	}
-	cs.AssertCalled(t, "CreateIfNotExists",
-		context.Background(),
-		storev2.NewResourceRequestFromResource(editClusterRole),
-		mock.Anything)
+	assertBatched(t, cs, storev2.NewResourceRequestFromResource(editClusterRole))
 
 	// ensure the view cluster role is created
 	viewClusterRole := &corev2.ClusterRole{
@@ -134,10 +127,7 @@ func TestSeedInitialDataWithContext(t *testing.T) {
 			},
 		},
 	}
-	cs.AssertCalled(t, "CreateIfNotExists",
-		context.Background(),
-		storev2.NewResourceRequestFromResource(viewClusterRole),
-		mock.Anything)
+	assertBatched(t, cs, storev2.NewResourceRequestFromResource(viewClusterRole))
 
 	// ensure the system:agent cluster role is created
 	systemAgentClusterRole := &corev2.ClusterRole{
@@ -149,25 +139,19 @@ func TestSeedInitialDataWithContext(t *testing.T) {
 			},
 		},
 	}
-	cs.AssertCalled(t, "CreateIfNotExists",
-		context.Background(),
-		storev2.NewResourceRequestFromResource(systemAgentClusterRole),
-		mock.Anything)
+	assertBatched(t, cs, storev2.NewResourceRequestFromResource(systemAgentClusterRole))
 
 	// ensure the system:user cluster role is created
 	systemUserClusterRole := &corev2.ClusterRole{
 		ObjectMeta: corev2.NewObjectMeta("system:user", ""),
 		Rules: []corev2.Rule{
 			{
-				Verbs:     []string{"get", "update"},
+				Verbs:     []string{"get", "update", "delete"},
 				Resources: []string{corev2.LocalSelfUserResource},
 			},
 		},
 	}
-	cs.AssertCalled(t, "CreateIfNotExists",
-		context.Background(),
-		storev2.NewResourceRequestFromResource(systemUserClusterRole),
-		mock.Anything)
+	assertBatched(t, cs, storev2.NewResourceRequestFromResource(systemUserClusterRole))
 
 	// ensure the cluster-admin cluster role binding is created
 	clusterAdminClusterRoleBinding := &corev2.ClusterRoleBinding{
@@ -183,10 +167,7 @@ func TestSeedInitialDataWithContext(t *testing.T) {
 			},
 		},
 	}
-	cs.AssertCalled(t, "CreateIfNotExists",
-		context.Background(),
-		storev2.NewResourceRequestFromResource(clusterAdminClusterRoleBinding),
-		mock.Anything)
+	assertBatched(t, cs, storev2.NewResourceRequestFromResource(clusterAdminClusterRoleBinding))
 
 	// ensure the system:agent cluster role binding is created
 	systemAgentClusterRoleBinding := &corev2.ClusterRoleBinding{
@@ -202,10 +183,7 @@ func TestSeedInitialDataWithContext(t *testing.T) {
 			},
 		},
 	}
-	cs.AssertCalled(t, "CreateIfNotExists",
-		context.Background(),
-		storev2.NewResourceRequestFromResource(systemAgentClusterRoleBinding),
-		mock.Anything)
+	assertBatched(t, cs, storev2.NewResourceRequestFromResource(systemAgentClusterRoleBinding))
 
 	// ensure the system:user cluster role binding is created
 	systemUserClusterRoleBinding := &corev2.ClusterRoleBinding{
@@ -221,8 +199,85 @@ func TestSeedInitialDataWithContext(t *testing.T) {
 			},
 		},
 	}
-	cs.AssertCalled(t, "CreateIfNotExists",
-		context.Background(),
-		storev2.NewResourceRequestFromResource(systemUserClusterRoleBinding),
-		mock.Anything)
+	assertBatched(t, cs, storev2.NewResourceRequestFromResource(systemUserClusterRoleBinding))
+}
+
+// assertBatched asserts that the resource of the request was written in a
+// batch.
+func assertBatched(t *testing.T, cs *mockstore.ConfigStore, req storev2.ResourceRequest) {
+	t.Helper()
+	for _, call := range cs.Calls {
+		if call.Method != "BatchCreateOrUpdate" {
+			continue
+		}
+		for _, batched := range call.Arguments.Get(1).([]storev2.ResourceRequest) {
+			if reflect.DeepEqual(batched, req) {
+				return
+			}
+		}
+	}
+	t.Errorf("%s/%s was not written in a batch", req.Type, req.Name)
+}
+
+func TestMigrateClusterRoles(t *testing.T) {
+	legacyRole := func() *corev2.ClusterRole {
+		return &corev2.ClusterRole{
+			ObjectMeta: corev2.NewObjectMeta("system:user", ""),
+			Rules: []corev2.Rule{
+				{
+					Verbs:     []string{"get", "update"},
+					Resources: []string{corev2.LocalSelfUserResource},
+				},
+			},
+		}
+	}
+	modifiedRole := legacyRole()
+	modifiedRole.Rules[0].Verbs = []string{"get"}
+
+	tests := []struct {
+		name        string
+		role        *corev2.ClusterRole
+		err         error
+		wantUpdated bool
+	}{
+		{
+			name:        "legacy role",
+			role:        legacyRole(),
+			wantUpdated: true,
+		},
+		{
+			name: "current role",
+			role: systemUserClusterRole(),
+		},
+		{
+			name: "role modified by the users",
+			role: modifiedRole,
+		},
+		{
+			name: "uninitialized cluster",
+			err:  &store.ErrNotFound{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(mockstore.V2MockStore)
+			cs := new(mockstore.ConfigStore)
+			s.On("GetConfigStore").Return(cs)
+			cs.On("Get", mock.Anything, mock.Anything).Return(mockstore.Wrapper[*corev2.ClusterRole]{Value: tt.role}, tt.err)
+			cs.On("CreateOrUpdate", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			require.NoError(t, MigrateClusterRoles(context.Background(), s))
+			if !tt.wantUpdated {
+				cs.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			cs.AssertCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything, mock.MatchedBy(func(w storev2.Wrapper) bool {
+				var role corev2.ClusterRole
+				if err := w.UnwrapInto(&role); err != nil {
+					return false
+				}
+				return reflect.DeepEqual(role.Rules, systemUserClusterRole().Rules)
+			}))
+		})
+	}
 }
@@ -60,6 +60,21 @@ const (
 
 	// matchesToken represents matches
 	matchesToken
+
+	// numberToken represents a number, see NumberPattern
+	numberToken
+
+	// lessThanToken represents <
+	lessThanToken
+
+	// lessThanOrEqualToken represents <=
+	lessThanOrEqualToken
+
+	// greaterThanToken represents >
+	greaterThanToken
+
+	// greaterThanOrEqualToken represents >=
+	greaterThanOrEqualToken
 )
 
 var reservedWords = map[string]Token{
@@ -88,6 +103,14 @@ func identTail(r rune) bool {
 	return r == '_' || r == '.' || r == '/' || unicode.IsDigit(r) || unicode.IsLetter(r)
 }
 
+func numberStart(r rune) bool {
+	return r == '+' || r == '-' || r == '.' || unicode.IsDigit(r)
+}
+
+func numberTail(r rune) bool {
+	return numberStart(r) || r == 'e' || r == 'E'
+}
+
 // Tokenize returns the next token found in the input stream
 func (l *lexer) Tokenize() Token {
 	// Yep, it's a state machine. 1-rune lookahead.
@@ -106,7 +129,7 @@ func (l *lexer) Tokenize() Token {
 					return Token{Type: errorToken, Value: fmt.Sprintf("end of input while scanning identifier: %q", string(buf))}
 				}
 				return Token{Type: endOfStringToken}
-			case identifierToken:
+			case identifierToken, numberToken, lessThanToken, greaterThanToken:
 			default:
 				return Token{Type: errorToken}
 			}
@@ -130,11 +153,22 @@ func (l *lexer) Tokenize() Token {
 			case '&':
 				state = doubleAmpersandToken
 				buf = append(buf, r)
+			case '<':
+				state = lessThanToken
+				buf = append(buf, r)
+			case '>':
+				state = greaterThanToken
+				buf = append(buf, r)
 			case ',':
 				return Token{Type: commaToken, Value: ","}
 			case '"', '\'':
 				state = stringToken
 			default:
+				if len(buf) == 0 && numberStart(r) {
+					state = numberToken
+					buf = append(buf, r)
+					continue
+				}
 				if !identStart(r) {
 					if len(buf) > 0 {
 						return Token{Type: errorToken, Value: fmt.Sprintf("invalid identifier: %q", string(append(buf, r)))}
@@ -152,6 +186,39 @@ func (l *lexer) Tokenize() Token {
 				errmsg := fmt.Sprintf("at %d, looking for %q but got %q", l.position, "=", string(append(buf, r)))
 				return Token{Type: errorToken, Value: errmsg}
 			}
+		case lessThanToken, greaterThanToken:
+			if err != io.EOF && r == '=' {
+				if state == lessThanToken {
+					return Token{Type: lessThanOrEqualToken, Value: "<="}
+				}
+				return Token{Type: greaterThanOrEqualToken, Value: ">="}
+			}
+			if err != io.EOF {
+				_ = l.input.UnreadRune()
+			}
+			return Token{Type: state, Value: string(buf)}
+		case numberToken:
+			end := unicode.IsSpace(r) || err == io.EOF
+			switch r {
+			case '[', ']', '!', '&', ',', '<', '>':
+				_ = l.input.UnreadRune()
+				end = true
+			}
+			if end {
+				if !numberRegexp.MatchString(string(buf)) {
+					return Token{Type: errorToken, Value: fmt.Sprintf("invalid number: %q", string(buf))}
+				}
+				return Token{Type: state, Value: string(buf)}
+			}
+			switch {
+			case numberTail(r):
+				buf = append(buf, r)
+				continue
+			case r == '_' || unicode.IsLetter(r):
+				// identifiers do not start with a digit
+				return Token{Type: errorToken, Value: fmt.Sprintf("invalid rune: %q", string(buf[0]))}
+			}
+			return Token{Type: errorToken, Value: fmt.Sprintf("invalid number: %q", string(append(buf, r)))}
 		case doubleAmpersandToken:
 			switch r {
 			case '&':
@@ -173,7 +240,7 @@ func (l *lexer) Tokenize() Token {
 				return Token{Type: state, Value: buf}
 			}
 			switch r {
-			case '[', ']', '!', '&', ',', '"', '\'':
+			case '[', ']', '!', '&', ',', '"', '\'', '<', '>':
 				_ = l.input.UnreadRune()
 				return Token{Type: state, Value: string(buf)}
 			case '.':
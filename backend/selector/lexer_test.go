@@ -142,6 +142,18 @@ func TestLexerScanOperator(t *testing.T) {
 			want:         Token{Type: notEqualToken, Value: "!="},
 			wantPosition: 2,
 		},
+		{
+			name:         "comparison operator",
+			input:        "<=",
+			want:         Token{Type: lessThanOrEqualToken, Value: "<="},
+			wantPosition: 2,
+		},
+		{
+			name:         "comparison operator with number",
+			input:        ">5",
+			want:         Token{Type: greaterThanToken, Value: ">"},
+			wantPosition: 2,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -279,6 +291,41 @@ func TestLexIdentifiers(t *testing.T) {
 			input: "asdf.",
 			want:  Token{Type: errorToken, Value: `end of input while scanning identifier: "asdf."`},
 		},
+		{
+			name:  "number",
+			input: "1700000000 ",
+			want:  Token{Type: numberToken, Value: "1700000000"},
+		},
+		{
+			name:  "decimal number",
+			input: "0.5",
+			want:  Token{Type: numberToken, Value: "0.5"},
+		},
+		{
+			name:  "signed number with an exponent",
+			input: "-2.5e3]",
+			want:  Token{Type: numberToken, Value: "-2.5e3"},
+		},
+		{
+			name:  "number without integer part",
+			input: "+.5",
+			want:  Token{Type: numberToken, Value: "+.5"},
+		},
+		{
+			name:  "bad number",
+			input: "1.",
+			want:  Token{Type: errorToken, Value: `invalid number: "1."`},
+		},
+		{
+			name:  "bad exponent",
+			input: "1e+ ",
+			want:  Token{Type: errorToken, Value: `invalid number: "1e+"`},
+		},
+		{
+			name:  "hexadecimal number",
+			input: "0x10",
+			want:  Token{Type: errorToken, Value: `invalid rune: "0"`},
+		},
 		{
 			name:  "bad identifier 4",
 			input: ".asdf",
@@ -15,6 +15,14 @@ const (
 	NotInOperator Operator = "notin"
 	// MatchesOperator represents matches
 	MatchesOperator Operator = "matches"
+	// LessThanOperator represents <
+	LessThanOperator Operator = "<"
+	// LessThanOrEqualOperator represents <=
+	LessThanOrEqualOperator Operator = "<="
+	// GreaterThanOperator represents >
+	GreaterThanOperator Operator = ">"
+	// GreaterThanOrEqualOperator represents >=
+	GreaterThanOrEqualOperator Operator = ">="
 )
 
 type OperationType int
@@ -103,6 +111,14 @@ func (p *parser) parseOperator() (Operator, error) {
 		return NotInOperator, nil
 	case matchesToken:
 		return MatchesOperator, nil
+	case lessThanToken:
+		return LessThanOperator, nil
+	case lessThanOrEqualToken:
+		return LessThanOrEqualOperator, nil
+	case greaterThanToken:
+		return GreaterThanOperator, nil
+	case greaterThanOrEqualToken:
+		return GreaterThanOrEqualOperator, nil
 	default:
 		return "", fmt.Errorf("unexpected operator '%s' found", result.Value)
 	}
@@ -130,10 +146,21 @@ func (p *parser) parseOperation() (Operation, error) {
 		if err != nil {
 			return r, err
 		}
+	case LessThanOperator, LessThanOrEqualOperator, GreaterThanOperator, GreaterThanOrEqualOperator:
+		result := p.read()
+		switch result.Type {
+		case numberToken, stringToken:
+			if _, err := ParseNumber(result.Value); err != nil {
+				return r, fmt.Errorf("unexpected value '%s': expected a number", result.Value)
+			}
+			r.RValues = []string{result.Value}
+		default:
+			return r, fmt.Errorf("unexpected token '%s': expected a number", result.Value)
+		}
 	default:
 		result := p.read()
 		switch result.Type {
-		case identifierToken, stringToken, boolToken, matchesToken:
+		case identifierToken, stringToken, boolToken, matchesToken, numberToken:
 			r.RValues = []string{result.Value}
 		default:
 			return r, fmt.Errorf("unexpected token '%s': expected an identifier or literal value", result.Value)
@@ -159,7 +186,7 @@ func (p *parser) parseValues() ([]string, error) {
 	for {
 		result = p.read()
 		switch result.Type {
-		case identifierToken, stringToken:
+		case identifierToken, stringToken, numberToken:
 			values = append(values, result.Value)
 		case commaToken:
 			continue
@@ -99,6 +99,43 @@ func TestParse(t *testing.T) {
 				Operation{LValue: "foo", Operator: MatchesOperator, RValues: []string{"bar"}},
 			}},
 		},
+		{
+			name:  "range",
+			input: "event.timestamp >= 1700000000 && event.timestamp<1800000000",
+			want: &Selector{Operations: []Operation{
+				{LValue: "event.timestamp", Operator: GreaterThanOrEqualOperator, RValues: []string{"1700000000"}},
+				{LValue: "event.timestamp", Operator: LessThanOperator, RValues: []string{"1800000000"}},
+			}},
+		},
+		{
+			name:  "number",
+			input: "event.check.status == 2",
+			want: &Selector{Operations: []Operation{
+				{LValue: "event.check.status", Operator: DoubleEqualSignOperator, RValues: []string{"2"}},
+			}},
+		},
+		{
+			name:  "comparison to an exponent",
+			input: "event.timestamp > 1.7e9",
+			want: &Selector{Operations: []Operation{
+				{LValue: "event.timestamp", Operator: GreaterThanOperator, RValues: []string{"1.7e9"}},
+			}},
+		},
+		{
+			name:    "comparison to a string",
+			input:   "event.timestamp > foo",
+			wantErr: true,
+		},
+		{
+			name:    "comparison to NaN",
+			input:   "event.timestamp > 'NaN'",
+			wantErr: true,
+		},
+		{
+			name:    "comparison to a hexadecimal number",
+			input:   "event.timestamp > '0x10'",
+			wantErr: true,
+		},
 		{
 			name:  "#1485",
 			input: "\"my sub\" in check.subscriptions && check.publish == true",
@@ -1,9 +1,35 @@
 package selector
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
+// NumberPattern is the grammar of the numbers compared by the <, <=, > and >=
+// operators: optionally signed integers and decimals, with an optional
+// exponent. Inf, NaN and hexadecimal numbers are not numbers, and the exponent
+// is bounded so that the stores can compare the numbers without overflowing.
+const NumberPattern = `^[+-]?([0-9]+(\.[0-9]+)?|\.[0-9]+)([eE][+-]?[0-9]{1,3})?$`
+
+var numberRegexp = regexp.MustCompile(NumberPattern)
+
+// ParseNumber parses a number of the NumberPattern grammar.
+func ParseNumber(s string) (float64, error) {
+	if !numberRegexp.MatchString(s) {
+		return 0, fmt.Errorf("invalid number: %q", s)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return 0, err
+	}
+	// Numbers out of the range of float64 are rounded to infinity or zero,
+	// which still compare like the numbers
+	return f, nil
+}
+
 // Selector represents a field or label selector that declares one or more
 // operations.
 type Selector struct {
@@ -65,6 +91,14 @@ func matches(r Operation, set map[string]string) bool {
 		//  Make sure the set's value for the operation's l-value matches
 		//  the operation r-values
 		return matchesValue(set[r.LValue], r.RValues)
+	case LessThanOperator, LessThanOrEqualOperator, GreaterThanOperator, GreaterThanOrEqualOperator:
+		// Make sure the r-value set has the specified l-value
+		if !hasKey(set, r.LValue) {
+			return false
+		}
+		// Make sure the set's value for the operation's l-value is a number
+		// that compares to the operation r-value
+		return compareValue(set[r.LValue], r.Operator, r.RValues)
 	default:
 		return false
 	}
@@ -98,6 +132,34 @@ func matchesValue(value string, values []string) bool {
 	return false
 }
 
+// compareValue determines if the set value compares to the operation value,
+// both being numbers
+func compareValue(value string, operator Operator, values []string) bool {
+	if len(values) != 1 {
+		return false
+	}
+	a, err := ParseNumber(value)
+	if err != nil {
+		return false
+	}
+	b, err := ParseNumber(values[0])
+	if err != nil {
+		return false
+	}
+	switch operator {
+	case LessThanOperator:
+		return a < b
+	case LessThanOrEqualOperator:
+		return a <= b
+	case GreaterThanOperator:
+		return a > b
+	case GreaterThanOrEqualOperator:
+		return a >= b
+	default:
+		return false
+	}
+}
+
 // hasKeysInValues determines if the values contains an actual key of the set
 func hasKeysInValues(set map[string]string, values []string) bool {
 	// We only support a single value in the array
@@ -156,6 +156,36 @@ func TestSelector_Matches(t *testing.T) {
 			set:   nil,
 			want:  true,
 		},
+		{
+			name:  "greater than matches",
+			input: "event.timestamp > 1700000000",
+			set:   map[string]string{"event.timestamp": "1700000001"},
+			want:  true,
+		},
+		{
+			name:  "less than or equal doesn't match",
+			input: "event.check.status <= 1",
+			set:   map[string]string{"event.check.status": "2"},
+			want:  false,
+		},
+		{
+			name:  "comparison with a value that is not a number",
+			input: "object.name >= 1",
+			set:   map[string]string{"object.name": "foo"},
+			want:  false,
+		},
+		{
+			name:  "comparison with an exponent",
+			input: "object.size >= 1e3",
+			set:   map[string]string{"object.size": "+.5e4"},
+			want:  true,
+		},
+		{
+			name:  "comparison with infinity",
+			input: "object.size < 1",
+			set:   map[string]string{"object.size": "-Inf"},
+			want:  false,
+		},
 		{
 			name:  "nil map with matches",
 			input: "foo matches bar",
@@ -0,0 +1,289 @@
+// Package encryption encrypts the sensitive fields of the resources before
+// they are written to the store, and decrypts them transparently when they are
+// read.
+//
+// The fields are encrypted with envelope encryption: each write encrypts the
+// fields with a new random data encryption key (DEK), which is itself
+// encrypted by a key encryption key (KEK), read from a file or held by a KMS,
+// and stored next to the data. Rotating the KEK only requires to re-encrypt
+// the DEKs, which Rotate does by rewriting the sensitive resources.
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	corev2 "github.com/sensu/core/v2"
+)
+
+// envelopeMagic prefixes the encrypted values, so that they can be told from
+// the values written before the encryption was enabled.
+var envelopeMagic = []byte("sensu-enc:v1:")
+
+// maxCachedKeys is the number of decrypted data encryption keys kept in memory,
+// so that reading the same resources does not decrypt their keys again.
+const maxCachedKeys = 1024
+
+type typeKey struct {
+	APIVersion string
+	Type       string
+}
+
+var (
+	sensitiveFieldsMu sync.RWMutex
+	sensitiveFields   = map[typeKey][]string{
+		{APIVersion: "core/v2", Type: "APIKey"}:       {"hash"},
+		{APIVersion: "core/v3", Type: "SymmetricKey"}: {"value"},
+	}
+)
+
+// RegisterSensitiveFields registers fields of a resource type to encrypt in
+// the store. The fields are dot-separated paths in the JSON representation of
+// the resource, and must be strings, or bytes encoded in base64.
+func RegisterSensitiveFields(tm corev2.TypeMeta, paths ...string) {
+	sensitiveFieldsMu.Lock()
+	defer sensitiveFieldsMu.Unlock()
+	key := typeKey{APIVersion: tm.APIVersion, Type: tm.Type}
+	sensitiveFields[key] = append(sensitiveFields[key], paths...)
+}
+
+// SensitiveFields returns the fields of a resource type encrypted in the
+// store.
+func SensitiveFields(tm corev2.TypeMeta) []string {
+	sensitiveFieldsMu.RLock()
+	defer sensitiveFieldsMu.RUnlock()
+	return sensitiveFields[typeKey{APIVersion: tm.APIVersion, Type: tm.Type}]
+}
+
+// SensitiveTypes returns the resource types with sensitive fields.
+func SensitiveTypes() []corev2.TypeMeta {
+	sensitiveFieldsMu.RLock()
+	defer sensitiveFieldsMu.RUnlock()
+	types := make([]corev2.TypeMeta, 0, len(sensitiveFields))
+	for key := range sensitiveFields {
+		types = append(types, corev2.TypeMeta{APIVersion: key.APIVersion, Type: key.Type})
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if types[i].APIVersion != types[j].APIVersion {
+			return types[i].APIVersion < types[j].APIVersion
+		}
+		return types[i].Type < types[j].Type
+	})
+	return types
+}
+
+// envelope is an encrypted field value.
+type envelope struct {
+	// KEK is the ID of the key encryption key that encrypted Key.
+	KEK string `json:"kek"`
+
+	// Key is the encrypted data encryption key.
+	Key []byte `json:"key"`
+
+	// Nonce is the nonce of the AES-GCM encryption of Data.
+	Nonce []byte `json:"nonce"`
+
+	// Data is the encrypted value.
+	Data []byte `json:"data"`
+}
+
+// Encrypter encrypts and decrypts the sensitive fields of the resources.
+type Encrypter struct {
+	kek KEK
+
+	mu   sync.Mutex
+	keys map[string][]byte
+}
+
+// NewEncrypter returns an Encrypter whose data encryption keys are encrypted
+// by the given key encryption key.
+func NewEncrypter(kek KEK) *Encrypter {
+	return &Encrypter{
+		kek:  kek,
+		keys: make(map[string][]byte),
+	}
+}
+
+// IsEncrypted returns whether a field value is encrypted.
+func IsEncrypted(value string) bool {
+	b, err := base64.StdEncoding.DecodeString(value)
+	return err == nil && bytes.HasPrefix(b, envelopeMagic)
+}
+
+// EncryptFields encrypts the sensitive fields of the JSON representation of a
+// resource of the given type. The values already encrypted are kept as is.
+func (e *Encrypter) EncryptFields(ctx context.Context, tm corev2.TypeMeta, value []byte) ([]byte, error) {
+	paths := SensitiveFields(tm)
+	if len(paths) == 0 {
+		return value, nil
+	}
+	var dek, wrapped []byte
+	return transformFields(value, paths, func(plaintext string) (string, error) {
+		if IsEncrypted(plaintext) {
+			return plaintext, nil
+		}
+		if dek == nil {
+			dek = make([]byte, 32)
+			if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+				return "", err
+			}
+			var err error
+			if wrapped, err = e.kek.WrapKey(ctx, dek); err != nil {
+				return "", fmt.Errorf("couldn't encrypt the data encryption key: %s", err)
+			}
+		}
+		return seal(e.kek.ID(), dek, wrapped, plaintext)
+	})
+}
+
+// DecryptFields decrypts the sensitive fields of the JSON representation of a
+// resource of the given type. The values which are not encrypted are kept as
+// is.
+func (e *Encrypter) DecryptFields(ctx context.Context, tm corev2.TypeMeta, value []byte) ([]byte, error) {
+	paths := SensitiveFields(tm)
+	if len(paths) == 0 {
+		return value, nil
+	}
+	return transformFields(value, paths, func(ciphertext string) (string, error) {
+		if !IsEncrypted(ciphertext) {
+			return ciphertext, nil
+		}
+		return e.open(ctx, ciphertext)
+	})
+}
+
+func seal(kekID string, dek, wrapped []byte, plaintext string) (string, error) {
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	env := envelope{
+		KEK:   kekID,
+		Key:   wrapped,
+		Nonce: nonce,
+		Data:  gcm.Seal(nil, nonce, []byte(plaintext), nil),
+	}
+	b, err := json.Marshal(env)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(append(append([]byte{}, envelopeMagic...), b...)), nil
+}
+
+func (e *Encrypter) open(ctx context.Context, ciphertext string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	var env envelope
+	if err := json.Unmarshal(bytes.TrimPrefix(b, envelopeMagic), &env); err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %s", err)
+	}
+	dek, err := e.dataKey(ctx, env.KEK, env.Key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := gcm.Open(nil, env.Nonce, env.Data, nil)
+	if err != nil {
+		return "", fmt.Errorf("couldn't decrypt value: %s", err)
+	}
+	return string(plaintext), nil
+}
+
+// dataKey decrypts a data encryption key, or returns it from the cache.
+func (e *Encrypter) dataKey(ctx context.Context, kekID string, wrapped []byte) ([]byte, error) {
+	cacheKey := kekID + "/" + string(wrapped)
+	e.mu.Lock()
+	dek, ok := e.keys[cacheKey]
+	e.mu.Unlock()
+	if ok {
+		return dek, nil
+	}
+	dek, err := e.kek.UnwrapKey(ctx, kekID, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decrypt the data encryption key: %s", err)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.keys) >= maxCachedKeys {
+		e.keys = make(map[string][]byte)
+	}
+	e.keys[cacheKey] = dek
+	return dek, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// transformFields replaces the string values found at the given paths of a
+// JSON object with the result of fn. The paths which are not found are
+// ignored.
+func transformFields(value []byte, paths []string, fn func(string) (string, error)) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(value))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	changed := false
+	for _, path := range paths {
+		parent := doc
+		keys := strings.Split(path, ".")
+		for _, key := range keys[:len(keys)-1] {
+			parent, _ = parent[key].(map[string]interface{})
+			if parent == nil {
+				break
+			}
+		}
+		if parent == nil {
+			continue
+		}
+		last := keys[len(keys)-1]
+		field, ok := parent[last]
+		if !ok || field == nil {
+			continue
+		}
+		s, ok := field.(string)
+		if !ok {
+			return nil, fmt.Errorf("sensitive field %s is not a string", path)
+		}
+		if s == "" {
+			continue
+		}
+		result, err := fn(s)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %s", path, err)
+		}
+		if result != s {
+			parent[last] = result
+			changed = true
+		}
+	}
+	if !changed {
+		return value, nil
+	}
+	return json.Marshal(doc)
+}
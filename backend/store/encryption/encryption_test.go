@@ -0,0 +1,131 @@
+package encryption
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testKeyFile returns a key file whose keys are derived from their IDs.
+func testKeyFile(ids ...string) []byte {
+	var b strings.Builder
+	b.WriteString("# key encryption keys\n")
+	for _, id := range ids {
+		key := sha256.Sum256([]byte(id))
+		b.WriteString(id + ":" + base64.StdEncoding.EncodeToString(key[:]) + "\n")
+	}
+	return []byte(b.String())
+}
+
+func testKEK(t *testing.T, ids ...string) *FileKEK {
+	t.Helper()
+	kek, err := ParseFileKEK(testKeyFile(ids...))
+	require.NoError(t, err)
+	return kek
+}
+
+func TestParseFileKEK(t *testing.T) {
+	kek := testKEK(t, "new", "old")
+	assert.Equal(t, "new", kek.ID())
+
+	_, err := ParseFileKEK([]byte("# no key\n"))
+	assert.Error(t, err)
+	_, err = ParseFileKEK([]byte("short:" + base64.StdEncoding.EncodeToString([]byte("too short"))))
+	assert.Error(t, err)
+	_, err = ParseFileKEK([]byte("nokey"))
+	assert.Error(t, err)
+	_, err = ParseFileKEK(append(testKeyFile("a"), testKeyFile("a")...))
+	assert.Error(t, err)
+}
+
+func TestFileKEKWrapKey(t *testing.T) {
+	ctx := context.Background()
+	kek := testKEK(t, "a", "b")
+	dek := []byte("0123456789abcdef0123456789abcdef")
+	wrapped, err := kek.WrapKey(ctx, dek)
+	require.NoError(t, err)
+	assert.NotContains(t, string(wrapped), string(dek))
+
+	got, err := kek.UnwrapKey(ctx, "a", wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, dek, got)
+
+	_, err = kek.UnwrapKey(ctx, "b", wrapped)
+	assert.Error(t, err)
+	_, err = kek.UnwrapKey(ctx, "c", wrapped)
+	assert.Error(t, err)
+}
+
+func TestEncrypterFields(t *testing.T) {
+	ctx := context.Background()
+	tm := corev2.TypeMeta{APIVersion: "core/v2", Type: "APIKey"}
+	e := NewEncrypter(testKEK(t, "a"))
+
+	hash := base64.StdEncoding.EncodeToString([]byte("$2a$10$hash"))
+	value := []byte(`{"metadata":{"name":"key"},"username":"admin","created_at":1700000000000,"hash":"` + hash + `"}`)
+	encrypted, err := e.EncryptFields(ctx, tm, value)
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(encrypted, &doc))
+	assert.True(t, IsEncrypted(doc["hash"].(string)))
+	assert.Equal(t, "admin", doc["username"])
+	assert.Equal(t, 1700000000000.0, doc["created_at"])
+
+	// The values already encrypted are not encrypted again
+	again, err := e.EncryptFields(ctx, tm, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, encrypted, again)
+
+	decrypted, err := e.DecryptFields(ctx, tm, encrypted)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(value), string(decrypted))
+
+	// The plaintext values are kept as is
+	decrypted, err = e.DecryptFields(ctx, tm, value)
+	require.NoError(t, err)
+	assert.Equal(t, value, decrypted)
+
+	// The resources without sensitive fields are kept as is
+	other := corev2.TypeMeta{APIVersion: "core/v2", Type: "CheckConfig"}
+	unchanged, err := e.EncryptFields(ctx, other, value)
+	require.NoError(t, err)
+	assert.Equal(t, value, unchanged)
+
+	// The fields can only be decrypted with the key encryption key
+	_, err = NewEncrypter(testKEK(t, "b")).DecryptFields(ctx, tm, encrypted)
+	assert.Error(t, err)
+}
+
+func TestRegisterSensitiveFields(t *testing.T) {
+	ctx := context.Background()
+	tm := corev2.TypeMeta{APIVersion: "secrets/v1", Type: "TestProvider"}
+	RegisterSensitiveFields(tm, "client.token")
+	defer func() {
+		sensitiveFieldsMu.Lock()
+		delete(sensitiveFields, typeKey{APIVersion: tm.APIVersion, Type: tm.Type})
+		sensitiveFieldsMu.Unlock()
+	}()
+	assert.Contains(t, SensitiveTypes(), tm)
+
+	e := NewEncrypter(testKEK(t, "a"))
+	value := []byte(`{"client":{"address":"https://vault:8200","token":"s.secret"}}`)
+	encrypted, err := e.EncryptFields(ctx, tm, value)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encrypted), "s.secret")
+	assert.Contains(t, string(encrypted), "https://vault:8200")
+
+	decrypted, err := e.DecryptFields(ctx, tm, encrypted)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(value), string(decrypted))
+
+	_, err = e.EncryptFields(ctx, tm, []byte(`{"client":{"token":42}}`))
+	assert.Error(t, err)
+}
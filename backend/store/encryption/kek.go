@@ -0,0 +1,154 @@
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// KEK is a key encryption key, which encrypts the data encryption keys.
+type KEK interface {
+	// ID identifies the key which encrypts the new data encryption keys.
+	ID() string
+
+	// WrapKey encrypts a data encryption key.
+	WrapKey(ctx context.Context, dek []byte) ([]byte, error)
+
+	// UnwrapKey decrypts a data encryption key encrypted by the key of the
+	// given ID.
+	UnwrapKey(ctx context.Context, id string, wrapped []byte) ([]byte, error)
+}
+
+// Config configures the encryption of the sensitive fields in the store. The
+// key encryption key is either read from KeyFile, or held by the transit
+// secrets engine of the Vault server at KMSAddress.
+type Config struct {
+	// KeyFile is the path of the file of the key encryption keys.
+	KeyFile string
+
+	// KMSAddress is the address of the Vault server.
+	KMSAddress string
+
+	// KMSKey is the name of the transit key of the Vault server.
+	KMSKey string
+
+	// KMSToken is the token used to authenticate with the Vault server.
+	KMSToken string
+}
+
+// Enabled returns whether the sensitive fields are encrypted.
+func (c Config) Enabled() bool {
+	return c.KeyFile != "" || c.KMSAddress != ""
+}
+
+// NewKEK returns the key encryption key configured.
+func NewKEK(c Config) (KEK, error) {
+	switch {
+	case c.KeyFile != "" && c.KMSAddress != "":
+		return nil, errors.New("a key file and a KMS cannot both be configured")
+	case c.KeyFile != "":
+		return NewFileKEK(c.KeyFile)
+	case c.KMSAddress != "":
+		return NewVaultTransitKEK(c.KMSAddress, c.KMSKey, c.KMSToken)
+	default:
+		return nil, errors.New("no key encryption key configured")
+	}
+}
+
+// FileKEK is a key encryption key read from a file. Each line of the file is a
+// key, in the form <id>:<base64 encoded 32 bytes key>; empty lines and lines
+// starting with # are ignored. The first key encrypts the new data encryption
+// keys, the others only decrypt the keys they encrypted, which allows to
+// rotate the keys: prepend the new key, rotate, then remove the old key.
+type FileKEK struct {
+	primary string
+	keys    map[string][]byte
+}
+
+// NewFileKEK reads the key encryption keys of a file.
+func NewFileKEK(path string) (*FileKEK, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read the encryption key file: %s", err)
+	}
+	return ParseFileKEK(b)
+}
+
+// ParseFileKEK parses the content of a key file.
+func ParseFileKEK(b []byte) (*FileKEK, error) {
+	kek := &FileKEK{keys: make(map[string][]byte)}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		id, encoded, ok := strings.Cut(line, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("line %d: expected <id>:<base64 key>", n)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", n, err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("line %d: the key must be 32 bytes long, not %d", n, len(key))
+		}
+		if _, ok := kek.keys[id]; ok {
+			return nil, fmt.Errorf("line %d: duplicate key %q", n, id)
+		}
+		if kek.primary == "" {
+			kek.primary = id
+		}
+		kek.keys[id] = key
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if kek.primary == "" {
+		return nil, errors.New("the encryption key file has no key")
+	}
+	return kek, nil
+}
+
+// ID returns the ID of the first key of the file.
+func (k *FileKEK) ID() string {
+	return k.primary
+}
+
+// WrapKey encrypts a data encryption key with the first key of the file.
+func (k *FileKEK) WrapKey(_ context.Context, dek []byte) ([]byte, error) {
+	gcm, err := newGCM(k.keys[k.primary])
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, dek, nil), nil
+}
+
+// UnwrapKey decrypts a data encryption key with the key of the given ID.
+func (k *FileKEK) UnwrapKey(_ context.Context, id string, wrapped []byte) ([]byte, error) {
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown key encryption key %q", id)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < gcm.NonceSize() {
+		return nil, errors.New("invalid encrypted key")
+	}
+	nonce, ciphertext := wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
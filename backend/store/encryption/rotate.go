@@ -0,0 +1,75 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	apitools "github.com/sensu/sensu-api-tools"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/backend/store/v2/wrap"
+)
+
+// Rotate re-encrypts the sensitive fields of all the resources of the store
+// with the current key encryption key, including the fields written before
+// the encryption was enabled. It returns the number of resources rewritten.
+func Rotate(ctx context.Context, s *Store) (int, error) {
+	configStore := s.GetConfigStore()
+	rotated := 0
+	for _, tm := range SensitiveTypes() {
+		req, err := typeRequest(tm)
+		if err != nil {
+			// The type is not available in this backend
+			continue
+		}
+		list, err := configStore.List(ctx, req, nil)
+		if err != nil {
+			return rotated, fmt.Errorf("couldn't list %s: %s", tm.Type, err)
+		}
+		wrappers, ok := list.(wrap.List)
+		if !ok {
+			return rotated, fmt.Errorf("couldn't list %s: unexpected list type %T", tm.Type, list)
+		}
+		for _, w := range wrappers {
+			resource, err := w.Unwrap()
+			if err != nil {
+				return rotated, err
+			}
+			meta := resource.GetMetadata()
+			itemReq := req
+			itemReq.Namespace = meta.Namespace
+			itemReq.Name = meta.Name
+
+			// Write the resource like a new version of it, without the
+			// metadata added by the store when it was read.
+			rewritten := *w
+			rewritten.CreatedAt = time.Time{}
+			rewritten.UpdatedAt = time.Time{}
+			rewritten.DeletedAt = time.Time{}
+			rewritten.ETag = ""
+			if err := configStore.UpdateIfExists(ctx, itemReq, &rewritten); err != nil {
+				return rotated, fmt.Errorf("couldn't rotate %s %s: %s", tm.Type, meta.Name, err)
+			}
+			rotated++
+		}
+	}
+	return rotated, nil
+}
+
+// typeRequest returns the request of all the resources of a type.
+func typeRequest(tm corev2.TypeMeta) (storev2.ResourceRequest, error) {
+	value, err := apitools.Resolve(tm.APIVersion, tm.Type)
+	if err != nil {
+		return storev2.ResourceRequest{}, err
+	}
+	switch resource := value.(type) {
+	case corev3.Resource:
+		return storev2.NewResourceRequest(tm, "", "", resource.StoreName()), nil
+	case corev2.Resource:
+		return storev2.NewResourceRequest(tm, "", "", resource.StorePrefix()), nil
+	default:
+		return storev2.ResourceRequest{}, fmt.Errorf("%T is not a resource", value)
+	}
+}
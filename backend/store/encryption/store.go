@@ -0,0 +1,273 @@
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/store/patch"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/backend/store/v2/wrap"
+)
+
+// Store is a store whose configuration store encrypts the sensitive fields of
+// the resources.
+type Store struct {
+	storev2.Interface
+	encrypter *Encrypter
+}
+
+// NewStore returns a store which encrypts the sensitive fields of the
+// resources of the given store.
+func NewStore(s storev2.Interface, encrypter *Encrypter) *Store {
+	return &Store{Interface: s, encrypter: encrypter}
+}
+
+// GetConfigStore returns the configuration store, which encrypts the
+// sensitive fields of the resources written, and decrypts those of the
+// resources read.
+func (s *Store) GetConfigStore() storev2.ConfigStore {
+	return &ConfigStore{ConfigStore: s.Interface.GetConfigStore(), encrypter: s.encrypter}
+}
+
+// ConfigStore is a configuration store which encrypts the sensitive fields of
+// the resources written, and decrypts those of the resources read. The
+// resources without sensitive fields are passed through.
+type ConfigStore struct {
+	storev2.ConfigStore
+	encrypter *Encrypter
+}
+
+func typeMeta(req storev2.ResourceRequest) corev2.TypeMeta {
+	return corev2.TypeMeta{APIVersion: req.APIVersion, Type: req.Type}
+}
+
+func storeKey(req storev2.ResourceRequest) string {
+	return store.NewKeyBuilder(req.StoreName).WithNamespace(req.Namespace).Build(req.Name)
+}
+
+func (s *ConfigStore) sensitive(req storev2.ResourceRequest) bool {
+	return len(SensitiveFields(typeMeta(req))) > 0
+}
+
+// jsonValue returns the JSON representation of a wrapped resource.
+func jsonValue(w storev2.Wrapper) ([]byte, error) {
+	wrapper, ok := w.(*wrap.Wrapper)
+	if !ok {
+		resource, err := w.Unwrap()
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(resource)
+	}
+	if wrapper.Encoding == wrap.Encoding_json {
+		return wrapper.Compression.Decompress(wrapper.Value)
+	}
+	resource, err := wrapper.UnwrapRaw()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(resource)
+}
+
+// withValue returns a copy of a wrapper with the given JSON value.
+func withValue(w storev2.Wrapper, tm corev2.TypeMeta, value []byte) storev2.Wrapper {
+	result := wrap.Wrapper{TypeMeta: &tm}
+	if wrapper, ok := w.(*wrap.Wrapper); ok {
+		result = *wrapper
+	}
+	result.Encoding = wrap.Encoding_json
+	result.Compression = wrap.Compression_none
+	result.Value = value
+	return &result
+}
+
+func (s *ConfigStore) encrypt(ctx context.Context, req storev2.ResourceRequest, w storev2.Wrapper) (storev2.Wrapper, error) {
+	if !s.sensitive(req) {
+		return w, nil
+	}
+	value, err := jsonValue(w)
+	if err != nil {
+		return nil, &store.ErrEncode{Key: storeKey(req), Err: err}
+	}
+	tm := typeMeta(req)
+	encrypted, err := s.encrypter.EncryptFields(ctx, tm, value)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	return withValue(w, tm, encrypted), nil
+}
+
+func (s *ConfigStore) decrypt(ctx context.Context, req storev2.ResourceRequest, w storev2.Wrapper) (storev2.Wrapper, error) {
+	if w == nil || !s.sensitive(req) {
+		return w, nil
+	}
+	wrapper, ok := w.(*wrap.Wrapper)
+	if !ok {
+		return w, nil
+	}
+	value, err := jsonValue(wrapper)
+	if err != nil {
+		return nil, &store.ErrDecode{Key: storeKey(req), Err: err}
+	}
+	tm := typeMeta(req)
+	if wrapper.TypeMeta != nil {
+		tm = *wrapper.TypeMeta
+	}
+	decrypted, err := s.encrypter.DecryptFields(ctx, tm, value)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	return withValue(wrapper, tm, decrypted), nil
+}
+
+// CreateOrUpdate creates or updates the wrapped resource.
+func (s *ConfigStore) CreateOrUpdate(ctx context.Context, req storev2.ResourceRequest, w storev2.Wrapper) error {
+	w, err := s.encrypt(ctx, req, w)
+	if err != nil {
+		return err
+	}
+	return s.ConfigStore.CreateOrUpdate(ctx, req, w)
+}
+
+// BatchCreateOrUpdate creates or updates the wrapped resources in a single
+// transaction.
+func (s *ConfigStore) BatchCreateOrUpdate(ctx context.Context, reqs []storev2.ResourceRequest, wrappers []storev2.Wrapper) error {
+	encrypted := make([]storev2.Wrapper, len(wrappers))
+	for i, w := range wrappers {
+		var err error
+		if i < len(reqs) {
+			if w, err = s.encrypt(ctx, reqs[i], w); err != nil {
+				return err
+			}
+		}
+		encrypted[i] = w
+	}
+	return s.ConfigStore.BatchCreateOrUpdate(ctx, reqs, encrypted)
+}
+
+// UpdateIfExists updates the resource with the wrapped resource, but only if
+// it already exists in the store.
+func (s *ConfigStore) UpdateIfExists(ctx context.Context, req storev2.ResourceRequest, w storev2.Wrapper) error {
+	w, err := s.encrypt(ctx, req, w)
+	if err != nil {
+		return err
+	}
+	return s.ConfigStore.UpdateIfExists(ctx, req, w)
+}
+
+// CreateIfNotExists writes the wrapped resource to the store, but only if it
+// does not already exist.
+func (s *ConfigStore) CreateIfNotExists(ctx context.Context, req storev2.ResourceRequest, w storev2.Wrapper) error {
+	w, err := s.encrypt(ctx, req, w)
+	if err != nil {
+		return err
+	}
+	return s.ConfigStore.CreateIfNotExists(ctx, req, w)
+}
+
+// Get gets a wrapped resource from the store.
+func (s *ConfigStore) Get(ctx context.Context, req storev2.ResourceRequest) (storev2.Wrapper, error) {
+	w, err := s.ConfigStore.Get(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(ctx, req, w)
+}
+
+// List lists all resources specified by the resource request, and the
+// selection predicate.
+func (s *ConfigStore) List(ctx context.Context, req storev2.ResourceRequest, pred *store.SelectionPredicate) (storev2.WrapList, error) {
+	list, err := s.ConfigStore.List(ctx, req, pred)
+	if err != nil || !s.sensitive(req) {
+		return list, err
+	}
+	wrappers, ok := list.(wrap.List)
+	if !ok {
+		return list, nil
+	}
+	result := make(wrap.List, len(wrappers))
+	for i, w := range wrappers {
+		decrypted, err := s.decrypt(ctx, req, w)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = decrypted.(*wrap.Wrapper)
+	}
+	return result, nil
+}
+
+// Patch patches the resource. The patches of the resources with sensitive
+// fields are applied to the decrypted resource, which is then encrypted
+// again, in the transaction of the patch of the underlying store.
+func (s *ConfigStore) Patch(ctx context.Context, req storev2.ResourceRequest, patcher patch.Patcher) error {
+	if !s.sensitive(req) {
+		return s.ConfigStore.Patch(ctx, req, patcher)
+	}
+	return s.ConfigStore.Patch(ctx, req, &encryptingPatcher{
+		ctx:       ctx,
+		tm:        typeMeta(req),
+		encrypter: s.encrypter,
+		patcher:   patcher,
+	})
+}
+
+// encryptingPatcher applies a patch to the decrypted document of a resource
+// with sensitive fields, and encrypts the patched document.
+type encryptingPatcher struct {
+	ctx       context.Context
+	tm        corev2.TypeMeta
+	encrypter *Encrypter
+	patcher   patch.Patcher
+}
+
+// Patch implements patch.Patcher.
+func (p *encryptingPatcher) Patch(document []byte) ([]byte, error) {
+	decrypted, err := p.encrypter.DecryptFields(p.ctx, p.tm, document)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := p.patcher.Patch(decrypted)
+	if err != nil {
+		return nil, err
+	}
+	return p.encrypter.EncryptFields(p.ctx, p.tm, patched)
+}
+
+// Watch watches the resources, and decrypts those of the watch events.
+func (s *ConfigStore) Watch(ctx context.Context, req storev2.ResourceRequest) <-chan []storev2.WatchEvent {
+	events := s.ConfigStore.Watch(ctx, req)
+	if events == nil || !s.sensitive(req) {
+		return events
+	}
+	result := make(chan []storev2.WatchEvent)
+	go func() {
+		defer close(result)
+		for batch := range events {
+			for i := range batch {
+				var err error
+				if batch[i].Value, err = s.decrypt(ctx, req, batch[i].Value); err != nil {
+					batch[i].Err = err
+				}
+				if batch[i].PreviousValue, err = s.decrypt(ctx, req, batch[i].PreviousValue); err != nil {
+					batch[i].Err = err
+				}
+			}
+			select {
+			case result <- batch:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return result
+}
+
+// Initialize initializes the store, with an initialization function whose
+// store encrypts the sensitive fields.
+func (s *ConfigStore) Initialize(ctx context.Context, fn storev2.InitializeFunc) error {
+	return s.ConfigStore.Initialize(ctx, func(ctx context.Context, st storev2.Interface) error {
+		return fn(ctx, NewStore(st, s.encrypter))
+	})
+}
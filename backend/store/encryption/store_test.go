@@ -0,0 +1,163 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/store/patch"
+	"github.com/sensu/sensu-go/backend/store/sqlite"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/backend/store/v2/wrap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withSQLiteStore(t *testing.T, fn func(context.Context, storev2.Interface)) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	db, err := sqlite.Open(ctx, ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	fn(ctx, sqlite.NewStore(sqlite.StoreConfig{DB: db}))
+}
+
+func symmetricKey(name string, value string) *corev3.SymmetricKey {
+	return &corev3.SymmetricKey{Metadata: corev2.NewObjectMetaP(name, ""), Value: []byte(value)}
+}
+
+// storedValue returns the value of the symmetric key stored in the underlying
+// store.
+func storedValue(t *testing.T, ctx context.Context, s storev2.Interface, name string) string {
+	t.Helper()
+	req := storev2.NewResourceRequestFromResource(symmetricKey(name, ""))
+	w, err := s.GetConfigStore().Get(ctx, req)
+	require.NoError(t, err)
+	value, err := jsonValue(w)
+	require.NoError(t, err)
+	var doc struct {
+		Value string `json:"value"`
+	}
+	require.NoError(t, json.Unmarshal(value, &doc))
+	return doc.Value
+}
+
+func TestStore(t *testing.T) {
+	withSQLiteStore(t, func(ctx context.Context, raw storev2.Interface) {
+		s := NewStore(raw, NewEncrypter(testKEK(t, "a")))
+		keys := storev2.Of[*corev3.SymmetricKey](s)
+
+		require.NoError(t, keys.CreateOrUpdate(ctx, symmetricKey("jwt", "secret")))
+		assert.True(t, IsEncrypted(storedValue(t, ctx, raw, "jwt")))
+
+		key, err := keys.Get(ctx, storev2.ID{Name: "jwt"})
+		require.NoError(t, err)
+		assert.Equal(t, []byte("secret"), key.Value)
+
+		list, err := keys.List(ctx, storev2.ID{}, nil)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, []byte("secret"), list[0].Value)
+
+		// The patches apply to the decrypted resource
+		req := storev2.NewResourceRequestFromResource(key)
+		merge := &patch.Merge{MergePatch: []byte(`{"metadata":{"labels":{"region":"us"}}}`)}
+		require.NoError(t, s.GetConfigStore().Patch(ctx, req, merge))
+		assert.True(t, IsEncrypted(storedValue(t, ctx, raw, "jwt")))
+		key, err = keys.Get(ctx, storev2.ID{Name: "jwt"})
+		require.NoError(t, err)
+		assert.Equal(t, []byte("secret"), key.Value)
+		assert.Equal(t, "us", key.Metadata.Labels["region"])
+
+		// The patches of the sensitive fields are encrypted, and the
+		// preconditions of the patches apply to the stored resource
+		merge = &patch.Merge{MergePatch: []byte(`{"value":"cm90YXRlZA=="}`)}
+		ifMatch := storev2.ContextWithIfMatch(ctx, storev2.IfMatch{storev2.ETag("stale")})
+		var precondErr *store.ErrPreconditionFailed
+		require.ErrorAs(t, s.GetConfigStore().Patch(ifMatch, req, merge), &precondErr)
+		require.NoError(t, s.GetConfigStore().Patch(ctx, req, merge))
+		assert.True(t, IsEncrypted(storedValue(t, ctx, raw, "jwt")))
+		key, err = keys.Get(ctx, storev2.ID{Name: "jwt"})
+		require.NoError(t, err)
+		assert.Equal(t, []byte("rotated"), key.Value)
+		assert.Equal(t, "us", key.Metadata.Labels["region"])
+
+		// The resources written before the encryption was enabled are read
+		// as is
+		require.NoError(t, storev2.Of[*corev3.SymmetricKey](raw).CreateOrUpdate(ctx, symmetricKey("legacy", "plaintext")))
+		key, err = keys.Get(ctx, storev2.ID{Name: "legacy"})
+		require.NoError(t, err)
+		assert.Equal(t, []byte("plaintext"), key.Value)
+	})
+}
+
+func TestStoreAPIKeys(t *testing.T) {
+	withSQLiteStore(t, func(ctx context.Context, raw storev2.Interface) {
+		s := NewStore(raw, NewEncrypter(testKEK(t, "a")))
+		apiKey := &corev2.APIKey{
+			ObjectMeta: corev2.ObjectMeta{Name: "83abef1e-e7d7-4beb-91fc-79ad90084d5b"},
+			Username:   "admin",
+			Hash:       []byte("$2a$10$hash"),
+		}
+		require.NoError(t, storev2.Of[*corev2.APIKey](s).CreateOrUpdate(ctx, apiKey))
+
+		stored, err := storev2.Of[*corev2.APIKey](raw).Get(ctx, storev2.ID{Name: apiKey.Name})
+		require.NoError(t, err)
+		assert.True(t, IsEncrypted(base64.StdEncoding.EncodeToString(stored.Hash)))
+
+		got, err := storev2.Of[*corev2.APIKey](s).Get(ctx, storev2.ID{Name: apiKey.Name})
+		require.NoError(t, err)
+		assert.Equal(t, apiKey.Hash, got.Hash)
+		assert.Equal(t, "admin", got.Username)
+	})
+}
+
+func TestStoreInitialize(t *testing.T) {
+	withSQLiteStore(t, func(ctx context.Context, raw storev2.Interface) {
+		s := NewStore(raw, NewEncrypter(testKEK(t, "a")))
+		err := s.GetConfigStore().Initialize(ctx, func(ctx context.Context, st storev2.Interface) error {
+			return storev2.Of[*corev3.SymmetricKey](st).CreateIfNotExists(ctx, symmetricKey("jwt", "secret"))
+		})
+		require.NoError(t, err)
+		assert.True(t, IsEncrypted(storedValue(t, ctx, raw, "jwt")))
+	})
+}
+
+func TestRotate(t *testing.T) {
+	withSQLiteStore(t, func(ctx context.Context, raw storev2.Interface) {
+		require.NoError(t, storev2.Of[*corev3.SymmetricKey](raw).CreateOrUpdate(ctx, symmetricKey("legacy", "plaintext")))
+		old := NewStore(raw, NewEncrypter(testKEK(t, "old")))
+		require.NoError(t, storev2.Of[*corev3.SymmetricKey](old).CreateOrUpdate(ctx, symmetricKey("jwt", "secret")))
+
+		// The new key is first, the old one only decrypts
+		s := NewStore(raw, NewEncrypter(testKEK(t, "new", "old")))
+		rotated, err := Rotate(ctx, s)
+		require.NoError(t, err)
+		assert.Equal(t, 2, rotated)
+		assert.True(t, IsEncrypted(storedValue(t, ctx, raw, "legacy")))
+
+		// The old key is no longer needed
+		s = NewStore(raw, NewEncrypter(testKEK(t, "new")))
+		keys := storev2.Of[*corev3.SymmetricKey](s)
+		for name, want := range map[string]string{"legacy": "plaintext", "jwt": "secret"} {
+			key, err := keys.Get(ctx, storev2.ID{Name: name})
+			require.NoError(t, err)
+			assert.Equal(t, []byte(want), key.Value)
+		}
+	})
+}
+
+func TestWithValue(t *testing.T) {
+	tm := corev2.TypeMeta{APIVersion: "core/v3", Type: "SymmetricKey"}
+	w := &wrap.Wrapper{TypeMeta: &tm, Encoding: wrap.Encoding_protobuf, Compression: wrap.Compression_snappy, ETag: "etag"}
+	got := withValue(w, tm, []byte("{}")).(*wrap.Wrapper)
+	assert.Equal(t, wrap.Encoding_json, got.Encoding)
+	assert.Equal(t, wrap.Compression_none, got.Compression)
+	assert.Equal(t, "etag", got.ETag)
+	assert.Equal(t, wrap.Compression_snappy, w.Compression)
+}
@@ -0,0 +1,104 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// vaultTimeout is the timeout of the requests to the Vault server.
+const vaultTimeout = 10 * time.Second
+
+// VaultTransitKEK is a key encryption key held by the transit secrets engine
+// of a Vault server, which encrypts and decrypts the data encryption keys.
+// Rotating the transit key in Vault, then rotating the store, re-encrypts the
+// data encryption keys with the latest version of the key.
+type VaultTransitKEK struct {
+	address string
+	key     string
+	token   string
+	client  *http.Client
+}
+
+// NewVaultTransitKEK returns the key encryption key of the given transit key
+// of a Vault server.
+func NewVaultTransitKEK(address, key, token string) (*VaultTransitKEK, error) {
+	if _, err := url.Parse(address); err != nil {
+		return nil, fmt.Errorf("invalid KMS address: %s", err)
+	}
+	if key == "" {
+		return nil, errors.New("the name of the KMS key is required")
+	}
+	return &VaultTransitKEK{
+		address: strings.TrimSuffix(address, "/"),
+		key:     key,
+		token:   token,
+		client:  &http.Client{Timeout: vaultTimeout},
+	}, nil
+}
+
+// ID returns the ID of the transit key.
+func (k *VaultTransitKEK) ID() string {
+	return "vault-transit:" + k.key
+}
+
+// WrapKey encrypts a data encryption key with the transit key.
+func (k *VaultTransitKEK) WrapKey(ctx context.Context, dek []byte) ([]byte, error) {
+	var resp struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	req := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dek)}
+	if err := k.do(ctx, "encrypt", req, &resp); err != nil {
+		return nil, err
+	}
+	return []byte(resp.Data.Ciphertext), nil
+}
+
+// UnwrapKey decrypts a data encryption key with the transit key.
+func (k *VaultTransitKEK) UnwrapKey(ctx context.Context, id string, wrapped []byte) ([]byte, error) {
+	if id != k.ID() {
+		return nil, fmt.Errorf("unknown key encryption key %q", id)
+	}
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	req := map[string]string{"ciphertext": string(wrapped)}
+	if err := k.do(ctx, "decrypt", req, &resp); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+}
+
+func (k *VaultTransitKEK) do(ctx context.Context, operation string, body, result interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf("%s/v1/transit/%s/%s", k.address, operation, url.PathEscape(k.key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", k.token)
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("KMS %s request failed with status %d", operation, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,78 @@
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTransit is a transit secrets engine which "encrypts" by prefixing the
+// plaintext.
+func fakeTransit(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var req map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var data map[string]string
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/sensu":
+			data = map[string]string{"ciphertext": "vault:v1:" + req["plaintext"]}
+		case "/v1/transit/decrypt/sensu":
+			data = map[string]string{"plaintext": strings.TrimPrefix(req["ciphertext"], "vault:v1:")}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+}
+
+func TestVaultTransitKEK(t *testing.T) {
+	ctx := context.Background()
+	server := fakeTransit(t)
+	defer server.Close()
+
+	kek, err := NewKEK(Config{KMSAddress: server.URL + "/", KMSKey: "sensu", KMSToken: "token"})
+	require.NoError(t, err)
+	assert.Equal(t, "vault-transit:sensu", kek.ID())
+
+	dek := []byte("0123456789abcdef0123456789abcdef")
+	wrapped, err := kek.WrapKey(ctx, dek)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(wrapped), "vault:v1:"))
+
+	got, err := kek.UnwrapKey(ctx, kek.ID(), wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, dek, got)
+
+	_, err = kek.UnwrapKey(ctx, "vault-transit:other", wrapped)
+	assert.Error(t, err)
+
+	denied, err := NewVaultTransitKEK(server.URL, "sensu", "wrong")
+	require.NoError(t, err)
+	_, err = denied.WrapKey(ctx, dek)
+	assert.Error(t, err)
+}
+
+func TestNewKEK(t *testing.T) {
+	_, err := NewKEK(Config{})
+	assert.Error(t, err)
+	_, err = NewKEK(Config{KeyFile: "keys", KMSAddress: "https://vault:8200"})
+	assert.Error(t, err)
+	_, err = NewKEK(Config{KMSAddress: "https://vault:8200"})
+	assert.Error(t, err)
+	assert.False(t, Config{}.Enabled())
+	assert.True(t, Config{KeyFile: "keys"}.Enabled())
+}
@@ -0,0 +1,7 @@
+package history
+
+import "github.com/sirupsen/logrus"
+
+var logger = logrus.WithFields(logrus.Fields{
+	"component": "resource-history",
+})
@@ -0,0 +1,183 @@
+// Package history records the versions of the configuration resources written
+// to the store, along with their author, so that they can be inspected and
+// rolled back.
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/store/patch"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/backend/store/v2/wrap"
+)
+
+// Store is a store whose configuration store records the versions of the
+// resources written.
+type Store struct {
+	storev2.Interface
+}
+
+// NewStore returns a store which records the versions of the resources
+// written to the given store.
+func NewStore(s storev2.Interface) *Store {
+	return &Store{Interface: s}
+}
+
+// GetConfigStore returns the configuration store, which records the versions
+// of the resources written and deleted.
+func (s *Store) GetConfigStore() storev2.ConfigStore {
+	return &ConfigStore{ConfigStore: s.Interface.GetConfigStore(), history: s.GetResourceHistoryStore()}
+}
+
+// ConfigStore is a configuration store which records a version of the
+// resources each time they are written or deleted. Failing to record a
+// version does not fail the write.
+type ConfigStore struct {
+	storev2.ConfigStore
+	history storev2.ResourceHistoryStore
+}
+
+type withoutRecordKey struct{}
+
+// ContextWithoutRecord returns a context whose writes are not recorded, such
+// as the bookkeeping writes of the backend which are not versions of the
+// resources.
+func ContextWithoutRecord(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutRecordKey{}, true)
+}
+
+// author returns the name of the user of the request being served, if any.
+func author(ctx context.Context) string {
+	if claims, ok := ctx.Value(corev2.ClaimsKey).(*corev2.Claims); ok && claims != nil {
+		return claims.StandardClaims.Subject
+	}
+	return ""
+}
+
+// resourceJSON returns the JSON representation of a wrapped resource, without
+// the labels and annotations added by the store when it is read, which some
+// writes, like the patches, store along with the resource.
+func resourceJSON(w storev2.Wrapper) ([]byte, error) {
+	var resource interface{}
+	var err error
+	if wrapper, ok := w.(*wrap.Wrapper); ok {
+		resource, err = wrapper.UnwrapRaw()
+	} else {
+		resource, err = w.Unwrap()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r, ok := resource.(interface{ GetMetadata() *corev2.ObjectMeta }); ok {
+		if meta := r.GetMetadata(); meta != nil {
+			delete(meta.Labels, store.SensuCreatedAtKey)
+			delete(meta.Labels, store.SensuUpdatedAtKey)
+			delete(meta.Labels, store.SensuDeletedAtKey)
+			delete(meta.Annotations, store.SensuETagKey)
+		}
+	}
+	return json.Marshal(resource)
+}
+
+// record records a version of the resource of the request. A nil wrapper
+// records its deletion.
+func (s *ConfigStore) record(ctx context.Context, req storev2.ResourceRequest, w storev2.Wrapper) {
+	if skip, _ := ctx.Value(withoutRecordKey{}).(bool); skip {
+		return
+	}
+	var resource []byte
+	if w != nil {
+		var err error
+		if resource, err = resourceJSON(w); err != nil {
+			logger.WithError(err).WithField("type", req.Type).WithField("name", req.Name).Error("failed to encode resource version")
+			return
+		}
+	}
+	version := storev2.NewResourceVersion(req, author(ctx), time.Now().Unix(), resource)
+	if err := s.history.AddResourceVersion(ctx, version); err != nil {
+		logger.WithError(err).WithField("type", req.Type).WithField("name", req.Name).Error("failed to record resource version")
+	}
+}
+
+// CreateOrUpdate creates or updates the wrapped resource.
+func (s *ConfigStore) CreateOrUpdate(ctx context.Context, req storev2.ResourceRequest, w storev2.Wrapper) error {
+	if err := s.ConfigStore.CreateOrUpdate(ctx, req, w); err != nil {
+		return err
+	}
+	s.record(ctx, req, w)
+	return nil
+}
+
+// BatchCreateOrUpdate creates or updates the wrapped resources in a single
+// transaction.
+func (s *ConfigStore) BatchCreateOrUpdate(ctx context.Context, reqs []storev2.ResourceRequest, wrappers []storev2.Wrapper) error {
+	if err := s.ConfigStore.BatchCreateOrUpdate(ctx, reqs, wrappers); err != nil {
+		return err
+	}
+	for i, req := range reqs {
+		if i < len(wrappers) {
+			s.record(ctx, req, wrappers[i])
+		}
+	}
+	return nil
+}
+
+// UpdateIfExists updates the resource with the wrapped resource, but only if
+// it already exists in the store.
+func (s *ConfigStore) UpdateIfExists(ctx context.Context, req storev2.ResourceRequest, w storev2.Wrapper) error {
+	if err := s.ConfigStore.UpdateIfExists(ctx, req, w); err != nil {
+		return err
+	}
+	s.record(ctx, req, w)
+	return nil
+}
+
+// CreateIfNotExists writes the wrapped resource to the store, but only if it
+// does not already exist.
+func (s *ConfigStore) CreateIfNotExists(ctx context.Context, req storev2.ResourceRequest, w storev2.Wrapper) error {
+	if err := s.ConfigStore.CreateIfNotExists(ctx, req, w); err != nil {
+		return err
+	}
+	s.record(ctx, req, w)
+	return nil
+}
+
+// Delete deletes a resource from the store.
+func (s *ConfigStore) Delete(ctx context.Context, req storev2.ResourceRequest) error {
+	if err := s.ConfigStore.Delete(ctx, req); err != nil {
+		return err
+	}
+	s.record(ctx, req, nil)
+	return nil
+}
+
+// Patch patches the resource, and records the patched resource.
+func (s *ConfigStore) Patch(ctx context.Context, req storev2.ResourceRequest, patcher patch.Patcher) error {
+	if err := s.ConfigStore.Patch(ctx, req, patcher); err != nil {
+		return err
+	}
+	// The preconditions of the patch, and the records of its transaction,
+	// do not apply to reading the patched resource
+	getCtx := storev2.ContextWithTxInfo(ctx, nil)
+	getCtx = storev2.ContextWithIfMatch(getCtx, nil)
+	getCtx = storev2.ContextWithIfNoneMatch(getCtx, nil)
+	w, err := s.ConfigStore.Get(getCtx, req)
+	if err != nil {
+		logger.WithError(err).WithField("type", req.Type).WithField("name", req.Name).Error("failed to get patched resource")
+		return nil
+	}
+	s.record(ctx, req, w)
+	return nil
+}
+
+// Initialize initializes the store, with an initialization function whose
+// store records the versions of the resources.
+func (s *ConfigStore) Initialize(ctx context.Context, fn storev2.InitializeFunc) error {
+	return s.ConfigStore.Initialize(ctx, func(ctx context.Context, st storev2.Interface) error {
+		return fn(ctx, NewStore(st))
+	})
+}
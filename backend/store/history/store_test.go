@@ -0,0 +1,120 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/store/patch"
+	"github.com/sensu/sensu-go/backend/store/sqlite"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withSQLiteStore(t *testing.T, fn func(context.Context, storev2.Interface)) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	db, err := sqlite.Open(ctx, ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	fn(ctx, sqlite.NewStore(sqlite.StoreConfig{DB: db}))
+}
+
+func withUser(ctx context.Context, username string) context.Context {
+	claims := &corev2.Claims{}
+	claims.StandardClaims.Subject = username
+	return context.WithValue(ctx, corev2.ClaimsKey, claims)
+}
+
+func TestStore(t *testing.T) {
+	withSQLiteStore(t, func(ctx context.Context, raw storev2.Interface) {
+		s := NewStore(raw)
+		checks := storev2.Of[*corev2.CheckConfig](s)
+		check := corev2.FixtureCheckConfig("check")
+		id := storev2.ID{Namespace: check.Namespace, Name: check.Name}
+
+		require.NoError(t, checks.CreateOrUpdate(withUser(ctx, "alice"), check))
+		// The unchanged resources are only recorded once
+		require.NoError(t, checks.CreateOrUpdate(withUser(ctx, "alice"), check))
+		check.Interval = 120
+		require.NoError(t, checks.UpdateIfExists(withUser(ctx, "bob"), check))
+
+		req := storev2.NewResourceRequestFromResource(check)
+		merge := &patch.Merge{MergePatch: []byte(`{"interval":30}`)}
+		require.NoError(t, s.GetConfigStore().Patch(withUser(ctx, "carol"), req, merge))
+		require.NoError(t, checks.Delete(ctx, id))
+
+		versions, err := checks.History(ctx, id)
+		require.NoError(t, err)
+		require.Len(t, versions, 4)
+
+		assert.True(t, versions[0].Deleted)
+		assert.Equal(t, "", versions[0].Author)
+		assert.Equal(t, int64(4), versions[0].Version)
+
+		authors := []string{}
+		intervals := []uint32{}
+		for _, version := range versions[1:] {
+			var recorded corev2.CheckConfig
+			require.NoError(t, json.Unmarshal(version.Resource, &recorded))
+			assert.NotContains(t, recorded.Labels, store.SensuCreatedAtKey)
+			assert.NotContains(t, recorded.Annotations, store.SensuETagKey)
+			authors = append(authors, version.Author)
+			intervals = append(intervals, recorded.Interval)
+		}
+		assert.Equal(t, []string{"carol", "bob", "alice"}, authors)
+		assert.Equal(t, []uint32{30, 120, 60}, intervals)
+	})
+}
+
+func TestStoreWithoutRecord(t *testing.T) {
+	withSQLiteStore(t, func(ctx context.Context, raw storev2.Interface) {
+		s := NewStore(raw)
+		checks := storev2.Of[*corev2.CheckConfig](s)
+		check := corev2.FixtureCheckConfig("check")
+		id := storev2.ID{Namespace: check.Namespace, Name: check.Name}
+
+		require.NoError(t, checks.CreateOrUpdate(ctx, check))
+		merge := &patch.Merge{MergePatch: []byte(`{"interval":30}`)}
+		require.NoError(t, checks.Patch(ContextWithoutRecord(ctx), id, merge))
+
+		versions, err := checks.History(ctx, id)
+		require.NoError(t, err)
+		require.Len(t, versions, 1)
+		var recorded corev2.CheckConfig
+		require.NoError(t, json.Unmarshal(versions[0].Resource, &recorded))
+		assert.Equal(t, uint32(60), recorded.Interval)
+	})
+}
+
+func TestStoreFailedWrite(t *testing.T) {
+	withSQLiteStore(t, func(ctx context.Context, raw storev2.Interface) {
+		s := NewStore(raw)
+		checks := storev2.Of[*corev2.CheckConfig](s)
+		check := corev2.FixtureCheckConfig("check")
+
+		// The writes that fail are not recorded
+		require.Error(t, checks.UpdateIfExists(ctx, check))
+		versions, err := checks.History(ctx, storev2.ID{Namespace: check.Namespace, Name: check.Name})
+		require.NoError(t, err)
+		assert.Empty(t, versions)
+	})
+}
+
+func TestStoreInitialize(t *testing.T) {
+	withSQLiteStore(t, func(ctx context.Context, raw storev2.Interface) {
+		s := NewStore(raw)
+		check := corev2.FixtureCheckConfig("check")
+		err := s.GetConfigStore().Initialize(ctx, func(ctx context.Context, st storev2.Interface) error {
+			return storev2.Of[*corev2.CheckConfig](st).CreateIfNotExists(ctx, check)
+		})
+		require.NoError(t, err)
+		versions, err := storev2.Of[*corev2.CheckConfig](s).History(ctx, storev2.ID{Namespace: check.Namespace, Name: check.Name})
+		require.NoError(t, err)
+		assert.Len(t, versions, 1)
+	})
+}
@@ -432,3 +432,12 @@ func (e *EventStore) GetKeepaliveGaugesByNamespace(ctx context.Context) (map[str
 	}
 	return getter.GetKeepaliveGaugesByNamespace(ctx)
 }
+
+// PruneEvents prunes the events of the backing store, if it supports it.
+func (e *EventStore) PruneEvents(ctx context.Context, namespace string, before int64, max int) (int64, error) {
+	pruner, ok := e.backingStore.(store.EventPruner)
+	if !ok {
+		return 0, errors.New("event pruning not supported")
+	}
+	return pruner.PruneEvents(ctx, namespace, before, max)
+}
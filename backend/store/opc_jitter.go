@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// JitteredOperatorConcierge is an OperatorConcierge which adds a random
+// jitter to the check-in timeouts of the agent and check operators, so that
+// the operators which checked in together, like the agents reconnecting after
+// a backend restart, don't all expire at the same time. The jitter only
+// extends the timeouts, so an operator is never considered absent earlier
+// than it would be without jitter. The backend operators are not jittered.
+type JitteredOperatorConcierge struct {
+	OperatorConcierge
+
+	// jitter is the maximum jitter, as a fraction of the timeout.
+	jitter float64
+
+	mu     sync.Mutex
+	random func() float64
+}
+
+// NewJitteredOperatorConcierge returns a JitteredOperatorConcierge which
+// extends the check-in timeouts by up to the jitter fraction of the timeouts,
+// e.g. a jitter of 0.1 extends a 60s timeout by up to 6s.
+func NewJitteredOperatorConcierge(opc OperatorConcierge, jitter float64) *JitteredOperatorConcierge {
+	return &JitteredOperatorConcierge{
+		OperatorConcierge: opc,
+		jitter:            jitter,
+		random:            rand.New(rand.NewSource(time.Now().UnixNano())).Float64,
+	}
+}
+
+// CheckIn checks in an operator, with a jittered check-in timeout.
+func (j *JitteredOperatorConcierge) CheckIn(ctx context.Context, state OperatorState) error {
+	state.CheckInTimeout = j.Timeout(state.Type, state.CheckInTimeout)
+	return j.OperatorConcierge.CheckIn(ctx, state)
+}
+
+// Timeout returns the jittered check-in timeout of an operator of the given
+// type, uniformly distributed between timeout and timeout*(1+jitter).
+func (j *JitteredOperatorConcierge) Timeout(typ OperatorType, timeout time.Duration) time.Duration {
+	if j.jitter <= 0 || timeout <= 0 || typ == BackendOperator {
+		return timeout
+	}
+	j.mu.Lock()
+	r := j.random()
+	j.mu.Unlock()
+	return timeout + time.Duration(r*j.jitter*float64(timeout))
+}
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+type recordingConcierge struct {
+	states []OperatorState
+}
+
+func (r *recordingConcierge) CheckIn(_ context.Context, state OperatorState) error {
+	r.states = append(r.states, state)
+	return nil
+}
+
+func (r *recordingConcierge) CheckOut(context.Context, OperatorKey) error {
+	return nil
+}
+
+func TestJitteredOperatorConciergeDistribution(t *testing.T) {
+	const (
+		timeout = time.Minute
+		jitter  = 0.2
+		samples = 10000
+		buckets = 10
+	)
+	opc := NewJitteredOperatorConcierge(&recordingConcierge{}, jitter)
+	opc.random = rand.New(rand.NewSource(42)).Float64
+
+	max := timeout + time.Duration(jitter*float64(timeout))
+	width := (max - timeout) / buckets
+	counts := make([]int, buckets)
+	var sum time.Duration
+	for i := 0; i < samples; i++ {
+		got := opc.Timeout(AgentOperator, timeout)
+		if got < timeout || got >= max {
+			t.Fatalf("timeout %s out of [%s, %s)", got, timeout, max)
+		}
+		counts[(got-timeout)/width]++
+		sum += got - timeout
+	}
+
+	// The jitter is uniformly distributed over the jitter window
+	expected := samples / buckets
+	for i, count := range counts {
+		if count < expected*8/10 || count > expected*12/10 {
+			t.Errorf("bucket %d: got %d timeouts, want about %d", i, count, expected)
+		}
+	}
+	mean := sum / samples
+	if want := (max - timeout) / 2; mean < want*95/100 || mean > want*105/100 {
+		t.Errorf("mean jitter %s, want about %s", mean, want)
+	}
+}
+
+func TestJitteredOperatorConciergeCheckIn(t *testing.T) {
+	recorder := &recordingConcierge{}
+	opc := NewJitteredOperatorConcierge(recorder, 0.5)
+	opc.random = func() float64 { return 0.5 }
+
+	ctx := context.Background()
+	for _, typ := range []OperatorType{AgentOperator, CheckOperator, BackendOperator} {
+		state := OperatorState{Type: typ, Name: typ.String(), CheckInTimeout: 40 * time.Second}
+		if err := opc.CheckIn(ctx, state); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []time.Duration{50 * time.Second, 50 * time.Second, 40 * time.Second}
+	for i, state := range recorder.states {
+		if got := state.CheckInTimeout; got != want[i] {
+			t.Errorf("%s operator: got timeout %s, want %s", state.Type, got, want[i])
+		}
+	}
+
+	// Without jitter, the timeouts are unchanged
+	opc = NewJitteredOperatorConcierge(recorder, 0)
+	if got := opc.Timeout(AgentOperator, time.Minute); got != time.Minute {
+		t.Errorf("got timeout %s, want 1m0s", got)
+	}
+}
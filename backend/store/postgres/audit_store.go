@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.AuditStore = &AuditStore{}
+
+type AuditStore struct {
+	db DBI
+}
+
+func NewAuditStore(db DBI) *AuditStore {
+	return &AuditStore{db: db}
+}
+
+const addAuditEntryQuery = `
+INSERT INTO audit_log (
+	timestamp, request_id, username, verb, api_group, api_version, namespace,
+	resource, resource_name, status, outcome, latency
+)
+VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12 );
+`
+
+// AddAuditEntry records an audit entry.
+func (s *AuditStore) AddAuditEntry(ctx context.Context, entry *storev2.AuditEntry) error {
+	_, err := s.db.Exec(ctx, addAuditEntryQuery,
+		entry.Timestamp,
+		entry.RequestID,
+		entry.User,
+		entry.Verb,
+		entry.APIGroup,
+		entry.APIVersion,
+		entry.Namespace,
+		entry.Resource,
+		entry.ResourceName,
+		entry.Status,
+		entry.Outcome,
+		entry.Latency,
+	)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	return nil
+}
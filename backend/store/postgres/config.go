@@ -1,7 +1,78 @@
 package postgres
 
+import (
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
 type Config struct {
 	DSN               string
 	MaxTPS            int
 	DisableEventCache bool
+
+	// MaxConns is the maximum number of connections of the pool. The pgx
+	// default is used when it is 0.
+	MaxConns int32
+
+	// MinConns is the number of connections the pool keeps open.
+	MinConns int32
+
+	// MaxConnLifetime is the duration after which a connection of the pool
+	// is closed. The pgx default is used when it is 0.
+	MaxConnLifetime time.Duration
+
+	// MaxConnIdleTime is the duration after which an idle connection of the
+	// pool is closed. The pgx default is used when it is 0.
+	MaxConnIdleTime time.Duration
+
+	// StatementTimeout is the statement_timeout of the connections, after
+	// which postgresql aborts a query. Queries are not timed out when it is 0.
+	StatementTimeout time.Duration
+
+	// ReadDSN is the DSN of a read replica of the database. When set, the
+	// reads which can be out of date are served by the replica.
+	ReadDSN string
+
+	// MaxReplicaLag is the replication lag above which the reads are served by
+	// the primary instead of the replica. It defaults to DefaultMaxReplicaLag.
+	MaxReplicaLag time.Duration
+}
+
+// PoolConfig returns the configuration of the pgx connection pool, parsed
+// from the DSN and overridden by the pool settings of the config. The queries
+// of the pool are timed by the query metrics.
+func (c Config) PoolConfig() (*pgxpool.Config, error) {
+	return c.poolConfig(c.DSN)
+}
+
+// ReadPoolConfig returns the configuration of the pgx connection pool of the
+// read replica, like PoolConfig but parsed from the ReadDSN.
+func (c Config) ReadPoolConfig() (*pgxpool.Config, error) {
+	return c.poolConfig(c.ReadDSN)
+}
+
+func (c Config) poolConfig(dsn string) (*pgxpool.Config, error) {
+	pgxConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if c.MaxConns > 0 {
+		pgxConfig.MaxConns = c.MaxConns
+	}
+	if c.MinConns > 0 {
+		pgxConfig.MinConns = c.MinConns
+	}
+	if c.MaxConnLifetime > 0 {
+		pgxConfig.MaxConnLifetime = c.MaxConnLifetime
+	}
+	if c.MaxConnIdleTime > 0 {
+		pgxConfig.MaxConnIdleTime = c.MaxConnIdleTime
+	}
+	if c.StatementTimeout > 0 {
+		pgxConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(c.StatementTimeout.Milliseconds(), 10)
+	}
+	pgxConfig.ConnConfig.Tracer = queryTracer{}
+	return pgxConfig, nil
 }
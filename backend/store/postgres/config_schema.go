@@ -56,6 +56,16 @@ VALUES ($1, $2, $3, $4, $5, $6, $7, $8, digest($8::jsonb::text, 'sha1'))
 	RETURNING xmax = 0, (SELECT cfg.etag FROM configuration cfg WHERE cfg.id = configuration.id), digest($8::jsonb::text, 'sha1')
 ;`
 
+const BatchCreateOrUpdateConfigQuery = `
+INSERT INTO configuration (api_version, api_type, namespace, name, labels, annotations, fields, resource, etag)
+SELECT api_version, api_type, namespace, name, labels::jsonb, annotations, fields::jsonb, resource::jsonb, digest(resource::jsonb::text, 'sha1')
+	FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::bytea[], $7::text[], $8::text[])
+	AS batch(api_version, api_type, namespace, name, labels, annotations, fields, resource)
+    ON CONFLICT (api_version, api_type, namespace, name, deleted_at) DO UPDATE
+	  SET labels=excluded.labels, annotations=excluded.annotations, fields=excluded.fields, resource=excluded.resource, etag=excluded.etag
+	RETURNING xmax = 0, (SELECT cfg.etag FROM configuration cfg WHERE cfg.id = configuration.id), etag
+;`
+
 const DeleteConfigQuery = `UPDATE configuration SET deleted_at=NOW()
     WHERE api_version=$1 AND api_type=$2 AND namespace=$3 AND name=$4 AND NOT isfinite(deleted_at);`
 
@@ -68,6 +78,14 @@ const DeleteConfigIfNoneMatchQuery = `UPDATE configuration SET deleted_at=NOW()
 const ExistsConfigQuery = `SELECT count(*) AS total FROM configuration
     WHERE api_version=$1 AND api_type=$2 AND namespace=$3 AND name=$4 AND NOT isfinite(deleted_at);`
 
+const BatchExistsConfigQuery = `
+SELECT batch.ord FROM unnest($1::text[], $2::text[], $3::text[], $4::text[])
+	WITH ORDINALITY AS batch(api_version, api_type, namespace, name, ord)
+	WHERE EXISTS (SELECT 1 FROM configuration
+		WHERE configuration.api_version=batch.api_version AND configuration.api_type=batch.api_type
+		AND configuration.namespace=batch.namespace AND configuration.name=batch.name
+		AND NOT isfinite(configuration.deleted_at));`
+
 const ExistsConfigIfMatchQuery = `SELECT count(*) AS total FROM configuration
 	WHERE api_version=$1 AND api_type=$2 AND namespace=$3 AND name=$4 AND NOT isfinite(deleted_at) AND etag = ANY(($5::bytea[]));`
 
@@ -99,7 +117,7 @@ const ListConfigQueryTmpl = `
 		AND updated_at > $4
         {{if .Namespaced}} AND namespace=$3 {{end}}
         {{if ne .SelectorSQL ""}}AND {{.SelectorSQL}}{{end}}
-    ORDER BY namespace, name ASC
+    ORDER BY namespace {{ .Direction }}, name {{ .Direction }}
     {{if (gt .Limit 0)}} LIMIT {{.Limit}} {{end}} OFFSET {{ .Offset }};`
 
 const UpdateIfExistsConfigQuery = `
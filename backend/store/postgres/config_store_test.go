@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lib/pq"
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/backend/selector"
 	"github.com/sensu/sensu-go/backend/store"
@@ -22,6 +23,41 @@ const (
 	assetName        = "__test-asset__"
 )
 
+func TestConfigStoreNotifications(t *testing.T) {
+	listener := new(mockListener)
+	listener.On("Listen", configurationNotifyChannel).Return(nil)
+	ch := make(chan *pq.Notification)
+	listener.On("NotificationChannel").Return(ch)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &ConfigStore{notifications: NewBus(ctx, listener)}
+	req := storev2.NewResourceRequestFromResource(corev2.FixtureCheckConfig("check"))
+	notify, err := s.Notifications(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The changes of the other types of resources are ignored
+	ch <- &pq.Notification{Channel: configurationNotifyChannel, Extra: "core/v2/Handler"}
+	ch <- &pq.Notification{Channel: configurationNotifyChannel, Extra: "core/v2/CheckConfig"}
+	select {
+	case <-notify:
+	case <-time.After(10 * time.Second):
+		t.Fatal("no notification")
+	}
+	select {
+	case <-notify:
+		t.Fatal("unexpected notification")
+	default:
+	}
+
+	// The stores without a notification bus are only polled
+	notify, err = (&ConfigStore{}).Notifications(ctx, req)
+	assert.NoError(t, err)
+	assert.Nil(t, notify)
+}
+
 func testWithPostgresConfigStore(t testing.TB, fn func(p storev2.ConfigStore)) {
 	t.Helper()
 	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
@@ -110,6 +146,91 @@ func TestConfigStore_CreateOrUpdate(t *testing.T) {
 	})
 }
 
+func TestConfigStore_BatchCreateOrUpdate(t *testing.T) {
+	testWithPostgresConfigStore(t, func(s storev2.ConfigStore) {
+		ctx := context.Background()
+
+		foo, bar := corev2.FixtureAsset("foo"), corev2.FixtureAsset("bar")
+		if err := createOrUpdateAsset(ctx, s, bar); err != nil {
+			t.Fatal(err)
+		}
+		bar.Sha512 = "updated"
+
+		var (
+			reqs     []storev2.ResourceRequest
+			wrappers []storev2.Wrapper
+		)
+		// The last value of a resource given twice is written
+		for _, asset := range []*corev2.Asset{foo, foo, bar} {
+			wrapper, err := wrapAsset(asset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			reqs = append(reqs, storev2.NewResourceRequestFromResource(asset))
+			wrappers = append(wrappers, wrapper)
+		}
+
+		var txInfo storev2.TxInfo
+		if err := s.BatchCreateOrUpdate(storev2.ContextWithTxInfo(ctx, &txInfo), reqs, wrappers); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(txInfo.Records), 2; got != want {
+			t.Fatalf("bad number of tx records: got %d, want %d", got, want)
+		}
+		var created, updated int
+		for _, rec := range txInfo.Records {
+			if rec.Created {
+				created++
+			}
+			if rec.Updated {
+				updated++
+			}
+		}
+		if created != 1 || updated != 1 {
+			t.Errorf("bad tx records: got %d created and %d updated, want 1 and 1", created, updated)
+		}
+
+		got, err := getAsset(ctx, s, "default", "bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Sha512 != "updated" {
+			t.Errorf("bad sha512: got %q, want %q", got.Sha512, "updated")
+		}
+		if exists, err := assetExists(ctx, s, "default", "foo"); err != nil || !exists {
+			t.Errorf("asset not created: %v", err)
+		}
+	})
+}
+
+func TestConfigStore_BatchExists(t *testing.T) {
+	testWithPostgresConfigStore(t, func(s storev2.ConfigStore) {
+		ctx := context.Background()
+
+		foo, bar := corev2.FixtureAsset("foo"), corev2.FixtureAsset("bar")
+		if err := createOrUpdateAsset(ctx, s, bar); err != nil {
+			t.Fatal(err)
+		}
+
+		reqs := []storev2.ResourceRequest{
+			storev2.NewResourceRequestFromResource(foo),
+			storev2.NewResourceRequestFromResource(bar),
+			storev2.NewResourceRequestFromResource(foo),
+		}
+		got, err := s.BatchExists(ctx, reqs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []bool{false, true, false}, got)
+
+		got, err = s.BatchExists(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Empty(t, got)
+	})
+}
+
 func TestConfigStore_CreateOrUpdateIfMatch(t *testing.T) {
 	testWithPostgresConfigStore(t, func(s storev2.ConfigStore) {
 		ctx := context.Background()
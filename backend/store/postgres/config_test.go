@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigPoolConfig(t *testing.T) {
+	config := Config{
+		DSN:              "postgresql://sensu@localhost/sensu?pool_max_conns=8",
+		MinConns:         2,
+		MaxConnLifetime:  time.Hour,
+		MaxConnIdleTime:  time.Minute,
+		StatementTimeout: 30 * time.Second,
+	}
+	pgxConfig, err := config.PoolConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The DSN settings are kept unless overridden
+	if got, want := pgxConfig.MaxConns, int32(8); got != want {
+		t.Errorf("bad max conns: got %d, want %d", got, want)
+	}
+	if got, want := pgxConfig.MinConns, int32(2); got != want {
+		t.Errorf("bad min conns: got %d, want %d", got, want)
+	}
+	if got, want := pgxConfig.MaxConnLifetime, time.Hour; got != want {
+		t.Errorf("bad max conn lifetime: got %s, want %s", got, want)
+	}
+	if got, want := pgxConfig.MaxConnIdleTime, time.Minute; got != want {
+		t.Errorf("bad max conn idle time: got %s, want %s", got, want)
+	}
+	if got, want := pgxConfig.ConnConfig.RuntimeParams["statement_timeout"], "30000"; got != want {
+		t.Errorf("bad statement timeout: got %q, want %q", got, want)
+	}
+	if pgxConfig.ConnConfig.Tracer == nil {
+		t.Error("the queries are not traced")
+	}
+
+	config.MaxConns = 4
+	config.StatementTimeout = 0
+	pgxConfig, err = config.PoolConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := pgxConfig.MaxConns, int32(4); got != want {
+		t.Errorf("bad max conns: got %d, want %d", got, want)
+	}
+	if _, ok := pgxConfig.ConnConfig.RuntimeParams["statement_timeout"]; ok {
+		t.Error("statement timeout set")
+	}
+
+	if _, err := (Config{DSN: "postgresql://localhost:notaport"}).PoolConfig(); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"strings"
 	"text/template"
 
 	corev2 "github.com/sensu/core/v2"
@@ -16,7 +17,7 @@ WITH ns AS (
 	SELECT id FROM namespaces
 	WHERE namespaces.name = $1
 )
-SELECT serialized
+SELECT serialized, {{ .SortKey }}, namespace, entity_name, check_name
 FROM events
 FULL OUTER JOIN ns
 ON events.namespace = ns.id
@@ -25,6 +26,7 @@ WHERE
 AND {{ .EntityCond }}
 AND {{ .CheckCond }}
 AND {{ .SelectorCond }}
+AND {{ .CursorCond }}
 ORDER BY ({{ .Ordering }}) {{ .OrderingDirection }}
 {{ .LimitClause }}
 {{ .OffsetClause }}
@@ -35,6 +37,8 @@ type getEventTmplData struct {
 	EntityCond        string
 	CheckCond         string
 	SelectorCond      string
+	CursorCond        string
+	SortKey           string
 	Ordering          string
 	OrderingDirection string
 	LimitClause       string
@@ -57,6 +61,26 @@ AND {{ .CheckCond }}
 AND {{ .SelectorCond }}
 ;`))
 
+// eventSortKeys are the expressions that events are sorted on, by ordering.
+var eventSortKeys = map[string]string{
+	corev2.EventSortLastOk:    "COALESCE((selectors ->> 'event.check.last_ok')::INTEGER, 0)",
+	corev2.EventSortSeverity:  "CASE WHEN selectors ->> 'event.check.status' = '0' THEN 3 WHEN selectors ->> 'event.check.status' = '1' THEN 1 WHEN selectors ->> 'event.check.status' = '2' THEN 0 ELSE 2 END",
+	corev2.EventSortTimestamp: "COALESCE((selectors ->> 'event.timestamp')::INTEGER, 0)",
+}
+
+// eventOrdering returns the ordering requested by the predicate. NAME is
+// accepted as an alias of ENTITY.
+func eventOrdering(pred *store.SelectionPredicate) (string, error) {
+	switch pred.Ordering {
+	case "", corev2.EventSortEntity, corev2.EventSortLastOk, corev2.EventSortSeverity, corev2.EventSortTimestamp:
+		return pred.Ordering, nil
+	case corev2.EntitySortName:
+		return corev2.EventSortEntity, nil
+	default:
+		return "", errors.New("unknown ordering requested")
+	}
+}
+
 type argCounter struct {
 	value int
 }
@@ -102,6 +126,8 @@ func getTemplateData(namespace, entity, check string, s *selector.Selector, pred
 		EntityCond:    "true",
 		CheckCond:     "true",
 		SelectorCond:  "true",
+		CursorCond:    "true",
+		SortKey:       "0",
 		Ordering:      "namespace, entity_name, check_name",
 	}
 	var ctr argCounter
@@ -127,34 +153,42 @@ func getTemplateData(namespace, entity, check string, s *selector.Selector, pred
 	}
 
 	if pred != nil {
-		switch pred.Ordering {
-		case "":
-			break
-
-		case corev2.EventSortEntity:
-			data.Ordering = "entity_name"
-
-		case corev2.EventSortLastOk:
-			data.Ordering = "(selectors ->> 'event.check.last_ok')::INTEGER"
-
-		case corev2.EventSortSeverity:
-			data.Ordering = "CASE WHEN selectors ->> 'event.check.status' = '0' THEN 3 WHEN selectors ->> 'event.check.status' = '1' THEN 1 WHEN selectors ->> 'event.check.status' = '2' THEN 0 ELSE 2 END"
-
-		case corev2.EventSortTimestamp:
-			data.Ordering = "(selectors ->> 'event.timestamp')::INTEGER"
+		ordering, err := eventOrdering(pred)
+		if err != nil {
+			return data, nil, err
+		}
 
-		default:
-			return data, nil, errors.New("unknown ordering requested")
+		// The ordering always ends with the primary key of events, so that
+		// the order is total and the page can be resumed from a cursor.
+		columns := []string{"namespace", "entity_name", "check_name"}
+		if ordering == corev2.EventSortEntity {
+			columns = []string{"entity_name", "namespace", "check_name"}
+		} else if key, ok := eventSortKeys[ordering]; ok {
+			data.SortKey = key
+			columns = append([]string{key}, columns...)
 		}
+		data.Ordering = strings.Join(columns, ", ")
 
 		if pred.Descending {
 			data.OrderingDirection = "DESC"
 		}
 
-		limit, offset, err := getLimitAndOffset(pred)
+		limit, offset, cursor, err := getEventsPage(pred, ordering)
 		if err != nil {
 			return data, nil, err
 		}
+		if cursor != nil {
+			placeholders := make([]string, len(columns))
+			for i := range columns {
+				placeholders[i] = fmt.Sprintf("$%d", ctr.Next())
+			}
+			args = append(args, cursor.values()...)
+			op := ">"
+			if pred.Descending {
+				op = "<"
+			}
+			data.CursorCond = fmt.Sprintf("(%s) %s (%s)", data.Ordering, op, strings.Join(placeholders, ", "))
+		}
 		if !limit.Valid {
 			data.LimitClause = "LIMIT ALL"
 		} else {
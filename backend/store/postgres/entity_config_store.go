@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
@@ -10,8 +11,10 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/sensu-go/backend/poll"
+	"github.com/sensu/sensu-go/backend/selector"
 	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/backend/store/patch"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
@@ -221,6 +224,27 @@ func (s *EntityConfigStore) HardDelete(ctx context.Context, namespace, name stri
 	return nil
 }
 
+// Undelete restores a soft deleted entity config using the given namespace &
+// name.
+func (s *EntityConfigStore) Undelete(ctx context.Context, namespace, name string) error {
+	if namespace == "" {
+		return &store.ErrNotValid{Err: errors.New("must specify namespace")}
+	}
+	if name == "" {
+		return &store.ErrNotValid{Err: errors.New("must specify name")}
+	}
+
+	result, err := s.db.Exec(ctx, undeleteEntityConfigQuery, namespace, name)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	affected := result.RowsAffected()
+	if affected < 1 {
+		return &store.ErrNotFound{Key: entityConfigStoreKey(namespace, name)}
+	}
+	return nil
+}
+
 // HardDeleted determines if an entity config has been hard deleted.
 func (s *EntityConfigStore) HardDeleted(ctx context.Context, namespace, name string) (bool, error) {
 	if namespace == "" {
@@ -247,10 +271,29 @@ func (s *EntityConfigStore) List(ctx context.Context, namespace string, pred *st
 	if pred == nil {
 		pred = &store.SelectionPredicate{}
 	}
-	query := listEntityConfigQuery
+	tmplData := listEntityConfigTmplData{Direction: "ASC"}
+	switch pred.Ordering {
+	case "", corev2.EntitySortName:
+	case corev2.EntitySortLastSeen:
+		tmplData.LastSeen = true
+	default:
+		return nil, &store.ErrNotValid{Err: fmt.Errorf("unknown ordering requested: %s", pred.Ordering)}
+	}
 	if pred.Descending {
-		query = listEntityConfigDescQuery
+		tmplData.Direction = "DESC"
+	}
+
+	selectorSQL, selectorArgs, err := getEntityConfigSelectorSQL(ctx, 5)
+	if err != nil {
+		return nil, &store.ErrNotValid{Err: err}
 	}
+	tmplData.SelectorSQL = selectorSQL
+
+	var buf bytes.Buffer
+	if err := listEntityConfigTmpl.Execute(&buf, tmplData); err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	query := buf.String()
 
 	if pred.UpdatedSince == "" {
 		pred.UpdatedSince = beginningOfTime
@@ -272,7 +315,8 @@ func (s *EntityConfigStore) List(ctx context.Context, namespace string, pred *st
 		sqlNamespace.Valid = true
 	}
 
-	rows, rerr := s.db.Query(ctx, query, sqlNamespace, limit, offset, pred.IncludeDeletes, updatedSince)
+	args := append([]interface{}{sqlNamespace, limit, offset, pred.IncludeDeletes, updatedSince}, selectorArgs...)
+	rows, rerr := s.db.Query(ctx, query, args...)
 	if rerr != nil {
 		return nil, &store.ErrInternal{Message: rerr.Error()}
 	}
@@ -304,6 +348,31 @@ func (s *EntityConfigStore) List(ctx context.Context, namespace string, pred *st
 	return configs, nil
 }
 
+// getEntityConfigSelectorSQL returns the condition of the label selectors of
+// the entity configs found in the context, and its arguments, which start after
+// nargs. Only the label values compared to literal values are matched in SQL,
+// which the entity configs' GIN index supports, so the other operations of the
+// selector must be filtered by the caller.
+func getEntityConfigSelectorSQL(ctx context.Context, nargs int) (string, []interface{}, error) {
+	ctxSelector := storev2.EntityConfigSelectorFromContext(ctx)
+	if ctxSelector == nil {
+		return "", nil, nil
+	}
+	builder := NewEntityConfigSelectorSQLBuilder(&selector.Selector{})
+	for _, op := range ctxSelector.Operations {
+		switch op.Operator {
+		case selector.DoubleEqualSignOperator, selector.NotEqualOperator, selector.InOperator, selector.NotInOperator:
+			if builder.isLabelValueMatch(op) {
+				builder.selector.Operations = append(builder.selector.Operations, op)
+			}
+		}
+	}
+	if len(builder.selector.Operations) == 0 {
+		return "", nil, nil
+	}
+	return builder.GetSelectorCond(&argCounter{value: nargs})
+}
+
 // Count returns the count of EntityConfigs found matching the namespace
 // and entity class provided.
 func (s *EntityConfigStore) Count(ctx context.Context, namespace, entityClass string) (int, error) {
@@ -12,6 +12,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/selector"
 	"github.com/sensu/sensu-go/backend/store"
 	"github.com/sensu/sensu-go/backend/store/patch"
 	storev2 "github.com/sensu/sensu-go/backend/store/v2"
@@ -1021,6 +1022,57 @@ func TestEntityConfigStore_UpdatedSince(t *testing.T) {
 	})
 }
 
+func TestEntityConfigStore_ListWithSelector(t *testing.T) {
+	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
+		s := &EntityConfigStore{
+			db: db,
+		}
+		ns := &NamespaceStore{
+			db: db,
+		}
+		createNamespace(t, ns, "default")
+		for i, region := range []string{"uswest", "useast", "uswest", ""} {
+			cfg := corev3.FixtureEntityConfig(fmt.Sprintf("foo-%d", i))
+			if region != "" {
+				cfg.Metadata.Labels["region"] = region
+			}
+			if err := s.CreateIfNotExists(ctx, cfg); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		tests := []struct {
+			selector string
+			want     []string
+		}{
+			{selector: "region == uswest", want: []string{"foo-0", "foo-2"}},
+			{selector: "region != uswest", want: []string{"foo-1", "foo-3"}},
+			{selector: "region in [useast, uswest]", want: []string{"foo-0", "foo-1", "foo-2"}},
+			{selector: "region notin [useast]", want: []string{"foo-0", "foo-2", "foo-3"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.selector, func(t *testing.T) {
+				sel, err := selector.ParseLabelSelector(tt.selector)
+				if err != nil {
+					t.Fatal(err)
+				}
+				ctx := storev2.EntityConfigContextWithSelector(ctx, sel)
+				configs, err := s.List(ctx, "default", &store.SelectionPredicate{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				var got []string
+				for _, cfg := range configs {
+					got = append(got, cfg.Metadata.Name)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("bad entity configs: got %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+}
+
 func TestEntityConfigStoreDeletedAt(t *testing.T) {
 	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
 		s := &EntityConfigStore{
@@ -1,5 +1,7 @@
 package postgres
 
+import "text/template"
+
 const createOrUpdateEntityConfigQuery = `
 -- This query creates a new entity config, or updates it if it already exists.
 --
@@ -254,6 +256,35 @@ WHERE
 	name = $2;
 `
 
+const undeleteEntityConfigQuery = `
+-- This query restores a soft deleted entity config.
+--
+-- Parameters:
+-- $1 Namespace
+-- $2 Entity name
+WITH namespace AS (
+	SELECT id FROM namespaces
+	WHERE name = $1 AND deleted_at IS NULL
+)
+UPDATE entity_configs
+SET deleted_at = NULL
+WHERE
+	namespace_id = (SELECT id FROM namespace) AND
+	name = $2 AND
+	deleted_at IS NOT NULL;
+`
+
+const purgeEntityConfigsQuery = `
+-- This query hard deletes the entity configs soft deleted before a given time.
+-- Any related entity, system & network state will also be deleted via
+-- ON DELETE CASCADE triggers.
+--
+-- Parameters:
+-- $1 Time before which the entity configs were soft deleted
+DELETE FROM entity_configs
+WHERE deleted_at < $1;
+`
+
 const hardDeletedEntityConfigQuery = `
 -- This query discovers if an entity config has been hard deleted.
 --
@@ -269,41 +300,10 @@ SELECT NOT EXISTS (
 );
 `
 
-const listEntityConfigQuery = `
--- This query lists entity configs from a given namespace.
---
-SELECT
-	namespaces.name,
-	entity_configs.name,
-	entity_configs.selectors,
-	entity_configs.annotations,
-	entity_configs.created_by,
-	entity_configs.entity_class,
-	entity_configs.sensu_user,
-	entity_configs.subscriptions,
-	entity_configs.deregister,
-	entity_configs.deregistration,
-	entity_configs.keepalive_handlers,
-	entity_configs.redact,
-	entity_configs.id,
-	entity_configs.namespace_id,
-	entity_configs.created_at,
-	entity_configs.updated_at,
-	entity_configs.deleted_at
-FROM entity_configs
-LEFT OUTER JOIN namespaces ON entity_configs.namespace_id = namespaces.id
-WHERE
-	($4 OR entity_configs.deleted_at IS NULL) AND
-	(namespaces.name = $1 OR $1 IS NULL) AND
-	entity_configs.updated_at > $5
-ORDER BY ( namespaces.name, entity_configs.name ) ASC
-LIMIT $2
-OFFSET $3
-`
-
-const listEntityConfigDescQuery = `
--- This query lists entities from a given namespace.
---
+// listEntityConfigTmpl is the template of the query that lists the entity
+// configs of a given namespace, by name or by the time their entity was last
+// seen, optionally filtered by label selectors.
+var listEntityConfigTmpl = template.Must(template.New("ListEntityConfigs").Parse(`
 SELECT
 	namespaces.name,
 	entity_configs.name,
@@ -324,14 +324,22 @@ SELECT
 	entity_configs.deleted_at
 FROM entity_configs
 LEFT OUTER JOIN namespaces ON namespaces.id = entity_configs.namespace_id
-WHERE
+{{ if .LastSeen }}LEFT OUTER JOIN entity_states ON entity_states.entity_config_id = entity_configs.id
+{{ end }}WHERE
 	($4 OR entity_configs.deleted_at IS NULL) AND
 	(namespaces.name = $1 OR $1 IS NULL) AND
 	entity_configs.updated_at > $5
-ORDER BY ( namespaces.name, entity_configs.name ) DESC
+	{{ if .SelectorSQL }}AND {{ .SelectorSQL }}{{ end }}
+ORDER BY ( {{ if .LastSeen }}COALESCE(entity_states.last_seen, 0), {{ end }}namespaces.name, entity_configs.name ) {{ .Direction }}
 LIMIT $2
 OFFSET $3
-`
+`))
+
+type listEntityConfigTmplData struct {
+	LastSeen    bool
+	Direction   string
+	SelectorSQL string
+}
 
 const countEntityConfigQuery = `
 -- This query counts entity configs from a given namespace and entity class.
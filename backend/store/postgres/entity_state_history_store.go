@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+
+	"github.com/jackc/pgx/v5"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.EntityStateHistoryStore = &EntityStateHistoryStore{}
+
+type EntityStateHistoryStore struct {
+	db DBI
+}
+
+func NewEntityStateHistoryStore(db DBI) *EntityStateHistoryStore {
+	return &EntityStateHistoryStore{db: db}
+}
+
+// getLastEntityStateSnapshotQuery gets the id of an entity state, and the
+// system of its last snapshot, if any.
+const getLastEntityStateSnapshotQuery = `
+SELECT
+	entity_states.id,
+	(
+		SELECT system FROM entity_state_history
+		WHERE entity_state_id = entity_states.id
+		ORDER BY id DESC
+		LIMIT 1
+	)
+FROM
+	entity_states
+	JOIN namespaces ON entity_states.namespace_id = namespaces.id
+WHERE
+	namespaces.name = $1
+	AND entity_states.name = $2
+	AND entity_states.deleted_at IS NULL;
+`
+
+const addEntityStateSnapshotQuery = `
+INSERT INTO entity_state_history ( entity_state_id, timestamp, system )
+VALUES ( $1, $2, $3 );
+`
+
+const pruneEntityStateHistoryQuery = `
+DELETE FROM entity_state_history
+WHERE entity_state_id = $1
+	AND id NOT IN (
+		SELECT id FROM entity_state_history
+		WHERE entity_state_id = $1
+		ORDER BY id DESC
+		LIMIT $2
+	);
+`
+
+// AddEntityStateSnapshot records a snapshot of the system of an entity, unless
+// it did not change since the last snapshot, and removes the snapshots of the
+// entity that exceed storev2.MaxEntityStateSnapshots.
+func (s *EntityStateHistoryStore) AddEntityStateSnapshot(ctx context.Context, snapshot *storev2.EntityStateSnapshot) (fErr error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	defer func() {
+		if fErr == nil {
+			fErr = tx.Commit(ctx)
+			return
+		}
+		if txerr := tx.Rollback(ctx); txerr != nil && txerr != pgx.ErrTxClosed {
+			fErr = txerr
+		}
+	}()
+
+	var (
+		stateID    int64
+		lastSystem []byte
+	)
+	row := tx.QueryRow(ctx, getLastEntityStateSnapshotQuery, snapshot.Namespace, snapshot.Entity)
+	if err := row.Scan(&stateID, &lastSystem); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &store.ErrNotFound{Key: path.Join(snapshot.Namespace, snapshot.Entity)}
+		}
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	if lastSystem != nil {
+		var last corev2.System
+		if err := json.Unmarshal(lastSystem, &last); err != nil {
+			return &store.ErrDecode{Key: path.Join(snapshot.Namespace, snapshot.Entity), Err: err}
+		}
+		if last.Equal(&snapshot.System) {
+			return nil
+		}
+	}
+
+	system, err := json.Marshal(&snapshot.System)
+	if err != nil {
+		return &store.ErrEncode{Key: path.Join(snapshot.Namespace, snapshot.Entity), Err: err}
+	}
+	if _, err := tx.Exec(ctx, addEntityStateSnapshotQuery, stateID, snapshot.Timestamp, system); err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	if _, err := tx.Exec(ctx, pruneEntityStateHistoryQuery, stateID, storev2.MaxEntityStateSnapshots); err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	return nil
+}
+
+const getEntityStateHistoryQuery = `
+SELECT
+	entity_state_history.timestamp,
+	entity_state_history.system
+FROM
+	entity_state_history
+	JOIN entity_states ON entity_state_history.entity_state_id = entity_states.id
+	JOIN namespaces ON entity_states.namespace_id = namespaces.id
+WHERE
+	namespaces.name = $1
+	AND entity_states.name = $2
+ORDER BY entity_state_history.id DESC;
+`
+
+// GetEntityStateHistory gets the snapshots of an entity, most recent first.
+func (s *EntityStateHistoryStore) GetEntityStateHistory(ctx context.Context, namespace, entity string) ([]*storev2.EntityStateSnapshot, error) {
+	rows, err := s.db.Query(ctx, getEntityStateHistoryQuery, namespace, entity)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	defer rows.Close()
+	snapshots := []*storev2.EntityStateSnapshot{}
+	for rows.Next() {
+		snapshot := storev2.EntityStateSnapshot{Namespace: namespace, Entity: entity}
+		var system []byte
+		if err := rows.Scan(&snapshot.Timestamp, &system); err != nil {
+			return nil, &store.ErrInternal{Message: err.Error()}
+		}
+		if err := json.Unmarshal(system, &snapshot.System); err != nil {
+			return nil, &store.ErrDecode{Key: path.Join(namespace, entity), Err: err}
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+func TestEntityStateHistoryStore(t *testing.T) {
+	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
+		createNamespace(t, NewNamespaceStore(db), "default")
+		createEntityConfig(t, NewEntityConfigStore(db), "default", "entity")
+		createEntityState(t, NewEntityStateStore(db), "default", "entity")
+
+		s := NewEntityStateHistoryStore(db)
+		state := corev3.FixtureEntityState("entity")
+		for i := 0; i < storev2.MaxEntityStateSnapshots+2; i++ {
+			state.System.Hostname = fmt.Sprintf("host-%d", i)
+			// The unchanged systems are only recorded once
+			for j := 0; j < 2; j++ {
+				if err := s.AddEntityStateSnapshot(ctx, storev2.NewEntityStateSnapshot(state, int64(i))); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		snapshots, err := s.GetEntityStateHistory(ctx, "default", "entity")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(snapshots), storev2.MaxEntityStateSnapshots; got != want {
+			t.Fatalf("bad number of snapshots: got %d, want %d", got, want)
+		}
+		if got, want := snapshots[0].System.Hostname, fmt.Sprintf("host-%d", storev2.MaxEntityStateSnapshots+1); got != want {
+			t.Errorf("bad most recent snapshot: got %s, want %s", got, want)
+		}
+
+		missing := storev2.NewEntityStateSnapshot(corev3.FixtureEntityState("missing"), 0)
+		if _, ok := s.AddEntityStateSnapshot(ctx, missing).(*store.ErrNotFound); !ok {
+			t.Errorf("expected not found error")
+		}
+	})
+}
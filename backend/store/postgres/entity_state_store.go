@@ -215,6 +215,27 @@ func (s *EntityStateStore) HardDelete(ctx context.Context, namespace, name strin
 	return nil
 }
 
+// Undelete restores a soft deleted entity state using the given namespace &
+// name.
+func (s *EntityStateStore) Undelete(ctx context.Context, namespace, name string) error {
+	if namespace == "" {
+		return &store.ErrNotValid{Err: errors.New("must specify namespace")}
+	}
+	if name == "" {
+		return &store.ErrNotValid{Err: errors.New("must specify name")}
+	}
+
+	result, err := s.db.Exec(ctx, undeleteEntityStateQuery, namespace, name)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	affected := result.RowsAffected()
+	if affected < 1 {
+		return &store.ErrNotFound{Key: entityStateStoreKey(namespace, name)}
+	}
+	return nil
+}
+
 // HardDeleted determines if an entity state has been hard deleted.
 func (s *EntityStateStore) HardDeleted(ctx context.Context, namespace, name string) (bool, error) {
 	if namespace == "" {
@@ -525,6 +525,35 @@ WHERE
 	name = $2;
 `
 
+const undeleteEntityStateQuery = `
+-- This query restores a soft deleted entity state.
+--
+-- Parameters:
+-- $1 Namespace
+-- $2 Entity name
+WITH namespace AS (
+	SELECT id FROM namespaces
+	WHERE name = $1 AND deleted_at IS NULL
+)
+UPDATE entity_states
+SET deleted_at = NULL
+WHERE
+	namespace_id = (SELECT id FROM namespace) AND
+	name = $2 AND
+	deleted_at IS NOT NULL;
+`
+
+const purgeEntityStatesQuery = `
+-- This query hard deletes the entity states soft deleted before a given time.
+-- Any related system & network state will also be deleted via
+-- ON DELETE CASCADE triggers.
+--
+-- Parameters:
+-- $1 Time before which the entity states were soft deleted
+DELETE FROM entity_states
+WHERE deleted_at < $1;
+`
+
 const hardDeletedEntityStateQuery = `
 -- This query discovers if an entity state has been hard deleted.
 --
@@ -4,12 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/sensu-go/backend/store"
 )
 
+var (
+	_ store.EntityBatchUpdater = &EntityStore{}
+	_ store.EntityUndeleter    = &EntityStore{}
+)
+
 type EntityStore struct {
 	db DBI
 }
@@ -93,6 +100,61 @@ func (s *EntityStore) deleteEntity(ctx context.Context, name, namespace string)
 	return nil
 }
 
+// UndeleteEntity restores a soft deleted entity config, along with its state,
+// using the given name and the namespace stored in ctx.
+func (s *EntityStore) UndeleteEntity(ctx context.Context, name string) error {
+	if name == "" {
+		return &store.ErrNotValid{Err: errors.New("must specify name")}
+	}
+	namespace := corev2.ContextNamespace(ctx)
+
+	entityConfigStore, entityStateStore, cleanup, err := prepareEntityStores(ctx, s.db)
+	if err != nil {
+		return err
+	}
+	var rollback bool
+	defer cleanup(&rollback)
+
+	if err := entityConfigStore.Undelete(ctx, namespace, name); err != nil {
+		rollback = true
+		return err
+	}
+
+	// Proxy entities created through the API may not have a state yet
+	if err := entityStateStore.Undelete(ctx, namespace, name); err != nil {
+		var e *store.ErrNotFound
+		if !errors.As(err, &e) {
+			rollback = true
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PurgeDeletedEntities hard deletes the entity configs and states which were
+// soft deleted before the given time. It returns the number of purged entity
+// configs.
+func (s *EntityStore) PurgeDeletedEntities(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, &store.ErrInternal{Message: err.Error()}
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, purgeEntityStatesQuery, before); err != nil {
+		return 0, &store.ErrInternal{Message: err.Error()}
+	}
+	result, err := tx.Exec(ctx, purgeEntityConfigsQuery, before)
+	if err != nil {
+		return 0, &store.ErrInternal{Message: err.Error()}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, &store.ErrInternal{Message: err.Error()}
+	}
+	return result.RowsAffected(), nil
+}
+
 type uniqueResource struct {
 	Name      string
 	Namespace string
@@ -228,3 +290,56 @@ func (s *EntityStore) UpdateEntity(ctx context.Context, entity *corev2.Entity) e
 	}
 	return nil
 }
+
+// UpdateEntities creates or updates the given entities in a single
+// transaction. Each entity is written under its own savepoint, so that an
+// invalid entity does not prevent the others from being stored.
+func (s *EntityStore) UpdateEntities(ctx context.Context, entities []*corev2.Entity) []error {
+	errs := make([]error, len(entities))
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		for i := range errs {
+			errs[i] = &store.ErrInternal{Message: err.Error()}
+		}
+		return errs
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for i, entity := range entities {
+		errs[i] = updateEntitySavepoint(ctx, tx, entity)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		for i := range errs {
+			if errs[i] == nil {
+				errs[i] = &store.ErrInternal{Message: err.Error()}
+			}
+		}
+	}
+	return errs
+}
+
+func updateEntitySavepoint(ctx context.Context, tx pgx.Tx, entity *corev2.Entity) error {
+	if entity.Namespace == "" {
+		entity.Namespace = corev2.ContextNamespace(ctx)
+	}
+
+	cfg, state := corev3.V2EntityToV3(entity)
+
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	if err := NewEntityConfigStore(savepoint).CreateOrUpdate(ctx, cfg); err != nil {
+		_ = savepoint.Rollback(ctx)
+		return fmt.Errorf("error updating entity config: %w", err)
+	}
+	if err := NewEntityStateStore(savepoint).CreateOrUpdate(ctx, state); err != nil {
+		_ = savepoint.Rollback(ctx)
+		return fmt.Errorf("error updating entity state: %w", err)
+	}
+	if err := savepoint.Commit(ctx); err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	return nil
+}
@@ -2,7 +2,9 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
@@ -213,3 +215,62 @@ func TestEntityDeleteByName(t *testing.T) {
 		}
 	})
 }
+
+func TestEntityUndeleteAndPurge(t *testing.T) {
+	testWithPostgresStore(t, func(str storev2.Interface) {
+		db := str.(*Store).db
+		s := NewEntityStore(db)
+		entity := corev2.FixtureEntity("entity")
+		ctx := context.WithValue(context.Background(), corev2.NamespaceKey, entity.Namespace)
+
+		namespace := corev3.FixtureNamespace(entity.Namespace)
+		if err := str.GetNamespaceStore().CreateOrUpdate(ctx, namespace); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.UpdateEntity(ctx, entity); err != nil {
+			t.Fatal(err)
+		}
+
+		// Only soft deleted entities can be restored
+		var notFound *store.ErrNotFound
+		if err := s.UndeleteEntity(ctx, "entity"); !errors.As(err, &notFound) {
+			t.Fatalf("expected a not found error, got %v", err)
+		}
+
+		if err := s.DeleteEntityByName(ctx, "entity"); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.UndeleteEntity(ctx, "entity"); err != nil {
+			t.Fatal(err)
+		}
+		retrieved, err := s.GetEntityByName(ctx, "entity")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if retrieved == nil {
+			t.Fatal("the entity was not restored")
+		}
+
+		// The entities deleted after the given time are kept
+		if err := s.DeleteEntityByName(ctx, "entity"); err != nil {
+			t.Fatal(err)
+		}
+		purged, err := s.PurgeDeletedEntities(ctx, time.Now().Add(-time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := purged, int64(0); got != want {
+			t.Errorf("bad purged entities: got %d, want %d", got, want)
+		}
+		purged, err = s.PurgeDeletedEntities(ctx, time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := purged, int64(1); got != want {
+			t.Errorf("bad purged entities: got %d, want %d", got, want)
+		}
+		if err := s.UndeleteEntity(ctx, "entity"); !errors.As(err, &notFound) {
+			t.Fatalf("expected a not found error, got %v", err)
+		}
+	})
+}
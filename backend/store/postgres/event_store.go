@@ -168,7 +168,34 @@ func scanEvents(rows pgx.Rows, pred *store.SelectionPredicate) ([]*corev2.Event,
 }
 
 type continueToken struct {
-	Offset int64 `json:"offset"`
+	Offset int64        `json:"offset,omitempty"`
+	Cursor *eventCursor `json:"cursor,omitempty"`
+}
+
+// eventCursor is the continue token of event lists. It records the position
+// of the last event of a page in the ordering of the list, so that the next
+// page starts right after that event, even if events were created or deleted
+// in the meantime.
+type eventCursor struct {
+	Ordering   string `json:"ordering,omitempty"`
+	Descending bool   `json:"descending,omitempty"`
+	Key        int64  `json:"key,omitempty"`
+	Namespace  int64  `json:"namespace"`
+	Entity     string `json:"entity"`
+	Check      string `json:"check"`
+}
+
+// values returns the values of the cursor, in the order of the columns that
+// events are sorted on.
+func (c *eventCursor) values() []interface{} {
+	if c.Ordering == corev2.EventSortEntity {
+		return []interface{}{c.Entity, c.Namespace, c.Check}
+	}
+	values := []interface{}{c.Namespace, c.Entity, c.Check}
+	if _, ok := eventSortKeys[c.Ordering]; ok {
+		values = append([]interface{}{c.Key}, values...)
+	}
+	return values
 }
 
 func (c *continueToken) Encode() string {
@@ -197,6 +224,19 @@ func (e *EventStore) DeleteEventByEntityCheck(ctx context.Context, entity, check
 	return nil
 }
 
+// PruneEvents deletes the events of the namespace older than before, and the
+// oldest events beyond max. Keepalive events are never pruned.
+func (e *EventStore) PruneEvents(ctx context.Context, namespace string, before int64, max int) (int64, error) {
+	if before <= 0 && max <= 0 {
+		return 0, nil
+	}
+	tag, err := e.db.Exec(ctx, pruneEvents, namespace, before, max)
+	if err != nil {
+		return 0, &store.ErrInternal{Message: fmt.Sprintf("couldn't prune events: %s", err)}
+	}
+	return tag.RowsAffected(), nil
+}
+
 func getLimitAndOffset(pred *store.SelectionPredicate) (sql.NullInt64, int64, error) {
 	var limit sql.NullInt64
 	var offset int64
@@ -218,6 +258,74 @@ func getLimitAndOffset(pred *store.SelectionPredicate) (sql.NullInt64, int64, er
 	return limit, offset, nil
 }
 
+// getEventsPage returns the limit and offset of the page of events selected
+// by the predicate, and the cursor it starts after, if any. Offset continue
+// tokens are still honored.
+func getEventsPage(pred *store.SelectionPredicate, ordering string) (sql.NullInt64, int64, *eventCursor, error) {
+	var limit sql.NullInt64
+	var offset int64
+	if pred.Limit <= 0 {
+		return limit, offset, nil, nil
+	}
+	limit.Int64, limit.Valid = pred.Limit, true
+	offset = pred.Offset
+	if pred.Continue == "" {
+		return limit, offset, nil, nil
+	}
+	var token continueToken
+	if err := token.Decode(pred.Continue); err != nil {
+		return limit, offset, nil, &store.ErrNotValid{Err: fmt.Errorf("couldn't get events: error decoding token: %s", err)}
+	}
+	if token.Cursor == nil {
+		return limit, token.Offset, nil, nil
+	}
+	if token.Cursor.Ordering != ordering || token.Cursor.Descending != pred.Descending {
+		return limit, offset, nil, &store.ErrNotValid{Err: errors.New("couldn't get events: continue token does not match the requested ordering")}
+	}
+	return limit, 0, token.Cursor, nil
+}
+
+// scanEventsPage reads the events of a query created with
+// CreateGetEventsQuery, and sets the continue token of the predicate to the
+// cursor of the next page, if the page is full.
+func scanEventsPage(rows pgx.Rows, pred *store.SelectionPredicate) ([]*corev2.Event, error) {
+	var (
+		serialized []byte
+		cursor     eventCursor
+	)
+	events := []*corev2.Event{}
+	for rows.Next() {
+		if err := rows.Scan(&serialized, &cursor.Key, &cursor.Namespace, &cursor.Entity, &cursor.Check); err != nil {
+			return nil, &store.ErrNotValid{Err: fmt.Errorf("error reading events: %s", err)}
+		}
+		decompressed, err := snappy.Decode(nil, serialized)
+		if err != nil {
+			return nil, &store.ErrNotValid{Err: err}
+		}
+		var event corev2.Event
+		if err := proto.Unmarshal(decompressed, &event); err != nil {
+			return nil, &store.ErrDecode{Err: fmt.Errorf("error reading events: %s", err)}
+		}
+		if event.Check == nil {
+			return nil, &store.ErrNotValid{Err: errors.New("nil check")}
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("error reading events: %s", err)}
+	}
+	if pred != nil {
+		pred.Continue = ""
+		if pred.Limit > 0 && int64(len(events)) == pred.Limit {
+			// The ordering was validated when the query was created
+			cursor.Ordering, _ = eventOrdering(pred)
+			cursor.Descending = pred.Descending
+			pred.Continue = (&continueToken{Cursor: &cursor}).Encode()
+		}
+	}
+	return events, nil
+}
+
 func (e *EventStore) GetEvents(ctx context.Context, pred *store.SelectionPredicate) ([]*corev2.Event, error) {
 	ns := corev2.ContextNamespace(ctx)
 	if ns == corev2.NamespaceTypeAll {
@@ -232,7 +340,7 @@ func (e *EventStore) GetEvents(ctx context.Context, pred *store.SelectionPredica
 		return nil, &store.ErrInternal{Message: fmt.Sprintf("couldn't get events: %s", err)}
 	}
 	defer rows.Close()
-	return scanEvents(rows, pred)
+	return scanEventsPage(rows, pred)
 }
 
 func (e *EventStore) GetEventsByEntity(ctx context.Context, entity string, pred *store.SelectionPredicate) ([]*corev2.Event, error) {
@@ -253,7 +361,7 @@ func (e *EventStore) GetEventsByEntity(ctx context.Context, entity string, pred
 		return nil, &store.ErrInternal{Message: fmt.Sprintf("couldn't get events: %s", err)}
 	}
 	defer rows.Close()
-	return scanEvents(rows, pred)
+	return scanEventsPage(rows, pred)
 }
 
 func (e *EventStore) GetEventByEntityCheck(ctx context.Context, entity, check string) (*corev2.Event, error) {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -38,6 +39,37 @@ func TestEventStorageMaxOutputSize(t *testing.T) {
 	})
 }
 
+func TestPruneEvents(t *testing.T) {
+	testWithPostgresEventStore(t, func(s store.EventStore, sv2 storev2.Interface) {
+		ctx := context.WithValue(context.Background(), corev2.NamespaceKey, "default")
+		for i, entity := range []string{"a", "b", "c", "d"} {
+			event := corev2.FixtureEvent(entity, "check")
+			event.Timestamp = int64(i+1) * 100
+			if _, _, err := s.UpdateEvent(ctx, event); err != nil {
+				t.Fatal(err)
+			}
+		}
+		keepalive := corev2.FixtureEvent("a", "keepalive")
+		keepalive.Timestamp = 1
+		if _, _, err := s.UpdateEvent(ctx, keepalive); err != nil {
+			t.Fatal(err)
+		}
+
+		pruner := s.(store.EventPruner)
+		pruned, err := pruner.PruneEvents(ctx, "default", 150, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), pruned)
+
+		pruned, err = pruner.PruneEvents(ctx, "default", 0, 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), pruned)
+
+		count, err := s.CountEvents(ctx, &store.SelectionPredicate{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+}
+
 func TestEventStorage(t *testing.T) {
 	testWithPostgresEventStore(t, func(s store.EventStore, sv2 storev2.Interface) {
 		event := corev2.FixtureEvent("entity1", "check1")
@@ -378,6 +410,72 @@ func TestGetEventsOrdering(t *testing.T) {
 	})
 }
 
+func TestGetEventsOrderingCursor(t *testing.T) {
+	testWithPostgresEventStore(t, func(s store.EventStore, sv2 storev2.Interface) {
+		ctx := store.NamespaceContext(context.Background(), "default")
+
+		statuses := map[string]uint32{"a": 0, "b": 2, "c": 1, "d": 2, "e": 0}
+		for entity, status := range statuses {
+			event := corev2.FixtureEvent(entity, "check")
+			event.Check.Status = status
+			if _, _, err := s.UpdateEvent(ctx, event); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		names := func(events []*corev2.Event) string {
+			var names []string
+			for _, event := range events {
+				names = append(names, event.Entity.Name)
+			}
+			return strings.Join(names, ",")
+		}
+
+		pred := &store.SelectionPredicate{Ordering: corev2.EventSortSeverity, Limit: 2}
+		events, err := s.GetEvents(ctx, pred)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := names(events), "b,d"; got != want {
+			t.Fatalf("bad first page: got %s, want %s", got, want)
+		}
+
+		// The next page starts after the last event of the previous page, even
+		// when events of the previous page are deleted.
+		if err := s.DeleteEventByEntityCheck(ctx, "b", "check"); err != nil {
+			t.Fatal(err)
+		}
+		events, err = s.GetEvents(ctx, pred)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := names(events), "c,a"; got != want {
+			t.Fatalf("bad second page: got %s, want %s", got, want)
+		}
+
+		events, err = s.GetEvents(ctx, pred)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := names(events), "e"; got != want {
+			t.Fatalf("bad last page: got %s, want %s", got, want)
+		}
+		if pred.Continue != "" {
+			t.Fatalf("expected no continue token, got %s", pred.Continue)
+		}
+
+		// The continue token is bound to the ordering of the list
+		pred = &store.SelectionPredicate{Ordering: corev2.EntitySortName, Limit: 1}
+		if _, err := s.GetEvents(ctx, pred); err != nil {
+			t.Fatal(err)
+		}
+		pred.Ordering = corev2.EventSortTimestamp
+		if _, err := s.GetEvents(ctx, pred); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
 func TestGetEventsPagination(t *testing.T) {
 	testWithPostgresEventStore(t, func(s store.EventStore, sv2 storev2.Interface) {
 		// Add 42 objects in the store: 21 in the "default" namespace and 21 in
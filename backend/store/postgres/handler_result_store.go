@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"path"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.HandlerResultStore = &HandlerResultStore{}
+
+type HandlerResultStore struct {
+	db DBI
+}
+
+func NewHandlerResultStore(db DBI) *HandlerResultStore {
+	return &HandlerResultStore{db: db}
+}
+
+const addHandlerResultQuery = `
+INSERT INTO handler_results (
+	namespace, entity_name, check_name, handler_name, executed, duration, status, output, error
+)
+SELECT entity_configs.namespace_id, entity_configs.name, $3, $4, $5, $6, $7, $8, $9
+FROM
+	entity_configs
+	JOIN namespaces ON entity_configs.namespace_id = namespaces.id
+WHERE
+	namespaces.name = $1
+	AND namespaces.deleted_at IS NULL
+	AND entity_configs.name = $2
+	AND entity_configs.deleted_at IS NULL;
+`
+
+const pruneHandlerResultsQuery = `
+WITH ns AS (
+	SELECT id FROM namespaces WHERE name = $1 AND deleted_at IS NULL
+)
+DELETE FROM handler_results
+WHERE namespace = (SELECT id FROM ns)
+	AND entity_name = $2
+	AND check_name = $3
+	AND handler_name = $4
+	AND id NOT IN (
+		SELECT id FROM handler_results
+		WHERE namespace = (SELECT id FROM ns)
+			AND entity_name = $2
+			AND check_name = $3
+			AND handler_name = $4
+		ORDER BY id DESC
+		LIMIT $5
+	);
+`
+
+// AddHandlerResult records a handler result and removes the results of the
+// same event and handler that exceed storev2.MaxHandlerResults. The entity of
+// the result must exist, and its results are deleted when it is purged.
+func (s *HandlerResultStore) AddHandlerResult(ctx context.Context, result *storev2.HandlerResult) (fErr error) {
+	result.TruncateOutput()
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	defer func() {
+		if fErr == nil {
+			fErr = tx.Commit(ctx)
+			return
+		}
+		if txerr := tx.Rollback(ctx); txerr != nil && txerr != pgx.ErrTxClosed {
+			fErr = txerr
+		}
+	}()
+	tag, err := tx.Exec(ctx, addHandlerResultQuery,
+		result.Namespace,
+		result.Entity,
+		result.Check,
+		result.Handler,
+		result.Executed,
+		result.Duration,
+		result.Status,
+		result.Output,
+		result.Error,
+	)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	if tag.RowsAffected() == 0 {
+		return &store.ErrNotFound{Key: path.Join(result.Namespace, result.Entity)}
+	}
+	_, err = tx.Exec(ctx, pruneHandlerResultsQuery,
+		result.Namespace,
+		result.Entity,
+		result.Check,
+		result.Handler,
+		storev2.MaxHandlerResults,
+	)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	return nil
+}
+
+const getHandlerResultsQuery = `
+SELECT
+	namespaces.name,
+	handler_results.entity_name,
+	handler_results.check_name,
+	handler_results.handler_name,
+	handler_results.executed,
+	handler_results.duration,
+	handler_results.status,
+	handler_results.output,
+	handler_results.error
+FROM
+	handler_results
+	JOIN namespaces ON handler_results.namespace = namespaces.id
+WHERE
+	namespaces.name = $1
+	AND handler_results.entity_name = $2
+	AND handler_results.check_name = $3
+ORDER BY handler_results.id DESC;
+`
+
+// GetHandlerResults gets the handler results of an event, most recent first.
+func (s *HandlerResultStore) GetHandlerResults(ctx context.Context, namespace, entity, check string) ([]*storev2.HandlerResult, error) {
+	rows, err := s.db.Query(ctx, getHandlerResultsQuery, namespace, entity, check)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	defer rows.Close()
+	results := []*storev2.HandlerResult{}
+	for rows.Next() {
+		var result storev2.HandlerResult
+		err := rows.Scan(
+			&result.Namespace,
+			&result.Entity,
+			&result.Check,
+			&result.Handler,
+			&result.Executed,
+			&result.Duration,
+			&result.Status,
+			&result.Output,
+			&result.Error,
+		)
+		if err != nil {
+			return nil, &store.ErrInternal{Message: err.Error()}
+		}
+		results = append(results, &result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	return results, nil
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+func TestHandlerResultStore(t *testing.T) {
+	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
+		if err := NewNamespaceStore(db).CreateIfNotExists(ctx, corev3.FixtureNamespace("default")); err != nil {
+			t.Fatal(err)
+		}
+		s := NewHandlerResultStore(db)
+		result := &storev2.HandlerResult{
+			Namespace: "default",
+			Entity:    "entity",
+			Check:     "check",
+			Handler:   "handler",
+		}
+		if err := s.AddHandlerResult(ctx, result); !errors.As(err, new(*store.ErrNotFound)) {
+			t.Fatalf("expected not found, got %v", err)
+		}
+		if err := NewEntityConfigStore(db).CreateOrUpdate(ctx, corev3.FixtureEntityConfig("entity")); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < storev2.MaxHandlerResults+2; i++ {
+			result.Executed = int64(i)
+			if err := s.AddHandlerResult(ctx, result); err != nil {
+				t.Fatal(err)
+			}
+		}
+		results, err := s.GetHandlerResults(ctx, "default", "entity", "check")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(results), storev2.MaxHandlerResults; got != want {
+			t.Fatalf("bad number of results: got %d, want %d", got, want)
+		}
+		if got, want := results[0].Executed, int64(storev2.MaxHandlerResults+1); got != want {
+			t.Errorf("bad most recent result: got %d, want %d", got, want)
+		}
+
+		// The results are deleted with their entity
+		if err := NewEntityConfigStore(db).HardDelete(ctx, "default", "entity"); err != nil {
+			t.Fatal(err)
+		}
+		results, err = s.GetHandlerResults(ctx, "default", "entity", "check")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 0 {
+			t.Errorf("expected no results, got %d", len(results))
+		}
+	})
+}
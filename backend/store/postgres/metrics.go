@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	metricspkg "github.com/sensu/sensu-go/metrics"
+)
+
+const (
+	// QueryDuration is the name of the prometheus summary vec used to track
+	// the latencies of the postgresql queries, by query name.
+	QueryDuration = "sensu_go_postgres_query_duration"
+
+	// PoolConnections is the name of the prometheus gauge vec used to track
+	// the connections of the postgresql pool, by state.
+	PoolConnections = "sensu_go_postgres_pool_connections"
+
+	// PoolMaxConnections is the name of the prometheus gauge of the maximum
+	// number of connections of the postgresql pool.
+	PoolMaxConnections = "sensu_go_postgres_pool_max_connections"
+
+	// ReplicaLag is the name of the prometheus gauge of the replication lag of
+	// the postgresql read replica, in seconds.
+	ReplicaLag = "sensu_go_postgres_replica_lag_seconds"
+
+	// poolStatsInterval is the interval at which the pool gauges are updated.
+	poolStatsInterval = 10 * time.Second
+)
+
+var (
+	queryDuration = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name:       QueryDuration,
+			Help:       "postgresql query latency distribution",
+			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+		},
+		[]string{metricspkg.StatusLabelName, "query"},
+	)
+
+	poolConnections = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: PoolConnections,
+			Help: "The number of connections of the postgresql pool, by state",
+		},
+		[]string{"state"},
+	)
+
+	poolMaxConnections = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: PoolMaxConnections,
+			Help: "The maximum number of connections of the postgresql pool",
+		},
+	)
+
+	replicaLag = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: ReplicaLag,
+			Help: "The replication lag of the postgresql read replica in seconds",
+		},
+	)
+)
+
+func init() {
+	if err := prometheus.Register(queryDuration); err != nil {
+		panic(fmt.Errorf("error registering %s: %s", QueryDuration, err))
+	}
+	if err := prometheus.Register(poolConnections); err != nil {
+		panic(fmt.Errorf("error registering %s: %s", PoolConnections, err))
+	}
+	if err := prometheus.Register(poolMaxConnections); err != nil {
+		panic(fmt.Errorf("error registering %s: %s", PoolMaxConnections, err))
+	}
+	if err := prometheus.Register(replicaLag); err != nil {
+		panic(fmt.Errorf("error registering %s: %s", ReplicaLag, err))
+	}
+}
+
+// observePool updates the pool gauges with the statistics of the pool until
+// the context is done.
+func observePool(ctx context.Context, pool *pgxpool.Pool) {
+	ticker := time.NewTicker(poolStatsInterval)
+	defer ticker.Stop()
+	for {
+		stat := pool.Stat()
+		poolConnections.WithLabelValues("acquired").Set(float64(stat.AcquiredConns()))
+		poolConnections.WithLabelValues("idle").Set(float64(stat.IdleConns()))
+		poolConnections.WithLabelValues("constructing").Set(float64(stat.ConstructingConns()))
+		poolConnections.WithLabelValues("total").Set(float64(stat.TotalConns()))
+		poolMaxConnections.Set(float64(stat.MaxConns()))
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	name string
+	time time.Time
+}
+
+// queryTracer records the latency of the queries in the query duration
+// summary.
+type queryTracer struct{}
+
+func (queryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{name: queryName(data.SQL), time: time.Now()})
+}
+
+func (queryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	status := metricspkg.StatusLabelSuccess
+	if data.Err != nil {
+		status = metricspkg.StatusLabelError
+	}
+	queryDuration.WithLabelValues(status, start.name).Observe(float64(time.Since(start.time)) / float64(time.Millisecond))
+}
+
+// queryName returns the name of a query in the query metrics, made of its
+// command and of the table it reads or writes, like insert_configuration. The
+// common table expressions of the query are skipped, and the comments are
+// ignored.
+func queryName(sql string) string {
+	var fields []string
+	for _, line := range strings.Split(sql, "\n") {
+		if i := strings.Index(line, "--"); i >= 0 {
+			line = line[:i]
+		}
+		fields = append(fields, strings.Fields(line)...)
+	}
+	if len(fields) == 0 {
+		return "unknown"
+	}
+
+	command, keyword := "", ""
+	depth := 0
+	for i, field := range fields {
+		lower := strings.ToLower(field)
+		if depth == 0 {
+			if keyword != "" && lower == keyword && i+1 < len(fields) {
+				return withTable(command, fields[i+1])
+			}
+			if keyword == "" || command == "with" {
+				switch lower {
+				case "select", "delete":
+					command, keyword = lower, "from"
+				case "insert":
+					command, keyword = lower, "into"
+				case "update":
+					if i+1 < len(fields) {
+						return withTable(lower, fields[i+1])
+					}
+					return lower
+				case "with":
+					command, keyword = lower, "with"
+				default:
+					if i == 0 {
+						return lower
+					}
+				}
+			}
+		}
+		depth += strings.Count(field, "(") - strings.Count(field, ")")
+	}
+	return command
+}
+
+// withTable appends the name of the table of a query field to a command, unless
+// the field is a subquery.
+func withTable(command, field string) string {
+	if i := strings.IndexByte(field, '('); i >= 0 {
+		field = field[:i]
+	}
+	table := strings.ToLower(strings.Trim(field, `;,"`))
+	if table == "" {
+		return command
+	}
+	return command + "_" + table
+}
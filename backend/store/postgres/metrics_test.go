@@ -0,0 +1,25 @@
+package postgres
+
+import "testing"
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{sql: "SELECT * FROM events WHERE namespace = $1", want: "select_events"},
+		{sql: "select count(*) from (SELECT id FROM entities) AS e", want: "select"},
+		{sql: "SELECT NOT EXISTS (SELECT 1 FROM namespaces WHERE name = $1)", want: "select"},
+		{sql: "\n\t-- comment\n\tINSERT INTO configuration (api_version) VALUES ($1)", want: "insert_configuration"},
+		{sql: "UPDATE entity_configs SET deleted_at = NULL", want: "update_entity_configs"},
+		{sql: "DELETE FROM \"silences\" WHERE id = $1;", want: "delete_silences"},
+		{sql: "WITH ns AS (SELECT id FROM namespaces WHERE name = $1) UPDATE entity_states SET x = 1", want: "update_entity_states"},
+		{sql: "LISTEN ring", want: "listen"},
+		{sql: "   ", want: "unknown"},
+	}
+	for _, test := range tests {
+		if got := queryName(test.sql); got != test.want {
+			t.Errorf("queryName(%q): got %q, want %q", test.sql, got, test.want)
+		}
+	}
+}
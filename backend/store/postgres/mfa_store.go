@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.MFAStore = &MFAStore{}
+
+type MFAStore struct {
+	db DBI
+}
+
+func NewMFAStore(db DBI) *MFAStore {
+	return &MFAStore{db: db}
+}
+
+// putMFAEnrollmentQuery replaces the enrollment of a user, unless it is
+// confirmed.
+const putMFAEnrollmentQuery = `
+INSERT INTO mfa_enrollments ( username, secret, confirmed, created_at, last_counter )
+VALUES ( $1, $2, false, $3, 0 )
+ON CONFLICT ( username ) DO UPDATE
+	SET secret = excluded.secret, confirmed = false, created_at = excluded.created_at, last_counter = 0
+	WHERE NOT mfa_enrollments.confirmed;
+`
+
+const deleteMFARecoveryCodesQuery = `DELETE FROM mfa_recovery_codes WHERE username = $1;`
+
+const addMFARecoveryCodesQuery = `
+INSERT INTO mfa_recovery_codes ( username, code_hash )
+SELECT $1, unnest($2::text[]);
+`
+
+// PutMFAEnrollment replaces the enrollment of a user and its recovery codes
+// with a new, unconfirmed enrollment.
+func (s *MFAStore) PutMFAEnrollment(ctx context.Context, enrollment *storev2.MFAEnrollment, recoveryCodeHashes []string) (fErr error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	defer func() {
+		if fErr == nil {
+			fErr = tx.Commit(ctx)
+			return
+		}
+		if txerr := tx.Rollback(ctx); txerr != nil && txerr != pgx.ErrTxClosed {
+			fErr = txerr
+		}
+	}()
+	tag, err := tx.Exec(ctx, putMFAEnrollmentQuery, enrollment.Username, enrollment.Secret, enrollment.CreatedAt)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	if tag.RowsAffected() == 0 {
+		return &store.ErrAlreadyExists{Key: enrollment.Username}
+	}
+	if _, err := tx.Exec(ctx, deleteMFARecoveryCodesQuery, enrollment.Username); err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	if recoveryCodeHashes == nil {
+		recoveryCodeHashes = []string{}
+	}
+	if _, err := tx.Exec(ctx, addMFARecoveryCodesQuery, enrollment.Username, recoveryCodeHashes); err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	return nil
+}
+
+const getMFAEnrollmentQuery = `
+SELECT username, secret, confirmed, created_at, last_counter
+FROM mfa_enrollments
+WHERE username = $1;
+`
+
+// GetMFAEnrollment gets the enrollment of a user.
+func (s *MFAStore) GetMFAEnrollment(ctx context.Context, username string) (*storev2.MFAEnrollment, error) {
+	var enrollment storev2.MFAEnrollment
+	err := s.db.QueryRow(ctx, getMFAEnrollmentQuery, username).Scan(
+		&enrollment.Username,
+		&enrollment.Secret,
+		&enrollment.Confirmed,
+		&enrollment.CreatedAt,
+		&enrollment.LastCounter,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, &store.ErrNotFound{Key: username}
+		}
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	return &enrollment, nil
+}
+
+const confirmMFAEnrollmentQuery = `UPDATE mfa_enrollments SET confirmed = true WHERE username = $1;`
+
+// ConfirmMFAEnrollment confirms the enrollment of a user.
+func (s *MFAStore) ConfirmMFAEnrollment(ctx context.Context, username string) error {
+	tag, err := s.db.Exec(ctx, confirmMFAEnrollmentQuery, username)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	if tag.RowsAffected() == 0 {
+		return &store.ErrNotFound{Key: username}
+	}
+	return nil
+}
+
+const useMFACounterQuery = `
+UPDATE mfa_enrollments SET last_counter = $2
+WHERE username = $1 AND last_counter < $2;
+`
+
+// UseMFACounter records that the one-time password of the given time step was
+// used by a user.
+func (s *MFAStore) UseMFACounter(ctx context.Context, username string, counter int64) (bool, error) {
+	tag, err := s.db.Exec(ctx, useMFACounterQuery, username, counter)
+	if err != nil {
+		return false, &store.ErrInternal{Message: err.Error()}
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+const useMFARecoveryCodeQuery = `DELETE FROM mfa_recovery_codes WHERE username = $1 AND code_hash = $2;`
+
+// UseMFARecoveryCode removes a recovery code of a user, and returns whether it
+// existed.
+func (s *MFAStore) UseMFARecoveryCode(ctx context.Context, username, codeHash string) (bool, error) {
+	tag, err := s.db.Exec(ctx, useMFARecoveryCodeQuery, username, codeHash)
+	if err != nil {
+		return false, &store.ErrInternal{Message: err.Error()}
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+const deleteMFAEnrollmentQuery = `DELETE FROM mfa_enrollments WHERE username = $1;`
+
+// DeleteMFAEnrollment removes the enrollment of a user. Its recovery codes are
+// removed in cascade.
+func (s *MFAStore) DeleteMFAEnrollment(ctx context.Context, username string) error {
+	if _, err := s.db.Exec(ctx, deleteMFAEnrollmentQuery, username); err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+func TestMFAStore(t *testing.T) {
+	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
+		s := NewMFAStore(db)
+		var notFound *store.ErrNotFound
+		if _, err := s.GetMFAEnrollment(ctx, "jane"); !errors.As(err, &notFound) {
+			t.Fatalf("expected a not found error, got %v", err)
+		}
+
+		enrollment := &storev2.MFAEnrollment{Username: "jane", Secret: "a", CreatedAt: 1}
+		if err := s.PutMFAEnrollment(ctx, enrollment, []string{"x", "y"}); err != nil {
+			t.Fatal(err)
+		}
+
+		// An unconfirmed enrollment can be replaced
+		enrollment.Secret = "b"
+		if err := s.PutMFAEnrollment(ctx, enrollment, []string{"z"}); err != nil {
+			t.Fatal(err)
+		}
+		if used, err := s.UseMFARecoveryCode(ctx, "jane", "x"); err != nil || used {
+			t.Fatalf("replaced recovery code used: %v", err)
+		}
+
+		if err := s.ConfirmMFAEnrollment(ctx, "jane"); err != nil {
+			t.Fatal(err)
+		}
+		var exists *store.ErrAlreadyExists
+		if err := s.PutMFAEnrollment(ctx, enrollment, nil); !errors.As(err, &exists) {
+			t.Fatalf("expected an already exists error, got %v", err)
+		}
+		got, err := s.GetMFAEnrollment(ctx, "jane")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Secret != "b" || !got.Confirmed {
+			t.Fatalf("bad enrollment: %v", got)
+		}
+
+		// The counters and the recovery codes can only be used once
+		for i, want := range []bool{true, false} {
+			if used, err := s.UseMFACounter(ctx, "jane", 10); err != nil || used != want {
+				t.Errorf("bad counter use %d: got %v, want %v (%v)", i, used, want, err)
+			}
+			if used, err := s.UseMFARecoveryCode(ctx, "jane", "z"); err != nil || used != want {
+				t.Errorf("bad recovery code use %d: got %v, want %v (%v)", i, used, want, err)
+			}
+		}
+
+		if err := s.DeleteMFAEnrollment(ctx, "jane"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.GetMFAEnrollment(ctx, "jane"); !errors.As(err, &notFound) {
+			t.Fatalf("expected a not found error, got %v", err)
+		}
+	})
+}
@@ -176,6 +176,66 @@ var Migrations = []migration.Migrator{
 		_, err := tx.Exec(context.Background(), "UPDATE configuration SET etag = digest(resource::text, 'sha1')")
 		return err
 	},
+	// Migration 29
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), migrateAddRingWeights)
+		return err
+	},
+	// Migration 30
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), handlerResultsDDL)
+		return err
+	},
+	// Migration 31
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), rateLimitsDDL)
+		return err
+	},
+	// Migration 32
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), auditLogDDL)
+		return err
+	},
+	// Migration 33
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), migrateAddConfigurationNotify)
+		return err
+	},
+	// Migration 34
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), entityStateHistoryDDL)
+		return err
+	},
+	// Migration 35
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), selectorIndexesDDL)
+		return err
+	},
+	// Migration 36
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), resourceHistoryDDL)
+		return err
+	},
+	// Migration 37
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), sessionsDDL)
+		return err
+	},
+	// Migration 38
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), mfaDDL)
+		return err
+	},
+	// Migration 39
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), migrateHandlerResultsEntity)
+		return err
+	},
+	// Migration 40
+	func(tx migration.LimitedTx) error {
+		_, err := tx.Exec(context.Background(), migrateRateLimitsExpiration)
+		return err
+	},
 }
 
 type eventRecord struct {
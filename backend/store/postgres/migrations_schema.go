@@ -415,3 +415,191 @@ CREATE TABLE events (
 const addConfigurationFields = `
 ALTER TABLE configuration
 ADD COLUMN fields JSONB NOT NULL DEFAULT '{}'::jsonb;`
+
+// Migration 29
+const migrateAddRingWeights = `
+-- weight is the number of times the entity is visited per iteration of the ring.
+ALTER TABLE ring_entities
+ADD COLUMN weight integer NOT NULL DEFAULT 1 CHECK ( weight > 0 );
+
+-- pointer_rep is the repetition of the entity the pointer refers to, in
+-- [1, weight].
+ALTER TABLE ring_subscribers
+ADD COLUMN pointer_rep integer NOT NULL DEFAULT 1;
+`
+
+// Migration 30
+const handlerResultsDDL = `
+CREATE TABLE IF NOT EXISTS handler_results (
+	id           bigserial PRIMARY KEY,
+	namespace    bigint    NOT NULL REFERENCES namespaces (id) ON DELETE CASCADE,
+	entity_name  text      NOT NULL,
+	check_name   text      NOT NULL,
+	handler_name text      NOT NULL,
+	executed     bigint    NOT NULL,
+	duration     double precision NOT NULL,
+	status       integer   NOT NULL,
+	output       text      NOT NULL,
+	error        text      NOT NULL
+);
+
+CREATE INDEX ON handler_results ( namespace, entity_name, check_name, handler_name );
+`
+
+// Migration 31
+const rateLimitsDDL = `
+-- window_start is the unix timestamp in milliseconds of the first occurrence
+-- of the current window.
+CREATE TABLE IF NOT EXISTS rate_limits (
+	key          text   PRIMARY KEY,
+	window_start bigint NOT NULL,
+	count        bigint NOT NULL
+);
+`
+
+// Migration 32
+const auditLogDDL = `
+CREATE TABLE IF NOT EXISTS audit_log (
+	id            bigserial        PRIMARY KEY,
+	timestamp     timestamptz      NOT NULL,
+	request_id    text             NOT NULL,
+	username      text             NOT NULL,
+	verb          text             NOT NULL,
+	api_group     text             NOT NULL,
+	api_version   text             NOT NULL,
+	namespace     text             NOT NULL,
+	resource      text             NOT NULL,
+	resource_name text             NOT NULL,
+	status        integer          NOT NULL,
+	outcome       text             NOT NULL,
+	latency       double precision NOT NULL
+);
+
+CREATE INDEX ON audit_log ( timestamp );
+`
+
+// Migration 33
+//
+// The notifications are sent on the configurationNotifyChannel channel when
+// the transaction commits. Their payload is the API version and the type of the
+// resource, joined by a slash.
+const migrateAddConfigurationNotify = `
+CREATE OR REPLACE FUNCTION notify_configuration_change()
+RETURNS TRIGGER AS $$
+BEGIN
+	PERFORM pg_notify('configuration', NEW.api_version || '/' || NEW.api_type);
+	RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER configuration_notify AFTER INSERT OR UPDATE
+	ON configuration FOR EACH ROW EXECUTE PROCEDURE
+	notify_configuration_change();
+`
+
+// Migration 34
+const entityStateHistoryDDL = `
+CREATE TABLE IF NOT EXISTS entity_state_history (
+	id              bigserial PRIMARY KEY,
+	entity_state_id bigint    NOT NULL REFERENCES entity_states (id) ON DELETE CASCADE,
+	timestamp       bigint    NOT NULL,
+	system          jsonb     NOT NULL
+);
+
+CREATE INDEX ON entity_state_history ( entity_state_id );
+`
+
+// Migration 35
+const selectorIndexesDDL = `
+CREATE INDEX IF NOT EXISTS idxginentityconfigs ON entity_configs USING GIN (selectors jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS idxginconfigurationlabels ON configuration USING GIN (labels jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS idxginconfigurationfields ON configuration USING GIN (fields jsonb_path_ops);
+`
+
+// Migration 36
+const resourceHistoryDDL = `
+CREATE TABLE IF NOT EXISTS resource_history (
+	id          bigserial PRIMARY KEY,
+	api_version text      NOT NULL,
+	api_type    text      NOT NULL,
+	namespace   text      NOT NULL,
+	name        text      NOT NULL,
+	version     bigint    NOT NULL,
+	author      text      NOT NULL,
+	timestamp   bigint    NOT NULL,
+	deleted     boolean   NOT NULL DEFAULT false,
+	resource    jsonb,
+	UNIQUE ( api_version, api_type, namespace, name, version )
+);
+`
+
+// Migration 37
+const sessionsDDL = `
+-- The timestamps are unix timestamps in seconds. The revoked tokens are kept
+-- until they expire.
+CREATE TABLE IF NOT EXISTS sessions (
+	id           text   PRIMARY KEY,
+	username     text   NOT NULL,
+	provider     text   NOT NULL,
+	issued_at    bigint NOT NULL,
+	refreshed_at bigint NOT NULL,
+	expires_at   bigint NOT NULL
+);
+
+CREATE INDEX ON sessions ( username );
+CREATE INDEX ON sessions ( expires_at );
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+	id         text   PRIMARY KEY,
+	expires_at bigint NOT NULL
+);
+
+CREATE INDEX ON revoked_tokens ( expires_at );
+`
+
+// Migration 38
+const mfaDDL = `
+-- The recovery codes are stored as SHA-256 hashes. The timestamps are unix
+-- timestamps in seconds.
+CREATE TABLE IF NOT EXISTS mfa_enrollments (
+	username     text    PRIMARY KEY,
+	secret       text    NOT NULL,
+	confirmed    boolean NOT NULL DEFAULT false,
+	created_at   bigint  NOT NULL,
+	last_counter bigint  NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+	username  text NOT NULL REFERENCES mfa_enrollments ( username ) ON DELETE CASCADE,
+	code_hash text NOT NULL,
+	PRIMARY KEY ( username, code_hash )
+);
+`
+
+// Migration 39
+const migrateHandlerResultsEntity = `
+-- The handler results are deleted with the entity configs, when the deleted
+-- entities are purged.
+DELETE FROM handler_results
+WHERE NOT EXISTS (
+	SELECT 1 FROM entity_configs
+	WHERE entity_configs.namespace_id = handler_results.namespace
+		AND entity_configs.name = handler_results.entity_name
+);
+
+ALTER TABLE handler_results
+ADD CONSTRAINT handler_results_entity_fkey FOREIGN KEY ( namespace, entity_name )
+REFERENCES entity_configs ( namespace_id, name )
+ON DELETE CASCADE;
+`
+
+// Migration 40
+const migrateRateLimitsExpiration = `
+-- expires_at is the unix timestamp in milliseconds of the end of the current
+-- window, after which the rate limit is removed. The windows of the existing
+-- rate limits are considered elapsed.
+ALTER TABLE rate_limits
+ADD COLUMN expires_at bigint NOT NULL DEFAULT 0;
+
+CREATE INDEX ON rate_limits ( expires_at );
+`
@@ -39,7 +39,13 @@ func open(ctx context.Context, config *pgxpool.Config, retryForever bool, migrat
 
 // Open opens a new postgresql database for storage. If the function
 // returns nil error, then the database will be upgraded to the latest schema
-// version, and will be ready to be used.
+// version, and will be ready to be used. The pool metrics are updated until
+// the context is done.
 func Open(ctx context.Context, config *pgxpool.Config, retryForever bool) (*pgxpool.Pool, error) {
-	return open(ctx, config, retryForever, Migrations)
+	db, err := open(ctx, config, retryForever, Migrations)
+	if err != nil {
+		return nil, err
+	}
+	go observePool(ctx, db)
+	return db, nil
 }
@@ -0,0 +1,20 @@
+WITH ns AS (
+	SELECT id
+	FROM namespaces
+	WHERE name = $1
+	LIMIT 1
+), ranked AS (
+	SELECT
+		events.id,
+		(events.selectors ->> 'event.timestamp')::bigint AS timestamp,
+		row_number() OVER (
+			ORDER BY (events.selectors ->> 'event.timestamp')::bigint DESC, events.id DESC
+		) AS rank
+	FROM events, ns
+	WHERE events.namespace = ns.id
+	  AND events.check_name != 'keepalive'
+)
+DELETE FROM events
+USING ranked
+WHERE events.id = ranked.id
+  AND (($2 > 0 AND ranked.timestamp < $2) OR ($3 > 0 AND ranked.rank > $3));
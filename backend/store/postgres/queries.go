@@ -16,3 +16,6 @@ var getKeepaliveCountsByNamespaceQuery string
 
 //go:embed getEventCountsByNamespaceQuery.sql
 var getEventCountsByNamespaceQuery string
+
+//go:embed pruneEvents.sql
+var pruneEvents string
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.RateLimitStore = &RateLimitStore{}
+
+type RateLimitStore struct {
+	db DBI
+}
+
+func NewRateLimitStore(db DBI) *RateLimitStore {
+	return &RateLimitStore{db: db}
+}
+
+// incrementRateLimitQuery increments the counter of a key, or resets it when
+// its window has elapsed. $2 is the current time and $3 the window length,
+// both in milliseconds.
+const incrementRateLimitQuery = `
+INSERT INTO rate_limits ( key, window_start, count, expires_at )
+VALUES ( $1, $2, 1, $2 + $3 )
+ON CONFLICT ( key ) DO UPDATE SET
+	window_start = CASE WHEN rate_limits.window_start + $3 <= $2 THEN $2 ELSE rate_limits.window_start END,
+	count = CASE WHEN rate_limits.window_start + $3 <= $2 THEN 1 ELSE rate_limits.count + 1 END,
+	expires_at = CASE WHEN rate_limits.window_start + $3 <= $2 THEN $2 + $3 ELSE rate_limits.window_start + $3 END
+RETURNING count;
+`
+
+const pruneRateLimitsQuery = `DELETE FROM rate_limits WHERE expires_at <= $1;`
+
+// IncrementRateLimit increments the counter of a key, or resets it when its
+// window has elapsed. When a window starts, the counters of the elapsed
+// windows are removed.
+func (s *RateLimitStore) IncrementRateLimit(ctx context.Context, key string, now time.Time, window time.Duration) (int64, error) {
+	var count int64
+	row := s.db.QueryRow(ctx, incrementRateLimitQuery, key, now.UnixMilli(), window.Milliseconds())
+	if err := row.Scan(&count); err != nil {
+		return 0, &store.ErrInternal{Message: err.Error()}
+	}
+	if count == 1 {
+		if _, err := s.db.Exec(ctx, pruneRateLimitsQuery, now.UnixMilli()); err != nil {
+			return 0, &store.ErrInternal{Message: err.Error()}
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+const (
+	// DefaultMaxReplicaLag is the default replication lag above which the
+	// reads are served by the primary instead of the read replica.
+	DefaultMaxReplicaLag = 5 * time.Second
+
+	// replicaLagInterval is the interval at which the replication lag of the
+	// read replica is checked.
+	replicaLagInterval = time.Second
+)
+
+// replicaLagQuery returns the replication lag of a replica in seconds. The lag
+// is 0 when the replica has replayed all the WAL it received, so that an idle
+// primary does not make the replica look stale, and when the server is not a
+// replica at all.
+const replicaLagQuery = `SELECT COALESCE(
+	CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())
+	END, 0)::float8;`
+
+// ReplicaDB is a DBI which sends the reads of the contexts allowing replica
+// reads (see store.ReplicaReadsContext) to a read replica, and everything else,
+// including the transactions, to the primary. The reads fall back to the
+// primary while the replication lag of the replica exceeds the maximum lag, or
+// when the replica cannot be queried.
+type ReplicaDB struct {
+	DBI
+
+	replica DBI
+	maxLag  time.Duration
+	fresh   int32
+}
+
+// NewReplicaDB returns a ReplicaDB of the primary and replica databases. The
+// replication lag of the replica is checked until the context is done, and
+// the replica serves no read before its lag has been checked.
+func NewReplicaDB(ctx context.Context, primary, replica DBI, maxLag time.Duration) *ReplicaDB {
+	if maxLag <= 0 {
+		maxLag = DefaultMaxReplicaLag
+	}
+	db := &ReplicaDB{
+		DBI:     primary,
+		replica: replica,
+		maxLag:  maxLag,
+	}
+	go db.observeLag(ctx)
+	return db
+}
+
+// Query runs a query on the replica if it can serve it, or on the primary.
+func (r *ReplicaDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if r.useReplica(ctx, sql) {
+		rows, err := r.replica.Query(ctx, sql, args...)
+		if err == nil || ctx.Err() != nil {
+			return rows, err
+		}
+		logger.WithError(err).Warn("could not query the read replica, falling back to the primary")
+		r.setFresh(false)
+	}
+	return r.DBI.Query(ctx, sql, args...)
+}
+
+// QueryRow runs a query on the replica if it can serve it, or on the primary.
+func (r *ReplicaDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if r.useReplica(ctx, sql) {
+		return r.replica.QueryRow(ctx, sql, args...)
+	}
+	return r.DBI.QueryRow(ctx, sql, args...)
+}
+
+// Fresh returns whether the replica is serving the reads.
+func (r *ReplicaDB) Fresh() bool {
+	return atomic.LoadInt32(&r.fresh) == 1
+}
+
+func (r *ReplicaDB) setFresh(fresh bool) {
+	var value int32
+	if fresh {
+		value = 1
+	}
+	if old := atomic.SwapInt32(&r.fresh, value); old != value {
+		if fresh {
+			logger.Info("the read replica is serving reads")
+		} else {
+			logger.Warn("the read replica is stale, reads are served by the primary")
+		}
+	}
+}
+
+func (r *ReplicaDB) useReplica(ctx context.Context, sql string) bool {
+	return store.IsReplicaReadsContext(ctx) && r.Fresh() && isReadQuery(sql)
+}
+
+// checkLag checks the replication lag of the replica, and updates whether it
+// can serve the reads.
+func (r *ReplicaDB) checkLag(ctx context.Context) {
+	var lag float64
+	if err := r.replica.QueryRow(ctx, replicaLagQuery).Scan(&lag); err != nil {
+		if ctx.Err() == nil {
+			logger.WithError(err).Error("could not check the replication lag of the read replica")
+			r.setFresh(false)
+		}
+		return
+	}
+	replicaLag.Set(lag)
+	r.setFresh(time.Duration(lag*float64(time.Second)) <= r.maxLag)
+}
+
+func (r *ReplicaDB) observeLag(ctx context.Context) {
+	ticker := time.NewTicker(replicaLagInterval)
+	defer ticker.Stop()
+	for {
+		r.checkLag(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// isReadQuery returns whether a query only reads, and so can be served by a
+// replica. Queries which write in a common table expression, lock rows or call
+// functions with side effects are not.
+func isReadQuery(sql string) bool {
+	name := queryName(sql)
+	if name != "select" && !strings.HasPrefix(name, "select_") {
+		return false
+	}
+	for _, field := range strings.FieldsFunc(strings.ToLower(sql), isNotWordChar) {
+		switch field {
+		case "insert", "update", "delete", "share", "pg_notify", "nextval", "setval":
+			return false
+		}
+		if strings.Contains(field, "advisory") {
+			return false
+		}
+	}
+	return true
+}
+
+func isNotWordChar(r rune) bool {
+	return !(r == '_' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+// fakeDB is a DBI counting the queries it runs. Its rows scan the lag.
+type fakeDB struct {
+	queries int
+	lag     float64
+	err     error
+}
+
+func (f *fakeDB) Begin(context.Context) (pgx.Tx, error) {
+	return nil, errors.New("unsupported")
+}
+
+func (f *fakeDB) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	f.queries++
+	return nil, f.err
+}
+
+func (f *fakeDB) QueryRow(context.Context, string, ...any) pgx.Row {
+	f.queries++
+	return fakeRow{lag: f.lag, err: f.err}
+}
+
+func (f *fakeDB) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	f.queries++
+	return pgconn.CommandTag{}, f.err
+}
+
+func (f *fakeDB) SendBatch(context.Context, *pgx.Batch) pgx.BatchResults {
+	return nil
+}
+
+type fakeRow struct {
+	lag float64
+	err error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	if lag, ok := dest[0].(*float64); ok {
+		*lag = r.lag
+	}
+	return nil
+}
+
+func TestReplicaDB(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	primary, replica := &fakeDB{}, &fakeDB{}
+	db := &ReplicaDB{DBI: primary, replica: replica, maxLag: time.Second}
+	replicaCtx := store.ReplicaReadsContext(ctx)
+	query := "SELECT * FROM configuration WHERE id = $1"
+
+	// The replica serves no read before its lag is checked
+	_, _ = db.Query(replicaCtx, query)
+	if primary.queries != 1 || replica.queries != 0 {
+		t.Fatalf("read served by the replica before its lag is checked")
+	}
+
+	db.checkLag(ctx)
+	replica.queries = 0
+	if !db.Fresh() {
+		t.Fatal("replica not fresh")
+	}
+	_, _ = db.Query(replicaCtx, query)
+	_ = db.QueryRow(replicaCtx, query)
+	if replica.queries != 2 {
+		t.Fatalf("reads not served by the replica: got %d queries", replica.queries)
+	}
+
+	// Only the reads of the contexts allowing them go to the replica
+	_, _ = db.Query(ctx, query)
+	_ = db.QueryRow(replicaCtx, "UPDATE configuration SET deleted_at = NULL")
+	_, _ = db.Exec(replicaCtx, "DELETE FROM configuration")
+	if primary.queries != 4 || replica.queries != 2 {
+		t.Fatalf("writes served by the replica: got %d primary and %d replica queries", primary.queries, replica.queries)
+	}
+
+	// Stale replica
+	replica.lag = 2
+	db.checkLag(ctx)
+	if db.Fresh() {
+		t.Fatal("replica fresh")
+	}
+	_, _ = db.Query(replicaCtx, query)
+	if primary.queries != 5 || replica.queries != 3 {
+		t.Fatalf("read served by the stale replica")
+	}
+
+	// Unavailable replica, the reads fall back to the primary
+	replica.lag = 0
+	db.checkLag(ctx)
+	replica.err = errors.New("unavailable")
+	if _, err := db.Query(replicaCtx, query); err != nil {
+		t.Fatal(err)
+	}
+	if primary.queries != 6 || db.Fresh() {
+		t.Fatalf("read not served by the primary")
+	}
+}
+
+func TestIsReadQuery(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want bool
+	}{
+		{sql: "SELECT * FROM events WHERE namespace = $1", want: true},
+		{sql: "SELECT NOT EXISTS (SELECT 1 FROM namespaces)", want: true},
+		{sql: "SELECT updated_at, last_update FROM configuration", want: true},
+		{sql: "INSERT INTO configuration (id) VALUES ($1)", want: false},
+		{sql: "WITH x AS (UPDATE entity_states SET y = 1 RETURNING id) SELECT * FROM x", want: false},
+		{sql: "SELECT id FROM queue FOR UPDATE SKIP LOCKED", want: false},
+		{sql: "SELECT pg_notify($1::text, '')", want: false},
+		{sql: "SELECT pg_try_advisory_lock($1)", want: false},
+		{sql: "LISTEN ring", want: false},
+	}
+	for _, test := range tests {
+		if got := isReadQuery(test.sql); got != test.want {
+			t.Errorf("isReadQuery(%q): got %v, want %v", test.sql, got, test.want)
+		}
+	}
+}
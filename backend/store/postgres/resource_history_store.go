@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.ResourceHistoryStore = &ResourceHistoryStore{}
+
+type ResourceHistoryStore struct {
+	db DBI
+}
+
+func NewResourceHistoryStore(db DBI) *ResourceHistoryStore {
+	return &ResourceHistoryStore{db: db}
+}
+
+// getLastResourceVersionQuery gets the number of the last version of a
+// resource, whether it is a deletion, and whether its resource is the same as
+// the given one.
+const getLastResourceVersionQuery = `
+SELECT
+	version,
+	deleted,
+	COALESCE(resource = $5::jsonb, false)
+FROM resource_history
+WHERE
+	api_version = $1
+	AND api_type = $2
+	AND namespace = $3
+	AND name = $4
+ORDER BY version DESC
+LIMIT 1;
+`
+
+const addResourceVersionQuery = `
+INSERT INTO resource_history ( api_version, api_type, namespace, name, version, author, timestamp, deleted, resource )
+VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9 );
+`
+
+const pruneResourceHistoryQuery = `
+DELETE FROM resource_history
+WHERE
+	api_version = $1
+	AND api_type = $2
+	AND namespace = $3
+	AND name = $4
+	AND version <= $5;
+`
+
+// AddResourceVersion records a version of a resource, unless it did not change
+// since the last version, and removes the versions of the resource that
+// exceed storev2.MaxResourceVersions.
+func (s *ResourceHistoryStore) AddResourceVersion(ctx context.Context, version *storev2.ResourceVersion) (fErr error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	defer func() {
+		if fErr == nil {
+			fErr = tx.Commit(ctx)
+			return
+		}
+		if txerr := tx.Rollback(ctx); txerr != nil && txerr != pgx.ErrTxClosed {
+			fErr = txerr
+		}
+	}()
+
+	var resource []byte
+	if !version.Deleted {
+		resource = version.Resource
+	}
+	var (
+		last        int64
+		lastDeleted bool
+		same        bool
+	)
+	row := tx.QueryRow(ctx, getLastResourceVersionQuery, version.APIVersion, version.Type, version.Namespace, version.Name, resource)
+	if err := row.Scan(&last, &lastDeleted, &same); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	if last > 0 && lastDeleted == version.Deleted && (version.Deleted || same) {
+		version.Version = last
+		return nil
+	}
+
+	version.Version = last + 1
+	_, err = tx.Exec(
+		ctx,
+		addResourceVersionQuery,
+		version.APIVersion, version.Type, version.Namespace, version.Name,
+		version.Version, version.Author, version.Timestamp, version.Deleted, resource,
+	)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	_, err = tx.Exec(
+		ctx,
+		pruneResourceHistoryQuery,
+		version.APIVersion, version.Type, version.Namespace, version.Name,
+		version.Version-storev2.MaxResourceVersions,
+	)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	return nil
+}
+
+const getResourceHistoryQuery = `
+SELECT
+	version,
+	author,
+	timestamp,
+	deleted,
+	resource
+FROM resource_history
+WHERE
+	api_version = $1
+	AND api_type = $2
+	AND namespace = $3
+	AND name = $4
+ORDER BY version DESC;
+`
+
+// GetResourceHistory gets the versions of a resource, most recent first.
+func (s *ResourceHistoryStore) GetResourceHistory(ctx context.Context, req storev2.ResourceRequest) ([]*storev2.ResourceVersion, error) {
+	rows, err := s.db.Query(ctx, getResourceHistoryQuery, req.APIVersion, req.Type, req.Namespace, req.Name)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	defer rows.Close()
+	versions := []*storev2.ResourceVersion{}
+	for rows.Next() {
+		version := storev2.ResourceVersion{
+			APIVersion: req.APIVersion,
+			Type:       req.Type,
+			Namespace:  req.Namespace,
+			Name:       req.Name,
+		}
+		var resource []byte
+		if err := rows.Scan(&version.Version, &version.Author, &version.Timestamp, &version.Deleted, &resource); err != nil {
+			return nil, &store.ErrInternal{Message: err.Error()}
+		}
+		version.Resource = resource
+		versions = append(versions, &version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	return versions, nil
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	corev2 "github.com/sensu/core/v2"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+func TestResourceHistoryStore(t *testing.T) {
+	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
+		s := NewResourceHistoryStore(db)
+		req := storev2.NewResourceRequestFromResource(corev2.FixtureCheckConfig("check"))
+		for i := 0; i < storev2.MaxResourceVersions+2; i++ {
+			resource := []byte(fmt.Sprintf(`{"interval": %d}`, i))
+			// The unchanged resources are only recorded once
+			for j := 0; j < 2; j++ {
+				version := storev2.NewResourceVersion(req, "admin", int64(i), resource)
+				if err := s.AddResourceVersion(ctx, version); err != nil {
+					t.Fatal(err)
+				}
+				if got, want := version.Version, int64(i+1); got != want {
+					t.Fatalf("bad version: got %d, want %d", got, want)
+				}
+			}
+		}
+		if err := s.AddResourceVersion(ctx, storev2.NewResourceVersion(req, "admin", 42, nil)); err != nil {
+			t.Fatal(err)
+		}
+
+		versions, err := s.GetResourceHistory(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(versions), storev2.MaxResourceVersions; got != want {
+			t.Fatalf("bad number of versions: got %d, want %d", got, want)
+		}
+		if !versions[0].Deleted || versions[0].Resource != nil {
+			t.Errorf("expected a deletion, got %v", versions[0])
+		}
+		if got, want := string(versions[1].Resource), fmt.Sprintf(`{"interval": %d}`, storev2.MaxResourceVersions+1); got != want {
+			t.Errorf("bad resource: got %s, want %s", got, want)
+		}
+	})
+}
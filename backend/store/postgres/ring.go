@@ -270,7 +270,7 @@ func (r *Ring) Remove(ctx context.Context, value string) error {
 }
 
 func (r *Ring) Add(ctx context.Context, value string, keepalive int64) (err error) {
-	r.logger.WithField("entity", value).WithField("keepalive", keepalive).Trace("ring.Add()")
+	r.logger.WithField("entity", value).WithField("keepalive", keepalive).WithField("weight", ringv2.WeightFromContext(ctx)).Trace("ring.Add()")
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return err
@@ -288,7 +288,8 @@ func (r *Ring) Add(ctx context.Context, value string, keepalive int64) (err erro
 	if _, err := tx.Exec(ctx, updateEntityStateExpiresAtQuery, r.namespace, value, dur.String()); err != nil {
 		return err
 	}
-	if _, err := tx.Exec(ctx, insertRingEntityQuery, r.namespace, value, r.path); err != nil {
+	weight := ringv2.WeightFromContext(ctx)
+	if _, err := tx.Exec(ctx, insertRingEntityQuery, r.namespace, value, r.path, weight); err != nil {
 		return err
 	}
 	return nil
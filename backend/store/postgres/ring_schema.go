@@ -6,6 +6,9 @@ const updateRingSubscribersQuery = `
 -- The next pointer will be greater than the current pointer, unless the next
 -- pointer would wrap around to be a lesser value.
 --
+-- Entities are visited once per unit of weight. The pointer is therefore made
+-- of an entity and a repetition of that entity, in [1, weight].
+--
 -- Parameters:
 -- $1 The name of the ring
 -- $2 The name of the subscriber
@@ -13,7 +16,7 @@ const updateRingSubscribersQuery = `
 --    the offset, so math cannot be done on it in the function.)
 -- $4 The time offset
 WITH current_pointer AS (
-	SELECT entity_states.name AS name
+	SELECT entity_states.name AS name, ring_subscribers.pointer_rep AS rep
 	FROM rings, entity_states, ring_entities, ring_subscribers
 	WHERE
 		rings.name = $1 AND
@@ -23,46 +26,45 @@ WITH current_pointer AS (
 		ring_subscribers.name = $2 AND
 		ring_subscribers.ring_id = rings.id
 ),
+slots AS (
+	-- Every entity of the ring, repeated once per unit of weight.
+	SELECT entity_states.id AS pointer, entity_states.name AS entity_name, rep
+	FROM rings, entity_states, ring_entities, ring_subscribers, generate_series(1, ring_entities.weight) AS rep
+	WHERE
+		rings.name = $1 AND
+		ring_entities.ring_id = rings.id AND
+		ring_entities.entity_id = entity_states.id AND
+		ring_subscribers.name = $2 AND
+		ring_subscribers.ring_id = rings.id AND
+		entity_states.expires_at > now()
+),
 next_all AS (
 	(
 		-- In most iterations, the pointer value will be higher than it was
 		-- previously.
-		SELECT entity_states.id AS pointer, entity_states.name AS entity_name
-		FROM rings, entity_states, ring_entities, ring_subscribers, current_pointer
+		SELECT slots.pointer AS pointer, slots.entity_name AS entity_name, slots.rep AS rep
+		FROM slots, current_pointer
 		WHERE
-			rings.name = $1 AND
-			ring_entities.ring_id = rings.id AND
-			ring_entities.entity_id = entity_states.id AND
-			ring_subscribers.name = $2 AND
-			ring_subscribers.ring_id = rings.id AND
-			entity_states.name > COALESCE(current_pointer.name, '') AND
-			entity_states.expires_at > now()
-		ORDER BY entity_states.name ASC
+			(slots.entity_name, slots.rep) > (COALESCE(current_pointer.name, ''), current_pointer.rep)
+		ORDER BY slots.entity_name ASC, slots.rep ASC
 	)
 	UNION ALL
 	(
 		-- If the pointer was at the end or NULL, it will get set to the first
 		-- entity in the selection (considering the offset).
-		SELECT ring_entities.entity_id AS pointer, entity_states.name AS entity_name
-		FROM rings, entity_states, ring_entities, ring_subscribers
-		WHERE
-			rings.name = $1 AND
-			ring_entities.ring_id = rings.id AND
-			ring_entities.entity_id = entity_states.id AND
-			ring_subscribers.name = $2 AND
-			ring_subscribers.ring_id = rings.id AND
-			entity_states.expires_at > now()
-		ORDER BY entity_states.name ASC
+		SELECT slots.pointer AS pointer, slots.entity_name AS entity_name, slots.rep AS rep
+		FROM slots
+		ORDER BY slots.entity_name ASC, slots.rep ASC
 	)
 ),
 next_numbered AS (
-	SELECT next_all.pointer AS pointer, next_all.entity_name AS entity_name, row_number () OVER () AS rnum
+	SELECT next_all.pointer AS pointer, next_all.entity_name AS entity_name, next_all.rep AS rep, row_number () OVER () AS rnum
 	FROM next_all
 ),
 next AS (
 	-- generate_series() is used to make sure that the offset does not go
 	-- beyond the bounds of the total number of entities, creating repetitions.
-	SELECT next_numbered.pointer AS pointer, next_numbered.entity_name AS entity_name, next_numbered.rnum as rnum
+	SELECT next_numbered.pointer AS pointer, next_numbered.entity_name AS entity_name, next_numbered.rep AS rep, next_numbered.rnum as rnum
 	FROM next_numbered, generate_series(1, $3 + 1)
 	ORDER BY generate_series, rnum
 	LIMIT 1
@@ -71,9 +73,10 @@ next AS (
 -- The actual update is quite simple. We just set last_updated to the current
 -- time, and we set pointer to be the next pointer, which is computed above.
 UPDATE ring_subscribers
-SET (last_updated, pointer) = (
+SET (last_updated, pointer, pointer_rep) = (
 	now(),
-	next.pointer
+	next.pointer,
+	next.rep
 )
 FROM rings, entity_states, ring_entities, next
 WHERE
@@ -96,6 +99,9 @@ const getRingEntitiesQuery = `
 -- The query isn't guaranteed to fill the limit; it's up to the caller to deal
 -- with that case.
 --
+-- Entities are repeated once per unit of weight, so an entity can be selected
+-- several times.
+--
 -- Parameters:
 -- $1: The name of the ring
 -- $2: The name of the subscriber
@@ -114,7 +120,7 @@ WITH namespace AS (
 	entity_states.expires_at < now()
 ),
 current_pointer AS (
-	SELECT entity_states.name AS name
+	SELECT entity_states.name AS name, ring_subscribers.pointer_rep AS rep
 	FROM rings, entity_states, ring_entities, ring_subscribers
 	WHERE rings.name = $1 AND
 	ring_entities.ring_id = rings.id AND
@@ -122,38 +128,37 @@ current_pointer AS (
 	entity_states.id = ring_entities.entity_id AND
 	ring_subscribers.name = $2 AND
 	ring_subscribers.ring_id = rings.id
-)
-(
-	-- This part of the query selects rows that have a name larger than or
-	-- equal to the current pointer's name.
-	SELECT entity_states.name AS name
-	FROM rings, ring_entities, entity_states, ring_subscribers, current_pointer
+),
+slots AS (
+	-- Every entity of the ring, repeated once per unit of weight.
+	SELECT entity_states.name AS name, rep
+	FROM rings, ring_entities, entity_states, ring_subscribers, generate_series(1, ring_entities.weight) AS rep
 	WHERE
 		rings.name = $1 AND
 		ring_entities.ring_id = rings.id AND
 		ring_entities.entity_id = entity_states.id AND
 		ring_subscribers.ring_id = rings.id AND
 		ring_subscribers.name = $2 AND
-		entity_states.name >= COALESCE(current_pointer.name, '') AND
 		entity_states.expires_at > now()
-	ORDER BY entity_states.name ASC
+)
+(
+	-- This part of the query selects rows that are larger than or equal to
+	-- the current pointer.
+	SELECT slots.name AS name
+	FROM slots, current_pointer
+	WHERE
+		(slots.name, slots.rep) >= (COALESCE(current_pointer.name, ''), current_pointer.rep)
+	ORDER BY slots.name ASC, slots.rep ASC
 	LIMIT $3
 )
 UNION ALL
 (
-	-- This part of the query selects rows that have a name smaller than
-	-- pointer.name. In many cases it will never be evaluated, as the limit
+	-- This part of the query selects rows that are smaller than the current
+	-- pointer. In many cases it will never be evaluated, as the limit
 	-- will have already been satisfied by the first half of the union.
-	SELECT entity_states.name AS name
-	FROM rings, ring_entities, entity_states, ring_subscribers
-	WHERE
-		rings.name = $1 AND
-		ring_entities.ring_id = rings.id AND
-		ring_entities.entity_id = entity_states.id AND
-		ring_subscribers.ring_id = rings.id AND
-		ring_subscribers.name = $2 AND
-		entity_states.expires_at > now()
-	ORDER BY entity_states.name ASC
+	SELECT slots.name AS name
+	FROM slots
+	ORDER BY slots.name ASC, slots.rep ASC
 	LIMIT $3
 )
 LIMIT $3;
@@ -184,26 +189,28 @@ WHERE namespace_id = (SELECT id FROM namespace) AND name = $2
 `
 
 const insertRingEntityQuery = `
--- This query creates an association between a ring and an entity.
+-- This query creates an association between a ring and an entity, or updates
+-- the weight of the association if it exists.
 --
 -- Parameters:
 -- $1: The entity namespace
 -- $2: The entity name
 -- $3: The ring name
+-- $4: The weight of the entity in the ring
 --
 WITH namespace AS (
 	SELECT id FROM namespaces
 	WHERE namespaces.name = $1
 )
-INSERT INTO ring_entities ( ring_id, entity_id )
-SELECT rings.id, entity_states.id
+INSERT INTO ring_entities ( ring_id, entity_id, weight )
+SELECT rings.id, entity_states.id, $4::integer
 FROM rings, entity_states
 WHERE
 	entity_states.namespace_id = (SELECT id FROM namespace) AND
 	entity_states.name = $2 AND
 	rings.name = $3
 LIMIT 1
-ON CONFLICT DO NOTHING
+ON CONFLICT ( ring_id, entity_id ) DO UPDATE SET weight = EXCLUDED.weight
 RETURNING TRUE;
 `
 
@@ -121,7 +121,7 @@ func TestInsertRingEntitiesQuery(t *testing.T) {
 		}
 		for _, entityName := range entityNames {
 			for _, ring := range rings {
-				row := db.QueryRow(ctx, insertRingEntityQuery, "default", entityName, ring)
+				row := db.QueryRow(ctx, insertRingEntityQuery, "default", entityName, ring, 1)
 				var inserted bool
 				if err := row.Scan(&inserted); err != nil {
 					t.Fatal(err)
@@ -133,7 +133,7 @@ func TestInsertRingEntitiesQuery(t *testing.T) {
 		}
 		// We should get errors if the ring doesn't exist
 		for _, entityName := range entityNames {
-			row := db.QueryRow(ctx, insertRingEntityQuery, "default", entityName, "does_not_exist")
+			row := db.QueryRow(ctx, insertRingEntityQuery, "default", entityName, "does_not_exist", 1)
 			var inserted bool
 			if err := row.Scan(&inserted); err != pgx.ErrNoRows && err.Error() != pgx.ErrNoRows.Error() {
 				t.Fatalf("expected pgx.ErrNoRows, got %q (%T)", err, err)
@@ -141,7 +141,7 @@ func TestInsertRingEntitiesQuery(t *testing.T) {
 		}
 		// We should get errors if the member doesn't exist
 		for _, ring := range rings {
-			row := db.QueryRow(ctx, insertRingEntityQuery, "default", "does_not_exist", ring)
+			row := db.QueryRow(ctx, insertRingEntityQuery, "default", "does_not_exist", ring, 1)
 			var inserted bool
 			if err := row.Scan(&inserted); err != pgx.ErrNoRows && err.Error() != pgx.ErrNoRows.Error() {
 				t.Fatalf("expected pgx.ErrNoRows, got %q (%T)", err, err)
@@ -186,7 +186,7 @@ func TestInsertRingSubscriberQuery(t *testing.T) {
 				if _, err := db.Exec(ctx, insertRingQuery, ring); err != nil {
 					t.Fatal(err)
 				}
-				row := db.QueryRow(ctx, insertRingEntityQuery, "default", entityName, ring)
+				row := db.QueryRow(ctx, insertRingEntityQuery, "default", entityName, ring, 1)
 				var inserted bool
 				if err := row.Scan(&inserted); err != nil {
 					t.Fatal(err)
@@ -245,7 +245,7 @@ func TestUpdateRingSubscriberQuery(t *testing.T) {
 				if _, err := db.Exec(ctx, insertRingQuery, ring); err != nil {
 					t.Fatal(err)
 				}
-				row := db.QueryRow(ctx, insertRingEntityQuery, entity.Namespace, entity.Name, ring)
+				row := db.QueryRow(ctx, insertRingEntityQuery, entity.Namespace, entity.Name, ring, 1)
 				var inserted bool
 				if err := row.Scan(&inserted); err != nil {
 					t.Fatal(err)
@@ -265,7 +265,7 @@ func TestUpdateRingSubscriberQuery(t *testing.T) {
 				if _, err := db.Exec(ctx, insertRingQuery, ring); err != nil {
 					t.Fatal(err)
 				}
-				_, err := db.Exec(ctx, insertRingEntityQuery, "default", entity, ring)
+				_, err := db.Exec(ctx, insertRingEntityQuery, "default", entity, ring, 1)
 				if err != nil {
 					t.Fatal(err)
 				}
@@ -391,7 +391,7 @@ func TestUpdateRingSubscriberQueryWithSubsequentEntityInsert(t *testing.T) {
 				t.Fatal(err)
 			}
 			for _, ring := range rings {
-				row := db.QueryRow(ctx, insertRingEntityQuery, entity.Namespace, entity.Name, ring)
+				row := db.QueryRow(ctx, insertRingEntityQuery, entity.Namespace, entity.Name, ring, 1)
 				var inserted bool
 				if err := row.Scan(&inserted); err != nil {
 					t.Fatal(err)
@@ -456,7 +456,7 @@ func TestGetRingEntitiesQuery(t *testing.T) {
 				if _, err := db.Exec(ctx, insertRingQuery, ring); err != nil {
 					t.Fatal(err)
 				}
-				row := db.QueryRow(ctx, insertRingEntityQuery, entity.Namespace, entity.Name, ring)
+				row := db.QueryRow(ctx, insertRingEntityQuery, entity.Namespace, entity.Name, ring, 1)
 				var inserted bool
 				if err := row.Scan(&inserted); err != nil {
 					t.Fatal(err)
@@ -584,7 +584,7 @@ func TestGetRingLengthQuery(t *testing.T) {
 				if _, err := db.Exec(ctx, insertRingQuery, ring); err != nil {
 					t.Fatal(err)
 				}
-				row := db.QueryRow(ctx, insertRingEntityQuery, entity.Namespace, entity.Name, ring)
+				row := db.QueryRow(ctx, insertRingEntityQuery, entity.Namespace, entity.Name, ring, 1)
 				var inserted bool
 				if err := row.Scan(&inserted); err != nil {
 					t.Fatal(err)
@@ -646,7 +646,7 @@ func TestDeleteRingEntityQuery(t *testing.T) {
 				if _, err := db.Exec(ctx, insertRingQuery, ring); err != nil {
 					t.Fatal(err)
 				}
-				row := db.QueryRow(ctx, insertRingEntityQuery, entity.Namespace, entity.Name, ring)
+				row := db.QueryRow(ctx, insertRingEntityQuery, entity.Namespace, entity.Name, ring, 1)
 				var inserted bool
 				if err := row.Scan(&inserted); err != nil {
 					t.Fatal(err)
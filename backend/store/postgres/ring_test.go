@@ -292,6 +292,74 @@ func TestRingOrdering(t *testing.T) {
 	})
 }
 
+func TestWeightedRingOrdering(t *testing.T) {
+	t.Parallel()
+	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
+		listener := pq.NewListener(dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+		t.Cleanup(func() {
+			_ = listener.UnlistenAll()
+			_ = listener.Close()
+		})
+		bus := NewBus(ctx, listener)
+		ring, err := NewRing(db, bus, ringName(t.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			_ = ring.Close()
+		})
+
+		namespaceStore := NewNamespaceStore(db)
+		entityStore := NewEntityStore(db)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		sub := ringv2.Subscription{
+			Name:             "test",
+			Items:            1,
+			IntervalSchedule: 1,
+		}
+
+		weights := map[string]int{
+			"mulder": 2,
+			"scully": 1,
+		}
+
+		for item, weight := range weights {
+			entity := corev2.FixtureEntity(item)
+			namespace := corev3.FixtureNamespace(entity.Namespace)
+			if err := namespaceStore.CreateOrUpdate(ctx, namespace); err != nil {
+				t.Fatal(err)
+			}
+			if err := entityStore.UpdateEntity(ctx, entity); err != nil {
+				t.Fatal(err)
+			}
+			if err := ring.Add(ringv2.WeightContext(ctx, weight), item, 600); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		wc := ring.Subscribe(ctx, sub)
+
+		want := []string{"mulder", "mulder", "scully", "mulder", "mulder", "scully"}
+		for i := range want {
+			got := <-wc
+			wantEvent := ringv2.Event{
+				Type:   ringv2.EventTrigger,
+				Values: []string{want[i]},
+			}
+			if !reflect.DeepEqual(got, wantEvent) {
+				t.Errorf("bad event (iteration %d): got %v, want %v", i, got, wantEvent)
+			}
+		}
+	})
+}
+
 func TestConcurrentRingOrdering(t *testing.T) {
 	t.Skip("Skipping")
 	t.Parallel()
@@ -53,6 +53,21 @@ func NewConfigSelectorSQLBuilder(selector *selector.Selector) *SelectorSQLBuilde
 	}
 }
 
+// NewEntityConfigSelectorSQLBuilder returns a builder of the conditions of
+// the label selectors of the entity configs, whose labels are stored in the
+// selectors column with the "labels." prefix.
+func NewEntityConfigSelectorSQLBuilder(selector *selector.Selector) *SelectorSQLBuilder {
+	return &SelectorSQLBuilder{
+		selectorColumn:      "entity_configs.selectors",
+		nestedSelectors:     false,
+		labelColumn:         "entity_configs.selectors",
+		labelPrefixes:       []string{"labels."},
+		includeLabelCaption: false,
+		validFieldKeys:      nil,
+		selector:            selector,
+	}
+}
+
 func (s *SelectorSQLBuilder) GetSelectorCond(ctr *argCounter) (string, []interface{}, error) {
 	vars := make([]interface{}, 0, 4)
 	conds := make([]string, 0, 4)
@@ -60,18 +75,29 @@ func (s *SelectorSQLBuilder) GetSelectorCond(ctr *argCounter) (string, []interfa
 	exclusions := map[string]string{}
 	for _, op := range s.selector.Operations {
 		switch op.Operator {
-		case selector.DoubleEqualSignOperator, selector.NotEqualOperator, selector.MatchesOperator:
+		case selector.DoubleEqualSignOperator, selector.NotEqualOperator, selector.MatchesOperator,
+			selector.LessThanOperator, selector.LessThanOrEqualOperator, selector.GreaterThanOperator, selector.GreaterThanOrEqualOperator:
 			if len(op.RValues) != 1 {
 				return "", nil, fmt.Errorf("invalid operator: %v", op)
 			}
 		}
 		switch op.Operator {
 		case selector.InOperator:
-			cond, vr := s.inOperatorMatch(ctr, op)
-			conds = append(conds, cond)
-			vars = append(vars, vr...)
+			if s.isLabelValueMatch(op) {
+				cond, vr := s.labelContainment(ctr, op)
+				conds = append(conds, cond)
+				vars = append(vars, vr...)
+			} else {
+				cond, vr := s.inOperatorMatch(ctr, op)
+				conds = append(conds, cond)
+				vars = append(vars, vr...)
+			}
 		case selector.DoubleEqualSignOperator:
-			if op.OperationType == selector.OperationTypeLabelSelector {
+			if s.isLabelValueMatch(op) {
+				cond, vr := s.labelContainment(ctr, op)
+				conds = append(conds, cond)
+				vars = append(vars, vr...)
+			} else if op.OperationType == selector.OperationTypeLabelSelector {
 				cond, vr := s.inOperatorMatch(ctr, op)
 				conds = append(conds, cond)
 				vars = append(vars, vr...)
@@ -79,11 +105,21 @@ func (s *SelectorSQLBuilder) GetSelectorCond(ctr *argCounter) (string, []interfa
 				inclusions[op.LValue] = op.RValues[0]
 			}
 		case selector.NotInOperator:
-			cond, vr := s.notInOperatorMatch(ctr, op)
-			conds = append(conds, cond)
-			vars = append(vars, vr...)
+			if s.isLabelValueMatch(op) {
+				cond, vr := s.labelContainment(ctr, op)
+				conds = append(conds, fmt.Sprintf("NOT %s", cond))
+				vars = append(vars, vr...)
+			} else {
+				cond, vr := s.notInOperatorMatch(ctr, op)
+				conds = append(conds, cond)
+				vars = append(vars, vr...)
+			}
 		case selector.NotEqualOperator:
-			if op.OperationType == selector.OperationTypeLabelSelector {
+			if s.isLabelValueMatch(op) {
+				cond, vr := s.labelContainment(ctr, op)
+				conds = append(conds, fmt.Sprintf("NOT %s", cond))
+				vars = append(vars, vr...)
+			} else if op.OperationType == selector.OperationTypeLabelSelector {
 				cond, vr := s.notInOperatorMatch(ctr, op)
 				conds = append(conds, cond)
 				vars = append(vars, vr...)
@@ -94,6 +130,10 @@ func (s *SelectorSQLBuilder) GetSelectorCond(ctr *argCounter) (string, []interfa
 			cond, vr := s.matchOperator(ctr, op)
 			conds = append(conds, cond)
 			vars = append(vars, vr...)
+		case selector.LessThanOperator, selector.LessThanOrEqualOperator, selector.GreaterThanOperator, selector.GreaterThanOrEqualOperator:
+			cond, vr := s.compareOperator(ctr, op)
+			conds = append(conds, cond)
+			vars = append(vars, vr...)
 		default:
 			return "", nil, fmt.Errorf("unsupported operator: %s", op.Operator)
 		}
@@ -157,6 +197,36 @@ func (s *SelectorSQLBuilder) inOperatorMatch(ctr *argCounter, op selector.Operat
 	}
 }
 
+// isLabelValueMatch returns whether an operation of a label selector compares
+// the value of a label to literal values, in which case it is matched with
+// jsonb containment, which the GIN indexes of the label columns support.
+func (s *SelectorSQLBuilder) isLabelValueMatch(op selector.Operation) bool {
+	if op.OperationType != selector.OperationTypeLabelSelector || len(op.RValues) == 0 {
+		return false
+	}
+	return !s.validFieldKey(&op) && !strings.HasPrefix(op.RValues[0], "labels.")
+}
+
+// labelContainment matches the value of a label against the values of an
+// operation, like the in-memory selectors: the label must be one of the
+// values exactly.
+func (s *SelectorSQLBuilder) labelContainment(ctr *argCounter, op selector.Operation) (string, []interface{}) {
+	key := op.LValue
+	if !strings.HasPrefix(key, "labels.") && s.includeLabelCaption {
+		key = fmt.Sprintf("labels.%s", key)
+	}
+	fragments := make([]string, 0, len(s.labelPrefixes)*len(op.RValues))
+	vars := make([]interface{}, 0, len(s.labelPrefixes)*len(op.RValues))
+	for _, prefix := range s.labelPrefixes {
+		for _, value := range op.RValues {
+			b, _ := json.Marshal(map[string]string{prefix + key: value})
+			fragments = append(fragments, fmt.Sprintf("%s @> $%d", s.labelColumn, ctr.Next()))
+			vars = append(vars, b)
+		}
+	}
+	return fmt.Sprintf("(%s)", strings.Join(fragments, " OR ")), vars
+}
+
 func (s *SelectorSQLBuilder) notInOperatorMatch(ctr *argCounter, op selector.Operation) (string, []interface{}) {
 	query, values := s.inOperatorMatch(ctr, op)
 	query = fmt.Sprintf("NOT (%s)", query)
@@ -203,6 +273,33 @@ func (s *SelectorSQLBuilder) matchOperator(ctr *argCounter, op selector.Operatio
 	return query, []interface{}{op.LValue, op.RValues[0]}
 }
 
+// numericComparison compares the numeric value of a text expression to a
+// parameter. Values that are not numbers compare to NULL, so that they do not
+// match, like they do not match the in-memory selectors, instead of failing
+// the cast. The numbers are those of the selector number grammar.
+var numericComparison = `CASE WHEN %[1]s ~` + pq.QuoteLiteral(selector.NumberPattern) + ` THEN (%[1]s)::NUMERIC END %[2]s $%[3]d::TEXT::NUMERIC`
+
+func (s *SelectorSQLBuilder) compareOperator(ctr *argCounter, op selector.Operation) (string, []interface{}) {
+	if op.OperationType != selector.OperationTypeLabelSelector {
+		value := fmt.Sprintf("%s#>>$%d", s.selectorColumn, ctr.Next())
+		query := fmt.Sprintf(numericComparison, value, op.Operator, ctr.Next())
+		return query, []interface{}{s.matchLValue(op.LValue), op.RValues[0]}
+	}
+	lvalue := op.LValue
+	if !strings.HasPrefix(lvalue, "labels.") && s.includeLabelCaption {
+		lvalue = fmt.Sprintf("labels.%s", lvalue)
+	}
+	keyArg := ctr.Next()
+	matchArg := ctr.Next()
+	fragments := make([]string, 0, len(s.labelPrefixes))
+	for _, prefix := range s.labelPrefixes {
+		value := fmt.Sprintf("%s->>(%s || $%d)", s.labelColumn, pq.QuoteLiteral(prefix), keyArg)
+		fragments = append(fragments, fmt.Sprintf("("+numericComparison+")", value, op.Operator, matchArg))
+	}
+	query := strings.Join(fragments, " OR ")
+	return query, []interface{}{lvalue, op.RValues[0]}
+}
+
 func (s *SelectorSQLBuilder) formatSelectorConds(ctr *argCounter, inclusions, exclusions map[string]string) ([]string, []interface{}) {
 	conds := make([]string, 0, 2)
 	vars := make([]interface{}, 0, 2)
@@ -26,6 +26,10 @@ func TestGetSelectorCond(t *testing.T) {
 			input:         "foo == bar && zip != zap && bim == bap",
 			expectedQuery: "testSelectorCol @> $1 AND NOT testSelectorCol @> $2",
 		},
+		{
+			input:         "event.timestamp > 1700000000",
+			expectedQuery: "CASE WHEN testSelectorCol#>>$1 ~ E'^[+-]?([0-9]+(\\\\.[0-9]+)?|\\\\.[0-9]+)([eE][+-]?[0-9]{1,3})?$' THEN (testSelectorCol#>>$1)::NUMERIC END > $2::TEXT::NUMERIC",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -47,3 +51,59 @@ func TestGetSelectorCond(t *testing.T) {
 	}
 
 }
+
+func TestGetSelectorCondLabels(t *testing.T) {
+	builder := NewEntityConfigSelectorSQLBuilder(nil)
+
+	testCases := []struct {
+		input         string
+		expectedQuery string
+		expectedVars  []string
+	}{
+		{
+			input:         "region == uswest",
+			expectedQuery: "(entity_configs.selectors @> $1)",
+			expectedVars:  []string{`{"labels.region":"uswest"}`},
+		},
+		{
+			input:         "region != uswest",
+			expectedQuery: "NOT (entity_configs.selectors @> $1)",
+			expectedVars:  []string{`{"labels.region":"uswest"}`},
+		},
+		{
+			input:         "region in [uswest, useast]",
+			expectedQuery: "(entity_configs.selectors @> $1 OR entity_configs.selectors @> $2)",
+			expectedVars:  []string{`{"labels.region":"uswest"}`, `{"labels.region":"useast"}`},
+		},
+		{
+			input:         "region notin [uswest] && tier == db",
+			expectedQuery: "NOT (entity_configs.selectors @> $1) AND (entity_configs.selectors @> $2)",
+			expectedVars:  []string{`{"labels.region":"uswest"}`, `{"labels.tier":"db"}`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			selector, err := selector.ParseLabelSelector(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			builder.selector = selector
+			actualQuery, vars, err := builder.GetSelectorCond(&argCounter{0})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actualQuery != tc.expectedQuery {
+				t.Errorf("expected %s, got %s", tc.expectedQuery, actualQuery)
+			}
+			if len(vars) != len(tc.expectedVars) {
+				t.Fatalf("expected %d vars, got %d", len(tc.expectedVars), len(vars))
+			}
+			for i, v := range vars {
+				if got := string(v.([]byte)); got != tc.expectedVars[i] {
+					t.Errorf("expected var %s, got %s", tc.expectedVars[i], got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.SessionStore = &SessionStore{}
+
+type SessionStore struct {
+	db DBI
+}
+
+func NewSessionStore(db DBI) *SessionStore {
+	return &SessionStore{db: db}
+}
+
+const addSessionQuery = `
+INSERT INTO sessions ( id, username, provider, issued_at, refreshed_at, expires_at )
+VALUES ( $1, $2, $3, $4, $5, $6 );
+`
+
+const pruneSessionsQuery = `DELETE FROM sessions WHERE expires_at <= $1;`
+
+const pruneRevokedTokensQuery = `DELETE FROM revoked_tokens WHERE expires_at <= $1;`
+
+// AddSession records a new session, and removes the sessions and the revoked
+// tokens expired at the time the session was issued.
+func (s *SessionStore) AddSession(ctx context.Context, session *storev2.Session) error {
+	_, err := s.db.Exec(
+		ctx,
+		addSessionQuery,
+		session.ID,
+		session.Username,
+		session.Provider,
+		session.IssuedAt,
+		session.RefreshedAt,
+		session.ExpiresAt,
+	)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	if _, err := s.db.Exec(ctx, pruneSessionsQuery, session.IssuedAt); err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	if _, err := s.db.Exec(ctx, pruneRevokedTokensQuery, session.IssuedAt); err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	return nil
+}
+
+const refreshSessionQuery = `
+UPDATE sessions SET refreshed_at = $2, expires_at = $3
+WHERE id = $1 AND expires_at > $2;
+`
+
+// RefreshSession records the renewal of the refresh token of a session. The
+// update locks the row of the session, so that a concurrent revocation
+// either happens first, or revokes the renewed token.
+func (s *SessionStore) RefreshSession(ctx context.Context, id string, refreshedAt, expiresAt int64) error {
+	tag, err := s.db.Exec(ctx, refreshSessionQuery, id, refreshedAt, expiresAt)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	if tag.RowsAffected() == 0 {
+		return &store.ErrNotFound{Key: id}
+	}
+	return nil
+}
+
+const getSessionsQuery = `
+SELECT id, username, provider, issued_at, refreshed_at, expires_at
+FROM sessions
+WHERE username = $1 AND expires_at > $2
+ORDER BY issued_at DESC, id;
+`
+
+// GetSessions gets the unexpired sessions of a user, most recent first.
+func (s *SessionStore) GetSessions(ctx context.Context, username string, now int64) ([]*storev2.Session, error) {
+	rows, err := s.db.Query(ctx, getSessionsQuery, username, now)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	defer rows.Close()
+	sessions := []*storev2.Session{}
+	for rows.Next() {
+		var session storev2.Session
+		err := rows.Scan(
+			&session.ID,
+			&session.Username,
+			&session.Provider,
+			&session.IssuedAt,
+			&session.RefreshedAt,
+			&session.ExpiresAt,
+		)
+		if err != nil {
+			return nil, &store.ErrInternal{Message: err.Error()}
+		}
+		sessions = append(sessions, &session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	return sessions, nil
+}
+
+// revokeSessionsQuery removes the sessions of a user, all of them when $2 is
+// empty, and adds their tokens to the revoked tokens.
+const revokeSessionsQuery = `
+WITH revoked AS (
+	DELETE FROM sessions
+	WHERE username = $1 AND ( cardinality($2::text[]) = 0 OR id = ANY($2) )
+	RETURNING id, expires_at
+)
+INSERT INTO revoked_tokens ( id, expires_at )
+SELECT id, expires_at FROM revoked
+ON CONFLICT ( id ) DO UPDATE SET expires_at = GREATEST(revoked_tokens.expires_at, excluded.expires_at);
+`
+
+// RevokeSessions removes the given sessions of a user, or all of them, and
+// revokes their refresh tokens.
+func (s *SessionStore) RevokeSessions(ctx context.Context, username string, ids ...string) (int, error) {
+	if ids == nil {
+		ids = []string{}
+	}
+	tag, err := s.db.Exec(ctx, revokeSessionsQuery, username, ids)
+	if err != nil {
+		return 0, &store.ErrInternal{Message: err.Error()}
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+const isTokenRevokedQuery = `SELECT EXISTS ( SELECT 1 FROM revoked_tokens WHERE id = $1 );`
+
+// IsTokenRevoked returns whether a refresh token was revoked.
+func (s *SessionStore) IsTokenRevoked(ctx context.Context, id string) (bool, error) {
+	var revoked bool
+	if err := s.db.QueryRow(ctx, isTokenRevokedQuery, id).Scan(&revoked); err != nil {
+		return false, &store.ErrInternal{Message: err.Error()}
+	}
+	return revoked, nil
+}
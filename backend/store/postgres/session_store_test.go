@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+func TestSessionStore(t *testing.T) {
+	withPostgres(t, func(ctx context.Context, db *pgxpool.Pool, dsn string) {
+		s := NewSessionStore(db)
+		for i, id := range []string{"a", "b", "c"} {
+			session := &storev2.Session{ID: id, Username: "jane", Provider: "basic", IssuedAt: int64(i), RefreshedAt: int64(i), ExpiresAt: 100}
+			if err := s.AddSession(ctx, session); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := s.RefreshSession(ctx, "a", 50, 200); err != nil {
+			t.Fatal(err)
+		}
+		sessions, err := s.GetSessions(ctx, "jane", 150)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sessions) != 1 || sessions[0].ID != "a" {
+			t.Fatalf("bad sessions: %v", sessions)
+		}
+
+		// The sessions of the other users are left untouched
+		if n, err := s.RevokeSessions(ctx, "john", "a"); err != nil || n != 0 {
+			t.Fatalf("bad revocation: %d, %v", n, err)
+		}
+		if n, err := s.RevokeSessions(ctx, "jane", "a"); err != nil || n != 1 {
+			t.Fatalf("bad revocation: %d, %v", n, err)
+		}
+		if revoked, err := s.IsTokenRevoked(ctx, "a"); err != nil || !revoked {
+			t.Fatalf("token not revoked: %v", err)
+		}
+		if err := s.RefreshSession(ctx, "a", 60, 300); err == nil {
+			t.Fatal("expected the revoked session not to be refreshed")
+		}
+		if n, err := s.RevokeSessions(ctx, "jane"); err != nil || n != 2 {
+			t.Fatalf("bad revocation: %d, %v", n, err)
+		}
+
+		// The revoked tokens are removed once expired
+		if err := s.AddSession(ctx, &storev2.Session{ID: "d", Username: "jane", IssuedAt: 200, RefreshedAt: 200, ExpiresAt: 300}); err != nil {
+			t.Fatal(err)
+		}
+		for id, want := range map[string]bool{"a": false, "b": false, "d": false} {
+			if revoked, err := s.IsTokenRevoked(ctx, id); err != nil || revoked != want {
+				t.Errorf("bad revocation of %s: got %v, want %v (%v)", id, revoked, want, err)
+			}
+		}
+	})
+}
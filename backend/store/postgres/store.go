@@ -33,6 +33,10 @@ type StoreConfig struct {
 	WatchTxnWindow    time.Duration
 	Bus               messaging.MessageBus
 	DisableEventCache bool
+
+	// Notifications, when set, wakes up the watchers of the resources as
+	// soon as they change, instead of at their next watch interval.
+	Notifications *Bus
 }
 
 func NewStore(cfg StoreConfig) *Store {
@@ -43,6 +47,7 @@ func NewStore(cfg StoreConfig) *Store {
 		maxTPS:            cfg.MaxTPS,
 		bus:               cfg.Bus,
 		disableEventCache: cfg.DisableEventCache,
+		notifications:     cfg.Notifications,
 	}
 }
 
@@ -55,6 +60,7 @@ type Store struct {
 	once              sync.Once
 	bus               messaging.MessageBus
 	disableEventCache bool
+	notifications     *Bus
 }
 
 func (s *Store) GetConfigStore() storev2.ConfigStore {
@@ -62,6 +68,7 @@ func (s *Store) GetConfigStore() storev2.ConfigStore {
 		db:             s.db,
 		watchTxnWindow: s.watchTxnWindow,
 		watchInterval:  s.watchInterval,
+		notifications:  s.notifications,
 	}
 }
 
@@ -117,6 +124,34 @@ func (s *Store) GetSilencesStore() storev2.SilencesStore {
 	return &SilenceStore{db: s.db}
 }
 
+func (s *Store) GetHandlerResultStore() storev2.HandlerResultStore {
+	return NewHandlerResultStore(s.db)
+}
+
+func (s *Store) GetEntityStateHistoryStore() storev2.EntityStateHistoryStore {
+	return NewEntityStateHistoryStore(s.db)
+}
+
+func (s *Store) GetResourceHistoryStore() storev2.ResourceHistoryStore {
+	return NewResourceHistoryStore(s.db)
+}
+
+func (s *Store) GetRateLimitStore() storev2.RateLimitStore {
+	return NewRateLimitStore(s.db)
+}
+
+func (s *Store) GetAuditStore() storev2.AuditStore {
+	return NewAuditStore(s.db)
+}
+
+func (s *Store) GetSessionStore() storev2.SessionStore {
+	return NewSessionStore(s.db)
+}
+
+func (s *Store) GetMFAStore() storev2.MFAStore {
+	return NewMFAStore(s.db)
+}
+
 const pgUniqueViolationCode = "23505"
 
 type DBI interface {
@@ -131,6 +166,7 @@ type ConfigStore struct {
 	db             DBI
 	watchInterval  time.Duration
 	watchTxnWindow time.Duration
+	notifications  *Bus
 }
 
 type configRecord struct {
@@ -151,6 +187,7 @@ type configRecord struct {
 type listTemplateValues struct {
 	Limit       int64
 	Offset      int64
+	Direction   string
 	Namespaced  bool
 	SelectorSQL string
 }
@@ -193,6 +230,43 @@ func (s *ConfigStore) Watch(ctx context.Context, req storev2.ResourceRequest) <-
 	return NewWatcher(s, s.watchInterval, s.watchTxnWindow).Watch(ctx, req)
 }
 
+// configurationNotifyChannel is the channel of the notifications sent by
+// postgres when the configuration table changes. See Migration 33.
+const configurationNotifyChannel = "configuration"
+
+// Notifications returns a channel which receives a value, without blocking,
+// when the resources of the type of the request change. It returns a nil
+// channel if the store has no notification bus, in which case the resources
+// can only be polled.
+func (s *ConfigStore) Notifications(ctx context.Context, req storev2.ResourceRequest) (<-chan struct{}, error) {
+	if s.notifications == nil {
+		return nil, nil
+	}
+	notes, err := s.notifications.Subscribe(ctx, "", configurationNotifyChannel)
+	if err != nil {
+		return nil, err
+	}
+	payload := req.APIVersion + "/" + req.Type
+	notify := make(chan struct{}, 1)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case note := <-notes:
+				if note == nil || note.Extra != payload {
+					continue
+				}
+				select {
+				case notify <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return notify, nil
+}
+
 func (s *ConfigStore) CreateOrUpdate(ctx context.Context, request storev2.ResourceRequest, wrapper storev2.Wrapper) error {
 	if err := request.Validate(); err != nil {
 		return &store.ErrNotValid{Err: err}
@@ -244,6 +318,90 @@ func (s *ConfigStore) CreateOrUpdate(ctx context.Context, request storev2.Resour
 	return nil
 }
 
+// BatchCreateOrUpdate creates or updates the wrapped resources with a single
+// multi-row statement. A resource given several times is written once, with
+// its last value, since a statement cannot update the same row twice.
+func (s *ConfigStore) BatchCreateOrUpdate(ctx context.Context, requests []storev2.ResourceRequest, wrappers []storev2.Wrapper) error {
+	if len(requests) != len(wrappers) {
+		return &store.ErrNotValid{Err: errors.New("the number of requests and resources differ")}
+	}
+	if storev2.IfMatchFromContext(ctx) != nil || storev2.IfNoneMatchFromContext(ctx) != nil {
+		return &store.ErrNotValid{Err: errors.New("can't use IfMatch or IfNoneMatch with this method")}
+	}
+
+	var (
+		apiVersions, apiTypes, namespaces, names []string
+		labels, fields, resources                []string
+		annotations                              [][]byte
+	)
+	indexes := make(map[string]int, len(requests))
+	for i, request := range requests {
+		if err := request.Validate(); err != nil {
+			return &store.ErrNotValid{Err: err}
+		}
+		data, err := extractResourceData(wrappers[i])
+		if err != nil {
+			return err
+		}
+		meta, typeMeta := data.Metadata, data.TypeMeta
+		key := fmt.Sprintf("%s.%s/%s/%s", typeMeta.APIVersion, typeMeta.Type, meta.Namespace, meta.Name)
+		j, ok := indexes[key]
+		if !ok {
+			j = len(names)
+			indexes[key] = j
+			apiVersions = append(apiVersions, typeMeta.APIVersion)
+			apiTypes = append(apiTypes, typeMeta.Type)
+			namespaces = append(namespaces, meta.Namespace)
+			names = append(names, meta.Name)
+			labels = append(labels, "")
+			annotations = append(annotations, nil)
+			fields = append(fields, "")
+			resources = append(resources, "")
+		}
+		labels[j] = data.Labels
+		annotations[j] = data.Annotations
+		fields[j] = string(data.Fields)
+		resources[j] = string(data.Resource)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	rows, err := s.db.Query(ctx, BatchCreateOrUpdateConfigQuery, apiVersions, apiTypes, namespaces, names, labels, annotations, fields, resources)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	defer rows.Close()
+	txInfo := storev2.TxInfoFromContext(ctx)
+	for rows.Next() {
+		var (
+			inserted       bool
+			prevEtag, etag storev2.ETag
+		)
+		if err := rows.Scan(&inserted, &prevEtag, &etag); err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		if txInfo != nil {
+			var record storev2.TxRecordInfo
+			if inserted {
+				record.Created = true
+			} else {
+				// it's only updated if the etag changed
+				if !etag.Equals(prevEtag) {
+					record.Updated = true
+				}
+				record.PrevETag = prevEtag
+			}
+			record.ETag = etag
+			txInfo.Records = append(txInfo.Records, record)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	return nil
+}
+
 func (s *ConfigStore) createOrUpdateIfNoneMatch(ctx context.Context, args []interface{}, ifNoneMatch storev2.IfNoneMatch) error {
 	var (
 		inserted       bool
@@ -513,6 +671,17 @@ func (s *ConfigStore) List(ctx context.Context, request storev2.ResourceRequest,
 		return nil, &store.ErrNotValid{Err: err}
 	}
 
+	if pred == nil {
+		pred = &store.SelectionPredicate{}
+	}
+	if pred.Ordering != "" && pred.Ordering != corev2.EntitySortName {
+		return nil, &store.ErrNotValid{Err: fmt.Errorf("unknown ordering requested: %s", pred.Ordering)}
+	}
+	direction := "ASC"
+	if pred.Descending {
+		direction = "DESC"
+	}
+
 	limit, offset, err := getLimitAndOffset(pred)
 	if err != nil {
 		return nil, err
@@ -523,12 +692,10 @@ func (s *ConfigStore) List(ctx context.Context, request storev2.ResourceRequest,
 		return nil, err
 	}
 	var queryBuilder strings.Builder
-	if pred == nil {
-		pred = &store.SelectionPredicate{}
-	}
 
 	templValues := listTemplateValues{
 		Offset:      offset,
+		Direction:   direction,
 		SelectorSQL: strings.TrimSpace(selectorSQL),
 		Namespaced:  request.Namespace != "",
 	}
@@ -616,6 +783,47 @@ func (s *ConfigStore) Exists(ctx context.Context, request storev2.ResourceReques
 	return count > 0, nil
 }
 
+// BatchExists returns whether each of the resources indicated by the requests
+// exists, with a single query.
+func (s *ConfigStore) BatchExists(ctx context.Context, requests []storev2.ResourceRequest) ([]bool, error) {
+	if storev2.IfMatchFromContext(ctx) != nil || storev2.IfNoneMatchFromContext(ctx) != nil {
+		return nil, &store.ErrNotValid{Err: errors.New("can't use IfMatch or IfNoneMatch with this method")}
+	}
+	apiVersions := make([]string, len(requests))
+	apiTypes := make([]string, len(requests))
+	namespaces := make([]string, len(requests))
+	names := make([]string, len(requests))
+	for i, request := range requests {
+		if err := request.Validate(); err != nil {
+			return nil, &store.ErrNotValid{Err: err}
+		}
+		apiVersions[i], apiTypes[i] = request.APIVersion, request.Type
+		namespaces[i], names[i] = request.Namespace, request.Name
+	}
+	result := make([]bool, len(requests))
+	if len(requests) == 0 {
+		return result, nil
+	}
+
+	rows, err := s.db.Query(ctx, BatchExistsConfigQuery, apiVersions, apiTypes, namespaces, names)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var ord int64
+		if err := rows.Scan(&ord); err != nil {
+			return nil, &store.ErrInternal{Message: err.Error()}
+		}
+		// The ordinality starts at 1
+		result[ord-1] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	return result, nil
+}
+
 func (s *ConfigStore) Patch(ctx context.Context, request storev2.ResourceRequest, patcher patch.Patcher) error {
 	if err := request.Validate(); err != nil {
 		return &store.ErrNotValid{Err: err}
@@ -661,7 +869,7 @@ func (s *ConfigStore) Patch(ctx context.Context, request storev2.ResourceRequest
 		return &store.ErrNotValid{Err: err}
 	}
 
-	if err := res.Validate(); err != nil {
+	if err := wrap.Validate(res); err != nil {
 		return &store.ErrNotValid{Err: err}
 	}
 
@@ -15,6 +15,14 @@ type WatchableStore interface {
 	GetPoller(request storev2.ResourceRequest) (poll.Table, error)
 }
 
+// NotifyingStore is a WatchableStore which is notified when its resources
+// change, so that their watchers do not wait for the next poll to see the
+// changes. The resources are still polled in case notifications are lost.
+type NotifyingStore interface {
+	WatchableStore
+	Notifications(ctx context.Context, request storev2.ResourceRequest) (<-chan struct{}, error)
+}
+
 type Watcher struct {
 	store          WatchableStore
 	watchInterval  time.Duration
@@ -49,6 +57,13 @@ func (w *Watcher) Watch(ctx context.Context, req storev2.ResourceRequest) <-chan
 		TxnWindow: txnWindow,
 		Table:     table,
 	}
+	if store, ok := w.store.(NotifyingStore); ok {
+		notify, err := store.Notifications(ctx, req)
+		if err != nil {
+			logger.WithError(err).Warnf("watcher not notified of changes, polling every %s", interval)
+		}
+		poller.Notify = notify
+	}
 
 	backoff := retry.ExponentialBackoff{
 		Ctx: ctx,
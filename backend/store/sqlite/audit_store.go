@@ -0,0 +1,42 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.AuditStore = &AuditStore{}
+
+type AuditStore struct {
+	db DBI
+}
+
+func NewAuditStore(db DBI) *AuditStore {
+	return &AuditStore{db: db}
+}
+
+// AddAuditEntry records an audit entry.
+func (s *AuditStore) AddAuditEntry(ctx context.Context, entry *storev2.AuditEntry) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		addAuditEntryQuery,
+		entry.Timestamp.UnixNano(),
+		entry.RequestID,
+		entry.User,
+		entry.Verb,
+		entry.APIGroup,
+		entry.APIVersion,
+		entry.Namespace,
+		entry.Resource,
+		entry.ResourceName,
+		entry.Status,
+		entry.Outcome,
+		entry.Latency,
+	)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	return nil
+}
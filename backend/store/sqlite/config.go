@@ -0,0 +1,7 @@
+package sqlite
+
+type Config struct {
+	// Path is the path to the sqlite database file. The special value
+	// ":memory:" creates a transient, in-memory database.
+	Path string
+}
@@ -0,0 +1,101 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/store/patch"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.EntityConfigStore = &EntityConfigStore{}
+
+type EntityConfigStore struct {
+	db        DBI
+	resources resourceStore[*corev3.EntityConfig, corev3.EntityConfig]
+}
+
+func NewEntityConfigStore(db DBI) *EntityConfigStore {
+	return &EntityConfigStore{
+		db:        db,
+		resources: newResourceStore[*corev3.EntityConfig](db),
+	}
+}
+
+func (s *EntityConfigStore) CreateOrUpdate(ctx context.Context, config *corev3.EntityConfig) error {
+	return s.resources.CreateOrUpdate(ctx, config)
+}
+
+func (s *EntityConfigStore) UpdateIfExists(ctx context.Context, config *corev3.EntityConfig) error {
+	return s.resources.UpdateIfExists(ctx, config)
+}
+
+func (s *EntityConfigStore) CreateIfNotExists(ctx context.Context, config *corev3.EntityConfig) error {
+	return s.resources.CreateIfNotExists(ctx, config)
+}
+
+func (s *EntityConfigStore) Get(ctx context.Context, namespace, name string) (*corev3.EntityConfig, error) {
+	if namespace == "" || name == "" {
+		return nil, &store.ErrNotValid{Err: errors.New("must specify namespace and name")}
+	}
+	return s.resources.get(ctx, namespace, name)
+}
+
+// Delete deletes an entity config, and the handler results of the entity.
+func (s *EntityConfigStore) Delete(ctx context.Context, namespace, name string) error {
+	if namespace == "" || name == "" {
+		return &store.ErrNotValid{Err: errors.New("must specify namespace and name")}
+	}
+	return withTx(ctx, s.db, func(tx DBI) error {
+		if err := NewEntityConfigStore(tx).resources.delete(ctx, namespace, name); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, deleteEntityHandlerResultsQuery, namespace, name); err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		return nil
+	})
+}
+
+func (s *EntityConfigStore) List(ctx context.Context, namespace string, pred *store.SelectionPredicate) ([]*corev3.EntityConfig, error) {
+	return s.resources.list(ctx, namespace, pred)
+}
+
+// Count returns the number of entity configs in the namespace, optionally
+// restricted to a single entity class.
+func (s *EntityConfigStore) Count(ctx context.Context, namespace, entityClass string) (int, error) {
+	if entityClass == "" {
+		return s.resources.count(ctx, namespace)
+	}
+	configs, err := s.resources.list(ctx, namespace, nil)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, config := range configs {
+		if config.EntityClass == entityClass {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *EntityConfigStore) Exists(ctx context.Context, namespace, name string) (bool, error) {
+	if namespace == "" || name == "" {
+		return false, &store.ErrNotValid{Err: errors.New("must specify namespace and name")}
+	}
+	return s.resources.exists(ctx, namespace, name)
+}
+
+func (s *EntityConfigStore) Patch(ctx context.Context, namespace, name string, patcher patch.Patcher) error {
+	if namespace == "" || name == "" {
+		return &store.ErrNotValid{Err: errors.New("must specify namespace and name")}
+	}
+	return s.resources.patch(ctx, namespace, name, patcher)
+}
+
+func (s *EntityConfigStore) Watch(ctx context.Context, namespace, name string) <-chan []storev2.WatchEvent {
+	return s.resources.watch(ctx, namespace, name)
+}
@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"path"
+
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.EntityStateHistoryStore = &EntityStateHistoryStore{}
+
+type EntityStateHistoryStore struct {
+	db DBI
+}
+
+func NewEntityStateHistoryStore(db DBI) *EntityStateHistoryStore {
+	return &EntityStateHistoryStore{db: db}
+}
+
+// AddEntityStateSnapshot records a snapshot of the system of an entity, unless
+// it did not change since the last snapshot, and removes the snapshots of the
+// entity that exceed storev2.MaxEntityStateSnapshots. The state of the entity
+// must exist.
+func (s *EntityStateHistoryStore) AddEntityStateSnapshot(ctx context.Context, snapshot *storev2.EntityStateSnapshot) error {
+	key := path.Join(snapshot.Namespace, snapshot.Entity)
+	system, err := json.Marshal(&snapshot.System)
+	if err != nil {
+		return &store.ErrEncode{Key: key, Err: err}
+	}
+	return withTx(ctx, s.db, func(tx DBI) error {
+		exists, err := NewEntityStateStore(tx).Exists(ctx, snapshot.Namespace, snapshot.Entity)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return &store.ErrNotFound{Key: key}
+		}
+		var lastSystem []byte
+		row := tx.QueryRowContext(ctx, getLastEntityStateSnapshotQuery, snapshot.Namespace, snapshot.Entity)
+		if err := row.Scan(&lastSystem); err != nil && err != sql.ErrNoRows {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		// The systems are always encoded the same way, so that their JSON
+		// can be compared
+		if bytes.Equal(lastSystem, system) {
+			return nil
+		}
+		_, err = tx.ExecContext(ctx, addEntityStateSnapshotQuery, snapshot.Namespace, snapshot.Entity, snapshot.Timestamp, system)
+		if err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		_, err = tx.ExecContext(
+			ctx,
+			pruneEntityStateHistoryQuery,
+			snapshot.Namespace, snapshot.Entity,
+			snapshot.Namespace, snapshot.Entity,
+			storev2.MaxEntityStateSnapshots,
+		)
+		if err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		return nil
+	})
+}
+
+// GetEntityStateHistory gets the snapshots of an entity, most recent first.
+func (s *EntityStateHistoryStore) GetEntityStateHistory(ctx context.Context, namespace, entity string) ([]*storev2.EntityStateSnapshot, error) {
+	rows, err := s.db.QueryContext(ctx, getEntityStateHistoryQuery, namespace, entity)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	defer rows.Close()
+	snapshots := []*storev2.EntityStateSnapshot{}
+	for rows.Next() {
+		snapshot := storev2.EntityStateSnapshot{Namespace: namespace, Entity: entity}
+		var system []byte
+		if err := rows.Scan(&snapshot.Timestamp, &system); err != nil {
+			return nil, &store.ErrInternal{Message: err.Error()}
+		}
+		if err := json.Unmarshal(system, &snapshot.System); err != nil {
+			return nil, &store.ErrDecode{Key: path.Join(namespace, entity), Err: err}
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	return snapshots, nil
+}
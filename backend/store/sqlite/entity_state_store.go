@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/store/patch"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.EntityStateStore = &EntityStateStore{}
+
+type EntityStateStore struct {
+	resources resourceStore[*corev3.EntityState, corev3.EntityState]
+}
+
+func NewEntityStateStore(db DBI) *EntityStateStore {
+	return &EntityStateStore{
+		resources: newResourceStore[*corev3.EntityState](db),
+	}
+}
+
+func (s *EntityStateStore) CreateOrUpdate(ctx context.Context, state *corev3.EntityState) error {
+	return s.resources.CreateOrUpdate(ctx, state)
+}
+
+func (s *EntityStateStore) UpdateIfExists(ctx context.Context, state *corev3.EntityState) error {
+	return s.resources.UpdateIfExists(ctx, state)
+}
+
+func (s *EntityStateStore) CreateIfNotExists(ctx context.Context, state *corev3.EntityState) error {
+	return s.resources.CreateIfNotExists(ctx, state)
+}
+
+func (s *EntityStateStore) Get(ctx context.Context, namespace, name string) (*corev3.EntityState, error) {
+	if namespace == "" || name == "" {
+		return nil, &store.ErrNotValid{Err: errors.New("must specify namespace and name")}
+	}
+	return s.resources.get(ctx, namespace, name)
+}
+
+func (s *EntityStateStore) Delete(ctx context.Context, namespace, name string) error {
+	if namespace == "" || name == "" {
+		return &store.ErrNotValid{Err: errors.New("must specify namespace and name")}
+	}
+	return s.resources.delete(ctx, namespace, name)
+}
+
+func (s *EntityStateStore) List(ctx context.Context, namespace string, pred *store.SelectionPredicate) ([]*corev3.EntityState, error) {
+	return s.resources.list(ctx, namespace, pred)
+}
+
+func (s *EntityStateStore) Count(ctx context.Context, namespace string) (int, error) {
+	return s.resources.count(ctx, namespace)
+}
+
+func (s *EntityStateStore) Exists(ctx context.Context, namespace, name string) (bool, error) {
+	if namespace == "" || name == "" {
+		return false, &store.ErrNotValid{Err: errors.New("must specify namespace and name")}
+	}
+	return s.resources.exists(ctx, namespace, name)
+}
+
+func (s *EntityStateStore) Patch(ctx context.Context, namespace, name string, patcher patch.Patcher) error {
+	if namespace == "" || name == "" {
+		return &store.ErrNotValid{Err: errors.New("must specify namespace and name")}
+	}
+	return s.resources.patch(ctx, namespace, name, patcher)
+}
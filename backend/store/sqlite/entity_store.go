@@ -0,0 +1,213 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+var (
+	_ store.EntityStore        = &EntityStore{}
+	_ store.EntityBatchUpdater = &EntityStore{}
+)
+
+// EntityStore is a storev1 compatibility shim which combines entity configs
+// and entity states into corev2 entities.
+type EntityStore struct {
+	db DBI
+}
+
+func NewEntityStore(db DBI) *EntityStore {
+	return &EntityStore{
+		db: db,
+	}
+}
+
+// DeleteEntity deletes an entity using the given entity struct.
+func (s *EntityStore) DeleteEntity(ctx context.Context, entity *corev2.Entity) error {
+	if err := entity.Validate(); err != nil {
+		return &store.ErrNotValid{Err: err}
+	}
+	return s.deleteEntity(ctx, entity.GetNamespace(), entity.GetName())
+}
+
+// DeleteEntityByName deletes an entity using the given name and the
+// namespace stored in ctx.
+func (s *EntityStore) DeleteEntityByName(ctx context.Context, name string) error {
+	if name == "" {
+		return &store.ErrNotValid{Err: errors.New("must specify name")}
+	}
+	return s.deleteEntity(ctx, corev2.ContextNamespace(ctx), name)
+}
+
+func (s *EntityStore) deleteEntity(ctx context.Context, namespace, name string) error {
+	return withTx(ctx, s.db, func(tx DBI) error {
+		var notFound *store.ErrNotFound
+		if err := NewEntityConfigStore(tx).Delete(ctx, namespace, name); err != nil && !errors.As(err, &notFound) {
+			return err
+		}
+		if err := NewEntityStateStore(tx).Delete(ctx, namespace, name); err != nil && !errors.As(err, &notFound) {
+			return err
+		}
+		return nil
+	})
+}
+
+// GetEntities returns all entities in the given ctx's namespace. A nil slice
+// with no error is returned if none were found.
+func (s *EntityStore) GetEntities(ctx context.Context, pred *store.SelectionPredicate) ([]*corev2.Entity, error) {
+	namespace := corev2.ContextNamespace(ctx)
+	if pred != nil && pred.Ordering == corev2.EntitySortLastSeen {
+		return s.getEntitiesByLastSeen(ctx, namespace, pred)
+	}
+	var entities []*corev2.Entity
+	err := withTx(ctx, s.db, func(tx DBI) error {
+		configs, err := NewEntityConfigStore(tx).List(ctx, namespace, pred)
+		if err != nil {
+			return err
+		}
+		entities, err = entitiesFromConfigs(ctx, tx, configs)
+		return err
+	})
+	return entities, err
+}
+
+// entitiesFromConfigs combines the entity configs with their entity states.
+func entitiesFromConfigs(ctx context.Context, tx DBI, configs []*corev3.EntityConfig) ([]*corev2.Entity, error) {
+	states := NewEntityStateStore(tx)
+	var entities []*corev2.Entity
+	for _, config := range configs {
+		state, err := states.Get(ctx, config.Metadata.Namespace, config.Metadata.Name)
+		if err != nil {
+			var notFound *store.ErrNotFound
+			if !errors.As(err, &notFound) {
+				return nil, err
+			}
+			state = corev3.NewEntityState(config.Metadata.Namespace, config.Metadata.Name)
+		}
+		entity, err := corev3.V3EntityToV2(config, state)
+		if err != nil {
+			return nil, &store.ErrNotValid{Err: err}
+		}
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
+
+// getEntitiesByLastSeen returns the page of entities selected by the
+// predicate, ordered by the time they were last seen, then by name.
+func (s *EntityStore) getEntitiesByLastSeen(ctx context.Context, namespace string, pred *store.SelectionPredicate) ([]*corev2.Entity, error) {
+	var entities []*corev2.Entity
+	err := withTx(ctx, s.db, func(tx DBI) error {
+		configs, err := NewEntityConfigStore(tx).List(ctx, namespace, &store.SelectionPredicate{IncludeDeletes: pred.IncludeDeletes})
+		if err != nil {
+			return err
+		}
+		entities, err = entitiesFromConfigs(ctx, tx, configs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entities, func(i, j int) bool {
+		a, b := entities[i], entities[j]
+		if pred.Descending {
+			a, b = b, a
+		}
+		if a.LastSeen != b.LastSeen {
+			return a.LastSeen < b.LastSeen
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return paginate(entities, pred)
+}
+
+// GetEntityByName returns an entity using the given name and the namespace stored
+// in ctx. The resulting entity is nil if none was found.
+func (s *EntityStore) GetEntityByName(ctx context.Context, name string) (*corev2.Entity, error) {
+	if name == "" {
+		return nil, &store.ErrNotValid{Err: errors.New("must specify name")}
+	}
+	namespace := corev2.ContextNamespace(ctx)
+	var entity *corev2.Entity
+	err := withTx(ctx, s.db, func(tx DBI) error {
+		var notFound *store.ErrNotFound
+		config, err := NewEntityConfigStore(tx).Get(ctx, namespace, name)
+		if err != nil {
+			if errors.As(err, &notFound) {
+				return nil
+			}
+			return fmt.Errorf("error fetching entity config: %w", err)
+		}
+		state, err := NewEntityStateStore(tx).Get(ctx, namespace, name)
+		if err != nil {
+			if !errors.As(err, &notFound) {
+				return fmt.Errorf("error fetching entity state: %w", err)
+			}
+			state = corev3.NewEntityState(namespace, name)
+		}
+		entity, err = corev3.V3EntityToV2(config, state)
+		return err
+	})
+	return entity, err
+}
+
+// UpdateEntity creates or updates a given entity.
+func (s *EntityStore) UpdateEntity(ctx context.Context, entity *corev2.Entity) error {
+	return withTx(ctx, s.db, func(tx DBI) error {
+		return s.updateEntity(ctx, tx, entity)
+	})
+}
+
+func (s *EntityStore) updateEntity(ctx context.Context, tx DBI, entity *corev2.Entity) error {
+	if entity.Namespace == "" {
+		entity.Namespace = corev2.ContextNamespace(ctx)
+	}
+	config, state := corev3.V2EntityToV3(entity)
+	if err := NewEntityConfigStore(tx).CreateOrUpdate(ctx, config); err != nil {
+		return fmt.Errorf("error updating entity config: %w", err)
+	}
+	if err := NewEntityStateStore(tx).CreateOrUpdate(ctx, state); err != nil {
+		return fmt.Errorf("error updating entity state: %w", err)
+	}
+	return nil
+}
+
+// UpdateEntities creates or updates the given entities in a single
+// transaction. Each entity is written under its own savepoint, so that an
+// invalid entity does not prevent the others from being stored.
+func (s *EntityStore) UpdateEntities(ctx context.Context, entities []*corev2.Entity) []error {
+	errs := make([]error, len(entities))
+	err := withTx(ctx, s.db, func(tx DBI) error {
+		for i, entity := range entities {
+			if _, err := tx.ExecContext(ctx, "SAVEPOINT update_entity;"); err != nil {
+				return err
+			}
+			if errs[i] = s.updateEntity(ctx, tx, entity); errs[i] != nil {
+				if _, err := tx.ExecContext(ctx, "ROLLBACK TO update_entity;"); err != nil {
+					return err
+				}
+			}
+			if _, err := tx.ExecContext(ctx, "RELEASE update_entity;"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		for i := range errs {
+			if errs[i] == nil {
+				errs[i] = &store.ErrInternal{Message: err.Error()}
+			}
+		}
+	}
+	return errs
+}
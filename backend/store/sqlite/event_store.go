@@ -0,0 +1,605 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/selector"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ store.EventStore = &EventStore{}
+
+// EventStore stores events as snappy compressed protobuf, along with a set of
+// selectors used for filtering and ordering.
+type EventStore struct {
+	db           DBI
+	silenceStore storev2.SilencesStore
+}
+
+func NewEventStore(db DBI, sStore storev2.SilencesStore) *EventStore {
+	return &EventStore{
+		db:           db,
+		silenceStore: sStore,
+	}
+}
+
+func getNamespace(ctx context.Context) (string, error) {
+	if ns := corev2.ContextNamespace(ctx); ns == "" {
+		return "", &store.ErrNotValid{Err: errors.New("namespace missing from context")}
+	} else {
+		return ns, nil
+	}
+}
+
+func decodeEvent(serialized []byte) (*corev2.Event, error) {
+	decompressed, err := snappy.Decode(nil, serialized)
+	if err != nil {
+		return nil, &store.ErrNotValid{Err: err}
+	}
+	var event corev2.Event
+	if err := proto.Unmarshal(decompressed, &event); err != nil {
+		return nil, &store.ErrDecode{Err: fmt.Errorf("error reading events: %s", err)}
+	}
+	if event.Check == nil {
+		return nil, &store.ErrNotValid{Err: errors.New("nil check")}
+	}
+	return &event, nil
+}
+
+type eventRecord struct {
+	namespace  string
+	entity     string
+	check      string
+	serialized []byte
+	selectors  map[string]string
+}
+
+// listEvents returns the event records of the namespace (or all namespaces,
+// if empty) and entity (or all entities, if empty) that match the selector.
+func (e *EventStore) listEvents(ctx context.Context, namespace, entity string, sel *selector.Selector) ([]eventRecord, error) {
+	rows, err := e.db.QueryContext(ctx, listEventsQuery, namespace, namespace, entity, entity)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("couldn't get events: %s", err)}
+	}
+	defer rows.Close()
+
+	var records []eventRecord
+	for rows.Next() {
+		var (
+			rec       eventRecord
+			selectors string
+		)
+		if err := rows.Scan(&rec.namespace, &rec.entity, &rec.check, &rec.serialized, &selectors); err != nil {
+			return nil, &store.ErrNotValid{Err: fmt.Errorf("error reading events: %s", err)}
+		}
+		if err := json.Unmarshal([]byte(selectors), &rec.selectors); err != nil {
+			return nil, &store.ErrDecode{Err: fmt.Errorf("error reading event selectors: %s", err)}
+		}
+		if sel != nil && len(sel.Operations) > 0 && !sel.Matches(rec.selectors) {
+			continue
+		}
+		records = append(records, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("error reading events: %s", err)}
+	}
+	return records, nil
+}
+
+// severity ranks check statuses from most to least severe.
+func severity(status string) int {
+	switch status {
+	case "2":
+		return 0
+	case "1":
+		return 1
+	case "0":
+		return 3
+	default:
+		return 2
+	}
+}
+
+func selectorInt(rec eventRecord, key string) int64 {
+	i, _ := strconv.ParseInt(rec.selectors[key], 10, 64)
+	return i
+}
+
+// eventSortKeys are the selectors that events are sorted on, by ordering.
+var eventSortKeys = map[string]string{
+	corev2.EventSortLastOk:    "event.check.last_ok",
+	corev2.EventSortSeverity:  "event.check.status",
+	corev2.EventSortTimestamp: "event.timestamp",
+}
+
+// eventOrdering returns the ordering requested by the predicate. NAME is
+// accepted as an alias of ENTITY.
+func eventOrdering(pred *store.SelectionPredicate) (string, error) {
+	if pred == nil {
+		return "", nil
+	}
+	switch pred.Ordering {
+	case "", corev2.EventSortEntity, corev2.EventSortLastOk, corev2.EventSortSeverity, corev2.EventSortTimestamp:
+		return pred.Ordering, nil
+	case corev2.EntitySortName:
+		return corev2.EventSortEntity, nil
+	default:
+		return "", fmt.Errorf("unknown ordering requested: %s", pred.Ordering)
+	}
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareEvents compares two records by the ordering. Records are ordered by
+// namespace, entity and check name by default, and records that are equal by
+// the ordering fall back to that order, so that the order is total.
+func compareEvents(ordering string, a, b eventRecord) int {
+	var c int
+	switch ordering {
+	case corev2.EventSortEntity:
+		if c = strings.Compare(a.entity, b.entity); c != 0 {
+			return c
+		}
+	case corev2.EventSortSeverity:
+		key := eventSortKeys[ordering]
+		c = compareInts(int64(severity(a.selectors[key])), int64(severity(b.selectors[key])))
+	case corev2.EventSortLastOk, corev2.EventSortTimestamp:
+		key := eventSortKeys[ordering]
+		c = compareInts(selectorInt(a, key), selectorInt(b, key))
+	}
+	if c != 0 {
+		return c
+	}
+	if c = strings.Compare(a.namespace, b.namespace); c != 0 {
+		return c
+	}
+	if c = strings.Compare(a.entity, b.entity); c != 0 {
+		return c
+	}
+	return strings.Compare(a.check, b.check)
+}
+
+func eventsLess(ordering string, descending bool) func(a, b eventRecord) bool {
+	return func(a, b eventRecord) bool {
+		if descending {
+			return compareEvents(ordering, a, b) > 0
+		}
+		return compareEvents(ordering, a, b) < 0
+	}
+}
+
+// eventCursor is the continue token of event lists. It records the position
+// of the last event of a page in the ordering of the list, so that the next
+// page starts right after that event, even if events were created or deleted
+// in the meantime.
+type eventCursor struct {
+	Ordering   string `json:"ordering,omitempty"`
+	Descending bool   `json:"descending,omitempty"`
+	Key        string `json:"key,omitempty"`
+	Namespace  string `json:"namespace"`
+	Entity     string `json:"entity"`
+	Check      string `json:"check"`
+}
+
+func newEventCursor(ordering string, descending bool, rec eventRecord) *eventCursor {
+	return &eventCursor{
+		Ordering:   ordering,
+		Descending: descending,
+		Key:        rec.selectors[eventSortKeys[ordering]],
+		Namespace:  rec.namespace,
+		Entity:     rec.entity,
+		Check:      rec.check,
+	}
+}
+
+func (c *eventCursor) record() eventRecord {
+	rec := eventRecord{
+		namespace: c.Namespace,
+		entity:    c.Entity,
+		check:     c.Check,
+		selectors: map[string]string{},
+	}
+	if key, ok := eventSortKeys[c.Ordering]; ok {
+		rec.selectors[key] = c.Key
+	}
+	return rec
+}
+
+// paginateEvents returns the page of sorted records selected by the
+// predicate, and sets its continue token to the cursor of the next page.
+// Offset continue tokens are still honored.
+func paginateEvents(records []eventRecord, ordering string, pred *store.SelectionPredicate) ([]eventRecord, error) {
+	if pred == nil || pred.Limit <= 0 {
+		return records, nil
+	}
+	var token continueToken
+	if pred.Continue != "" {
+		if err := token.Decode(pred.Continue); err != nil {
+			return nil, &store.ErrNotValid{Err: fmt.Errorf("error decoding continue token: %s", err)}
+		}
+	}
+	less := eventsLess(ordering, pred.Descending)
+	start := pred.Offset
+	if cursor := token.Cursor; cursor != nil {
+		if cursor.Ordering != ordering || cursor.Descending != pred.Descending {
+			return nil, &store.ErrNotValid{Err: errors.New("continue token does not match the requested ordering")}
+		}
+		after := cursor.record()
+		start = int64(sort.Search(len(records), func(i int) bool {
+			return less(after, records[i])
+		}))
+	} else if pred.Continue != "" {
+		start = token.Offset
+	}
+	if start >= int64(len(records)) {
+		records = records[:0]
+	} else {
+		records = records[start:]
+	}
+	pred.Continue = ""
+	if int64(len(records)) > pred.Limit {
+		records = records[:pred.Limit]
+		last := records[len(records)-1]
+		token = continueToken{Cursor: newEventCursor(ordering, pred.Descending, last)}
+		pred.Continue = token.Encode()
+	}
+	return records, nil
+}
+
+func (e *EventStore) getEvents(ctx context.Context, namespace, entity string, pred *store.SelectionPredicate) ([]*corev2.Event, error) {
+	records, err := e.listEvents(ctx, namespace, entity, storev2.EventSelectorFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	ordering, err := eventOrdering(pred)
+	if err != nil {
+		return nil, &store.ErrNotValid{Err: err}
+	}
+	less := eventsLess(ordering, pred != nil && pred.Descending)
+	sort.Slice(records, func(i, j int) bool {
+		return less(records[i], records[j])
+	})
+	records, err = paginateEvents(records, ordering, pred)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*corev2.Event, 0, len(records))
+	for _, rec := range records {
+		event, err := decodeEvent(rec.serialized)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (e *EventStore) DeleteEventByEntityCheck(ctx context.Context, entity, check string) error {
+	ns, err := getNamespace(ctx)
+	if err != nil {
+		return err
+	}
+	if entity == "" || check == "" {
+		return &store.ErrNotValid{Err: errors.New("must specify entity and check name")}
+	}
+	if _, err := e.db.ExecContext(ctx, deleteEventQuery, ns, entity, check); err != nil {
+		return &store.ErrInternal{Message: fmt.Sprintf("couldn't delete event: %s", err)}
+	}
+	return nil
+}
+
+// PruneEvents deletes the events of the namespace older than before, and the
+// oldest events beyond max. Keepalive events are never pruned.
+func (e *EventStore) PruneEvents(ctx context.Context, namespace string, before int64, max int) (int64, error) {
+	if before <= 0 && max <= 0 {
+		return 0, nil
+	}
+	result, err := e.db.ExecContext(ctx, pruneEventsQuery, namespace, before, before, max, max)
+	if err != nil {
+		return 0, &store.ErrInternal{Message: fmt.Sprintf("couldn't prune events: %s", err)}
+	}
+	return result.RowsAffected()
+}
+
+func (e *EventStore) GetEvents(ctx context.Context, pred *store.SelectionPredicate) ([]*corev2.Event, error) {
+	ns := corev2.ContextNamespace(ctx)
+	if ns == corev2.NamespaceTypeAll {
+		ns = ""
+	}
+	return e.getEvents(ctx, ns, "", pred)
+}
+
+func (e *EventStore) GetEventsByEntity(ctx context.Context, entity string, pred *store.SelectionPredicate) ([]*corev2.Event, error) {
+	ns, err := getNamespace(ctx)
+	if err != nil {
+		// Warning: do not wrap this error
+		return nil, err
+	}
+	if entity == "" {
+		return nil, &store.ErrNotValid{Err: errors.New("couldn't get events: must specify entity")}
+	}
+	return e.getEvents(ctx, ns, entity, pred)
+}
+
+func (e *EventStore) getEvent(ctx context.Context, namespace, entity, check string) (*corev2.Event, error) {
+	var serialized []byte
+	row := e.db.QueryRowContext(ctx, getEventQuery, namespace, entity, check)
+	if err := row.Scan(&serialized); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("couldn't get event: %s", err)}
+	}
+	return decodeEvent(serialized)
+}
+
+func (e *EventStore) GetEventByEntityCheck(ctx context.Context, entity, check string) (*corev2.Event, error) {
+	ns, err := getNamespace(ctx)
+	if err != nil {
+		// Warning: do not wrap this error
+		return nil, err
+	}
+	if entity == "" || check == "" {
+		return nil, &store.ErrNotValid{Err: errors.New("must specify entity and check name")}
+	}
+	return e.getEvent(ctx, ns, entity, check)
+}
+
+func marshalSelectors(event *corev2.Event) ([]byte, error) {
+	selectors := corev2.EventFields(event)
+	for k, v := range event.Labels {
+		k = fmt.Sprintf("event.labels.%s", k)
+		selectors[k] = v
+	}
+	if event.HasCheck() {
+		for k, v := range event.Check.Labels {
+			k = fmt.Sprintf("event.check.labels.%s", k)
+			selectors[k] = v
+		}
+	}
+	for k, v := range event.Entity.Labels {
+		k = fmt.Sprintf("event.entity.labels.%s", k)
+		selectors[k] = v
+	}
+	return json.Marshal(selectors)
+}
+
+// UpdateEvent updates the event in the store, returns the fully updated event,
+// and the previous event, along with any error encountered.
+func (e *EventStore) UpdateEvent(ctx context.Context, event *corev2.Event) (uEvent, pEvent *corev2.Event, eErr error) {
+	if event == nil || event.Check == nil {
+		return nil, nil, errors.New("event has no check")
+	}
+
+	if err := event.Check.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := event.Entity.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	persistEvent := event
+
+	if event.HasMetrics() {
+		// Taking pains to not modify our input, set metrics to nil so they are
+		// not persisted. Set metrics back to non-nil before returning the event.
+		metrics := event.Metrics
+		defer func() {
+			if uEvent != nil {
+				uEvent.Metrics = metrics
+			}
+		}()
+		newEvent := *event
+		persistEvent = &newEvent
+		persistEvent.Metrics = nil
+	}
+
+	// Truncate check output if the output is larger than MaxOutputSize
+	if size := event.Check.MaxOutputSize; size > 0 && int64(len(event.Check.Output)) > size {
+		// Taking pains to not modify our input, set a bound on the check
+		// output size.
+		newEvent := *persistEvent
+		persistEvent = &newEvent
+		check := *persistEvent.Check
+		check.Output = check.Output[:size]
+		persistEvent.Check = &check
+	}
+
+	if persistEvent.Timestamp == 0 {
+		// If the event is being created for the first time, it may not include
+		// a timestamp. Use the current time.
+		persistEvent.Timestamp = time.Now().Unix()
+	}
+
+	var prevEvent *corev2.Event
+
+	err := withTx(ctx, e.db, func(tx DBI) error {
+		exists, err := NewNamespaceStore(tx).Exists(ctx, event.Entity.Namespace)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return &store.ErrNamespaceMissing{Namespace: event.Entity.Namespace}
+		}
+
+		if !store.IsNoMergeEventContext(ctx) {
+			txStore := EventStore{db: tx}
+			prevEvent, err = txStore.getEvent(ctx, event.Entity.Namespace, event.Entity.Name, event.Check.Name)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := updateEventHistory(event, prevEvent); err != nil {
+			return &store.ErrNotValid{Err: err}
+		}
+
+		updateOccurrences(event.Check)
+
+		selectors, err := marshalSelectors(event)
+		if err != nil {
+			return &store.ErrEncode{Err: err}
+		}
+
+		b, err := proto.Marshal(persistEvent)
+		if err != nil {
+			return &store.ErrEncode{Err: err}
+		}
+
+		serialized := snappy.Encode(nil, b)
+
+		updateCheckState(event.Check)
+
+		if _, err := tx.ExecContext(ctx, createOrUpdateEventQuery, event.Entity.Namespace, event.Entity.Name, event.Check.Name, string(selectors), serialized); err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return event, prevEvent, nil
+}
+
+func (e *EventStore) CountEvents(ctx context.Context, pred *store.SelectionPredicate) (int64, error) {
+	ns := corev2.ContextNamespace(ctx)
+	if ns == corev2.NamespaceTypeAll {
+		ns = ""
+	}
+	records, err := e.listEvents(ctx, ns, "", storev2.EventSelectorFromContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(records)), nil
+}
+
+func (e *EventStore) EventStoreSupportsFiltering(_ context.Context) bool { return true }
+
+// isFlapping determines if the check is flapping, based on the TotalStateChange
+// and configured thresholds
+func isFlapping(check *corev2.Check) bool {
+	if check == nil {
+		return false
+	}
+
+	if check.LowFlapThreshold == 0 || check.HighFlapThreshold == 0 {
+		return false
+	}
+
+	// Is the check already flapping?
+	if check.State == corev2.EventFlappingState {
+		return check.TotalStateChange > check.LowFlapThreshold
+	}
+
+	// The check was not flapping, now determine if it does now
+	return check.TotalStateChange >= check.HighFlapThreshold
+}
+
+// updateCheckState determines the check state based on whether the check is
+// flapping, and its status
+func updateCheckState(check *corev2.Check) {
+	if check == nil {
+		return
+	}
+	check.TotalStateChange = totalStateChange(check)
+	if flapping := isFlapping(check); flapping {
+		check.State = corev2.EventFlappingState
+	} else if check.Status == 0 {
+		check.State = corev2.EventPassingState
+		check.LastOK = check.Executed
+	} else {
+		check.State = corev2.EventFailingState
+	}
+}
+
+// totalStateChange calculates the total state change percentage for the
+// history, which is later used for check state flap detection.
+func totalStateChange(check *corev2.Check) uint32 {
+	if check == nil || len(check.History) < 21 {
+		return 0
+	}
+
+	stateChanges := 0.00
+	changeWeight := 0.80
+	previousStatus := check.History[0].Status
+
+	for i := 1; i <= len(check.History)-1; i++ {
+		if check.History[i].Status != previousStatus {
+			stateChanges += changeWeight
+		}
+
+		changeWeight += 0.02
+		previousStatus = check.History[i].Status
+	}
+
+	return uint32(float32(stateChanges) / 20 * 100)
+}
+
+func updateOccurrences(check *corev2.Check) {
+	if check == nil {
+		return
+	}
+
+	historyLen := len(check.History)
+	if historyLen > 1 && check.History[historyLen-1].Status == check.History[historyLen-2].Status {
+		// 1. Occurrences should always be incremented if the current Check status is the same as the previous status (this includes events with the Check status of OK)
+		check.Occurrences++
+	} else {
+		// 2. Occurrences should always reset to 1 if the current Check status is different than the previous status
+		check.Occurrences = 1
+	}
+
+	if historyLen > 1 && check.History[historyLen-1].Status != 0 && check.History[historyLen-2].Status == 0 {
+		// 3. OccurrencesWatermark only resets on the a first non OK Check status (it does not get reset going between warning, critical, unknown)
+		check.OccurrencesWatermark = 1
+	} else if check.Occurrences <= check.OccurrencesWatermark {
+		// 4. OccurrencesWatermark should remain the same when occurrences is less than or equal to the watermark
+		return
+	} else {
+		// 5. OccurrencesWatermark should be incremented if conditions 3 and 4 have not been met.
+		check.OccurrencesWatermark++
+	}
+}
+
+// updateEventHistory takes two events and merges the check result history of
+// the second event into the first event.
+func updateEventHistory(event *corev2.Event, prevEvent *corev2.Event) error {
+	if prevEvent != nil {
+		if !prevEvent.HasCheck() {
+			return errors.New("invalid previous event")
+		}
+		event.Check.MergeWith(prevEvent.Check)
+	} else {
+		// If there was no previous check, we still need to set State and LastOK.
+		event.Check.State = corev2.EventFailingState
+		if event.Check.Status == 0 {
+			event.Check.LastOK = event.Check.Executed
+			event.Check.State = corev2.EventPassingState
+		}
+		event.Check.MergeWith(event.Check)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"context"
+	"path"
+
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.HandlerResultStore = &HandlerResultStore{}
+
+type HandlerResultStore struct {
+	db DBI
+}
+
+func NewHandlerResultStore(db DBI) *HandlerResultStore {
+	return &HandlerResultStore{db: db}
+}
+
+// AddHandlerResult records a handler result and removes the results of the
+// same event and handler that exceed storev2.MaxHandlerResults. The entity of
+// the result must exist, and its results are deleted with it.
+func (s *HandlerResultStore) AddHandlerResult(ctx context.Context, result *storev2.HandlerResult) error {
+	result.TruncateOutput()
+	return withTx(ctx, s.db, func(tx DBI) error {
+		exists, err := NewEntityConfigStore(tx).Exists(ctx, result.Namespace, result.Entity)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return &store.ErrNotFound{Key: path.Join(result.Namespace, result.Entity)}
+		}
+		_, err = tx.ExecContext(
+			ctx,
+			addHandlerResultQuery,
+			result.Namespace,
+			result.Entity,
+			result.Check,
+			result.Handler,
+			result.Executed,
+			result.Duration,
+			result.Status,
+			result.Output,
+			result.Error,
+		)
+		if err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		_, err = tx.ExecContext(
+			ctx,
+			pruneHandlerResultsQuery,
+			result.Namespace, result.Entity, result.Check, result.Handler,
+			result.Namespace, result.Entity, result.Check, result.Handler,
+			storev2.MaxHandlerResults,
+		)
+		if err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		return nil
+	})
+}
+
+// GetHandlerResults gets the handler results of an event, most recent first.
+func (s *HandlerResultStore) GetHandlerResults(ctx context.Context, namespace, entity, check string) ([]*storev2.HandlerResult, error) {
+	rows, err := s.db.QueryContext(ctx, getHandlerResultsQuery, namespace, entity, check)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	defer rows.Close()
+	results := []*storev2.HandlerResult{}
+	for rows.Next() {
+		var result storev2.HandlerResult
+		err := rows.Scan(
+			&result.Namespace,
+			&result.Entity,
+			&result.Check,
+			&result.Handler,
+			&result.Executed,
+			&result.Duration,
+			&result.Status,
+			&result.Output,
+			&result.Error,
+		)
+		if err != nil {
+			return nil, &store.ErrInternal{Message: err.Error()}
+		}
+		results = append(results, &result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	return results, nil
+}
@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.MFAStore = &MFAStore{}
+
+type MFAStore struct {
+	db DBI
+}
+
+func NewMFAStore(db DBI) *MFAStore {
+	return &MFAStore{db: db}
+}
+
+// PutMFAEnrollment replaces the enrollment of a user and its recovery codes
+// with a new, unconfirmed enrollment.
+func (s *MFAStore) PutMFAEnrollment(ctx context.Context, enrollment *storev2.MFAEnrollment, recoveryCodeHashes []string) error {
+	return withTx(ctx, s.db, func(tx DBI) error {
+		result, err := tx.ExecContext(ctx, putMFAEnrollmentQuery, enrollment.Username, enrollment.Secret, enrollment.CreatedAt)
+		if err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		if affected == 0 {
+			return &store.ErrAlreadyExists{Key: enrollment.Username}
+		}
+		if _, err := tx.ExecContext(ctx, deleteMFARecoveryCodesQuery, enrollment.Username); err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		for _, hash := range recoveryCodeHashes {
+			if _, err := tx.ExecContext(ctx, addMFARecoveryCodeQuery, enrollment.Username, hash); err != nil {
+				return &store.ErrInternal{Message: err.Error()}
+			}
+		}
+		return nil
+	})
+}
+
+// GetMFAEnrollment gets the enrollment of a user.
+func (s *MFAStore) GetMFAEnrollment(ctx context.Context, username string) (*storev2.MFAEnrollment, error) {
+	var enrollment storev2.MFAEnrollment
+	err := s.db.QueryRowContext(ctx, getMFAEnrollmentQuery, username).Scan(
+		&enrollment.Username,
+		&enrollment.Secret,
+		&enrollment.Confirmed,
+		&enrollment.CreatedAt,
+		&enrollment.LastCounter,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &store.ErrNotFound{Key: username}
+		}
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	return &enrollment, nil
+}
+
+// ConfirmMFAEnrollment confirms the enrollment of a user.
+func (s *MFAStore) ConfirmMFAEnrollment(ctx context.Context, username string) error {
+	result, err := s.db.ExecContext(ctx, confirmMFAEnrollmentQuery, username)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	if affected == 0 {
+		return &store.ErrNotFound{Key: username}
+	}
+	return nil
+}
+
+// UseMFACounter records that the one-time password of the given time step was
+// used by a user.
+func (s *MFAStore) UseMFACounter(ctx context.Context, username string, counter int64) (bool, error) {
+	return s.exec(ctx, useMFACounterQuery, username, counter)
+}
+
+// UseMFARecoveryCode removes a recovery code of a user, and returns whether it
+// existed.
+func (s *MFAStore) UseMFARecoveryCode(ctx context.Context, username, codeHash string) (bool, error) {
+	return s.exec(ctx, useMFARecoveryCodeQuery, username, codeHash)
+}
+
+// DeleteMFAEnrollment removes the enrollment of a user and its recovery codes.
+func (s *MFAStore) DeleteMFAEnrollment(ctx context.Context, username string) error {
+	return withTx(ctx, s.db, func(tx DBI) error {
+		if _, err := tx.ExecContext(ctx, deleteMFARecoveryCodesQuery, username); err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		if _, err := tx.ExecContext(ctx, deleteMFAEnrollmentQuery, username); err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		return nil
+	})
+}
+
+// exec executes a query, and returns whether it affected rows.
+func (s *MFAStore) exec(ctx context.Context, query string, args ...interface{}) (bool, error) {
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, &store.ErrInternal{Message: err.Error()}
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, &store.ErrInternal{Message: err.Error()}
+	}
+	return affected > 0, nil
+}
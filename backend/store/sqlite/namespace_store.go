@@ -0,0 +1,120 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/store/patch"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.NamespaceStore = &NamespaceStore{}
+
+type NamespaceStore struct {
+	db        DBI
+	resources resourceStore[*corev3.Namespace, corev3.Namespace]
+}
+
+func NewNamespaceStore(db DBI) *NamespaceStore {
+	return &NamespaceStore{
+		db:        db,
+		resources: newResourceStore[*corev3.Namespace](db),
+	}
+}
+
+func (s *NamespaceStore) CreateOrUpdate(ctx context.Context, namespace *corev3.Namespace) error {
+	return s.resources.CreateOrUpdate(ctx, namespace)
+}
+
+func (s *NamespaceStore) UpdateIfExists(ctx context.Context, namespace *corev3.Namespace) error {
+	return s.resources.UpdateIfExists(ctx, namespace)
+}
+
+func (s *NamespaceStore) CreateIfNotExists(ctx context.Context, namespace *corev3.Namespace) error {
+	return s.resources.CreateIfNotExists(ctx, namespace)
+}
+
+func (s *NamespaceStore) Get(ctx context.Context, name string) (*corev3.Namespace, error) {
+	if name == "" {
+		return nil, &store.ErrNotValid{Err: errors.New("must specify name")}
+	}
+	return s.resources.get(ctx, "", name)
+}
+
+// Delete deletes the namespace, along with its events, silences and
+// operators. It returns an error if the namespace still contains configuration
+// resources.
+func (s *NamespaceStore) Delete(ctx context.Context, name string) error {
+	if name == "" {
+		return &store.ErrNotValid{Err: errors.New("must specify name")}
+	}
+	return withTx(ctx, s.db, func(tx DBI) error {
+		txStore := NewNamespaceStore(tx)
+		empty, err := txStore.IsEmpty(ctx, name)
+		if err != nil {
+			return err
+		}
+		if !empty {
+			return &store.ErrNamespaceNotEmpty{Namespace: name}
+		}
+		if err := txStore.resources.delete(ctx, "", name); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, deleteNamespaceEventsQuery, name); err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		if _, err := tx.ExecContext(ctx, deleteNamespaceSilencesQuery, name); err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		if _, err := tx.ExecContext(ctx, deleteNamespaceHandlerResultsQuery, name); err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		if _, err := tx.ExecContext(ctx, deleteNamespaceOperatorsQuery, name); err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		if _, err := tx.ExecContext(ctx, deleteNamespaceEntityStateHistoryQuery, name); err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		if _, err := tx.ExecContext(ctx, deleteNamespaceResourceHistoryQuery, name); err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		return nil
+	})
+}
+
+func (s *NamespaceStore) List(ctx context.Context, pred *store.SelectionPredicate) ([]*corev3.Namespace, error) {
+	return s.resources.list(ctx, "", pred)
+}
+
+func (s *NamespaceStore) Count(ctx context.Context) (int, error) {
+	return s.resources.count(ctx, "")
+}
+
+func (s *NamespaceStore) Exists(ctx context.Context, name string) (bool, error) {
+	if name == "" {
+		return false, &store.ErrNotValid{Err: errors.New("must specify name")}
+	}
+	return s.resources.exists(ctx, "", name)
+}
+
+func (s *NamespaceStore) Patch(ctx context.Context, name string, patcher patch.Patcher) error {
+	if name == "" {
+		return &store.ErrNotValid{Err: errors.New("must specify name")}
+	}
+	return s.resources.patch(ctx, "", name, patcher)
+}
+
+// IsEmpty returns whether the namespace contains any configuration
+// resources, including entities.
+func (s *NamespaceStore) IsEmpty(ctx context.Context, name string) (bool, error) {
+	if name == "" {
+		return false, &store.ErrNotValid{Err: errors.New("must specify name")}
+	}
+	var count int
+	if err := s.db.QueryRowContext(ctx, namespaceResourceCountQuery, name).Scan(&count); err != nil {
+		return false, &store.ErrInternal{Message: err.Error()}
+	}
+	return count == 0, nil
+}
@@ -0,0 +1,201 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+var (
+	_ store.OperatorConcierge = &OPC{}
+	_ store.OperatorMonitor   = &OPC{}
+	_ store.OperatorQueryer   = &OPC{}
+)
+
+// OPC is the operator concierge of a single backend, backed by sqlite. It
+// keeps track of the operators checking in, like the postgres OPC, so that
+// the absences of the agents are noticed across restarts of the backend.
+type OPC struct {
+	db DBI
+}
+
+// NewOPC returns an OPC storing the operators in the given database.
+func NewOPC(db DBI) *OPC {
+	return &OPC{db: db}
+}
+
+func (o *OPC) MonitorOperators(ctx context.Context, req store.MonitorOperatorsRequest) <-chan []store.OperatorState {
+	results := make(chan []store.OperatorState, 1)
+	if req.Every == 0 {
+		req.Every = time.Second
+	}
+	if req.ErrorHandler == nil {
+		req.ErrorHandler = func(error) {}
+	}
+	go func() {
+		ticker := time.NewTicker(req.Every)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				operators, err := o.notifications(ctx, req)
+				if err != nil {
+					req.ErrorHandler(err)
+					continue
+				}
+				results <- operators
+			}
+		}
+	}()
+	return results
+}
+
+// notifications returns the absent operators monitored by the request, and
+// notes that they are still absent, so that they are only notified again
+// after another timeout.
+func (o *OPC) notifications(ctx context.Context, req store.MonitorOperatorsRequest) ([]store.OperatorState, error) {
+	var operators []store.OperatorState
+	err := withTx(ctx, o.db, func(tx DBI) error {
+		now := time.Now().UnixMicro()
+		if _, err := tx.ExecContext(ctx, opcReassignAbsentControllersQuery, now); err != nil {
+			return fmt.Errorf("couldn't reassign absent controllers: %s", err)
+		}
+		query := opcGetNotificationsQuery
+		if req.Micromanage {
+			query = opcGetGrandchildNotificationsQuery
+		}
+		rows, err := tx.QueryContext(ctx, query, req.Type, req.ControllerNamespace, req.ControllerType, req.ControllerName, now)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		var ids []int64
+		for rows.Next() {
+			var id int64
+			var operator store.OperatorState
+			if err := scanOperator(rows, &id, &operator); err != nil {
+				return err
+			}
+			operator.Present = false
+			ids = append(ids, id)
+			operators = append(operators, operator)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if _, err := tx.ExecContext(ctx, opcUpdateNotificationQuery, now, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if operators == nil {
+		operators = []store.OperatorState{}
+	}
+	return operators, nil
+}
+
+func (o *OPC) CheckIn(ctx context.Context, state store.OperatorState) error {
+	var ctl store.OperatorKey
+	if state.Controller != nil {
+		ctl = *state.Controller
+	}
+	meta := []byte("{}")
+	if state.Metadata != nil {
+		meta = *state.Metadata
+	}
+	timeout := int64(state.CheckInTimeout / time.Microsecond)
+	_, err := o.db.ExecContext(ctx, opcCheckInQuery, state.Namespace, state.Type, state.Name,
+		ctl.Namespace, ctl.Type, ctl.Name, state.Present, time.Now().UnixMicro(), timeout, string(meta))
+	if err != nil {
+		return fmt.Errorf("couldn't check in operator: %s", err)
+	}
+	return nil
+}
+
+func (o *OPC) CheckOut(ctx context.Context, key store.OperatorKey) error {
+	result, err := o.db.ExecContext(ctx, opcCheckOutQuery, key.Namespace, key.Type, key.Name)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n < 1 {
+		return &store.ErrNotFound{Key: fmt.Sprintf("%#v", key)}
+	}
+	return nil
+}
+
+func (o *OPC) QueryOperator(ctx context.Context, key store.OperatorKey) (store.OperatorState, error) {
+	row := o.db.QueryRowContext(ctx, opcGetOperatorQuery, key.Namespace, key.Type, key.Name)
+	var operator store.OperatorState
+	if err := scanOperator(row, nil, &operator); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return store.OperatorState{}, &store.ErrNotFound{Key: fmt.Sprintf("%#v\n", key)}
+		}
+		return store.OperatorState{}, err
+	}
+	return operator, nil
+}
+
+func (o *OPC) ListOperators(ctx context.Context, key store.OperatorKey) ([]store.OperatorState, error) {
+	rows, err := o.db.QueryContext(ctx, opcListOperatorsQuery, key.Namespace, key.Type, key.Name)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("could not get operators: %s", err)}
+	}
+	defer rows.Close()
+	var operators []store.OperatorState
+	for rows.Next() {
+		var operator store.OperatorState
+		if err := scanOperator(rows, nil, &operator); err != nil {
+			return nil, &store.ErrInternal{Message: fmt.Sprintf("error reading operator state: %s", err)}
+		}
+		operators = append(operators, operator)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: fmt.Sprintf("error reading operator states: %s", err)}
+	}
+	return operators, nil
+}
+
+type scanner interface {
+	Scan(...any) error
+}
+
+// scanOperator scans the opc columns of a row into an operator, preceded by
+// the operator id when id is not nil.
+func scanOperator(row scanner, id *int64, operator *store.OperatorState) error {
+	var ctl store.OperatorKey
+	var lastUpdate, timeout int64
+	var meta string
+	dest := []any{
+		&operator.Namespace, &operator.Type, &operator.Name,
+		&ctl.Namespace, &ctl.Type, &ctl.Name,
+		&operator.Present, &lastUpdate, &timeout, &meta,
+	}
+	if id != nil {
+		dest = append([]any{id}, dest...)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+	if ctl.Type != store.NullOperator {
+		operator.Controller = &ctl
+	}
+	operator.LastUpdate = time.UnixMicro(lastUpdate)
+	operator.CheckInTimeout = time.Duration(timeout) * time.Microsecond
+	metadata := json.RawMessage(meta)
+	operator.Metadata = &metadata
+	return nil
+}
@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sensu/sensu-go/backend/store"
+)
+
+type continueToken struct {
+	Offset int64        `json:"offset,omitempty"`
+	Cursor *eventCursor `json:"cursor,omitempty"`
+}
+
+func (c *continueToken) Encode() string {
+	b, _ := json.Marshal(c)
+	return string(b)
+}
+
+func (c *continueToken) Decode(token string) error {
+	if err := json.Unmarshal([]byte(token), c); err != nil {
+		return fmt.Errorf("couldn't decode token: %s", err)
+	}
+	return nil
+}
+
+// getLimitAndOffset reads the limit and offset of the selection predicate,
+// and sets the continue token for the next page. A limit of zero means no
+// limit.
+func getLimitAndOffset(pred *store.SelectionPredicate) (int64, int64, error) {
+	var limit, offset int64
+	if pred != nil && pred.Limit > 0 {
+		limit = pred.Limit
+		var token continueToken
+		if pred.Offset > 0 {
+			offset = pred.Offset
+		}
+		if pred.Continue != "" {
+			if err := token.Decode(pred.Continue); err != nil {
+				return limit, offset, &store.ErrNotValid{Err: fmt.Errorf("error decoding continue token: %s", err)}
+			}
+			offset = token.Offset
+		}
+		token.Offset = offset + pred.Limit
+		pred.Continue = token.Encode()
+	}
+	return limit, offset, nil
+}
+
+// paginate returns the page of items selected by the predicate. The continue
+// token of the predicate is cleared when there are no more items.
+func paginate[T any](items []T, pred *store.SelectionPredicate) ([]T, error) {
+	limit, offset, err := getLimitAndOffset(pred)
+	if err != nil {
+		return nil, err
+	}
+	if offset >= int64(len(items)) {
+		items = items[:0]
+	} else {
+		items = items[offset:]
+	}
+	if limit > 0 && int64(len(items)) > limit {
+		items = items[:limit]
+	} else if pred != nil {
+		pred.Continue = ""
+	}
+	return items, nil
+}
@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.RateLimitStore = &RateLimitStore{}
+
+type RateLimitStore struct {
+	db DBI
+}
+
+func NewRateLimitStore(db DBI) *RateLimitStore {
+	return &RateLimitStore{db: db}
+}
+
+// IncrementRateLimit increments the counter of a key, or resets it when its
+// window has elapsed. When a window starts, the counters of the elapsed
+// windows are removed. Times are stored in unix milliseconds.
+func (s *RateLimitStore) IncrementRateLimit(ctx context.Context, key string, now time.Time, window time.Duration) (int64, error) {
+	var count int64
+	row := s.db.QueryRowContext(ctx, incrementRateLimitQuery, key, now.UnixMilli(), window.Milliseconds())
+	if err := row.Scan(&count); err != nil {
+		return 0, &store.ErrInternal{Message: err.Error()}
+	}
+	if count == 1 {
+		if _, err := s.db.ExecContext(ctx, pruneRateLimitsQuery, now.UnixMilli()); err != nil {
+			return 0, &store.ErrInternal{Message: err.Error()}
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.ResourceHistoryStore = &ResourceHistoryStore{}
+
+type ResourceHistoryStore struct {
+	db DBI
+}
+
+func NewResourceHistoryStore(db DBI) *ResourceHistoryStore {
+	return &ResourceHistoryStore{db: db}
+}
+
+// AddResourceVersion records a version of a resource, unless it did not change
+// since the last version, and removes the versions of the resource that
+// exceed storev2.MaxResourceVersions.
+func (s *ResourceHistoryStore) AddResourceVersion(ctx context.Context, version *storev2.ResourceVersion) error {
+	var resource []byte
+	if !version.Deleted {
+		resource = version.Resource
+	}
+	return withTx(ctx, s.db, func(tx DBI) error {
+		var (
+			last         int64
+			lastDeleted  bool
+			lastResource []byte
+		)
+		row := tx.QueryRowContext(ctx, getLastResourceVersionQuery, version.APIVersion, version.Type, version.Namespace, version.Name)
+		if err := row.Scan(&last, &lastDeleted, &lastResource); err != nil && err != sql.ErrNoRows {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		// The resources are always encoded the same way, so that their JSON
+		// can be compared
+		if last > 0 && lastDeleted == version.Deleted && bytes.Equal(lastResource, resource) {
+			version.Version = last
+			return nil
+		}
+
+		version.Version = last + 1
+		_, err := tx.ExecContext(
+			ctx,
+			addResourceVersionQuery,
+			version.APIVersion, version.Type, version.Namespace, version.Name,
+			version.Version, version.Author, version.Timestamp, version.Deleted, nullableText(resource),
+		)
+		if err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		_, err = tx.ExecContext(
+			ctx,
+			pruneResourceHistoryQuery,
+			version.APIVersion, version.Type, version.Namespace, version.Name,
+			version.Version-storev2.MaxResourceVersions,
+		)
+		if err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		return nil
+	})
+}
+
+// nullableText returns the text of a value, or NULL if it is nil.
+func nullableText(value []byte) sql.NullString {
+	return sql.NullString{String: string(value), Valid: value != nil}
+}
+
+// GetResourceHistory gets the versions of a resource, most recent first.
+func (s *ResourceHistoryStore) GetResourceHistory(ctx context.Context, req storev2.ResourceRequest) ([]*storev2.ResourceVersion, error) {
+	rows, err := s.db.QueryContext(ctx, getResourceHistoryQuery, req.APIVersion, req.Type, req.Namespace, req.Name)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	defer rows.Close()
+	versions := []*storev2.ResourceVersion{}
+	for rows.Next() {
+		version := storev2.ResourceVersion{
+			APIVersion: req.APIVersion,
+			Type:       req.Type,
+			Namespace:  req.Namespace,
+			Name:       req.Name,
+		}
+		var resource sql.NullString
+		if err := rows.Scan(&version.Version, &version.Author, &version.Timestamp, &version.Deleted, &resource); err != nil {
+			return nil, &store.ErrInternal{Message: err.Error()}
+		}
+		if resource.Valid {
+			version.Resource = []byte(resource.String)
+		}
+		versions = append(versions, &version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	return versions, nil
+}
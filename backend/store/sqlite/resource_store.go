@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/store/patch"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/backend/store/v2/wrap"
+)
+
+// resourceStore adapts the generic configuration store to a single resource
+// type, so that namespaces and entities can be stored alongside every other
+// configuration resource.
+type resourceStore[R interface {
+	*T
+	corev3.Resource
+}, T any] struct {
+	config *ConfigStore
+}
+
+func newResourceStore[R interface {
+	*T
+	corev3.Resource
+}, T any](db DBI) resourceStore[R, T] {
+	return resourceStore[R, T]{config: NewConfigStore(db)}
+}
+
+func (s resourceStore[R, T]) request(namespace, name string) storev2.ResourceRequest {
+	req := storev2.NewResourceRequestFromResource(R(new(T)))
+	req.Namespace = namespace
+	req.Name = name
+	return req
+}
+
+func (s resourceStore[R, T]) wrap(resource R) (storev2.ResourceRequest, storev2.Wrapper, error) {
+	if err := wrap.Validate(resource); err != nil {
+		return storev2.ResourceRequest{}, nil, &store.ErrNotValid{Err: err}
+	}
+	meta := resource.GetMetadata()
+	req := s.request(meta.Namespace, meta.Name)
+	wrapper, err := wrap.Resource(resource)
+	if err != nil {
+		return req, nil, &store.ErrEncode{Key: storeKey(req), Err: err}
+	}
+	return req, wrapper, nil
+}
+
+func (s resourceStore[R, T]) CreateOrUpdate(ctx context.Context, resource R) error {
+	req, wrapper, err := s.wrap(resource)
+	if err != nil {
+		return err
+	}
+	return s.config.CreateOrUpdate(ctx, req, wrapper)
+}
+
+func (s resourceStore[R, T]) UpdateIfExists(ctx context.Context, resource R) error {
+	req, wrapper, err := s.wrap(resource)
+	if err != nil {
+		return err
+	}
+	return s.config.UpdateIfExists(ctx, req, wrapper)
+}
+
+func (s resourceStore[R, T]) CreateIfNotExists(ctx context.Context, resource R) error {
+	req, wrapper, err := s.wrap(resource)
+	if err != nil {
+		return err
+	}
+	return s.config.CreateIfNotExists(ctx, req, wrapper)
+}
+
+func (s resourceStore[R, T]) get(ctx context.Context, namespace, name string) (R, error) {
+	wrapper, err := s.config.Get(ctx, s.request(namespace, name))
+	if err != nil {
+		return nil, err
+	}
+	resource := R(new(T))
+	if err := wrapper.UnwrapInto(resource); err != nil {
+		return nil, &store.ErrDecode{Key: storeKey(s.request(namespace, name)), Err: err}
+	}
+	return resource, nil
+}
+
+func (s resourceStore[R, T]) delete(ctx context.Context, namespace, name string) error {
+	return s.config.Delete(ctx, s.request(namespace, name))
+}
+
+func (s resourceStore[R, T]) list(ctx context.Context, namespace string, pred *store.SelectionPredicate) ([]R, error) {
+	list, err := s.config.List(ctx, s.request(namespace, ""), pred)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]R, list.Len())
+	if err := list.UnwrapInto(&resources); err != nil {
+		return nil, &store.ErrDecode{Key: storeKey(s.request(namespace, "")), Err: err}
+	}
+	return resources, nil
+}
+
+func (s resourceStore[R, T]) count(ctx context.Context, namespace string) (int, error) {
+	return s.config.Count(ctx, s.request(namespace, ""))
+}
+
+func (s resourceStore[R, T]) exists(ctx context.Context, namespace, name string) (bool, error) {
+	return s.config.Exists(ctx, s.request(namespace, name))
+}
+
+func (s resourceStore[R, T]) patch(ctx context.Context, namespace, name string, patcher patch.Patcher) error {
+	return s.config.Patch(ctx, s.request(namespace, name), patcher)
+}
+
+func (s resourceStore[R, T]) watch(ctx context.Context, namespace, name string) <-chan []storev2.WatchEvent {
+	return s.config.Watch(ctx, s.request(namespace, name))
+}
@@ -0,0 +1,597 @@
+package sqlite
+
+// migrations contains the data definition language for the sqlite store,
+// in order. Migrations must never be modified once released; add a new one
+// instead. The index of the last applied migration is kept in the database's
+// user_version.
+var migrations = []string{
+	// Migration 1
+	configurationDDL + eventsDDL + silencesDDL,
+	// Migration 2
+	handlerResultsDDL,
+	// Migration 3
+	rateLimitsDDL,
+	// Migration 4
+	auditLogDDL,
+	// Migration 5
+	opcDDL,
+	// Migration 6
+	entityStateHistoryDDL,
+	// Migration 7
+	resourceHistoryDDL,
+	// Migration 8
+	sessionsDDL,
+	// Migration 9
+	mfaDDL,
+	// Migration 10
+	rateLimitsExpirationDDL,
+}
+
+// configurationDDL defines the generic resource table schema. Timestamps are
+// stored as unix nanoseconds. Deleted resources are kept around, with a
+// non-null deleted_at, so that watchers can observe deletions.
+const configurationDDL = `
+CREATE TABLE IF NOT EXISTS configuration (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	api_version TEXT NOT NULL,
+	api_type    TEXT NOT NULL,
+	namespace   TEXT NOT NULL,
+	name        TEXT NOT NULL,
+	labels      TEXT NOT NULL,
+	annotations TEXT NOT NULL,
+	fields      TEXT NOT NULL,
+	resource    TEXT NOT NULL,
+	etag        BLOB NOT NULL,
+	created_at  INTEGER NOT NULL,
+	updated_at  INTEGER NOT NULL,
+	deleted_at  INTEGER
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS configuration_unique
+	ON configuration (api_version, api_type, namespace, name)
+	WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS configuration_updated_at
+	ON configuration (api_version, api_type, updated_at);
+`
+
+const eventsDDL = `
+CREATE TABLE IF NOT EXISTS events (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	namespace   TEXT NOT NULL,
+	entity_name TEXT NOT NULL,
+	check_name  TEXT NOT NULL,
+	selectors   TEXT NOT NULL,
+	serialized  BLOB NOT NULL,
+	UNIQUE (namespace, entity_name, check_name)
+);
+`
+
+const silencesDDL = `
+CREATE TABLE IF NOT EXISTS silences (
+	id                INTEGER PRIMARY KEY AUTOINCREMENT,
+	namespace         TEXT NOT NULL,
+	name              TEXT NOT NULL,
+	labels            TEXT NOT NULL,
+	annotations       TEXT NOT NULL,
+	subscription      TEXT NOT NULL,
+	check_name        TEXT NOT NULL,
+	reason            TEXT NOT NULL,
+	expire_on_resolve INTEGER NOT NULL,
+	begin             INTEGER NOT NULL,
+	expire_at         INTEGER NOT NULL,
+	UNIQUE (namespace, name)
+);
+`
+
+const handlerResultsDDL = `
+CREATE TABLE IF NOT EXISTS handler_results (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	namespace    TEXT NOT NULL,
+	entity_name  TEXT NOT NULL,
+	check_name   TEXT NOT NULL,
+	handler_name TEXT NOT NULL,
+	executed     INTEGER NOT NULL,
+	duration     REAL NOT NULL,
+	status       INTEGER NOT NULL,
+	output       TEXT NOT NULL,
+	error        TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS handler_results_event
+	ON handler_results (namespace, entity_name, check_name, handler_name);
+`
+
+// rateLimitsExpirationDDL adds the end of the windows of the rate limits, in
+// unix milliseconds, after which they are removed. The windows of the existing
+// rate limits are considered elapsed.
+const rateLimitsExpirationDDL = `
+ALTER TABLE rate_limits ADD COLUMN expires_at INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS rate_limits_expires_at ON rate_limits (expires_at);
+`
+
+const rateLimitsDDL = `
+CREATE TABLE IF NOT EXISTS rate_limits (
+	key          TEXT PRIMARY KEY,
+	window_start INTEGER NOT NULL,
+	count        INTEGER NOT NULL
+);
+`
+
+// auditLogDDL defines the table of the audit entries. Timestamps are stored as
+// unix nanoseconds.
+const auditLogDDL = `
+CREATE TABLE IF NOT EXISTS audit_log (
+	id            INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp     INTEGER NOT NULL,
+	request_id    TEXT NOT NULL,
+	username      TEXT NOT NULL,
+	verb          TEXT NOT NULL,
+	api_group     TEXT NOT NULL,
+	api_version   TEXT NOT NULL,
+	namespace     TEXT NOT NULL,
+	resource      TEXT NOT NULL,
+	resource_name TEXT NOT NULL,
+	status        INTEGER NOT NULL,
+	outcome       TEXT NOT NULL,
+	latency       REAL NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_timestamp ON audit_log (timestamp);
+`
+
+// opcDDL defines the table of the operators, which check in to the operator
+// concierge. The controller of an operator is referenced by its key, and
+// times are stored as unix microseconds.
+const opcDDL = `
+CREATE TABLE IF NOT EXISTS opc (
+	id                   INTEGER PRIMARY KEY AUTOINCREMENT,
+	namespace            TEXT NOT NULL,
+	operator_type        INTEGER NOT NULL,
+	operator_name        TEXT NOT NULL,
+	controller_namespace TEXT NOT NULL DEFAULT '',
+	controller_type      INTEGER NOT NULL DEFAULT 0,
+	controller_name      TEXT NOT NULL DEFAULT '',
+	last_update          INTEGER NOT NULL,
+	timeout_micro        INTEGER NOT NULL,
+	present              INTEGER NOT NULL,
+	metadata             TEXT NOT NULL DEFAULT '{}',
+	UNIQUE (namespace, operator_type, operator_name)
+);
+`
+
+// entityStateHistoryDDL defines the table of the snapshots of the systems of
+// the entities. The systems are stored as JSON.
+const entityStateHistoryDDL = `
+CREATE TABLE IF NOT EXISTS entity_state_history (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	namespace   TEXT NOT NULL,
+	entity_name TEXT NOT NULL,
+	timestamp   INTEGER NOT NULL,
+	system      TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS entity_state_history_entity
+	ON entity_state_history (namespace, entity_name);
+`
+
+// resourceHistoryDDL defines the table of the versions of the configuration
+// resources. The resources are stored as JSON, and are null for the versions
+// recording a deletion.
+const resourceHistoryDDL = `
+CREATE TABLE IF NOT EXISTS resource_history (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	api_version TEXT NOT NULL,
+	api_type    TEXT NOT NULL,
+	namespace   TEXT NOT NULL,
+	name        TEXT NOT NULL,
+	version     INTEGER NOT NULL,
+	author      TEXT NOT NULL,
+	timestamp   INTEGER NOT NULL,
+	deleted     INTEGER NOT NULL DEFAULT 0,
+	resource    TEXT,
+	UNIQUE (api_version, api_type, namespace, name, version)
+);
+`
+
+// sessionsDDL defines the tables of the login sessions of the users, and of
+// the refresh tokens revoked. The revoked tokens are kept until they expire.
+// Timestamps are stored as unix seconds.
+const sessionsDDL = `
+CREATE TABLE IF NOT EXISTS sessions (
+	id           TEXT PRIMARY KEY,
+	username     TEXT NOT NULL,
+	provider     TEXT NOT NULL,
+	issued_at    INTEGER NOT NULL,
+	refreshed_at INTEGER NOT NULL,
+	expires_at   INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS sessions_username ON sessions (username);
+CREATE INDEX IF NOT EXISTS sessions_expires_at ON sessions (expires_at);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+	id         TEXT PRIMARY KEY,
+	expires_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS revoked_tokens_expires_at ON revoked_tokens (expires_at);
+`
+
+// mfaDDL defines the tables of the enrollments of the users in the
+// multi-factor authentication, and of their recovery codes, stored as SHA-256
+// hashes. Timestamps are stored as unix seconds.
+const mfaDDL = `
+CREATE TABLE IF NOT EXISTS mfa_enrollments (
+	username     TEXT PRIMARY KEY,
+	secret       TEXT NOT NULL,
+	confirmed    INTEGER NOT NULL DEFAULT 0,
+	created_at   INTEGER NOT NULL,
+	last_counter INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+	username  TEXT NOT NULL,
+	code_hash TEXT NOT NULL,
+	PRIMARY KEY (username, code_hash)
+);
+`
+
+const configColumns = `id, labels, annotations, resource, created_at, updated_at, deleted_at, etag`
+
+const createConfigQuery = `
+INSERT INTO configuration (api_version, api_type, namespace, name, labels, annotations, fields, resource, etag, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
+
+const updateConfigQuery = `
+UPDATE configuration SET labels = ?, annotations = ?, fields = ?, resource = ?, etag = ?, updated_at = ?
+WHERE api_version = ? AND api_type = ? AND namespace = ? AND name = ? AND deleted_at IS NULL;`
+
+const deleteConfigQuery = `
+UPDATE configuration SET deleted_at = ?, updated_at = ?
+WHERE api_version = ? AND api_type = ? AND namespace = ? AND name = ? AND deleted_at IS NULL;`
+
+const getETagQuery = `
+SELECT etag FROM configuration
+WHERE api_version = ? AND api_type = ? AND namespace = ? AND name = ? AND deleted_at IS NULL;`
+
+const getResourceQuery = `
+SELECT resource FROM configuration
+WHERE api_version = ? AND api_type = ? AND namespace = ? AND name = ? AND deleted_at IS NULL;`
+
+const getConfigQuery = `
+SELECT ` + configColumns + ` FROM configuration
+WHERE api_version = ? AND api_type = ? AND namespace = ? AND name = ? AND deleted_at IS NULL;`
+
+// listConfigQuery orders by namespace and name, as the postgres store does,
+// so that offsets are stable. The namespace filter is skipped when the
+// namespace argument is empty.
+const listConfigQuery = `
+SELECT ` + configColumns + `, fields FROM configuration
+WHERE api_version = ? AND api_type = ? AND (? = '' OR namespace = ?)
+	AND (? OR deleted_at IS NULL) AND updated_at > ?
+ORDER BY namespace, name ASC;`
+
+const countConfigQuery = `
+SELECT count(*) FROM configuration
+WHERE api_version = ? AND api_type = ? AND (? = '' OR namespace = ?) AND deleted_at IS NULL;`
+
+const watchConfigQuery = `
+SELECT ` + configColumns + `, namespace, name FROM configuration
+WHERE api_version = ? AND api_type = ? AND updated_at > ?
+ORDER BY updated_at ASC;`
+
+const namespaceResourceCountQuery = `
+SELECT count(*) FROM configuration WHERE namespace = ? AND deleted_at IS NULL;`
+
+const getEventQuery = `
+SELECT serialized FROM events WHERE namespace = ? AND entity_name = ? AND check_name = ?;`
+
+const listEventsQuery = `
+SELECT namespace, entity_name, check_name, serialized, selectors FROM events
+WHERE (? = '' OR namespace = ?) AND (? = '' OR entity_name = ?)
+ORDER BY namespace, entity_name, check_name ASC;`
+
+const createOrUpdateEventQuery = `
+INSERT INTO events (namespace, entity_name, check_name, selectors, serialized)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (namespace, entity_name, check_name) DO UPDATE
+	SET selectors = excluded.selectors, serialized = excluded.serialized;`
+
+const deleteEventQuery = `
+DELETE FROM events WHERE namespace = ? AND entity_name = ? AND check_name = ?;`
+
+const deleteNamespaceEventsQuery = `DELETE FROM events WHERE namespace = ?;`
+
+const pruneEventsQuery = `
+WITH ranked AS (
+	SELECT
+		id,
+		CAST(json_extract(selectors, '$."event.timestamp"') AS INTEGER) AS timestamp,
+		row_number() OVER (
+			ORDER BY CAST(json_extract(selectors, '$."event.timestamp"') AS INTEGER) DESC, id DESC
+		) AS rank
+	FROM events
+	WHERE namespace = ? AND check_name != 'keepalive'
+)
+DELETE FROM events
+WHERE id IN (
+	SELECT id FROM ranked
+	WHERE (? > 0 AND timestamp < ?) OR (? > 0 AND rank > ?)
+);`
+
+const silenceColumns = `namespace, name, labels, annotations, subscription, check_name, reason, expire_on_resolve, begin, expire_at`
+
+const getSilencesQuery = `
+SELECT ` + silenceColumns + ` FROM silences WHERE ? = '' OR namespace = ? ORDER BY namespace, name;`
+
+const getSilencesByCheckQuery = `
+SELECT ` + silenceColumns + ` FROM silences WHERE namespace = ? AND check_name = ? ORDER BY name;`
+
+const getSilenceByNameQuery = `
+SELECT ` + silenceColumns + ` FROM silences WHERE namespace = ? AND name = ?;`
+
+const updateSilenceQuery = `
+INSERT INTO silences (` + silenceColumns + `)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (namespace, name) DO UPDATE SET
+	labels = excluded.labels,
+	annotations = excluded.annotations,
+	subscription = excluded.subscription,
+	check_name = excluded.check_name,
+	reason = excluded.reason,
+	expire_on_resolve = excluded.expire_on_resolve,
+	begin = excluded.begin,
+	expire_at = excluded.expire_at;`
+
+const deleteSilenceQuery = `DELETE FROM silences WHERE namespace = ? AND name = ?;`
+
+const deleteNamespaceSilencesQuery = `DELETE FROM silences WHERE namespace = ?;`
+
+const handlerResultColumns = `namespace, entity_name, check_name, handler_name, executed, duration, status, output, error`
+
+const addHandlerResultQuery = `
+INSERT INTO handler_results (` + handlerResultColumns + `)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);`
+
+const pruneHandlerResultsQuery = `
+DELETE FROM handler_results
+WHERE namespace = ? AND entity_name = ? AND check_name = ? AND handler_name = ?
+	AND id NOT IN (
+		SELECT id FROM handler_results
+		WHERE namespace = ? AND entity_name = ? AND check_name = ? AND handler_name = ?
+		ORDER BY id DESC
+		LIMIT ?
+	);`
+
+const getHandlerResultsQuery = `
+SELECT ` + handlerResultColumns + ` FROM handler_results
+WHERE namespace = ? AND entity_name = ? AND check_name = ?
+ORDER BY id DESC;`
+
+const deleteNamespaceHandlerResultsQuery = `DELETE FROM handler_results WHERE namespace = ?;`
+
+const deleteEntityHandlerResultsQuery = `DELETE FROM handler_results WHERE namespace = ? AND entity_name = ?;`
+
+const incrementRateLimitQuery = `
+INSERT INTO rate_limits (key, window_start, count, expires_at)
+VALUES (?1, ?2, 1, ?2 + ?3)
+ON CONFLICT (key) DO UPDATE SET
+	window_start = CASE WHEN rate_limits.window_start + ?3 <= ?2 THEN ?2 ELSE rate_limits.window_start END,
+	count = CASE WHEN rate_limits.window_start + ?3 <= ?2 THEN 1 ELSE rate_limits.count + 1 END,
+	expires_at = CASE WHEN rate_limits.window_start + ?3 <= ?2 THEN ?2 + ?3 ELSE rate_limits.window_start + ?3 END
+RETURNING count;`
+
+const pruneRateLimitsQuery = `DELETE FROM rate_limits WHERE expires_at <= ?;`
+
+const addAuditEntryQuery = `
+INSERT INTO audit_log (
+	timestamp, request_id, username, verb, api_group, api_version, namespace,
+	resource, resource_name, status, outcome, latency
+)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
+
+const opcColumns = `namespace, operator_type, operator_name, controller_namespace, controller_type, controller_name, present, last_update, timeout_micro, metadata`
+
+const opcCheckInQuery = `
+INSERT INTO opc (` + opcColumns + `)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (namespace, operator_type, operator_name) DO UPDATE SET
+	controller_namespace = excluded.controller_namespace,
+	controller_type = excluded.controller_type,
+	controller_name = excluded.controller_name,
+	present = excluded.present,
+	last_update = excluded.last_update,
+	timeout_micro = excluded.timeout_micro,
+	metadata = excluded.metadata;`
+
+const opcCheckOutQuery = `
+DELETE FROM opc WHERE namespace = ? AND operator_type = ? AND operator_name = ?;`
+
+const opcGetOperatorQuery = `
+SELECT ` + opcColumns + ` FROM opc
+WHERE (?1 = '' OR namespace = ?1) AND operator_type = ?2 AND operator_name = ?3
+LIMIT 1;`
+
+const opcListOperatorsQuery = `
+SELECT ` + opcColumns + ` FROM opc
+WHERE (?1 = '' OR namespace = ?1)
+	AND (?2 = 0 OR operator_type = ?2)
+	AND (?3 = '' OR operator_name = ?3)
+ORDER BY id;`
+
+// opcReassignAbsentControllersQuery gives the operators whose controller is
+// absent, because it checked out or failed to check in, a new controller of
+// the same type, picked at random among the present ones. The operators keep
+// their controller while none is present.
+//
+// ?1: current time (unix microseconds)
+const opcReassignAbsentControllersQuery = `
+UPDATE opc SET (controller_namespace, controller_name) = (
+	SELECT c.namespace, c.operator_name FROM opc AS c
+	WHERE c.operator_type = opc.controller_type
+		AND c.id != opc.id
+		AND c.timeout_micro > ?1 - c.last_update
+	ORDER BY random()
+	LIMIT 1
+)
+WHERE controller_type > 0
+	AND NOT EXISTS (
+		SELECT 1 FROM opc AS c
+		WHERE c.namespace = opc.controller_namespace
+			AND c.operator_type = opc.controller_type
+			AND c.operator_name = opc.controller_name
+			AND c.timeout_micro > ?1 - c.last_update
+	)
+	AND EXISTS (
+		SELECT 1 FROM opc AS c
+		WHERE c.operator_type = opc.controller_type
+			AND c.id != opc.id
+			AND c.timeout_micro > ?1 - c.last_update
+	);`
+
+// opcGetNotificationsQuery gets the operators of a type, controlled by a
+// controller, which have not checked in for longer than their timeout.
+//
+// ?1: operator type
+// ?2: controller namespace
+// ?3: controller type
+// ?4: controller name
+// ?5: current time (unix microseconds)
+const opcGetNotificationsQuery = `
+SELECT id, ` + opcColumns + ` FROM opc
+WHERE operator_type = ?1
+	AND controller_namespace = ?2
+	AND controller_type = ?3
+	AND controller_name = ?4
+	AND timeout_micro < ?5 - last_update;`
+
+// opcGetGrandchildNotificationsQuery is like opcGetNotificationsQuery, for
+// the operators controlled by the operators that the controller controls.
+const opcGetGrandchildNotificationsQuery = `
+SELECT o.id, o.namespace, o.operator_type, o.operator_name, o.controller_namespace,
+	o.controller_type, o.controller_name, o.present, o.last_update, o.timeout_micro, o.metadata
+FROM opc AS o, opc AS child
+WHERE o.operator_type = ?1
+	AND child.controller_namespace = ?2
+	AND child.controller_type = ?3
+	AND child.controller_name = ?4
+	AND o.controller_namespace = child.namespace
+	AND o.controller_type = child.operator_type
+	AND o.controller_name = child.operator_name
+	AND o.timeout_micro < ?5 - o.last_update;`
+
+// opcUpdateNotificationQuery notes that an absent operator is still absent.
+const opcUpdateNotificationQuery = `
+UPDATE opc SET last_update = ?, present = 0 WHERE id = ?;`
+
+const deleteNamespaceOperatorsQuery = `DELETE FROM opc WHERE namespace = ?;`
+
+const getLastEntityStateSnapshotQuery = `
+SELECT system FROM entity_state_history
+WHERE namespace = ? AND entity_name = ?
+ORDER BY id DESC
+LIMIT 1;`
+
+const addEntityStateSnapshotQuery = `
+INSERT INTO entity_state_history (namespace, entity_name, timestamp, system)
+VALUES (?, ?, ?, ?);`
+
+const pruneEntityStateHistoryQuery = `
+DELETE FROM entity_state_history
+WHERE namespace = ? AND entity_name = ?
+	AND id NOT IN (
+		SELECT id FROM entity_state_history
+		WHERE namespace = ? AND entity_name = ?
+		ORDER BY id DESC
+		LIMIT ?
+	);`
+
+const getEntityStateHistoryQuery = `
+SELECT timestamp, system FROM entity_state_history
+WHERE namespace = ? AND entity_name = ?
+ORDER BY id DESC;`
+
+const deleteNamespaceEntityStateHistoryQuery = `DELETE FROM entity_state_history WHERE namespace = ?;`
+
+const getLastResourceVersionQuery = `
+SELECT version, deleted, resource FROM resource_history
+WHERE api_version = ? AND api_type = ? AND namespace = ? AND name = ?
+ORDER BY version DESC
+LIMIT 1;`
+
+const addResourceVersionQuery = `
+INSERT INTO resource_history (api_version, api_type, namespace, name, version, author, timestamp, deleted, resource)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);`
+
+const pruneResourceHistoryQuery = `
+DELETE FROM resource_history
+WHERE api_version = ? AND api_type = ? AND namespace = ? AND name = ? AND version <= ?;`
+
+const getResourceHistoryQuery = `
+SELECT version, author, timestamp, deleted, resource FROM resource_history
+WHERE api_version = ? AND api_type = ? AND namespace = ? AND name = ?
+ORDER BY version DESC;`
+
+const deleteNamespaceResourceHistoryQuery = `DELETE FROM resource_history WHERE namespace = ?;`
+
+const addSessionQuery = `
+INSERT INTO sessions (id, username, provider, issued_at, refreshed_at, expires_at)
+VALUES (?, ?, ?, ?, ?, ?);`
+
+const pruneSessionsQuery = `DELETE FROM sessions WHERE expires_at <= ?;`
+
+const pruneRevokedTokensQuery = `DELETE FROM revoked_tokens WHERE expires_at <= ?;`
+
+const refreshSessionQuery = `
+UPDATE sessions SET refreshed_at = ?2, expires_at = ?3
+WHERE id = ?1 AND expires_at > ?2;`
+
+const getSessionsQuery = `
+SELECT id, username, provider, issued_at, refreshed_at, expires_at
+FROM sessions
+WHERE username = ? AND expires_at > ?
+ORDER BY issued_at DESC, id;`
+
+const revokeSessionQuery = `
+INSERT INTO revoked_tokens (id, expires_at)
+SELECT id, expires_at FROM sessions WHERE username = ?1 AND id = ?2
+ON CONFLICT (id) DO UPDATE SET expires_at = max(revoked_tokens.expires_at, excluded.expires_at);`
+
+const deleteSessionQuery = `DELETE FROM sessions WHERE username = ? AND id = ?;`
+
+const revokeUserSessionsQuery = `
+INSERT INTO revoked_tokens (id, expires_at)
+SELECT id, expires_at FROM sessions WHERE username = ?1
+ON CONFLICT (id) DO UPDATE SET expires_at = max(revoked_tokens.expires_at, excluded.expires_at);`
+
+const deleteUserSessionsQuery = `DELETE FROM sessions WHERE username = ?;`
+
+const isTokenRevokedQuery = `SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE id = ?);`
+
+const putMFAEnrollmentQuery = `
+INSERT INTO mfa_enrollments (username, secret, confirmed, created_at, last_counter)
+VALUES (?, ?, 0, ?, 0)
+ON CONFLICT (username) DO UPDATE
+	SET secret = excluded.secret, confirmed = 0, created_at = excluded.created_at, last_counter = 0
+	WHERE NOT mfa_enrollments.confirmed;`
+
+const addMFARecoveryCodeQuery = `INSERT INTO mfa_recovery_codes (username, code_hash) VALUES (?, ?);`
+
+const deleteMFARecoveryCodesQuery = `DELETE FROM mfa_recovery_codes WHERE username = ?;`
+
+const getMFAEnrollmentQuery = `
+SELECT username, secret, confirmed, created_at, last_counter
+FROM mfa_enrollments
+WHERE username = ?;`
+
+const confirmMFAEnrollmentQuery = `UPDATE mfa_enrollments SET confirmed = 1 WHERE username = ?;`
+
+const useMFACounterQuery = `
+UPDATE mfa_enrollments SET last_counter = ?2
+WHERE username = ?1 AND last_counter < ?2;`
+
+const useMFARecoveryCodeQuery = `DELETE FROM mfa_recovery_codes WHERE username = ? AND code_hash = ?;`
+
+const deleteMFAEnrollmentQuery = `DELETE FROM mfa_enrollments WHERE username = ?;`
@@ -0,0 +1,136 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.SessionStore = &SessionStore{}
+
+type SessionStore struct {
+	db DBI
+}
+
+func NewSessionStore(db DBI) *SessionStore {
+	return &SessionStore{db: db}
+}
+
+// AddSession records a new session, and removes the sessions and the revoked
+// tokens expired at the time the session was issued.
+func (s *SessionStore) AddSession(ctx context.Context, session *storev2.Session) error {
+	return withTx(ctx, s.db, func(tx DBI) error {
+		_, err := tx.ExecContext(
+			ctx,
+			addSessionQuery,
+			session.ID,
+			session.Username,
+			session.Provider,
+			session.IssuedAt,
+			session.RefreshedAt,
+			session.ExpiresAt,
+		)
+		if err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		if _, err := tx.ExecContext(ctx, pruneSessionsQuery, session.IssuedAt); err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		if _, err := tx.ExecContext(ctx, pruneRevokedTokensQuery, session.IssuedAt); err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		return nil
+	})
+}
+
+// RefreshSession records the renewal of the refresh token of a session.
+func (s *SessionStore) RefreshSession(ctx context.Context, id string, refreshedAt, expiresAt int64) error {
+	result, err := s.db.ExecContext(ctx, refreshSessionQuery, id, refreshedAt, expiresAt)
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return &store.ErrInternal{Message: err.Error()}
+	}
+	if affected == 0 {
+		return &store.ErrNotFound{Key: id}
+	}
+	return nil
+}
+
+// GetSessions gets the unexpired sessions of a user, most recent first.
+func (s *SessionStore) GetSessions(ctx context.Context, username string, now int64) ([]*storev2.Session, error) {
+	rows, err := s.db.QueryContext(ctx, getSessionsQuery, username, now)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	defer rows.Close()
+	sessions := []*storev2.Session{}
+	for rows.Next() {
+		var session storev2.Session
+		err := rows.Scan(
+			&session.ID,
+			&session.Username,
+			&session.Provider,
+			&session.IssuedAt,
+			&session.RefreshedAt,
+			&session.ExpiresAt,
+		)
+		if err != nil {
+			return nil, &store.ErrInternal{Message: err.Error()}
+		}
+		sessions = append(sessions, &session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	return sessions, nil
+}
+
+// RevokeSessions removes the given sessions of a user, or all of them, and
+// revokes their refresh tokens.
+func (s *SessionStore) RevokeSessions(ctx context.Context, username string, ids ...string) (int, error) {
+	var revoked int64
+	err := withTx(ctx, s.db, func(tx DBI) error {
+		if len(ids) == 0 {
+			n, err := revokeSessions(ctx, tx, revokeUserSessionsQuery, deleteUserSessionsQuery, username)
+			revoked = n
+			return err
+		}
+		for _, id := range ids {
+			n, err := revokeSessions(ctx, tx, revokeSessionQuery, deleteSessionQuery, username, id)
+			if err != nil {
+				return err
+			}
+			revoked += n
+		}
+		return nil
+	})
+	return int(revoked), err
+}
+
+func revokeSessions(ctx context.Context, tx DBI, revokeQuery, deleteQuery string, args ...interface{}) (int64, error) {
+	if _, err := tx.ExecContext(ctx, revokeQuery, args...); err != nil {
+		return 0, &store.ErrInternal{Message: err.Error()}
+	}
+	result, err := tx.ExecContext(ctx, deleteQuery, args...)
+	if err != nil {
+		return 0, &store.ErrInternal{Message: err.Error()}
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, &store.ErrInternal{Message: err.Error()}
+	}
+	return n, nil
+}
+
+// IsTokenRevoked returns whether a refresh token was revoked.
+func (s *SessionStore) IsTokenRevoked(ctx context.Context, id string) (bool, error) {
+	var revoked bool
+	if err := s.db.QueryRowContext(ctx, isTokenRevokedQuery, id).Scan(&revoked); err != nil {
+		return false, &store.ErrInternal{Message: err.Error()}
+	}
+	return revoked, nil
+}
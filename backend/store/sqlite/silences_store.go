@@ -0,0 +1,173 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+var _ storev2.SilencesStore = &SilenceStore{}
+
+type SilenceStore struct {
+	db DBI
+}
+
+func NewSilenceStore(db DBI) *SilenceStore {
+	return &SilenceStore{db: db}
+}
+
+type scanFunc func(...interface{}) error
+
+func readSilence(sf scanFunc) (*corev2.Silenced, error) {
+	var (
+		result              corev2.Silenced
+		labels, annotations string
+	)
+	err := sf(
+		&result.ObjectMeta.Namespace,
+		&result.ObjectMeta.Name,
+		&labels,
+		&annotations,
+		&result.Subscription,
+		&result.Check,
+		&result.Reason,
+		&result.ExpireOnResolve,
+		&result.Begin,
+		&result.ExpireAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(labels), &result.ObjectMeta.Labels); err != nil {
+		return nil, &store.ErrDecode{Key: result.Name, Err: err}
+	}
+	if err := json.Unmarshal([]byte(annotations), &result.ObjectMeta.Annotations); err != nil {
+		return nil, &store.ErrDecode{Key: result.Name, Err: err}
+	}
+	return &result, nil
+}
+
+func (s *SilenceStore) query(ctx context.Context, query string, args ...interface{}) ([]*corev2.Silenced, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	defer rows.Close()
+	result := []*corev2.Silenced{}
+	for rows.Next() {
+		silenced, err := readSilence(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, silenced)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	return result, nil
+}
+
+func (s *SilenceStore) GetSilences(ctx context.Context, namespace string) ([]*corev2.Silenced, error) {
+	return s.query(ctx, getSilencesQuery, namespace, namespace)
+}
+
+func (s *SilenceStore) GetSilencesByCheck(ctx context.Context, namespace, check string) ([]*corev2.Silenced, error) {
+	return s.query(ctx, getSilencesByCheckQuery, namespace, check)
+}
+
+func (s *SilenceStore) GetSilencesBySubscription(ctx context.Context, namespace string, subscriptions []string) ([]*corev2.Silenced, error) {
+	if len(subscriptions) == 0 {
+		return []*corev2.Silenced{}, nil
+	}
+	query, args := inQuery(fmt.Sprintf("SELECT %s FROM silences WHERE namespace = ? AND subscription", silenceColumns), namespace, subscriptions)
+	return s.query(ctx, query+" ORDER BY name;", args...)
+}
+
+func (s *SilenceStore) GetSilenceByName(ctx context.Context, namespace, name string) (*corev2.Silenced, error) {
+	row := s.db.QueryRowContext(ctx, getSilenceByNameQuery, namespace, name)
+	silenced, err := readSilence(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, &store.ErrNotFound{Key: store.NewKeyBuilder("silenced").WithNamespace(namespace).Build(name)}
+	}
+	return silenced, err
+}
+
+func (s *SilenceStore) GetSilencesByName(ctx context.Context, namespace string, names []string) ([]*corev2.Silenced, error) {
+	if len(names) == 0 {
+		return []*corev2.Silenced{}, nil
+	}
+	query, args := inQuery(fmt.Sprintf("SELECT %s FROM silences WHERE namespace = ? AND name", silenceColumns), namespace, names)
+	return s.query(ctx, query+" ORDER BY name;", args...)
+}
+
+// UpdateSilence creates or updates a silence. The namespace of the silence
+// must exist.
+func (s *SilenceStore) UpdateSilence(ctx context.Context, si *corev2.Silenced) error {
+	labels, err := mapToJSON(si.Labels)
+	if err != nil {
+		return &store.ErrEncode{Key: si.Name, Err: err}
+	}
+	annotations, err := mapToJSON(si.Annotations)
+	if err != nil {
+		return &store.ErrEncode{Key: si.Name, Err: err}
+	}
+	return withTx(ctx, s.db, func(tx DBI) error {
+		exists, err := NewNamespaceStore(tx).Exists(ctx, si.Namespace)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return &store.ErrNamespaceMissing{Namespace: si.Namespace}
+		}
+		_, err = tx.ExecContext(
+			ctx,
+			updateSilenceQuery,
+			si.Namespace,
+			si.Name,
+			labels,
+			annotations,
+			si.Subscription,
+			si.Check,
+			si.Reason,
+			si.ExpireOnResolve,
+			si.Begin,
+			si.ExpireAt,
+		)
+		if err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		return nil
+	})
+}
+
+func (s *SilenceStore) DeleteSilences(ctx context.Context, namespace string, names []string) error {
+	return withTx(ctx, s.db, func(tx DBI) error {
+		for _, name := range names {
+			if _, err := tx.ExecContext(ctx, deleteSilenceQuery, namespace, name); err != nil {
+				return &store.ErrInternal{Message: err.Error()}
+			}
+		}
+		return nil
+	})
+}
+
+// inQuery appends an IN clause for values to the query prefix. sqlite has no
+// array type, so each value is bound to its own placeholder.
+func inQuery(prefix string, namespace string, values []string) (string, []interface{}) {
+	args := make([]interface{}, 0, len(values)+1)
+	args = append(args, namespace)
+	placeholders := make([]byte, 0, len(values)*2)
+	for i, value := range values {
+		if i > 0 {
+			placeholders = append(placeholders, ',')
+		}
+		placeholders = append(placeholders, '?')
+		args = append(args, value)
+	}
+	return fmt.Sprintf("%s IN (%s)", prefix, placeholders), args
+}
@@ -0,0 +1,107 @@
+// Package sqlite provides a storev2 implementation backed by an embedded
+// SQLite database. It is intended for single-node deployments with low
+// resource budgets, such as edge installs, where running a database server is
+// not practical. Without postgres, it also keeps the state of the operators of
+// the backend.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// Type is the type of a sqlite store provider.
+const Type = "sqlite"
+
+// DBI is the subset of database/sql shared by *sql.DB and *sql.Tx that the
+// store uses.
+type DBI interface {
+	ExecContext(context.Context, string, ...any) (sql.Result, error)
+	QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...any) *sql.Row
+}
+
+// Open opens the sqlite database at the given path, and applies any pending
+// migrations.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", dsn(path))
+	if err != nil {
+		return nil, fmt.Errorf("error opening sqlite database: %s", err)
+	}
+	// SQLite only supports a single writer. Using a single connection
+	// serializes access to the database, avoiding SQLITE_BUSY errors, and
+	// ensures that in-memory databases are shared by all store clients.
+	db.SetMaxOpenConns(1)
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("error opening sqlite database: %s", err)
+	}
+	if err := migrate(ctx, db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("error migrating sqlite database: %s", err)
+	}
+	return db, nil
+}
+
+func dsn(path string) string {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	if path != ":memory:" {
+		params.Add("_pragma", "journal_mode(WAL)")
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("file:%s%s%s", path, sep, params.Encode())
+}
+
+// migrate applies the migrations in order, starting after the migration
+// recorded in the database's user_version.
+func migrate(ctx context.Context, db *sql.DB) error {
+	var version int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version;").Scan(&version); err != nil {
+		return err
+	}
+	for i := version; i < len(migrations); i++ {
+		err := withTx(ctx, db, func(tx DBI) error {
+			if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d;", i+1))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d: %s", i+1, err)
+		}
+		logger.WithField("version", i+1).Info("applied sqlite migration")
+	}
+	return nil
+}
+
+// withTx runs fn inside a transaction. If db is already a transaction, fn is
+// run as part of it.
+func withTx(ctx context.Context, db DBI, fn func(DBI) error) (err error) {
+	sqlDB, ok := db.(*sql.DB)
+	if !ok {
+		return fn(db)
+	}
+	tx, err := sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+	return fn(tx)
+}
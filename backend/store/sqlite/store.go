@@ -0,0 +1,704 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	apitools "github.com/sensu/sensu-api-tools"
+	"github.com/sensu/sensu-go/backend/messaging"
+	"github.com/sensu/sensu-go/backend/store"
+	"github.com/sensu/sensu-go/backend/store/memory"
+	"github.com/sensu/sensu-go/backend/store/patch"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/backend/store/v2/wrap"
+	"golang.org/x/time/rate"
+)
+
+var _ storev2.Interface = &Store{}
+
+type StoreConfig struct {
+	DB                DBI
+	MaxTPS            int
+	WatchInterval     time.Duration
+	Bus               messaging.MessageBus
+	DisableEventCache bool
+}
+
+func NewStore(cfg StoreConfig) *Store {
+	return &Store{
+		db:                cfg.DB,
+		watchInterval:     cfg.WatchInterval,
+		maxTPS:            cfg.MaxTPS,
+		bus:               cfg.Bus,
+		disableEventCache: cfg.DisableEventCache,
+	}
+}
+
+// Store is a storev2.Interface implementation backed by sqlite. Config
+// resources, including namespaces and entities, live in a single generic
+// configuration table. Events and silences have their own tables.
+type Store struct {
+	db                DBI
+	watchInterval     time.Duration
+	eventStore        store.EventStore
+	maxTPS            int
+	once              sync.Once
+	bus               messaging.MessageBus
+	disableEventCache bool
+}
+
+func (s *Store) GetConfigStore() storev2.ConfigStore {
+	return &ConfigStore{
+		db:            s.db,
+		watchInterval: s.watchInterval,
+	}
+}
+
+func (s *Store) GetEntityConfigStore() storev2.EntityConfigStore {
+	return NewEntityConfigStore(s.db)
+}
+
+func (s *Store) GetEntityStateStore() storev2.EntityStateStore {
+	return NewEntityStateStore(s.db)
+}
+
+func (s *Store) GetNamespaceStore() storev2.NamespaceStore {
+	return NewNamespaceStore(s.db)
+}
+
+// legacy
+func (s *Store) GetEventStore() store.EventStore {
+	s.once.Do(func() {
+		sstore := s.GetSilencesStore()
+		eventStore := NewEventStore(s.db, sstore)
+		if s.disableEventCache || s.bus == nil {
+			s.eventStore = eventStore
+			return
+		}
+		cfg := memory.EventStoreConfig{
+			BackingStore:    eventStore,
+			FlushInterval:   time.Second,
+			EventWriteLimit: rate.Limit(s.maxTPS),
+			SilenceStore:    sstore,
+			Bus:             s.bus,
+		}
+		memstore := memory.NewEventStore(cfg)
+		memstore.Start(context.Background())
+		s.eventStore = memstore
+	})
+	return s.eventStore
+}
+
+// legacy
+func (s *Store) GetEntityStore() store.EntityStore {
+	return NewEntityStore(s.db)
+}
+
+func (s *Store) GetSilencesStore() storev2.SilencesStore {
+	return NewSilenceStore(s.db)
+}
+
+func (s *Store) GetHandlerResultStore() storev2.HandlerResultStore {
+	return NewHandlerResultStore(s.db)
+}
+
+func (s *Store) GetEntityStateHistoryStore() storev2.EntityStateHistoryStore {
+	return NewEntityStateHistoryStore(s.db)
+}
+
+func (s *Store) GetResourceHistoryStore() storev2.ResourceHistoryStore {
+	return NewResourceHistoryStore(s.db)
+}
+
+func (s *Store) GetRateLimitStore() storev2.RateLimitStore {
+	return NewRateLimitStore(s.db)
+}
+
+func (s *Store) GetAuditStore() storev2.AuditStore {
+	return NewAuditStore(s.db)
+}
+
+func (s *Store) GetSessionStore() storev2.SessionStore {
+	return NewSessionStore(s.db)
+}
+
+func (s *Store) GetMFAStore() storev2.MFAStore {
+	return NewMFAStore(s.db)
+}
+
+// ConfigStore stores wrapped resources in the generic configuration table.
+type ConfigStore struct {
+	db            DBI
+	watchInterval time.Duration
+}
+
+func NewConfigStore(db DBI) *ConfigStore {
+	return &ConfigStore{
+		db: db,
+	}
+}
+
+type configRecord struct {
+	id          int64
+	labels      string
+	annotations string
+	resource    string
+	createdAt   int64
+	updatedAt   int64
+	deletedAt   sql.NullInt64
+	etag        []byte
+}
+
+func (r *configRecord) scanArgs() []interface{} {
+	return []interface{}{&r.id, &r.labels, &r.annotations, &r.resource, &r.createdAt, &r.updatedAt, &r.deletedAt, &r.etag}
+}
+
+func (r *configRecord) wrapper(apiVersion, apiType string) *wrap.Wrapper {
+	w := &wrap.Wrapper{
+		TypeMeta:    &corev2.TypeMeta{APIVersion: apiVersion, Type: apiType},
+		Encoding:    wrap.Encoding_json,
+		Compression: wrap.Compression_none,
+		Value:       []byte(r.resource),
+		CreatedAt:   time.Unix(0, r.createdAt),
+		UpdatedAt:   time.Unix(0, r.updatedAt),
+		ETag:        storev2.ETag(r.etag).String(),
+	}
+	if r.deletedAt.Valid {
+		w.DeletedAt = time.Unix(0, r.deletedAt.Int64)
+	}
+	return w
+}
+
+func requestKey(req storev2.ResourceRequest) string {
+	return fmt.Sprintf("%s.%s/%s/%s", req.APIVersion, req.Type, req.Namespace, req.Name)
+}
+
+func (s *ConfigStore) Initialize(ctx context.Context, fn storev2.InitializeFunc) error {
+	return withTx(ctx, s.db, func(tx DBI) error {
+		return fn(ctx, &Store{db: tx, disableEventCache: true})
+	})
+}
+
+func (s *ConfigStore) Watch(ctx context.Context, req storev2.ResourceRequest) <-chan []storev2.WatchEvent {
+	if req.APIVersion == "" || req.Type == "" {
+		return nil
+	}
+	return newWatcher(s.db, s.watchInterval).Watch(ctx, req)
+}
+
+// getETag returns the etag of the live resource, or nil if it doesn't exist.
+func getETag(ctx context.Context, db DBI, apiVersion, apiType, namespace, name string) (storev2.ETag, error) {
+	var etag []byte
+	row := db.QueryRowContext(ctx, getETagQuery, apiVersion, apiType, namespace, name)
+	if err := row.Scan(&etag); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	return storev2.ETag(etag), nil
+}
+
+func recordTx(ctx context.Context, record storev2.TxRecordInfo) {
+	if txInfo := storev2.TxInfoFromContext(ctx); txInfo != nil {
+		txInfo.Records = append(txInfo.Records, record)
+	}
+}
+
+func (s *ConfigStore) CreateOrUpdate(ctx context.Context, request storev2.ResourceRequest, wrapper storev2.Wrapper) error {
+	if err := request.Validate(); err != nil {
+		return &store.ErrNotValid{Err: err}
+	}
+	if storev2.IfMatchFromContext(ctx) != nil {
+		return &store.ErrNotValid{Err: errors.New("can't use IfMatch with this method")}
+	}
+	ifNoneMatch := storev2.IfNoneMatchFromContext(ctx)
+
+	data, err := extractResourceData(wrapper)
+	if err != nil {
+		return err
+	}
+	meta, typeMeta := data.Metadata, data.TypeMeta
+
+	return withTx(ctx, s.db, func(tx DBI) error {
+		prevETag, err := getETag(ctx, tx, typeMeta.APIVersion, typeMeta.Type, meta.Namespace, meta.Name)
+		if err != nil {
+			return err
+		}
+		if prevETag != nil && ifNoneMatch != nil && !ifNoneMatch.Matches(prevETag) {
+			return &store.ErrPreconditionFailed{Key: prevETag.String()}
+		}
+		now := time.Now().UnixNano()
+		if prevETag == nil {
+			_, err = tx.ExecContext(ctx, createConfigQuery, typeMeta.APIVersion, typeMeta.Type, meta.Namespace, meta.Name, data.Labels, data.Annotations, data.Fields, data.Resource, []byte(data.ETag), now, now)
+		} else {
+			_, err = tx.ExecContext(ctx, updateConfigQuery, data.Labels, data.Annotations, data.Fields, data.Resource, []byte(data.ETag), now, typeMeta.APIVersion, typeMeta.Type, meta.Namespace, meta.Name)
+		}
+		if err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		record := storev2.TxRecordInfo{ETag: data.ETag}
+		if prevETag == nil {
+			record.Created = true
+		} else {
+			// it's only updated if the etag changed
+			record.Updated = !data.ETag.Equals(prevETag)
+			record.PrevETag = prevETag
+		}
+		recordTx(ctx, record)
+		return nil
+	})
+}
+
+// BatchCreateOrUpdate creates or updates the wrapped resources in a single
+// transaction.
+func (s *ConfigStore) BatchCreateOrUpdate(ctx context.Context, requests []storev2.ResourceRequest, wrappers []storev2.Wrapper) error {
+	if len(requests) != len(wrappers) {
+		return &store.ErrNotValid{Err: errors.New("the number of requests and resources differ")}
+	}
+	return withTx(ctx, s.db, func(tx DBI) error {
+		txStore := &ConfigStore{db: tx, watchInterval: s.watchInterval}
+		for i, request := range requests {
+			if err := txStore.CreateOrUpdate(ctx, request, wrappers[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *ConfigStore) UpdateIfExists(ctx context.Context, request storev2.ResourceRequest, wrapper storev2.Wrapper) error {
+	if err := request.Validate(); err != nil {
+		return &store.ErrNotValid{Err: err}
+	}
+
+	data, err := extractResourceData(wrapper)
+	if err != nil {
+		return err
+	}
+	meta, typeMeta := data.Metadata, data.TypeMeta
+
+	return withTx(ctx, s.db, func(tx DBI) error {
+		prevETag, err := getETag(ctx, tx, typeMeta.APIVersion, typeMeta.Type, meta.Namespace, meta.Name)
+		if err != nil {
+			return err
+		}
+		if prevETag == nil {
+			return &store.ErrNotFound{Key: requestKey(request)}
+		}
+		if err := checkPreconditions(ctx, request, prevETag); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, updateConfigQuery, data.Labels, data.Annotations, data.Fields, data.Resource, []byte(data.ETag), time.Now().UnixNano(), typeMeta.APIVersion, typeMeta.Type, meta.Namespace, meta.Name)
+		if err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		recordTx(ctx, storev2.TxRecordInfo{Updated: true, PrevETag: prevETag, ETag: data.ETag})
+		return nil
+	})
+}
+
+func (s *ConfigStore) CreateIfNotExists(ctx context.Context, request storev2.ResourceRequest, wrapper storev2.Wrapper) error {
+	if err := request.Validate(); err != nil {
+		return &store.ErrNotValid{Err: err}
+	}
+
+	data, err := extractResourceData(wrapper)
+	if err != nil {
+		return err
+	}
+	meta, typeMeta := data.Metadata, data.TypeMeta
+
+	return withTx(ctx, s.db, func(tx DBI) error {
+		prevETag, err := getETag(ctx, tx, typeMeta.APIVersion, typeMeta.Type, meta.Namespace, meta.Name)
+		if err != nil {
+			return err
+		}
+		if prevETag != nil {
+			return &store.ErrAlreadyExists{Key: fmt.Sprintf("%s.%s/%s/%s", typeMeta.APIVersion, typeMeta.Type, request.Namespace, request.Name)}
+		}
+		now := time.Now().UnixNano()
+		_, err = tx.ExecContext(ctx, createConfigQuery, typeMeta.APIVersion, typeMeta.Type, meta.Namespace, meta.Name, data.Labels, data.Annotations, data.Fields, data.Resource, []byte(data.ETag), now, now)
+		if err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		recordTx(ctx, storev2.TxRecordInfo{Created: true, ETag: data.ETag})
+		return nil
+	})
+}
+
+// checkPreconditions verifies the IfMatch and IfNoneMatch conditions of the
+// context, if any, against the etag of an existing resource.
+func checkPreconditions(ctx context.Context, request storev2.ResourceRequest, etag storev2.ETag) error {
+	ifMatch := storev2.IfMatchFromContext(ctx)
+	ifNoneMatch := storev2.IfNoneMatchFromContext(ctx)
+
+	if ifMatch != nil {
+		if ifMatch.Matches(etag) {
+			return nil
+		}
+		return &store.ErrPreconditionFailed{Key: requestKey(request)}
+	}
+	if ifNoneMatch != nil && !ifNoneMatch.Matches(etag) {
+		return &store.ErrPreconditionFailed{Key: requestKey(request)}
+	}
+	return nil
+}
+
+// checkGetPreconditions is like checkPreconditions, but looks up the etag of
+// the requested resource first. It is a no-op when the context has no
+// conditions.
+func checkGetPreconditions(ctx context.Context, db DBI, request storev2.ResourceRequest) error {
+	if storev2.IfMatchFromContext(ctx) == nil && storev2.IfNoneMatchFromContext(ctx) == nil {
+		return nil
+	}
+	etag, err := getETag(ctx, db, request.APIVersion, request.Type, request.Namespace, request.Name)
+	if err != nil {
+		return err
+	}
+	if etag == nil {
+		return &store.ErrNotFound{Key: requestKey(request)}
+	}
+	return checkPreconditions(ctx, request, etag)
+}
+
+func (s *ConfigStore) Get(ctx context.Context, request storev2.ResourceRequest) (storev2.Wrapper, error) {
+	if err := request.Validate(); err != nil {
+		return nil, &store.ErrNotValid{Err: err}
+	}
+
+	var rec configRecord
+	err := withTx(ctx, s.db, func(tx DBI) error {
+		if err := checkGetPreconditions(ctx, tx, request); err != nil {
+			return err
+		}
+		row := tx.QueryRowContext(ctx, getConfigQuery, request.APIVersion, request.Type, request.Namespace, request.Name)
+		if err := row.Scan(rec.scanArgs()...); err != nil {
+			if err == sql.ErrNoRows {
+				return &store.ErrNotFound{Key: requestKey(request)}
+			}
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec.wrapper(request.APIVersion, request.Type), nil
+}
+
+func (s *ConfigStore) Delete(ctx context.Context, request storev2.ResourceRequest) error {
+	if err := request.Validate(); err != nil {
+		return &store.ErrNotValid{Err: err}
+	}
+
+	return withTx(ctx, s.db, func(tx DBI) error {
+		if err := checkGetPreconditions(ctx, tx, request); err != nil {
+			return err
+		}
+		now := time.Now().UnixNano()
+		result, err := tx.ExecContext(ctx, deleteConfigQuery, now, now, request.APIVersion, request.Type, request.Namespace, request.Name)
+		if err != nil {
+			return &store.ErrInternal{Message: err.Error()}
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return &store.ErrNotFound{Key: requestKey(request)}
+		}
+		recordTx(ctx, storev2.TxRecordInfo{Deleted: true})
+		return nil
+	})
+}
+
+// list returns the records matching the request, the selector from the
+// context and the updatedSince/includeDeletes settings of the predicate.
+// Selectors are evaluated in the store process, rather than in SQL.
+func (s *ConfigStore) list(ctx context.Context, request storev2.ResourceRequest, pred *store.SelectionPredicate) ([]configRecord, error) {
+	var (
+		updatedSince   time.Time
+		includeDeletes bool
+	)
+	if pred != nil {
+		includeDeletes = pred.IncludeDeletes
+		if pred.UpdatedSince != "" {
+			if err := updatedSince.UnmarshalText([]byte(pred.UpdatedSince)); err != nil {
+				return nil, &store.ErrNotValid{Err: fmt.Errorf("bad UpdatedSince time: %s", err)}
+			}
+		}
+	}
+	var since int64
+	if !updatedSince.IsZero() {
+		since = updatedSince.UnixNano()
+	}
+
+	sel := storev2.SelectorFromContext(ctx, corev2.TypeMeta{APIVersion: request.APIVersion, Type: request.Type})
+
+	rows, err := s.db.QueryContext(ctx, listConfigQuery, request.APIVersion, request.Type, request.Namespace, request.Namespace, includeDeletes, since)
+	if err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	defer rows.Close()
+
+	var records []configRecord
+	for rows.Next() {
+		var (
+			rec    configRecord
+			fields string
+		)
+		if err := rows.Scan(append(rec.scanArgs(), &fields)...); err != nil {
+			return nil, &store.ErrInternal{Message: err.Error()}
+		}
+		if sel != nil && len(sel.Operations) > 0 {
+			set, err := selectorSet(rec.labels, fields)
+			if err != nil {
+				return nil, &store.ErrDecode{Key: requestKey(request), Err: err}
+			}
+			if !sel.Matches(set) {
+				continue
+			}
+		}
+		records = append(records, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &store.ErrInternal{Message: err.Error()}
+	}
+	return records, nil
+}
+
+func (s *ConfigStore) List(ctx context.Context, request storev2.ResourceRequest, pred *store.SelectionPredicate) (storev2.WrapList, error) {
+	if err := request.Validate(); err != nil {
+		return nil, &store.ErrNotValid{Err: err}
+	}
+
+	if pred != nil && pred.Ordering != "" && pred.Ordering != corev2.EntitySortName {
+		return nil, &store.ErrNotValid{Err: fmt.Errorf("unknown ordering requested: %s", pred.Ordering)}
+	}
+	records, err := s.list(ctx, request, pred)
+	if err != nil {
+		return nil, err
+	}
+	if pred != nil && pred.Descending {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+	records, err = paginate(records, pred)
+	if err != nil {
+		return nil, err
+	}
+
+	wrapList := make(wrap.List, 0, len(records))
+	for i := range records {
+		wrapList = append(wrapList, records[i].wrapper(request.APIVersion, request.Type))
+	}
+	return wrapList, nil
+}
+
+func (s *ConfigStore) Count(ctx context.Context, request storev2.ResourceRequest) (int, error) {
+	if err := request.Validate(); err != nil {
+		return 0, &store.ErrNotValid{Err: err}
+	}
+	// The selectors are matched against the decoded records
+	if sel := storev2.SelectorFromContext(ctx, corev2.TypeMeta{APIVersion: request.APIVersion, Type: request.Type}); sel != nil && len(sel.Operations) > 0 {
+		records, err := s.list(ctx, request, nil)
+		if err != nil {
+			return 0, err
+		}
+		return len(records), nil
+	}
+	var count int
+	row := s.db.QueryRowContext(ctx, countConfigQuery, request.APIVersion, request.Type, request.Namespace, request.Namespace)
+	if err := row.Scan(&count); err != nil {
+		return 0, &store.ErrInternal{Message: err.Error()}
+	}
+	return count, nil
+}
+
+func (s *ConfigStore) Exists(ctx context.Context, request storev2.ResourceRequest) (bool, error) {
+	if err := request.Validate(); err != nil {
+		return false, &store.ErrNotValid{Err: err}
+	}
+
+	etag, err := getETag(ctx, s.db, request.APIVersion, request.Type, request.Namespace, request.Name)
+	if err != nil {
+		return false, err
+	}
+	if etag == nil {
+		return false, nil
+	}
+	if ifMatch := storev2.IfMatchFromContext(ctx); ifMatch != nil {
+		return ifMatch.Matches(etag), nil
+	}
+	if ifNoneMatch := storev2.IfNoneMatchFromContext(ctx); ifNoneMatch != nil {
+		return ifNoneMatch.Matches(etag), nil
+	}
+	return true, nil
+}
+
+// BatchExists returns whether each of the resources indicated by the requests
+// exists.
+func (s *ConfigStore) BatchExists(ctx context.Context, requests []storev2.ResourceRequest) ([]bool, error) {
+	if storev2.IfMatchFromContext(ctx) != nil || storev2.IfNoneMatchFromContext(ctx) != nil {
+		return nil, &store.ErrNotValid{Err: errors.New("can't use IfMatch or IfNoneMatch with this method")}
+	}
+	result := make([]bool, len(requests))
+	err := withTx(ctx, s.db, func(tx DBI) error {
+		for i, request := range requests {
+			if err := request.Validate(); err != nil {
+				return &store.ErrNotValid{Err: err}
+			}
+			etag, err := getETag(ctx, tx, request.APIVersion, request.Type, request.Namespace, request.Name)
+			if err != nil {
+				return err
+			}
+			result[i] = etag != nil
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *ConfigStore) Patch(ctx context.Context, request storev2.ResourceRequest, patcher patch.Patcher) error {
+	if err := request.Validate(); err != nil {
+		return &store.ErrNotValid{Err: err}
+	}
+
+	key := storeKey(request)
+
+	return withTx(ctx, s.db, func(tx DBI) error {
+		if err := checkGetPreconditions(ctx, tx, request); err != nil {
+			return err
+		}
+
+		var resource []byte
+		row := tx.QueryRowContext(ctx, getResourceQuery, request.APIVersion, request.Type, request.Namespace, request.Name)
+		if err := row.Scan(&resource); err != nil {
+			if err == sql.ErrNoRows {
+				return &store.ErrNotFound{Key: requestKey(request)}
+			}
+			return &store.ErrInternal{Message: err.Error()}
+		}
+
+		patchedResource, err := patcher.Patch(resource)
+		if err != nil {
+			return &store.ErrNotValid{Err: err}
+		}
+
+		kind, err := apitools.Resolve(request.APIVersion, request.Type)
+		if err != nil {
+			return &store.ErrNotValid{Err: err}
+		}
+		res, ok := kind.(corev3.Resource)
+		if !ok {
+			return &store.ErrNotValid{Err: fmt.Errorf("%T is not a core/v3 resource", kind)}
+		}
+		if err := json.Unmarshal(patchedResource, res); err != nil {
+			return &store.ErrNotValid{Err: err}
+		}
+		if err := wrap.Validate(res); err != nil {
+			return &store.ErrNotValid{Err: err}
+		}
+
+		wrappedPatch, err := wrap.Resource(res)
+		if err != nil {
+			return &store.ErrEncode{Key: key, Err: err}
+		}
+
+		txStore := ConfigStore{db: tx}
+		return txStore.UpdateIfExists(ctx, request, wrappedPatch)
+	})
+}
+
+// storeKey converts a ResourceRequest into a key that uniquely identifies a
+// singular resource, or collection of resources, in a namespace.
+func storeKey(req storev2.ResourceRequest) string {
+	return store.NewKeyBuilder(req.StoreName).WithNamespace(req.Namespace).Build(req.Name)
+}
+
+type resourceData struct {
+	TypeMeta    corev2.TypeMeta
+	Metadata    *corev2.ObjectMeta
+	Labels      string
+	Annotations string
+	Fields      string
+	Resource    string
+	ETag        storev2.ETag
+}
+
+func extractResourceData(wrapper storev2.Wrapper) (data resourceData, err error) {
+	res, err := wrapper.Unwrap()
+	if err != nil {
+		return data, &store.ErrNotValid{Err: err}
+	}
+
+	data.Metadata = res.GetMetadata()
+	if data.Metadata == nil {
+		return data, &store.ErrNotValid{Err: errors.New("resource has no metadata")}
+	}
+
+	data.Labels, err = mapToJSON(data.Metadata.Labels)
+	if err != nil {
+		return data, &store.ErrEncode{Err: err}
+	}
+
+	data.Annotations, err = mapToJSON(data.Metadata.Annotations)
+	if err != nil {
+		return data, &store.ErrEncode{Err: err}
+	}
+
+	data.Fields = "{}"
+	if fielder, ok := res.(corev3.Fielder); ok {
+		data.Fields, err = mapToJSON(fielder.Fields())
+		if err != nil {
+			return data, &store.ErrEncode{Err: err}
+		}
+	}
+
+	resource, err := json.Marshal(res)
+	if err != nil {
+		return data, &store.ErrEncode{Err: err}
+	}
+	data.Resource = string(resource)
+	sum := sha1.Sum(resource)
+	data.ETag = storev2.ETag(sum[:])
+
+	resProxy := corev3.V2ResourceProxy{Resource: res}
+	data.TypeMeta = resProxy.GetTypeMeta()
+
+	return data, nil
+}
+
+func mapToJSON(m map[string]string) (string, error) {
+	if len(m) == 0 {
+		return "{}", nil
+	}
+	b, err := json.Marshal(m)
+	return string(b), err
+}
+
+// selectorSet builds the set of key-value pairs that selectors are matched
+// against, from the JSON encoded labels and fields of a resource.
+func selectorSet(labels, fields string) (map[string]string, error) {
+	set := map[string]string{}
+	if err := json.Unmarshal([]byte(fields), &set); err != nil {
+		return nil, err
+	}
+	var labelSet map[string]string
+	if err := json.Unmarshal([]byte(labels), &labelSet); err != nil {
+		return nil, err
+	}
+	for k, v := range labelSet {
+		set[k] = v
+	}
+	return set, nil
+}
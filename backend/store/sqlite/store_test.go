@@ -0,0 +1,814 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/backend/store/v2/wrap"
+	"github.com/stretchr/testify/require"
+)
+
+func withSQLite(t testing.TB, fn func(context.Context, *sql.DB)) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	db, err := Open(ctx, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	fn(ctx, db)
+}
+
+func createNamespace(t testing.TB, db DBI, name string) {
+	t.Helper()
+	ctx := context.Background()
+	if err := NewNamespaceStore(db).CreateIfNotExists(ctx, corev3.FixtureNamespace(name)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func isErr[E error](err error) bool {
+	var target E
+	return errors.As(err, &target)
+}
+
+func fixtureCheck(namespace, name string) *corev2.CheckConfig {
+	check := corev2.FixtureCheckConfig(name)
+	check.Namespace = namespace
+	return check
+}
+
+func checkRequest(namespace, name string) storev2.ResourceRequest {
+	return storev2.NewResourceRequestFromResource(fixtureCheck(namespace, name))
+}
+
+func wrapCheck(t testing.TB, check *corev2.CheckConfig) storev2.Wrapper {
+	t.Helper()
+	w, err := wrap.Resource(check)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestConfigStoreCRUD(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		s := NewConfigStore(db)
+		check := corev2.FixtureCheckConfig("foo")
+		req := storev2.NewResourceRequestFromResource(check)
+
+		if _, err := s.Get(ctx, req); !isErr[*store.ErrNotFound](err) {
+			t.Fatalf("expected not found, got %v", err)
+		}
+		require.NoError(t, s.CreateIfNotExists(ctx, req, wrapCheck(t, check)))
+		if err := s.CreateIfNotExists(ctx, req, wrapCheck(t, check)); !isErr[*store.ErrAlreadyExists](err) {
+			t.Fatalf("expected already exists, got %v", err)
+		}
+
+		check.Interval = 30
+		require.NoError(t, s.UpdateIfExists(ctx, req, wrapCheck(t, check)))
+
+		w, err := s.Get(ctx, req)
+		require.NoError(t, err)
+		var got corev2.CheckConfig
+		require.NoError(t, w.UnwrapInto(&got))
+		require.Equal(t, uint32(30), got.Interval)
+		require.NotEmpty(t, got.Labels[store.SensuCreatedAtKey])
+
+		exists, err := s.Exists(ctx, req)
+		require.NoError(t, err)
+		require.True(t, exists)
+
+		require.NoError(t, s.Delete(ctx, req))
+		if err := s.Delete(ctx, req); !isErr[*store.ErrNotFound](err) {
+			t.Fatalf("expected not found, got %v", err)
+		}
+		if err := s.UpdateIfExists(ctx, req, wrapCheck(t, check)); !isErr[*store.ErrNotFound](err) {
+			t.Fatalf("expected not found, got %v", err)
+		}
+
+		// soft deleted resources can be created again
+		require.NoError(t, s.CreateIfNotExists(ctx, req, wrapCheck(t, check)))
+	})
+}
+
+func TestConfigStoreBatchCreateOrUpdate(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		s := NewConfigStore(db)
+		foo, bar := corev2.FixtureCheckConfig("foo"), corev2.FixtureCheckConfig("bar")
+		reqs := []storev2.ResourceRequest{
+			storev2.NewResourceRequestFromResource(foo),
+			storev2.NewResourceRequestFromResource(bar),
+		}
+		require.NoError(t, s.BatchCreateOrUpdate(ctx, reqs, []storev2.Wrapper{wrapCheck(t, foo), wrapCheck(t, bar)}))
+		for _, req := range reqs {
+			exists, err := s.Exists(ctx, req)
+			require.NoError(t, err)
+			require.True(t, exists)
+		}
+
+		// Nothing is written if one of the resources cannot be
+		foo.Interval = 30
+		invalid := storev2.ResourceRequest{}
+		err := s.BatchCreateOrUpdate(ctx,
+			[]storev2.ResourceRequest{reqs[0], invalid},
+			[]storev2.Wrapper{wrapCheck(t, foo), wrapCheck(t, bar)})
+		if !isErr[*store.ErrNotValid](err) {
+			t.Fatalf("expected not valid, got %v", err)
+		}
+		w, err := s.Get(ctx, reqs[0])
+		require.NoError(t, err)
+		var got corev2.CheckConfig
+		require.NoError(t, w.UnwrapInto(&got))
+		require.Equal(t, uint32(60), got.Interval)
+
+		err = s.BatchCreateOrUpdate(ctx, reqs, []storev2.Wrapper{wrapCheck(t, foo)})
+		if !isErr[*store.ErrNotValid](err) {
+			t.Fatalf("expected not valid, got %v", err)
+		}
+	})
+}
+
+func TestConfigStoreIfMatch(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		s := NewConfigStore(db)
+		check := corev2.FixtureCheckConfig("foo")
+		req := storev2.NewResourceRequestFromResource(check)
+		require.NoError(t, s.CreateOrUpdate(ctx, req, wrapCheck(t, check)))
+
+		w, err := s.Get(ctx, req)
+		require.NoError(t, err)
+		var got corev2.CheckConfig
+		require.NoError(t, w.UnwrapInto(&got))
+		etag, err := storev2.DecodeETag(got.Annotations[store.SensuETagKey])
+		require.NoError(t, err)
+		require.NotEmpty(t, etag)
+
+		badCtx := storev2.ContextWithIfMatch(ctx, storev2.IfMatch{storev2.ETag("nope")})
+		if err := s.UpdateIfExists(badCtx, req, wrapCheck(t, check)); !isErr[*store.ErrPreconditionFailed](err) {
+			t.Fatalf("expected precondition failed, got %v", err)
+		}
+		goodCtx := storev2.ContextWithIfMatch(ctx, storev2.IfMatch{etag})
+		require.NoError(t, s.UpdateIfExists(goodCtx, req, wrapCheck(t, check)))
+	})
+}
+
+func TestConfigStoreList(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		s := NewConfigStore(db)
+		for _, ns := range []string{"default", "other"} {
+			for _, name := range []string{"a", "b", "c"} {
+				check := fixtureCheck(ns, name)
+				req := storev2.NewResourceRequestFromResource(check)
+				require.NoError(t, s.CreateOrUpdate(ctx, req, wrapCheck(t, check)))
+			}
+		}
+
+		list, err := s.List(ctx, checkRequest("default", ""), &store.SelectionPredicate{})
+		require.NoError(t, err)
+		require.Equal(t, 3, list.Len())
+
+		list, err = s.List(ctx, checkRequest("", ""), &store.SelectionPredicate{})
+		require.NoError(t, err)
+		require.Equal(t, 6, list.Len())
+
+		pred := &store.SelectionPredicate{Limit: 2}
+		list, err = s.List(ctx, checkRequest("default", ""), pred)
+		require.NoError(t, err)
+		require.Equal(t, 2, list.Len())
+		require.NotEmpty(t, pred.Continue)
+		list, err = s.List(ctx, checkRequest("default", ""), pred)
+		require.NoError(t, err)
+		require.Equal(t, 1, list.Len())
+		require.Empty(t, pred.Continue)
+
+		count, err := s.Count(ctx, checkRequest("other", ""))
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+
+		count, err = s.Count(ctx, checkRequest("", ""))
+		require.NoError(t, err)
+		require.Equal(t, 6, count)
+
+		exists, err := s.BatchExists(ctx, []storev2.ResourceRequest{
+			checkRequest("default", "a"), checkRequest("default", "d"), checkRequest("other", "c"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []bool{true, false, true}, exists)
+	})
+}
+
+func TestConfigStoreWatch(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		s := NewConfigStore(db)
+		s.watchInterval = 10 * time.Millisecond
+		watch := s.Watch(ctx, checkRequest("default", ""))
+
+		check := corev2.FixtureCheckConfig("foo")
+		req := storev2.NewResourceRequestFromResource(check)
+		require.NoError(t, s.CreateOrUpdate(ctx, req, wrapCheck(t, check)))
+
+		select {
+		case events := <-watch:
+			require.Len(t, events, 1)
+			require.Equal(t, storev2.WatchCreate, events[0].Type)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no watch event received")
+		}
+
+		require.NoError(t, s.Delete(ctx, req))
+		select {
+		case events := <-watch:
+			require.Len(t, events, 1)
+			require.Equal(t, storev2.WatchDelete, events[0].Type)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no watch event received")
+		}
+	})
+}
+
+func TestNamespaceStoreDelete(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		createNamespace(t, db, "foo")
+		s := NewNamespaceStore(db)
+
+		entity := corev3.FixtureEntityConfig("bar")
+		entity.Metadata.Namespace = "foo"
+		require.NoError(t, NewEntityConfigStore(db).CreateOrUpdate(ctx, entity))
+
+		if err := s.Delete(ctx, "foo"); !isErr[*store.ErrNamespaceNotEmpty](err) {
+			t.Fatalf("expected namespace not empty, got %v", err)
+		}
+		require.NoError(t, NewEntityConfigStore(db).Delete(ctx, "foo", "bar"))
+		require.NoError(t, s.Delete(ctx, "foo"))
+
+		exists, err := s.Exists(ctx, "foo")
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+}
+
+func TestEventStoreUpdateEvent(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		createNamespace(t, db, "default")
+		s := NewEventStore(db, NewSilenceStore(db))
+
+		event := corev2.FixtureEvent("entity", "check")
+		event.Check.Status = 1
+		ctx = store.NamespaceContext(ctx, "default")
+
+		updated, prev, err := s.UpdateEvent(ctx, event)
+		require.NoError(t, err)
+		require.Nil(t, prev)
+		require.Equal(t, int64(1), updated.Check.Occurrences)
+
+		event = corev2.FixtureEvent("entity", "check")
+		event.Check.Status = 1
+		updated, prev, err = s.UpdateEvent(ctx, event)
+		require.NoError(t, err)
+		require.NotNil(t, prev)
+		require.Equal(t, int64(2), updated.Check.Occurrences)
+
+		got, err := s.GetEventByEntityCheck(ctx, "entity", "check")
+		require.NoError(t, err)
+		require.Equal(t, int64(2), got.Check.Occurrences)
+
+		events, err := s.GetEventsByEntity(ctx, "entity", &store.SelectionPredicate{})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+
+		count, err := s.CountEvents(ctx, &store.SelectionPredicate{})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+
+		require.NoError(t, s.DeleteEventByEntityCheck(ctx, "entity", "check"))
+		got, err = s.GetEventByEntityCheck(ctx, "entity", "check")
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+}
+
+func TestEventStoreGetEventsOrdering(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		createNamespace(t, db, "default")
+		s := NewEventStore(db, NewSilenceStore(db))
+		ctx = store.NamespaceContext(ctx, "default")
+
+		statuses := map[string]uint32{"a": 0, "b": 2, "c": 1, "d": 2, "e": 0}
+		for entity, status := range statuses {
+			event := corev2.FixtureEvent(entity, "check")
+			event.Check.Status = status
+			_, _, err := s.UpdateEvent(ctx, event)
+			require.NoError(t, err)
+		}
+
+		names := func(events []*corev2.Event) []string {
+			var names []string
+			for _, event := range events {
+				names = append(names, event.Entity.Name)
+			}
+			return names
+		}
+
+		pred := &store.SelectionPredicate{Ordering: corev2.EventSortSeverity, Limit: 2}
+		events, err := s.GetEvents(ctx, pred)
+		require.NoError(t, err)
+		require.Equal(t, []string{"b", "d"}, names(events))
+		require.NotEmpty(t, pred.Continue)
+
+		// The next page starts after the last event of the previous page, even
+		// when events of the previous page are deleted.
+		require.NoError(t, s.DeleteEventByEntityCheck(ctx, "b", "check"))
+		events, err = s.GetEvents(ctx, pred)
+		require.NoError(t, err)
+		require.Equal(t, []string{"c", "a"}, names(events))
+
+		events, err = s.GetEvents(ctx, pred)
+		require.NoError(t, err)
+		require.Equal(t, []string{"e"}, names(events))
+		require.Empty(t, pred.Continue)
+
+		pred = &store.SelectionPredicate{Ordering: corev2.EntitySortName, Descending: true, Limit: 3}
+		events, err = s.GetEvents(ctx, pred)
+		require.NoError(t, err)
+		require.Equal(t, []string{"e", "d", "c"}, names(events))
+
+		// The continue token is bound to the ordering of the list
+		pred.Ordering = corev2.EventSortTimestamp
+		_, err = s.GetEvents(ctx, pred)
+		if !isErr[*store.ErrNotValid](err) {
+			t.Fatalf("expected invalid continue token, got %v", err)
+		}
+
+		_, err = s.GetEvents(ctx, &store.SelectionPredicate{Ordering: "garbage"})
+		if !isErr[*store.ErrNotValid](err) {
+			t.Fatalf("expected invalid ordering, got %v", err)
+		}
+	})
+}
+
+func TestEventStorePruneEvents(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		createNamespace(t, db, "default")
+		s := NewEventStore(db, NewSilenceStore(db))
+		ctx = store.NamespaceContext(ctx, "default")
+
+		timestamps := map[string]int64{"a": 100, "b": 200, "c": 300, "d": 400}
+		for entity, timestamp := range timestamps {
+			event := corev2.FixtureEvent(entity, "check")
+			event.Timestamp = timestamp
+			_, _, err := s.UpdateEvent(ctx, event)
+			require.NoError(t, err)
+		}
+		keepalive := corev2.FixtureEvent("a", "keepalive")
+		keepalive.Timestamp = 1
+		_, _, err := s.UpdateEvent(ctx, keepalive)
+		require.NoError(t, err)
+
+		// Nothing is pruned without limits
+		pruned, err := s.PruneEvents(ctx, "default", 0, 0)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), pruned)
+
+		pruned, err = s.PruneEvents(ctx, "default", 150, 0)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), pruned)
+
+		pruned, err = s.PruneEvents(ctx, "default", 0, 2)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), pruned)
+
+		events, err := s.GetEvents(ctx, &store.SelectionPredicate{})
+		require.NoError(t, err)
+		var names []string
+		for _, event := range events {
+			names = append(names, event.Entity.Name+"/"+event.Check.Name)
+		}
+		require.ElementsMatch(t, []string{"a/keepalive", "c/check", "d/check"}, names)
+	})
+}
+
+func TestEventStoreNamespaceMissing(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		s := NewEventStore(db, NewSilenceStore(db))
+		event := corev2.FixtureEvent("entity", "check")
+		_, _, err := s.UpdateEvent(store.NamespaceContext(ctx, "default"), event)
+		if !isErr[*store.ErrNamespaceMissing](err) {
+			t.Fatalf("expected namespace missing, got %v", err)
+		}
+	})
+}
+
+func TestSilenceStore(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		createNamespace(t, db, "default")
+		s := NewSilenceStore(db)
+
+		silence := corev2.FixtureSilenced("sub:check")
+		require.NoError(t, s.UpdateSilence(ctx, silence))
+
+		got, err := s.GetSilenceByName(ctx, "default", silence.Name)
+		require.NoError(t, err)
+		require.Equal(t, silence.Subscription, got.Subscription)
+		require.Equal(t, silence.Check, got.Check)
+
+		silences, err := s.GetSilencesByCheck(ctx, "default", "check")
+		require.NoError(t, err)
+		require.Len(t, silences, 1)
+
+		require.NoError(t, s.DeleteSilences(ctx, "default", []string{silence.Name}))
+		if _, err := s.GetSilenceByName(ctx, "default", silence.Name); !isErr[*store.ErrNotFound](err) {
+			t.Fatalf("expected not found, got %v", err)
+		}
+	})
+}
+
+func TestEntityStoreUpdateEntities(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		createNamespace(t, db, "default")
+		s := NewEntityStore(db)
+		ctx = store.NamespaceContext(ctx, "default")
+
+		bad := corev2.FixtureEntity("bad")
+		bad.Name = ""
+		errs := s.UpdateEntities(ctx, []*corev2.Entity{
+			corev2.FixtureEntity("foo"),
+			bad,
+			corev2.FixtureEntity("bar"),
+		})
+		require.Len(t, errs, 3)
+		require.NoError(t, errs[0])
+		require.Error(t, errs[1])
+		require.NoError(t, errs[2])
+
+		entities, err := s.GetEntities(ctx, &store.SelectionPredicate{})
+		require.NoError(t, err)
+		require.Len(t, entities, 2)
+	})
+}
+
+func TestEntityStoreGetEntitiesByLastSeen(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		createNamespace(t, db, "default")
+		s := NewEntityStore(db)
+		ctx = store.NamespaceContext(ctx, "default")
+
+		for name, lastSeen := range map[string]int64{"a": 30, "b": 10, "c": 20} {
+			entity := corev2.FixtureEntity(name)
+			entity.LastSeen = lastSeen
+			require.NoError(t, s.UpdateEntity(ctx, entity))
+		}
+
+		pred := &store.SelectionPredicate{Ordering: corev2.EntitySortLastSeen, Descending: true, Limit: 2}
+		entities, err := s.GetEntities(ctx, pred)
+		require.NoError(t, err)
+		require.Len(t, entities, 2)
+		require.Equal(t, "a", entities[0].Name)
+		require.Equal(t, "c", entities[1].Name)
+		require.NotEmpty(t, pred.Continue)
+
+		entities, err = s.GetEntities(ctx, pred)
+		require.NoError(t, err)
+		require.Len(t, entities, 1)
+		require.Equal(t, "b", entities[0].Name)
+		require.Empty(t, pred.Continue)
+	})
+}
+
+func TestHandlerResultStore(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		s := NewHandlerResultStore(db)
+		result := &storev2.HandlerResult{
+			Namespace: "default",
+			Entity:    "entity",
+			Check:     "check",
+			Handler:   "handler",
+		}
+		if err := s.AddHandlerResult(ctx, result); !isErr[*store.ErrNotFound](err) {
+			t.Fatalf("expected not found, got %v", err)
+		}
+		createNamespace(t, db, "default")
+		require.NoError(t, NewEntityConfigStore(db).CreateOrUpdate(ctx, corev3.FixtureEntityConfig("entity")))
+
+		for i := 0; i < storev2.MaxHandlerResults+2; i++ {
+			result.Executed = int64(i)
+			require.NoError(t, s.AddHandlerResult(ctx, result))
+		}
+		other := *result
+		other.Handler = "other"
+		other.Output = string(make([]byte, storev2.MaxHandlerResultOutput+1))
+		require.NoError(t, s.AddHandlerResult(ctx, &other))
+
+		results, err := s.GetHandlerResults(ctx, "default", "entity", "check")
+		require.NoError(t, err)
+		require.Len(t, results, storev2.MaxHandlerResults+1)
+		require.Equal(t, "other", results[0].Handler)
+		require.Len(t, results[0].Output, storev2.MaxHandlerResultOutput)
+		require.Equal(t, int64(storev2.MaxHandlerResults+1), results[1].Executed)
+		require.Equal(t, int64(2), results[len(results)-1].Executed)
+
+		// The results are deleted with their entity
+		require.NoError(t, NewEntityConfigStore(db).Delete(ctx, "default", "entity"))
+		results, err = s.GetHandlerResults(ctx, "default", "entity", "check")
+		require.NoError(t, err)
+		require.Empty(t, results)
+	})
+}
+
+func TestEntityStateHistoryStore(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		s := NewEntityStateHistoryStore(db)
+		state := corev3.FixtureEntityState("entity")
+		err := s.AddEntityStateSnapshot(ctx, storev2.NewEntityStateSnapshot(state, 0))
+		if !isErr[*store.ErrNotFound](err) {
+			t.Fatalf("expected not found, got %v", err)
+		}
+		createNamespace(t, db, "default")
+		require.NoError(t, NewEntityStateStore(db).CreateOrUpdate(ctx, state))
+
+		for i := 0; i < storev2.MaxEntityStateSnapshots+2; i++ {
+			state.System.Hostname = fmt.Sprintf("host-%d", i)
+			// The unchanged systems are only recorded once
+			for j := 0; j < 2; j++ {
+				require.NoError(t, s.AddEntityStateSnapshot(ctx, storev2.NewEntityStateSnapshot(state, int64(i))))
+			}
+		}
+		snapshots, err := s.GetEntityStateHistory(ctx, "default", "entity")
+		require.NoError(t, err)
+		require.Len(t, snapshots, storev2.MaxEntityStateSnapshots)
+		require.Equal(t, fmt.Sprintf("host-%d", storev2.MaxEntityStateSnapshots+1), snapshots[0].System.Hostname)
+		require.Equal(t, int64(2), snapshots[len(snapshots)-1].Timestamp)
+	})
+}
+
+func TestResourceHistoryStore(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		s := NewResourceHistoryStore(db)
+		req := checkRequest("default", "check")
+		for i := 0; i < storev2.MaxResourceVersions+2; i++ {
+			resource := []byte(fmt.Sprintf(`{"interval":%d}`, i))
+			// The unchanged resources are only recorded once
+			for j := 0; j < 2; j++ {
+				version := storev2.NewResourceVersion(req, "admin", int64(i), resource)
+				require.NoError(t, s.AddResourceVersion(ctx, version))
+				require.Equal(t, int64(i+1), version.Version)
+			}
+		}
+		deletion := storev2.NewResourceVersion(req, "admin", 42, nil)
+		require.NoError(t, s.AddResourceVersion(ctx, deletion))
+		require.True(t, deletion.Deleted)
+
+		versions, err := s.GetResourceHistory(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, versions, storev2.MaxResourceVersions)
+		require.Equal(t, int64(storev2.MaxResourceVersions+3), versions[0].Version)
+		require.True(t, versions[0].Deleted)
+		require.Nil(t, versions[0].Resource)
+		require.Equal(t, fmt.Sprintf(`{"interval":%d}`, storev2.MaxResourceVersions+1), string(versions[1].Resource))
+		require.Equal(t, "admin", versions[1].Author)
+
+		versions, err = s.GetResourceHistory(ctx, checkRequest("default", "other"))
+		require.NoError(t, err)
+		require.Empty(t, versions)
+	})
+}
+
+func TestRateLimitStore(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		s := NewRateLimitStore(db)
+		now := time.Unix(1000, 0)
+		for i := int64(1); i <= 3; i++ {
+			count, err := s.IncrementRateLimit(ctx, "key", now.Add(time.Duration(i)*time.Second), time.Minute)
+			require.NoError(t, err)
+			require.Equal(t, i, count)
+		}
+		count, err := s.IncrementRateLimit(ctx, "other", now, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+
+		// the window started with the first occurrence, at now + 1s
+		count, err = s.IncrementRateLimit(ctx, "key", now.Add(time.Minute+time.Second), time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+
+		// the elapsed window of the other key was removed when the new window
+		// started
+		var keys []string
+		rows, err := db.QueryContext(ctx, "SELECT key FROM rate_limits")
+		require.NoError(t, err)
+		defer rows.Close()
+		for rows.Next() {
+			var key string
+			require.NoError(t, rows.Scan(&key))
+			keys = append(keys, key)
+		}
+		require.NoError(t, rows.Err())
+		require.Equal(t, []string{"key"}, keys)
+	})
+}
+
+func TestAuditStore(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		s := NewAuditStore(db)
+		entry := &storev2.AuditEntry{
+			Timestamp:  time.Unix(1000, 0),
+			User:       "admin",
+			Verb:       "create",
+			APIGroup:   "core",
+			APIVersion: "v2",
+			Namespace:  "default",
+			Resource:   "checks",
+			Status:     201,
+			Outcome:    storev2.AuditOutcomeSuccess,
+			Latency:    0.01,
+		}
+		require.NoError(t, s.AddAuditEntry(ctx, entry))
+
+		var user, outcome string
+		var timestamp int64
+		row := db.QueryRowContext(ctx, "SELECT username, outcome, timestamp FROM audit_log")
+		require.NoError(t, row.Scan(&user, &outcome, &timestamp))
+		require.Equal(t, "admin", user)
+		require.Equal(t, storev2.AuditOutcomeSuccess, outcome)
+		require.Equal(t, entry.Timestamp.UnixNano(), timestamp)
+	})
+}
+
+func TestOPC(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		opc := NewOPC(db)
+		backend := store.OperatorState{
+			Type:           store.BackendOperator,
+			Name:           "backend",
+			CheckInTimeout: time.Minute,
+			Present:        true,
+		}
+		if err := opc.CheckIn(ctx, backend); err != nil {
+			t.Fatal(err)
+		}
+		agent := store.OperatorState{
+			Namespace:      "default",
+			Type:           store.AgentOperator,
+			Name:           "agent",
+			CheckInTimeout: time.Microsecond,
+			Present:        true,
+			Controller:     &store.OperatorKey{Type: store.BackendOperator, Name: "backend"},
+		}
+		if err := opc.CheckIn(ctx, agent); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := opc.QueryOperator(ctx, agent.Key())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Present || got.CheckInTimeout != agent.CheckInTimeout || *got.Controller != *agent.Controller {
+			t.Fatalf("bad operator: %#v", got)
+		}
+		operators, err := opc.ListOperators(ctx, store.OperatorKey{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(operators) != 2 {
+			t.Fatalf("got %d operators, want 2", len(operators))
+		}
+
+		// The agent does not check in on time
+		time.Sleep(time.Millisecond)
+		req := store.MonitorOperatorsRequest{
+			Type:           store.AgentOperator,
+			ControllerType: store.BackendOperator,
+			ControllerName: "backend",
+		}
+		absent, err := opc.notifications(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(absent) != 1 || absent[0].Name != "agent" || absent[0].Present {
+			t.Fatalf("bad notifications: %#v", absent)
+		}
+		got, err = opc.QueryOperator(ctx, agent.Key())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Present {
+			t.Fatal("absent operator still present")
+		}
+
+		// The agent moves to another backend when its backend checks out
+		other := backend
+		other.Name = "other"
+		if err := opc.CheckIn(ctx, other); err != nil {
+			t.Fatal(err)
+		}
+		if err := opc.CheckOut(ctx, backend.Key()); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+		req.ControllerName = "other"
+		absent, err = opc.notifications(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(absent) != 1 || absent[0].Controller.Name != "other" {
+			t.Fatalf("bad notifications: %#v", absent)
+		}
+
+		if err := opc.CheckOut(ctx, backend.Key()); !isErr[*store.ErrNotFound](err) {
+			t.Fatalf("expected a not found error, got %v", err)
+		}
+		if _, err := opc.QueryOperator(ctx, backend.Key()); !isErr[*store.ErrNotFound](err) {
+			t.Fatalf("expected a not found error, got %v", err)
+		}
+	})
+}
+
+func TestSessionStore(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		s := NewSessionStore(db)
+		for i, id := range []string{"a", "b", "c"} {
+			session := &storev2.Session{ID: id, Username: "jane", Provider: "basic", IssuedAt: int64(i), RefreshedAt: int64(i), ExpiresAt: 100}
+			require.NoError(t, s.AddSession(ctx, session))
+		}
+		require.NoError(t, s.RefreshSession(ctx, "a", 50, 200))
+		sessions, err := s.GetSessions(ctx, "jane", 150)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		require.Equal(t, "a", sessions[0].ID)
+
+		// The sessions of the other users are left untouched
+		n, err := s.RevokeSessions(ctx, "john", "a")
+		require.NoError(t, err)
+		require.Equal(t, 0, n)
+		n, err = s.RevokeSessions(ctx, "jane", "a")
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+		revoked, err := s.IsTokenRevoked(ctx, "a")
+		require.NoError(t, err)
+		require.True(t, revoked)
+		require.Error(t, s.RefreshSession(ctx, "a", 60, 300))
+		n, err = s.RevokeSessions(ctx, "jane")
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+
+		// The revoked tokens are removed once expired
+		require.NoError(t, s.AddSession(ctx, &storev2.Session{ID: "d", Username: "jane", IssuedAt: 200, RefreshedAt: 200, ExpiresAt: 300}))
+		for _, id := range []string{"a", "b", "d"} {
+			revoked, err := s.IsTokenRevoked(ctx, id)
+			require.NoError(t, err)
+			require.False(t, revoked, id)
+		}
+	})
+}
+
+func TestMFAStore(t *testing.T) {
+	withSQLite(t, func(ctx context.Context, db *sql.DB) {
+		s := NewMFAStore(db)
+		_, err := s.GetMFAEnrollment(ctx, "jane")
+		require.True(t, isErr[*store.ErrNotFound](err))
+
+		enrollment := &storev2.MFAEnrollment{Username: "jane", Secret: "a", CreatedAt: 1}
+		require.NoError(t, s.PutMFAEnrollment(ctx, enrollment, []string{"x", "y"}))
+
+		// An unconfirmed enrollment can be replaced
+		enrollment.Secret = "b"
+		require.NoError(t, s.PutMFAEnrollment(ctx, enrollment, []string{"z"}))
+		used, err := s.UseMFARecoveryCode(ctx, "jane", "x")
+		require.NoError(t, err)
+		require.False(t, used)
+
+		require.NoError(t, s.ConfirmMFAEnrollment(ctx, "jane"))
+		require.True(t, isErr[*store.ErrAlreadyExists](s.PutMFAEnrollment(ctx, enrollment, nil)))
+		got, err := s.GetMFAEnrollment(ctx, "jane")
+		require.NoError(t, err)
+		require.Equal(t, "b", got.Secret)
+		require.True(t, got.Confirmed)
+
+		// The counters and the recovery codes can only be used once
+		used, err = s.UseMFACounter(ctx, "jane", 10)
+		require.NoError(t, err)
+		require.True(t, used)
+		used, err = s.UseMFACounter(ctx, "jane", 10)
+		require.NoError(t, err)
+		require.False(t, used)
+		used, err = s.UseMFARecoveryCode(ctx, "jane", "z")
+		require.NoError(t, err)
+		require.True(t, used)
+		used, err = s.UseMFARecoveryCode(ctx, "jane", "z")
+		require.NoError(t, err)
+		require.False(t, used)
+
+		require.NoError(t, s.DeleteMFAEnrollment(ctx, "jane"))
+		_, err = s.GetMFAEnrollment(ctx, "jane")
+		require.True(t, isErr[*store.ErrNotFound](err))
+	})
+}
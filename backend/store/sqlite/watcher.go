@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/sensu/sensu-go/backend/store"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+)
+
+const defaultWatchInterval = time.Second
+
+// watcher polls the configuration table for resources that were created,
+// updated or deleted since the last poll. sqlite has no notification
+// mechanism that works across connections, and the updated_at column is
+// bumped on every write, including soft deletes.
+type watcher struct {
+	db       DBI
+	interval time.Duration
+}
+
+func newWatcher(db DBI, interval time.Duration) *watcher {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	return &watcher{db: db, interval: interval}
+}
+
+// Watch polls for changes to the resources selected by the request, until
+// the context is canceled. If the request specifies a namespace or a name,
+// only matching resources are reported.
+func (w *watcher) Watch(ctx context.Context, req storev2.ResourceRequest) <-chan []storev2.WatchEvent {
+	eventCh := make(chan []storev2.WatchEvent, 1)
+	since := time.Now().UnixNano()
+	go func() {
+		defer close(eventCh)
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			events, last, err := w.poll(ctx, req, since)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				logger.WithError(err).Error("error polling sqlite configuration")
+				events = []storev2.WatchEvent{{Type: storev2.WatchError, Key: req, Err: err}}
+			} else {
+				since = last
+			}
+			if len(events) == 0 {
+				continue
+			}
+			select {
+			case eventCh <- events:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return eventCh
+}
+
+func (w *watcher) poll(ctx context.Context, req storev2.ResourceRequest, since int64) ([]storev2.WatchEvent, int64, error) {
+	rows, err := w.db.QueryContext(ctx, watchConfigQuery, req.APIVersion, req.Type, since)
+	if err != nil {
+		return nil, since, &store.ErrInternal{Message: err.Error()}
+	}
+	defer rows.Close()
+
+	var events []storev2.WatchEvent
+	last := since
+	for rows.Next() {
+		var (
+			rec             configRecord
+			namespace, name string
+		)
+		if err := rows.Scan(append(rec.scanArgs(), &namespace, &name)...); err != nil {
+			return nil, since, &store.ErrInternal{Message: err.Error()}
+		}
+		if rec.updatedAt > last {
+			last = rec.updatedAt
+		}
+		if req.Namespace != "" && req.Namespace != namespace {
+			continue
+		}
+		if req.Name != "" && req.Name != name {
+			continue
+		}
+		key := req
+		key.Namespace, key.Name = namespace, name
+		event := storev2.WatchEvent{
+			Key:      key,
+			Value:    rec.wrapper(req.APIVersion, req.Type),
+			Revision: rec.id,
+		}
+		switch {
+		case rec.deletedAt.Valid:
+			event.Type = storev2.WatchDelete
+		case rec.createdAt == rec.updatedAt:
+			event.Type = storev2.WatchCreate
+		default:
+			event.Type = storev2.WatchUpdate
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, since, &store.ErrInternal{Message: err.Error()}
+	}
+	return events, last, nil
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
@@ -389,6 +390,29 @@ type EntityStore interface {
 	UpdateEntity(ctx context.Context, entity *corev2.Entity) error
 }
 
+// EntityBatchUpdater is implemented by entity stores that can create or update
+// many entities in a single round trip.
+type EntityBatchUpdater interface {
+	// UpdateEntities creates or updates the given entities. The returned slice
+	// has one element per entity, which is nil if the entity was stored
+	// successfully. A failure to store one entity does not prevent the others
+	// from being stored.
+	UpdateEntities(ctx context.Context, entities []*corev2.Entity) []error
+}
+
+// EntityUndeleter is implemented by entity stores that soft delete the
+// entities, so that they can be restored until they are purged.
+type EntityUndeleter interface {
+	// UndeleteEntity restores the soft deleted entity config of the given name
+	// in the namespace of ctx, along with its state. It returns ErrNotFound if
+	// there is no such soft deleted entity.
+	UndeleteEntity(ctx context.Context, name string) error
+
+	// PurgeDeletedEntities hard deletes the entities soft deleted before the
+	// given time, and returns the number of purged entities.
+	PurgeDeletedEntities(ctx context.Context, before time.Time) (int64, error)
+}
+
 // EventStore provides methods for managing events
 type EventStore interface {
 	// DeleteEventByEntityCheck deletes an event using the given entity and check,
@@ -422,6 +446,16 @@ type EventStore interface {
 	EventStoreSupportsFiltering(ctx context.Context) bool
 }
 
+// EventPruner is implemented by the event stores which can prune the events of
+// a namespace.
+type EventPruner interface {
+	// PruneEvents deletes the events of the namespace whose timestamp is older
+	// than the given unix timestamp, unless it is 0, and the oldest events
+	// beyond the given maximum number of events, unless it is 0. Keepalive
+	// events are never pruned. It returns the number of deleted events.
+	PruneEvents(ctx context.Context, namespace string, before int64, max int) (int64, error)
+}
+
 // EventFilterStore provides methods for managing events filters
 type EventFilterStore interface {
 	// DeleteEventFilterByName deletes an event filter using the given name and the
@@ -653,3 +687,17 @@ func NoMergeEventContext(ctx context.Context) context.Context {
 func IsNoMergeEventContext(ctx context.Context) bool {
 	return ctx.Value(noMergeEventKey{}) != nil
 }
+
+type replicaReadsKey struct{}
+
+// ReplicaReadsContext returns a context whose reads can be served by a read
+// replica of the store, and so can be slightly out of date.
+func ReplicaReadsContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaReadsKey{}, struct{}{})
+}
+
+// IsReplicaReadsContext returns whether the reads of a context can be served
+// by a read replica of the store.
+func IsReplicaReadsContext(ctx context.Context) bool {
+	return ctx.Value(replicaReadsKey{}) != nil
+}
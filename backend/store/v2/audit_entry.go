@@ -0,0 +1,51 @@
+package v2
+
+import "time"
+
+const (
+	// AuditOutcomeSuccess is the outcome of the requests that succeeded.
+	AuditOutcomeSuccess = "success"
+
+	// AuditOutcomeDenied is the outcome of the requests that the user was not
+	// authorized to make.
+	AuditOutcomeDenied = "denied"
+
+	// AuditOutcomeFailure is the outcome of the requests that failed for any
+	// other reason.
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEntry records a request made to the API.
+type AuditEntry struct {
+	// Timestamp is the time the request was received.
+	Timestamp time.Time `json:"timestamp"`
+
+	// RequestID is the identifier assigned to the request.
+	RequestID string `json:"request_id,omitempty"`
+
+	// User is the name of the user who made the request.
+	User string `json:"user"`
+
+	// Verb is the RBAC verb of the request, e.g. create or list.
+	Verb string `json:"verb"`
+
+	// APIGroup and APIVersion identify the API the request was made to.
+	APIGroup   string `json:"api_group"`
+	APIVersion string `json:"api_version"`
+
+	// Namespace, Resource and ResourceName identify the resources the request
+	// applies to. They are empty when not applicable.
+	Namespace    string `json:"namespace,omitempty"`
+	Resource     string `json:"resource"`
+	ResourceName string `json:"resource_name,omitempty"`
+
+	// Status is the HTTP status code of the response.
+	Status int `json:"status"`
+
+	// Outcome is one of AuditOutcomeSuccess, AuditOutcomeDenied or
+	// AuditOutcomeFailure.
+	Outcome string `json:"outcome"`
+
+	// Latency is the time taken to serve the request, in seconds.
+	Latency float64 `json:"latency"`
+}
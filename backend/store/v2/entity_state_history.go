@@ -0,0 +1,39 @@
+package v2
+
+import (
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+)
+
+// MaxEntityStateSnapshots is the number of snapshots kept in the state
+// history of an entity.
+const MaxEntityStateSnapshots = 10
+
+// EntityStateSnapshot is a snapshot of the system of an entity, recorded when
+// it changes.
+type EntityStateSnapshot struct {
+	// Namespace and Entity identify the entity.
+	Namespace string `json:"namespace"`
+	Entity    string `json:"entity"`
+
+	// Timestamp is the unix timestamp at which the system was first seen.
+	Timestamp int64 `json:"timestamp"`
+
+	// System is the system of the entity, including its network. The
+	// processes of the entity change too often to be tracked, and are left
+	// out.
+	System corev2.System `json:"system"`
+}
+
+// NewEntityStateSnapshot returns a snapshot of the system of an entity state,
+// taken at the given unix timestamp.
+func NewEntityStateSnapshot(state *corev3.EntityState, timestamp int64) *EntityStateSnapshot {
+	system := state.System
+	system.Processes = nil
+	return &EntityStateSnapshot{
+		Namespace: state.Metadata.Namespace,
+		Entity:    state.Metadata.Name,
+		Timestamp: timestamp,
+		System:    system,
+	}
+}
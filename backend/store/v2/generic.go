@@ -90,6 +90,34 @@ func (g Generic[R, T]) CreateOrUpdate(ctx context.Context, resource R) error {
 	return g.Interface.GetConfigStore().CreateOrUpdate(ctx, req, wrapper)
 }
 
+// BatchCreateOrUpdate creates or updates the resources with a single call to
+// the config store, in a single transaction. The resources with a specialized
+// store, like the entity configs, are written one at a time instead.
+func (g Generic[R, T]) BatchCreateOrUpdate(ctx context.Context, resources []R) error {
+	switch any(R(nil)).(type) {
+	case *corev3.EntityConfig, *corev3.EntityState, *corev3.Namespace:
+		for _, resource := range resources {
+			if err := g.CreateOrUpdate(ctx, resource); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+	reqs := make([]ResourceRequest, len(resources))
+	wrappers := make([]Wrapper, len(resources))
+	for i, resource := range resources {
+		req, wrapper, err := prepare(resource)
+		if err != nil {
+			return err
+		}
+		reqs[i], wrappers[i] = req, wrapper
+	}
+	return g.Interface.GetConfigStore().BatchCreateOrUpdate(ctx, reqs, wrappers)
+}
+
 func (g Generic[R, T]) trySpecializeUpdateIfExists(ctx context.Context, resource R) error {
 	switch value := any(resource).(type) {
 	case *corev3.EntityConfig:
@@ -362,6 +390,15 @@ func (g Generic[R, T]) Count(ctx context.Context, id ID) (int, error) {
 	return g.Interface.GetConfigStore().Count(ctx, req)
 }
 
+// History returns the versions recorded for the resource with the given ID,
+// most recent first.
+func (g Generic[R, T]) History(ctx context.Context, id ID) ([]*ResourceVersion, error) {
+	var r R
+	tm := getGenericTypeMeta[R, T]()
+	req := NewResourceRequest(tm, id.Namespace, id.Name, r.StoreName())
+	return g.Interface.GetResourceHistoryStore().GetResourceHistory(ctx, req)
+}
+
 func (g Generic[R, T]) trySpecializeExists(ctx context.Context, id ID) (bool, error) {
 	switch any(new(T)).(type) {
 	case *corev3.EntityConfig:
@@ -398,6 +435,35 @@ func (g Generic[R, T]) Exists(ctx context.Context, id ID) (bool, error) {
 	return g.Interface.GetConfigStore().Exists(ctx, req)
 }
 
+// BatchExists returns whether each of the resources exists, in the order of
+// the ids, with a single call to the config store. The existence of the
+// resources with a specialized store, like the entity configs, is checked one
+// resource at a time instead.
+func (g Generic[R, T]) BatchExists(ctx context.Context, ids []ID) ([]bool, error) {
+	switch any(R(nil)).(type) {
+	case *corev3.EntityConfig, *corev3.EntityState, *corev3.Namespace:
+		result := make([]bool, len(ids))
+		for i, id := range ids {
+			exists, err := g.Exists(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = exists
+		}
+		return result, nil
+	}
+	if len(ids) == 0 {
+		return []bool{}, nil
+	}
+	tm := getGenericTypeMeta[R, T]()
+	var r R
+	reqs := make([]ResourceRequest, len(ids))
+	for i, id := range ids {
+		reqs[i] = NewResourceRequest(tm, id.Namespace, id.Name, r.StoreName())
+	}
+	return g.Interface.GetConfigStore().BatchExists(ctx, reqs)
+}
+
 func (g Generic[R, T]) trySpecializePatch(ctx context.Context, id ID, patcher patch.Patcher) error {
 	namespace := id.Namespace
 	name := id.Name
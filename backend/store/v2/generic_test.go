@@ -2,6 +2,7 @@ package v2_test
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	corev2 "github.com/sensu/core/v2"
@@ -177,6 +178,52 @@ func TestGenericStoreCreateOrUpdate(t *testing.T) {
 	cfgstore.AssertCalled(t, "CreateOrUpdate", mock.Anything, req, mock.Anything)
 }
 
+func TestGenericStoreBatchCreateOrUpdate(t *testing.T) {
+	sv2 := new(mockstore.V2MockStore)
+	cfgstore := new(mockstore.ConfigStore)
+	cfgstore.On("BatchCreateOrUpdate", mock.Anything, mock.MatchedBy(func(reqs []storev2.ResourceRequest) bool {
+		return len(reqs) == 2 && reqs[0].Name == "foo" && reqs[1].Name == "bar"
+	}), mock.Anything).Return(nil)
+	sv2.On("GetConfigStore").Return(cfgstore)
+	store := storev2.Of[*corev2.CheckConfig](sv2)
+	checks := []*corev2.CheckConfig{corev2.FixtureCheckConfig("foo"), corev2.FixtureCheckConfig("bar")}
+	if err := store.BatchCreateOrUpdate(context.Background(), checks); err != nil {
+		t.Fatal(err)
+	}
+	cfgstore.AssertNumberOfCalls(t, "BatchCreateOrUpdate", 1)
+}
+
+func TestGenericStoreBatchExists(t *testing.T) {
+	sv2 := new(mockstore.V2MockStore)
+	cfgstore := new(mockstore.ConfigStore)
+	cfgstore.On("BatchExists", mock.Anything, mock.MatchedBy(func(reqs []storev2.ResourceRequest) bool {
+		return len(reqs) == 2 && reqs[0].Name == "foo" && reqs[1].Name == "bar" && reqs[0].Type == "CheckConfig"
+	})).Return([]bool{true, false}, nil)
+	sv2.On("GetConfigStore").Return(cfgstore)
+	store := storev2.Of[*corev2.CheckConfig](sv2)
+	ids := []storev2.ID{{Namespace: "default", Name: "foo"}, {Namespace: "default", Name: "bar"}}
+	got, err := store.BatchExists(context.Background(), ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []bool{true, false}) {
+		t.Errorf("bad exists: got %v", got)
+	}
+}
+
+func TestGenericStoreBatchCreateOrUpdateEntityConfig(t *testing.T) {
+	sv2 := new(mockstore.V2MockStore)
+	entConfigStore := new(mockstore.EntityConfigStore)
+	entConfigStore.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(nil)
+	sv2.On("GetEntityConfigStore").Return(entConfigStore)
+	store := storev2.Of[*corev3.EntityConfig](sv2)
+	configs := []*corev3.EntityConfig{corev3.FixtureEntityConfig("foo"), corev3.FixtureEntityConfig("bar")}
+	if err := store.BatchCreateOrUpdate(context.Background(), configs); err != nil {
+		t.Fatal(err)
+	}
+	entConfigStore.AssertNumberOfCalls(t, "CreateOrUpdate", 2)
+}
+
 func TestGenericStoreCreateOrUpdateEntityConfig(t *testing.T) {
 	sv2 := new(mockstore.V2MockStore)
 	entConfigStore := new(mockstore.EntityConfigStore)
@@ -0,0 +1,54 @@
+package v2
+
+import "unicode/utf8"
+
+const (
+	// MaxHandlerResults is the number of handler results kept per event and
+	// handler pair.
+	MaxHandlerResults = 10
+
+	// MaxHandlerResultOutput is the number of bytes of handler output kept in
+	// a handler result. Longer outputs are truncated.
+	MaxHandlerResultOutput = 4096
+)
+
+// HandlerResult is the result of the execution of a handler for an event.
+type HandlerResult struct {
+	// Namespace, Entity and Check identify the handled event.
+	Namespace string `json:"namespace"`
+	Entity    string `json:"entity"`
+	Check     string `json:"check"`
+
+	// Handler is the name of the executed handler.
+	Handler string `json:"handler"`
+
+	// Executed is the unix timestamp of the handler execution.
+	Executed int64 `json:"executed"`
+
+	// Duration is the handler execution time, in seconds.
+	Duration float64 `json:"duration"`
+
+	// Status is the exit status of the handler. For handlers that do not run
+	// a command, it is 0 on success and 1 on failure.
+	Status int32 `json:"status"`
+
+	// Output is the output of the handler, truncated to
+	// MaxHandlerResultOutput bytes.
+	Output string `json:"output,omitempty"`
+
+	// Error is the error that prevented the handler from executing, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// TruncateOutput truncates the output of the result to at most
+// MaxHandlerResultOutput bytes, on a rune boundary so that the output stays
+// valid UTF-8.
+func (r *HandlerResult) TruncateOutput() {
+	if len(r.Output) > MaxHandlerResultOutput {
+		n := MaxHandlerResultOutput
+		for n > 0 && !utf8.RuneStart(r.Output[n]) {
+			n--
+		}
+		r.Output = r.Output[:n]
+	}
+}
@@ -0,0 +1,47 @@
+package v2
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestHandlerResultTruncateOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		wantLen int
+	}{
+		{
+			name:    "short output",
+			output:  "ok",
+			wantLen: 2,
+		},
+		{
+			name:    "ascii output",
+			output:  strings.Repeat("a", MaxHandlerResultOutput+1),
+			wantLen: MaxHandlerResultOutput,
+		},
+		{
+			// The limit falls in the middle of the last 2-byte rune
+			name:    "multi-byte output",
+			output:  "!" + strings.Repeat("é", MaxHandlerResultOutput/2),
+			wantLen: MaxHandlerResultOutput - 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &HandlerResult{Output: tt.output}
+			r.TruncateOutput()
+			if got := len(r.Output); got != tt.wantLen {
+				t.Errorf("bad output length: got %d, want %d", got, tt.wantLen)
+			}
+			if !utf8.ValidString(r.Output) {
+				t.Errorf("truncated output is not valid UTF-8: %q", r.Output[len(r.Output)-4:])
+			}
+			if !strings.HasPrefix(tt.output, r.Output) {
+				t.Error("truncated output is not a prefix of the output")
+			}
+		})
+	}
+}
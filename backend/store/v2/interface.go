@@ -2,6 +2,7 @@ package v2
 
 import (
 	"context"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
@@ -18,6 +19,13 @@ type Interface interface {
 	EventStoreGetter
 	EntityStoreGetter
 	SilencesStoreGetter
+	HandlerResultStoreGetter
+	EntityStateHistoryStoreGetter
+	ResourceHistoryStoreGetter
+	RateLimitStoreGetter
+	AuditStoreGetter
+	SessionStoreGetter
+	MFAStoreGetter
 }
 
 // Wrapper is an abstraction of a store wrapper.
@@ -68,11 +76,51 @@ type SilencesStoreGetter interface {
 	GetSilencesStore() SilencesStore
 }
 
+// HandlerResultStoreGetter gets you a HandlerResultStore.
+type HandlerResultStoreGetter interface {
+	GetHandlerResultStore() HandlerResultStore
+}
+
+// EntityStateHistoryStoreGetter gets you an EntityStateHistoryStore.
+type EntityStateHistoryStoreGetter interface {
+	GetEntityStateHistoryStore() EntityStateHistoryStore
+}
+
+// ResourceHistoryStoreGetter gets you a ResourceHistoryStore.
+type ResourceHistoryStoreGetter interface {
+	GetResourceHistoryStore() ResourceHistoryStore
+}
+
+// RateLimitStoreGetter gets you a RateLimitStore.
+type RateLimitStoreGetter interface {
+	GetRateLimitStore() RateLimitStore
+}
+
+// AuditStoreGetter gets you an AuditStore.
+type AuditStoreGetter interface {
+	GetAuditStore() AuditStore
+}
+
+// SessionStoreGetter gets you a SessionStore.
+type SessionStoreGetter interface {
+	GetSessionStore() SessionStore
+}
+
+// MFAStoreGetter gets you an MFAStore.
+type MFAStoreGetter interface {
+	GetMFAStore() MFAStore
+}
+
 // ConfigStore specifies the interface of a v2 store.
 type ConfigStore interface {
 	// CreateOrUpdate creates or updates the wrapped resource.
 	CreateOrUpdate(context.Context, ResourceRequest, Wrapper) error
 
+	// BatchCreateOrUpdate creates or updates the wrapped resources, each of
+	// them described by the request of the same index, in a single
+	// transaction: either all the resources are written, or none.
+	BatchCreateOrUpdate(context.Context, []ResourceRequest, []Wrapper) error
+
 	// UpdateIfExists updates the resource with the wrapped resource, but only
 	// if it already exists in the store.
 	UpdateIfExists(context.Context, ResourceRequest, Wrapper) error
@@ -98,6 +146,10 @@ type ConfigStore interface {
 	// Exists returns true if the resource indicated by the request exists
 	Exists(context.Context, ResourceRequest) (bool, error)
 
+	// BatchExists returns whether each of the resources indicated by the
+	// requests exists, in the order of the requests, with a single query.
+	BatchExists(context.Context, []ResourceRequest) ([]bool, error)
+
 	// Patch patches the resource
 	Patch(context.Context, ResourceRequest, patch.Patcher) error
 
@@ -266,3 +318,118 @@ type SilencesStore interface {
 	// DeleteSilences deletes one or more named silences
 	DeleteSilences(ctx context.Context, namespace string, names []string) error
 }
+
+// HandlerResultStore provides an interface for recording the results of
+// handler executions.
+type HandlerResultStore interface {
+	// AddHandlerResult records the result of a handler execution. Only the
+	// last MaxHandlerResults results of each event and handler pair are kept,
+	// and the results are deleted with their entity. It returns
+	// *store.ErrNotFound if the entity does not exist.
+	AddHandlerResult(ctx context.Context, result *HandlerResult) error
+
+	// GetHandlerResults gets the handler results recorded for the event of
+	// the given namespace, entity and check, most recent first.
+	GetHandlerResults(ctx context.Context, namespace, entity, check string) ([]*HandlerResult, error)
+}
+
+// EntityStateHistoryStore provides an interface for recording the history of
+// the systems of the entities.
+type EntityStateHistoryStore interface {
+	// AddEntityStateSnapshot records a snapshot of the system of an entity,
+	// unless its system is the same as in the last snapshot recorded. Only
+	// the last MaxEntityStateSnapshots snapshots of each entity are kept.
+	// The state of the entity must exist.
+	AddEntityStateSnapshot(ctx context.Context, snapshot *EntityStateSnapshot) error
+
+	// GetEntityStateHistory gets the snapshots recorded for the entity of the
+	// given namespace and name, most recent first.
+	GetEntityStateHistory(ctx context.Context, namespace, entity string) ([]*EntityStateSnapshot, error)
+}
+
+// ResourceHistoryStore provides an interface for recording the versions of
+// the configuration resources.
+type ResourceHistoryStore interface {
+	// AddResourceVersion records a version of a resource and assigns its
+	// number, unless the version is the same as the last one recorded. Only
+	// the last MaxResourceVersions versions of each resource are kept.
+	AddResourceVersion(ctx context.Context, version *ResourceVersion) error
+
+	// GetResourceHistory gets the versions recorded for the resource of the
+	// request, most recent first.
+	GetResourceHistory(ctx context.Context, req ResourceRequest) ([]*ResourceVersion, error)
+}
+
+// RateLimitStore provides an interface for counting occurrences within fixed
+// time windows, shared by all backends.
+type RateLimitStore interface {
+	// IncrementRateLimit records an occurrence for the key at the given time
+	// and returns the number of occurrences recorded in the current window,
+	// including this one. A window starts with the first occurrence recorded
+	// after the previous window has elapsed, and the keys whose window has
+	// elapsed are eventually removed.
+	IncrementRateLimit(ctx context.Context, key string, now time.Time, window time.Duration) (int64, error)
+}
+
+// AuditStore provides an interface for recording the requests made to the API.
+type AuditStore interface {
+	// AddAuditEntry records an audit entry.
+	AddAuditEntry(ctx context.Context, entry *AuditEntry) error
+}
+
+// SessionStore provides an interface for recording the login sessions of the
+// users, and for revoking their refresh tokens. The IDs of the revoked tokens
+// are kept until the tokens expire.
+type SessionStore interface {
+	// AddSession records a new session, and removes the sessions and the
+	// revoked tokens that expired.
+	AddSession(ctx context.Context, session *Session) error
+
+	// RefreshSession records that the refresh token of a session was renewed,
+	// with a new expiration. It returns a store.ErrNotFound if the session
+	// does not exist anymore, because it was revoked or it expired.
+	RefreshSession(ctx context.Context, id string, refreshedAt, expiresAt int64) error
+
+	// GetSessions gets the sessions of a user that are not expired at the
+	// given unix timestamp, most recent first.
+	GetSessions(ctx context.Context, username string, now int64) ([]*Session, error)
+
+	// RevokeSessions removes the sessions of a user with the given IDs, or
+	// all of its sessions if no ID is given, and revokes their refresh
+	// tokens. It returns the number of sessions revoked.
+	RevokeSessions(ctx context.Context, username string, ids ...string) (int, error)
+
+	// IsTokenRevoked returns whether the refresh token with the given ID was
+	// revoked.
+	IsTokenRevoked(ctx context.Context, id string) (bool, error)
+}
+
+// MFAStore stores the enrollments of the users in the multi-factor
+// authentication, and their recovery codes.
+type MFAStore interface {
+	// PutMFAEnrollment replaces the enrollment of a user and its recovery
+	// codes with a new, unconfirmed enrollment. It returns a
+	// store.ErrAlreadyExists if the user has a confirmed enrollment.
+	PutMFAEnrollment(ctx context.Context, enrollment *MFAEnrollment, recoveryCodeHashes []string) error
+
+	// GetMFAEnrollment gets the enrollment of a user. It returns a
+	// store.ErrNotFound if the user is not enrolled.
+	GetMFAEnrollment(ctx context.Context, username string) (*MFAEnrollment, error)
+
+	// ConfirmMFAEnrollment confirms the enrollment of a user. It returns a
+	// store.ErrNotFound if the user is not enrolled.
+	ConfirmMFAEnrollment(ctx context.Context, username string) error
+
+	// UseMFACounter records that the one-time password of the given time
+	// step was used by a user. It returns false if a one-time password of
+	// the same or a later time step was already used.
+	UseMFACounter(ctx context.Context, username string, counter int64) (bool, error)
+
+	// UseMFARecoveryCode removes the recovery code of a user with the given
+	// hash, and returns whether it existed.
+	UseMFARecoveryCode(ctx context.Context, username, codeHash string) (bool, error)
+
+	// DeleteMFAEnrollment removes the enrollment of a user and its recovery
+	// codes.
+	DeleteMFAEnrollment(ctx context.Context, username string) error
+}
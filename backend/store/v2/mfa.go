@@ -0,0 +1,23 @@
+package v2
+
+// MFAEnrollment is the enrollment of a user in the multi-factor
+// authentication with time-based one-time passwords.
+type MFAEnrollment struct {
+	// Username is the user enrolled.
+	Username string
+
+	// Secret is the base32-encoded secret of the one-time passwords.
+	Secret string
+
+	// Confirmed is whether the user confirmed the enrollment with a first
+	// one-time password. The one-time passwords are only required once the
+	// enrollment is confirmed.
+	Confirmed bool
+
+	// CreatedAt is the unix timestamp at which the user enrolled.
+	CreatedAt int64
+
+	// LastCounter is the time step of the last one-time password used, so
+	// that the one-time passwords can't be replayed.
+	LastCounter int64
+}
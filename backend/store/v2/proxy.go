@@ -29,6 +29,14 @@ func (p *Proxy) CreateOrUpdate(ctx context.Context, req ResourceRequest, wrapper
 	return p.impl.CreateOrUpdate(ctx, req, wrapper)
 }
 
+// BatchCreateOrUpdate creates or updates the wrapped resources in a single
+// transaction.
+func (p *Proxy) BatchCreateOrUpdate(ctx context.Context, reqs []ResourceRequest, wrappers []Wrapper) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.impl.BatchCreateOrUpdate(ctx, reqs, wrappers)
+}
+
 // UpdateIfExists updates the resource with the wrapped resource, but only
 // if it already exists in the store.
 func (p *Proxy) UpdateIfExists(ctx context.Context, req ResourceRequest, wrapper Wrapper) error {
@@ -80,6 +88,14 @@ func (p *Proxy) Exists(ctx context.Context, req ResourceRequest) (bool, error) {
 	return p.impl.Exists(ctx, req)
 }
 
+// BatchExists returns whether each of the resources indicated by the requests
+// exists.
+func (p *Proxy) BatchExists(ctx context.Context, reqs []ResourceRequest) ([]bool, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.impl.BatchExists(ctx, reqs)
+}
+
 // Patch patches the resource given in the request
 func (p *Proxy) Patch(ctx context.Context, req ResourceRequest, patcher patch.Patcher) error {
 	p.mu.RLock()
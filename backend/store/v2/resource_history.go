@@ -0,0 +1,49 @@
+package v2
+
+import "encoding/json"
+
+// MaxResourceVersions is the number of versions kept in the history of a
+// configuration resource.
+const MaxResourceVersions = 10
+
+// ResourceVersion is a version of a configuration resource, recorded when the
+// resource is written or deleted.
+type ResourceVersion struct {
+	// APIVersion, Type, Namespace and Name identify the resource.
+	APIVersion string `json:"api_version"`
+	Type       string `json:"type"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+
+	// Version is the number of the version, starting at 1 and incremented
+	// with each version of the resource. It is assigned by the store.
+	Version int64 `json:"version"`
+
+	// Author is the name of the user who wrote the version. It is empty when
+	// the resource was written by the backend itself.
+	Author string `json:"author,omitempty"`
+
+	// Timestamp is the unix timestamp at which the version was written.
+	Timestamp int64 `json:"timestamp"`
+
+	// Deleted is true when the version records the deletion of the resource.
+	Deleted bool `json:"deleted,omitempty"`
+
+	// Resource is the JSON representation of the resource, as stored. It is
+	// empty when the version records a deletion.
+	Resource json.RawMessage `json:"resource,omitempty"`
+}
+
+// NewResourceVersion returns a version of the resource of a request.
+func NewResourceVersion(req ResourceRequest, author string, timestamp int64, resource []byte) *ResourceVersion {
+	return &ResourceVersion{
+		APIVersion: req.APIVersion,
+		Type:       req.Type,
+		Namespace:  req.Namespace,
+		Name:       req.Name,
+		Author:     author,
+		Timestamp:  timestamp,
+		Deleted:    resource == nil,
+		Resource:   resource,
+	}
+}
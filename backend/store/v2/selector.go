@@ -19,6 +19,18 @@ func EventSelectorFromContext(ctx context.Context) *selector.Selector {
 	return SelectorFromContext(ctx, corev2.TypeMeta{APIVersion: "core/v2", Type: "Event"})
 }
 
+// EntityConfigContextWithSelector returns a new context, with the selector of
+// the entity configs stored as a value.
+func EntityConfigContextWithSelector(ctx context.Context, selector *selector.Selector) context.Context {
+	return ContextWithSelector(ctx, corev2.TypeMeta{APIVersion: "core/v3", Type: "EntityConfig"}, selector)
+}
+
+// EntityConfigSelectorFromContext extracts the selector of the entity configs
+// stored as a context value, if it exists.
+func EntityConfigSelectorFromContext(ctx context.Context) *selector.Selector {
+	return SelectorFromContext(ctx, corev2.TypeMeta{APIVersion: "core/v3", Type: "EntityConfig"})
+}
+
 type selectorCtxKey struct {
 	Type       string
 	APIVersion string
@@ -0,0 +1,26 @@
+package v2
+
+// Session is a login session of a user, identified by the ID of its refresh
+// token.
+type Session struct {
+	// ID is the ID of the refresh token of the session.
+	ID string `json:"id"`
+
+	// Username is the user logged in.
+	Username string `json:"username"`
+
+	// Provider is the ID of the authentication provider the user logged in
+	// with.
+	Provider string `json:"provider"`
+
+	// IssuedAt is the unix timestamp at which the user logged in.
+	IssuedAt int64 `json:"issued_at"`
+
+	// RefreshedAt is the unix timestamp at which the refresh token was last
+	// renewed.
+	RefreshedAt int64 `json:"refreshed_at"`
+
+	// ExpiresAt is the unix timestamp at which the last refresh token issued
+	// expires, ending the session.
+	ExpiresAt int64 `json:"expires_at"`
+}
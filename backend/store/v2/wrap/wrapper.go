@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 
 	//nolint:staticcheck // SA1004 Replacing this will take some planning.
@@ -131,6 +132,43 @@ type validatable interface {
 
 var ErrValidateMethodMissing = errors.New("resource is missing required Validate() method")
 
+// ValidatorFunc validates a resource.
+type ValidatorFunc func(resource interface{}) error
+
+var (
+	validatorsMu sync.RWMutex
+	validators   = make(map[reflect.Type]ValidatorFunc)
+)
+
+// RegisterValidator registers the validator of the resources of the type of
+// resource, used in place of their Validate method. It lets the packages that
+// extend resources defined in other modules, like the handler types, validate
+// their extensions.
+func RegisterValidator(resource interface{}, fn ValidatorFunc) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	validators[reflect.TypeOf(resource)] = fn
+}
+
+// Validate validates a resource with its registered validator, or its
+// Validate method.
+func Validate(r interface{}) error {
+	resource := r
+	if proxy, ok := r.(*corev3.V2ResourceProxy); ok {
+		resource = proxy.Resource
+	}
+	validatorsMu.RLock()
+	fn, ok := validators[reflect.TypeOf(resource)]
+	validatorsMu.RUnlock()
+	if ok {
+		return fn(resource)
+	}
+	if v, ok := r.(validatable); ok {
+		return v.Validate()
+	}
+	return ErrValidateMethodMissing
+}
+
 func (e Encoding) Encode(v interface{}) ([]byte, error) {
 	switch e {
 	case Encoding_json:
@@ -280,12 +318,11 @@ func wrapWithoutValidation(r interface{}, opts ...Option) (*Wrapper, error) {
 }
 
 func wrap(r interface{}, opts ...Option) (*Wrapper, error) {
-	if v, ok := r.(validatable); ok {
-		if err := v.Validate(); err != nil {
+	if err := Validate(r); err != nil {
+		if err == ErrValidateMethodMissing {
 			return nil, err
 		}
-	} else {
-		return nil, ErrValidateMethodMissing
+		return nil, &store.ErrNotValid{Err: err}
 	}
 	return wrapWithoutValidation(r, opts...)
 }
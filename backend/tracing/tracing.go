@@ -0,0 +1,168 @@
+// Package tracing provides the OpenTelemetry tracing of the event pipeline,
+// from the reception of an event by eventd to the execution of its handlers by
+// pipelined.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	corev2 "github.com/sensu/core/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TraceParentAnnotation is the event annotation that propagates the trace
+	// context of an event, in the W3C traceparent format, from eventd to
+	// pipelined. It also lets operators find the trace of a stored event.
+	TraceParentAnnotation = "sensu.io/traceparent"
+
+	// ServiceName is the name of the service reported in traces.
+	ServiceName = "sensu-backend"
+
+	instrumentationName = "github.com/sensu/sensu-go/backend"
+)
+
+// Config configures the export of traces.
+type Config struct {
+	// OTLPEndpoint is the host:port of the OTLP/HTTP collector. Tracing is
+	// disabled when it is empty.
+	OTLPEndpoint string
+
+	// OTLPInsecure disables TLS for the connection to the collector.
+	OTLPInsecure bool
+
+	// SampleRatio is the ratio of the events traced, between 0 and 1.
+	SampleRatio float64
+
+	// BackendName identifies the backend in traces.
+	BackendName string
+}
+
+// Setup installs the global tracer provider, exporting traces to the OTLP
+// collector of the configuration. It returns a function that flushes and
+// stops the export of traces.
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	if config.OTLPEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return nil, fmt.Errorf("tracing sample ratio must be between 0 and 1, got %v", config.SampleRatio)
+	}
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.OTLPEndpoint)}
+	if config.OTLPInsecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create otlp trace exporter: %s", err)
+	}
+	res := resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceNameKey.String(ServiceName),
+		semconv.ServiceInstanceIDKey.String(config.BackendName),
+	)
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return provider.Shutdown, nil
+}
+
+// Start starts a span named after the operation, as a child of the span of
+// ctx, if any. The span is a no-op unless tracing is set up.
+func Start(ctx context.Context, operation string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return otel.Tracer(instrumentationName).Start(ctx, operation, trace.WithAttributes(attributes...))
+}
+
+// End records the error, if any, and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// EventAttributes returns the attributes identifying the event in spans.
+func EventAttributes(event *corev2.Event) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{
+		attribute.String("sensu.namespace", event.Entity.Namespace),
+		attribute.String("sensu.entity", event.Entity.Name),
+	}
+	if event.HasCheck() {
+		attributes = append(attributes,
+			attribute.String("sensu.check", event.Check.Name),
+			attribute.Int64("sensu.check.status", int64(event.Check.Status)),
+		)
+	}
+	return attributes
+}
+
+// PipelineAttributes returns the attributes identifying the pipeline and
+// workflow of ctx in spans, followed by the given attributes.
+func PipelineAttributes(ctx context.Context, attributes ...attribute.KeyValue) []attribute.KeyValue {
+	if ctx == nil {
+		return attributes
+	}
+	return append([]attribute.KeyValue{
+		attribute.String("sensu.pipeline", corev2.ContextPipeline(ctx)),
+		attribute.String("sensu.pipeline_workflow", corev2.ContextPipelineWorkflow(ctx)),
+	}, attributes...)
+}
+
+// annotationCarrier adapts event annotations to propagation.TextMapCarrier.
+type annotationCarrier struct {
+	annotations map[string]string
+}
+
+func (c annotationCarrier) Get(key string) string {
+	if key == "traceparent" {
+		return c.annotations[TraceParentAnnotation]
+	}
+	return ""
+}
+
+func (c annotationCarrier) Set(key, value string) {
+	if key == "traceparent" {
+		c.annotations[TraceParentAnnotation] = value
+	}
+}
+
+func (c annotationCarrier) Keys() []string {
+	return []string{"traceparent"}
+}
+
+// InjectEvent stores the trace context of ctx in the event annotations.
+func InjectEvent(ctx context.Context, event *corev2.Event) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+	if event.ObjectMeta.Annotations == nil {
+		event.ObjectMeta.Annotations = make(map[string]string)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, annotationCarrier{annotations: event.ObjectMeta.Annotations})
+}
+
+// ExtractEvent returns a context carrying the trace context stored in the
+// event annotations, if any.
+func ExtractEvent(ctx context.Context, event *corev2.Event) context.Context {
+	if _, ok := event.ObjectMeta.Annotations[TraceParentAnnotation]; !ok {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, annotationCarrier{annotations: event.ObjectMeta.Annotations})
+}
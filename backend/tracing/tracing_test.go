@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEventPropagation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	event := corev2.FixtureEvent("entity1", "check1")
+	event.ObjectMeta.Annotations = nil
+
+	ctx, span := Start(context.Background(), "eventd.handle_event", EventAttributes(event)...)
+	InjectEvent(ctx, event)
+	End(span, nil)
+
+	if _, ok := event.Annotations[TraceParentAnnotation]; !ok {
+		t.Fatalf("expected %s annotation", TraceParentAnnotation)
+	}
+
+	_, child := Start(ExtractEvent(context.Background(), event), "pipelined.handle_event")
+	End(child, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if got, want := spans[1].Parent().SpanID(), spans[0].SpanContext().SpanID(); got != want {
+		t.Errorf("bad parent span: got %s, want %s", got, want)
+	}
+	if got, want := spans[1].SpanContext().TraceID(), spans[0].SpanContext().TraceID(); got != want {
+		t.Errorf("bad trace id: got %s, want %s", got, want)
+	}
+}
+
+func TestInjectEventWithoutSpan(t *testing.T) {
+	event := corev2.FixtureEvent("entity1", "check1")
+	InjectEvent(context.Background(), event)
+	if _, ok := event.Annotations[TraceParentAnnotation]; ok {
+		t.Errorf("unexpected %s annotation", TraceParentAnnotation)
+	}
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetupInvalidSampleRatio(t *testing.T) {
+	if _, err := Setup(context.Background(), Config{OTLPEndpoint: "localhost:4318", SampleRatio: 2}); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -3,6 +3,8 @@ package client
 import (
 	"encoding/json"
 	"net/http"
+	"path"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/core/v3/types"
@@ -117,3 +119,31 @@ func (client *RestClient) UpdateAPIKey(name, username string) error {
 	_, err := request.SetBody(obj).Patch(apikey.URIPath())
 	return err
 }
+
+// RotateAPIKey replaces the secret of an api-key, and returns the new secret.
+// The previous secret is still accepted for the grace period.
+func (client *RestClient) RotateAPIKey(name string, gracePeriod time.Duration) (corev2.APIKeyResponse, error) {
+	var response corev2.APIKeyResponse
+
+	apikey := &corev2.APIKey{
+		ObjectMeta: corev2.ObjectMeta{
+			Name: name,
+		},
+	}
+	res, err := client.R().
+		SetQueryParam("grace_period", gracePeriod.String()).
+		Put(path.Join(apikey.URIPath(), "rotate"))
+	if err != nil {
+		return response, err
+	}
+
+	if res.StatusCode() >= 400 {
+		return response, UnmarshalError(res)
+	}
+
+	if err := json.Unmarshal(res.Body(), &response); err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
@@ -17,6 +17,14 @@ import (
 const (
 	clusterFilename	= "cluster"
 	profileFilename	= "profile"
+
+	// contextsDirname is the directory of the named contexts, each one
+	// having its own cluster and profile files
+	contextsDirname	= "contexts"
+
+	// currentContextFilename is the file storing the name of the current
+	// context
+	currentContextFilename	= "current-context"
 )
 
 var logger = logrus.WithFields(logrus.Fields{
@@ -27,7 +35,11 @@ var logger = logrus.WithFields(logrus.Fields{
 type Config struct {
 	Cluster
 	Profile
+	// dir is the configuration directory, and path the directory of the
+	// active context
+	dir	string
 	path	string
+	context	string
 }
 
 // Cluster contains the Sensu cluster access information
@@ -74,6 +86,16 @@ func Load(flags *pflag.FlagSet, v *viper.Viper) *Config {
 		}
 	}
 
+	// Select the active context, which can be overridden per command
+	conf.dir = conf.path
+	conf.context = conf.currentContext()
+	if v != nil {
+		if value := v.GetString("context"); value != "" {
+			conf.context = value
+		}
+	}
+	conf.path = conf.contextPath(conf.context)
+
 	// Load the profile config file
 	if err := conf.open(profileFilename); err != nil {
 		logger.Debug(err)
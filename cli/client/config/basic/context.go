@@ -0,0 +1,106 @@
+package basic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/sensu/sensu-go/cli/client/config"
+)
+
+// currentContext is the content of the current context file
+type currentContext struct {
+	CurrentContext string `json:"current-context"`
+}
+
+// Context returns the name of the active context
+func (c *Config) Context() string {
+	if c.context == "" {
+		return config.DefaultContext
+	}
+	return c.context
+}
+
+// Contexts returns the names of the configured contexts, starting with the
+// default context
+func (c *Config) Contexts() ([]string, error) {
+	infos, err := ioutil.ReadDir(filepath.Join(c.dir, contextsDirname))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var names []string
+	for _, info := range infos {
+		if info.IsDir() && config.ValidateContextName(info.Name()) == nil && info.Name() != config.DefaultContext {
+			names = append(names, info.Name())
+		}
+	}
+	sort.Strings(names)
+	return append([]string{config.DefaultContext}, names...), nil
+}
+
+// UseContext saves the name of the current context, which must have been
+// configured with "sensuctl configure --context"
+func (c *Config) UseContext(name string) error {
+	if err := c.contextExists(name); err != nil {
+		return err
+	}
+	return write(currentContext{CurrentContext: name}, filepath.Join(c.dir, currentContextFilename))
+}
+
+// DeleteContext deletes the configuration of a context, unless it is the
+// default or the current context
+func (c *Config) DeleteContext(name string) error {
+	if name == config.DefaultContext {
+		return fmt.Errorf("the %s context can't be deleted", config.DefaultContext)
+	}
+	if err := c.contextExists(name); err != nil {
+		return err
+	}
+	if name == c.currentContext() {
+		return fmt.Errorf("context %q is the current context", name)
+	}
+	return os.RemoveAll(c.contextPath(name))
+}
+
+// currentContext returns the name of the current context, as saved in the
+// configuration directory
+func (c *Config) currentContext() string {
+	content, err := ioutil.ReadFile(filepath.Join(c.dir, currentContextFilename))
+	if err != nil {
+		logger.Debug(err)
+		return config.DefaultContext
+	}
+	var current currentContext
+	if err := json.Unmarshal(content, &current); err != nil || current.CurrentContext == "" {
+		return config.DefaultContext
+	}
+	return current.CurrentContext
+}
+
+// contextPath returns the directory of a context. The default context is
+// configured at the root of the configuration directory.
+func (c *Config) contextPath(name string) string {
+	if name == "" || name == config.DefaultContext {
+		return c.dir
+	}
+	return filepath.Join(c.dir, contextsDirname, name)
+}
+
+func (c *Config) contextExists(name string) error {
+	if name == config.DefaultContext {
+		return nil
+	}
+	if err := config.ValidateContextName(name); err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(c.contextPath(name), clusterFilename)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("context %q does not exist, it can be created with \"sensuctl configure --context %s\"", name, name)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package basic
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadContext(t *testing.T, dir, context string) *Config {
+	t.Helper()
+	flags := pflag.NewFlagSet("config-dir", pflag.ContinueOnError)
+	flags.String("config-dir", dir, "")
+	flags.String("context", context, "")
+	v := viper.New()
+	require.NoError(t, v.BindPFlags(flags))
+	return Load(flags, v)
+}
+
+func TestContexts(t *testing.T) {
+	dir := t.TempDir()
+
+	// Configure the default and prod contexts
+	config := loadContext(t, dir, "")
+	assert.Equal(t, "default", config.Context())
+	require.NoError(t, config.SaveAPIUrl("http://default:8080"))
+	config = loadContext(t, dir, "prod")
+	assert.Equal(t, "prod", config.Context())
+	require.NoError(t, config.SaveAPIUrl("http://prod:8080"))
+	require.NoError(t, config.SaveNamespace("ops"))
+	assert.FileExists(t, filepath.Join(dir, contextsDirname, "prod", clusterFilename))
+
+	contexts, err := config.Contexts()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "prod"}, contexts)
+
+	// The default context is used until the current context is switched
+	config = loadContext(t, dir, "")
+	assert.Equal(t, "http://default:8080", config.APIUrl())
+	assert.Equal(t, "default", config.Namespace())
+	require.NoError(t, config.UseContext("prod"))
+	config = loadContext(t, dir, "")
+	assert.Equal(t, "prod", config.Context())
+	assert.Equal(t, "http://prod:8080", config.APIUrl())
+	assert.Equal(t, "ops", config.Namespace())
+
+	// The current context can be overridden per command
+	config = loadContext(t, dir, "default")
+	assert.Equal(t, "http://default:8080", config.APIUrl())
+
+	// The current context can't be deleted
+	assert.Error(t, config.DeleteContext("prod"))
+	assert.Error(t, config.DeleteContext("default"))
+	require.NoError(t, config.UseContext("default"))
+	require.NoError(t, config.DeleteContext("prod"))
+	_, err = os.Stat(filepath.Join(dir, contextsDirname, "prod"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestUseContextMissing(t *testing.T) {
+	config := loadContext(t, t.TempDir(), "")
+	assert.Error(t, config.UseContext("staging"))
+	assert.Error(t, config.UseContext("../staging"))
+	assert.Error(t, config.DeleteContext("staging"))
+}
@@ -1,12 +1,18 @@
 package config
 
 import (
+	"fmt"
+	"regexp"
 	"time"
 
 	v2 "github.com/sensu/core/v2"
 )
 
 const (
+	// DefaultContext is the name of the context configured at the root of the
+	// configuration directory
+	DefaultContext	= "default"
+
 	// DefaultNamespace represents the default namespace
 	DefaultNamespace	= "default"
 
@@ -28,6 +34,14 @@ const (
 	// FormatYAML indicates YAML format for printers. It has the same layout
 	// as wrapped JSON.
 	FormatYAML	= "yaml"
+
+	// FormatCSV indicates CSV format for the printers of the lists, with a
+	// column per field of the resources.
+	FormatCSV	= "csv"
+
+	// FormatJSONL indicates JSON lines format for the printers of the lists,
+	// with a compact JSON document per resource.
+	FormatJSONL	= "jsonl"
 )
 
 // Config is an abstract configuration
@@ -39,6 +53,8 @@ type Config interface {
 // Read contains all methods related to reading configuration
 type Read interface {
 	APIUrl() string
+	Context() string
+	Contexts() ([]string, error)
 	Format() string
 	InsecureSkipTLSVerify() bool
 	Namespace() string
@@ -51,6 +67,8 @@ type Read interface {
 // Write contains all methods related to setting and writting configuration
 type Write interface {
 	SaveAPIUrl(string) error
+	UseContext(string) error
+	DeleteContext(string) error
 	SaveFormat(string) error
 	SaveInsecureSkipTLSVerify(bool) error
 	SaveNamespace(string) error
@@ -58,3 +76,14 @@ type Write interface {
 	SaveTrustedCAFile(string) error
 	SaveTimeout(time.Duration) error
 }
+
+var contextNameRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// ValidateContextName returns an error if the name of a context can't be used
+// as a directory name.
+func ValidateContextName(name string) error {
+	if !contextNameRe.MatchString(name) {
+		return fmt.Errorf("invalid context name %q: it must start with a letter or a digit, followed by letters, digits, '_', '.' or '-'", name)
+	}
+	return nil
+}
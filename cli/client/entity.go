@@ -5,6 +5,7 @@ import (
 
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/backend/apid/actions"
 )
 
 // EntitiesPath is the api path for entities.
@@ -15,6 +16,21 @@ func (client *RestClient) DeleteEntity(namespace, name string) (err error) {
 	return client.Delete(EntitiesPath(namespace, name))
 }
 
+// UndeleteEntity restores an entity deleted recently, along with its state
+func (client *RestClient) UndeleteEntity(namespace, name string) error {
+	path := EntitiesPath(namespace, name, "undelete")
+	res, err := client.R().Post(path)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode() >= 400 {
+		return UnmarshalError(res)
+	}
+
+	return nil
+}
+
 // FetchEntity fetches a specific entity
 func (client *RestClient) FetchEntity(name string) (*corev2.Entity, error) {
 	path := EntitiesPath(client.config.Namespace(), name)
@@ -71,3 +87,30 @@ func (client *RestClient) CreateEntity(entity *corev2.Entity) (err error) {
 
 	return nil
 }
+
+// ImportEntities creates or replaces the given proxy entities in a single
+// request and returns the result of each operation
+func (client *RestClient) ImportEntities(entities []*corev2.Entity) ([]actions.BulkEntityResult, error) {
+	wrapped := make([]types.Wrapper, len(entities))
+	for i, entity := range entities {
+		wrapped[i] = types.WrapResource(entity)
+	}
+	bytes, err := json.Marshal(wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	path := EntitiesPath(client.config.Namespace(), "bulk")
+	res, err := client.R().SetBody(bytes).Post(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode() >= 400 {
+		return nil, UnmarshalError(res)
+	}
+
+	var results []actions.BulkEntityResult
+	err = json.Unmarshal(res.Body(), &results)
+	return results, err
+}
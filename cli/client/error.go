@@ -4,19 +4,39 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/sensu/sensu-go/backend/apid/actions"
 )
 
+const requestIDHeader = "X-Request-Id"
+
 // APIError describes an error message returned by the REST API
 type APIError struct {
-	Message string `json:"message"`
-	Code    uint32 `json:"code,omitempty"`
+	Message   string                   `json:"message"`
+	Code      uint32                   `json:"code,omitempty"`
+	Reason    string                   `json:"reason,omitempty"`
+	Retryable bool                     `json:"retryable,omitempty"`
+	Details   []actions.FieldViolation `json:"details,omitempty"`
+	RequestID string                   `json:"request_id,omitempty"`
 }
 
 func (a APIError) Error() string {
-	return a.Message
+	var b strings.Builder
+	b.WriteString(a.Message)
+	for _, detail := range a.Details {
+		if detail.Description == a.Message {
+			continue
+		}
+		fmt.Fprintf(&b, "\n  %s: %s", detail.Field, detail.Description)
+	}
+	// Only internal errors are worth reporting to an operator, who can find the
+	// request ID in the backend logs
+	if a.RequestID != "" && a.Reason == actions.InternalErr.String() {
+		fmt.Fprintf(&b, " (request ID: %s)", a.RequestID)
+	}
+	return b.String()
 }
 
 // UnmarshalError decode the API error
@@ -43,5 +63,9 @@ func UnmarshalError(res *resty.Response) error {
 		}
 	}
 
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = res.Header().Get(requestIDHeader)
+	}
+
 	return apiErr
 }
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalError(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		want      APIError
+		wantError string
+	}{
+		{
+			name:   "structured error",
+			status: http.StatusBadRequest,
+			body:   `{"message":"bad name","code":1,"reason":"invalid_argument","details":[{"field":"metadata.name","description":"must not be empty"}],"request_id":"abc"}`,
+			want: APIError{
+				Message:   "bad name",
+				Code:      uint32(actions.InvalidArgument),
+				Reason:    "invalid_argument",
+				Details:   []actions.FieldViolation{{Field: "metadata.name", Description: "must not be empty"}},
+				RequestID: "abc",
+			},
+			wantError: "bad name\n  metadata.name: must not be empty",
+		},
+		{
+			name:   "internal error",
+			status: http.StatusInternalServerError,
+			body:   `{"message":"boom","code":0,"reason":"internal","retryable":true,"request_id":"abc"}`,
+			want: APIError{
+				Message:   "boom",
+				Reason:    "internal",
+				Retryable: true,
+				RequestID: "abc",
+			},
+			wantError: "boom (request ID: abc)",
+		},
+		{
+			name:   "plain text error",
+			status: http.StatusUnauthorized,
+			body:   "Request unauthorized",
+			want: APIError{
+				Message:   "Request unauthorized",
+				RequestID: "xyz",
+			},
+			wantError: "Request unauthorized",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(requestIDHeader, "xyz")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			res, err := resty.New().R().Get(server.URL)
+			require.NoError(t, err)
+
+			err = UnmarshalError(res)
+			assert.Equal(t, tt.want, err)
+			assert.Equal(t, tt.wantError, err.Error())
+		})
+	}
+}
@@ -1,11 +1,18 @@
 package client
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
 	"time"
 
+	"github.com/go-resty/resty/v2"
 	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/core/v3/types"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 )
 
 // EventsPath is the api path for events.
@@ -28,6 +35,23 @@ func (client *RestClient) FetchEvent(entity, check string) (*corev2.Event, error
 	return wrapper.Value.(*corev2.Event), err
 }
 
+// FetchEventHandlerResults fetches the last handler results of an event
+func (client *RestClient) FetchEventHandlerResults(entity, check string) ([]*storev2.HandlerResult, error) {
+	path := EventsPath(client.config.Namespace(), entity, check, "handler-results")
+	res, err := client.R().Get(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode() >= 400 {
+		return nil, UnmarshalError(res)
+	}
+
+	var results []*storev2.HandlerResult
+	err = json.Unmarshal(res.Body(), &results)
+	return results, err
+}
+
 // DeleteEvent deletes an event.
 func (client *RestClient) DeleteEvent(namespace, entity, check string) error {
 	return client.Delete(EventsPath(namespace, entity, check))
@@ -61,3 +85,101 @@ func (client *RestClient) ResolveEvent(event *corev2.Event) error {
 	event.Timestamp = event.Check.Executed
 	return client.UpdateEvent(event)
 }
+
+// BulkResult is the outcome of a bulk operation on one of its resources.
+type BulkResult struct {
+	Name   string    `json:"name"`
+	Status int       `json:"status"`
+	Error  *APIError `json:"error,omitempty"`
+}
+
+// ResolveEvents resolves the failing events of a namespace matching the
+// selectors of the options. The selection is done by the backend.
+func (client *RestClient) ResolveEvents(namespace string, options *ListOptions) ([]BulkResult, error) {
+	request := client.R().SetQueryParam("source", "sensuctl")
+	ApplyListOptions(request, options)
+	res, err := request.Post(bulkEventsPath(namespace, "resolve"))
+	return bulkResults(res, err)
+}
+
+// DeleteEvents deletes the events of a namespace matching the selectors of the
+// options. The selection is done by the backend.
+func (client *RestClient) DeleteEvents(namespace string, options *ListOptions) ([]BulkResult, error) {
+	request := client.R()
+	ApplyListOptions(request, options)
+	res, err := request.Delete(bulkEventsPath(namespace))
+	return bulkResults(res, err)
+}
+
+func bulkEventsPath(namespace string, elems ...string) string {
+	return path.Join(append([]string{path.Dir(EventsPath(namespace)), "bulk", "events"}, elems...)...)
+}
+
+func bulkResults(res *resty.Response, err error) ([]BulkResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode() >= 400 {
+		return nil, UnmarshalError(res)
+	}
+	var results []BulkResult
+	err = json.Unmarshal(res.Body(), &results)
+	return results, err
+}
+
+// StreamEvents streams the events of a namespace matching the selectors of the
+// options, calling fn for every event, until the stream ends, the context is
+// done or fn returns an error. The stored events whose ID, their timestamp, is
+// greater than or equal to lastEventID are sent first, if it is set. It
+// returns the ID of the last event received, so that the stream can be
+// resumed.
+func (client *RestClient) StreamEvents(ctx context.Context, namespace string, options *ListOptions, lastEventID string, fn func(*corev2.Event) error) (string, error) {
+	request := client.R().SetContext(ctx).SetDoNotParseResponse(true)
+	ApplyListOptions(request, options)
+	request.SetHeader("Accept", "text/event-stream")
+	if lastEventID != "" {
+		request.SetHeader("Last-Event-ID", lastEventID)
+	}
+
+	res, err := request.Get(EventsPath(namespace, "stream"))
+	if err != nil {
+		return lastEventID, err
+	}
+	body := res.RawBody()
+	defer body.Close()
+
+	if res.StatusCode() >= 400 {
+		var apiErr APIError
+		if err := json.NewDecoder(body).Decode(&apiErr); err != nil || apiErr.Message == "" {
+			apiErr.Message = fmt.Sprintf("the API returned: %s", res.Status())
+		}
+		return lastEventID, apiErr
+	}
+
+	// The events are sent as server-sent events, whose data is on a single
+	// line
+	var id string
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			var event corev2.Event
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+				return lastEventID, err
+			}
+			if id != "" {
+				lastEventID = id
+			}
+			if err := fn(&event); err != nil {
+				return lastEventID, err
+			}
+		}
+	}
+	// The stream ends when the connection times out, or is closed by the
+	// backend
+	return lastEventID, nil
+}
@@ -121,6 +121,42 @@ func (client *RestClient) List(path string, objs interface{}, options *ListOptio
 	return nil
 }
 
+// ListPages sends GET requests for all wrapped objects at the given path, one
+// page of options.ChunkSize objects at a time, and calls fn with the objects
+// of each page. Unlike List, the objects are never all held in memory.
+func (client *RestClient) ListPages(path string, options *ListOptions, fn func([]*types.Wrapper) error) error {
+	for {
+		request := client.R()
+		ApplyListOptions(request, options)
+
+		resp, err := request.Get(path)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode() >= 400 {
+			return UnmarshalError(resp)
+		}
+
+		var page []*types.Wrapper
+		if body := resp.Body(); len(body) > 0 {
+			if err := json.Unmarshal(body, &page); err != nil {
+				return err
+			}
+		}
+		if len(page) > 0 {
+			if err := fn(page); err != nil {
+				return err
+			}
+		}
+
+		options.ContinueToken = resp.Header().Get(corev2.PaginationContinueHeader)
+		if options.ContinueToken == "" {
+			return nil
+		}
+	}
+}
+
 // Post sends a POST request with obj as the payload to the given path
 func (client *RestClient) Post(path string, resource corev3.Resource) error {
 	wrapper := types.WrapResource(resource)
@@ -167,6 +203,16 @@ func (client *RestClient) Put(path string, obj interface{}) error {
 
 // PutResource ...
 func (client *RestClient) PutResource(r types.Wrapper) error {
+	return client.putResource(r, false)
+}
+
+// PutResourceDryRun validates the put of a resource by the backend, without
+// persisting it.
+func (client *RestClient) PutResourceDryRun(r types.Wrapper) error {
+	return client.putResource(r, true)
+}
+
+func (client *RestClient) putResource(r types.Wrapper, dryRun bool) error {
 	var path string
 	switch value := r.Value.(type) {
 	case corev2.Resource:
@@ -180,7 +226,11 @@ func (client *RestClient) PutResource(r types.Wrapper) error {
 		return err
 	}
 
-	res, err := client.R().SetBody(bytes).Put(path)
+	req := client.R().SetBody(bytes)
+	if dryRun {
+		req.SetQueryParam("dryRun", "server")
+	}
+	res, err := req.Put(path)
 	if err != nil {
 		return fmt.Errorf("PUT %q: %s", path, err)
 	}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/cli/client/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListPages(t *testing.T) {
+	pages := map[string][]types.Wrapper{
+		"": {
+			types.WrapResource(corev2.FixtureCheckConfig("a")),
+			types.WrapResource(corev2.FixtureCheckConfig("b")),
+		},
+		"b": {
+			types.WrapResource(corev2.FixtureCheckConfig("c")),
+		},
+	}
+	testHandler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2", r.URL.Query().Get("limit"))
+		assert.Equal(t, "region==eu", r.URL.Query().Get("labelSelector"))
+		continueToken := r.URL.Query().Get("continue")
+		if continueToken == "" {
+			w.Header().Set(corev2.PaginationContinueHeader, "b")
+		}
+		_ = json.NewEncoder(w).Encode(pages[continueToken])
+	}
+	server := httptest.NewServer(http.HandlerFunc(testHandler))
+	defer server.Close()
+
+	mockConfig := &config.MockConfig{}
+	client := &RestClient{resty: resty.New(), config: mockConfig}
+
+	mockConfig.On("APIUrl").Return(server.URL)
+	mockConfig.On("Tokens").Return(&corev2.Tokens{Access: "foo"})
+	mockConfig.On("APIKey").Return("")
+
+	var names [][]string
+	err := client.ListPages("/api/core/v2/namespaces/default/checks", &ListOptions{ChunkSize: 2, LabelSelector: "region==eu"}, func(page []*types.Wrapper) error {
+		var pageNames []string
+		for _, w := range page {
+			pageNames = append(pageNames, w.Value.(*corev2.CheckConfig).Name)
+		}
+		names = append(names, pageNames)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, names)
+}
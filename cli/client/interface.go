@@ -1,12 +1,16 @@
 package client
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
 	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 )
 
 // ListOptions represents the various options that can be used when listing
@@ -53,6 +57,8 @@ type APIClient interface {
 type APIKeyClient interface {
 	// PostAPIKey creates an api key and returns the location header.
 	PostAPIKey(path string, obj interface{}) (corev2.APIKeyResponse, error)
+	// RotateAPIKey replaces the secret of an api key and returns the new secret.
+	RotateAPIKey(name string, gracePeriod time.Duration) (corev2.APIKeyResponse, error)
 }
 
 // GenericClient exposes generic resource methods.
@@ -63,6 +69,9 @@ type GenericClient interface {
 	Get(path string, obj interface{}) error
 	// List retrieves all keys with the given path prefix and stores them into objs
 	List(path string, objs interface{}, options *ListOptions, header *http.Header) error
+	// ListPages retrieves all wrapped objects with the given path prefix, one
+	// page at a time, and calls fn with the objects of each page
+	ListPages(path string, options *ListOptions, fn func([]*types.Wrapper) error) error
 	// Post creates the given obj at the specified path
 	Post(path string, obj corev3.Resource) error
 	// Put creates the given obj at the specified path
@@ -72,6 +81,10 @@ type GenericClient interface {
 
 	// PutResource puts a resource according to its URIPath.
 	PutResource(types.Wrapper) error
+
+	// PutResourceDryRun validates the put of a resource by the backend,
+	// without persisting it.
+	PutResourceDryRun(types.Wrapper) error
 }
 
 // AuthenticationAPIClient client methods for authenticating
@@ -121,6 +134,8 @@ type EntityAPIClient interface {
 	DeleteEntity(string, string) error
 	FetchEntity(ID string) (*corev2.Entity, error)
 	UpdateEntity(entity *corev2.Entity) error
+	ImportEntities(entities []*corev2.Entity) ([]actions.BulkEntityResult, error)
+	UndeleteEntity(namespace, name string) error
 }
 
 // FilterAPIClient client methods for filters
@@ -139,6 +154,18 @@ type EventAPIClient interface {
 	DeleteEvent(namespace, entity, check string) error
 	UpdateEvent(*corev2.Event) error
 	ResolveEvent(*corev2.Event) error
+	FetchEventHandlerResults(entity, check string) ([]*storev2.HandlerResult, error)
+
+	// StreamEvents streams the events of a namespace, until the stream ends.
+	StreamEvents(ctx context.Context, namespace string, options *ListOptions, lastEventID string, fn func(*corev2.Event) error) (string, error)
+
+	// ResolveEvents resolves the failing events of a namespace matching the
+	// selectors of the options.
+	ResolveEvents(namespace string, options *ListOptions) ([]BulkResult, error)
+
+	// DeleteEvents deletes the events of a namespace matching the selectors
+	// of the options.
+	DeleteEvents(namespace string, options *ListOptions) ([]BulkResult, error)
 }
 
 // HandlerAPIClient client methods for handlers
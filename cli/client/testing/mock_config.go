@@ -21,6 +21,18 @@ func (m *MockConfig) APIUrl() string {
 	return args.String(0)
 }
 
+// Context mocks the context config
+func (m *MockConfig) Context() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+// Contexts mocks the contexts config
+func (m *MockConfig) Contexts() ([]string, error) {
+	args := m.Called()
+	return args.Get(0).([]string), args.Error(1)
+}
+
 // Format mocks the format config
 func (m *MockConfig) Format() string {
 	args := m.Called()
@@ -57,6 +69,18 @@ func (m *MockConfig) SaveAPIUrl(url string) error {
 	return args.Error(0)
 }
 
+// UseContext mocks switching the current context
+func (m *MockConfig) UseContext(name string) error {
+	args := m.Called(name)
+	return args.Error(0)
+}
+
+// DeleteContext mocks deleting a context
+func (m *MockConfig) DeleteContext(name string) error {
+	args := m.Called(name)
+	return args.Error(0)
+}
+
 // SaveFormat mocks saving the format
 func (m *MockConfig) SaveFormat(format string) error {
 	args := m.Called(format)
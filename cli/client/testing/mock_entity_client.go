@@ -2,6 +2,7 @@ package testing
 
 import (
 	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/backend/apid/actions"
 )
 
 // FetchEntity for use with mock lib
@@ -27,3 +28,15 @@ func (c *MockClient) CreateEntity(entity *corev2.Entity) error {
 	args := c.Called(entity)
 	return args.Error(0)
 }
+
+// ImportEntities for use with mock lib
+func (c *MockClient) ImportEntities(entities []*corev2.Entity) ([]actions.BulkEntityResult, error) {
+	args := c.Called(entities)
+	return args.Get(0).([]actions.BulkEntityResult), args.Error(1)
+}
+
+// UndeleteEntity for use with mock lib
+func (c *MockClient) UndeleteEntity(namespace, name string) error {
+	args := c.Called(namespace, name)
+	return args.Error(0)
+}
@@ -1,7 +1,11 @@
 package testing
 
 import (
+	"context"
+
 	corev2 "github.com/sensu/core/v2"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
+	"github.com/sensu/sensu-go/cli/client"
 )
 
 // FetchEvent for use with mock lib
@@ -27,3 +31,33 @@ func (c *MockClient) ResolveEvent(event *corev2.Event) error {
 	args := c.Called(event)
 	return args.Error(0)
 }
+
+// FetchEventHandlerResults for use with mock lib
+func (c *MockClient) FetchEventHandlerResults(entity, check string) ([]*storev2.HandlerResult, error) {
+	args := c.Called(entity, check)
+	return args.Get(0).([]*storev2.HandlerResult), args.Error(1)
+}
+
+// StreamEvents for use with mock lib. The events given to the mock are passed
+// to fn.
+func (c *MockClient) StreamEvents(ctx context.Context, namespace string, options *client.ListOptions, lastEventID string, fn func(*corev2.Event) error) (string, error) {
+	args := c.Called(ctx, namespace, options, lastEventID)
+	for _, event := range args.Get(0).([]*corev2.Event) {
+		if err := fn(event); err != nil {
+			return lastEventID, err
+		}
+	}
+	return args.String(1), args.Error(2)
+}
+
+// ResolveEvents for use with mock lib
+func (c *MockClient) ResolveEvents(namespace string, options *client.ListOptions) ([]client.BulkResult, error) {
+	args := c.Called(namespace, options)
+	return args.Get(0).([]client.BulkResult), args.Error(1)
+}
+
+// DeleteEvents for use with mock lib
+func (c *MockClient) DeleteEvents(namespace string, options *client.ListOptions) ([]client.BulkResult, error) {
+	args := c.Called(namespace, options)
+	return args.Get(0).([]client.BulkResult), args.Error(1)
+}
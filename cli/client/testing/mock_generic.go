@@ -27,6 +27,19 @@ func (c *MockClient) List(path string, objs interface{}, options *client.ListOpt
 	return args.Error(0)
 }
 
+// ListPages calls fn with each page of the [][]*types.Wrapper returned
+func (c *MockClient) ListPages(path string, options *client.ListOptions, fn func([]*types.Wrapper) error) error {
+	args := c.Called(path, options)
+	if pages, ok := args.Get(0).([][]*types.Wrapper); ok {
+		for _, page := range pages {
+			if err := fn(page); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
 // Post ...
 func (c *MockClient) Post(path string, obj corev3.Resource) error {
 	args := c.Called(path, obj)
@@ -50,3 +63,9 @@ func (c *MockClient) PutResource(r types.Wrapper) error {
 	args := c.Called(r)
 	return args.Error(0)
 }
+
+// PutResourceDryRun ...
+func (c *MockClient) PutResourceDryRun(r types.Wrapper) error {
+	args := c.Called(r)
+	return args.Error(0)
+}
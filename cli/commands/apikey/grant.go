@@ -3,12 +3,16 @@ package apikey
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
+	apikeys "github.com/sensu/sensu-go/backend/authentication/apikey"
 	"github.com/sensu/sensu-go/cli"
 	"github.com/spf13/cobra"
 )
 
+const flagExpiresIn = "expires-in"
+
 // GrantCommand adds a command that creates apikeys.
 func GrantCommand(cli *cli.SensuCli) *cobra.Command {
 	cmd := &cobra.Command{
@@ -24,6 +28,16 @@ func GrantCommand(cli *cli.SensuCli) *cobra.Command {
 			apikey := &corev2.APIKey{
 				Username: args[0],
 			}
+			expiresIn, err := cmd.Flags().GetDuration(flagExpiresIn)
+			if err != nil {
+				return err
+			}
+			if expiresIn < 0 {
+				return errors.New("the expiration must be positive")
+			}
+			if expiresIn > 0 {
+				apikeys.SetExpiresAt(apikey, time.Now().Add(expiresIn))
+			}
 
 			response, err := cli.Client.PostAPIKey(apikey.URIPath(), apikey)
 			if err != nil {
@@ -37,5 +51,7 @@ func GrantCommand(cli *cli.SensuCli) *cobra.Command {
 		},
 	}
 
+	cmd.Flags().Duration(flagExpiresIn, 0, "duration after which the api-key expires (e.g. 720h), never by default")
+
 	return cmd
 }
@@ -3,8 +3,10 @@ package apikey
 import (
 	"errors"
 	"testing"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
+	apikeys "github.com/sensu/sensu-go/backend/authentication/apikey"
 	client "github.com/sensu/sensu-go/cli/client/testing"
 	test "github.com/sensu/sensu-go/cli/commands/testing"
 	"github.com/stretchr/testify/assert"
@@ -51,6 +53,25 @@ func TestGrantCommandWithArgs(t *testing.T) {
 	require.NoError(t, err)
 	assert.Regexp("Key:  keystuff", out)
 }
+
+func TestGrantCommandWithExpiration(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewMockCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("PostAPIKey", mock.Anything, mock.MatchedBy(func(key *corev2.APIKey) bool {
+		expiresAt, err := apikeys.ExpiresAt(key)
+		return err == nil && expiresAt.After(time.Now().Add(23*time.Hour))
+	})).Return(corev2.APIKeyResponse{Name: "mykey", Key: "keystuff"}, nil)
+
+	cmd := GrantCommand(cli)
+	require.NoError(t, cmd.Flags().Set("expires-in", "24h"))
+	out, err := test.RunCmd(cmd, []string{"user1"})
+
+	require.NoError(t, err)
+	assert.Regexp("Key:  keystuff", out)
+}
+
 func TestGrantCommandServerError(t *testing.T) {
 	assert := assert.New(t)
 
@@ -18,6 +18,7 @@ func HelpCommand(cli *cli.SensuCli) *cobra.Command {
 	cmd.AddCommand(
 		GrantCommand(cli),
 		RevokeCommand(cli),
+		RotateCommand(cli),
 		ListCommand(cli),
 		InfoCommand(cli),
 	)
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	corev2 "github.com/sensu/core/v2"
+	apikeys "github.com/sensu/sensu-go/backend/authentication/apikey"
 	"github.com/sensu/sensu-go/cli"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
 	"github.com/sensu/sensu-go/cli/elements/list"
@@ -66,6 +67,14 @@ func printToList(v interface{}, writer io.Writer) error {
 				Label: "Created At",
 				Value: time.Unix(r.CreatedAt, 0).String(),
 			},
+			{
+				Label: "Expires At",
+				Value: humanTime(apikeys.ExpiresAt(r)),
+			},
+			{
+				Label: "Last Used",
+				Value: humanTime(apikeys.LastUsedAt(r)),
+			},
 		},
 	}
 
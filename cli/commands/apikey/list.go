@@ -4,9 +4,11 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
+	apikeys "github.com/sensu/sensu-go/backend/authentication/apikey"
 	"github.com/sensu/sensu-go/cli"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
 	"github.com/sensu/sensu-go/cli/commands/timeutil"
@@ -49,7 +51,7 @@ func ListCommand(cli *cli.SensuCli) *cobra.Command {
 		},
 	}
 
-	helpers.AddFormatFlag(cmd.Flags())
+	helpers.AddListFormatFlag(cmd.Flags())
 	helpers.AddAllNamespace(cmd.Flags())
 	helpers.AddFieldSelectorFlag(cmd.Flags())
 	helpers.AddLabelSelectorFlag(cmd.Flags())
@@ -91,7 +93,38 @@ func printToTable(results interface{}, writer io.Writer) {
 				return timeutil.HumanTimestamp(apikey.CreatedAt)
 			},
 		},
+		{
+			Title: "Expires At",
+			CellTransformer: func(data interface{}) string {
+				apikey, ok := data.(corev2.APIKey)
+				if !ok {
+					return cli.TypeError
+				}
+				return humanTime(apikeys.ExpiresAt(&apikey))
+			},
+		},
+		{
+			Title: "Last Used",
+			CellTransformer: func(data interface{}) string {
+				apikey, ok := data.(corev2.APIKey)
+				if !ok {
+					return cli.TypeError
+				}
+				return humanTime(apikeys.LastUsedAt(&apikey))
+			},
+		},
 	})
 
 	table.Render(writer, results)
 }
+
+// humanTime formats the times kept in the annotations of the api-keys.
+func humanTime(t time.Time, err error) string {
+	if err != nil {
+		return "invalid"
+	}
+	if t.IsZero() {
+		return timeutil.HumanTimestamp(0)
+	}
+	return timeutil.HumanTimestamp(t.Unix())
+}
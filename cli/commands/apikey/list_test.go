@@ -102,6 +102,8 @@ func TestListCommandRunEClosureWithTable(t *testing.T) {
 	assert.Contains(out, "Name")
 	assert.Contains(out, "Username")
 	assert.Contains(out, "Created At")
+	assert.Contains(out, "Expires At")
+	assert.Contains(out, "Last Used")
 }
 
 func TestListCommandRunEClosureWithErr(t *testing.T) {
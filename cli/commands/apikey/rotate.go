@@ -0,0 +1,52 @@
+package apikey
+
+import (
+	"errors"
+	"fmt"
+
+	apikeys "github.com/sensu/sensu-go/backend/authentication/apikey"
+	"github.com/sensu/sensu-go/cli"
+	"github.com/spf13/cobra"
+)
+
+const flagGracePeriod = "grace-period"
+
+// RotateCommand adds a command that rotates the secret of apikeys.
+func RotateCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "rotate [NAME]",
+		Short:        "rotate the secret of an api-key",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+
+			gracePeriod, err := cmd.Flags().GetDuration(flagGracePeriod)
+			if err != nil {
+				return err
+			}
+			if gracePeriod < 0 {
+				return errors.New("the grace period must be positive")
+			}
+
+			response, err := cli.Client.RotateAPIKey(args[0], gracePeriod)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Rotated the API key. Save this key as it will not be retrievable later!\n")
+			fmt.Fprintf(cmd.OutOrStdout(), "Name: %s\n", response.Name)
+			fmt.Fprintf(cmd.OutOrStdout(), "Key:  %s\n", response.Key)
+			if gracePeriod > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "The previous key is revoked in %s\n", gracePeriod)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Duration(flagGracePeriod, apikeys.DefaultRotationGracePeriod, "duration during which the previous key is still accepted")
+
+	return cmd
+}
@@ -0,0 +1,67 @@
+package apikey
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	client "github.com/sensu/sensu-go/cli/client/testing"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewMockCLI()
+	cmd := RotateCommand(cli)
+
+	assert.NotNil(cmd, "cmd should be returned")
+	assert.NotNil(cmd.RunE, "cmd should be able to be executed")
+	assert.Regexp("rotate", cmd.Use)
+	assert.Regexp("api-key", cmd.Short)
+}
+
+func TestRotateCommandWithoutArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewMockCLI()
+	cmd := RotateCommand(cli)
+	out, err := test.RunCmd(cmd, []string{})
+
+	assert.NotEmpty(out)
+	assert.Error(err)
+}
+
+func TestRotateCommandWithArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewMockCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("RotateAPIKey", "mykey", 10*time.Minute).Return(corev2.APIKeyResponse{Name: "mykey", Key: "keystuff"}, nil)
+
+	cmd := RotateCommand(cli)
+	require.NoError(t, cmd.Flags().Set("grace-period", "10m"))
+	out, err := test.RunCmd(cmd, []string{"mykey"})
+
+	require.NoError(t, err)
+	assert.Regexp("Key:  keystuff", out)
+	assert.Regexp("revoked in 10m0s", out)
+}
+
+func TestRotateCommandServerError(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewMockCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("RotateAPIKey", "mykey", time.Hour).Return(corev2.APIKeyResponse{}, errors.New("err"))
+
+	cmd := RotateCommand(cli)
+	out, err := test.RunCmd(cmd, []string{"mykey"})
+
+	assert.Empty(out)
+	assert.Error(err)
+	assert.Equal("err", err.Error())
+}
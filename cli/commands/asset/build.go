@@ -0,0 +1,341 @@
+package asset
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/spf13/cobra"
+)
+
+var buildDescription = `sensuctl asset build
+
+Package a plugin directory into asset tarballs, and generate the definition of
+the asset with the URL and the SHA-512 sum of each build. Example:
+$ sensuctl asset build check-disk --dir ./dist --url https://assets.example.com/check-disk/1.0.0
+$ sensuctl create -f check-disk.yml
+
+The directory is packaged as a single build, unless it has subdirectories named
+after platforms, like linux_amd64 or windows_386, which are packaged as one
+build each, filtered on the operating system and the architecture of the
+entities. Only the bin, lib and include directories of a build are packaged,
+which is the layout the agents expect.
+
+The tarballs are uploaded with --upload-url, by HTTP PUT requests to the URL
+followed by the name of each tarball, which works with web servers and
+S3-compatible buckets accepting uploads. --upload-header adds headers to the
+requests, for instance to authenticate them.
+`
+
+// platformDir matches the names of the build directories of the platforms,
+// OS_ARCH.
+var platformDir = regexp.MustCompile(`^([a-z0-9]+)_([a-z0-9]+)$`)
+
+// assetDirs are the directories of the content of an asset.
+var assetDirs = []string{"bin", "lib", "include"}
+
+// BuildCommand adds a command that packages a plugin directory into an asset.
+func BuildCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "build [NAME]",
+		Short:        "package a plugin directory into asset tarballs and generate the asset definition",
+		Long:         buildDescription,
+		SilenceUsage: true,
+		RunE:         buildCommandExecute(cli),
+	}
+
+	_ = cmd.Flags().String("dir", ".", "directory of the plugin to package")
+	_ = cmd.Flags().StringP("url", "u", "", "base URL the tarballs are downloaded from, defaults to --upload-url")
+	_ = cmd.Flags().String("version", "", "version of the asset, added to the names of the tarballs")
+	_ = cmd.Flags().StringP("output-dir", "o", ".", "directory the tarballs and the asset definition are written to")
+	_ = cmd.Flags().String("upload-url", "", "base URL the tarballs are uploaded to")
+	_ = cmd.Flags().StringArray("upload-header", nil, `header of the upload requests, "KEY: VALUE"`)
+
+	return cmd
+}
+
+func buildCommandExecute(cli *cli.SensuCli) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			_ = cmd.Help()
+			return errors.New("invalid argument(s) received")
+		}
+		name := args[0]
+
+		dir, _ := cmd.Flags().GetString("dir")
+		baseURL, _ := cmd.Flags().GetString("url")
+		version, _ := cmd.Flags().GetString("version")
+		outputDir, _ := cmd.Flags().GetString("output-dir")
+		uploadURL, _ := cmd.Flags().GetString("upload-url")
+		uploadHeaders, _ := cmd.Flags().GetStringArray("upload-header")
+
+		if baseURL == "" {
+			baseURL = uploadURL
+		}
+		if baseURL == "" {
+			return errors.New("the URL of the tarballs is required, with --url or --upload-url")
+		}
+		headers, err := parseHeaders(uploadHeaders)
+		if err != nil {
+			return err
+		}
+
+		if err := corev2.ValidateAssetName(name); err != nil {
+			return err
+		}
+		asset := &corev2.Asset{ObjectMeta: corev2.NewObjectMeta(name, cli.Config.Namespace())}
+
+		builds, err := findBuilds(dir)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		for _, build := range builds {
+			filename := tarballName(name, version, build.platform)
+			tarball := filepath.Join(outputDir, filename)
+			sum, err := packageBuild(build.dir, tarball)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Wrote %s\n", tarball)
+
+			if uploadURL != "" {
+				if err := upload(tarball, joinURL(uploadURL, filename), headers); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "Uploaded %s\n", joinURL(uploadURL, filename))
+			}
+
+			asset.Builds = append(asset.Builds, &corev2.AssetBuild{
+				URL:     joinURL(baseURL, filename),
+				Sha512:  sum,
+				Filters: build.filters(),
+			})
+		}
+
+		if err := asset.Validate(); err != nil {
+			return err
+		}
+		definition := filepath.Join(outputDir, name+".yml")
+		f, err := os.Create(definition)
+		if err != nil {
+			return err
+		}
+		if err := helpers.PrintYAML(asset, f); err != nil {
+			_ = f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "Wrote %s\n", definition)
+		return err
+	}
+}
+
+// assetBuild is the directory of a build of the asset, and its platform,
+// empty for the single build of a plugin directory without platforms.
+type assetBuild struct {
+	dir      string
+	platform string
+}
+
+// filters returns the filters of the build on the platform of the entities.
+func (b assetBuild) filters() []string {
+	match := platformDir.FindStringSubmatch(b.platform)
+	if match == nil {
+		return nil
+	}
+	return []string{
+		fmt.Sprintf("entity.system.os == '%s'", match[1]),
+		fmt.Sprintf("entity.system.arch == '%s'", match[2]),
+	}
+}
+
+// findBuilds returns the builds of a plugin directory: its platform
+// subdirectories, or the directory itself if it has none.
+func findBuilds(dir string) ([]assetBuild, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var builds []assetBuild
+	for _, entry := range entries {
+		if entry.IsDir() && platformDir.MatchString(entry.Name()) {
+			builds = append(builds, assetBuild{dir: filepath.Join(dir, entry.Name()), platform: entry.Name()})
+		}
+	}
+	if len(builds) == 0 {
+		builds = []assetBuild{{dir: dir}}
+	}
+	for _, build := range builds {
+		if !hasAssetDirs(build.dir) {
+			return nil, fmt.Errorf("%s has none of the %s directories of an asset", build.dir, strings.Join(assetDirs, ", "))
+		}
+	}
+	sort.Slice(builds, func(i, j int) bool { return builds[i].platform < builds[j].platform })
+	return builds, nil
+}
+
+func hasAssetDirs(dir string) bool {
+	for _, name := range assetDirs {
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil && info.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+// tarballName returns the name of the tarball of a build,
+// NAME[_VERSION][_OS_ARCH].tar.gz.
+func tarballName(name, version, platform string) string {
+	parts := []string{name}
+	for _, part := range []string{version, platform} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "_") + ".tar.gz"
+}
+
+// packageBuild writes the bin, lib and include directories of a build to a
+// gzipped tarball, and returns its SHA-512 sum. The paths are relative to the
+// build directory, and the owners of the files are not kept.
+func packageBuild(dir, tarball string) (sum string, err error) {
+	f, err := os.Create(tarball)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	hash := sha512.New()
+	gz := gzip.NewWriter(io.MultiWriter(f, hash))
+	tw := tar.NewWriter(gz)
+	for _, name := range assetDirs {
+		root := filepath.Join(dir, name)
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			continue
+		}
+		if err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			return addFile(tw, dir, path, info)
+		}); err != nil {
+			return "", err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// addFile adds a file to a tarball, with its path relative to dir.
+func addFile(tw *tar.Writer, dir, path string, info os.FileInfo) error {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return err
+	}
+	link := ""
+	if info.Mode()&os.ModeSymlink != 0 {
+		if link, err = os.Readlink(path); err != nil {
+			return err
+		}
+	}
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.ToSlash(rel)
+	if info.IsDir() {
+		header.Name += "/"
+	}
+	header.Uid, header.Gid, header.Uname, header.Gname = 0, 0, "", ""
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+	content, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+	_, err = io.Copy(tw, content)
+	return err
+}
+
+// upload uploads a tarball with a PUT request.
+func upload(tarball, url string, headers map[string]string) error {
+	f, err := os.Open(tarball)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, f)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", "application/gzip")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("error uploading %s: %s", tarball, resp.Status)
+	}
+	return nil
+}
+
+// parseHeaders parses the "KEY: VALUE" headers of the upload requests.
+func parseHeaders(values []string) (map[string]string, error) {
+	headers := make(map[string]string, len(values))
+	for _, value := range values {
+		k, v, ok := strings.Cut(value, ":")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("invalid header %q, expected KEY: VALUE", value)
+		}
+		headers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return headers, nil
+}
+
+func joinURL(base, name string) string {
+	return strings.TrimSuffix(base, "/") + "/" + name
+}
@@ -0,0 +1,176 @@
+package asset
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha512"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/sensu/sensu-go/cli/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files ...string) {
+	t.Helper()
+	for _, file := range files {
+		path := filepath.Join(dir, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(file), 0755))
+	}
+}
+
+func tarballFiles(t *testing.T, path string) []string {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if header.Typeflag == tar.TypeReg {
+			names = append(names, header.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readAsset(t *testing.T, path string) *corev2.Asset {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	resources, err := resource.Parse(f)
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	asset, ok := resources[0].Value.(*corev2.Asset)
+	require.True(t, ok)
+	return asset
+}
+
+func TestBuildCommandPlatforms(t *testing.T) {
+	dir, output := t.TempDir(), t.TempDir()
+	writeFiles(t, dir,
+		"linux_amd64/bin/check-disk",
+		"linux_amd64/lib/libdisk.so",
+		"linux_amd64/README.md",
+		"windows_amd64/bin/check-disk.exe",
+		"docs/index.md",
+	)
+
+	cli := test.NewMockCLI()
+	cmd := BuildCommand(cli)
+	require.NoError(t, cmd.Flags().Set("dir", dir))
+	require.NoError(t, cmd.Flags().Set("output-dir", output))
+	require.NoError(t, cmd.Flags().Set("url", "https://assets.example.com/check-disk/"))
+	require.NoError(t, cmd.Flags().Set("version", "1.0.0"))
+	out, err := test.RunCmd(cmd, []string{"check-disk"})
+	require.NoError(t, err)
+	assert.Contains(t, out, "Wrote "+filepath.Join(output, "check-disk.yml"))
+
+	linux := filepath.Join(output, "check-disk_1.0.0_linux_amd64.tar.gz")
+	windows := filepath.Join(output, "check-disk_1.0.0_windows_amd64.tar.gz")
+	assert.Equal(t, []string{"bin/check-disk", "lib/libdisk.so"}, tarballFiles(t, linux))
+	assert.Equal(t, []string{"bin/check-disk.exe"}, tarballFiles(t, windows))
+
+	asset := readAsset(t, filepath.Join(output, "check-disk.yml"))
+	assert.Equal(t, "check-disk", asset.Name)
+	assert.Equal(t, "default", asset.Namespace)
+	require.Len(t, asset.Builds, 2)
+	build := asset.Builds[0]
+	assert.Equal(t, "https://assets.example.com/check-disk/check-disk_1.0.0_linux_amd64.tar.gz", build.URL)
+	assert.Equal(t, []string{"entity.system.os == 'linux'", "entity.system.arch == 'amd64'"}, build.Filters)
+	content, err := os.ReadFile(linux)
+	require.NoError(t, err)
+	sum := sha512.Sum512(content)
+	assert.Equal(t, hex.EncodeToString(sum[:]), build.Sha512)
+}
+
+func TestBuildCommandUpload(t *testing.T) {
+	var uploads []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		uploads = append(uploads, r.URL.Path)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	dir, output := t.TempDir(), t.TempDir()
+	writeFiles(t, dir, "bin/check-disk")
+
+	cli := test.NewMockCLI()
+	cmd := BuildCommand(cli)
+	require.NoError(t, cmd.Flags().Set("dir", dir))
+	require.NoError(t, cmd.Flags().Set("output-dir", output))
+	require.NoError(t, cmd.Flags().Set("upload-url", server.URL+"/assets"))
+	require.NoError(t, cmd.Flags().Set("upload-header", "Authorization: Bearer token"))
+	_, err := test.RunCmd(cmd, []string{"check-disk"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/assets/check-disk.tar.gz"}, uploads)
+
+	asset := readAsset(t, filepath.Join(output, "check-disk.yml"))
+	require.Len(t, asset.Builds, 1)
+	assert.Equal(t, server.URL+"/assets/check-disk.tar.gz", asset.Builds[0].URL)
+	assert.Empty(t, asset.Builds[0].Filters)
+}
+
+func TestBuildCommandErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "check-disk")
+
+	tests := []struct {
+		name    string
+		args    []string
+		flags   map[string]string
+		wantErr string
+	}{
+		{
+			name:    "no url",
+			args:    []string{"check-disk"},
+			flags:   map[string]string{"dir": dir},
+			wantErr: "URL of the tarballs is required",
+		},
+		{
+			name:    "no asset directories",
+			args:    []string{"check-disk"},
+			flags:   map[string]string{"dir": dir, "url": "https://assets.example.com"},
+			wantErr: "none of the bin, lib, include directories",
+		},
+		{
+			name:    "invalid header",
+			args:    []string{"check-disk"},
+			flags:   map[string]string{"dir": dir, "upload-url": "https://assets.example.com", "upload-header": "token"},
+			wantErr: "invalid header",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := BuildCommand(test.NewMockCLI())
+			for k, v := range tt.flags {
+				require.NoError(t, cmd.Flags().Set(k, v))
+			}
+			require.NoError(t, cmd.Flags().Set("output-dir", t.TempDir()))
+			_, err := test.RunCmd(cmd, tt.args)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -23,6 +23,7 @@ func HelpCommand(cli *cli.SensuCli) *cobra.Command {
 		DeleteCommand(cli),
 		AddCommand(cli),
 		OutdatedCommand(cli),
+		BuildCommand(cli),
 	)
 	return cmd
 }
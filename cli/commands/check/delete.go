@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/completion"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
 	"github.com/spf13/cobra"
 )
@@ -12,9 +13,10 @@ import (
 // DeleteCommand adds a command that allows user to delete checks
 func DeleteCommand(cli *cli.SensuCli) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "delete [NAME]",
-		Short:        "delete checks given name",
-		SilenceUsage: true,
+		Use:               "delete [NAME]",
+		ValidArgsFunction: completion.CheckNames(cli),
+		Short:             "delete checks given name",
+		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// If no name is present print out usage
 			if len(args) != 1 {
@@ -1,12 +1,20 @@
 package check
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	v2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/client"
+	"github.com/sensu/sensu-go/cli/commands/completion"
 	"github.com/sensu/sensu-go/cli/commands/flags"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
 	"github.com/spf13/cobra"
@@ -24,6 +32,7 @@ type executionOpts struct {
 func ExecuteCommand(cli *cli.SensuCli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:		"execute [NAME]",
+		ValidArgsFunction:	completion.CheckNames(cli),
 		Short:		"request a check execution",
 		SilenceUsage:	true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -64,23 +73,99 @@ func ExecuteCommand(cli *cli.SensuCli) *cobra.Command {
 				return err
 			}
 
+			// The results are streamed from the time of the request, so
+			// that none of them is missed
+			issued := time.Now().Unix()
 			if err := cli.Client.ExecuteCheck(adhocRequest); err != nil {
 				return err
 			}
 
 			fmt.Fprintln(cmd.OutOrStdout(), "Issued")
+
+			if wait, _ := cmd.Flags().GetBool("wait"); wait {
+				timeout, _ := cmd.Flags().GetDuration("wait-timeout")
+				return waitResults(cli, cmd.OutOrStdout(), opts.Name, issued, timeout)
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().StringP("reason", "r", "", "optional reason for requesting a check execution")
 	cmd.Flags().StringP("subscriptions", "s", "", "optional comma separated list of subscriptions to override the check configuration")
+	cmd.Flags().Bool("wait", false, "print the results of the execution as the agents send them back")
+	cmd.Flags().Duration("wait-timeout", time.Minute, "how long to wait for the results with --wait")
 
 	helpers.AddInteractiveFlag(cmd.Flags())
 
 	return cmd
 }
 
+// waitReconnectInterval is the time waited before resuming an event stream
+// that ended while waiting for the results of an execution.
+var waitReconnectInterval = time.Second
+
+// waitResults prints the results of the executions of a check, as streamed
+// from the events of the check, until the timeout or an interrupt. The
+// results are the events more recent than the request of the execution.
+func waitResults(cli *cli.SensuCli, w io.Writer, check string, issued int64, timeout time.Duration) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, timeout)
+	defer cancelTimeout()
+
+	fmt.Fprintf(w, "Waiting %s for the results, press Ctrl-C to stop\n", timeout)
+	opts := &client.ListOptions{FieldSelector: fmt.Sprintf("event.check.name == %q", check)}
+	lastEventID := fmt.Sprint(issued)
+
+	// The streams are resumed with the last event received, which the
+	// backend sends again along with the events that were missed
+	printed := map[string]int64{}
+	results := 0
+	onEvent := func(event *v2.Event) error {
+		if event.Entity == nil || !event.HasCheck() || event.Timestamp < issued {
+			return nil
+		}
+		if ts, ok := printed[event.Entity.Name]; ok && ts == event.Timestamp {
+			return nil
+		}
+		printed[event.Entity.Name] = event.Timestamp
+		results++
+		return printResult(w, event)
+	}
+
+	var err error
+	for ctx.Err() == nil {
+		lastEventID, err = cli.Client.StreamEvents(ctx, cli.Config.Namespace(), opts, lastEventID, onEvent)
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(waitReconnectInterval):
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "Received %d results\n", results)
+	return err
+}
+
+// printResult prints the status and the output of an execution of a check by
+// an agent.
+func printResult(w io.Writer, event *v2.Event) error {
+	if _, err := fmt.Fprintf(w, "%s: status %d\n", event.Entity.Name, event.Check.Status); err != nil {
+		return err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(event.Check.Output), "\n") {
+		if line == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "  %s\n", line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (opts *executionOpts) withFlags(flags *pflag.FlagSet) {
 	if name, _ := flags.GetString("check"); name != "" {
 		opts.Name = name
@@ -3,9 +3,11 @@ package check
 import (
 	"errors"
 	"testing"
+	"time"
 
 	v2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/backend/authentication/jwt"
+	clientpkg "github.com/sensu/sensu-go/cli/client"
 	clientmock "github.com/sensu/sensu-go/cli/client/testing"
 	test "github.com/sensu/sensu-go/cli/commands/testing"
 	"github.com/stretchr/testify/assert"
@@ -65,6 +67,43 @@ func TestExecuteCommandRunEClosureServerErr(t *testing.T) {
 	assert.Error(err)
 }
 
+func TestExecuteCommandWait(t *testing.T) {
+	waitReconnectInterval = time.Millisecond
+	cli := test.NewMockCLI()
+
+	now := time.Now().Unix()
+	old := v2.FixtureEvent("old", "name")
+	old.Timestamp = now - 60
+	ok := v2.FixtureEvent("web-1", "name")
+	ok.Timestamp = now + 1
+	ok.Check.Output = "OK\n"
+	failed := v2.FixtureEvent("web-2", "name")
+	failed.Timestamp = now + 2
+	failed.Check.Status = 2
+	failed.Check.Output = "CRITICAL\ndisk full"
+
+	client := cli.Client.(*clientmock.MockClient)
+	client.On("ExecuteCheck", mock.Anything).Return(nil)
+	client.On("StreamEvents", mock.Anything, "default", mock.MatchedBy(func(opts *clientpkg.ListOptions) bool {
+		return opts.FieldSelector == `event.check.name == "name"`
+	}), mock.Anything).Return([]*v2.Event{old, ok, failed}, "", nil)
+
+	config := cli.Config.(*clientmock.MockConfig)
+	_, accessToken, _ := jwt.AccessToken(v2.FixtureClaims("foo", nil))
+	config.On("Tokens").Return(&v2.Tokens{Access: accessToken})
+
+	cmd := ExecuteCommand(cli)
+	require.NoError(t, cmd.Flags().Set("wait", "true"))
+	require.NoError(t, cmd.Flags().Set("wait-timeout", "50ms"))
+	out, err := test.RunCmd(cmd, []string{"name"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Issued\nWaiting 50ms for the results, press Ctrl-C to stop\n"+
+		"web-1: status 0\n  OK\n"+
+		"web-2: status 2\n  CRITICAL\n  disk full\n"+
+		"Received 2 results\n", out)
+}
+
 func TestExecuteCommandRunEClosureMissingArgs(t *testing.T) {
 	assert := assert.New(t)
 	cli := test.NewMockCLI()
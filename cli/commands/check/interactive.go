@@ -7,6 +7,8 @@ import (
 	"github.com/AlecAivazis/survey/v2"
 	cron "github.com/robfig/cron/v3"
 	v2 "github.com/sensu/core/v2"
+	// Registers the output metric formats the agent supports beyond sensu/core
+	_ "github.com/sensu/sensu-go/agent/transformers"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
 	"github.com/spf13/pflag"
 )
@@ -5,15 +5,17 @@ import (
 	"fmt"
 
 	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/completion"
 	"github.com/spf13/cobra"
 )
 
 // UpdateCommand adds command that allows user to create new checks
 func UpdateCommand(cli *cli.SensuCli) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "update [NAME]",
-		Short:        "update checks",
-		SilenceUsage: false,
+		Use:               "update [NAME]",
+		ValidArgsFunction: completion.CheckNames(cli),
+		Short:             "update checks",
+		SilenceUsage:      false,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Print usage if we do not receive one argument
 			if len(args) != 1 {
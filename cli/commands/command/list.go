@@ -26,7 +26,7 @@ func ListCommand(cli *cli.SensuCli) *cobra.Command {
 		RunE:  listCommandExecute(cli),
 	}
 
-	helpers.AddFormatFlag(cmd.Flags())
+	helpers.AddListFormatFlag(cmd.Flags())
 
 	return cmd
 }
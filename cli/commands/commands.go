@@ -14,6 +14,7 @@ import (
 	"github.com/sensu/sensu-go/cli/commands/create"
 	"github.com/sensu/sensu-go/cli/commands/delete"
 	"github.com/sensu/sensu-go/cli/commands/describetype"
+	"github.com/sensu/sensu-go/cli/commands/diff"
 	"github.com/sensu/sensu-go/cli/commands/dump"
 	"github.com/sensu/sensu-go/cli/commands/edit"
 	"github.com/sensu/sensu-go/cli/commands/entity"
@@ -26,6 +27,7 @@ import (
 	"github.com/sensu/sensu-go/cli/commands/mutator"
 	"github.com/sensu/sensu-go/cli/commands/namespace"
 	"github.com/sensu/sensu-go/cli/commands/pipeline"
+	"github.com/sensu/sensu-go/cli/commands/restore"
 	"github.com/sensu/sensu-go/cli/commands/role"
 	"github.com/sensu/sensu-go/cli/commands/rolebinding"
 	"github.com/sensu/sensu-go/cli/commands/silenced"
@@ -63,9 +65,11 @@ func AddCommands(rootCmd *cobra.Command, cli *cli.SensuCli) {
 		silenced.HelpCommand(cli),
 		create.CreateCommand(cli),
 		delete.DeleteCommand(cli),
+		diff.Command(cli),
 		edit.Command(cli),
 		tessen.HelpCommand(cli),
 		dump.Command(cli),
+		restore.Command(cli),
 		command.HelpCommand(cli),
 		describetype.Command(cli),
 	)
@@ -21,10 +21,18 @@ For help using with ZSH:
 For help using with Bash:
 
     $ ` + cli.SensuCmdName + ` completion bash -h
+
+For help using with Fish:
+
+    $ ` + cli.SensuCmdName + ` completion fish -h
+
+The names of the checks, entities, handlers and namespaces are completed
+with the resources of the backend.
 	`
 
 	zshShell  = "zsh"
 	bashShell = "bash"
+	fishShell = "fish"
 )
 
 // Command defines new command to help installing completions in shell
@@ -32,7 +40,7 @@ func Command(rootCmd *cobra.Command) *cobra.Command {
 	exec := &completionExecutor{rootCmd: rootCmd}
 	cmd := &cobra.Command{
 		Use:   "completion",
-		Short: "Output shell completion code for the specified shell (bash or zsh)",
+		Short: "Output shell completion code for the specified shell (bash, zsh or fish)",
 		RunE:  exec.run,
 		Annotations: map[string]string{
 			// We want to be able to run this command regardless of whether the CLI
@@ -57,6 +65,8 @@ func (e *completionExecutor) run(cmd *cobra.Command, args []string) error {
 		return genZshCompletion(e.rootCmd)
 	} else if shell == bashShell {
 		return genBashCompletion(e.rootCmd)
+	} else if shell == fishShell {
+		return genFishCompletion(e.rootCmd)
 	} else if err != nil {
 		fmt.Fprintf(
 			cmd.OutOrStderr(),
@@ -80,6 +90,8 @@ func (e *completionExecutor) runHelp(cmd *cobra.Command, args []string) {
 		fmt.Fprintln(stdErr, zshUsage)
 	} else if shell == bashShell {
 		fmt.Fprintln(stdErr, bashUsage)
+	} else if shell == fishShell {
+		fmt.Fprintln(stdErr, fishUsage)
 	} else {
 		fmt.Fprintln(stdErr, longUsage)
 	}
@@ -88,7 +100,7 @@ func (e *completionExecutor) runHelp(cmd *cobra.Command, args []string) {
 func extractShell(args []string, i int) (string, error) {
 	if len(args) > i {
 		shell := args[i]
-		if shell == zshShell || shell == bashShell {
+		if shell == zshShell || shell == bashShell || shell == fishShell {
 			return shell, nil
 		}
 		return shell, fmt.Errorf("unknown shell: %q", shell)
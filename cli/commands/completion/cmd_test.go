@@ -75,11 +75,21 @@ func TestRunWithArgBash(t *testing.T) {
 	assert.Contains(t, out, "_init_completion")
 }
 
-func TestRunWithBadArg(t *testing.T) {
+func TestRunWithArgFish(t *testing.T) {
 	test := newExecutorTest()
 	err := test.exec.run(test.cmd, []string{"fish"})
 	out := test.out.result
 
+	require.NoError(t, err)
+	require.NotEmpty(t, out)
+	assert.Contains(t, out, "complete -c sensuctl")
+}
+
+func TestRunWithBadArg(t *testing.T) {
+	test := newExecutorTest()
+	err := test.exec.run(test.cmd, []string{"tcsh"})
+	out := test.out.result
+
 	require.NoError(t, err)
 	require.NotEmpty(t, out)
 	assert.Contains(t, out, "unknown shell")
@@ -116,11 +126,20 @@ func TestHelpWithBash(t *testing.T) {
 	assert.Contains(t, out, "bash_profile")
 }
 
-func TestHelpWithBadArg(t *testing.T) {
+func TestHelpWithFish(t *testing.T) {
 	test := newExecutorTest()
 	test.exec.runHelp(test.cmd, []string{"completion", "fish"})
 	out := test.out.result
 
+	require.NotEmpty(t, out)
+	assert.Contains(t, out, "config.fish")
+}
+
+func TestHelpWithBadArg(t *testing.T) {
+	test := newExecutorTest()
+	test.exec.runHelp(test.cmd, []string{"completion", "tcsh"})
+	out := test.out.result
+
 	require.NotEmpty(t, out)
 	assert.Contains(t, out, "unknown shell")
 	assert.Contains(t, out, "help")
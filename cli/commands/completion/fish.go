@@ -0,0 +1,21 @@
+package completion
+
+import (
+	"github.com/sensu/sensu-go/cli"
+	"github.com/spf13/cobra"
+)
+
+const (
+	fishUsage = `
+# Add the following to your ~/.config/fish/config.fish
+` + cli.SensuCmdName + ` completion fish | source
+
+# Or write the completions to the fish completions directory
+` + cli.SensuCmdName + ` completion fish > ~/.config/fish/completions/` + cli.SensuCmdName + `.fish
+	`
+)
+
+func genFishCompletion(rootCmd *cobra.Command) error {
+	stdout := rootCmd.OutOrStdout()
+	return rootCmd.GenFishCompletion(stdout, true)
+}
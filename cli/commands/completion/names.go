@@ -0,0 +1,112 @@
+package completion
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/client"
+	"github.com/sensu/sensu-go/util/compat"
+	"github.com/spf13/cobra"
+)
+
+// NamesCacheTTL is how long the names of the resources listed for completion
+// are cached, since the shell runs the completion at every <TAB>.
+var NamesCacheTTL = 30 * time.Second
+
+// ResourceNames returns a function completing the first argument of a command
+// with the names of the resources of the given type, as listed by the
+// backend in the namespace of the command.
+func ResourceNames(cli *cli.SensuCli, resource func() corev3.Resource) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names, err := resourceNames(cli, cacheDir(cmd), resource())
+		if err != nil {
+			cobra.CompErrorln(err.Error())
+			return nil, cobra.ShellCompDirectiveError
+		}
+		var completions []string
+		for _, name := range names {
+			if strings.HasPrefix(name, toComplete) {
+				completions = append(completions, name)
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// resourceNames lists the names of the resources of the given type, from the
+// cache directory if they were listed less than NamesCacheTTL ago.
+func resourceNames(cli *cli.SensuCli, dir string, resource corev3.Resource) ([]string, error) {
+	if gr, ok := resource.(corev3.GlobalResource); !ok || !gr.IsGlobalResource() {
+		resource.GetMetadata().Namespace = cli.Config.Namespace()
+	}
+	path := fmt.Sprintf("%s?types=%s", resource.URIPath(), url.QueryEscape(types.WrapResource(resource).Type))
+
+	var cacheFile string
+	if dir != "" {
+		sum := sha256.Sum256([]byte(cli.Config.APIUrl() + " " + path))
+		cacheFile = filepath.Join(dir, "completion", hex.EncodeToString(sum[:16]))
+		if info, err := os.Stat(cacheFile); err == nil && time.Since(info.ModTime()) < NamesCacheTTL {
+			if content, err := os.ReadFile(cacheFile); err == nil {
+				return strings.Fields(string(content)), nil
+			}
+		}
+	}
+
+	var wrappers []*types.Wrapper
+	if err := cli.Client.List(path, &wrappers, &client.ListOptions{}, nil); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(wrappers))
+	for _, w := range wrappers {
+		names = append(names, compat.GetObjectMeta(w.Value).Name)
+	}
+
+	// The completion works without the cache, so it is best effort
+	if cacheFile != "" {
+		if err := os.MkdirAll(filepath.Dir(cacheFile), 0700); err == nil {
+			_ = os.WriteFile(cacheFile, []byte(strings.Join(names, "\n")), 0600)
+		}
+	}
+	return names, nil
+}
+
+// cacheDir returns the cache directory given by the --cache-dir flag, if any.
+func cacheDir(cmd *cobra.Command) string {
+	if flag := cmd.Flag("cache-dir"); flag != nil {
+		return flag.Value.String()
+	}
+	return ""
+}
+
+// CheckNames completes the names of the checks.
+func CheckNames(cli *cli.SensuCli) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return ResourceNames(cli, func() corev3.Resource { return &corev2.CheckConfig{} })
+}
+
+// EntityNames completes the names of the entities.
+func EntityNames(cli *cli.SensuCli) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return ResourceNames(cli, func() corev3.Resource { return &corev2.Entity{} })
+}
+
+// HandlerNames completes the names of the handlers.
+func HandlerNames(cli *cli.SensuCli) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return ResourceNames(cli, func() corev3.Resource { return &corev2.Handler{} })
+}
+
+// NamespaceNames completes the names of the namespaces.
+func NamespaceNames(cli *cli.SensuCli) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return ResourceNames(cli, func() corev3.Resource { return &corev3.Namespace{} })
+}
@@ -0,0 +1,59 @@
+package completion
+
+import (
+	"errors"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/core/v3/types"
+	clienttest "github.com/sensu/sensu-go/cli/client/testing"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckNames(t *testing.T) {
+	cli := test.NewMockCLI()
+	config := cli.Config.(*clienttest.MockConfig)
+	config.On("APIUrl").Return("http://127.0.0.1:8080")
+	client := cli.Client.(*clienttest.MockClient)
+	client.On("List", "/api/core/v2/namespaces/default/checks?types=CheckConfig", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil).Run(func(args mock.Arguments) {
+		checks := args.Get(1).(*[]*types.Wrapper)
+		for _, name := range []string{"cpu", "disk", "cpu-eu"} {
+			w := types.WrapResource(corev2.FixtureCheckConfig(name))
+			*checks = append(*checks, &w)
+		}
+	}).Once()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("cache-dir", t.TempDir(), "")
+	complete := CheckNames(cli)
+
+	names, directive := complete(cmd, nil, "cpu")
+	assert.Equal(t, []string{"cpu", "cpu-eu"}, names)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	// The names are cached
+	names, _ = complete(cmd, nil, "")
+	assert.Equal(t, []string{"cpu", "disk", "cpu-eu"}, names)
+	client.AssertNumberOfCalls(t, "List", 1)
+
+	// Only the first argument is completed
+	names, _ = complete(cmd, []string{"cpu"}, "")
+	assert.Empty(t, names)
+}
+
+func TestResourceNamesError(t *testing.T) {
+	cli := test.NewMockCLI()
+	config := cli.Config.(*clienttest.MockConfig)
+	config.On("APIUrl").Return("http://127.0.0.1:8080")
+	client := cli.Client.(*clienttest.MockClient)
+	client.On("List", "api/core/v3/namespaces?types=Namespace", mock.Anything, mock.Anything, mock.Anything).
+		Return(errors.New("unauthorized"))
+
+	names, directive := NamespaceNames(cli)(&cobra.Command{}, nil, "")
+	assert.Empty(t, names)
+	assert.Equal(t, cobra.ShellCompDirectiveError, directive)
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/hooks"
+	"github.com/spf13/cobra"
+)
+
+// DeleteContextCommand given argument deletes a context
+func DeleteContextCommand(cli *cli.SensuCli) *cobra.Command {
+	return &cobra.Command{
+		Use:          "delete-context [CONTEXT]",
+		Short:        "Delete a context",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+
+			if err := cli.Config.DeleteContext(args[0]); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Deleted")
+			return nil
+		},
+		Annotations: map[string]string{
+			hooks.ConfigurationRequirement: hooks.ConfigurationNotRequired,
+		},
+	}
+}
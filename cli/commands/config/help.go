@@ -16,9 +16,12 @@ func HelpCommand(cli *cli.SensuCli) *cobra.Command {
 
 	// Add sub-commands
 	cmd.AddCommand(
+		DeleteContextCommand(cli),
+		ListContextsCommand(cli),
 		SetFormatCommand(cli),
 		SetNamespaceCommand(cli),
 		SetTimeoutCommand(cli),
+		UseContextCommand(cli),
 		ViewCommand(cli),
 	)
 
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/hooks"
+	"github.com/spf13/cobra"
+)
+
+// ListContextsCommand lists the contexts, marking the active one
+func ListContextsCommand(cli *cli.SensuCli) *cobra.Command {
+	return &cobra.Command{
+		Use:          "list-contexts",
+		Short:        "List the contexts",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+
+			contexts, err := cli.Config.Contexts()
+			if err != nil {
+				return err
+			}
+
+			active := cli.Config.Context()
+			for _, context := range contexts {
+				marker := " "
+				if context == active {
+					marker = "*"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", marker, context)
+			}
+			return nil
+		},
+		Annotations: map[string]string{
+			hooks.ConfigurationRequirement: hooks.ConfigurationNotRequired,
+		},
+	}
+}
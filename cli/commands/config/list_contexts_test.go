@@ -0,0 +1,37 @@
+package config
+
+import (
+	"testing"
+
+	clienttest "github.com/sensu/sensu-go/cli/client/testing"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListContextsExec(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewMockCLI()
+	cmd := ListContextsCommand(cli)
+
+	config := cli.Config.(*clienttest.MockConfig)
+	config.On("Contexts").Return([]string{"default", "prod"}, nil)
+
+	out, err := test.RunCmd(cmd, nil)
+	assert.Equal("* default\n  prod\n", out)
+	assert.Nil(err, "Should not produce any errors")
+}
+
+func TestDeleteContextExec(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewMockCLI()
+	cmd := DeleteContextCommand(cli)
+
+	config := cli.Config.(*clienttest.MockConfig)
+	config.On("DeleteContext", "prod").Return(nil)
+
+	out, err := test.RunCmd(cmd, []string{"prod"})
+	assert.Equal("Deleted\n", out)
+	assert.Nil(err, "Should not produce any errors")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/completion"
 	"github.com/sensu/sensu-go/cli/commands/hooks"
 	"github.com/spf13/cobra"
 )
@@ -12,9 +13,10 @@ import (
 // SetNamespaceCommand given argument changes namespace for active profile
 func SetNamespaceCommand(cli *cli.SensuCli) *cobra.Command {
 	return &cobra.Command{
-		Use:          "set-namespace [NAMESPACE]",
-		Short:        "Set namespace for active profile",
-		SilenceUsage: true,
+		Use:               "set-namespace [NAMESPACE]",
+		ValidArgsFunction: completion.NamespaceNames(cli),
+		Short:             "Set namespace for active profile",
+		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				_ = cmd.Help()
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/hooks"
+	"github.com/spf13/cobra"
+)
+
+// UseContextCommand given argument changes the current context
+func UseContextCommand(cli *cli.SensuCli) *cobra.Command {
+	return &cobra.Command{
+		Use:          "use-context [CONTEXT]",
+		Short:        "Set the current context",
+		Long:         "Set the current context. The contexts are created with \"sensuctl configure --context CONTEXT\".",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+
+			if err := cli.Config.UseContext(args[0]); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Switched to context %q\n", args[0])
+			return nil
+		},
+		Annotations: map[string]string{
+			// We want to be able to run this command regardless of whether the CLI
+			// has been configured.
+			hooks.ConfigurationRequirement: hooks.ConfigurationNotRequired,
+		},
+	}
+}
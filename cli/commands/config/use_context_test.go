@@ -0,0 +1,52 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sensu/sensu-go/cli"
+	clienttest "github.com/sensu/sensu-go/cli/client/testing"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseContextCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := &cli.SensuCli{}
+	cmd := UseContextCommand(cli)
+
+	assert.NotNil(cmd, "cmd should be returned")
+	assert.NotNil(cmd.RunE, "cmd should be able to be executed")
+	assert.Regexp("use-context", cmd.Use)
+	assert.Regexp("current context", cmd.Short)
+}
+
+func TestUseContextBadArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := &cli.SensuCli{}
+	cmd := UseContextCommand(cli)
+
+	out, err := test.RunCmd(cmd, []string{})
+	assert.NotEmpty(out, "output should display help usage")
+	assert.Error(err, "error should be returned")
+}
+
+func TestUseContextExec(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewMockCLI()
+	cmd := UseContextCommand(cli)
+
+	config := cli.Config.(*clienttest.MockConfig)
+	config.On("UseContext", "prod").Return(nil)
+	config.On("UseContext", "staging").Return(errors.New("context \"staging\" does not exist"))
+
+	out, err := test.RunCmd(cmd, []string{"prod"})
+	assert.Equal("Switched to context \"prod\"\n", out)
+	assert.Nil(err, "Should not produce any errors")
+
+	_, err = test.RunCmd(cmd, []string{"staging"})
+	assert.Error(err)
+}
@@ -25,6 +25,7 @@ func ViewCommand(cli *cli.SensuCli) *cobra.Command {
 				return errors.New("no active configuration found")
 			}
 			activeConfig := map[string]string{
+				"context":        cli.Config.Context(),
 				"api-url":        cli.Config.APIUrl(),
 				"namespace":      cli.Config.Namespace(),
 				"format":         cli.Config.Format(),
@@ -59,6 +60,10 @@ func printToList(v interface{}, writer io.Writer) error {
 	cfg := &list.Config{
 		Title: "Active Configuration",
 		Rows: []*list.Row{
+			{
+				Label: "Context",
+				Value: r["context"],
+			},
 			{
 				Label: "API URL",
 				Value: r["api-url"],
@@ -2,6 +2,7 @@ package create
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/sensu/sensu-go/cli"
@@ -19,6 +20,8 @@ func CreateCommand(cli *cli.SensuCli) *cobra.Command {
 
 	_ = cmd.Flags().StringSliceP("file", "f", nil, "Files, directories, or URLs to create resources from")
 	_ = cmd.Flags().BoolP("recursive", "r", false, "Follow subdirectories")
+	_ = cmd.Flags().String("dry-run", "none", `Validate the resources without creating them, if set to "server"`)
+	resource.AddRenderFlags(cmd.Flags())
 
 	return cmd
 }
@@ -37,14 +40,29 @@ func execute(cli *cli.SensuCli) func(*cobra.Command, []string) error {
 			return err
 		}
 		processor := resource.NewManagedByLabelPutter("sensuctl")
+		dryRun, err := cmd.Flags().GetString("dry-run")
+		if err != nil {
+			return err
+		}
+		switch dryRun {
+		case "none":
+		case "server":
+			processor.DryRun = true
+		default:
+			return fmt.Errorf(`invalid dry run mode %q: expected "none" or "server"`, dryRun)
+		}
+		renderer, err := resource.RendererFromFlags(cmd.Flags())
+		if err != nil {
+			return err
+		}
 		if len(inputs) == 0 {
-			return resource.ProcessStdin(cli, client, processor)
+			return resource.ProcessStdin(cli, client, processor, renderer)
 		}
 		recurse, err := cmd.Flags().GetBool("recursive")
 		if err != nil {
 			return err
 		}
-		if err := resource.Process(cli, client, inputs, recurse, processor); err != nil {
+		if err := resource.Process(cli, client, inputs, recurse, processor, renderer); err != nil {
 			return err
 		}
 		return nil
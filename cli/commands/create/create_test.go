@@ -178,3 +178,28 @@ func TestCreateCommandStdin(t *testing.T) {
 	client.AssertCalled(t, "PutResource", mock.Anything)
 	client.AssertCalled(t, "PutResource", mock.Anything)
 }
+
+func TestCreateCommandDryRun(t *testing.T) {
+	cli := cmdtesting.NewMockCLI()
+	client := cli.Client.(*mockclient.MockClient)
+	client.On("PutResourceDryRun", mock.Anything).Return(nil)
+
+	cmd := CreateCommand(cli)
+	fp := filepath.Join(t.TempDir(), "input")
+	f, err := os.Create(fp)
+	require.NoError(t, err)
+	require.NoError(t, resourceSpecTmpl.Execute(f, resources))
+	require.NoError(t, f.Close())
+
+	require.NoError(t, cmd.Flags().Set("file", fp))
+	require.NoError(t, cmd.Flags().Set("dry-run", "server"))
+	_, err = cmdtesting.RunCmd(cmd, nil)
+	require.NoError(t, err)
+
+	client.AssertNumberOfCalls(t, "PutResourceDryRun", 3)
+	client.AssertNotCalled(t, "PutResource", mock.Anything)
+
+	require.NoError(t, cmd.Flags().Set("dry-run", "client"))
+	_, err = cmdtesting.RunCmd(cmd, nil)
+	require.Error(t, err)
+}
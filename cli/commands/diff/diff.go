@@ -0,0 +1,286 @@
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mgutz/ansi"
+	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/client"
+	"github.com/sensu/sensu-go/cli/resource"
+	"github.com/sensu/sensu-go/command"
+	"github.com/sensu/sensu-go/util/compat"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// DriftExitStatus is the exit status of sensuctl when resources differ
+	// from the cluster state.
+	DriftExitStatus = 1
+
+	// ErrorExitStatus is the exit status of sensuctl when the resources could
+	// not be compared.
+	ErrorExitStatus = 2
+)
+
+var (
+	addedStyle   = ansi.ColorFunc("green")
+	removedStyle = ansi.ColorFunc("red")
+	headerStyle  = ansi.ColorFunc("default+b")
+)
+
+// DriftError is returned when resources differ from the cluster state.
+type DriftError struct {
+	Count int
+}
+
+func (e *DriftError) Error() string {
+	if e.Count == 1 {
+		return "1 resource differs from the cluster state"
+	}
+	return fmt.Sprintf("%d resources differ from the cluster state", e.Count)
+}
+
+func (e *DriftError) ExitStatus() int { return DriftExitStatus }
+
+// diffError is an error preventing the resources from being compared.
+type diffError struct{ err error }
+
+func (e *diffError) Error() string   { return e.err.Error() }
+func (e *diffError) ExitStatus() int { return ErrorExitStatus }
+
+// Command compares resources with the cluster state.
+func Command(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff [-r] [[-f URL] ... ]",
+		Short: "Compare resources from file or URL (path, file://, http[s]://), or STDIN otherwise, with the cluster state.",
+		Long: "Compare resources from file or URL (path, file://, http[s]://), or STDIN otherwise, with the cluster state.\n\n" +
+			"The exit status is 0 if the resources match the cluster state, 1 if they differ and 2 if they could not be compared.",
+		SilenceUsage: true,
+		RunE:         execute(cli),
+	}
+
+	_ = cmd.Flags().StringSliceP("file", "f", nil, "Files, directories, or URLs to compare resources from")
+	_ = cmd.Flags().BoolP("recursive", "r", false, "Follow subdirectories")
+	_ = cmd.Flags().Bool("no-color", false, "Print the differences without colors")
+	resource.AddRenderFlags(cmd.Flags())
+
+	return cmd
+}
+
+func execute(cli *cli.SensuCli) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			_ = cmd.Help()
+			return &command.UsageError{Message: "invalid argument(s) received"}
+		}
+		err := run(cli, cmd)
+		if _, ok := err.(*DriftError); err != nil && !ok {
+			return &diffError{err: err}
+		}
+		return err
+	}
+}
+
+func run(cli *cli.SensuCli, cmd *cobra.Command) error {
+	t := &http.Transport{}
+	t.RegisterProtocol("file", http.NewFileTransport(http.Dir("/")))
+	client := &http.Client{Transport: t}
+	inputs, err := cmd.Flags().GetStringSlice("file")
+	if err != nil {
+		return err
+	}
+	noColor, err := cmd.Flags().GetBool("no-color")
+	if err != nil {
+		return err
+	}
+	processor := NewDiffer(cmd.OutOrStdout(), !noColor)
+	renderer, err := resource.RendererFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+	if len(inputs) == 0 {
+		return resource.ProcessStdin(cli, client, processor, renderer)
+	}
+	recurse, err := cmd.Flags().GetBool("recursive")
+	if err != nil {
+		return err
+	}
+	return resource.Process(cli, client, inputs, recurse, processor, renderer)
+}
+
+// Differ is a Processor that prints the differences between resources and
+// their current state in the API.
+type Differ struct {
+	w     io.Writer
+	color bool
+}
+
+// NewDiffer instantiates a new Differ Processor, printing the differences to
+// w.
+func NewDiffer(w io.Writer, color bool) *Differ {
+	return &Differ{w: w, color: color}
+}
+
+// Process prints the fields of the resources that differ from the API. A
+// DriftError is returned if any resource differs.
+func (d *Differ) Process(client client.GenericClient, resources []*types.Wrapper) error {
+	count := 0
+	for i, resource := range resources {
+		changed, err := d.diff(client, resource)
+		if err != nil {
+			return fmt.Errorf("error comparing resource #%d: %s", i, err)
+		}
+		if changed {
+			count++
+		}
+	}
+	if count > 0 {
+		return &DriftError{Count: count}
+	}
+	return nil
+}
+
+// diff prints the differences of a resource, and returns whether there are
+// any.
+func (d *Differ) diff(c client.GenericClient, local *types.Wrapper) (bool, error) {
+	name := local.Type
+	if meta := compat.GetObjectMeta(local.Value); meta.Namespace != "" {
+		name += " " + meta.Namespace + "/" + meta.Name
+	} else {
+		name += " " + meta.Name
+	}
+
+	live := &types.Wrapper{}
+	if err := c.Get(compat.URIPath(local.Value), live); err != nil {
+		if err, ok := err.(client.APIError); ok && actions.ErrCode(err.Code) == actions.NotFound {
+			fmt.Fprintln(d.w, d.style(addedStyle, "+ "+name+" (not in the cluster)"))
+			return true, nil
+		}
+		return false, err
+	}
+	if live.Value == nil {
+		return false, errors.New("empty response from the API")
+	}
+
+	localFields, err := fields(local.Value)
+	if err != nil {
+		return false, err
+	}
+	liveFields, err := fields(live.Value)
+	if err != nil {
+		return false, err
+	}
+
+	paths := make([]string, 0, len(localFields))
+	for path := range localFields {
+		paths = append(paths, path)
+	}
+	for path := range liveFields {
+		if _, ok := localFields[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var lines []string
+	for _, path := range paths {
+		localValue, inLocal := localFields[path]
+		liveValue, inLive := liveFields[path]
+		if inLocal && inLive && localValue == liveValue {
+			continue
+		}
+		if inLive {
+			lines = append(lines, d.style(removedStyle, fmt.Sprintf("  - %s: %s", path, liveValue)))
+		}
+		if inLocal {
+			lines = append(lines, d.style(addedStyle, fmt.Sprintf("  + %s: %s", path, localValue)))
+		}
+	}
+	if len(lines) == 0 {
+		return false, nil
+	}
+	fmt.Fprintln(d.w, d.style(headerStyle, "~ "+name))
+	for _, line := range lines {
+		fmt.Fprintln(d.w, line)
+	}
+	return true, nil
+}
+
+func (d *Differ) style(style func(string) string, s string) string {
+	if !d.color {
+		return s
+	}
+	return style(s)
+}
+
+// fields returns the JSON encoded values of the fields of a resource, by path.
+// The fields managed by the backend are omitted.
+func fields(value interface{}) (map[string]string, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(b, &object); err != nil {
+		return nil, err
+	}
+	if meta, ok := object["metadata"].(map[string]interface{}); ok {
+		delete(meta, "created_by")
+		for _, field := range []string{"labels", "annotations"} {
+			if values, ok := meta[field].(map[string]interface{}); ok {
+				for key := range values {
+					if strings.HasPrefix(key, "sensu.io/") {
+						delete(values, key)
+					}
+				}
+			}
+		}
+	}
+	result := make(map[string]string)
+	if err := flatten("", object, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// flatten adds the values of the object to result, by path. Arrays are
+// compared as a whole, and the empty objects and arrays are omitted.
+func flatten(prefix string, object map[string]interface{}, result map[string]string) error {
+	for key, value := range object {
+		path := key
+		if strings.ContainsAny(key, "./") {
+			path = fmt.Sprintf("[%q]", key)
+		} else if prefix != "" {
+			path = "." + key
+		}
+		path = prefix + path
+		switch value := value.(type) {
+		case nil:
+			continue
+		case map[string]interface{}:
+			if err := flatten(path, value, result); err != nil {
+				return err
+			}
+			continue
+		case []interface{}:
+			// Empty and missing arrays are equivalent
+			if len(value) == 0 {
+				continue
+			}
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		result[path] = string(b)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package diff
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/cli/client"
+	mockclient "github.com/sensu/sensu-go/cli/client/testing"
+	cmdtesting "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/sensu/sensu-go/cli/resource"
+	"github.com/sensu/sensu-go/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const manifest = `
+type: CheckConfig
+api_version: core/v2
+spec:
+  metadata:
+    name: cpu
+    namespace: default
+  command: check-cpu -w 90
+  interval: 60
+  subscriptions: [linux]
+  publish: true
+---
+type: Handler
+api_version: core/v2
+spec:
+  metadata:
+    name: slack
+    namespace: default
+  type: pipe
+  command: notify
+`
+
+func liveCheck() *corev2.CheckConfig {
+	return &corev2.CheckConfig{
+		ObjectMeta: corev2.ObjectMeta{
+			Name:        "cpu",
+			Namespace:   "default",
+			Labels:      map[string]string{corev2.ManagedByLabel: "sensuctl"},
+			Annotations: map[string]string{"sensu.io/etag": "etag"},
+			CreatedBy:   "admin",
+		},
+		Command:       "check-cpu -w 80",
+		Interval:      60,
+		Subscriptions: []string{"linux"},
+		Publish:       true,
+	}
+}
+
+func returnResource(value interface{}) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		*args.Get(1).(*types.Wrapper) = types.Wrapper{Value: value}
+	}
+}
+
+func TestDiffer(t *testing.T) {
+	mockClient := new(mockclient.MockClient)
+	mockClient.On("Get", "/api/core/v2/namespaces/default/checks/cpu", mock.Anything).
+		Return(nil).Run(returnResource(liveCheck()))
+	mockClient.On("Get", "/api/core/v2/namespaces/default/handlers/slack", mock.Anything).
+		Return(client.APIError{Code: uint32(actions.NotFound)})
+
+	resources, err := resourcesFromManifest(manifest)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	err = NewDiffer(&out, false).Process(mockClient, resources)
+	require.Error(t, err)
+	assert.Equal(t, &DriftError{Count: 2}, err)
+	assert.Equal(t, DriftExitStatus, err.(command.CommandErrorer).ExitStatus())
+	assert.Equal(t, `~ CheckConfig default/cpu
+  - command: "check-cpu -w 80"
+  + command: "check-cpu -w 90"
++ Handler default/slack (not in the cluster)
+`, out.String())
+}
+
+func TestDifferNoDrift(t *testing.T) {
+	mockClient := new(mockclient.MockClient)
+	check := liveCheck()
+	check.Command = "check-cpu -w 90"
+	mockClient.On("Get", mock.Anything, mock.Anything).Return(nil).Run(returnResource(check))
+
+	resources, err := resourcesFromManifest(manifest)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, NewDiffer(&out, false).Process(mockClient, resources[:1]))
+	assert.Empty(t, out.String())
+}
+
+func TestCommandError(t *testing.T) {
+	cli := cmdtesting.NewMockCLI()
+	cli.Client.(*mockclient.MockClient).On("Get", mock.Anything, mock.Anything).
+		Return(errors.New("connection refused"))
+
+	fp := filepath.Join(t.TempDir(), "resources.yaml")
+	require.NoError(t, os.WriteFile(fp, []byte(manifest), 0600))
+
+	cmd := Command(cli)
+	require.NoError(t, cmd.Flags().Set("file", fp))
+	_, err := cmdtesting.RunCmd(cmd, nil)
+	require.Error(t, err)
+	assert.Equal(t, ErrorExitStatus, err.(command.CommandErrorer).ExitStatus())
+}
+
+func resourcesFromManifest(manifest string) ([]*types.Wrapper, error) {
+	return resource.Parse(strings.NewReader(manifest))
+}
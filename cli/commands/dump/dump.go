@@ -6,6 +6,8 @@ import (
 	"io"
 	"net/url"
 	"os"
+	"path"
+	"strings"
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
@@ -36,6 +38,12 @@ $ sensuctl dump core/v2.CheckConfig,core/v2.Entity
 
 You can also use the 'all' qualifier to dump all supported resources:
 $ sensuctl dump all
+
+The resources can be filtered by label, and excluded by name:
+$ sensuctl dump checks,entities --label-selector 'region == eu' --exclude 'entities:*-test'
+
+The resources are written as they are fetched, one page at a time, and can be
+applied with "sensuctl restore".
 `
 
 // Command dumps generic Sensu resources to a file or STDOUT.
@@ -57,6 +65,8 @@ func Command(cli *cli.SensuCli) *cobra.Command {
 	_ = cmd.Flags().BoolP("types", "t", false, "list supported resource types")
 	_ = cmd.Flags().MarkDeprecated("types", `please use "sensuctl describe-type all" instead`)
 	_ = cmd.Flags().StringP("omit", "o", "", "when using 'sensuctl dump all', omit can be used to exclude types from being dumped")
+	_ = cmd.Flags().StringSlice("exclude", nil, "exclude the resources of a type by name, as TYPE:NAME or TYPE:NAMESPACE/NAME, where NAME can be a glob pattern")
+	helpers.AddLabelSelectorFlag(cmd.Flags())
 
 	return cmd
 }
@@ -64,8 +74,7 @@ func Command(cli *cli.SensuCli) *cobra.Command {
 func printAllTypes(cli *cli.SensuCli, cmd *cobra.Command) error {
 	var typeNames []string
 	for _, resource := range resource.All {
-		wrapped := types.WrapResource(resource)
-		typeNames = append(typeNames, fmt.Sprintf("%s.%s", wrapped.APIVersion, wrapped.Type))
+		typeNames = append(typeNames, typeName(resource))
 	}
 	switch getFormat(cli, cmd) {
 	case config.FormatJSON:
@@ -133,6 +142,15 @@ func execute(cli *cli.SensuCli) func(*cobra.Command, []string) error {
 
 		requests = resource.TrimResources(requests, omitRequests)
 
+		excludeSpecs, err := cmd.Flags().GetStringSlice("exclude")
+		if err != nil {
+			return err
+		}
+		excludes, err := parseExcludes(excludeSpecs)
+		if err != nil {
+			return fmt.Errorf("error parsing --exclude: %s", err)
+		}
+
 		var w io.Writer = cmd.OutOrStdout()
 
 		// if a file is requested, write data to that
@@ -149,25 +167,37 @@ func execute(cli *cli.SensuCli) func(*cobra.Command, []string) error {
 			w = f
 		}
 
-		for i, req := range requests {
-			// set the namespaces on the requests
-			ok, err := cmd.Flags().GetBool(flags.AllNamespaces)
-			if err != nil {
-				return err
-			}
-			if ok {
+		labelSelector, err := cmd.Flags().GetString(flags.LabelSelector)
+		if err != nil {
+			return err
+		}
+
+		allNamespaces, err := cmd.Flags().GetBool(flags.AllNamespaces)
+		if err != nil {
+			return err
+		}
+
+		dumper := &dumper{w: w, format: format, excludes: excludes}
+		for _, req := range requests {
+			// set the namespaces on the requests, the global resources
+			// having none
+			if gr, ok := req.(corev3.GlobalResource); ok && gr.IsGlobalResource() {
+				req.GetMetadata().Namespace = ""
+			} else if allNamespaces {
 				req.GetMetadata().Namespace = corev2.NamespaceTypeAll
 			} else {
 				req.GetMetadata().Namespace = cli.Config.Namespace()
 			}
 
-			var wrappers []types.Wrapper
-
-			err = cli.Client.List(
+			// the resources are written one page at a time, so that the
+			// whole cluster is never held in memory
+			options := &client.ListOptions{
+				LabelSelector: labelSelector,
+				ChunkSize:     ChunkSize,
+			}
+			err = cli.Client.ListPages(
 				fmt.Sprintf("%s?types=%s", req.URIPath(), url.QueryEscape(types.WrapResource(req).Type)),
-				&wrappers, &client.ListOptions{
-					ChunkSize: ChunkSize,
-				}, nil)
+				options, dumper.write)
 			if err != nil {
 				// We want to ignore non-nil errors that are a result of
 				// resources not existing, or features being licensed.
@@ -180,33 +210,102 @@ func execute(cli *cli.SensuCli) func(*cobra.Command, []string) error {
 
 				return fmt.Errorf("API error: %s", err)
 			}
+		}
 
-			if len(wrappers) == 0 {
-				continue
-			}
+		return nil
+	}
+}
 
-			resources := make([]corev3.Resource, len(wrappers))
-			for i := range resources {
-				resources[i] = wrappers[i].Value.(corev3.Resource)
-			}
+// dumper writes the dumped resources
+type dumper struct {
+	w        io.Writer
+	format   string
+	excludes []exclude
+	count    int
+}
 
-			switch format {
-			case config.FormatJSON:
-				err = helpers.PrintResourceListJSON(resources, w)
-			case config.FormatYAML:
-				if i > 0 {
-					_, _ = fmt.Fprintln(w, "---")
-				}
-				err = helpers.PrintYAML(resources, w)
-			default:
-				err = fmt.Errorf("invalid output format: %s", format)
+// write writes a page of resources, skipping the excluded resources
+func (d *dumper) write(page []*types.Wrapper) error {
+	for _, wrapper := range page {
+		resource, ok := wrapper.Value.(corev3.Resource)
+		if !ok {
+			return fmt.Errorf("unexpected resource: %T", wrapper.Value)
+		}
+		if excluded(d.excludes, resource) {
+			continue
+		}
+		var err error
+		switch d.format {
+		case config.FormatJSON:
+			err = helpers.PrintResourceJSON(resource, d.w)
+		case config.FormatYAML:
+			if d.count > 0 {
+				_, _ = fmt.Fprintln(d.w, "---")
 			}
+			err = helpers.PrintYAML(types.WrapResource(resource), d.w)
+		default:
+			err = fmt.Errorf("invalid output format: %s", d.format)
+		}
+		if err != nil {
+			return err
+		}
+		d.count++
+	}
+	return nil
+}
 
-			if err != nil {
-				return err
-			}
+// exclude excludes the resources of a type by name
+type exclude struct {
+	typeName string
+	pattern  string
+}
+
+// parseExcludes parses the TYPE:NAME and TYPE:NAMESPACE/NAME exclusions
+func parseExcludes(specs []string) ([]exclude, error) {
+	var excludes []exclude
+	for _, spec := range specs {
+		typeSpec, pattern, ok := strings.Cut(spec, ":")
+		if !ok || typeSpec == "" || pattern == "" {
+			return nil, fmt.Errorf("invalid exclusion %q, expected TYPE:NAME", spec)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclusion %q: %s", spec, err)
+		}
+		requests, err := resource.GetResourceRequests(typeSpec, resource.All)
+		if err != nil {
+			return nil, err
 		}
+		for _, req := range requests {
+			excludes = append(excludes, exclude{typeName: typeName(req), pattern: pattern})
+		}
+	}
+	return excludes, nil
+}
 
-		return nil
+// excluded returns whether a resource is excluded. The patterns containing a
+// slash are matched against the namespace and the name of the resource.
+func excluded(excludes []exclude, r corev3.Resource) bool {
+	if len(excludes) == 0 {
+		return false
 	}
+	name := typeName(r)
+	meta := r.GetMetadata()
+	for _, exclude := range excludes {
+		if exclude.typeName != name {
+			continue
+		}
+		value := meta.Name
+		if strings.Contains(exclude.pattern, "/") {
+			value = meta.Namespace + "/" + meta.Name
+		}
+		if ok, _ := path.Match(exclude.pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func typeName(r corev3.Resource) string {
+	wrapped := types.WrapResource(r)
+	return fmt.Sprintf("%s.%s", wrapped.APIVersion, wrapped.Type)
 }
@@ -3,8 +3,14 @@ package dump
 import (
 	"testing"
 
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/cli/client"
+	clienttest "github.com/sensu/sensu-go/cli/client/testing"
 	test "github.com/sensu/sensu-go/cli/commands/testing"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCommand(t *testing.T) {
@@ -49,3 +55,50 @@ func TestListFlags(t *testing.T) {
 	flag = cmd.Flag("file")
 	assert.NotNil(flag)
 }
+
+func TestDumpPages(t *testing.T) {
+	cli := test.NewMockCLI()
+	config := cli.Config.(*clienttest.MockConfig)
+	config.On("Format").Return("yaml")
+
+	page := func(names ...string) []*types.Wrapper {
+		var wrappers []*types.Wrapper
+		for _, name := range names {
+			w := types.WrapResource(corev2.FixtureCheckConfig(name))
+			wrappers = append(wrappers, &w)
+		}
+		return wrappers
+	}
+	mockClient := cli.Client.(*clienttest.MockClient)
+	mockClient.On("ListPages", "/api/core/v2/namespaces/default/checks?types=CheckConfig", mock.MatchedBy(func(options *client.ListOptions) bool {
+		return options.LabelSelector == "region == eu" && options.ChunkSize == ChunkSize
+	})).Return([][]*types.Wrapper{page("cpu", "cpu-test"), page("disk")}, nil)
+
+	cmd := Command(cli)
+	require.NoError(t, cmd.Flags().Set("label-selector", "region == eu"))
+	require.NoError(t, cmd.Flags().Set("exclude", "checks:*-test"))
+	out, err := test.RunCmd(cmd, []string{"checks"})
+	require.NoError(t, err)
+	assert.Contains(t, out, "name: cpu\n")
+	assert.Contains(t, out, "name: disk\n")
+	assert.NotContains(t, out, "cpu-test")
+	assert.Contains(t, out, "\n---\n")
+	mockClient.AssertExpectations(t)
+}
+
+func TestParseExcludes(t *testing.T) {
+	excludes, err := parseExcludes([]string{"checks:cpu*", "core/v2.Entity:dev/*"})
+	require.NoError(t, err)
+
+	assert.True(t, excluded(excludes, corev2.FixtureCheckConfig("cpu-eu")))
+	assert.False(t, excluded(excludes, corev2.FixtureCheckConfig("disk")))
+	assert.False(t, excluded(excludes, corev2.FixtureEntity("cpu")))
+	entity := corev2.FixtureEntity("laptop")
+	entity.Namespace = "dev"
+	assert.True(t, excluded(excludes, entity))
+
+	for _, spec := range []string{"checks", "checks:", "checks:[", "foo:bar"} {
+		_, err := parseExcludes([]string{spec})
+		assert.Error(t, err, spec)
+	}
+}
@@ -18,8 +18,10 @@ func HelpCommand(cli *cli.SensuCli) *cobra.Command {
 	cmd.AddCommand(
 		CreateCommand(cli),
 		DeleteCommand(cli),
+		ImportCommand(cli),
 		ListCommand(cli),
 		InfoCommand(cli),
+		UndeleteCommand(cli),
 		UpdateCommand(cli),
 	)
 
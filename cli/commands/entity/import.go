@@ -0,0 +1,135 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/cli"
+	"github.com/spf13/cobra"
+)
+
+const defaultImportBatchSize = 500
+
+// ImportCommand allows a user to create or replace many proxy entities at
+// once, for example from a CMDB export
+func ImportCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "import",
+		Short:        "create or replace proxy entities from a file or stdin",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+
+			batchSize, _ := cmd.Flags().GetInt("batch-size")
+			if batchSize <= 0 {
+				return errors.New("batch size must be greater than 0")
+			}
+
+			filePath, _ := cmd.Flags().GetString("file")
+			var in io.Reader = cmd.InOrStdin()
+			if len(filePath) > 0 {
+				f, err := os.Open(filePath)
+				if err != nil {
+					return err
+				}
+				defer func() { _ = f.Close() }()
+				in = f
+			}
+
+			entities, err := readEntities(in, cli.Config.Namespace())
+			if err != nil {
+				return err
+			}
+
+			var imported, failed int
+			for start := 0; start < len(entities); start += batchSize {
+				end := start + batchSize
+				if end > len(entities) {
+					end = len(entities)
+				}
+				results, err := cli.Client.ImportEntities(entities[start:end])
+				if err != nil {
+					return err
+				}
+				for _, result := range results {
+					if result.Error != "" {
+						failed++
+						fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", result.Name, result.Error)
+						continue
+					}
+					imported++
+				}
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Imported %d entities\n", imported)
+			if failed > 0 {
+				return fmt.Errorf("%d entities could not be imported", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("file", "f", "", "file containing a JSON list of entities")
+	cmd.Flags().Int("batch-size", defaultImportBatchSize, "number of entities sent per request")
+
+	return cmd
+}
+
+// readEntities decodes a JSON list of entities, either wrapped or not. Entities
+// default to the proxy class and to the given namespace.
+func readEntities(in io.Reader, namespace string) ([]*corev2.Entity, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(in).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("expected a JSON list of entities: %s", err)
+	}
+
+	entities := make([]*corev2.Entity, 0, len(raw))
+	for i, b := range raw {
+		entity, err := decodeEntity(b)
+		if err != nil {
+			return nil, fmt.Errorf("entity %d: %s", i, err)
+		}
+		if entity.EntityClass == "" {
+			entity.EntityClass = corev2.EntityProxyClass
+		}
+		if entity.Namespace == "" {
+			entity.Namespace = namespace
+		}
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
+
+func decodeEntity(b []byte) (*corev2.Entity, error) {
+	var probe struct {
+		Type string          `json:"type"`
+		Spec json.RawMessage `json:"spec"`
+	}
+	if err := json.Unmarshal(b, &probe); err != nil {
+		return nil, err
+	}
+	if probe.Type == "" && len(probe.Spec) == 0 {
+		var entity corev2.Entity
+		if err := json.Unmarshal(b, &entity); err != nil {
+			return nil, err
+		}
+		return &entity, nil
+	}
+	var wrapper types.Wrapper
+	if err := json.Unmarshal(b, &wrapper); err != nil {
+		return nil, err
+	}
+	entity, ok := wrapper.Value.(*corev2.Entity)
+	if !ok {
+		return nil, fmt.Errorf("expected an entity, got %T", wrapper.Value)
+	}
+	return entity, nil
+}
@@ -0,0 +1,118 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	client "github.com/sensu/sensu-go/cli/client/testing"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func writeImportFile(t *testing.T, v interface{}) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "cmdb.json")
+	require.NoError(t, os.WriteFile(path, b, 0600))
+	return path
+}
+
+func TestImportCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewMockCLI()
+	cmd := ImportCommand(cli)
+
+	assert.NotNil(cmd, "cmd should be returned")
+	assert.NotNil(cmd.RunE, "cmd should be able to be executed")
+	assert.Regexp("import", cmd.Use)
+	assert.Regexp("entities", cmd.Short)
+}
+
+func TestImportCommandRunEClosure(t *testing.T) {
+	assert := assert.New(t)
+
+	bare := corev2.FixtureEntity("bare")
+	bare.EntityClass = ""
+	bare.Namespace = ""
+	path := writeImportFile(t, []interface{}{
+		types.WrapResource(corev2.FixtureEntity("wrapped")),
+		bare,
+		corev2.FixtureEntity("third"),
+	})
+
+	cli := test.NewMockCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("ImportEntities", mock.MatchedBy(func(entities []*corev2.Entity) bool {
+		return len(entities) == 2
+	})).Return([]actions.BulkEntityResult{{Name: "wrapped"}, {Name: "bare"}}, nil)
+	client.On("ImportEntities", mock.MatchedBy(func(entities []*corev2.Entity) bool {
+		return len(entities) == 1
+	})).Return([]actions.BulkEntityResult{{Name: "third"}}, nil)
+
+	cmd := ImportCommand(cli)
+	require.NoError(t, cmd.Flags().Set("file", path))
+	require.NoError(t, cmd.Flags().Set("batch-size", "2"))
+	out, err := test.RunCmd(cmd, []string{})
+
+	assert.NoError(err)
+	assert.Regexp("Imported 3 entities", out)
+	client.AssertNumberOfCalls(t, "ImportEntities", 2)
+
+	entities := client.Calls[0].Arguments.Get(0).([]*corev2.Entity)
+	assert.Equal(corev2.EntityProxyClass, entities[1].EntityClass)
+	assert.Equal("default", entities[1].Namespace)
+}
+
+func TestImportCommandRunEClosureWithFailures(t *testing.T) {
+	assert := assert.New(t)
+
+	path := writeImportFile(t, []interface{}{corev2.FixtureEntity("foo")})
+
+	cli := test.NewMockCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("ImportEntities", mock.Anything).Return([]actions.BulkEntityResult{{Name: "foo", Error: "entity is managed by its agent"}}, nil)
+
+	cmd := ImportCommand(cli)
+	require.NoError(t, cmd.Flags().Set("file", path))
+	out, err := test.RunCmd(cmd, []string{})
+
+	assert.Error(err)
+	assert.Regexp("foo: entity is managed by its agent", out)
+}
+
+func TestImportCommandRunEClosureWithAPIErr(t *testing.T) {
+	assert := assert.New(t)
+
+	path := writeImportFile(t, []interface{}{corev2.FixtureEntity("foo")})
+
+	cli := test.NewMockCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("ImportEntities", mock.Anything).Return([]actions.BulkEntityResult(nil), errors.New("whoops"))
+
+	cmd := ImportCommand(cli)
+	require.NoError(t, cmd.Flags().Set("file", path))
+	_, err := test.RunCmd(cmd, []string{})
+
+	assert.EqualError(err, "whoops")
+}
+
+func TestImportCommandRunEClosureWithBadFile(t *testing.T) {
+	path := writeImportFile(t, map[string]string{"not": "a list"})
+
+	cli := test.NewMockCLI()
+	cmd := ImportCommand(cli)
+	require.NoError(t, cmd.Flags().Set("file", path))
+	_, err := test.RunCmd(cmd, []string{})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sensu/sensu-go/cli"
+	"github.com/spf13/cobra"
+)
+
+// UndeleteCommand adds a command that allows user to restore entities deleted
+// recently
+func UndeleteCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "undelete [NAME]",
+		Short:        "restore a recently deleted entity given name",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// If no name is present print out usage
+			if len(args) != 1 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+
+			name := args[0]
+			namespace := cli.Config.Namespace()
+
+			if err := cli.Client.UndeleteEntity(namespace, name); err != nil {
+				return err
+			}
+
+			_, err := fmt.Fprintln(cmd.OutOrStdout(), "Restored")
+			return err
+		},
+	}
+
+	return cmd
+}
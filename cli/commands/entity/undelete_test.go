@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+
+	client "github.com/sensu/sensu-go/cli/client/testing"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUndeleteCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewMockCLI()
+	cmd := UndeleteCommand(cli)
+
+	assert.NotNil(cmd, "cmd should be returned")
+	assert.NotNil(cmd.RunE, "cmd should be able to be executed")
+	assert.Regexp("undelete", cmd.Use)
+	assert.Regexp("entity", cmd.Short)
+}
+
+func TestUndeleteCommandRunEClosureWithoutName(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewMockCLI()
+	cmd := UndeleteCommand(cli)
+	out, err := test.RunCmd(cmd, []string{})
+
+	assert.Regexp("Usage", out) // usage should print out
+	assert.Error(err)
+}
+
+func TestUndeleteCommandRunEClosureWithName(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewMockCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("UndeleteEntity", "default", "my-ID").Return(nil)
+
+	cmd := UndeleteCommand(cli)
+	out, err := test.RunCmd(cmd, []string{"my-ID"})
+
+	assert.Regexp("Restored", out)
+	assert.Nil(err)
+}
+
+func TestUndeleteCommandRunEClosureWithServerErr(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := test.NewMockCLI()
+	client := cli.Client.(*client.MockClient)
+	client.On("UndeleteEntity", mock.Anything, mock.Anything).Return(errors.New("oh noes"))
+
+	cmd := UndeleteCommand(cli)
+	out, err := test.RunCmd(cmd, []string{"test-entity"})
+
+	assert.Empty(out)
+	assert.NotNil(err)
+	assert.Equal("oh noes", err.Error())
+}
@@ -5,15 +5,17 @@ import (
 	"fmt"
 
 	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/completion"
 	"github.com/spf13/cobra"
 )
 
 // UpdateCommand adds command that allows user to create new checks
 func UpdateCommand(cli *cli.SensuCli) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "update [ID]",
-		Short:        "update entity",
-		SilenceUsage: false,
+		Use:               "update [ID]",
+		ValidArgsFunction: completion.EntityNames(cli),
+		Short:             "update entity",
+		SilenceUsage:      false,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Print out usage if we do not receive one argument
 			if len(args) != 1 {
@@ -0,0 +1,108 @@
+package event
+
+import (
+	"fmt"
+	"io"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/client"
+	"github.com/sensu/sensu-go/cli/commands/flags"
+	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/spf13/cobra"
+)
+
+// addBulkFlags adds the flags selecting the events of the bulk operations, and
+// the flag skipping their confirmation.
+func addBulkFlags(cmd *cobra.Command) {
+	_ = cmd.Flags().StringP("selector", "l", "", "operate on all the events matching this label selector instead of ENTITY CHECK")
+	helpers.AddFieldSelectorFlag(cmd.Flags())
+	_ = cmd.Flags().Bool("yes", false, "skip the confirmation of the operation on the selected events")
+}
+
+// bulkOptions returns the selectors of the bulk operations, or nil if none of
+// the selector flags is set.
+func bulkOptions(cmd *cobra.Command) (*client.ListOptions, error) {
+	labelSelector, err := cmd.Flags().GetString("selector")
+	if err != nil {
+		return nil, err
+	}
+	fieldSelector, err := cmd.Flags().GetString(flags.FieldSelector)
+	if err != nil {
+		return nil, err
+	}
+	if labelSelector == "" && fieldSelector == "" {
+		return nil, nil
+	}
+	return &client.ListOptions{LabelSelector: labelSelector, FieldSelector: fieldSelector}, nil
+}
+
+// bulkOperation is an operation on the events matching selectors, which is
+// done by the backend.
+type bulkOperation struct {
+	// verb and past are the verb of the operation, and its past tense.
+	verb, past string
+	// selects tells whether an event listed with the selectors is affected
+	// by the operation.
+	selects func(*corev2.Event) bool
+	do      func(namespace string, options *client.ListOptions) ([]client.BulkResult, error)
+}
+
+// run confirms the operation with the number of events it affects, unless
+// --yes is set, then does it and prints a summary of the affected events.
+func (op bulkOperation) run(cli *cli.SensuCli, cmd *cobra.Command, options *client.ListOptions) error {
+	namespace := cli.Config.Namespace()
+	out := cmd.OutOrStdout()
+
+	if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+		var events []corev2.Event
+		if err := cli.Client.List(client.EventsPath(namespace), &events, options, nil); err != nil {
+			return err
+		}
+		count := 0
+		for i := range events {
+			if op.selects(&events[i]) {
+				count++
+			}
+		}
+		if count == 0 {
+			_, err := fmt.Fprintf(out, "No events to %s\n", op.verb)
+			return err
+		}
+		confirm := &helpers.Confirm{
+			Message: fmt.Sprintf("Are you sure you would like to %s %d events in namespace %q?", op.verb, count, namespace),
+		}
+		if ok, err := confirm.Ask(); err != nil || !ok {
+			fmt.Fprintln(out, "Canceled")
+			return nil
+		}
+	}
+
+	results, err := op.do(namespace, options)
+	if err != nil {
+		return err
+	}
+	return op.summarize(out, results)
+}
+
+// summarize prints the events that the operation failed on, and the number
+// of affected events.
+func (op bulkOperation) summarize(w io.Writer, results []client.BulkResult) error {
+	failed := 0
+	for _, result := range results {
+		if result.Error == nil {
+			continue
+		}
+		failed++
+		if _, err := fmt.Fprintf(w, "Failed to %s %s: %s\n", op.verb, result.Name, result.Error.Message); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "%s %d events\n", op.past, len(results)-failed); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to %s %d events", op.verb, failed)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/sensu/sensu-go/cli/client"
+	mockclient "github.com/sensu/sensu-go/cli/client/testing"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveCommandSelector(t *testing.T) {
+	cli := test.NewMockCLI()
+	options := &client.ListOptions{LabelSelector: "region == us-west-1"}
+	cli.Client.(*mockclient.MockClient).
+		On("ResolveEvents", "default", options).
+		Return([]client.BulkResult{{Name: "foo/check-cpu", Status: 200}, {Name: "bar/check-cpu", Status: 200}}, nil)
+
+	cmd := ResolveCommand(cli)
+	require.NoError(t, cmd.Flags().Set("selector", "region == us-west-1"))
+	require.NoError(t, cmd.Flags().Set("yes", "true"))
+	out, err := test.RunCmd(cmd, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Resolved 2 events\n", out)
+}
+
+func TestDeleteCommandSelector(t *testing.T) {
+	cli := test.NewMockCLI()
+	options := &client.ListOptions{FieldSelector: "event.check.name == check-cpu"}
+	cli.Client.(*mockclient.MockClient).
+		On("DeleteEvents", "default", options).
+		Return([]client.BulkResult{
+			{Name: "foo/check-cpu", Status: 200},
+			{Name: "bar/check-cpu", Status: 404, Error: &client.APIError{Message: "not found"}},
+		}, nil)
+
+	cmd := DeleteCommand(cli)
+	require.NoError(t, cmd.Flags().Set("field-selector", "event.check.name == check-cpu"))
+	require.NoError(t, cmd.Flags().Set("yes", "true"))
+	out, err := test.RunCmd(cmd, nil)
+	require.EqualError(t, err, "failed to delete 1 events")
+	assert.Equal(t, "Failed to delete bar/check-cpu: not found\nDeleted 1 events\n", out)
+}
+
+func TestDeleteCommandSelectorNoEvents(t *testing.T) {
+	cli := test.NewMockCLI()
+	mockClient := cli.Client.(*mockclient.MockClient)
+	mockClient.On("List", client.EventsPath("default"), mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	cmd := DeleteCommand(cli)
+	require.NoError(t, cmd.Flags().Set("selector", "region == us-west-1"))
+	out, err := test.RunCmd(cmd, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "No events to delete\n", out)
+	mockClient.AssertNotCalled(t, "DeleteEvents", mock.Anything, mock.Anything)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/cli"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
 	"github.com/spf13/cobra"
@@ -12,10 +13,23 @@ import (
 // DeleteCommand deletes an event
 func DeleteCommand(cli *cli.SensuCli) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "delete [ENTITY] [CHECK]",
+		Use:          "delete [ENTITY] [CHECK] | --selector SELECTOR",
 		Short:        "delete events",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			options, err := bulkOptions(cmd)
+			if err != nil {
+				return err
+			}
+			if options != nil && len(args) == 0 {
+				op := bulkOperation{
+					verb:    "delete",
+					past:    "Deleted",
+					selects: (*corev2.Event).HasCheck,
+					do:      cli.Client.DeleteEvents,
+				}
+				return op.run(cli, cmd, options)
+			}
 			if len(args) != 2 {
 				_ = cmd.Help()
 				return errors.New("invalid argument(s) received")
@@ -33,8 +47,7 @@ func DeleteCommand(cli *cli.SensuCli) *cobra.Command {
 				}
 			}
 
-			err := cli.Client.DeleteEvent(namespace, entity, check)
-			if err != nil {
+			if err := cli.Client.DeleteEvent(namespace, entity, check); err != nil {
 				return err
 			}
 
@@ -44,6 +57,7 @@ func DeleteCommand(cli *cli.SensuCli) *cobra.Command {
 	}
 
 	_ = cmd.Flags().Bool("skip-confirm", false, "skip interactive confirmation prompt")
+	addBulkFlags(cmd)
 
 	return cmd
 }
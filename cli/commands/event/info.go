@@ -10,9 +10,12 @@ import (
 
 	"github.com/google/uuid"
 	v2 "github.com/sensu/core/v2"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/client/config"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
 	"github.com/sensu/sensu-go/cli/elements/list"
+	"github.com/sensu/sensu-go/cli/elements/table"
 	"github.com/spf13/cobra"
 )
 
@@ -39,15 +42,102 @@ func InfoCommand(cli *cli.SensuCli) *cobra.Command {
 			// Determine the format to use to output the data
 			flag := helpers.GetChangedStringValueViper("format", cmd.Flags())
 			format := cli.Config.Format()
-			return helpers.PrintFormatted(flag, format, event, cmd.OutOrStdout(), printToList)
+			if err := helpers.PrintFormatted(flag, format, event, cmd.OutOrStdout(), printToList); err != nil {
+				return err
+			}
+
+			withResults, err := cmd.Flags().GetBool(flagWithHandlerResults)
+			if err != nil || !withResults {
+				return err
+			}
+			results, err := cli.Client.FetchEventHandlerResults(entity, check)
+			if err != nil {
+				return err
+			}
+			if flag != "" {
+				format = flag
+			}
+			switch format {
+			case config.FormatJSON:
+				return helpers.PrintJSON(results, cmd.OutOrStdout())
+			case config.FormatYAML:
+				return helpers.PrintYAML(results, cmd.OutOrStdout())
+			default:
+				printHandlerResultsToTable(results, cmd.OutOrStdout())
+				return nil
+			}
 		},
 	}
 
 	helpers.AddFormatFlag(cmd.Flags())
+	cmd.Flags().Bool(flagWithHandlerResults, false, "also show the last results of the handlers executed for the event")
 
 	return cmd
 }
 
+const flagWithHandlerResults = "with-handler-results"
+
+func printHandlerResultsToTable(results []*storev2.HandlerResult, writer io.Writer) {
+	table := table.New([]*table.Column{
+		{
+			Title:       "Handler",
+			ColumnStyle: table.PrimaryTextStyle,
+			CellTransformer: func(data interface{}) string {
+				result, ok := data.(*storev2.HandlerResult)
+				if !ok {
+					return cli.TypeError
+				}
+				return result.Handler
+			},
+		},
+		{
+			Title: "Executed",
+			CellTransformer: func(data interface{}) string {
+				result, ok := data.(*storev2.HandlerResult)
+				if !ok {
+					return cli.TypeError
+				}
+				return time.Unix(result.Executed, 0).String()
+			},
+		},
+		{
+			Title: "Duration",
+			CellTransformer: func(data interface{}) string {
+				result, ok := data.(*storev2.HandlerResult)
+				if !ok {
+					return cli.TypeError
+				}
+				return time.Duration(result.Duration * float64(time.Second)).Round(time.Millisecond).String()
+			},
+		},
+		{
+			Title: "Status",
+			CellTransformer: func(data interface{}) string {
+				result, ok := data.(*storev2.HandlerResult)
+				if !ok {
+					return cli.TypeError
+				}
+				return strconv.Itoa(int(result.Status))
+			},
+		},
+		{
+			Title: "Output",
+			CellTransformer: func(data interface{}) string {
+				result, ok := data.(*storev2.HandlerResult)
+				if !ok {
+					return cli.TypeError
+				}
+				if result.Error != "" {
+					return result.Error
+				}
+				return strings.TrimSuffix(result.Output, "\n")
+			},
+		},
+	})
+
+	table.Render(writer, results)
+}
+
 func printToList(v interface{}, writer io.Writer) error {
 	event, ok := v.(*v2.Event)
 	if !ok {
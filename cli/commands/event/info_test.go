@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	v2 "github.com/sensu/core/v2"
+	storev2 "github.com/sensu/sensu-go/backend/store/v2"
 	client "github.com/sensu/sensu-go/cli/client/testing"
 	test "github.com/sensu/sensu-go/cli/commands/testing"
 	"github.com/stretchr/testify/assert"
@@ -78,3 +79,22 @@ func TestInfoCommandRunEClosureWithErr(t *testing.T) {
 	assert.Equal(t, "error", err.Error())
 	assert.Empty(t, out)
 }
+
+func TestInfoCommandRunEClosureWithHandlerResults(t *testing.T) {
+	cli := test.NewMockCLI()
+	cli.Client.(*client.MockClient).
+		On("FetchEvent", "foo", "check_foo").
+		Return(v2.FixtureEvent("foo", "check_foo"), nil)
+	cli.Client.(*client.MockClient).
+		On("FetchEventHandlerResults", "foo", "check_foo").
+		Return([]*storev2.HandlerResult{{Handler: "slack", Status: 2, Output: "channel not found"}}, nil)
+	cli.Config.(*client.MockConfig).On("Format").Return("tabular")
+
+	cmd := InfoCommand(cli)
+	require.NoError(t, cmd.Flags().Set("with-handler-results", "true"))
+
+	out, err := test.RunCmd(cmd, []string{"foo", "check_foo"})
+	require.NoError(t, err)
+	assert.Contains(t, out, "slack")
+	assert.Contains(t, out, "channel not found")
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	corev2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/cli"
 	"github.com/spf13/cobra"
 )
@@ -11,10 +12,23 @@ import (
 // ResolveCommand manually resolves an event
 func ResolveCommand(cli *cli.SensuCli) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "resolve [ENTITY] [CHECK]",
-		Short:        "manually resolves an event",
+		Use:          "resolve [ENTITY] [CHECK] | --selector SELECTOR",
+		Short:        "manually resolves an event, or the failing events matching selectors",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			options, err := bulkOptions(cmd)
+			if err != nil {
+				return err
+			}
+			if options != nil && len(args) == 0 {
+				op := bulkOperation{
+					verb:    "resolve",
+					past:    "Resolved",
+					selects: func(event *corev2.Event) bool { return event.HasCheck() && event.Check.Status != 0 },
+					do:      cli.Client.ResolveEvents,
+				}
+				return op.run(cli, cmd, options)
+			}
 			if len(args) != 2 {
 				_ = cmd.Help()
 				return errors.New("invalid argument(s) received")
@@ -38,5 +52,7 @@ func ResolveCommand(cli *cli.SensuCli) *cobra.Command {
 		},
 	}
 
+	addBulkFlags(cmd)
+
 	return cmd
 }
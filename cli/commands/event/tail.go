@@ -0,0 +1,123 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/client/config"
+	"github.com/sensu/sensu-go/cli/commands/helpers"
+	"github.com/spf13/cobra"
+)
+
+// tailReconnectInterval is the time waited before resuming an event stream
+// that ended.
+var tailReconnectInterval = time.Second
+
+// TailCommand defines new tail events command
+func TailCommand(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "tail",
+		Short:        "print events as they are processed",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				_ = cmd.Help()
+				return errors.New("invalid argument(s) received")
+			}
+
+			opts, err := helpers.ListOptionsFromFlags(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			format := cli.Config.Format()
+			if flag := helpers.GetChangedStringValueViper("format", cmd.Flags()); flag != "" {
+				format = flag
+			}
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer cancel()
+
+			// The streams are resumed with the last event received, which the
+			// backend sends again along with the events that were missed
+			printed := map[string]int64{}
+			printEvent := func(event *corev2.Event) error {
+				key := eventKey(event)
+				if ts, ok := printed[key]; ok && ts == event.Timestamp {
+					return nil
+				}
+				printed[key] = event.Timestamp
+				return printTailedEvent(format, event, cmd.OutOrStdout())
+			}
+
+			var lastEventID string
+			for {
+				lastEventID, err = cli.Client.StreamEvents(ctx, cli.Config.Namespace(), &opts, lastEventID, printEvent)
+				if ctx.Err() != nil {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(tailReconnectInterval):
+				}
+			}
+		},
+	}
+
+	helpers.AddFormatFlag(cmd.Flags())
+	helpers.AddFieldSelectorFlag(cmd.Flags())
+	helpers.AddLabelSelectorFlag(cmd.Flags())
+
+	return cmd
+}
+
+// eventKey identifies the events of a check of an entity.
+func eventKey(event *corev2.Event) string {
+	var entity, check string
+	if event.HasCheck() {
+		check = event.Check.Name
+	}
+	if event.Entity != nil {
+		entity = event.Entity.Name
+	}
+	return entity + "/" + check
+}
+
+// printTailedEvent prints an event in the given format. The tabular format
+// prints a line per event.
+func printTailedEvent(format string, event *corev2.Event, w io.Writer) error {
+	switch format {
+	case config.FormatJSON:
+		return helpers.PrintResourceJSON(event, w)
+	case config.FormatYAML:
+		if _, err := fmt.Fprintln(w, "---"); err != nil {
+			return err
+		}
+		return helpers.PrintYAML(event, w)
+	default:
+		var entity, check, output string
+		var status uint32
+		if event.Entity != nil {
+			entity = event.Entity.Name
+		}
+		if event.HasCheck() {
+			check = event.Check.Name
+			status = event.Check.Status
+			output = strings.TrimSpace(strings.SplitN(event.Check.Output, "\n", 2)[0])
+		}
+		timestamp := time.Unix(event.Timestamp, 0).Format(time.RFC3339)
+		_, err := fmt.Fprintf(w, "%s  %s  %s  %d  %s\n", timestamp, entity, check, status, output)
+		return err
+	}
+}
@@ -0,0 +1,66 @@
+package event
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	client "github.com/sensu/sensu-go/cli/client/testing"
+	"github.com/sensu/sensu-go/cli/commands/flags"
+	test "github.com/sensu/sensu-go/cli/commands/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTailCommand(t *testing.T) {
+	cli := newConfiguredCLI()
+	cmd := TailCommand(cli)
+
+	assert.NotNil(t, cmd, "cmd should be returned")
+	assert.NotNil(t, cmd.RunE, "cmd should be able to be executed")
+	assert.Regexp(t, "tail", cmd.Use)
+	assert.Regexp(t, "events", cmd.Short)
+}
+
+func TestTailCommandRunEClosure(t *testing.T) {
+	interval := tailReconnectInterval
+	tailReconnectInterval = 0
+	defer func() { tailReconnectInterval = interval }()
+
+	first := corev2.FixtureEvent("foo", "check_foo")
+	first.Timestamp = 1
+	second := corev2.FixtureEvent("bar", "check_bar")
+	second.Timestamp = 2
+	third := corev2.FixtureEvent("foo", "check_foo")
+	third.Timestamp = 3
+
+	cli := newConfiguredCLI()
+	mockClient := cli.Client.(*client.MockClient)
+	mockClient.On("StreamEvents", mock.Anything, "default", mock.Anything, "").
+		Return([]*corev2.Event{first, second}, "2", nil).Once()
+	// The stream is resumed with the last event received, which is sent again
+	mockClient.On("StreamEvents", mock.Anything, "default", mock.Anything, "2").
+		Return([]*corev2.Event{second, third}, "3", errors.New("error")).Once()
+
+	cmd := TailCommand(cli)
+	require.NoError(t, cmd.Flags().Set(flags.Format, "tabular"))
+	out, err := test.RunCmd(cmd, []string{})
+	require.Error(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "foo  check_foo")
+	assert.Contains(t, lines[1], "bar  check_bar")
+	assert.Contains(t, lines[2], "foo  check_foo")
+	mockClient.AssertExpectations(t)
+}
+
+func TestTailCommandRunMissingArgs(t *testing.T) {
+	cli := newConfiguredCLI()
+	cmd := TailCommand(cli)
+	out, err := test.RunCmd(cmd, []string{"foo"})
+	require.Error(t, err)
+	assert.Contains(t, out, "Usage")
+}
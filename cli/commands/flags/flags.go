@@ -21,4 +21,15 @@ const (
 	// ChunkSize is used to specify that a list of objects is to be fetched in
 	// chunks of the given size, using the API's pagination capabilities.
 	ChunkSize = "chunk-size"
+
+	// SubstituteEnv is used to substitute the ${VAR} references of the inputs
+	// with the values of the environment variables.
+	SubstituteEnv = "substitute-env"
+
+	// Template is used to execute the inputs as Go templates.
+	Template = "template"
+
+	// Fields is used to select the columns of the csv and jsonl outputs of the
+	// lists.
+	Fields = "fields"
 )
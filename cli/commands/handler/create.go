@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	v2 "github.com/sensu/core/v2"
+	pipelinehandler "github.com/sensu/sensu-go/backend/pipeline/handler"
 	"github.com/sensu/sensu-go/cli"
 	"github.com/sensu/sensu-go/cli/commands/flags"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
@@ -43,7 +44,7 @@ func CreateCommand(cli *cli.SensuCli) *cobra.Command {
 			handler := v2.Handler{}
 			opts.Copy(&handler)
 
-			if err := handler.Validate(); err != nil {
+			if err := pipelinehandler.ValidateHandler(&handler); err != nil {
 				if !isInteractive {
 					_ = cmd.Help()
 					return errors.New("invalid argument(s) received")
@@ -69,8 +70,10 @@ func CreateCommand(cli *cli.SensuCli) *cobra.Command {
 	cmd.Flags().String("socket-host", "", "host of handler socket")
 	cmd.Flags().String("socket-port", "", "port of handler socket")
 	cmd.Flags().StringP("timeout", "i", "", "execution duration timeout in seconds (hard stop)")
-	cmd.Flags().StringP("type", "t", typeDefault, "type of handler (pipe, tcp, udp, or set)")
+	cmd.Flags().StringP("type", "t", typeDefault, "type of handler (pipe, tcp, udp, set, http, grpc, or pagerduty)")
 	cmd.Flags().StringP("runtime-assets", "r", "", "comma separated list of assets this handler depends on")
+	cmd.Flags().String("annotations", "", "comma separated list of key=value annotations, e.g. sensu.io/http/url=https://example.com for http handlers")
+	cmd.Flags().String("secrets", "", "comma separated list of name=secret pairs of Sensu secrets, e.g. PAGERDUTY_ROUTING_KEY=pagerduty-key for pagerduty handlers")
 
 	helpers.AddInteractiveFlag(cmd.Flags())
 	return cmd
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"testing"
 
+	corev2 "github.com/sensu/core/v2"
+
 	client "github.com/sensu/sensu-go/cli/client/testing"
 	test "github.com/sensu/sensu-go/cli/commands/testing"
 	"github.com/stretchr/testify/assert"
@@ -71,3 +73,66 @@ func TestCreateCommandRunEClosureWithAPIErr(t *testing.T) {
 	assert.NotNil(err)
 	assert.Equal("nope", err.Error())
 }
+
+func TestCreateCommandRunEClosureWithPipelineTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		wantErr bool
+	}{
+		{
+			name: "http",
+			flags: map[string]string{
+				"type":        "http",
+				"annotations": "sensu.io/http/url=https://example.com/events,sensu.io/http/retries=2",
+			},
+		},
+		{
+			name:    "http without url",
+			flags:   map[string]string{"type": "http"},
+			wantErr: true,
+		},
+		{
+			name: "grpc",
+			flags: map[string]string{
+				"type":        "grpc",
+				"socket-host": "localhost",
+				"socket-port": "50051",
+			},
+		},
+		{
+			name: "pagerduty",
+			flags: map[string]string{
+				"type":    "pagerduty",
+				"secrets": "PAGERDUTY_ROUTING_KEY=pagerduty-key",
+			},
+		},
+		{
+			name:    "pagerduty without routing key",
+			flags:   map[string]string{"type": "pagerduty"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := test.NewMockCLI()
+			client := cli.Client.(*client.MockClient)
+			client.On("CreateHandler", mock.MatchedBy(func(h *corev2.Handler) bool {
+				return h.Type == tt.flags["type"]
+			})).Return(nil)
+
+			cmd := CreateCommand(cli)
+			for name, value := range tt.flags {
+				require.NoError(t, cmd.Flags().Set(name, value))
+			}
+			out, err := test.RunCmd(cmd, []string{"test-handler"})
+			if tt.wantErr {
+				assert.Error(t, err)
+				client.AssertNotCalled(t, "CreateHandler", mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Regexp(t, "Created", out)
+		})
+	}
+}
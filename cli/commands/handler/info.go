@@ -9,6 +9,7 @@ import (
 
 	v2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/completion"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
 	"github.com/sensu/sensu-go/cli/elements/list"
 	"github.com/sensu/sensu-go/cli/elements/table"
@@ -19,6 +20,7 @@ import (
 func InfoCommand(cli *cli.SensuCli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:		"info [ID]",
+		ValidArgsFunction:	completion.HandlerNames(cli),
 		Short:		"show detailed handler information",
 		SilenceUsage:	true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	v2 "github.com/sensu/core/v2"
+	pipelinehandler "github.com/sensu/sensu-go/backend/pipeline/handler"
 	"github.com/sensu/sensu-go/cli/commands/helpers"
 	"github.com/spf13/pflag"
 )
@@ -23,6 +25,8 @@ type handlerOpts struct {
 	Type		string	`survey:"type"`
 	Namespace	string
 	RuntimeAssets	string	`survey:"assets"`
+	Annotations	string
+	Secrets		string
 }
 
 const (
@@ -65,6 +69,8 @@ func (opts *handlerOpts) withFlags(flags *pflag.FlagSet) {
 	opts.Timeout, _ = flags.GetString("timeout")
 	opts.Type, _ = flags.GetString("type")
 	opts.RuntimeAssets, _ = flags.GetString("runtime-assets")
+	opts.Annotations, _ = flags.GetString("annotations")
+	opts.Secrets, _ = flags.GetString("secrets")
 
 	if namespace := helpers.GetChangedStringValueViper("namespace", flags); namespace != "" {
 		opts.Namespace = namespace
@@ -83,6 +89,8 @@ func (opts *handlerOpts) administerQuestionnaire(editing bool) error {
 	case v2.HandlerTCPType:
 		fallthrough
 	case v2.HandlerUDPType:
+		fallthrough
+	case pipelinehandler.HandlerGRPCType:
 		return opts.queryForSocket()
 	case v2.HandlerSetType:
 		return opts.queryForHandlers()
@@ -149,7 +157,7 @@ func (opts *handlerOpts) queryForBaseParameters(editing bool) error {
 			Name:	"type",
 			Prompt: &survey.Select{
 				Message:	"Type:",
-				Options:	[]string{"pipe", "tcp", "udp", "set"},
+				Options:	[]string{"pipe", "tcp", "udp", "set", "http", "grpc", "pagerduty"},
 				Default:	opts.Type,
 			},
 			Validate:	survey.Required,
@@ -262,4 +270,35 @@ func (opts *handlerOpts) Copy(handler *v2.Handler) {
 	for i, h := range assets {
 		handler.RuntimeAssets[i] = strings.TrimSpace(h)
 	}
+
+	// The annotations and the secrets configure the http, grpc and pagerduty
+	// handlers, and are added to those of the handler
+	for key, value := range splitKeyValues(opts.Annotations) {
+		if handler.Annotations == nil {
+			handler.Annotations = make(map[string]string)
+		}
+		handler.Annotations[key] = value
+	}
+	secrets := splitKeyValues(opts.Secrets)
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		handler.Secrets = append(handler.Secrets, &v2.Secret{Name: name, Secret: secrets[name]})
+	}
+}
+
+// splitKeyValues splits a comma separated list of key=value pairs
+func splitKeyValues(s string) map[string]string {
+	values := make(map[string]string)
+	for _, pair := range helpers.SafeSplitCSV(s) {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || key == "" {
+			continue
+		}
+		values[key] = value
+	}
+	return values
 }
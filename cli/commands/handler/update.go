@@ -4,16 +4,19 @@ import (
 	"errors"
 	"fmt"
 
+	pipelinehandler "github.com/sensu/sensu-go/backend/pipeline/handler"
 	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/commands/completion"
 	"github.com/spf13/cobra"
 )
 
 // UpdateCommand allows the user to update handlers
 func UpdateCommand(cli *cli.SensuCli) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "update [NAME]",
-		Short:        "update handlers",
-		SilenceUsage: false,
+		Use:               "update [NAME]",
+		ValidArgsFunction: completion.HandlerNames(cli),
+		Short:             "update handlers",
+		SilenceUsage:      false,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Print out usage if we do not receive one argument
 			if len(args) != 1 {
@@ -37,7 +40,7 @@ func UpdateCommand(cli *cli.SensuCli) *cobra.Command {
 
 			opts.Copy(handler)
 
-			if err := handler.Validate(); err != nil {
+			if err := pipelinehandler.ValidateHandler(handler); err != nil {
 				return err
 			}
 
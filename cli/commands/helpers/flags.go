@@ -33,6 +33,25 @@ func AddFormatFlag(flagSet *pflag.FlagSet) {
 	)
 }
 
+// AddListFormatFlag adds the format flag of the list commands, which also
+// supports the csv and jsonl formats, and the '--fields' flag selecting their
+// columns.
+func AddListFormatFlag(flagSet *pflag.FlagSet) {
+	flagSet.String(
+		"format",
+		config.DefaultFormat,
+		fmt.Sprintf(
+			`format of data returned ("%s"|"%s"|"%s"|"%s"|"%s")`,
+			config.FormatJSON,
+			config.FormatTabular,
+			config.FormatYAML,
+			config.FormatCSV,
+			config.FormatJSONL,
+		),
+	)
+	flagSet.StringSlice(flags.Fields, nil, `fields of the csv and jsonl formats, in order, e.g. "metadata.name,interval" (default all the fields, sorted)`)
+}
+
 // AddAllNamespace adds the '--all-namespaces' flag to the given command
 func AddAllNamespace(flagSet *pflag.FlagSet) {
 	flagSet.Bool(flags.AllNamespaces, false, "Include records from all namespaces")
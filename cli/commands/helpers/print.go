@@ -48,6 +48,17 @@ func Print(cmd *cobra.Command, format string, printTable printTableFunc, objects
 			return PrintYAML(v, cmd.OutOrStdout())
 		}
 		return PrintYAML(objects, cmd.OutOrStdout())
+	case config.FormatCSV, config.FormatJSONL:
+		var fields []string
+		if cmd.Flags().Lookup(flags.Fields) != nil {
+			if fields, err = cmd.Flags().GetStringSlice(flags.Fields); err != nil {
+				return err
+			}
+		}
+		if format == config.FormatCSV {
+			return PrintCSV(objects, fields, cmd.OutOrStdout())
+		}
+		return PrintJSONL(objects, fields, cmd.OutOrStdout())
 	default:
 		printTable(v, cmd.OutOrStdout())
 	}
@@ -83,7 +94,9 @@ func PrintTitle(flag string, format string, title string, w io.Writer) error {
 	}
 	// checking the formats exclusively to cover invalid formats
 	// that get defaulted to tabular
-	if format != config.FormatJSON && format != config.FormatYAML {
+	switch format {
+	case config.FormatJSON, config.FormatYAML, config.FormatCSV, config.FormatJSONL:
+	default:
 		cfg := &list.Config{
 			Title: title,
 		}
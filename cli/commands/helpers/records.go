@@ -0,0 +1,144 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/core/v3/types"
+)
+
+// PrintCSV prints resources as CSV, with a header and a row per resource. The
+// columns are the given fields, in order, or all the fields of the resources,
+// sorted. The fields are the paths of the JSON values of the resources, like
+// "metadata.name"; the lists and the objects are written as compact JSON.
+func PrintCSV(resources []corev3.Resource, fields []string, w io.Writer) error {
+	records, err := flattenResources(resources)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		fields = recordFields(records)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(fields); err != nil {
+		return err
+	}
+	row := make([]string, len(fields))
+	for _, record := range records {
+		for i, field := range fields {
+			if row[i], err = csvValue(record[field]); err != nil {
+				return err
+			}
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// PrintJSONL prints resources as JSON lines, a compact JSON document per
+// resource. The resources are wrapped like with the json format, unless fields
+// are given, in which case a document has the values of the fields of a
+// resource, keyed by their paths.
+func PrintJSONL(resources []corev3.Resource, fields []string, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	if len(fields) == 0 {
+		for _, r := range resources {
+			if err := encoder.Encode(types.WrapResource(r)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	records, err := flattenResources(resources)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		selected := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			selected[field] = record[field]
+		}
+		if err := encoder.Encode(selected); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flattenResources returns the fields of each resource, keyed by their path.
+func flattenResources(resources []corev3.Resource) ([]map[string]interface{}, error) {
+	records := make([]map[string]interface{}, 0, len(resources))
+	for _, r := range resources {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return nil, err
+		}
+		decoder := json.NewDecoder(bytes.NewReader(b))
+		decoder.UseNumber()
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		record := map[string]interface{}{}
+		flatten("", value, record)
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// flatten adds the values of the objects to record, keyed by their path. The
+// lists are values.
+func flatten(prefix string, value interface{}, record map[string]interface{}) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		record[prefix] = value
+		return
+	}
+	for k, v := range object {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+		flatten(k, v, record)
+	}
+}
+
+// recordFields returns the fields of all the records, sorted.
+func recordFields(records []map[string]interface{}) []string {
+	seen := map[string]bool{}
+	fields := []string{}
+	for _, record := range records {
+		for field := range record {
+			if !seen[field] {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func csvValue(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case json.Number, bool:
+		return fmt.Sprint(value), nil
+	default:
+		b, err := json.Marshal(value)
+		return string(b), err
+	}
+}
@@ -0,0 +1,61 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/core/v3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordsFixture() []corev3.Resource {
+	foo := corev2.FixtureCheckConfig("foo")
+	foo.Labels = map[string]string{"region": "us-west-1"}
+	foo.Subscriptions = []string{"linux", "windows"}
+	bar := corev2.FixtureCheckConfig("bar")
+	bar.Command = `echo "a, b"`
+	return []corev3.Resource{foo, bar}
+}
+
+func TestPrintCSVFields(t *testing.T) {
+	var buf bytes.Buffer
+	fields := []string{"metadata.name", "metadata.labels.region", "interval", "command", "subscriptions", "publish"}
+	require.NoError(t, PrintCSV(recordsFixture(), fields, &buf))
+	assert.Equal(t, `metadata.name,metadata.labels.region,interval,command,subscriptions,publish
+foo,us-west-1,60,command,"[""linux"",""windows""]",true
+bar,,60,"echo ""a, b""","[""linux""]",true
+`, buf.String())
+}
+
+func TestPrintCSVAllFields(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, PrintCSV(recordsFixture(), nil, &buf))
+	header := strings.Split(strings.SplitN(buf.String(), "\n", 2)[0], ",")
+	assert.Contains(t, header, "metadata.name")
+	assert.Contains(t, header, "metadata.labels.region")
+	assert.IsIncreasing(t, header)
+	assert.Len(t, strings.Split(strings.TrimSpace(buf.String()), "\n"), 3)
+}
+
+func TestPrintJSONL(t *testing.T) {
+	resources := recordsFixture()
+
+	var buf bytes.Buffer
+	require.NoError(t, PrintJSONL(resources, nil, &buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var w types.Wrapper
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &w))
+	assert.Equal(t, "bar", w.Value.(*corev2.CheckConfig).Name)
+
+	buf.Reset()
+	require.NoError(t, PrintJSONL(resources, []string{"metadata.name", "interval", "metadata.labels.region"}, &buf))
+	assert.Equal(t, `{"interval":60,"metadata.labels.region":"us-west-1","metadata.name":"foo"}
+{"interval":60,"metadata.labels.region":null,"metadata.name":"bar"}
+`, buf.String())
+}
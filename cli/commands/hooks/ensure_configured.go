@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/client/config"
 	"github.com/spf13/cobra"
 )
 
@@ -58,3 +59,9 @@ func ConfigurationPresent(cmd *cobra.Command, cli *cli.SensuCli) error {
 
 	return nil
 }
+
+// ContextValid checks that the name of the active context, which can be given
+// with the --context flag, is valid.
+func ContextValid(cli *cli.SensuCli) error {
+	return config.ValidateContextName(cli.Config.Context())
+}
@@ -0,0 +1,194 @@
+package restore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	corev2 "github.com/sensu/core/v2"
+	corev3 "github.com/sensu/core/v3"
+	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/cli"
+	"github.com/sensu/sensu-go/cli/client"
+	"github.com/sensu/sensu-go/cli/resource"
+	"github.com/sensu/sensu-go/util/compat"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// ConflictOverwrite replaces the resources that already exist
+	ConflictOverwrite = "overwrite"
+
+	// ConflictSkip leaves the resources that already exist untouched
+	ConflictSkip = "skip"
+
+	// ConflictFail stops the restore at the first resource that already exists
+	ConflictFail = "fail"
+)
+
+var description = `sensuctl restore
+
+Restore the resources of a dump from file or URL (path, file://, http[s]://),
+or STDIN otherwise. Example:
+$ sensuctl dump all -f dump.yaml
+$ sensuctl restore -f dump.yaml
+
+The resources are restored in dependency order: namespaces and the cluster-wide
+resources first, then the namespaced resources. The resources that already
+exist are replaced by default, or skipped or reported as errors with
+--on-conflict.
+`
+
+// Command restores the resources dumped by "sensuctl dump".
+func Command(cli *cli.SensuCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "restore [-r] [[-f URL] ... ] [--on-conflict overwrite|skip|fail]",
+		Short:        "Restore resources dumped by sensuctl dump",
+		Long:         description,
+		SilenceUsage: true,
+		RunE:         execute(cli),
+	}
+
+	_ = cmd.Flags().StringSliceP("file", "f", nil, "Files, directories, or URLs to restore resources from")
+	_ = cmd.Flags().BoolP("recursive", "r", false, "Follow subdirectories")
+	_ = cmd.Flags().String("on-conflict", ConflictOverwrite, fmt.Sprintf(`what to do with the resources that already exist ("%s"|"%s"|"%s")`, ConflictOverwrite, ConflictSkip, ConflictFail))
+
+	return cmd
+}
+
+func execute(cli *cli.SensuCli) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			_ = cmd.Help()
+			return errors.New("invalid argument(s) received")
+		}
+		onConflict, err := cmd.Flags().GetString("on-conflict")
+		if err != nil {
+			return err
+		}
+		switch onConflict {
+		case ConflictOverwrite, ConflictSkip, ConflictFail:
+		default:
+			return fmt.Errorf("invalid conflict policy %q", onConflict)
+		}
+		t := &http.Transport{}
+		t.RegisterProtocol("file", http.NewFileTransport(http.Dir("/")))
+		client := &http.Client{Transport: t}
+		inputs, err := cmd.Flags().GetStringSlice("file")
+		if err != nil {
+			return err
+		}
+		processor := NewRestorer(cmd.OutOrStdout(), onConflict)
+		if len(inputs) == 0 {
+			return resource.ProcessStdin(cli, client, processor, nil)
+		}
+		recurse, err := cmd.Flags().GetBool("recursive")
+		if err != nil {
+			return err
+		}
+		return resource.Process(cli, client, inputs, recurse, processor, nil)
+	}
+}
+
+// Restorer is a Processor that puts resources in the API in dependency order,
+// applying a conflict policy to the resources that already exist.
+type Restorer struct {
+	w          io.Writer
+	onConflict string
+}
+
+// NewRestorer instantiates a new Restorer Processor, printing a summary to w.
+func NewRestorer(w io.Writer, onConflict string) *Restorer {
+	return &Restorer{w: w, onConflict: onConflict}
+}
+
+// Process restores the resources, namespaces and cluster-wide resources first.
+// The resources that can't be restored or already exist, depending on the
+// conflict policy, are skipped.
+func (r *Restorer) Process(client client.GenericClient, resources []*types.Wrapper) error {
+	resources = Sort(resources)
+	restored, skipped := 0, 0
+	for _, resource := range resources {
+		ok, err := r.restore(client, resource)
+		if err != nil {
+			return fmt.Errorf("error restoring %s: %s", name(resource), err)
+		}
+		if ok {
+			restored++
+		} else {
+			skipped++
+		}
+	}
+	if skipped > 0 {
+		_, err := fmt.Fprintf(r.w, "Restored %d resources, skipped %d resources\n", restored, skipped)
+		return err
+	}
+	_, err := fmt.Fprintf(r.w, "Restored %d resources\n", restored)
+	return err
+}
+
+// restore puts a resource, and returns whether it was restored or skipped.
+func (r *Restorer) restore(c client.GenericClient, resource *types.Wrapper) (bool, error) {
+	// The API doesn't return the password hashes of the users, so the dumped
+	// users can't be restored
+	if user, ok := resource.Value.(*corev2.User); ok && user.Password == "" && user.PasswordHash == "" {
+		_, err := fmt.Fprintf(r.w, "Skipped %s: the dump has no password hash\n", name(resource))
+		return false, err
+	}
+	if r.onConflict != ConflictOverwrite {
+		err := c.Get(compat.URIPath(resource.Value), &types.Wrapper{})
+		if err == nil {
+			if r.onConflict == ConflictSkip {
+				return false, nil
+			}
+			return false, errors.New("the resource already exists")
+		}
+		if err, ok := err.(client.APIError); !ok || actions.ErrCode(err.Code) != actions.NotFound {
+			return false, err
+		}
+	}
+	if err := c.PutResource(*resource); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Sort sorts the resources in the order of resource.All, which has the
+// namespaces and the cluster-wide resources first, so that the resources are
+// restored after the resources they depend on. The other types are sorted
+// last, and the order of the resources of a type is kept.
+func Sort(resources []*types.Wrapper) []*types.Wrapper {
+	order := make(map[string]int, len(resource.All))
+	for i, r := range resource.All {
+		order[typeName(types.WrapResource(r))] = i
+	}
+	rank := func(w *types.Wrapper) int {
+		if r, ok := w.Value.(corev3.Resource); ok {
+			if i, ok := order[typeName(types.WrapResource(r))]; ok {
+				return i
+			}
+		}
+		return len(resource.All)
+	}
+	sorted := make([]*types.Wrapper, len(resources))
+	copy(sorted, resources)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return rank(sorted[i]) < rank(sorted[j])
+	})
+	return sorted
+}
+
+func typeName(w types.Wrapper) string {
+	return w.APIVersion + "." + w.Type
+}
+
+func name(w *types.Wrapper) string {
+	meta := compat.GetObjectMeta(w.Value)
+	if meta.Namespace != "" {
+		return fmt.Sprintf("%s %s/%s", w.Type, meta.Namespace, meta.Name)
+	}
+	return fmt.Sprintf("%s %s", w.Type, meta.Name)
+}
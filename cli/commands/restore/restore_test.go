@@ -0,0 +1,134 @@
+package restore
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/sensu/core/v3/types"
+	"github.com/sensu/sensu-go/backend/apid/actions"
+	"github.com/sensu/sensu-go/cli/client"
+	mockclient "github.com/sensu/sensu-go/cli/client/testing"
+	"github.com/sensu/sensu-go/cli/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const dump = `
+type: CheckConfig
+api_version: core/v2
+spec:
+  metadata:
+    name: cpu
+    namespace: ops
+  command: check-cpu
+  interval: 60
+---
+type: Role
+api_version: core/v2
+spec:
+  metadata:
+    name: viewer
+    namespace: ops
+  rules:
+  - verbs: [get]
+    resources: [checks]
+---
+type: Namespace
+api_version: core/v3
+spec:
+  metadata:
+    name: ops
+`
+
+func resources(t *testing.T) []*types.Wrapper {
+	t.Helper()
+	resources, err := resource.Parse(strings.NewReader(dump))
+	require.NoError(t, err)
+	return resources
+}
+
+func TestSort(t *testing.T) {
+	var names []string
+	for _, w := range Sort(resources(t)) {
+		names = append(names, w.Type)
+	}
+	assert.Equal(t, []string{"Namespace", "CheckConfig", "Role"}, names)
+}
+
+func TestRestorer(t *testing.T) {
+	notFound := client.APIError{Code: uint32(actions.NotFound)}
+
+	tests := []struct {
+		name       string
+		onConflict string
+		exists     bool
+		wantPuts   int
+		wantOut    string
+		wantErr    bool
+	}{
+		{
+			name:       "overwrite",
+			onConflict: ConflictOverwrite,
+			exists:     true,
+			wantPuts:   3,
+			wantOut:    "Restored 3 resources\n",
+		},
+		{
+			name:       "skip existing",
+			onConflict: ConflictSkip,
+			exists:     true,
+			wantOut:    "Restored 0 resources, skipped 3 resources\n",
+		},
+		{
+			name:       "skip missing",
+			onConflict: ConflictSkip,
+			wantPuts:   3,
+			wantOut:    "Restored 3 resources\n",
+		},
+		{
+			name:       "fail existing",
+			onConflict: ConflictFail,
+			exists:     true,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(mockclient.MockClient)
+			if tt.exists {
+				mockClient.On("Get", mock.Anything, mock.Anything).Return(nil)
+			} else {
+				mockClient.On("Get", mock.Anything, mock.Anything).Return(notFound)
+			}
+			mockClient.On("PutResource", mock.Anything).Return(nil)
+
+			var out bytes.Buffer
+			err := NewRestorer(&out, tt.onConflict).Process(mockClient, resources(t))
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "Namespace ops")
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantOut, out.String())
+			}
+			mockClient.AssertNumberOfCalls(t, "PutResource", tt.wantPuts)
+			if tt.onConflict == ConflictOverwrite {
+				mockClient.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestRestorerUserWithoutPassword(t *testing.T) {
+	user := types.WrapResource(corev2.FixtureUser("bob"))
+	user.Value.(*corev2.User).Password = ""
+	mockClient := new(mockclient.MockClient)
+
+	var out bytes.Buffer
+	require.NoError(t, NewRestorer(&out, ConflictOverwrite).Process(mockClient, []*types.Wrapper{&user}))
+	assert.Equal(t, "Skipped User bob: the dump has no password hash\nRestored 0 resources, skipped 1 resources\n", out.String())
+	mockClient.AssertNotCalled(t, "PutResource", mock.Anything)
+}
@@ -38,6 +38,7 @@ func Command() *cobra.Command {
 	cmd.PersistentFlags().String("trusted-ca-file", "", "TLS CA certificate bundle in PEM format")
 	cmd.PersistentFlags().Bool("insecure-skip-tls-verify", false, "skip TLS certificate verification (not recommended!)")
 	cmd.PersistentFlags().String("config-dir", path.UserConfigDir("sensuctl"), "path to directory containing configuration files")
+	cmd.PersistentFlags().String("context", "", "name of the context to use instead of the current context")
 	cmd.PersistentFlags().String("cache-dir", path.UserCacheDir("sensuctl"), "path to directory containing cache & temporary files")
 	cmd.PersistentFlags().String("namespace", config.DefaultNamespace, "namespace in which we perform actions")
 	cmd.PersistentFlags().Duration("timeout", 15*time.Second, "timeout when communicating with sensu backend")
@@ -74,7 +74,7 @@ func ListCommand(cli *cli.SensuCli) *cobra.Command {
 	}
 
 	flags := cmd.Flags()
-	helpers.AddFormatFlag(flags)
+	helpers.AddListFormatFlag(flags)
 	helpers.AddAllNamespace(flags)
 	helpers.AddFieldSelectorFlag(cmd.Flags())
 	helpers.AddLabelSelectorFlag(cmd.Flags())
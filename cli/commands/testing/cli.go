@@ -28,6 +28,7 @@ func NewMockCLI() *cli.SensuCli {
 
 	// Set defaults ...
 	config.On("Namespace").Return("default")
+	config.On("Context").Return("default")
 
 	return &cli.SensuCli{
 		Client: client,
@@ -29,11 +29,12 @@ type httpDirectory struct {
 	Files   []string `xml:"a"`
 }
 
-// Process processes the input.
-func Process(cli *cli.SensuCli, client *http.Client, inputs []string, recurse bool, processor Processor) error {
+// Process processes the input. The inputs are rendered by the renderer, if
+// not nil.
+func Process(cli *cli.SensuCli, client *http.Client, inputs []string, recurse bool, processor Processor, renderer Renderer) error {
 	var resources []*types.Wrapper
 	for _, input := range inputs {
-		res, err := process(client, input, recurse)
+		res, err := process(client, input, recurse, renderer)
 		if err != nil {
 			return err
 		}
@@ -45,7 +46,7 @@ func Process(cli *cli.SensuCli, client *http.Client, inputs []string, recurse bo
 	return processor.Process(cli.Client, resources)
 }
 
-func process(client *http.Client, input string, recurse bool) ([]*types.Wrapper, error) {
+func process(client *http.Client, input string, recurse bool, renderer Renderer) ([]*types.Wrapper, error) {
 	var resources []*types.Wrapper
 	urly, err := url.Parse(input)
 	if err != nil {
@@ -54,12 +55,12 @@ func process(client *http.Client, input string, recurse bool) ([]*types.Wrapper,
 	var res []*types.Wrapper
 	if urly.Scheme == "" || len(urly.Scheme) == 1 {
 		// We are dealing with a file path
-		res, err = ProcessFile(input, recurse)
+		res, err = ProcessFile(input, recurse, renderer)
 		if err != nil {
 			return resources, err
 		}
 	} else {
-		res, err = ProcessURL(client, urly, input, recurse)
+		res, err = ProcessURL(client, urly, input, recurse, renderer)
 		if err != nil {
 			return resources, err
 		}
@@ -69,7 +70,7 @@ func process(client *http.Client, input string, recurse bool) ([]*types.Wrapper,
 }
 
 // ProcessFile processes a file.
-func ProcessFile(input string, recurse bool) ([]*types.Wrapper, error) {
+func ProcessFile(input string, recurse bool, renderer Renderer) ([]*types.Wrapper, error) {
 	var resources []*types.Wrapper
 	var tld = true
 	err := filepath.Walk(input, func(path string, info os.FileInfo, err error) error {
@@ -95,11 +96,11 @@ func ProcessFile(input string, recurse bool) ([]*types.Wrapper, error) {
 			tld = false
 			return nil
 		}
-		f, err := os.Open(path)
+		b, err := os.ReadFile(path)
 		if err != nil {
 			return err
 		}
-		res, err := Parse(f)
+		res, err := parse(b, renderer)
 		if err != nil {
 			return fmt.Errorf("in %s: %s", input, err)
 		}
@@ -110,7 +111,7 @@ func ProcessFile(input string, recurse bool) ([]*types.Wrapper, error) {
 }
 
 // ProcessURL processes a url.
-func ProcessURL(client *http.Client, urly *url.URL, input string, recurse bool) ([]*types.Wrapper, error) {
+func ProcessURL(client *http.Client, urly *url.URL, input string, recurse bool, renderer Renderer) ([]*types.Wrapper, error) {
 	var resources []*types.Wrapper
 	req, err := http.NewRequest("GET", urly.String(), nil)
 	if err != nil {
@@ -138,14 +139,18 @@ func ProcessURL(client *http.Client, urly *url.URL, input string, recurse bool)
 			return resources, err
 		}
 		for _, file := range dir.Files {
-			res, err := process(client, filepath.Join(input, file), recurse)
+			res, err := process(client, filepath.Join(input, file), recurse, renderer)
 			if err != nil {
 				return resources, err
 			}
 			resources = append(resources, res...)
 		}
 	} else {
-		resources, err = Parse(resp.Body)
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return resources, err
+		}
+		resources, err = parse(b, renderer)
 		if err != nil {
 			return resources, fmt.Errorf("in %s: %s", input, err)
 		}
@@ -153,9 +158,14 @@ func ProcessURL(client *http.Client, urly *url.URL, input string, recurse bool)
 	return resources, nil
 }
 
-// ProcessStdin processes standard in.
-func ProcessStdin(cli *cli.SensuCli, client *http.Client, processor Processor) error {
-	resources, err := Parse(os.Stdin)
+// ProcessStdin processes standard in. The input is rendered by the renderer,
+// if not nil.
+func ProcessStdin(cli *cli.SensuCli, client *http.Client, processor Processor, renderer Renderer) error {
+	b, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+	resources, err := parse(b, renderer)
 	if err != nil {
 		return fmt.Errorf("in stdin: %s", err)
 	}
@@ -165,8 +175,21 @@ func ProcessStdin(cli *cli.SensuCli, client *http.Client, processor Processor) e
 	return processor.Process(cli.Client, resources)
 }
 
+// parse renders an input, and parses its resources.
+func parse(input []byte, renderer Renderer) ([]*types.Wrapper, error) {
+	input, err := render(input, renderer)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(bytes.NewReader(input))
+}
+
 // Putter is a Processor that puts resources in the API.
-type Putter struct{}
+type Putter struct {
+	// DryRun only validates the resources with the API, without persisting
+	// them.
+	DryRun bool
+}
 
 // NewPutter instantiates a new Putter Processor.
 func NewPutter() *Putter {
@@ -175,8 +198,12 @@ func NewPutter() *Putter {
 
 // Process puts resources in the API.
 func (p *Putter) Process(client client.GenericClient, resources []*types.Wrapper) error {
+	put := client.PutResource
+	if p.DryRun {
+		put = client.PutResourceDryRun
+	}
 	for i, resource := range resources {
-		if err := client.PutResource(*resource); err != nil {
+		if err := put(*resource); err != nil {
 			return fmt.Errorf(
 				"error putting resource #%d: %s", i, err,
 			)
@@ -190,6 +217,10 @@ func (p *Putter) Process(client client.GenericClient, resources []*types.Wrapper
 type ManagedByLabelPutter struct {
 	putter *Putter
 	Label  string
+
+	// DryRun only validates the resources with the API, without persisting
+	// them.
+	DryRun bool
 }
 
 func NewManagedByLabelPutter(label string) *ManagedByLabelPutter {
@@ -203,6 +234,7 @@ func (p *ManagedByLabelPutter) Process(client client.GenericClient, resources []
 	for _, resource := range resources {
 		p.label(resource)
 	}
+	p.putter.DryRun = p.DryRun
 	return p.putter.Process(client, resources)
 }
 
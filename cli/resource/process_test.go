@@ -30,7 +30,7 @@ func TestProcessURL(t *testing.T) {
 
 	urly, err := url.Parse(ts.URL)
 	assert.NoError(t, err)
-	_, err = ProcessURL(ts.Client(), urly, ts.URL, false)
+	_, err = ProcessURL(ts.Client(), urly, ts.URL, false, nil)
 	assert.NoError(t, err)
 }
 
@@ -43,7 +43,7 @@ func TestProcessFile(t *testing.T) {
 	err = ioutil.WriteFile(fp, []byte(`{"type": "Namespace", "spec": {"name": "foo"}}`), 0644)
 	assert.NoError(t, err)
 
-	_, err = ProcessFile(fp, false)
+	_, err = ProcessFile(fp, false, nil)
 	assert.NoError(t, err)
 }
 
@@ -0,0 +1,153 @@
+package resource
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/sensu/sensu-go/cli/commands/flags"
+	"github.com/spf13/pflag"
+)
+
+// Renderer renders the resources of an input before they are parsed, so that
+// a manifest can be promoted across environments.
+type Renderer func(input []byte) ([]byte, error)
+
+// NewRenderer returns a Renderer executing the inputs as Go templates, when
+// templates is set, and then substituting the environment variables of the
+// inputs, when env is set. It returns nil if neither is set.
+func NewRenderer(env, templates bool) Renderer {
+	if !env && !templates {
+		return nil
+	}
+	return func(input []byte) ([]byte, error) {
+		var err error
+		if templates {
+			if input, err = ExecuteTemplate(input); err != nil {
+				return nil, err
+			}
+		}
+		if env {
+			if input, err = SubstituteEnv(input); err != nil {
+				return nil, err
+			}
+		}
+		return input, nil
+	}
+}
+
+// AddRenderFlags adds the flags configuring the rendering of the inputs to the
+// flag set.
+func AddRenderFlags(flagSet *pflag.FlagSet) {
+	_ = flagSet.Bool(flags.SubstituteEnv, false, "Substitute the ${VAR} references of the inputs with the environment variables ($$ escapes $)")
+	_ = flagSet.Bool(flags.Template, false, "Execute the inputs as Go templates, with the environment variables as .Env")
+}
+
+// RendererFromFlags returns the Renderer configured by the flags added by
+// AddRenderFlags, or nil if the inputs are not rendered.
+func RendererFromFlags(flagSet *pflag.FlagSet) (Renderer, error) {
+	env, err := flagSet.GetBool(flags.SubstituteEnv)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := flagSet.GetBool(flags.Template)
+	if err != nil {
+		return nil, err
+	}
+	return NewRenderer(env, templates), nil
+}
+
+// render renders an input, unless the renderer is nil.
+func render(input []byte, renderer Renderer) ([]byte, error) {
+	if renderer == nil {
+		return input, nil
+	}
+	return renderer(input)
+}
+
+var envRe = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// SubstituteEnv substitutes the ${VAR} references of an input with the values
+// of the environment variables. The references to undefined variables are an
+// error, and $$ is substituted with $ so that ${VAR} can be escaped as
+// $${VAR}. The other uses of $, like $VAR, are left as is.
+func SubstituteEnv(input []byte) ([]byte, error) {
+	var undefined []string
+	output := envRe.ReplaceAllFunc(input, func(match []byte) []byte {
+		if string(match) == "$$" {
+			return []byte("$")
+		}
+		name := string(match[2 : len(match)-1])
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			undefined = append(undefined, name)
+		}
+		return []byte(value)
+	})
+	if len(undefined) > 0 {
+		return nil, fmt.Errorf("undefined environment variables: %s", strings.Join(undefined, ", "))
+	}
+	return output, nil
+}
+
+// templateFuncs are the functions of the templated inputs, besides the
+// predefined functions of text/template.
+var templateFuncs = template.FuncMap{
+	// env returns the value of an environment variable, or an empty string
+	// if it is undefined.
+	"env": os.Getenv,
+
+	// required returns the value of an environment variable, or fails if it
+	// is undefined.
+	"required": func(name string) (string, error) {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is required", name)
+		}
+		return value, nil
+	},
+
+	// default returns the value, or the default value if it is empty.
+	"default": func(def, value string) string {
+		if value == "" {
+			return def
+		}
+		return value
+	},
+
+	// quote quotes a value, so that it is a valid YAML or JSON string.
+	"quote": strconv.Quote,
+
+	"lower":     strings.ToLower,
+	"upper":     strings.ToUpper,
+	"trimSpace": strings.TrimSpace,
+	"split":     strings.Split,
+	"join": func(sep string, elems []string) string {
+		return strings.Join(elems, sep)
+	},
+}
+
+// ExecuteTemplate executes an input as a Go template. The environment
+// variables are available as .Env, and the references to the undefined ones
+// are an error.
+func ExecuteTemplate(input []byte) ([]byte, error) {
+	tmpl, err := template.New("input").Option("missingkey=error").Funcs(templateFuncs).Parse(string(input))
+	if err != nil {
+		return nil, err
+	}
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if i := strings.IndexByte(kv, '='); i > 0 {
+			env[kv[:i]] = kv[i+1:]
+		}
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, map[string]interface{}{"Env": env}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
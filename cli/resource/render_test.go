@@ -0,0 +1,132 @@
+package resource
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubstituteEnv(t *testing.T) {
+	t.Setenv("SENSU_TEST_ENV", "production")
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "reference",
+			input: "namespace: ${SENSU_TEST_ENV}",
+			want:  "namespace: production",
+		},
+		{
+			name:  "escaped reference",
+			input: "command: echo $${SENSU_TEST_ENV}",
+			want:  "command: echo ${SENSU_TEST_ENV}",
+		},
+		{
+			name:  "unbraced reference",
+			input: "command: echo $SENSU_TEST_ENV $1",
+			want:  "command: echo $SENSU_TEST_ENV $1",
+		},
+		{
+			name:    "undefined variable",
+			input:   "namespace: ${SENSU_TEST_UNDEFINED}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SubstituteEnv([]byte(tt.input))
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "SENSU_TEST_UNDEFINED")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestExecuteTemplate(t *testing.T) {
+	t.Setenv("SENSU_TEST_ENV", "Production")
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "env",
+			input: `namespace: {{ .Env.SENSU_TEST_ENV | lower }}`,
+			want:  "namespace: production",
+		},
+		{
+			name:  "required",
+			input: `namespace: {{ required "SENSU_TEST_ENV" | quote }}`,
+			want:  `namespace: "Production"`,
+		},
+		{
+			name:    "required undefined variable",
+			input:   `namespace: {{ required "SENSU_TEST_UNDEFINED" }}`,
+			wantErr: true,
+		},
+		{
+			name:  "default",
+			input: `interval: {{ env "SENSU_TEST_UNDEFINED" | default "60" }}`,
+			want:  "interval: 60",
+		},
+		{
+			name:    "missing key",
+			input:   `namespace: {{ .Env.SENSU_TEST_UNDEFINED }}`,
+			wantErr: true,
+		},
+		{
+			name:  "split and join",
+			input: `subscriptions: [{{ join ", " (split "linux,windows" ",") }}]`,
+			want:  "subscriptions: [linux, windows]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExecuteTemplate([]byte(tt.input))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestNewRenderer(t *testing.T) {
+	assert.Nil(t, NewRenderer(false, false))
+
+	t.Setenv("SENSU_TEST_ENV", "production")
+	// The templates are executed before the environment is substituted
+	renderer := NewRenderer(true, true)
+	got, err := renderer([]byte(`{{ "${SENSU_TEST_ENV}" }}-{{ .Env.SENSU_TEST_ENV }}`))
+	require.NoError(t, err)
+	assert.Equal(t, "production-production", string(got))
+}
+
+func TestProcessFileRenderer(t *testing.T) {
+	t.Setenv("SENSU_TEST_ENV", "production")
+
+	fp := filepath.Join(t.TempDir(), "input")
+	input := `{"type": "CheckConfig", "spec": {"metadata": {"name": "check", "namespace": "${SENSU_TEST_ENV}"}, "command": "true", "interval": 60}}`
+	require.NoError(t, os.WriteFile(fp, []byte(input), 0644))
+
+	resources, err := ProcessFile(fp, false, NewRenderer(true, false))
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "production", resources[0].Value.(*corev2.CheckConfig).Namespace)
+}
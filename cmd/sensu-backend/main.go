@@ -24,6 +24,7 @@ func main() {
 	rootCmd.AddCommand(cmd.StartCommand(backend.Initialize))
 	rootCmd.AddCommand(cmd.VersionCommand())
 	rootCmd.AddCommand(cmd.InitCommand())
+	rootCmd.AddCommand(cmd.RotateEncryptionKeyCommand())
 
 	if err := rootCmd.Execute(); err != nil {
 		if err == seeds.ErrAlreadyInitialized {
@@ -16,6 +16,9 @@ func main() {
 	sensuCli := cli.New(rootCmd.PersistentFlags())
 
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+		if err := hooks.ContextValid(sensuCli); err != nil {
+			return err
+		}
 		return hooks.ConfigurationPresent(cmd, sensuCli)
 	}
 
@@ -66,6 +66,10 @@ const (
 	// status used when golang is unable to determine the exit
 	// status.
 	FallbackExitStatus	int	= 3
+
+	// TruncatedOutputMarker follows the command execution output
+	// when it was truncated.
+	TruncatedOutputMarker	string	= "\n[output truncated]\n"
 )
 
 // ExecutionRequest provides information about a system command execution,
@@ -94,6 +98,11 @@ type ExecutionRequest struct {
 
 	// InProgressMu is the mutex for the InProgress map.
 	InProgressMu	*sync.Mutex
+
+	// MaxOutputSize is the maximum number of bytes of output captured, or 0
+	// for no limit. The rest of the output is discarded, and the captured
+	// output is followed by TruncatedOutputMarker.
+	MaxOutputSize	int
 }
 
 // ExecutionResponse provides the response information of an ExecutionRequest.
@@ -104,6 +113,10 @@ type ExecutionResponse struct {
 	// Command execution exit status.
 	Status	int
 
+	// Truncated is true if the output exceeded the MaxOutputSize of the
+	// request and was truncated.
+	Truncated	bool
+
 	// Duration provides command execution time in seconds.
 	Duration	float64
 }
@@ -147,7 +160,7 @@ func (e *ExecutionRequest) Execute(ctx context.Context, execution ExecutionReque
 
 	// Share an output buffer between STDOUT/ERR, following the
 	// Nagios plugin spec.
-	var output bytesutil.SyncBuffer
+	output := bytesutil.SyncBuffer{Limit: execution.MaxOutputSize}
 
 	cmd.Stdout = &output
 	cmd.Stderr = &output
@@ -160,6 +173,10 @@ func (e *ExecutionRequest) Execute(ctx context.Context, execution ExecutionReque
 	started := time.Now()
 	defer func() {
 		resp.Duration = time.Since(started).Seconds()
+		if output.Truncated() {
+			resp.Truncated = true
+			resp.Output += TruncatedOutputMarker
+		}
 	}()
 
 	timer := time.NewTimer(math.MaxInt64)
@@ -92,3 +92,21 @@ func TestExecute(t *testing.T) {
 	assert.Equal(t, 2, sleepExec.Status)
 	assert.NotEqual(t, 0, sleepExec.Duration)
 }
+
+func TestExecuteMaxOutputSize(t *testing.T) {
+	cat := FakeCommand("cat")
+	cat.Input = "0123456789"
+	cat.MaxOutputSize = 4
+
+	catExec, catErr := cat.Execute(context.Background(), cat)
+	assert.Equal(t, nil, catErr)
+	assert.Equal(t, "0123"+TruncatedOutputMarker, catExec.Output)
+	assert.True(t, catExec.Truncated)
+	assert.Equal(t, 0, catExec.Status)
+
+	cat.MaxOutputSize = 64
+	catExec, catErr = cat.Execute(context.Background(), cat)
+	assert.Equal(t, nil, catErr)
+	assert.Equal(t, "0123456789", catExec.Output)
+	assert.False(t, catExec.Truncated)
+}
@@ -56,6 +56,13 @@ func TestExerciseService(t *testing.T) {
 		},
 		"order": 66,
 	})
+
+	// mutations are rejected by read-only queries, unlike the other operations
+	res = svc.Do(ctx, graphql.QueryParams{Query: `query { order }`, ReadOnly: true})
+	require.Empty(t, res.Errors)
+	res = svc.Do(ctx, graphql.QueryParams{Query: `mutation { order }`, ReadOnly: true})
+	require.Len(t, res.Errors, 1)
+	assert.Equal(t, graphql.ErrReadOnly.Error(), res.Errors[0].Message)
 }
 
 type queryExtResolver struct{}
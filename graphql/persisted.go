@@ -0,0 +1,150 @@
+package graphql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// MaxPersistedQueries is the maximum number of queries that the clients can
+// persist, in addition to the allow-listed queries.
+const MaxPersistedQueries = 1000
+
+var (
+	// ErrPersistedQueryNotFound is returned when the hash of a query is not
+	// known. Its message is the one expected by the clients of automatic
+	// persisted queries, which then send the hash along with the query.
+	ErrPersistedQueryNotFound = errors.New("PersistedQueryNotFound")
+
+	// ErrPersistedQueryNotAllowed is returned for the queries that are not
+	// allow-listed, when only persisted queries are allowed.
+	ErrPersistedQueryNotAllowed = errors.New("only persisted queries are allowed")
+
+	// ErrPersistedQueryHashMismatch is returned when the hash sent along with a
+	// query is not the hash of the query.
+	ErrPersistedQueryHashMismatch = errors.New("provided sha256Hash does not match query")
+)
+
+// PersistedQueries is a store of queries identified by the SHA-256 hash of
+// their text, which the clients can send instead of the queries. The queries
+// are parsed once, when they are persisted.
+//
+// The store is initialized with an allow-list of queries. When it is enforced,
+// the clients can only execute the allow-listed queries. Otherwise, the clients
+// can persist their own queries by sending them along with their hash.
+type PersistedQueries struct {
+	enforce bool
+
+	mu         sync.RWMutex
+	documents  map[string]*ast.Document
+	registered int
+}
+
+// NewPersistedQueries returns a store of persisted queries, given the
+// allow-listed queries keyed by their SHA-256 hash.
+func NewPersistedQueries(queries map[string]string, enforce bool) (*PersistedQueries, error) {
+	q := &PersistedQueries{
+		enforce:   enforce,
+		documents: make(map[string]*ast.Document, len(queries)),
+	}
+	for hash, query := range queries {
+		if PersistedQueryHash(query) != hash {
+			return nil, fmt.Errorf("persisted query %s: %w", hash, ErrPersistedQueryHashMismatch)
+		}
+		doc, err := parsePersistedQuery(query)
+		if err != nil {
+			return nil, fmt.Errorf("persisted query %s: %w", hash, err)
+		}
+		q.documents[hash] = doc
+	}
+	return q, nil
+}
+
+// LoadPersistedQueries returns a store of persisted queries, given the path to
+// a JSON object of the allow-listed queries keyed by their SHA-256 hash. The
+// store is empty when the path is empty.
+func LoadPersistedQueries(path string, enforce bool) (*PersistedQueries, error) {
+	queries := map[string]string{}
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &queries); err != nil {
+			return nil, fmt.Errorf("invalid persisted queries file %s: %w", path, err)
+		}
+	}
+	return NewPersistedQueries(queries, enforce)
+}
+
+// PersistedQueryHash returns the hex-encoded SHA-256 hash of a query.
+func PersistedQueryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// Enforced returns true if only the allow-listed queries are allowed.
+func (q *PersistedQueries) Enforced() bool {
+	return q != nil && q.enforce
+}
+
+// Document returns the parsed document of a query, given its hash, its text or
+// both. It returns nil when the query is not persisted and must be parsed by
+// the caller.
+func (q *PersistedQueries) Document(hash, query string) (*ast.Document, error) {
+	if hash == "" {
+		if !q.Enforced() {
+			return nil, nil
+		}
+		// Allow-listed queries can still be sent in full
+		hash = PersistedQueryHash(query)
+	} else if query != "" && PersistedQueryHash(query) != hash {
+		return nil, ErrPersistedQueryHashMismatch
+	}
+
+	if q != nil {
+		q.mu.RLock()
+		doc, ok := q.documents[hash]
+		q.mu.RUnlock()
+		if ok {
+			return doc, nil
+		}
+	}
+	if q.Enforced() {
+		return nil, ErrPersistedQueryNotAllowed
+	}
+	if query == "" {
+		return nil, ErrPersistedQueryNotFound
+	}
+	if q == nil {
+		return nil, nil
+	}
+
+	doc, err := parsePersistedQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.documents[hash]; !ok && q.registered < MaxPersistedQueries {
+		q.documents[hash] = doc
+		q.registered++
+	}
+	return doc, nil
+}
+
+func parsePersistedQuery(query string) (*ast.Document, error) {
+	src := source.NewSource(&source.Source{
+		Body: []byte(query),
+		Name: "GraphQL request",
+	})
+	return parser.Parse(parser.ParseParams{Source: src})
+}
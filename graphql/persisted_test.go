@@ -0,0 +1,91 @@
+package graphql
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistedQueriesDocument(t *testing.T) {
+	allowed := "{ allowed }"
+	adhoc := "{ adhoc }"
+	allowedHash := PersistedQueryHash(allowed)
+	adhocHash := PersistedQueryHash(adhoc)
+
+	testCases := []struct {
+		desc    string
+		enforce bool
+		nilMap  bool
+		hash    string
+		query   string
+		wantDoc bool
+		wantErr error
+	}{
+		{desc: "ad-hoc query", query: adhoc},
+		{desc: "allow-listed hash", hash: allowedHash, wantDoc: true},
+		{desc: "unknown hash", hash: adhocHash, wantErr: ErrPersistedQueryNotFound},
+		{desc: "hash mismatch", hash: allowedHash, query: adhoc, wantErr: ErrPersistedQueryHashMismatch},
+		{desc: "new persisted query", hash: adhocHash, query: adhoc, wantDoc: true},
+		{desc: "enforced ad-hoc query", enforce: true, query: adhoc, wantErr: ErrPersistedQueryNotAllowed},
+		{desc: "enforced new persisted query", enforce: true, hash: adhocHash, query: adhoc, wantErr: ErrPersistedQueryNotAllowed},
+		{desc: "enforced allow-listed query", enforce: true, query: allowed, wantDoc: true},
+		{desc: "enforced allow-listed hash", enforce: true, hash: allowedHash, wantDoc: true},
+		{desc: "no store", nilMap: true, query: adhoc},
+		{desc: "no store with hash", nilMap: true, hash: adhocHash, wantErr: ErrPersistedQueryNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var queries *PersistedQueries
+			if !tc.nilMap {
+				var err error
+				queries, err = NewPersistedQueries(map[string]string{allowedHash: allowed}, tc.enforce)
+				require.NoError(t, err)
+			}
+			doc, err := queries.Document(tc.hash, tc.query)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantDoc, doc != nil)
+		})
+	}
+}
+
+func TestPersistedQueriesRegistered(t *testing.T) {
+	queries, err := NewPersistedQueries(nil, false)
+	require.NoError(t, err)
+
+	query := "{ adhoc }"
+	hash := PersistedQueryHash(query)
+	doc, err := queries.Document(hash, query)
+	require.NoError(t, err)
+
+	persisted, err := queries.Document(hash, "")
+	require.NoError(t, err)
+	assert.Same(t, doc, persisted)
+}
+
+func TestLoadPersistedQueries(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.json")
+	query := "{ allowed }"
+	require.NoError(t, os.WriteFile(valid, []byte(`{"`+PersistedQueryHash(query)+`": "`+query+`"}`), 0600))
+	queries, err := LoadPersistedQueries(valid, true)
+	require.NoError(t, err)
+	assert.True(t, queries.Enforced())
+	_, err = queries.Document(PersistedQueryHash(query), "")
+	assert.NoError(t, err)
+
+	mismatch := filepath.Join(dir, "mismatch.json")
+	require.NoError(t, os.WriteFile(mismatch, []byte(`{"abc": "`+query+`"}`), 0600))
+	_, err = LoadPersistedQueries(mismatch, true)
+	assert.ErrorIs(t, err, ErrPersistedQueryHashMismatch)
+
+	_, err = LoadPersistedQueries(filepath.Join(dir, "missing.json"), true)
+	assert.Error(t, err)
+
+	queries, err = LoadPersistedQueries("", false)
+	require.NoError(t, err)
+	assert.False(t, queries.Enforced())
+}
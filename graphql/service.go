@@ -2,10 +2,12 @@ package graphql
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
 	"github.com/graphql-go/graphql/language/parser"
 	"github.com/graphql-go/graphql/language/source"
 )
@@ -16,6 +18,14 @@ type Service struct {
 	// the default executor is used.
 	Executor func(p graphql.ExecuteParams) *graphql.Result
 
+	// PersistedQueries are the queries that the clients can execute by hash.
+	// Only the hashes of the queries sent in full are checked when nil.
+	PersistedQueries *PersistedQueries
+
+	// QueryContext, when set, returns the context in which the requests
+	// without mutations are executed.
+	QueryContext func(context.Context) context.Context
+
 	schema graphql.Schema
 	types  *typeRegister
 	mware  []Middleware
@@ -83,6 +93,9 @@ func (service *Service) RegisterObject(t ObjectDesc, impl interface{}) {
 		for fieldName, handler := range t.FieldHandlers {
 			fields[fieldName].Resolve = handler(impl)
 		}
+		if service.types.isSubscriptionRoot(cfg.Name) {
+			subscriptionFields(fields)
+		}
 
 		cfg.IsTypeOf = nil
 		if typeResolver, ok := impl.(isTypeOfResolver); ok {
@@ -157,14 +170,21 @@ func (service *Service) Middleware() []Middleware {
 	return service.mware
 }
 
-// QueryParams describe parameters of a GraphQL query.
+// ErrReadOnly is returned when a read-only query contains mutations.
+var ErrReadOnly = errors.New("mutations are not allowed while the API is read-only for maintenance")
+
+// QueryParams describe parameters of a GraphQL query. The mutations of
+// ReadOnly queries are rejected. The Query can be omitted when the
+// PersistedQueryHash of a persisted query is given.
 type QueryParams struct {
-	IsAuthed       bool
-	OperationName  string
-	Query          string
-	RootObject     map[string]interface{}
-	SkipValidation bool
-	Variables      map[string]interface{}
+	IsAuthed           bool
+	OperationName      string
+	PersistedQueryHash string
+	Query              string
+	ReadOnly           bool
+	RootObject         map[string]interface{}
+	SkipValidation     bool
+	Variables          map[string]interface{}
 }
 
 // Do executes given query.
@@ -184,16 +204,25 @@ func (service *Service) Do(ctx context.Context, p QueryParams) *Result {
 
 	// parse the source
 	parseFinishFn := MiddlewareHandleParseDidStart(service, &params)
-	source := source.NewSource(&source.Source{
-		Body: []byte(p.Query),
-		Name: "GraphQL request",
-	})
-	AST, err := parser.Parse(parser.ParseParams{Source: source})
+	AST, err := service.parse(p, "GraphQL request")
 	parseFinishFn(err)
 	if err != nil {
 		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
 	}
 
+	hasMutation := false
+	for _, def := range AST.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok && op.Operation == ast.OperationTypeMutation {
+			hasMutation = true
+		}
+	}
+	if hasMutation && p.ReadOnly {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(ErrReadOnly)}
+	}
+	if !hasMutation && service.QueryContext != nil {
+		params.Context = service.QueryContext(params.Context)
+	}
+
 	// run mandatory (un-skippable) validators
 	rules := MandatoryValidators()
 	validationResult := graphql.ValidateDocument(&schema, AST, rules)
@@ -220,6 +249,109 @@ func (service *Service) Do(ctx context.Context, p QueryParams) *Result {
 	})
 }
 
+// Subscribe executes the subscription given by the query. The results of the
+// subscription are sent on the returned channel, which is closed when the
+// subscription ends or the context is done. Queries and mutations are executed
+// as by Do, and their result is sent on the channel.
+func (service *Service) Subscribe(ctx context.Context, p QueryParams) chan *Result {
+	schema := service.schema
+
+	// parse the source
+	AST, err := service.parse(p, "GraphQL subscription")
+	if err != nil {
+		return sendResult(&graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+	}
+
+	op, err := findOperation(AST, p.OperationName)
+	if err != nil {
+		return sendResult(&graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+	}
+	if op.Operation != ast.OperationTypeSubscription {
+		return sendResult(service.Do(ctx, p))
+	}
+	if len(op.SelectionSet.Selections) != 1 {
+		err := errors.New("subscriptions must select exactly one top level field")
+		return sendResult(&graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+	}
+
+	// run the mandatory validators, then the built-in validators
+	validationResult := graphql.ValidateDocument(&schema, AST, MandatoryValidators())
+	if !validationResult.IsValid {
+		return sendResult(&graphql.Result{Errors: validationResult.Errors})
+	}
+	validationResult = graphql.ValidateDocument(&schema, AST, nil)
+	if !validationResult.IsValid {
+		return sendResult(&graphql.Result{Errors: validationResult.Errors})
+	}
+
+	return graphql.ExecuteSubscription(graphql.ExecuteParams{
+		Schema:        schema,
+		Root:          p.RootObject,
+		AST:           AST,
+		OperationName: p.OperationName,
+		Args:          p.Variables,
+		Context:       ctx,
+	})
+}
+
+// parse returns the document of the query, which is only parsed if it was not
+// persisted.
+func (service *Service) parse(p QueryParams, name string) (*ast.Document, error) {
+	doc, err := service.PersistedQueries.Document(p.PersistedQueryHash, p.Query)
+	if doc != nil || err != nil {
+		return doc, err
+	}
+	source := source.NewSource(&source.Source{
+		Body: []byte(p.Query),
+		Name: name,
+	})
+	return parser.Parse(parser.ParseParams{Source: source})
+}
+
+// findOperation returns the operation of the document with the given name, or
+// its only operation if the name is empty.
+func findOperation(doc *ast.Document, name string) (*ast.OperationDefinition, error) {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil, errors.New("must provide operation name if query contains multiple operations")
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == name {
+			return op, nil
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("unknown operation named %q", name)
+	}
+	return found, nil
+}
+
+func sendResult(result *Result) chan *Result {
+	results := make(chan *Result, 1)
+	results <- result
+	close(results)
+	return results
+}
+
+// subscriptionFields configures the fields of the root type of the
+// subscriptions. Their resolvers subscribe to the source streams of the
+// subscriptions, by returning a channel of the values to send, and each of
+// these values is then resolved as the value of the field.
+func subscriptionFields(fields graphql.Fields) {
+	for _, field := range fields {
+		field.Subscribe = field.Resolve
+		field.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source, nil
+		}
+	}
+}
+
 type typeRegister struct {
 	types      map[Kind]map[string]registerTypeFn
 	extensions map[string][]interface{}
@@ -266,6 +398,16 @@ func (r *typeRegister) setSchema(desc SchemaDesc) {
 	r.schema = desc
 }
 
+// isSubscriptionRoot returns true if the type is the root type of the
+// subscriptions of the schema.
+func (r *typeRegister) isSubscriptionRoot(name string) bool {
+	if r.schema.Config == nil {
+		return false
+	}
+	root := r.schema.Config().Subscription
+	return root != nil && root.Name() == name
+}
+
 func newSchema(reg *typeRegister, mware []Middleware) (graphql.Schema, error) {
 	typeMap := make(graphql.TypeMap, len(reg.types))
 
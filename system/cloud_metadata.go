@@ -0,0 +1,203 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	// CloudProviderEC2 is the cloud provider of the EC2 instances.
+	CloudProviderEC2 = "EC2"
+
+	// CloudProviderGCP is the cloud provider of the GCE instances.
+	CloudProviderGCP = "GCP"
+
+	// CloudProviderAzure is the cloud provider of the Azure virtual machines.
+	CloudProviderAzure = "Azure"
+)
+
+var (
+	// The endpoints of the instance metadata services, which are variables so
+	// that they can be served by the tests.
+	ec2MetadataEndpoint   = "http://169.254.169.254"
+	gcpMetadataEndpoint   = "http://metadata.google.internal"
+	azureMetadataEndpoint = "http://169.254.169.254"
+)
+
+// CloudMetadata describes the cloud instance the local system runs on.
+type CloudMetadata struct {
+	Provider     string
+	Region       string
+	Zone         string
+	InstanceID   string
+	InstanceType string
+	Image        string
+}
+
+// GetCloudMetadata queries the instance metadata service of the cloud provider
+// the local system runs on. The provider is detected with GetCloudProvider if
+// it is empty.
+func GetCloudMetadata(ctx context.Context, provider string) (CloudMetadata, error) {
+	if provider == "" {
+		provider = GetCloudProvider(ctx)
+	}
+	switch provider {
+	case CloudProviderEC2:
+		return getEC2Metadata(ctx)
+	case CloudProviderGCP:
+		return getGCPMetadata(ctx)
+	case CloudProviderAzure:
+		return getAzureMetadata(ctx)
+	case "":
+		return CloudMetadata{}, fmt.Errorf("no cloud provider detected")
+	default:
+		return CloudMetadata{}, fmt.Errorf("unsupported cloud provider: %s", provider)
+	}
+}
+
+type ec2IdentityDocument struct {
+	AvailabilityZone string `json:"availabilityZone"`
+	ImageID          string `json:"imageId"`
+	InstanceID       string `json:"instanceId"`
+	InstanceType     string `json:"instanceType"`
+	Region           string `json:"region"`
+}
+
+func getEC2Metadata(ctx context.Context) (CloudMetadata, error) {
+	// IMDSv2 requires a session token, but IMDSv1 does not, so the instance
+	// identity document is requested without a token if there is none
+	header := http.Header{}
+	token, err := getMetadata(ctx, http.MethodPut, ec2MetadataEndpoint+"/latest/api/token", http.Header{
+		"X-Aws-Ec2-Metadata-Token-Ttl-Seconds": []string{"60"},
+	})
+	if err != nil {
+		logger.WithError(err).Debug("couldn't get an IMDSv2 token")
+	} else {
+		header.Set("X-Aws-Ec2-Metadata-Token", string(token))
+	}
+
+	b, err := getMetadata(ctx, http.MethodGet, ec2MetadataEndpoint+"/latest/dynamic/instance-identity/document", header)
+	if err != nil {
+		return CloudMetadata{}, err
+	}
+	var doc ec2IdentityDocument
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return CloudMetadata{}, fmt.Errorf("invalid EC2 instance identity document: %s", err)
+	}
+	return CloudMetadata{
+		Provider:     CloudProviderEC2,
+		Region:       doc.Region,
+		Zone:         doc.AvailabilityZone,
+		InstanceID:   doc.InstanceID,
+		InstanceType: doc.InstanceType,
+		Image:        doc.ImageID,
+	}, nil
+}
+
+type gcpInstance struct {
+	ID          json.Number `json:"id"`
+	Image       string      `json:"image"`
+	MachineType string      `json:"machineType"`
+	Zone        string      `json:"zone"`
+}
+
+func getGCPMetadata(ctx context.Context) (CloudMetadata, error) {
+	b, err := getMetadata(ctx, http.MethodGet, gcpMetadataEndpoint+"/computeMetadata/v1/instance/?recursive=true", http.Header{
+		"Metadata-Flavor": []string{"Google"},
+	})
+	if err != nil {
+		return CloudMetadata{}, err
+	}
+	var instance gcpInstance
+	if err := json.Unmarshal(b, &instance); err != nil {
+		return CloudMetadata{}, fmt.Errorf("invalid GCE instance metadata: %s", err)
+	}
+
+	// The zone and the machine type are the last segment of their resource
+	// name, e.g. projects/123/zones/us-central1-a, and the region is the zone
+	// without its suffix
+	zone := lastSegment(instance.Zone)
+	region := zone
+	if i := strings.LastIndexByte(zone, '-'); i > 0 {
+		region = zone[:i]
+	}
+	return CloudMetadata{
+		Provider:     CloudProviderGCP,
+		Region:       region,
+		Zone:         zone,
+		InstanceID:   instance.ID.String(),
+		InstanceType: lastSegment(instance.MachineType),
+		Image:        instance.Image,
+	}, nil
+}
+
+type azureCompute struct {
+	Location       string `json:"location"`
+	VMID           string `json:"vmId"`
+	VMSize         string `json:"vmSize"`
+	Zone           string `json:"zone"`
+	StorageProfile struct {
+		ImageReference struct {
+			ID        string `json:"id"`
+			Offer     string `json:"offer"`
+			Publisher string `json:"publisher"`
+			SKU       string `json:"sku"`
+			Version   string `json:"version"`
+		} `json:"imageReference"`
+	} `json:"storageProfile"`
+}
+
+func getAzureMetadata(ctx context.Context) (CloudMetadata, error) {
+	b, err := getMetadata(ctx, http.MethodGet, azureMetadataEndpoint+"/metadata/instance/compute?api-version=2021-02-01", http.Header{
+		"Metadata": []string{"true"},
+	})
+	if err != nil {
+		return CloudMetadata{}, err
+	}
+	var compute azureCompute
+	if err := json.Unmarshal(b, &compute); err != nil {
+		return CloudMetadata{}, fmt.Errorf("invalid Azure instance metadata: %s", err)
+	}
+
+	// Marketplace images are identified by their URN, and custom images by
+	// their resource ID
+	ref := compute.StorageProfile.ImageReference
+	image := ref.ID
+	if ref.Publisher != "" {
+		image = strings.Join([]string{ref.Publisher, ref.Offer, ref.SKU, ref.Version}, ":")
+	}
+	return CloudMetadata{
+		Provider:     CloudProviderAzure,
+		Region:       compute.Location,
+		Zone:         compute.Zone,
+		InstanceID:   compute.VMID,
+		InstanceType: compute.VMSize,
+		Image:        image,
+	}, nil
+}
+
+func getMetadata(ctx context.Context, method, url string, header http.Header) ([]byte, error) {
+	logger.Debug(method, " ", url)
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s", method, url, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+func lastSegment(name string) string {
+	return name[strings.LastIndexByte(name, '/')+1:]
+}
@@ -0,0 +1,114 @@
+package system
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveMetadata(t *testing.T, endpoint *string, handler http.HandlerFunc) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	original := *endpoint
+	*endpoint = server.URL
+	t.Cleanup(func() {
+		*endpoint = original
+		server.Close()
+	})
+}
+
+func TestGetCloudMetadataEC2(t *testing.T) {
+	serveMetadata(t, &ec2MetadataEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest/api/token":
+			assert.Equal(t, http.MethodPut, r.Method)
+			_, _ = w.Write([]byte("token"))
+		case "/latest/dynamic/instance-identity/document":
+			assert.Equal(t, "token", r.Header.Get("X-Aws-Ec2-Metadata-Token"))
+			_, _ = w.Write([]byte(`{"availabilityZone":"us-west-2b","imageId":"ami-5fb8c835","instanceId":"i-1234567890abcdef0","instanceType":"t2.micro","region":"us-west-2"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	metadata, err := GetCloudMetadata(context.Background(), CloudProviderEC2)
+	require.NoError(t, err)
+	assert.Equal(t, CloudMetadata{
+		Provider:     CloudProviderEC2,
+		Region:       "us-west-2",
+		Zone:         "us-west-2b",
+		InstanceID:   "i-1234567890abcdef0",
+		InstanceType: "t2.micro",
+		Image:        "ami-5fb8c835",
+	}, metadata)
+}
+
+func TestGetCloudMetadataEC2WithoutToken(t *testing.T) {
+	serveMetadata(t, &ec2MetadataEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/latest/dynamic/instance-identity/document" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		assert.Empty(t, r.Header.Get("X-Aws-Ec2-Metadata-Token"))
+		_, _ = w.Write([]byte(`{"instanceId":"i-1234567890abcdef0","region":"us-west-2"}`))
+	})
+
+	metadata, err := GetCloudMetadata(context.Background(), CloudProviderEC2)
+	require.NoError(t, err)
+	assert.Equal(t, "i-1234567890abcdef0", metadata.InstanceID)
+	assert.Equal(t, "us-west-2", metadata.Region)
+}
+
+func TestGetCloudMetadataGCP(t *testing.T) {
+	serveMetadata(t, &gcpMetadataEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+		assert.Equal(t, "/computeMetadata/v1/instance/", r.URL.Path)
+		_, _ = w.Write([]byte(`{"id":4520031799277581759,"image":"projects/debian-cloud/global/images/debian-11-bullseye-v20220822","machineType":"projects/123456789/machineTypes/e2-medium","zone":"projects/123456789/zones/us-central1-a"}`))
+	})
+
+	metadata, err := GetCloudMetadata(context.Background(), CloudProviderGCP)
+	require.NoError(t, err)
+	assert.Equal(t, CloudMetadata{
+		Provider:     CloudProviderGCP,
+		Region:       "us-central1",
+		Zone:         "us-central1-a",
+		InstanceID:   "4520031799277581759",
+		InstanceType: "e2-medium",
+		Image:        "projects/debian-cloud/global/images/debian-11-bullseye-v20220822",
+	}, metadata)
+}
+
+func TestGetCloudMetadataAzure(t *testing.T) {
+	serveMetadata(t, &azureMetadataEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.Header.Get("Metadata"))
+		assert.Equal(t, "/metadata/instance/compute", r.URL.Path)
+		_, _ = w.Write([]byte(`{"location":"westeurope","vmId":"02aab8a4-74ef-476e-8182-f6d2ba4166a6","vmSize":"Standard_A3","zone":"1","storageProfile":{"imageReference":{"offer":"UbuntuServer","publisher":"Canonical","sku":"16.04.0-LTS","version":"latest"}}}`))
+	})
+
+	metadata, err := GetCloudMetadata(context.Background(), CloudProviderAzure)
+	require.NoError(t, err)
+	assert.Equal(t, CloudMetadata{
+		Provider:     CloudProviderAzure,
+		Region:       "westeurope",
+		Zone:         "1",
+		InstanceID:   "02aab8a4-74ef-476e-8182-f6d2ba4166a6",
+		InstanceType: "Standard_A3",
+		Image:        "Canonical:UbuntuServer:16.04.0-LTS:latest",
+	}, metadata)
+}
+
+func TestGetCloudMetadataError(t *testing.T) {
+	serveMetadata(t, &azureMetadataEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := GetCloudMetadata(context.Background(), CloudProviderAzure)
+	assert.Error(t, err)
+
+	_, err = GetCloudMetadata(context.Background(), "DigitalOcean")
+	assert.Error(t, err)
+}
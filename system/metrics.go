@@ -0,0 +1,146 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"runtime"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// MetricsAnnotation is the annotation of the entity states holding the system
+// metrics sent by their agent along with its keepalives.
+const MetricsAnnotation = "sensu.io/system-metrics"
+
+// The names of the metric points of the system metrics.
+const (
+	load1MetricName             = "system_load1"
+	load5MetricName             = "system_load5"
+	load15MetricName            = "system_load15"
+	memoryUsedPercentMetricName = "system_memory_used_percent"
+	diskUsedPercentMetricName   = "system_disk_used_percent"
+)
+
+// Metrics is a compact snapshot of the health of the local system.
+type Metrics struct {
+	Timestamp         int64   `json:"timestamp"`
+	Load1             float64 `json:"load1"`
+	Load5             float64 `json:"load5"`
+	Load15            float64 `json:"load15"`
+	MemoryUsedPercent float64 `json:"memory_used_percent"`
+	DiskUsedPercent   float64 `json:"disk_used_percent"`
+}
+
+// GetMetrics returns the load averages, the memory usage and the usage of the
+// disk of the root filesystem of the local system. The load averages are zero
+// on Windows, which has none.
+func GetMetrics(ctx context.Context, timestamp int64) (Metrics, error) {
+	metrics := Metrics{Timestamp: timestamp}
+	if runtime.GOOS != "windows" {
+		avg, err := load.AvgWithContext(ctx)
+		if err != nil {
+			return metrics, err
+		}
+		metrics.Load1, metrics.Load5, metrics.Load15 = avg.Load1, avg.Load5, avg.Load15
+	}
+
+	vm, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return metrics, err
+	}
+	metrics.MemoryUsedPercent = vm.UsedPercent
+
+	usage, err := disk.UsageWithContext(ctx, rootPath())
+	if err != nil {
+		return metrics, err
+	}
+	metrics.DiskUsedPercent = usage.UsedPercent
+
+	return metrics, nil
+}
+
+func rootPath() string {
+	if runtime.GOOS == "windows" {
+		if drive := os.Getenv("SystemDrive"); drive != "" {
+			return drive + `\`
+		}
+		return `C:\`
+	}
+	return "/"
+}
+
+// Points returns the metric points of the system metrics.
+func (m Metrics) Points() []*corev2.MetricPoint {
+	values := []struct {
+		name  string
+		value float64
+	}{
+		{load1MetricName, m.Load1},
+		{load5MetricName, m.Load5},
+		{load15MetricName, m.Load15},
+		{memoryUsedPercentMetricName, m.MemoryUsedPercent},
+		{diskUsedPercentMetricName, m.DiskUsedPercent},
+	}
+	points := make([]*corev2.MetricPoint, 0, len(values))
+	for _, v := range values {
+		points = append(points, &corev2.MetricPoint{
+			Name:      v.name,
+			Value:     v.value,
+			Timestamp: m.Timestamp,
+			Tags:      []*corev2.MetricTag{},
+		})
+	}
+	return points
+}
+
+// MetricsFromPoints returns the system metrics of the given metric points, and
+// false if none of them is a system metric.
+func MetricsFromPoints(points []*corev2.MetricPoint) (Metrics, bool) {
+	var metrics Metrics
+	found := false
+	for _, point := range points {
+		var value *float64
+		switch point.Name {
+		case load1MetricName:
+			value = &metrics.Load1
+		case load5MetricName:
+			value = &metrics.Load5
+		case load15MetricName:
+			value = &metrics.Load15
+		case memoryUsedPercentMetricName:
+			value = &metrics.MemoryUsedPercent
+		case diskUsedPercentMetricName:
+			value = &metrics.DiskUsedPercent
+		default:
+			continue
+		}
+		*value = point.Value
+		metrics.Timestamp = point.Timestamp
+		found = true
+	}
+	return metrics, found
+}
+
+// ParseMetricsAnnotation returns the system metrics held by the annotations of
+// an entity state, if any.
+func ParseMetricsAnnotation(annotations map[string]string) (*Metrics, error) {
+	value, ok := annotations[MetricsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var metrics Metrics
+	if err := json.Unmarshal([]byte(value), &metrics); err != nil {
+		return nil, err
+	}
+	return &metrics, nil
+}
+
+// Annotation returns the value of the annotation holding the system metrics.
+func (m Metrics) Annotation() string {
+	b, _ := json.Marshal(m)
+	return string(b)
+}
@@ -0,0 +1,56 @@
+package system
+
+import (
+	"context"
+	"testing"
+
+	corev2 "github.com/sensu/core/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMetrics(t *testing.T) {
+	metrics, err := GetMetrics(context.Background(), 42)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), metrics.Timestamp)
+	assert.Greater(t, metrics.MemoryUsedPercent, 0.0)
+	assert.LessOrEqual(t, metrics.MemoryUsedPercent, 100.0)
+	assert.LessOrEqual(t, metrics.DiskUsedPercent, 100.0)
+}
+
+func TestMetricsPoints(t *testing.T) {
+	metrics := Metrics{
+		Timestamp:         1667902530,
+		Load1:             0.5,
+		Load5:             0.25,
+		Load15:            0.125,
+		MemoryUsedPercent: 42,
+		DiskUsedPercent:   84,
+	}
+	points := metrics.Points()
+	require.Len(t, points, 5)
+	assert.NoError(t, (&corev2.Metrics{Points: points}).Validate())
+
+	// The other metric points are ignored
+	points = append(points, &corev2.MetricPoint{Name: "foo", Value: 1})
+	got, ok := MetricsFromPoints(points)
+	require.True(t, ok)
+	assert.Equal(t, metrics, got)
+
+	_, ok = MetricsFromPoints([]*corev2.MetricPoint{{Name: "foo", Value: 1}})
+	assert.False(t, ok)
+}
+
+func TestParseMetricsAnnotation(t *testing.T) {
+	metrics, err := ParseMetricsAnnotation(nil)
+	require.NoError(t, err)
+	assert.Nil(t, metrics)
+
+	want := Metrics{Timestamp: 1667902530, Load1: 0.5, DiskUsedPercent: 84}
+	metrics, err = ParseMetricsAnnotation(map[string]string{MetricsAnnotation: want.Annotation()})
+	require.NoError(t, err)
+	assert.Equal(t, &want, metrics)
+
+	_, err = ParseMetricsAnnotation(map[string]string{MetricsAnnotation: "{"})
+	assert.Error(t, err)
+}
@@ -48,6 +48,12 @@ func (m *MockBus) Subscribe(topic string, consumer string, subscriber messaging.
 	return args.Get(0).(messaging.Subscription), args.Error(1)
 }
 
+// SubscribePattern ...
+func (m *MockBus) SubscribePattern(pattern string, consumer string, subscriber messaging.Subscriber) (messaging.Subscription, error) {
+	args := m.Called(pattern, consumer, subscriber)
+	return args.Get(0).(messaging.Subscription), args.Error(1)
+}
+
 // Publish ...
 func (m *MockBus) Publish(topic string, message interface{}) error {
 	args := m.Called(topic, message)
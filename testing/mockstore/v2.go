@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	corev2 "github.com/sensu/core/v2"
 	corev3 "github.com/sensu/core/v3"
@@ -45,6 +46,34 @@ func (v *V2MockStore) GetSilencesStore() storev2.SilencesStore {
 	return v.Called().Get(0).(storev2.SilencesStore)
 }
 
+func (v *V2MockStore) GetHandlerResultStore() storev2.HandlerResultStore {
+	return v.Called().Get(0).(storev2.HandlerResultStore)
+}
+
+func (v *V2MockStore) GetEntityStateHistoryStore() storev2.EntityStateHistoryStore {
+	return v.Called().Get(0).(storev2.EntityStateHistoryStore)
+}
+
+func (v *V2MockStore) GetResourceHistoryStore() storev2.ResourceHistoryStore {
+	return v.Called().Get(0).(storev2.ResourceHistoryStore)
+}
+
+func (v *V2MockStore) GetRateLimitStore() storev2.RateLimitStore {
+	return v.Called().Get(0).(storev2.RateLimitStore)
+}
+
+func (v *V2MockStore) GetAuditStore() storev2.AuditStore {
+	return v.Called().Get(0).(storev2.AuditStore)
+}
+
+func (v *V2MockStore) GetSessionStore() storev2.SessionStore {
+	return v.Called().Get(0).(storev2.SessionStore)
+}
+
+func (v *V2MockStore) GetMFAStore() storev2.MFAStore {
+	return v.Called().Get(0).(storev2.MFAStore)
+}
+
 type ConfigStore struct {
 	mock.Mock
 }
@@ -53,6 +82,10 @@ func (v *ConfigStore) CreateOrUpdate(ctx context.Context, req storev2.ResourceRe
 	return v.Called(ctx, req, w).Error(0)
 }
 
+func (v *ConfigStore) BatchCreateOrUpdate(ctx context.Context, reqs []storev2.ResourceRequest, ws []storev2.Wrapper) error {
+	return v.Called(ctx, reqs, ws).Error(0)
+}
+
 func (v *ConfigStore) UpdateIfExists(ctx context.Context, req storev2.ResourceRequest, w storev2.Wrapper) error {
 	return v.Called(ctx, req, w).Error(0)
 }
@@ -87,6 +120,12 @@ func (v *ConfigStore) Exists(ctx context.Context, req storev2.ResourceRequest) (
 	return args.Get(0).(bool), args.Error(1)
 }
 
+func (v *ConfigStore) BatchExists(ctx context.Context, reqs []storev2.ResourceRequest) ([]bool, error) {
+	args := v.Called(ctx, reqs)
+	exists, _ := args.Get(0).([]bool)
+	return exists, args.Error(1)
+}
+
 func (v *ConfigStore) Patch(ctx context.Context, req storev2.ResourceRequest, patcher patch.Patcher) error {
 	return v.Called(ctx, req, patcher).Error(0)
 }
@@ -338,3 +377,118 @@ func (s *SilencesStore) GetSilencesByName(ctx context.Context, namespace string,
 func (s *SilencesStore) DeleteSilences(ctx context.Context, namespace string, names []string) error {
 	return s.Called(ctx, namespace, names).Error(0)
 }
+
+type HandlerResultStore struct {
+	mock.Mock
+}
+
+func (s *HandlerResultStore) AddHandlerResult(ctx context.Context, result *storev2.HandlerResult) error {
+	return s.Called(ctx, result).Error(0)
+}
+
+func (s *HandlerResultStore) GetHandlerResults(ctx context.Context, namespace, entity, check string) ([]*storev2.HandlerResult, error) {
+	args := s.Called(ctx, namespace, entity, check)
+	return args.Get(0).([]*storev2.HandlerResult), args.Error(1)
+}
+
+type EntityStateHistoryStore struct {
+	mock.Mock
+}
+
+func (s *EntityStateHistoryStore) AddEntityStateSnapshot(ctx context.Context, snapshot *storev2.EntityStateSnapshot) error {
+	return s.Called(ctx, snapshot).Error(0)
+}
+
+func (s *EntityStateHistoryStore) GetEntityStateHistory(ctx context.Context, namespace, entity string) ([]*storev2.EntityStateSnapshot, error) {
+	args := s.Called(ctx, namespace, entity)
+	return args.Get(0).([]*storev2.EntityStateSnapshot), args.Error(1)
+}
+
+type ResourceHistoryStore struct {
+	mock.Mock
+}
+
+func (s *ResourceHistoryStore) AddResourceVersion(ctx context.Context, version *storev2.ResourceVersion) error {
+	return s.Called(ctx, version).Error(0)
+}
+
+func (s *ResourceHistoryStore) GetResourceHistory(ctx context.Context, req storev2.ResourceRequest) ([]*storev2.ResourceVersion, error) {
+	args := s.Called(ctx, req)
+	return args.Get(0).([]*storev2.ResourceVersion), args.Error(1)
+}
+
+type RateLimitStore struct {
+	mock.Mock
+}
+
+func (s *RateLimitStore) IncrementRateLimit(ctx context.Context, key string, now time.Time, window time.Duration) (int64, error) {
+	args := s.Called(ctx, key, now, window)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type AuditStore struct {
+	mock.Mock
+}
+
+func (s *AuditStore) AddAuditEntry(ctx context.Context, entry *storev2.AuditEntry) error {
+	return s.Called(ctx, entry).Error(0)
+}
+
+type SessionStore struct {
+	mock.Mock
+}
+
+func (s *SessionStore) AddSession(ctx context.Context, session *storev2.Session) error {
+	return s.Called(ctx, session).Error(0)
+}
+
+func (s *SessionStore) RefreshSession(ctx context.Context, id string, refreshedAt, expiresAt int64) error {
+	return s.Called(ctx, id, refreshedAt, expiresAt).Error(0)
+}
+
+func (s *SessionStore) GetSessions(ctx context.Context, username string, now int64) ([]*storev2.Session, error) {
+	args := s.Called(ctx, username, now)
+	return args.Get(0).([]*storev2.Session), args.Error(1)
+}
+
+func (s *SessionStore) RevokeSessions(ctx context.Context, username string, ids ...string) (int, error) {
+	args := s.Called(ctx, username, ids)
+	return args.Int(0), args.Error(1)
+}
+
+func (s *SessionStore) IsTokenRevoked(ctx context.Context, id string) (bool, error) {
+	args := s.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+type MFAStore struct {
+	mock.Mock
+}
+
+func (s *MFAStore) PutMFAEnrollment(ctx context.Context, enrollment *storev2.MFAEnrollment, recoveryCodeHashes []string) error {
+	return s.Called(ctx, enrollment, recoveryCodeHashes).Error(0)
+}
+
+func (s *MFAStore) GetMFAEnrollment(ctx context.Context, username string) (*storev2.MFAEnrollment, error) {
+	args := s.Called(ctx, username)
+	enrollment, _ := args.Get(0).(*storev2.MFAEnrollment)
+	return enrollment, args.Error(1)
+}
+
+func (s *MFAStore) ConfirmMFAEnrollment(ctx context.Context, username string) error {
+	return s.Called(ctx, username).Error(0)
+}
+
+func (s *MFAStore) UseMFACounter(ctx context.Context, username string, counter int64) (bool, error) {
+	args := s.Called(ctx, username, counter)
+	return args.Bool(0), args.Error(1)
+}
+
+func (s *MFAStore) UseMFARecoveryCode(ctx context.Context, username, codeHash string) (bool, error) {
+	args := s.Called(ctx, username, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (s *MFAStore) DeleteMFAEnrollment(ctx context.Context, username string) error {
+	return s.Called(ctx, username).Error(0)
+}
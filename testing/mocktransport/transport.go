@@ -3,6 +3,7 @@ package mocktransport
 import (
 	"context"
 	"net/http"
+	"time"
 
 	v2 "github.com/sensu/core/v2"
 	"github.com/sensu/sensu-go/transport"
@@ -20,6 +21,12 @@ func (m *MockTransport) Close() error {
 	return args.Error(0)
 }
 
+// CloseWithRetry ...
+func (m *MockTransport) CloseWithRetry(retryAfter time.Duration) error {
+	args := m.Called(retryAfter)
+	return args.Error(0)
+}
+
 // Closed ...
 func (m *MockTransport) Closed() bool {
 	args := m.Called()
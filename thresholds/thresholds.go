@@ -0,0 +1,221 @@
+// Package thresholds evaluates the output metric thresholds of the checks
+// against the metric points of their events.
+package thresholds
+
+import (
+	"strconv"
+	"strings"
+
+	corev2 "github.com/sensu/core/v2"
+)
+
+// AnnotationPrefix is the prefix of the annotations of the events with the
+// result of the output metric thresholds.
+const AnnotationPrefix = "sensu.io/output_metric_thresholds/"
+
+// Evaluate evaluates the output metric thresholds of the check of an event
+// against its metric points, and returns the resulting status: the highest
+// status of the exceeded thresholds, or the null status of the thresholds
+// without metric points. The status of a failed check is returned as is.
+//
+// The event is annotated with the result of each threshold, under
+// AnnotationPrefix, and with the notification of the overall status.
+func Evaluate(event *corev2.Event) uint32 {
+	if event.Check.Status > 0 {
+		return event.Check.Status
+	}
+
+	points := event.Metrics.Points
+	thresholds := event.Check.OutputMetricThresholds
+
+	var overallStatus uint32 = 0
+	annotationValue := ""
+	for _, thresholdRule := range thresholds {
+		ruleMatched := false
+		for _, metricPoint := range points {
+			if thresholdRule.MatchesMetricPoint(metricPoint) {
+				ruleMatched = true
+				var status uint32 = 0
+				isExceeded := false
+				for _, rule := range thresholdRule.Thresholds {
+					if rule.Min != "" {
+						min, err := strconv.ParseFloat(rule.Min, 64)
+						if err != nil {
+							continue
+						}
+						if metricPoint.Value < min {
+							isExceeded = true
+							if status < rule.Status {
+								status = rule.Status
+							}
+							if overallStatus < rule.Status {
+								overallStatus = rule.Status
+								annotationValue = getAnnotationValue(thresholdRule, metricPoint.Value, isExceeded)
+							}
+							continue
+						} else {
+							annotationValue = getAnnotationValue(thresholdRule, metricPoint.Value, isExceeded)
+						}
+					}
+					if rule.Max != "" {
+						max, err := strconv.ParseFloat(rule.Max, 64)
+						if err != nil {
+							continue
+						}
+						if metricPoint.Value > max {
+							isExceeded = true
+							if status < rule.Status {
+								status = rule.Status
+							}
+							if overallStatus < rule.Status {
+								overallStatus = rule.Status
+								annotationValue = getAnnotationValue(thresholdRule, metricPoint.Value, isExceeded)
+							}
+						} else {
+							annotationValue = getAnnotationValue(thresholdRule, metricPoint.Value, isExceeded)
+						}
+					}
+				}
+				addThresholdAnnotation(event, thresholdRule, status, metricPoint.Value, isExceeded)
+			}
+		}
+		if !ruleMatched {
+			if thresholdRule.NullStatus > 0 {
+				addNullStatusThresholdAnnotation(event, thresholdRule, thresholdRule.NullStatus)
+				if overallStatus < thresholdRule.NullStatus {
+					overallStatus = thresholdRule.NullStatus
+					annotationValue = getNullStatusAnnotationValue(thresholdRule)
+				}
+			}
+		}
+	}
+
+	if annotationValue != "" {
+		event.AddAnnotation("sensu.io/notifications/"+corev2.CheckStatusToCaption(overallStatus), annotationValue)
+	}
+
+	return overallStatus
+}
+
+func addThresholdAnnotation(event *corev2.Event, metricThreshold *corev2.MetricThreshold, status uint32, value float64, isExceeded bool) {
+	event.AddAnnotation(getAnnotationKey(metricThreshold, status), getAnnotationValue(metricThreshold, value, isExceeded))
+}
+
+func addNullStatusThresholdAnnotation(event *corev2.Event, metricThreshold *corev2.MetricThreshold, status uint32) {
+	event.AddAnnotation(getAnnotationKey(metricThreshold, status), getNullStatusAnnotationValue(metricThreshold))
+}
+
+func getAnnotationKey(metricThreshold *corev2.MetricThreshold, status uint32) string {
+	var key strings.Builder
+
+	key.WriteString(AnnotationPrefix)
+	key.WriteString(metricThreshold.Name)
+	for _, tag := range metricThreshold.Tags {
+		key.WriteString(".")
+		key.WriteString(tag.Value)
+	}
+	key.WriteString("/")
+	key.WriteString(corev2.CheckStatusToCaption(status))
+
+	return key.String()
+}
+
+func getAnnotationValue(metricThreshold *corev2.MetricThreshold, value float64, isExceeded bool) string {
+	var val strings.Builder
+	var tagsKeyVal strings.Builder
+
+	for tagIdx, tag := range metricThreshold.Tags {
+		if tagIdx > 0 {
+			tagsKeyVal.WriteString(",")
+		}
+		tagsKeyVal.WriteString(tag.Name)
+		tagsKeyVal.WriteString("=")
+		tagsKeyVal.WriteString(tag.Value)
+	}
+
+	val.WriteString("The value of ")
+	val.WriteString(metricThreshold.Name)
+	if tagsKeyVal.Len() > 0 {
+		val.WriteString(" (")
+		val.WriteString(tagsKeyVal.String())
+		val.WriteString(")")
+	}
+	if isExceeded {
+		val.WriteString(" exceeded the configured threshold")
+	} else {
+		val.WriteString(" is within the configured threshold")
+	}
+
+	for _, t := range metricThreshold.Thresholds {
+		hasMin := len(t.Min) > 0
+		hasMax := len(t.Max) > 0
+		val.WriteString("; expected ")
+		if hasMin {
+			val.WriteString("min: ")
+			val.WriteString(t.Min)
+		}
+		if hasMin && hasMax {
+			val.WriteString(" - ")
+		}
+		if hasMax {
+			val.WriteString("max: ")
+			val.WriteString(t.Max)
+		}
+		val.WriteString(" (status: ")
+		val.WriteString(corev2.CheckStatusToCaption(t.Status))
+		val.WriteString(")")
+	}
+
+	val.WriteString("; actual: ")
+	val.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+
+	return val.String()
+}
+
+func getNullStatusAnnotationValue(metricThreshold *corev2.MetricThreshold) string {
+	var val strings.Builder
+	var tagsKeyVal strings.Builder
+
+	for tagIdx, tag := range metricThreshold.Tags {
+		if tagIdx > 0 {
+			tagsKeyVal.WriteString(", ")
+		}
+		tagsKeyVal.WriteString(tag.Name)
+		tagsKeyVal.WriteString("=\"")
+		tagsKeyVal.WriteString(tag.Value)
+		tagsKeyVal.WriteString("\"")
+	}
+
+	val.WriteString(strings.ToUpper(corev2.CheckStatusToCaption(metricThreshold.NullStatus)))
+	val.WriteString(" : no metric matching \"")
+	val.WriteString(metricThreshold.Name)
+	val.WriteString("\"")
+	if tagsKeyVal.Len() > 0 {
+		val.WriteString(" (")
+		val.WriteString(tagsKeyVal.String())
+		val.WriteString(")")
+	}
+	val.WriteString(" was found")
+
+	for _, t := range metricThreshold.Thresholds {
+		hasMin := len(t.Min) > 0
+		hasMax := len(t.Max) > 0
+		val.WriteString("; expected ")
+		if hasMin {
+			val.WriteString("min: ")
+			val.WriteString(t.Min)
+		}
+		if hasMin && hasMax {
+			val.WriteString(" - ")
+		}
+		if hasMax {
+			val.WriteString("max: ")
+			val.WriteString(t.Max)
+		}
+		val.WriteString(" (status: ")
+		val.WriteString(corev2.CheckStatusToCaption(t.Status))
+		val.WriteString(")")
+	}
+
+	return val.String()
+}
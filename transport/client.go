@@ -16,8 +16,14 @@ import (
 var ErrTooManyRequests = errors.New("too many requests")
 
 // connect establish the connection to a given websocket backend and returns it
-// along with any error encountered
-func connect(wsServerURL string, tlsOpts *v2.TLSOptions, requestHeader http.Header, handshakeTimeout int) (*websocket.Conn, http.Header, error) {
+// along with any error encountered. The messages are compressed with the
+// permessage-deflate extension if the compression level is not 0 and the
+// backend enabled the compression as well.
+func connect(wsServerURL string, tlsOpts *v2.TLSOptions, requestHeader http.Header, handshakeTimeout int, compressionLevel int) (*websocket.Conn, http.Header, error) {
+	if err := ValidateCompressionLevel(compressionLevel); err != nil {
+		return nil, nil, err
+	}
+
 	// TODO(grep): configurable max sendq depth
 	u, err := url.Parse(wsServerURL)
 	if err != nil {
@@ -30,6 +36,7 @@ func connect(wsServerURL string, tlsOpts *v2.TLSOptions, requestHeader http.Head
 	dialer := websocket.Dialer{
 		HandshakeTimeout:	time.Second * time.Duration(handshakeTimeout),
 		Proxy:			http.ProxyFromEnvironment,
+		EnableCompression:	compressionLevel > 0,
 	}
 
 	if tlsOpts != nil {
@@ -57,6 +64,10 @@ func connect(wsServerURL string, tlsOpts *v2.TLSOptions, requestHeader http.Head
 		}
 		return nil, nil, err
 	}
+	if err := SetCompressionLevel(conn, compressionLevel); err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
 
 	return conn, resp.Header, nil
 }
@@ -64,8 +75,8 @@ func connect(wsServerURL string, tlsOpts *v2.TLSOptions, requestHeader http.Head
 // Connect causes the transport Client to connect to a given websocket server.
 // Transport is a thin wrapper around a websocket connection that makes the
 // connection safe for concurrent use by multiple goroutines.
-func Connect(wsServerURL string, tlsOpts *v2.TLSOptions, requestHeader http.Header, handshakeTimeout int) (Transport, http.Header, error) {
-	conn, resp, err := connect(wsServerURL, tlsOpts, requestHeader, handshakeTimeout)
+func Connect(wsServerURL string, tlsOpts *v2.TLSOptions, requestHeader http.Header, handshakeTimeout int, compressionLevel int) (Transport, http.Header, error) {
+	conn, resp, err := connect(wsServerURL, tlsOpts, requestHeader, handshakeTimeout, compressionLevel)
 	if err != nil {
 		return nil, nil, err
 	}
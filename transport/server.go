@@ -8,7 +8,8 @@ import (
 
 // Server ...
 type Server struct {
-	upgrader *websocket.Upgrader
+	upgrader         *websocket.Upgrader
+	compressionLevel int
 }
 
 // NewServer is used to initialize a new Server and return a pointer to it.
@@ -18,6 +19,18 @@ func NewServer() *Server {
 	}
 }
 
+// NewCompressedServer is used to initialize a new Server that compresses the
+// messages at the given level, when the client enables the compression.
+func NewCompressedServer(compressionLevel int) (*Server, error) {
+	if err := ValidateCompressionLevel(compressionLevel); err != nil {
+		return nil, err
+	}
+	return &Server{
+		upgrader:         &websocket.Upgrader{EnableCompression: compressionLevel > 0},
+		compressionLevel: compressionLevel,
+	}, nil
+}
+
 // Serve is used to initialize a websocket connection and returns an pointer to
 // a Transport used to communicate with that client.
 func (s *Server) Serve(w http.ResponseWriter, r *http.Request) (Transport, error) {
@@ -25,6 +38,10 @@ func (s *Server) Serve(w http.ResponseWriter, r *http.Request) (Transport, error
 	if err != nil {
 		return nil, err
 	}
+	if err := SetCompressionLevel(conn, s.compressionLevel); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
 
 	return NewTransport(conn), err
 }
@@ -2,9 +2,11 @@ package transport
 
 import (
 	"bytes"
+	"compress/flate"
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -38,12 +40,29 @@ const (
 
 	// HeaderKeySubscriptions is the HTTP request header specifying the Agent Subscriptions
 	HeaderKeySubscriptions = "Sensu-Subscriptions"
+
+	// MaxCompressionLevel is the maximum compression level of the
+	// permessage-deflate extension. Level 0 disables the compression.
+	MaxCompressionLevel = flate.BestCompression
+
+	// CompressionThreshold is the size in bytes from which the messages are
+	// compressed, when the compression was negotiated with the peer. Smaller
+	// messages, such as keepalives, are not worth the CPU time.
+	CompressionThreshold = 1024
 )
 
+// retryAfterPrefix prefixes the delay in the reason of the close messages
+// asking the peer to reconnect later.
+const retryAfterPrefix = "retry after "
+
 // A ClosedError is returned when Receive or Send is called on a closed
 // Transport.
 type ClosedError struct {
 	Message string
+
+	// RetryAfter is the delay the peer asked to wait before reconnecting, when
+	// it closed the connection to shed its load. It is 0 otherwise.
+	RetryAfter time.Duration
 }
 
 func (e ClosedError) Error() string {
@@ -60,6 +79,26 @@ func (e ConnectionError) Error() string {
 	return fmt.Sprintf("Connection error: %s", e.Message)
 }
 
+// ValidateCompressionLevel returns an error if level is not a compression level
+// of the permessage-deflate extension, between 0 (disabled) and
+// MaxCompressionLevel.
+func ValidateCompressionLevel(level int) error {
+	if level < 0 || level > MaxCompressionLevel {
+		return fmt.Errorf("invalid compression level %d: must be between 0 (disabled) and %d", level, MaxCompressionLevel)
+	}
+	return nil
+}
+
+// SetCompressionLevel sets the compression level of the messages sent over a
+// websocket connection, if the compression was negotiated with the peer. The
+// compression is only negotiated when enabled, so level 0 leaves it as is.
+func SetCompressionLevel(conn *websocket.Conn, level int) error {
+	if level == 0 {
+		return nil
+	}
+	return conn.SetCompressionLevel(level)
+}
+
 // Encode a message to be sent over a websocket channel
 func Encode(msgType string, payload []byte) []byte {
 	buf := []byte(msgType + "\n")
@@ -116,6 +155,10 @@ type Transport interface {
 	// socket was successful and an error otherwise.
 	Send(*Message) error
 
+	// CloseWithRetry closes the connection like Close, but asks the peer to
+	// wait for retryAfter before reconnecting.
+	CloseWithRetry(retryAfter time.Duration) error
+
 	// SendCloseMessage sends a close control message over the transport, and the
 	// peer should echo the message back and that message will be returned as an
 	// error from the websocket connection's read API
@@ -149,6 +192,16 @@ func NewMessage(msgType string, payload []byte) *Message {
 // Close closes the WebsocketTransport. Before closing, a closing message will
 // be sent to the other side.
 func (t *WebSocketTransport) Close() (err error) {
+	return t.close(websocket.FormatCloseMessage(websocket.CloseGoingAway, "bye"))
+}
+
+// CloseWithRetry closes the WebsocketTransport with a close message asking the
+// other side to try again after retryAfter.
+func (t *WebSocketTransport) CloseWithRetry(retryAfter time.Duration) error {
+	return t.close(websocket.FormatCloseMessage(websocket.CloseTryAgainLater, retryAfterPrefix+retryAfter.String()))
+}
+
+func (t *WebSocketTransport) close(message []byte) (err error) {
 	if t.Closed() {
 		return nil
 	}
@@ -165,7 +218,7 @@ func (t *WebSocketTransport) Close() (err error) {
 		}
 	}()
 
-	if err := t.Connection.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "bye")); err != websocket.ErrCloseSent {
+	if err := t.Connection.WriteMessage(websocket.CloseMessage, message); err != websocket.ErrCloseSent {
 		return err
 	}
 
@@ -231,14 +284,18 @@ func (t *WebSocketTransport) Receive() (*Message, error) {
 	defer t.readMu.Unlock()
 
 	if t.Closed() {
-		return nil, ClosedError{"the websocket connection is no longer open"}
+		return nil, ClosedError{Message: "the websocket connection is no longer open"}
 	}
 
 	_, p, err := t.Connection.ReadMessage()
 	if err != nil {
 		if websocket.IsCloseError(err, websocket.CloseGoingAway) {
 			t.closed.Store(true)
-			return nil, ClosedError{err.Error()}
+			return nil, ClosedError{Message: err.Error()}
+		}
+		if websocket.IsCloseError(err, websocket.CloseTryAgainLater) {
+			t.closed.Store(true)
+			return nil, ClosedError{Message: err.Error(), RetryAfter: parseRetryAfter(err.(*websocket.CloseError).Text)}
 		}
 		return nil, ConnectionError{err.Error()}
 	}
@@ -259,7 +316,7 @@ func (t *WebSocketTransport) Send(m *Message) (err error) {
 	t.writeMu.Lock()
 	defer t.writeMu.Unlock()
 	if t.Closed() {
-		return ClosedError{"the websocket connection is no longer open"}
+		return ClosedError{Message: "the websocket connection is no longer open"}
 	}
 
 	defer func() {
@@ -269,6 +326,8 @@ func (t *WebSocketTransport) Send(m *Message) (err error) {
 	}()
 
 	msg := Encode(m.Type, m.Payload)
+	// This has no effect when the compression was not negotiated
+	t.Connection.EnableWriteCompression(len(msg) >= CompressionThreshold)
 	if err := t.Connection.WriteMessage(websocket.BinaryMessage, msg); err != nil {
 		// If we get _any_ error, let's just considered the connection closed,
 		// because it's _really_ hard to figure out what errors from the
@@ -276,7 +335,7 @@ func (t *WebSocketTransport) Send(m *Message) (err error) {
 		// hope, and reconnect if we get an error from the websocket lib.
 		t.closed.Store(true)
 		if websocket.IsCloseError(err, websocket.CloseGoingAway) {
-			return ClosedError{err.Error()}
+			return ClosedError{Message: err.Error()}
 		}
 		return ConnectionError{err.Error()}
 	}
@@ -290,3 +349,16 @@ func (t *WebSocketTransport) SendCloseMessage() (err error) {
 	defer t.writeMu.Unlock()
 	return t.Connection.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
 }
+
+// parseRetryAfter returns the delay in the reason of a close message, or 0 if
+// the reason has none.
+func parseRetryAfter(reason string) time.Duration {
+	if !strings.HasPrefix(reason, retryAfterPrefix) {
+		return 0
+	}
+	retryAfter, err := time.ParseDuration(strings.TrimPrefix(reason, retryAfterPrefix))
+	if err != nil || retryAfter < 0 {
+		return 0
+	}
+	return retryAfter
+}
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -35,7 +36,7 @@ func TestTransportSendReceive(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	clientTransport, _, err := Connect(strings.Replace(ts.URL, "http", "ws", 1), nil, nil, 5)
+	clientTransport, _, err := Connect(strings.Replace(ts.URL, "http", "ws", 1), nil, nil, 5, 0)
 	assert.NoError(t, err)
 	msgBytes, err := json.Marshal(testMessage)
 	assert.NoError(t, err)
@@ -60,7 +61,7 @@ func TestClosedWebsocket(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	clientTransport, _, err := Connect(strings.Replace(ts.URL, "http", "ws", 1), nil, nil, 5)
+	clientTransport, _, err := Connect(strings.Replace(ts.URL, "http", "ws", 1), nil, nil, 5, 0)
 	assert.NoError(t, err)
 	<-done
 	// At this point we should receive a connection closed message.
@@ -77,6 +78,73 @@ func TestClosedWebsocket(t *testing.T) {
 	assert.IsType(t, ClosedError{}, err)
 }
 
+func TestCloseWithRetry(t *testing.T) {
+	done := make(chan struct{}, 1)
+
+	server := NewServer()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		transport, err := server.Serve(w, r)
+		assert.NoError(t, err)
+		require.NoError(t, transport.CloseWithRetry(90*time.Second))
+		done <- struct{}{}
+	}))
+	defer ts.Close()
+
+	clientTransport, _, err := Connect(strings.Replace(ts.URL, "http", "ws", 1), nil, nil, 5, 0)
+	require.NoError(t, err)
+	<-done
+	_, err = clientTransport.Receive()
+	require.IsType(t, ClosedError{}, err)
+	assert.Equal(t, 90*time.Second, err.(ClosedError).RetryAfter)
+	assert.True(t, clientTransport.Closed())
+}
+
+func TestCompressedTransport(t *testing.T) {
+	payload := []byte(strings.Repeat("check output ", 1000))
+
+	tests := []struct {
+		name           string
+		serverLevel    int
+		clientLevel    int
+		wantNegotiated bool
+	}{
+		{name: "enabled on both sides", serverLevel: 6, clientLevel: 9, wantNegotiated: true},
+		{name: "disabled by the server", serverLevel: 0, clientLevel: 9},
+		{name: "disabled by the client", serverLevel: 6, clientLevel: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done := make(chan struct{})
+			server, err := NewCompressedServer(tt.serverLevel)
+			require.NoError(t, err)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer close(done)
+				transport, err := server.Serve(w, r)
+				require.NoError(t, err)
+				msg, err := transport.Receive()
+				require.NoError(t, err)
+				assert.Equal(t, payload, msg.Payload)
+			}))
+			defer ts.Close()
+
+			clientTransport, header, err := Connect(strings.Replace(ts.URL, "http", "ws", 1), nil, nil, 5, tt.clientLevel)
+			require.NoError(t, err)
+			negotiated := strings.Contains(header.Get("Sec-Websocket-Extensions"), "permessage-deflate")
+			assert.Equal(t, tt.wantNegotiated, negotiated)
+			require.NoError(t, clientTransport.Send(&Message{Type: "event", Payload: payload}))
+			<-done
+		})
+	}
+}
+
+func TestInvalidCompressionLevel(t *testing.T) {
+	_, err := NewCompressedServer(10)
+	assert.Error(t, err)
+
+	_, _, err = Connect("ws://127.0.0.1:0", nil, nil, 5, -1)
+	assert.Error(t, err)
+}
+
 // This was all mostly to prove that performance of encoding/decoding was
 // not super-linear.
 
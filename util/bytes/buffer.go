@@ -8,11 +8,25 @@ import (
 type SyncBuffer struct {
 	buf bytes.Buffer
 	mu  sync.Mutex
+
+	// Limit is the maximum number of bytes kept by the buffer, or 0 for no
+	// limit. Writes beyond the limit are discarded, but reported as
+	// successful so that writers are not interrupted.
+	Limit int
+
+	truncated bool
 }
 
 func (s *SyncBuffer) Write(p []byte) (n int, err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.Limit > 0 && s.buf.Len()+len(p) > s.Limit {
+		s.truncated = true
+		if _, err := s.buf.Write(p[:s.Limit-s.buf.Len()]); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
 	return s.buf.Write(p)
 }
 
@@ -21,3 +35,10 @@ func (s *SyncBuffer) String() string {
 	defer s.mu.Unlock()
 	return s.buf.String()
 }
+
+// Truncated returns true if writes were discarded because of the limit.
+func (s *SyncBuffer) Truncated() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.truncated
+}
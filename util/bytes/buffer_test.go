@@ -0,0 +1,33 @@
+package bytes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncBufferLimit(t *testing.T) {
+	buf := SyncBuffer{Limit: 5}
+	n, err := buf.Write([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.False(t, buf.Truncated())
+
+	n, err = buf.Write([]byte("defgh"))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.True(t, buf.Truncated())
+
+	n, err = buf.Write([]byte("ijk"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, "abcde", buf.String())
+}
+
+func TestSyncBufferNoLimit(t *testing.T) {
+	var buf SyncBuffer
+	_, _ = buf.Write([]byte("abc"))
+	_, _ = buf.Write([]byte("def"))
+	assert.Equal(t, "abcdef", buf.String())
+	assert.False(t, buf.Truncated())
+}
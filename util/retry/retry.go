@@ -12,6 +12,10 @@ import (
 
 const DefaultMultiplier float64 = 2.0
 
+// DefaultJitter is the default maximal fraction of the delay randomly added to
+// it.
+const DefaultJitter float64 = 1.0
+
 // JSONTimeDuration is like time.Duration, but with friendly JSON methods.
 type JSONTimeDuration time.Duration
 
@@ -69,6 +73,11 @@ type ExponentialBackoff struct {
 	// this multiplier. If not supplied, it will be set to DefaultMultiplier.
 	Multiplier float64 `json:"multiplier"`
 
+	// Jitter is the maximal fraction of the delay randomly added to it, to
+	// prevent potential collisions. If not supplied, it will be set to
+	// DefaultJitter. A negative value disables the jitter.
+	Jitter float64 `json:"jitter,omitempty"`
+
 	// start contains the starting time of the retry attempts
 	start time.Time
 }
@@ -88,6 +97,11 @@ func (e *ExponentialBackoff) UnmarshalJSON(b []byte) error {
 			return err
 		}
 	}
+	if jitter, ok := blob["jitter"]; ok {
+		if err := json.Unmarshal(*jitter, &e.Jitter); err != nil {
+			return err
+		}
+	}
 	if initDelay, ok := blob["initial_delay_interval"]; ok {
 		var td JSONTimeDuration
 		if err := json.Unmarshal(*initDelay, &td); err != nil {
@@ -119,6 +133,7 @@ func (e ExponentialBackoff) MarshalJSON() (out []byte, err error) {
 		MaxElapsedTime       JSONTimeDuration `json:"max_elapsed_time,omitempty"`
 		MaxRetryAttempts     int              `json:"max_retry_attempts,omitempty"`
 		Multiplier           float64          `json:"multiplier"`
+		Jitter               float64          `json:"jitter,omitempty"`
 	}
 	eb := ebFacade{
 		InitialDelayInterval: JSONTimeDuration(e.InitialDelayInterval),
@@ -126,6 +141,7 @@ func (e ExponentialBackoff) MarshalJSON() (out []byte, err error) {
 		MaxElapsedTime:       JSONTimeDuration(e.MaxElapsedTime),
 		MaxRetryAttempts:     e.MaxRetryAttempts,
 		Multiplier:           e.Multiplier,
+		Jitter:               e.Jitter,
 	}
 	return json.Marshal(eb)
 }
@@ -177,7 +193,13 @@ func (b *ExponentialBackoff) Retry(fn Func) error {
 
 			// Add a jitter (randomized delay) for the next attempt, to prevent
 			// potential collisions
-			wait = wait + time.Duration(rand.Float64()*float64(wait))
+			jitter := b.Jitter
+			if jitter == 0 {
+				jitter = DefaultJitter
+			}
+			if jitter > 0 {
+				wait = wait + time.Duration(rand.Float64()*jitter*float64(wait))
+			}
 		} else {
 			// Save the current time, in order to measure the total execution time
 			b.start = time.Now()
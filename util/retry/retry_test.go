@@ -90,7 +90,8 @@ func TestUnmarshalExponentialBackoff(t *testing.T) {
 	"max_delay_interval": 0,
 	"max_elapsed_time": "1h",
 	"max_retry_attempts": 5,
-	"multiplier": 1.5
+	"multiplier": 1.5,
+	"jitter": 0.25
 	}`)
 	var eb ExponentialBackoff
 	if err := json.Unmarshal(doc, &eb); err != nil {
@@ -105,6 +106,9 @@ func TestUnmarshalExponentialBackoff(t *testing.T) {
 	if got, want := eb.MaxElapsedTime, time.Hour; got != want {
 		t.Fatalf("bad MaxElapsedTime: got %s, want %s", got, want)
 	}
+	if got, want := eb.Jitter, 0.25; got != want {
+		t.Fatalf("bad Jitter: got %v, want %v", got, want)
+	}
 }
 
 func TestMarshalExponentialBackoff(t *testing.T) {